@@ -0,0 +1,133 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+func init() {
+	rootCmd.AddCommand(newAgentCmd())
+}
+
+func newAgentCmd() *cobra.Command {
+	var (
+		listen   string
+		name     string
+		facts    []string
+		insecure bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Run controls placed on this node by a distributed check",
+		Long: `Serve the agent gRPC API that "reglet check --distributed" uses to run part of
+a profile on this node. The coordinator sends the profile and the IDs of the
+controls placed here; the agent runs them and returns the partial result.
+
+Controls with runs_on entries are only placed on agents having all of them as
+facts. An agent reports its operating system ("linux", "os:linux"), its
+architecture ("arch:amd64"), "has:<command>" for commands on its PATH, and the
+facts declared with --fact. The coordinator adds the agent's tags from
+cluster.agents.
+
+The agent resolves variables, secrets and plugins with its own system config,
+and only grants capabilities saved in its config: nobody answers prompts on
+the agent's console. Storage is left to the coordinator, so agents should not
+configure storage.backend.
+
+Configure cluster.tls with a certificate and key to serve over TLS. With
+cluster.tls.ca set, the agent only accepts coordinators presenting a client
+certificate signed by that CA. Anyone reaching the agent can run controls on
+it, so listening on a non-loopback address requires cluster.tls.ca, or
+--insecure to accept unauthenticated coordinators.`,
+		Example: `  # Serve on all interfaces with mutual TLS from the system config
+  reglet agent --listen :9443 --name web-1 --config agent.yaml
+
+  # Declare facts for runs_on constraints
+  reglet agent --fact gpu --fact zone:eu-west`,
+		Args: cobra.NoArgs,
+		RunE: withContainer(func(ctx *CommandContext, _ *cobra.Command, _ []string) error {
+			runCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if name == "" {
+				hostname, err := os.Hostname()
+				if err != nil {
+					return fmt.Errorf("failed to determine agent name, set --name: %w", err)
+				}
+				name = hostname
+			}
+
+			agent, tlsConfig, err := ctx.Container.AgentServer(name, facts)
+			if err != nil {
+				return err
+			}
+			if err := checkAgentListen(listen, tlsConfig, insecure); err != nil {
+				return err
+			}
+
+			listener, err := net.Listen("tcp", listen)
+			if err != nil {
+				return fmt.Errorf("failed to listen: %w", err)
+			}
+			server := agent.NewGRPCServer(tlsConfig)
+			errs := make(chan error, 1)
+			go func() { errs <- server.Serve(listener) }()
+			ctx.Logger.Info("serving agent", "name", name, "listen", listener.Addr().String(), "tls", tlsConfig != nil)
+
+			select {
+			case err := <-errs:
+				return fmt.Errorf("agent failed: %w", err)
+			case <-runCtx.Done():
+			}
+
+			server.GracefulStop()
+			if err := <-errs; err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				return err
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", "127.0.0.1:9443", "Address to listen on")
+	cmd.Flags().StringVar(&name, "name", "", "Agent name reported in results (default: hostname)")
+	cmd.Flags().StringArrayVar(&facts, "fact", nil, "Fact matched against runs_on of controls, besides the discovered ones (repeatable)")
+	cmd.Flags().BoolVar(&insecure, "insecure", false, "Allow serving a non-loopback address without client certificate authentication")
+	return cmd
+}
+
+// checkAgentListen refuses to serve a non-loopback address unless
+// coordinators are authenticated by a client certificate, or insecure is set.
+func checkAgentListen(listen string, tlsConfig *tls.Config, insecure bool) error {
+	if insecure || isLoopback(listen) {
+		return nil
+	}
+	if tlsConfig == nil {
+		return fmt.Errorf("refusing to serve %s without TLS: configure cluster.tls with a certificate, key and ca, or pass --insecure", listen)
+	}
+	if tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert {
+		return fmt.Errorf("refusing to serve %s without client certificates: set cluster.tls.ca, or pass --insecure", listen)
+	}
+	return nil
+}
+
+// isLoopback reports whether listen only accepts local connections.
+func isLoopback(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
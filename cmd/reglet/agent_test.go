@@ -0,0 +1,23 @@
+package main
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAgentListen(t *testing.T) {
+	t.Parallel()
+
+	mutual := &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert}
+	serverOnly := &tls.Config{}
+
+	assert.NoError(t, checkAgentListen("127.0.0.1:9443", nil, false))
+	assert.NoError(t, checkAgentListen("localhost:9443", serverOnly, false))
+	assert.NoError(t, checkAgentListen(":9443", mutual, false))
+	assert.NoError(t, checkAgentListen(":9443", nil, true))
+
+	assert.ErrorContains(t, checkAgentListen(":9443", nil, false), "without TLS")
+	assert.ErrorContains(t, checkAgentListen("10.0.0.5:9443", serverOnly, false), "set cluster.tls.ca")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newAnonymizeCmd())
+}
+
+func newAnonymizeCmd() *cobra.Command {
+	var (
+		runID       string
+		mappingPath string
+	)
+	opts := &CheckOptions{CommonOptions: DefaultCommonOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "anonymize (--run <id> | <result.json> | <run-dir>)",
+		Short: "Replace identifying values in a result before sharing it",
+		Long: `Write a copy of a stored run, a JSON result file or a run directory of
+--work-dir with identifying values replaced, for sharing reports outside the
+organization. By default IP addresses, host names and email addresses are
+replaced with pseudonyms like ip-3f2a9c1b7d4e: equal values get equal
+pseudonyms, so the report can still be correlated. The anonymization.rules of the system config select other
+values, by pattern or by evidence and config field, and can mask them
+instead.
+
+The original value of every pseudonym is recorded in a local mapping file
+(default: ~/.reglet/anonymization-mapping.json), readable only by its owner,
+to trace shared reports back internally. Keep it private: it holds the
+values the report hides. The stored run or result file is not modified.`,
+		Example: `  # Share the SARIF report of a stored run
+  reglet anonymize --run 0f6c1d1e-5b0a-4c1e-9d3e-2a8c5f7b9e41 --format sarif -o report.sarif
+
+  # Anonymize a result file, keeping the mapping with the audit records
+  reglet anonymize result.json --mapping audit/mapping.json --format json -o shared.json`,
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if (runID == "") == (len(args) == 0) {
+				return fmt.Errorf("specify either --run or a result file")
+			}
+			return opts.ValidateFlags()
+		},
+		RunE: withContainer(func(ctx *CommandContext, _ *cobra.Command, args []string) error {
+			anonymization, err := ctx.Container.ResultAnonymizationService(mappingPath)
+			if err != nil {
+				return err
+			}
+
+			req := dto.AnonymizeResultRequest{RunID: runID}
+			if len(args) > 0 {
+				req.ResultPath = args[0]
+			}
+			result, err := anonymization.Anonymize(ctx.Context, req)
+			if err != nil {
+				return fmt.Errorf("anonymize failed: %w", err)
+			}
+
+			if err := writeOutput(ctx.Container.OutputFormatterFactory(), result, "", opts); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+			if !opts.Quiet {
+				fmt.Fprintln(os.Stderr, "Pseudonyms recorded in the mapping file; keep it private.")
+			}
+			return nil
+		}),
+	}
+
+	opts.RegisterFlags(cmd)
+	cmd.Flags().StringVar(&runID, "run", "", "Execution ID of the stored run to anonymize")
+	cmd.Flags().StringVar(&mappingPath, "mapping", "", "Pseudonym mapping file (default: anonymization.mapping_file of the config, or ~/.reglet/anonymization-mapping.json)")
+	cmd.Flags().StringVarP(&opts.outFile, "output", "o", "", "Output file path, compressed when named *.gz or *.zst (default: stdout)")
+	return cmd
+}
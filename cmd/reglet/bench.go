@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/adapters"
+	"github.com/reglet-dev/reglet/internal/infrastructure/bench"
+	"github.com/reglet-dev/reglet/internal/infrastructure/container"
+	"github.com/spf13/cobra"
+)
+
+// BenchOptions holds the configuration for the bench command.
+type BenchOptions struct {
+	mix        string
+	format     string
+	pluginDir  string
+	command    string
+	hostname   string
+	httpURL    string
+	tcpAddress string
+
+	controls             int
+	observations         int
+	iterations           int
+	warmup               int
+	instantiationSamples int
+
+	parallel bool
+}
+
+func init() {
+	rootCmd.AddCommand(newBenchCmd())
+}
+
+func newBenchCmd() *cobra.Command {
+	opts := &BenchOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Benchmark the engine with a synthetic profile",
+		Long: `Generate a synthetic profile with the given number of controls, observations
+and plugin mix, run it repeatedly and report throughput, p50/p95 latency per
+plugin, plugin compile and instantiation overhead, and memory.
+
+Run the same benchmark against two releases to measure performance regressions.
+Plugin capabilities are granted automatically for the synthetic profile.
+
+Observations are cheap and local by default: file reads a temporary file,
+command runs --command and dns resolves --hostname. http and tcp have no
+default target; point them at an endpoint with --http-url and --tcp-address.`,
+		Example: `  # Default mix, table output
+  reglet bench
+
+  # Larger profile, JSON report for comparison across releases
+  reglet bench --controls 200 --observations 5 --iterations 10 --format json > bench.json
+
+  # Include network plugins against a staging endpoint
+  reglet bench --plugins file=2,http=1,tcp=1 --http-url https://staging.example.com --tcp-address staging.example.com:443`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if opts.format != "table" && opts.format != "json" {
+				return fmt.Errorf("invalid --format %q (must be table or json)", opts.format)
+			}
+			if !cmd.Flags().Changed("log-level") && !quiet {
+				// Per-run progress logs drown out the report
+				logLevel = "warn"
+				setupLogging()
+			}
+			return runBench(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.controls, "controls", 20, "Number of controls in the synthetic profile")
+	cmd.Flags().IntVar(&opts.observations, "observations", 5, "Observations per control")
+	cmd.Flags().StringVar(&opts.mix, "plugins", "file=2,command=1,dns=1", "Plugin mix as plugin=weight pairs (plugins: command, dns, file, http, tcp)")
+	cmd.Flags().IntVar(&opts.iterations, "iterations", 5, "Measured runs of the profile")
+	cmd.Flags().IntVar(&opts.warmup, "warmup", 1, "Unmeasured runs before measuring")
+	cmd.Flags().IntVar(&opts.instantiationSamples, "instantiation-samples", 10, "Fresh plugin instances timed per plugin to measure instantiation overhead")
+	cmd.Flags().BoolVar(&opts.parallel, "parallel", true, "Run controls in parallel")
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Report format: table, json")
+	cmd.Flags().StringVar(&opts.pluginDir, "plugin-dir", "", "Directory containing plugins (default: ./plugins or next to the executable)")
+	cmd.Flags().StringVar(&opts.command, "command", "true", "Command run by command observations")
+	cmd.Flags().StringVar(&opts.hostname, "hostname", "localhost", "Hostname resolved by dns observations")
+	cmd.Flags().StringVar(&opts.httpURL, "http-url", "", "URL fetched by http observations")
+	cmd.Flags().StringVar(&opts.tcpAddress, "tcp-address", "", "host:port connected to by tcp observations")
+
+	return cmd
+}
+
+// runBench generates the synthetic profile, runs it and writes the report.
+func runBench(ctx context.Context, opts *BenchOptions) error {
+	mix, err := bench.ParseMix(opts.mix)
+	if err != nil {
+		return fmt.Errorf("invalid --plugins: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "reglet-bench-")
+	if err != nil {
+		return fmt.Errorf("failed to create benchmark directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	targetFile := filepath.Join(dir, "target.txt")
+	if err := os.WriteFile(targetFile, []byte("reglet bench\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to create benchmark target: %w", err)
+	}
+
+	spec := bench.Spec{
+		Mix: mix,
+		Targets: bench.Targets{
+			File:       targetFile,
+			Command:    opts.command,
+			Hostname:   opts.hostname,
+			HTTPURL:    opts.httpURL,
+			TCPAddress: opts.tcpAddress,
+		},
+		Controls:     opts.controls,
+		Observations: opts.observations,
+	}
+	data, err := spec.YAML()
+	if err != nil {
+		return err
+	}
+	profilePath := filepath.Join(dir, "bench.yaml")
+	if err := os.WriteFile(profilePath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write benchmark profile: %w", err)
+	}
+
+	c, err := container.New(container.Options{
+		TrustPlugins:     true,
+		SystemConfigPath: cfgFile,
+		Logger:           slog.Default(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize application: %w", err)
+	}
+
+	pluginDir := opts.pluginDir
+	if pluginDir == "" {
+		pluginDir, err = adapters.NewPluginDirectoryAdapter().ResolvePluginDir(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to resolve plugin directory: %w", err)
+		}
+	}
+
+	// Measured first, while compiles are still cold
+	plugins := make([]string, 0, len(mix))
+	for _, w := range mix {
+		plugins = append(plugins, w.Plugin)
+	}
+	instantiation, err := bench.MeasureInstantiation(ctx, pluginDir, plugins, opts.instantiationSamples)
+	if err != nil {
+		return err
+	}
+
+	run := func(ctx context.Context, profilePath string) (*execution.ExecutionResult, error) {
+		response, err := c.CheckProfileUseCase().Execute(ctx, dto.CheckProfileRequest{
+			ProfilePath: profilePath,
+			Execution:   dto.ExecutionOptions{Parallel: opts.parallel},
+			Options:     dto.CheckOptions{TrustPlugins: true, PluginDir: opts.pluginDir},
+			Metadata:    dto.RequestMetadata{RequestID: generateRequestID()},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return response.ExecutionResult, nil
+	}
+	report, err := bench.Run(ctx, profilePath, run, bench.Options{Iterations: opts.iterations, Warmup: opts.warmup})
+	if err != nil {
+		return fmt.Errorf("benchmark failed: %w", err)
+	}
+	report.SetInstantiation(instantiation)
+
+	if opts.format == "json" {
+		return report.WriteJSON(os.Stdout)
+	}
+	return report.WriteTable(os.Stdout)
+}
@@ -9,13 +9,25 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/reglet-dev/reglet/internal/application/dto"
 	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/application/services"
 	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/compression"
 	"github.com/reglet-dev/reglet/internal/infrastructure/container"
+	"github.com/reglet-dev/reglet/internal/infrastructure/exporter"
+	"github.com/reglet-dev/reglet/internal/infrastructure/metrics"
+	"github.com/reglet-dev/reglet/internal/infrastructure/rundir"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
 	"github.com/spf13/cobra"
 )
 
@@ -23,19 +35,56 @@ import (
 type CheckOptions struct {
 	outFile           string
 	securityLevel     string
+	pluginMode        string
 	filterExpr        string
 	includeTags       []string
 	includeSeverities []string
 	includeControlIDs []string
 	excludeTags       []string
 	excludeControlIDs []string
+	exporters         []string
+	injectFaults      []string
+	publish           string
+	policyBundle      string
+	severityRules     string
+	clockTime         string
+	recordCassette    string
+	replayCassette    string
+	timezone          string
+	environment       string
+	statusFile        string
+	workDir           string
 
 	CommonOptions // Embed common options (Starts with Ptr, ends with NonPtr)
 
+	maxEvidenceSize int
+	maxControls     int
+	maxObservations int
+	flapWindow      int
+	flapThreshold   int
+	faultSeed       uint64
+	interval        time.Duration
+	metricsListen   string
+	clock           func() time.Time
+
 	trustPlugins        bool
 	includeDependencies bool
+	stream              bool
+	profilePerf         bool
+	preflight           bool
+	allowSudo           bool
+	httpCache           bool
+	maxEgress           int64
+	debugHTTP           bool
+	distributed         bool
+	offline             bool
+	skipMissingPlugins  bool
+	noDedup             bool
 }
 
+// publishTargets are the CI report exporters selectable with --publish.
+var publishTargets = []string{exporter.GitHubExporterName, exporter.GitLabExporterName, exporter.BitbucketExporterName}
+
 func init() {
 	rootCmd.AddCommand(newCheckCmd())
 }
@@ -46,7 +95,7 @@ func newCheckCmd() *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "check <profile.yaml>",
+		Use:   "check [profile.yaml]",
 		Short: "Execute compliance checks from a profile",
 		Long: `Load a profile configuration and execute the defined validation controls.
 The profile must be a valid YAML file defining the checks to run.
@@ -58,13 +107,22 @@ Filtering:
   --control ssh-check           Run specific controls (exclusive)
   --exclude-tags slow           Exclude controls with 'slow' tag
   --filter "severity == 'high'" Advanced filtering expression
-  --include-dependencies        Include dependencies of selected controls`,
+  --include-dependencies        Include dependencies of selected controls
+
+Workspace:
+  Without a profile argument, the profiles listed in the reglet.yaml of the
+  working directory or its parents are run. Its output and notifications
+  settings supply defaults for --format, --output and --export.`,
 		Example: `  # Run all controls in a profile
   reglet check profile.yaml
 
   # Output results as JSON
   reglet check profile.yaml --format json
 
+  # Terse CI log line, or every observation with evidence snippets
+  reglet check profile.yaml -q
+  reglet check profile.yaml -vv
+
   # Run only critical and high severity controls
   reglet check profile.yaml --severity critical,high
 
@@ -72,33 +130,140 @@ Filtering:
   reglet check profile.yaml --tags security -o results.json --format json
 
   # Auto-grant plugin capabilities (CI/CD pipelines)
-  reglet check profile.yaml --trust-plugins`,
-		Args: cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// Validate common flags
-			if err := opts.ValidateFlags(); err != nil {
-				return err
+  reglet check profile.yaml --trust-plugins
+
+  # Stream results for very large profiles, capping evidence at 64KB
+  reglet check profile.yaml --format jsonl --stream --max-evidence-size 65536 -o results.jsonl
+
+  # Also send results to an exporter (compiled in or installed as a WASM plugin)
+  reglet check profile.yaml --export jira
+
+  # Publish a pipeline report in GitLab CI (auth from CI variables)
+  reglet check profile.yaml --publish gitlab
+
+  # Layer custom gating rules from a Rego policy bundle (requires opa)
+  reglet check profile.yaml --policy ./policies
+
+  # Assign severities from rules, e.g. certificates expiring within 7 days are critical
+  reglet check profile.yaml --severity-rules severity-rules.yaml
+
+  # Reproduce a recorded run: fixed clock for results, plugins and expiry checks
+  reglet check profile.yaml --clock 2026-01-01T00:00:00Z --timezone Europe/Berlin
+
+  # Record plugin network calls and file reads, then re-run offline from them
+  reglet check profile.yaml --record run.cassette.json
+  reglet check profile.yaml --replay run.cassette.json
+
+  # Chaos test: 30% of http plugin calls time out
+  reglet check profile.yaml --inject-fault plugin=http,rate=0.3,type=timeout
+
+  # Show where the run spends its time
+  reglet check profile.yaml --profile-perf
+
+  # Verify secrets, target DNS and host permissions first, stopping on setup problems
+  reglet check profile.yaml --preflight
+
+  # Continuous verification: re-run every 5 minutes, alert only on transitions
+  reglet check profile.yaml --interval 5m --export pagerduty
+
+  # ... and expose the results of each run to Prometheus at :9090/metrics
+  reglet check profile.yaml --interval 5m --metrics-listen :9090
+
+  # Air-gapped audit: no network from plugins, cached plugins only
+  reglet check profile.yaml --offline
+
+  # Split the run across the agents listed under cluster.agents
+  reglet check profile.yaml --distributed --config cluster.yaml
+
+  # Write the outcome and exit code as JSON for a wrapper script
+  reglet check profile.yaml --status-file status.json
+
+  # Keep each run in its own directory: results.json, events.jsonl, logs/, artifacts/
+  reglet check profile.yaml --work-dir runs/ --format sarif
+
+  # Run the profiles of the workspace's reglet.yaml
+  reglet check`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			status := &runStatus{}
+			if opts.statusFile != "" {
+				defer func() {
+					if writeErr := writeStatusFile(opts.statusFile, status, err); writeErr != nil {
+						slog.Error("failed to write status file", "file", opts.statusFile, "error", writeErr)
+					}
+				}()
+			}
+
+			profiles, err := profileArgs(args)
+			if err != nil {
+				return withExitCode(exitConfigError, err)
+			}
+			applyProjectDefaults(cmd, opts)
+			if err := validateCheckFlags(opts, len(profiles)); err != nil {
+				return withExitCode(exitConfigError, err)
 			}
 
 			// Apply logging overrides
 			if opts.Quiet {
 				quiet = true
 				setupLogging()
-			} else if opts.Verbose {
+			} else if opts.Verbosity >= ports.VerbosityEvidence {
 				logLevel = "debug"
 				setupLogging()
 			}
 
-			return runCheckAction(cmd.Context(), args[0], opts)
+			var failed []error
+			for _, profilePath := range profiles {
+				result, err := runCheckAction(cmd.Context(), profilePath, opts)
+				status.add(profilePath, result, err)
+				if err != nil {
+					if len(profiles) == 1 {
+						return err
+					}
+					failed = append(failed, fmt.Errorf("%s: %w", profilePath, err))
+				}
+			}
+			return errors.Join(failed...)
 		},
 	}
-
 	// Register common flags
 	opts.RegisterFlags(cmd)
 
-	cmd.Flags().StringVarP(&opts.outFile, "output", "o", "", "Output file path (default: stdout)")
+	cmd.Flags().StringVarP(&opts.outFile, "output", "o", "", "Output file path, compressed when named *.gz or *.zst (default: stdout)")
+	cmd.Flags().StringVar(&opts.statusFile, "status-file", "", "Write a JSON summary of the run's outcome and exit code to this file, even when the run fails")
+	cmd.Flags().StringVar(&opts.workDir, "work-dir", "", "Write each run to a directory of its own in this work directory: results.json, events.jsonl, provenance.json, logs/ and artifacts/ (the report of --format), moved into place once complete")
+	cmd.Flags().StringVar(&opts.environment, "env", "", "Resolve the profile for one of its environments: apply its var overrides and fan {{ .target }} out to its targets")
 	cmd.Flags().BoolVar(&opts.trustPlugins, "trust-plugins", false, "Auto-grant all plugin capabilities (use with caution)")
 	cmd.Flags().StringVar(&opts.securityLevel, "security", "", "Security level: strict, standard, permissive (default: standard or config file)")
+	cmd.Flags().BoolVar(&opts.stream, "stream", false, "Write each control as it completes instead of buffering the full result (json, jsonl)")
+	cmd.Flags().StringVar(&opts.pluginMode, "plugin-mode", dto.PluginModeWASM, "Plugin execution mode: wasm, or native to run plugins linked into this binary in-process (development only, no sandbox)")
+	cmd.Flags().StringSliceVar(&opts.exporters, "export", nil, "Send results to these exporters after the run (comma-separated)")
+	cmd.Flags().StringVar(&opts.publish, "publish", "", "Publish a CI pipeline report: "+strings.Join(publishTargets, ", "))
+	cmd.Flags().StringVar(&opts.policyBundle, "policy", "", "Rego policy bundle evaluated over the result with opa; its findings fail the run")
+	cmd.Flags().StringVar(&opts.severityRules, "severity-rules", "", "YAML rules assigning severities to controls by tags, plugins and evidence (see docs/severity-rules.md)")
+	cmd.Flags().StringVar(&opts.clockTime, "clock", "", "Run with a fixed clock at this RFC 3339 time (reproducible timestamps and time-relative checks)")
+	cmd.Flags().StringVar(&opts.timezone, "timezone", "", "IANA time zone for the clock, e.g. UTC or Europe/Berlin (default: local)")
+	cmd.Flags().StringVar(&opts.recordCassette, "record", "", "Record plugin host calls (DNS, HTTP, TCP, SMTP, exec) and file reads into this cassette file")
+	cmd.Flags().StringVar(&opts.replayCassette, "replay", "", "Replay plugin host calls and file reads from a recorded cassette, offline")
+	cmd.Flags().StringArrayVar(&opts.injectFaults, "inject-fault", nil, "Make plugin host calls fail: plugin=<name>,function=<host function>,type=timeout|refused|not_found|error,rate=<0-1>,delay=<duration> (repeatable)")
+	cmd.Flags().Uint64Var(&opts.faultSeed, "fault-seed", 0, "Seed for --inject-fault so the same calls fail on every run (default: random)")
+	cmd.Flags().BoolVar(&opts.profilePerf, "profile-perf", false, "Add a timing breakdown to the result: profile load, capability collection, plugin compile, and instantiation vs execution vs host I/O per observation")
+	cmd.Flags().BoolVar(&opts.preflight, "preflight", false, "Before running, verify setup prerequisites (secrets, target DNS, proxy, file/command/network access of this host) and stop with a report grouped by plugin if any fail")
+	cmd.Flags().IntVar(&opts.maxControls, "max-controls", 0, "Run at most this many controls at once (default: the CPUs reglet may use, after cgroup quotas and CPU pinning, and at least 4)")
+	cmd.Flags().IntVar(&opts.maxObservations, "max-observations", 0, "Run at most this many observations of a control at once (default: half the CPUs reglet may use, between 2 and 10)")
+	cmd.Flags().IntVar(&opts.maxEvidenceSize, "max-evidence-size", 0, "Truncate evidence larger than this many bytes per observation (default: config file or 1MB)")
+	cmd.Flags().BoolVar(&opts.allowSudo, "allow-sudo", false, "Let observations marked privileged read the files reglet is denied access to with \"sudo -n reglet privileged-read\" (needs a sudoers rule for those files)")
+	cmd.Flags().BoolVar(&opts.httpCache, "http-cache", false, "Answer plugin HTTP GET requests from responses fetched earlier in the run while their Cache-Control max-age allows, so controls checking the same endpoint share one request")
+	cmd.Flags().Int64Var(&opts.maxEgress, "max-egress", 0, "Abort the run once plugins would send more than this many bytes in network requests (default: unlimited)")
+	cmd.Flags().BoolVar(&opts.debugHTTP, "debug-http", false, "Log the HTTP requests of failed observations as curl commands with status, timing and header names (values, bodies and query values redacted)")
+	cmd.Flags().BoolVar(&opts.noDedup, "no-dedup-observations", false, "Run identical observations (same plugin and config) of different controls separately instead of running them once and sharing their evidence")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 0, "Run continuously, starting a run at this interval (e.g. 5m); print and export only status transitions until interrupted")
+	cmd.Flags().StringVar(&opts.metricsListen, "metrics-listen", "", "With --interval: serve the results of the latest run as Prometheus metrics at /metrics on this address (e.g. :9090)")
+	cmd.Flags().IntVar(&opts.flapWindow, "flap-window", 10, "With --interval: number of recent runs considered for flap detection")
+	cmd.Flags().IntVar(&opts.flapThreshold, "flap-threshold", 4, "With --interval: status changes within --flap-window that mark a control as flapping")
+	cmd.Flags().BoolVar(&opts.skipMissingPlugins, "skip-missing-plugins", false, "Run the observations of installed plugins when others are not installed, marking the observations of missing plugins not_run instead of refusing to start")
+	cmd.Flags().BoolVar(&opts.offline, "offline", false, "Air-gapped mode: deny plugins network access regardless of grants, never pull plugins, and refuse to start if selected controls need the network")
+	cmd.Flags().BoolVar(&opts.distributed, "distributed", false, "Split the run across the agents under cluster.agents in the system config, placing controls by tag affinity")
 
 	// Filtering flags
 	cmd.Flags().StringSliceVar(&opts.includeTags, "tags", nil, "Run controls with these tags (comma-separated)")
@@ -112,55 +277,492 @@ Filtering:
 	return cmd
 }
 
-// runCheckAction encapsulates the logic for the check command.
-func runCheckAction(ctx context.Context, profilePath string, opts *CheckOptions) error {
+// runCheckAction encapsulates the logic for the check command. It returns the
+// result when the profile ran, and an error carrying the exit code of the run.
+func runCheckAction(ctx context.Context, profilePath string, opts *CheckOptions) (_ *execution.ExecutionResult, err error) {
+	// 0. Lay the run out in a run directory of --work-dir. Continuous
+	// verification starts one per run
+	var run *rundir.Run
+	if opts.workDir != "" && opts.interval == 0 {
+		var restoreLogger func()
+		run, restoreLogger, err = startRunDir(opts.workDir)
+		if err != nil {
+			return nil, withExitCode(exitConfigError, err)
+		}
+		defer restoreLogger()
+		defer run.Discard() // unless committed
+	}
+
 	// 1. Initialize container (uses global cfgFile)
 	c, err := container.New(container.Options{
 		TrustPlugins:     opts.trustPlugins,
 		SecurityLevel:    opts.securityLevel,
 		SystemConfigPath: cfgFile, // Pass config path from CLI flag
 		Logger:           slog.Default(),
+		Offline:          opts.offline,
+		Layers:           layers,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to initialize application: %w", err)
+		return nil, withExitCode(exitConfigError, fmt.Errorf("failed to initialize application: %w", err))
+	}
+	ctx, err = namespaceContext(ctx, c)
+	if err != nil {
+		return nil, withExitCode(exitConfigError, err)
 	}
 
 	// 2. Build request
 	request := buildCheckProfileRequest(profilePath, opts)
+	if run != nil {
+		request.Execution.Hooks = append(request.Execution.Hooks, run.Hooks())
+	}
+
+	// 2a. Verify setup prerequisites before spending time on the run
+	if opts.preflight {
+		if err := runPreflight(ctx, c, profilePath, request.Environment, request.Filters, os.Stderr); err != nil {
+			return nil, withExitCode(exitConfigError, err)
+		}
+	}
+
+	// 2b. Continuous verification owns the run loop from here
+	if opts.interval > 0 {
+		return nil, runContinuousCheck(ctx, c, profilePath, opts)
+	}
+
+	// 2c. Open the result stream before execution when streaming
+	if opts.stream {
+		writer, closeWriter, openErr := openOutputWriter(opts)
+		if openErr != nil {
+			return nil, fmt.Errorf("failed to write output: %w", openErr)
+		}
+		defer func() { err = errors.Join(err, closeWriter()) }()
+
+		stream, err := c.OutputFormatterFactory().CreateStream(opts.Format, writer, ports.FormatterOptions{
+			ProfilePath: profilePath,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write output: %w", err)
+		}
+		request.Execution.ResultStream = stream
+		request.Execution.RetainEvidence = len(opts.exporters) > 0
+	}
 
 	// 3. Apply timeout to context
 	ctx, cancel := opts.ApplyToContext(ctx)
 	defer cancel()
 
-	// 4. Execute
-	response, err := c.CheckProfileUseCase().Execute(ctx, request)
+	// 4. Execute, locally or across the cluster agents
+	var response *dto.CheckProfileResponse
+	if opts.distributed {
+		uc, closeAgents, ucErr := c.DistributedCheckUseCase()
+		if ucErr != nil {
+			return nil, ucErr
+		}
+		defer closeAgents()
+		response, err = uc.Execute(ctx, request)
+	} else {
+		response, err = c.CheckProfileUseCase().Execute(ctx, request)
+	}
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
-			return fmt.Errorf("execution exceeded global timeout (%s)", opts.Timeout)
+			return nil, fmt.Errorf("execution exceeded global timeout (%s)", opts.Timeout)
+		}
+		return nil, fmt.Errorf("check failed: %w", err)
+	}
+
+	if !opts.offline {
+		recordRunUsage(response.ExecutionResult)
+	}
+
+	// The run directory is complete once the output and exporters ran
+	if run != nil {
+		defer func() {
+			if commitErr := commitRunDir(run, c.OutputFormatterFactory(), response.ExecutionResult, profilePath, opts); commitErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to write run directory: %w", commitErr))
+			}
+		}()
+	}
+
+	// 4. Write output (already written incrementally when streaming)
+	if !opts.stream {
+		if err := writeOutput(c.OutputFormatterFactory(), response.ExecutionResult, profilePath, opts); err != nil {
+			return response.ExecutionResult, fmt.Errorf("failed to write output: %w", err)
 		}
-		return fmt.Errorf("check failed: %w", err)
 	}
 
-	// 4. Write output
-	if err := writeOutput(c.OutputFormatterFactory(), response.ExecutionResult, profilePath, opts); err != nil {
-		return fmt.Errorf("failed to write output: %w", err)
+	// 5. Run exporters on the finalized result
+	if len(opts.exporters) > 0 {
+		if err := exportResults(ctx, c, response, opts.exporters); err != nil {
+			return response.ExecutionResult, fmt.Errorf("failed to export results: %w", err)
+		}
 	}
 
-	// 5. Verify results
+	// 6. Verify results
 	if c.CheckProfileUseCase().CheckFailed(response.ExecutionResult) {
-		return fmt.Errorf("check failed: %d passed, %d failed, %d errors",
-			response.ExecutionResult.Summary.PassedControls,
-			response.ExecutionResult.Summary.FailedControls,
-			response.ExecutionResult.Summary.ErrorControls)
+		return response.ExecutionResult, findingsError(response.ExecutionResult)
 	}
 
+	return response.ExecutionResult, nil
+}
+
+// validateCheckFlags checks the flags of a run of profileCount profiles and
+// completes the options derived from them.
+func validateCheckFlags(opts *CheckOptions, profileCount int) error {
+	if profileCount > 1 && (opts.outFile != "" || opts.interval > 0) {
+		return fmt.Errorf("--output and --interval need a single profile; the workspace lists %d", profileCount)
+	}
+	if err := opts.ValidateFlags(); err != nil {
+		return err
+	}
+	if opts.stream && opts.Format != "json" && opts.Format != "jsonl" {
+		return fmt.Errorf("--stream requires --format json or jsonl")
+	}
+	if opts.stream && opts.workDir != "" {
+		return fmt.Errorf("--stream cannot be used with --work-dir, whose results.json holds the full result")
+	}
+	if opts.maxEvidenceSize < 0 {
+		return fmt.Errorf("--max-evidence-size must be >= 0")
+	}
+	if opts.maxControls < 0 || opts.maxObservations < 0 {
+		return fmt.Errorf("--max-controls and --max-observations must be >= 0")
+	}
+	if opts.pluginMode != dto.PluginModeWASM && opts.pluginMode != dto.PluginModeNative {
+		return fmt.Errorf("invalid --plugin-mode %q (must be %s or %s)", opts.pluginMode, dto.PluginModeWASM, dto.PluginModeNative)
+	}
+	if opts.preflight && opts.replayCassette != "" {
+		return fmt.Errorf("--preflight cannot be used with --replay, which runs offline")
+	}
+	if opts.recordCassette != "" && opts.replayCassette != "" {
+		return fmt.Errorf("--record and --replay cannot be used together")
+	}
+	if err := validateContinuousFlags(opts); err != nil {
+		return err
+	}
+	if err := validateDistributedFlags(opts); err != nil {
+		return err
+	}
+	if err := validateOfflineFlags(opts); err != nil {
+		return err
+	}
+	for _, spec := range opts.injectFaults {
+		if _, err := hostfuncs.ParseFault(spec); err != nil {
+			return fmt.Errorf("invalid --inject-fault: %w", err)
+		}
+	}
+	clock, err := parseClock(opts.clockTime, opts.timezone)
+	if err != nil {
+		return err
+	}
+	opts.clock = clock
+	if opts.publish != "" {
+		if !slices.Contains(publishTargets, opts.publish) {
+			return fmt.Errorf("invalid --publish %q (must be one of %s)", opts.publish, strings.Join(publishTargets, ", "))
+		}
+		if !slices.Contains(opts.exporters, opts.publish) {
+			opts.exporters = append(opts.exporters, opts.publish)
+		}
+	}
 	return nil
 }
 
+// validateDistributedFlags rejects flags that only apply to local runs.
+func validateDistributedFlags(opts *CheckOptions) error {
+	if !opts.distributed {
+		return nil
+	}
+	switch {
+	case opts.stream:
+		return fmt.Errorf("--distributed cannot be used with --stream")
+	case opts.interval > 0:
+		return fmt.Errorf("--distributed cannot be used with --interval")
+	case opts.recordCassette != "" || opts.replayCassette != "":
+		return fmt.Errorf("--distributed cannot be used with --record or --replay")
+	case opts.preflight:
+		return fmt.Errorf("--distributed cannot be used with --preflight, which checks this host")
+	case opts.allowSudo:
+		return fmt.Errorf("--distributed cannot be used with --allow-sudo, which escalates on this host")
+	case opts.pluginMode != dto.PluginModeWASM:
+		return fmt.Errorf("--distributed requires --plugin-mode %s", dto.PluginModeWASM)
+	case len(opts.injectFaults) > 0 || opts.profilePerf:
+		return fmt.Errorf("--distributed cannot be used with --inject-fault or --profile-perf")
+	case opts.severityRules != "":
+		return fmt.Errorf("--distributed cannot be used with --severity-rules")
+	}
+	return nil
+}
+
+// validateOfflineFlags rejects flags that reach out to the network.
+func validateOfflineFlags(opts *CheckOptions) error {
+	if !opts.offline {
+		return nil
+	}
+	switch {
+	case opts.distributed:
+		return fmt.Errorf("--offline cannot be used with --distributed, which sends the profile to agents")
+	case len(opts.exporters) > 0 || opts.publish != "":
+		return fmt.Errorf("--offline cannot be used with --export or --publish, which send results over the network")
+	case opts.preflight:
+		return fmt.Errorf("--offline cannot be used with --preflight, which resolves target DNS")
+	case opts.pluginMode != dto.PluginModeWASM:
+		return fmt.Errorf("--offline requires --plugin-mode %s: native plugins are not sandboxed", dto.PluginModeWASM)
+	}
+	return nil
+}
+
+// validateContinuousFlags checks the flags of continuous verification.
+func validateContinuousFlags(opts *CheckOptions) error {
+	if opts.interval < 0 {
+		return fmt.Errorf("--interval must be >= 0")
+	}
+	if opts.interval == 0 {
+		if opts.metricsListen != "" {
+			return fmt.Errorf("--metrics-listen needs --interval: a single run ends before it can be scraped")
+		}
+		return nil
+	}
+	if opts.stream {
+		return fmt.Errorf("--interval cannot be used with --stream")
+	}
+	if opts.recordCassette != "" {
+		return fmt.Errorf("--interval cannot be used with --record, which would overwrite the cassette on every run")
+	}
+	if opts.flapThreshold < 2 {
+		return fmt.Errorf("--flap-threshold must be >= 2")
+	}
+	if opts.flapWindow < opts.flapThreshold {
+		return fmt.Errorf("--flap-window must be >= --flap-threshold")
+	}
+	return nil
+}
+
+// runContinuousCheck re-runs the profile every --interval until interrupted.
+// Each run overwrites --output with the full result; only controls that
+// changed status are printed and sent to the exporters. Plugins are
+// hot-reloaded as in "reglet serve".
+func runContinuousCheck(ctx context.Context, c *container.Container, profilePath string, opts *CheckOptions) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	stopWatching, err := c.WatchPlugins(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopWatching()
+
+	var exportService *services.ResultExportService
+	if len(opts.exporters) > 0 {
+		exportService, err = c.ResultExportService(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to export results: %w", err)
+		}
+		defer func() { _ = exportService.Close(context.Background()) }()
+	}
+
+	var exporter *metrics.Exporter
+	if opts.metricsListen != "" {
+		exporter = metrics.NewExporter()
+		closeMetrics, err := serveMetrics(opts.metricsListen, exporter)
+		if err != nil {
+			return withExitCode(exitConfigError, err)
+		}
+		defer closeMetrics()
+	}
+
+	var integrations map[string]map[string]interface{}
+	run := func(ctx context.Context) (*execution.ExecutionResult, error) {
+		runCtx, cancel := opts.ApplyToContext(ctx)
+		defer cancel()
+
+		request := buildCheckProfileRequest(profilePath, opts)
+		if exporter != nil {
+			// The performance breakdown carries the engine statistics
+			request.Execution.ProfilePerf = true
+		}
+		var runDir *rundir.Run
+		if opts.workDir != "" {
+			var restoreLogger func()
+			var err error
+			runDir, restoreLogger, err = startRunDir(opts.workDir)
+			if err != nil {
+				return nil, err
+			}
+			defer restoreLogger()
+			defer runDir.Discard() // unless committed
+			request.Execution.Hooks = append(request.Execution.Hooks, runDir.Hooks())
+		}
+		response, err := c.CheckProfileUseCase().Execute(runCtx, request)
+		if err != nil {
+			return nil, err
+		}
+		integrations = response.Integrations
+		if exporter != nil {
+			exporter.Record(response.ExecutionResult)
+			if !opts.profilePerf {
+				response.ExecutionResult.Performance = nil
+			}
+		}
+		if opts.outFile != "" {
+			if err := writeOutput(c.OutputFormatterFactory(), response.ExecutionResult, profilePath, opts); err != nil {
+				return nil, fmt.Errorf("failed to write output: %w", err)
+			}
+		}
+		if runDir != nil {
+			if err := commitRunDir(runDir, c.OutputFormatterFactory(), response.ExecutionResult, profilePath, opts); err != nil {
+				return nil, fmt.Errorf("failed to write run directory: %w", err)
+			}
+		}
+		return response.ExecutionResult, nil
+	}
+	notify := func(ctx context.Context, delta *execution.ExecutionResult) error {
+		writeTransitions(os.Stdout, delta)
+		if exportService == nil {
+			return nil
+		}
+		return exportService.Export(ctx, delta, opts.exporters, integrations)
+	}
+
+	slog.Info("starting continuous verification", "profile", profilePath, "interval", opts.interval)
+	return c.ContinuousCheckService().Run(ctx, dto.ContinuousCheckOptions{
+		Interval:      opts.interval,
+		FlapWindow:    opts.flapWindow,
+		FlapThreshold: opts.flapThreshold,
+	}, run, notify)
+}
+
+// serveMetrics serves the metrics of exporter at /metrics on addr until the
+// returned function is called.
+func serveMetrics(addr string, exporter *metrics.Exporter) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --metrics-listen: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter.Handler())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("metrics server failed", "error", err)
+		}
+	}()
+	slog.Info("serving Prometheus metrics", "listen", listener.Addr().String())
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}, nil
+}
+
+// writeTransitions prints one line per control that changed status.
+func writeTransitions(w io.Writer, delta *execution.ExecutionResult) {
+	at := delta.StartTime.Format(time.RFC3339)
+	for _, ctrl := range delta.Controls {
+		t := ctrl.Transition
+		change := string(ctrl.Status)
+		switch {
+		case t.Kind == execution.TransitionFlapping:
+			change = fmt.Sprintf("%s, %d status changes within the flap window", ctrl.Status, t.Changes)
+		case t.From != "":
+			change = fmt.Sprintf("%s → %s", t.From, ctrl.Status)
+		}
+		_, _ = fmt.Fprintf(w, "%s  %-9s  %s (%s): %s\n", at, t.Kind, ctrl.ID, ctrl.Name, change)
+	}
+}
+
+// runPreflight checks the profile's setup prerequisites and writes the report
+// to w. It fails when any problem other than a warning is found.
+func runPreflight(ctx context.Context, c *container.Container, profilePath, environment string, filters dto.FilterOptions, w io.Writer) error {
+	report, err := c.PreflightService().Run(ctx, profilePath, environment, filters)
+	if err != nil {
+		return fmt.Errorf("preflight failed: %w", err)
+	}
+
+	writePreflightReport(w, report)
+	if errs := report.ErrorCount(); errs > 0 {
+		return fmt.Errorf("preflight failed: %d setup problem(s), fix them before running", errs)
+	}
+	return nil
+}
+
+// writePreflightReport prints the preflight issues grouped by plugin.
+func writePreflightReport(w io.Writer, report *dto.PreflightReport) {
+	if len(report.Groups) == 0 {
+		_, _ = fmt.Fprintln(w, "✓ Preflight passed")
+		return
+	}
+
+	for _, group := range report.Groups {
+		_, _ = fmt.Fprintf(w, "%s:\n", group.Plugin)
+		for _, issue := range group.Issues {
+			mark := "✗"
+			if issue.Warning {
+				mark = "!"
+			}
+			_, _ = fmt.Fprintf(w, "  %s [%s] %s\n", mark, issue.Check, issue.Message)
+			if len(issue.Controls) > 0 {
+				_, _ = fmt.Fprintf(w, "      affects %d control(s): %s\n", len(issue.Controls), summarizeControls(issue.Controls))
+			}
+			_, _ = fmt.Fprintf(w, "      fix: %s\n", issue.Hint)
+		}
+	}
+	if report.ErrorCount() == 0 {
+		_, _ = fmt.Fprintln(w, "✓ Preflight passed with warnings")
+	}
+}
+
+// maxListedControls caps the control IDs printed per preflight issue.
+const maxListedControls = 5
+
+func summarizeControls(ids []string) string {
+	if len(ids) <= maxListedControls {
+		return strings.Join(ids, ", ")
+	}
+	return fmt.Sprintf("%s, and %d more", strings.Join(ids[:maxListedControls], ", "), len(ids)-maxListedControls)
+}
+
+// parseClock builds the engine clock from --clock and --timezone. It returns
+// nil when neither is set, leaving the system clock in place.
+func parseClock(at, timezone string) (func() time.Time, error) {
+	if at == "" && timezone == "" {
+		return nil, nil
+	}
+
+	loc := time.Local
+	if timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --timezone %q: %w", timezone, err)
+		}
+	}
+
+	if at == "" {
+		return func() time.Time { return time.Now().In(loc) }, nil
+	}
+	fixed, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --clock %q (must be an RFC 3339 time): %w", at, err)
+	}
+	fixed = fixed.In(loc)
+	return func() time.Time { return fixed }, nil
+}
+
+// exportResults sends the execution result to the selected exporters.
+func exportResults(ctx context.Context, c *container.Container, response *dto.CheckProfileResponse, names []string) error {
+	exportService, err := c.ResultExportService(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = exportService.Close(ctx) }()
+
+	return exportService.Export(ctx, response.ExecutionResult, names, projectIntegrations(response.Integrations))
+}
+
 // buildCheckProfileRequest maps CLI flags to a CheckProfileRequest DTO.
 func buildCheckProfileRequest(profilePath string, opts *CheckOptions) dto.CheckProfileRequest {
 	return dto.CheckProfileRequest{
 		ProfilePath: profilePath,
+		Environment: opts.environment,
 		Filters: dto.FilterOptions{
 			IncludeTags:         opts.includeTags,
 			IncludeSeverities:   opts.includeSeverities,
@@ -171,8 +773,26 @@ func buildCheckProfileRequest(profilePath string, opts *CheckOptions) dto.CheckP
 			IncludeDependencies: opts.includeDependencies,
 		},
 		Execution: dto.ExecutionOptions{
-			Parallel: opts.Parallel, // Use common option
-			// MaxConcurrentControls and MaxConcurrentObservations will use defaults (0 = auto-detect)
+			Parallel:                  opts.Parallel, // Use common option
+			MaxEvidenceSizeBytes:      opts.maxEvidenceSize,
+			PluginMode:                opts.pluginMode,
+			Offline:                   opts.offline,
+			PolicyBundle:              opts.policyBundle,
+			SeverityRules:             opts.severityRules,
+			Clock:                     opts.clock,
+			RecordCassette:            opts.recordCassette,
+			ReplayCassette:            opts.replayCassette,
+			InjectFaults:              opts.injectFaults,
+			FaultSeed:                 opts.faultSeed,
+			ProfilePerf:               opts.profilePerf,
+			AllowSudo:                 opts.allowSudo,
+			HTTPCache:                 opts.httpCache,
+			MaxEgressBytes:            opts.maxEgress,
+			DebugHTTP:                 opts.debugHTTP,
+			SkipMissingPlugins:        opts.skipMissingPlugins,
+			NoObservationDedup:        opts.noDedup,
+			MaxConcurrentControls:     opts.maxControls,     // 0 = from the available CPUs
+			MaxConcurrentObservations: opts.maxObservations, // 0 = from the available CPUs
 		},
 		Options: dto.CheckOptions{
 			TrustPlugins: opts.trustPlugins,
@@ -185,33 +805,55 @@ func buildCheckProfileRequest(profilePath string, opts *CheckOptions) dto.CheckP
 
 // writeOutput directs the execution result to the configured output destination.
 func writeOutput(factory ports.OutputFormatterFactory, result *execution.ExecutionResult, profilePath string, opts *CheckOptions) error {
-	var writer io.Writer = os.Stdout
-	if opts.outFile != "" {
-		//nolint:gosec // G304: User-controlled output file path is intentional
-		file, err := os.Create(opts.outFile)
-		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
-		}
-		defer func() {
-			_ = file.Close()
-		}()
-		writer = file
-		slog.Info("writing output", "file", opts.outFile, "format", opts.Format)
+	writer, closeWriter, err := openOutputWriter(opts)
+	if err != nil {
+		return err
 	}
 
-	return formatOutput(factory, writer, result, opts.Format, profilePath)
+	color, width := terminalStyle(writer)
+	err = formatOutput(factory, writer, result, opts.Format, ports.FormatterOptions{
+		Indent:      true,
+		ProfilePath: profilePath,
+		Verbosity:   opts.OutputVerbosity(),
+		Color:       color,
+		Width:       width,
+		Language:    outputLanguage(),
+	})
+	return errors.Join(err, closeWriter())
+}
+
+// openOutputWriter returns the configured output destination (file or stdout)
+// and a function that closes it. A file named *.gz or *.zst is compressed
+// with gzip or zstd.
+func openOutputWriter(opts *CheckOptions) (io.Writer, func() error, error) {
+	if opts.outFile == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+
+	//nolint:gosec // G304: User-controlled output file path is intentional
+	file, err := os.Create(opts.outFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	algorithm := compression.FromPath(opts.outFile)
+	writer, err := compression.NewWriter(file, algorithm)
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	slog.Info("writing output", "file", opts.outFile, "format", opts.Format, "compression", string(algorithm))
+
+	return writer, func() error {
+		if err := errors.Join(writer.Close(), file.Close()); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return nil
+	}, nil
 }
 
 // formatOutput applies the selected formatter to the execution result.
-func formatOutput(factory ports.OutputFormatterFactory, writer io.Writer, result *execution.ExecutionResult, format string, profilePath string) error {
-	formatter, err := factory.Create(
-		format,
-		writer,
-		ports.FormatterOptions{
-			Indent:      true,
-			ProfilePath: profilePath,
-		},
-	)
+func formatOutput(factory ports.OutputFormatterFactory, writer io.Writer, result *execution.ExecutionResult, format string, options ports.FormatterOptions) error {
+	formatter, err := factory.Create(format, writer, options)
 	if err != nil {
 		return err
 	}
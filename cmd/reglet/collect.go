@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/infrastructure/container"
+	"github.com/reglet-dev/reglet/internal/infrastructure/rundir"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newCollectCmd())
+}
+
+func newCollectCmd() *cobra.Command {
+	opts := &CheckOptions{
+		CommonOptions: DefaultCommonOptions(),
+		pluginMode:    dto.PluginModeWASM,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "collect [profile.yaml]",
+		Short: "Collect evidence without evaluating expectations",
+		Long: `Run the observations of a profile and record their raw evidence without
+evaluating expect expressions or thresholds. Controls whose observations all
+returned evidence are "collected"; plugin errors are still reported as errors,
+and skip the controls depending on them.
+
+The result carries "mode": "collect" and is saved by the storage backend of
+the system config like any run, so assessors can gather data first and
+evaluate it later, possibly against different expectations. Evidence is
+redacted and truncated as in "reglet check".
+
+Without a profile argument, the profiles listed in the workspace's
+reglet.yaml are collected.
+
+Collect exits non-zero only when evidence could not be collected.`,
+		Example: `  # Collect evidence into the configured storage backend
+  reglet collect profile.yaml
+
+  # Collect evidence for critical controls and keep a copy of the result
+  reglet collect profile.yaml --severity critical -o evidence.json --format json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profiles, err := profileArgs(args)
+			if err != nil {
+				return err
+			}
+			applyProjectDefaults(cmd, opts)
+			if len(profiles) > 1 && opts.outFile != "" {
+				return fmt.Errorf("--output needs a single profile; the workspace lists %d", len(profiles))
+			}
+
+			if err := opts.ValidateFlags(); err != nil {
+				return err
+			}
+			if opts.maxEvidenceSize < 0 {
+				return fmt.Errorf("--max-evidence-size must be >= 0")
+			}
+			clock, err := parseClock(opts.clockTime, opts.timezone)
+			if err != nil {
+				return err
+			}
+			opts.clock = clock
+
+			if opts.Quiet {
+				quiet = true
+				setupLogging()
+			} else if opts.Verbosity >= ports.VerbosityEvidence {
+				logLevel = "debug"
+				setupLogging()
+			}
+
+			var failed []error
+			for _, profilePath := range profiles {
+				if err := runCollectAction(cmd.Context(), profilePath, opts); err != nil {
+					if len(profiles) == 1 {
+						return err
+					}
+					failed = append(failed, fmt.Errorf("%s: %w", profilePath, err))
+				}
+			}
+			return errors.Join(failed...)
+		},
+	}
+
+	opts.RegisterFlags(cmd)
+
+	cmd.Flags().StringVarP(&opts.outFile, "output", "o", "", "Output file path, compressed when named *.gz or *.zst (default: stdout)")
+	cmd.Flags().StringVar(&opts.environment, "env", "", "Resolve the profile for one of its environments: apply its var overrides and fan {{ .target }} out to its targets")
+	cmd.Flags().BoolVar(&opts.trustPlugins, "trust-plugins", false, "Auto-grant all plugin capabilities (use with caution)")
+	cmd.Flags().StringVar(&opts.securityLevel, "security", "", "Security level: strict, standard, permissive (default: standard or config file)")
+	cmd.Flags().StringVar(&opts.clockTime, "clock", "", "Run with a fixed clock at this RFC 3339 time (reproducible timestamps)")
+	cmd.Flags().StringVar(&opts.timezone, "timezone", "", "IANA time zone for the clock, e.g. UTC or Europe/Berlin (default: local)")
+	cmd.Flags().IntVar(&opts.maxEvidenceSize, "max-evidence-size", 0, "Truncate evidence larger than this many bytes per observation (default: config file or 1MB)")
+	cmd.Flags().BoolVar(&opts.allowSudo, "allow-sudo", false, "Let observations marked privileged read the files reglet is denied access to with \"sudo -n reglet privileged-read\" (needs a sudoers rule for those files)")
+	cmd.Flags().BoolVar(&opts.httpCache, "http-cache", false, "Answer plugin HTTP GET requests from responses fetched earlier in the run while their Cache-Control max-age allows, so controls checking the same endpoint share one request")
+	cmd.Flags().Int64Var(&opts.maxEgress, "max-egress", 0, "Abort the run once plugins would send more than this many bytes in network requests (default: unlimited)")
+	cmd.Flags().BoolVar(&opts.debugHTTP, "debug-http", false, "Log the HTTP requests of failed observations as curl commands with status, timing and header names (values, bodies and query values redacted)")
+	cmd.Flags().BoolVar(&opts.noDedup, "no-dedup-observations", false, "Run identical observations (same plugin and config) of different controls separately instead of running them once and sharing their evidence")
+	cmd.Flags().BoolVar(&opts.offline, "offline", false, "Air-gapped mode: deny plugins network access regardless of grants, never pull plugins, and refuse to start if selected controls need the network")
+	cmd.Flags().StringVar(&opts.workDir, "work-dir", "", "Write the run to a directory of its own in this work directory: results.json, events.jsonl, provenance.json, logs/ and artifacts/, moved into place once complete")
+
+	// Filtering flags
+	cmd.Flags().StringSliceVar(&opts.includeTags, "tags", nil, "Collect evidence for controls with these tags (comma-separated)")
+	cmd.Flags().StringSliceVar(&opts.includeSeverities, "severity", nil, "Collect evidence for controls with these severities (comma-separated)")
+	cmd.Flags().StringSliceVar(&opts.includeControlIDs, "control", nil, "Collect evidence for specific controls by ID (exclusive, comma-separated)")
+	cmd.Flags().StringSliceVar(&opts.excludeTags, "exclude-tags", nil, "Exclude controls with these tags (comma-separated)")
+	cmd.Flags().StringSliceVar(&opts.excludeControlIDs, "exclude-control", nil, "Exclude specific controls by ID (comma-separated)")
+	cmd.Flags().StringVar(&opts.filterExpr, "filter", "", "Advanced filter expression (e.g. \"severity == 'critical'\")")
+	cmd.Flags().BoolVar(&opts.includeDependencies, "include-dependencies", false, "Include dependencies of selected controls")
+
+	return cmd
+}
+
+// runCollectAction runs the profile in collect mode and writes the result.
+func runCollectAction(ctx context.Context, profilePath string, opts *CheckOptions) (err error) {
+	var run *rundir.Run
+	if opts.workDir != "" {
+		var restoreLogger func()
+		run, restoreLogger, err = startRunDir(opts.workDir)
+		if err != nil {
+			return withExitCode(exitConfigError, err)
+		}
+		defer restoreLogger()
+		defer run.Discard() // unless committed
+	}
+
+	c, err := container.New(container.Options{
+		TrustPlugins:     opts.trustPlugins,
+		SecurityLevel:    opts.securityLevel,
+		SystemConfigPath: cfgFile,
+		Logger:           slog.Default(),
+		Offline:          opts.offline,
+		Layers:           layers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize application: %w", err)
+	}
+	ctx, err = namespaceContext(ctx, c)
+	if err != nil {
+		return err
+	}
+	if c.SystemConfig().Storage.Backend == "" {
+		slog.Warn("no storage backend configured: the evidence is only written to the output (set storage.backend to keep it)")
+	}
+
+	request := buildCheckProfileRequest(profilePath, opts)
+	request.Execution.CollectOnly = true
+	if run != nil {
+		request.Execution.Hooks = append(request.Execution.Hooks, run.Hooks())
+	}
+
+	ctx, cancel := opts.ApplyToContext(ctx)
+	defer cancel()
+
+	response, err := c.CheckProfileUseCase().Execute(ctx, request)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("execution exceeded global timeout (%s)", opts.Timeout)
+		}
+		return fmt.Errorf("collect failed: %w", err)
+	}
+
+	result := response.ExecutionResult
+	if run != nil {
+		defer func() {
+			if commitErr := commitRunDir(run, c.OutputFormatterFactory(), result, profilePath, opts); commitErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to write run directory: %w", commitErr))
+			}
+		}()
+	}
+	if err := writeOutput(c.OutputFormatterFactory(), result, profilePath, opts); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if !opts.Quiet {
+		fmt.Fprintf(os.Stderr, "Execution ID: %s\n", result.GetID())
+	}
+
+	if result.Summary.ErrorControls > 0 {
+		return fmt.Errorf("collect failed: %d collected, %d errors",
+			result.Summary.CollectedControls, result.Summary.ErrorControls)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
 	"github.com/reglet-dev/reglet/internal/infrastructure/container"
 	"github.com/spf13/cobra"
 )
@@ -45,16 +46,23 @@ func withContainer(handler CommandHandler) func(*cobra.Command, []string) error
 		c, err := container.New(container.Options{
 			SystemConfigPath: configPath,
 			Logger:           logger,
+			Layers:           layers,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to initialize application: %w", err)
 		}
 
+		// Scope stored results to the selected namespace
+		scoped, err := namespaceContext(cmd.Context(), c)
+		if err != nil {
+			return err
+		}
+
 		// Create command context
 		ctx := &CommandContext{
 			Container: c,
 			Logger:    logger,
-			Context:   cmd.Context(),
+			Context:   scoped,
 		}
 
 		// Execute handler
@@ -62,6 +70,22 @@ func withContainer(handler CommandHandler) func(*cobra.Command, []string) error
 	}
 }
 
+// namespaceContext scopes ctx to the namespace of stored results: the
+// --namespace flag, else storage.namespace from the system config.
+func namespaceContext(ctx context.Context, c *container.Container) (context.Context, error) {
+	ns := namespace
+	if ns == "" {
+		ns = c.SystemConfig().Storage.Namespace
+	}
+	if ns == "" {
+		ns = repositories.DefaultNamespace
+	}
+	if err := repositories.ValidateNamespace(ns); err != nil {
+		return nil, err
+	}
+	return repositories.WithNamespace(ctx, ns), nil
+}
+
 // addCommonFlags adds standard flags to a command.
 // Ensures consistent flag naming across all commands.
 func addCommonFlags(cmd *cobra.Command) {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/reglet-dev/reglet/internal/application/ports"
 	"github.com/spf13/cobra"
 )
 
@@ -16,9 +17,11 @@ type CommonOptions struct {
 	// Execution
 	Timeout time.Duration
 
+	// Verbosity is the number of -v flags
+	Verbosity int
+
 	// Flags (bools grouped for alignment)
 	Parallel bool
-	Verbose  bool
 	Quiet    bool
 
 	// Future: add more as needed
@@ -46,11 +49,19 @@ func (opts *CommonOptions) RegisterFlags(cmd *cobra.Command) {
 
 	// Output
 	cmd.Flags().StringVar(&opts.Format, "format", opts.Format,
-		"Output format: table, json, yaml, junit, sarif")
-	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false,
-		"Verbose output")
+		"Output format: table, json, jsonl, yaml, junit, sarif, html, html-accessible, text")
+	cmd.Flags().CountVarP(&opts.Verbosity, "verbose", "v",
+		"Verbose table output: -v lists every observation, -vv adds evidence snippets and debug logs")
 	cmd.Flags().BoolVarP(&opts.Quiet, "quiet", "q", false,
-		"Quiet output (errors only)")
+		"Quiet output: a single summary line in the table, errors only in logs")
+}
+
+// OutputVerbosity returns the ports.Verbosity tier selected by -q and -v.
+func (opts *CommonOptions) OutputVerbosity() int {
+	if opts.Quiet {
+		return ports.VerbosityQuiet
+	}
+	return min(opts.Verbosity, ports.VerbosityEvidence)
 }
 
 // ApplyToContext applies timeout to context.
@@ -65,16 +76,16 @@ func (opts *CommonOptions) ApplyToContext(ctx context.Context) (context.Context,
 
 // ValidateFlags validates common options.
 func (opts *CommonOptions) ValidateFlags() error {
-	if opts.Verbose && opts.Quiet {
+	if opts.Verbosity > 0 && opts.Quiet {
 		return fmt.Errorf("--verbose and --quiet are mutually exclusive")
 	}
 
 	validFormats := map[string]bool{
-		"table": true, "json": true, "yaml": true,
-		"junit": true, "sarif": true,
+		"table": true, "json": true, "jsonl": true, "yaml": true,
+		"junit": true, "sarif": true, "html": true, "html-accessible": true, "text": true,
 	}
 	if !validFormats[opts.Format] {
-		return fmt.Errorf("invalid format: %s (valid: table, json, yaml, junit, sarif)", opts.Format)
+		return fmt.Errorf("invalid format: %s (valid: table, json, jsonl, yaml, junit, sarif, html, html-accessible, text)", opts.Format)
 	}
 
 	return nil
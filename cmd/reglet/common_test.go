@@ -46,18 +46,18 @@ func TestCommonOptions_ValidateFlags(t *testing.T) {
 		{
 			name: "valid options",
 			opts: CommonOptions{
-				Format:  "table",
-				Verbose: false,
-				Quiet:   false,
+				Format:    "table",
+				Verbosity: 0,
+				Quiet:     false,
 			},
 			wantErr: false,
 		},
 		{
 			name: "verbose and quiet",
 			opts: CommonOptions{
-				Format:  "table",
-				Verbose: true,
-				Quiet:   true,
+				Format:    "table",
+				Verbosity: 1,
+				Quiet:     true,
 			},
 			wantErr: true,
 			errMsg:  "mutually exclusive",
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/spf13/cobra"
+)
+
+// CompareOptions holds the configuration for the compare command.
+type CompareOptions struct {
+	profile      string
+	format       string
+	environments []string
+	ignore       []string
+	failOnDrift  bool
+}
+
+func init() {
+	rootCmd.AddCommand(newCompareCmd())
+}
+
+func newCompareCmd() *cobra.Command {
+	opts := &CompareOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "compare [paths...]",
+		Short: "Compare the latest run of a profile across environments",
+		Long: `Compare saved results of a profile run with --env (reglet check --format json)
+and report configuration drift between environments. The latest run per
+environment is compared control by control: by status, and by every field of
+the evidence the plugins returned, so a setting that differs between staging
+and prod shows up even when both pass.
+
+Paths may be result files or directories, which are searched recursively for
+*.json results, such as a work directory of --work-dir. The default is the
+current directory. Runs without an environment are ignored.`,
+		Example: `  # Save one run per environment, then compare them
+  reglet check web.yaml --env staging --format json -o results/staging.json
+  reglet check web.yaml --env prod --format json -o results/prod.json
+  reglet compare results/
+
+  # Compare two environments, ignoring fields that differ by design
+  reglet compare results/ --env staging,prod --ignore tcp.address,http.response_time_ms
+
+  # Fail a pipeline when environments drift
+  reglet compare results/ --fail-on-drift`,
+		RunE: withContainer(func(ctx *CommandContext, _ *cobra.Command, args []string) error {
+			if opts.format != "table" && opts.format != "json" {
+				return fmt.Errorf("invalid --format %q (must be table or json)", opts.format)
+			}
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+
+			cmp, err := ctx.Container.EnvironmentComparisonService().Compare(ctx.Context, dto.CompareEnvironmentsRequest{
+				Paths:        args,
+				Profile:      opts.profile,
+				Environments: opts.environments,
+				Ignore:       opts.ignore,
+			})
+			if err != nil {
+				return err
+			}
+
+			if opts.format == "json" {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(cmp); err != nil {
+					return err
+				}
+			} else {
+				writeComparison(os.Stdout, cmp)
+			}
+
+			if opts.failOnDrift && cmp.HasDrift() {
+				return withExitCode(exitFindings, fmt.Errorf("%d control(s) drift between environments", len(cmp.Controls)))
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&opts.profile, "profile", "", "Profile name to compare, when the results cover several profiles")
+	cmd.Flags().StringSliceVar(&opts.environments, "env", nil, "Environments to compare, in column order (default: all, by name)")
+	cmd.Flags().StringSliceVar(&opts.ignore, "ignore", nil, "Evidence fields to leave out, as plugin.path (e.g. tcp.address)")
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Report format: table, json")
+	cmd.Flags().BoolVar(&opts.failOnDrift, "fail-on-drift", false, "Exit non-zero when any control drifts")
+
+	return cmd
+}
+
+// writeComparison prints the compared runs, then each drifting control with
+// one row per differing field and one column per environment.
+//
+//nolint:errcheck // Report formatting errors are non-critical (best-effort terminal output)
+func writeComparison(w io.Writer, cmp *execution.EnvironmentComparison) {
+	environments := cmp.EnvironmentNames()
+
+	fmt.Fprintf(w, "Profile: %s\n", cmp.ProfileName)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, run := range cmp.Environments {
+		fmt.Fprintf(tw, "  %s\tv%s\t%s\t%s\n", run.Environment, run.ProfileVersion, run.StartTime.Format(time.RFC3339), run.ExecutionID.String())
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(w)
+
+	if !cmp.HasDrift() {
+		fmt.Fprintf(w, "No drift across %d controls\n", cmp.Compared)
+		return
+	}
+	fmt.Fprintf(w, "%d of %d controls drift\n", len(cmp.Controls), cmp.Compared)
+
+	for _, ctrl := range cmp.Controls {
+		fmt.Fprintf(w, "\n%s  %s\n", ctrl.ID, ctrl.Name)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "  FIELD\t%s\n", strings.ToUpper(strings.Join(environments, "\t")))
+		if ctrl.StatusDrifts(environments) {
+			row := make([]string, len(environments))
+			for i, env := range environments {
+				row[i] = "-"
+				if status, ok := ctrl.Statuses[env]; ok {
+					row[i] = string(status)
+				}
+			}
+			fmt.Fprintf(tw, "  status\t%s\n", strings.Join(row, "\t"))
+		}
+		for _, field := range ctrl.Fields {
+			row := make([]string, len(environments))
+			for i, env := range environments {
+				row[i] = "-"
+				if vals, ok := field.Values[env]; ok {
+					row[i] = strings.Join(vals, ", ")
+				}
+			}
+			fmt.Fprintf(tw, "  %s\t%s\n", field.Field, strings.Join(row, "\t"))
+		}
+		_ = tw.Flush()
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and change configuration",
+	Long: `Inspect and change the configuration reglet runs with. Settings are
+layered, each layer overriding the ones before it:
+
+  1. built-in defaults
+  2. the user config (~/.reglet/config.yaml, or --config)
+  3. the project file (reglet.yaml in the working directory or its parents)
+  4. environment variables (REGLET_<KEY>, e.g. REGLET_SECURITY_LEVEL)
+  5. command-line flags
+
+Lists and maps are replaced as a whole by a later layer, not merged. Use
+get and show to read the effective values, and set to change the user config
+or project file.`,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
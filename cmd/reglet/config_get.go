@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/goccy/go-yaml"
+	"github.com/reglet-dev/reglet/internal/infrastructure/system"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	configCmd.AddCommand(newConfigGetCmd())
+}
+
+func newConfigGetCmd() *cobra.Command {
+	var origin bool
+
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the effective value of a configuration key",
+		Long: `Print the effective value of a configuration key after layering. Lists and
+maps are printed as YAML. Unlike show, get prints sensitive values as they are.`,
+		Example: `  reglet config get security.level
+
+  # Also print the layer the value came from
+  reglet config get storage.backend --origin`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			setting, ok := layers.Lookup(args[0])
+			if !ok || !system.IsConfigKey(args[0]) {
+				return fmt.Errorf("unknown configuration key %q (see reglet config show)", args[0])
+			}
+
+			switch setting.Value.(type) {
+			case []interface{}, map[string]interface{}:
+				data, err := yaml.Marshal(setting.Value)
+				if err != nil {
+					return fmt.Errorf("failed to render %s: %w", setting.Key, err)
+				}
+				if _, err := os.Stdout.Write(data); err != nil {
+					return err
+				}
+			default:
+				fmt.Println(setting.Value)
+			}
+			if origin {
+				fmt.Fprintf(os.Stderr, "origin: %s\n", setting.Origin())
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&origin, "origin", false, "Print the layer the value came from to stderr")
+	addCommonFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/system"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	configCmd.AddCommand(newConfigSetCmd())
+}
+
+func newConfigSetCmd() *cobra.Command {
+	var (
+		inProject bool
+		appendTo  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a configuration key in the user config or project file",
+		Long: `Set a configuration key in the user config (~/.reglet/config.yaml, or
+--config), or with --project in the workspace's reglet.yaml (created in the
+current directory if there is none).
+
+The value is read as YAML: numbers and booleans keep their types, and lists
+and maps can be given in flow style ("[a, b]", "{kind: fs, pattern: /etc/**}").
+--append adds the value to a list instead of replacing it.
+
+Comments and the other keys of the file are kept. The file is only written
+when the key exists and the result is a valid configuration.`,
+		Example: `  # Default to strict capability checks
+  reglet config set security.level strict
+
+  # Grant a capability to all plugins
+  reglet config set capabilities --append '{kind: fs, pattern: "read:/etc/**"}'
+
+  # Make SARIF the project's default output format
+  reglet config set output.format sarif --project`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], system.ParseValue(args[1])
+
+			path := userConfigPath
+			if inProject {
+				if system.UserOnlyKey(key) {
+					return fmt.Errorf("%s can only be set in the user config, not in the project file", key)
+				}
+				path = project.Path
+				if path == "" {
+					cwd, err := os.Getwd()
+					if err != nil {
+						return err
+					}
+					path = filepath.Join(cwd, system.ProjectFileName)
+				}
+			}
+			if path == "" {
+				return fmt.Errorf("no user config file: pass --config")
+			}
+
+			if appendTo {
+				current, _, err := system.ConfigFileValue(path, key)
+				if err != nil {
+					return err
+				}
+				list, ok := current.([]interface{})
+				if current != nil && !ok {
+					return fmt.Errorf("--append: %s is not a list in %s", key, path)
+				}
+				value = append(list, value)
+			}
+
+			if err := system.SetConfigValue(path, key, value); err != nil {
+				return err
+			}
+			fmt.Printf("Set %s in %s\n", key, path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&inProject, "project", false, "Write the workspace's reglet.yaml instead of the user config")
+	cmd.Flags().BoolVar(&appendTo, "append", false, "Append the value to a list key")
+	addCommonFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/goccy/go-yaml"
+	"github.com/reglet-dev/reglet/internal/infrastructure/system"
+	"github.com/spf13/cobra"
+)
+
+// redacted replaces sensitive values in config output.
+const redacted = "<redacted>"
+
+func init() {
+	configCmd.AddCommand(newConfigShowCmd())
+}
+
+func newConfigShowCmd() *cobra.Command {
+	var origin bool
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the effective configuration",
+		Long: `Show the effective configuration after layering defaults, the user config,
+the project file, environment variables and flags. With --origin, each key
+is listed with its value and the layer it came from. Secrets, salts and
+database DSNs are redacted.`,
+		Example: `  # Print the effective configuration as YAML
+  reglet config show
+
+  # Show where each value comes from
+  reglet config show --origin
+
+  # Check which layer wins for the security level
+  REGLET_SECURITY_LEVEL=strict reglet config show --origin`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings := layers.Settings()
+			if origin {
+				return writeSettingOrigins(settings)
+			}
+			return writeSettings(settings)
+		},
+	}
+
+	cmd.Flags().BoolVar(&origin, "origin", false, "List each key with its value and the layer it came from")
+	addCommonFlags(cmd)
+
+	return cmd
+}
+
+// writeSettings prints the effective configuration as YAML.
+func writeSettings(settings []system.Setting) error {
+	nested := make(map[string]interface{})
+	for _, setting := range settings {
+		values := nested
+		path := strings.Split(setting.Key, ".")
+		for _, name := range path[:len(path)-1] {
+			next, ok := values[name].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				values[name] = next
+			}
+			values = next
+		}
+		values[path[len(path)-1]] = settingValue(setting)
+	}
+	data, err := yaml.Marshal(nested)
+	if err != nil {
+		return fmt.Errorf("failed to render configuration: %w", err)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// writeSettingOrigins prints a table of keys, values and origins.
+func writeSettingOrigins(settings []system.Setting) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprintln(w, "KEY\tVALUE\tORIGIN"); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for _, setting := range settings {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", setting.Key, formatSettingValue(setting), setting.Origin()); err != nil {
+			return fmt.Errorf("failed to write setting: %w", err)
+		}
+	}
+	return w.Flush()
+}
+
+// settingValue returns the value of a setting, redacted when sensitive and
+// set.
+func settingValue(setting system.Setting) interface{} {
+	if setting.Sensitive() && !isEmptyValue(setting.Value) {
+		return redacted
+	}
+	return setting.Value
+}
+
+// formatSettingValue renders a value on one line: lists and maps as JSON.
+func formatSettingValue(setting system.Setting) string {
+	switch value := settingValue(setting).(type) {
+	case nil:
+		return ""
+	case string:
+		if value == "" {
+			return `""`
+		}
+		return value
+	case []interface{}, map[string]interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
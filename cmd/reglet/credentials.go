@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// credentialsCmd represents the credentials command
+var credentialsCmd = &cobra.Command{
+	Use:   "credentials",
+	Short: "Manage the encrypted credential store",
+	Long: `Manage named credentials, such as integration tokens and registry logins,
+kept in an encrypted store (~/.reglet/credentials.enc, or
+sensitive_data.secrets.credentials_file). Stored credentials never appear in
+config files or shell history.
+
+Profiles and project files reference a credential by name, like any other
+secret: {{ secret "slack_token" }}. A credential named registry/<host>,
+holding username:password, is used to log in to that plugin registry.
+
+The store is encrypted with a key derived from REGLET_CREDENTIALS_PASSPHRASE,
+which must be set to write it. sensitive_data.secrets.insecure_key_file: true
+keeps a random key in a file next to the store instead; that only keeps
+credentials out of config files and protects nothing against anyone who can
+read the store.`,
+}
+
+func init() {
+	rootCmd.AddCommand(credentialsCmd)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	credentialsCmd.AddCommand(newCredentialsListCmd())
+}
+
+func newCredentialsListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List stored credentials",
+		Long:    `List the names of the stored credentials. Values are never printed.`,
+		Example: `  reglet credentials list`,
+		Args:    cobra.NoArgs,
+		RunE: withContainer(func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+			names, err := ctx.Container.CredentialStore().Names()
+			if err != nil {
+				return fmt.Errorf("failed to list credentials: %w", err)
+			}
+			if len(names) == 0 {
+				fmt.Println("No credentials stored.")
+				return nil
+			}
+			for _, name := range names {
+				fmt.Println(name)
+			}
+			return nil
+		}),
+	}
+
+	addCommonFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	credentialsCmd.AddCommand(newCredentialsRemoveCmd())
+}
+
+func newCredentialsRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove <name>",
+		Short:   "Remove a stored credential",
+		Example: `  reglet credentials remove slack_token`,
+		Args:    cobra.ExactArgs(1),
+		RunE: withContainer(func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+			removed, err := ctx.Container.CredentialStore().Delete(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to remove credential: %w", err)
+			}
+			if !removed {
+				return fmt.Errorf("no credential named %q", args[0])
+			}
+			fmt.Printf("Removed %s\n", args[0])
+			return nil
+		}),
+	}
+
+	addCommonFlags(cmd)
+
+	return cmd
+}
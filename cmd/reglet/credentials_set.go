@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func init() {
+	credentialsCmd.AddCommand(newCredentialsSetCmd())
+}
+
+func newCredentialsSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Store a credential",
+		Long: `Store a credential under a name, replacing any previous value. The value is
+read from a prompt that does not echo it, or from stdin when stdin is not a
+terminal; it is never taken as an argument.`,
+		Example: `  reglet credentials set slack_token
+
+  # Log in to a plugin registry
+  reglet credentials set registry/ghcr.io
+
+  # Read the value from another tool
+  op read op://ci/jira/token | reglet credentials set jira_token`,
+		Args: cobra.ExactArgs(1),
+		RunE: withContainer(func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+			value, err := readCredential(args[0])
+			if err != nil {
+				return err
+			}
+			if value == "" {
+				return errors.New("credential value is empty")
+			}
+
+			store := ctx.Container.CredentialStore()
+			if err := store.Set(args[0], value); err != nil {
+				return fmt.Errorf("failed to store credential: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "Stored %s in %s\n", args[0], store.Path())
+			return nil
+		}),
+	}
+
+	addCommonFlags(cmd)
+
+	return cmd
+}
+
+// readCredential reads a credential value from a terminal prompt without
+// echo, or from stdin.
+func readCredential(name string) (string, error) {
+	fd := int(os.Stdin.Fd()) //nolint:gosec // file descriptors fit in an int
+	if term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read credential: %w", err)
+		}
+		return string(value), nil
+	}
+
+	value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read credential: %w", err)
+	}
+	return strings.TrimRight(value, "\r\n"), nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newEvaluateCmd())
+}
+
+func newEvaluateCmd() *cobra.Command {
+	var (
+		runID       string
+		profilePath string
+		save        bool
+	)
+	opts := &CheckOptions{CommonOptions: DefaultCommonOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "evaluate --run <id> --profile <profile.yaml>",
+		Short: "Evaluate stored evidence against a profile without observing again",
+		Long: `Apply the expect expressions and thresholds of a profile to the evidence a
+stored run collected, without running plugins. Use it to iterate on
+expectations without touching production systems again, typically on runs
+of "reglet collect".
+
+Each observation of the profile is answered with the evidence stored for the
+observation at the same position of the same control. When the plugin or
+config of an observation changed since the run, or its evidence was
+truncated, the observation is an error: collect the evidence again. Controls
+the run did not observe are skipped. Stored evidence is already redacted, so
+expectations on redacted fields see the redaction marker.
+
+The profile is resolved for the environment of the run, and time-relative
+checks evaluate at the time the run started. The result is marked with
+"mode": "evaluate" and the ID of the run; it is only stored with --save.`,
+		Example: `  # Try new expectations on evidence collected earlier
+  reglet collect profile.yaml
+  reglet evaluate --run 0f6c1d1e-5b0a-4c1e-9d3e-2a8c5f7b9e41 --profile profile-v2.yaml
+
+  # Keep the evaluated result
+  reglet evaluate --run <id> --profile profile-v2.yaml --save --format json -o result.json`,
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return opts.ValidateFlags()
+		},
+		RunE: withContainer(func(ctx *CommandContext, _ *cobra.Command, _ []string) error {
+			evaluation, err := ctx.Container.EvidenceEvaluationService()
+			if err != nil {
+				return err
+			}
+
+			runCtx, cancel := opts.ApplyToContext(ctx.Context)
+			defer cancel()
+			result, err := evaluation.Evaluate(runCtx, dto.EvaluateEvidenceRequest{
+				RunID:       runID,
+				ProfilePath: profilePath,
+				Save:        save,
+			})
+			if err != nil {
+				return fmt.Errorf("evaluate failed: %w", err)
+			}
+
+			if err := writeOutput(ctx.Container.OutputFormatterFactory(), result, profilePath, opts); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+			if save && !opts.Quiet {
+				fmt.Fprintf(os.Stderr, "Execution ID: %s\n", result.GetID())
+			}
+
+			if ctx.Container.CheckProfileUseCase().CheckFailed(result) {
+				return findingsError(result)
+			}
+			return nil
+		}),
+	}
+
+	opts.RegisterFlags(cmd)
+	cmd.Flags().StringVar(&runID, "run", "", "Execution ID of the stored run whose evidence is evaluated")
+	cmd.Flags().StringVar(&profilePath, "profile", "", "Profile whose expectations are applied")
+	cmd.Flags().StringVarP(&opts.outFile, "output", "o", "", "Output file path, compressed when named *.gz or *.zst (default: stdout)")
+	cmd.Flags().BoolVar(&save, "save", false, "Store the evaluated result in the storage backend as a new run")
+	_ = cmd.MarkFlagRequired("run")
+	_ = cmd.MarkFlagRequired("profile")
+	return cmd
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	apperrors "github.com/reglet-dev/reglet/internal/application/errors"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+// Exit codes of the reglet CLI, documented in docs/exit-codes.md. Wrappers
+// rely on them: never renumber.
+const (
+	exitPass             = 0 // everything examined passed
+	exitFindings         = 1 // the run completed and found failures
+	exitExecutionError   = 2 // the run could not complete
+	exitConfigError      = 3 // invalid flags, config, profile or setup
+	exitCapabilityDenied = 4 // plugin capabilities were not granted
+)
+
+// exitStatuses names the exit codes in the status file.
+var exitStatuses = map[int]string{
+	exitPass:             "pass",
+	exitFindings:         "findings",
+	exitExecutionError:   "execution_error",
+	exitConfigError:      "config_error",
+	exitCapabilityDenied: "capability_denied",
+}
+
+// exitError attaches an exit code to an error.
+type exitError struct {
+	err  error
+	code int
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode marks err to exit reglet with code; nil stays nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{err: err, code: code}
+}
+
+// exitCodeOf maps the error a command returned to its exit code. Errors
+// joined from several runs exit with the highest of their codes; errors
+// nobody classified are execution errors.
+func exitCodeOf(err error) int {
+	if err == nil {
+		return exitPass
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		code := exitPass
+		for _, e := range joined.Unwrap() {
+			code = max(code, exitCodeOf(e))
+		}
+		return code
+	}
+
+	var exitErr *exitError
+	var capErr *apperrors.CapabilityError
+	var validationErr *apperrors.ValidationError
+	var configErr *apperrors.ConfigurationError
+	var offlineErr *apperrors.OfflineError
+	switch {
+	case errors.As(err, &exitErr):
+		return exitErr.code
+	case errors.As(err, &capErr):
+		return exitCapabilityDenied
+	case errors.As(err, &validationErr), errors.As(err, &configErr), errors.As(err, &offlineErr):
+		return exitConfigError
+	}
+	return exitExecutionError
+}
+
+// findingsError reports the failed and errored controls of a result: an
+// execution error when a control could not be evaluated, findings otherwise.
+func findingsError(result *execution.ExecutionResult) error {
+	err := fmt.Errorf("check failed: %d passed, %d failed, %d errors",
+		result.Summary.PassedControls, result.Summary.FailedControls, result.Summary.ErrorControls)
+	if result.Summary.ErrorControls > 0 {
+		return withExitCode(exitExecutionError, err)
+	}
+	return withExitCode(exitFindings, err)
+}
+
+// runStatus is the summary --status-file writes, so wrappers can act on a
+// run without parsing its report.
+type runStatus struct {
+	Status     string          `json:"status"`
+	ExitCode   int             `json:"exit_code"`
+	Error      string          `json:"error,omitempty"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Summary    statusSummary   `json:"summary"`
+	Profiles   []profileStatus `json:"profiles,omitempty"`
+}
+
+// statusSummary counts controls over all profiles of the run.
+type statusSummary struct {
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Errors  int `json:"errors"`
+	Skipped int `json:"skipped"`
+}
+
+// profileStatus is the outcome of one profile of the run.
+type profileStatus struct {
+	Profile     string `json:"profile"`
+	Status      string `json:"status"`
+	ExitCode    int    `json:"exit_code"`
+	ExecutionID string `json:"execution_id,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// add records the outcome of a profile; result is nil when it did not run.
+func (s *runStatus) add(profile string, result *execution.ExecutionResult, err error) {
+	code := exitCodeOf(err)
+	entry := profileStatus{Profile: profile, Status: exitStatuses[code], ExitCode: code}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if result != nil {
+		if id := result.GetID(); !id.IsZero() {
+			entry.ExecutionID = id.String()
+		}
+		s.Summary.Total += result.Summary.TotalControls
+		s.Summary.Passed += result.Summary.PassedControls
+		s.Summary.Failed += result.Summary.FailedControls
+		s.Summary.Errors += result.Summary.ErrorControls
+		s.Summary.Skipped += result.Summary.SkippedControls
+	}
+	s.Profiles = append(s.Profiles, entry)
+}
+
+// writeStatusFile writes the status of a run that returned err to path,
+// atomically so wrappers never read a partial file.
+func writeStatusFile(path string, status *runStatus, err error) error {
+	status.ExitCode = exitCodeOf(err)
+	status.Status = exitStatuses[status.ExitCode]
+	status.Error = ""
+	if err != nil {
+		status.Error = err.Error()
+	}
+	status.FinishedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write status file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write status file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	apperrors "github.com/reglet-dev/reglet/internal/application/errors"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCodeOf(t *testing.T) {
+	t.Parallel()
+
+	capErr := fmt.Errorf("check failed: %w", apperrors.NewCapabilityError("capability grant failed", nil))
+	findings := withExitCode(exitFindings, errors.New("check failed: 1 failed"))
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, exitPass},
+		{"marked", findings, exitFindings},
+		{"capability", capErr, exitCapabilityDenied},
+		{"validation", fmt.Errorf("check failed: %w", apperrors.NewValidationError("profile", "failed to load profile")), exitConfigError},
+		{"configuration", apperrors.NewConfigurationError("engine", "failed to create engine", nil), exitConfigError},
+		{"offline", &apperrors.OfflineError{}, exitConfigError},
+		{"unclassified", errors.New("execution exceeded global timeout"), exitExecutionError},
+		{"joined takes the highest", errors.Join(fmt.Errorf("a.yaml: %w", findings), fmt.Errorf("b.yaml: %w", capErr)), exitCapabilityDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, exitCodeOf(tt.err))
+		})
+	}
+}
+
+func TestFindingsError(t *testing.T) {
+	t.Parallel()
+
+	failed := &execution.ExecutionResult{Summary: execution.ResultSummary{PassedControls: 2, FailedControls: 1}}
+	assert.Equal(t, exitFindings, exitCodeOf(findingsError(failed)))
+	assert.EqualError(t, findingsError(failed), "check failed: 2 passed, 1 failed, 0 errors")
+
+	errored := &execution.ExecutionResult{Summary: execution.ResultSummary{FailedControls: 1, ErrorControls: 1}}
+	assert.Equal(t, exitExecutionError, exitCodeOf(findingsError(errored)))
+}
+
+func TestWriteStatusFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "status.json")
+	status := &runStatus{}
+	status.add("a.yaml", &execution.ExecutionResult{Summary: execution.ResultSummary{TotalControls: 3, PassedControls: 3}}, nil)
+	bErr := findingsError(&execution.ExecutionResult{Summary: execution.ResultSummary{TotalControls: 2, PassedControls: 1, FailedControls: 1}})
+	status.add("b.yaml", &execution.ExecutionResult{Summary: execution.ResultSummary{TotalControls: 2, PassedControls: 1, FailedControls: 1}}, bErr)
+
+	require.NoError(t, writeStatusFile(path, status, errors.Join(fmt.Errorf("b.yaml: %w", bErr))))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var got runStatus
+	require.NoError(t, json.Unmarshal(data, &got))
+
+	assert.Equal(t, "findings", got.Status)
+	assert.Equal(t, exitFindings, got.ExitCode)
+	assert.Contains(t, got.Error, "b.yaml: check failed")
+	assert.Equal(t, statusSummary{Total: 5, Passed: 4, Failed: 1}, got.Summary)
+	require.Len(t, got.Profiles, 2)
+	assert.Equal(t, profileStatus{Profile: "a.yaml", Status: "pass", ExitCode: exitPass}, got.Profiles[0])
+	assert.Equal(t, "findings", got.Profiles[1].Status)
+	assert.False(t, got.FinishedAt.IsZero())
+
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
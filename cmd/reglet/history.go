@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/compression"
+	"github.com/reglet-dev/reglet/internal/infrastructure/rundir"
+	"github.com/spf13/cobra"
+)
+
+// historyCmd groups commands working on stored results.
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Manage stored execution results",
+	Long: `Manage execution results kept by the storage backend of the system config
+(storage.backend: file or postgres). Export runs to an archive and import them
+elsewhere, for example from an air-gapped machine into a central repository.`,
+}
+
+func init() {
+	historyCmd.AddCommand(newHistoryExportCmd(), newHistoryImportCmd())
+	rootCmd.AddCommand(historyCmd)
+}
+
+func newHistoryExportCmd() *cobra.Command {
+	var runIDs []string
+
+	cmd := &cobra.Command{
+		Use:   "export --run <id> <file>",
+		Short: "Export stored runs to an archive",
+		Long: `Write stored runs to a result archive. Each result is archived exactly as
+stored, with its SHA-256 digest, together with the host and reglet version
+that exported it. Import the archive on another machine with
+"reglet history import".`,
+		Example: `  # Export a run from an air-gapped machine
+  reglet history export --run 0f6c1d1e-5b0a-4c1e-9d3e-2a8c5f7b9e41 run.json
+
+  # Export several runs, compressed with zstd
+  reglet history export --run <id> --run <id> runs.json.zst`,
+		Args: cobra.ExactArgs(1),
+		RunE: withContainer(func(ctx *CommandContext, _ *cobra.Command, args []string) error {
+			history, err := ctx.Container.ResultHistoryService()
+			if err != nil {
+				return err
+			}
+
+			archive, err := history.Export(ctx.Context, runIDs)
+			if err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(archive, "", "  ")
+			if err != nil {
+				return err
+			}
+			// An archive named *.gz or *.zst is compressed
+			data, err = compression.Compress(append(data, '\n'), compression.FromPath(args[0]))
+			if err != nil {
+				return fmt.Errorf("failed to write archive: %w", err)
+			}
+			if err := os.WriteFile(args[0], data, 0o600); err != nil {
+				return fmt.Errorf("failed to write archive: %w", err)
+			}
+
+			fmt.Printf("Exported %d run(s) to %s\n", len(archive.Results), args[0])
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringSliceVar(&runIDs, "run", nil, "Execution ID of a run to export (repeatable)")
+	_ = cmd.MarkFlagRequired("run")
+	return cmd
+}
+
+func newHistoryImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <file|run-dir>...",
+		Short: "Import runs from archives or run directories",
+		Long: `Import result archives written by "reglet history export" into the storage
+backend. Every result is checked against its digest and stored unchanged,
+keeping its execution ID, version and timestamps. Runs already stored are
+skipped, so importing an archive twice is harmless. An archive is imported
+completely or not at all: a tampered result, or a run stored with different
+content, fails the import.
+
+A run directory written with --work-dir is imported like an archive holding
+its results.json, once its files match the sizes and digests of its
+manifest.json. Archives compressed with gzip or zstd are read as they are.`,
+		Example: `  # Import into the central repository
+  reglet history import run.json --config central.yaml
+
+  # Import the run directories of a work directory
+  reglet history import runs/*/ --config central.yaml`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: withContainer(func(ctx *CommandContext, _ *cobra.Command, args []string) error {
+			history, err := ctx.Container.ResultHistoryService()
+			if err != nil {
+				return err
+			}
+
+			for _, path := range args {
+				archive, err := readArchive(path)
+				if err != nil {
+					return err
+				}
+
+				report, err := history.Import(ctx.Context, archive)
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				if rundir.IsRunDir(path) {
+					fmt.Printf("%s: imported %d run(s), %d already stored\n", path, len(report.Imported), len(report.Skipped))
+					continue
+				}
+
+				from := report.Source.Hostname
+				if from == "" {
+					from = "unknown host"
+				}
+				fmt.Printf("%s: imported %d run(s), %d already stored (exported from %s on %s)\n",
+					path, len(report.Imported), len(report.Skipped), from, archive.ExportedAt.Format("2006-01-02 15:04:05 MST"))
+			}
+			return nil
+		}),
+	}
+}
+
+// readArchive reads a result archive, or wraps the result of a run
+// directory in one once its files match its manifest.
+func readArchive(path string) (*execution.ResultArchive, error) {
+	if rundir.IsRunDir(path) {
+		if err := rundir.Verify(path); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		data, err := os.ReadFile(rundir.ResultPath(path)) //nolint:gosec // G304: user-specified run directory is intentional
+		if err != nil {
+			return nil, fmt.Errorf("failed to read run directory: %w", err)
+		}
+		var result execution.ExecutionResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("%s: failed to parse %s: %w", path, rundir.ResultsFile, err)
+		}
+		archive := execution.NewResultArchive(execution.ArchiveSource{RegletVersion: result.RegletVersion}, time.Now())
+		if err := archive.Add(&result); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return archive, nil
+	}
+
+	data, err := compression.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	var archive execution.ResultArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("%s: failed to parse archive: %w", path, err)
+	}
+	return &archive, nil
+}
@@ -6,9 +6,12 @@ import (
 
 // pluginsCmd represents the plugins command
 var pluginsCmd = &cobra.Command{
-	Use:   "plugins",
-	Short: "Manage plugins",
-	Long:  `Manage plugins for Reglet using OCI registries. Pull, list, push, and prune plugins.`,
+	Use:     "plugins",
+	Aliases: []string{"plugin"},
+	Short:   "Manage plugins",
+	Long: `Manage plugins for Reglet using OCI registries. Pull, list, push, and prune plugins,
+install, update, and remove plugins from the plugin index, generate plugin
+config code with gen, and build plugins for WASM and native targets with build.`,
 }
 
 func init() {
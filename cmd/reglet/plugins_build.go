@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/pluginbuild"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	pluginsCmd.AddCommand(newPluginsBuildCmd())
+}
+
+func newPluginsBuildCmd() *cobra.Command {
+	var (
+		targets   []string
+		outDir    string
+		version   string
+		commit    string
+		buildTime string
+		goVersion string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "build [plugin-dir...]",
+		Short: "Build plugins reproducibly for WASM and native targets",
+		Long: `Build plugins reproducibly for one or more targets.
+
+Each argument is a plugin directory, or a pattern ending in /... that stands
+for every plugin directory below it. The wasm target builds a WASM plugin;
+os/arch targets (linux/amd64, linux/arm64, darwin/amd64, darwin/arm64,
+windows/amd64, windows/arm64) build the plugin as an external process plugin
+executable, whose main package must not be restricted to wasip1.
+
+Builds are reproducible: paths, build IDs and VCS stamps are stripped, so the
+same sources built with the same Go version produce the same bytes. --go pins
+the Go toolchain version (downloading it if needed). The version, commit,
+build time and Go version are embedded into the metadata the plugin's
+describe() reports. The build time defaults to SOURCE_DATE_EPOCH or the
+commit time, never the current time.
+
+Artifacts are written to <out>/<plugin>/, and their SHA-256 checksums to
+<out>/SHA256SUMS.`,
+		Example: `  # Build all bundled plugins for WASM
+  reglet plugin build ./plugins/...
+
+  # Build a plugin for ARM64 Linux and macOS hosts with a pinned Go version
+  reglet plugin build ./my-plugin --target linux/arm64,darwin/arm64 --go 1.25.5 --version 1.2.0`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			dirs, err := pluginbuild.Discover(args)
+			if err != nil {
+				return err
+			}
+
+			usedGo, err := pluginbuild.GoVersion(ctx, dirs[0], goVersion)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Building %d plugin(s) with %s\n", len(dirs), usedGo)
+
+			var artifacts []pluginbuild.Artifact
+			for _, dir := range dirs {
+				opts := pluginbuild.Options{
+					OutDir:    outDir,
+					Version:   version,
+					Commit:    commit,
+					BuildTime: buildTime,
+					GoVersion: goVersion,
+					Targets:   targets,
+				}
+				if opts.Commit == "" {
+					opts.Commit = pluginbuild.GitCommit(ctx, dir)
+				}
+				if opts.BuildTime == "" {
+					opts.BuildTime = pluginbuild.SourceDate(ctx, dir)
+				}
+
+				built, err := pluginbuild.Build(ctx, dir, opts)
+				if err != nil {
+					return err
+				}
+				for _, a := range built {
+					fmt.Printf("✓ Built %s (%s)\n", a.Path, a.Target)
+				}
+				artifacts = append(artifacts, built...)
+			}
+
+			if err := pluginbuild.WriteChecksums(outDir, artifacts); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Wrote checksums to %s/%s\n", outDir, pluginbuild.ChecksumFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&targets, "target", []string{pluginbuild.WASM}, "Build targets: wasm or os/arch (repeatable or comma-separated)")
+	cmd.Flags().StringVarP(&outDir, "out", "o", "dist/plugins", "Output directory")
+	cmd.Flags().StringVar(&version, "version", "", "Version reported by describe() (default: the version the plugin declares)")
+	cmd.Flags().StringVar(&commit, "commit", "", "Commit reported by describe() (default: the checked out git commit)")
+	cmd.Flags().StringVar(&buildTime, "build-time", "", "Build time reported by describe() (default: SOURCE_DATE_EPOCH or the commit time)")
+	cmd.Flags().StringVar(&goVersion, "go", "", "Pin the Go toolchain version, e.g. 1.25.5")
+
+	return cmd
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/plugins/delta"
+	ociplugin "github.com/reglet-dev/reglet/internal/infrastructure/plugins/oci"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	pluginsCmd.AddCommand(newPluginsDiffCmd())
+}
+
+func newPluginsDiffCmd() *cobra.Command {
+	var (
+		outFile string
+		version string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <base.wasm> <new.wasm>",
+		Short: "Create a binary patch between two plugin versions",
+		Long: `Create a zstd patch turning one plugin binary into another, for publishing
+next to the new version so clients with the old version cached download only
+what changed.
+
+Push the patch to the plugin repository under the printed tag, as a layer of
+media type ` + ociplugin.MediaTypePatch + `
+annotated with ` + ociplugin.AnnotationPatchTarget + `=<digest of new.wasm>, and with
+the plugin metadata as config. Clients verify the patched binary against that
+digest and pull the new version in full when a patch is missing or does not
+match.`,
+		Example: `  reglet plugins diff file-1.0.0.wasm file-1.1.0.wasm --version 1.1.0 -o file-1.1.0.patch`,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			base, err := os.ReadFile(filepath.Clean(args[0]))
+			if err != nil {
+				return fmt.Errorf("failed to read base plugin: %w", err)
+			}
+			target, err := os.ReadFile(filepath.Clean(args[1]))
+			if err != nil {
+				return fmt.Errorf("failed to read new plugin: %w", err)
+			}
+
+			patch, err := delta.Create(base, target)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(outFile, patch, 0o600); err != nil {
+				return fmt.Errorf("failed to write patch: %w", err)
+			}
+
+			baseSum, targetSum := sha256.Sum256(base), sha256.Sum256(target)
+			baseDigest, err := values.NewDigest("sha256", hex.EncodeToString(baseSum[:]))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %s (%d bytes, %.1f%% of %s)\n", outFile, len(patch), 100*float64(len(patch))/float64(max(len(target), 1)), args[1])
+			fmt.Printf("Tag:    %s\n", ociplugin.PatchTag(version, baseDigest))
+			fmt.Printf("Target: sha256:%s\n", hex.EncodeToString(targetSum[:]))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outFile, "output", "o", "plugin.patch", "Patch file to write")
+	cmd.Flags().StringVar(&version, "version", "", "Version of the new plugin, used for the patch tag")
+	_ = cmd.MarkFlagRequired("version")
+
+	return cmd
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/spf13/cobra"
+)
+
+// doctorColumns are the checks shown in the plugin health table, in order.
+var doctorColumns = []string{
+	dto.PluginCheckLoad,
+	dto.PluginCheckDescribe,
+	dto.PluginCheckSchema,
+	dto.PluginCheckABI,
+	dto.PluginCheckSelfTest,
+	dto.PluginCheckDigest,
+	dto.PluginCheckSignature,
+}
+
+func init() {
+	pluginsCmd.AddCommand(newPluginsDoctorCmd())
+}
+
+func newPluginsDoctorCmd() *cobra.Command {
+	var verifySignatures bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor [plugin...]",
+		Short: "Check that installed plugins load and work",
+		Long: `Check the health of the plugins in the plugin directory and the local cache,
+to catch corrupt or stale WASM files before a scheduled run fails.
+
+Each plugin is compiled and its describe() and schema() functions called; the
+SDK and minimum host version it reports must match this reglet, and plugins
+with a self-test (sdk.SelfTester) must pass it. Cached plugins must also match
+the digest recorded when they were pulled, and with --verify-signatures their
+registry signature.
+
+Exits with an error when any plugin fails a check.`,
+		Example: `  # Check every installed plugin
+  reglet plugins doctor
+
+  # Check the http and dns plugins, including registry signatures
+  reglet plugins doctor http dns --verify-signatures`,
+		RunE: withContainer(func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+			// Without a plugin directory, only the cache is checked
+			pluginDir, err := ctx.Container.PluginDir(ctx.Context)
+			if err != nil {
+				ctx.Logger.Debug("no plugin directory, checking cached plugins only", "error", err)
+				pluginDir = ""
+			}
+
+			report, err := ctx.Container.PluginDoctorService().Diagnose(ctx.Context, dto.PluginDoctorRequest{
+				PluginDir:        pluginDir,
+				Names:            args,
+				VerifySignatures: verifySignatures,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to check plugins: %w", err)
+			}
+
+			if len(report.Plugins) == 0 {
+				fmt.Println("No plugins installed.")
+				return nil
+			}
+			if err := writeDoctorTable(report); err != nil {
+				return err
+			}
+
+			if unhealthy := report.UnhealthyCount(); unhealthy > 0 {
+				return withExitCode(exitFindings, fmt.Errorf("%d of %d plugin(s) unhealthy", unhealthy, len(report.Plugins)))
+			}
+			fmt.Printf("\nAll %d plugin(s) healthy.\n", len(report.Plugins))
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVar(&verifySignatures, "verify-signatures", false, "Verify the registry signature of cached plugins (needs network access)")
+	addCommonFlags(cmd)
+
+	return cmd
+}
+
+// writeDoctorTable prints one row of check outcomes per plugin, followed by
+// the reasons of the failed checks.
+func writeDoctorTable(report *dto.PluginDoctorReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprint(w, "PLUGIN\tSOURCE\tVERSION"); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for _, column := range doctorColumns {
+		if _, err := fmt.Fprintf(w, "\t%s", strings.ToUpper(column)); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for _, plugin := range report.Plugins {
+		version := plugin.Version
+		if version == "" {
+			version = "-"
+		}
+		outcomes := make(map[string]string, len(plugin.Checks))
+		for _, check := range plugin.Checks {
+			outcomes[check.Name] = check.Status
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s", plugin.Name, plugin.Source, version); err != nil {
+			return fmt.Errorf("failed to write plugin health: %w", err)
+		}
+		for _, column := range doctorColumns {
+			cell := "-"
+			switch outcomes[column] {
+			case dto.PluginCheckOK:
+				cell = "ok"
+			case dto.PluginCheckFailed:
+				cell = "FAIL"
+			}
+			if _, err := fmt.Fprintf(w, "\t%s", cell); err != nil {
+				return fmt.Errorf("failed to write plugin health: %w", err)
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return fmt.Errorf("failed to write plugin health: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+
+	for _, plugin := range report.Plugins {
+		if plugin.Healthy() {
+			continue
+		}
+		fmt.Printf("\n%s (%s):\n", plugin.Name, plugin.Path)
+		for _, check := range plugin.Checks {
+			if check.Status == dto.PluginCheckFailed {
+				fmt.Printf("  %s: %s\n", check.Name, check.Message)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/plugingen"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	pluginsCmd.AddCommand(newPluginsGenCmd())
+}
+
+func newPluginsGenCmd() *cobra.Command {
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "gen [plugin-dir]",
+		Short: "Generate a plugin's config struct and JSON Schema",
+		Long: `Generate a plugin's Go config struct and JSON Schema from its config.yaml.
+
+config.yaml is the single source of truth for the plugin's configuration.
+From it, gen writes config_gen.go, holding the config struct with validator
+tags and a parse function that applies defaults, and schema.json, which the
+generated code embeds for the plugin's schema() export. Both files are
+regenerated together, so the reported schema and the unmarshaling code
+cannot drift apart.`,
+		Example: `  # Regenerate the config of the plugin in the current directory
+  reglet plugin gen
+
+  # Fail if generated files are out of date (for CI)
+  reglet plugin gen ./plugins/file --check`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			outputs, err := plugingen.Render(dir)
+			if err != nil {
+				return err
+			}
+
+			if check {
+				if stale := plugingen.Stale(outputs); len(stale) > 0 {
+					return fmt.Errorf("generated files are out of date, run 'reglet plugin gen': %s", strings.Join(stale, ", "))
+				}
+				fmt.Println("✓ Generated files are up to date")
+				return nil
+			}
+
+			if err := plugingen.Write(outputs); err != nil {
+				return err
+			}
+			for _, out := range outputs {
+				fmt.Printf("✓ Generated %s\n", out.Path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "Verify generated files are current instead of writing them")
+
+	return cmd
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	pluginsCmd.AddCommand(newPluginsInstallCmd())
+}
+
+func newPluginsInstallCmd() *cobra.Command {
+	var (
+		index           string
+		verifySignature bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install <namespace/name[@version]>...",
+		Short: "Install plugins from the plugin index",
+		Long: `Install plugins from the plugin index configured as registry.index (or
+--index), an HTTPS URL or a file listing the releases of each plugin with
+their source and SHA-256 digest.
+
+The highest release the version constraint accepts is pulled from its OCI
+registry or downloaded from its HTTPS URL, checked against the digest in the
+index, and installed in the plugin directory as
+<plugin_dir>/<namespace>/<name>/<version>/<name>.wasm, where declarations such
+as reglet/http@1.2 find it. The highest installed release also serves
+declarations without a version.`,
+		Example: `  # Install a release
+  reglet plugins install reglet/http@1.2.0
+
+  # Install the highest 1.x release, checking its signature
+  reglet plugins install reglet/http@^1 --verify-signature
+
+  # Install from another index
+  reglet plugins install reglet/file --index https://plugins.corp.example/index.yaml`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: withContainer(func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+			service := ctx.Container.PluginInstallService(ctx.Context, index)
+			for _, declaration := range args {
+				plugin, err := service.Install(ctx.Context, declaration, verifySignature)
+				if err != nil {
+					return fmt.Errorf("failed to install plugin: %w", err)
+				}
+				fmt.Printf("Installed %s %s: %s\n", plugin.Name, plugin.Version, plugin.Path)
+			}
+			return nil
+		}),
+	}
+
+	addIndexFlags(cmd, &index, &verifySignature)
+	addCommonFlags(cmd)
+
+	return cmd
+}
+
+// addIndexFlags adds the flags of the commands installing plugins from the
+// plugin index.
+func addIndexFlags(cmd *cobra.Command, index *string, verifySignature *bool) {
+	cmd.Flags().StringVar(index, "index", "", "HTTPS URL or path of the plugin index (overrides registry.index)")
+	cmd.Flags().BoolVar(verifySignature, "verify-signature", false, "Require a valid signature on plugins pulled from OCI registries")
+}
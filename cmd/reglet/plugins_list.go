@@ -13,13 +13,21 @@ func init() {
 }
 
 func newPluginsListCmd() *cobra.Command {
+	var installed bool
+
 	cmd := &cobra.Command{
-		Use:     "list",
-		Short:   "List cached plugins",
-		Long:    `List all plugins currently available in the local cache.`,
-		Example: `  reglet plugins list`,
-		Args:    cobra.NoArgs,
+		Use:   "list",
+		Short: "List cached plugins",
+		Long: `List all plugins currently available in the local cache, or with --installed
+the plugins installed in the plugin directory with reglet plugins install.`,
+		Example: `  reglet plugins list
+  reglet plugins list --installed`,
+		Args: cobra.NoArgs,
 		RunE: withContainer(func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+			if installed {
+				return listInstalledPlugins(ctx)
+			}
+
 			// Service call
 			plugins, err := ctx.Container.PluginService().ListCachedPlugins(ctx.Context)
 			if err != nil {
@@ -62,7 +70,38 @@ func newPluginsListCmd() *cobra.Command {
 		}),
 	}
 
+	cmd.Flags().BoolVar(&installed, "installed", false, "List the plugins installed in the plugin directory")
 	addCommonFlags(cmd)
 
 	return cmd
 }
+
+// listInstalledPlugins prints the plugins installed from the plugin index.
+func listInstalledPlugins(ctx *CommandContext) error {
+	plugins, err := ctx.Container.PluginInstallService(ctx.Context, "").List(ctx.Context)
+	if err != nil {
+		return fmt.Errorf("failed to list plugins: %w", err)
+	}
+	if len(plugins) == 0 {
+		fmt.Println("No plugins installed.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprintln(w, "NAME\tVERSION\tDIGEST\tSOURCE"); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for _, p := range plugins {
+		digest := p.Digest
+		if len(digest) > 19 {
+			digest = digest[:19]
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Name, p.Version, digest, p.Source); err != nil {
+			return fmt.Errorf("failed to write plugin info: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+	return nil
+}
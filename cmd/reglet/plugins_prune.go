@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/reglet-dev/reglet/internal/application/dto"
 	"github.com/spf13/cobra"
 )
 
@@ -11,28 +13,67 @@ func init() {
 }
 
 func newPluginsPruneCmd() *cobra.Command {
-	var keepVersions int
+	var (
+		keepVersions int
+		cacheMaxAge  time.Duration
+		dryRun       bool
+	)
 
 	cmd := &cobra.Command{
-		Use:   "prune",
-		Short: "Remove old plugin versions",
-		Long:  `Remove older versions of plugins from the local cache, keeping only the most recent ones.`,
-		Example: `  # Keep last 3 versions of each plugin (default is 5)
-  reglet plugins prune --keep 3`,
-		Args: cobra.NoArgs,
+		Use:   "prune [workspace]",
+		Short: "Remove unused plugin versions and old compiled plugins",
+		Long: `Remove plugin versions from the local cache that no profile or reglet.lock
+in the workspace references, and compiled plugin code older than
+--cache-max-age. The workspace defaults to the directory of the reglet.yaml
+project file, or the current directory.
+
+A profile keeps the highest cached version its declaration accepts; a lockfile
+keeps the version it pins. --keep additionally keeps the newest unreferenced
+versions of each plugin. Embedded plugins are never removed.`,
+		Example: `  # Show what would be removed for the profiles in ./compliance
+  reglet plugins prune ./compliance --dry-run
+
+  # Keep the last 2 unreferenced versions of each plugin
+  reglet plugins prune --keep 2
+
+  # Age out compiled plugins not rebuilt in a week
+  reglet plugins prune --cache-max-age 168h`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: withContainer(func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
-			// Service call
-			err := ctx.Container.PluginService().PruneCache(ctx.Context, keepVersions)
+			workspace := project.Dir()
+			if len(args) == 1 {
+				workspace = args[0]
+			}
+
+			report, err := ctx.Container.PluginPruneService().Prune(ctx.Context, dto.PrunePluginsRequest{
+				Workspace:   workspace,
+				Keep:        keepVersions,
+				CacheMaxAge: cacheMaxAge,
+				DryRun:      dryRun,
+			})
 			if err != nil {
 				return fmt.Errorf("failed to prune cache: %w", err)
 			}
 
-			fmt.Printf("Cache pruned. Kept %d latest versions per plugin.\n", keepVersions)
+			verb := "Removed"
+			if report.DryRun {
+				verb = "Would remove"
+			}
+			for _, ref := range report.Removed {
+				fmt.Printf("%s %s\n", verb, ref)
+			}
+			for _, entry := range report.CacheEntries {
+				fmt.Printf("%s compiled %s\n", verb, entry)
+			}
+			fmt.Printf("%s %d plugin version(s) and %d compilation cache entr(ies); kept %d plugin version(s).\n",
+				verb, len(report.Removed), len(report.CacheEntries), report.Kept)
 			return nil
 		}),
 	}
 
-	cmd.Flags().IntVar(&keepVersions, "keep", 5, "Number of versions to keep per plugin")
+	cmd.Flags().IntVar(&keepVersions, "keep", 0, "Number of unreferenced versions to keep per plugin")
+	cmd.Flags().DurationVar(&cacheMaxAge, "cache-max-age", 30*24*time.Hour, "Remove compiled plugins older than this (0 keeps all)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be removed without removing it")
 	addCommonFlags(cmd)
 
 	return cmd
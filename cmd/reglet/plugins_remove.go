@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	pluginsCmd.AddCommand(newPluginsRemoveCmd())
+}
+
+func newPluginsRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <namespace/name[@version]>...",
+		Short: "Remove installed plugins",
+		Long: `Remove plugins installed with reglet plugins install from the plugin directory:
+the release an exact version names, or every release of the plugin. The
+highest remaining release becomes the default.`,
+		Example: `  # Remove one release
+  reglet plugins remove reglet/http@1.2.0
+
+  # Remove every release
+  reglet plugins remove reglet/http`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: withContainer(func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+			service := ctx.Container.PluginInstallService(ctx.Context, "")
+			for _, declaration := range args {
+				removed, err := service.Remove(ctx.Context, declaration)
+				if err != nil {
+					return fmt.Errorf("failed to remove plugin: %w", err)
+				}
+				for _, plugin := range removed {
+					fmt.Printf("Removed %s %s\n", plugin.Name, plugin.Version)
+				}
+			}
+			return nil
+		}),
+	}
+
+	addCommonFlags(cmd)
+
+	return cmd
+}
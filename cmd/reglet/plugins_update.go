@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	pluginsCmd.AddCommand(newPluginsUpdateCmd())
+}
+
+func newPluginsUpdateCmd() *cobra.Command {
+	var (
+		index           string
+		verifySignature bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update [namespace/name]...",
+		Short: "Update installed plugins from the plugin index",
+		Long: `Install the highest release the plugin index offers of every installed plugin,
+or of the named ones, when it is newer than the highest installed release.
+Older releases stay installed for the profiles pinning them; remove them with
+reglet plugins remove.`,
+		Example: `  # Update every installed plugin
+  reglet plugins update
+
+  # Update one plugin
+  reglet plugins update reglet/http`,
+		RunE: withContainer(func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+			service := ctx.Container.PluginInstallService(ctx.Context, index)
+			updates, err := service.Update(ctx.Context, args, verifySignature)
+			for _, update := range updates {
+				fmt.Printf("Updated %s %s -> %s\n", update.Name, update.From, update.To)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to update plugins: %w", err)
+			}
+			if len(updates) == 0 {
+				fmt.Println("All installed plugins are up to date.")
+			}
+			return nil
+		}),
+	}
+
+	addIndexFlags(cmd, &index, &verifySignature)
+	addCommonFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/system"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	pluginsCmd.AddCommand(newPluginsVendorCmd())
+}
+
+func newPluginsVendorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vendor [workspace]",
+		Short: "Copy the plugins a workspace uses into vendor/plugins",
+		Long: `Copy the registry plugins the profiles and reglet.lock files of a workspace
+use from the local cache into its vendor/plugins directory, and remove the
+vendored versions nothing uses any more. The workspace defaults to the
+directory of the reglet.yaml project file, or the current directory.
+
+Commit vendor/plugins with reglet.lock: inside the workspace, vendored plugins
+are loaded before the cache and the registry, so runs work offline and on
+machines without registry access. A vendored binary that does not match its
+recorded digest is rejected.
+
+Plugins not in the cache are reported; pull them first.`,
+		Example: `  reglet plugins vendor
+  git add vendor/plugins reglet.lock`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: withContainer(func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+			workspace := project.Dir()
+			if len(args) == 1 {
+				workspace = args[0]
+			}
+			vendorDir := filepath.Join(workspace, filepath.FromSlash(system.VendorPluginDir))
+
+			service, err := ctx.Container.PluginVendorService(vendorDir)
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", vendorDir, err)
+			}
+			report, err := service.Vendor(ctx.Context, workspace)
+			if err != nil {
+				return fmt.Errorf("failed to vendor plugins: %w", err)
+			}
+
+			for _, ref := range report.Vendored {
+				fmt.Printf("Vendored %s\n", ref)
+			}
+			for _, ref := range report.Removed {
+				fmt.Printf("Removed %s\n", ref)
+			}
+			fmt.Printf("%d plugin version(s) vendored, %d removed, %d unchanged in %s.\n",
+				len(report.Vendored), len(report.Removed), report.Unchanged, vendorDir)
+
+			if len(report.Missing) > 0 {
+				return fmt.Errorf("not in the plugin cache, pull them and vendor again: %s", strings.Join(report.Missing, ", "))
+			}
+			return nil
+		}),
+	}
+
+	addCommonFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newPrivilegedReadCmd())
+}
+
+// newPrivilegedReadCmd is the helper --allow-sudo runs as root to read one
+// file. It loads no configuration and reports no usage: it only writes the
+// file to stdout.
+func newPrivilegedReadCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    wasm.PrivilegedReadCommand + " <path>",
+		Short:  "Read a regular file without following symlinks (used by --allow-sudo)",
+		Args:   cobra.ExactArgs(1),
+		Hidden: true,
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			return nil
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			return wasm.ReadNoFollow(args[0], os.Stdout)
+		},
+	}
+}
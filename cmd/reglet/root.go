@@ -1,18 +1,23 @@
 package main
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
 	"strings"
 
+	"github.com/reglet-dev/reglet/internal/infrastructure/i18n"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	cfgFile  string
-	logLevel string
-	quiet    bool
+	cfgFile   string
+	logLevel  string
+	namespace string
+	quiet     bool
+	noColor   bool
+	lang      string
 )
 
 // rootCmd is the application entry point.
@@ -23,26 +28,50 @@ var rootCmd = &cobra.Command{
 platform built on WebAssembly (Wasm). It enables engineering teams to define 
 policy-as-code, execute validation checks in isolated sandboxed environments, 
 and generate standardized audit artifacts (OSCAL/SARIF).`,
-	PersistentPreRun: func(_ *cobra.Command, _ []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
 		setupLogging()
+		if lang != "" && !i18n.Supported(lang) {
+			return withExitCode(exitConfigError, fmt.Errorf("unsupported language %q (supported: %s)", lang, strings.Join(i18n.Languages(), ", ")))
+		}
+		if err := loadConfigLayers(cmd); err != nil {
+			return withExitCode(exitConfigError, err)
+		}
+		recordCommandUsage(cmd.Context(), cmd)
+		return nil
 	},
 	SilenceUsage: true,
 }
 
-// Execute runs the root command.
+// Execute runs the root command and exits with the code of its outcome
+// (see exit_status.go).
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitCodeOf(err))
 	}
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return withExitCode(exitConfigError, err)
+	})
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.reglet/config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "log level: debug, info, warn, error")
+	rootCmd.PersistentFlags().StringVar(&namespace, "namespace", "", "namespace of stored results (default: storage.namespace from config, or \"default\")")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress all log output (equivalent to --log-level=error)")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also set by the NO_COLOR environment variable)")
+	rootCmd.PersistentFlags().StringVar(&lang, "lang", "", "language of table, HTML and text reports: "+strings.Join(i18n.Languages(), ", ")+" (default: from LANG)")
+}
+
+// outputLanguage returns the language of reports: --lang, else the one of
+// the locale environment (LC_ALL, LC_MESSAGES, LANG), else English.
+func outputLanguage() string {
+	if lang != "" {
+		return i18n.Resolve(lang)
+	}
+	return i18n.FromEnv(os.Getenv)
 }
 
 // initConfig loads configuration from the config file and environment.
@@ -52,7 +81,7 @@ func initConfig() {
 		viper.SetConfigFile(cfgFile)
 		if err := viper.ReadInConfig(); err != nil {
 			slog.Error("failed to read specified config file", "file", cfgFile, "error", err)
-			os.Exit(1)
+			os.Exit(exitConfigError)
 		}
 		slog.Debug("using config file", "file", viper.ConfigFileUsed())
 		return
@@ -62,7 +91,7 @@ func initConfig() {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		slog.Error("failed to find home directory", "error", err)
-		os.Exit(1)
+		os.Exit(exitConfigError)
 	}
 
 	viper.AddConfigPath(home + "/.reglet")
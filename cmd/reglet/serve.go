@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/spf13/cobra"
+)
+
+// shutdownTimeout bounds how long in-flight requests may finish on shutdown.
+const shutdownTimeout = 10 * time.Second
+
+func init() {
+	rootCmd.AddCommand(newServeCmd())
+}
+
+func newServeCmd() *cobra.Command {
+	var (
+		listen   string
+		insecure bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve stored results over an HTTP API and web dashboard",
+		Long: `Serve the results of the configured storage backend (storage.backend in the
+system config) over an HTTP API, so several teams can share one central store.
+A web dashboard at / shows the compliance posture, trends over the last 30 days
+and per-control details, and lets operators run the profiles listed under
+server.profiles.
+
+Runs are queued and start once they fit within server.queue (default: 4 at a
+time, 1 per profile). A request identical to one still queued joins it.
+server.schedules runs profiles on an interval.
+
+Local plugins are pinned when a run first loads them. A plugin file that
+changes on disk is used by later runs once it matches a release installed
+with "reglet plugins install" (see "Installing from a Plugin Index"); other
+changes are ignored until the server restarts.
+
+Every request works in one namespace, selected with the X-Reglet-Namespace
+header (default: --namespace, storage.namespace, or "default"). Namespaces are
+isolated by the repository: a request never sees or overwrites results of
+another namespace.
+
+With server.auth configured, requests need an "Authorization: Bearer" JWT from
+the configured OIDC issuer. The token's roles claim grants one of the roles
+viewer, operator or admin, each including the ones before it, and its
+namespaces claim (if configured) limits the namespaces it may use. Without
+server.auth every request has full access, so reglet serve refuses to listen
+on a non-loopback address unless --insecure is passed.
+
+POST and PUT requests must send a JSON body with "Content-Type:
+application/json"; browser requests from another origin are refused.
+
+Admins manage plugins and capability grants over the API; the security
+level is set in the server's config only.
+
+Endpoints:
+  GET  /api/v1/me                viewer    show the caller's identity and role
+  GET  /api/v1/results           viewer    list results, newest first (profile,
+                                           environment, since, until, limit,
+                                           page_token, view=summary)
+  GET  /api/v1/results/{id}      viewer    get one result
+  POST /api/v1/results/import    operator  import a "reglet history export" archive
+  GET  /api/v1/profiles          viewer    list the profiles that can be run
+  GET  /api/v1/runs              viewer    list recent runs
+  GET  /api/v1/runs/{id}         viewer    get one run
+  POST /api/v1/runs              operator  run a profile ({"profile": name,
+                                           "environment": env})
+  GET  /api/v1/queue             viewer    show running and queued runs
+  GET  /api/v1/plugins           admin     list cached plugins
+  POST /api/v1/plugins/prune     admin     prune the plugin cache ({"keep_versions": n})
+  GET  /api/v1/plugins/installed admin     list plugins installed from the index
+  POST /api/v1/plugins/install   admin     install a plugin ({"plugin": "reglet/http@1.2",
+                                           "verify_signature": bool})
+  POST /api/v1/plugins/remove    admin     remove installed releases ({"plugin": name})
+  GET  /api/v1/capabilities      admin     show the capability policy and grants
+  PUT  /api/v1/capabilities/grants admin   replace the grants ({"grants": [{"kind",
+                                           "pattern"}]}); broad grants are refused
+                                           at security level strict
+  GET  /healthz                            liveness`,
+		Example: `  # Serve the central PostgreSQL store
+  reglet serve --listen :8080 --config central.yaml
+
+  # Query the results of one team
+  curl -H "Authorization: Bearer $TOKEN" -H 'X-Reglet-Namespace: team-a' \
+    'localhost:8080/api/v1/results?profile=web&limit=10'`,
+		Args: cobra.NoArgs,
+		RunE: withContainer(func(ctx *CommandContext, _ *cobra.Command, _ []string) error {
+			authEnabled := ctx.Container.SystemConfig().Server.Auth.Issuer != ""
+			if err := checkServeListen(listen, authEnabled, insecure); err != nil {
+				return err
+			}
+
+			runCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			stopWatching, err := ctx.Container.WatchPlugins(runCtx)
+			if err != nil {
+				return err
+			}
+			defer stopWatching()
+
+			api, err := ctx.Container.APIServer(runCtx, repositories.NamespaceFromContext(ctx.Context))
+			if err != nil {
+				return err
+			}
+			defer api.Close()
+			if !authEnabled {
+				ctx.Logger.Warn("API authentication is disabled; every request has admin access (configure server.auth)")
+			}
+
+			server := &http.Server{
+				Addr:              listen,
+				Handler:           api.Handler(),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			errs := make(chan error, 1)
+			go func() { errs <- server.ListenAndServe() }()
+			ctx.Logger.Info("serving results API", "listen", listen)
+
+			select {
+			case err := <-errs:
+				return fmt.Errorf("server failed: %w", err)
+			case <-runCtx.Done():
+			}
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				return fmt.Errorf("failed to shut down: %w", err)
+			}
+			if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", "127.0.0.1:8080", "Address to listen on")
+	cmd.Flags().BoolVar(&insecure, "insecure", false, "Allow serving a non-loopback address without server.auth")
+	return cmd
+}
+
+// checkServeListen refuses to serve a non-loopback address unless callers
+// are authenticated, or insecure is set.
+func checkServeListen(listen string, authEnabled, insecure bool) error {
+	if authEnabled || insecure || isLoopback(listen) {
+		return nil
+	}
+	return fmt.Errorf("refusing to serve %s without authentication: configure server.auth, or pass --insecure", listen)
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckServeListen(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkServeListen("127.0.0.1:8080", false, false))
+	assert.NoError(t, checkServeListen("localhost:8080", false, false))
+	assert.NoError(t, checkServeListen(":8080", true, false))
+	assert.NoError(t, checkServeListen("10.0.0.5:8080", false, true))
+
+	assert.ErrorContains(t, checkServeListen(":8080", false, false), "configure server.auth")
+	assert.ErrorContains(t, checkServeListen("0.0.0.0:8080", false, false), "without authentication")
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/services"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/system"
+	"github.com/reglet-dev/reglet/internal/infrastructure/telemetry"
+	"github.com/spf13/cobra"
+)
+
+// telemetryFlushTimeout bounds the time a command waits for a due usage
+// report to be sent.
+const telemetryFlushTimeout = 3 * time.Second
+
+// telemetryCmd represents the telemetry command
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Manage anonymous usage metrics",
+	Long: `Manage the anonymous usage metrics reglet can send to help prioritize its
+development. Telemetry is off unless you enable it, and DO_NOT_TRACK=1 turns it
+off whatever the configuration says.
+
+When enabled, reglet counts the commands run, the built-in plugins runs use
+(other plugins count as "custom") and how long runs take, in coarse buckets.
+The counts are kept in ~/.reglet/telemetry.json and sent once a day, without
+identifiers, host names, profiles, paths or results. See docs/telemetry.md for
+the payload.`,
+}
+
+func init() {
+	telemetryCmd.AddCommand(newTelemetryStatusCmd(), newTelemetryEnableCmd(), newTelemetryDisableCmd())
+	rootCmd.AddCommand(telemetryCmd)
+}
+
+func newTelemetryStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether telemetry is enabled and the usage not yet sent",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := layers.Config().Telemetry
+			collector := newUsageCollector(cfg)
+
+			switch {
+			case telemetry.Enabled(cfg.Enabled, os.Getenv):
+				fmt.Println("Telemetry: enabled")
+			case cfg.Enabled:
+				fmt.Printf("Telemetry: disabled by %s\n", telemetry.DoNotTrackEnv)
+			default:
+				fmt.Println("Telemetry: disabled")
+			}
+			fmt.Printf("Endpoint:  %s\n", collector.Endpoint())
+
+			report, err := collector.Pending()
+			if err != nil {
+				return err
+			}
+			if report == nil {
+				fmt.Println("\nNo usage recorded since the last report.")
+				return nil
+			}
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("\nNext report:\n%s\n", data)
+			return nil
+		},
+	}
+}
+
+func newTelemetryEnableCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable",
+		Short: "Send anonymous usage metrics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := setTelemetryEnabled(true); err != nil {
+				return err
+			}
+			fmt.Println("Telemetry enabled. Thank you! Run 'reglet telemetry status' to see what will be sent.")
+			if !telemetry.Enabled(true, os.Getenv) {
+				fmt.Printf("Note: %s is set, so nothing is sent until it is unset.\n", telemetry.DoNotTrackEnv)
+			}
+			return nil
+		},
+	}
+}
+
+func newTelemetryDisableCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable",
+		Short: "Stop sending usage metrics and discard those not yet sent",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := setTelemetryEnabled(false); err != nil {
+				return err
+			}
+			if err := newUsageCollector(layers.Config().Telemetry).Discard(); err != nil {
+				return err
+			}
+			fmt.Println("Telemetry disabled; usage not yet sent was discarded.")
+			return nil
+		},
+	}
+}
+
+// setTelemetryEnabled writes telemetry.enabled to the user config.
+func setTelemetryEnabled(enabled bool) error {
+	if userConfigPath == "" {
+		return fmt.Errorf("no user config file: pass --config")
+	}
+	return system.SetConfigValue(userConfigPath, "telemetry.enabled", enabled)
+}
+
+// newUsageCollector creates the collector of the usage metrics in
+// ~/.reglet/telemetry.json.
+func newUsageCollector(cfg system.TelemetryConfig) *telemetry.Collector {
+	home, _ := os.UserHomeDir()
+	return telemetry.NewCollector(filepath.Join(home, ".reglet", "telemetry.json"), cfg.Endpoint, build.Get().Version)
+}
+
+// usageCollector returns the usage collector, nil when telemetry is off.
+func usageCollector() *telemetry.Collector {
+	if layers == nil {
+		return nil
+	}
+	cfg := layers.Config().Telemetry
+	if !telemetry.Enabled(cfg.Enabled, os.Getenv) {
+		return nil
+	}
+	return newUsageCollector(cfg)
+}
+
+// recordCommandUsage counts cmd in the usage metrics and sends them when a
+// report is due. Telemetry never fails a command: errors are only logged.
+// Commands run with --offline are neither counted nor send a report.
+func recordCommandUsage(ctx context.Context, cmd *cobra.Command) {
+	collector := usageCollector()
+	if collector == nil || !cmd.Runnable() || offlineCommand(cmd) {
+		return
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == telemetryCmd {
+			return
+		}
+	}
+
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if err := collector.RecordCommand(command); err != nil {
+		slog.Debug("failed to record usage", "error", err)
+		return
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, telemetryFlushTimeout)
+	defer cancel()
+	if sent, err := collector.Flush(ctx); err != nil {
+		slog.Debug("failed to send usage report", "error", err)
+	} else if sent {
+		slog.Debug("sent usage report", "endpoint", collector.Endpoint())
+	}
+}
+
+// offlineCommand reports whether cmd runs air-gapped, with --offline set.
+func offlineCommand(cmd *cobra.Command) bool {
+	offline, err := cmd.Flags().GetBool("offline")
+	return err == nil && offline
+}
+
+// recordRunUsage counts a profile run in the usage metrics: its duration
+// and the kinds of plugins its observations used.
+func recordRunUsage(result *execution.ExecutionResult) {
+	collector := usageCollector()
+	if collector == nil || result == nil {
+		return
+	}
+
+	var kinds []string
+	for _, control := range result.Controls {
+		for _, obs := range control.ObservationResults {
+			kind := "custom"
+			if services.IsBuiltInPlugin(obs.Plugin) {
+				kind = obs.Plugin
+			}
+			kinds = append(kinds, kind)
+		}
+	}
+	if err := collector.RecordRun(result.EndTime.Sub(result.StartTime), kinds); err != nil {
+		slog.Debug("failed to record usage", "error", err)
+	}
+}
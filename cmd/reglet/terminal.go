@@ -0,0 +1,33 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strconv"
+
+	"golang.org/x/term"
+)
+
+// terminalStyle returns whether output to w is colored and the width its
+// lines are truncated to (0 = no limit). Only terminals get either: files and
+// pipes receive plain, complete lines. Colors are off with --no-color, a
+// non-empty NO_COLOR (https://no-color.org) or TERM=dumb.
+func terminalStyle(w io.Writer) (color bool, width int) {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false, 0
+	}
+	fd := int(file.Fd()) //nolint:gosec // file descriptors fit in an int
+	if !term.IsTerminal(fd) {
+		return false, 0
+	}
+
+	color = !noColor && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return color, cols
+	}
+	if cols, _, err := term.GetSize(fd); err == nil && cols > 0 {
+		return color, cols
+	}
+	return color, 0
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newTestCmd())
+}
+
+func newTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test [paths...]",
+		Short: "Run profile unit tests against fixture evidence",
+		Long: `Run profile unit tests from *_test.yaml files. Each test case runs the profile
+with every plugin replaced by fixture evidence and asserts which controls pass
+or fail and what messages they produce, so expect expressions and control logic
+can be tested in CI without touching real infrastructure.
+
+Paths may be suite files or directories, which are searched recursively for
+*_test.yaml files. The default is the current directory.`,
+		Example: `  # Run every suite under the current directory
+  reglet test
+
+  # Run one suite
+  reglet test profiles/ssh_test.yaml`,
+		RunE: withContainer(func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+			if !cmd.Flags().Changed("log-level") && !quiet {
+				// Per-control engine logs drown out the test report
+				logLevel = "warn"
+				setupLogging()
+			}
+
+			report, err := ctx.Container.ProfileTestService().Run(ctx.Context, args)
+			if err != nil {
+				return err
+			}
+
+			writeTestReport(os.Stdout, report)
+			if _, failed := report.Counts(); failed > 0 {
+				return withExitCode(exitFindings, fmt.Errorf("%d profile test(s) failed", failed))
+			}
+			return nil
+		}),
+	}
+
+	return cmd
+}
+
+// writeTestReport prints one line per test case, failures indented below it,
+// followed by a summary.
+func writeTestReport(w io.Writer, report *dto.ProfileTestReport) {
+	for _, suite := range report.Suites {
+		_, _ = fmt.Fprintf(w, "%s\n", suite.Path)
+		if suite.Error != "" {
+			_, _ = fmt.Fprintf(w, "  ✗ %s\n", suite.Error)
+			continue
+		}
+		for _, c := range suite.Cases {
+			if c.Passed() {
+				_, _ = fmt.Fprintf(w, "  ✓ %s\n", c.Name)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "  ✗ %s\n", c.Name)
+			for _, failure := range c.Failures {
+				_, _ = fmt.Fprintf(w, "      %s\n", failure)
+			}
+		}
+	}
+
+	passed, failed := report.Counts()
+	_, _ = fmt.Fprintf(w, "\n%d passed, %d failed\n", passed, failed)
+}
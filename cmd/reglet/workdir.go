@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/rundir"
+)
+
+// reportExtensions names the report artifact of a run directory by output
+// format. JSON is left out: results.json already holds it.
+var reportExtensions = map[string]string{
+	"table":           "txt",
+	"text":            "txt",
+	"jsonl":           "jsonl",
+	"yaml":            "yaml",
+	"junit":           "xml",
+	"sarif":           "sarif",
+	"html":            "html",
+	"html-accessible": "accessible.html",
+}
+
+// startRunDir starts a run directory in workDir and logs to it as well until
+// the returned function is called.
+func startRunDir(workDir string) (*rundir.Run, func(), error) {
+	run, err := rundir.Create(workDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid --work-dir: %w", err)
+	}
+	previous := slog.Default()
+	slog.SetDefault(run.Logger(previous))
+	return run, func() { slog.SetDefault(previous) }, nil
+}
+
+// commitRunDir writes the report of the selected format to the artifacts of
+// the run directory and moves the run directory into place.
+func commitRunDir(run *rundir.Run, factory ports.OutputFormatterFactory, result *execution.ExecutionResult, profilePath string, opts *CheckOptions) error {
+	if ext, ok := reportExtensions[opts.Format]; ok {
+		if err := writeReportArtifact(run.ArtifactPath("report."+ext), factory, result, profilePath, opts); err != nil {
+			run.Discard()
+			return err
+		}
+	}
+
+	dir, err := run.Commit(result)
+	if err != nil {
+		return err
+	}
+	slog.Info("run directory written", "path", dir)
+	return nil
+}
+
+// writeReportArtifact writes the report of the selected format to path.
+func writeReportArtifact(path string, factory ports.OutputFormatterFactory, result *execution.ExecutionResult, profilePath string, opts *CheckOptions) error {
+	//nolint:gosec // G304: path is inside the run directory
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write report artifact: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return formatOutput(factory, file, result, opts.Format, ports.FormatterOptions{
+		Indent:      true,
+		ProfilePath: profilePath,
+		Verbosity:   opts.OutputVerbosity(),
+		Language:    outputLanguage(),
+	})
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/system"
+	"github.com/spf13/cobra"
+)
+
+// layers is the configuration merged from defaults, the user config, the
+// project file, environment variables and flags.
+var layers *system.Layers
+
+// userConfigPath is the user config file: --config, or
+// ~/.reglet/config.yaml.
+var userConfigPath string
+
+// project holds the effective project settings. Outside a workspace its Path
+// is empty.
+var project *system.Project
+
+// flagKeys map the flags that override configuration keys to those keys.
+var flagKeys = map[string]string{
+	"namespace":         "storage.namespace",
+	"security":          "security.level",
+	"max-evidence-size": "max_evidence_size_bytes",
+	"format":            "output.format",
+	"output":            "output.file",
+	"export":            "notifications.export",
+}
+
+// loadConfigLayers merges the configuration layers of cmd: the user config
+// (--config, or ~/.reglet/config.yaml), the reglet.yaml of the working
+// directory or its parents, REGLET_* environment variables and the flags
+// of cmd.
+func loadConfigLayers(cmd *cobra.Command) error {
+	src := system.LayerSources{Env: os.Environ(), Flags: make(map[string]system.FlagSetting)}
+
+	src.UserConfigPath = cfgFile
+	if flag := cmd.Flags().Lookup("config"); flag != nil && flag.Value.String() != "" {
+		src.UserConfigPath = flag.Value.String()
+	}
+	if src.UserConfigPath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			src.UserConfigPath = filepath.Join(home, ".reglet", "config.yaml")
+		}
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		found, err := system.FindProject(cwd)
+		if err != nil {
+			return err
+		}
+		if found != nil {
+			slog.Debug("using project file", "file", found.Path)
+			src.ProjectPath = found.Path
+		}
+	}
+
+	for name, key := range flagKeys {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		var value interface{} = flag.Value.String()
+		if values, ok := flag.Value.(interface{ GetSlice() []string }); ok {
+			value = values.GetSlice()
+		} else if flag.Value.Type() == "int" {
+			value, _ = cmd.Flags().GetInt(name)
+		}
+		src.Flags[key] = system.FlagSetting{Name: name, Value: value}
+	}
+
+	loaded, err := system.LoadLayers(src)
+	if err != nil {
+		return err
+	}
+	layers, project, userConfigPath = loaded, loaded.Project(), src.UserConfigPath
+	return nil
+}
+
+// profileArgs returns the profile given as argument, or else the profiles of
+// the project file.
+func profileArgs(args []string) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+	if len(project.Profiles) == 0 {
+		return nil, fmt.Errorf("no profile given and no profiles listed in a %s of this directory or its parents", system.ProjectFileName)
+	}
+	return project.ProfilePaths()
+}
+
+// applyProjectDefaults fills the output and export flags not set on the
+// command line from the project settings.
+func applyProjectDefaults(cmd *cobra.Command, opts *CheckOptions) {
+	if out := project.Output; out.Format != "" && !cmd.Flags().Changed("format") {
+		opts.Format = out.Format
+	}
+	if out := project.Output; out.File != "" && !cmd.Flags().Changed("output") {
+		opts.outFile = out.File
+	}
+	if export := project.Notifications.Export; len(export) > 0 && cmd.Flags().Lookup("export") != nil && !cmd.Flags().Changed("export") {
+		opts.exporters = append([]string(nil), export...)
+	}
+}
+
+// projectIntegrations returns the exporter settings of a profile layered
+// over those of the project file.
+func projectIntegrations(profile map[string]map[string]interface{}) map[string]map[string]interface{} {
+	if len(project.Notifications.Integrations) == 0 {
+		return profile
+	}
+	merged := make(map[string]map[string]interface{}, len(project.Notifications.Integrations))
+	for name, settings := range project.Notifications.Integrations {
+		merged[name] = make(map[string]interface{}, len(settings))
+		for key, value := range settings {
+			merged[name][key] = value
+		}
+	}
+	for name, settings := range profile {
+		if merged[name] == nil {
+			merged[name] = make(map[string]interface{}, len(settings))
+		}
+		for key, value := range settings {
+			merged[name][key] = value
+		}
+	}
+	return merged
+}
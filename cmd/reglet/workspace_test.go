@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectIntegrations(t *testing.T) {
+	project = &system.Project{Notifications: system.NotificationsConfig{
+		Integrations: map[string]map[string]interface{}{
+			"jira": {"url": "https://example.atlassian.net", "project": "SEC"},
+		},
+	}}
+	t.Cleanup(func() { project = nil })
+
+	merged := projectIntegrations(map[string]map[string]interface{}{
+		"jira":      {"project": "OPS"},
+		"pagerduty": {"routing_key": "abc"},
+	})
+
+	assert.Equal(t, map[string]map[string]interface{}{
+		"jira":      {"url": "https://example.atlassian.net", "project": "OPS"},
+		"pagerduty": {"routing_key": "abc"},
+	}, merged)
+}
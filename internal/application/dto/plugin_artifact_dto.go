@@ -28,3 +28,11 @@ func (d *PluginArtifactDTO) Close() error {
 	}
 	return nil
 }
+
+// PluginPatchDTO is a binary patch from a cached plugin version to Plugin,
+// whose digest the patched binary must match. Plugin is the one published at
+// the requested reference, not one described by the patch artifact.
+type PluginPatchDTO struct {
+	Plugin *entities.Plugin
+	Patch  []byte
+}
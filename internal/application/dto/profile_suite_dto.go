@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// ProfileTestSuite is a profile unit test file (*_test.yaml): test cases that
+// run a profile against fixture evidence instead of real plugins.
+type ProfileTestSuite struct {
+	// Path is the suite file the suite was loaded from.
+	Path string `yaml:"-"`
+	// Profile is the profile under test, relative to the suite file.
+	Profile string `yaml:"profile"`
+	// Environment selects one of the profile's environments ("" = none).
+	Environment string            `yaml:"environment,omitempty"`
+	Tests       []ProfileTestCase `yaml:"tests"`
+}
+
+// ProfileTestCase runs the profile once with the given fixtures and checks
+// the resulting control statuses and messages.
+type ProfileTestCase struct {
+	Name     string                   `yaml:"name"`
+	Fixtures []ProfileTestFixture     `yaml:"fixtures"`
+	Expect   []ProfileTestExpectation `yaml:"expect"`
+}
+
+// ProfileTestFixture is the evidence returned in place of a plugin call. A
+// fixture applies to every observation matching all of its selectors
+// (control, observation, plugin and config); the first matching fixture wins.
+type ProfileTestFixture struct {
+	// Config matches observations whose config has these keys with equal values.
+	Config map[string]interface{} `yaml:"config,omitempty"`
+	// Data is the evidence data the plugin would have returned.
+	Data map[string]interface{} `yaml:"data,omitempty"`
+	// Error makes the plugin report an error instead of evidence data.
+	Error *ProfileTestError `yaml:"error,omitempty"`
+	// Status is the plugin-reported status (default: true, or false with Error).
+	Status *bool `yaml:"status,omitempty"`
+	// Observation matches the observation at this index within the control.
+	Observation *int   `yaml:"observation,omitempty"`
+	Control     string `yaml:"control,omitempty"`
+	Plugin      string `yaml:"plugin,omitempty"`
+}
+
+// ProfileTestError is a plugin-reported error returned by a fixture.
+type ProfileTestError struct {
+	Code    string `yaml:"code"`
+	Message string `yaml:"message"`
+}
+
+// ProfileTestExpectation asserts the outcome of one control.
+type ProfileTestExpectation struct {
+	Control         string        `yaml:"control"`
+	Status          values.Status `yaml:"status,omitempty"`
+	Message         string        `yaml:"message,omitempty"`          // Exact control message
+	MessageContains string        `yaml:"message_contains,omitempty"` // Substring of the control message
+}
+
+// ProfileTestReport is the outcome of running profile test suites.
+type ProfileTestReport struct {
+	Suites []ProfileTestSuiteResult
+}
+
+// ProfileTestSuiteResult is the outcome of one suite file.
+type ProfileTestSuiteResult struct {
+	Path    string
+	Profile string
+	// Error is set when the suite or its profile could not be loaded; no
+	// cases ran.
+	Error string
+	Cases []ProfileTestCaseResult
+}
+
+// ProfileTestCaseResult is the outcome of one test case. It passed when it
+// has no failures.
+type ProfileTestCaseResult struct {
+	Name     string
+	Failures []string
+}
+
+// Passed reports whether every expectation of the case held.
+func (r ProfileTestCaseResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// Counts returns the number of passed and failed cases. A suite that could
+// not be loaded counts as one failure.
+func (r *ProfileTestReport) Counts() (passed, failed int) {
+	for _, suite := range r.Suites {
+		if suite.Error != "" {
+			failed++
+			continue
+		}
+		for _, c := range suite.Cases {
+			if c.Passed() {
+				passed++
+			} else {
+				failed++
+			}
+		}
+	}
+	return passed, failed
+}
@@ -2,13 +2,18 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
 )
 
 // CheckProfileRequest encapsulates all inputs needed to check a profile.
 type CheckProfileRequest struct {
 	Options     CheckOptions
 	ProfilePath string
+	// Environment selects one of the profile's environments ("" = none)
+	Environment string
 	Metadata    RequestMetadata
 	Filters     FilterOptions
 	Execution   ExecutionOptions
@@ -25,11 +30,100 @@ type FilterOptions struct {
 	IncludeDependencies bool
 }
 
+// Plugin execution modes.
+const (
+	// PluginModeWASM runs plugins in the sandboxed WASM runtime (default).
+	PluginModeWASM = "wasm"
+	// PluginModeNative runs plugins linked into the binary in-process, without
+	// sandboxing. Development use only.
+	PluginModeNative = "native"
+)
+
 // ExecutionOptions controls how the profile is executed.
 type ExecutionOptions struct {
+	// PluginMode selects how plugins run ("" or PluginModeWASM, PluginModeNative)
+	PluginMode string
+
+	// ResultStream receives controls as they complete (nil = buffer the full result)
+	ResultStream execution.ResultStream
+
+	// RetainEvidence keeps the evidence of streamed controls in the returned
+	// result, for exporters run on it (saved and policy-evaluated results
+	// always keep it)
+	RetainEvidence bool
+
+	// Hooks are called as controls and observations start and end
+	Hooks []execution.Hooks
+
+	// PolicyBundle is a Rego policy bundle evaluated over the result before
+	// finalization; its findings become failing controls ("" = none)
+	PolicyBundle string
+
+	// SeverityRules is a file of rules assigning severities to controls by
+	// tags, plugins and evidence as they complete ("" = none)
+	SeverityRules string
+
+	// Clock replaces the system clock for result timestamps, plugins and
+	// time-relative checks, for reproducible runs (nil = system time)
+	Clock func() time.Time
+
+	// RecordCassette captures plugin host calls and file reads into this file ("" = off)
+	RecordCassette string
+
+	// ReplayCassette serves plugin host calls and file reads from a recorded
+	// cassette instead of the network and filesystem ("" = off)
+	ReplayCassette string
+
+	// InjectFaults are fault specs ("plugin=http,rate=0.3,type=timeout") that
+	// make plugin host calls fail, for testing profiles under outages
+	InjectFaults []string
+
+	// FaultSeed makes injected failures reproducible (0 = random)
+	FaultSeed uint64
+
+	// DebugHTTP logs sanitized summaries of the HTTP exchanges of failed
+	// observations
+	DebugHTTP bool
+
+	// MaxEgressBytes aborts the run once plugins would send more than this
+	// many request bytes through network host functions (0 = unlimited)
+	MaxEgressBytes int64
+
+	// MaxEvidenceSizeBytes overrides the evidence truncation threshold (0 = use config)
+	MaxEvidenceSizeBytes int
+
 	// Parallel enables parallel execution of controls
 	Parallel bool
 
+	// Offline withholds network capabilities from plugins regardless of
+	// grants, and refuses to start when selected controls need the network
+	// (unless served from ReplayCassette)
+	Offline bool
+
+	// AllowSudo lets privileged observations read files the reglet process
+	// cannot through "sudo -n reglet privileged-read"
+	AllowSudo bool
+
+	// HTTPCache answers plugin GET requests from responses fetched earlier in
+	// the run while their Cache-Control max-age allows
+	HTTPCache bool
+
+	// CollectOnly stores the evidence of observations without evaluating
+	// expectations; controls end up collected or errored
+	CollectOnly bool
+
+	// ProfilePerf adds a per-phase timing breakdown to the result
+	ProfilePerf bool
+
+	// SkipMissingPlugins runs the observations of installed plugins when
+	// others are missing, marking the observations of missing plugins not_run
+	// instead of refusing to start
+	SkipMissingPlugins bool
+
+	// NoObservationDedup runs identical observations of different controls
+	// separately instead of sharing the evidence of one plugin call
+	NoObservationDedup bool
+
 	// MaxConcurrentControls limits parallel control execution (0 = no limit)
 	MaxConcurrentControls int
 
@@ -71,3 +165,129 @@ type ExecuteProfileRequest struct {
 	Execution            ExecutionOptions
 	SkipSchemaValidation bool
 }
+
+// CompareEnvironmentsRequest encapsulates inputs for comparing the latest
+// saved run of a profile in each environment.
+type CompareEnvironmentsRequest struct {
+	// Profile selects the profile when the results cover several ("" = the only one).
+	Profile string
+	// Paths are result files, or directories searched for *.json results.
+	Paths []string
+	// Environments limits and orders the compared environments (empty = all, by name).
+	Environments []string
+	// Ignore lists evidence fields ("plugin.path") left out of the comparison.
+	Ignore []string
+}
+
+// EvaluateEvidenceRequest asks to evaluate the stored evidence of a run
+// against a profile, without running plugins.
+type EvaluateEvidenceRequest struct {
+	// RunID is the execution ID of the stored run
+	RunID string
+	// ProfilePath is the profile whose expectations are applied
+	ProfilePath string
+	// Save stores the evaluated result as a new run
+	Save bool
+}
+
+// AnonymizeResultRequest selects the result to anonymize: a stored run or a
+// JSON result file.
+type AnonymizeResultRequest struct {
+	// RunID is the execution ID of a stored run
+	RunID string
+	// ResultPath is a result file written with --format json, instead of RunID
+	ResultPath string
+}
+
+// ContinuousCheckOptions configures continuous verification: re-running a
+// profile on an interval and notifying only about status transitions.
+type ContinuousCheckOptions struct {
+	// Interval is the time between the start of one run and the next.
+	Interval time.Duration
+	// FlapWindow is the number of recent runs considered for flap detection.
+	FlapWindow int
+	// FlapThreshold is the number of status changes within FlapWindow that
+	// marks a control as flapping.
+	FlapThreshold int
+}
+
+// RunRequest asks the server to run one of its configured profiles.
+type RunRequest struct {
+	// Profile is the name the profile is configured under.
+	Profile string
+	// Environment selects one of the profile's environments ("" = none).
+	Environment string
+	// RequestedBy identifies the caller, for the run's record.
+	RequestedBy string
+}
+
+// RunQueueOptions limits how many server runs execute at once. Runs beyond
+// the limits wait in the queue.
+type RunQueueOptions struct {
+	// MaxConcurrent limits runs across all profiles (0 = 4).
+	MaxConcurrent int
+	// MaxPerProfile limits runs of one profile, which usually share targets
+	// (0 = 1).
+	MaxPerProfile int
+}
+
+// RunSchedule requests a run of a server profile on an interval.
+type RunSchedule struct {
+	Profile     string
+	Environment string
+	Namespace   string
+	Interval    time.Duration
+}
+
+// AgentNode is an agent a distributed run can place controls on.
+type AgentNode struct {
+	// Name identifies the agent in results.
+	Name string
+	// Address is the agent's gRPC address ("host:port").
+	Address string
+	// Tags attract controls with the same tags.
+	Tags []string
+}
+
+// PartialCheckRequest asks an agent to run some controls of a profile.
+type PartialCheckRequest struct {
+	// Profile is the profile document with the profiles it extends merged in.
+	// Variables are left unresolved, so they resolve with the agent's secrets.
+	Profile []byte
+	// Environment selects one of the profile's environments ("" = none).
+	Environment string
+	// RequestID identifies the distributed run in the agent's logs.
+	RequestID string
+	// ControlIDs are the controls to run; the others are skipped.
+	ControlIDs []string
+
+	Parallel                  bool
+	MaxConcurrentControls     int
+	MaxConcurrentObservations int
+	MaxEvidenceSizeBytes      int
+}
+
+// PrunePluginsRequest selects the cached plugin versions and compilation
+// cache entries a prune removes.
+type PrunePluginsRequest struct {
+	// Workspace is searched for profiles and lockfiles; the plugin versions
+	// they reference are kept ("" = none are referenced).
+	Workspace string
+	// Keep is the number of unreferenced versions kept per plugin, newest first.
+	Keep int
+	// CacheMaxAge ages out compilation cache entries (0 = keep all).
+	CacheMaxAge time.Duration
+	// DryRun reports what would be removed without removing it.
+	DryRun bool
+}
+
+// PluginDoctorRequest selects the installed plugins a health check covers.
+type PluginDoctorRequest struct {
+	// PluginDir is searched for <name>/<name>.wasm ("" = not searched).
+	PluginDir string
+	// Names limits the check to these plugins (empty = all).
+	Names []string
+	// VerifySignatures checks the registry signature of cached plugins,
+	// which needs network access.
+	VerifySignatures bool
+}
@@ -11,6 +11,7 @@ import (
 type CheckProfileResponse struct {
 	Diagnostics     Diagnostics
 	ExecutionResult *execution.ExecutionResult
+	Integrations    map[string]map[string]interface{} // Exporter settings from the profile
 	Metadata        ResponseMetadata
 }
 
@@ -60,3 +61,193 @@ type ExecuteProfileResponse struct {
 	// ExecutionResult is the domain execution result
 	ExecutionResult *execution.ExecutionResult
 }
+
+// Preflight check kinds.
+const (
+	PreflightCheckDNS        = "dns"
+	PreflightCheckSecret     = "secret"
+	PreflightCheckProxy      = "proxy"
+	PreflightCheckPermission = "permission"
+	PreflightCheckCommand    = "command"
+	PreflightCheckNetwork    = "network"
+)
+
+// PreflightReport contains the setup problems found by a preflight check,
+// grouped by plugin.
+type PreflightReport struct {
+	Groups []PreflightGroup
+}
+
+// PreflightGroup contains the problems of one plugin. Secrets referenced by
+// integrations are grouped under the integration name.
+type PreflightGroup struct {
+	Plugin string
+	Issues []PreflightIssue
+}
+
+// PreflightIssue is one setup problem, reported once however many
+// observations it affects.
+type PreflightIssue struct {
+	Check    string   // dns, secret, proxy, permission, command or network
+	Subject  string   // Host, secret, environment variable, file, command or address concerned
+	Message  string   // What is wrong
+	Hint     string   // How to fix it
+	Controls []string // Controls affected, sorted
+	Warning  bool     // The run may still succeed
+}
+
+// ErrorCount returns the number of issues that are not warnings.
+func (r *PreflightReport) ErrorCount() int {
+	count := 0
+	for _, group := range r.Groups {
+		for _, issue := range group.Issues {
+			if !issue.Warning {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// ImportResultsReport lists what a result import did, by execution ID.
+type ImportResultsReport struct {
+	Source   execution.ArchiveSource
+	Imported []string // Results added to the repository
+	Skipped  []string // Results already stored unchanged
+}
+
+// PrunePluginsReport lists what a plugin prune removed, or would remove in a
+// dry run.
+type PrunePluginsReport struct {
+	Removed      []string // References of the removed plugin versions
+	CacheEntries []string // Removed compilation cache entries
+	Kept         int      // Cached plugin versions left in place
+	DryRun       bool
+}
+
+// VendorPluginsReport lists what a plugin vendor run changed in the vendor
+// directory.
+type VendorPluginsReport struct {
+	Vendored  []string // References copied in or updated
+	Removed   []string // References no longer used, removed
+	Missing   []string // Registry references not in the cache
+	Unchanged int      // Vendored versions already up to date
+}
+
+// InstalledPlugin is a plugin release installed in the plugin directory
+// from a plugin index.
+type InstalledPlugin struct {
+	InstalledAt time.Time
+	Name        string // Namespace-qualified, e.g. reglet/http
+	Version     string
+	Source      string // OCI reference or HTTPS URL it was downloaded from
+	Digest      string // sha256 of the .wasm binary
+	Path        string // The installed .wasm binary
+
+	SignatureVerified bool // Its signature was verified on install
+}
+
+// PluginUpdate is a plugin an update moved to a newer release.
+type PluginUpdate struct {
+	Name string
+	From string // Highest version installed before
+	To   string
+}
+
+// Plugin health checks, in the order they run.
+const (
+	PluginCheckLoad      = "load"
+	PluginCheckDescribe  = "describe"
+	PluginCheckSchema    = "schema"
+	PluginCheckABI       = "abi"
+	PluginCheckSelfTest  = "self-test"
+	PluginCheckDigest    = "digest"
+	PluginCheckSignature = "signature"
+)
+
+// Plugin health check outcomes.
+const (
+	PluginCheckOK      = "ok"
+	PluginCheckFailed  = "failed"
+	PluginCheckSkipped = "skipped" // Not applicable, or an earlier check failed
+)
+
+// PluginDoctorReport contains the health of the installed plugins, sorted
+// by source and name.
+type PluginDoctorReport struct {
+	Plugins []PluginHealth
+}
+
+// PluginHealth is the outcome of the health checks of one installed plugin.
+type PluginHealth struct {
+	Name    string // Plugin name, or the reference of cached registry plugins
+	Source  string // "plugin-dir" or "cache"
+	Path    string // WASM file checked
+	Version string // Reported by describe(), empty when it failed
+	Checks  []PluginCheck
+}
+
+// PluginCheck is the outcome of one health check.
+type PluginCheck struct {
+	Name    string // One of the PluginCheck kinds
+	Status  string // One of the PluginCheck outcomes
+	Message string // Why the check failed or was skipped
+}
+
+// Healthy reports whether no check of the plugin failed.
+func (h PluginHealth) Healthy() bool {
+	for _, check := range h.Checks {
+		if check.Status == PluginCheckFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// UnhealthyCount returns the number of plugins with a failed check.
+func (r *PluginDoctorReport) UnhealthyCount() int {
+	count := 0
+	for _, plugin := range r.Plugins {
+		if !plugin.Healthy() {
+			count++
+		}
+	}
+	return count
+}
+
+// Run states.
+const (
+	// RunStateQueued marks a run waiting for a free slot.
+	RunStateQueued = "queued"
+	// RunStateRunning marks a run in progress.
+	RunStateRunning = "running"
+	// RunStateCompleted marks a run that produced a result, passing or not.
+	RunStateCompleted = "completed"
+	// RunStateFailed marks a run that ended without a result.
+	RunStateFailed = "failed"
+)
+
+// RunStatus describes a run triggered on the server.
+type RunStatus struct {
+	CreatedAt   time.Time
+	StartedAt   time.Time // Zero while queued
+	FinishedAt  time.Time // Zero until the run ends
+	ID          string
+	Profile     string
+	Environment string
+	Namespace   string // Namespace the result is stored in
+	RequestedBy string
+	State       string // One of the RunState constants
+	ExecutionID string // Result of a completed run
+	Error       string // Why a failed run failed
+	Position    int    // Place in the queue of a queued run, from 1
+}
+
+// RunQueueStatus describes the run queue of a server.
+type RunQueueStatus struct {
+	Runs          []RunStatus // Active runs of the caller's namespace, running first
+	Running       int         // Runs in progress, in all namespaces
+	Queued        int         // Runs waiting, in all namespaces
+	MaxConcurrent int
+	MaxPerProfile int
+}
@@ -3,6 +3,7 @@ package apperrors
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
 )
@@ -48,6 +49,27 @@ func NewCapabilityError(reason string, required []capabilities.Capability) *Capa
 	}
 }
 
+// OfflineBlocker is a control that cannot run offline.
+type OfflineBlocker struct {
+	ControlID string
+	Plugins   []string // Plugins of the control that need network access
+}
+
+// OfflineError lists the controls of an offline run that need the network.
+type OfflineError struct {
+	Controls []OfflineBlocker
+}
+
+func (e *OfflineError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d control(s) cannot run offline, their plugins need network access:", len(e.Controls))
+	for _, ctrl := range e.Controls {
+		fmt.Fprintf(&b, "\n  - %s (%s)", ctrl.ControlID, strings.Join(ctrl.Plugins, ", "))
+	}
+	b.WriteString("\nexclude them with --exclude-control, or replay a recorded run with --replay")
+	return b.String()
+}
+
 // ExecutionError indicates execution failed (not validation).
 type ExecutionError struct {
 	Cause     error
@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+// AgentClient runs part of a profile on a remote agent.
+type AgentClient interface {
+	// ExecutePartial returns the agent's result. It covers every control of
+	// the profile; those not requested are skipped.
+	ExecutePartial(ctx context.Context, agent dto.AgentNode, req dto.PartialCheckRequest) (*execution.ExecutionResult, error)
+
+	// Facts returns the facts runs_on entries are matched against that the
+	// agent reports about its host, including "has:<command>" for each of
+	// commands it has on its PATH.
+	Facts(ctx context.Context, agent dto.AgentNode, commands []string) ([]string, error)
+}
+
+// ProfileBundler loads a profile for distribution to agents.
+type ProfileBundler interface {
+	// BundleProfile loads the profile at path with the profiles it extends
+	// merged in, leaving variables unresolved, and returns it with the
+	// serialized document agents load.
+	BundleProfile(path string) (*entities.Profile, []byte, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// PluginReferenceScanner finds the plugins a workspace uses.
+type PluginReferenceScanner interface {
+	// ScanPluginReferences returns the plugins declared by the profiles and
+	// pinned by the lockfiles under dir.
+	ScanPluginReferences(ctx context.Context, dir string) ([]*entities.PluginSpec, error)
+}
+
+// CompilationCache stores compiled plugin code between runs.
+type CompilationCache interface {
+	// Prune removes the entries written before cutoff and returns their
+	// names. With dryRun, it only returns them.
+	Prune(ctx context.Context, cutoff time.Time, dryRun bool) ([]string, error)
+}
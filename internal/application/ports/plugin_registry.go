@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
 	"github.com/reglet-dev/reglet/internal/domain/values"
 )
 
@@ -18,3 +19,48 @@ type PluginRegistry interface {
 	// Resolve resolves a reference to its content digest.
 	Resolve(ctx context.Context, ref values.PluginReference) (values.Digest, error)
 }
+
+// PluginPatchSource provides binary patches between plugin versions, so an
+// update downloads only what changed.
+type PluginPatchSource interface {
+	// PullPatch returns a patch turning the plugin binary with digest base
+	// into ref, or nil if the registry publishes none. The patch's plugin
+	// holds the binary digest from ref's own manifest.
+	PullPatch(ctx context.Context, ref values.PluginReference, base values.Digest) (*dto.PluginPatchDTO, error)
+}
+
+// PluginPatcher applies binary patches to plugin binaries.
+type PluginPatcher interface {
+	// Apply returns the plugin binary base with patch applied, or an error
+	// if the patch does not apply to base.
+	Apply(base, patch []byte) ([]byte, error)
+}
+
+// PluginIndexSource fetches the plugin index (registry manifest) that
+// "reglet plugins install" resolves plugin names with.
+type PluginIndexSource interface {
+	// FetchIndex returns the validated plugin index.
+	FetchIndex(ctx context.Context) (*entities.PluginIndex, error)
+}
+
+// PluginDownloader downloads plugin binaries published at HTTPS URLs.
+type PluginDownloader interface {
+	// Download returns the content at url.
+	Download(ctx context.Context, url string) ([]byte, error)
+}
+
+// PluginInstallStore keeps the plugins installed in the plugin directory,
+// where profiles load them from.
+type PluginInstallStore interface {
+	// Install places a release of a plugin, returning the path of its binary.
+	// The highest installed release of a plugin is also its default, used by
+	// declarations without a version.
+	Install(ctx context.Context, plugin dto.InstalledPlugin, wasm []byte) (string, error)
+
+	// List returns the installed releases, by name then version.
+	List(ctx context.Context) ([]dto.InstalledPlugin, error)
+
+	// Remove removes a release of a plugin, or all its releases when version
+	// is empty, and returns the removed releases.
+	Remove(ctx context.Context, name, version string) ([]dto.InstalledPlugin, error)
+}
@@ -21,9 +21,14 @@ type PluginRepository interface {
 	// List returns all cached plugins.
 	List(ctx context.Context) ([]*entities.Plugin, error)
 
-	// Prune removes old versions, keeping only the specified number.
-	Prune(ctx context.Context, keepVersions int) error
-
 	// Delete removes a specific plugin from cache.
 	Delete(ctx context.Context, ref values.PluginReference) error
 }
+
+// PluginFileReader reads the binaries of local plugins. Long-running
+// processes use one that pins each file, so a plugin changed on disk only
+// takes effect once it has been verified.
+type PluginFileReader interface {
+	// ReadPlugin returns the binary to run for the plugin file at path.
+	ReadPlugin(ctx context.Context, path string) ([]byte, error)
+}
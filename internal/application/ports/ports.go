@@ -6,6 +6,7 @@ package ports
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/reglet-dev/reglet/internal/application/dto"
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
@@ -20,6 +21,7 @@ type PluginInfo struct {
 	Name         string
 	Version      string
 	Description  string
+	Dependencies []string // Plugins a composite plugin observes
 	Capabilities []capabilities.Capability
 }
 
@@ -41,6 +43,23 @@ type PluginRuntime interface {
 	Close(ctx context.Context) error
 }
 
+// ProcessPluginLoader loads external process plugins: standalone executables
+// that speak the stdio plugin protocol instead of being compiled to WASM.
+type ProcessPluginLoader interface {
+	// LoadProcessPlugin prepares the plugin executable at path for inspection.
+	LoadProcessPlugin(ctx context.Context, name, path string) (Plugin, error)
+}
+
+// PluginInspector runs the health checks of a plugin binary that need a
+// runtime.
+type PluginInspector interface {
+	// InspectPlugin compiles the plugin and calls describe(), schema() and,
+	// when exported, self_test(), and checks the plugin is compatible with
+	// this host. It returns the version the plugin reports and the load,
+	// describe, schema, abi and self-test checks, in that order.
+	InspectPlugin(ctx context.Context, name string, wasmBytes []byte) (version string, checks []dto.PluginCheck)
+}
+
 // PluginRuntimeFactory creates runtime instances.
 // This allows the application layer to create runtimes without importing infrastructure.
 type PluginRuntimeFactory interface {
@@ -57,7 +76,26 @@ type PluginRuntimeFactory interface {
 
 // ProfileLoader loads profiles from storage.
 type ProfileLoader interface {
-	LoadProfile(path string) (*entities.Profile, error)
+	// LoadProfile loads a profile resolved for the named environment ("" = none).
+	LoadProfile(path, environment string) (*entities.Profile, error)
+}
+
+// HostResolver resolves host names. *net.Resolver satisfies it.
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// HostAccessProbe tries the host operations observations perform, with the
+// permissions of the reglet process. Missing files and commands match
+// fs.ErrNotExist; permission failures match fs.ErrPermission.
+type HostAccessProbe interface {
+	// OpenFile opens path for reading and closes it again.
+	OpenFile(path string) error
+	// LookupCommand finds an executable by path, or by name in PATH.
+	LookupCommand(name string) error
+	// Dial opens a TCP connection to address and closes it again. A refused
+	// connection is not an error: the host is reachable.
+	Dial(ctx context.Context, address string) error
 }
 
 // ProfileValidator validates profile structure and schemas.
@@ -113,6 +151,53 @@ type EngineFactory interface {
 	CreateEngine(ctx context.Context, profile entities.ProfileReader, grantedCaps map[string][]capabilities.Capability, pluginDir string, filters dto.FilterOptions, execution dto.ExecutionOptions, skipSchemaValidation bool) (ExecutionEngine, error)
 }
 
+// FixtureEngineFactory creates engines that answer observations from
+// fixtures instead of running plugins. Used by profile unit tests.
+type FixtureEngineFactory interface {
+	// CreateFixtureEngine serves fixtures for each of the given plugin names.
+	CreateFixtureEngine(fixtures []dto.ProfileTestFixture, plugins []string) (ExecutionEngine, error)
+}
+
+// EvaluationEngineFactory creates engines that evaluate a profile against
+// evidence collected earlier instead of running plugins.
+type EvaluationEngineFactory interface {
+	// CreateEvaluationEngine serves fixtures for each of the given plugin
+	// names, with the clock fixed at the time the evidence was collected.
+	CreateEvaluationEngine(fixtures []dto.ProfileTestFixture, plugins []string, at time.Time) (ExecutionEngine, error)
+}
+
+// ProfileTestSuiteLoader finds and reads profile unit test suites.
+type ProfileTestSuiteLoader interface {
+	// DiscoverSuites expands files and directories into suite files.
+	DiscoverSuites(paths []string) ([]string, error)
+
+	// LoadSuite reads and validates a suite file.
+	LoadSuite(path string) (*dto.ProfileTestSuite, error)
+}
+
+// ExecutionResultLoader finds and reads execution results saved as JSON.
+type ExecutionResultLoader interface {
+	// DiscoverResults expands files and directories into result files.
+	DiscoverResults(paths []string) ([]string, error)
+
+	// LoadResult reads a result file.
+	LoadResult(path string) (*execution.ExecutionResult, error)
+}
+
+// ResultAnonymizer replaces identifying values in execution results.
+type ResultAnonymizer interface {
+	// Anonymize returns an anonymized copy of result and the original value
+	// of each pseudonym it used.
+	Anonymize(result *execution.ExecutionResult) (*execution.ExecutionResult, map[string]string, error)
+}
+
+// PseudonymStore keeps the original values of pseudonyms, so anonymized
+// reports can be traced back internally.
+type PseudonymStore interface {
+	// Add records pseudonyms (pseudonym -> original value).
+	Add(pseudonyms map[string]string) error
+}
+
 // OutputFormatter formats execution results.
 type OutputFormatter interface {
 	Format(result *execution.ExecutionResult) error
@@ -121,19 +206,64 @@ type OutputFormatter interface {
 // FormatterOptions configures formatter behavior.
 type FormatterOptions struct {
 	ProfilePath string // For SARIF: reference to profile location
+	Verbosity   int    // For table: one of the Verbosity tiers
+	Width       int    // For table: terminal width to truncate lines to (0 = no limit)
 	Indent      bool   // For JSON: pretty-print with indentation
+	Color       bool   // For table: ANSI colors
+	Language    string // For table, HTML and text: language of the report strings
 }
 
+// Verbosity tiers of human-readable output.
+const (
+	VerbosityQuiet    = -1 // a single summary line
+	VerbosityNormal   = 0  // every control, and the observations that did not pass
+	VerbosityDetail   = 1  // every observation, with timing and personal data findings
+	VerbosityEvidence = 2  // every observation, with evidence snippets
+)
+
 // OutputFormatterFactory creates formatters by name.
 type OutputFormatterFactory interface {
 	// Create returns a formatter for the given format name.
 	// Returns error if format is unknown.
 	Create(format string, writer io.Writer, options FormatterOptions) (OutputFormatter, error)
 
+	// CreateStream returns a writer that emits results incrementally.
+	// Returns error if the format does not support streaming.
+	CreateStream(format string, writer io.Writer, options FormatterOptions) (execution.ResultStream, error)
+
 	// SupportedFormats returns list of available format names.
 	SupportedFormats() []string
 }
 
+// ResultExporter sends a finalized execution result to an external sink,
+// such as a ticketing system or a custom report format.
+type ResultExporter interface {
+	// Name returns the name used to select the exporter (--export <name>).
+	Name() string
+
+	// Export delivers the result. It is called once, after the result is finalized.
+	Export(ctx context.Context, result *execution.ExecutionResult) error
+}
+
+// ConfigurableExporter is implemented by exporters that read settings from
+// the profile's integrations section of the same name.
+type ConfigurableExporter interface {
+	ResultExporter
+
+	// WithConfig returns a copy of the exporter using the given settings.
+	WithConfig(config map[string]interface{}) (ResultExporter, error)
+}
+
+// ResultExporterResolver finds exporters by name, either compiled into the
+// binary or loaded as WASM exporter plugins.
+type ResultExporterResolver interface {
+	// Resolve returns the exporter registered or installed under name.
+	Resolve(ctx context.Context, name string) (ResultExporter, error)
+
+	// Close releases resources held by loaded exporter plugins.
+	Close(ctx context.Context) error
+}
+
 // OutputWriter writes formatted output to destination.
 type OutputWriter interface {
 	Write(ctx context.Context, data []byte, dest string) error
@@ -13,9 +13,10 @@ import (
 // CapabilityGatekeeper handles capability granting decisions, user interaction, and persistence.
 // This is an application service responsible for the security boundary between required and granted capabilities.
 type CapabilityGatekeeper struct {
-	fileStore     *infraCapabilities.FileStore
-	prompter      *infraCapabilities.TerminalPrompter
-	securityLevel string // Security level: strict, standard, permissive
+	fileStore      *infraCapabilities.FileStore
+	prompter       *infraCapabilities.TerminalPrompter
+	securityLevel  string // Security level: strict, standard, permissive
+	promptDisabled bool   // Never prompt, even on a terminal
 }
 
 // NewCapabilityGatekeeper creates a new capability gatekeeper.
@@ -27,6 +28,13 @@ func NewCapabilityGatekeeper(configPath string, securityLevel string) *Capabilit
 	}
 }
 
+// DisablePrompts makes the gatekeeper grant only saved capabilities, as in
+// non-interactive mode, even when running on a terminal. Servers use this so
+// a run triggered remotely never waits on the server's console.
+func (g *CapabilityGatekeeper) DisablePrompts() {
+	g.promptDisabled = true
+}
+
 // GrantCapabilities determines which capabilities to grant based on security policy, user input, and saved grants.
 // It handles the complete granting workflow: check saved grants, apply security policy, prompt if needed, persist decisions.
 //
@@ -65,7 +73,7 @@ func (g *CapabilityGatekeeper) GrantCapabilities(
 	}
 
 	// Non-interactive mode check
-	if !g.prompter.IsInteractive() {
+	if g.promptDisabled || !g.prompter.IsInteractive() {
 		return capabilities.NewGrant(), g.prompter.FormatNonInteractiveError(missing)
 	}
 
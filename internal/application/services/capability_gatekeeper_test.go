@@ -1,6 +1,7 @@
 package services
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/reglet-dev/reglet/internal/application/ports"
@@ -123,3 +124,14 @@ func TestCapabilityGatekeeper_EmptyRequired(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, granted)
 }
+
+func TestCapabilityGatekeeper_DisablePrompts(t *testing.T) {
+	gatekeeper := NewCapabilityGatekeeper(filepath.Join(t.TempDir(), "config.yaml"), "standard")
+	gatekeeper.DisablePrompts()
+
+	required := capabilities.NewGrant()
+	required.Add(capabilities.Capability{Kind: "exec", Pattern: "/bin/ls"})
+
+	_, err := gatekeeper.GrantCapabilities(required, map[string]ports.CapabilityInfo{}, false)
+	assert.Error(t, err, "missing grants fail instead of prompting")
+}
@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
 	"github.com/reglet-dev/reglet/internal/domain/entities"
 	domainServices "github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/reglet-dev/reglet/internal/domain/values"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -25,6 +28,7 @@ type CapabilityOrchestrator struct {
 	analyzer       ports.CapabilityAnalyzer
 	gatekeeper     ports.CapabilityGatekeeperPort
 	runtimeFactory ports.PluginRuntimeFactory
+	processLoader  ports.ProcessPluginLoader
 	capabilityInfo map[string]ports.CapabilityInfo
 	trustAll       bool
 }
@@ -65,6 +69,12 @@ func NewCapabilityOrchestratorWithDeps(
 	}
 }
 
+// SetProcessPluginLoader enables external process plugins. Plugins without a
+// WASM module are then loaded from their reglet-plugin-<name> executable.
+func (o *CapabilityOrchestrator) SetProcessPluginLoader(loader ports.ProcessPluginLoader) {
+	o.processLoader = loader
+}
+
 // CollectCapabilities creates a temporary runtime and collects required capabilities.
 // Returns the required capabilities and the temporary runtime (caller must close it).
 func (o *CapabilityOrchestrator) CollectCapabilities(ctx context.Context, profile entities.ProfileReader, pluginDir string) (map[string][]capabilities.Capability, ports.PluginRuntime, error) {
@@ -95,62 +105,142 @@ func (o *CapabilityOrchestrator) CollectRequiredCapabilities(ctx context.Context
 	pluginNames := extractPluginNames(profile)
 
 	// Load plugins in parallel to get their declared capabilities
-	pluginMetaCaps, err := o.loadPluginCapabilities(ctx, runtime, pluginDir, pluginNames)
+	plugins, err := o.loadPlugins(ctx, runtime, pluginDir, pluginNames)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkPluginVersions(profile, plugins); err != nil {
+		return nil, err
+	}
+	pluginMetaCaps := make(map[string][]capabilities.Capability, len(plugins))
+	for name, info := range plugins {
+		pluginMetaCaps[name] = info.Capabilities
+	}
 
 	// Merge profile-extracted capabilities with plugin metadata
-	return o.mergeCapabilities(pluginNames, profileCaps, pluginMetaCaps)
+	required, err := o.mergeCapabilities(pluginNames, profileCaps, pluginMetaCaps)
+	if err != nil {
+		return nil, err
+	}
+
+	o.addObservationEnvCapabilities(profile, required)
+	return required, nil
 }
 
-// extractPluginNames gets unique plugin names from all profile controls.
+// addObservationEnvCapabilities requests an env:observation:KEY capability for
+// every variable set through an observation's env block. It passes the
+// profile's value to that observation only, never the host's variable, and is
+// skipped when the plugin already needs the host's variable. They are added
+// on top of the merged capabilities so they never displace what the plugin
+// itself needs.
+func (o *CapabilityOrchestrator) addObservationEnvCapabilities(profile entities.ProfileReader, required map[string][]capabilities.Capability) {
+	for _, obs := range profile.AllObservations() {
+		keys := make([]string, 0, len(obs.Env))
+		for key := range obs.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			capability := capabilities.ObservationEnvCapability(key)
+			if slices.Contains(required[obs.Plugin], capability) ||
+				slices.Contains(required[obs.Plugin], capabilities.Capability{Kind: "env", Pattern: key}) {
+				continue
+			}
+			required[obs.Plugin] = append(required[obs.Plugin], capability)
+			o.capabilityInfo[capability.Kind+":"+capability.Pattern] = ports.CapabilityInfo{
+				Capability:     capability,
+				IsProfileBased: true,
+				PluginName:     obs.Plugin,
+			}
+		}
+	}
+}
+
+// extractPluginNames gets unique plugin names from all profile observations.
 func extractPluginNames(profile entities.ProfileReader) map[string]bool {
 	pluginNames := make(map[string]bool)
-	for _, ctrl := range profile.GetAllControls() {
-		for _, obs := range ctrl.ObservationDefinitions {
-			pluginNames[obs.Plugin] = true
-		}
+	for _, obs := range profile.AllObservations() {
+		pluginNames[obs.Plugin] = true
 	}
 	return pluginNames
 }
 
-// loadPluginCapabilities loads plugins in parallel and collects their declared capabilities.
-func (o *CapabilityOrchestrator) loadPluginCapabilities(ctx context.Context, runtime ports.PluginRuntime, pluginDir string, pluginNames map[string]bool) (map[string][]capabilities.Capability, error) {
+// loadPlugins loads plugins in parallel and collects their metadata.
+// Plugins that composite plugins depend on are loaded too and added to
+// pluginNames, since a composite run needs the union of its own and its
+// dependencies' capabilities.
+func (o *CapabilityOrchestrator) loadPlugins(ctx context.Context, runtime ports.PluginRuntime, pluginDir string, pluginNames map[string]bool) (map[string]*ports.PluginInfo, error) {
 	// Convert to slice for parallel iteration
 	names := make([]string, 0, len(pluginNames))
 	for name := range pluginNames {
 		names = append(names, name)
 	}
 
-	// Thread-safe map for collecting plugin metadata capabilities
+	// Thread-safe map for collecting plugin metadata
 	var mu sync.Mutex
-	pluginMetaCaps := make(map[string][]capabilities.Capability)
+	plugins := make(map[string]*ports.PluginInfo)
+
+	for len(names) > 0 {
+		var dependencies []string
+		g, gctx := errgroup.WithContext(ctx)
+		for _, name := range names {
+			g.Go(func() error {
+				info, err := o.loadSinglePlugin(gctx, runtime, pluginDir, name)
+				if err != nil {
+					return err
+				}
+
+				mu.Lock()
+				plugins[name] = info
+				dependencies = append(dependencies, info.Dependencies...)
+				mu.Unlock()
+				return nil
+			})
+		}
 
-	g, gctx := errgroup.WithContext(ctx)
-	for _, name := range names {
-		g.Go(func() error {
-			caps, err := o.loadSinglePlugin(gctx, runtime, pluginDir, name)
-			if err != nil {
-				return err
+		if err := g.Wait(); err != nil {
+			return nil, err
+		}
+
+		// Load dependencies not seen yet in the next round
+		names = names[:0]
+		for _, name := range dependencies {
+			if !pluginNames[name] {
+				pluginNames[name] = true
+				names = append(names, name)
 			}
+		}
+	}
+
+	return plugins, nil
+}
 
-			mu.Lock()
-			pluginMetaCaps[name] = caps
-			mu.Unlock()
-			return nil
-		})
+// checkPluginVersions verifies that every loaded plugin has a version its
+// declaration in the profile accepts.
+func checkPluginVersions(profile entities.ProfileReader, plugins map[string]*ports.PluginInfo) error {
+	registry, err := profile.BuildPluginRegistry()
+	if err != nil {
+		return err
 	}
 
-	if err := g.Wait(); err != nil {
-		return nil, err
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	return pluginMetaCaps, nil
+	for _, name := range names {
+		spec := registry.Resolve(name)
+		if version := plugins[name].Version; !spec.AcceptsVersion(version) {
+			return fmt.Errorf("plugin %s is version %s, but the profile requires %s", name, version, spec.Version)
+		}
+	}
+	return nil
 }
 
-// loadSinglePlugin loads a single plugin and returns its declared capabilities.
-func (o *CapabilityOrchestrator) loadSinglePlugin(ctx context.Context, runtime ports.PluginRuntime, pluginDir, name string) ([]capabilities.Capability, error) {
+// loadSinglePlugin loads a single plugin and returns its metadata.
+func (o *CapabilityOrchestrator) loadSinglePlugin(ctx context.Context, runtime ports.PluginRuntime, pluginDir, name string) (*ports.PluginInfo, error) {
 	// Security: Validate plugin name to prevent path traversal
 	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
 		return nil, fmt.Errorf("invalid plugin name %q: contains path separator or traversal", name)
@@ -166,6 +256,9 @@ func (o *CapabilityOrchestrator) loadSinglePlugin(ctx context.Context, runtime p
 	// Read plugin file using sandboxed Root.ReadFile
 	pluginSubpath := filepath.Join(name, name+".wasm")
 	wasmBytes, err := rootDir.ReadFile(pluginSubpath)
+	if err != nil && o.processLoader != nil && os.IsNotExist(err) {
+		return o.loadProcessPlugin(ctx, rootDir, pluginDir, name)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read plugin %s: %w", name, err)
 	}
@@ -176,22 +269,33 @@ func (o *CapabilityOrchestrator) loadSinglePlugin(ctx context.Context, runtime p
 		return nil, fmt.Errorf("failed to load plugin %s: %w", name, err)
 	}
 
-	// Get plugin metadata
-	info, err := plugin.Describe(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get capabilities from plugin %s: %w", name, err)
+	return describePlugin(ctx, plugin, name)
+}
+
+// loadProcessPlugin loads a plugin shipped as an external process executable
+// and returns its metadata.
+func (o *CapabilityOrchestrator) loadProcessPlugin(ctx context.Context, rootDir *os.Root, pluginDir, name string) (*ports.PluginInfo, error) {
+	execSubpath := filepath.Join(name, values.ProcessPluginExecutable(name))
+	if _, err := rootDir.Stat(execSubpath); err != nil {
+		return nil, fmt.Errorf("failed to read plugin %s: no %s.wasm or %s executable found", name, name, values.ProcessPluginExecutable(name))
 	}
 
-	// Convert to domain capabilities
-	var caps []capabilities.Capability
-	for _, capability := range info.Capabilities {
-		caps = append(caps, capabilities.Capability{
-			Kind:    capability.Kind,
-			Pattern: capability.Pattern,
-		})
+	plugin, err := o.processLoader.LoadProcessPlugin(ctx, name, filepath.Join(pluginDir, execSubpath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load process plugin %s: %w", name, err)
 	}
 
-	return caps, nil
+	return describePlugin(ctx, plugin, name)
+}
+
+// describePlugin returns the metadata of a loaded plugin, including the
+// capabilities it declares.
+func describePlugin(ctx context.Context, plugin ports.Plugin, name string) (*ports.PluginInfo, error) {
+	info, err := plugin.Describe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get capabilities from plugin %s: %w", name, err)
+	}
+	return info, nil
 }
 
 // mergeCapabilities merges profile-extracted capabilities with plugin metadata.
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/reglet-dev/reglet/internal/application/ports"
@@ -233,3 +235,146 @@ func TestCapabilityOrchestrator_WithMockAnalyzer(t *testing.T) {
 // - Delegation to domain services
 // - Coordination of plugin loading and granting
 // - Security policy application
+
+// mockProcessPluginLoader is a test double for ProcessPluginLoader.
+type mockProcessPluginLoader struct {
+	loadedPath string
+}
+
+func (m *mockProcessPluginLoader) LoadProcessPlugin(_ context.Context, _, path string) (ports.Plugin, error) {
+	m.loadedPath = path
+	return &mockProcessPlugin{}, nil
+}
+
+type mockProcessPlugin struct{}
+
+func (m *mockProcessPlugin) Describe(_ context.Context) (*ports.PluginInfo, error) {
+	return &ports.PluginInfo{
+		Name:         "ldap",
+		Capabilities: []capabilities.Capability{{Kind: "network", Pattern: "outbound:636"}},
+	}, nil
+}
+
+// TestCapabilityOrchestrator_ProcessPluginFallback verifies that plugins without
+// a WASM module are loaded from their process executable when enabled.
+func TestCapabilityOrchestrator_ProcessPluginFallback(t *testing.T) {
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "ldap"), 0o750))
+	execPath := filepath.Join(pluginDir, "ldap", "reglet-plugin-ldap")
+	require.NoError(t, os.WriteFile(execPath, []byte("#!/bin/sh\n"), 0o700))
+
+	analyzer := domainServices.NewCapabilityAnalyzer(capabilities.NewRegistry())
+	orchestrator := NewCapabilityOrchestratorWithDeps(analyzer, &mockCapabilityGatekeeper{}, &mockPluginRuntimeFactory{}, false)
+
+	_, err := orchestrator.loadSinglePlugin(context.Background(), &mockPluginRuntime{}, pluginDir, "ldap")
+	require.Error(t, err, "process plugins are ignored without a loader")
+
+	loader := &mockProcessPluginLoader{}
+	orchestrator.SetProcessPluginLoader(loader)
+
+	info, err := orchestrator.loadSinglePlugin(context.Background(), &mockPluginRuntime{}, pluginDir, "ldap")
+	require.NoError(t, err)
+	assert.Equal(t, []capabilities.Capability{{Kind: "network", Pattern: "outbound:636"}}, info.Capabilities)
+	assert.Equal(t, execPath, loader.loadedPath)
+
+	_, err = orchestrator.loadSinglePlugin(context.Background(), &mockPluginRuntime{}, pluginDir, "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reglet-plugin-missing")
+}
+
+func TestCapabilityOrchestrator_AddObservationEnvCapabilities(t *testing.T) {
+	t.Parallel()
+
+	orchestrator := NewCapabilityOrchestrator("", false, capabilities.NewRegistry(), &mockPluginRuntimeFactory{})
+	profile := &entities.Profile{
+		Controls: entities.ControlsSection{
+			Items: []entities.Control{
+				{
+					ID: "ctrl-1",
+					ObservationDefinitions: []entities.ObservationDefinition{
+						{Plugin: "command", Env: map[string]string{"PGSSLMODE": "require", "LANG": "C"}},
+						{Plugin: "command", Env: map[string]string{"LANG": "C"}},
+					},
+				},
+			},
+		},
+	}
+	required := map[string][]capabilities.Capability{
+		"command": {{Kind: "exec", Pattern: "psql"}, {Kind: "env", Pattern: "PGSSLMODE"}},
+	}
+
+	orchestrator.addObservationEnvCapabilities(profile, required)
+
+	// The plugin already reads the host's PGSSLMODE; LANG is scoped to the
+	// profile's value
+	assert.Equal(t, []capabilities.Capability{
+		{Kind: "exec", Pattern: "psql"},
+		{Kind: "env", Pattern: "PGSSLMODE"},
+		{Kind: "env", Pattern: "observation:LANG"},
+	}, required["command"])
+	assert.True(t, orchestrator.capabilityInfo["env:observation:LANG"].IsProfileBased)
+	assert.Equal(t, "command", orchestrator.capabilityInfo["env:observation:LANG"].PluginName)
+}
+
+// namedPluginRuntime loads plugins described by their name.
+type namedPluginRuntime struct {
+	plugins map[string]*ports.PluginInfo
+}
+
+func (m *namedPluginRuntime) LoadPlugin(_ context.Context, name string, _ []byte) (ports.Plugin, error) {
+	return &namedPlugin{info: m.plugins[name]}, nil
+}
+
+func (m *namedPluginRuntime) Close(_ context.Context) error {
+	return nil
+}
+
+type namedPlugin struct {
+	info *ports.PluginInfo
+}
+
+func (m *namedPlugin) Describe(_ context.Context) (*ports.PluginInfo, error) {
+	return m.info, nil
+}
+
+// TestCapabilityOrchestrator_LoadsDependencies verifies that the plugins a
+// composite plugin depends on are loaded, so their capabilities are requested.
+func TestCapabilityOrchestrator_LoadsDependencies(t *testing.T) {
+	t.Parallel()
+
+	pluginDir := t.TempDir()
+	for _, name := range []string{"webapp", "http", "tls"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, name), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(pluginDir, name, name+".wasm"), []byte("wasm"), 0o600))
+	}
+	runtime := &namedPluginRuntime{plugins: map[string]*ports.PluginInfo{
+		"webapp": {Name: "webapp", Dependencies: []string{"http", "tls"}},
+		"http":   {Name: "http", Capabilities: []capabilities.Capability{{Kind: "network", Pattern: "outbound:443"}}},
+		"tls":    {Name: "tls", Dependencies: []string{"http"}, Capabilities: []capabilities.Capability{{Kind: "network", Pattern: "outbound:*"}}},
+	}}
+
+	orchestrator := NewCapabilityOrchestrator("", false, capabilities.NewRegistry(), &mockPluginRuntimeFactory{})
+	pluginNames := map[string]bool{"webapp": true}
+	plugins, err := orchestrator.loadPlugins(context.Background(), runtime, pluginDir, pluginNames)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{"webapp": true, "http": true, "tls": true}, pluginNames)
+	assert.Equal(t, []capabilities.Capability{{Kind: "network", Pattern: "outbound:443"}}, plugins["http"].Capabilities)
+	assert.Equal(t, []capabilities.Capability{{Kind: "network", Pattern: "outbound:*"}}, plugins["tls"].Capabilities)
+	assert.Empty(t, plugins["webapp"].Capabilities)
+}
+
+func TestCheckPluginVersions(t *testing.T) {
+	t.Parallel()
+
+	profile := &entities.Profile{Plugins: entities.PluginDeclarations{"reglet/http@1.0", "http2=reglet/http@2.0", "dns"}}
+	plugins := map[string]*ports.PluginInfo{
+		"http":  {Name: "http", Version: "1.0.4"},
+		"http2": {Name: "http", Version: "2.0.0"},
+		"dns":   {Name: "dns", Version: "0.3.0"},
+	}
+	require.NoError(t, checkPluginVersions(profile, plugins))
+
+	plugins["http2"] = &ports.PluginInfo{Name: "http", Version: "1.0.4"}
+	assert.EqualError(t, checkPluginVersions(profile, plugins), "plugin http2 is version 1.0.4, but the profile requires 2.0")
+}
@@ -5,8 +5,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 	"github.com/reglet-dev/reglet/internal/domain/entities"
 	"github.com/reglet-dev/reglet/internal/domain/execution"
 	"github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/reglet-dev/reglet/internal/domain/values"
 )
 
 // CheckProfileUseCase orchestrates the complete profile check workflow.
@@ -32,6 +35,7 @@ type CheckProfileUseCase struct {
 	lockfileService  *LockfileService
 	pluginService    *PluginService
 	engineFactory    ports.EngineFactory
+	pluginReader     ports.PluginFileReader
 	logger           *slog.Logger
 }
 
@@ -66,20 +70,36 @@ func NewCheckProfileUseCase(
 	}
 }
 
+// SetPluginReader makes runs read local plugin files through reader. Without
+// one, every run reads them from disk as they are.
+func (uc *CheckProfileUseCase) SetPluginReader(reader ports.PluginFileReader) {
+	uc.pluginReader = reader
+}
+
 // Execute runs the complete check profile workflow.
 func (uc *CheckProfileUseCase) Execute(ctx context.Context, req dto.CheckProfileRequest) (*dto.CheckProfileResponse, error) {
 	startTime := time.Now()
 
+	if req.Execution.ProfilePerf {
+		ctx = execution.WithPerfRecorder(ctx, execution.NewPerfRecorder())
+	}
+
 	uc.logger.Info("loading profile", "path", req.ProfilePath)
 
 	// 1-2. Load and compile (clean up imports, validation)
-	profile, err := uc.loadAndCompileProfile(req.ProfilePath)
+	loadStart := time.Now()
+	profile, err := uc.loadAndCompileProfile(req.ProfilePath, req.Environment)
 	if err != nil {
 		return nil, err
 	}
+	recordPhase(ctx, execution.PhaseProfileLoad, loadStart)
 
 	uc.logger.Info("profile compiled and validated", "controls", profile.ControlCount())
 
+	if req.Execution.PluginMode == dto.PluginModeNative {
+		return uc.executeNative(ctx, profile, req, startTime)
+	}
+
 	// 2b. Resolve/Lock plugins
 	if err := uc.resolveAndLockPlugins(ctx, profile, req.ProfilePath); err != nil {
 		return nil, err
@@ -97,21 +117,30 @@ func (uc *CheckProfileUseCase) Execute(ctx context.Context, req dto.CheckProfile
 		localPluginDir = ""
 	}
 
-	// 4b. Validate Declared Plugins
-	if err := uc.validateDeclaredPlugins(profile, localPluginDir); err != nil {
+	// 4b. Validate Declared Plugins. With --skip-missing-plugins, plugins
+	// that are not installed are left out of the setup and the engine marks
+	// their observations not_run
+	installed := profile
+	if req.Execution.SkipMissingPlugins {
+		installed, err = uc.withoutMissingPlugins(profile, localPluginDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.validateDeclaredPlugins(installed, localPluginDir); err != nil {
 		return nil, err
 	}
 
 	// 5. Prepare Plugin Runtime Environment (Hybrid Local/OCI)
 	// Creates a temporary directory with symlinks to all required plugins
-	runtimePluginDir, cleanup, err := uc.preparePluginEnvironment(ctx, profile.Plugins, localPluginDir)
+	runtimePluginDir, cleanup, err := uc.preparePluginEnvironment(ctx, installed.Plugins, localPluginDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare plugin environment: %w", err)
 	}
 	defer cleanup()
 
 	// 6-8. Prepare Engine using runtime dir
-	eng, requiredCaps, grantedCaps, err := uc.prepareEngine(ctx, profile, runtimePluginDir, req)
+	eng, requiredCaps, grantedCaps, err := uc.prepareEngine(ctx, installed, runtimePluginDir, req)
 	if err != nil {
 		return nil, err
 	}
@@ -124,16 +153,48 @@ func (uc *CheckProfileUseCase) Execute(ctx context.Context, req dto.CheckProfile
 	}
 
 	// 10. Start Response
-	return uc.buildResponse(req, startTime, result, requiredCaps, grantedCaps), nil
+	return uc.buildResponse(req, startTime, profile, result, requiredCaps, grantedCaps), nil
 }
 
-func (uc *CheckProfileUseCase) loadAndCompileProfile(path string) (*entities.ValidatedProfile, error) {
-	rawProfile, err := uc.profileLoader.LoadProfile(path)
+// executeNative runs the profile against plugins linked into the binary.
+// Lockfile resolution, plugin directories and capability grants only apply to
+// WASM plugins, so they are skipped.
+func (uc *CheckProfileUseCase) executeNative(
+	ctx context.Context,
+	profile *entities.ValidatedProfile,
+	req dto.CheckProfileRequest,
+	startTime time.Time,
+) (*dto.CheckProfileResponse, error) {
+	if err := uc.validateFilters(profile, req.Filters); err != nil {
+		return nil, err
+	}
+
+	if req.Execution.Offline {
+		return nil, apperrors.NewValidationError("execution", "offline mode cannot be enforced on native plugins")
+	}
+	uc.logger.Warn("running plugins natively: plugins are not sandboxed and capabilities are not enforced")
+
+	eng, err := uc.engineFactory.CreateEngine(ctx, profile, nil, "", req.Filters, req.Execution, req.Options.SkipSchemaValidation)
+	if err != nil {
+		return nil, apperrors.NewConfigurationError("engine", "failed to create engine", err)
+	}
+	defer func() { _ = eng.Close(ctx) }()
+
+	result, err := uc.executeProfile(ctx, eng, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.buildResponse(req, startTime, profile, result, nil, nil), nil
+}
+
+func (uc *CheckProfileUseCase) loadAndCompileProfile(path, environment string) (*entities.ValidatedProfile, error) {
+	rawProfile, err := uc.profileLoader.LoadProfile(path, environment)
 	if err != nil {
 		return nil, apperrors.NewValidationError("profile", "failed to load profile", err.Error())
 	}
 
-	uc.logger.Info("profile loaded", "name", rawProfile.Metadata.Name, "version", rawProfile.Metadata.Version)
+	uc.logger.Info("profile loaded", "name", rawProfile.Metadata.Name, "version", rawProfile.Metadata.Version, "environment", rawProfile.Environment)
 
 	profile, err := uc.profileCompiler.Compile(rawProfile)
 	if err != nil {
@@ -157,12 +218,7 @@ func (uc *CheckProfileUseCase) resolveAndLockPlugins(
 	for _, p := range profile.Plugins {
 		spec, _ := entities.ParsePluginDeclaration(p) // Error checked in ResolvePlugins
 		if locked := lockfile.GetPlugin(spec.Name); locked != nil {
-			// Using simplified strict declaration: currently appending @resolved
-			strictDecl := fmt.Sprintf("%s@%s", spec.Name, locked.Resolved)
-			if spec.Name != spec.Source {
-				strictDecl = fmt.Sprintf("%s@%s", spec.Source, locked.Resolved)
-			}
-			strictPlugins = append(strictPlugins, strictDecl)
+			strictPlugins = append(strictPlugins, lockedDeclaration(p, spec, locked.Resolved))
 		} else {
 			strictPlugins = append(strictPlugins, p)
 		}
@@ -173,6 +229,28 @@ func (uc *CheckProfileUseCase) resolveAndLockPlugins(
 	return nil
 }
 
+// lockedDeclaration pins a plugin declaration to the version its lockfile
+// entry resolved, keeping its alias. Declarations pinned by digest or registry
+// tag are already exact and kept as they are.
+func lockedDeclaration(decl string, spec *entities.PluginSpec, resolved string) string {
+	alias, source, aliased := strings.Cut(decl, "=")
+	if !aliased {
+		source = decl
+	}
+	if spec.Digest != "" || strings.Contains(source[strings.LastIndex(source, "/")+1:], ":") {
+		return decl
+	}
+
+	if idx := strings.LastIndex(source, "@"); idx != -1 {
+		source = source[:idx]
+	}
+	source += "@" + resolved
+	if aliased {
+		return alias + "=" + source
+	}
+	return source
+}
+
 func (uc *CheckProfileUseCase) resolvePluginDir(ctx context.Context, override string) (string, error) {
 	if override != "" {
 		return override, nil
@@ -191,14 +269,25 @@ func (uc *CheckProfileUseCase) prepareEngine(
 	map[string][]capabilities.Capability,
 	error,
 ) {
+	collectStart := time.Now()
 	requiredCaps, tempRuntime, err := uc.capOrchestrator.CollectCapabilities(ctx, profile, pluginDir)
 	if err != nil {
 		return nil, nil, nil, apperrors.NewConfigurationError("capabilities", "failed to collect capabilities", err)
 	}
+	recordPhase(ctx, execution.PhaseCapabilityCollection, collectStart)
 	if tempRuntime != nil {
 		_ = tempRuntime.Close(ctx)
 	}
 
+	if req.Execution.Offline {
+		if req.Execution.ReplayCassette == "" {
+			if err := checkOffline(profile.GetAllControls(), req.Filters, requiredCaps); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		requiredCaps = withoutNetwork(requiredCaps)
+	}
+
 	grantedCaps, err := uc.capOrchestrator.GrantCapabilities(requiredCaps, req.Options.TrustPlugins)
 	if err != nil {
 		return nil, nil, nil, apperrors.NewCapabilityError("capability grant failed", flattenCapabilities(requiredCaps))
@@ -220,6 +309,54 @@ func (uc *CheckProfileUseCase) prepareEngine(
 	return eng, requiredCaps, grantedCaps, nil
 }
 
+// checkOffline returns an OfflineError listing the controls selected by
+// filters whose plugins need network capabilities.
+func checkOffline(controls []entities.Control, filters dto.FilterOptions, required map[string][]capabilities.Capability) error {
+	selected, _, err := selectControls(controls, filters)
+	if err != nil {
+		return err
+	}
+
+	var blockers []apperrors.OfflineBlocker
+	for _, ctrl := range selected {
+		var plugins []string
+		for _, obs := range ctrl.ObservationDefinitions {
+			if !slices.Contains(plugins, obs.Plugin) && slices.ContainsFunc(required[obs.Plugin], isNetwork) {
+				plugins = append(plugins, obs.Plugin)
+			}
+		}
+		if len(plugins) > 0 {
+			blockers = append(blockers, apperrors.OfflineBlocker{ControlID: ctrl.ID, Plugins: plugins})
+		}
+	}
+	if len(blockers) > 0 {
+		return &apperrors.OfflineError{Controls: blockers}
+	}
+	return nil
+}
+
+// withoutNetwork returns caps without network capabilities, so offline runs
+// never grant them.
+func withoutNetwork(caps map[string][]capabilities.Capability) map[string][]capabilities.Capability {
+	filtered := make(map[string][]capabilities.Capability, len(caps))
+	for plugin, pluginCaps := range caps {
+		filtered[plugin] = slices.DeleteFunc(slices.Clone(pluginCaps), isNetwork)
+	}
+	return filtered
+}
+
+func isNetwork(c capabilities.Capability) bool {
+	return c.Kind == "network"
+}
+
+// recordPhase records the time since start against a phase when the run is
+// being profiled.
+func recordPhase(ctx context.Context, phase string, start time.Time) {
+	if recorder, ok := execution.PerfRecorderFromContext(ctx); ok {
+		recorder.RecordPhase(phase, time.Since(start))
+	}
+}
+
 func (uc *CheckProfileUseCase) executeProfile(
 	ctx context.Context,
 	eng ports.ExecutionEngine,
@@ -244,11 +381,13 @@ func (uc *CheckProfileUseCase) executeProfile(
 func (uc *CheckProfileUseCase) buildResponse(
 	req dto.CheckProfileRequest,
 	startTime time.Time,
+	profile entities.ProfileReader,
 	result *execution.ExecutionResult,
 	reqCaps, grantedCaps map[string][]capabilities.Capability,
 ) *dto.CheckProfileResponse {
 	return &dto.CheckProfileResponse{
 		ExecutionResult: result,
+		Integrations:    profile.GetIntegrations(),
 		Metadata: dto.ResponseMetadata{
 			RequestID:   req.Metadata.RequestID,
 			ProcessedAt: time.Now(),
@@ -296,9 +435,8 @@ func (uc *CheckProfileUseCase) validateFilters(profile entities.ProfileReader, f
 
 	// Compile filter expression if provided
 	if filters.FilterExpression != "" {
-		_, err := expr.Compile(filters.FilterExpression,
-			expr.Env(services.ControlEnv{}),
-			expr.AsBool())
+		options := append([]expr.Option{expr.Env(services.ControlEnv{}), expr.AsBool()}, services.ExpressionFunctions()...)
+		_, err := expr.Compile(filters.FilterExpression, options...)
 		if err != nil {
 			return apperrors.NewValidationError(
 				"filters",
@@ -318,6 +456,13 @@ var builtInPlugins = map[string]bool{
 	"tcp":     true,
 	"smtp":    true,
 	"command": true,
+	"mock":    true,
+}
+
+// IsBuiltInPlugin reports whether name is a plugin embedded in the reglet
+// binary.
+func IsBuiltInPlugin(name string) bool {
+	return builtInPlugins[name]
 }
 
 // validateDeclaredPlugins validates that declared plugins exist and all used plugins are declared.
@@ -326,10 +471,21 @@ func (uc *CheckProfileUseCase) validateDeclaredPlugins(profile entities.ProfileR
 	declaredPlugins := profile.GetPlugins()
 	usedPlugins := uc.getUsedPlugins(profile)
 
-	// Build set of declared plugin names for lookup
+	// Build set of declared plugin names (or aliases) for lookup. Two
+	// declarations of one name, e.g. acme/customdb and corp/customdb, would
+	// silently shadow each other
 	declaredSet := make(map[string]bool)
+	declaredBy := make(map[string]string)
 	for _, declared := range declaredPlugins {
-		declaredSet[extractPluginName(declared)] = true
+		alias, _ := splitPluginAlias(declared)
+		if previous, ok := declaredBy[alias]; ok && previous != declared {
+			return apperrors.NewValidationError(
+				"plugins",
+				fmt.Sprintf("plugins %q and %q are both named %q; give one an alias (e.g. '- %s-2: %s')", previous, declared, alias, alias, declared),
+			)
+		}
+		declaredBy[alias] = declared
+		declaredSet[alias] = true
 	}
 
 	// 1. Check if used plugins are declared
@@ -343,10 +499,8 @@ func (uc *CheckProfileUseCase) validateDeclaredPlugins(profile entities.ProfileR
 
 func (uc *CheckProfileUseCase) getUsedPlugins(profile entities.ProfileReader) map[string]bool {
 	usedPlugins := make(map[string]bool)
-	for _, ctrl := range profile.GetAllControls() {
-		for _, obs := range ctrl.ObservationDefinitions {
-			usedPlugins[obs.Plugin] = true
-		}
+	for _, obs := range profile.AllObservations() {
+		usedPlugins[obs.Plugin] = true
 	}
 	return usedPlugins
 }
@@ -378,35 +532,224 @@ func (uc *CheckProfileUseCase) checkMissingDeclarations(declared []string, used
 
 func (uc *CheckProfileUseCase) verifyPluginExistence(declared []string, pluginDir string) error {
 	for _, rawDecl := range declared {
-		// Extract plugin name from path if it's a path (e.g., ./plugins/file/file.wasm -> file)
-		pluginName := extractPluginName(rawDecl)
+		found, err := pluginExists(rawDecl, pluginDir)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return apperrors.NewValidationError(
+				"plugins",
+				fmt.Sprintf("declared plugin %q not found (not built-in and not found at %s)", rawDecl, pluginDir),
+			)
+		}
+	}
+	return nil
+}
+
+// pluginExists reports whether a plugin declaration is built in, installed
+// in pluginDir, or a path to an existing plugin file.
+func pluginExists(rawDecl, pluginDir string) (bool, error) {
+	// Extract plugin name from path if it's a path (e.g., ./plugins/file/file.wasm -> file)
+	_, source := splitPluginAlias(rawDecl)
+	pluginName := extractPluginName(source)
+
+	// Check if it's a built-in plugin
+	if builtInPlugins[pluginName] {
+		return true, nil
+	}
+
+	// Check if external plugin exists on filesystem
+	if pluginDir != "" {
+		path, err := findInstalledPlugin(pluginDir, source)
+		if err != nil {
+			return false, apperrors.NewValidationError("plugins", err.Error())
+		}
+		if path != "" {
+			return true, nil
+		}
+	}
+
+	// Check if declared path exists directly (for ./plugins/... format)
+	if strings.HasPrefix(source, "./") || strings.HasPrefix(source, "/") {
+		if _, err := os.Stat(source); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
 
-		// Check if it's a built-in plugin
-		if builtInPlugins[pluginName] {
+// withoutMissingPlugins returns a copy of profile without the declarations
+// of plugins that are not installed and without the observations using
+// them, for setting up a run with --skip-missing-plugins. The profile itself
+// is left whole, so its result still lists those observations.
+func (uc *CheckProfileUseCase) withoutMissingPlugins(profile *entities.ValidatedProfile, pluginDir string) (*entities.ValidatedProfile, error) {
+	missing := make(map[string]bool)
+	var plugins entities.PluginDeclarations
+	for _, rawDecl := range profile.Plugins {
+		found, err := pluginExists(rawDecl, pluginDir)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			alias, _ := splitPluginAlias(rawDecl)
+			missing[alias] = true
 			continue
 		}
+		plugins = append(plugins, rawDecl)
+	}
+	if len(missing) == 0 {
+		return profile, nil
+	}
+
+	names := slices.Sorted(maps.Keys(missing))
+	uc.logger.Warn("plugins are not installed; their observations will not run", "plugins", strings.Join(names, ", "))
+
+	installed := *profile.Profile
+	installed.Plugins = plugins
+	installed.Controls.Items = make([]entities.Control, 0, len(profile.Controls.Items))
+	for _, ctrl := range profile.Controls.Items {
+		ctrl.ObservationDefinitions = slices.DeleteFunc(slices.Clone(ctrl.ObservationDefinitions), func(obs entities.ObservationDefinition) bool {
+			return missing[obs.Plugin]
+		})
+		installed.Controls.Items = append(installed.Controls.Items, ctrl)
+	}
+	return entities.NewValidatedProfile(&installed), nil
+}
+
+// splitPluginAlias splits a plugin declaration into the name observations use
+// and the declaration of the plugin itself. Aliased declarations
+// ("http2=reglet/http@2.0") are used by their alias, others by their plugin
+// name.
+func splitPluginAlias(declared string) (alias, source string) {
+	if alias, source, ok := strings.Cut(declared, "="); ok {
+		return alias, source
+	}
+	return extractPluginName(declared), declared
+}
+
+// findInstalledPlugin returns the path of the WASM module or process
+// executable installed in pluginDir for a plugin declaration, or "" if the
+// plugin is not installed. Third-party plugins are installed under their
+// namespace (<pluginDir>/acme/customdb/); a plugin declared by its short name
+// must be installed under exactly one namespace, or without one. Only
+// plugins of the reglet namespace are found outside their namespace
+// directory. A pinned version selects the highest matching release installed
+// under <install>/<version>/; otherwise the plugin's unversioned install is
+// used, and its version is verified once it is loaded. Pinning a version no
+// installed release matches is an error.
+func findInstalledPlugin(pluginDir, declared string) (string, error) {
+	pluginName := extractPluginName(declared)
+	spec, err := entities.ParsePluginDeclaration(declared)
+	if err != nil {
+		spec = &entities.PluginSpec{Name: pluginName}
+	}
 
-		// Check if external plugin exists on filesystem
-		if pluginDir != "" {
-			pluginPath := filepath.Join(pluginDir, pluginName, pluginName+".wasm")
-			if _, err := os.Stat(pluginPath); err == nil {
-				continue
+	installDir, err := pluginInstallDir(pluginDir, pluginName, spec.Namespace)
+	if err != nil || installDir == "" {
+		return "", err
+	}
+
+	var candidates, releases []string
+	if spec.Version != "" && spec.Version != "latest" {
+		entries, _ := os.ReadDir(installDir)
+		for _, entry := range entries {
+			if entry.IsDir() {
+				releases = append(releases, entry.Name())
 			}
 		}
+		if version, ok := spec.SelectVersion(releases); ok {
+			releaseDir := filepath.Join(installDir, version)
+			candidates = append(candidates,
+				filepath.Join(releaseDir, pluginName+".wasm"),
+				filepath.Join(releaseDir, values.ProcessPluginExecutable(pluginName)),
+			)
+		}
+	}
 
-		// Check if declared path exists directly (for ./plugins/... format)
-		if strings.HasPrefix(rawDecl, "./") || strings.HasPrefix(rawDecl, "/") {
-			if _, err := os.Stat(rawDecl); err == nil {
-				continue
-			}
+	candidates = append(candidates, filepath.Join(installDir, pluginName+".wasm"))
+	if spec.Namespace == "" || spec.Namespace == builtinPluginNamespace {
+		candidates = append(candidates, filepath.Join(pluginDir, pluginName+".wasm"))
+	}
+	candidates = append(candidates, filepath.Join(installDir, values.ProcessPluginExecutable(pluginName)))
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return c, nil
 		}
+	}
 
-		return apperrors.NewValidationError(
-			"plugins",
-			fmt.Sprintf("declared plugin %q not found (not built-in and not found at %s)", rawDecl, pluginDir),
-		)
+	if len(releases) > 0 {
+		return "", fmt.Errorf("plugin %q is not installed in a matching version (installed: %s)", declared, strings.Join(releases, ", "))
 	}
-	return nil
+	return "", nil
+}
+
+// builtinPluginNamespace is the namespace of the plugins reglet ships, which
+// are also installed without a namespace directory.
+const builtinPluginNamespace = "reglet"
+
+// pluginInstallDir returns the directory a plugin is installed in:
+// <pluginDir>/<namespace>/<name> for namespace-qualified plugins, otherwise
+// <pluginDir>/<name>. Only plugins of the reglet namespace fall back to
+// <pluginDir>/<name>; other namespaces never resolve to a plugin of the same
+// name installed elsewhere, so it returns "" when they are not installed. A
+// plugin declared by its short name that is installed more than once, under
+// different namespaces or also without one, is a conflict: it must be
+// declared with its namespace.
+func pluginInstallDir(pluginDir, pluginName, namespace string) (string, error) {
+	if namespace != "" {
+		if dir := filepath.Join(pluginDir, namespace, pluginName); isDir(dir) {
+			return dir, nil
+		}
+		if namespace != builtinPluginNamespace {
+			return "", nil
+		}
+		return filepath.Join(pluginDir, pluginName), nil
+	}
+
+	var installs []string
+	if isDir(filepath.Join(pluginDir, pluginName)) {
+		installs = append(installs, pluginName)
+	}
+	entries, _ := os.ReadDir(pluginDir)
+	for _, entry := range entries {
+		ns := entry.Name()
+		if _, err := values.NewPluginName(ns); err != nil || !entry.IsDir() || ns == pluginName {
+			continue
+		}
+		// A plugin's own directory holds releases, not namespaced plugins
+		if isPluginInstall(filepath.Join(pluginDir, ns), ns) {
+			continue
+		}
+		if isDir(filepath.Join(pluginDir, ns, pluginName)) {
+			installs = append(installs, ns+"/"+pluginName)
+		}
+	}
+
+	switch {
+	case len(installs) > 1:
+		return "", fmt.Errorf("plugin %q is installed more than once (%s); declare it with its namespace, e.g. %q",
+			pluginName, strings.Join(installs, ", "), installs[len(installs)-1])
+	case len(installs) == 1:
+		return filepath.Join(pluginDir, installs[0]), nil
+	default:
+		return filepath.Join(pluginDir, pluginName), nil
+	}
+}
+
+// isPluginInstall reports whether dir holds plugin name itself: its WASM
+// module or process executable.
+func isPluginInstall(dir, name string) bool {
+	for _, file := range []string{name + ".wasm", values.ProcessPluginExecutable(name)} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
 
 // extractPluginName extracts the plugin name from a path or returns the input.
@@ -414,12 +757,15 @@ func (uc *CheckProfileUseCase) verifyPluginExistence(declared []string, pluginDi
 //   - "./plugins/file/file.wasm" -> "file"
 //   - "file" -> "file"
 //   - "/path/to/custom.wasm" -> "custom"
+//   - "./plugins/ldap/reglet-plugin-ldap" -> "ldap"
 func extractPluginName(declared string) string {
 	name := declared
-	// If it's a path, extract the base name without extension
-	if strings.Contains(name, "/") {
+	// If it's a path, extract the base name without extension or process plugin prefix
+	if strings.ContainsAny(name, `/\`) {
 		base := filepath.Base(name)
 		name = strings.TrimSuffix(base, ".wasm")
+		name = strings.TrimSuffix(name, ".exe")
+		name = strings.TrimPrefix(name, values.ProcessPluginPrefix)
 	}
 
 	// Strip version/digest suffix if present (e.g. name@1.0 or name@sha256:...)
@@ -478,6 +824,9 @@ func (uc *CheckProfileUseCase) prepareSinglePlugin(
 	localPluginDir string,
 	tempDir string,
 ) error {
+	// Plugins are placed under the name observations use, so aliased
+	// versions of one plugin live side by side
+	alias, decl := splitPluginAlias(decl)
 	pluginName := extractPluginName(decl)
 	var sourcePath string
 
@@ -486,16 +835,11 @@ func (uc *CheckProfileUseCase) prepareSinglePlugin(
 		sourcePath = decl
 	} else if localPluginDir != "" {
 		// Search in local plugin dir
-		candidates := []string{
-			filepath.Join(localPluginDir, pluginName, pluginName+".wasm"),
-			filepath.Join(localPluginDir, pluginName+".wasm"),
-		}
-		for _, c := range candidates {
-			if _, err := os.Stat(c); err == nil {
-				sourcePath = c
-				break
-			}
+		path, err := findInstalledPlugin(localPluginDir, decl)
+		if err != nil {
+			return apperrors.NewValidationError("plugins", err.Error())
 		}
+		sourcePath = path
 	}
 
 	// 2. If locally found, use it (Link/Copy)
@@ -507,19 +851,25 @@ func (uc *CheckProfileUseCase) prepareSinglePlugin(
 		}
 		sourcePath = absSource
 
-		// Create subdirectory: tempDir/pluginName
-		pluginDir := filepath.Join(tempDir, pluginName)
+		// Create subdirectory: tempDir/alias
+		pluginDir := filepath.Join(tempDir, alias)
 		if err := os.MkdirAll(pluginDir, 0o750); err != nil {
 			return fmt.Errorf("create plugin dir %s: %w", pluginDir, err)
 		}
-		destPath := filepath.Join(pluginDir, pluginName+".wasm")
+		destPath := filepath.Join(pluginDir, alias+".wasm")
+		var mode os.FileMode = 0o600
+		if strings.HasPrefix(filepath.Base(sourcePath), values.ProcessPluginPrefix) {
+			// External process plugins keep their executable name and must stay executable
+			destPath = filepath.Join(pluginDir, values.ProcessPluginExecutable(alias))
+			mode = 0o700
+		}
 
 		// Always copy to avoid "path escapes from parent" errors in sandoxed runtimes
-		data, err := os.ReadFile(filepath.Clean(sourcePath))
+		data, err := uc.readPlugin(ctx, sourcePath)
 		if err != nil {
 			return fmt.Errorf("read plugin %s: %w", sourcePath, err)
 		}
-		if err := os.WriteFile(destPath, data, 0o600); err != nil {
+		if err := os.WriteFile(destPath, data, mode); err != nil {
 			return fmt.Errorf("write plugin to temp %s: %w", destPath, err)
 		}
 		return nil
@@ -534,11 +884,11 @@ func (uc *CheckProfileUseCase) prepareSinglePlugin(
 			return fmt.Errorf("load remote plugin %s: %w", decl, err)
 		}
 
-		pluginDir := filepath.Join(tempDir, pluginName)
+		pluginDir := filepath.Join(tempDir, alias)
 		if err := os.MkdirAll(pluginDir, 0o750); err != nil {
 			return fmt.Errorf("create plugin dir %s: %w", pluginDir, err)
 		}
-		destPath := filepath.Join(pluginDir, pluginName+".wasm")
+		destPath := filepath.Join(pluginDir, alias+".wasm")
 
 		// Always copy to avoid sandbox issues
 		data, err := os.ReadFile(filepath.Clean(path))
@@ -561,3 +911,11 @@ func (uc *CheckProfileUseCase) prepareSinglePlugin(
 		fmt.Sprintf("plugin %q not found locally or in registry, and is not built-in", decl),
 	)
 }
+
+// readPlugin reads a local plugin file through the plugin reader, if any.
+func (uc *CheckProfileUseCase) readPlugin(ctx context.Context, path string) ([]byte, error) {
+	if uc.pluginReader != nil {
+		return uc.pluginReader.ReadPlugin(ctx, path)
+	}
+	return os.ReadFile(filepath.Clean(path))
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindInstalledPlugin(t *testing.T) {
+	t.Parallel()
+
+	pluginDir := t.TempDir()
+	for _, path := range []string{
+		"http/http.wasm",
+		"http/1.0.2/http.wasm",
+		"http/1.0.10/http.wasm",
+		"http/2.0.0/http.wasm",
+		"dns/1.0.0/dns.wasm",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, filepath.Dir(path)), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(pluginDir, path), []byte("wasm"), 0o600))
+	}
+
+	tests := []struct {
+		declared string
+		want     string
+		wantErr  string
+	}{
+		{declared: "http", want: "http/http.wasm"},
+		{declared: "http@latest", want: "http/http.wasm"},
+		{declared: "reglet/http@1.0", want: "http/1.0.10/http.wasm"},
+		{declared: "http@2.0.0", want: "http/2.0.0/http.wasm"},
+		{declared: "http@3.0", want: "http/http.wasm"}, // Unversioned install, verified once loaded
+		{declared: "dns@2.0", wantErr: `plugin "dns@2.0" is not installed in a matching version (installed: 1.0.0)`},
+		{declared: "tcp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.declared, func(t *testing.T) {
+			t.Parallel()
+			path, err := findInstalledPlugin(pluginDir, tt.declared)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.want == "" {
+				assert.Empty(t, path)
+				return
+			}
+			assert.Equal(t, filepath.Join(pluginDir, tt.want), path)
+		})
+	}
+}
+
+func TestSplitPluginAlias(t *testing.T) {
+	t.Parallel()
+
+	alias, source := splitPluginAlias("http2=reglet/http@2.0")
+	assert.Equal(t, "http2", alias)
+	assert.Equal(t, "reglet/http@2.0", source)
+
+	alias, source = splitPluginAlias("./plugins/file/file.wasm")
+	assert.Equal(t, "file", alias)
+	assert.Equal(t, "./plugins/file/file.wasm", source)
+}
+
+func TestLockedDeclaration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		declared string
+		resolved string
+		want     string
+	}{
+		{"file", "latest", "file@latest"},
+		{"reglet/http@1.0", "1.0", "reglet/http@1.0"},
+		{"http2=reglet/http@2.0", "2.0.1", "http2=reglet/http@2.0.1"},
+		{"ghcr.io/reglet-dev/reglet-plugins/file:1.2.0", "1.2.0", "ghcr.io/reglet-dev/reglet-plugins/file:1.2.0"},
+		{"ghcr.io/reglet-dev/reglet-plugins/file@sha256:abc", "latest", "ghcr.io/reglet-dev/reglet-plugins/file@sha256:abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.declared, func(t *testing.T) {
+			t.Parallel()
+			spec, err := entities.ParsePluginDeclaration(tt.declared)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, lockedDeclaration(tt.declared, spec, tt.resolved))
+		})
+	}
+}
+
+func TestFindInstalledPlugin_Namespaces(t *testing.T) {
+	t.Parallel()
+
+	pluginDir := t.TempDir()
+	for _, path := range []string{
+		"acme/customdb/customdb.wasm",
+		"corp/customdb/customdb.wasm",
+		"acme/ldap/1.2.0/ldap.wasm",
+		"http/http.wasm",
+		"http/2.0.0/http.wasm",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, filepath.Dir(path)), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(pluginDir, path), []byte("wasm"), 0o600))
+	}
+
+	path, err := findInstalledPlugin(pluginDir, "acme/customdb")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(pluginDir, "acme/customdb/customdb.wasm"), path)
+
+	path, err = findInstalledPlugin(pluginDir, "ldap@1.2")
+	require.NoError(t, err, "a plugin installed under one namespace is found by its short name")
+	assert.Equal(t, filepath.Join(pluginDir, "acme/ldap/1.2.0/ldap.wasm"), path)
+
+	path, err = findInstalledPlugin(pluginDir, "reglet/http")
+	require.NoError(t, err, "reglet declarations fall back to the unnamespaced install")
+	assert.Equal(t, filepath.Join(pluginDir, "http/http.wasm"), path)
+
+	path, err = findInstalledPlugin(pluginDir, "evil/http")
+	require.NoError(t, err)
+	assert.Empty(t, path, "other namespaces are not installed unless under their namespace")
+
+	_, err = findInstalledPlugin(pluginDir, "customdb")
+	assert.EqualError(t, err, `plugin "customdb" is installed more than once (acme/customdb, corp/customdb); declare it with its namespace, e.g. "corp/customdb"`)
+}
+
+func TestValidateDeclaredPlugins_NameConflict(t *testing.T) {
+	t.Parallel()
+
+	uc := &CheckProfileUseCase{}
+	profile := &entities.Profile{
+		Plugins: entities.PluginDeclarations{"acme/customdb", "corp/customdb"},
+	}
+	err := uc.validateDeclaredPlugins(profile, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `plugins "acme/customdb" and "corp/customdb" are both named "customdb"; give one an alias`)
+
+	profile.Plugins = entities.PluginDeclarations{"acme/customdb", "customdb2=corp/customdb"}
+	err = uc.validateDeclaredPlugins(profile, "")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "both named", "aliased declarations do not conflict")
+}
+
+func TestWithoutMissingPlugins(t *testing.T) {
+	t.Parallel()
+
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "ldap"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "ldap", "ldap.wasm"), []byte("wasm"), 0o600))
+
+	profile := entities.NewValidatedProfile(&entities.Profile{
+		Plugins: entities.PluginDeclarations{"ldap", "customdb", "db2=acme/customdb", "file"},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "mixed", ObservationDefinitions: []entities.ObservationDefinition{{Plugin: "ldap"}, {Plugin: "customdb"}}},
+			{ID: "missing", ObservationDefinitions: []entities.ObservationDefinition{{Plugin: "db2"}}},
+			{ID: "builtin", ObservationDefinitions: []entities.ObservationDefinition{{Plugin: "file"}}},
+		}},
+	})
+
+	uc := &CheckProfileUseCase{logger: slog.New(slog.DiscardHandler)}
+	installed, err := uc.withoutMissingPlugins(profile, pluginDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.PluginDeclarations{"ldap", "file"}, installed.Plugins)
+	assert.Equal(t, []entities.ObservationDefinition{{Plugin: "ldap"}}, installed.Controls.Items[0].ObservationDefinitions)
+	assert.Empty(t, installed.Controls.Items[1].ObservationDefinitions)
+	assert.Len(t, installed.Controls.Items[2].ObservationDefinitions, 1)
+	require.NoError(t, uc.validateDeclaredPlugins(installed, pluginDir))
+
+	// The profile that runs keeps every observation
+	assert.Len(t, profile.Plugins, 4)
+	assert.Len(t, profile.Controls.Items[0].ObservationDefinitions, 2)
+	assert.Len(t, profile.Controls.Items[1].ObservationDefinitions, 1)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+// EnvironmentComparisonService compares the latest saved run of a profile in
+// each environment and reports configuration drift between them.
+type EnvironmentComparisonService struct {
+	results ports.ExecutionResultLoader
+	logger  *slog.Logger
+}
+
+// NewEnvironmentComparisonService creates a new environment comparison service.
+func NewEnvironmentComparisonService(results ports.ExecutionResultLoader, logger *slog.Logger) *EnvironmentComparisonService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &EnvironmentComparisonService{
+		results: results,
+		logger:  logger,
+	}
+}
+
+// Compare loads the results under req.Paths, picks the latest run of the
+// profile per environment and compares them. Runs without an environment
+// are ignored.
+func (s *EnvironmentComparisonService) Compare(ctx context.Context, req dto.CompareEnvironmentsRequest) (*execution.EnvironmentComparison, error) {
+	files, err := s.results.DiscoverResults(req.Paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no execution results (*.json) found in %s", strings.Join(req.Paths, ", "))
+	}
+
+	var runs []*execution.ExecutionResult
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := s.results.LoadResult(file)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, result)
+	}
+
+	runs, err = selectProfileRuns(runs, req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]*execution.ExecutionResult)
+	for _, run := range runs {
+		if run.Environment == "" {
+			s.logger.Debug("ignoring run without environment", "execution_id", run.ExecutionID.String())
+			continue
+		}
+		if current, ok := latest[run.Environment]; !ok || run.StartTime.After(current.StartTime) {
+			latest[run.Environment] = run
+		}
+	}
+	found := make([]string, 0, len(latest))
+	for env := range latest {
+		found = append(found, env)
+	}
+	sort.Strings(found)
+
+	environments := found
+	if len(req.Environments) > 0 {
+		environments = req.Environments
+	}
+
+	selected := make([]*execution.ExecutionResult, 0, len(environments))
+	for _, env := range environments {
+		run, ok := latest[env]
+		if !ok {
+			return nil, fmt.Errorf("no run of profile %s in environment %q (found: %s)", runs[0].ProfileName, env, describeEnvironments(found))
+		}
+		s.logger.Debug("comparing run", "environment", env, "execution_id", run.ExecutionID.String(), "start_time", run.StartTime)
+		selected = append(selected, run)
+	}
+	if len(selected) < 2 {
+		return nil, fmt.Errorf("need runs of profile %s in at least two environments, found: %s", runs[0].ProfileName, describeEnvironments(found))
+	}
+
+	return execution.CompareEnvironments(selected, req.Ignore), nil
+}
+
+// selectProfileRuns returns the runs of profile, or of the only profile the
+// runs cover when profile is empty.
+func selectProfileRuns(runs []*execution.ExecutionResult, profile string) ([]*execution.ExecutionResult, error) {
+	byProfile := make(map[string][]*execution.ExecutionResult)
+	for _, run := range runs {
+		byProfile[run.ProfileName] = append(byProfile[run.ProfileName], run)
+	}
+	names := make([]string, 0, len(byProfile))
+	for name := range byProfile {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if profile == "" {
+		if len(names) > 1 {
+			return nil, fmt.Errorf("results cover several profiles (%s); choose one with --profile", strings.Join(names, ", "))
+		}
+		return runs, nil
+	}
+	selected, ok := byProfile[profile]
+	if !ok {
+		return nil, fmt.Errorf("no results for profile %q (found: %s)", profile, strings.Join(names, ", "))
+	}
+	return selected, nil
+}
+
+// describeEnvironments lists environment names for error messages.
+func describeEnvironments(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
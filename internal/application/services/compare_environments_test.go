@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResultLoader serves results by path.
+type fakeResultLoader map[string]*execution.ExecutionResult
+
+func (l fakeResultLoader) DiscoverResults(_ []string) ([]string, error) {
+	paths := make([]string, 0, len(l))
+	for path := range l {
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func (l fakeResultLoader) LoadResult(path string) (*execution.ExecutionResult, error) {
+	return l[path], nil
+}
+
+func savedRun(profile, env string, start time.Time, status values.Status) *execution.ExecutionResult {
+	result := execution.NewExecutionResult(profile, "1.0.0")
+	result.Environment = env
+	result.StartTime = start
+	result.Controls = []execution.ControlResult{{ID: "tls", Name: "TLS", Status: status}}
+	return result
+}
+
+func TestEnvironmentComparisonService_Compare(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	loader := fakeResultLoader{
+		"prod-old.json":    savedRun("web", "prod", day, values.StatusFail),
+		"prod-new.json":    savedRun("web", "prod", day.Add(time.Hour), values.StatusPass),
+		"staging.json":     savedRun("web", "staging", day, values.StatusPass),
+		"qa.json":          savedRun("web", "qa", day, values.StatusFail),
+		"no-env.json":      savedRun("web", "", day, values.StatusFail),
+		"other-prof.json":  savedRun("db", "prod", day, values.StatusFail),
+		"other-prof2.json": savedRun("db", "staging", day, values.StatusFail),
+	}
+	svc := NewEnvironmentComparisonService(loader, NewTestLogger())
+
+	t.Run("latest run per environment", func(t *testing.T) {
+		t.Parallel()
+		cmp, err := svc.Compare(context.Background(), dto.CompareEnvironmentsRequest{Profile: "web", Environments: []string{"staging", "prod"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"staging", "prod"}, cmp.EnvironmentNames())
+		assert.Equal(t, day.Add(time.Hour), cmp.Environments[1].StartTime)
+		assert.False(t, cmp.HasDrift(), "the older failing prod run is superseded")
+	})
+
+	t.Run("all environments by name", func(t *testing.T) {
+		t.Parallel()
+		cmp, err := svc.Compare(context.Background(), dto.CompareEnvironmentsRequest{Profile: "web"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod", "qa", "staging"}, cmp.EnvironmentNames())
+		require.Len(t, cmp.Controls, 1)
+		assert.Equal(t, map[string]values.Status{"prod": values.StatusPass, "qa": values.StatusFail, "staging": values.StatusPass}, cmp.Controls[0].Statuses)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := svc.Compare(context.Background(), dto.CompareEnvironmentsRequest{})
+		assert.EqualError(t, err, "results cover several profiles (db, web); choose one with --profile")
+
+		_, err = svc.Compare(context.Background(), dto.CompareEnvironmentsRequest{Profile: "api"})
+		assert.EqualError(t, err, `no results for profile "api" (found: db, web)`)
+
+		_, err = svc.Compare(context.Background(), dto.CompareEnvironmentsRequest{Profile: "web", Environments: []string{"dev", "prod"}})
+		assert.EqualError(t, err, `no run of profile web in environment "dev" (found: prod, qa, staging)`)
+
+		_, err = svc.Compare(context.Background(), dto.CompareEnvironmentsRequest{Profile: "web", Environments: []string{"prod"}})
+		assert.EqualError(t, err, "need runs of profile web in at least two environments, found: prod, qa, staging")
+	})
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+// ContinuousCheckService re-runs a profile on an interval and passes on only
+// the controls whose status changed, so a control that keeps failing alerts
+// once instead of on every run.
+type ContinuousCheckService struct {
+	logger *slog.Logger
+}
+
+// NewContinuousCheckService creates a new continuous check service.
+func NewContinuousCheckService(logger *slog.Logger) *ContinuousCheckService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ContinuousCheckService{logger: logger}
+}
+
+// Run calls run every opts.Interval until ctx is cancelled. After each run,
+// notify receives a result holding only the controls that transitioned, each
+// with its Transition set; it is not called when nothing changed. Failed runs
+// and notifications are logged and retried on the next interval rather than
+// ending the loop.
+func (s *ContinuousCheckService) Run(
+	ctx context.Context,
+	opts dto.ContinuousCheckOptions,
+	run func(context.Context) (*execution.ExecutionResult, error),
+	notify func(context.Context, *execution.ExecutionResult) error,
+) error {
+	tracker := execution.NewTransitionTracker(opts.FlapWindow, opts.FlapThreshold)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for iteration := 1; ; iteration++ {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+		timer.Reset(opts.Interval)
+
+		result, err := run(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			s.logger.Error("continuous check run failed", "iteration", iteration, "error", err)
+			continue
+		}
+
+		delta := tracker.Observe(result)
+		s.logger.Info("continuous check run complete",
+			"iteration", iteration,
+			"passed", result.Summary.PassedControls,
+			"failed", result.Summary.FailedControls,
+			"errors", result.Summary.ErrorControls,
+			"transitions", len(delta.Controls))
+		if len(delta.Controls) == 0 {
+			continue
+		}
+		if err := notify(ctx, delta); err != nil {
+			s.logger.Error("failed to notify transitions", "iteration", iteration, "error", err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContinuousCheckService_Run(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	statuses := []values.Status{values.StatusPass, values.StatusFail, "", values.StatusFail, values.StatusPass}
+	runs := 0
+	run := func(context.Context) (*execution.ExecutionResult, error) {
+		status := statuses[runs]
+		runs++
+		if runs == len(statuses) {
+			cancel()
+		}
+		if status == "" {
+			return nil, errors.New("profile not found")
+		}
+		result := execution.NewExecutionResult("web", "1.0.0")
+		result.Controls = []execution.ControlResult{{ID: "tls", Status: status}}
+		result.Finalize()
+		return result, nil
+	}
+
+	var notified []execution.ControlTransition
+	notify := func(_ context.Context, delta *execution.ExecutionResult) error {
+		for _, ctrl := range delta.Controls {
+			notified = append(notified, *ctrl.Transition)
+		}
+		return errors.New("sink unavailable")
+	}
+
+	svc := NewContinuousCheckService(NewTestLogger())
+	err := svc.Run(ctx, dto.ContinuousCheckOptions{Interval: time.Millisecond, FlapWindow: 10, FlapThreshold: 4}, run, notify)
+	require.NoError(t, err)
+
+	assert.Equal(t, len(statuses), runs, "the loop stops once the context is cancelled")
+	assert.Equal(t, []execution.ControlTransition{
+		{Kind: execution.TransitionFailing, From: values.StatusPass},
+	}, notified, "repeated failures, failed runs and the cancelled run are not notified")
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	apperrors "github.com/reglet-dev/reglet/internal/application/errors"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// DistributedCheckUseCase runs a profile across agents: it places each
+// selected control on one agent, has every agent run its share, and merges
+// the partial results into one result recording which agent ran what.
+// Controls with runs_on entries only go to agents having them as facts,
+// either as configured tags or reported by the agent.
+//
+// Agents load the profile themselves, so variables and secrets resolve with
+// the agent's configuration and capabilities are granted by the agent's
+// policy. An agent that fails turns its controls into errors; the other
+// agents' controls are still reported.
+type DistributedCheckUseCase struct {
+	bundler       ports.ProfileBundler
+	client        ports.AgentClient
+	compiler      *services.ProfileCompiler
+	partitioner   *services.ControlPartitioner
+	repository    repositories.ExecutionResultRepository
+	logger        *slog.Logger
+	agents        []dto.AgentNode
+	regletVersion string
+}
+
+// NewDistributedCheckUseCase creates a use case running profiles on agents
+// through client. regletVersion is recorded in merged results.
+func NewDistributedCheckUseCase(
+	bundler ports.ProfileBundler,
+	client ports.AgentClient,
+	agents []dto.AgentNode,
+	regletVersion string,
+	logger *slog.Logger,
+) *DistributedCheckUseCase {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &DistributedCheckUseCase{
+		bundler:       bundler,
+		client:        client,
+		compiler:      services.NewProfileCompiler(),
+		partitioner:   services.NewControlPartitioner(),
+		logger:        logger,
+		agents:        agents,
+		regletVersion: regletVersion,
+	}
+}
+
+// SetResultRepository saves merged results to repository, like local runs
+// are saved. Agents should not be configured to store their partial results.
+func (uc *DistributedCheckUseCase) SetResultRepository(repository repositories.ExecutionResultRepository) {
+	uc.repository = repository
+}
+
+// Execute runs the profile of req across the agents.
+func (uc *DistributedCheckUseCase) Execute(ctx context.Context, req dto.CheckProfileRequest) (*dto.CheckProfileResponse, error) {
+	startTime := time.Now()
+	if len(uc.agents) == 0 {
+		return nil, apperrors.NewConfigurationError("cluster", "no agents configured", nil)
+	}
+
+	raw, document, err := uc.bundler.BundleProfile(req.ProfilePath)
+	if err != nil {
+		return nil, apperrors.NewValidationError("profile", "failed to load profile", err.Error())
+	}
+	if err := raw.SelectEnvironment(req.Environment); err != nil {
+		return nil, apperrors.NewValidationError("profile", "failed to load profile", err.Error())
+	}
+	profile, err := uc.compiler.Compile(raw)
+	if err != nil {
+		return nil, apperrors.NewValidationError("profile", "compilation failed", err.Error())
+	}
+
+	selected, skipped, err := selectControls(profile.GetAllControls(), req.Filters)
+	if err != nil {
+		return nil, err
+	}
+	result := execution.NewExecutionResult(profile.Metadata.Name, profile.Metadata.Version)
+	result.Environment = req.Environment
+	result.RegletVersion = uc.regletVersion
+
+	nodes := uc.placementNodes(ctx, selected, result)
+	if len(nodes) == 0 {
+		return nil, apperrors.NewExecutionError("", "no agent is reachable", nil)
+	}
+	placement, err := uc.partitioner.Partition(selected, nodes)
+	if err != nil {
+		return nil, apperrors.NewConfigurationError("cluster", "failed to place controls", err)
+	}
+	for id, runsOn := range placement.Unplaced {
+		skipped[id] = "no agent satisfies runs_on: " + strings.Join(runsOn, ", ")
+	}
+	if len(placement.Unplaced) > 0 {
+		uc.logger.Warn("controls skipped: no agent satisfies their runs_on", "controls", len(placement.Unplaced))
+	}
+
+	index := make(map[string]int)
+	controls := make(map[string]entities.Control)
+	for i, ctrl := range profile.GetAllControls() {
+		index[ctrl.ID] = i
+		controls[ctrl.ID] = ctrl
+	}
+	for id, reason := range skipped {
+		ctrl := newControlResult(controls[id], index[id])
+		ctrl.Status = values.StatusSkipped
+		ctrl.SkipReason = reason
+		ctrl.Message = reason
+		result.AddControlResult(ctrl)
+	}
+
+	var wg sync.WaitGroup
+	for _, agent := range uc.agents {
+		ids, ok := placement.Controls[agent.Name]
+		if !ok {
+			continue
+		}
+		partial := dto.PartialCheckRequest{
+			Profile:                   document,
+			Environment:               req.Environment,
+			RequestID:                 req.Metadata.RequestID,
+			ControlIDs:                ids,
+			Parallel:                  req.Execution.Parallel,
+			MaxConcurrentControls:     req.Execution.MaxConcurrentControls,
+			MaxConcurrentObservations: req.Execution.MaxConcurrentObservations,
+			MaxEvidenceSizeBytes:      req.Execution.MaxEvidenceSizeBytes,
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run := execution.NodeRun{Node: agent.Name, Address: agent.Address, Controls: ids, StartTime: time.Now()}
+			uc.logger.Info("running controls on agent", "agent", agent.Name, "controls", len(ids))
+
+			partialResult, err := uc.client.ExecutePartial(ctx, agent, partial)
+			run.EndTime = time.Now()
+			reason := fmt.Sprintf("agent %s returned no result for the control", agent.Name)
+			if err != nil {
+				uc.logger.Error("agent failed", "agent", agent.Name, "error", err)
+				run.Error = err.Error()
+				reason = fmt.Sprintf("agent %s failed: %v", agent.Name, err)
+			} else {
+				run.ExecutionID = partialResult.GetID().String()
+				run.RegletVersion = partialResult.RegletVersion
+			}
+
+			for _, id := range result.AddNodeResult(run, partialResult) {
+				ctrl := newControlResult(controls[id], index[id])
+				ctrl.Status = values.StatusError
+				ctrl.Message = reason
+				ctrl.Node = agent.Name
+				result.AddControlResult(ctrl)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, apperrors.NewExecutionError("", "execution failed", err)
+	}
+	result.Finalize()
+
+	if uc.repository != nil {
+		if err := uc.repository.Save(ctx, result); err != nil {
+			uc.logger.Warn("failed to persist execution result", "error", err, "execution_id", result.GetID())
+		}
+	}
+
+	return &dto.CheckProfileResponse{
+		ExecutionResult: result,
+		Integrations:    profile.GetIntegrations(),
+		Metadata: dto.ResponseMetadata{
+			RequestID:   req.Metadata.RequestID,
+			ProcessedAt: time.Now(),
+			Duration:    time.Since(startTime),
+		},
+	}, nil
+}
+
+// placementNodes returns the agents controls can be placed on. When some of
+// controls have runs_on entries, each agent is asked for its facts; agents
+// that cannot answer are left out and recorded in result.
+func (uc *DistributedCheckUseCase) placementNodes(ctx context.Context, controls []entities.Control, result *execution.ExecutionResult) []services.PlacementNode {
+	var commands []string
+	constrained := false
+	for _, ctrl := range controls {
+		for _, fact := range ctrl.RunsOn {
+			constrained = true
+			if command, ok := strings.CutPrefix(fact, entities.RunsOnCommandPrefix); ok && !slices.Contains(commands, command) {
+				commands = append(commands, command)
+			}
+		}
+	}
+
+	nodes := make([]services.PlacementNode, len(uc.agents))
+	for i, agent := range uc.agents {
+		nodes[i] = services.PlacementNode{Name: agent.Name, Tags: agent.Tags, Facts: agent.Tags}
+	}
+	if !constrained {
+		return nodes
+	}
+
+	reported := make([][]string, len(uc.agents))
+	failures := make([]error, len(uc.agents))
+	var wg sync.WaitGroup
+	for i, agent := range uc.agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reported[i], failures[i] = uc.client.Facts(ctx, agent, commands)
+		}()
+	}
+	wg.Wait()
+
+	available := nodes[:0]
+	for i, node := range nodes {
+		if err := failures[i]; err != nil {
+			uc.logger.Error("agent unavailable", "agent", node.Name, "error", err)
+			now := time.Now()
+			result.Nodes = append(result.Nodes, execution.NodeRun{
+				StartTime: now,
+				EndTime:   now,
+				Node:      node.Name,
+				Address:   uc.agents[i].Address,
+				Error:     fmt.Sprintf("failed to get facts: %v", err),
+			})
+			continue
+		}
+		node.Facts = append(slices.Clone(node.Tags), reported[i]...)
+		available = append(available, node)
+	}
+	return available
+}
+
+// selectControls applies filters like the engine does, returning the
+// controls to run and the skip reasons of the others.
+func selectControls(all []entities.Control, filters dto.FilterOptions) ([]entities.Control, map[string]string, error) {
+	filter := services.NewControlFilter().
+		WithExclusiveControls(filters.IncludeControlIDs).
+		WithExcludedControls(filters.ExcludeControlIDs).
+		WithExcludedTags(filters.ExcludeTags).
+		WithIncludedTags(filters.IncludeTags).
+		WithIncludedSeverities(filters.IncludeSeverities)
+	if filters.FilterExpression != "" {
+		options := append([]expr.Option{expr.Env(services.ControlEnv{}), expr.AsBool()}, services.ExpressionFunctions()...)
+		program, err := expr.Compile(filters.FilterExpression, options...)
+		if err != nil {
+			return nil, nil, apperrors.NewValidationError("filters", fmt.Sprintf("invalid --filter expression: %v", err))
+		}
+		filter = filter.WithFilterExpression(program)
+	}
+
+	run := make(map[string]bool)
+	skipped := make(map[string]string)
+	for _, ctrl := range all {
+		if ok, reason := filter.ShouldRun(ctrl); ok {
+			run[ctrl.ID] = true
+		} else {
+			skipped[ctrl.ID] = reason
+		}
+	}
+
+	if filters.IncludeDependencies {
+		dependencies, err := services.NewDependencyResolver().ResolveDependencies(all)
+		if err != nil {
+			return nil, nil, apperrors.NewValidationError("controls", err.Error())
+		}
+		required := make(map[string]bool)
+		for id := range run {
+			for dep := range dependencies[id] {
+				required[dep] = true
+			}
+		}
+		for dep := range required {
+			run[dep] = true
+			delete(skipped, dep)
+		}
+	}
+
+	selected := make([]entities.Control, 0, len(run))
+	for _, ctrl := range all {
+		if run[ctrl.ID] {
+			selected = append(selected, ctrl)
+		}
+	}
+	return selected, skipped, nil
+}
+
+// newControlResult creates the result of a control that did not run on an
+// agent.
+func newControlResult(ctrl entities.Control, index int) execution.ControlResult {
+	return execution.ControlResult{
+		Index:              index,
+		ID:                 ctrl.ID,
+		Name:               ctrl.Name,
+		Description:        ctrl.Description,
+		Rationale:          ctrl.Rationale,
+		References:         ctrl.References,
+		Severity:           ctrl.Severity,
+		Tags:               ctrl.Tags,
+		Labels:             ctrl.Labels,
+		ObservationResults: []execution.ObservationResult{},
+	}
+}
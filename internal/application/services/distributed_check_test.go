@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubBundler struct {
+	profile *entities.Profile
+}
+
+func (b *stubBundler) BundleProfile(string) (*entities.Profile, []byte, error) {
+	return b.profile, []byte("profile document"), nil
+}
+
+// stubAgents answers partial checks like an agent would: every control of
+// the profile is reported, those not requested as skipped.
+type stubAgents struct {
+	failing  map[string]error
+	facts    map[string][]string // reported facts; agents without entry fail
+	requests map[string]dto.PartialCheckRequest
+	controls []string
+	mu       sync.Mutex
+}
+
+func (a *stubAgents) ExecutePartial(_ context.Context, agent dto.AgentNode, req dto.PartialCheckRequest) (*execution.ExecutionResult, error) {
+	a.mu.Lock()
+	a.requests[agent.Name] = req
+	a.mu.Unlock()
+	if err := a.failing[agent.Name]; err != nil {
+		return nil, err
+	}
+
+	result := execution.NewExecutionResult("fleet", "1.0.0")
+	result.RegletVersion = "0.9.0"
+	requested := toSet(req.ControlIDs)
+	for i, id := range a.controls {
+		status := values.StatusSkipped
+		if requested[id] {
+			status = values.StatusPass
+		}
+		result.AddControlResult(execution.ControlResult{ID: id, Index: i, Status: status})
+	}
+	return result, nil
+}
+
+func (a *stubAgents) Facts(_ context.Context, agent dto.AgentNode, commands []string) ([]string, error) {
+	facts, ok := a.facts[agent.Name]
+	if !ok {
+		return nil, errors.New("unavailable")
+	}
+	var reported []string
+	for _, fact := range facts {
+		if command, ok := strings.CutPrefix(fact, "has:"); !ok || slices.Contains(commands, command) {
+			reported = append(reported, fact)
+		}
+	}
+	return reported, nil
+}
+
+func toSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+func fleetProfile() *entities.Profile {
+	observation := []entities.ObservationDefinition{{Plugin: "file"}}
+	return &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "fleet", Version: "1.0.0"},
+		Plugins:  []string{"file"},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "nginx", Name: "nginx", Tags: []string{"web"}, ObservationDefinitions: observation},
+			{ID: "postgres", Name: "postgres", Tags: []string{"db"}, ObservationDefinitions: observation},
+			{ID: "tls", Name: "tls", Tags: []string{"web"}, ObservationDefinitions: observation},
+			{ID: "slow", Name: "slow", Tags: []string{"slow"}, ObservationDefinitions: observation},
+		}},
+	}
+}
+
+func TestDistributedCheckUseCase_Execute(t *testing.T) {
+	t.Parallel()
+
+	agents := &stubAgents{
+		controls: []string{"nginx", "postgres", "tls", "slow"},
+		requests: make(map[string]dto.PartialCheckRequest),
+	}
+	nodes := []dto.AgentNode{
+		{Name: "web-1", Address: "web-1:9443", Tags: []string{"web"}},
+		{Name: "db-1", Address: "db-1:9443", Tags: []string{"db"}},
+	}
+	repo := newFakeResultRepository()
+	uc := NewDistributedCheckUseCase(&stubBundler{profile: fleetProfile()}, agents, nodes, "1.2.3", nil)
+	uc.SetResultRepository(repo)
+
+	response, err := uc.Execute(context.Background(), dto.CheckProfileRequest{
+		ProfilePath: "fleet.yaml",
+		Filters:     dto.FilterOptions{ExcludeTags: []string{"slow"}},
+		Execution:   dto.ExecutionOptions{Parallel: true},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"nginx", "tls"}, agents.requests["web-1"].ControlIDs)
+	assert.Equal(t, []string{"postgres"}, agents.requests["db-1"].ControlIDs)
+	assert.Equal(t, []byte("profile document"), agents.requests["web-1"].Profile)
+	assert.True(t, agents.requests["db-1"].Parallel)
+
+	result := response.ExecutionResult
+	assert.Equal(t, "1.2.3", result.RegletVersion)
+	require.Len(t, result.Controls, 4)
+	nodeOf := make(map[string]string)
+	for _, ctrl := range result.Controls {
+		nodeOf[ctrl.ID] = ctrl.Node
+	}
+	assert.Equal(t, map[string]string{"nginx": "web-1", "postgres": "db-1", "tls": "web-1", "slow": ""}, nodeOf)
+	assert.Equal(t, values.StatusSkipped, result.Controls[3].Status, "filtered controls are skipped without an agent")
+	assert.Equal(t, 3, result.Summary.PassedControls)
+
+	require.Len(t, result.Nodes, 2)
+	for _, run := range result.Nodes {
+		assert.Equal(t, "0.9.0", run.RegletVersion)
+		assert.NotEmpty(t, run.ExecutionID)
+	}
+
+	_, err = repo.FindByID(context.Background(), result.GetID().UUID())
+	assert.NoError(t, err, "the merged result is saved")
+}
+
+func TestDistributedCheckUseCase_AgentFailure(t *testing.T) {
+	t.Parallel()
+
+	agents := &stubAgents{
+		controls: []string{"nginx", "postgres", "tls", "slow"},
+		requests: make(map[string]dto.PartialCheckRequest),
+		failing:  map[string]error{"db-1": errors.New("connection refused")},
+	}
+	nodes := []dto.AgentNode{{Name: "web-1", Tags: []string{"web"}}, {Name: "db-1", Tags: []string{"db"}}}
+	uc := NewDistributedCheckUseCase(&stubBundler{profile: fleetProfile()}, agents, nodes, "", nil)
+
+	response, err := uc.Execute(context.Background(), dto.CheckProfileRequest{ProfilePath: "fleet.yaml"})
+	require.NoError(t, err)
+
+	result := response.ExecutionResult
+	postgres := result.GetControlResultByID("postgres")
+	require.NotNil(t, postgres)
+	assert.Equal(t, values.StatusError, postgres.Status)
+	assert.Contains(t, postgres.Message, "connection refused")
+	assert.Equal(t, "db-1", postgres.Node)
+	assert.Equal(t, values.StatusPass, result.GetControlResultByID("nginx").Status)
+
+	for _, run := range result.Nodes {
+		if run.Node == "db-1" {
+			assert.Equal(t, "connection refused", run.Error)
+		}
+	}
+}
+
+func TestDistributedCheckUseCase_RunsOn(t *testing.T) {
+	t.Parallel()
+
+	profile := fleetProfile()
+	items := profile.Controls.Items
+	items[0].RunsOn = []string{"linux", "has:docker"} // nginx
+	items[1].RunsOn = []string{"windows"}             // postgres
+	items[2].RunsOn = []string{"edge"}                // tls: a configured tag
+	agents := &stubAgents{
+		controls: []string{"nginx", "postgres", "tls", "slow"},
+		requests: make(map[string]dto.PartialCheckRequest),
+		facts: map[string][]string{
+			"web-1":   {"linux"},
+			"build-1": {"linux", "has:docker", "has:git"},
+		},
+	}
+	nodes := []dto.AgentNode{
+		{Name: "web-1", Tags: []string{"web", "edge"}},
+		{Name: "build-1"},
+		{Name: "down-1"},
+	}
+	uc := NewDistributedCheckUseCase(&stubBundler{profile: profile}, agents, nodes, "", nil)
+
+	response, err := uc.Execute(context.Background(), dto.CheckProfileRequest{ProfilePath: "fleet.yaml"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"nginx"}, agents.requests["build-1"].ControlIDs)
+	assert.Equal(t, []string{"tls", "slow"}, agents.requests["web-1"].ControlIDs)
+	assert.NotContains(t, agents.requests, "down-1")
+
+	result := response.ExecutionResult
+	postgres := result.GetControlResultByID("postgres")
+	require.NotNil(t, postgres)
+	assert.Equal(t, values.StatusSkipped, postgres.Status)
+	assert.Equal(t, "no agent satisfies runs_on: windows", postgres.SkipReason)
+
+	var down *execution.NodeRun
+	for i := range result.Nodes {
+		if result.Nodes[i].Node == "down-1" {
+			down = &result.Nodes[i]
+		}
+	}
+	require.NotNil(t, down, "unavailable agents are recorded")
+	assert.Contains(t, down.Error, "unavailable")
+	assert.Empty(t, down.Controls)
+}
+
+func TestDistributedCheckUseCase_NoAgents(t *testing.T) {
+	t.Parallel()
+
+	uc := NewDistributedCheckUseCase(&stubBundler{profile: fleetProfile()}, &stubAgents{}, nil, "", nil)
+	_, err := uc.Execute(context.Background(), dto.CheckProfileRequest{ProfilePath: "fleet.yaml"})
+	assert.ErrorContains(t, err, "no agents configured")
+}
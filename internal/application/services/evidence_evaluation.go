@@ -0,0 +1,180 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// EvidenceEvaluationService evaluates the stored evidence of a run against a
+// profile, so expectations can be iterated on without observing the systems
+// again. Each observation of the profile is answered with the evidence the
+// run stored for the same control, position, plugin and config; observations
+// that changed since the run, or whose evidence was truncated, are errors.
+type EvidenceEvaluationService struct {
+	repo            repositories.ExecutionResultRepository
+	profileLoader   ports.ProfileLoader
+	profileCompiler *services.ProfileCompiler
+	engines         ports.EvaluationEngineFactory
+	logger          *slog.Logger
+}
+
+// NewEvidenceEvaluationService creates a service evaluating runs stored in
+// repo.
+func NewEvidenceEvaluationService(
+	repo repositories.ExecutionResultRepository,
+	profileLoader ports.ProfileLoader,
+	profileCompiler *services.ProfileCompiler,
+	engines ports.EvaluationEngineFactory,
+	logger *slog.Logger,
+) *EvidenceEvaluationService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &EvidenceEvaluationService{
+		repo:            repo,
+		profileLoader:   profileLoader,
+		profileCompiler: profileCompiler,
+		engines:         engines,
+		logger:          logger,
+	}
+}
+
+// Evaluate evaluates the evidence of the run of req against its profile. The
+// profile is resolved for the environment of the run. Controls the run did
+// not observe are skipped.
+func (s *EvidenceEvaluationService) Evaluate(ctx context.Context, req dto.EvaluateEvidenceRequest) (*execution.ExecutionResult, error) {
+	id, err := values.ParseExecutionID(req.RunID)
+	if err != nil {
+		return nil, fmt.Errorf("run %q: %w", req.RunID, err)
+	}
+	source, err := s.repo.FindByID(ctx, id.UUID())
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := s.profileLoader.LoadProfile(req.ProfilePath, source.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profile: %w", err)
+	}
+	profile, err := s.profileCompiler.Compile(raw)
+	if err != nil {
+		return nil, fmt.Errorf("profile compilation failed: %w", err)
+	}
+
+	fixtures, unobserved := storedEvidence(profile.GetAllControls(), source)
+	eng, err := s.engines.CreateEvaluationEngine(fixtures, observedPlugins(profile), source.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = eng.Close(ctx) }()
+
+	result, err := eng.Execute(ctx, profile)
+	if err != nil {
+		return nil, fmt.Errorf("evaluation failed: %w", err)
+	}
+
+	reason := fmt.Sprintf("not observed in run %s", source.GetID())
+	for _, id := range unobserved {
+		ctrl := result.GetControlResultByID(id)
+		if ctrl == nil || ctrl.Status == values.StatusSkipped {
+			continue
+		}
+		ctrl.Status = values.StatusSkipped
+		ctrl.SkipReason = reason
+		ctrl.Message = reason
+		ctrl.ObservationResults = []execution.ObservationResult{}
+	}
+	result.Mode = execution.ModeEvaluate
+	result.EvaluatedRun = source.GetID().String()
+	result.FinalizeAt(result.EndTime)
+
+	if req.Save {
+		if err := s.repo.Save(ctx, result); err != nil {
+			return nil, fmt.Errorf("failed to save result: %w", err)
+		}
+		s.logger.Info("saved evaluated result", "execution_id", result.GetID(), "evaluated_run", result.EvaluatedRun)
+	}
+	return result, nil
+}
+
+// storedEvidence returns a fixture answering each observation of controls
+// with the evidence stored in source, and the IDs of the controls source has
+// no observations for.
+func storedEvidence(controls []entities.Control, source *execution.ExecutionResult) ([]dto.ProfileTestFixture, []string) {
+	var fixtures []dto.ProfileTestFixture
+	var unobserved []string
+	for _, ctrl := range controls {
+		stored := source.GetControlResultByID(ctrl.ID)
+		if stored == nil || len(stored.ObservationResults) == 0 {
+			unobserved = append(unobserved, ctrl.ID)
+			continue
+		}
+		for i, obs := range ctrl.ObservationDefinitions {
+			fixture := evidenceFixture(obs, stored.ObservationResults, i)
+			fixture.Control = ctrl.ID
+			fixture.Observation = &i
+			fixtures = append(fixtures, fixture)
+		}
+	}
+	return fixtures, unobserved
+}
+
+// evidenceFixture answers the observation obs at index i with the evidence
+// stored at the same index.
+func evidenceFixture(obs entities.ObservationDefinition, stored []execution.ObservationResult, i int) dto.ProfileTestFixture {
+	if i >= len(stored) {
+		return errorFixture("no_evidence", "the run has no evidence for this observation")
+	}
+	result := stored[i]
+	if result.Plugin != obs.Plugin || !sameConfig(result.Config, obs.Config) {
+		return errorFixture("evidence_mismatch", fmt.Sprintf("observation changed since the run (was plugin %s with config %s): collect the evidence again", result.Plugin, configJSON(result.Config)))
+	}
+	if result.EvidenceMeta != nil && result.EvidenceMeta.Truncated {
+		return errorFixture("evidence_truncated", "evidence was truncated when collected: collect it again with a larger --max-evidence-size")
+	}
+	if result.Evidence == nil {
+		if result.Error != nil {
+			return errorFixture(result.Error.Code, result.Error.Message)
+		}
+		return errorFixture("no_evidence", "the run has no evidence for this observation")
+	}
+
+	status := result.Evidence.Status
+	fixture := dto.ProfileTestFixture{Plugin: obs.Plugin, Data: result.Evidence.Data, Status: &status}
+	if result.Evidence.Error != nil {
+		fixture.Error = &dto.ProfileTestError{Code: result.Evidence.Error.Code, Message: result.Evidence.Error.Message}
+	}
+	return fixture
+}
+
+func errorFixture(code, message string) dto.ProfileTestFixture {
+	return dto.ProfileTestFixture{Error: &dto.ProfileTestError{Code: code, Message: message}}
+}
+
+// sameConfig compares observation configs in their JSON form, so values
+// decoded from YAML profiles equal those decoded from stored JSON results.
+func sameConfig(a, b map[string]interface{}) bool {
+	return bytes.Equal(configJSON(a), configJSON(b))
+}
+
+func configJSON(config map[string]interface{}) []byte {
+	if len(config) == 0 {
+		return []byte("{}")
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return []byte(fmt.Sprint(config))
+	}
+	return data
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	domainservices "github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEvaluationEngines builds engines that turn the fixtures of each control
+// into its result: error if a fixture reports one or no fixture exists, pass
+// otherwise.
+type fakeEvaluationEngines struct {
+	fixtures []dto.ProfileTestFixture
+	at       time.Time
+}
+
+func (f *fakeEvaluationEngines) CreateEvaluationEngine(fixtures []dto.ProfileTestFixture, _ []string, at time.Time) (ports.ExecutionEngine, error) {
+	f.fixtures = fixtures
+	f.at = at
+	return fakeEvaluationEngine(fixtures), nil
+}
+
+type fakeEvaluationEngine []dto.ProfileTestFixture
+
+func (e fakeEvaluationEngine) Execute(_ context.Context, profile entities.ProfileReader) (*execution.ExecutionResult, error) {
+	result := execution.NewExecutionResult(profile.GetMetadata().Name, profile.GetMetadata().Version)
+	for i, ctrl := range profile.GetAllControls() {
+		cr := execution.ControlResult{ID: ctrl.ID, Index: i, Status: values.StatusError}
+		for _, f := range e {
+			if f.Control != ctrl.ID {
+				continue
+			}
+			status := values.StatusPass
+			if f.Error != nil {
+				status = values.StatusError
+			}
+			cr.Status = status
+			cr.ObservationResults = append(cr.ObservationResults, execution.ObservationResult{Plugin: f.Plugin, Status: status})
+		}
+		result.AddControlResult(cr)
+	}
+	result.Finalize()
+	return result, nil
+}
+
+func (e fakeEvaluationEngine) Close(_ context.Context) error {
+	return nil
+}
+
+func TestEvidenceEvaluationService_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	source := execution.NewExecutionResult("ssh", "1.0.0")
+	source.StartTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	source.Mode = execution.ModeCollect
+	stored := func(path string, data map[string]interface{}) execution.ObservationResult {
+		return execution.ObservationResult{
+			Plugin:   "file",
+			Config:   map[string]interface{}{"path": path, "mode": float64(600)},
+			Status:   values.StatusCollected,
+			Evidence: &execution.Evidence{Status: true, Data: data},
+		}
+	}
+	source.AddControlResult(execution.ControlResult{ID: "config", Status: values.StatusCollected, ObservationResults: []execution.ObservationResult{
+		stored("/etc/ssh/sshd_config", map[string]interface{}{"exists": true}),
+	}})
+	truncated := stored("/etc/ssh/moduli", map[string]interface{}{"content": "[TRUNCATED]"})
+	truncated.EvidenceMeta = &execution.EvidenceMeta{Truncated: true}
+	source.AddControlResult(execution.ControlResult{ID: "moduli", Status: values.StatusCollected, ObservationResults: []execution.ObservationResult{truncated}})
+	source.AddControlResult(execution.ControlResult{ID: "keys", Status: values.StatusCollected, ObservationResults: []execution.ObservationResult{
+		stored("/etc/ssh/ssh_host_rsa_key", map[string]interface{}{"exists": true}),
+	}})
+	source.Finalize()
+	repo := newFakeResultRepository(source)
+
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "ssh", Version: "2.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "config", Name: "config", ObservationDefinitions: []entities.ObservationDefinition{
+				{Plugin: "file", Config: map[string]interface{}{"path": "/etc/ssh/sshd_config", "mode": 600}, Expect: []string{"data.exists"}},
+				{Plugin: "file", Config: map[string]interface{}{"path": "/etc/ssh/ssh_config"}},
+			}},
+			{ID: "moduli", Name: "moduli", ObservationDefinitions: []entities.ObservationDefinition{
+				{Plugin: "file", Config: map[string]interface{}{"path": "/etc/ssh/moduli", "mode": 600}},
+			}},
+			{ID: "keys", Name: "keys", ObservationDefinitions: []entities.ObservationDefinition{
+				{Plugin: "file", Config: map[string]interface{}{"path": "/etc/ssh/ssh_host_ed25519_key", "mode": 600}},
+			}},
+			{ID: "banner", Name: "banner", ObservationDefinitions: []entities.ObservationDefinition{
+				{Plugin: "file", Config: map[string]interface{}{"path": "/etc/issue.net"}},
+			}},
+		}},
+	}
+	loader := profileLoaderFunc(func(string) (*entities.Profile, error) { return profile, nil })
+	engines := &fakeEvaluationEngines{}
+	svc := NewEvidenceEvaluationService(repo, loader, domainservices.NewProfileCompiler(), engines, NewTestLogger())
+
+	result, err := svc.Evaluate(context.Background(), dto.EvaluateEvidenceRequest{
+		RunID:       source.GetID().String(),
+		ProfilePath: "ssh.yaml",
+		Save:        true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, source.StartTime, engines.at, "evaluation runs at the time of the run")
+	require.Len(t, engines.fixtures, 4)
+	assert.Equal(t, map[string]interface{}{"exists": true}, engines.fixtures[0].Data, "numbers from YAML and JSON configs compare equal")
+	assert.Equal(t, "no_evidence", engines.fixtures[1].Error.Code)
+	assert.Equal(t, "evidence_truncated", engines.fixtures[2].Error.Code)
+	assert.Equal(t, "evidence_mismatch", engines.fixtures[3].Error.Code)
+	assert.Contains(t, engines.fixtures[3].Error.Message, `"path":"/etc/ssh/ssh_host_rsa_key"`)
+	assert.Equal(t, "keys", engines.fixtures[3].Control)
+	assert.Equal(t, 0, *engines.fixtures[3].Observation)
+
+	banner := result.GetControlResultByID("banner")
+	assert.Equal(t, values.StatusSkipped, banner.Status)
+	assert.Equal(t, "not observed in run "+source.GetID().String(), banner.SkipReason)
+	assert.Equal(t, 1, result.Summary.SkippedControls)
+	assert.Equal(t, execution.ModeEvaluate, result.Mode)
+	assert.Equal(t, source.GetID().String(), result.EvaluatedRun)
+	assert.Equal(t, 1, repo.saves)
+
+	_, err = svc.Evaluate(context.Background(), dto.EvaluateEvidenceRequest{RunID: "not-a-run", ProfilePath: "ssh.yaml"})
+	assert.ErrorContains(t, err, `run "not-a-run"`)
+}
@@ -46,6 +46,8 @@ type MockRepository struct {
 
 	ListPlugins []*entities.Plugin
 	ListErr     error
+
+	Deleted []values.PluginReference
 }
 
 func (m *MockRepository) Find(ctx context.Context, ref values.PluginReference) (*entities.Plugin, string, error) {
@@ -66,11 +68,8 @@ func (m *MockRepository) List(ctx context.Context) ([]*entities.Plugin, error) {
 	return m.ListPlugins, m.ListErr
 }
 
-func (m *MockRepository) Prune(ctx context.Context, keep int) error {
-	return nil
-}
-
 func (m *MockRepository) Delete(ctx context.Context, ref values.PluginReference) error {
+	m.Deleted = append(m.Deleted, ref)
 	return nil
 }
 
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// OfflinePluginResolver ends the resolver chain in offline mode, in place of
+// the registry: plugins that are neither embedded nor cached are not pulled.
+type OfflinePluginResolver struct {
+	services.BaseResolver
+}
+
+// NewOfflinePluginResolver creates an offline plugin resolver.
+func NewOfflinePluginResolver() *OfflinePluginResolver {
+	return &OfflinePluginResolver{}
+}
+
+// Resolve always fails, naming the plugin to pull before going offline.
+func (r *OfflinePluginResolver) Resolve(_ context.Context, ref values.PluginReference) (*entities.Plugin, error) {
+	return nil, fmt.Errorf("plugin %s is not cached and offline mode forbids pulling it: run \"reglet plugins pull %s\" while online", ref.String(), ref.String())
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	apperrors "github.com/reglet-dev/reglet/internal/application/errors"
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckOffline(t *testing.T) {
+	t.Parallel()
+
+	controls := []entities.Control{
+		{ID: "config", ObservationDefinitions: []entities.ObservationDefinition{{Plugin: "file"}}},
+		{ID: "tls", ObservationDefinitions: []entities.ObservationDefinition{{Plugin: "file"}, {Plugin: "http"}, {Plugin: "http"}}},
+		{ID: "dns", Tags: []string{"dns"}, ObservationDefinitions: []entities.ObservationDefinition{{Plugin: "dns"}}},
+	}
+	required := map[string][]capabilities.Capability{
+		"file": {{Kind: "fs", Pattern: "read:/etc/**"}},
+		"http": {{Kind: "network", Pattern: "outbound:443"}},
+		"dns":  {{Kind: "network", Pattern: "outbound:53"}},
+	}
+
+	err := checkOffline(controls, dto.FilterOptions{}, required)
+	var offlineErr *apperrors.OfflineError
+	require.True(t, errors.As(err, &offlineErr))
+	assert.Equal(t, []apperrors.OfflineBlocker{
+		{ControlID: "tls", Plugins: []string{"http"}},
+		{ControlID: "dns", Plugins: []string{"dns"}},
+	}, offlineErr.Controls)
+	assert.Contains(t, err.Error(), "  - tls (http)")
+
+	err = checkOffline(controls, dto.FilterOptions{ExcludeTags: []string{"dns"}, ExcludeControlIDs: []string{"tls"}}, required)
+	assert.NoError(t, err, "controls filtered out do not block the run")
+}
+
+func TestWithoutNetwork(t *testing.T) {
+	t.Parallel()
+
+	required := map[string][]capabilities.Capability{
+		"http": {{Kind: "network", Pattern: "outbound:443"}, {Kind: "env", Pattern: "HTTPS_PROXY"}},
+	}
+	filtered := withoutNetwork(required)
+
+	assert.Equal(t, []capabilities.Capability{{Kind: "env", Pattern: "HTTPS_PROXY"}}, filtered["http"])
+	assert.Len(t, required["http"], 2, "the input is not modified")
+}
+
+func TestOfflinePluginResolver(t *testing.T) {
+	t.Parallel()
+
+	ref, err := values.ParsePluginReference("ghcr.io/reglet-dev/plugins/http:1.0.0")
+	require.NoError(t, err)
+
+	_, err = NewOfflinePluginResolver().Resolve(context.Background(), ref)
+	assert.ErrorContains(t, err, "offline mode forbids pulling it")
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// Sources of the plugins a health check covers.
+const (
+	PluginSourceDir   = "plugin-dir"
+	PluginSourceCache = "cache"
+)
+
+// PluginDoctorService checks the health of the installed plugins, so that
+// corrupt or stale binaries are found before a scheduled run needs them.
+type PluginDoctorService struct {
+	repository ports.PluginRepository
+	inspector  ports.PluginInspector
+	verifier   ports.IntegrityVerifier
+}
+
+// NewPluginDoctorService creates a plugin health check service.
+func NewPluginDoctorService(
+	repository ports.PluginRepository,
+	inspector ports.PluginInspector,
+	verifier ports.IntegrityVerifier,
+) *PluginDoctorService {
+	return &PluginDoctorService{
+		repository: repository,
+		inspector:  inspector,
+		verifier:   verifier,
+	}
+}
+
+// Diagnose checks the WASM plugins of req.PluginDir, then the plugins of the
+// local cache. Every plugin is loaded and inspected; cached plugins are also
+// checked against the digest recorded when they were pulled and, with
+// req.VerifySignatures, against their registry signature.
+func (s *PluginDoctorService) Diagnose(ctx context.Context, req dto.PluginDoctorRequest) (*dto.PluginDoctorReport, error) {
+	selected := func(name string) bool {
+		return len(req.Names) == 0 || slices.Contains(req.Names, name)
+	}
+
+	report := &dto.PluginDoctorReport{}
+	if req.PluginDir != "" {
+		entries, err := os.ReadDir(req.PluginDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plugin directory: %w", err)
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			path := filepath.Join(req.PluginDir, name, name+".wasm")
+			if !entry.IsDir() || !selected(name) {
+				continue
+			}
+			if _, err := os.Stat(path); err != nil {
+				continue // process plugins and versioned releases only
+			}
+			health := dto.PluginHealth{Name: name, Source: PluginSourceDir, Path: path}
+			s.diagnose(ctx, &health, nil, req.VerifySignatures)
+			report.Plugins = append(report.Plugins, health)
+		}
+	}
+
+	cached, err := s.repository.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached plugins: %w", err)
+	}
+	sort.Slice(cached, func(i, j int) bool {
+		return cached[i].Reference().String() < cached[j].Reference().String()
+	})
+	for _, plugin := range cached {
+		if !selected(plugin.Reference().Name()) {
+			continue
+		}
+		_, path, err := s.repository.Find(ctx, plugin.Reference())
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate %s: %w", plugin.Reference(), err)
+		}
+		health := dto.PluginHealth{Name: plugin.Reference().String(), Source: PluginSourceCache, Path: path}
+		s.diagnose(ctx, &health, plugin, req.VerifySignatures)
+		report.Plugins = append(report.Plugins, health)
+	}
+	return report, nil
+}
+
+// diagnose runs the checks of the plugin binary at health.Path. cached is
+// the cache entry the binary belongs to, nil for plugin directory binaries.
+func (s *PluginDoctorService) diagnose(ctx context.Context, health *dto.PluginHealth, cached *entities.Plugin, verifySignatures bool) {
+	wasmBytes, err := os.ReadFile(health.Path)
+	if err != nil {
+		health.Checks = []dto.PluginCheck{{Name: dto.PluginCheckLoad, Status: dto.PluginCheckFailed, Message: err.Error()}}
+		return
+	}
+	health.Version, health.Checks = s.inspector.InspectPlugin(ctx, health.Name, wasmBytes)
+
+	if cached == nil {
+		health.Checks = append(health.Checks,
+			dto.PluginCheck{Name: dto.PluginCheckDigest, Status: dto.PluginCheckSkipped, Message: "no digest recorded outside the cache"},
+			dto.PluginCheck{Name: dto.PluginCheckSignature, Status: dto.PluginCheckSkipped, Message: "not pulled from a registry"},
+		)
+		return
+	}
+
+	digest := dto.PluginCheck{Name: dto.PluginCheckDigest, Status: dto.PluginCheckOK}
+	if err := cached.Digest().Verify(wasmBytes); err != nil {
+		digest.Status = dto.PluginCheckFailed
+		digest.Message = fmt.Sprintf("binary does not match the pulled digest %s; pull the plugin again", cached.Digest())
+	}
+	health.Checks = append(health.Checks, digest, s.checkSignature(ctx, cached, verifySignatures))
+}
+
+// checkSignature verifies the registry signature of a cached plugin when asked to.
+func (s *PluginDoctorService) checkSignature(ctx context.Context, cached *entities.Plugin, verify bool) dto.PluginCheck {
+	check := dto.PluginCheck{Name: dto.PluginCheckSignature}
+	switch {
+	case !verify:
+		check.Status = dto.PluginCheckSkipped
+		check.Message = "not requested"
+	case cached.Reference().IsEmbedded():
+		check.Status = dto.PluginCheckSkipped
+		check.Message = "built-in plugin"
+	default:
+		result, err := s.verifier.VerifySignature(ctx, cached.Reference())
+		switch {
+		case err != nil:
+			check.Status = dto.PluginCheckFailed
+			check.Message = err.Error()
+		case !result.Verified:
+			check.Status = dto.PluginCheckFailed
+			check.Message = "signature not verified"
+		default:
+			check.Status = dto.PluginCheckOK
+			check.Message = "signed by " + result.Signer
+		}
+	}
+	return check
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInspector passes every binary except those named in broken.
+type fakeInspector struct {
+	broken map[string]string
+}
+
+func (f *fakeInspector) InspectPlugin(_ context.Context, name string, _ []byte) (string, []dto.PluginCheck) {
+	if reason, ok := f.broken[name]; ok {
+		return "", []dto.PluginCheck{{Name: dto.PluginCheckLoad, Status: dto.PluginCheckFailed, Message: reason}}
+	}
+	return "1.0.0", []dto.PluginCheck{{Name: dto.PluginCheckLoad, Status: dto.PluginCheckOK}}
+}
+
+// checkStatuses maps check names to outcomes.
+func checkStatuses(health dto.PluginHealth) map[string]string {
+	statuses := make(map[string]string, len(health.Checks))
+	for _, check := range health.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestPluginDoctorService_Diagnose(t *testing.T) {
+	ctx := context.Background()
+
+	pluginDir := t.TempDir()
+	for _, name := range []string{"custom", "corrupt"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, name), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(pluginDir, name, name+".wasm"), []byte("wasm"), 0o600))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "empty"), 0o750))
+
+	cache := newDirRepository(t)
+	cache.add(t, "file", "1.0.0")
+	tampered := cache.add(t, "http", "1.0.0")
+	_, path, _ := cache.Find(ctx, tampered.Reference())
+	require.NoError(t, os.WriteFile(path, []byte("tampered"), 0o600))
+
+	inspector := &fakeInspector{broken: map[string]string{"corrupt": "invalid magic number"}}
+	svc := NewPluginDoctorService(cache, inspector, &MockVerifier{VerifyResult: &ports.SignatureResult{Verified: true, Signer: "ci"}})
+
+	report, err := svc.Diagnose(ctx, dto.PluginDoctorRequest{PluginDir: pluginDir, VerifySignatures: true})
+	require.NoError(t, err)
+	require.Len(t, report.Plugins, 4)
+
+	corrupt, custom, file, http := report.Plugins[0], report.Plugins[1], report.Plugins[2], report.Plugins[3]
+	assert.Equal(t, "corrupt", corrupt.Name)
+	assert.False(t, corrupt.Healthy())
+	assert.Equal(t, dto.PluginCheckFailed, checkStatuses(corrupt)[dto.PluginCheckLoad])
+
+	assert.Equal(t, "custom", custom.Name)
+	assert.Equal(t, PluginSourceDir, custom.Source)
+	assert.Equal(t, "1.0.0", custom.Version)
+	assert.True(t, custom.Healthy())
+	assert.Equal(t, dto.PluginCheckSkipped, checkStatuses(custom)[dto.PluginCheckDigest])
+
+	assert.Equal(t, "ghcr.io/reglet-dev/reglet-plugins/file:1.0.0", file.Name)
+	assert.Equal(t, PluginSourceCache, file.Source)
+	assert.True(t, file.Healthy())
+	assert.Equal(t, dto.PluginCheckOK, checkStatuses(file)[dto.PluginCheckDigest])
+	assert.Equal(t, dto.PluginCheckOK, checkStatuses(file)[dto.PluginCheckSignature])
+
+	assert.False(t, http.Healthy())
+	assert.Equal(t, dto.PluginCheckFailed, checkStatuses(http)[dto.PluginCheckDigest])
+	assert.Equal(t, 2, report.UnhealthyCount())
+}
+
+func TestPluginDoctorService_Diagnose_Selection(t *testing.T) {
+	ctx := context.Background()
+	cache := newDirRepository(t)
+	cache.add(t, "file", "1.0.0")
+	cache.add(t, "http", "1.0.0")
+
+	svc := NewPluginDoctorService(cache, &fakeInspector{}, &MockVerifier{VerifyErr: assert.AnError})
+
+	report, err := svc.Diagnose(ctx, dto.PluginDoctorRequest{Names: []string{"http"}})
+	require.NoError(t, err)
+	require.Len(t, report.Plugins, 1)
+	assert.Equal(t, "ghcr.io/reglet-dev/reglet-plugins/http:1.0.0", report.Plugins[0].Name)
+	assert.Equal(t, dto.PluginCheckSkipped, checkStatuses(report.Plugins[0])[dto.PluginCheckSignature], "signatures are only verified on request")
+
+	report, err = svc.Diagnose(ctx, dto.PluginDoctorRequest{Names: []string{"http"}, VerifySignatures: true})
+	require.NoError(t, err)
+	assert.Equal(t, dto.PluginCheckFailed, checkStatuses(report.Plugins[0])[dto.PluginCheckSignature])
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// PluginInstallService installs plugins from a plugin index into the plugin
+// directory: each release is pulled from its OCI registry or downloaded from
+// its HTTPS URL, and installed only if it matches the digest the index
+// lists.
+type PluginInstallService struct {
+	index      ports.PluginIndexSource
+	registry   ports.PluginRegistry
+	downloader ports.PluginDownloader
+	store      ports.PluginInstallStore
+	verifier   ports.IntegrityVerifier
+	integrity  *services.IntegrityService
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// NewPluginInstallService creates a plugin install service.
+func NewPluginInstallService(
+	index ports.PluginIndexSource,
+	registry ports.PluginRegistry,
+	downloader ports.PluginDownloader,
+	store ports.PluginInstallStore,
+	verifier ports.IntegrityVerifier,
+	integrity *services.IntegrityService,
+	logger *slog.Logger,
+) *PluginInstallService {
+	return &PluginInstallService{
+		index:      index,
+		registry:   registry,
+		downloader: downloader,
+		store:      store,
+		verifier:   verifier,
+		integrity:  integrity,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Install installs the highest release of the plugin index a declaration
+// such as "reglet/http@1.2" selects. With verifySignature, or when the
+// integrity policy requires it, releases pulled from OCI registries must
+// carry a valid signature.
+func (s *PluginInstallService) Install(ctx context.Context, declaration string, verifySignature bool) (dto.InstalledPlugin, error) {
+	spec, err := entities.ParsePluginDeclaration(declaration)
+	if err != nil {
+		return dto.InstalledPlugin{}, fmt.Errorf("invalid plugin %q: %w", declaration, err)
+	}
+	index, err := s.index.FetchIndex(ctx)
+	if err != nil {
+		return dto.InstalledPlugin{}, err
+	}
+	name, version, release, err := index.Resolve(spec)
+	if err != nil {
+		return dto.InstalledPlugin{}, err
+	}
+	return s.install(ctx, name, version, release, verifySignature)
+}
+
+// install downloads a release, checks its digest and installs it.
+func (s *PluginInstallService) install(ctx context.Context, name, version string, release entities.PluginRelease, verifySignature bool) (dto.InstalledPlugin, error) {
+	verifySignature = verifySignature || s.integrity.ShouldVerifySignature()
+	wasm, err := s.download(ctx, release, verifySignature)
+	if err != nil {
+		return dto.InstalledPlugin{}, fmt.Errorf("failed to download %s %s: %w", name, version, err)
+	}
+
+	digest, err := values.ParseDigest(release.Digest)
+	if err != nil {
+		return dto.InstalledPlugin{}, fmt.Errorf("%s %s: %w", name, version, err)
+	}
+	if err := digest.Verify(wasm); err != nil {
+		return dto.InstalledPlugin{}, fmt.Errorf("%s %s does not match the plugin index: %w", name, version, err)
+	}
+
+	plugin := dto.InstalledPlugin{
+		InstalledAt: s.now().UTC(),
+		Name:        name,
+		Version:     version,
+		Source:      release.Source,
+		Digest:      release.Digest,
+
+		SignatureVerified: verifySignature,
+	}
+	plugin.Path, err = s.store.Install(ctx, plugin, wasm)
+	if err != nil {
+		return dto.InstalledPlugin{}, fmt.Errorf("failed to install %s %s: %w", name, version, err)
+	}
+	s.logger.Info("plugin installed", "plugin", name, "version", version, "path", plugin.Path)
+	return plugin, nil
+}
+
+// download fetches the binary of a release.
+func (s *PluginInstallService) download(ctx context.Context, release entities.PluginRelease, verifySignature bool) ([]byte, error) {
+	if release.IsURL() {
+		if verifySignature {
+			return nil, fmt.Errorf("signatures can only be verified for releases pulled from OCI registries, not %s", release.Source)
+		}
+		return s.downloader.Download(ctx, release.Source)
+	}
+
+	ref, err := values.ParsePluginReference(release.Source)
+	if err != nil {
+		return nil, err
+	}
+	if verifySignature {
+		result, err := s.verifier.VerifySignature(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("signature verification failed: %w", err)
+		}
+		s.logger.Info("plugin signature verified", "plugin", ref.String(), "signer", result.Signer)
+	}
+	artifact, err := s.registry.Pull(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = artifact.Close() }()
+	return io.ReadAll(artifact.WASM)
+}
+
+// List returns the installed plugins.
+func (s *PluginInstallService) List(ctx context.Context) ([]dto.InstalledPlugin, error) {
+	return s.store.List(ctx)
+}
+
+// Remove removes the installed releases of a plugin: the version a
+// declaration such as "reglet/http@1.2.0" names exactly, or all of them.
+func (s *PluginInstallService) Remove(ctx context.Context, declaration string) ([]dto.InstalledPlugin, error) {
+	spec, err := entities.ParsePluginDeclaration(declaration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin %q: %w", declaration, err)
+	}
+	if spec.Namespace == "" {
+		return nil, fmt.Errorf("name the namespace of plugin %q, e.g. reglet/%s", declaration, spec.PluginName())
+	}
+	removed, err := s.store.Remove(ctx, spec.Namespace+"/"+spec.PluginName(), spec.Version)
+	if err != nil {
+		return nil, err
+	}
+	if len(removed) == 0 {
+		return nil, fmt.Errorf("plugin %q is not installed", declaration)
+	}
+	return removed, nil
+}
+
+// Update installs the highest release the plugin index offers of each
+// installed plugin, or of the named ones, that is newer than its highest
+// installed release. Older releases stay installed for the profiles pinning
+// them.
+func (s *PluginInstallService) Update(ctx context.Context, names []string, verifySignature bool) ([]dto.PluginUpdate, error) {
+	installed, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	highest := make(map[string]*semver.Version)
+	for _, plugin := range installed {
+		v, err := semver.NewVersion(plugin.Version)
+		if err != nil {
+			continue
+		}
+		if current, ok := highest[plugin.Name]; !ok || v.GreaterThan(current) {
+			highest[plugin.Name] = v
+		}
+	}
+	if len(names) == 0 {
+		for name := range highest {
+			names = append(names, name)
+		}
+	}
+
+	index, err := s.index.FetchIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var updates []dto.PluginUpdate
+	for _, name := range sortedUnique(names) {
+		current, ok := highest[name]
+		if !ok {
+			return nil, fmt.Errorf("plugin %q is not installed", name)
+		}
+		spec, err := entities.ParsePluginDeclaration(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plugin %q: %w", name, err)
+		}
+		_, version, release, err := index.Resolve(spec)
+		if err != nil {
+			return nil, err
+		}
+		if latest, err := semver.NewVersion(version); err != nil || !latest.GreaterThan(current) {
+			continue
+		}
+		if _, err := s.install(ctx, name, version, release, verifySignature); err != nil {
+			return updates, err
+		}
+		updates = append(updates, dto.PluginUpdate{Name: name, From: current.Original(), To: version})
+	}
+	return updates, nil
+}
+
+// sortedUnique returns names sorted, without duplicates.
+func sortedUnique(names []string) []string {
+	unique := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
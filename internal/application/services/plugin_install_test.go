@@ -0,0 +1,191 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticIndex is a ports.PluginIndexSource serving a fixed index.
+type staticIndex struct {
+	index *entities.PluginIndex
+}
+
+func (s staticIndex) FetchIndex(context.Context) (*entities.PluginIndex, error) {
+	return s.index, nil
+}
+
+// mapDownloader is a ports.PluginDownloader serving content by URL.
+type mapDownloader map[string][]byte
+
+func (d mapDownloader) Download(_ context.Context, url string) ([]byte, error) {
+	data, ok := d[url]
+	if !ok {
+		return nil, errors.New("404 Not Found")
+	}
+	return data, nil
+}
+
+// memInstallStore is a ports.PluginInstallStore keeping releases in memory.
+type memInstallStore struct {
+	plugins []dto.InstalledPlugin
+	wasm    map[string][]byte
+}
+
+func (s *memInstallStore) Install(_ context.Context, plugin dto.InstalledPlugin, wasm []byte) (string, error) {
+	if s.wasm == nil {
+		s.wasm = make(map[string][]byte)
+	}
+	plugin.Path = plugin.Name + "/" + plugin.Version
+	s.plugins = append(s.plugins, plugin)
+	s.wasm[plugin.Path] = wasm
+	return plugin.Path, nil
+}
+
+func (s *memInstallStore) List(context.Context) ([]dto.InstalledPlugin, error) {
+	return s.plugins, nil
+}
+
+func (s *memInstallStore) Remove(_ context.Context, name, version string) ([]dto.InstalledPlugin, error) {
+	var kept, removed []dto.InstalledPlugin
+	for _, plugin := range s.plugins {
+		if plugin.Name == name && (version == "" || plugin.Version == version) {
+			removed = append(removed, plugin)
+		} else {
+			kept = append(kept, plugin)
+		}
+	}
+	s.plugins = kept
+	return removed, nil
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// newInstallFixture returns an install service on an index offering
+// reglet/http 1.1.0 and 1.2.0 by URL and reglet/dns 1.0.0 by OCI reference.
+func newInstallFixture(verifier *MockVerifier) (*PluginInstallService, *memInstallStore, mapDownloader) {
+	downloads := mapDownloader{
+		"https://plugins.example.com/http-1.1.0.wasm": []byte("http 1.1.0"),
+		"https://plugins.example.com/http-1.2.0.wasm": []byte("http 1.2.0"),
+	}
+	dnsWASM := []byte("dns 1.0.0")
+	index := &entities.PluginIndex{Plugins: map[string]entities.IndexedPlugin{
+		"reglet/http": {Releases: map[string]entities.PluginRelease{
+			"1.1.0": {Source: "https://plugins.example.com/http-1.1.0.wasm", Digest: sha256Digest([]byte("http 1.1.0"))},
+			"1.2.0": {Source: "https://plugins.example.com/http-1.2.0.wasm", Digest: sha256Digest([]byte("http 1.2.0"))},
+		}},
+		"reglet/dns": {Releases: map[string]entities.PluginRelease{
+			"1.0.0": {Source: "ghcr.io/reglet-dev/plugins/dns:1.0.0", Digest: sha256Digest(dnsWASM)},
+		}},
+	}}
+	registry := &MockRegistry{PullArtifact: dto.NewPluginArtifactDTO(nil, io.NopCloser(bytes.NewReader(dnsWASM)))}
+	store := &memInstallStore{}
+	service := NewPluginInstallService(staticIndex{index}, registry, downloads, store, verifier,
+		services.NewIntegrityService(false), NewTestLogger())
+	return service, store, downloads
+}
+
+func TestPluginInstallService_Install(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	service, store, _ := newInstallFixture(&MockVerifier{})
+	plugin, err := service.Install(ctx, "reglet/http@1.1", false)
+	require.NoError(t, err)
+	assert.Equal(t, "reglet/http", plugin.Name)
+	assert.Equal(t, "1.1.0", plugin.Version)
+	assert.Equal(t, "https://plugins.example.com/http-1.1.0.wasm", plugin.Source)
+	assert.Equal(t, []byte("http 1.1.0"), store.wasm[plugin.Path])
+	assert.False(t, plugin.InstalledAt.IsZero())
+
+	plugin, err = service.Install(ctx, "dns", true)
+	require.NoError(t, err, "releases pulled from OCI registries can be signature-checked")
+	assert.Equal(t, []byte("dns 1.0.0"), store.wasm[plugin.Path])
+}
+
+func TestPluginInstallService_Install_Rejected(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	service, store, downloads := newInstallFixture(&MockVerifier{})
+	downloads["https://plugins.example.com/http-1.2.0.wasm"] = []byte("tampered")
+	_, err := service.Install(ctx, "reglet/http", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match the plugin index")
+
+	_, err = service.Install(ctx, "reglet/http@1.1.0", true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only be verified for releases pulled from OCI registries")
+
+	service, store, _ = newInstallFixture(&MockVerifier{VerifyErr: errors.New("no signature")})
+	_, err = service.Install(ctx, "reglet/dns", true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "signature verification failed")
+	assert.Empty(t, store.plugins)
+}
+
+func TestPluginInstallService_Update(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	service, store, _ := newInstallFixture(&MockVerifier{})
+	_, err := service.Install(ctx, "reglet/http@1.1.0", false)
+	require.NoError(t, err)
+	_, err = service.Install(ctx, "reglet/dns", false)
+	require.NoError(t, err)
+
+	updates, err := service.Update(ctx, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, []dto.PluginUpdate{{Name: "reglet/http", From: "1.1.0", To: "1.2.0"}}, updates)
+	assert.Len(t, store.plugins, 3, "the previous release stays installed")
+
+	updates, err = service.Update(ctx, []string{"reglet/http"}, false)
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+
+	_, err = service.Update(ctx, []string{"reglet/file"}, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not installed")
+}
+
+func TestPluginInstallService_Remove(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	service, store, _ := newInstallFixture(&MockVerifier{})
+	for _, declaration := range []string{"reglet/http@1.1.0", "reglet/http@1.2.0", "reglet/dns"} {
+		_, err := service.Install(ctx, declaration, false)
+		require.NoError(t, err)
+	}
+
+	removed, err := service.Remove(ctx, "reglet/http@1.1.0")
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "1.1.0", removed[0].Version)
+
+	removed, err = service.Remove(ctx, "reglet/http")
+	require.NoError(t, err)
+	assert.Len(t, removed, 1)
+	assert.Len(t, store.plugins, 1)
+
+	_, err = service.Remove(ctx, "reglet/http")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not installed")
+
+	_, err = service.Remove(ctx, "dns")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "name the namespace")
+}
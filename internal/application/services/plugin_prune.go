@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// PluginPruneService removes cached plugin versions a workspace no longer
+// uses, and ages out compiled plugin code.
+type PluginPruneService struct {
+	repository ports.PluginRepository
+	scanner    ports.PluginReferenceScanner
+	cache      ports.CompilationCache
+	now        func() time.Time
+}
+
+// NewPluginPruneService creates a prune service. cache may be nil when
+// compiled code is not cached on disk.
+func NewPluginPruneService(
+	repository ports.PluginRepository,
+	scanner ports.PluginReferenceScanner,
+	cache ports.CompilationCache,
+) *PluginPruneService {
+	return &PluginPruneService{
+		repository: repository,
+		scanner:    scanner,
+		cache:      cache,
+		now:        time.Now,
+	}
+}
+
+// Prune removes the cached plugin versions that no profile or lockfile in
+// the workspace references, except for the newest req.Keep versions of each
+// plugin, and the compilation cache entries older than req.CacheMaxAge.
+func (s *PluginPruneService) Prune(ctx context.Context, req dto.PrunePluginsRequest) (*dto.PrunePluginsReport, error) {
+	var references []*entities.PluginSpec
+	if req.Workspace != "" {
+		var err error
+		references, err = s.scanner.ScanPluginReferences(ctx, req.Workspace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workspace %s: %w", req.Workspace, err)
+		}
+	}
+
+	cached, err := s.repository.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached plugins: %w", err)
+	}
+
+	report := &dto.PrunePluginsReport{DryRun: req.DryRun}
+	prunable := selectPrunablePlugins(cached, references, req.Keep)
+	for _, plugin := range prunable {
+		if !req.DryRun {
+			if err := s.repository.Delete(ctx, plugin.Reference()); err != nil {
+				return nil, fmt.Errorf("failed to remove %s: %w", plugin.Reference(), err)
+			}
+		}
+		report.Removed = append(report.Removed, plugin.Reference().String())
+	}
+	report.Kept = len(cached) - len(prunable)
+
+	if req.CacheMaxAge > 0 && s.cache != nil {
+		report.CacheEntries, err = s.cache.Prune(ctx, s.now().Add(-req.CacheMaxAge), req.DryRun)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prune compilation cache: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// selectPrunablePlugins returns the cached plugins to remove: per plugin
+// repository, the versions not referenced by any of references, except for
+// the newest keep of them. A reference keeps the highest cached version it
+// accepts, or the version with its digest.
+func selectPrunablePlugins(cached []*entities.Plugin, references []*entities.PluginSpec, keep int) []*entities.Plugin {
+	repositories := make(map[string][]*entities.Plugin)
+	for _, plugin := range cached {
+		if plugin.Reference().IsEmbedded() {
+			continue
+		}
+		repo := pluginRepository(plugin.Reference().String())
+		repositories[repo] = append(repositories[repo], plugin)
+	}
+
+	var prunable []*entities.Plugin
+	for repo, plugins := range repositories {
+		referenced := referencedPlugins(repo, plugins, references)
+
+		var unreferenced []*entities.Plugin
+		for _, plugin := range plugins {
+			if !referenced[plugin] {
+				unreferenced = append(unreferenced, plugin)
+			}
+		}
+		sort.Slice(unreferenced, func(i, j int) bool {
+			return newerVersion(unreferenced[i].Reference().Version(), unreferenced[j].Reference().Version())
+		})
+		if len(unreferenced) > keep {
+			prunable = append(prunable, unreferenced[keep:]...)
+		}
+	}
+
+	sort.Slice(prunable, func(i, j int) bool {
+		return prunable[i].Reference().String() < prunable[j].Reference().String()
+	})
+	return prunable
+}
+
+// referencedPlugins returns the versions of one plugin repository that
+// references keep.
+func referencedPlugins(repo string, plugins []*entities.Plugin, references []*entities.PluginSpec) map[*entities.Plugin]bool {
+	name := plugins[0].Reference().Name()
+	versions := make([]string, 0, len(plugins))
+	for _, plugin := range plugins {
+		versions = append(versions, plugin.Reference().Version())
+	}
+
+	referenced := make(map[*entities.Plugin]bool)
+	for _, spec := range references {
+		if spec.PluginName() != name {
+			continue
+		}
+		// Registry references name the repository; short names match any
+		if specRepo := pluginRepository(spec.Source); strings.Count(specRepo, "/") >= 3 && specRepo != repo {
+			continue
+		}
+
+		if spec.Digest != "" {
+			for _, plugin := range plugins {
+				if plugin.Digest().String() == spec.Digest {
+					referenced[plugin] = true
+				}
+			}
+			continue
+		}
+		version, ok := spec.SelectVersion(versions)
+		if !ok {
+			continue
+		}
+		for _, plugin := range plugins {
+			if plugin.Reference().Version() == version {
+				referenced[plugin] = true
+			}
+		}
+	}
+	return referenced
+}
+
+// pluginRepository strips the version, tag or digest from a plugin source
+// (e.g., "ghcr.io/org/repo/file:1.0.0" -> "ghcr.io/org/repo/file").
+func pluginRepository(source string) string {
+	if idx := strings.Index(source, "@"); idx != -1 {
+		source = source[:idx]
+	}
+	if idx := strings.LastIndex(source, ":"); idx > strings.LastIndex(source, "/") {
+		source = source[:idx]
+	}
+	return source
+}
+
+// newerVersion orders plugin versions newest first. Versions that are not
+// semantic versions sort after the others.
+func newerVersion(a, b string) bool {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	switch {
+	case errA == nil && errB == nil:
+		return va.GreaterThan(vb)
+	case errA == nil || errB == nil:
+		return errA == nil
+	default:
+		return a > b
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReferenceScanner struct {
+	specs []*entities.PluginSpec
+}
+
+func (f *fakeReferenceScanner) ScanPluginReferences(_ context.Context, _ string) ([]*entities.PluginSpec, error) {
+	return f.specs, nil
+}
+
+type fakeCompilationCache struct {
+	cutoff time.Time
+	dryRun bool
+}
+
+func (f *fakeCompilationCache) Prune(_ context.Context, cutoff time.Time, dryRun bool) ([]string, error) {
+	f.cutoff, f.dryRun = cutoff, dryRun
+	return []string{"wazero-v1.11.0/abc"}, nil
+}
+
+func cachedPlugin(name, version string) *entities.Plugin {
+	ref := values.NewPluginReference("ghcr.io", "reglet-dev", "reglet-plugins", name, version)
+	digest, _ := values.NewDigest("sha256", name+version)
+	return entities.NewPlugin(ref, digest, values.NewPluginMetadata(name, version, "", nil))
+}
+
+func TestPluginPruneService_Prune(t *testing.T) {
+	cached := []*entities.Plugin{
+		cachedPlugin("file", "1.0.0"),
+		cachedPlugin("file", "1.1.0"),
+		cachedPlugin("file", "2.0.0"),
+		cachedPlugin("http", "1.0.0"),
+		cachedPlugin("http", "1.2.0"),
+	}
+	references := []*entities.PluginSpec{
+		{Name: "file", Source: "file", Version: "1"},
+	}
+
+	t.Run("removes unreferenced versions", func(t *testing.T) {
+		repo := &MockRepository{ListPlugins: cached}
+		svc := NewPluginPruneService(repo, &fakeReferenceScanner{specs: references}, nil)
+
+		report, err := svc.Prune(context.Background(), dto.PrunePluginsRequest{Workspace: "."})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"ghcr.io/reglet-dev/reglet-plugins/file:1.0.0",
+			"ghcr.io/reglet-dev/reglet-plugins/file:2.0.0",
+			"ghcr.io/reglet-dev/reglet-plugins/http:1.0.0",
+			"ghcr.io/reglet-dev/reglet-plugins/http:1.2.0",
+		}, report.Removed)
+		assert.Equal(t, 1, report.Kept)
+		assert.Len(t, repo.Deleted, 4)
+	})
+
+	t.Run("keeps newest unreferenced versions", func(t *testing.T) {
+		repo := &MockRepository{ListPlugins: cached}
+		svc := NewPluginPruneService(repo, &fakeReferenceScanner{specs: references}, nil)
+
+		report, err := svc.Prune(context.Background(), dto.PrunePluginsRequest{Workspace: ".", Keep: 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"ghcr.io/reglet-dev/reglet-plugins/file:1.0.0",
+			"ghcr.io/reglet-dev/reglet-plugins/http:1.0.0",
+		}, report.Removed)
+		assert.Equal(t, 3, report.Kept)
+	})
+
+	t.Run("dry run removes nothing", func(t *testing.T) {
+		repo := &MockRepository{ListPlugins: cached}
+		cache := &fakeCompilationCache{}
+		svc := NewPluginPruneService(repo, &fakeReferenceScanner{specs: references}, cache)
+		now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+		svc.now = func() time.Time { return now }
+
+		report, err := svc.Prune(context.Background(), dto.PrunePluginsRequest{
+			Workspace:   ".",
+			CacheMaxAge: 24 * time.Hour,
+			DryRun:      true,
+		})
+		require.NoError(t, err)
+
+		assert.True(t, report.DryRun)
+		assert.Len(t, report.Removed, 4)
+		assert.Empty(t, repo.Deleted)
+		assert.Equal(t, []string{"wazero-v1.11.0/abc"}, report.CacheEntries)
+		assert.Equal(t, now.Add(-24*time.Hour), cache.cutoff)
+		assert.True(t, cache.dryRun)
+	})
+}
+
+func TestSelectPrunablePlugins_Digest(t *testing.T) {
+	cached := []*entities.Plugin{cachedPlugin("file", "1.0.0"), cachedPlugin("file", "1.1.0")}
+	references := []*entities.PluginSpec{{
+		Name:   "file",
+		Source: "ghcr.io/reglet-dev/reglet-plugins/file@" + cached[0].Digest().String(),
+		Digest: cached[0].Digest().String(),
+	}}
+
+	prunable := selectPrunablePlugins(cached, references, 0)
+
+	require.Len(t, prunable, 1)
+	assert.Equal(t, "1.1.0", prunable[0].Reference().Version())
+}
@@ -126,7 +126,17 @@ func (s *PluginService) ListCachedPlugins(ctx context.Context) ([]*entities.Plug
 	return s.repository.List(ctx)
 }
 
-// PruneCache removes old plugin versions.
+// PruneCache removes old plugin versions, keeping the newest keepVersions
+// versions of each plugin.
 func (s *PluginService) PruneCache(ctx context.Context, keepVersions int) error {
-	return s.repository.Prune(ctx, keepVersions)
+	plugins, err := s.repository.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list cached plugins: %w", err)
+	}
+	for _, plugin := range selectPrunablePlugins(plugins, nil, keepVersions) {
+		if err := s.repository.Delete(ctx, plugin.Reference()); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", plugin.Reference(), err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// PluginVendorService copies the registry plugins a workspace uses from the
+// cache into its vendor directory, so they can be committed with the
+// profiles and reglet.lock and runs need no registry access.
+type PluginVendorService struct {
+	repository ports.PluginRepository
+	vendor     ports.PluginRepository
+	scanner    ports.PluginReferenceScanner
+}
+
+// NewPluginVendorService creates a vendor service copying plugins from
+// repository (the cache) into vendor.
+func NewPluginVendorService(
+	repository ports.PluginRepository,
+	vendor ports.PluginRepository,
+	scanner ports.PluginReferenceScanner,
+) *PluginVendorService {
+	return &PluginVendorService{
+		repository: repository,
+		vendor:     vendor,
+		scanner:    scanner,
+	}
+}
+
+// Vendor brings the vendor directory in line with the workspace: the cached
+// plugin versions its profiles and lockfiles reference are copied in, and
+// vendored versions nothing references any more are removed. Registry
+// plugins the workspace references but the cache lacks are reported as
+// missing and must be pulled first.
+func (s *PluginVendorService) Vendor(ctx context.Context, workspace string) (*dto.VendorPluginsReport, error) {
+	references, err := s.scanner.ScanPluginReferences(ctx, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan workspace %s: %w", workspace, err)
+	}
+	cached, err := s.repository.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached plugins: %w", err)
+	}
+
+	unreferenced := make(map[string]bool)
+	for _, plugin := range selectPrunablePlugins(cached, references, 0) {
+		unreferenced[plugin.Reference().String()] = true
+	}
+	var used []*entities.Plugin
+	for _, plugin := range cached {
+		if !plugin.Reference().IsEmbedded() && !unreferenced[plugin.Reference().String()] {
+			used = append(used, plugin)
+		}
+	}
+	sort.Slice(used, func(i, j int) bool { return used[i].Reference().String() < used[j].Reference().String() })
+
+	report := &dto.VendorPluginsReport{Missing: missingPlugins(references, used)}
+	keep := make(map[string]bool, len(used))
+	for _, plugin := range used {
+		ref := plugin.Reference()
+		keep[ref.String()] = true
+		if vendored, _, err := s.vendor.Find(ctx, ref); err == nil && vendored.Digest().Equals(plugin.Digest()) {
+			report.Unchanged++
+			continue
+		}
+
+		_, wasmPath, err := s.repository.Find(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate %s: %w", ref.String(), err)
+		}
+		wasm, err := os.ReadFile(filepath.Clean(wasmPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", ref.String(), err)
+		}
+		if _, err := s.vendor.Store(ctx, plugin, bytes.NewReader(wasm)); err != nil {
+			return nil, fmt.Errorf("failed to vendor %s: %w", ref.String(), err)
+		}
+		report.Vendored = append(report.Vendored, ref.String())
+	}
+
+	vendored, err := s.vendor.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vendored plugins: %w", err)
+	}
+	for _, plugin := range vendored {
+		ref := plugin.Reference()
+		if keep[ref.String()] {
+			continue
+		}
+		if err := s.vendor.Delete(ctx, ref); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", ref.String(), err)
+		}
+		report.Removed = append(report.Removed, ref.String())
+	}
+	sort.Strings(report.Removed)
+	return report, nil
+}
+
+// missingPlugins returns the registry references no plugin in plugins
+// satisfies.
+func missingPlugins(references []*entities.PluginSpec, plugins []*entities.Plugin) []string {
+	seen := make(map[string]bool)
+	var missing []string
+	for _, spec := range references {
+		repo := pluginRepository(spec.Source)
+		if strings.Count(repo, "/") < 3 || seen[spec.Source] {
+			continue
+		}
+		var versions []string
+		for _, plugin := range plugins {
+			if pluginRepository(plugin.Reference().String()) == repo {
+				versions = append(versions, plugin.Reference().Version())
+			}
+		}
+		if _, ok := spec.SelectVersion(versions); ok {
+			continue
+		}
+		seen[spec.Source] = true
+		missing = append(missing, spec.Source)
+	}
+	sort.Strings(missing)
+	return missing
+}
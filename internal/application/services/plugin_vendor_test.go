@@ -0,0 +1,155 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dirRepository is a ports.PluginRepository keeping binaries in a directory.
+type dirRepository struct {
+	dir     string
+	plugins map[string]*entities.Plugin
+}
+
+func newDirRepository(t *testing.T) *dirRepository {
+	return &dirRepository{dir: t.TempDir(), plugins: make(map[string]*entities.Plugin)}
+}
+
+func (r *dirRepository) path(ref values.PluginReference) string {
+	return filepath.Join(r.dir, hex.EncodeToString([]byte(ref.String())))
+}
+
+func (r *dirRepository) Find(_ context.Context, ref values.PluginReference) (*entities.Plugin, string, error) {
+	plugin, ok := r.plugins[ref.String()]
+	if !ok {
+		return nil, "", &entities.PluginNotFoundError{Reference: ref}
+	}
+	return plugin, r.path(ref), nil
+}
+
+func (r *dirRepository) Store(_ context.Context, plugin *entities.Plugin, wasm io.Reader) (string, error) {
+	data, err := io.ReadAll(wasm)
+	if err != nil {
+		return "", err
+	}
+	path := r.path(plugin.Reference())
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", err
+	}
+	r.plugins[plugin.Reference().String()] = plugin
+	return path, nil
+}
+
+func (r *dirRepository) List(_ context.Context) ([]*entities.Plugin, error) {
+	plugins := make([]*entities.Plugin, 0, len(r.plugins))
+	for _, plugin := range r.plugins {
+		plugins = append(plugins, plugin)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Reference().String() < plugins[j].Reference().String() })
+	return plugins, nil
+}
+
+func (r *dirRepository) Delete(_ context.Context, ref values.PluginReference) error {
+	delete(r.plugins, ref.String())
+	return os.Remove(r.path(ref))
+}
+
+func (r *dirRepository) add(t *testing.T, name, version string) *entities.Plugin {
+	wasm := []byte("wasm " + name + " " + version)
+	sum := sha256.Sum256(wasm)
+	digest, err := values.NewDigest("sha256", hex.EncodeToString(sum[:]))
+	require.NoError(t, err)
+	ref := values.NewPluginReference("ghcr.io", "reglet-dev", "reglet-plugins", name, version)
+	plugin := entities.NewPlugin(ref, digest, values.NewPluginMetadata(name, version, "", nil))
+	_, err = r.Store(context.Background(), plugin, bytes.NewReader(wasm))
+	require.NoError(t, err)
+	return plugin
+}
+
+func TestPluginVendorService_Vendor(t *testing.T) {
+	ctx := context.Background()
+	cache := newDirRepository(t)
+	cache.add(t, "file", "1.0.0")
+	cache.add(t, "file", "1.1.0")
+	cache.add(t, "http", "1.0.0")
+
+	vendor := newDirRepository(t)
+	vendor.add(t, "dns", "0.9.0") // no longer used
+	vendor.add(t, "file", "1.1.0")
+
+	scanner := &fakeReferenceScanner{specs: []*entities.PluginSpec{
+		{Name: "file", Source: "ghcr.io/reglet-dev/reglet-plugins/file:1.1.0", Version: "1.1.0"},
+		{Name: "http", Source: "http", Version: "1"},
+		{Name: "aws", Source: "ghcr.io/reglet-dev/reglet-plugins/aws:2.0.0", Version: "2.0.0"},
+	}}
+
+	report, err := NewPluginVendorService(cache, vendor, scanner).Vendor(ctx, ".")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ghcr.io/reglet-dev/reglet-plugins/http:1.0.0"}, report.Vendored)
+	assert.Equal(t, []string{"ghcr.io/reglet-dev/reglet-plugins/dns:0.9.0"}, report.Removed)
+	assert.Equal(t, []string{"ghcr.io/reglet-dev/reglet-plugins/aws:2.0.0"}, report.Missing)
+	assert.Equal(t, 1, report.Unchanged)
+
+	vendored, err := vendor.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, vendored, 2)
+	assert.Equal(t, "ghcr.io/reglet-dev/reglet-plugins/file:1.1.0", vendored[0].Reference().String())
+	assert.Equal(t, "ghcr.io/reglet-dev/reglet-plugins/http:1.0.0", vendored[1].Reference().String())
+}
+
+func TestVendoredPluginResolver_Resolve(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("copies vendored plugin into cache", func(t *testing.T) {
+		vendor, cache := newDirRepository(t), newDirRepository(t)
+		plugin := vendor.add(t, "file", "1.0.0")
+
+		resolver := NewVendoredPluginResolver(vendor, cache, logger)
+		resolved, err := resolver.Resolve(ctx, plugin.Reference())
+		require.NoError(t, err)
+		assert.Equal(t, plugin, resolved)
+
+		_, path, err := cache.Find(ctx, plugin.Reference())
+		require.NoError(t, err)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "wasm file 1.0.0", string(data))
+	})
+
+	t.Run("rejects tampered binary", func(t *testing.T) {
+		vendor, cache := newDirRepository(t), newDirRepository(t)
+		plugin := vendor.add(t, "file", "1.0.0")
+		_, path, _ := vendor.Find(ctx, plugin.Reference())
+		require.NoError(t, os.WriteFile(path, []byte("tampered"), 0o600))
+
+		_, err := NewVendoredPluginResolver(vendor, cache, logger).Resolve(ctx, plugin.Reference())
+		assert.ErrorContains(t, err, "digest mismatch")
+	})
+
+	t.Run("delegates plugins not vendored", func(t *testing.T) {
+		ref := values.NewPluginReference("ghcr.io", "reglet-dev", "reglet-plugins", "http", "1.0.0")
+		next := &mockResolver{foundPlugin: entities.NewPlugin(ref, values.Digest{}, values.PluginMetadata{})}
+		resolver := NewVendoredPluginResolver(newDirRepository(t), newDirRepository(t), logger)
+		resolver.SetNext(next)
+
+		resolved, err := resolver.Resolve(ctx, ref)
+		require.NoError(t, err)
+		assert.Equal(t, ref, resolved.Reference())
+		assert.True(t, next.called)
+	})
+}
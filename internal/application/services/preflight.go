@@ -0,0 +1,546 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	apperrors "github.com/reglet-dev/reglet/internal/application/errors"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/services"
+)
+
+const (
+	// preflightLookupTimeout bounds each host lookup so a slow resolver
+	// cannot stall the preflight.
+	preflightLookupTimeout = 5 * time.Second
+
+	// preflightLookupConcurrency caps concurrent host lookups.
+	preflightLookupConcurrency = 16
+
+	// preflightDialTimeout bounds each connection attempt to a target.
+	preflightDialTimeout = 5 * time.Second
+)
+
+// missingSecretPattern matches the placeholder substituted for a secret that
+// failed to resolve, so the preflight can tell which observations use it.
+var missingSecretPattern = regexp.MustCompile("\x00missing-secret:([^\x00]+)\x00")
+
+// PreflightService verifies a profile's setup prerequisites before a run:
+// that referenced secrets resolve, that target hosts resolve, and that no
+// proxy is configured that plugins would bypass. With a host access probe it
+// also verifies that this host lets reglet read the files, run the commands
+// and reach the targets of the observations. Each problem is reported once
+// per plugin with the controls it affects, instead of as one error per
+// observation.
+type PreflightService struct {
+	newLoader func(ports.SecretResolver) ports.ProfileLoader
+	secrets   ports.SecretResolver
+	resolver  ports.HostResolver
+	probe     ports.HostAccessProbe
+	getenv    func(string) string
+	logger    *slog.Logger
+}
+
+// NewPreflightService creates a preflight service. newLoader builds a profile
+// loader around the given secret resolver; getenv defaults to os.Getenv.
+func NewPreflightService(
+	newLoader func(ports.SecretResolver) ports.ProfileLoader,
+	secrets ports.SecretResolver,
+	resolver ports.HostResolver,
+	getenv func(string) string,
+	logger *slog.Logger,
+) *PreflightService {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PreflightService{
+		newLoader: newLoader,
+		secrets:   secrets,
+		resolver:  resolver,
+		getenv:    getenv,
+		logger:    logger,
+	}
+}
+
+// SetHostAccessProbe makes the preflight try the file reads, commands and
+// connections of the observations with probe. Without one, they are not
+// checked.
+func (s *PreflightService) SetHostAccessProbe(probe ports.HostAccessProbe) {
+	s.probe = probe
+}
+
+// Run checks the controls of the profile at profilePath selected by filters.
+// It returns an error only when the profile cannot be checked at all.
+func (s *PreflightService) Run(ctx context.Context, profilePath, environment string, filters dto.FilterOptions) (*dto.PreflightReport, error) {
+	secrets := &recordingSecretResolver{next: s.secrets, errs: make(map[string]error)}
+	profile, err := s.newLoader(secrets).LoadProfile(profilePath, environment)
+	if err != nil {
+		return nil, apperrors.NewValidationError("profile", "failed to load profile", err.Error())
+	}
+
+	filter, err := preflightFilter(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := newPreflightIssues()
+	targets := make(map[string]map[string][]string)      // host -> plugin -> controls
+	accesses := make(map[hostAccess]map[string][]string) // access -> plugin -> controls
+
+	for _, ctrl := range profile.Controls.Items {
+		if run, _ := filter.ShouldRun(ctrl); !run {
+			continue
+		}
+		for _, obs := range ctrl.ObservationDefinitions {
+			for _, name := range missingSecrets(obs.Config) {
+				issues.add(obs.Plugin, secretIssue(name, secrets.errs[name]), ctrl.ID)
+			}
+			if s.probe != nil {
+				for _, access := range observationAccess(obs.Plugin, obs.Config) {
+					if accesses[access] == nil {
+						accesses[access] = make(map[string][]string)
+					}
+					accesses[access][obs.Plugin] = append(accesses[access][obs.Plugin], ctrl.ID)
+				}
+			}
+
+			host, scheme := observationTarget(obs.Config)
+			if host == "" {
+				continue
+			}
+			if targets[host] == nil {
+				targets[host] = make(map[string][]string)
+			}
+			targets[host][obs.Plugin] = append(targets[host][obs.Plugin], ctrl.ID)
+
+			if issue, ok := s.proxyIssue(scheme); ok {
+				issues.add(obs.Plugin, issue, ctrl.ID)
+			}
+		}
+	}
+
+	for name, section := range profile.Integrations {
+		for _, secret := range missingSecrets(section) {
+			issues.add(name, secretIssue(secret, secrets.errs[secret]))
+		}
+	}
+
+	unresolved := s.lookupHosts(ctx, targets)
+	for host, err := range unresolved {
+		for plugin, controls := range targets[host] {
+			issues.add(plugin, dnsIssue(host, err), controls...)
+		}
+	}
+
+	for access, issue := range s.probeAccess(ctx, accesses, unresolved) {
+		for plugin, controls := range accesses[access] {
+			issues.add(plugin, issue, controls...)
+		}
+	}
+
+	return issues.report(), nil
+}
+
+// preflightFilter builds the control filter the run will apply. With
+// dependencies included every control may run, so none are filtered out.
+func preflightFilter(filters dto.FilterOptions) (*services.ControlFilter, error) {
+	filter := services.NewControlFilter()
+	if filters.IncludeDependencies {
+		return filter, nil
+	}
+
+	filter = filter.
+		WithExclusiveControls(filters.IncludeControlIDs).
+		WithExcludedControls(filters.ExcludeControlIDs).
+		WithExcludedTags(filters.ExcludeTags).
+		WithIncludedTags(filters.IncludeTags).
+		WithIncludedSeverities(filters.IncludeSeverities)
+
+	if filters.FilterExpression != "" {
+		options := append([]expr.Option{expr.Env(services.ControlEnv{}), expr.AsBool()}, services.ExpressionFunctions()...)
+		program, err := expr.Compile(filters.FilterExpression, options...)
+		if err != nil {
+			return nil, apperrors.NewValidationError("filters", fmt.Sprintf("invalid --filter expression: %v", err))
+		}
+		filter = filter.WithFilterExpression(program)
+	}
+	return filter, nil
+}
+
+// observationTarget returns the host an observation connects to, read from
+// its host or url setting, and the URL scheme if any. IP addresses, values
+// still holding template syntax and unresolved secrets yield no host.
+func observationTarget(config map[string]interface{}) (host, scheme string) {
+	if raw, ok := config["url"].(string); ok {
+		if u, err := url.Parse(raw); err == nil {
+			host, scheme = u.Hostname(), u.Scheme
+		}
+	} else if raw, ok := config["host"].(string); ok {
+		host = raw
+	}
+
+	if host == "" || net.ParseIP(host) != nil || strings.ContainsAny(host, "{}\x00") {
+		return "", ""
+	}
+	return strings.ToLower(host), scheme
+}
+
+// lookupHosts resolves every target host concurrently and returns the
+// failures keyed by host.
+func (s *PreflightService) lookupHosts(ctx context.Context, targets map[string]map[string][]string) map[string]error {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failures = make(map[string]error)
+		sem      = make(chan struct{}, preflightLookupConcurrency)
+	)
+
+	for host := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			lookupCtx, cancel := context.WithTimeout(ctx, preflightLookupTimeout)
+			defer cancel()
+
+			if _, err := s.resolver.LookupHost(lookupCtx, host); err != nil {
+				s.logger.Debug("preflight host lookup failed", "host", host, "error", err)
+				mu.Lock()
+				failures[host] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return failures
+}
+
+// hostAccess is a host operation an observation performs: reading a file
+// (PreflightCheckPermission), running a command (PreflightCheckCommand) or
+// connecting to a target (PreflightCheckNetwork).
+type hostAccess struct {
+	check   string
+	subject string // file, command or host:port
+}
+
+// observationAccess returns the host operations of an observation that the
+// preflight can try: the file of the file plugin, the command of the command
+// plugin, and the target of any plugin configured with an http(s) url or a
+// host and port. Values still holding template syntax, globs or unresolved
+// secrets are not tried.
+func observationAccess(plugin string, config map[string]interface{}) []hostAccess {
+	usable := func(value string) bool { return value != "" && !strings.ContainsAny(value, "{}\x00") }
+
+	var accesses []hostAccess
+	switch plugin {
+	case "file":
+		if path, ok := config["path"].(string); ok && usable(path) && !strings.ContainsAny(path, "*?[") {
+			accesses = append(accesses, hostAccess{check: dto.PreflightCheckPermission, subject: path})
+		}
+	case "command":
+		command, _ := config["command"].(string)
+		if run, ok := config["run"].(string); ok && run != "" {
+			command = "/bin/sh"
+			if shell, ok := config["shell"].(string); ok && shell != "" {
+				command = shell
+			}
+		}
+		if usable(command) {
+			accesses = append(accesses, hostAccess{check: dto.PreflightCheckCommand, subject: command})
+		}
+	}
+
+	if raw, ok := config["url"].(string); ok && usable(raw) {
+		if u, err := url.Parse(raw); err == nil && u.Hostname() != "" {
+			port := u.Port()
+			switch {
+			case port != "":
+			case u.Scheme == "http":
+				port = "80"
+			case u.Scheme == "https":
+				port = "443"
+			}
+			if port != "" {
+				accesses = append(accesses, hostAccess{check: dto.PreflightCheckNetwork, subject: net.JoinHostPort(u.Hostname(), port)})
+			}
+		}
+	} else if host, ok := config["host"].(string); ok && usable(host) {
+		if port := fmt.Sprint(config["port"]); config["port"] != nil && usable(port) {
+			accesses = append(accesses, hostAccess{check: dto.PreflightCheckNetwork, subject: net.JoinHostPort(host, port)})
+		}
+	}
+	return accesses
+}
+
+// probeAccess tries every host operation concurrently and returns the
+// problems found, keyed by operation. Targets whose host did not resolve
+// are already reported and not tried.
+func (s *PreflightService) probeAccess(ctx context.Context, accesses map[hostAccess]map[string][]string, unresolved map[string]error) map[hostAccess]dto.PreflightIssue {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		found = make(map[hostAccess]dto.PreflightIssue)
+		sem   = make(chan struct{}, preflightLookupConcurrency)
+	)
+
+	for access := range accesses {
+		if access.check == dto.PreflightCheckNetwork {
+			host, _, _ := net.SplitHostPort(access.subject)
+			if _, failed := unresolved[strings.ToLower(host)]; failed {
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var err error
+			switch access.check {
+			case dto.PreflightCheckPermission:
+				err = s.probe.OpenFile(access.subject)
+			case dto.PreflightCheckCommand:
+				err = s.probe.LookupCommand(access.subject)
+			case dto.PreflightCheckNetwork:
+				dialCtx, cancel := context.WithTimeout(ctx, preflightDialTimeout)
+				err = s.probe.Dial(dialCtx, access.subject)
+				cancel()
+			}
+			if err == nil {
+				return
+			}
+			s.logger.Debug("preflight host access failed", "check", access.check, "subject", access.subject, "error", err)
+			if issue, ok := accessIssue(access, err); ok {
+				mu.Lock()
+				found[access] = issue
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return found
+}
+
+// accessIssue explains a failed host operation and how to fix it. A missing
+// file is not a problem: observations report it as evidence.
+func accessIssue(access hostAccess, err error) (dto.PreflightIssue, bool) {
+	issue := dto.PreflightIssue{Check: access.check, Subject: access.subject}
+	denied := errors.Is(err, fs.ErrPermission)
+
+	switch access.check {
+	case dto.PreflightCheckPermission:
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			return issue, false
+		case denied:
+			issue.Message = fmt.Sprintf("cannot read %s: permission denied", access.subject)
+			issue.Hint = fmt.Sprintf("run reglet with sudo, grant the user running it read access (sudo setfacl -m u:$(id -un):r %s), "+
+				"or let reglet read any file (sudo setcap cap_dac_read_search+ep $(command -v reglet))", access.subject)
+		default:
+			issue.Message = fmt.Sprintf("cannot read %s: %v", access.subject, err)
+			issue.Hint = "check that the file is readable on this host"
+			issue.Warning = true
+		}
+
+	case dto.PreflightCheckCommand:
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			issue.Message = fmt.Sprintf("command %s not found", access.subject)
+			issue.Hint = "install it on this host, or set command to its absolute path"
+		case denied:
+			issue.Message = fmt.Sprintf("cannot run %s: permission denied", access.subject)
+			issue.Hint = fmt.Sprintf("make it executable for the user running reglet (sudo chmod o+x %s), or run reglet with sudo", access.subject)
+		default:
+			issue.Message = fmt.Sprintf("cannot run %s: %v", access.subject, err)
+			issue.Hint = "check that the command is installed and executable on this host"
+		}
+
+	case dto.PreflightCheckNetwork:
+		switch {
+		case denied:
+			issue.Message = fmt.Sprintf("connections to %s are blocked on this host: permission denied", access.subject)
+			issue.Hint = "allow outbound connections to the target for the user running reglet in the host firewall (iptables, nftables) or security policy (SELinux, AppArmor)"
+		case errors.Is(err, context.DeadlineExceeded):
+			issue.Message = fmt.Sprintf("%s did not answer within %s", access.subject, preflightDialTimeout)
+			issue.Hint = "check firewalls and security groups between this host and the target"
+			issue.Warning = true
+		default:
+			issue.Message = fmt.Sprintf("cannot connect to %s: %v", access.subject, err)
+			issue.Hint = "check the routes from this host to the target (VPN, gateway, network interfaces)"
+		}
+	}
+	return issue, true
+}
+
+// proxyIssue warns when a proxy is configured for scheme. Plugin HTTP
+// requests are pinned to the resolved target address and never go through a
+// proxy, so a network that only allows egress via the proxy fails them.
+func (s *PreflightService) proxyIssue(scheme string) (dto.PreflightIssue, bool) {
+	if scheme != "http" && scheme != "https" {
+		return dto.PreflightIssue{}, false
+	}
+
+	for _, name := range []string{strings.ToUpper(scheme) + "_PROXY", scheme + "_proxy"} {
+		if s.getenv(name) == "" {
+			continue
+		}
+		return dto.PreflightIssue{
+			Check:   dto.PreflightCheckProxy,
+			Subject: name,
+			Message: name + " is set, but plugin HTTP requests connect directly and do not use it",
+			Hint:    "allow direct egress to the targets, or run from a network where they are reachable without the proxy",
+			Warning: true,
+		}, true
+	}
+	return dto.PreflightIssue{}, false
+}
+
+func secretIssue(name string, err error) dto.PreflightIssue {
+	message := fmt.Sprintf("secret %q could not be resolved", name)
+	if err != nil {
+		message = err.Error()
+	}
+	return dto.PreflightIssue{
+		Check:   dto.PreflightCheckSecret,
+		Subject: name,
+		Message: message,
+		Hint:    "define it under sensitive_data.secrets (local, env or files) in the system config and make sure its source is present",
+	}
+}
+
+func dnsIssue(host string, err error) dto.PreflightIssue {
+	message := fmt.Sprintf("%s does not resolve: %v", host, err)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		switch {
+		case dnsErr.IsNotFound:
+			message = host + " does not resolve: no such host"
+		case dnsErr.IsTimeout:
+			message = host + " does not resolve: lookup timed out"
+		}
+	}
+	return dto.PreflightIssue{
+		Check:   dto.PreflightCheckDNS,
+		Subject: host,
+		Message: message,
+		Hint:    "check the host name, and that this machine's DNS can resolve it (VPN, split-horizon DNS, /etc/hosts)",
+	}
+}
+
+// missingSecrets returns the names of unresolved secrets used in value.
+func missingSecrets(value interface{}) []string {
+	var names []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case string:
+			for _, m := range missingSecretPattern.FindAllStringSubmatch(v, -1) {
+				names = append(names, m[1])
+			}
+		case map[string]interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// recordingSecretResolver resolves secrets through next, substituting a
+// placeholder for any that fail so loading continues and every missing
+// secret is reported, not just the first.
+type recordingSecretResolver struct {
+	next ports.SecretResolver
+	errs map[string]error
+	mu   sync.Mutex
+}
+
+func (r *recordingSecretResolver) Resolve(name string) (string, error) {
+	value, err := r.next.Resolve(name)
+	if err == nil {
+		return value, nil
+	}
+
+	r.mu.Lock()
+	r.errs[name] = err
+	r.mu.Unlock()
+	return "\x00missing-secret:" + name + "\x00", nil
+}
+
+// preflightIssues collects issues, merging repeats of the same problem.
+type preflightIssues struct {
+	byPlugin map[string]map[string]*dto.PreflightIssue // plugin -> check/subject -> issue
+}
+
+func newPreflightIssues() *preflightIssues {
+	return &preflightIssues{byPlugin: make(map[string]map[string]*dto.PreflightIssue)}
+}
+
+func (p *preflightIssues) add(plugin string, issue dto.PreflightIssue, controls ...string) {
+	if p.byPlugin[plugin] == nil {
+		p.byPlugin[plugin] = make(map[string]*dto.PreflightIssue)
+	}
+
+	key := issue.Check + "\x00" + issue.Subject
+	existing, ok := p.byPlugin[plugin][key]
+	if !ok {
+		existing = &issue
+		p.byPlugin[plugin][key] = existing
+	}
+	existing.Controls = append(existing.Controls, controls...)
+}
+
+// report returns the issues grouped by plugin, with plugins and issues in a
+// stable order.
+func (p *preflightIssues) report() *dto.PreflightReport {
+	report := &dto.PreflightReport{}
+	for plugin, issues := range p.byPlugin {
+		group := dto.PreflightGroup{Plugin: plugin}
+		for _, issue := range issues {
+			slices.Sort(issue.Controls)
+			issue.Controls = slices.Compact(issue.Controls)
+			group.Issues = append(group.Issues, *issue)
+		}
+		slices.SortFunc(group.Issues, func(a, b dto.PreflightIssue) int {
+			if c := strings.Compare(a.Check, b.Check); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Subject, b.Subject)
+		})
+		report.Groups = append(report.Groups, group)
+	}
+	slices.SortFunc(report.Groups, func(a, b dto.PreflightGroup) int {
+		return strings.Compare(a.Plugin, b.Plugin)
+	})
+	return report
+}
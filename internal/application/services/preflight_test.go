@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type profileLoaderFunc func(path string) (*entities.Profile, error)
+
+func (f profileLoaderFunc) LoadProfile(path, _ string) (*entities.Profile, error) {
+	return f(path)
+}
+
+type fakeSecrets map[string]string
+
+func (s fakeSecrets) Resolve(name string) (string, error) {
+	if value, ok := s[name]; ok {
+		return value, nil
+	}
+	return "", errors.New("secret " + name + " not found")
+}
+
+// fakeHostResolver resolves only the hosts it knows and counts lookups.
+type fakeHostResolver struct {
+	known   map[string]bool
+	lookups map[string]int
+	mu      sync.Mutex
+}
+
+func (r *fakeHostResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups[host]++
+	if r.known[host] {
+		return []string{"192.0.2.1"}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+// preflightProfile builds a profile the way the loader would, resolving the
+// token secret through the given resolver.
+func preflightProfile(resolver ports.SecretResolver) (*entities.Profile, error) {
+	token, err := resolver.Resolve("api_token")
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := resolver.Resolve("webhook_url")
+	if err != nil {
+		return nil, err
+	}
+
+	observe := func(plugin string, config map[string]interface{}) []entities.ObservationDefinition {
+		return []entities.ObservationDefinition{{Plugin: plugin, Config: config}}
+	}
+	return &entities.Profile{
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "web-1", Tags: []string{"web"}, ObservationDefinitions: observe("http", map[string]interface{}{
+				"url":     "https://missing.example/health",
+				"headers": map[string]interface{}{"Authorization": "Bearer " + token},
+			})},
+			{ID: "web-2", Tags: []string{"web"}, ObservationDefinitions: observe("http", map[string]interface{}{
+				"url": "https://missing.example/ready",
+			})},
+			{ID: "db", ObservationDefinitions: observe("tcp", map[string]interface{}{"host": "db.example", "port": "5432"})},
+			{ID: "local", ObservationDefinitions: observe("tcp", map[string]interface{}{"host": "127.0.0.1", "port": "22"})},
+			{ID: "templated", ObservationDefinitions: observe("tcp", map[string]interface{}{"host": "{{ .env.HOST }}"})},
+		}},
+		Integrations: map[string]map[string]interface{}{
+			"slack": {"webhook": webhook},
+		},
+	}, nil
+}
+
+func newTestPreflightService(secrets fakeSecrets, hosts *fakeHostResolver, env map[string]string) *PreflightService {
+	newLoader := func(resolver ports.SecretResolver) ports.ProfileLoader {
+		return profileLoaderFunc(func(string) (*entities.Profile, error) { return preflightProfile(resolver) })
+	}
+	getenv := func(name string) string { return env[name] }
+	return NewPreflightService(newLoader, secrets, hosts, getenv, nil)
+}
+
+func newFakeHostResolver(known ...string) *fakeHostResolver {
+	r := &fakeHostResolver{known: make(map[string]bool), lookups: make(map[string]int)}
+	for _, host := range known {
+		r.known[host] = true
+	}
+	return r
+}
+
+func TestPreflightService_Run_GroupsIssuesByPlugin(t *testing.T) {
+	t.Parallel()
+
+	hosts := newFakeHostResolver("db.example")
+	svc := newTestPreflightService(fakeSecrets{"webhook_url": "https://hooks.example"}, hosts, nil)
+
+	report, err := svc.Run(context.Background(), "profile.yaml", "", dto.FilterOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, report.Groups, 1)
+	group := report.Groups[0]
+	assert.Equal(t, "http", group.Plugin)
+	require.Len(t, group.Issues, 2)
+
+	dns := group.Issues[0]
+	assert.Equal(t, dto.PreflightCheckDNS, dns.Check)
+	assert.Equal(t, "missing.example", dns.Subject)
+	assert.Equal(t, "missing.example does not resolve: no such host", dns.Message)
+	assert.Equal(t, []string{"web-1", "web-2"}, dns.Controls, "one issue for every control using the host")
+
+	secret := group.Issues[1]
+	assert.Equal(t, dto.PreflightCheckSecret, secret.Check)
+	assert.Equal(t, "api_token", secret.Subject)
+	assert.Equal(t, "secret api_token not found", secret.Message)
+	assert.Equal(t, []string{"web-1"}, secret.Controls)
+
+	assert.Equal(t, 2, report.ErrorCount())
+	assert.Equal(t, map[string]int{"missing.example": 1, "db.example": 1}, hosts.lookups,
+		"each host is looked up once; IPs and templated hosts are skipped")
+}
+
+func TestPreflightService_Run_Integrations(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestPreflightService(fakeSecrets{"api_token": "t"}, newFakeHostResolver("missing.example", "db.example"), nil)
+
+	report, err := svc.Run(context.Background(), "profile.yaml", "", dto.FilterOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, report.Groups, 1)
+	assert.Equal(t, "slack", report.Groups[0].Plugin)
+	require.Len(t, report.Groups[0].Issues, 1)
+	assert.Equal(t, "webhook_url", report.Groups[0].Issues[0].Subject)
+	assert.Empty(t, report.Groups[0].Issues[0].Controls)
+}
+
+func TestPreflightService_Run_ProxyWarning(t *testing.T) {
+	t.Parallel()
+
+	secrets := fakeSecrets{"api_token": "t", "webhook_url": "u"}
+	svc := newTestPreflightService(secrets, newFakeHostResolver("missing.example", "db.example"), map[string]string{
+		"https_proxy": "http://proxy:3128",
+	})
+
+	report, err := svc.Run(context.Background(), "profile.yaml", "", dto.FilterOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, report.Groups, 1)
+	require.Len(t, report.Groups[0].Issues, 1)
+	issue := report.Groups[0].Issues[0]
+	assert.Equal(t, dto.PreflightCheckProxy, issue.Check)
+	assert.Equal(t, "https_proxy", issue.Subject)
+	assert.True(t, issue.Warning)
+	assert.Zero(t, report.ErrorCount(), "warnings do not fail the preflight")
+}
+
+func TestPreflightService_Run_AppliesFilters(t *testing.T) {
+	t.Parallel()
+
+	hosts := newFakeHostResolver()
+	svc := newTestPreflightService(fakeSecrets{"webhook_url": "u"}, hosts, nil)
+
+	report, err := svc.Run(context.Background(), "profile.yaml", "", dto.FilterOptions{ExcludeTags: []string{"web"}})
+	require.NoError(t, err)
+
+	require.Len(t, report.Groups, 1)
+	assert.Equal(t, "tcp", report.Groups[0].Plugin)
+	assert.Equal(t, map[string]int{"db.example": 1}, hosts.lookups)
+
+	_, err = svc.Run(context.Background(), "profile.yaml", "", dto.FilterOptions{FilterExpression: "severity =="})
+	assert.Error(t, err)
+}
+
+func TestPreflightService_Run_LoadError(t *testing.T) {
+	t.Parallel()
+
+	newLoader := func(ports.SecretResolver) ports.ProfileLoader {
+		return profileLoaderFunc(func(string) (*entities.Profile, error) { return nil, errors.New("bad yaml") })
+	}
+	svc := NewPreflightService(newLoader, fakeSecrets{}, newFakeHostResolver(), nil, nil)
+
+	_, err := svc.Run(context.Background(), "profile.yaml", "", dto.FilterOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load profile")
+}
+
+// fakeHostAccessProbe fails the operations it has errors for and records
+// what it was asked to try.
+type fakeHostAccessProbe struct {
+	errs  map[string]error
+	tried []string
+	mu    sync.Mutex
+}
+
+func (p *fakeHostAccessProbe) try(subject string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tried = append(p.tried, subject)
+	return p.errs[subject]
+}
+
+func (p *fakeHostAccessProbe) OpenFile(path string) error             { return p.try(path) }
+func (p *fakeHostAccessProbe) LookupCommand(name string) error        { return p.try(name) }
+func (p *fakeHostAccessProbe) Dial(_ context.Context, a string) error { return p.try(a) }
+
+func TestPreflightService_Run_HostAccess(t *testing.T) {
+	t.Parallel()
+
+	observe := func(id, plugin string, config map[string]interface{}) entities.Control {
+		return entities.Control{ID: id, ObservationDefinitions: []entities.ObservationDefinition{{Plugin: plugin, Config: config}}}
+	}
+	profile := &entities.Profile{Controls: entities.ControlsSection{Items: []entities.Control{
+		observe("shadow", "file", map[string]interface{}{"path": "/etc/shadow"}),
+		observe("shadow-mode", "file", map[string]interface{}{"path": "/etc/shadow"}),
+		observe("optional", "file", map[string]interface{}{"path": "/etc/optional.conf"}),
+		observe("audit", "command", map[string]interface{}{"command": "auditctl", "args": []interface{}{"-s"}}),
+		observe("sshd", "command", map[string]interface{}{"run": "systemctl is-active sshd"}),
+		observe("ssh-port", "tcp", map[string]interface{}{"host": "10.0.0.5", "port": 22}),
+		observe("api", "http", map[string]interface{}{"url": "https://api.example/health"}),
+		observe("gone", "tcp", map[string]interface{}{"host": "missing.example", "port": "5432"}),
+		observe("templated", "file", map[string]interface{}{"path": "{{ .vars.path }}"}),
+	}}}
+	newLoader := func(ports.SecretResolver) ports.ProfileLoader {
+		return profileLoaderFunc(func(string) (*entities.Profile, error) { return profile, nil })
+	}
+	probe := &fakeHostAccessProbe{errs: map[string]error{
+		"/etc/shadow":        &fs.PathError{Op: "open", Path: "/etc/shadow", Err: syscall.EACCES},
+		"/etc/optional.conf": &fs.PathError{Op: "open", Path: "/etc/optional.conf", Err: fs.ErrNotExist},
+		"auditctl":           fmt.Errorf("auditctl not found in PATH: %w", fs.ErrNotExist),
+		"10.0.0.5:22":        &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.EPERM)},
+		"api.example:443":    context.DeadlineExceeded,
+	}}
+	svc := NewPreflightService(newLoader, fakeSecrets{}, newFakeHostResolver("api.example"), nil, nil)
+	svc.SetHostAccessProbe(probe)
+
+	report, err := svc.Run(context.Background(), "profile.yaml", "", dto.FilterOptions{})
+	require.NoError(t, err)
+
+	issues := make(map[string]dto.PreflightIssue)
+	for _, group := range report.Groups {
+		for _, issue := range group.Issues {
+			issues[group.Plugin+" "+issue.Check+" "+issue.Subject] = issue
+		}
+	}
+	require.Len(t, issues, 5)
+
+	shadow := issues["file permission /etc/shadow"]
+	assert.Equal(t, "cannot read /etc/shadow: permission denied", shadow.Message)
+	assert.Contains(t, shadow.Hint, "cap_dac_read_search")
+	assert.Equal(t, []string{"shadow", "shadow-mode"}, shadow.Controls)
+	assert.Equal(t, "command auditctl not found", issues["command command auditctl"].Message)
+	assert.Contains(t, issues["tcp network 10.0.0.5:22"].Message, "blocked on this host")
+	assert.True(t, issues["http network api.example:443"].Warning, "a silent target may be filtered on purpose")
+	assert.Contains(t, issues, "tcp dns missing.example")
+	assert.Equal(t, 4, report.ErrorCount())
+
+	assert.ElementsMatch(t, []string{"/etc/shadow", "/etc/optional.conf", "auditctl", "/bin/sh", "10.0.0.5:22", "api.example:443"}, probe.tried,
+		"files are tried once, shell commands need /bin/sh, and unresolved and templated targets are skipped")
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/services"
+)
+
+// ProfileTestService runs profile unit tests: each test case executes the
+// profile with plugins replaced by fixture evidence and checks the resulting
+// control statuses and messages.
+type ProfileTestService struct {
+	suites          ports.ProfileTestSuiteLoader
+	profileLoader   ports.ProfileLoader
+	profileCompiler *services.ProfileCompiler
+	engines         ports.FixtureEngineFactory
+	logger          *slog.Logger
+}
+
+// NewProfileTestService creates a new profile test service.
+func NewProfileTestService(
+	suites ports.ProfileTestSuiteLoader,
+	profileLoader ports.ProfileLoader,
+	profileCompiler *services.ProfileCompiler,
+	engines ports.FixtureEngineFactory,
+	logger *slog.Logger,
+) *ProfileTestService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ProfileTestService{
+		suites:          suites,
+		profileLoader:   profileLoader,
+		profileCompiler: profileCompiler,
+		engines:         engines,
+		logger:          logger,
+	}
+}
+
+// Run discovers the suites under paths and runs every test case. Suites that
+// cannot be loaded are reported in the result rather than aborting the run.
+func (s *ProfileTestService) Run(ctx context.Context, paths []string) (*dto.ProfileTestReport, error) {
+	files, err := s.suites.DiscoverSuites(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no test suites (*_test.yaml) found in %s", strings.Join(paths, ", "))
+	}
+
+	report := &dto.ProfileTestReport{}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report.Suites = append(report.Suites, s.runSuite(ctx, file))
+	}
+	return report, nil
+}
+
+func (s *ProfileTestService) runSuite(ctx context.Context, path string) dto.ProfileTestSuiteResult {
+	result := dto.ProfileTestSuiteResult{Path: path}
+
+	suite, err := s.suites.LoadSuite(path)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Profile = suite.Profile
+	profilePath := suite.Profile
+	if !filepath.IsAbs(profilePath) {
+		profilePath = filepath.Join(filepath.Dir(path), profilePath)
+	}
+
+	profile, err := s.loadProfile(profilePath, suite.Environment)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	plugins := observedPlugins(profile)
+
+	for _, tc := range suite.Tests {
+		s.logger.Debug("running profile test", "suite", path, "test", tc.Name)
+		result.Cases = append(result.Cases, s.runCase(ctx, profile, plugins, tc))
+	}
+	return result
+}
+
+func (s *ProfileTestService) loadProfile(path, environment string) (*entities.ValidatedProfile, error) {
+	raw, err := s.profileLoader.LoadProfile(path, environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profile: %w", err)
+	}
+	profile, err := s.profileCompiler.Compile(raw)
+	if err != nil {
+		return nil, fmt.Errorf("profile compilation failed: %w", err)
+	}
+	return profile, nil
+}
+
+func (s *ProfileTestService) runCase(ctx context.Context, profile *entities.ValidatedProfile, plugins []string, tc dto.ProfileTestCase) dto.ProfileTestCaseResult {
+	result := dto.ProfileTestCaseResult{Name: tc.Name}
+
+	eng, err := s.engines.CreateFixtureEngine(tc.Fixtures, plugins)
+	if err != nil {
+		result.Failures = []string{err.Error()}
+		return result
+	}
+	defer func() { _ = eng.Close(ctx) }()
+
+	execResult, err := eng.Execute(ctx, profile)
+	if err != nil {
+		result.Failures = []string{fmt.Sprintf("execution failed: %v", err)}
+		return result
+	}
+
+	for _, exp := range tc.Expect {
+		result.Failures = append(result.Failures, checkExpectation(execResult, exp)...)
+	}
+	return result
+}
+
+// checkExpectation returns a failure message for each part of exp that does
+// not hold.
+func checkExpectation(result *execution.ExecutionResult, exp dto.ProfileTestExpectation) []string {
+	ctrl := result.GetControlResultByID(exp.Control)
+	if ctrl == nil {
+		return []string{fmt.Sprintf("control %s: not in profile", exp.Control)}
+	}
+
+	var failures []string
+	if exp.Status != "" && ctrl.Status != exp.Status {
+		failures = append(failures, fmt.Sprintf("control %s: status is %s, expected %s (message: %q)", exp.Control, ctrl.Status, exp.Status, ctrl.Message))
+	}
+	if exp.Message != "" && ctrl.Message != exp.Message {
+		failures = append(failures, fmt.Sprintf("control %s: message is %q, expected %q", exp.Control, ctrl.Message, exp.Message))
+	}
+	if exp.MessageContains != "" && !strings.Contains(ctrl.Message, exp.MessageContains) {
+		failures = append(failures, fmt.Sprintf("control %s: message %q does not contain %q", exp.Control, ctrl.Message, exp.MessageContains))
+	}
+	return failures
+}
+
+// observedPlugins returns the plugin names the profile's observations use.
+func observedPlugins(profile entities.ProfileReader) []string {
+	seen := make(map[string]bool)
+	var plugins []string
+	for _, ctrl := range profile.GetAllControls() {
+		for _, obs := range ctrl.ObservationDefinitions {
+			if !seen[obs.Plugin] {
+				seen[obs.Plugin] = true
+				plugins = append(plugins, obs.Plugin)
+			}
+		}
+	}
+	return plugins
+}
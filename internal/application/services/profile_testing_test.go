@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	domainservices "github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSuiteLoader struct {
+	suites map[string]*dto.ProfileTestSuite
+}
+
+func (l *fakeSuiteLoader) DiscoverSuites(paths []string) ([]string, error) {
+	return paths, nil
+}
+
+func (l *fakeSuiteLoader) LoadSuite(path string) (*dto.ProfileTestSuite, error) {
+	suite, ok := l.suites[path]
+	if !ok {
+		return nil, errors.New("failed to parse test suite")
+	}
+	suite.Path = path
+	return suite, nil
+}
+
+// fakeFixtureEngines builds engines that turn each fixture into the result of
+// the control it names: pass unless the fixture status is false.
+type fakeFixtureEngines struct {
+	plugins []string
+}
+
+func (f *fakeFixtureEngines) CreateFixtureEngine(fixtures []dto.ProfileTestFixture, plugins []string) (ports.ExecutionEngine, error) {
+	f.plugins = plugins
+	return fakeFixtureEngine(fixtures), nil
+}
+
+type fakeFixtureEngine []dto.ProfileTestFixture
+
+func (e fakeFixtureEngine) Execute(_ context.Context, _ entities.ProfileReader) (*execution.ExecutionResult, error) {
+	result := execution.NewExecutionResult("test", "1.0.0")
+	for _, f := range e {
+		cr := execution.ControlResult{ID: f.Control, Status: values.StatusPass, Message: "All checks passed"}
+		if f.Status != nil && !*f.Status {
+			cr.Status = values.StatusFail
+			cr.Message = "1 check failed"
+		}
+		result.AddControlResult(cr)
+	}
+	return result, nil
+}
+
+func (e fakeFixtureEngine) Close(_ context.Context) error {
+	return nil
+}
+
+func testedProfile(path string) (*entities.Profile, error) {
+	if path != "profiles/ssh.yaml" {
+		return nil, errors.New("no such profile: " + path)
+	}
+	observe := func(plugin string) []entities.ObservationDefinition {
+		return []entities.ObservationDefinition{{Plugin: plugin, Config: map[string]interface{}{"path": "/etc/ssh/sshd_config"}}}
+	}
+	return &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "ssh", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "exists", Name: "exists", ObservationDefinitions: observe("file")},
+			{ID: "readable", Name: "readable", ObservationDefinitions: observe("file")},
+			{ID: "listening", Name: "listening", ObservationDefinitions: observe("tcp")},
+		}},
+	}, nil
+}
+
+func TestProfileTestService_Run(t *testing.T) {
+	t.Parallel()
+
+	failing := false
+	suites := &fakeSuiteLoader{suites: map[string]*dto.ProfileTestSuite{
+		"profiles/ssh_test.yaml": {
+			Profile: "ssh.yaml",
+			Tests: []dto.ProfileTestCase{
+				{
+					Name:     "all pass",
+					Fixtures: []dto.ProfileTestFixture{{Control: "exists"}, {Control: "readable"}},
+					Expect: []dto.ProfileTestExpectation{
+						{Control: "exists", Status: values.StatusPass},
+						{Control: "readable", MessageContains: "passed"},
+					},
+				},
+				{
+					Name:     "wrong expectations",
+					Fixtures: []dto.ProfileTestFixture{{Control: "exists", Status: &failing}},
+					Expect: []dto.ProfileTestExpectation{
+						{Control: "exists", Status: values.StatusPass, Message: "All checks passed"},
+						{Control: "missing", Status: values.StatusPass},
+					},
+				},
+			},
+		},
+		"profiles/other_test.yaml": {Profile: "other.yaml", Tests: []dto.ProfileTestCase{{Name: "never runs"}}},
+	}}
+	engines := &fakeFixtureEngines{}
+	svc := NewProfileTestService(suites, profileLoaderFunc(testedProfile), domainservices.NewProfileCompiler(), engines, NewTestLogger())
+
+	report, err := svc.Run(context.Background(), []string{"profiles/ssh_test.yaml", "profiles/other_test.yaml", "profiles/bad_test.yaml"})
+	require.NoError(t, err)
+	require.Len(t, report.Suites, 3)
+
+	ssh := report.Suites[0]
+	assert.Empty(t, ssh.Error)
+	require.Len(t, ssh.Cases, 2)
+	assert.True(t, ssh.Cases[0].Passed())
+	assert.Equal(t, []string{
+		`control exists: status is fail, expected pass (message: "1 check failed")`,
+		`control exists: message is "1 check failed", expected "All checks passed"`,
+		"control missing: not in profile",
+	}, ssh.Cases[1].Failures)
+	assert.Equal(t, []string{"file", "tcp"}, engines.plugins)
+
+	assert.Contains(t, report.Suites[1].Error, "no such profile: profiles/other.yaml")
+	assert.Contains(t, report.Suites[2].Error, "failed to parse test suite")
+
+	passed, failed := report.Counts()
+	assert.Equal(t, 1, passed)
+	assert.Equal(t, 3, failed)
+}
@@ -1,9 +1,12 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 
 	"github.com/reglet-dev/reglet/internal/application/ports"
 	"github.com/reglet-dev/reglet/internal/domain/entities"
@@ -16,6 +19,8 @@ type RegistryPluginResolver struct {
 	services.BaseResolver
 	registry   ports.PluginRegistry
 	repository ports.PluginRepository
+	patches    ports.PluginPatchSource
+	patcher    ports.PluginPatcher
 	logger     *slog.Logger
 }
 
@@ -32,8 +37,19 @@ func NewRegistryPluginResolver(
 	}
 }
 
+// SetPatches enables delta updates: a plugin with an older version cached
+// is updated by patching that version when the registry publishes a patch.
+func (r *RegistryPluginResolver) SetPatches(patches ports.PluginPatchSource, patcher ports.PluginPatcher) {
+	r.patches = patches
+	r.patcher = patcher
+}
+
 // Resolve pulls from registry and caches.
 func (r *RegistryPluginResolver) Resolve(ctx context.Context, ref values.PluginReference) (*entities.Plugin, error) {
+	if plugin, ok := r.resolvePatched(ctx, ref); ok {
+		return plugin, nil
+	}
+
 	r.logger.Info("pulling plugin from registry", "ref", ref.String())
 
 	// Pull artifact from registry
@@ -57,3 +73,71 @@ func (r *RegistryPluginResolver) Resolve(ctx context.Context, ref values.PluginR
 
 	return artifact.Plugin, nil
 }
+
+// resolvePatched updates the newest cached version of the plugin to ref with
+// a patch from the registry. Patches that are missing, fail to apply or do
+// not produce the published digest are skipped, so the plugin is pulled in
+// full instead.
+func (r *RegistryPluginResolver) resolvePatched(ctx context.Context, ref values.PluginReference) (*entities.Plugin, bool) {
+	if r.patches == nil || r.patcher == nil {
+		return nil, false
+	}
+	base := r.patchBase(ctx, ref)
+	if base == nil {
+		return nil, false
+	}
+
+	patch, err := r.patches.PullPatch(ctx, ref, base.Digest())
+	if err != nil {
+		r.logger.Debug("no usable patch, pulling in full", "ref", ref.String(), "error", err)
+		return nil, false
+	}
+	if patch == nil {
+		return nil, false
+	}
+
+	_, basePath, err := r.repository.Find(ctx, base.Reference())
+	if err != nil {
+		return nil, false
+	}
+	baseWASM, err := os.ReadFile(filepath.Clean(basePath))
+	if err != nil {
+		return nil, false
+	}
+	patched, err := r.patcher.Apply(baseWASM, patch.Patch)
+	if err == nil {
+		err = patch.Plugin.Digest().Verify(patched)
+	}
+	if err != nil {
+		r.logger.Warn("patched plugin failed verification, pulling in full", "ref", ref.String(), "base", base.Reference().Version(), "error", err)
+		return nil, false
+	}
+
+	if _, err := r.repository.Store(ctx, patch.Plugin, bytes.NewReader(patched)); err != nil {
+		r.logger.Warn("failed to cache patched plugin, pulling in full", "ref", ref.String(), "error", err)
+		return nil, false
+	}
+	r.logger.Info("plugin updated from patch", "ref", ref.String(), "base", base.Reference().Version(), "patch_bytes", len(patch.Patch))
+	return patch.Plugin, true
+}
+
+// patchBase returns the newest cached version of ref's plugin other than
+// ref itself, or nil if there is none.
+func (r *RegistryPluginResolver) patchBase(ctx context.Context, ref values.PluginReference) *entities.Plugin {
+	cached, err := r.repository.List(ctx)
+	if err != nil {
+		return nil
+	}
+	repo := pluginRepository(ref.String())
+	var base *entities.Plugin
+	for _, plugin := range cached {
+		version := plugin.Reference().Version()
+		if pluginRepository(plugin.Reference().String()) != repo || version == ref.Version() {
+			continue
+		}
+		if base == nil || newerVersion(version, base.Reference().Version()) {
+			base = plugin
+		}
+	}
+	return base
+}
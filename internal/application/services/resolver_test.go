@@ -3,6 +3,9 @@ package services
 import (
 	"context"
 	"errors"
+	"io"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/reglet-dev/reglet/internal/application/dto"
@@ -133,3 +136,95 @@ func TestRegistryPluginResolver(t *testing.T) {
 		}
 	})
 }
+
+// fakePatchSource serves one patch, counting requests.
+type fakePatchSource struct {
+	patch    *dto.PluginPatchDTO
+	requests int
+}
+
+func (f *fakePatchSource) PullPatch(_ context.Context, _ values.PluginReference, _ values.Digest) (*dto.PluginPatchDTO, error) {
+	f.requests++
+	return f.patch, nil
+}
+
+// replacePatcher applies a patch by replacing the base with it.
+type replacePatcher struct{}
+
+func (replacePatcher) Apply(_, patch []byte) ([]byte, error) {
+	return patch, nil
+}
+
+func TestRegistryPluginResolver_Patches(t *testing.T) {
+	ctx := context.Background()
+	logger := NewTestLogger()
+
+	newPatch := func(t *testing.T, cache *dirRepository, wasm string) *dto.PluginPatchDTO {
+		t.Helper()
+		target := cache.add(t, "file", "1.1.0")
+		if err := cache.Delete(ctx, target.Reference()); err != nil {
+			t.Fatal(err)
+		}
+		return &dto.PluginPatchDTO{Plugin: target, Patch: []byte(wasm)}
+	}
+
+	t.Run("PatchesNewestCachedVersion", func(t *testing.T) {
+		cache := newDirRepository(t)
+		cache.add(t, "file", "1.0.0")
+		patch := newPatch(t, cache, "wasm file 1.1.0")
+		registry := &MockRegistry{PullErr: errors.New("full pull not expected")}
+
+		resolver := NewRegistryPluginResolver(registry, cache, logger)
+		resolver.SetPatches(&fakePatchSource{patch: patch}, replacePatcher{})
+
+		got, err := resolver.Resolve(ctx, patch.Plugin.Reference())
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if got != patch.Plugin {
+			t.Error("expected patched plugin")
+		}
+		if _, _, err := cache.Find(ctx, patch.Plugin.Reference()); err != nil {
+			t.Errorf("patched plugin not cached: %v", err)
+		}
+	})
+
+	t.Run("FallsBackOnDigestMismatch", func(t *testing.T) {
+		cache := newDirRepository(t)
+		cache.add(t, "file", "1.0.0")
+		patch := newPatch(t, cache, "corrupt")
+		full := io.NopCloser(strings.NewReader("wasm file 1.1.0"))
+		registry := &MockRegistry{PullArtifact: dto.NewPluginArtifactDTO(patch.Plugin, full)}
+
+		resolver := NewRegistryPluginResolver(registry, cache, logger)
+		resolver.SetPatches(&fakePatchSource{patch: patch}, replacePatcher{})
+
+		if _, err := resolver.Resolve(ctx, patch.Plugin.Reference()); err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		_, path, err := cache.Find(ctx, patch.Plugin.Reference())
+		if err != nil {
+			t.Fatalf("plugin not cached: %v", err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != "wasm file 1.1.0" {
+			t.Errorf("expected plugin pulled in full, cached %q", data)
+		}
+	})
+
+	t.Run("NoCachedBase", func(t *testing.T) {
+		source := &fakePatchSource{}
+		ref := values.NewPluginReference("ghcr.io", "reglet-dev", "reglet-plugins", "file", "1.1.0")
+		plugin := entities.NewPlugin(ref, values.Digest{}, values.PluginMetadata{})
+		registry := &MockRegistry{PullArtifact: dto.NewPluginArtifactDTO(plugin, io.NopCloser(strings.NewReader("wasm")))}
+
+		resolver := NewRegistryPluginResolver(registry, newDirRepository(t), logger)
+		resolver.SetPatches(source, replacePatcher{})
+
+		if _, err := resolver.Resolve(ctx, ref); err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if source.requests != 0 {
+			t.Error("no patch should be requested without a cached base")
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// ResultAnonymizationService prepares results for sharing outside the
+// organization: identifying values are replaced with pseudonyms or masks,
+// and the original value of every pseudonym is kept in a local store for
+// internal traceability. Stored results are never modified.
+type ResultAnonymizationService struct {
+	anonymizer ports.ResultAnonymizer
+	store      ports.PseudonymStore
+	loader     ports.ExecutionResultLoader
+	repo       repositories.ExecutionResultRepository
+}
+
+// NewResultAnonymizationService creates a service anonymizing results with
+// anonymizer and recording pseudonyms in store.
+func NewResultAnonymizationService(anonymizer ports.ResultAnonymizer, store ports.PseudonymStore, loader ports.ExecutionResultLoader) *ResultAnonymizationService {
+	return &ResultAnonymizationService{
+		anonymizer: anonymizer,
+		store:      store,
+		loader:     loader,
+	}
+}
+
+// SetResultRepository lets requests select stored runs.
+func (s *ResultAnonymizationService) SetResultRepository(repo repositories.ExecutionResultRepository) {
+	s.repo = repo
+}
+
+// Anonymize returns an anonymized copy of the result selected by req. The
+// pseudonyms are recorded before the copy is returned, so a shared report
+// can always be traced back.
+func (s *ResultAnonymizationService) Anonymize(ctx context.Context, req dto.AnonymizeResultRequest) (*execution.ExecutionResult, error) {
+	result, err := s.load(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	anonymized, pseudonyms, err := s.anonymizer.Anonymize(result)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Add(pseudonyms); err != nil {
+		return nil, fmt.Errorf("failed to record pseudonyms: %w", err)
+	}
+	return anonymized, nil
+}
+
+func (s *ResultAnonymizationService) load(ctx context.Context, req dto.AnonymizeResultRequest) (*execution.ExecutionResult, error) {
+	switch {
+	case (req.RunID == "") == (req.ResultPath == ""):
+		return nil, errors.New("select either a stored run or a result file")
+	case req.ResultPath != "":
+		return s.loader.LoadResult(req.ResultPath)
+	case s.repo == nil:
+		return nil, errors.New("no result storage configured: set storage.backend in the system config")
+	}
+
+	id, err := values.ParseExecutionID(req.RunID)
+	if err != nil {
+		return nil, fmt.Errorf("run %q: %w", req.RunID, err)
+	}
+	return s.repo.FindByID(ctx, id.UUID())
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAnonymizer replaces control messages with a fixed pseudonym.
+type fakeAnonymizer struct{}
+
+func (fakeAnonymizer) Anonymize(result *execution.ExecutionResult) (*execution.ExecutionResult, map[string]string, error) {
+	out := execution.NewExecutionResult(result.ProfileName, result.ProfileVersion)
+	pseudonyms := make(map[string]string)
+	for _, ctrl := range result.Controls {
+		pseudonyms["host-1"] = ctrl.Message
+		ctrl.Message = "host-1"
+		out.AddControlResult(ctrl)
+	}
+	return out, pseudonyms, nil
+}
+
+type fakePseudonymStore map[string]string
+
+func (s fakePseudonymStore) Add(pseudonyms map[string]string) error {
+	for k, v := range pseudonyms {
+		s[k] = v
+	}
+	return nil
+}
+
+func TestResultAnonymizationService_Anonymize(t *testing.T) {
+	t.Parallel()
+
+	source := execution.NewExecutionResult("web", "1.0.0")
+	source.AddControlResult(execution.ControlResult{ID: "tls", Status: values.StatusFail, Message: "web01.corp.example.com"})
+	store := fakePseudonymStore{}
+	svc := NewResultAnonymizationService(fakeAnonymizer{}, store, fakeResultLoader{"result.json": source})
+
+	result, err := svc.Anonymize(context.Background(), dto.AnonymizeResultRequest{ResultPath: "result.json"})
+	require.NoError(t, err)
+	assert.Equal(t, "host-1", result.Controls[0].Message)
+	assert.Equal(t, "web01.corp.example.com", source.Controls[0].Message, "source is not modified")
+	assert.Equal(t, fakePseudonymStore{"host-1": "web01.corp.example.com"}, store)
+
+	_, err = svc.Anonymize(context.Background(), dto.AnonymizeResultRequest{RunID: source.GetID().String()})
+	assert.ErrorContains(t, err, "no result storage configured")
+
+	svc.SetResultRepository(newFakeResultRepository(source))
+	result, err = svc.Anonymize(context.Background(), dto.AnonymizeResultRequest{RunID: source.GetID().String()})
+	require.NoError(t, err)
+	assert.Equal(t, "tls", result.Controls[0].ID)
+
+	_, err = svc.Anonymize(context.Background(), dto.AnonymizeResultRequest{RunID: "x", ResultPath: "result.json"})
+	assert.ErrorContains(t, err, "either a stored run or a result file")
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+// ResultExportService sends finalized execution results to the exporters
+// selected by the user.
+type ResultExportService struct {
+	resolver ports.ResultExporterResolver
+	logger   *slog.Logger
+}
+
+// NewResultExportService creates a new ResultExportService.
+func NewResultExportService(resolver ports.ResultExporterResolver, logger *slog.Logger) *ResultExportService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ResultExportService{
+		resolver: resolver,
+		logger:   logger,
+	}
+}
+
+// Export runs each named exporter against the result. Exporters that accept
+// settings receive the integrations section of the same name. A failing
+// exporter does not stop the others; all failures are returned together.
+func (s *ResultExportService) Export(
+	ctx context.Context,
+	result *execution.ExecutionResult,
+	names []string,
+	integrations map[string]map[string]interface{},
+) error {
+	var errs []error
+	for _, name := range names {
+		exporter, err := s.resolve(ctx, name, integrations[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("exporter %s: %w", name, err))
+			continue
+		}
+
+		s.logger.Info("exporting results", "exporter", exporter.Name())
+		if err := exporter.Export(ctx, result); err != nil {
+			errs = append(errs, fmt.Errorf("exporter %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolve looks up an exporter and applies its integration settings.
+func (s *ResultExportService) resolve(ctx context.Context, name string, config map[string]interface{}) (ports.ResultExporter, error) {
+	exporter, err := s.resolver.Resolve(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	configurable, ok := exporter.(ports.ConfigurableExporter)
+	if !ok {
+		if config != nil {
+			s.logger.Warn("exporter does not take settings, ignoring integrations section", "exporter", name)
+		}
+		return exporter, nil
+	}
+	return configurable.WithConfig(config)
+}
+
+// Close releases resources held by the exporter resolver.
+func (s *ResultExportService) Close(ctx context.Context) error {
+	return s.resolver.Close(ctx)
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingExporter struct {
+	err      error
+	name     string
+	exported []*execution.ExecutionResult
+}
+
+func (e *recordingExporter) Name() string { return e.name }
+
+func (e *recordingExporter) Export(_ context.Context, result *execution.ExecutionResult) error {
+	e.exported = append(e.exported, result)
+	return e.err
+}
+
+type configurableExporter struct {
+	recordingExporter
+	config map[string]interface{}
+}
+
+func (e *configurableExporter) WithConfig(config map[string]interface{}) (ports.ResultExporter, error) {
+	if config["url"] == nil {
+		return nil, errors.New("url is required")
+	}
+	return &configurableExporter{recordingExporter: e.recordingExporter, config: config}, nil
+}
+
+type mapExporterResolver struct {
+	exporters map[string]ports.ResultExporter
+	closed    bool
+}
+
+func (r *mapExporterResolver) Resolve(_ context.Context, name string) (ports.ResultExporter, error) {
+	e, ok := r.exporters[name]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return e, nil
+}
+
+func (r *mapExporterResolver) Close(_ context.Context) error {
+	r.closed = true
+	return nil
+}
+
+func TestResultExportService_Export(t *testing.T) {
+	t.Parallel()
+
+	ok := &recordingExporter{name: "ok"}
+	failing := &recordingExporter{name: "failing", err: errors.New("sink unavailable")}
+	resolver := &mapExporterResolver{exporters: map[string]ports.ResultExporter{
+		"ok":      ok,
+		"failing": failing,
+	}}
+	svc := NewResultExportService(resolver, nil)
+	result := execution.NewExecutionResult("test-profile", "1.0.0")
+
+	err := svc.Export(context.Background(), result, []string{"failing", "missing", "ok"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exporter failing: sink unavailable")
+	assert.Contains(t, err.Error(), "exporter missing: not found")
+
+	// A failing exporter must not prevent the others from running
+	assert.Equal(t, []*execution.ExecutionResult{result}, ok.exported)
+	assert.Len(t, failing.exported, 1)
+
+	require.NoError(t, svc.Close(context.Background()))
+	assert.True(t, resolver.closed)
+}
+
+func TestResultExportService_ExportAppliesIntegrations(t *testing.T) {
+	t.Parallel()
+
+	resolver := &mapExporterResolver{exporters: map[string]ports.ResultExporter{
+		"jira": &configurableExporter{recordingExporter: recordingExporter{name: "jira"}},
+	}}
+	svc := NewResultExportService(resolver, nil)
+	result := execution.NewExecutionResult("test-profile", "1.0.0")
+
+	err := svc.Export(context.Background(), result, []string{"jira"}, nil)
+	assert.ErrorContains(t, err, "exporter jira: url is required")
+
+	err = svc.Export(context.Background(), result, []string{"jira"}, map[string]map[string]interface{}{
+		"jira": {"url": "https://jira.example.com"},
+	})
+	assert.NoError(t, err)
+}
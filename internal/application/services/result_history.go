@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// ResultHistoryService moves stored execution results between repositories
+// through result archives.
+type ResultHistoryService struct {
+	repo   repositories.ExecutionResultRepository
+	now    func() time.Time
+	source execution.ArchiveSource
+}
+
+// NewResultHistoryService creates a service over repo. Archives it exports
+// are marked as coming from source.
+func NewResultHistoryService(repo repositories.ExecutionResultRepository, source execution.ArchiveSource) *ResultHistoryService {
+	return &ResultHistoryService{
+		repo:   repo,
+		source: source,
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Find returns the stored result of a run.
+func (s *ResultHistoryService) Find(ctx context.Context, runID string) (*execution.ExecutionResult, error) {
+	id, err := values.ParseExecutionID(runID)
+	if err != nil {
+		return nil, fmt.Errorf("run %q: %w", runID, err)
+	}
+	return s.repo.FindByID(ctx, id.UUID())
+}
+
+// List returns one page of the stored results matching query, newest first.
+func (s *ResultHistoryService) List(ctx context.Context, query repositories.ResultQuery) (*repositories.ResultPage, error) {
+	return s.repo.List(ctx, query)
+}
+
+// Export archives the stored results of the given runs, in the given order.
+func (s *ResultHistoryService) Export(ctx context.Context, runIDs []string) (*execution.ResultArchive, error) {
+	archive := execution.NewResultArchive(s.source, s.now())
+	for _, runID := range runIDs {
+		result, err := s.Find(ctx, runID)
+		if err != nil {
+			return nil, err
+		}
+		if err := archive.Add(result); err != nil {
+			return nil, err
+		}
+	}
+	return archive, nil
+}
+
+// Import verifies an archive and saves its results as they were exported.
+// Results already stored unchanged are skipped, so importing an archive twice
+// is harmless. Import is all or nothing: if any result fails verification or
+// differs from the stored result with the same execution ID, nothing is
+// saved.
+func (s *ResultHistoryService) Import(ctx context.Context, archive *execution.ResultArchive) (*dto.ImportResultsReport, error) {
+	results, err := archive.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.ImportResultsReport{Source: archive.Source}
+	var pending []*execution.ExecutionResult
+	var conflicts []string
+	for _, result := range results {
+		stored, err := s.repo.FindByID(ctx, result.GetID().UUID())
+		switch {
+		case errors.Is(err, repositories.ErrResultNotFound):
+			pending = append(pending, result)
+			report.Imported = append(report.Imported, result.GetID().String())
+		case err != nil:
+			return nil, err
+		default:
+			same, err := sameResult(stored, result)
+			if err != nil {
+				return nil, err
+			}
+			if !same {
+				conflicts = append(conflicts, result.GetID().String())
+				continue
+			}
+			report.Skipped = append(report.Skipped, result.GetID().String())
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("%w: stored results differ from the archive for %s", repositories.ErrVersionConflict, strings.Join(conflicts, ", "))
+	}
+	if len(pending) > 0 {
+		if err := s.repo.SaveBatch(ctx, pending); err != nil {
+			return nil, fmt.Errorf("failed to save imported results: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// sameResult reports whether two results serialize identically.
+func sameResult(a, b *execution.ExecutionResult) (bool, error) {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return string(left) == string(right), nil
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResultRepository stores results by ID, without versioning.
+type fakeResultRepository struct {
+	results map[uuid.UUID]*execution.ExecutionResult
+	saves   int
+}
+
+func newFakeResultRepository(results ...*execution.ExecutionResult) *fakeResultRepository {
+	repo := &fakeResultRepository{results: make(map[uuid.UUID]*execution.ExecutionResult)}
+	for _, r := range results {
+		repo.results[r.GetID().UUID()] = r
+	}
+	return repo
+}
+
+func (r *fakeResultRepository) Save(ctx context.Context, result *execution.ExecutionResult) error {
+	return r.SaveBatch(ctx, []*execution.ExecutionResult{result})
+}
+
+func (r *fakeResultRepository) SaveBatch(_ context.Context, results []*execution.ExecutionResult) error {
+	r.saves++
+	for _, result := range results {
+		r.results[result.GetID().UUID()] = result
+	}
+	return nil
+}
+
+func (r *fakeResultRepository) FindByID(_ context.Context, id uuid.UUID) (*execution.ExecutionResult, error) {
+	result, ok := r.results[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", repositories.ErrResultNotFound, id)
+	}
+	return result, nil
+}
+
+func (r *fakeResultRepository) FindByProfile(context.Context, string, int) ([]*execution.ExecutionResult, error) {
+	return nil, nil
+}
+
+func (r *fakeResultRepository) FindBetween(context.Context, string, time.Time, time.Time) ([]*execution.ExecutionResult, error) {
+	return nil, nil
+}
+
+func (r *fakeResultRepository) List(context.Context, repositories.ResultQuery) (*repositories.ResultPage, error) {
+	return &repositories.ResultPage{}, nil
+}
+
+func storedRun(profile string) *execution.ExecutionResult {
+	result := execution.NewExecutionResult(profile, "1.0.0")
+	result.Environment = "prod"
+	result.Controls = []execution.ControlResult{{ID: "tls", Name: "TLS", Status: values.StatusPass}}
+	result.Finalize()
+	result.IncrementVersion()
+	return result
+}
+
+func TestResultHistoryService_ExportImport(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	run := storedRun("web")
+	source := execution.ArchiveSource{Hostname: "airgap-01", RegletVersion: "1.2.3"}
+	archive, err := NewResultHistoryService(newFakeResultRepository(run), source).Export(ctx, []string{run.GetID().String()})
+	require.NoError(t, err)
+	assert.Equal(t, source, archive.Source)
+	require.Len(t, archive.Results, 1)
+
+	central := newFakeResultRepository()
+	history := NewResultHistoryService(central, execution.ArchiveSource{})
+	report, err := history.Import(ctx, archive)
+	require.NoError(t, err)
+	assert.Equal(t, []string{run.GetID().String()}, report.Imported)
+	assert.Equal(t, source, report.Source)
+
+	imported, err := central.FindByID(ctx, run.GetID().UUID())
+	require.NoError(t, err)
+	assert.Equal(t, run.GetVersion(), imported.GetVersion(), "version is preserved")
+	assert.True(t, run.StartTime.Equal(imported.StartTime))
+	assert.Equal(t, "prod", imported.Environment)
+
+	// Importing again skips the stored run.
+	report, err = history.Import(ctx, archive)
+	require.NoError(t, err)
+	assert.Empty(t, report.Imported)
+	assert.Equal(t, []string{run.GetID().String()}, report.Skipped)
+	assert.Equal(t, 1, central.saves)
+}
+
+func TestResultHistoryService_ImportConflict(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	run := storedRun("web")
+	other := storedRun("web")
+	archive, err := NewResultHistoryService(newFakeResultRepository(run, other), execution.ArchiveSource{}).
+		Export(ctx, []string{other.GetID().String(), run.GetID().String()})
+	require.NoError(t, err)
+
+	changed := storedRun("web")
+	changed.ExecutionID = run.ExecutionID
+	changed.Environment = "qa"
+	central := newFakeResultRepository(changed)
+
+	_, err = NewResultHistoryService(central, execution.ArchiveSource{}).Import(ctx, archive)
+	require.ErrorIs(t, err, repositories.ErrVersionConflict)
+	assert.Contains(t, err.Error(), run.GetID().String())
+	assert.Zero(t, central.saves, "nothing is imported")
+}
+
+func TestResultHistoryService_ExportUnknownRun(t *testing.T) {
+	t.Parallel()
+
+	history := NewResultHistoryService(newFakeResultRepository(), execution.ArchiveSource{})
+
+	_, err := history.Export(context.Background(), []string{uuid.NewString()})
+	require.ErrorIs(t, err, repositories.ErrResultNotFound)
+
+	_, err = history.Export(context.Background(), []string{"latest"})
+	assert.ErrorContains(t, err, `run "latest"`)
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+)
+
+var (
+	// ErrUnknownProfile is returned when a run names a profile the server
+	// is not configured with.
+	ErrUnknownProfile = errors.New("unknown profile")
+
+	// ErrRunNotFound is returned when no run has the requested ID.
+	ErrRunNotFound = errors.New("run not found")
+)
+
+const (
+	// maxFinishedRuns bounds how many finished runs are remembered.
+	maxFinishedRuns = 100
+
+	// Queue limits used when RunQueueOptions leaves them zero.
+	defaultMaxConcurrentRuns = 4
+	defaultMaxRunsPerProfile = 1
+)
+
+// RunFunc executes a check and returns its result.
+type RunFunc func(ctx context.Context, req dto.CheckProfileRequest) (*execution.ExecutionResult, error)
+
+// RunService runs the profiles a server is configured with on request, in
+// the background, and tracks their progress. Only configured profiles can be
+// run, so callers never choose paths on the server. Runs belong to the
+// namespace they were requested in, which is also where their results are
+// stored.
+//
+// Requests are queued first in, first out. A run starts once fewer than the
+// configured number of runs are in progress, overall and for its profile, so
+// requests arriving together do not hammer the same targets. A request
+// identical to one still queued joins it instead of queueing again.
+type RunService struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	execute  RunFunc
+	logger   *slog.Logger
+	now      func() time.Time
+	profiles map[string]string
+	runs     map[string]*dto.RunStatus
+	running  map[string]int // Runs in progress by profile
+	queue    []string       // IDs of queued runs, oldest first
+	finished []string       // IDs of finished runs, oldest first
+	limits   dto.RunQueueOptions
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+}
+
+// NewRunService creates a service running the given profiles, keyed by name
+// and giving the profile path, with execute.
+func NewRunService(profiles map[string]string, execute RunFunc, limits dto.RunQueueOptions, logger *slog.Logger) *RunService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if limits.MaxConcurrent <= 0 {
+		limits.MaxConcurrent = defaultMaxConcurrentRuns
+	}
+	if limits.MaxPerProfile <= 0 {
+		limits.MaxPerProfile = defaultMaxRunsPerProfile
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RunService{
+		ctx:      ctx,
+		cancel:   cancel,
+		execute:  execute,
+		logger:   logger,
+		now:      func() time.Time { return time.Now().UTC() },
+		profiles: profiles,
+		runs:     make(map[string]*dto.RunStatus),
+		running:  make(map[string]int),
+		limits:   limits,
+	}
+}
+
+// Profiles returns the names of the profiles that can be run, sorted.
+func (s *RunService) Profiles() []string {
+	names := make([]string, 0, len(s.profiles))
+	for name := range s.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Trigger queues a run in the namespace of ctx and returns its status. If an
+// identical request is already queued, its run is returned instead and
+// queued is false. The run outlives ctx; it ends when it completes or the
+// service is closed.
+func (s *RunService) Trigger(ctx context.Context, req dto.RunRequest) (run *dto.RunStatus, queued bool, err error) {
+	if _, ok := s.profiles[req.Profile]; !ok {
+		return nil, false, fmt.Errorf("%w: %q", ErrUnknownProfile, req.Profile)
+	}
+	namespace := repositories.NamespaceFromContext(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return nil, false, errors.New("run service is closed")
+	}
+	for _, id := range s.queue {
+		pending := s.runs[id]
+		if pending.Profile == req.Profile && pending.Environment == req.Environment && pending.Namespace == namespace {
+			s.logger.Debug("run request joined a queued run", "run", id, "requested_by", req.RequestedBy)
+			return s.snapshot(pending), false, nil
+		}
+	}
+
+	pending := &dto.RunStatus{
+		ID:          uuid.NewString(),
+		Profile:     req.Profile,
+		Environment: req.Environment,
+		Namespace:   namespace,
+		RequestedBy: req.RequestedBy,
+		State:       dto.RunStateQueued,
+		CreatedAt:   s.now(),
+	}
+	s.runs[pending.ID] = pending
+	s.queue = append(s.queue, pending.ID)
+	s.logger.Info("run queued", "run", pending.ID, "profile", req.Profile, "namespace", namespace, "requested_by", req.RequestedBy)
+
+	s.dispatch()
+	return s.snapshot(pending), true, nil
+}
+
+// Schedule requests a run every schedule.Interval, in schedule.Namespace,
+// until the service is closed. The first request is made one interval from
+// now. A request made while the previous one is still queued joins it, so a
+// slow profile does not pile up runs.
+func (s *RunService) Schedule(schedule dto.RunSchedule) error {
+	if _, ok := s.profiles[schedule.Profile]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownProfile, schedule.Profile)
+	}
+	if schedule.Interval <= 0 {
+		return fmt.Errorf("schedule of %q: interval must be positive", schedule.Profile)
+	}
+	namespace := schedule.Namespace
+	if namespace == "" {
+		namespace = repositories.DefaultNamespace
+	}
+	if err := repositories.ValidateNamespace(namespace); err != nil {
+		return fmt.Errorf("schedule of %q: %w", schedule.Profile, err)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(schedule.Interval)
+		defer ticker.Stop()
+		ctx := repositories.WithNamespace(s.ctx, namespace)
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			req := dto.RunRequest{Profile: schedule.Profile, Environment: schedule.Environment, RequestedBy: "schedule"}
+			if _, _, err := s.Trigger(ctx, req); err != nil && s.ctx.Err() == nil {
+				s.logger.Error("scheduled run request failed", "profile", schedule.Profile, "error", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// dispatch starts the queued runs that fit within the limits, oldest first.
+// A run whose profile is at its limit does not hold back runs of other
+// profiles queued behind it. Callers hold s.mu.
+func (s *RunService) dispatch() {
+	total := 0
+	for _, n := range s.running {
+		total += n
+	}
+	for i := 0; i < len(s.queue) && total < s.limits.MaxConcurrent; {
+		run := s.runs[s.queue[i]]
+		if s.running[run.Profile] >= s.limits.MaxPerProfile {
+			i++
+			continue
+		}
+		s.queue = slices.Delete(s.queue, i, i+1)
+		s.running[run.Profile]++
+		total++
+		run.State = dto.RunStateRunning
+		run.StartedAt = s.now()
+		s.start(run)
+	}
+}
+
+// start executes a run in the background. Callers hold s.mu.
+func (s *RunService) start(run *dto.RunStatus) {
+	s.logger.Info("run started", "run", run.ID, "profile", run.Profile, "namespace", run.Namespace)
+	req := dto.CheckProfileRequest{
+		ProfilePath: s.profiles[run.Profile],
+		Environment: run.Environment,
+		Metadata:    dto.RequestMetadata{RequestID: run.ID},
+	}
+	ctx := repositories.WithNamespace(s.ctx, run.Namespace)
+	id := run.ID
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		result, err := s.execute(ctx, req)
+		s.finish(id, result, err)
+	}()
+}
+
+// finish records the outcome of a run and starts the runs it made room for.
+func (s *RunService) finish(id string, result *execution.ExecutionResult, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run := s.runs[id]
+	s.running[run.Profile]--
+	if s.running[run.Profile] == 0 {
+		delete(s.running, run.Profile)
+	}
+	if err != nil {
+		s.logger.Error("run failed", "run", id, "profile", run.Profile, "error", err)
+		s.retire(run, dto.RunStateFailed, err.Error())
+	} else {
+		run.ExecutionID = result.GetID().String()
+		s.logger.Info("run completed", "run", id, "profile", run.Profile, "execution_id", run.ExecutionID)
+		s.retire(run, dto.RunStateCompleted, "")
+	}
+
+	if s.ctx.Err() == nil {
+		s.dispatch()
+	}
+}
+
+// retire marks a run as finished, forgetting the oldest finished runs beyond
+// maxFinishedRuns. Callers hold s.mu.
+func (s *RunService) retire(run *dto.RunStatus, state, reason string) {
+	run.State = state
+	run.Error = reason
+	run.FinishedAt = s.now()
+
+	s.finished = append(s.finished, run.ID)
+	if len(s.finished) > maxFinishedRuns {
+		delete(s.runs, s.finished[0])
+		s.finished = slices.Delete(s.finished, 0, 1)
+	}
+}
+
+// snapshot copies a run, with its queue position. Callers hold s.mu.
+func (s *RunService) snapshot(run *dto.RunStatus) *dto.RunStatus {
+	status := *run
+	if run.State == dto.RunStateQueued {
+		status.Position = slices.Index(s.queue, run.ID) + 1
+	}
+	return &status
+}
+
+// Get returns a run of the namespace of ctx.
+func (s *RunService) Get(ctx context.Context, id string) (*dto.RunStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, ok := s.runs[id]
+	if !ok || run.Namespace != repositories.NamespaceFromContext(ctx) {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, id)
+	}
+	return s.snapshot(run), nil
+}
+
+// List returns the runs of the namespace of ctx, newest first. Finished
+// runs are forgotten once a hundred newer runs have finished.
+func (s *RunService) List(ctx context.Context) []dto.RunStatus {
+	namespace := repositories.NamespaceFromContext(ctx)
+
+	s.mu.Lock()
+	runs := make([]dto.RunStatus, 0, len(s.runs))
+	for _, run := range s.runs {
+		if run.Namespace == namespace {
+			runs = append(runs, *s.snapshot(run))
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].CreatedAt.Equal(runs[j].CreatedAt) {
+			return runs[i].CreatedAt.After(runs[j].CreatedAt)
+		}
+		return runs[i].ID > runs[j].ID
+	})
+	return runs
+}
+
+// Queue returns the state of the queue. Counts cover every namespace; the
+// runs listed are those of the namespace of ctx, running ones first, then
+// queued ones in the order they will start.
+func (s *RunService) Queue(ctx context.Context) *dto.RunQueueStatus {
+	namespace := repositories.NamespaceFromContext(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := &dto.RunQueueStatus{
+		Queued:        len(s.queue),
+		MaxConcurrent: s.limits.MaxConcurrent,
+		MaxPerProfile: s.limits.MaxPerProfile,
+		Runs:          []dto.RunStatus{},
+	}
+	for _, n := range s.running {
+		status.Running += n
+	}
+
+	var running []dto.RunStatus
+	for _, run := range s.runs {
+		if run.State == dto.RunStateRunning && run.Namespace == namespace {
+			running = append(running, *run)
+		}
+	}
+	sort.Slice(running, func(i, j int) bool { return running[i].StartedAt.Before(running[j].StartedAt) })
+	status.Runs = append(status.Runs, running...)
+	for _, id := range s.queue {
+		if run := s.runs[id]; run.Namespace == namespace {
+			status.Runs = append(status.Runs, *s.snapshot(run))
+		}
+	}
+	return status
+}
+
+// Close stops schedules, drops queued runs, cancels the runs in progress
+// and waits for them to end.
+func (s *RunService) Close() {
+	s.mu.Lock()
+	s.cancel()
+	for _, id := range s.queue {
+		s.retire(s.runs[id], dto.RunStateFailed, "server shut down before the run started")
+	}
+	s.queue = nil
+	s.mu.Unlock()
+	s.wg.Wait()
+}
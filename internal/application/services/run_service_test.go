@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForRun polls until the run has finished.
+func waitForRun(t *testing.T, s *RunService, ctx context.Context, id string) *dto.RunStatus {
+	t.Helper()
+
+	var run *dto.RunStatus
+	require.Eventually(t, func() bool {
+		var err error
+		run, err = s.Get(ctx, id)
+		require.NoError(t, err)
+		return run.State != dto.RunStateRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	return run
+}
+
+func TestRunService_Trigger(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan dto.CheckProfileRequest, 1)
+	namespaces := make(chan string, 1)
+	result := execution.NewExecutionResult("web", "1.0.0")
+	s := NewRunService(map[string]string{"web": "/etc/reglet/web.yaml"}, func(ctx context.Context, req dto.CheckProfileRequest) (*execution.ExecutionResult, error) {
+		requests <- req
+		namespaces <- repositories.NamespaceFromContext(ctx)
+		return result, nil
+	}, dto.RunQueueOptions{}, nil)
+	defer s.Close()
+
+	teamA := repositories.WithNamespace(context.Background(), "team-a")
+	triggerCtx, cancel := context.WithCancel(teamA)
+	started, queued, err := s.Trigger(triggerCtx, dto.RunRequest{Profile: "web", Environment: "prod", RequestedBy: "alice"})
+	cancel() // the run outlives the request that triggered it
+	require.NoError(t, err)
+	assert.True(t, queued)
+	assert.Equal(t, dto.RunStateRunning, started.State, "an idle service starts the run at once")
+	assert.Equal(t, "team-a", started.Namespace)
+
+	req := <-requests
+	assert.Equal(t, "/etc/reglet/web.yaml", req.ProfilePath)
+	assert.Equal(t, "prod", req.Environment)
+	assert.Equal(t, "team-a", <-namespaces, "results are stored in the namespace of the request")
+
+	run := waitForRun(t, s, teamA, started.ID)
+	assert.Equal(t, dto.RunStateCompleted, run.State)
+	assert.Equal(t, result.GetID().String(), run.ExecutionID)
+	assert.Equal(t, "alice", run.RequestedBy)
+	assert.False(t, run.FinishedAt.IsZero())
+
+	// Runs are visible in their own namespace only.
+	_, err = s.Get(context.Background(), started.ID)
+	assert.ErrorIs(t, err, ErrRunNotFound)
+	assert.Empty(t, s.List(context.Background()))
+	assert.Len(t, s.List(teamA), 1)
+}
+
+func TestRunService_UnknownProfile(t *testing.T) {
+	t.Parallel()
+
+	s := NewRunService(map[string]string{"web": "web.yaml", "db": "db.yaml"}, nil, dto.RunQueueOptions{}, nil)
+	defer s.Close()
+
+	_, _, err := s.Trigger(context.Background(), dto.RunRequest{Profile: "../../etc/passwd"})
+	assert.ErrorIs(t, err, ErrUnknownProfile)
+	assert.Equal(t, []string{"db", "web"}, s.Profiles())
+}
+
+func TestRunService_FailedRun(t *testing.T) {
+	t.Parallel()
+
+	s := NewRunService(map[string]string{"web": "web.yaml"}, func(context.Context, dto.CheckProfileRequest) (*execution.ExecutionResult, error) {
+		return nil, errors.New("capability grant failed")
+	}, dto.RunQueueOptions{}, nil)
+	defer s.Close()
+
+	started, _, err := s.Trigger(context.Background(), dto.RunRequest{Profile: "web"})
+	require.NoError(t, err)
+
+	run := waitForRun(t, s, context.Background(), started.ID)
+	assert.Equal(t, dto.RunStateFailed, run.State)
+	assert.Equal(t, "capability grant failed", run.Error)
+	assert.Empty(t, run.ExecutionID)
+}
+
+func TestRunService_CloseCancelsRuns(t *testing.T) {
+	t.Parallel()
+
+	s := NewRunService(map[string]string{"web": "web.yaml"}, func(ctx context.Context, _ dto.CheckProfileRequest) (*execution.ExecutionResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, dto.RunQueueOptions{}, nil)
+
+	started, _, err := s.Trigger(context.Background(), dto.RunRequest{Profile: "web"})
+	require.NoError(t, err)
+	waiting, _, err := s.Trigger(context.Background(), dto.RunRequest{Profile: "web", Environment: "prod"})
+	require.NoError(t, err)
+	s.Close()
+
+	run, err := s.Get(context.Background(), started.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dto.RunStateFailed, run.State)
+	run, err = s.Get(context.Background(), waiting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dto.RunStateFailed, run.State, "queued runs are dropped")
+	assert.Contains(t, run.Error, "shut down")
+
+	_, _, err = s.Trigger(context.Background(), dto.RunRequest{Profile: "web"})
+	assert.Error(t, err, "a closed service starts no runs")
+}
+
+// gatedRuns executes runs that block until released, recording which
+// profiles are in progress.
+type gatedRuns struct {
+	started chan string
+	release chan struct{}
+}
+
+func newGatedRuns() *gatedRuns {
+	return &gatedRuns{started: make(chan string, 10), release: make(chan struct{})}
+}
+
+func (g *gatedRuns) run(ctx context.Context, req dto.CheckProfileRequest) (*execution.ExecutionResult, error) {
+	g.started <- req.ProfilePath
+	select {
+	case <-g.release:
+		return execution.NewExecutionResult(req.ProfilePath, "1.0.0"), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestRunService_QueueLimits(t *testing.T) {
+	t.Parallel()
+
+	runs := newGatedRuns()
+	s := NewRunService(map[string]string{"web": "web.yaml", "db": "db.yaml", "dns": "dns.yaml"}, runs.run,
+		dto.RunQueueOptions{MaxConcurrent: 2, MaxPerProfile: 1}, nil)
+	defer s.Close()
+	ctx := context.Background()
+
+	trigger := func(profile, env string) *dto.RunStatus {
+		run, queued, err := s.Trigger(ctx, dto.RunRequest{Profile: profile, Environment: env})
+		require.NoError(t, err)
+		require.True(t, queued)
+		return run
+	}
+	web1 := trigger("web", "prod")
+	web2 := trigger("web", "qa")
+	db := trigger("db", "")
+	dns := trigger("dns", "")
+
+	// web2 waits for its profile, but does not hold back db behind it.
+	assert.Equal(t, dto.RunStateRunning, web1.State)
+	assert.Equal(t, dto.RunStateQueued, web2.State)
+	assert.Equal(t, dto.RunStateRunning, db.State)
+	assert.Equal(t, dto.RunStateQueued, dns.State, "at the overall limit")
+	assert.Equal(t, 2, dns.Position)
+
+	queue := s.Queue(ctx)
+	assert.Equal(t, 2, queue.Running)
+	assert.Equal(t, 2, queue.Queued)
+	require.Len(t, queue.Runs, 4)
+	assert.Equal(t, []string{web2.ID, dns.ID}, []string{queue.Runs[2].ID, queue.Runs[3].ID})
+	assert.Equal(t, 1, queue.Runs[2].Position)
+
+	// Each finished run makes room for the oldest queued run that fits.
+	assert.ElementsMatch(t, []string{"web.yaml", "db.yaml"}, []string{<-runs.started, <-runs.started})
+	runs.release <- struct{}{}
+	next := <-runs.started
+	assert.Contains(t, []string{"web.yaml", "dns.yaml"}, next)
+
+	close(runs.release)
+	for _, run := range []*dto.RunStatus{web1, web2, db, dns} {
+		assert.Equal(t, dto.RunStateCompleted, waitForRun(t, s, ctx, run.ID).State)
+	}
+	assert.Zero(t, s.Queue(ctx).Running)
+}
+
+func TestRunService_Dedup(t *testing.T) {
+	t.Parallel()
+
+	runs := newGatedRuns()
+	s := NewRunService(map[string]string{"web": "web.yaml"}, runs.run, dto.RunQueueOptions{}, nil)
+	defer s.Close()
+	ctx := context.Background()
+	teamA := repositories.WithNamespace(ctx, "team-a")
+
+	running, _, err := s.Trigger(ctx, dto.RunRequest{Profile: "web", RequestedBy: "alice"})
+	require.NoError(t, err)
+	pending, queued, err := s.Trigger(ctx, dto.RunRequest{Profile: "web", RequestedBy: "bob"})
+	require.NoError(t, err)
+	assert.True(t, queued, "a running request does not absorb new ones: targets may have changed")
+	assert.NotEqual(t, running.ID, pending.ID)
+
+	joined, queued, err := s.Trigger(ctx, dto.RunRequest{Profile: "web", RequestedBy: "carol"})
+	require.NoError(t, err)
+	assert.False(t, queued)
+	assert.Equal(t, pending.ID, joined.ID)
+	assert.Equal(t, "bob", joined.RequestedBy)
+
+	_, queued, err = s.Trigger(ctx, dto.RunRequest{Profile: "web", Environment: "prod"})
+	require.NoError(t, err)
+	assert.True(t, queued, "another environment is another request")
+	_, queued, err = s.Trigger(teamA, dto.RunRequest{Profile: "web"})
+	require.NoError(t, err)
+	assert.True(t, queued, "another namespace is another request")
+
+	assert.Equal(t, 3, s.Queue(ctx).Queued)
+	close(runs.release)
+}
+
+func TestRunService_Schedule(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan string, 10)
+	s := NewRunService(map[string]string{"web": "web.yaml"}, func(ctx context.Context, _ dto.CheckProfileRequest) (*execution.ExecutionResult, error) {
+		select {
+		case requests <- repositories.NamespaceFromContext(ctx):
+		default: // the test has seen enough runs
+		}
+		return execution.NewExecutionResult("web", "1.0.0"), nil
+	}, dto.RunQueueOptions{}, nil)
+	defer s.Close()
+
+	require.NoError(t, s.Schedule(dto.RunSchedule{Profile: "web", Namespace: "team-a", Interval: 10 * time.Millisecond}))
+	assert.Equal(t, "team-a", <-requests)
+	assert.Equal(t, "team-a", <-requests)
+
+	runs := s.List(repositories.WithNamespace(context.Background(), "team-a"))
+	require.NotEmpty(t, runs)
+	assert.Equal(t, "schedule", runs[0].RequestedBy)
+
+	assert.ErrorIs(t, s.Schedule(dto.RunSchedule{Profile: "db", Interval: time.Hour}), ErrUnknownProfile)
+	assert.ErrorContains(t, s.Schedule(dto.RunSchedule{Profile: "web"}), "interval must be positive")
+	assert.ErrorIs(t, s.Schedule(dto.RunSchedule{Profile: "web", Namespace: "Team A", Interval: time.Hour}), repositories.ErrInvalidNamespace)
+}
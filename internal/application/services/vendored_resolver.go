@@ -0,0 +1,67 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// VendoredPluginResolver resolves registry plugins from the vendor directory
+// committed to a workspace, so runs need neither the registry nor a warm
+// cache. Vendored plugins are copied into the cache they are loaded from.
+type VendoredPluginResolver struct {
+	services.BaseResolver
+	vendor     ports.PluginRepository
+	repository ports.PluginRepository
+	logger     *slog.Logger
+}
+
+// NewVendoredPluginResolver creates a resolver reading plugins from vendor
+// and caching them in repository.
+func NewVendoredPluginResolver(
+	vendor ports.PluginRepository,
+	repository ports.PluginRepository,
+	logger *slog.Logger,
+) *VendoredPluginResolver {
+	return &VendoredPluginResolver{
+		vendor:     vendor,
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Resolve returns the vendored plugin, otherwise delegates to next.
+func (r *VendoredPluginResolver) Resolve(ctx context.Context, ref values.PluginReference) (*entities.Plugin, error) {
+	plugin, wasmPath, err := r.vendor.Find(ctx, ref)
+	if err != nil {
+		return r.ResolveNext(ctx, ref)
+	}
+
+	if cached, _, err := r.repository.Find(ctx, ref); err == nil && cached.Digest().Equals(plugin.Digest()) {
+		return cached, nil
+	}
+
+	// Vendored files are edited in repositories like any other; the binary
+	// must still match the digest it was vendored with
+	wasm, err := os.ReadFile(filepath.Clean(wasmPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vendored plugin %s: %w", ref.String(), err)
+	}
+	if err := plugin.Digest().Verify(wasm); err != nil {
+		return nil, fmt.Errorf("vendored plugin %s: %w", ref.String(), err)
+	}
+
+	if _, err := r.repository.Store(ctx, plugin, bytes.NewReader(wasm)); err != nil {
+		return nil, fmt.Errorf("cache storage failed: %w", err)
+	}
+	r.logger.Debug("using vendored plugin", "ref", ref.String())
+	return plugin, nil
+}
@@ -14,7 +14,11 @@ var (
 	}
 
 	// Shell interpreters that allow arbitrary command execution
-	dangerousShells = []string{"bash", "sh", "zsh", "fish", "/bin/bash", "/bin/sh"}
+	dangerousShells = []string{
+		"bash", "sh", "zsh", "fish", "/bin/bash", "/bin/sh",
+		"cmd", "cmd.exe", "powershell", "powershell.exe", "pwsh", "pwsh.exe",
+		`C:\Windows\System32\cmd.exe`, `C:\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`,
+	}
 
 	// Script interpreters that can execute arbitrary code via flags (-c, -e, etc.)
 	// Matches base + versioned variants (python3, python3.11, etc.)
@@ -196,9 +200,23 @@ Recommendation: Grant only specific variables:
 
 Recommendation: Grant only required variables individually`
 	}
+	if key, ok := strings.CutPrefix(c.Pattern, ObservationEnvPrefix); ok {
+		return "Plugin receives the profile's value of " + key + "; the host's value is not exposed"
+	}
 	return "Plugin can access environment variable: " + c.Pattern
 }
 
+// ObservationEnvPrefix marks an env capability that passes the value an
+// observation's env block sets for a key to that observation's invocation
+// only. Unlike env:KEY it never exposes the host's value of the key.
+const ObservationEnvPrefix = "observation:"
+
+// ObservationEnvCapability returns the capability to set key through an
+// observation's env block.
+func ObservationEnvCapability(key string) Capability {
+	return Capability{Kind: "env", Pattern: ObservationEnvPrefix + key}
+}
+
 // matchesAny checks if pattern exactly matches any string in the list
 func matchesAny(pattern string, list []string) bool {
 	for _, item := range list {
@@ -11,12 +11,19 @@ import (
 // Policy represents an authorization policy that determines if a requested operation is allowed.
 // This is a pure domain service.
 type Policy struct {
-	// TODO: Implement more sophisticated policy logic
+	symlinks SymlinkPolicy
 }
 
-// NewPolicy creates a new domain policy.
+// NewPolicy creates a new domain policy that restricts symlinks to the
+// granted tree.
 func NewPolicy() *Policy {
-	return &Policy{}
+	return &Policy{symlinks: SymlinkRestrict}
+}
+
+// NewPolicyWithSymlinks creates a new domain policy that handles symlinks in
+// filesystem paths as symlinks says.
+func NewPolicyWithSymlinks(symlinks SymlinkPolicy) *Policy {
+	return &Policy{symlinks: symlinks}
 }
 
 // IsGranted checks if a specific capability (request) is covered by any of the granted capabilities.
@@ -33,9 +40,9 @@ func (p *Policy) IsGranted(request Capability, granted []Capability, cwd string)
 		case "network":
 			matches = matchNetworkPattern(request.Pattern, grant.Pattern)
 		case "fs":
-			matches = matchFilesystemPattern(request.Pattern, grant.Pattern, cwd)
+			matches = matchFilesystemPattern(request.Pattern, grant.Pattern, cwd, p.symlinks)
 		case "env":
-			matches = MatchEnvironmentPattern(request.Pattern, grant.Pattern)
+			matches = matchEnvCapability(request.Pattern, grant.Pattern)
 		case "exec":
 			matches = matchExecPattern(request.Pattern, grant.Pattern)
 		default:
@@ -150,7 +157,8 @@ func parsePort(s string) (int, error) {
 // matchFilesystemPattern checks if a filesystem request matches a granted pattern.
 // The cwd parameter is used to resolve relative paths. If cwd is empty,
 // relative paths will fail to match (defaulting to a safe deny).
-func matchFilesystemPattern(requested, granted, cwd string) bool {
+// Symlinks in the requested path are handled according to symlinks.
+func matchFilesystemPattern(requested, granted, cwd string, symlinks SymlinkPolicy) bool {
 	reqParts := strings.SplitN(requested, ":", 2)
 	grantParts := strings.SplitN(granted, ":", 2)
 
@@ -161,21 +169,16 @@ func matchFilesystemPattern(requested, granted, cwd string) bool {
 		return false
 	}
 
-	reqPath := filepath.Clean(reqParts[1])
+	rawPath := reqParts[1] // as the kernel will see it, before ".." is cleaned away
 	grantPattern := grantParts[1]
 
-	if !filepath.IsAbs(reqPath) {
+	if !filepath.IsAbs(rawPath) {
 		if cwd == "" {
 			return false // No cwd provided, cannot resolve relative path
 		}
-		reqPath = filepath.Join(cwd, reqPath)
-		reqPath = filepath.Clean(reqPath)
-	}
-
-	realPath, err := filepath.EvalSymlinks(reqPath)
-	if err == nil {
-		reqPath = realPath
+		rawPath = cwd + string(filepath.Separator) + rawPath
 	}
+	reqPath := filepath.Clean(rawPath)
 
 	if !filepath.IsAbs(grantPattern) && !strings.Contains(grantPattern, "**") {
 		if cwd == "" {
@@ -185,6 +188,18 @@ func matchFilesystemPattern(requested, granted, cwd string) bool {
 		grantPattern = filepath.Clean(grantPattern)
 	}
 
+	switch symlinks {
+	case SymlinkFollow:
+		// The path is matched as written
+	case SymlinkNoFollow:
+		if hasSymlinkBelow(PatternRoot(grantPattern), rawPath) {
+			return false
+		}
+	default:
+		// Match where the links lead, even for files not created yet
+		reqPath = Canonicalize(rawPath)
+	}
+
 	if strings.Contains(grantPattern, "**") {
 		prefix := strings.TrimSuffix(grantPattern, "**")
 		prefix = filepath.Clean(prefix) + string(filepath.Separator)
@@ -223,6 +238,35 @@ func MatchEnvironmentPattern(requested, granted string) bool {
 	return requested == granted
 }
 
+// matchEnvCapability matches an env capability. A grant of the host's
+// variable also covers the observation-set value of the same key.
+func matchEnvCapability(requested, granted string) bool {
+	if MatchEnvironmentPattern(requested, granted) {
+		return true
+	}
+	key, ok := strings.CutPrefix(requested, ObservationEnvPrefix)
+	return ok && MatchEnvironmentPattern(key, granted)
+}
+
+// ObservationEnvGranted reports whether granted lets an observation's env
+// block set key for its plugin invocation: by an env:observation:KEY grant,
+// or by a grant of the host's variable.
+func ObservationEnvGranted(key string, granted []Capability) bool {
+	for _, grant := range granted {
+		if grant.Kind == "env" && matchEnvCapability(ObservationEnvPrefix+key, grant.Pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoaderEnvironmentVariable reports whether key controls how programs are
+// found and loaded (PATH, LD_*, DYLD_*). A profile must not set these: in a
+// process plugin they would run code outside the sandbox, whatever is granted.
+func LoaderEnvironmentVariable(key string) bool {
+	return key == "PATH" || strings.HasPrefix(key, "LD_") || strings.HasPrefix(key, "DYLD_")
+}
+
 func matchExecPattern(requested, granted string) bool {
 	if granted == "**" {
 		return true
@@ -91,7 +91,9 @@ func FuzzFilesystemPatternMatching(f *testing.F) {
 		cap := Capability{Kind: "fs", Pattern: pattern}
 		requestCap := Capability{Kind: "fs", Pattern: "read:/etc/passwd"}
 
-		_ = matchFilesystemPattern(requestCap.Pattern, cap.Pattern, "/tmp")
+		for _, symlinks := range []SymlinkPolicy{SymlinkRestrict, SymlinkFollow, SymlinkNoFollow} {
+			_ = matchFilesystemPattern(requestCap.Pattern, cap.Pattern, "/tmp", symlinks)
+		}
 	})
 }
 
@@ -179,6 +179,22 @@ func TestPolicy_IsGranted_Environment(t *testing.T) {
 			requested: Capability{Kind: "env", Pattern: "DB_PASSWORD"},
 			expected:  false,
 		},
+		{
+			name: "host grant covers observation value",
+			grants: []Capability{
+				{Kind: "env", Pattern: "LANG"},
+			},
+			requested: ObservationEnvCapability("LANG"),
+			expected:  true,
+		},
+		{
+			name: "observation grant does not expose host variable",
+			grants: []Capability{
+				ObservationEnvCapability("LANG"),
+			},
+			requested: Capability{Kind: "env", Pattern: "LANG"},
+			expected:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -188,6 +204,13 @@ func TestPolicy_IsGranted_Environment(t *testing.T) {
 	}
 }
 
+func TestObservationEnvGranted(t *testing.T) {
+	assert.True(t, ObservationEnvGranted("LANG", []Capability{ObservationEnvCapability("LANG")}))
+	assert.True(t, ObservationEnvGranted("PGSSLMODE", []Capability{{Kind: "env", Pattern: "PG*"}}))
+	assert.False(t, ObservationEnvGranted("PGSSLMODE", []Capability{ObservationEnvCapability("LANG")}))
+	assert.False(t, ObservationEnvGranted("LANG", []Capability{{Kind: "fs", Pattern: "observation:LANG"}}))
+}
+
 func TestPolicy_IsGranted_Exec(t *testing.T) {
 	policy := NewPolicy()
 
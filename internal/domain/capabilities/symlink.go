@@ -0,0 +1,144 @@
+package capabilities
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SymlinkPolicy decides how symbolic links are handled when a plugin's file
+// path is checked against its filesystem capabilities.
+type SymlinkPolicy string
+
+const (
+	// SymlinkRestrict follows links, but only to targets inside the granted
+	// tree: the path is checked after resolving every link in it. This is the
+	// default.
+	SymlinkRestrict SymlinkPolicy = "restrict-to-granted-tree"
+	// SymlinkFollow follows links anywhere: only the path as written is
+	// checked, so a link inside the granted tree can reach any host file.
+	SymlinkFollow SymlinkPolicy = "follow"
+	// SymlinkNoFollow refuses paths that go through a link inside the granted
+	// tree. Links above the tree, such as /tmp on macOS, are still followed.
+	SymlinkNoFollow SymlinkPolicy = "no-follow"
+)
+
+// ParseSymlinkPolicy parses a symlink policy name. An empty name is the
+// default, SymlinkRestrict.
+func ParseSymlinkPolicy(name string) (SymlinkPolicy, error) {
+	switch policy := SymlinkPolicy(name); policy {
+	case "":
+		return SymlinkRestrict, nil
+	case SymlinkRestrict, SymlinkFollow, SymlinkNoFollow:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("symlink_policy must be %s, %s or %s, got %q",
+			SymlinkRestrict, SymlinkFollow, SymlinkNoFollow, name)
+	}
+}
+
+// Permits reports whether the policy lets the absolute path be reached from
+// the granted directory root.
+func (p SymlinkPolicy) Permits(root, path string) bool {
+	root = filepath.Clean(root)
+	switch p {
+	case SymlinkFollow:
+		return withinDir(root, filepath.Clean(path))
+	case SymlinkNoFollow:
+		return withinDir(root, filepath.Clean(path)) && !hasSymlinkBelow(root, path)
+	default:
+		return withinDir(Canonicalize(root), Canonicalize(path))
+	}
+}
+
+// maxSymlinks bounds the links Canonicalize follows, like the kernel's ELOOP.
+const maxSymlinks = 255
+
+// Canonicalize resolves the absolute path the way the kernel does when it
+// opens it: each link is replaced by its target before the ".." components
+// after it apply, so "/srv/link/../x" names a file next to the link's target,
+// not /srv/x as filepath.Clean would have it. Dangling links are followed to
+// where they would create a file, and components that do not exist are
+// appended as written.
+func Canonicalize(path string) string {
+	sep := string(filepath.Separator)
+	resolved := sep
+	pending := strings.Split(path, sep)
+	links := 0
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, name)
+		info, err := os.Lstat(next)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 || links == maxSymlinks {
+			resolved = next
+			continue
+		}
+		target, err := os.Readlink(next)
+		if err != nil {
+			resolved = next
+			continue
+		}
+		links++
+		if filepath.IsAbs(target) {
+			resolved = sep
+		}
+		pending = append(strings.Split(target, sep), pending...)
+	}
+	return resolved
+}
+
+// hasSymlinkBelow reports whether the absolute path goes through a link
+// below root, walking its components as written so that a link followed by
+// ".." is still seen. Components that do not exist are not links.
+func hasSymlinkBelow(root, path string) bool {
+	dir := string(filepath.Separator)
+	for _, name := range strings.Split(path, string(filepath.Separator)) {
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			dir = filepath.Dir(dir)
+			continue
+		}
+		dir = filepath.Join(dir, name)
+		if dir == root || !withinDir(root, dir) {
+			continue
+		}
+		info, err := os.Lstat(dir)
+		if err != nil {
+			return false
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// withinDir reports whether path is dir or below it. Both must be clean.
+func withinDir(dir, path string) bool {
+	if dir == path || dir == string(filepath.Separator) {
+		return true
+	}
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// PatternRoot returns the leading directories of a filesystem path pattern
+// that hold no glob characters: the root of the tree the pattern grants.
+func PatternRoot(pattern string) string {
+	dir := filepath.Dir(pattern)
+	for strings.ContainsAny(dir, "*?[\\") {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
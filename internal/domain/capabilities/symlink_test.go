@@ -0,0 +1,131 @@
+package capabilities
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSymlinkPolicy(t *testing.T) {
+	for name, want := range map[string]SymlinkPolicy{
+		"":                         SymlinkRestrict,
+		"restrict-to-granted-tree": SymlinkRestrict,
+		"follow":                   SymlinkFollow,
+		"no-follow":                SymlinkNoFollow,
+	} {
+		got, err := ParseSymlinkPolicy(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, err := ParseSymlinkPolicy("nofollow")
+	assert.ErrorContains(t, err, `got "nofollow"`)
+}
+
+// symlinkTree creates a granted directory holding a file, a link to a file
+// outside it, a link to a directory outside it and a link to the file inside.
+func symlinkTree(t *testing.T) (granted, outside string) {
+	t.Helper()
+	base := t.TempDir()
+	granted = filepath.Join(base, "granted")
+	outside = filepath.Join(base, "outside")
+	require.NoError(t, os.MkdirAll(granted, 0o750))
+	require.NoError(t, os.MkdirAll(outside, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(granted, "file"), []byte("in"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("out"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "secret"), filepath.Join(granted, "escape")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(granted, "escape-dir")))
+	require.NoError(t, os.Symlink("file", filepath.Join(granted, "alias")))
+	return granted, outside
+}
+
+func TestPolicy_IsGranted_Symlinks(t *testing.T) {
+	granted, _ := symlinkTree(t)
+	grants := []Capability{{Kind: "fs", Pattern: "read:" + granted + "/**"}}
+
+	tests := []struct {
+		path                       string
+		restrict, follow, noFollow bool
+	}{
+		{path: "file", restrict: true, follow: true, noFollow: true},
+		{path: "alias", restrict: true, follow: true, noFollow: false},
+		{path: "escape", restrict: false, follow: true, noFollow: false},
+		{path: "escape-dir/secret", restrict: false, follow: true, noFollow: false},
+		{path: "escape-dir/new-file", restrict: false, follow: true, noFollow: false},
+		{path: "new-file", restrict: true, follow: true, noFollow: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			requested := Capability{Kind: "fs", Pattern: "read:" + filepath.Join(granted, tt.path)}
+			assert.Equal(t, tt.restrict, NewPolicy().IsGranted(requested, grants, ""), "restrict")
+			assert.Equal(t, tt.follow, NewPolicyWithSymlinks(SymlinkFollow).IsGranted(requested, grants, ""), "follow")
+			assert.Equal(t, tt.noFollow, NewPolicyWithSymlinks(SymlinkNoFollow).IsGranted(requested, grants, ""), "no-follow")
+		})
+	}
+}
+
+func TestSymlinkPolicy_Permits(t *testing.T) {
+	granted, outside := symlinkTree(t)
+
+	assert.True(t, SymlinkRestrict.Permits(granted, filepath.Join(granted, "alias")))
+	assert.False(t, SymlinkRestrict.Permits(granted, filepath.Join(granted, "escape-dir", "secret")))
+	assert.True(t, SymlinkFollow.Permits(granted, filepath.Join(granted, "escape-dir", "secret")))
+	assert.False(t, SymlinkNoFollow.Permits(granted, filepath.Join(granted, "alias")))
+	assert.True(t, SymlinkNoFollow.Permits(granted, filepath.Join(granted, "file")))
+
+	for _, policy := range []SymlinkPolicy{SymlinkRestrict, SymlinkFollow, SymlinkNoFollow} {
+		assert.False(t, policy.Permits(granted, filepath.Join(outside, "secret")), policy)
+		assert.False(t, policy.Permits(granted, granted+"-sibling"), policy)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	granted, outside := symlinkTree(t)
+	require.NoError(t, os.Symlink(filepath.Join(outside, "created"), filepath.Join(granted, "dangling")))
+	require.NoError(t, os.Symlink("loop", filepath.Join(granted, "loop")))
+
+	assert.Equal(t, filepath.Join(granted, "file"), Canonicalize(filepath.Join(granted, "alias")))
+	assert.Equal(t, filepath.Join(granted, "file"), Canonicalize(granted+"//./file"))
+	assert.Equal(t, filepath.Join(outside, "secret"), Canonicalize(filepath.Join(granted, "escape")))
+	assert.Equal(t, filepath.Join(outside, "new", "file"), Canonicalize(filepath.Join(granted, "escape-dir", "new", "file")))
+	assert.Equal(t, filepath.Join(outside, "created"), Canonicalize(filepath.Join(granted, "dangling")),
+		"a dangling link leads where a file would be created")
+	assert.Equal(t, filepath.Dir(outside)+"/granted", Canonicalize(filepath.Join(granted, "escape-dir")+"/../granted"),
+		".. applies to the link's target, not to the link")
+	assert.Equal(t, filepath.Join(granted, "loop"), Canonicalize(filepath.Join(granted, "loop")), "loops end")
+}
+
+func TestPolicy_IsGranted_TraversalThroughSymlink(t *testing.T) {
+	base := t.TempDir()
+	granted := filepath.Join(base, "srv", "granted")
+	require.NoError(t, os.MkdirAll(filepath.Join(granted, "sub"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "etc"), 0o750))
+	// granted/link/../secret looks like granted/secret, but the kernel
+	// resolves it to base/etc/secret
+	require.NoError(t, os.Symlink(filepath.Join(base, "etc", "deep"), filepath.Join(granted, "link")))
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "etc", "deep"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "etc", "secret"), []byte("out"), 0o600))
+
+	grants := []Capability{{Kind: "fs", Pattern: "read:" + granted + "/**"}}
+	requested := Capability{Kind: "fs", Pattern: "read:" + granted + "/link/../secret"}
+
+	assert.False(t, NewPolicy().IsGranted(requested, grants, ""))
+	assert.False(t, NewPolicyWithSymlinks(SymlinkNoFollow).IsGranted(requested, grants, ""))
+	assert.False(t, SymlinkRestrict.Permits(granted, granted+"/link/../secret"))
+	assert.False(t, SymlinkNoFollow.Permits(granted, granted+"/link/../secret"))
+
+	// A plain .. that stays in the tree is still fine
+	assert.True(t, NewPolicy().IsGranted(Capability{Kind: "fs", Pattern: "read:" + granted + "/sub/../sub"}, grants, ""))
+}
+
+func TestPatternRoot(t *testing.T) {
+	assert.Equal(t, "/etc", PatternRoot("/etc/**"))
+	assert.Equal(t, "/etc", PatternRoot("/etc/hosts"))
+	assert.Equal(t, "/var/log", PatternRoot("/var/log/*.log"))
+	assert.Equal(t, "/home", PatternRoot("/home/*/.ssh/**"))
+	assert.Equal(t, "/", PatternRoot("/**"))
+}
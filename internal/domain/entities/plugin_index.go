@@ -0,0 +1,133 @@
+package entities
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// PluginIndex is the manifest of a plugin registry: the plugins it offers by
+// namespace-qualified name ("reglet/http"), with the source and digest of
+// each release.
+type PluginIndex struct {
+	Plugins map[string]IndexedPlugin `json:"plugins" yaml:"plugins"`
+}
+
+// IndexedPlugin is a plugin of a plugin index.
+type IndexedPlugin struct {
+	Description string                   `json:"description,omitempty" yaml:"description,omitempty"`
+	Releases    map[string]PluginRelease `json:"releases" yaml:"releases"` // by version
+}
+
+// PluginRelease is a release of a plugin of a plugin index.
+type PluginRelease struct {
+	// Source is an OCI reference or an HTTPS URL of the .wasm binary
+	Source string `json:"source" yaml:"source"`
+	// Digest is the SHA-256 of the .wasm binary ("sha256:<hex>")
+	Digest string `json:"digest" yaml:"digest"`
+}
+
+// IsURL reports whether the release is downloaded from an HTTPS URL rather
+// than pulled from an OCI registry.
+func (r PluginRelease) IsURL() bool {
+	return strings.HasPrefix(r.Source, "https://")
+}
+
+// Validate checks the names, versions, sources and digests of the index.
+func (i *PluginIndex) Validate() error {
+	for _, name := range i.Names() {
+		namespace, short, ok := strings.Cut(name, "/")
+		if !ok || !isPluginName(namespace) || !isPluginName(short) {
+			return fmt.Errorf("plugin index: %q is not a namespace-qualified plugin name such as reglet/http", name)
+		}
+		plugin := i.Plugins[name]
+		if len(plugin.Releases) == 0 {
+			return fmt.Errorf("plugin index: %s has no releases", name)
+		}
+		for version, release := range plugin.Releases {
+			if _, err := semver.NewVersion(version); err != nil {
+				return fmt.Errorf("plugin index: %s %s: invalid version: %w", name, version, err)
+			}
+			if err := release.validate(); err != nil {
+				return fmt.Errorf("plugin index: %s %s: %w", name, version, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r PluginRelease) validate() error {
+	switch {
+	case r.IsURL():
+		if _, err := url.Parse(r.Source); err != nil {
+			return fmt.Errorf("invalid source: %w", err)
+		}
+	case strings.Contains(r.Source, "://"):
+		return fmt.Errorf("source %q must be an OCI reference or an https:// URL", r.Source)
+	default:
+		ref, err := values.ParsePluginReference(r.Source)
+		if err != nil || ref.IsEmbedded() {
+			return fmt.Errorf("source %q must be an OCI reference (registry/org/repo/name:version) or an https:// URL", r.Source)
+		}
+	}
+
+	digest, err := values.ParseDigest(r.Digest)
+	if err != nil || digest.Algorithm() != "sha256" || len(digest.Value()) != 64 {
+		return fmt.Errorf("digest %q must be sha256:<64 hex digits>", r.Digest)
+	}
+	return nil
+}
+
+// Names returns the plugin names of the index, sorted.
+func (i *PluginIndex) Names() []string {
+	names := make([]string, 0, len(i.Plugins))
+	for name := range i.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the namespace-qualified name and the highest release of
+// the plugin a declaration such as "reglet/http@1.2" selects. A plugin
+// declared by its short name must be offered under exactly one namespace.
+func (i *PluginIndex) Resolve(spec *PluginSpec) (string, string, PluginRelease, error) {
+	name := spec.Namespace + "/" + spec.PluginName()
+	if spec.Namespace == "" {
+		var matches []string
+		for _, candidate := range i.Names() {
+			if _, short, _ := strings.Cut(candidate, "/"); short == spec.PluginName() {
+				matches = append(matches, candidate)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return "", "", PluginRelease{}, fmt.Errorf("plugin %q is not in the plugin index", spec.PluginName())
+		case 1:
+			name = matches[0]
+		default:
+			return "", "", PluginRelease{}, fmt.Errorf("plugin %q is offered by several namespaces (%s); name its namespace, e.g. %q",
+				spec.PluginName(), strings.Join(matches, ", "), matches[0])
+		}
+	}
+
+	plugin, ok := i.Plugins[name]
+	if !ok {
+		return "", "", PluginRelease{}, fmt.Errorf("plugin %q is not in the plugin index", name)
+	}
+	versions := make([]string, 0, len(plugin.Releases))
+	for version := range plugin.Releases {
+		versions = append(versions, version)
+	}
+	version, ok := spec.SelectVersion(versions)
+	if !ok {
+		sort.Strings(versions)
+		return "", "", PluginRelease{}, fmt.Errorf("plugin %q has no release matching %s (available: %s)",
+			name, spec.Version, strings.Join(versions, ", "))
+	}
+	return name, version, plugin.Releases[version], nil
+}
@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDigest = "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func testIndex() *PluginIndex {
+	return &PluginIndex{Plugins: map[string]IndexedPlugin{
+		"reglet/http": {Releases: map[string]PluginRelease{
+			"1.1.0": {Source: "ghcr.io/reglet-dev/plugins/http:1.1.0", Digest: testDigest},
+			"1.2.0": {Source: "ghcr.io/reglet-dev/plugins/http:1.2.0", Digest: testDigest},
+			"2.0.0": {Source: "https://plugins.example.com/http-2.0.0.wasm", Digest: testDigest},
+		}},
+		"reglet/file": {Releases: map[string]PluginRelease{
+			"1.0.0": {Source: "ghcr.io/reglet-dev/plugins/file:1.0.0", Digest: testDigest},
+		}},
+		"acme/file": {Releases: map[string]PluginRelease{
+			"0.3.0": {Source: "https://plugins.acme.example/file.wasm", Digest: testDigest},
+		}},
+	}}
+}
+
+func TestPluginIndex_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, testIndex().Validate())
+
+	tests := []struct {
+		name    string
+		plugin  string
+		version string
+		release PluginRelease
+		wantErr string
+	}{
+		{"unqualified name", "http", "1.0.0", PluginRelease{Source: "ghcr.io/reglet-dev/plugins/http:1.0.0", Digest: testDigest}, "namespace-qualified"},
+		{"invalid version", "reglet/http", "one", PluginRelease{Source: "ghcr.io/reglet-dev/plugins/http:1.0.0", Digest: testDigest}, "invalid version"},
+		{"plain HTTP source", "reglet/http", "1.0.0", PluginRelease{Source: "http://example.com/http.wasm", Digest: testDigest}, "https:// URL"},
+		{"short digest", "reglet/http", "1.0.0", PluginRelease{Source: "ghcr.io/reglet-dev/plugins/http:1.0.0", Digest: "sha256:abc"}, "64 hex digits"},
+		{"other algorithm", "reglet/http", "1.0.0", PluginRelease{Source: "ghcr.io/reglet-dev/plugins/http:1.0.0", Digest: "md5:abc"}, "sha256"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			index := &PluginIndex{Plugins: map[string]IndexedPlugin{
+				tt.plugin: {Releases: map[string]PluginRelease{tt.version: tt.release}},
+			}}
+			err := index.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPluginIndex_Resolve(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		declaration string
+		wantName    string
+		wantVersion string
+		wantErr     string
+	}{
+		{declaration: "reglet/http@1.2.0", wantName: "reglet/http", wantVersion: "1.2.0"},
+		{declaration: "reglet/http@1", wantName: "reglet/http", wantVersion: "1.2.0"},
+		{declaration: "reglet/http", wantName: "reglet/http", wantVersion: "2.0.0"},
+		{declaration: "http@1.1", wantName: "reglet/http", wantVersion: "1.1.0"},
+		{declaration: "file", wantErr: "several namespaces"},
+		{declaration: "acme/file", wantName: "acme/file", wantVersion: "0.3.0"},
+		{declaration: "reglet/http@3", wantErr: "no release matching"},
+		{declaration: "reglet/dns", wantErr: "not in the plugin index"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.declaration, func(t *testing.T) {
+			t.Parallel()
+			spec, err := ParsePluginDeclaration(tt.declaration)
+			require.NoError(t, err)
+
+			name, version, release, err := testIndex().Resolve(spec)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantVersion, version)
+			assert.Equal(t, testIndex().Plugins[name].Releases[version], release)
+		})
+	}
+}
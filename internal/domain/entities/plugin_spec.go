@@ -3,7 +3,11 @@ package entities
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/reglet-dev/reglet/internal/domain/values"
 )
 
 // PluginSpec represents a plugin declaration with optional version and source.
@@ -14,6 +18,10 @@ type PluginSpec struct {
 	// Source is the plugin source (e.g., "file", "ghcr.io/reglet-dev/reglet-plugins/file:1.0.0")
 	Source string
 
+	// Namespace is the publisher of a namespace-qualified plugin (e.g., "acme"
+	// for "acme/customdb"), empty for plugins declared by their short name
+	Namespace string
+
 	// Version is the explicit version constraint (e.g., "1.2.0")
 	Version string
 
@@ -62,6 +70,43 @@ func (ps *PluginSpec) PluginName() string {
 	return source
 }
 
+// AcceptsVersion reports whether a plugin version satisfies the declared
+// version. A partial version accepts its releases ("1.0" accepts 1.0.3), and
+// constraints such as "^1.2" are honored. Declarations without a version, or
+// pinned to "latest", accept any version.
+func (ps *PluginSpec) AcceptsVersion(version string) bool {
+	if ps.Version == "" || ps.Version == "latest" {
+		return true
+	}
+	constraint, err := semver.NewConstraint(ps.Version)
+	if err != nil {
+		return strings.TrimPrefix(version, "v") == strings.TrimPrefix(ps.Version, "v")
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	return constraint.Check(v)
+}
+
+// SelectVersion returns the highest of the installed versions the declaration
+// accepts, or false if it accepts none.
+func (ps *PluginSpec) SelectVersion(installed []string) (string, bool) {
+	var accepted []*semver.Version
+	for _, version := range installed {
+		v, err := semver.NewVersion(version)
+		if err != nil || !ps.AcceptsVersion(version) {
+			continue
+		}
+		accepted = append(accepted, v)
+	}
+	if len(accepted) == 0 {
+		return "", false
+	}
+	sort.Sort(semver.Collection(accepted))
+	return accepted[len(accepted)-1].Original(), true
+}
+
 // PluginRegistry maps plugin aliases to their specifications.
 // This allows observations to reference plugins by alias while the runtime
 // resolves them to their actual sources.
@@ -122,11 +167,18 @@ func (pr *PluginRegistry) AllSpecs() []*PluginSpec {
 //   - "file@1.2.0"                              -> name=file, source=file, version=1.2.0
 //   - "ghcr.io/.../file:1.2.0"                  -> name=file, source=full path
 //   - "ghcr.io/.../file@sha256:abc..."          -> name=file, source=path, digest=sha256:abc...
+//   - "acme/customdb@1.0"                       -> name=customdb, namespace=acme, version=1.0
+//   - "http2=reglet/http@2.0"                   -> name=http2, source=reglet/http@2.0, version=2.0
 func ParsePluginDeclaration(declaration string) (*PluginSpec, error) {
 	if declaration == "" {
 		return nil, fmt.Errorf("empty plugin declaration")
 	}
 
+	// Aliased declaration: the alias names the plugin in observations
+	if alias, source, ok := strings.Cut(declaration, "="); ok {
+		return ParsePluginDeclarationWithAlias(alias, source)
+	}
+
 	spec := &PluginSpec{
 		Source: declaration,
 	}
@@ -156,6 +208,11 @@ func ParsePluginDeclaration(declaration string) (*PluginSpec, error) {
 			name = name[:idx]
 		}
 		spec.Name = name
+
+		// Namespace-qualified name: namespace/name
+		if len(parts) == 2 && isPluginName(parts[0]) && isPluginName(name) {
+			spec.Namespace = parts[0]
+		}
 	} else {
 		// Simple name or name@version
 		spec.Name = declaration
@@ -167,6 +224,12 @@ func ParsePluginDeclaration(declaration string) (*PluginSpec, error) {
 	return spec, nil
 }
 
+// isPluginName reports whether s is a valid plugin or namespace name.
+func isPluginName(s string) bool {
+	_, err := values.NewPluginName(s)
+	return err == nil
+}
+
 // ParsePluginDeclarationWithAlias parses a plugin declaration with an explicit alias.
 // Format: "alias: source" or expanded map format.
 func ParsePluginDeclarationWithAlias(alias string, source interface{}) (*PluginSpec, error) {
@@ -17,6 +17,7 @@ func TestParsePluginDeclaration(t *testing.T) {
 		wantSource  string
 		wantVersion string
 		wantDigest  string
+		wantNS      string
 		wantErr     bool
 	}{
 		{
@@ -46,6 +47,33 @@ func TestParsePluginDeclaration(t *testing.T) {
 			wantSource:  "ghcr.io/reglet-dev/reglet-plugins/file@sha256:abc123",
 			wantDigest:  "sha256:abc123",
 		},
+		{
+			name:        "namespace-qualified",
+			declaration: "acme/customdb@1.0",
+			wantName:    "customdb",
+			wantSource:  "acme/customdb@1.0",
+			wantVersion: "1.0",
+			wantNS:      "acme",
+		},
+		{
+			name:        "path is not namespace-qualified",
+			declaration: "plugins/custom.wasm",
+			wantName:    "custom.wasm",
+			wantSource:  "plugins/custom.wasm",
+		},
+		{
+			name:        "aliased declaration",
+			declaration: "http2=reglet/http@2.0",
+			wantName:    "http2",
+			wantSource:  "reglet/http@2.0",
+			wantVersion: "2.0",
+			wantNS:      "reglet",
+		},
+		{
+			name:        "empty alias",
+			declaration: "=reglet/http@2.0",
+			wantErr:     true,
+		},
 		{
 			name:        "empty declaration",
 			declaration: "",
@@ -66,6 +94,7 @@ func TestParsePluginDeclaration(t *testing.T) {
 			assert.Equal(t, tt.wantSource, spec.Source)
 			assert.Equal(t, tt.wantVersion, spec.Version)
 			assert.Equal(t, tt.wantDigest, spec.Digest)
+			assert.Equal(t, tt.wantNS, spec.Namespace)
 		})
 	}
 }
@@ -186,6 +215,46 @@ func TestPluginSpec_PluginName(t *testing.T) {
 	}
 }
 
+func TestPluginSpec_AcceptsVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		declared string
+		version  string
+		accepts  bool
+	}{
+		{"", "2.0.0", true},
+		{"latest", "2.0.0", true},
+		{"1.0", "1.0.3", true},
+		{"1.0", "1.1.0", false},
+		{"1.0.2", "1.0.2", true},
+		{"1.0.2", "1.0.3", false},
+		{"^1.2", "1.4.0", true},
+		{"^1.2", "2.0.0", false},
+		{"1.0", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.declared+"/"+tt.version, func(t *testing.T) {
+			t.Parallel()
+			spec := &PluginSpec{Version: tt.declared}
+			assert.Equal(t, tt.accepts, spec.AcceptsVersion(tt.version))
+		})
+	}
+}
+
+func TestPluginSpec_SelectVersion(t *testing.T) {
+	t.Parallel()
+
+	spec := &PluginSpec{Version: "1.0"}
+	version, ok := spec.SelectVersion([]string{"1.0.1", "2.0.0", "1.0.10", "1.0.2", "notes"})
+	require.True(t, ok)
+	assert.Equal(t, "1.0.10", version)
+
+	_, ok = spec.SelectVersion([]string{"2.0.0"})
+	assert.False(t, ok)
+}
+
 func TestPluginRegistry(t *testing.T) {
 	t.Parallel()
 
@@ -4,6 +4,10 @@ package entities
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -27,16 +31,82 @@ const (
 // - At least one observation per control
 type Profile struct {
 	Metadata ProfileMetadata        `yaml:"profile"`
-	Plugins  []string               `yaml:"plugins,omitempty"`
+	Plugins  PluginDeclarations     `yaml:"plugins,omitempty"`
 	Vars     map[string]interface{} `yaml:"vars,omitempty"`
 	Controls ControlsSection        `yaml:"controls"`
 
+	// Integrations configures result exporters, keyed by exporter name
+	// (e.g. "jira"). A section is only used when its exporter is selected.
+	Integrations map[string]map[string]interface{} `yaml:"integrations,omitempty"`
+
+	// Environments holds per-environment var overrides and targets, keyed by
+	// environment name (e.g. "staging", "prod"). One is selected with --env.
+	Environments map[string]Environment `yaml:"environments,omitempty"`
+
+	// Quotas bound the resources each plugin may use during a run, keyed by
+	// the plugin name observations use. Observations of a plugin that used up
+	// a quota fail with quota_exceeded.
+	Quotas map[string]PluginQuota `yaml:"quotas,omitempty"`
+
+	// Setup observations run once before the controls, e.g. to obtain an
+	// auth token or open a tunnel. Observations of controls and teardown
+	// reference their evidence as {{ .setup.<id>.<field> }}; a setup
+	// observation that does not pass aborts the run.
+	Setup []LifecycleStep `yaml:"setup,omitempty"`
+
+	// Teardown observations run once after the controls, or after setup
+	// aborted the run, e.g. to close a tunnel or clean temporary artifacts.
+	Teardown []LifecycleStep `yaml:"teardown,omitempty"`
+
+	// Environment is the name of the selected environment ("" = none). It is
+	// set by SelectEnvironment, never read from the profile file.
+	Environment string `yaml:"-"`
+
 	// Extends specifies parent profiles to inherit from.
 	// Multiple parents are merged left-to-right before applying current profile.
 	// This field is NOT propagated after merge resolution.
 	Extends []string `yaml:"extends,omitempty"`
 }
 
+// PluginDeclarations lists the plugins a profile uses, as plugin declarations
+// (see ParsePluginDeclaration). An entry may also map an alias to a
+// declaration ("http2: reglet/http@2.0"), so that two versions of a plugin can
+// be used side by side; it is kept as "alias=declaration".
+type PluginDeclarations []string
+
+// UnmarshalYAML accepts plain declarations and alias mappings.
+func (d *PluginDeclarations) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var entries []interface{}
+	if err := unmarshal(&entries); err != nil {
+		return err
+	}
+
+	declarations := make(PluginDeclarations, 0, len(entries))
+	for _, entry := range entries {
+		switch v := entry.(type) {
+		case string:
+			declarations = append(declarations, v)
+		case map[string]interface{}:
+			aliases := make([]string, 0, len(v))
+			for alias := range v {
+				aliases = append(aliases, alias)
+			}
+			sort.Strings(aliases)
+			for _, alias := range aliases {
+				source, ok := v[alias].(string)
+				if !ok {
+					return fmt.Errorf("plugin alias %q must map to a plugin declaration, got %T", alias, v[alias])
+				}
+				declarations = append(declarations, alias+"="+source)
+			}
+		default:
+			return fmt.Errorf("invalid plugin declaration %v: expected a plugin or an alias mapping", entry)
+		}
+	}
+	*d = declarations
+	return nil
+}
+
 // ProfileMetadata contains descriptive information about the profile.
 type ProfileMetadata struct {
 	Name        string `yaml:"name"`
@@ -44,6 +114,13 @@ type ProfileMetadata struct {
 	Description string `yaml:"description,omitempty"`
 }
 
+// Environment overrides vars for one deployment environment and lists the
+// targets observations referencing {{ .target }} fan out to.
+type Environment struct {
+	Vars    map[string]interface{} `yaml:"vars,omitempty"`
+	Targets []string               `yaml:"targets,omitempty"`
+}
+
 // ControlsSection groups validation controls and their default settings.
 type ControlsSection struct {
 	Defaults *ControlDefaults `yaml:"defaults,omitempty"`
@@ -52,26 +129,33 @@ type ControlsSection struct {
 
 // ControlDefaults specifies values inherited by controls when not explicitly set.
 type ControlDefaults struct {
-	Severity      string        `yaml:"severity,omitempty"`
-	Owner         string        `yaml:"owner,omitempty"`
-	RetryBackoff  BackoffType   `yaml:"retry_backoff,omitempty"`
-	Tags          []string      `yaml:"tags,omitempty"`
-	Timeout       time.Duration `yaml:"timeout,omitempty"`
-	Retries       int           `yaml:"retries,omitempty"`
-	RetryDelay    time.Duration `yaml:"retry_delay,omitempty"`
-	RetryMaxDelay time.Duration `yaml:"retry_max_delay,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Severity      string            `yaml:"severity,omitempty"`
+	Owner         string            `yaml:"owner,omitempty"`
+	RetryBackoff  BackoffType       `yaml:"retry_backoff,omitempty"`
+	RunsOn        []string          `yaml:"runs_on,omitempty"`
+	Tags          []string          `yaml:"tags,omitempty"`
+	Timeout       time.Duration     `yaml:"timeout,omitempty"`
+	Retries       int               `yaml:"retries,omitempty"`
+	RetryDelay    time.Duration     `yaml:"retry_delay,omitempty"`
+	RetryMaxDelay time.Duration     `yaml:"retry_max_delay,omitempty"`
 }
 
 // Control represents a specific compliance check or validation unit.
 // It is uniquely identified by its ID.
 type Control struct {
+	Labels                 map[string]string       `yaml:"labels,omitempty"` // Free-form metadata (CMDB IDs, services, cost centers) carried into results and exporters
 	ID                     string                  `yaml:"id"`
 	Name                   string                  `yaml:"name"`
 	Description            string                  `yaml:"description,omitempty"`
+	Rationale              string                  `yaml:"rationale,omitempty"`  // Why the requirement exists, shown with findings
+	References             []string                `yaml:"references,omitempty"` // URLs documenting the requirement (standards, runbooks)
 	Severity               string                  `yaml:"severity,omitempty"`
 	Owner                  string                  `yaml:"owner,omitempty"`
 	RetryBackoff           BackoffType             `yaml:"retry_backoff,omitempty"`
 	DependsOn              []string                `yaml:"depends_on,omitempty"`
+	RunsOn                 []string                `yaml:"runs_on,omitempty"` // Agent facts a distributed run requires of the node running the control
+	When                   string                  `yaml:"when,omitempty"`    // Condition over host facts, e.g. facts.os == "windows"; the control is skipped where it is false
 	ObservationDefinitions []ObservationDefinition `yaml:"observations"`
 	Tags                   []string                `yaml:"tags,omitempty"`
 	Timeout                time.Duration           `yaml:"timeout,omitempty"`
@@ -85,7 +169,77 @@ type Control struct {
 type ObservationDefinition struct {
 	Plugin string                 `yaml:"plugin"`
 	Config map[string]interface{} `yaml:"config,omitempty"`
+	Env    map[string]string      `yaml:"env,omitempty"` // Non-secret variables exposed to the plugin, subject to env capabilities
 	Expect []string               `yaml:"expect,omitempty"`
+
+	// Privileged observations may read files the reglet process cannot
+	// through a narrowly scoped sudo helper, when runs allow it (--allow-sudo)
+	Privileged bool `yaml:"privileged,omitempty"`
+
+	// FreshConnections makes HTTP requests open a new connection each instead
+	// of reusing pooled ones, so timings include the full handshake
+	FreshConnections bool `yaml:"fresh_connections,omitempty"`
+
+	// Debug logs sanitized summaries of the observation's HTTP exchanges
+	// when it fails, as --debug-http does for all observations
+	Debug bool `yaml:"debug,omitempty"`
+
+	// Thresholds bound numeric evidence fields, keyed by field path (e.g. "latency_ms")
+	Thresholds map[string]Threshold `yaml:"thresholds,omitempty"`
+}
+
+// LifecycleStep is a setup or teardown observation of a profile, identified
+// by the ID controls reference its evidence with.
+type LifecycleStep struct {
+	ID                    string `yaml:"id"`
+	ObservationDefinition `yaml:",inline"`
+}
+
+// SetupRefPattern matches a reference to the evidence of a setup step,
+// {{ .setup.<id>.<field> }}, the field a dot-separated path into its data.
+var SetupRefPattern = regexp.MustCompile(`\{\{\s*\.setup\.([a-zA-Z0-9_-]+)\.([a-zA-Z0-9_.]+)\s*\}\}`)
+
+// SetupReferences returns the IDs of the setup steps whose evidence the
+// observation's config or env references, in order of first appearance.
+func (o ObservationDefinition) SetupReferences() []string {
+	var ids []string
+	add := func(s string) {
+		for _, match := range SetupRefPattern.FindAllStringSubmatch(s, -1) {
+			if !slices.Contains(ids, match[1]) {
+				ids = append(ids, match[1])
+			}
+		}
+	}
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case string:
+			add(v)
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				walk(v[key])
+			}
+		case []interface{}:
+			for _, elem := range v {
+				walk(elem)
+			}
+		}
+	}
+	walk(o.Config)
+	keys := make([]string, 0, len(o.Env))
+	for key := range o.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add(o.Env[key])
+	}
+	return ids
 }
 
 // ===== PROFILE AGGREGATE ROOT METHODS =====
@@ -100,9 +254,8 @@ func (p *Profile) GetPlugins() []string {
 	return p.Plugins
 }
 
-// BuildPluginRegistry creates a PluginRegistry from the profile's plugin declarations.
-// This supports the current simple list format for backwards compatibility.
-// Future versions will support map format with aliases.
+// BuildPluginRegistry creates a PluginRegistry from the profile's plugin
+// declarations, keyed by the name observations use (the alias, if any).
 func (p *Profile) BuildPluginRegistry() (*PluginRegistry, error) {
 	registry := NewPluginRegistry()
 
@@ -124,6 +277,89 @@ func (p *Profile) GetVars() map[string]interface{} {
 	return p.Vars
 }
 
+// GetIntegrations returns the exporter settings keyed by exporter name.
+func (p *Profile) GetIntegrations() map[string]map[string]interface{} {
+	return p.Integrations
+}
+
+// GetQuotas returns the resource quotas of the profile's plugins, by plugin name.
+func (p *Profile) GetQuotas() map[string]PluginQuota {
+	return p.Quotas
+}
+
+// GetSetup returns the observations run once before the controls.
+func (p *Profile) GetSetup() []LifecycleStep {
+	return p.Setup
+}
+
+// GetTeardown returns the observations run once after the controls.
+func (p *Profile) GetTeardown() []LifecycleStep {
+	return p.Teardown
+}
+
+// AllObservations returns the observations of the controls followed by those
+// of setup and teardown: every plugin call a run may make.
+func (p *Profile) AllObservations() []ObservationDefinition {
+	var all []ObservationDefinition
+	for _, ctrl := range p.Controls.Items {
+		all = append(all, ctrl.ObservationDefinitions...)
+	}
+	for _, step := range p.Setup {
+		all = append(all, step.ObservationDefinition)
+	}
+	for _, step := range p.Teardown {
+		all = append(all, step.ObservationDefinition)
+	}
+	return all
+}
+
+// GetEnvironment returns the name of the selected environment ("" = none).
+func (p *Profile) GetEnvironment() string {
+	return p.Environment
+}
+
+// SelectEnvironment resolves the profile for the named environment: its vars
+// override the profile's vars key by key. An empty name selects nothing.
+func (p *Profile) SelectEnvironment(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	env, ok := p.Environments[name]
+	if !ok {
+		if len(p.Environments) == 0 {
+			return fmt.Errorf("unknown environment %q: profile defines no environments", name)
+		}
+		names := make([]string, 0, len(p.Environments))
+		for n := range p.Environments {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown environment %q (defined: %s)", name, strings.Join(names, ", "))
+	}
+
+	if len(env.Vars) > 0 {
+		vars := make(map[string]interface{}, len(p.Vars)+len(env.Vars))
+		for k, v := range p.Vars {
+			vars[k] = v
+		}
+		for k, v := range env.Vars {
+			vars[k] = v
+		}
+		p.Vars = vars
+	}
+	p.Environment = name
+	return nil
+}
+
+// Targets returns the targets of the selected environment.
+func (p *Profile) Targets() []string {
+	if p.Environment == "" {
+		return nil
+	}
+	return p.Environments[p.Environment].Targets
+}
+
 // GetAllControls returns all controls in the profile.
 func (p *Profile) GetAllControls() []Control {
 	return p.Controls.Items
@@ -154,6 +390,12 @@ func (p *Profile) Validate() error {
 		controlIDs[ctrl.ID] = true
 	}
 
+	for plugin, quota := range p.Quotas {
+		if err := quota.Validate(); err != nil {
+			return fmt.Errorf("quotas.%s: %w", plugin, err)
+		}
+	}
+
 	for _, ctrl := range p.Controls.Items {
 		for _, dep := range ctrl.DependsOn {
 			if !controlIDs[dep] {
@@ -162,9 +404,71 @@ func (p *Profile) Validate() error {
 		}
 	}
 
+	if err := p.validateLifecycle(); err != nil {
+		return err
+	}
+
 	return p.CheckForControlDependencyCycles()
 }
 
+// validateLifecycle checks the setup and teardown steps, and that every
+// reference to setup evidence names a setup step that runs before it.
+func (p *Profile) validateLifecycle() error {
+	setupIDs := make(map[string]bool)
+	for i, step := range p.Setup {
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("setup step %d: %w", i, err)
+		}
+		for _, ref := range step.SetupReferences() {
+			if !setupIDs[ref] {
+				return fmt.Errorf("setup step %s references setup step %s, which does not run before it", step.ID, ref)
+			}
+		}
+		if setupIDs[step.ID] {
+			return fmt.Errorf("duplicate setup step ID: %s", step.ID)
+		}
+		setupIDs[step.ID] = true
+	}
+
+	teardownIDs := make(map[string]bool)
+	for i, step := range p.Teardown {
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("teardown step %d: %w", i, err)
+		}
+		for _, ref := range step.SetupReferences() {
+			if !setupIDs[ref] {
+				return fmt.Errorf("teardown step %s references non-existent setup step %s", step.ID, ref)
+			}
+		}
+		if teardownIDs[step.ID] {
+			return fmt.Errorf("duplicate teardown step ID: %s", step.ID)
+		}
+		teardownIDs[step.ID] = true
+	}
+
+	for _, ctrl := range p.Controls.Items {
+		for _, obs := range ctrl.ObservationDefinitions {
+			for _, ref := range obs.SetupReferences() {
+				if !setupIDs[ref] {
+					return fmt.Errorf("control %s references non-existent setup step %s", ctrl.ID, ref)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks that the step has an ID and a plugin.
+func (s LifecycleStep) validate() error {
+	if s.ID == "" {
+		return fmt.Errorf("step ID cannot be empty")
+	}
+	if s.Plugin == "" {
+		return fmt.Errorf("step %s: plugin cannot be empty", s.ID)
+	}
+	return nil
+}
+
 // AddControl safely adds a new control to the profile.
 // It returns an error if the control is invalid or already exists.
 func (p *Profile) AddControl(ctrl Control) error {
@@ -350,6 +654,11 @@ func (c *Control) ApplyDefaults(defaults *ControlDefaults) {
 	}
 
 	c.applyTagDefaults(defaults.Tags)
+	c.applyLabelDefaults(defaults.Labels)
+
+	if len(c.RunsOn) == 0 && len(defaults.RunsOn) > 0 {
+		c.RunsOn = defaults.RunsOn
+	}
 
 	if c.Timeout == 0 && defaults.Timeout > 0 {
 		c.Timeout = defaults.Timeout
@@ -378,6 +687,22 @@ func (c *Control) applyTagDefaults(defaultTags []string) {
 	c.Tags = mergedTags
 }
 
+// applyLabelDefaults adds default labels the control does not set itself.
+func (c *Control) applyLabelDefaults(defaultLabels map[string]string) {
+	if len(defaultLabels) == 0 {
+		return
+	}
+
+	merged := make(map[string]string, len(defaultLabels)+len(c.Labels))
+	for key, value := range defaultLabels {
+		merged[key] = value
+	}
+	for key, value := range c.Labels {
+		merged[key] = value
+	}
+	c.Labels = merged
+}
+
 func (c *Control) applyRetryDefaults(defaults *ControlDefaults) {
 	if c.Retries == 0 && defaults.Retries > 0 {
 		c.Retries = defaults.Retries
@@ -425,9 +750,19 @@ func (c *Control) Validate() error {
 		}
 	}
 
+	for _, fact := range c.RunsOn {
+		if strings.TrimSpace(fact) == "" || fact == RunsOnCommandPrefix {
+			return fmt.Errorf("control %s: invalid runs_on entry %q", c.ID, fact)
+		}
+	}
+
 	return nil
 }
 
+// RunsOnCommandPrefix marks runs_on entries naming a command the node must
+// have on its PATH, e.g. "has:docker".
+const RunsOnCommandPrefix = "has:"
+
 // HasTag returns true if the control has the specified tag.
 func (c *Control) HasTag(tag string) bool {
 	for _, t := range c.Tags {
@@ -12,12 +12,18 @@ type ProfileReader interface {
 	GetPlugins() []string
 	BuildPluginRegistry() (*PluginRegistry, error)
 	GetVars() map[string]interface{}
+	GetIntegrations() map[string]map[string]interface{}
+	GetQuotas() map[string]PluginQuota
+	GetSetup() []LifecycleStep
+	GetTeardown() []LifecycleStep
+	GetEnvironment() string
 
 	// Control queries
 	GetControl(id string) *Control
 	HasControl(id string) bool
 	ControlCount() int
 	GetAllControls() []Control
+	AllObservations() []ObservationDefinition
 
 	// Filtering
 	SelectControlsByTags(tags []string) []Control
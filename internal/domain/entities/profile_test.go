@@ -85,6 +85,31 @@ func Test_Control_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid severity",
 		},
+		{
+			name: "valid_runs_on",
+			control: Control{
+				ID:     "ctrl-001",
+				Name:   "Test",
+				RunsOn: []string{"linux", "has:docker"},
+				ObservationDefinitions: []ObservationDefinition{
+					{Plugin: "http"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "runs_on_without_command",
+			control: Control{
+				ID:     "ctrl-001",
+				Name:   "Test",
+				RunsOn: []string{"linux", "has:"},
+				ObservationDefinitions: []ObservationDefinition{
+					{Plugin: "http"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid runs_on entry",
+		},
 	}
 
 	for _, tt := range tests {
@@ -354,6 +379,107 @@ func Test_Profile_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "circular dependency detected",
 		},
+		{
+			name: "negative_quota",
+			profile: Profile{
+				Metadata: ProfileMetadata{
+					Name:    "Test",
+					Version: "1.0.0",
+				},
+				Quotas: map[string]PluginQuota{
+					"http": {MaxInvocations: 10, MaxHostCalls: -1},
+				},
+				Controls: ControlsSection{
+					Items: []Control{
+						{
+							ID:   "ctrl-001",
+							Name: "Test Control",
+							ObservationDefinitions: []ObservationDefinition{
+								{Plugin: "http"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "quotas.http: max_host_calls must not be negative",
+		},
+		{
+			name: "setup_reference_to_unknown_step",
+			profile: Profile{
+				Metadata: ProfileMetadata{
+					Name:    "Test",
+					Version: "1.0.0",
+				},
+				Setup: []LifecycleStep{
+					{ID: "auth", ObservationDefinition: ObservationDefinition{Plugin: "http"}},
+				},
+				Controls: ControlsSection{
+					Items: []Control{
+						{
+							ID:   "ctrl-001",
+							Name: "Test Control",
+							ObservationDefinitions: []ObservationDefinition{
+								{Plugin: "http", Config: map[string]interface{}{"token": "{{ .setup.login.token }}"}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "control ctrl-001 references non-existent setup step login",
+		},
+		{
+			name: "setup_step_references_later_step",
+			profile: Profile{
+				Metadata: ProfileMetadata{
+					Name:    "Test",
+					Version: "1.0.0",
+				},
+				Setup: []LifecycleStep{
+					{ID: "tunnel", ObservationDefinition: ObservationDefinition{Plugin: "command", Env: map[string]string{"TOKEN": "{{ .setup.auth.token }}"}}},
+					{ID: "auth", ObservationDefinition: ObservationDefinition{Plugin: "http"}},
+				},
+				Controls: ControlsSection{
+					Items: []Control{
+						{
+							ID:                     "ctrl-001",
+							Name:                   "Test Control",
+							ObservationDefinitions: []ObservationDefinition{{Plugin: "http"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "setup step tunnel references setup step auth, which does not run before it",
+		},
+		{
+			name: "valid_setup_and_teardown",
+			profile: Profile{
+				Metadata: ProfileMetadata{
+					Name:    "Test",
+					Version: "1.0.0",
+				},
+				Setup: []LifecycleStep{
+					{ID: "auth", ObservationDefinition: ObservationDefinition{Plugin: "http"}},
+				},
+				Teardown: []LifecycleStep{
+					{ID: "logout", ObservationDefinition: ObservationDefinition{Plugin: "http", Config: map[string]interface{}{"token": "{{ .setup.auth.token }}"}}},
+				},
+				Controls: ControlsSection{
+					Items: []Control{
+						{
+							ID:   "ctrl-001",
+							Name: "Test Control",
+							ObservationDefinitions: []ObservationDefinition{
+								{Plugin: "http", Config: map[string]interface{}{"headers": []interface{}{"Bearer {{ .setup.auth.token }}"}}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -542,6 +668,8 @@ func Test_Profile_ApplyDefaults(t *testing.T) {
 				Severity: "medium",
 				Owner:    "platform",
 				Tags:     []string{"default-tag"},
+				Labels:   map[string]string{"service": "platform", "cost_center": "cc-100"},
+				RunsOn:   []string{"linux"},
 				Timeout:  10 * time.Second,
 			},
 			Items: []Control{
@@ -551,6 +679,8 @@ func Test_Profile_ApplyDefaults(t *testing.T) {
 					Severity: "high",
 					Owner:    "security",
 					Tags:     []string{"custom-tag"},
+					Labels:   map[string]string{"service": "billing", "cmdb_id": "CI0042"},
+					RunsOn:   []string{"windows"},
 					Timeout:  5 * time.Second,
 				},
 				{
@@ -570,6 +700,10 @@ func Test_Profile_ApplyDefaults(t *testing.T) {
 	assert.Equal(t, 5*time.Second, ctrl1.Timeout)
 	// Tags should be merged
 	assert.Len(t, ctrl1.Tags, 2) // custom-tag + default-tag
+	// Labels should be merged, control wins
+	assert.Equal(t, map[string]string{"service": "billing", "cmdb_id": "CI0042", "cost_center": "cc-100"}, ctrl1.Labels)
+	// runs_on replaces the default
+	assert.Equal(t, []string{"windows"}, ctrl1.RunsOn)
 
 	// Second control gets defaults
 	ctrl2 := profile.Controls.Items[1]
@@ -577,4 +711,52 @@ func Test_Profile_ApplyDefaults(t *testing.T) {
 	assert.Equal(t, "platform", ctrl2.Owner)
 	assert.Equal(t, 10*time.Second, ctrl2.Timeout)
 	assert.Contains(t, ctrl2.Tags, "default-tag")
+	assert.Equal(t, map[string]string{"service": "platform", "cost_center": "cc-100"}, ctrl2.Labels)
+	assert.Equal(t, []string{"linux"}, ctrl2.RunsOn)
+}
+
+func Test_Profile_SelectEnvironment(t *testing.T) {
+	t.Parallel()
+
+	newProfile := func() *Profile {
+		return &Profile{
+			Vars: map[string]interface{}{"port": 443, "min_days": 30},
+			Environments: map[string]Environment{
+				"prod":    {Vars: map[string]interface{}{"min_days": 14}, Targets: []string{"web-1", "web-2"}},
+				"staging": {Targets: []string{"stg-1"}},
+			},
+		}
+	}
+
+	t.Run("applies environment vars and targets", func(t *testing.T) {
+		t.Parallel()
+		profile := newProfile()
+		require.NoError(t, profile.SelectEnvironment("prod"))
+		assert.Equal(t, "prod", profile.GetEnvironment())
+		assert.Equal(t, map[string]interface{}{"port": 443, "min_days": 14}, profile.Vars)
+		assert.Equal(t, []string{"web-1", "web-2"}, profile.Targets())
+	})
+
+	t.Run("empty name selects nothing", func(t *testing.T) {
+		t.Parallel()
+		profile := newProfile()
+		require.NoError(t, profile.SelectEnvironment(""))
+		assert.Empty(t, profile.GetEnvironment())
+		assert.Equal(t, 30, profile.Vars["min_days"])
+		assert.Empty(t, profile.Targets())
+	})
+
+	t.Run("unknown environment lists defined ones", func(t *testing.T) {
+		t.Parallel()
+		err := newProfile().SelectEnvironment("qa")
+		require.Error(t, err)
+		assert.Equal(t, `unknown environment "qa" (defined: prod, staging)`, err.Error())
+	})
+
+	t.Run("profile without environments", func(t *testing.T) {
+		t.Parallel()
+		err := (&Profile{}).SelectEnvironment("prod")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "profile defines no environments")
+	})
 }
@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// PluginQuota bounds the resources one plugin may use during a run. Zero
+// fields are unlimited.
+type PluginQuota struct {
+	MaxInvocations int           `yaml:"max_invocations,omitempty"` // observations run
+	MaxRuntime     time.Duration `yaml:"max_runtime,omitempty"`     // total time of its observations
+	MaxHostCalls   int           `yaml:"max_host_calls,omitempty"`  // host function calls of its WASM instances
+}
+
+// Validate checks the quota bounds are not negative.
+func (q PluginQuota) Validate() error {
+	if q.MaxInvocations < 0 {
+		return fmt.Errorf("max_invocations must not be negative, got %d", q.MaxInvocations)
+	}
+	if q.MaxRuntime < 0 {
+		return fmt.Errorf("max_runtime must not be negative, got %s", q.MaxRuntime)
+	}
+	if q.MaxHostCalls < 0 {
+		return fmt.Errorf("max_host_calls must not be negative, got %d", q.MaxHostCalls)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"fmt"
+
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// Threshold bounds a numeric, duration, size or time evidence field with
+// quantities such as "500ms", "20%" or "30d".
+//
+// For time-valued fields (RFC 3339 strings), bounds apply to the offset from
+// now: min: 30d means at least 30 days in the future, within: 7d means no more
+// than 7 days away in either direction.
+type Threshold struct {
+	Min    string `yaml:"min,omitempty"`
+	Max    string `yaml:"max,omitempty"`
+	Within string `yaml:"within,omitempty"` // duration; the field must be a time
+	Unit   string `yaml:"unit,omitempty"`   // unit of plain numeric evidence, e.g. "ms"
+}
+
+// ParsedThreshold holds the parsed bounds of a Threshold. Nil bounds are unset.
+type ParsedThreshold struct {
+	Min    *values.Quantity
+	Max    *values.Quantity
+	Within *values.Quantity
+	Unit   string
+}
+
+// Parse validates the threshold and parses its bounds.
+func (t Threshold) Parse() (ParsedThreshold, error) {
+	parsed := ParsedThreshold{Unit: t.Unit}
+	if t.Min == "" && t.Max == "" && t.Within == "" {
+		return parsed, fmt.Errorf("threshold needs min, max or within")
+	}
+
+	var kind *values.QuantityKind
+	for _, bound := range []struct {
+		name  string
+		raw   string
+		field **values.Quantity
+	}{
+		{"min", t.Min, &parsed.Min},
+		{"max", t.Max, &parsed.Max},
+		{"within", t.Within, &parsed.Within},
+	} {
+		if bound.raw == "" {
+			continue
+		}
+		q, err := values.ParseQuantity(bound.raw)
+		if err != nil {
+			return parsed, fmt.Errorf("%s: %w", bound.name, err)
+		}
+		if kind != nil && *kind != q.Kind() {
+			return parsed, fmt.Errorf("%s is a %s but other bounds are %s", bound.name, q.Kind(), *kind)
+		}
+		k := q.Kind()
+		kind = &k
+		*bound.field = &q
+	}
+
+	if parsed.Within != nil && parsed.Within.Kind() != values.QuantityDuration {
+		return parsed, fmt.Errorf("within must be a duration such as 30d")
+	}
+	if t.Unit != "" {
+		unitKind, ok := values.UnitKind(t.Unit)
+		if !ok {
+			return parsed, fmt.Errorf("unknown unit %q", t.Unit)
+		}
+		if unitKind != *kind {
+			return parsed, fmt.Errorf("unit %q is a %s but bounds are %s", t.Unit, unitKind, *kind)
+		}
+	}
+	return parsed, nil
+}
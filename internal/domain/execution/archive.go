@@ -0,0 +1,114 @@
+package execution
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+const (
+	// ResultArchiveFormat identifies files written by "reglet history export".
+	ResultArchiveFormat = "reglet-result-archive"
+	// ResultArchiveVersion is the archive layout version this build writes.
+	ResultArchiveVersion = 1
+)
+
+var (
+	// ErrInvalidArchive is returned for archives that cannot be read.
+	ErrInvalidArchive = errors.New("invalid result archive")
+
+	// ErrArchiveTampered is returned when an archived result does not match
+	// its digest.
+	ErrArchiveTampered = errors.New("archived result does not match its digest")
+)
+
+// ResultArchive moves execution results between repositories, for example
+// from an air-gapped machine to a central store. Each result is kept as the
+// JSON document it was stored as, with a digest, so it arrives unchanged:
+// execution ID, version, timestamps and the reglet version that produced it
+// are preserved.
+type ResultArchive struct {
+	ExportedAt    time.Time        `json:"exported_at"`
+	Format        string           `json:"format"`
+	Source        ArchiveSource    `json:"source"`
+	Results       []ArchivedResult `json:"results"`
+	FormatVersion int              `json:"format_version"`
+}
+
+// ArchiveSource records where an archive was exported.
+type ArchiveSource struct {
+	Hostname      string `json:"hostname,omitempty"`
+	RegletVersion string `json:"reglet_version,omitempty"`
+}
+
+// ArchivedResult is one result of an archive.
+type ArchivedResult struct {
+	// Digest is the SHA-256 of Result in compact form ("sha256:<hex>"), so
+	// re-indenting the archive does not invalidate it.
+	Digest string          `json:"digest"`
+	Result json.RawMessage `json:"result"`
+}
+
+// NewResultArchive creates an empty archive.
+func NewResultArchive(source ArchiveSource, exportedAt time.Time) *ResultArchive {
+	return &ResultArchive{
+		Format:        ResultArchiveFormat,
+		FormatVersion: ResultArchiveVersion,
+		ExportedAt:    exportedAt,
+		Source:        source,
+		Results:       []ArchivedResult{},
+	}
+}
+
+// Add appends a result to the archive.
+func (a *ResultArchive) Add(result *ExecutionResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to serialize execution result %s: %w", result.GetID(), err)
+	}
+	sum := sha256.Sum256(data)
+	a.Results = append(a.Results, ArchivedResult{
+		Digest: "sha256:" + hex.EncodeToString(sum[:]),
+		Result: data,
+	})
+	return nil
+}
+
+// Decode checks the archive format and every digest, and returns the
+// archived results. Nothing is returned if any result fails its check.
+func (a *ResultArchive) Decode() ([]*ExecutionResult, error) {
+	if a.Format != ResultArchiveFormat {
+		return nil, fmt.Errorf("%w: format %q", ErrInvalidArchive, a.Format)
+	}
+	if a.FormatVersion < 1 || a.FormatVersion > ResultArchiveVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d (supported: 1-%d)", ErrInvalidArchive, a.FormatVersion, ResultArchiveVersion)
+	}
+
+	results := make([]*ExecutionResult, 0, len(a.Results))
+	for i, archived := range a.Results {
+		digest, err := values.ParseDigest(archived.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: result %d: %w", ErrInvalidArchive, i+1, err)
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, archived.Result); err != nil {
+			return nil, fmt.Errorf("%w: result %d: %w", ErrInvalidArchive, i+1, err)
+		}
+		if err := digest.Verify(compact.Bytes()); err != nil {
+			return nil, fmt.Errorf("result %d: %w: %w", i+1, ErrArchiveTampered, err)
+		}
+
+		result, err := DecodeExecutionResult(compact.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%w: result %d: %w", ErrInvalidArchive, i+1, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,75 @@
+package execution_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func archiveOf(t *testing.T, results ...*execution.ExecutionResult) *execution.ResultArchive {
+	t.Helper()
+
+	archive := execution.NewResultArchive(execution.ArchiveSource{Hostname: "airgap-01"}, time.Now())
+	for _, r := range results {
+		require.NoError(t, archive.Add(r))
+	}
+
+	// Round-trip through indented JSON, as written by history export.
+	data, err := json.MarshalIndent(archive, "", "  ")
+	require.NoError(t, err)
+	var decoded execution.ResultArchive
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	return &decoded
+}
+
+func TestResultArchive_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	run := execution.NewExecutionResult("web", "1.0.0")
+	run.RegletVersion = "1.2.3"
+	run.Controls = []execution.ControlResult{{ID: "c", Status: values.StatusFail, Message: "<bad> & worse"}}
+	run.Finalize()
+	run.IncrementVersion()
+
+	archive := archiveOf(t, run)
+	assert.Equal(t, "airgap-01", archive.Source.Hostname)
+
+	results, err := archive.Decode()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, run.ExecutionID, results[0].ExecutionID)
+	assert.Equal(t, 2, results[0].GetVersion())
+	assert.Equal(t, "1.2.3", results[0].RegletVersion)
+	assert.Equal(t, "<bad> & worse", results[0].Controls[0].Message)
+}
+
+func TestResultArchive_DetectsTampering(t *testing.T) {
+	t.Parallel()
+
+	run := execution.NewExecutionResult("web", "1.0.0")
+	run.Controls = []execution.ControlResult{{ID: "c", Status: values.StatusFail}}
+	archive := archiveOf(t, run)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(archive.Results[0].Result, &doc))
+	doc["profile_name"] = "other"
+	archive.Results[0].Result, _ = json.Marshal(doc)
+
+	_, err := archive.Decode()
+	assert.ErrorIs(t, err, execution.ErrArchiveTampered)
+}
+
+func TestResultArchive_RejectsUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := (&execution.ResultArchive{Format: "something-else", FormatVersion: 1}).Decode()
+	assert.ErrorIs(t, err, execution.ErrInvalidArchive)
+
+	_, err = (&execution.ResultArchive{Format: execution.ResultArchiveFormat, FormatVersion: 99}).Decode()
+	assert.ErrorContains(t, err, "unsupported version 99")
+}
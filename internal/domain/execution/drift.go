@@ -0,0 +1,246 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// EnvironmentComparison is the configuration drift between runs of one
+// profile in different environments. Only controls that drift are listed.
+type EnvironmentComparison struct {
+	ProfileName  string         `json:"profile_name" yaml:"profile_name"`
+	Environments []ComparedRun  `json:"environments" yaml:"environments"`
+	Controls     []ControlDrift `json:"controls" yaml:"controls"`
+	Compared     int            `json:"compared_controls" yaml:"compared_controls"`
+}
+
+// ComparedRun identifies the run that represents an environment.
+type ComparedRun struct {
+	StartTime      time.Time          `json:"start_time" yaml:"start_time"`
+	Environment    string             `json:"environment" yaml:"environment"`
+	ProfileVersion string             `json:"profile_version" yaml:"profile_version"`
+	ExecutionID    values.ExecutionID `json:"execution_id" yaml:"execution_id"`
+}
+
+// ControlDrift is a control whose status or evidence differs between
+// environments. Statuses has no entry for an environment whose run lacks the
+// control.
+type ControlDrift struct {
+	Statuses map[string]values.Status `json:"statuses" yaml:"statuses"`
+	ID       string                   `json:"id" yaml:"id"`
+	Name     string                   `json:"name" yaml:"name"`
+	Fields   []FieldDrift             `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// StatusDrifts reports whether the control's status differs between the
+// environments.
+func (d *ControlDrift) StatusDrifts(environments []string) bool {
+	return differs(environments, func(env string) (string, bool) {
+		status, ok := d.Statuses[env]
+		return string(status), ok
+	})
+}
+
+// FieldDrift is an evidence field with different values between
+// environments. Field is the plugin name followed by the dotted path into the
+// evidence data ("tcp.tls_version"). Values holds, per environment, the
+// distinct values the field took across the control's observations; it has
+// no entry for an environment whose evidence lacks the field.
+type FieldDrift struct {
+	Values map[string][]string `json:"values" yaml:"values"`
+	Field  string              `json:"field" yaml:"field"`
+}
+
+// HasDrift reports whether any control drifts.
+func (c *EnvironmentComparison) HasDrift() bool {
+	return len(c.Controls) > 0
+}
+
+// EnvironmentNames returns the compared environments in report order.
+func (c *EnvironmentComparison) EnvironmentNames() []string {
+	names := make([]string, len(c.Environments))
+	for i, run := range c.Environments {
+		names[i] = run.Environment
+	}
+	return names
+}
+
+// CompareEnvironments compares runs of the same profile, one per environment,
+// in the given order. Controls are matched by ID and compared by status and
+// by evidence data rather than by observation, because an environment's
+// targets decide how many observations a control has. Evidence fields named
+// in ignore, or nested below one, are left out.
+func CompareEnvironments(runs []*ExecutionResult, ignore []string) *EnvironmentComparison {
+	cmp := &EnvironmentComparison{}
+	if len(runs) == 0 {
+		return cmp
+	}
+	cmp.ProfileName = runs[0].ProfileName
+
+	environments := make([]string, len(runs))
+	controls := make([]map[string]*ControlResult, len(runs))
+	var order []string
+	seen := make(map[string]bool)
+	for i, run := range runs {
+		environments[i] = run.Environment
+		cmp.Environments = append(cmp.Environments, ComparedRun{
+			Environment:    run.Environment,
+			ProfileVersion: run.ProfileVersion,
+			ExecutionID:    run.ExecutionID,
+			StartTime:      run.StartTime,
+		})
+
+		controls[i] = make(map[string]*ControlResult, len(run.Controls))
+		for j := range run.Controls {
+			ctrl := &run.Controls[j]
+			controls[i][ctrl.ID] = ctrl
+			if !seen[ctrl.ID] {
+				seen[ctrl.ID] = true
+				order = append(order, ctrl.ID)
+			}
+		}
+	}
+
+	cmp.Compared = len(order)
+	for _, id := range order {
+		drift := ControlDrift{ID: id, Statuses: make(map[string]values.Status)}
+		fields := make(map[string]map[string][]string)
+		for i, env := range environments {
+			ctrl, ok := controls[i][id]
+			if !ok {
+				continue
+			}
+			if drift.Name == "" {
+				drift.Name = ctrl.Name
+			}
+			drift.Statuses[env] = ctrl.Status
+			for field, vals := range evidenceFields(ctrl, ignore) {
+				if fields[field] == nil {
+					fields[field] = make(map[string][]string)
+				}
+				fields[field][env] = vals
+			}
+		}
+
+		for field, byEnv := range fields {
+			if valuesDiffer(environments, byEnv) {
+				drift.Fields = append(drift.Fields, FieldDrift{Field: field, Values: byEnv})
+			}
+		}
+		sort.Slice(drift.Fields, func(i, j int) bool { return drift.Fields[i].Field < drift.Fields[j].Field })
+
+		if len(drift.Fields) > 0 || drift.StatusDrifts(environments) {
+			cmp.Controls = append(cmp.Controls, drift)
+		}
+	}
+	return cmp
+}
+
+// evidenceFields flattens the evidence of a control's observations into
+// plugin-qualified field paths, each with its sorted distinct values. A
+// plugin error is reported as the "<plugin>.error" field.
+func evidenceFields(ctrl *ControlResult, ignore []string) map[string][]string {
+	distinct := make(map[string]map[string]bool)
+	add := func(field, value string) {
+		if isIgnored(field, ignore) {
+			return
+		}
+		if distinct[field] == nil {
+			distinct[field] = make(map[string]bool)
+		}
+		distinct[field][value] = true
+	}
+
+	for _, obs := range ctrl.ObservationResults {
+		if obs.Evidence == nil {
+			continue
+		}
+		if obs.Evidence.Error != nil {
+			msg := obs.Evidence.Error.Code
+			if msg == "" {
+				msg = obs.Evidence.Error.Message
+			}
+			add(obs.Plugin+".error", msg)
+		}
+		flattenEvidence(obs.Plugin, obs.Evidence.Data, add)
+	}
+
+	fields := make(map[string][]string, len(distinct))
+	for field, set := range distinct {
+		vals := make([]string, 0, len(set))
+		for v := range set {
+			vals = append(vals, v)
+		}
+		sort.Strings(vals)
+		fields[field] = vals
+	}
+	return fields
+}
+
+// flattenEvidence calls add for every leaf of data. Nested maps extend the
+// path; lists and scalars are leaves.
+func flattenEvidence(prefix string, data map[string]interface{}, add func(field, value string)) {
+	for key, value := range data {
+		path := prefix + "." + key
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenEvidence(path, nested, add)
+			continue
+		}
+		add(path, formatEvidenceValue(value))
+	}
+}
+
+// formatEvidenceValue renders a leaf value for comparison and display:
+// strings as-is, everything else as JSON.
+func formatEvidenceValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// isIgnored reports whether field is one of ignore or nested below one.
+func isIgnored(field string, ignore []string) bool {
+	for _, prefix := range ignore {
+		if field == prefix || strings.HasPrefix(field, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// valuesDiffer reports whether the per-environment values are not all equal,
+// counting a missing entry as a difference.
+func valuesDiffer(environments []string, byEnv map[string][]string) bool {
+	return differs(environments, func(env string) (string, bool) {
+		vals, ok := byEnv[env]
+		return strings.Join(vals, "\x00"), ok
+	})
+}
+
+// differs reports whether value returns different results, or no result, for
+// any of the environments.
+func differs(environments []string, value func(env string) (string, bool)) bool {
+	var first string
+	for i, env := range environments {
+		v, ok := value(env)
+		if !ok {
+			return true
+		}
+		if i == 0 {
+			first = v
+		} else if v != first {
+			return true
+		}
+	}
+	return false
+}
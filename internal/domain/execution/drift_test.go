@@ -0,0 +1,88 @@
+package execution_test
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareEnvironments(t *testing.T) {
+	t.Parallel()
+
+	observed := func(plugin string, data map[string]interface{}) execution.ObservationResult {
+		return execution.ObservationResult{Plugin: plugin, Evidence: &execution.Evidence{Status: true, Data: data}}
+	}
+	run := func(env string, controls ...execution.ControlResult) *execution.ExecutionResult {
+		result := execution.NewExecutionResult("web", "1.0.0")
+		result.Environment = env
+		result.Controls = controls
+		return result
+	}
+
+	staging := run("staging",
+		execution.ControlResult{ID: "tls", Name: "TLS", Status: values.StatusPass, ObservationResults: []execution.ObservationResult{
+			observed("tcp", map[string]interface{}{"connected": true, "tls_version": "TLS 1.3", "address": "stg:443"}),
+		}},
+		execution.ControlResult{ID: "ssh", Name: "SSH", Status: values.StatusPass, ObservationResults: []execution.ObservationResult{
+			observed("file", map[string]interface{}{"exists": true, "stat": map[string]interface{}{"mode": "0600"}}),
+		}},
+		execution.ControlResult{ID: "ntp", Name: "NTP", Status: values.StatusPass},
+		execution.ControlResult{ID: "staging-only", Name: "Staging only", Status: values.StatusPass},
+	)
+	prod := run("prod",
+		execution.ControlResult{ID: "tls", Name: "TLS", Status: values.StatusPass, ObservationResults: []execution.ObservationResult{
+			observed("tcp", map[string]interface{}{"connected": true, "tls_version": "TLS 1.3", "address": "web-1:443"}),
+			observed("tcp", map[string]interface{}{"connected": true, "tls_version": "TLS 1.2", "address": "web-2:443"}),
+		}},
+		execution.ControlResult{ID: "ssh", Name: "SSH", Status: values.StatusFail, ObservationResults: []execution.ObservationResult{
+			observed("file", map[string]interface{}{"exists": true, "stat": map[string]interface{}{"mode": "0644"}}),
+		}},
+		execution.ControlResult{ID: "ntp", Name: "NTP", Status: values.StatusPass},
+	)
+
+	cmp := execution.CompareEnvironments([]*execution.ExecutionResult{staging, prod}, []string{"tcp.address"})
+
+	assert.Equal(t, "web", cmp.ProfileName)
+	assert.Equal(t, []string{"staging", "prod"}, cmp.EnvironmentNames())
+	assert.Equal(t, 4, cmp.Compared)
+	assert.True(t, cmp.HasDrift())
+	require.Len(t, cmp.Controls, 3, "ntp matches in both environments")
+
+	tls := cmp.Controls[0]
+	assert.Equal(t, "tls", tls.ID)
+	assert.False(t, tls.StatusDrifts(cmp.EnvironmentNames()), "same status, different evidence")
+	assert.Equal(t, []execution.FieldDrift{{
+		Field:  "tcp.tls_version",
+		Values: map[string][]string{"staging": {"TLS 1.3"}, "prod": {"TLS 1.2", "TLS 1.3"}},
+	}}, tls.Fields, "ignored and equal fields are left out")
+
+	ssh := cmp.Controls[1]
+	assert.True(t, ssh.StatusDrifts(cmp.EnvironmentNames()))
+	assert.Equal(t, []execution.FieldDrift{{
+		Field:  "file.stat.mode",
+		Values: map[string][]string{"staging": {"0600"}, "prod": {"0644"}},
+	}}, ssh.Fields)
+
+	missing := cmp.Controls[2]
+	assert.Equal(t, "staging-only", missing.ID)
+	assert.Equal(t, map[string]values.Status{"staging": values.StatusPass}, missing.Statuses)
+	assert.True(t, missing.StatusDrifts(cmp.EnvironmentNames()))
+}
+
+func TestCompareEnvironments_NoDrift(t *testing.T) {
+	t.Parallel()
+
+	a := execution.NewExecutionResult("web", "1.0.0")
+	a.Environment = "a"
+	a.Controls = []execution.ControlResult{{ID: "c", Status: values.StatusPass}}
+	b := execution.NewExecutionResult("web", "1.0.0")
+	b.Environment = "b"
+	b.Controls = []execution.ControlResult{{ID: "c", Status: values.StatusPass}}
+
+	cmp := execution.CompareEnvironments([]*execution.ExecutionResult{a, b}, nil)
+	assert.False(t, cmp.HasDrift())
+	assert.Equal(t, 1, cmp.Compared)
+}
@@ -0,0 +1,120 @@
+package execution
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// minErrorGroupSize is the number of observations that must share a root
+// cause before they are reported as a group. A lone error is already clear
+// from its control.
+const minErrorGroupSize = 2
+
+// ErrorGroup is a set of errored observations that share a root cause, such
+// as every observation that failed during a DNS outage.
+type ErrorGroup struct {
+	Fingerprint  string           `json:"fingerprint" yaml:"fingerprint"`
+	Code         string           `json:"code,omitempty" yaml:"code,omitempty"`
+	Message      string           `json:"message" yaml:"message"` // Normalized message shared by the group
+	Example      string           `json:"example" yaml:"example"` // Message of the first observation, as reported
+	Observations []ObservationRef `json:"observations" yaml:"observations"`
+}
+
+// fingerprintRules rewrite the parts of an error message that vary between
+// observations hitting the same problem, so their fingerprints match. Order
+// matters: URLs before hosts, hosts before bare numbers.
+var fingerprintRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`"[^"]*"|'[^']*'`), `"…"`},
+	{regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s,;)]+`), "<url>"},
+	{regexp.MustCompile(`\[[0-9a-fA-F:]+\](:\d+)?`), "<addr>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<addr>"},
+	{regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`), "<id>"},
+	{regexp.MustCompile(`\b([a-zA-Z0-9-]+\.)+[a-zA-Z]{2,}(:\d+)?\b`), "<host>"},
+	{regexp.MustCompile(`(^|[\s=:(])/[^\s:,;)]*`), "$1<path>"},
+	{regexp.MustCompile(`\b\d+(\.\d+)?(ms|s|m|h|µs|ns)?\b`), "<n>"},
+}
+
+// NormalizeErrorMessage replaces hosts, addresses, paths, quoted values and
+// numbers in an error message with placeholders.
+func NormalizeErrorMessage(message string) string {
+	normalized := strings.TrimSpace(message)
+	for _, rule := range fingerprintRules {
+		normalized = rule.pattern.ReplaceAllString(normalized, rule.replacement)
+	}
+	return normalized
+}
+
+// ErrorFingerprint identifies the root cause of an error by its code and
+// normalized message.
+func ErrorFingerprint(code, message string) string {
+	sum := sha256.Sum256([]byte(code + "\x00" + NormalizeErrorMessage(message)))
+	return hex.EncodeToString(sum[:6])
+}
+
+// groupErrors fingerprints every errored observation and returns the groups
+// with at least minErrorGroupSize members, largest first.
+func groupErrors(controls []ControlResult) []ErrorGroup {
+	byFingerprint := make(map[string]*ErrorGroup)
+	var order []string
+
+	for _, ctrl := range controls {
+		for i, obs := range ctrl.ObservationResults {
+			code, message, ok := observationError(obs)
+			if !ok {
+				continue
+			}
+
+			fingerprint := ErrorFingerprint(code, message)
+			group, seen := byFingerprint[fingerprint]
+			if !seen {
+				group = &ErrorGroup{
+					Fingerprint: fingerprint,
+					Code:        code,
+					Message:     NormalizeErrorMessage(message),
+					Example:     message,
+				}
+				byFingerprint[fingerprint] = group
+				order = append(order, fingerprint)
+			}
+			group.Observations = append(group.Observations, ObservationRef{
+				ControlID: ctrl.ID,
+				Plugin:    obs.Plugin,
+				Index:     i,
+			})
+		}
+	}
+
+	var groups []ErrorGroup
+	for _, fingerprint := range order {
+		if group := byFingerprint[fingerprint]; len(group.Observations) >= minErrorGroupSize {
+			groups = append(groups, *group)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].Observations) > len(groups[j].Observations)
+	})
+	return groups
+}
+
+// observationError returns the error of an errored observation, from the
+// host or, failing that, from the plugin's own evidence.
+func observationError(obs ObservationResult) (code, message string, ok bool) {
+	if obs.Status != values.StatusError {
+		return "", "", false
+	}
+	switch {
+	case obs.Error != nil:
+		return obs.Error.Code, obs.Error.Message, true
+	case obs.Evidence != nil && obs.Evidence.Error != nil:
+		return obs.Evidence.Error.Code, obs.Evidence.Error.Message, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,95 @@
+package execution_test
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeErrorMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"dial tcp 10.0.0.5:443: connect: no route to host", "dial tcp <addr>: connect: no route to host"},
+		{"dial tcp [2001:db8::1]:443: connect: no route to host", "dial tcp <addr>: connect: no route to host"},
+		{"lookup api.example.com on 127.0.0.53:53: server misbehaving", "lookup <host> on <addr>: server misbehaving"},
+		{`Get "https://api.example.com/health": context deadline exceeded`, `Get "…": context deadline exceeded`},
+		{"request to https://a.example/x?y=1 timed out after 30s", "request to <url> timed out after <n>"},
+		{"open /etc/ssh/sshd_config: permission denied", "open <path>: permission denied"},
+		{"exit status 127", "exit status <n>"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, execution.NormalizeErrorMessage(tt.message), tt.message)
+	}
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	t.Parallel()
+
+	a := execution.ErrorFingerprint("network", "dial tcp 10.0.0.5:443: connect: no route to host")
+	b := execution.ErrorFingerprint("network", "dial tcp 10.0.0.9:8443: connect: no route to host")
+	assert.Equal(t, a, b, "same cause, different targets")
+	assert.Len(t, a, 12)
+
+	assert.NotEqual(t, a, execution.ErrorFingerprint("timeout", "dial tcp 10.0.0.5:443: connect: no route to host"), "code is part of the fingerprint")
+	assert.NotEqual(t, a, execution.ErrorFingerprint("network", "dial tcp 10.0.0.5:443: connect: connection refused"))
+}
+
+func TestExecutionResult_Finalize_GroupsErrors(t *testing.T) {
+	t.Parallel()
+
+	errored := func(plugin, code, message string) execution.ObservationResult {
+		return execution.ObservationResult{
+			Plugin: plugin,
+			Status: values.StatusError,
+			Error:  &execution.PluginError{Code: code, Message: message},
+		}
+	}
+
+	result := execution.NewExecutionResult("profile", "1.0.0")
+	result.AddControlResult(execution.ControlResult{ID: "web", Index: 0, ObservationResults: []execution.ObservationResult{
+		errored("http", "network", "lookup web.example on 127.0.0.53:53: no such host"),
+		{Plugin: "http", Status: values.StatusPass},
+	}})
+	result.AddControlResult(execution.ControlResult{ID: "db", Index: 1, ObservationResults: []execution.ObservationResult{
+		errored("tcp", "network", "lookup db.example on 127.0.0.53:53: no such host"),
+		errored("tcp", "timeout", "i/o timeout"),
+	}})
+	result.AddControlResult(execution.ControlResult{ID: "mail", Index: 2, ObservationResults: []execution.ObservationResult{
+		errored("smtp", "network", "lookup mx.example on 127.0.0.53:53: no such host"),
+		// Plugin-reported errors count too
+		{Plugin: "smtp", Status: values.StatusError, Evidence: &execution.Evidence{
+			Error: &execution.PluginError{Code: "timeout", Message: "i/o timeout"},
+		}},
+	}})
+	result.AddControlResult(execution.ControlResult{ID: "file", Index: 3, ObservationResults: []execution.ObservationResult{
+		errored("file", "io", "open /etc/shadow: permission denied"),
+	}})
+	result.Finalize()
+
+	require.Len(t, result.ErrorGroups, 2, "a lone error is not grouped")
+
+	dns := result.ErrorGroups[0]
+	assert.Equal(t, "network", dns.Code)
+	assert.Equal(t, "lookup <host> on <addr>: no such host", dns.Message)
+	assert.Equal(t, "lookup web.example on 127.0.0.53:53: no such host", dns.Example)
+	assert.Equal(t, []execution.ObservationRef{
+		{ControlID: "web", Plugin: "http", Index: 0},
+		{ControlID: "db", Plugin: "tcp", Index: 0},
+		{ControlID: "mail", Plugin: "smtp", Index: 0},
+	}, dns.Observations)
+
+	timeout := result.ErrorGroups[1]
+	assert.Equal(t, "timeout", timeout.Code)
+	assert.Equal(t, []execution.ObservationRef{
+		{ControlID: "db", Plugin: "tcp", Index: 1},
+		{ControlID: "mail", Plugin: "smtp", Index: 1},
+	}, timeout.Observations)
+}
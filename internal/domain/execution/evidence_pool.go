@@ -0,0 +1,50 @@
+package execution
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// maxPooledBufferSize caps the buffers kept for reuse so a single oversized
+// evidence payload does not pin its memory for the rest of the run.
+const maxPooledBufferSize = 1 << 20
+
+// jsonBufferPool holds buffers for encoding evidence and plugin config. Runs
+// with thousands of observations otherwise allocate a fresh buffer for every
+// size check and config encoding.
+var jsonBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// EncodeJSON encodes v into a pooled buffer and passes the encoding to fn.
+// The bytes are identical to json.Marshal's but only valid until fn returns;
+// fn must copy anything it keeps. It is safe for concurrent use.
+func EncodeJSON(v any, fn func(data []byte) error) error {
+	buf, ok := jsonBufferPool.Get().(*bytes.Buffer)
+	if !ok {
+		buf = new(bytes.Buffer)
+	}
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			buf.Reset()
+			jsonBufferPool.Put(buf)
+		}
+	}()
+
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return err
+	}
+	// Encode terminates the value with a newline; Marshal does not
+	return fn(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+// EncodedSize returns the length of v's JSON encoding without keeping it.
+func EncodedSize(v any) (int, error) {
+	var size int
+	err := EncodeJSON(v, func(data []byte) error {
+		size = len(data)
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,82 @@
+package execution_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeJSON_MatchesMarshal(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]interface{}{
+		"html":   "<a href=\"x\">&</a>",
+		"nested": map[string]interface{}{"list": []interface{}{1, "two", 3.5}},
+		"empty":  nil,
+	}
+	want, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	err = execution.EncodeJSON(data, func(got []byte) error {
+		assert.Equal(t, string(want), string(got))
+		return nil
+	})
+	require.NoError(t, err)
+
+	size, err := execution.EncodedSize(data)
+	require.NoError(t, err)
+	assert.Equal(t, len(want), size)
+}
+
+func TestEncodeJSON_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := execution.EncodedSize(map[string]interface{}{"ch": make(chan int)})
+	assert.Error(t, err)
+
+	sentinel := errors.New("stop")
+	err = execution.EncodeJSON("x", func([]byte) error { return sentinel })
+	assert.ErrorIs(t, err, sentinel)
+}
+
+func TestEncodeJSON_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value := map[string]interface{}{"id": i, "body": strings.Repeat(fmt.Sprint(i), 100*i)}
+			want, _ := json.Marshal(value)
+			_ = execution.EncodeJSON(value, func(got []byte) error {
+				assert.Equal(t, want, got, "buffers must not be shared between callers")
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkGreedyTruncator_WithinLimit(b *testing.B) {
+	truncator := &execution.GreedyTruncator{}
+	data := map[string]interface{}{
+		"path":        "/etc/ssh/sshd_config",
+		"exists":      true,
+		"size":        3285,
+		"mode":        "0644",
+		"permissions": map[string]interface{}{"owner": "root", "group": "root"},
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _, _ = truncator.Truncate(data, execution.DefaultMaxEvidenceSize)
+	}
+}
@@ -0,0 +1,42 @@
+package execution
+
+import "context"
+
+// Hooks observe an execution while it runs, e.g. to report progress. The
+// engine calls them synchronously from the goroutine running the control
+// or observation, so they must return quickly and, with parallel execution,
+// be safe for concurrent calls.
+type Hooks interface {
+	// OnControlStart is called before a control runs, skipped ones included.
+	// Only the fields known from the definition (ID, Name, Severity, Tags,
+	// Labels, Index) are set.
+	OnControlStart(ctx context.Context, control ControlResult)
+
+	// OnControlEnd is called with the result of a control, before it is
+	// recorded or streamed.
+	OnControlEnd(ctx context.Context, control ControlResult)
+
+	// OnObservationStart is called before an observation runs; retried
+	// controls run their observations again.
+	OnObservationStart(ctx context.Context, ref ObservationRef)
+
+	// OnObservationEnd is called with the result of an observation, after
+	// its evidence has been truncated.
+	OnObservationEnd(ctx context.Context, ref ObservationRef, result ObservationResult)
+}
+
+// NopHooks implements Hooks doing nothing. Embed it to implement only some
+// of the hooks.
+type NopHooks struct{}
+
+// OnControlStart implements Hooks.
+func (NopHooks) OnControlStart(context.Context, ControlResult) {}
+
+// OnControlEnd implements Hooks.
+func (NopHooks) OnControlEnd(context.Context, ControlResult) {}
+
+// OnObservationStart implements Hooks.
+func (NopHooks) OnObservationStart(context.Context, ObservationRef) {}
+
+// OnObservationEnd implements Hooks.
+func (NopHooks) OnObservationEnd(context.Context, ObservationRef, ObservationResult) {}
@@ -0,0 +1,43 @@
+package execution
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+)
+
+// IdempotencyKey derives the key a side-effecting plugin (remediation,
+// ticket creation) passes to the systems it changes, so they can drop
+// duplicate actions. It depends only on what the observation does: the
+// profile, environment, control, observation position, plugin and config.
+// Retries and later runs of an unchanged observation therefore get the same
+// key, while changing its config yields a new one.
+func IdempotencyKey(run RunRef, ref ObservationRef, config map[string]interface{}) string {
+	h := sha256.New()
+	for _, part := range []string{run.Profile, run.Environment, ref.ControlID, strconv.Itoa(ref.Index), ref.Plugin} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	// encoding/json sorts map keys, so equal configs encode equally
+	if data, err := json.Marshal(config); err == nil {
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+type idempotencyKeyKey struct{}
+
+// WithIdempotencyKey attaches the idempotency key of the observation being
+// executed to the context.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key attached to the
+// context, if any.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyKey{}).(string)
+	return key, ok
+}
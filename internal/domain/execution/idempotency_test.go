@@ -0,0 +1,44 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	run := RunRef{ExecutionID: "run-1", Profile: "remediation", Environment: "prod"}
+	ref := ObservationRef{ControlID: "open-ticket", Plugin: "jira", Index: 0}
+	config := map[string]interface{}{"project": "OPS", "summary": "SSH root login enabled"}
+	key := IdempotencyKey(run, ref, config)
+	assert.Len(t, key, 32)
+
+	rerun := run
+	rerun.ExecutionID = "run-2"
+	assert.Equal(t, key, IdempotencyKey(rerun, ref, map[string]interface{}{"summary": "SSH root login enabled", "project": "OPS"}),
+		"re-runs of the same observation share the key")
+
+	otherEnv := run
+	otherEnv.Environment = "staging"
+	assert.NotEqual(t, key, IdempotencyKey(otherEnv, ref, config))
+
+	otherIndex := ref
+	otherIndex.Index = 1
+	assert.NotEqual(t, key, IdempotencyKey(run, otherIndex, config))
+
+	assert.NotEqual(t, key, IdempotencyKey(run, ref, map[string]interface{}{"project": "SEC", "summary": "SSH root login enabled"}))
+}
+
+func TestIdempotencyKeyContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := IdempotencyKeyFromContext(context.Background())
+	assert.False(t, ok)
+
+	key, ok := IdempotencyKeyFromContext(WithIdempotencyKey(context.Background(), "abc"))
+	assert.True(t, ok)
+	assert.Equal(t, "abc", key)
+}
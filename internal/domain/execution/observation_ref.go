@@ -0,0 +1,44 @@
+package execution
+
+import "context"
+
+// ObservationRef identifies one observation of a control.
+type ObservationRef struct {
+	ControlID string `json:"control_id" yaml:"control_id"`
+	Plugin    string `json:"plugin" yaml:"plugin"`
+	Index     int    `json:"index" yaml:"index"` // Position within the control's observations
+}
+
+type observationRefKey struct{}
+
+// WithObservationRef attaches the observation being executed to the context,
+// so components below the engine know which control they run for.
+func WithObservationRef(ctx context.Context, ref ObservationRef) context.Context {
+	return context.WithValue(ctx, observationRefKey{}, ref)
+}
+
+// ObservationRefFromContext returns the observation attached to the context, if any.
+func ObservationRefFromContext(ctx context.Context) (ObservationRef, bool) {
+	ref, ok := ctx.Value(observationRefKey{}).(ObservationRef)
+	return ref, ok
+}
+
+// RunRef identifies the execution components below the engine run for.
+type RunRef struct {
+	ExecutionID string `json:"execution_id" yaml:"execution_id"`
+	Profile     string `json:"profile" yaml:"profile"`
+	Environment string `json:"environment,omitempty" yaml:"environment,omitempty"`
+}
+
+type runRefKey struct{}
+
+// WithRunRef attaches the execution being run to the context.
+func WithRunRef(ctx context.Context, ref RunRef) context.Context {
+	return context.WithValue(ctx, runRefKey{}, ref)
+}
+
+// RunRefFromContext returns the execution attached to the context, if any.
+func RunRefFromContext(ctx context.Context) (RunRef, bool) {
+	ref, ok := ctx.Value(runRefKey{}).(RunRef)
+	return ref, ok
+}
@@ -0,0 +1,300 @@
+package execution
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Phases of a run reported by the performance breakdown.
+const (
+	PhaseProfileLoad          = "profile_load"          // loading and compiling the profile
+	PhaseCapabilityCollection = "capability_collection" // collecting and granting plugin capabilities
+	PhasePluginCompile        = "plugin_compile"        // compiling WASM modules, summed over plugins and overlapping the phase that needs them
+	PhaseExecution            = "execution"             // running the controls
+)
+
+// phaseOrder is the order phases are reported in.
+var phaseOrder = []string{PhaseProfileLoad, PhaseCapabilityCollection, PhasePluginCompile, PhaseExecution}
+
+// PerformanceReport breaks a run's time down by phase and, for each plugin,
+// splits observation time into instantiation, plugin execution and host I/O.
+type PerformanceReport struct {
+	Phases  []PhaseTiming  `json:"phases" yaml:"phases"`
+	Plugins []PluginTiming `json:"plugins" yaml:"plugins"`
+
+	DNS  *DNSStats  `json:"dns,omitempty" yaml:"dns,omitempty"`   // set when plugins resolved names
+	HTTP *HTTPStats `json:"http,omitempty" yaml:"http,omitempty"` // set when plugins made HTTP requests
+
+	Traffic *TrafficReport `json:"traffic,omitempty" yaml:"traffic,omitempty"` // set when plugins made network calls
+
+	Scheduling []SchedulingStats `json:"scheduling,omitempty" yaml:"scheduling,omitempty"` // set when controls ran in parallel
+}
+
+// SchedulingStats shows how the parallel worker pool shared its workers with
+// the controls of one plugin: how many it ran, how long they waited for a
+// worker once their dependencies were met, and the most workers they held at
+// once. Controls are scheduled under the plugin of their first observation.
+type SchedulingStats struct {
+	Plugin      string        `json:"plugin" yaml:"plugin"`
+	Weight      int           `json:"weight" yaml:"weight"`
+	Controls    int           `json:"controls" yaml:"controls"`
+	TotalWait   time.Duration `json:"total_wait_ns" yaml:"total_wait_ns"`
+	MaxWait     time.Duration `json:"max_wait_ns" yaml:"max_wait_ns"`
+	PeakWorkers int           `json:"peak_workers" yaml:"peak_workers"`
+}
+
+// TrafficReport breaks the network calls of a run down by plugin and by
+// destination (host:port), each sorted by name.
+type TrafficReport struct {
+	Plugins      []TrafficStats `json:"plugins" yaml:"plugins"`
+	Destinations []TrafficStats `json:"destinations" yaml:"destinations"`
+}
+
+// TrafficStats counts the network host calls of a plugin or to a
+// destination, and the request and response bytes they exchanged with it.
+type TrafficStats struct {
+	Name          string `json:"name" yaml:"name"`
+	Requests      int    `json:"requests" yaml:"requests"`
+	BytesSent     int64  `json:"bytes_sent" yaml:"bytes_sent"`
+	BytesReceived int64  `json:"bytes_received" yaml:"bytes_received"`
+}
+
+// DNSStats counts the DNS lookups plugins made during a run and how many
+// were answered from the run's DNS cache.
+type DNSStats struct {
+	Lookups   int `json:"lookups" yaml:"lookups"`
+	CacheHits int `json:"cache_hits" yaml:"cache_hits"`
+}
+
+// HTTPStats counts the connections plugins' HTTP requests used during a run,
+// how many were reused from the run's connection pool, and how many requests
+// the run's response cache answered without a connection.
+type HTTPStats struct {
+	Connections       int `json:"connections" yaml:"connections"`
+	ReusedConnections int `json:"reused_connections" yaml:"reused_connections"`
+	CachedResponses   int `json:"cached_responses" yaml:"cached_responses"`
+}
+
+// PhaseTiming is the time spent in one phase of a run.
+type PhaseTiming struct {
+	Phase    string        `json:"phase" yaml:"phase"`
+	Duration time.Duration `json:"duration_ns" yaml:"duration_ns"`
+}
+
+// PluginTiming sums the observation timings of one plugin.
+type PluginTiming struct {
+	Plugin            string `json:"plugin" yaml:"plugin"`
+	ObservationTiming `yaml:",inline"`
+	Observations      int `json:"observations" yaml:"observations"`
+}
+
+// ObservationTiming splits the time of a plugin call. Instantiation is
+// creating the plugin instance, host I/O the time spent in network and exec
+// host functions, and execution the rest of the call.
+type ObservationTiming struct {
+	Instantiation time.Duration `json:"instantiation_ns" yaml:"instantiation_ns"`
+	Execution     time.Duration `json:"execution_ns" yaml:"execution_ns"`
+	HostIO        time.Duration `json:"host_io_ns" yaml:"host_io_ns"`
+}
+
+// PerfRecorder collects phase and observation timings while a run executes.
+// It is safe for concurrent use.
+type PerfRecorder struct {
+	plugins map[string]*PluginTiming
+	phases  []PhaseTiming
+	dns     DNSStats
+	http    HTTPStats
+	traffic map[string]*TrafficStats // by plugin
+	targets map[string]*TrafficStats // by destination
+	sched   map[string]*SchedulingStats
+	mu      sync.Mutex
+}
+
+// NewPerfRecorder creates an empty recorder.
+func NewPerfRecorder() *PerfRecorder {
+	return &PerfRecorder{
+		plugins: make(map[string]*PluginTiming),
+		traffic: make(map[string]*TrafficStats),
+		targets: make(map[string]*TrafficStats),
+		sched:   make(map[string]*SchedulingStats),
+	}
+}
+
+// RecordPhase adds time spent in a phase. Repeated phases, such as compiling
+// several plugins, accumulate.
+func (r *PerfRecorder) RecordPhase(phase string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.phases {
+		if r.phases[i].Phase == phase {
+			r.phases[i].Duration += d
+			return
+		}
+	}
+	r.phases = append(r.phases, PhaseTiming{Phase: phase, Duration: d})
+}
+
+// RecordObservation adds the timing of one observation run by plugin.
+func (r *PerfRecorder) RecordObservation(plugin string, timing ObservationTiming) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	total, ok := r.plugins[plugin]
+	if !ok {
+		total = &PluginTiming{Plugin: plugin}
+		r.plugins[plugin] = total
+	}
+	total.Observations++
+	total.Instantiation += timing.Instantiation
+	total.Execution += timing.Execution
+	total.HostIO += timing.HostIO
+}
+
+// RecordDNSLookup counts a DNS lookup made by a plugin; cached reports
+// whether it was answered from the DNS cache.
+func (r *PerfRecorder) RecordDNSLookup(cached bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dns.Lookups++
+	if cached {
+		r.dns.CacheHits++
+	}
+}
+
+// RecordHTTPConnection counts a connection used by a plugin's HTTP request;
+// reused reports whether it was kept open from an earlier request.
+func (r *PerfRecorder) RecordHTTPConnection(reused bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.http.Connections++
+	if reused {
+		r.http.ReusedConnections++
+	}
+}
+
+// RecordHTTPCacheHit counts a plugin's HTTP request answered from the
+// response cache.
+func (r *PerfRecorder) RecordHTTPCacheHit() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.http.CachedResponses++
+}
+
+// RecordTraffic counts a network host call plugin made to destination, and
+// the bytes of its request and response.
+func (r *PerfRecorder) RecordTraffic(plugin, destination string, sent, received int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	addTraffic(r.traffic, plugin, sent, received)
+	addTraffic(r.targets, destination, sent, received)
+}
+
+// RecordScheduling counts a control of plugin handed to a worker after
+// waiting for wait, with workers the number of workers the plugin's controls
+// now hold and weight its share of the pool.
+func (r *PerfRecorder) RecordScheduling(plugin string, weight int, wait time.Duration, workers int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.sched[plugin]
+	if !ok {
+		s = &SchedulingStats{Plugin: plugin, Weight: weight}
+		r.sched[plugin] = s
+	}
+	s.Controls++
+	s.TotalWait += wait
+	s.MaxWait = max(s.MaxWait, wait)
+	s.PeakWorkers = max(s.PeakWorkers, workers)
+}
+
+// addTraffic counts a call in the stats of name.
+func addTraffic(stats map[string]*TrafficStats, name string, sent, received int64) {
+	s, ok := stats[name]
+	if !ok {
+		s = &TrafficStats{Name: name}
+		stats[name] = s
+	}
+	s.Requests++
+	s.BytesSent += sent
+	s.BytesReceived += received
+}
+
+// sortedTraffic returns the traffic stats in stats sorted by name.
+func sortedTraffic(stats map[string]*TrafficStats) []TrafficStats {
+	sorted := make([]TrafficStats, 0, len(stats))
+	for _, s := range stats {
+		sorted = append(sorted, *s)
+	}
+	slices.SortFunc(sorted, func(a, b TrafficStats) int { return cmp.Compare(a.Name, b.Name) })
+	return sorted
+}
+
+// Report returns the timings recorded so far. Phases are in run order,
+// plugins by name.
+func (r *PerfRecorder) Report() *PerformanceReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &PerformanceReport{
+		Phases:  slices.Clone(r.phases),
+		Plugins: make([]PluginTiming, 0, len(r.plugins)),
+	}
+	if r.dns.Lookups > 0 {
+		dns := r.dns
+		report.DNS = &dns
+	}
+	if r.http.Connections > 0 || r.http.CachedResponses > 0 {
+		http := r.http
+		report.HTTP = &http
+	}
+	if len(r.traffic) > 0 {
+		report.Traffic = &TrafficReport{Plugins: sortedTraffic(r.traffic), Destinations: sortedTraffic(r.targets)}
+	}
+	for _, s := range r.sched {
+		report.Scheduling = append(report.Scheduling, *s)
+	}
+	slices.SortFunc(report.Scheduling, func(a, b SchedulingStats) int { return cmp.Compare(a.Plugin, b.Plugin) })
+	slices.SortStableFunc(report.Phases, func(a, b PhaseTiming) int {
+		return cmp.Compare(phaseRank(a.Phase), phaseRank(b.Phase))
+	})
+	for _, timing := range r.plugins {
+		report.Plugins = append(report.Plugins, *timing)
+	}
+	slices.SortFunc(report.Plugins, func(a, b PluginTiming) int {
+		return cmp.Compare(a.Plugin, b.Plugin)
+	})
+	return report
+}
+
+// phaseRank orders known phases by phaseOrder and unknown ones after them.
+func phaseRank(phase string) int {
+	if i := slices.Index(phaseOrder, phase); i >= 0 {
+		return i
+	}
+	return len(phaseOrder)
+}
+
+type perfRecorderKey struct{}
+
+// WithPerfRecorder attaches a recorder to the context. Components that do
+// timed work on behalf of the run record into it.
+func WithPerfRecorder(ctx context.Context, recorder *PerfRecorder) context.Context {
+	if recorder == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, perfRecorderKey{}, recorder)
+}
+
+// PerfRecorderFromContext returns the recorder attached to the context, if any.
+func PerfRecorderFromContext(ctx context.Context) (*PerfRecorder, bool) {
+	recorder, ok := ctx.Value(perfRecorderKey{}).(*PerfRecorder)
+	return recorder, ok
+}
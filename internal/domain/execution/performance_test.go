@@ -0,0 +1,117 @@
+package execution
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerfRecorder_Report(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewPerfRecorder()
+	recorder.RecordPhase(PhaseProfileLoad, time.Millisecond)
+	recorder.RecordPhase(PhasePluginCompile, 2*time.Second)
+	recorder.RecordPhase(PhasePluginCompile, time.Second)
+	recorder.RecordPhase(PhaseCapabilityCollection, 3*time.Second)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder.RecordObservation("http", ObservationTiming{
+				Instantiation: 2 * time.Millisecond,
+				Execution:     time.Millisecond,
+				HostIO:        5 * time.Millisecond,
+			})
+		}()
+	}
+	wg.Wait()
+	recorder.RecordObservation("file", ObservationTiming{Instantiation: time.Millisecond})
+
+	report := recorder.Report()
+	assert.Equal(t, []PhaseTiming{
+		{Phase: PhaseProfileLoad, Duration: time.Millisecond},
+		{Phase: PhaseCapabilityCollection, Duration: 3 * time.Second},
+		{Phase: PhasePluginCompile, Duration: 3 * time.Second},
+	}, report.Phases, "phases are in run order and repeated phases accumulate")
+
+	require.Len(t, report.Plugins, 2)
+	assert.Equal(t, "file", report.Plugins[0].Plugin)
+	assert.Equal(t, PluginTiming{
+		Plugin:       "http",
+		Observations: 10,
+		ObservationTiming: ObservationTiming{
+			Instantiation: 20 * time.Millisecond,
+			Execution:     10 * time.Millisecond,
+			HostIO:        50 * time.Millisecond,
+		},
+	}, report.Plugins[1])
+}
+
+func TestPerfRecorder_DNS(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewPerfRecorder()
+	assert.Nil(t, recorder.Report().DNS, "no lookups, no DNS stats")
+
+	recorder.RecordDNSLookup(false)
+	recorder.RecordDNSLookup(true)
+	recorder.RecordDNSLookup(true)
+	assert.Equal(t, &DNSStats{Lookups: 3, CacheHits: 2}, recorder.Report().DNS)
+}
+
+func TestPerfRecorder_HTTP(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewPerfRecorder()
+	assert.Nil(t, recorder.Report().HTTP, "no requests, no HTTP stats")
+
+	recorder.RecordHTTPConnection(false)
+	recorder.RecordHTTPConnection(true)
+	assert.Equal(t, &HTTPStats{Connections: 2, ReusedConnections: 1}, recorder.Report().HTTP)
+
+	recorder.RecordHTTPCacheHit()
+	assert.Equal(t, &HTTPStats{Connections: 2, ReusedConnections: 1, CachedResponses: 1}, recorder.Report().HTTP)
+}
+
+func TestPerfRecorder_Traffic(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewPerfRecorder()
+	assert.Nil(t, recorder.Report().Traffic, "no calls, no traffic")
+
+	recorder.RecordTraffic("http", "example.com:443", 100, 2000)
+	recorder.RecordTraffic("tcp", "db:5432", 10, 20)
+	recorder.RecordTraffic("http", "api.example.com:443", 50, 500)
+
+	assert.Equal(t, &TrafficReport{
+		Plugins: []TrafficStats{
+			{Name: "http", Requests: 2, BytesSent: 150, BytesReceived: 2500},
+			{Name: "tcp", Requests: 1, BytesSent: 10, BytesReceived: 20},
+		},
+		Destinations: []TrafficStats{
+			{Name: "api.example.com:443", Requests: 1, BytesSent: 50, BytesReceived: 500},
+			{Name: "db:5432", Requests: 1, BytesSent: 10, BytesReceived: 20},
+			{Name: "example.com:443", Requests: 1, BytesSent: 100, BytesReceived: 2000},
+		},
+	}, recorder.Report().Traffic)
+}
+
+func TestPerfRecorder_Context(t *testing.T) {
+	t.Parallel()
+
+	_, ok := PerfRecorderFromContext(context.Background())
+	assert.False(t, ok)
+	assert.Equal(t, context.Background(), WithPerfRecorder(context.Background(), nil))
+
+	recorder := NewPerfRecorder()
+	got, ok := PerfRecorderFromContext(WithPerfRecorder(context.Background(), recorder))
+	require.True(t, ok)
+	assert.Same(t, recorder, got)
+}
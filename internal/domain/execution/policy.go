@@ -0,0 +1,64 @@
+package execution
+
+import (
+	"context"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// PolicyControlPrefix prefixes the IDs of controls derived from policy findings,
+// keeping them apart from the profile's own control IDs.
+const PolicyControlPrefix = "policy:"
+
+// PolicyTag is added to every control derived from a policy finding.
+const PolicyTag = "policy"
+
+// ResultPolicy evaluates organization-specific gating rules over a complete
+// execution result before it is finalized, for example "fail the run if more
+// than three medium findings carry the prod tag".
+//
+// Evaluate receives the result with controls in definition order and the
+// summary already calculated. The findings it returns are recorded as failing
+// controls, so they fail the run and appear in every output format.
+type ResultPolicy interface {
+	Evaluate(ctx context.Context, result *ExecutionResult) ([]PolicyFinding, error)
+}
+
+// PolicyFinding is a finding derived by a ResultPolicy.
+type PolicyFinding struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name,omitempty"`
+	Message  string   `json:"message"`
+	Severity string   `json:"severity,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Controls []string `json:"controls,omitempty"` // IDs of the controls the finding is about
+}
+
+// ControlResult converts the finding into a failing control at the given
+// definition index.
+func (f PolicyFinding) ControlResult(index int) ControlResult {
+	name := f.Name
+	if name == "" {
+		name = f.ID
+	}
+
+	tags := append([]string{PolicyTag}, f.Tags...)
+
+	description := ""
+	if len(f.Controls) > 0 {
+		description = "Derived from controls: " + strings.Join(f.Controls, ", ")
+	}
+
+	return ControlResult{
+		ID:                 PolicyControlPrefix + f.ID,
+		Name:               name,
+		Description:        description,
+		Severity:           f.Severity,
+		Status:             values.StatusFail,
+		Message:            f.Message,
+		Tags:               tags,
+		ObservationResults: []ObservationResult{},
+		Index:              index,
+	}
+}
@@ -0,0 +1,111 @@
+package execution
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Provenance records how the reglet binary and the plugins that produced a
+// result were built.
+type Provenance struct {
+	Reglet  BuildInfo   `json:"reglet" yaml:"reglet"`
+	Plugins []BuildInfo `json:"plugins,omitempty" yaml:"plugins,omitempty"` // by name, then version
+	mu      sync.Mutex
+}
+
+// BuildInfo describes the build of reglet or of a plugin.
+type BuildInfo struct {
+	Name       string `json:"name,omitempty" yaml:"name,omitempty"` // plugin name; empty for reglet
+	Version    string `json:"version" yaml:"version"`
+	Commit     string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	BuildTime  string `json:"build_time,omitempty" yaml:"build_time,omitempty"`
+	GoVersion  string `json:"go_version,omitempty" yaml:"go_version,omitempty"`
+	SDKVersion string `json:"sdk_version,omitempty" yaml:"sdk_version,omitempty"` // plugins built with the Go SDK
+	Platform   string `json:"platform,omitempty" yaml:"platform,omitempty"`       // reglet only
+}
+
+// NewProvenance creates the provenance of a run of the given reglet build.
+func NewProvenance(reglet BuildInfo) *Provenance {
+	return &Provenance{Reglet: reglet}
+}
+
+// RecordPlugin adds the build of a plugin the run used, once per name and
+// version. A plugin reloaded during the run is listed with each version.
+// Thread-safe.
+func (p *Provenance) RecordPlugin(build BuildInfo) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, known := range p.Plugins {
+		if known.Name == build.Name && known.Version == build.Version {
+			return
+		}
+	}
+	p.Plugins = append(p.Plugins, build)
+	slices.SortFunc(p.Plugins, func(a, b BuildInfo) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+	})
+}
+
+type provenanceKey struct{}
+
+// WithProvenance attaches the provenance of a run to the context, for the
+// components that load plugins to record their builds.
+func WithProvenance(ctx context.Context, p *Provenance) context.Context {
+	if p == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, provenanceKey{}, p)
+}
+
+// ProvenanceFromContext returns the provenance attached to the context, if any.
+func ProvenanceFromContext(ctx context.Context) (*Provenance, bool) {
+	p, ok := ctx.Value(provenanceKey{}).(*Provenance)
+	return p, ok
+}
+
+// NodeRun records the part of a distributed run one agent executed.
+type NodeRun struct {
+	StartTime     time.Time `json:"start_time" yaml:"start_time"`
+	EndTime       time.Time `json:"end_time" yaml:"end_time"`
+	Node          string    `json:"node" yaml:"node"`
+	Address       string    `json:"address,omitempty" yaml:"address,omitempty"`
+	RegletVersion string    `json:"reglet_version,omitempty" yaml:"reglet_version,omitempty"`
+	// ExecutionID identifies the agent's partial result
+	ExecutionID string `json:"execution_id,omitempty" yaml:"execution_id,omitempty"`
+	// Error is set when the agent could not return a result
+	Error    string   `json:"error,omitempty" yaml:"error,omitempty"`
+	Controls []string `json:"controls" yaml:"controls"`
+}
+
+// AddNodeResult merges the controls run.Controls assigns to a node from its
+// partial result, recording the node on each control, and adds run to the
+// result's provenance. Controls the partial result lacks are returned so
+// the caller can account for them. Thread-safe.
+func (r *ExecutionResult) AddNodeResult(run NodeRun, partial *ExecutionResult) (missing []string) {
+	found := make(map[string]*ControlResult)
+	if partial != nil {
+		for i := range partial.Controls {
+			found[partial.Controls[i].ID] = &partial.Controls[i]
+		}
+	}
+
+	for _, id := range run.Controls {
+		ctrl, ok := found[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		merged := *ctrl
+		merged.Node = run.Node
+		r.AddPartialResult(merged)
+	}
+
+	r.mu.Lock()
+	r.Nodes = append(r.Nodes, run)
+	r.mu.Unlock()
+	return missing
+}
@@ -0,0 +1,57 @@
+package execution_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionResult_AddNodeResult(t *testing.T) {
+	t.Parallel()
+
+	partial := execution.NewExecutionResult("fleet", "1.0.0")
+	partial.AddControlResult(execution.ControlResult{ID: "ssh", Index: 1, Status: values.StatusPass})
+	partial.AddControlResult(execution.ControlResult{ID: "nginx", Index: 0, Status: values.StatusSkipped})
+
+	result := execution.NewExecutionResult("fleet", "1.0.0")
+	missing := result.AddNodeResult(execution.NodeRun{Node: "web-1", Controls: []string{"ssh", "dns"}}, partial)
+	assert.Equal(t, []string{"dns"}, missing)
+
+	missing = result.AddNodeResult(execution.NodeRun{Node: "web-2", Controls: []string{"nginx"}, Error: "unreachable"}, nil)
+	assert.Equal(t, []string{"nginx"}, missing, "a failed node returns no controls")
+
+	require.Len(t, result.Controls, 1, "controls assigned elsewhere are not merged")
+	assert.Equal(t, "ssh", result.Controls[0].ID)
+	assert.Equal(t, "web-1", result.Controls[0].Node)
+	assert.Empty(t, partial.Controls[0].Node, "the partial result is not modified")
+
+	require.Len(t, result.Nodes, 2)
+	assert.Equal(t, "web-1", result.Nodes[0].Node)
+	assert.Equal(t, "unreachable", result.Nodes[1].Error)
+}
+
+func TestProvenance_RecordPlugin(t *testing.T) {
+	t.Parallel()
+
+	p := execution.NewProvenance(execution.BuildInfo{Version: "1.0.0", Commit: "abc1234"})
+	ctx := execution.WithProvenance(context.Background(), p)
+
+	recorded, ok := execution.ProvenanceFromContext(ctx)
+	require.True(t, ok)
+	recorded.RecordPlugin(execution.BuildInfo{Name: "http", Version: "1.1.0"})
+	recorded.RecordPlugin(execution.BuildInfo{Name: "file", Version: "1.0.0", SDKVersion: "0.1.0"})
+	recorded.RecordPlugin(execution.BuildInfo{Name: "file", Version: "1.0.0", SDKVersion: "0.1.0"})
+	recorded.RecordPlugin(execution.BuildInfo{Name: "file", Version: "1.0.1"})
+
+	require.Len(t, p.Plugins, 3, "each plugin version is recorded once")
+	assert.Equal(t, "file", p.Plugins[0].Name)
+	assert.Equal(t, "1.0.1", p.Plugins[1].Version)
+	assert.Equal(t, "http", p.Plugins[2].Name)
+
+	_, ok = execution.ProvenanceFromContext(context.Background())
+	assert.False(t, ok)
+}
@@ -0,0 +1,134 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// CodeQuotaExceeded is the error code of observations refused, or cut
+// short, because their plugin used up one of its quotas.
+const CodeQuotaExceeded = "quota_exceeded"
+
+// ErrQuotaExceeded is returned when a plugin has used up one of its quotas.
+var ErrQuotaExceeded = errors.New("plugin quota exceeded")
+
+// QuotaUsage is what a plugin used during a run.
+type QuotaUsage struct {
+	Invocations int
+	Runtime     time.Duration
+	HostCalls   int
+}
+
+// PluginQuotas tracks the usage of plugins against the quotas of a run.
+// Plugins without a quota are not tracked. It is safe for concurrent use;
+// observations of a plugin running in parallel may together overrun its
+// runtime quota by up to one observation each.
+type PluginQuotas struct {
+	quotas map[string]entities.PluginQuota
+
+	mu    sync.Mutex
+	usage map[string]*QuotaUsage
+}
+
+// NewPluginQuotas creates a tracker for quotas, keyed by plugin name.
+func NewPluginQuotas(quotas map[string]entities.PluginQuota) *PluginQuotas {
+	return &PluginQuotas{quotas: quotas, usage: make(map[string]*QuotaUsage)}
+}
+
+// Start records an invocation of plugin and returns the runtime it may take
+// (0 = unlimited). It fails with ErrQuotaExceeded, recording nothing, when
+// the plugin has used up one of its quotas.
+func (q *PluginQuotas) Start(plugin string) (time.Duration, error) {
+	quota, ok := q.quotas[plugin]
+	if !ok {
+		return 0, nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	usage := q.usageOf(plugin)
+
+	switch {
+	case quota.MaxInvocations > 0 && usage.Invocations >= quota.MaxInvocations:
+		return 0, fmt.Errorf("%w: plugin %s ran its %d invocations", ErrQuotaExceeded, plugin, quota.MaxInvocations)
+	case quota.MaxRuntime > 0 && usage.Runtime >= quota.MaxRuntime:
+		return 0, fmt.Errorf("%w: plugin %s used its %s of runtime", ErrQuotaExceeded, plugin, quota.MaxRuntime)
+	case quota.MaxHostCalls > 0 && usage.HostCalls >= quota.MaxHostCalls:
+		return 0, fmt.Errorf("%w: plugin %s made its %d host calls", ErrQuotaExceeded, plugin, quota.MaxHostCalls)
+	}
+
+	usage.Invocations++
+	if quota.MaxRuntime > 0 {
+		return quota.MaxRuntime - usage.Runtime, nil
+	}
+	return 0, nil
+}
+
+// Finish adds the runtime of an invocation started with Start to the
+// plugin's usage.
+func (q *PluginQuotas) Finish(plugin string, runtime time.Duration) {
+	if _, ok := q.quotas[plugin]; !ok {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.usageOf(plugin).Runtime += runtime
+}
+
+// HostCall records a host function call of plugin. It fails with
+// ErrQuotaExceeded, recording nothing, when the call would exceed the
+// plugin's host call quota.
+func (q *PluginQuotas) HostCall(plugin string) error {
+	quota, ok := q.quotas[plugin]
+	if !ok {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	usage := q.usageOf(plugin)
+	if quota.MaxHostCalls > 0 && usage.HostCalls >= quota.MaxHostCalls {
+		return fmt.Errorf("%w: plugin %s made its %d host calls", ErrQuotaExceeded, plugin, quota.MaxHostCalls)
+	}
+	usage.HostCalls++
+	return nil
+}
+
+// Usage returns what plugin used so far.
+func (q *PluginQuotas) Usage(plugin string) QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if usage, ok := q.usage[plugin]; ok {
+		return *usage
+	}
+	return QuotaUsage{}
+}
+
+// usageOf returns the usage record of plugin, creating it. q.mu must be held.
+func (q *PluginQuotas) usageOf(plugin string) *QuotaUsage {
+	usage, ok := q.usage[plugin]
+	if !ok {
+		usage = &QuotaUsage{}
+		q.usage[plugin] = usage
+	}
+	return usage
+}
+
+type pluginQuotasKey struct{}
+
+// WithPluginQuotas attaches the quota tracker of a run to the context.
+func WithPluginQuotas(ctx context.Context, quotas *PluginQuotas) context.Context {
+	if quotas == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, pluginQuotasKey{}, quotas)
+}
+
+// PluginQuotasFromContext returns the quota tracker attached to the context, if any.
+func PluginQuotasFromContext(ctx context.Context) (*PluginQuotas, bool) {
+	quotas, ok := ctx.Value(pluginQuotasKey{}).(*PluginQuotas)
+	return quotas, ok
+}
@@ -0,0 +1,63 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginQuotas_Invocations(t *testing.T) {
+	quotas := NewPluginQuotas(map[string]entities.PluginQuota{"http": {MaxInvocations: 2}})
+
+	for range 2 {
+		remaining, err := quotas.Start("http")
+		require.NoError(t, err)
+		assert.Zero(t, remaining, "no runtime quota")
+		quotas.Finish("http", time.Second)
+	}
+	_, err := quotas.Start("http")
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.ErrorContains(t, err, "ran its 2 invocations")
+	assert.Equal(t, QuotaUsage{Invocations: 2, Runtime: 2 * time.Second}, quotas.Usage("http"))
+
+	for range 3 {
+		_, err := quotas.Start("dns")
+		require.NoError(t, err, "plugins without a quota are not limited")
+	}
+	assert.Equal(t, QuotaUsage{}, quotas.Usage("dns"), "nor tracked")
+}
+
+func TestPluginQuotas_Runtime(t *testing.T) {
+	quotas := NewPluginQuotas(map[string]entities.PluginQuota{"command": {MaxRuntime: time.Minute}})
+
+	remaining, err := quotas.Start("command")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, remaining)
+	quotas.Finish("command", 45*time.Second)
+
+	remaining, err = quotas.Start("command")
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Second, remaining)
+	quotas.Finish("command", 15*time.Second)
+
+	_, err = quotas.Start("command")
+	assert.ErrorContains(t, err, "used its 1m0s of runtime")
+}
+
+func TestPluginQuotas_HostCalls(t *testing.T) {
+	quotas := NewPluginQuotas(map[string]entities.PluginQuota{"http": {MaxHostCalls: 2}})
+
+	_, err := quotas.Start("http")
+	require.NoError(t, err)
+	require.NoError(t, quotas.HostCall("http"))
+	require.NoError(t, quotas.HostCall("http"))
+	assert.ErrorIs(t, quotas.HostCall("http"), ErrQuotaExceeded)
+	assert.Equal(t, 2, quotas.Usage("http").HostCalls, "refused calls are not counted")
+
+	_, err = quotas.Start("http")
+	assert.ErrorContains(t, err, "made its 2 host calls")
+	assert.NoError(t, quotas.HostCall("tcp"))
+}
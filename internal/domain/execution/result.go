@@ -13,27 +13,44 @@ import (
 //
 //nolint:revive // ST1003: Name is intentional - "Result" alone lacks context in imports
 type ExecutionResult struct {
-	StartTime      time.Time       `json:"start_time" yaml:"start_time"`
-	EndTime        time.Time       `json:"end_time" yaml:"end_time"`
-	RegletVersion  string          `json:"reglet_version,omitempty" yaml:"reglet_version,omitempty"`
-	ProfileName    string          `json:"profile_name" yaml:"profile_name"`
-	ProfileVersion string          `json:"profile_version" yaml:"profile_version"`
-	Controls       []ControlResult `json:"controls" yaml:"controls"`
-	Summary        ResultSummary   `json:"summary" yaml:"summary"`
-	Version        int             `json:"version" yaml:"version"`
-	Duration       time.Duration   `json:"duration_ms" yaml:"duration_ms"`
+	SchemaVersion  int                `json:"schema_version" yaml:"schema_version"`
+	StartTime      time.Time          `json:"start_time" yaml:"start_time"`
+	EndTime        time.Time          `json:"end_time" yaml:"end_time"`
+	RegletVersion  string             `json:"reglet_version,omitempty" yaml:"reglet_version,omitempty"`
+	ProfileName    string             `json:"profile_name" yaml:"profile_name"`
+	ProfileVersion string             `json:"profile_version" yaml:"profile_version"`
+	Environment    string             `json:"environment,omitempty" yaml:"environment,omitempty"`     // selected with --env
+	Mode           string             `json:"mode,omitempty" yaml:"mode,omitempty"`                   // ModeCollect or ModeEvaluate
+	EvaluatedRun   string             `json:"evaluated_run,omitempty" yaml:"evaluated_run,omitempty"` // run whose evidence ModeEvaluate used
+	Setup          []StepResult       `json:"setup,omitempty" yaml:"setup,omitempty"`                 // profile setup steps, run before the controls
+	Controls       []ControlResult    `json:"controls" yaml:"controls"`
+	Teardown       []StepResult       `json:"teardown,omitempty" yaml:"teardown,omitempty"` // profile teardown steps, run after the controls
+	Summary        ResultSummary      `json:"summary" yaml:"summary"`
+	ErrorGroups    []ErrorGroup       `json:"error_groups,omitempty" yaml:"error_groups,omitempty"` // errors sharing a root cause
+	Performance    *PerformanceReport `json:"performance,omitempty" yaml:"performance,omitempty"`   // set with --profile-perf
+	Nodes          []NodeRun          `json:"nodes,omitempty" yaml:"nodes,omitempty"`               // set by distributed runs
+	Provenance     *Provenance        `json:"provenance,omitempty" yaml:"provenance,omitempty"`     // builds of reglet and the plugins used
+	Version        int                `json:"version" yaml:"version"`
+	Duration       time.Duration      `json:"duration_ms" yaml:"duration_ms"`
 	mu             sync.Mutex
 	ExecutionID    values.ExecutionID `json:"execution_id" yaml:"execution_id"`
 }
 
 // ControlResult represents the result of executing a single control.
 type ControlResult struct {
+	Labels             map[string]string   `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Transition         *ControlTransition  `json:"transition,omitempty" yaml:"transition,omitempty"` // set by continuous verification
 	ID                 string              `json:"id" yaml:"id"`
 	Name               string              `json:"name" yaml:"name"`
 	Description        string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Rationale          string              `json:"rationale,omitempty" yaml:"rationale,omitempty"`
+	References         []string            `json:"references,omitempty" yaml:"references,omitempty"`
 	Severity           string              `json:"severity,omitempty" yaml:"severity,omitempty"`
+	DeclaredSeverity   string              `json:"declared_severity,omitempty" yaml:"declared_severity,omitempty"` // profile severity a severity rule replaced
+	SeverityRule       string              `json:"severity_rule,omitempty" yaml:"severity_rule,omitempty"`         // name of that rule
 	Status             values.Status       `json:"status" yaml:"status"`
 	Message            string              `json:"message,omitempty" yaml:"message,omitempty"`
+	Node               string              `json:"node,omitempty" yaml:"node,omitempty"` // agent that ran the control in a distributed run
 	SkipReason         string              `json:"skip_reason,omitempty" yaml:"skip_reason,omitempty"`
 	Tags               []string            `json:"tags,omitempty" yaml:"tags,omitempty"`
 	ObservationResults []ObservationResult `json:"observations" yaml:"observations"`
@@ -43,15 +60,31 @@ type ControlResult struct {
 
 // ObservationResult represents the result of executing a single observation.
 type ObservationResult struct {
-	RawError     error                  `json:"-" yaml:"-"`
-	Config       map[string]interface{} `json:"config" yaml:"config"`
-	Evidence     *Evidence              `json:"evidence,omitempty" yaml:"evidence,omitempty"`
-	EvidenceMeta *EvidenceMeta          `json:"evidence_meta,omitempty" yaml:"evidence_meta,omitempty"`
-	Error        *PluginError           `json:"error,omitempty" yaml:"error,omitempty"`
-	Plugin       string                 `json:"plugin" yaml:"plugin"`
-	Status       values.Status          `json:"status" yaml:"status"`
-	Expectations []ExpectationResult    `json:"expectations,omitempty" yaml:"expectations,omitempty"`
-	Duration     time.Duration          `json:"duration_ms" yaml:"duration_ms"`
+	RawError      error                  `json:"-" yaml:"-"`
+	Config        map[string]interface{} `json:"config" yaml:"config"`
+	Evidence      *Evidence              `json:"evidence,omitempty" yaml:"evidence,omitempty"`
+	EvidenceMeta  *EvidenceMeta          `json:"evidence_meta,omitempty" yaml:"evidence_meta,omitempty"`
+	Error         *PluginError           `json:"error,omitempty" yaml:"error,omitempty"`
+	Plugin        string                 `json:"plugin" yaml:"plugin"`
+	PluginVersion string                 `json:"plugin_version,omitempty" yaml:"plugin_version,omitempty"`
+	Status        values.Status          `json:"status" yaml:"status"`
+	Expectations  []ExpectationResult    `json:"expectations,omitempty" yaml:"expectations,omitempty"`
+	PII           []PIIFinding           `json:"pii,omitempty" yaml:"pii,omitempty"` // likely personal data in the evidence
+	Timing        *ObservationTiming     `json:"timing,omitempty" yaml:"timing,omitempty"`
+	Duration      time.Duration          `json:"duration_ms" yaml:"duration_ms"`
+
+	// IdempotencyKey was passed to a side-effecting plugin; see IdempotencyKey
+	IdempotencyKey string `json:"idempotency_key,omitempty" yaml:"idempotency_key,omitempty"`
+
+	// SharedEvidence is set when the evidence came from an identical
+	// observation of another control in the run instead of a plugin call
+	SharedEvidence bool `json:"shared_evidence,omitempty" yaml:"shared_evidence,omitempty"`
+}
+
+// StepResult represents the result of a setup or teardown step of a profile.
+type StepResult struct {
+	ID                string `json:"id" yaml:"id"`
+	ObservationResult `json:",inline" yaml:",inline"`
 }
 
 // ExpectationResult represents the result of evaluating a single expectation expression.
@@ -74,8 +107,23 @@ type ResultSummary struct {
 	PassedObservations int `json:"passed_observations" yaml:"passed_observations"`
 	FailedObservations int `json:"failed_observations" yaml:"failed_observations"`
 	ErrorObservations  int `json:"error_observations" yaml:"error_observations"`
+
+	CollectedControls     int `json:"collected_controls,omitempty" yaml:"collected_controls,omitempty"`
+	CollectedObservations int `json:"collected_observations,omitempty" yaml:"collected_observations,omitempty"`
+	PIIObservations       int `json:"pii_observations,omitempty" yaml:"pii_observations,omitempty"`
+	NotRunObservations    int `json:"not_run_observations,omitempty" yaml:"not_run_observations,omitempty"` // plugin not installed
 }
 
+// Result modes. Results of regular checks have no mode.
+const (
+	// ModeCollect marks results of evidence-only runs ("reglet collect"):
+	// the observations ran but no expectation was evaluated.
+	ModeCollect = "collect"
+	// ModeEvaluate marks results evaluated from the stored evidence of
+	// another run ("reglet evaluate") instead of running plugins.
+	ModeEvaluate = "evaluate"
+)
+
 // NewExecutionResult creates a new execution result.
 func NewExecutionResult(profileName, profileVersion string) *ExecutionResult {
 	return NewExecutionResultWithID(values.NewExecutionID(), profileName, profileVersion)
@@ -84,6 +132,7 @@ func NewExecutionResult(profileName, profileVersion string) *ExecutionResult {
 // NewExecutionResultWithID creates a new execution result with a specific ID.
 func NewExecutionResultWithID(id values.ExecutionID, profileName, profileVersion string) *ExecutionResult {
 	return &ExecutionResult{
+		SchemaVersion:  CurrentSchemaVersion,
 		ExecutionID:    id,
 		ProfileName:    profileName,
 		ProfileVersion: profileVersion,
@@ -93,6 +142,16 @@ func NewExecutionResultWithID(id values.ExecutionID, profileName, profileVersion
 	}
 }
 
+// ProfileRef names the profile and, when one was selected, the environment
+// ("name@env"). Exporters key issues and reports by it, so runs of the same
+// profile in different environments never overwrite each other.
+func (r *ExecutionResult) ProfileRef() string {
+	if r.Environment == "" {
+		return r.ProfileName
+	}
+	return r.ProfileName + "@" + r.Environment
+}
+
 // GetID returns the execution ID.
 func (r *ExecutionResult) GetID() values.ExecutionID {
 	return r.ExecutionID
@@ -160,7 +219,12 @@ func (r *ExecutionResult) IsComplete(expectedControlCount int) bool {
 // Finalize completes the execution result and calculates the summary.
 // Controls are sorted by their original definition order for deterministic output.
 func (r *ExecutionResult) Finalize() {
-	r.EndTime = time.Now()
+	r.FinalizeAt(time.Now())
+}
+
+// FinalizeAt completes the execution result with the given end time.
+func (r *ExecutionResult) FinalizeAt(end time.Time) {
+	r.EndTime = end
 	r.Duration = r.EndTime.Sub(r.StartTime)
 
 	// Sort controls by original definition order for deterministic output
@@ -169,6 +233,7 @@ func (r *ExecutionResult) Finalize() {
 	})
 
 	r.calculateSummary()
+	r.ErrorGroups = groupErrors(r.Controls)
 }
 
 // calculateSummary computes summary statistics from control results.
@@ -188,6 +253,8 @@ func (r *ExecutionResult) calculateSummary() {
 			r.Summary.ErrorControls++
 		case values.StatusSkipped:
 			r.Summary.SkippedControls++
+		case values.StatusCollected:
+			r.Summary.CollectedControls++
 		}
 
 		// Count observation statuses
@@ -200,6 +267,13 @@ func (r *ExecutionResult) calculateSummary() {
 				r.Summary.FailedObservations++
 			case values.StatusError:
 				r.Summary.ErrorObservations++
+			case values.StatusCollected:
+				r.Summary.CollectedObservations++
+			case values.StatusNotRun:
+				r.Summary.NotRunObservations++
+			}
+			if len(obs.PII) > 0 {
+				r.Summary.PIIObservations++
 			}
 		}
 	}
@@ -230,6 +304,15 @@ func (e *PluginError) Error() string {
 // DefaultMaxEvidenceSize is the default limit for evidence size (1MB).
 const DefaultMaxEvidenceSize = 1 * 1024 * 1024
 
+// PIIFinding records likely personal data found in the evidence of an
+// observation by the optional PII scan.
+type PIIFinding struct {
+	Kind     string `json:"kind" yaml:"kind"` // email, phone or national_id
+	Path     string `json:"path" yaml:"path"` // evidence key, e.g. data.users.email
+	Count    int    `json:"count" yaml:"count"`
+	Redacted bool   `json:"redacted" yaml:"redacted"`
+}
+
 // EvidenceMeta contains metadata about evidence truncation.
 type EvidenceMeta struct {
 	Reason       string `json:"reason,omitempty" yaml:"reason,omitempty"`
@@ -237,3 +320,16 @@ type EvidenceMeta struct {
 	TruncatedAt  int    `json:"truncated_at_bytes" yaml:"truncated_at_bytes"`
 	Truncated    bool   `json:"truncated" yaml:"truncated"`
 }
+
+// LabelPairs returns the control's labels as "key=value" strings, sorted by key.
+func (cr ControlResult) LabelPairs() []string {
+	if len(cr.Labels) == 0 {
+		return nil
+	}
+	pairs := make([]string, 0, len(cr.Labels))
+	for key, value := range cr.Labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
@@ -0,0 +1,129 @@
+package execution
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Result schema versions.
+//
+// The serialized ExecutionResult is a public contract consumed by external
+// tooling. Any change that renames, removes, or changes the meaning of a field
+// must bump CurrentSchemaVersion and register an upgrade from the previous
+// version in schemaUpgrades so stored results remain readable.
+const (
+	// LegacySchemaVersion identifies results written before schema_version existed.
+	LegacySchemaVersion = 0
+
+	// CurrentSchemaVersion is the schema version written by this build.
+	CurrentSchemaVersion = 2
+)
+
+// ErrUnsupportedSchemaVersion is returned when a serialized result was written
+// by a newer (or unknown) schema than this build can read.
+var ErrUnsupportedSchemaVersion = errors.New("unsupported result schema version")
+
+// schemaUpgrade migrates a decoded result document from version N to N+1 in place.
+type schemaUpgrade func(doc map[string]interface{}) error
+
+// schemaUpgrades maps a source version to the upgrade that produces the next version.
+var schemaUpgrades = map[int]schemaUpgrade{
+	LegacySchemaVersion: upgradeLegacyResult,
+	1:                   upgradeV1Result,
+}
+
+// DecodeExecutionResult parses a JSON-serialized execution result written by any
+// supported schema version and returns it upgraded to CurrentSchemaVersion.
+func DecodeExecutionResult(data []byte) (*ExecutionResult, error) {
+	var header struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to read result schema version: %w", err)
+	}
+
+	version := LegacySchemaVersion
+	if header.SchemaVersion != nil {
+		version = *header.SchemaVersion
+	}
+
+	if version < LegacySchemaVersion || version > CurrentSchemaVersion {
+		return nil, fmt.Errorf("%w: %d (supported: %d-%d)",
+			ErrUnsupportedSchemaVersion, version, LegacySchemaVersion, CurrentSchemaVersion)
+	}
+
+	if version < CurrentSchemaVersion {
+		upgraded, err := upgradeResultDocument(data, version)
+		if err != nil {
+			return nil, err
+		}
+		data = upgraded
+	}
+
+	var result ExecutionResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode execution result: %w", err)
+	}
+	return &result, nil
+}
+
+// upgradeResultDocument applies each registered upgrade from version up to
+// CurrentSchemaVersion and returns the re-encoded document.
+func upgradeResultDocument(data []byte, version int) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Preserve integer precision (durations are nanoseconds)
+
+	var doc map[string]interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode result document: %w", err)
+	}
+
+	for v := version; v < CurrentSchemaVersion; v++ {
+		upgrade, ok := schemaUpgrades[v]
+		if !ok {
+			return nil, fmt.Errorf("%w: no upgrade path from version %d", ErrUnsupportedSchemaVersion, v)
+		}
+		if err := upgrade(doc); err != nil {
+			return nil, fmt.Errorf("failed to upgrade result from schema version %d: %w", v, err)
+		}
+		doc["schema_version"] = v + 1
+	}
+
+	return json.Marshal(doc)
+}
+
+// upgradeLegacyResult migrates pre-versioned results to schema version 1.
+// Legacy writers emitted null instead of empty arrays for controls without
+// observations; version 1 guarantees both arrays are always present.
+func upgradeLegacyResult(doc map[string]interface{}) error {
+	controls, ok := doc["controls"].([]interface{})
+	if !ok {
+		if doc["controls"] != nil {
+			return fmt.Errorf("controls must be an array")
+		}
+		controls = []interface{}{}
+	}
+
+	for _, raw := range controls {
+		ctrl, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("control entries must be objects")
+		}
+		if ctrl["observations"] == nil {
+			ctrl["observations"] = []interface{}{}
+		}
+	}
+	doc["controls"] = controls
+
+	return nil
+}
+
+// upgradeV1Result migrates version 1 results to schema version 2. Version 2
+// only adds the "collected" status of evidence-only runs and the "not_run"
+// status of observations whose plugin is missing, so every version 1
+// document is already a valid version 2 document.
+func upgradeV1Result(map[string]interface{}) error {
+	return nil
+}
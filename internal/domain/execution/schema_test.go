@@ -0,0 +1,136 @@
+package execution_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExecutionResult_SetsCurrentSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	result := execution.NewExecutionResult("profile", "1.0.0")
+	assert.Equal(t, execution.CurrentSchemaVersion, result.SchemaVersion)
+}
+
+func TestDecodeExecutionResult_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	original := execution.NewExecutionResult("profile", "1.0.0")
+	original.AddControlResult(execution.ControlResult{
+		ID:     "ctrl-1",
+		Status: values.StatusPass,
+		ObservationResults: []execution.ObservationResult{
+			{Plugin: "file", Status: values.StatusPass},
+		},
+	})
+	original.Finalize()
+
+	data, err := json.Marshal(original)
+	require.NoError(t, err)
+
+	decoded, err := execution.DecodeExecutionResult(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, execution.CurrentSchemaVersion, decoded.SchemaVersion)
+	assert.Equal(t, original.ExecutionID, decoded.ExecutionID)
+	assert.Equal(t, original.Duration, decoded.Duration)
+	require.Len(t, decoded.Controls, 1)
+	assert.Equal(t, "ctrl-1", decoded.Controls[0].ID)
+	assert.Equal(t, original.Summary, decoded.Summary)
+}
+
+func TestDecodeExecutionResult_Version1(t *testing.T) {
+	t.Parallel()
+
+	v1 := `{
+		"schema_version": 1,
+		"profile_name": "v1",
+		"execution_id": "6f1c2a3e-8d4b-4c5a-9e7f-0a1b2c3d4e5f",
+		"controls": [
+			{"id": "ctrl-1", "name": "V1", "status": "pass", "observations": [], "index": 0, "duration_ms": 0}
+		],
+		"summary": {"total_controls": 1, "passed_controls": 1}
+	}`
+
+	result, err := execution.DecodeExecutionResult([]byte(v1))
+	require.NoError(t, err)
+
+	assert.Equal(t, execution.CurrentSchemaVersion, result.SchemaVersion)
+	require.Len(t, result.Controls, 1)
+	assert.Equal(t, values.StatusPass, result.Controls[0].Status)
+}
+
+func TestDecodeExecutionResult_Legacy(t *testing.T) {
+	t.Parallel()
+
+	legacy := `{
+		"start_time": "2026-01-01T00:00:00Z",
+		"end_time": "2026-01-01T00:00:01Z",
+		"profile_name": "legacy",
+		"profile_version": "0.1.0",
+		"execution_id": "6f1c2a3e-8d4b-4c5a-9e7f-0a1b2c3d4e5f",
+		"duration_ms": 1000000000,
+		"version": 1,
+		"controls": [
+			{"id": "ctrl-1", "name": "Legacy", "status": "skipped", "observations": null, "index": 0, "duration_ms": 0}
+		],
+		"summary": {"total_controls": 1, "skipped_controls": 1}
+	}`
+
+	result, err := execution.DecodeExecutionResult([]byte(legacy))
+	require.NoError(t, err)
+
+	assert.Equal(t, execution.CurrentSchemaVersion, result.SchemaVersion)
+	assert.Equal(t, "legacy", result.ProfileName)
+	assert.Equal(t, "6f1c2a3e-8d4b-4c5a-9e7f-0a1b2c3d4e5f", result.ExecutionID.String())
+	assert.EqualValues(t, 1000000000, result.Duration)
+	require.Len(t, result.Controls, 1)
+	assert.NotNil(t, result.Controls[0].ObservationResults)
+	assert.Empty(t, result.Controls[0].ObservationResults)
+}
+
+func TestDecodeExecutionResult_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{
+			name:    "newer schema version",
+			input:   `{"schema_version": 99}`,
+			wantErr: execution.ErrUnsupportedSchemaVersion,
+		},
+		{
+			name:    "negative schema version",
+			input:   `{"schema_version": -1}`,
+			wantErr: execution.ErrUnsupportedSchemaVersion,
+		},
+		{
+			name:  "invalid json",
+			input: `{not json`,
+		},
+		{
+			name:  "legacy with malformed controls",
+			input: `{"controls": "nope"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := execution.DecodeExecutionResult([]byte(tt.input))
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
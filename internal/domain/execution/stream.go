@@ -0,0 +1,46 @@
+package execution
+
+// ResultStream receives an execution result incrementally while it is produced.
+// It lets outputs be written control-by-control instead of materializing every
+// observation's evidence in memory until the run finishes.
+//
+// Implementations must be safe for concurrent calls to WriteControl, since
+// controls complete in parallel. Begin is called once before the first control
+// and End once after the result has been finalized.
+type ResultStream interface {
+	// Begin writes run-level metadata known before any control executes.
+	Begin(result *ExecutionResult) error
+
+	// WriteControl writes a single completed control, including full evidence.
+	// Controls arrive in completion order; use ControlResult.Index to restore
+	// definition order.
+	WriteControl(control ControlResult) error
+
+	// End writes run-level data only known after finalization (end time, summary).
+	End(result *ExecutionResult) error
+}
+
+// Compact returns a copy of the control result with per-observation payloads
+// (config, evidence, expectations) removed. Statuses, errors and timings are
+// kept so dependency checks and summary calculation still work after the full
+// result has been handed to a ResultStream.
+func (cr ControlResult) Compact() ControlResult {
+	if len(cr.ObservationResults) == 0 {
+		return cr
+	}
+
+	compacted := make([]ObservationResult, len(cr.ObservationResults))
+	for i, obs := range cr.ObservationResults {
+		compacted[i] = ObservationResult{
+			Plugin:         obs.Plugin,
+			Status:         obs.Status,
+			Error:          obs.Error,
+			RawError:       obs.RawError,
+			Timing:         obs.Timing,
+			Duration:       obs.Duration,
+			IdempotencyKey: obs.IdempotencyKey,
+		}
+	}
+	cr.ObservationResults = compacted
+	return cr
+}
@@ -0,0 +1,137 @@
+package execution
+
+import (
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// TransitionKind is the kind of status change a control went through between
+// runs of continuous verification.
+type TransitionKind string
+
+const (
+	// TransitionFailing is a passing control that stopped passing, or a
+	// control that did not pass on its first run.
+	TransitionFailing TransitionKind = "failing"
+	// TransitionRecovered is a failing or errored control that passes again.
+	TransitionRecovered TransitionKind = "recovered"
+	// TransitionFlapping is a control that changed status too often within
+	// the flap window. Further changes are held back until it settles.
+	TransitionFlapping TransitionKind = "flapping"
+	// TransitionSettled is a flapping control that stopped changing; its
+	// status is the one it settled on.
+	TransitionSettled TransitionKind = "settled"
+)
+
+// ControlTransition describes why a control is reported by continuous
+// verification.
+type ControlTransition struct {
+	Kind    TransitionKind `json:"kind" yaml:"kind"`
+	From    values.Status  `json:"from,omitempty" yaml:"from,omitempty"`       // status on the previous run ("" on the first run)
+	Changes int            `json:"changes,omitempty" yaml:"changes,omitempty"` // pass/not-pass changes within the flap window
+}
+
+// controlState is what a TransitionTracker remembers about one control.
+type controlState struct {
+	status   values.Status
+	passing  []bool // last window+1 runs, oldest first
+	flapping bool
+}
+
+// TransitionTracker is a per-control state machine over successive runs of
+// a profile. It reports a control only when it starts or stops passing, and
+// collapses a control that keeps changing into one flapping event, so
+// notification sinks are not flooded by repeated or unstable failures.
+// Skipped controls leave the state untouched. Not safe for concurrent use.
+type TransitionTracker struct {
+	controls  map[string]*controlState
+	window    int
+	threshold int
+}
+
+// NewTransitionTracker creates a tracker that marks a control as flapping
+// when it changes between passing and not passing threshold times within
+// its last window runs, and as settled once it changes fewer than half as
+// often.
+func NewTransitionTracker(window, threshold int) *TransitionTracker {
+	return &TransitionTracker{
+		controls:  make(map[string]*controlState),
+		window:    window,
+		threshold: threshold,
+	}
+}
+
+// Observe feeds one run into the tracker and returns a result holding only
+// the controls that transitioned, each with its Transition set. The returned
+// result has no controls when nothing changed.
+func (t *TransitionTracker) Observe(result *ExecutionResult) *ExecutionResult {
+	delta := NewExecutionResultWithID(result.ExecutionID, result.ProfileName, result.ProfileVersion)
+	delta.Environment = result.Environment
+	delta.RegletVersion = result.RegletVersion
+	delta.StartTime = result.StartTime
+
+	for i := range result.Controls {
+		ctrl := result.Controls[i]
+		transition := t.observe(ctrl.ID, ctrl.Status)
+		if transition == nil {
+			continue
+		}
+		ctrl.Transition = transition
+		delta.AddControlResult(ctrl)
+	}
+
+	delta.FinalizeAt(result.EndTime)
+	return delta
+}
+
+// observe advances the state of one control and returns its transition, if
+// any.
+func (t *TransitionTracker) observe(id string, status values.Status) *ControlTransition {
+	if status == values.StatusSkipped {
+		return nil
+	}
+	passing := status == values.StatusPass
+
+	state, ok := t.controls[id]
+	if !ok {
+		t.controls[id] = &controlState{status: status, passing: []bool{passing}}
+		if !passing {
+			return &ControlTransition{Kind: TransitionFailing}
+		}
+		return nil
+	}
+
+	from := state.status
+	wasPassing := state.passing[len(state.passing)-1]
+	state.status = status
+	state.passing = append(state.passing, passing)
+	if len(state.passing) > t.window+1 {
+		state.passing = state.passing[len(state.passing)-t.window-1:]
+	}
+	changes := countChanges(state.passing)
+
+	switch {
+	case !state.flapping && changes >= t.threshold:
+		state.flapping = true
+		return &ControlTransition{Kind: TransitionFlapping, From: from, Changes: changes}
+	case state.flapping && changes*2 < t.threshold:
+		state.flapping = false
+		return &ControlTransition{Kind: TransitionSettled, From: from, Changes: changes}
+	case state.flapping || passing == wasPassing:
+		return nil
+	case passing:
+		return &ControlTransition{Kind: TransitionRecovered, From: from}
+	default:
+		return &ControlTransition{Kind: TransitionFailing, From: from}
+	}
+}
+
+// countChanges counts adjacent entries that differ.
+func countChanges(passing []bool) int {
+	changes := 0
+	for i := 1; i < len(passing); i++ {
+		if passing[i] != passing[i-1] {
+			changes++
+		}
+	}
+	return changes
+}
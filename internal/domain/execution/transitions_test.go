@@ -0,0 +1,93 @@
+package execution_test
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// observeStatuses feeds one run per status of control "c" and returns the
+// transition reported for each run (nil when none).
+func observeStatuses(tracker *execution.TransitionTracker, statuses ...values.Status) []*execution.ControlTransition {
+	transitions := make([]*execution.ControlTransition, len(statuses))
+	for i, status := range statuses {
+		run := execution.NewExecutionResult("p", "1.0.0")
+		run.Controls = []execution.ControlResult{{ID: "c", Status: status}}
+		delta := tracker.Observe(run)
+		if len(delta.Controls) > 0 {
+			transitions[i] = delta.Controls[0].Transition
+		}
+	}
+	return transitions
+}
+
+func TestTransitionTracker_StatusChanges(t *testing.T) {
+	t.Parallel()
+
+	pass, fail, errored, skipped := values.StatusPass, values.StatusFail, values.StatusError, values.StatusSkipped
+	got := observeStatuses(execution.NewTransitionTracker(10, 4), pass, pass, fail, fail, errored, skipped, pass, pass)
+
+	assert.Equal(t, []*execution.ControlTransition{
+		nil, // first run passing: nothing to report
+		nil,
+		{Kind: execution.TransitionFailing, From: pass},
+		nil, // still failing
+		nil, // fail → error is still not passing
+		nil, // skipped runs are ignored
+		{Kind: execution.TransitionRecovered, From: errored},
+		nil,
+	}, got)
+}
+
+func TestTransitionTracker_FirstRunFailing(t *testing.T) {
+	t.Parallel()
+
+	got := observeStatuses(execution.NewTransitionTracker(10, 4), values.StatusFail)
+	assert.Equal(t, []*execution.ControlTransition{{Kind: execution.TransitionFailing}}, got)
+}
+
+func TestTransitionTracker_Flapping(t *testing.T) {
+	t.Parallel()
+
+	pass, fail := values.StatusPass, values.StatusFail
+	got := observeStatuses(execution.NewTransitionTracker(4, 3),
+		pass, fail, pass, // two changes: reported
+		fail,       // third change within the window: flapping
+		pass, fail, // held back while flapping
+		fail, fail, fail, // settles once the window has fewer than 1.5 changes
+	)
+
+	require.Len(t, got, 9)
+	assert.Equal(t, execution.TransitionFailing, got[1].Kind)
+	assert.Equal(t, execution.TransitionRecovered, got[2].Kind)
+	assert.Equal(t, &execution.ControlTransition{Kind: execution.TransitionFlapping, From: pass, Changes: 3}, got[3])
+	assert.Nil(t, got[4])
+	assert.Nil(t, got[5])
+	assert.Nil(t, got[6], "window [pass fail pass fail fail] still has 3 changes")
+	assert.Nil(t, got[7], "window [fail pass fail fail fail] still has 2 changes")
+	assert.Equal(t, &execution.ControlTransition{Kind: execution.TransitionSettled, From: fail, Changes: 1}, got[8])
+}
+
+func TestTransitionTracker_ObserveKeepsRunIdentity(t *testing.T) {
+	t.Parallel()
+
+	run := execution.NewExecutionResult("web", "1.0.0")
+	run.Environment = "prod"
+	run.Controls = []execution.ControlResult{
+		{ID: "a", Status: values.StatusFail, Index: 0},
+		{ID: "b", Status: values.StatusPass, Index: 1},
+	}
+	run.Finalize()
+
+	delta := execution.NewTransitionTracker(10, 4).Observe(run)
+
+	assert.Equal(t, run.ExecutionID, delta.ExecutionID)
+	assert.Equal(t, "web@prod", delta.ProfileRef())
+	require.Len(t, delta.Controls, 1)
+	assert.Equal(t, "a", delta.Controls[0].ID)
+	assert.Nil(t, run.Controls[0].Transition, "the observed run is not modified")
+	assert.Equal(t, 1, delta.Summary.FailedControls)
+}
@@ -20,23 +20,29 @@ func (t *GreedyTruncator) Truncate(data map[string]interface{}, limit int) (map[
 		return data, nil, nil // No limit
 	}
 
-	// 1. Serialize to measure size
-	serialized, err := json.Marshal(data)
+	// 1. Serialize to measure size, deep copying via round-trip only when
+	// the evidence is over the limit so the common case keeps no encoding
+	var originalSize int
+	var truncated map[string]interface{}
+	var copyErr error
+	err := EncodeJSON(data, func(serialized []byte) error {
+		originalSize = len(serialized)
+		if originalSize > limit {
+			// 2. Deep copy via round-trip to avoid mutating original
+			copyErr = json.Unmarshal(serialized, &truncated)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to measure evidence size: %w", err)
 	}
-
-	originalSize := len(serialized)
+	if copyErr != nil {
+		return nil, nil, fmt.Errorf("failed to deep copy evidence: %w", copyErr)
+	}
 	if originalSize <= limit {
 		return data, nil, nil // Within limit
 	}
 
-	// 2. Deep copy via round-trip to avoid mutating original
-	var truncated map[string]interface{}
-	if err := json.Unmarshal(serialized, &truncated); err != nil {
-		return nil, nil, fmt.Errorf("failed to deep copy evidence: %w", err)
-	}
-
 	// 3. Truncate fields
 	threshold := limit / 2 // simple heuristic
 
@@ -48,8 +54,8 @@ func (t *GreedyTruncator) Truncate(data map[string]interface{}, limit int) (map[
 			}
 		default:
 			// For complex objects, re-serialize to check size
-			vBytes, _ := json.Marshal(v)
-			if len(vBytes) > threshold {
+			size, _ := EncodedSize(v)
+			if size > threshold {
 				truncated[key] = map[string]string{
 					"_truncated": "value exceeded size limit",
 					"_type":      fmt.Sprintf("%T", v),
@@ -3,18 +3,51 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/reglet-dev/reglet/internal/domain/execution"
 )
 
+var (
+	// ErrResultNotFound is returned when no result is stored under an execution ID.
+	ErrResultNotFound = errors.New("execution result not found")
+
+	// ErrVersionConflict is returned when a result is saved over a stored
+	// version other than the one it was read at.
+	ErrVersionConflict = errors.New("execution result version conflict")
+
+	// ErrInvalidPageToken is returned by List for a page token it did not issue.
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
+
+// DefaultPageSize is the page size of List when the query sets no limit.
+const DefaultPageSize = 100
+
 // ExecutionResultRepository defines the interface for persisting execution results.
+//
+// Implementations must be safe for concurrent use, so several processes or
+// goroutines (continuous verification, servers) can share one backend.
+// Every call is scoped to the namespace of its context (see WithNamespace);
+// results of other namespaces are invisible to it.
+// Results are keyed by execution ID and versioned for optimistic locking:
+//   - Saving an unknown ID stores the result as given.
+//   - Saving a known ID at the stored version replaces the stored result and
+//     increments the version, on the store and on the given result.
+//   - Saving a result identical to the stored one is a no-op, so retried
+//     writes are idempotent.
+//   - Any other version returns ErrVersionConflict; re-read and retry.
 type ExecutionResultRepository interface {
 	// Save persists an execution result.
 	Save(ctx context.Context, result *execution.ExecutionResult) error
 
+	// SaveBatch persists several results atomically: either every result is
+	// saved, or none is and the first conflict is returned.
+	SaveBatch(ctx context.Context, results []*execution.ExecutionResult) error
+
 	// FindByID retrieves an execution result by its unique ID.
+	// Returns ErrResultNotFound if it is not stored.
 	FindByID(ctx context.Context, id uuid.UUID) (*execution.ExecutionResult, error)
 
 	// FindByProfile retrieves recent execution results for a specific profile.
@@ -22,4 +55,28 @@ type ExecutionResultRepository interface {
 
 	// FindBetween retrieves execution results for a profile within a time range.
 	FindBetween(ctx context.Context, profileName string, start, end time.Time) ([]*execution.ExecutionResult, error)
+
+	// List returns one page of the results matching query, newest first.
+	List(ctx context.Context, query ResultQuery) (*ResultPage, error)
+}
+
+// ResultQuery selects stored results. Zero fields match everything.
+type ResultQuery struct {
+	// Since and Until bound the start time, inclusive.
+	Since time.Time
+	Until time.Time
+	// ProfileName and Environment match exactly.
+	ProfileName string
+	Environment string
+	// PageToken continues after the page that returned it.
+	PageToken string
+	// Limit is the page size (0 = DefaultPageSize).
+	Limit int
+}
+
+// ResultPage is one page of a List query.
+type ResultPage struct {
+	// NextPageToken fetches the next page; empty on the last page.
+	NextPageToken string
+	Results       []*execution.ExecutionResult
 }
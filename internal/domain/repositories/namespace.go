@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// DefaultNamespace is the namespace of repository calls whose context names
+// none, and of results stored before namespaces existed.
+const DefaultNamespace = "default"
+
+// ErrInvalidNamespace is returned for namespace names that are not DNS labels.
+var ErrInvalidNamespace = errors.New("invalid namespace")
+
+// namespacePattern is a DNS label, as used for Kubernetes namespaces.
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+type namespaceKey struct{}
+
+// WithNamespace scopes the repository calls made with the returned context to
+// namespace, typically a team or tenant. Repositories enforce the scope: a
+// call never reads, lists or overwrites results of another namespace, and the
+// same execution ID may exist independently in several namespaces.
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceKey{}, namespace)
+}
+
+// NamespaceFromContext returns the namespace of ctx, or DefaultNamespace.
+func NamespaceFromContext(ctx context.Context) string {
+	if namespace, ok := ctx.Value(namespaceKey{}).(string); ok && namespace != "" {
+		return namespace
+	}
+	return DefaultNamespace
+}
+
+// ValidateNamespace checks that namespace is a DNS label: at most 63
+// lowercase letters, digits and hyphens, starting and ending alphanumeric.
+// Namespace names end up in file paths and URLs, so they are restricted.
+func ValidateNamespace(namespace string) error {
+	if !namespacePattern.MatchString(namespace) {
+		return fmt.Errorf("%w %q: use lowercase letters, digits and '-' (at most 63)", ErrInvalidNamespace, namespace)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package repositories_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceFromContext(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, repositories.DefaultNamespace, repositories.NamespaceFromContext(context.Background()))
+	assert.Equal(t, repositories.DefaultNamespace, repositories.NamespaceFromContext(repositories.WithNamespace(context.Background(), "")))
+	assert.Equal(t, "team-a", repositories.NamespaceFromContext(repositories.WithNamespace(context.Background(), "team-a")))
+}
+
+func TestValidateNamespace(t *testing.T) {
+	t.Parallel()
+
+	for _, valid := range []string{"default", "team-a", "a", "42", strings.Repeat("a", 63)} {
+		assert.NoError(t, repositories.ValidateNamespace(valid), valid)
+	}
+	for _, invalid := range []string{"", "Team", "team_a", "-team", "team-", "../etc", "a/b", strings.Repeat("a", 64)} {
+		assert.ErrorIs(t, repositories.ValidateNamespace(invalid), repositories.ErrInvalidNamespace, invalid)
+	}
+}
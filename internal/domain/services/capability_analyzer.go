@@ -27,32 +27,30 @@ func (a *CapabilityAnalyzer) ExtractCapabilities(profile entities.ProfileReader)
 	// Use map to deduplicate capabilities per plugin
 	profileCaps := make(map[string]map[string]capabilities.Capability)
 
-	// Analyze each control's observations
-	for _, ctrl := range profile.GetAllControls() {
-		for _, obs := range ctrl.ObservationDefinitions {
-			pluginName := obs.Plugin
+	// Analyze every observation, setup and teardown included
+	for _, obs := range profile.AllObservations() {
+		pluginName := obs.Plugin
 
-			// Initialize plugin entry if needed
-			if _, ok := profileCaps[pluginName]; !ok {
-				profileCaps[pluginName] = make(map[string]capabilities.Capability)
-			}
+		// Initialize plugin entry if needed
+		if _, ok := profileCaps[pluginName]; !ok {
+			profileCaps[pluginName] = make(map[string]capabilities.Capability)
+		}
 
-			// Look up extractor for this plugin
-			extractor, ok := a.registry.Get(pluginName)
-			if !ok {
-				// No specific extractor found. Assume no additional dynamic capabilities are needed
-				// beyond what the plugin declares in its manifest.
-				continue
-			}
+		// Look up extractor for this plugin
+		extractor, ok := a.registry.Get(pluginName)
+		if !ok {
+			// No specific extractor found. Assume no additional dynamic capabilities are needed
+			// beyond what the plugin declares in its manifest.
+			continue
+		}
 
-			// Extract plugin-specific capabilities based on config
-			extractedCaps := extractor.Extract(obs.Config)
+		// Extract plugin-specific capabilities based on config
+		extractedCaps := extractor.Extract(obs.Config)
 
-			// Deduplicate by using capability string as key
-			for _, capability := range extractedCaps {
-				key := capability.Kind + ":" + capability.Pattern
-				profileCaps[pluginName][key] = capability
-			}
+		// Deduplicate by using capability string as key
+		for _, capability := range extractedCaps {
+			key := capability.Kind + ":" + capability.Pattern
+			profileCaps[pluginName][key] = capability
 		}
 	}
 
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// conditionEnv is the environment of control when conditions.
+type conditionEnv struct {
+	Facts map[string]interface{} `expr:"facts"`
+}
+
+// CompileCondition compiles the when condition of a control: a boolean
+// expression over the facts of the host running it, such as
+// facts.os == "windows". The expression functions are available, e.g.
+// semverCompare(facts.platform_version, "22.04") >= 0.
+func CompileCondition(when string) (*vm.Program, error) {
+	options := append([]expr.Option{expr.Env(conditionEnv{}), expr.AsBool()}, ExpressionFunctions()...)
+	return expr.Compile(when, options...)
+}
+
+// EvaluateCondition reports whether the when condition holds for facts.
+func EvaluateCondition(when string, facts map[string]interface{}) (bool, error) {
+	program, err := CompileCondition(when)
+	if err != nil {
+		return false, err
+	}
+	output, err := expr.Run(program, conditionEnv{Facts: facts})
+	if err != nil {
+		return false, err
+	}
+	result, ok := output.(bool)
+	if !ok {
+		return false, fmt.Errorf("condition did not return a boolean: %v", output)
+	}
+	return result, nil
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	t.Parallel()
+
+	facts := map[string]interface{}{"os": "windows", "platform": "windows", "platform_version": "10.0.22631"}
+	tests := []struct {
+		when string
+		want bool
+	}{
+		{`facts.os == "windows"`, true},
+		{`facts.os == "darwin"`, false},
+		{`facts.os in ["linux", "darwin"]`, false},
+		{`semverCompare(facts.platform_version, "10.0.19041") >= 0`, true},
+		{`facts.service_manager == "scm"`, false},
+	}
+	for _, tt := range tests {
+		got, err := EvaluateCondition(tt.when, facts)
+		require.NoError(t, err, tt.when)
+		assert.Equal(t, tt.want, got, tt.when)
+	}
+
+	_, err := EvaluateCondition(`facts.os`, facts)
+	assert.Error(t, err, "conditions must be boolean")
+}
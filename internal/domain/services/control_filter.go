@@ -7,11 +7,12 @@ import (
 
 // ControlEnv defines the variables available during filter expression evaluation.
 type ControlEnv struct {
-	ID       string   `expr:"id"`
-	Name     string   `expr:"name"`
-	Severity string   `expr:"severity"`
-	Owner    string   `expr:"owner"`
-	Tags     []string `expr:"tags"`
+	ID       string            `expr:"id"`
+	Name     string            `expr:"name"`
+	Severity string            `expr:"severity"`
+	Owner    string            `expr:"owner"`
+	Labels   map[string]string `expr:"labels"`
+	Tags     []string          `expr:"tags"`
 }
 
 // ControlFilter implements policy selection logic based on tags, severity, and IDs.
@@ -157,6 +157,20 @@ func Test_ControlFilter_FilterExpression(t *testing.T) {
 	}
 }
 
+func Test_ControlFilter_FilterExpression_Labels(t *testing.T) {
+	program, err := expr.Compile(`labels.service == "billing"`, expr.Env(ControlEnv{}), expr.AsBool())
+	require.NoError(t, err)
+
+	filter := NewControlFilter().
+		WithFilterExpression(program)
+
+	shouldRun, _ := filter.ShouldRun(entities.Control{ID: "ctrl-1", Labels: map[string]string{"service": "billing"}})
+	assert.True(t, shouldRun)
+
+	shouldRun, _ = filter.ShouldRun(entities.Control{ID: "ctrl-2"})
+	assert.False(t, shouldRun, "missing label")
+}
+
 func Test_ControlFilter_Precedence(t *testing.T) {
 	// Test: Exclusive mode overrides all other filters
 	filter := NewControlFilter().
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// PlacementNode is a node a distributed run can place controls on.
+type PlacementNode struct {
+	Name  string
+	Tags  []string // Preferred for controls sharing them
+	Facts []string // Matched against runs_on; a control only runs where all of its entries are facts
+}
+
+// Placement is the assignment of controls to nodes.
+type Placement struct {
+	// Controls lists the IDs of the controls of each node, in profile order.
+	// Nodes without controls are left out.
+	Controls map[string][]string
+
+	// Unplaced maps the IDs of controls no node can run to the runs_on
+	// entries, of the control or a control it shares dependencies with, that
+	// no single node satisfies.
+	Unplaced map[string][]string
+}
+
+// ControlPartitioner splits a profile's controls across nodes.
+type ControlPartitioner struct{}
+
+// NewControlPartitioner creates a new control partitioner.
+func NewControlPartitioner() *ControlPartitioner {
+	return &ControlPartitioner{}
+}
+
+// Partition assigns each control to one node.
+//
+// Controls connected by depends_on stay together, because a dependency's
+// status must be known where its dependents run. Each such group goes to a
+// node having all runs_on entries of the group as facts: the one sharing the
+// most tags with it, and among equally good nodes the one with the fewest
+// controls so far, so untagged controls spread evenly. Groups no node
+// satisfies are left unplaced.
+func (p *ControlPartitioner) Partition(controls []entities.Control, nodes []PlacementNode) (*Placement, error) {
+	if len(nodes) == 0 {
+		return nil, errors.New("no nodes to place controls on")
+	}
+	nodeTags := make([]map[string]bool, len(nodes))
+	nodeFacts := make([]map[string]bool, len(nodes))
+	seen := make(map[string]bool, len(nodes))
+	for i, node := range nodes {
+		if seen[node.Name] {
+			return nil, fmt.Errorf("duplicate node %q", node.Name)
+		}
+		seen[node.Name] = true
+		nodeTags[i] = toSet(node.Tags)
+		nodeFacts[i] = toSet(node.Facts)
+	}
+
+	load := make([]int, len(nodes))
+	placed := make(map[string][]string)
+	unplaced := make(map[string][]string)
+	for _, group := range dependencyGroups(controls) {
+		best := -1
+		bestScore := -1
+		for i := range nodes {
+			if !hasAll(nodeFacts[i], group.runsOn) {
+				continue
+			}
+			score := 0
+			for tag := range group.tags {
+				if nodeTags[i][tag] {
+					score++
+				}
+			}
+			if score > bestScore || (score == bestScore && load[i] < load[best]) {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			for _, id := range group.ids {
+				unplaced[id] = group.runsOn
+			}
+			continue
+		}
+		load[best] += len(group.ids)
+		placed[nodes[best].Name] = append(placed[nodes[best].Name], group.ids...)
+	}
+
+	// Groups interleave in the profile; restore profile order per node.
+	position := make(map[string]int, len(controls))
+	for i, ctrl := range controls {
+		position[ctrl.ID] = i
+	}
+	for _, ids := range placed {
+		sort.Slice(ids, func(i, j int) bool { return position[ids[i]] < position[ids[j]] })
+	}
+	return &Placement{Controls: placed, Unplaced: unplaced}, nil
+}
+
+// hasAll reports whether set contains all of values.
+func hasAll(set map[string]bool, values []string) bool {
+	for _, value := range values {
+		if !set[value] {
+			return false
+		}
+	}
+	return true
+}
+
+// controlGroup is a set of controls connected by dependencies.
+type controlGroup struct {
+	tags   map[string]bool
+	runsOn []string // runs_on entries of all members, deduplicated
+	ids    []string
+}
+
+// dependencyGroups returns the connected components of the dependency graph
+// of controls, ordered by their first control. Dependencies on controls not
+// in the list are ignored.
+func dependencyGroups(controls []entities.Control) []controlGroup {
+	parent := make(map[string]string, len(controls))
+	for _, ctrl := range controls {
+		parent[ctrl.ID] = ctrl.ID
+	}
+	var find func(id string) string
+	find = func(id string) string {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+	for _, ctrl := range controls {
+		for _, dep := range ctrl.DependsOn {
+			if _, ok := parent[dep]; ok {
+				parent[find(dep)] = find(ctrl.ID)
+			}
+		}
+	}
+
+	index := make(map[string]int)
+	var groups []controlGroup
+	for _, ctrl := range controls {
+		root := find(ctrl.ID)
+		i, ok := index[root]
+		if !ok {
+			i = len(groups)
+			index[root] = i
+			groups = append(groups, controlGroup{tags: make(map[string]bool)})
+		}
+		groups[i].ids = append(groups[i].ids, ctrl.ID)
+		for _, tag := range ctrl.Tags {
+			groups[i].tags[tag] = true
+		}
+		for _, fact := range ctrl.RunsOn {
+			if !slices.Contains(groups[i].runsOn, fact) {
+				groups[i].runsOn = append(groups[i].runsOn, fact)
+			}
+		}
+	}
+	return groups
+}
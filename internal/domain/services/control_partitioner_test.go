@@ -0,0 +1,97 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ControlPartitioner_TagAffinity(t *testing.T) {
+	t.Parallel()
+
+	controls := []entities.Control{
+		{ID: "nginx", Tags: []string{"web"}},
+		{ID: "postgres", Tags: []string{"db"}},
+		{ID: "tls", Tags: []string{"web", "public"}},
+		{ID: "replication", Tags: []string{"db"}},
+	}
+	nodes := []PlacementNode{
+		{Name: "web-1", Tags: []string{"web", "public"}},
+		{Name: "db-1", Tags: []string{"db"}},
+	}
+
+	placed, err := NewControlPartitioner().Partition(controls, nodes)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"web-1": {"nginx", "tls"},
+		"db-1":  {"postgres", "replication"},
+	}, placed.Controls)
+}
+
+func Test_ControlPartitioner_BalancesUntagged(t *testing.T) {
+	t.Parallel()
+
+	controls := []entities.Control{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+	nodes := []PlacementNode{{Name: "n1"}, {Name: "n2"}, {Name: "n3"}}
+
+	placed, err := NewControlPartitioner().Partition(controls, nodes)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"n1": {"a", "d"}, "n2": {"b"}, "n3": {"c"}}, placed.Controls)
+}
+
+func Test_ControlPartitioner_KeepsDependenciesTogether(t *testing.T) {
+	t.Parallel()
+
+	controls := []entities.Control{
+		{ID: "dns"},
+		{ID: "other"},
+		{ID: "https", DependsOn: []string{"dns"}, Tags: []string{"web"}},
+		{ID: "cert", DependsOn: []string{"https", "outside-selection"}},
+	}
+	nodes := []PlacementNode{{Name: "n1"}, {Name: "n2", Tags: []string{"web"}}}
+
+	placed, err := NewControlPartitioner().Partition(controls, nodes)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"n2": {"dns", "https", "cert"}, // the group's tags come from any member
+		"n1": {"other"},
+	}, placed.Controls)
+}
+
+func Test_ControlPartitioner_RunsOn(t *testing.T) {
+	t.Parallel()
+
+	controls := []entities.Control{
+		{ID: "containers", RunsOn: []string{"linux", "has:docker"}},
+		{ID: "registry", RunsOn: []string{"linux"}},
+		{ID: "gpo", RunsOn: []string{"windows"}},
+		{ID: "image-scan", DependsOn: []string{"containers"}, Tags: []string{"mac"}},
+		{ID: "any"},
+	}
+	nodes := []PlacementNode{
+		{Name: "mac-1", Tags: []string{"mac"}, Facts: []string{"darwin", "has:docker"}},
+		{Name: "build-1", Facts: []string{"linux", "has:docker"}},
+		{Name: "web-1", Facts: []string{"linux"}},
+	}
+
+	placed, err := NewControlPartitioner().Partition(controls, nodes)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"build-1": {"containers", "image-scan"}, // constraints win over tag affinity
+		"web-1":   {"registry"},
+		"mac-1":   {"any"},
+	}, placed.Controls)
+	assert.Equal(t, map[string][]string{"gpo": {"windows"}}, placed.Unplaced)
+}
+
+func Test_ControlPartitioner_InvalidNodes(t *testing.T) {
+	t.Parallel()
+
+	p := NewControlPartitioner()
+	_, err := p.Partition([]entities.Control{{ID: "a"}}, nil)
+	assert.Error(t, err)
+	_, err = p.Partition([]entities.Control{{ID: "a"}}, []PlacementNode{{Name: "n1"}, {Name: "n1"}})
+	assert.ErrorContains(t, err, "duplicate node")
+}
@@ -20,9 +20,15 @@ func DeepCopyProfile(original *entities.Profile) *entities.Profile {
 	}
 
 	return &entities.Profile{
-		Metadata: original.Metadata, // ProfileMetadata is a value type (copied automatically)
-		Plugins:  CopyStringSlice(original.Plugins),
-		Vars:     CopyVars(original.Vars),
+		Metadata:     original.Metadata, // ProfileMetadata is a value type (copied automatically)
+		Plugins:      CopyStringSlice(original.Plugins),
+		Vars:         CopyVars(original.Vars),
+		Integrations: CopyIntegrations(original.Integrations),
+		Environments: CopyEnvironments(original.Environments),
+		Quotas:       CopyQuotas(original.Quotas),
+		Setup:        CopyLifecycleSteps(original.Setup),
+		Teardown:     CopyLifecycleSteps(original.Teardown),
+		Environment:  original.Environment,
 		Controls: entities.ControlsSection{
 			Defaults: CopyDefaults(original.Controls.Defaults),
 			Items:    CopyControls(original.Controls.Items),
@@ -41,6 +47,59 @@ func CopyStringSlice(src []string) []string {
 	return dst
 }
 
+// CopyStringMap creates a copy of a string map.
+func CopyStringMap(src map[string]string) map[string]string {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[string]string, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// CopyIntegrations creates a copy of an integrations map. Each section is
+// copied shallowly, like vars.
+func CopyIntegrations(src map[string]map[string]interface{}) map[string]map[string]interface{} {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[string]map[string]interface{}, len(src))
+	for name, section := range src {
+		dst[name] = CopyVars(section)
+	}
+	return dst
+}
+
+// CopyQuotas creates a copy of a plugin quotas map.
+func CopyQuotas(src map[string]entities.PluginQuota) map[string]entities.PluginQuota {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[string]entities.PluginQuota, len(src))
+	for plugin, quota := range src {
+		dst[plugin] = quota
+	}
+	return dst
+}
+
+// CopyEnvironments creates a copy of an environments map. Vars are copied
+// shallowly, like profile vars.
+func CopyEnvironments(src map[string]entities.Environment) map[string]entities.Environment {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[string]entities.Environment, len(src))
+	for name, env := range src {
+		dst[name] = entities.Environment{
+			Vars:    CopyVars(env.Vars),
+			Targets: CopyStringSlice(env.Targets),
+		}
+	}
+	return dst
+}
+
 // CopyVars creates a shallow copy of a vars map.
 // Note: Values are interface{} and cannot be deep copied generically.
 // For most use cases (strings, numbers, bools), this is sufficient.
@@ -64,6 +123,8 @@ func CopyDefaults(src *entities.ControlDefaults) *entities.ControlDefaults {
 		Severity: src.Severity,
 		Owner:    src.Owner,
 		Tags:     CopyStringSlice(src.Tags),
+		Labels:   CopyStringMap(src.Labels),
+		RunsOn:   CopyStringSlice(src.RunsOn),
 		Timeout:  src.Timeout,
 	}
 }
@@ -79,10 +140,15 @@ func CopyControls(src []entities.Control) []entities.Control {
 			ID:                     ctrl.ID,
 			Name:                   ctrl.Name,
 			Description:            ctrl.Description,
+			Rationale:              ctrl.Rationale,
+			References:             CopyStringSlice(ctrl.References),
 			Severity:               ctrl.Severity,
 			Owner:                  ctrl.Owner,
 			Tags:                   CopyStringSlice(ctrl.Tags),
+			Labels:                 CopyStringMap(ctrl.Labels),
 			DependsOn:              CopyStringSlice(ctrl.DependsOn),
+			RunsOn:                 CopyStringSlice(ctrl.RunsOn),
+			When:                   ctrl.When,
 			Timeout:                ctrl.Timeout,
 			ObservationDefinitions: CopyObservations(ctrl.ObservationDefinitions),
 		}
@@ -100,12 +166,45 @@ func CopyObservations(src []entities.ObservationDefinition) []entities.Observati
 		dst[i] = entities.ObservationDefinition{
 			Plugin: obs.Plugin,
 			Config: CopyConfig(obs.Config),
+			Env:    CopyStringMap(obs.Env),
 			Expect: CopyStringSlice(obs.Expect),
+
+			Privileged:       obs.Privileged,
+			FreshConnections: obs.FreshConnections,
+			Debug:            obs.Debug,
+			Thresholds:       CopyThresholds(obs.Thresholds),
 		}
 	}
 	return dst
 }
 
+// CopyLifecycleSteps creates a deep copy of setup or teardown steps.
+func CopyLifecycleSteps(src []entities.LifecycleStep) []entities.LifecycleStep {
+	if src == nil {
+		return nil
+	}
+	dst := make([]entities.LifecycleStep, len(src))
+	for i, step := range src {
+		dst[i] = entities.LifecycleStep{
+			ID:                    step.ID,
+			ObservationDefinition: CopyObservations([]entities.ObservationDefinition{step.ObservationDefinition})[0],
+		}
+	}
+	return dst
+}
+
+// CopyThresholds creates a copy of an observation's thresholds.
+func CopyThresholds(src map[string]entities.Threshold) map[string]entities.Threshold {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[string]entities.Threshold, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
 // CopyConfig creates a shallow copy of a config map.
 // Note: Values are interface{} and cannot be deep copied generically.
 func CopyConfig(src map[string]interface{}) map[string]interface{} {
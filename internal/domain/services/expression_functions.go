@@ -0,0 +1,336 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/expr-lang/expr"
+)
+
+// ExpressionFunctions returns the helper functions available in expect
+// expressions and --filter expressions, in addition to the expr-lang builtins
+// (now(), date(), duration(), ...).
+//
+//	isIPv4(ip)                      true if ip is an IPv4 address
+//	cidrContains(cidr, ip)          true if ip lies within cidr
+//	semverCompare(a, b)             -1, 0 or 1 comparing semantic versions
+//	semverMatches(v, constraint)    true if v satisfies a constraint such as ">= 1.2, < 2"
+//	parseTime(v [, layout])         time from an RFC 3339 string, a layout or Unix seconds
+//	daysUntil(t)                    fractional days from now until t (negative if past)
+//	base64Decode(s)                 decoded string (standard or URL encoding)
+//	jsonpath(v, path)               value at a JSONPath such as "$.items[0].name" or "$.items[*].name"
+func ExpressionFunctions() []expr.Option {
+	return expressionFunctions(time.Now)
+}
+
+// ExpressionFunctionsWithClock returns the expression functions with now()
+// and daysUntil() reading the given clock instead of the system time.
+func ExpressionFunctionsWithClock(clock func() time.Time) []expr.Option {
+	return append(expressionFunctions(clock),
+		expr.Function("now", func(params ...interface{}) (interface{}, error) {
+			if len(params) != 0 {
+				return nil, fmt.Errorf("now expects no arguments")
+			}
+			return clock(), nil
+		}, new(func() time.Time)),
+	)
+}
+
+func expressionFunctions(clock func() time.Time) []expr.Option {
+	return []expr.Option{
+		expr.Function("isIPv4", isIPv4),
+		expr.Function("cidrContains", cidrContains),
+		expr.Function("semverCompare", semverCompare),
+		expr.Function("semverMatches", semverMatches),
+		expr.Function("parseTime", parseTime),
+		expr.Function("daysUntil", func(params ...interface{}) (interface{}, error) {
+			return daysUntil(clock(), params...)
+		}),
+		expr.Function("base64Decode", base64Decode),
+		expr.Function("jsonpath", jsonpath),
+	}
+}
+
+// stringArgs validates the argument count and that every argument is a string.
+func stringArgs(name string, params []interface{}, n int) ([]string, error) {
+	if len(params) != n {
+		return nil, fmt.Errorf("%s expects %d argument(s)", name, n)
+	}
+	args := make([]string, n)
+	for i, p := range params {
+		s, ok := p.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: argument %d must be a string", name, i+1)
+		}
+		args[i] = s
+	}
+	return args, nil
+}
+
+func isIPv4(params ...interface{}) (interface{}, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("isIPv4 expects 1 argument")
+	}
+	ipStr, ok := params[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("isIPv4: argument must be a string")
+	}
+	ip := net.ParseIP(ipStr)
+	return ip != nil && ip.To4() != nil, nil
+}
+
+func cidrContains(params ...interface{}) (interface{}, error) {
+	args, err := stringArgs("cidrContains", params, 2)
+	if err != nil {
+		return nil, err
+	}
+	_, network, err := net.ParseCIDR(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("cidrContains: %w", err)
+	}
+	ip := net.ParseIP(args[1])
+	if ip == nil {
+		return false, nil
+	}
+	return network.Contains(ip), nil
+}
+
+func semverCompare(params ...interface{}) (interface{}, error) {
+	args, err := stringArgs("semverCompare", params, 2)
+	if err != nil {
+		return nil, err
+	}
+	a, err := semver.NewVersion(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("semverCompare: invalid version %q: %w", args[0], err)
+	}
+	b, err := semver.NewVersion(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("semverCompare: invalid version %q: %w", args[1], err)
+	}
+	return a.Compare(b), nil
+}
+
+func semverMatches(params ...interface{}) (interface{}, error) {
+	args, err := stringArgs("semverMatches", params, 2)
+	if err != nil {
+		return nil, err
+	}
+	v, err := semver.NewVersion(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("semverMatches: invalid version %q: %w", args[0], err)
+	}
+	c, err := semver.NewConstraint(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("semverMatches: invalid constraint %q: %w", args[1], err)
+	}
+	return c.Check(v), nil
+}
+
+func parseTime(params ...interface{}) (interface{}, error) {
+	if len(params) == 2 {
+		args, err := stringArgs("parseTime", params, 2)
+		if err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(args[1], args[0])
+		if err != nil {
+			return nil, fmt.Errorf("parseTime: %w", err)
+		}
+		return t, nil
+	}
+	if len(params) != 1 {
+		return nil, fmt.Errorf("parseTime expects 1 or 2 arguments")
+	}
+	return toTime("parseTime", params[0])
+}
+
+// toTime converts RFC 3339 strings, Unix seconds and times to a time.
+func toTime(name string, v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s: %w", name, err)
+		}
+		return parsed, nil
+	case int:
+		return time.Unix(int64(t), 0).UTC(), nil
+	case int64:
+		return time.Unix(t, 0).UTC(), nil
+	case float64:
+		sec := int64(t)
+		return time.Unix(sec, int64((t-float64(sec))*float64(time.Second))).UTC(), nil
+	default:
+		return time.Time{}, fmt.Errorf("%s: unsupported time value %T", name, v)
+	}
+}
+
+func daysUntil(now time.Time, params ...interface{}) (interface{}, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("daysUntil expects 1 argument")
+	}
+	t, err := toTime("daysUntil", params[0])
+	if err != nil {
+		return nil, err
+	}
+	return t.Sub(now).Hours() / 24, nil
+}
+
+func base64Decode(params ...interface{}) (interface{}, error) {
+	args, err := stringArgs("base64Decode", params, 1)
+	if err != nil {
+		return nil, err
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if decoded, err := enc.DecodeString(args[0]); err == nil {
+			return string(decoded), nil
+		}
+	}
+	return nil, fmt.Errorf("base64Decode: input is not valid base64")
+}
+
+func jsonpath(params ...interface{}) (interface{}, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("jsonpath expects 2 arguments")
+	}
+	path, ok := params[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("jsonpath: path must be a string")
+	}
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath: %w", err)
+	}
+
+	// Raw JSON text (e.g. an HTTP body) is decoded first
+	root := params[0]
+	if s, ok := root.(string); ok {
+		if err := json.Unmarshal([]byte(s), &root); err != nil {
+			return nil, fmt.Errorf("jsonpath: value is not JSON: %w", err)
+		}
+	}
+
+	return evalJSONPath(root, segments), nil
+}
+
+// jsonPathSegment is a single step of a JSONPath: a key, an index or a wildcard.
+type jsonPathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses the supported JSONPath subset: $, .key, ['key'], [n]
+// (negative counts from the end) and [*] / .*.
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("path %q must start with $", path)
+	}
+	rest := path[1:]
+
+	var segments []jsonPathSegment
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			if key == "" {
+				return nil, fmt.Errorf("empty key in path %q", path)
+			}
+			segments = append(segments, jsonPathSegment{key: key, wildcard: key == "*"})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed [ in path %q", path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			switch {
+			case inner == "*":
+				segments = append(segments, jsonPathSegment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segments = append(segments, jsonPathSegment{key: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q in path %q", inner, path)
+				}
+				segments = append(segments, jsonPathSegment{index: n, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q in path %q", rest[0], path)
+		}
+	}
+	return segments, nil
+}
+
+// evalJSONPath applies the segments to root. Paths with a wildcard return a
+// list of all matches; other paths return the single value or nil if absent.
+func evalJSONPath(root interface{}, segments []jsonPathSegment) interface{} {
+	nodes := []interface{}{root}
+	multi := false
+
+	for _, seg := range segments {
+		var next []interface{}
+		for _, node := range nodes {
+			switch v := node.(type) {
+			case map[string]interface{}:
+				if seg.wildcard {
+					// Sorted keys keep wildcard results deterministic
+					keys := make([]string, 0, len(v))
+					for key := range v {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						next = append(next, v[key])
+					}
+				} else if child, ok := v[seg.key]; ok && !seg.isIndex {
+					next = append(next, child)
+				}
+			case []interface{}:
+				switch {
+				case seg.wildcard:
+					next = append(next, v...)
+				case seg.isIndex:
+					i := seg.index
+					if i < 0 {
+						i += len(v)
+					}
+					if i >= 0 && i < len(v) {
+						next = append(next, v[i])
+					}
+				}
+			}
+		}
+		multi = multi || seg.wildcard
+		nodes = next
+	}
+
+	if multi {
+		if nodes == nil {
+			return []interface{}{}
+		}
+		return nodes
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[0]
+}
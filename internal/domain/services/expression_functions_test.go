@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionFunctions_InExpectations(t *testing.T) {
+	t.Parallel()
+
+	aggregator := NewStatusAggregator()
+	evidence := &execution.Evidence{
+		Status: true,
+		Data: map[string]interface{}{
+			"ip":        "10.1.2.3",
+			"version":   "1.24.3",
+			"not_after": time.Now().Add(90 * 24 * time.Hour).UTC().Format(time.RFC3339),
+			"issued":    float64(1700000000),
+			"secret":    "aGVsbG8=",
+			"body":      `{"items":[{"name":"a","replicas":3},{"name":"b","replicas":1}]}`,
+			"config":    map[string]interface{}{"tls": map[string]interface{}{"min_version": "1.2"}},
+		},
+	}
+
+	tests := []struct {
+		expr       string
+		wantStatus values.Status
+	}{
+		{`cidrContains("10.0.0.0/8", data.ip)`, values.StatusPass},
+		{`cidrContains("192.168.0.0/16", data.ip)`, values.StatusFail},
+		{`semverCompare(data.version, "1.24.0") >= 0`, values.StatusPass},
+		{`semverMatches(data.version, ">= 1.25")`, values.StatusFail},
+		{`semverMatches(data.version, "~1.24")`, values.StatusPass},
+		{`daysUntil(data.not_after) > 30`, values.StatusPass},
+		{`parseTime(data.not_after) - now() > duration("720h")`, values.StatusPass},
+		{`parseTime(data.issued).Year() == 2023`, values.StatusPass},
+		{`parseTime("2024-01-02", "2006-01-02").Day() == 2`, values.StatusPass},
+		{`base64Decode(data.secret) == "hello"`, values.StatusPass},
+		{`jsonpath(data.body, "$.items[0].name") == "a"`, values.StatusPass},
+		{`all(jsonpath(data.body, "$.items[*].replicas"), # >= 2)`, values.StatusFail},
+		{`jsonpath(data.config, "$.tls.min_version") == "1.2"`, values.StatusPass},
+		{`semverCompare(data.version, "not-a-version") == 0`, values.StatusError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			status, results := aggregator.DetermineObservationStatus(context.Background(), evidence, []string{tt.expr})
+			assert.Equal(t, tt.wantStatus, status, "%+v", results)
+		})
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	t.Parallel()
+
+	doc := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "a", "labels": map[string]interface{}{"app.kubernetes.io/name": "web"}},
+			map[string]interface{}{"name": "b"},
+		},
+		"meta": map[string]interface{}{"y": float64(2), "x": float64(1)},
+	}
+
+	tests := []struct {
+		path    string
+		want    interface{}
+		wantErr string
+	}{
+		{path: "$", want: doc},
+		{path: "$.items[1].name", want: "b"},
+		{path: "$.items[-1].name", want: "b"},
+		{path: "$.items[0].labels['app.kubernetes.io/name']", want: "web"},
+		{path: "$.items[*].name", want: []interface{}{"a", "b"}},
+		{path: "$.meta.*", want: []interface{}{float64(1), float64(2)}},
+		{path: "$.missing.name", want: nil},
+		{path: "$.missing[*]", want: []interface{}{}},
+		{path: "items", wantErr: "must start with $"},
+		{path: "$.items[x]", wantErr: "invalid index"},
+		{path: "$.items[0", wantErr: "unclosed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := jsonpath(doc, tt.path)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpressionFunctions_WithClock(t *testing.T) {
+	t.Parallel()
+
+	fixed := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	aggregator := NewStatusAggregatorWithClock(func() time.Time { return fixed })
+	evidence := &execution.Evidence{
+		Status: true,
+		Data:   map[string]interface{}{"not_after": "2026-01-31T00:00:00Z"},
+	}
+
+	for _, expr := range []string{
+		`daysUntil(data.not_after) == 30`,
+		`now().Year() == 2026 && now().YearDay() == 1`,
+		`parseTime(data.not_after) > now()`,
+	} {
+		status, results := aggregator.DetermineObservationStatus(context.Background(), evidence, []string{expr})
+		require.Len(t, results, 1)
+		assert.Equal(t, values.StatusPass, status, "%s: %s", expr, results[0].Message)
+	}
+}
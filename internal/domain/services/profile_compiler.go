@@ -40,6 +40,14 @@ func (c *ProfileCompiler) Compile(raw *entities.Profile) (*entities.ValidatedPro
 	if err := compiled.Validate(); err != nil {
 		return nil, fmt.Errorf("profile validation failed: %w", err)
 	}
+	for _, ctrl := range compiled.Controls.Items {
+		if ctrl.When == "" {
+			continue
+		}
+		if _, err := CompileCondition(ctrl.When); err != nil {
+			return nil, fmt.Errorf("profile validation failed: control %s: invalid when condition: %w", ctrl.ID, err)
+		}
+	}
 
 	// Step 4: Create immutable ValidatedProfile
 	return entities.NewValidatedProfile(compiled), nil
@@ -84,6 +92,20 @@ func (c *ProfileCompiler) applyDefaults(profile *entities.Profile) {
 			ctrl.Tags = mergedTags
 		}
 
+		// Merge labels (control labels win)
+		if len(defaults.Labels) > 0 {
+			labels := CopyStringMap(defaults.Labels)
+			for key, value := range ctrl.Labels {
+				labels[key] = value
+			}
+			ctrl.Labels = labels
+		}
+
+		// Apply default runs_on if the control sets none
+		if len(ctrl.RunsOn) == 0 && len(defaults.RunsOn) > 0 {
+			ctrl.RunsOn = CopyStringSlice(defaults.RunsOn)
+		}
+
 		// Apply default timeout if not set
 		if ctrl.Timeout == 0 && defaults.Timeout > 0 {
 			ctrl.Timeout = defaults.Timeout
@@ -26,6 +26,7 @@ func Test_ProfileCompiler_Compile_Success(t *testing.T) {
 				Severity: "medium",
 				Owner:    "security-team",
 				Tags:     []string{"compliance"},
+				RunsOn:   []string{"linux"},
 				Timeout:  30 * time.Second,
 			},
 			Items: []entities.Control{
@@ -41,6 +42,7 @@ func Test_ProfileCompiler_Compile_Success(t *testing.T) {
 					Name:     "Check Config",
 					Severity: "high", // Override default
 					Tags:     []string{"config"},
+					RunsOn:   []string{"has:app"},
 					ObservationDefinitions: []entities.ObservationDefinition{
 						{Plugin: "file", Config: map[string]interface{}{"path": "/etc/app.conf"}},
 					},
@@ -63,6 +65,7 @@ func Test_ProfileCompiler_Compile_Success(t *testing.T) {
 	assert.Equal(t, "security-team", ctrl1.Owner, "Should inherit default owner")
 	assert.Contains(t, ctrl1.Tags, "compliance", "Should inherit default tags")
 	assert.Equal(t, 30*time.Second, ctrl1.Timeout, "Should inherit default timeout")
+	assert.Equal(t, []string{"linux"}, ctrl1.RunsOn, "Should inherit default runs_on")
 
 	// Verify C-002 kept its overrides but merged tags
 	ctrl2 := validated.GetControl("C-002")
@@ -70,6 +73,7 @@ func Test_ProfileCompiler_Compile_Success(t *testing.T) {
 	assert.Equal(t, "high", ctrl2.Severity, "Should keep explicit severity")
 	assert.Contains(t, ctrl2.Tags, "compliance", "Should have default tag")
 	assert.Contains(t, ctrl2.Tags, "config", "Should have explicit tag")
+	assert.Equal(t, []string{"has:app"}, ctrl2.RunsOn, "Should keep explicit runs_on")
 
 	// Verify original profile was NOT mutated
 	origCtrl1 := raw.GetControl("C-001")
@@ -183,6 +187,21 @@ func Test_ProfileCompiler_Compile_ValidationFails(t *testing.T) {
 			},
 			errMsg: "circular dependency",
 		},
+		{
+			name: "invalid when condition",
+			profile: &entities.Profile{
+				Metadata: entities.ProfileMetadata{
+					Name:    "test",
+					Version: "1.0.0",
+				},
+				Controls: entities.ControlsSection{
+					Items: []entities.Control{
+						{ID: "C-001", Name: "Test", When: `facts.os ==`, ObservationDefinitions: []entities.ObservationDefinition{{Plugin: "file"}}},
+					},
+				},
+			},
+			errMsg: "control C-001: invalid when condition",
+		},
 	}
 
 	for _, tt := range tests {
@@ -11,8 +11,12 @@ import (
 // Merge Semantics:
 //   - Metadata: overlay wins, fallback to base if empty
 //   - Vars: deep merge, overlay wins on conflict
+//   - Integrations: merge by exporter name (same name = overlay section replaces base)
+//   - Environments: merge by name (vars merge, overlay wins; overlay targets replace base targets)
+//   - Quotas: merge by plugin name (same name = overlay quota replaces base)
+//   - Setup, Teardown: merge by step ID (same ID = replace, new ID = append)
 //   - Plugins: concatenate and deduplicate (preserving order)
-//   - Controls.Defaults: deep merge, overlay wins (tags concatenate)
+//   - Controls.Defaults: deep merge, overlay wins (tags concatenate, labels merge by key)
 //   - Controls.Items: merge by ID (same ID = replace, new ID = append)
 //   - Extends: NOT propagated (already resolved)
 type ProfileMerger struct{}
@@ -71,6 +75,19 @@ func (m *ProfileMerger) mergeTwoProfiles(
 	// Vars: deep merge (overlay wins on conflict)
 	merged.Vars = m.mergeVars(base.Vars, overlay.Vars)
 
+	// Integrations: merge by exporter name (overlay section wins)
+	merged.Integrations = m.mergeIntegrations(base.Integrations, overlay.Integrations)
+
+	// Environments: merge by name (vars merge, overlay targets replace)
+	merged.Environments = m.mergeEnvironments(base.Environments, overlay.Environments)
+
+	// Quotas: merge by plugin name (overlay quota wins)
+	merged.Quotas = m.mergeQuotas(base.Quotas, overlay.Quotas)
+
+	// Setup, Teardown: merge by step ID (same ID = replace, new ID = append)
+	merged.Setup = m.mergeLifecycleSteps(base.Setup, overlay.Setup)
+	merged.Teardown = m.mergeLifecycleSteps(base.Teardown, overlay.Teardown)
+
 	// Plugins: concatenate and deduplicate
 	merged.Plugins = m.mergeStringSliceDedup(base.Plugins, overlay.Plugins)
 
@@ -106,6 +123,67 @@ func (m *ProfileMerger) mergeMetadata(
 	return result
 }
 
+// mergeQuotas merges plugin quotas by plugin name. A quota in overlay
+// replaces the base quota of the same plugin as a whole.
+func (m *ProfileMerger) mergeQuotas(
+	base, overlay map[string]entities.PluginQuota,
+) map[string]entities.PluginQuota {
+	if base == nil && overlay == nil {
+		return nil
+	}
+	result := make(map[string]entities.PluginQuota)
+	for plugin, quota := range base {
+		result[plugin] = quota
+	}
+	for plugin, quota := range overlay {
+		result[plugin] = quota
+	}
+	return result
+}
+
+// mergeIntegrations merges integration sections by exporter name. A section
+// in overlay replaces the base section of the same name as a whole, so a
+// child profile never inherits half of a parent's exporter settings.
+func (m *ProfileMerger) mergeIntegrations(
+	base, overlay map[string]map[string]interface{},
+) map[string]map[string]interface{} {
+	if base == nil && overlay == nil {
+		return nil
+	}
+	result := make(map[string]map[string]interface{})
+	for name, section := range base {
+		result[name] = CopyVars(section)
+	}
+	for name, section := range overlay {
+		result[name] = CopyVars(section)
+	}
+	return result
+}
+
+// mergeEnvironments merges environments by name. Vars of an environment
+// defined in both merge with overlay winning; overlay targets, when set,
+// replace the base list rather than extending it.
+func (m *ProfileMerger) mergeEnvironments(
+	base, overlay map[string]entities.Environment,
+) map[string]entities.Environment {
+	if base == nil && overlay == nil {
+		return nil
+	}
+	result := CopyEnvironments(base)
+	if result == nil {
+		result = make(map[string]entities.Environment, len(overlay))
+	}
+	for name, env := range overlay {
+		merged := result[name]
+		merged.Vars = m.mergeVars(merged.Vars, env.Vars)
+		if len(env.Targets) > 0 {
+			merged.Targets = CopyStringSlice(env.Targets)
+		}
+		result[name] = merged
+	}
+	return result
+}
+
 // mergeVars performs a shallow merge of vars maps with overlay winning.
 func (m *ProfileMerger) mergeVars(
 	base, overlay map[string]interface{},
@@ -123,6 +201,21 @@ func (m *ProfileMerger) mergeVars(
 	return result
 }
 
+// mergeLabels merges label maps with overlay winning.
+func (m *ProfileMerger) mergeLabels(base, overlay map[string]string) map[string]string {
+	if base == nil && overlay == nil {
+		return nil
+	}
+	result := make(map[string]string, len(base)+len(overlay))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range overlay {
+		result[k] = v
+	}
+	return result
+}
+
 // mergeStringSliceDedup concatenates two slices and deduplicates, preserving order.
 func (m *ProfileMerger) mergeStringSliceDedup(base, overlay []string) []string {
 	seen := make(map[string]bool)
@@ -157,6 +250,7 @@ func (m *ProfileMerger) mergeDefaults(
 		result.Severity = base.Severity
 		result.Owner = base.Owner
 		result.Tags = CopyStringSlice(base.Tags)
+		result.Labels = CopyStringMap(base.Labels)
 		result.Timeout = base.Timeout
 	}
 	if overlay != nil {
@@ -168,6 +262,8 @@ func (m *ProfileMerger) mergeDefaults(
 		}
 		// Tags: concatenate and deduplicate (not replace)
 		result.Tags = m.mergeStringSliceDedup(result.Tags, overlay.Tags)
+		// Labels: merge by key, overlay wins
+		result.Labels = m.mergeLabels(result.Labels, overlay.Labels)
 		if overlay.Timeout > 0 {
 			result.Timeout = overlay.Timeout
 		}
@@ -215,3 +311,27 @@ func (m *ProfileMerger) mergeControlItems(
 
 	return result
 }
+
+// mergeLifecycleSteps merges setup or teardown steps by ID. A step in overlay
+// replaces the base step with the same ID in place; new steps are appended.
+func (m *ProfileMerger) mergeLifecycleSteps(
+	base, overlay []entities.LifecycleStep,
+) []entities.LifecycleStep {
+	if base == nil && overlay == nil {
+		return nil
+	}
+	result := CopyLifecycleSteps(base)
+	index := make(map[string]int, len(result))
+	for i, step := range result {
+		index[step.ID] = i
+	}
+	for _, step := range CopyLifecycleSteps(overlay) {
+		if i, ok := index[step.ID]; ok {
+			result[i] = step
+			continue
+		}
+		index[step.ID] = len(result)
+		result = append(result, step)
+	}
+	return result
+}
@@ -87,6 +87,118 @@ func Test_ProfileMerger_MergeVars_DeepMerge(t *testing.T) {
 	assert.Equal(t, 30, result.Vars["timeout"])
 }
 
+func Test_ProfileMerger_MergeIntegrations_SectionReplaces(t *testing.T) {
+	t.Parallel()
+	merger := NewProfileMerger()
+
+	base := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "base", Version: "1.0.0"},
+		Integrations: map[string]map[string]interface{}{
+			"jira":  {"url": "https://jira.example.com", "project": "BASE"},
+			"slack": {"channel": "#compliance"},
+		},
+	}
+
+	overlay := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "overlay", Version: "1.0.0"},
+		Integrations: map[string]map[string]interface{}{
+			"jira": {"url": "https://jira.internal", "project": "SEC"},
+		},
+	}
+
+	result := merger.Merge(base, overlay)
+
+	assert.Equal(t, map[string]interface{}{"url": "https://jira.internal", "project": "SEC"}, result.Integrations["jira"])
+	assert.Equal(t, map[string]interface{}{"channel": "#compliance"}, result.Integrations["slack"])
+
+	result.Integrations["slack"]["channel"] = "#changed"
+	assert.Equal(t, "#compliance", base.Integrations["slack"]["channel"], "Merge must not share sections with inputs")
+}
+
+func Test_ProfileMerger_MergeQuotas_ByPlugin(t *testing.T) {
+	t.Parallel()
+	merger := NewProfileMerger()
+
+	base := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "base", Version: "1.0.0"},
+		Quotas: map[string]entities.PluginQuota{
+			"http":    {MaxInvocations: 10, MaxHostCalls: 100},
+			"command": {MaxRuntime: time.Minute},
+		},
+	}
+
+	overlay := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "overlay", Version: "1.0.0"},
+		Quotas: map[string]entities.PluginQuota{
+			"http": {MaxInvocations: 5},
+		},
+	}
+
+	result := merger.Merge(base, overlay)
+
+	assert.Equal(t, map[string]entities.PluginQuota{
+		"http":    {MaxInvocations: 5},
+		"command": {MaxRuntime: time.Minute},
+	}, result.Quotas)
+	assert.Nil(t, merger.Merge(&entities.Profile{}, &entities.Profile{}).Quotas)
+}
+
+func Test_ProfileMerger_MergeLifecycleSteps_ByID(t *testing.T) {
+	t.Parallel()
+	merger := NewProfileMerger()
+
+	step := func(id, plugin string) entities.LifecycleStep {
+		return entities.LifecycleStep{ID: id, ObservationDefinition: entities.ObservationDefinition{Plugin: plugin}}
+	}
+	base := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "base", Version: "1.0.0"},
+		Setup:    []entities.LifecycleStep{step("auth", "http"), step("tunnel", "command")},
+		Teardown: []entities.LifecycleStep{step("close", "command")},
+	}
+	overlay := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "overlay", Version: "1.0.0"},
+		Setup:    []entities.LifecycleStep{step("auth", "oauth"), step("seed", "file")},
+	}
+
+	result := merger.Merge(base, overlay)
+
+	assert.Equal(t, []entities.LifecycleStep{step("auth", "oauth"), step("tunnel", "command"), step("seed", "file")}, result.Setup)
+	assert.Equal(t, []entities.LifecycleStep{step("close", "command")}, result.Teardown)
+	assert.Nil(t, merger.Merge(&entities.Profile{}, &entities.Profile{}).Setup)
+}
+
+func Test_ProfileMerger_MergeEnvironments_ByName(t *testing.T) {
+	t.Parallel()
+	merger := NewProfileMerger()
+
+	base := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "base", Version: "1.0.0"},
+		Environments: map[string]entities.Environment{
+			"prod":    {Vars: map[string]interface{}{"port": 443, "min_days": 30}, Targets: []string{"web-1"}},
+			"staging": {Targets: []string{"stg-1"}},
+		},
+	}
+
+	overlay := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "overlay", Version: "1.0.0"},
+		Environments: map[string]entities.Environment{
+			"prod": {Vars: map[string]interface{}{"min_days": 14}},
+			"dev":  {Targets: []string{"localhost"}},
+		},
+	}
+
+	result := merger.Merge(base, overlay)
+
+	require.Len(t, result.Environments, 3)
+	assert.Equal(t, map[string]interface{}{"port": 443, "min_days": 14}, result.Environments["prod"].Vars)
+	assert.Equal(t, []string{"web-1"}, result.Environments["prod"].Targets, "Empty overlay targets should inherit from base")
+	assert.Equal(t, []string{"stg-1"}, result.Environments["staging"].Targets)
+	assert.Equal(t, []string{"localhost"}, result.Environments["dev"].Targets)
+
+	result.Environments["prod"].Targets[0] = "changed"
+	assert.Equal(t, "web-1", base.Environments["prod"].Targets[0], "Merge must not share targets with inputs")
+}
+
 func Test_ProfileMerger_MergePlugins_Deduplicate(t *testing.T) {
 	t.Parallel()
 	merger := NewProfileMerger()
@@ -114,7 +226,7 @@ func Test_ProfileMerger_MergePlugins_Deduplicate(t *testing.T) {
 	result := merger.Merge(base, overlay)
 
 	// Should preserve order: base first, then new overlay plugins
-	expected := []string{"reglet/file@1.0", "reglet/http@1.0", "reglet/dns@1.0"}
+	expected := entities.PluginDeclarations{"reglet/file@1.0", "reglet/http@1.0", "reglet/dns@1.0"}
 	assert.Equal(t, expected, result.Plugins)
 }
 
@@ -129,6 +241,7 @@ func Test_ProfileMerger_MergeDefaults_TagsConcatenate(t *testing.T) {
 				Severity: "high",
 				Owner:    "base-owner",
 				Tags:     []string{"security", "compliance"},
+				Labels:   map[string]string{"service": "base", "cost_center": "cc-1"},
 				Timeout:  30 * time.Second,
 			},
 			Items: []entities.Control{
@@ -144,7 +257,8 @@ func Test_ProfileMerger_MergeDefaults_TagsConcatenate(t *testing.T) {
 				Severity: "critical", // Override
 				// Owner is empty - inherit from base
 				Tags:    []string{"production", "security"}, // security is duplicate
-				Timeout: 60 * time.Second,                   // Override
+				Labels:  map[string]string{"service": "overlay"},
+				Timeout: 60 * time.Second, // Override
 			},
 			Items: []entities.Control{
 				{ID: "ctrl-2", Name: "Control 2", ObservationDefinitions: []entities.ObservationDefinition{{Plugin: "http"}}},
@@ -164,6 +278,9 @@ func Test_ProfileMerger_MergeDefaults_TagsConcatenate(t *testing.T) {
 	assert.Contains(t, result.Controls.Defaults.Tags, "compliance")
 	assert.Contains(t, result.Controls.Defaults.Tags, "production")
 	assert.Len(t, result.Controls.Defaults.Tags, 3, "Should have 3 unique tags")
+
+	// Labels merge by key, overlay wins
+	assert.Equal(t, map[string]string{"service": "overlay", "cost_center": "cc-1"}, result.Controls.Defaults.Labels)
 }
 
 func Test_ProfileMerger_MergeControlItems_SameIDReplaces(t *testing.T) {
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// Severity rule modes: how a matching rule treats the declared severity.
+const (
+	SeverityModeOverride = "override" // replace it (default)
+	SeverityModeDefault  = "default"  // only set a severity where none is declared
+	SeverityModeRaise    = "raise"    // only replace a lower severity
+)
+
+// SeverityRule assigns a severity to the controls it matches, keeping
+// severities consistent across large control sets. A rule matches a control
+// when all of its conditions hold; a rule without conditions matches every
+// control.
+type SeverityRule struct {
+	Name     string   `yaml:"name"`
+	Severity string   `yaml:"severity"`
+	Mode     string   `yaml:"mode,omitempty"`
+	Tags     []string `yaml:"tags,omitempty"`   // the control has any of the tags
+	Plugin   string   `yaml:"plugin,omitempty"` // an observation of the control ran the plugin
+	When     string   `yaml:"when,omitempty"`   // expression true for the evidence of an observation (of Plugin, if set)
+}
+
+// severityRuleEnv is the environment of severity rule when expressions: the
+// evidence of one observation, as in expect expressions, and the control.
+type severityRuleEnv struct {
+	Data     map[string]interface{} `expr:"data"`
+	Status   string                 `expr:"status"` // observation status
+	Plugin   string                 `expr:"plugin"`
+	Control  string                 `expr:"control"`
+	Severity string                 `expr:"severity"` // declared severity
+	Tags     []string               `expr:"tags"`
+	Labels   map[string]string      `expr:"labels"`
+}
+
+// compiledSeverityRule is a validated rule with its compiled condition.
+type compiledSeverityRule struct {
+	SeverityRule
+	severity values.Severity
+	when     *vm.Program
+}
+
+// SeverityRules applies severity rules to control results. The first rule
+// matching a control decides its severity.
+type SeverityRules struct {
+	rules []compiledSeverityRule
+}
+
+// NewSeverityRules validates rules and compiles their conditions. Time
+// functions in conditions, such as daysUntil, read clock.
+func NewSeverityRules(rules []SeverityRule, clock func() time.Time) (*SeverityRules, error) {
+	options := append([]expr.Option{expr.Env(severityRuleEnv{}), expr.AsBool()}, ExpressionFunctionsWithClock(clock)...)
+
+	compiled := make([]compiledSeverityRule, 0, len(rules))
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+		severity, err := values.NewSeverity(rule.Severity)
+		if err != nil || rule.Severity == "" {
+			return nil, fmt.Errorf("%s: invalid severity %q (must be low, medium, high, or critical)", name, rule.Severity)
+		}
+		if rule.Mode == "" {
+			rule.Mode = SeverityModeOverride
+		}
+		if !slices.Contains([]string{SeverityModeOverride, SeverityModeDefault, SeverityModeRaise}, rule.Mode) {
+			return nil, fmt.Errorf("%s: invalid mode %q (must be override, default, or raise)", name, rule.Mode)
+		}
+
+		c := compiledSeverityRule{SeverityRule: rule, severity: severity}
+		c.Name = name
+		if rule.When != "" {
+			if c.when, err = expr.Compile(rule.When, options...); err != nil {
+				return nil, fmt.Errorf("%s: invalid when expression: %w", name, err)
+			}
+		}
+		compiled = append(compiled, c)
+	}
+	return &SeverityRules{rules: compiled}, nil
+}
+
+// Apply sets the severity of ctrl from the first rule matching it, keeping
+// the declared severity in DeclaredSeverity when it changes.
+func (r *SeverityRules) Apply(ctrl *execution.ControlResult) {
+	for _, rule := range r.rules {
+		if !rule.matches(ctrl) {
+			continue
+		}
+
+		declared, _ := values.NewSeverity(ctrl.Severity)
+		switch {
+		case rule.Mode == SeverityModeDefault && ctrl.Severity != "":
+		case rule.Mode == SeverityModeRaise && !rule.severity.IsHigherThan(declared):
+		case rule.severity.String() != ctrl.Severity:
+			ctrl.DeclaredSeverity = ctrl.Severity
+			ctrl.Severity = rule.severity.String()
+			ctrl.SeverityRule = rule.Name
+		}
+		return
+	}
+}
+
+// matches reports whether all conditions of the rule hold for ctrl.
+func (r *compiledSeverityRule) matches(ctrl *execution.ControlResult) bool {
+	if len(r.Tags) > 0 && !slices.ContainsFunc(r.Tags, func(tag string) bool { return slices.Contains(ctrl.Tags, tag) }) {
+		return false
+	}
+	if r.Plugin == "" && r.when == nil {
+		return true
+	}
+
+	for _, obs := range ctrl.ObservationResults {
+		if r.Plugin != "" && obs.Plugin != r.Plugin {
+			continue
+		}
+		if r.when == nil || r.holds(ctrl, &obs) {
+			return true
+		}
+	}
+	return false
+}
+
+// holds reports whether the when condition is true for an observation.
+// Conditions that fail to evaluate, for example over evidence the
+// observation lacks, do not hold.
+func (r *compiledSeverityRule) holds(ctrl *execution.ControlResult, obs *execution.ObservationResult) bool {
+	env := severityRuleEnv{
+		Status:   string(obs.Status),
+		Plugin:   obs.Plugin,
+		Control:  ctrl.ID,
+		Severity: ctrl.Severity,
+		Tags:     ctrl.Tags,
+		Labels:   ctrl.Labels,
+	}
+	if obs.Evidence != nil {
+		env.Data = obs.Evidence.Data
+	}
+	output, err := expr.Run(r.when, env)
+	if err != nil {
+		return false
+	}
+	holds, _ := output.(bool)
+	return holds
+}
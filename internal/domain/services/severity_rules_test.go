@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tlsControl returns a control whose tcp observation reports a certificate
+// expiring in days.
+func tlsControl(severity string, days int) execution.ControlResult {
+	return execution.ControlResult{
+		ID:       "api-tls",
+		Severity: severity,
+		Tags:     []string{"tls", "prod"},
+		ObservationResults: []execution.ObservationResult{
+			{Plugin: "dns", Evidence: &execution.Evidence{Data: map[string]interface{}{"records": []string{"1.2.3.4"}}}},
+			{Plugin: "tcp", Evidence: &execution.Evidence{Data: map[string]interface{}{"tls_cert_days_remaining": days}}},
+		},
+	}
+}
+
+func TestSeverityRules_Apply(t *testing.T) {
+	rules, err := NewSeverityRules([]SeverityRule{
+		{Name: "tls-expiry", Plugin: "tcp", When: "data.tls_cert_days_remaining < 7", Severity: "critical"},
+		{Name: "prod-floor", Tags: []string{"prod"}, Severity: "medium", Mode: SeverityModeRaise},
+		{Name: "fallback", Severity: "low", Mode: SeverityModeDefault},
+	}, time.Now)
+	require.NoError(t, err)
+
+	t.Run("evidence overrides the declared severity", func(t *testing.T) {
+		ctrl := tlsControl("low", 3)
+		rules.Apply(&ctrl)
+		assert.Equal(t, "critical", ctrl.Severity)
+		assert.Equal(t, "low", ctrl.DeclaredSeverity)
+		assert.Equal(t, "tls-expiry", ctrl.SeverityRule)
+	})
+
+	t.Run("first matching rule decides", func(t *testing.T) {
+		ctrl := tlsControl("low", 30)
+		rules.Apply(&ctrl)
+		assert.Equal(t, "medium", ctrl.Severity, "raised by prod-floor")
+		assert.Equal(t, "prod-floor", ctrl.SeverityRule)
+
+		ctrl = tlsControl("high", 30)
+		rules.Apply(&ctrl)
+		assert.Equal(t, "high", ctrl.Severity, "raise never lowers")
+		assert.Empty(t, ctrl.SeverityRule)
+	})
+
+	t.Run("default only fills a missing severity", func(t *testing.T) {
+		ctrl := execution.ControlResult{ID: "other"}
+		rules.Apply(&ctrl)
+		assert.Equal(t, "low", ctrl.Severity)
+		assert.Empty(t, ctrl.DeclaredSeverity)
+		assert.Equal(t, "fallback", ctrl.SeverityRule)
+
+		ctrl = execution.ControlResult{ID: "other", Severity: "high"}
+		rules.Apply(&ctrl)
+		assert.Equal(t, "high", ctrl.Severity)
+	})
+
+	t.Run("conditions over missing evidence do not hold", func(t *testing.T) {
+		ctrl := tlsControl("", 0)
+		ctrl.Tags = nil
+		ctrl.ObservationResults[1].Evidence = nil
+		rules.Apply(&ctrl)
+		assert.Equal(t, "fallback", ctrl.SeverityRule)
+	})
+}
+
+func TestNewSeverityRules_Invalid(t *testing.T) {
+	tests := []struct {
+		rule    SeverityRule
+		wantErr string
+	}{
+		{SeverityRule{Name: "none"}, `none: invalid severity ""`},
+		{SeverityRule{Severity: "urgent"}, `rule 1: invalid severity "urgent"`},
+		{SeverityRule{Name: "m", Severity: "high", Mode: "lower"}, `m: invalid mode "lower"`},
+		{SeverityRule{Name: "w", Severity: "high", When: "data.x <"}, "w: invalid when expression"},
+		{SeverityRule{Name: "b", Severity: "high", When: "plugin"}, "b: invalid when expression: expected bool"},
+	}
+	for _, tt := range tests {
+		_, err := NewSeverityRules([]SeverityRule{tt.rule}, time.Now)
+		assert.ErrorContains(t, err, tt.wantErr)
+	}
+}
@@ -162,6 +162,7 @@ func (s *ExpressionSpecification) IsSatisfiedBy(ctrl entities.Control) (bool, st
 		Severity: ctrl.Severity,
 		Owner:    ctrl.Owner,
 		Tags:     ctrl.Tags,
+		Labels:   ctrl.Labels,
 	}
 
 	output, err := expr.Run(s.program, env)
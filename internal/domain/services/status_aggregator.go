@@ -6,9 +6,9 @@ package services
 import (
 	"context"
 	"fmt"
-	"net"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/expr-lang/expr"
 	"github.com/expr-lang/expr/vm"
@@ -21,6 +21,7 @@ import (
 type StatusAggregator struct {
 	programCache map[string]*vm.Program // Cache of compiled expressions (thread-safe with mutex)
 	cacheMu      sync.RWMutex           // Protects programCache
+	clock        func() time.Time       // Overrides now() and daysUntil() in expressions when set
 }
 
 // NewStatusAggregator creates a new status aggregator service with initialized cache.
@@ -30,12 +31,67 @@ func NewStatusAggregator() *StatusAggregator {
 	}
 }
 
+// NewStatusAggregatorWithClock creates a status aggregator whose expressions
+// read time from clock, so time-relative expectations are reproducible.
+func NewStatusAggregatorWithClock(clock func() time.Time) *StatusAggregator {
+	s := NewStatusAggregator()
+	s.clock = clock
+	return s
+}
+
+// expressionFunctions returns the helper functions bound to the aggregator's clock.
+func (s *StatusAggregator) expressionFunctions() []expr.Option {
+	if s.clock != nil {
+		return ExpressionFunctionsWithClock(s.clock)
+	}
+	return ExpressionFunctions()
+}
+
+// compileOptions returns the options expect expressions are compiled with.
+func (s *StatusAggregator) compileOptions(env map[string]interface{}) []expr.Option {
+	// Security: AST node limit prevents deeply nested expressions (DoS via nested operations)
+	const maxASTNodes = 100
+
+	return append([]expr.Option{
+		expr.Env(env),
+		expr.AsBool(),
+		expr.MaxNodes(maxASTNodes),
+	}, s.expressionFunctions()...)
+}
+
+// expectEnvPool reuses expression environments across observations; runs
+// with thousands of observations otherwise allocate one per evaluation.
+var expectEnvPool = sync.Pool{
+	New: func() any { return make(map[string]interface{}, 4) },
+}
+
+// newExpectEnv returns a pooled environment exposing evidence to expressions.
+func newExpectEnv(evidence *execution.Evidence) map[string]interface{} {
+	env, ok := expectEnvPool.Get().(map[string]interface{})
+	if !ok {
+		env = make(map[string]interface{}, 4)
+	}
+	env["data"] = evidence.Data           // The original data map
+	env["status"] = evidence.Status       // Top-level status
+	env["timestamp"] = evidence.Timestamp // Top-level timestamp
+	env["error"] = evidence.Error         // Top-level error
+	return env
+}
+
+// releaseExpectEnv clears env so it holds no evidence and returns it to the pool.
+func releaseExpectEnv(env map[string]interface{}) {
+	clear(env)
+	expectEnvPool.Put(env)
+}
+
 // AggregateControlStatus determines control status from observation statuses.
 //
 // Business Rule: Failure precedence for compliance reporting
 // - If ANY observation is StatusFail → Control is StatusFail (proven non-compliance)
 // - If ANY observation is StatusError (but no failures) → Control is StatusError (inconclusive)
 // - If ALL observations are StatusPass → Control is StatusPass
+// - If observations are StatusCollected (evidence-only runs) → Control is StatusCollected
+// - Skipped and not-run observations are ignored; if ALL are → Control is StatusSkipped
 //
 // Rationale: If 9 observations FAIL and 1 errors, the control FAILED (not errored).
 // A proven compliance violation is more important than a technical error.
@@ -47,6 +103,7 @@ func (s *StatusAggregator) AggregateControlStatus(observationStatuses []values.S
 
 	hasFailure := false
 	hasError := false
+	hasCollected := false
 
 	for _, status := range observationStatuses {
 		switch status {
@@ -54,7 +111,9 @@ func (s *StatusAggregator) AggregateControlStatus(observationStatuses []values.S
 			hasFailure = true
 		case values.StatusError:
 			hasError = true
-		case values.StatusSkipped:
+		case values.StatusCollected:
+			hasCollected = true
+		case values.StatusSkipped, values.StatusNotRun:
 			// Skipped observations don't affect control status
 			continue
 		case values.StatusPass:
@@ -74,7 +133,7 @@ func (s *StatusAggregator) AggregateControlStatus(observationStatuses []values.S
 	// All observations passed (or were skipped)
 	allSkipped := true
 	for _, status := range observationStatuses {
-		if status != values.StatusSkipped {
+		if !status.IsSkipped() {
 			allSkipped = false
 			break
 		}
@@ -83,13 +142,17 @@ func (s *StatusAggregator) AggregateControlStatus(observationStatuses []values.S
 	if allSkipped {
 		return values.StatusSkipped
 	}
+	if hasCollected {
+		return values.StatusCollected
+	}
 
 	return values.StatusPass
 }
 
 // getOrCompileExpression retrieves a cached program or compiles and caches a new one.
+// Compile options are only built on a cache miss.
 // Thread-safe via RWMutex: multiple readers or single writer.
-func (s *StatusAggregator) getOrCompileExpression(expression string, options []expr.Option) (*vm.Program, error) {
+func (s *StatusAggregator) getOrCompileExpression(expression string, env map[string]interface{}) (*vm.Program, error) {
 	// Try read lock first (optimistic path - expression likely cached)
 	s.cacheMu.RLock()
 	program, found := s.programCache[expression]
@@ -109,7 +172,7 @@ func (s *StatusAggregator) getOrCompileExpression(expression string, options []e
 	}
 
 	// Compile and cache
-	program, err := expr.Compile(expression, options...)
+	program, err := expr.Compile(expression, s.compileOptions(env)...)
 	if err != nil {
 		return nil, err
 	}
@@ -152,34 +215,11 @@ func (s *StatusAggregator) DetermineObservationStatus(
 
 	// Create environment for expression evaluation
 	// The evidence data is available under "data" namespace, plus top-level fields
-	env := map[string]interface{}{
-		"data":      evidence.Data,      // The original data map
-		"status":    evidence.Status,    // Top-level status
-		"timestamp": evidence.Timestamp, // Top-level timestamp
-		"error":     evidence.Error,     // Top-level error
-	}
+	env := newExpectEnv(evidence)
+	defer releaseExpectEnv(env)
 
-	// Security: Complexity limits to prevent DoS attacks
+	// Security: Complexity limit to prevent DoS attacks
 	const maxExpressionLength = 1000 // Character limit for readability
-	const maxASTNodes = 100          // AST node limit prevents deeply nested expressions
-
-	options := []expr.Option{
-		expr.Env(env),
-		expr.AsBool(),
-		expr.MaxNodes(maxASTNodes), // Security: Limit expression complexity (prevents DoS via nested operations)
-
-		expr.Function("isIPv4", func(params ...interface{}) (interface{}, error) {
-			if len(params) != 1 {
-				return nil, fmt.Errorf("isIPv4 expects 1 argument")
-			}
-			ipStr, ok := params[0].(string)
-			if !ok {
-				return nil, fmt.Errorf("isIPv4: argument must be a string")
-			}
-			ip := net.ParseIP(ipStr)
-			return ip != nil && ip.To4() != nil, nil
-		}),
-	}
 
 	// Track all expectation results
 	results := make([]execution.ExpectationResult, 0, len(expects))
@@ -200,7 +240,7 @@ func (s *StatusAggregator) DetermineObservationStatus(
 		}
 
 		// Get or compile expression (uses cache for performance)
-		program, err := s.getOrCompileExpression(expectExpr, options)
+		program, err := s.getOrCompileExpression(expectExpr, env)
 		if err != nil {
 			results = append(results, execution.ExpectationResult{
 				Expression: expectExpr,
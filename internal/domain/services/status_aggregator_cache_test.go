@@ -297,3 +297,35 @@ func TestStatusAggregator_CachePerformance(t *testing.T) {
 	t.Logf("Second call (cached): %v", duration2)
 	t.Logf("Speedup: %.2fx", float64(duration1)/float64(duration2))
 }
+
+// TestStatusAggregator_PooledEnvIsolation verifies that concurrent evaluations
+// sharing pooled environments never see each other's evidence
+func TestStatusAggregator_PooledEnvIsolation(t *testing.T) {
+	t.Parallel()
+
+	aggregator := NewStatusAggregator()
+	expects := []string{"data.value % 2 == 0"}
+
+	var wg sync.WaitGroup
+	for i := range 200 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			evidence := &execution.Evidence{
+				Status: true,
+				Data:   map[string]interface{}{"value": i},
+			}
+			status, _ := aggregator.DetermineObservationStatus(context.Background(), evidence, expects)
+			want := values.StatusFail
+			if i%2 == 0 {
+				want = values.StatusPass
+			}
+			assert.Equal(t, want, status, "value %d", i)
+		}()
+	}
+	wg.Wait()
+
+	env := newExpectEnv(&execution.Evidence{Data: map[string]interface{}{"secret": "x"}})
+	releaseExpectEnv(env)
+	assert.Empty(t, env, "released environments hold no evidence")
+}
@@ -65,6 +65,38 @@ func Test_StatusAggregator_AggregateControlStatus(t *testing.T) {
 			},
 			expected: values.StatusSkipped,
 		},
+		{
+			name: "not run observations don't affect pass",
+			statuses: []values.Status{
+				values.StatusPass,
+				values.StatusNotRun,
+			},
+			expected: values.StatusPass,
+		},
+		{
+			name: "all not run returns skipped",
+			statuses: []values.Status{
+				values.StatusNotRun,
+				values.StatusSkipped,
+			},
+			expected: values.StatusSkipped,
+		},
+		{
+			name: "collected evidence returns collected",
+			statuses: []values.Status{
+				values.StatusCollected,
+				values.StatusSkipped,
+			},
+			expected: values.StatusCollected,
+		},
+		{
+			name: "error while collecting returns error",
+			statuses: []values.Status{
+				values.StatusCollected,
+				values.StatusError,
+			},
+			expected: values.StatusError,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// EvaluateObservation determines observation status from expect expressions
+// and thresholds. Both are reported as expectation results; any failure fails
+// the observation and any evaluation problem errors it.
+func (s *StatusAggregator) EvaluateObservation(
+	ctx context.Context,
+	evidence *execution.Evidence,
+	expects []string,
+	thresholds map[string]entities.Threshold,
+	now time.Time,
+) (values.Status, []execution.ExpectationResult) {
+	if len(thresholds) == 0 {
+		return s.DetermineObservationStatus(ctx, evidence, expects)
+	}
+	if evidence.Error != nil {
+		return values.StatusError, nil
+	}
+
+	status := values.StatusPass
+	var results []execution.ExpectationResult
+	if len(expects) > 0 {
+		status, results = s.DetermineObservationStatus(ctx, evidence, expects)
+	}
+
+	for _, result := range s.EvaluateThresholds(evidence.Data, thresholds, now) {
+		results = append(results, result)
+		if result.Passed {
+			continue
+		}
+		if strings.HasPrefix(result.Message, thresholdErrorPrefix) {
+			status = values.StatusError
+		} else if status != values.StatusError {
+			status = values.StatusFail
+		}
+	}
+	return status, results
+}
+
+// thresholdErrorPrefix marks results that could not be evaluated, as opposed
+// to values outside their bounds.
+const thresholdErrorPrefix = "Threshold error: "
+
+// EvaluateThresholds checks evidence fields against their thresholds, in
+// field order. now is the reference point for time-valued fields.
+func (s *StatusAggregator) EvaluateThresholds(
+	data map[string]interface{},
+	thresholds map[string]entities.Threshold,
+	now time.Time,
+) []execution.ExpectationResult {
+	fields := make([]string, 0, len(thresholds))
+	for field := range thresholds {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	results := make([]execution.ExpectationResult, 0, len(fields))
+	for _, field := range fields {
+		results = append(results, evaluateThreshold(data, field, thresholds[field], now))
+	}
+	return results
+}
+
+// evaluateThreshold checks a single field.
+func evaluateThreshold(data map[string]interface{}, field string, threshold entities.Threshold, now time.Time) execution.ExpectationResult {
+	path := strings.TrimPrefix(field, "data.")
+	result := execution.ExpectationResult{Expression: describeThreshold(path, threshold)}
+	fail := func(format string, args ...interface{}) execution.ExpectationResult {
+		result.Message = thresholdErrorPrefix + fmt.Sprintf(format, args...)
+		return result
+	}
+
+	bounds, err := threshold.Parse()
+	if err != nil {
+		return fail("%s: %v", path, err)
+	}
+	raw, ok := lookupPath(data, path)
+	if !ok {
+		return fail("field data.%s not found in evidence", path)
+	}
+
+	kind := boundKind(bounds)
+	actual, isTime, err := evidenceQuantity(raw, kind, bounds.Unit, now)
+	if err != nil {
+		return fail("data.%s: %v", path, err)
+	}
+
+	subject := fmt.Sprintf("data.%s is %s", path, actual)
+	if isTime {
+		subject = fmt.Sprintf("data.%s is %s from now", path, actual)
+	}
+
+	switch {
+	case bounds.Min != nil && actual.Value() < bounds.Min.Value():
+		result.Message = fmt.Sprintf("%s, below min %s", subject, bounds.Min)
+	case bounds.Max != nil && actual.Value() > bounds.Max.Value():
+		result.Message = fmt.Sprintf("%s, above max %s", subject, bounds.Max)
+	case bounds.Within != nil && actual.Abs().Value() > bounds.Within.Value():
+		result.Message = fmt.Sprintf("%s, not within %s", subject, bounds.Within)
+	default:
+		result.Passed = true
+	}
+	return result
+}
+
+// describeThreshold renders a threshold for expectation results.
+func describeThreshold(path string, t entities.Threshold) string {
+	var parts []string
+	for _, bound := range []struct{ name, value string }{{"min", t.Min}, {"max", t.Max}, {"within", t.Within}} {
+		if bound.value != "" {
+			parts = append(parts, bound.name+" "+bound.value)
+		}
+	}
+	return "data." + path + ": " + strings.Join(parts, ", ")
+}
+
+// boundKind returns the dimension shared by a threshold's bounds.
+func boundKind(bounds entities.ParsedThreshold) values.QuantityKind {
+	for _, q := range []*values.Quantity{bounds.Min, bounds.Max, bounds.Within} {
+		if q != nil {
+			return q.Kind()
+		}
+	}
+	return values.QuantityNumber
+}
+
+// baseUnits are the units plain numeric evidence is read in when a threshold
+// has no unit.
+var baseUnits = map[values.QuantityKind]string{
+	values.QuantityDuration: "s",
+	values.QuantityPercent:  "%",
+	values.QuantityBytes:    "B",
+}
+
+// evidenceQuantity converts an evidence value to a quantity of the given kind.
+// RFC 3339 times become their offset from now when durations are expected.
+func evidenceQuantity(raw interface{}, kind values.QuantityKind, unit string, now time.Time) (values.Quantity, bool, error) {
+	if unit == "" {
+		unit = baseUnits[kind]
+	}
+
+	var q values.Quantity
+	var err error
+	switch v := raw.(type) {
+	case time.Time:
+		if kind != values.QuantityDuration {
+			return q, false, fmt.Errorf("time value needs a duration threshold")
+		}
+		return values.QuantityFromDuration(v.Sub(now)), true, nil
+	case float64:
+		q, err = values.NewQuantity(v, unit)
+	case int:
+		q, err = values.NewQuantity(float64(v), unit)
+	case int64:
+		q, err = values.NewQuantity(float64(v), unit)
+	case string:
+		if kind == values.QuantityDuration {
+			if t, terr := time.Parse(time.RFC3339Nano, v); terr == nil {
+				return values.QuantityFromDuration(t.Sub(now)), true, nil
+			}
+			if d, derr := time.ParseDuration(v); derr == nil {
+				return values.QuantityFromDuration(d), false, nil
+			}
+		}
+		if n, nerr := strconv.ParseFloat(v, 64); nerr == nil {
+			q, err = values.NewQuantity(n, unit)
+		} else {
+			q, err = values.ParseQuantity(v)
+		}
+	default:
+		return q, false, fmt.Errorf("unsupported value %v (%T)", raw, raw)
+	}
+	if err != nil {
+		return q, false, err
+	}
+	if q.Kind() != kind {
+		return q, false, fmt.Errorf("value %v is a %s, threshold is a %s", raw, q.Kind(), kind)
+	}
+	return q, false, nil
+}
+
+// lookupPath resolves a dotted path in nested evidence maps. A top-level key
+// containing dots takes precedence.
+func lookupPath(data map[string]interface{}, path string) (interface{}, bool) {
+	if v, ok := data[path]; ok {
+		return v, true
+	}
+	var current interface{} = data
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
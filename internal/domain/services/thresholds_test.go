@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateThresholds(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	data := map[string]interface{}{
+		"latency_ms":  float64(820),
+		"duration":    "1.2s",
+		"disk_free":   "15%",
+		"cpu_percent": float64(42),
+		"size":        float64(2048),
+		"not_after":   now.Add(12 * 24 * time.Hour).Format(time.RFC3339),
+		"last_backup": now.Add(-2 * 24 * time.Hour).Format(time.RFC3339),
+		"tls":         map[string]interface{}{"days_left": float64(40)},
+		"name":        "web",
+	}
+
+	tests := []struct {
+		name        string
+		field       string
+		threshold   entities.Threshold
+		wantPassed  bool
+		wantMessage string
+	}{
+		{"duration with unit above max", "latency_ms", entities.Threshold{Max: "500ms", Unit: "ms"}, false, "data.latency_ms is 820ms, above max 500ms"},
+		{"duration string", "duration", entities.Threshold{Max: "2s"}, true, ""},
+		{"percent string below min", "disk_free", entities.Threshold{Min: "20%"}, false, "data.disk_free is 15%, below min 20%"},
+		{"plain number as percent", "cpu_percent", entities.Threshold{Max: "80%"}, true, ""},
+		{"bytes", "size", entities.Threshold{Max: "1KiB"}, false, "data.size is 2KiB, above max 1KiB"},
+		{"time too soon", "not_after", entities.Threshold{Min: "30d"}, false, "data.not_after is 12d from now, below min 30d"},
+		{"time within", "last_backup", entities.Threshold{Within: "7d"}, true, ""},
+		{"nested field with data prefix", "data.tls.days_left", entities.Threshold{Min: "30"}, true, ""},
+		{"missing field", "missing", entities.Threshold{Max: "1"}, false, "Threshold error: field data.missing not found in evidence"},
+		{"wrong kind", "disk_free", entities.Threshold{Max: "5GB"}, false, "Threshold error: data.disk_free: value 15% is a percent, threshold is a size"},
+		{"not a number", "name", entities.Threshold{Max: "1"}, false, `Threshold error: data.name: invalid quantity "web"`},
+	}
+
+	aggregator := NewStatusAggregator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			results := aggregator.EvaluateThresholds(data, map[string]entities.Threshold{tt.field: tt.threshold}, now)
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.wantPassed, results[0].Passed)
+			assert.Equal(t, tt.wantMessage, results[0].Message)
+		})
+	}
+}
+
+func TestEvaluateObservation_CombinesExpectAndThresholds(t *testing.T) {
+	t.Parallel()
+
+	aggregator := NewStatusAggregator()
+	now := time.Now()
+	evidence := &execution.Evidence{
+		Status: false, // ignored once expectations are given
+		Data:   map[string]interface{}{"status_code": float64(200), "latency_ms": float64(120)},
+	}
+
+	status, results := aggregator.EvaluateObservation(context.Background(), evidence,
+		[]string{"data.status_code == 200"},
+		map[string]entities.Threshold{"latency_ms": {Max: "500ms", Unit: "ms"}}, now)
+	assert.Equal(t, values.StatusPass, status)
+	require.Len(t, results, 2)
+	assert.Equal(t, "data.latency_ms: max 500ms", results[1].Expression)
+
+	status, _ = aggregator.EvaluateObservation(context.Background(), evidence, nil,
+		map[string]entities.Threshold{"latency_ms": {Max: "100ms", Unit: "ms"}}, now)
+	assert.Equal(t, values.StatusFail, status)
+
+	status, _ = aggregator.EvaluateObservation(context.Background(), evidence, nil,
+		map[string]entities.Threshold{"missing": {Max: "100ms"}}, now)
+	assert.Equal(t, values.StatusError, status)
+
+	// Without thresholds the expect-only behavior is unchanged
+	status, results = aggregator.EvaluateObservation(context.Background(), evidence, nil, nil, now)
+	assert.Equal(t, values.StatusFail, status)
+	assert.Empty(t, results)
+}
@@ -3,9 +3,24 @@ package values
 import (
 	"encoding/json"
 	"fmt"
+	"runtime"
 	"strings"
 )
 
+// ProcessPluginPrefix prefixes the executable name of external process plugins,
+// e.g. plugin "ldap" ships as "reglet-plugin-ldap" instead of "ldap.wasm".
+const ProcessPluginPrefix = "reglet-plugin-"
+
+// ProcessPluginExecutable returns the executable file name of process plugin
+// name on this platform: "reglet-plugin-ldap", or "reglet-plugin-ldap.exe"
+// on Windows.
+func ProcessPluginExecutable(name string) string {
+	if runtime.GOOS == "windows" {
+		return ProcessPluginPrefix + name + ".exe"
+	}
+	return ProcessPluginPrefix + name
+}
+
 // PluginName represents a validated plugin identifier.
 // Enforces non-empty, trimmed plugin names.
 type PluginName struct {
@@ -71,6 +86,11 @@ func (p PluginName) String() string {
 	return p.value
 }
 
+// ProcessExecutable returns the file name of this plugin as an external process plugin.
+func (p PluginName) ProcessExecutable() string {
+	return ProcessPluginExecutable(p.value)
+}
+
 // IsEmpty returns true if this is the zero value
 func (p PluginName) IsEmpty() bool {
 	return p.value == ""
@@ -0,0 +1,158 @@
+package values
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuantityKind is the dimension of a Quantity.
+type QuantityKind int
+
+const (
+	// QuantityNumber is a plain number without a unit.
+	QuantityNumber QuantityKind = iota
+	// QuantityDuration is a duration, stored in seconds.
+	QuantityDuration
+	// QuantityPercent is a percentage, stored in percent points.
+	QuantityPercent
+	// QuantityBytes is a data size, stored in bytes.
+	QuantityBytes
+)
+
+// String returns the kind name.
+func (k QuantityKind) String() string {
+	switch k {
+	case QuantityDuration:
+		return "duration"
+	case QuantityPercent:
+		return "percent"
+	case QuantityBytes:
+		return "size"
+	default:
+		return "number"
+	}
+}
+
+type quantityUnit struct {
+	kind   QuantityKind
+	factor float64 // multiplier to the kind's base unit
+}
+
+// quantityUnits maps unit suffixes to their dimension and scale.
+var quantityUnits = map[string]quantityUnit{
+	"ns": {QuantityDuration, 1e-9},
+	"us": {QuantityDuration, 1e-6},
+	"µs": {QuantityDuration, 1e-6},
+	"ms": {QuantityDuration, 1e-3},
+	"s":  {QuantityDuration, 1},
+	"m":  {QuantityDuration, 60},
+	"h":  {QuantityDuration, 3600},
+	"d":  {QuantityDuration, 86400},
+	"w":  {QuantityDuration, 7 * 86400},
+
+	"%": {QuantityPercent, 1},
+
+	"B":   {QuantityBytes, 1},
+	"KB":  {QuantityBytes, 1e3},
+	"MB":  {QuantityBytes, 1e6},
+	"GB":  {QuantityBytes, 1e9},
+	"TB":  {QuantityBytes, 1e12},
+	"KiB": {QuantityBytes, 1 << 10},
+	"MiB": {QuantityBytes, 1 << 20},
+	"GiB": {QuantityBytes, 1 << 30},
+	"TiB": {QuantityBytes, 1 << 40},
+}
+
+var quantityPattern = regexp.MustCompile(`^([+-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?)\s*(\S*)$`)
+
+// Quantity is a number with an optional unit, such as 500ms, 20%, 30d or 10GiB.
+// Values are normalized to the base unit of their kind so quantities of the
+// same kind compare directly.
+type Quantity struct {
+	value float64
+	kind  QuantityKind
+}
+
+// ParseQuantity parses a number with an optional unit suffix.
+func ParseQuantity(s string) (Quantity, error) {
+	m := quantityPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Quantity{}, fmt.Errorf("invalid quantity %q", s)
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return Quantity{}, fmt.Errorf("invalid quantity %q: %w", s, err)
+	}
+	return NewQuantity(n, m[2])
+}
+
+// NewQuantity creates a quantity from a number in the given unit ("" for a
+// plain number).
+func NewQuantity(n float64, unit string) (Quantity, error) {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return Quantity{}, fmt.Errorf("invalid quantity %v", n)
+	}
+	if unit == "" {
+		return Quantity{value: n, kind: QuantityNumber}, nil
+	}
+	u, ok := quantityUnits[unit]
+	if !ok {
+		return Quantity{}, fmt.Errorf("unknown unit %q", unit)
+	}
+	return Quantity{value: n * u.factor, kind: u.kind}, nil
+}
+
+// QuantityFromDuration creates a duration quantity.
+func QuantityFromDuration(d time.Duration) Quantity {
+	return Quantity{value: d.Seconds(), kind: QuantityDuration}
+}
+
+// UnitKind returns the kind a unit suffix belongs to.
+func UnitKind(unit string) (QuantityKind, bool) {
+	u, ok := quantityUnits[unit]
+	return u.kind, ok
+}
+
+// Kind returns the quantity's dimension.
+func (q Quantity) Kind() QuantityKind {
+	return q.kind
+}
+
+// Value returns the quantity in its base unit (seconds, percent points, bytes).
+func (q Quantity) Value() float64 {
+	return q.value
+}
+
+// Abs returns the quantity with a non-negative value.
+func (q Quantity) Abs() Quantity {
+	q.value = math.Abs(q.value)
+	return q
+}
+
+// String formats the quantity in a readable unit.
+func (q Quantity) String() string {
+	switch q.kind {
+	case QuantityDuration:
+		if days := q.value / 86400; q.value != 0 && days == math.Trunc(days) {
+			return strconv.FormatFloat(days, 'f', -1, 64) + "d"
+		}
+		return time.Duration(q.value * float64(time.Second)).String()
+	case QuantityPercent:
+		return strconv.FormatFloat(q.value, 'f', -1, 64) + "%"
+	case QuantityBytes:
+		// Largest unit that divides the size exactly
+		for _, unit := range []string{"TiB", "TB", "GiB", "GB", "MiB", "MB", "KiB", "KB"} {
+			n := q.value / quantityUnits[unit].factor
+			if q.value != 0 && n == math.Trunc(n) {
+				return strconv.FormatFloat(n, 'f', -1, 64) + unit
+			}
+		}
+		return strconv.FormatFloat(q.value, 'f', -1, 64) + "B"
+	default:
+		return strconv.FormatFloat(q.value, 'f', -1, 64)
+	}
+}
@@ -0,0 +1,60 @@
+package values
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuantity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		kind    QuantityKind
+		value   float64
+		str     string
+		wantErr bool
+	}{
+		{input: "500ms", kind: QuantityDuration, value: 0.5, str: "500ms"},
+		{input: "30d", kind: QuantityDuration, value: 30 * 86400, str: "30d"},
+		{input: "1.5h", kind: QuantityDuration, value: 5400, str: "1h30m0s"},
+		{input: "2w", kind: QuantityDuration, value: 14 * 86400, str: "14d"},
+		{input: "20%", kind: QuantityPercent, value: 20, str: "20%"},
+		{input: "99.9 %", kind: QuantityPercent, value: 99.9, str: "99.9%"},
+		{input: "10GB", kind: QuantityBytes, value: 1e10, str: "10GB"},
+		{input: "1KiB", kind: QuantityBytes, value: 1024, str: "1KiB"},
+		{input: "-3", kind: QuantityNumber, value: -3, str: "-3"},
+		{input: "1e3", kind: QuantityNumber, value: 1000, str: "1000"},
+		{input: "10 parsecs", wantErr: true},
+		{input: "fast", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := ParseQuantity(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.kind, q.Kind())
+			assert.InDelta(t, tt.value, q.Value(), 1e-9)
+			assert.Equal(t, tt.str, q.String())
+		})
+	}
+}
+
+func TestQuantityFromDuration(t *testing.T) {
+	t.Parallel()
+
+	q := QuantityFromDuration(-36 * time.Hour)
+	assert.Equal(t, QuantityDuration, q.Kind())
+	assert.Equal(t, "-36h0m0s", q.String())
+	assert.Equal(t, 36.0*3600, q.Abs().Value())
+}
@@ -16,22 +16,30 @@ const (
 	StatusError Status = "error"
 	// StatusSkipped indicates the check was skipped (dependency failure or filtered)
 	StatusSkipped Status = "skipped"
+	// StatusCollected indicates evidence was collected without evaluating
+	// expectations (evidence-only runs)
+	StatusCollected Status = "collected"
+	// StatusNotRun indicates an observation was not run because its plugin
+	// is not installed (--skip-missing-plugins). Controls never have it.
+	StatusNotRun Status = "not_run"
 )
 
 // Precedence returns the numeric precedence of this status.
 // Higher values indicate higher priority in aggregation.
 // Used by status aggregator to determine control status.
 //
-// Precedence: Fail (3) > Error (2) > Skipped (1) > Pass (0)
+// Precedence: Fail (3) > Error (2) > Skipped (1) > Pass (0). Collected
+// ranks like Pass; evidence-only runs never pass or fail. NotRun ranks like
+// Skipped.
 func (s Status) Precedence() int {
 	switch s {
 	case StatusFail:
 		return 3
 	case StatusError:
 		return 2
-	case StatusSkipped:
+	case StatusSkipped, StatusNotRun:
 		return 1
-	case StatusPass:
+	case StatusPass, StatusCollected:
 		return 0
 	default:
 		return -1
@@ -50,13 +58,13 @@ func (s Status) IsSuccess() bool {
 
 // IsSkipped returns true if this status represents a skip
 func (s Status) IsSkipped() bool {
-	return s == StatusSkipped
+	return s == StatusSkipped || s == StatusNotRun
 }
 
 // Validate returns an error if the status value is invalid
 func (s Status) Validate() error {
 	switch s {
-	case StatusPass, StatusFail, StatusError, StatusSkipped:
+	case StatusPass, StatusFail, StatusError, StatusSkipped, StatusCollected, StatusNotRun:
 		return nil
 	default:
 		return fmt.Errorf("invalid status: %s", s)
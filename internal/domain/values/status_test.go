@@ -15,6 +15,8 @@ func Test_Status_Precedence(t *testing.T) {
 		{StatusError, 2},
 		{StatusSkipped, 1},
 		{StatusPass, 0},
+		{StatusCollected, 0},
+		{StatusNotRun, 1},
 		{Status("unknown"), -1},
 	}
 
@@ -52,7 +54,7 @@ func Test_Status_IsSkipped(t *testing.T) {
 }
 
 func Test_Status_Validate(t *testing.T) {
-	validStatuses := []Status{StatusPass, StatusFail, StatusError, StatusSkipped}
+	validStatuses := []Status{StatusPass, StatusFail, StatusError, StatusSkipped, StatusCollected}
 
 	for _, s := range validStatuses {
 		t.Run(string(s), func(t *testing.T) {
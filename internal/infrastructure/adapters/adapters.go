@@ -4,29 +4,41 @@ package adapters
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/expr-lang/expr"
+	"github.com/goccy/go-yaml"
 	"github.com/reglet-dev/reglet/internal/application/dto"
 	"github.com/reglet-dev/reglet/internal/application/ports"
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
 	"github.com/reglet-dev/reglet/internal/domain/entities"
 	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/reglet-dev/reglet/internal/domain/services"
 	"github.com/reglet-dev/reglet/internal/infrastructure/build"
 	infraconfig "github.com/reglet-dev/reglet/internal/infrastructure/config"
 	"github.com/reglet-dev/reglet/internal/infrastructure/engine"
+	"github.com/reglet-dev/reglet/internal/infrastructure/native"
+	"github.com/reglet-dev/reglet/internal/infrastructure/policy"
+	"github.com/reglet-dev/reglet/internal/infrastructure/process"
+	"github.com/reglet-dev/reglet/internal/infrastructure/proftest"
 	"github.com/reglet-dev/reglet/internal/infrastructure/sensitivedata"
 	"github.com/reglet-dev/reglet/internal/infrastructure/system"
 	"github.com/reglet-dev/reglet/internal/infrastructure/validation"
 	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
 )
 
 // Ensure adapters implement ports at compile time
 var (
 	_ ports.ProfileLoader           = (*ProfileLoaderAdapter)(nil)
+	_ ports.ProfileBundler          = (*ProfileLoaderAdapter)(nil)
 	_ ports.ProfileValidator        = (*ProfileValidatorAdapter)(nil)
 	_ ports.SystemConfigProvider    = (*SystemConfigAdapter)(nil)
 	_ ports.PluginDirectoryResolver = (*PluginDirectoryAdapter)(nil)
@@ -35,6 +47,9 @@ var (
 	_ ports.PluginRuntimeFactory    = (*PluginRuntimeFactoryAdapter)(nil)
 	_ ports.PluginRuntime           = (*PluginRuntimeAdapter)(nil)
 	_ ports.Plugin                  = (*PluginAdapter)(nil)
+	_ ports.ProcessPluginLoader     = (*ProcessPluginLoaderAdapter)(nil)
+	_ ports.Plugin                  = (*ProcessPluginAdapter)(nil)
+	_ ports.PluginInspector         = (*PluginInspectorAdapter)(nil)
 )
 
 // PluginRuntimeFactoryAdapter creates PluginRuntime instances.
@@ -114,6 +129,127 @@ func (p *PluginAdapter) Describe(ctx context.Context) (*ports.PluginInfo, error)
 		Name:         info.Name,
 		Version:      info.Version,
 		Description:  info.Description,
+		Dependencies: info.Dependencies,
+		Capabilities: info.Capabilities,
+	}, nil
+}
+
+// PluginInspectorAdapter inspects plugin binaries in a WASM runtime without
+// capabilities.
+type PluginInspectorAdapter struct {
+	version build.Info
+}
+
+// NewPluginInspectorAdapter creates a new plugin inspector adapter.
+func NewPluginInspectorAdapter() *PluginInspectorAdapter {
+	return &PluginInspectorAdapter{version: build.Get()}
+}
+
+// InspectPlugin loads the plugin in a runtime of its own and runs its
+// checks. Checks that depend on a failed one are skipped.
+func (a *PluginInspectorAdapter) InspectPlugin(ctx context.Context, name string, wasmBytes []byte) (string, []dto.PluginCheck) {
+	checks := make([]dto.PluginCheck, 0, 5)
+	record := func(check string, err error) bool {
+		if err != nil {
+			checks = append(checks, dto.PluginCheck{Name: check, Status: dto.PluginCheckFailed, Message: err.Error()})
+			return false
+		}
+		checks = append(checks, dto.PluginCheck{Name: check, Status: dto.PluginCheckOK})
+		return true
+	}
+	skip := func(check, reason string) {
+		checks = append(checks, dto.PluginCheck{Name: check, Status: dto.PluginCheckSkipped, Message: reason})
+	}
+
+	runtime, err := wasm.NewRuntime(ctx, a.version)
+	if err != nil {
+		record(dto.PluginCheckLoad, err)
+		return "", checks
+	}
+	defer func() { _ = runtime.Close(ctx) }()
+
+	plugin, err := runtime.LoadPlugin(ctx, name, wasmBytes)
+	if !record(dto.PluginCheckLoad, err) {
+		return "", checks
+	}
+
+	info, err := plugin.Describe(ctx)
+	if err == nil && info.Name == "" {
+		err = errors.New("describe() returned no plugin name")
+	}
+	described := record(dto.PluginCheckDescribe, err)
+
+	schema, err := plugin.Schema(ctx)
+	if err == nil && !json.Valid(schema.RawSchema) {
+		err = errors.New("schema() returned invalid JSON")
+	}
+	record(dto.PluginCheckSchema, err)
+
+	version := ""
+	if described {
+		version = info.Version
+		record(dto.PluginCheckABI, wasm.CheckCompatibility(info, a.version.Version))
+	} else {
+		skip(dto.PluginCheckABI, "describe() failed")
+	}
+
+	evidence, err := plugin.SelfTest(ctx)
+	switch {
+	case errors.Is(err, wasm.ErrNoSelfTest):
+		skip(dto.PluginCheckSelfTest, "plugin has no self-test")
+	case err == nil && !evidence.Status:
+		message := "self_test() reported failure"
+		if evidence.Error != nil {
+			message += ": " + evidence.Error.Message
+		}
+		record(dto.PluginCheckSelfTest, errors.New(message))
+	default:
+		record(dto.PluginCheckSelfTest, err)
+	}
+	return version, checks
+}
+
+// ProcessPluginLoaderAdapter loads external process plugins for inspection.
+type ProcessPluginLoaderAdapter struct {
+	trusted []string
+}
+
+// NewProcessPluginLoaderAdapter creates a process plugin loader adapter that
+// only loads the trusted plugins.
+func NewProcessPluginLoaderAdapter(trusted []string) *ProcessPluginLoaderAdapter {
+	return &ProcessPluginLoaderAdapter{trusted: trusted}
+}
+
+// LoadProcessPlugin wraps the executable at path if the plugin is trusted.
+// No capabilities are granted yet, so the plugin runs with an empty
+// environment, no network and no filesystem access beyond its own loading.
+func (a *ProcessPluginLoaderAdapter) LoadProcessPlugin(_ context.Context, name, path string) (ports.Plugin, error) {
+	if err := process.CheckTrusted(name, a.trusted); err != nil {
+		return nil, err
+	}
+	return &ProcessPluginAdapter{plugin: process.NewPlugin(name, path, process.NewSandboxProfile(nil, nil))}, nil
+}
+
+// ProcessPluginAdapter wraps process.Plugin to implement ports.Plugin.
+type ProcessPluginAdapter struct {
+	plugin *process.Plugin
+}
+
+// Describe returns plugin metadata.
+func (p *ProcessPluginAdapter) Describe(ctx context.Context) (*ports.PluginInfo, error) {
+	data, err := p.plugin.Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, err := wasm.ParsePluginInfo(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse process plugin %s metadata: %w", p.plugin.Name(), err)
+	}
+	return &ports.PluginInfo{
+		Name:         info.Name,
+		Version:      info.Version,
+		Description:  info.Description,
+		Dependencies: info.Dependencies,
 		Capabilities: info.Capabilities,
 	}, nil
 }
@@ -132,13 +268,18 @@ func NewProfileLoaderAdapter(resolver ports.SecretResolver) *ProfileLoaderAdapte
 	}
 }
 
-// LoadProfile loads and substitutes variables in a profile.
-func (a *ProfileLoaderAdapter) LoadProfile(path string) (*entities.Profile, error) {
+// LoadProfile loads a profile, selects the environment and substitutes
+// variables.
+func (a *ProfileLoaderAdapter) LoadProfile(path, environment string) (*entities.Profile, error) {
 	profile, err := a.loader.LoadProfile(path)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := profile.SelectEnvironment(environment); err != nil {
+		return nil, err
+	}
+
 	// Apply variable substitution
 	if err := a.substitutor.Substitute(profile); err != nil {
 		return nil, fmt.Errorf("variable substitution failed: %w", err)
@@ -147,6 +288,20 @@ func (a *ProfileLoaderAdapter) LoadProfile(path string) (*entities.Profile, erro
 	return profile, nil
 }
 
+// BundleProfile loads a profile with the profiles it extends merged in and
+// serializes it as YAML, leaving environments and variables unresolved.
+func (a *ProfileLoaderAdapter) BundleProfile(path string) (*entities.Profile, []byte, error) {
+	profile, err := a.loader.LoadProfile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	document, err := yaml.Marshal(profile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to serialize profile: %w", err)
+	}
+	return profile, document, nil
+}
+
 // ProfileValidatorAdapter adapts infrastructure validator to port interface.
 type ProfileValidatorAdapter struct {
 	validator *validation.ProfileValidator
@@ -201,15 +356,30 @@ func (a *SystemConfigAdapter) LoadConfig(_ context.Context, path string) (*syste
 }
 
 // PluginDirectoryAdapter resolves plugin directory paths.
-type PluginDirectoryAdapter struct{}
+type PluginDirectoryAdapter struct {
+	dir string // configured directory, searched for when empty
+}
 
 // NewPluginDirectoryAdapter creates a new plugin directory adapter.
 func NewPluginDirectoryAdapter() *PluginDirectoryAdapter {
 	return &PluginDirectoryAdapter{}
 }
 
+// SetPluginDir makes the adapter resolve to dir instead of searching the
+// working and executable directories.
+func (a *PluginDirectoryAdapter) SetPluginDir(dir string) {
+	a.dir = dir
+}
+
 // ResolvePluginDir determines the plugin directory.
 func (a *PluginDirectoryAdapter) ResolvePluginDir(_ context.Context) (string, error) {
+	if a.dir != "" {
+		if _, err := os.Stat(a.dir); err != nil {
+			return "", fmt.Errorf("plugin directory: %w", err)
+		}
+		return a.dir, nil
+	}
+
 	// Try current working directory first
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -238,12 +408,21 @@ func (a *PluginDirectoryAdapter) ResolvePluginDir(_ context.Context) (string, er
 
 // EngineAdapter wraps infrastructure engine to implement port interface.
 type EngineAdapter struct {
-	engine *engine.Engine
+	engine       *engine.Engine
+	cassette     *hostfuncs.Cassette // saved to cassettePath after execution when recording
+	cassettePath string
 }
 
 // Execute executes the profile using the wrapped engine.
 func (a *EngineAdapter) Execute(ctx context.Context, profile entities.ProfileReader) (*execution.ExecutionResult, error) {
-	return a.engine.Execute(ctx, profile)
+	result, err := a.engine.Execute(ctx, profile)
+	if err != nil || a.cassette == nil || a.cassette.Mode() != hostfuncs.CassetteRecord {
+		return result, err
+	}
+	if err := a.cassette.Save(a.cassettePath); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // Close closes the wrapped engine.
@@ -253,8 +432,10 @@ func (a *EngineAdapter) Close(ctx context.Context) error {
 
 // EngineFactoryAdapter creates execution engines.
 type EngineFactoryAdapter struct {
-	redactor *sensitivedata.Redactor
-	runtime  *infraconfig.RuntimeConfig
+	redactor   *sensitivedata.Redactor
+	pii        *sensitivedata.PIIScanner
+	runtime    *infraconfig.RuntimeConfig
+	repository repositories.ExecutionResultRepository
 }
 
 // NewEngineFactoryAdapter creates a new engine factory adapter.
@@ -265,6 +446,18 @@ func NewEngineFactoryAdapter(redactor *sensitivedata.Redactor, runtime *infracon
 	}
 }
 
+// SetResultRepository makes created engines persist every execution result
+// to repo. Without one, results are not persisted.
+func (a *EngineFactoryAdapter) SetResultRepository(repo repositories.ExecutionResultRepository) {
+	a.repository = repo
+}
+
+// SetPIIScanner makes created engines scan evidence for likely personal
+// data. Without one, evidence is not scanned.
+func (a *EngineFactoryAdapter) SetPIIScanner(scanner *sensitivedata.PIIScanner) {
+	a.pii = scanner
+}
+
 // CreateEngine creates an execution engine with capabilities.
 func (a *EngineFactoryAdapter) CreateEngine(
 	ctx context.Context,
@@ -275,49 +468,197 @@ func (a *EngineFactoryAdapter) CreateEngine(
 	exec dto.ExecutionOptions,
 	_ bool, // skipSchemaValidation - reserved for future schema validation feature
 ) (ports.ExecutionEngine, error) {
-	// Create capability manager that uses the granted capabilities
-	capMgr := &staticCapabilityManager{granted: grantedCaps}
-
 	// Build execution config from filters and execution options
 	cfg := a.buildExecutionConfig(filters, exec)
 
-	// Create engine
-	eng, err := engine.NewEngineWithCapabilities(
-		ctx,
-		build.Get(),
-		capMgr,
-		pluginDir,
-		profile,
-		cfg,
-		a.redactor,
-		nil, // No persistence
-		a.runtime.WasmMemoryLimitMB,
-		&execution.GreedyTruncator{},
-	)
+	var eng *engine.Engine
+	if exec.PluginMode == dto.PluginModeNative {
+		// In-process plugins for development; granted capabilities do not apply
+		eng = engine.NewNativeEngine(build.Get(), native.Default(), cfg, a.redactor, a.repository, &execution.GreedyTruncator{})
+		registry, err := profile.BuildPluginRegistry()
+		if err != nil {
+			return nil, err
+		}
+		eng.SetPluginRegistry(registry)
+	} else {
+		// Create capability manager that uses the granted capabilities
+		capMgr := &staticCapabilityManager{granted: grantedCaps}
+
+		var err error
+		eng, err = engine.NewEngineWithCapabilities(
+			ctx,
+			build.Get(),
+			capMgr,
+			pluginDir,
+			profile,
+			cfg,
+			a.redactor,
+			a.repository,
+			a.runtime.WasmMemoryLimitMB,
+			&execution.GreedyTruncator{},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if mb := a.runtime.ScratchLimitMB; mb < 0 {
+		eng.SetScratchLimit(-1)
+	} else {
+		eng.SetScratchLimit(int64(mb) << 20)
+	}
+	symlinks, err := capabilities.ParseSymlinkPolicy(a.runtime.SymlinkPolicy)
 	if err != nil {
 		return nil, err
 	}
+	eng.SetSymlinkPolicy(symlinks)
+	eng.SetPathHardening(a.runtime.HardenPaths)
+	eng.SetProcessPlugins(a.runtime.ProcessPlugins)
+	if exec.ResultStream != nil {
+		eng.SetResultStream(exec.ResultStream)
+	}
+	if exec.RetainEvidence {
+		eng.SetRetainEvidence(true)
+	}
+	for _, hooks := range exec.Hooks {
+		eng.AddHooks(hooks)
+	}
+	if exec.PolicyBundle != "" {
+		eng.SetResultPolicy(policy.NewRegoPolicy(exec.PolicyBundle))
+	}
+	if exec.SeverityRules != "" {
+		rules, err := infraconfig.LoadSeverityRules(exec.SeverityRules, exec.Clock)
+		if err != nil {
+			return nil, err
+		}
+		eng.SetSeverityRules(rules)
+	}
+	if exec.Clock != nil {
+		eng.SetClock(exec.Clock)
+	}
+	if exec.CollectOnly {
+		eng.SetCollectOnly(true)
+	}
+	if a.pii != nil {
+		eng.SetPIIScanner(a.pii)
+	}
+	if exec.AllowSudo {
+		eng.SetAllowSudo(true)
+	}
+	if exec.HTTPCache {
+		eng.SetHTTPCache(hostfuncs.NewHTTPCache())
+	}
+	if exec.DebugHTTP {
+		eng.SetDebugHTTP(true)
+	}
+	if exec.MaxEgressBytes > 0 {
+		eng.SetMaxEgress(exec.MaxEgressBytes)
+	}
+
+	if len(exec.InjectFaults) > 0 {
+		faults := make([]hostfuncs.Fault, 0, len(exec.InjectFaults))
+		for _, spec := range exec.InjectFaults {
+			fault, err := hostfuncs.ParseFault(spec)
+			if err != nil {
+				return nil, err
+			}
+			faults = append(faults, fault)
+		}
+		eng.SetFaultInjector(hostfuncs.NewFaultInjector(faults, exec.FaultSeed))
+	}
+
+	adapter := &EngineAdapter{engine: eng}
+	if err := a.configureCassette(eng, adapter, exec); err != nil {
+		return nil, err
+	}
+	return adapter, nil
+}
 
+// CreateFixtureEngine creates an engine that answers every observation from
+// fixtures instead of running plugins, for profile unit tests.
+func (a *EngineFactoryAdapter) CreateFixtureEngine(fixtures []dto.ProfileTestFixture, plugins []string) (ports.ExecutionEngine, error) {
+	registry, err := proftest.NewFixtureRegistry(fixtures, plugins)
+	if err != nil {
+		return nil, err
+	}
+	cfg := a.buildExecutionConfig(dto.FilterOptions{}, dto.ExecutionOptions{})
+	eng := engine.NewNativeEngine(build.Get(), registry, cfg, a.redactor, nil, &execution.GreedyTruncator{})
+	return &EngineAdapter{engine: eng}, nil
+}
+
+// CreateEvaluationEngine creates an engine that answers every observation
+// from fixtures holding stored evidence, running at the time it was
+// collected so time-relative checks evaluate as they would have then.
+func (a *EngineFactoryAdapter) CreateEvaluationEngine(fixtures []dto.ProfileTestFixture, plugins []string, at time.Time) (ports.ExecutionEngine, error) {
+	registry, err := proftest.NewFixtureRegistry(fixtures, plugins)
+	if err != nil {
+		return nil, err
+	}
+	cfg := a.buildExecutionConfig(dto.FilterOptions{}, dto.ExecutionOptions{})
+	eng := engine.NewNativeEngine(build.Get(), registry, cfg, a.redactor, nil, &execution.GreedyTruncator{})
+	eng.SetClock(func() time.Time { return at })
 	return &EngineAdapter{engine: eng}, nil
 }
 
+// configureCassette sets up recording or replay of plugin interactions.
+// Replays run at the recorded time unless a clock was given.
+func (a *EngineFactoryAdapter) configureCassette(eng *engine.Engine, adapter *EngineAdapter, exec dto.ExecutionOptions) error {
+	var opts []hostfuncs.CassetteOption
+	if a.redactor != nil {
+		opts = append(opts, hostfuncs.WithCassetteRedactor(a.redactor))
+	}
+
+	switch {
+	case exec.RecordCassette != "" && exec.ReplayCassette != "":
+		return fmt.Errorf("cannot record and replay a cassette in the same run")
+	case exec.RecordCassette != "":
+		adapter.cassette = hostfuncs.NewRecordingCassette(opts...)
+		adapter.cassettePath = exec.RecordCassette
+	case exec.ReplayCassette != "":
+		cassette, err := hostfuncs.LoadCassette(exec.ReplayCassette, opts...)
+		if err != nil {
+			return err
+		}
+		adapter.cassette = cassette
+		if exec.Clock == nil {
+			recordedAt := cassette.RecordedAt()
+			eng.SetClock(func() time.Time { return recordedAt })
+		}
+	default:
+		return nil
+	}
+
+	eng.SetCassette(adapter.cassette)
+	return nil
+}
+
 // buildExecutionConfig constructs an ExecutionConfig from filter and execution options.
 func (a *EngineFactoryAdapter) buildExecutionConfig(filters dto.FilterOptions, exec dto.ExecutionOptions) engine.ExecutionConfig {
 	cfg := engine.DefaultExecutionConfig()
 
 	// Apply runtime config defaults
 	cfg.MaxEvidenceSizeBytes = a.runtime.MaxEvidenceSizeBytes
-	cfg.MaxConcurrentControls = a.runtime.MaxConcurrentControls
-	cfg.MaxConcurrentObservations = a.runtime.MaxConcurrentObservations
+	if a.runtime.MaxConcurrentControls > 0 {
+		cfg.MaxConcurrentControls = a.runtime.MaxConcurrentControls
+	}
+	if a.runtime.MaxConcurrentObservations > 0 {
+		cfg.MaxConcurrentObservations = a.runtime.MaxConcurrentObservations
+	}
+	cfg.PluginWeights = a.runtime.PluginWeights
 
 	// Apply execution options overrides if set
 	cfg.Parallel = exec.Parallel
+	cfg.SkipMissingPlugins = exec.SkipMissingPlugins
+	cfg.NoObservationDedup = exec.NoObservationDedup
 	if exec.MaxConcurrentControls > 0 {
 		cfg.MaxConcurrentControls = exec.MaxConcurrentControls
 	}
 	if exec.MaxConcurrentObservations > 0 {
 		cfg.MaxConcurrentObservations = exec.MaxConcurrentObservations
 	}
+	if exec.MaxEvidenceSizeBytes > 0 {
+		cfg.MaxEvidenceSizeBytes = exec.MaxEvidenceSizeBytes
+	}
 
 	// Apply filters
 	cfg.IncludeTags = filters.IncludeTags
@@ -329,7 +670,7 @@ func (a *EngineFactoryAdapter) buildExecutionConfig(filters dto.FilterOptions, e
 
 	// Compile filter expression if provided
 	if filters.FilterExpression != "" {
-		program, err := expr.Compile(filters.FilterExpression)
+		program, err := expr.Compile(filters.FilterExpression, services.ExpressionFunctions()...)
 		if err != nil {
 			// Log warning but don't fail - validation should have caught this earlier
 			slog.Warn("failed to compile filter expression", "expression", filters.FilterExpression, "error", err)
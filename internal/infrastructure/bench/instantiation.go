@@ -0,0 +1,65 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+)
+
+// Instantiation is the fixed cost of a WASM plugin, paid before it observes
+// anything.
+type Instantiation struct {
+	Compile     time.Duration // compiling the module, once per process
+	Instantiate time.Duration // median cost of a fresh instance, paid per observation
+}
+
+// MeasureInstantiation compiles each plugin found in pluginDir and times
+// samples fresh instances of it, the overhead every observation pays before
+// the plugin runs. Plugins not in pluginDir (embedded or cached) are skipped.
+//
+// Call it before running profiles: compiled modules are cached for the rest
+// of the process, so later compiles are nearly free.
+func MeasureInstantiation(ctx context.Context, pluginDir string, plugins []string, samples int) (map[string]Instantiation, error) {
+	if samples < 1 {
+		samples = 1
+	}
+	runtime, err := wasm.NewRuntime(ctx, build.Get())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runtime: %w", err)
+	}
+	defer func() { _ = runtime.Close(ctx) }()
+
+	results := make(map[string]Instantiation)
+	for _, name := range plugins {
+		//nolint:gosec // G304: plugin path is built from the plugin directory and a known plugin name
+		wasmBytes, err := os.ReadFile(filepath.Join(pluginDir, name, name+".wasm"))
+		if err != nil {
+			continue
+		}
+
+		start := time.Now()
+		plugin, err := runtime.LoadPlugin(ctx, name, wasmBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load plugin %s: %w", name, err)
+		}
+		compile := time.Since(start)
+
+		durations := make([]time.Duration, 0, samples)
+		for range samples {
+			start := time.Now()
+			if err := plugin.Instantiate(ctx); err != nil {
+				return nil, err
+			}
+			durations = append(durations, time.Since(start))
+		}
+		slices.Sort(durations)
+		results[name] = Instantiation{Compile: compile, Instantiate: percentile(durations, 50)}
+	}
+	return results, nil
+}
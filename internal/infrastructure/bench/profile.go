@@ -0,0 +1,171 @@
+// Package bench runs synthetic profiles through the engine to measure
+// throughput, per-plugin latency, instantiation overhead and memory, so
+// performance regressions between releases are measurable.
+package bench
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// Plugins that can appear in a benchmark mix.
+var Plugins = []string{"command", "dns", "file", "http", "tcp"}
+
+// PluginWeight is a plugin's share of the observations in a synthetic profile.
+type PluginWeight struct {
+	Plugin string
+	Weight int
+}
+
+// ParseMix parses a plugin mix such as "file=3,command=1". A plugin without
+// a weight counts once.
+func ParseMix(mix string) ([]PluginWeight, error) {
+	var weights []PluginWeight
+	for _, part := range strings.Split(mix, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, hasWeight := strings.Cut(part, "=")
+		if !slices.Contains(Plugins, name) {
+			return nil, fmt.Errorf("unsupported plugin %q in mix (supported: %s)", name, strings.Join(Plugins, ", "))
+		}
+		weight := 1
+		if hasWeight {
+			var err error
+			weight, err = strconv.Atoi(value)
+			if err != nil || weight < 1 {
+				return nil, fmt.Errorf("invalid weight %q for plugin %s: must be a positive integer", value, name)
+			}
+		}
+		weights = append(weights, PluginWeight{Plugin: name, Weight: weight})
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("plugin mix is empty")
+	}
+	return weights, nil
+}
+
+// Targets are what the synthetic observations check.
+type Targets struct {
+	File       string // path read by file observations
+	Command    string // command run by command observations
+	Hostname   string // name resolved by dns observations
+	HTTPURL    string // URL fetched by http observations
+	TCPAddress string // host:port connected to by tcp observations
+}
+
+// validate reports the first target the mix needs but does not have.
+func (t Targets) validate(mix []PluginWeight) error {
+	for _, w := range mix {
+		switch {
+		case w.Plugin == "file" && t.File == "":
+			return fmt.Errorf("file observations need a target file")
+		case w.Plugin == "command" && t.Command == "":
+			return fmt.Errorf("command observations need a command")
+		case w.Plugin == "dns" && t.Hostname == "":
+			return fmt.Errorf("dns observations need a hostname")
+		case w.Plugin == "http" && t.HTTPURL == "":
+			return fmt.Errorf("http observations need a target URL")
+		case w.Plugin == "tcp" && t.TCPAddress == "":
+			return fmt.Errorf("tcp observations need a target address")
+		}
+		if w.Plugin == "tcp" {
+			if _, _, err := net.SplitHostPort(t.TCPAddress); err != nil {
+				return fmt.Errorf("invalid tcp target %q: %w", t.TCPAddress, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Spec describes a synthetic profile.
+type Spec struct {
+	Mix          []PluginWeight
+	Targets      Targets
+	Controls     int
+	Observations int // per control
+}
+
+// Profile builds the synthetic profile. Plugins are assigned to observations
+// in proportion to their weight, interleaved so every control sees the mix.
+func (s Spec) Profile() (*entities.Profile, error) {
+	if s.Controls < 1 || s.Observations < 1 {
+		return nil, fmt.Errorf("controls and observations per control must be at least 1")
+	}
+	if err := s.Targets.validate(s.Mix); err != nil {
+		return nil, err
+	}
+
+	var cycle []string
+	var plugins []string
+	for _, w := range s.Mix {
+		for range w.Weight {
+			cycle = append(cycle, w.Plugin)
+		}
+		if !slices.Contains(plugins, w.Plugin) {
+			plugins = append(plugins, w.Plugin)
+		}
+	}
+
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{
+			Name:        "reglet-bench",
+			Version:     "1.0.0",
+			Description: "Synthetic benchmark profile",
+		},
+		Plugins: plugins,
+	}
+	n := 0
+	for c := range s.Controls {
+		ctrl := entities.Control{
+			ID:   fmt.Sprintf("bench-%04d", c+1),
+			Name: fmt.Sprintf("Benchmark control %d", c+1),
+		}
+		for range s.Observations {
+			ctrl.ObservationDefinitions = append(ctrl.ObservationDefinitions, s.observation(cycle[n%len(cycle)]))
+			n++
+		}
+		profile.Controls.Items = append(profile.Controls.Items, ctrl)
+	}
+	return profile, nil
+}
+
+// YAML renders the synthetic profile as a profile file.
+func (s Spec) YAML() ([]byte, error) {
+	profile, err := s.Profile()
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(profile)
+}
+
+// observation returns a cheap, passing observation for a plugin.
+func (s Spec) observation(plugin string) entities.ObservationDefinition {
+	obs := entities.ObservationDefinition{Plugin: plugin}
+	switch plugin {
+	case "file":
+		obs.Config = map[string]interface{}{"path": s.Targets.File}
+		obs.Expect = []string{"data.exists"}
+	case "command":
+		obs.Config = map[string]interface{}{"run": s.Targets.Command}
+		obs.Expect = []string{"data.exit_code == 0"}
+	case "dns":
+		obs.Config = map[string]interface{}{"hostname": s.Targets.Hostname, "record_type": "A"}
+		obs.Expect = []string{"data.record_count > 0"}
+	case "http":
+		obs.Config = map[string]interface{}{"url": s.Targets.HTTPURL, "method": "GET"}
+		obs.Expect = []string{"data.status_code < 500"}
+	case "tcp":
+		host, port, _ := net.SplitHostPort(s.Targets.TCPAddress)
+		obs.Config = map[string]interface{}{"host": host, "port": port, "timeout_ms": 5000}
+		obs.Expect = []string{"data.connected == true"}
+	}
+	return obs
+}
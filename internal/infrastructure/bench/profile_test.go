@@ -0,0 +1,73 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMix(t *testing.T) {
+	t.Parallel()
+
+	mix, err := ParseMix("file=3, command")
+	require.NoError(t, err)
+	assert.Equal(t, []PluginWeight{{Plugin: "file", Weight: 3}, {Plugin: "command", Weight: 1}}, mix)
+
+	for _, invalid := range []string{"", "ldap", "file=0", "file=x"} {
+		_, err := ParseMix(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSpec_Profile(t *testing.T) {
+	t.Parallel()
+
+	spec := Spec{
+		Mix:          []PluginWeight{{Plugin: "file", Weight: 2}, {Plugin: "tcp", Weight: 1}},
+		Targets:      Targets{File: "/etc/hostname", TCPAddress: "[::1]:8080"},
+		Controls:     4,
+		Observations: 3,
+	}
+
+	data, err := spec.YAML()
+	require.NoError(t, err)
+
+	var profile entities.Profile
+	require.NoError(t, yaml.Unmarshal(data, &profile))
+	assert.Equal(t, entities.PluginDeclarations{"file", "tcp"}, profile.Plugins)
+	require.Len(t, profile.Controls.Items, 4)
+
+	counts := map[string]int{}
+	for _, ctrl := range profile.Controls.Items {
+		require.Len(t, ctrl.ObservationDefinitions, 3)
+		for _, obs := range ctrl.ObservationDefinitions {
+			counts[obs.Plugin]++
+			assert.NotEmpty(t, obs.Expect)
+		}
+	}
+	assert.Equal(t, map[string]int{"file": 8, "tcp": 4}, counts, "observations follow the mix weights")
+
+	tcp := profile.Controls.Items[0].ObservationDefinitions[2]
+	assert.Equal(t, "::1", tcp.Config["host"])
+	assert.Equal(t, "8080", tcp.Config["port"])
+}
+
+func TestSpec_ProfileRequiresTargets(t *testing.T) {
+	t.Parallel()
+
+	spec := Spec{
+		Mix:          []PluginWeight{{Plugin: "http", Weight: 1}},
+		Controls:     1,
+		Observations: 1,
+	}
+	_, err := spec.Profile()
+	assert.ErrorContains(t, err, "target URL")
+
+	spec.Mix = []PluginWeight{{Plugin: "tcp", Weight: 1}}
+	spec.Targets.TCPAddress = "localhost"
+	_, err = spec.Profile()
+	assert.ErrorContains(t, err, "invalid tcp target")
+}
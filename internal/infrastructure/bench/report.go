@@ -0,0 +1,106 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Report is the outcome of a benchmark. Durations are in milliseconds so
+// reports from different releases can be compared directly.
+type Report struct {
+	Plugins      []PluginReport `json:"plugins"`
+	Memory       MemoryReport   `json:"memory"`
+	Controls     int            `json:"controls"`
+	Observations int            `json:"observations"` // per iteration
+	Iterations   int            `json:"iterations"`
+	ElapsedMs    float64        `json:"elapsed_ms"`
+	Throughput   float64        `json:"throughput_obs_per_sec"`
+}
+
+// PluginReport is the latency of one plugin's observations across all
+// measured iterations.
+type PluginReport struct {
+	Plugin        string  `json:"plugin"`
+	Observations  int     `json:"observations"`
+	Errors        int     `json:"errors"`
+	P50Ms         float64 `json:"p50_ms"`
+	P95Ms         float64 `json:"p95_ms"`
+	MeanMs        float64 `json:"mean_ms"`
+	CompileMs     float64 `json:"compile_ms,omitempty"`
+	InstantiateMs float64 `json:"instantiate_ms,omitempty"`
+}
+
+// MemoryReport is the Go heap usage of the measured iterations.
+type MemoryReport struct {
+	AllocPerRunBytes uint64  `json:"alloc_per_run_bytes"`
+	PeakHeapBytes    uint64  `json:"peak_heap_bytes"`
+	GCPerRun         float64 `json:"gc_per_run"`
+}
+
+// SetInstantiation adds measured instantiation costs to the plugin reports.
+func (r *Report) SetInstantiation(costs map[string]Instantiation) {
+	for i := range r.Plugins {
+		if cost, ok := costs[r.Plugins[i].Plugin]; ok {
+			r.Plugins[i].CompileMs = milliseconds(cost.Compile)
+			r.Plugins[i].InstantiateMs = milliseconds(cost.Instantiate)
+		}
+	}
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// WriteTable writes the report as a human-readable table.
+//
+//nolint:errcheck // Table formatting errors are non-critical (best-effort terminal output)
+func (r *Report) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "Controls: %d  Observations: %d  Iterations: %d\n", r.Controls, r.Observations, r.Iterations)
+	fmt.Fprintf(w, "Elapsed: %.0fms  Throughput: %.1f obs/s\n\n", r.ElapsedMs, r.Throughput)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "PLUGIN\tOBSERVATIONS\tERRORS\tP50\tP95\tMEAN\tCOMPILE\tINSTANTIATE")
+	for _, p := range r.Plugins {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			p.Plugin, p.Observations, p.Errors,
+			formatMs(p.P50Ms), formatMs(p.P95Ms), formatMs(p.MeanMs),
+			formatMs(p.CompileMs), formatMs(p.InstantiateMs))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nMemory: %s allocated per run, %s peak heap, %.1f GC cycles per run\n",
+		formatBytes(r.Memory.AllocPerRunBytes), formatBytes(r.Memory.PeakHeapBytes), r.Memory.GCPerRun)
+	return nil
+}
+
+// formatMs renders milliseconds, or "-" when not measured.
+func formatMs(ms float64) string {
+	if ms == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2fms", ms)
+}
+
+// formatBytes renders a byte count in binary units.
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
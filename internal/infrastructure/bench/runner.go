@@ -0,0 +1,155 @@
+package bench
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// memorySampleInterval is how often heap usage is sampled during a run.
+const memorySampleInterval = 20 * time.Millisecond
+
+// RunFunc executes a profile once and returns its result.
+type RunFunc func(ctx context.Context, profilePath string) (*execution.ExecutionResult, error)
+
+// Options controls how often the profile runs.
+type Options struct {
+	Iterations int // measured runs
+	Warmup     int // unmeasured runs first, e.g. to fill the compilation cache
+}
+
+// Run executes the profile Warmup+Iterations times and reports on the
+// measured iterations.
+func Run(ctx context.Context, profilePath string, run RunFunc, opts Options) (*Report, error) {
+	if opts.Iterations < 1 {
+		return nil, fmt.Errorf("iterations must be at least 1")
+	}
+
+	for i := range opts.Warmup {
+		if _, err := run(ctx, profilePath); err != nil {
+			return nil, fmt.Errorf("warmup run %d: %w", i+1, err)
+		}
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	sampler := startHeapSampler()
+
+	latencies := make(map[string][]time.Duration)
+	errors := make(map[string]int)
+	report := &Report{Iterations: opts.Iterations}
+	start := time.Now()
+	for i := range opts.Iterations {
+		result, err := run(ctx, profilePath)
+		if err != nil {
+			sampler.stop()
+			return nil, fmt.Errorf("run %d: %w", i+1, err)
+		}
+		report.Controls = len(result.Controls)
+		report.Observations = 0
+		for _, ctrl := range result.Controls {
+			for _, obs := range ctrl.ObservationResults {
+				report.Observations++
+				latencies[obs.Plugin] = append(latencies[obs.Plugin], obs.Duration)
+				if obs.Status == values.StatusError {
+					errors[obs.Plugin]++
+				}
+			}
+		}
+	}
+	elapsed := time.Since(start)
+	peak := sampler.stop()
+	runtime.ReadMemStats(&after)
+
+	report.ElapsedMs = milliseconds(elapsed)
+	report.Throughput = float64(report.Observations*opts.Iterations) / elapsed.Seconds()
+	report.Memory = MemoryReport{
+		AllocPerRunBytes: (after.TotalAlloc - before.TotalAlloc) / uint64(opts.Iterations), //nolint:gosec // G115: iterations is positive
+		PeakHeapBytes:    max(peak, after.HeapInuse),
+		GCPerRun:         float64(after.NumGC-before.NumGC) / float64(opts.Iterations),
+	}
+	for plugin, durations := range latencies {
+		slices.Sort(durations)
+		report.Plugins = append(report.Plugins, PluginReport{
+			Plugin:       plugin,
+			Observations: len(durations),
+			Errors:       errors[plugin],
+			P50Ms:        milliseconds(percentile(durations, 50)),
+			P95Ms:        milliseconds(percentile(durations, 95)),
+			MeanMs:       milliseconds(mean(durations)),
+		})
+	}
+	slices.SortFunc(report.Plugins, func(a, b PluginReport) int {
+		return cmp.Compare(a.Plugin, b.Plugin)
+	})
+	return report, nil
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	return sorted[max(rank, 1)-1]
+}
+
+// mean returns the average of durations.
+func mean(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations))
+}
+
+// milliseconds converts a duration to fractional milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// heapSampler records the peak in-use heap while a benchmark runs.
+type heapSampler struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+	peak uint64
+}
+
+func startHeapSampler() *heapSampler {
+	s := &heapSampler{done: make(chan struct{})}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(memorySampleInterval)
+		defer ticker.Stop()
+		var stats runtime.MemStats
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				runtime.ReadMemStats(&stats)
+				s.peak = max(s.peak, stats.HeapInuse)
+			}
+		}
+	}()
+	return s
+}
+
+// stop ends sampling and returns the peak in-use heap in bytes.
+func (s *heapSampler) stop() uint64 {
+	close(s.done)
+	s.wg.Wait()
+	return s.peak
+}
@@ -0,0 +1,112 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRun returns a result with one file and one http observation, timing
+// file at n ms on the nth call and failing the http one.
+func fakeRun(calls *int) RunFunc {
+	return func(_ context.Context, _ string) (*execution.ExecutionResult, error) {
+		*calls++
+		return &execution.ExecutionResult{
+			Controls: []execution.ControlResult{{
+				ID: "bench-0001",
+				ObservationResults: []execution.ObservationResult{
+					{Plugin: "file", Status: values.StatusPass, Duration: time.Duration(*calls) * time.Millisecond},
+					{Plugin: "http", Status: values.StatusError, Duration: time.Millisecond},
+				},
+			}},
+		}, nil
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	report, err := Run(context.Background(), "bench.yaml", fakeRun(&calls), Options{Iterations: 20, Warmup: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, 22, calls)
+	assert.Equal(t, 1, report.Controls)
+	assert.Equal(t, 2, report.Observations)
+	assert.Equal(t, 20, report.Iterations)
+	assert.Positive(t, report.Throughput)
+
+	require.Len(t, report.Plugins, 2)
+	file, http := report.Plugins[0], report.Plugins[1]
+	assert.Equal(t, "file", file.Plugin)
+	assert.Equal(t, 20, file.Observations)
+	assert.Zero(t, file.Errors)
+	// Measured calls 3..22 take 3..22ms
+	assert.InDelta(t, 12.0, file.P50Ms, 0.001)
+	assert.InDelta(t, 21.0, file.P95Ms, 0.001)
+	assert.InDelta(t, 12.5, file.MeanMs, 0.001)
+	assert.Equal(t, 20, http.Errors)
+
+	report.SetInstantiation(map[string]Instantiation{"file": {Compile: time.Second, Instantiate: 2 * time.Millisecond}})
+	assert.InDelta(t, 1000.0, report.Plugins[0].CompileMs, 0.001)
+	assert.InDelta(t, 2.0, report.Plugins[0].InstantiateMs, 0.001)
+	assert.Zero(t, report.Plugins[1].InstantiateMs)
+}
+
+func TestRun_Error(t *testing.T) {
+	t.Parallel()
+
+	failing := func(context.Context, string) (*execution.ExecutionResult, error) {
+		return nil, errors.New("boom")
+	}
+	_, err := Run(context.Background(), "bench.yaml", failing, Options{Iterations: 1})
+	assert.ErrorContains(t, err, "run 1: boom")
+
+	_, err = Run(context.Background(), "bench.yaml", failing, Options{})
+	assert.ErrorContains(t, err, "iterations")
+}
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	sorted := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, time.Duration(5), percentile(sorted, 50))
+	assert.Equal(t, time.Duration(10), percentile(sorted, 95))
+	assert.Equal(t, time.Duration(1), percentile(sorted, 0))
+	assert.Equal(t, time.Duration(0), percentile(nil, 50))
+}
+
+func TestReport_Write(t *testing.T) {
+	t.Parallel()
+
+	report := &Report{
+		Plugins:      []PluginReport{{Plugin: "file", Observations: 10, P50Ms: 1.5, P95Ms: 3, MeanMs: 2}},
+		Memory:       MemoryReport{AllocPerRunBytes: 3 << 20, PeakHeapBytes: 1536},
+		Controls:     5,
+		Observations: 10,
+		Iterations:   1,
+		ElapsedMs:    20,
+		Throughput:   500,
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, report.WriteTable(&table))
+	assert.Contains(t, table.String(), "Throughput: 500.0 obs/s")
+	assert.Contains(t, table.String(), "1.50ms")
+	assert.Contains(t, table.String(), "3.0MiB allocated per run, 1.5KiB peak heap")
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteJSON(&out))
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.InDelta(t, 500.0, decoded["throughput_obs_per_sec"], 0.001)
+	assert.NotContains(t, out.String(), "instantiate_ms", "unmeasured costs are omitted")
+}
@@ -1,7 +1,10 @@
 // Package build provides build version information for Reglet.
 package build
 
-import "runtime"
+import (
+	"runtime"
+	"runtime/debug"
+)
 
 var (
 	// Version is the semantic version (set by build flags)
@@ -21,15 +24,44 @@ type Info struct {
 	Platform  string
 }
 
-// Get returns the version information
+// Get returns the version information. Without build flags, the commit and
+// build date come from the VCS information the Go toolchain stamps into the
+// binary, so plain "go build" binaries are traceable too.
 func Get() Info {
-	return Info{
+	info := Info{
 		Version:   Version,
 		Commit:    Commit,
 		BuildDate: BuildDate,
 		GoVersion: runtime.Version(),
 		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
 	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info = withVCS(info, bi.Settings)
+	}
+	return info
+}
+
+// withVCS fills the commit and build date left unset by build flags from VCS
+// build settings. The build date is the commit time, which keeps it
+// reproducible; a commit with uncommitted changes is marked "-dirty".
+func withVCS(info Info, settings []debug.BuildSetting) Info {
+	vcs := make(map[string]string, len(settings))
+	for _, s := range settings {
+		vcs[s.Key] = s.Value
+	}
+	if info.Commit == "unknown" && vcs["vcs.revision"] != "" {
+		info.Commit = vcs["vcs.revision"]
+		if len(info.Commit) > 12 {
+			info.Commit = info.Commit[:12]
+		}
+		if vcs["vcs.modified"] == "true" {
+			info.Commit += "-dirty"
+		}
+	}
+	if info.BuildDate == "unknown" && vcs["vcs.time"] != "" {
+		info.BuildDate = vcs["vcs.time"]
+	}
+	return info
 }
 
 // String returns a formatted version string
@@ -0,0 +1,29 @@
+package build
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithVCS(t *testing.T) {
+	t.Parallel()
+
+	settings := []debug.BuildSetting{
+		{Key: "vcs.revision", Value: "4f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a39"},
+		{Key: "vcs.time", Value: "2026-03-01T12:00:00Z"},
+		{Key: "vcs.modified", Value: "true"},
+	}
+
+	info := withVCS(Info{Version: "dev", Commit: "unknown", BuildDate: "unknown"}, settings)
+	assert.Equal(t, "4f2a9c1e8b7d-dirty", info.Commit)
+	assert.Equal(t, "2026-03-01T12:00:00Z", info.BuildDate)
+
+	info = withVCS(Info{Version: "1.0.0", Commit: "abc1234", BuildDate: "2026-02-01T00:00:00Z"}, settings)
+	assert.Equal(t, "abc1234", info.Commit, "build flags take precedence")
+	assert.Equal(t, "2026-02-01T00:00:00Z", info.BuildDate)
+
+	info = withVCS(Info{Commit: "unknown", BuildDate: "unknown"}, nil)
+	assert.Equal(t, "unknown", info.Commit)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/goccy/go-yaml"
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/infrastructure/system"
 )
 
 // FileStore provides file-based persistence for capability grants.
@@ -67,7 +68,8 @@ func (s *FileStore) Load() (capabilities.Grant, error) {
 	return caps, nil
 }
 
-// Save saves capability grants to ~/.reglet/config.yaml.
+// Save saves capability grants to ~/.reglet/config.yaml. The other settings
+// and comments of an existing config file are kept.
 func (s *FileStore) Save(grants capabilities.Grant) error {
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(s.configPath)
@@ -87,6 +89,13 @@ func (s *FileStore) Save(grants capabilities.Grant) error {
 		cfgCaps[i].Pattern = capability.Pattern
 	}
 
+	if info, err := os.Stat(s.configPath); err == nil && info.Size() > 0 {
+		if err := system.SetConfigValue(s.configPath, "capabilities", cfgCaps); err != nil {
+			return fmt.Errorf("failed to save capabilities: %w", err)
+		}
+		return nil
+	}
+
 	cfg := configFile{Capabilities: cfgCaps}
 
 	// Marshal to YAML
@@ -55,6 +55,28 @@ func TestFileStore_LoadAndSave(t *testing.T) {
 	assert.True(t, loadedGrants.Contains(grant2))
 }
 
+func TestFileStore_Save_KeepsOtherSettings(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("# host config\nsecurity:\n  level: strict\ncapabilities:\n  - kind: env\n    pattern: HOME\n"), 0o600))
+
+	store := NewFileStore(configPath)
+	grants := capabilities.NewGrant()
+	grants.Add(capabilities.Capability{Kind: "fs", Pattern: "read:/etc/hosts"})
+	require.NoError(t, store.Save(grants))
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# host config")
+	assert.Contains(t, string(content), "level: strict")
+	assert.NotContains(t, string(content), "HOME")
+
+	loaded, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, grants, loaded)
+}
+
 func TestFileStore_Load_InvalidYAML(t *testing.T) {
 	t.Parallel()
 
@@ -164,6 +164,9 @@ func (p *TerminalPrompter) describeCapability(capability capabilities.Capability
 		}
 		return fmt.Sprintf("Execute commands: %s", capability.Pattern)
 	case "env":
+		if key, ok := strings.CutPrefix(capability.Pattern, capabilities.ObservationEnvPrefix); ok {
+			return fmt.Sprintf("Set environment variable from the profile: %s (host value not exposed)", key)
+		}
 		return fmt.Sprintf("Read environment variables: %s", capability.Pattern)
 	default:
 		return fmt.Sprintf("%s: %s", capability.Kind, capability.Pattern)
@@ -33,6 +33,7 @@ func TestTerminalPrompter_describeCapability(t *testing.T) {
 		{capabilities.Capability{Kind: "fs", Pattern: "read:/var/log"}, "Read files: /var/log"},
 		{capabilities.Capability{Kind: "exec", Pattern: "/bin/sh"}, "Shell execution (executes shell commands)"},
 		{capabilities.Capability{Kind: "env", Pattern: "AWS_ACCESS_KEY"}, "Read environment variables: AWS_ACCESS_KEY"},
+		{capabilities.ObservationEnvCapability("LANG"), "Set environment variable from the profile: LANG (host value not exposed)"},
 		{capabilities.Capability{Kind: "unknown", Pattern: "foo"}, "unknown: foo"},
 	}
 
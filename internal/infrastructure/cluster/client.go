@@ -0,0 +1,108 @@
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client runs partial checks on agents. It keeps one connection per agent
+// address until closed.
+type Client struct {
+	creds credentials.TransportCredentials
+	conns map[string]*grpc.ClientConn
+	mu    sync.Mutex
+}
+
+// Ensure interface compliance
+var _ ports.AgentClient = (*Client)(nil)
+
+// NewClient creates a client connecting over TLS when tlsConfig is set.
+func NewClient(tlsConfig *tls.Config) *Client {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	return &Client{creds: creds, conns: make(map[string]*grpc.ClientConn)}
+}
+
+// ExecutePartial runs req on agent and returns the agent's result.
+func (c *Client) ExecutePartial(ctx context.Context, agent dto.AgentNode, req dto.PartialCheckRequest) (*execution.ExecutionResult, error) {
+	conn, err := c.conn(agent.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp executeResponse
+	err = conn.Invoke(ctx, executeMethod, &executeRequest{
+		Profile:                   req.Profile,
+		Environment:               req.Environment,
+		RequestID:                 req.RequestID,
+		ControlIDs:                req.ControlIDs,
+		Parallel:                  req.Parallel,
+		MaxConcurrentControls:     req.MaxConcurrentControls,
+		MaxConcurrentObservations: req.MaxConcurrentObservations,
+		MaxEvidenceSizeBytes:      req.MaxEvidenceSizeBytes,
+	}, &resp, grpc.CallContentSubtype(codecName), grpc.MaxCallRecvMsgSize(maxMessageSize), grpc.MaxCallSendMsgSize(maxMessageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := execution.DecodeExecutionResult(resp.Result)
+	if err != nil {
+		return nil, fmt.Errorf("invalid result from agent %s: %w", agent.Name, err)
+	}
+	return result, nil
+}
+
+// Facts returns the facts agent reports, probing its PATH for commands.
+func (c *Client) Facts(ctx context.Context, agent dto.AgentNode, commands []string) ([]string, error) {
+	conn, err := c.conn(agent.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp factsResponse
+	if err := conn.Invoke(ctx, factsMethod, &factsRequest{Commands: commands}, &resp, grpc.CallContentSubtype(codecName)); err != nil {
+		return nil, err
+	}
+	return resp.Facts, nil
+}
+
+func (c *Client) conn(address string) (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if conn, ok := c.conns[address]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(c.creds))
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent address %q: %w", address, err)
+	}
+	c.conns[address] = conn
+	return conn, nil
+}
+
+// Close closes the connections to agents.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var firstErr error
+	for address, conn := range c.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(c.conns, address)
+	}
+	return firstErr
+}
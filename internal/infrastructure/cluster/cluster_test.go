@@ -0,0 +1,121 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// startAgent serves execute on a local port and returns the agent's address.
+func startAgent(t *testing.T, execute ExecuteFunc) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := NewAgentServer("web-1", execute, nil).NewGRPCServer(nil)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func TestClient_ExecutePartial(t *testing.T) {
+	t.Parallel()
+
+	var received dto.PartialCheckRequest
+	address := startAgent(t, func(_ context.Context, req dto.PartialCheckRequest) (*execution.ExecutionResult, error) {
+		received = req
+		result := execution.NewExecutionResult("fleet", "1.0.0")
+		result.AddControlResult(execution.ControlResult{ID: "nginx", Status: values.StatusPass})
+		result.Finalize()
+		return result, nil
+	})
+
+	client := NewClient(nil)
+	defer client.Close()
+	req := dto.PartialCheckRequest{
+		Profile:              []byte("profile:\n  name: fleet\n"),
+		Environment:          "prod",
+		RequestID:            "req-1",
+		ControlIDs:           []string{"nginx"},
+		Parallel:             true,
+		MaxEvidenceSizeBytes: 1024,
+	}
+	result, err := client.ExecutePartial(context.Background(), dto.AgentNode{Name: "web-1", Address: address}, req)
+	require.NoError(t, err)
+
+	assert.Equal(t, req, received)
+	assert.Equal(t, "fleet", result.ProfileName)
+	require.Len(t, result.Controls, 1)
+	assert.Equal(t, values.StatusPass, result.Controls[0].Status)
+	assert.Equal(t, 1, result.Summary.PassedControls)
+}
+
+func TestClient_ExecutePartial_Errors(t *testing.T) {
+	t.Parallel()
+
+	address := startAgent(t, func(context.Context, dto.PartialCheckRequest) (*execution.ExecutionResult, error) {
+		return nil, errors.New("capability grant failed")
+	})
+	client := NewClient(nil)
+	defer client.Close()
+	agent := dto.AgentNode{Name: "web-1", Address: address}
+
+	_, err := client.ExecutePartial(context.Background(), agent, dto.PartialCheckRequest{Profile: []byte("x"), ControlIDs: []string{"a"}})
+	assert.ErrorContains(t, err, "capability grant failed")
+
+	_, err = client.ExecutePartial(context.Background(), agent, dto.PartialCheckRequest{ControlIDs: []string{"a"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestClient_Facts(t *testing.T) {
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "docker"), []byte("#!/bin/sh\n"), 0o755)) //nolint:gosec // G306: must be executable
+	t.Setenv("PATH", bin)
+
+	agent := NewAgentServer("build-1", nil, nil)
+	agent.SetFacts([]string{"zone:eu"})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := agent.NewGRPCServer(nil)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	client := NewClient(nil)
+	defer client.Close()
+	facts, err := client.Facts(context.Background(), dto.AgentNode{Name: "build-1", Address: lis.Addr().String()},
+		[]string{"docker", "kubectl", bin + "/docker"})
+	require.NoError(t, err)
+
+	assert.Contains(t, facts, runtime.GOOS)
+	assert.Contains(t, facts, "arch:"+runtime.GOARCH)
+	assert.Contains(t, facts, "zone:eu")
+	assert.Contains(t, facts, "has:docker")
+	assert.NotContains(t, facts, "has:kubectl")
+	assert.NotContains(t, facts, "has:"+bin+"/docker", "paths are not probed")
+}
+
+func TestTLSFiles(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := TLSFiles{}.ServerConfig()
+	require.NoError(t, err)
+	assert.Nil(t, cfg, "TLS is off without files")
+
+	_, err = TLSFiles{CAFile: "ca.pem"}.ServerConfig()
+	assert.ErrorContains(t, err, "requires a certificate and key")
+
+	_, err = TLSFiles{CAFile: "/nonexistent/ca.pem"}.ClientConfig()
+	assert.ErrorContains(t, err, "failed to read CA")
+}
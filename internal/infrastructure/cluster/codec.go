@@ -0,0 +1,36 @@
+// Package cluster connects coordinators and agents of distributed runs over
+// gRPC.
+//
+// The agent service has a single unary method. Its messages are JSON
+// documents, so the service is registered by hand instead of from generated
+// protobuf code; requests select the JSON codec with the "json" content
+// subtype.
+package cluster
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// codecName is the content subtype of agent calls.
+const codecName = "json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec marshals gRPC messages as JSON.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
@@ -0,0 +1,29 @@
+package cluster
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// hostFacts returns the facts of this host: its operating system, both as
+// "linux" and "os:linux", its architecture as "arch:amd64", the declared
+// facts, and "has:<command>" for each of commands found on the PATH.
+//
+// Commands are looked up by name only; paths are ignored so coordinators
+// cannot probe the agent's file system.
+func hostFacts(declared, commands []string) []string {
+	facts := []string{runtime.GOOS, "os:" + runtime.GOOS, "arch:" + runtime.GOARCH}
+	facts = append(facts, declared...)
+	for _, command := range commands {
+		if command == "" || strings.ContainsAny(command, `/\`) {
+			continue
+		}
+		if _, err := exec.LookPath(command); err == nil {
+			facts = append(facts, entities.RunsOnCommandPrefix+command)
+		}
+	}
+	return facts
+}
@@ -0,0 +1,100 @@
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// ExecuteFunc runs part of a profile on the agent.
+type ExecuteFunc func(ctx context.Context, req dto.PartialCheckRequest) (*execution.ExecutionResult, error)
+
+// AgentServer answers partial checks of coordinators.
+type AgentServer struct {
+	run      ExecuteFunc
+	logger   *slog.Logger
+	name     string
+	declared []string
+}
+
+// Ensure interface compliance
+var _ agentService = (*AgentServer)(nil)
+
+// NewAgentServer creates an agent named name that runs checks with execute.
+func NewAgentServer(name string, execute ExecuteFunc, logger *slog.Logger) *AgentServer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &AgentServer{run: execute, logger: logger, name: name}
+}
+
+// SetFacts declares facts of this agent reported in addition to the
+// discovered ones, such as "gpu" or "zone:eu-west".
+func (s *AgentServer) SetFacts(facts []string) {
+	s.declared = facts
+}
+
+// NewGRPCServer creates a gRPC server serving the agent, over TLS when
+// tlsConfig is set.
+func (s *AgentServer) NewGRPCServer(tlsConfig *tls.Config) *grpc.Server {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	server := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.MaxRecvMsgSize(maxMessageSize),
+		grpc.MaxSendMsgSize(maxMessageSize),
+	)
+	server.RegisterService(&serviceDesc, s)
+	return server
+}
+
+func (s *AgentServer) execute(ctx context.Context, req *executeRequest) (*executeResponse, error) {
+	if len(req.Profile) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing profile")
+	}
+	if len(req.ControlIDs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no controls requested")
+	}
+	s.logger.Info("running controls for coordinator", "request_id", req.RequestID, "controls", len(req.ControlIDs))
+
+	result, err := s.run(ctx, dto.PartialCheckRequest{
+		Profile:                   req.Profile,
+		Environment:               req.Environment,
+		RequestID:                 req.RequestID,
+		ControlIDs:                req.ControlIDs,
+		Parallel:                  req.Parallel,
+		MaxConcurrentControls:     req.MaxConcurrentControls,
+		MaxConcurrentObservations: req.MaxConcurrentObservations,
+		MaxEvidenceSizeBytes:      req.MaxEvidenceSizeBytes,
+	})
+	if err != nil {
+		s.logger.Error("partial check failed", "request_id", req.RequestID, "error", err)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, status.FromContextError(err).Err()
+		}
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to serialize result: %v", err))
+	}
+	return &executeResponse{Node: s.name, Result: data}, nil
+}
+
+func (s *AgentServer) facts(_ context.Context, req *factsRequest) (*factsResponse, error) {
+	return &factsResponse{Node: s.name, Facts: hostFacts(s.declared, req.Commands)}, nil
+}
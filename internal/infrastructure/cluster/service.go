@@ -0,0 +1,93 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	serviceName   = "reglet.agent.v1.Agent"
+	executeMethod = "/" + serviceName + "/Execute"
+	factsMethod   = "/" + serviceName + "/Facts"
+
+	// maxMessageSize bounds profile documents and results with evidence.
+	maxMessageSize = 64 << 20
+)
+
+// executeRequest asks an agent to run some controls of a profile.
+type executeRequest struct {
+	Profile                   []byte   `json:"profile"`
+	Environment               string   `json:"environment,omitempty"`
+	RequestID                 string   `json:"request_id,omitempty"`
+	ControlIDs                []string `json:"control_ids"`
+	Parallel                  bool     `json:"parallel,omitempty"`
+	MaxConcurrentControls     int      `json:"max_concurrent_controls,omitempty"`
+	MaxConcurrentObservations int      `json:"max_concurrent_observations,omitempty"`
+	MaxEvidenceSizeBytes      int      `json:"max_evidence_size_bytes,omitempty"`
+}
+
+// executeResponse carries the agent's result as a serialized ExecutionResult,
+// so it is read with the schema upgrades of stored results.
+type executeResponse struct {
+	Node   string          `json:"node"`
+	Result json.RawMessage `json:"result"`
+}
+
+// factsRequest asks an agent for its facts, probing for commands.
+type factsRequest struct {
+	Commands []string `json:"commands,omitempty"`
+}
+
+// factsResponse lists the facts of an agent.
+type factsResponse struct {
+	Node  string   `json:"node"`
+	Facts []string `json:"facts"`
+}
+
+// agentService is implemented by the agent side of the service.
+type agentService interface {
+	execute(ctx context.Context, req *executeRequest) (*executeResponse, error)
+	facts(ctx context.Context, req *factsRequest) (*factsResponse, error)
+}
+
+// serviceDesc describes the agent service to gRPC.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*agentService)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Execute", Handler: executeHandler},
+		{MethodName: "Facts", Handler: factsHandler},
+	},
+}
+
+func executeHandler(srv any, ctx context.Context, decode func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	req := new(executeRequest)
+	if err := decode(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(agentService).execute(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: executeMethod}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(agentService).execute(ctx, req.(*executeRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+func factsHandler(srv any, ctx context.Context, decode func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	req := new(factsRequest)
+	if err := decode(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(agentService).facts(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: factsMethod}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(agentService).facts(ctx, req.(*factsRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
@@ -0,0 +1,91 @@
+package cluster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSFiles names the PEM files securing agent connections. Leaving all of
+// them empty disables TLS.
+type TLSFiles struct {
+	// CAFile verifies the other side: agents require coordinators to present
+	// a certificate it signed, and coordinators verify agents with it.
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// Enabled reports whether any TLS file is configured.
+func (f TLSFiles) Enabled() bool {
+	return f.CAFile != "" || f.CertFile != "" || f.KeyFile != ""
+}
+
+// ServerConfig returns the TLS configuration of an agent, or nil when TLS is
+// disabled. The agent needs a certificate; with a CA, it only accepts
+// coordinators presenting a certificate signed by it. Without one, any
+// client is accepted, so "reglet agent" refuses non-loopback listeners.
+func (f TLSFiles) ServerConfig() (*tls.Config, error) {
+	if !f.Enabled() {
+		return nil, nil
+	}
+	if f.CertFile == "" || f.KeyFile == "" {
+		return nil, errors.New("agent TLS requires a certificate and key")
+	}
+	cert, err := tls.LoadX509KeyPair(f.CertFile, f.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load agent certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if f.CAFile != "" {
+		pool, err := loadCertPool(f.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// ClientConfig returns the TLS configuration of a coordinator, or nil when
+// TLS is disabled. Agents are verified with the CA (default: the system
+// roots); the certificate, if any, authenticates the coordinator.
+func (f TLSFiles) ClientConfig() (*tls.Config, error) {
+	if !f.Enabled() {
+		return nil, nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if f.CAFile != "" {
+		pool, err := loadCertPool(f.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	if f.CertFile != "" || f.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(f.CertFile, f.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load coordinator certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: CA path comes from the system config
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates in CA file %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,175 @@
+// Package compression compresses persisted execution results with gzip or
+// zstd. Readers detect compressed content by its magic bytes rather than by
+// file name, so compressed and plain results can be read alike.
+package compression
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Algorithm is a compression algorithm.
+type Algorithm string
+
+// Supported algorithms.
+const (
+	None Algorithm = ""
+	Gzip Algorithm = "gzip"
+	Zstd Algorithm = "zstd"
+)
+
+// Magic bytes starting compressed content.
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Parse parses a configured algorithm: "none" (or empty), "gzip" or "zstd".
+func Parse(name string) (Algorithm, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return None, nil
+	case "gzip", "gz":
+		return Gzip, nil
+	case "zstd", "zst":
+		return Zstd, nil
+	default:
+		return None, fmt.Errorf("unknown compression %q (expected none, gzip or zstd)", name)
+	}
+}
+
+// FromPath returns the algorithm a file name asks for by its extension:
+// ".gz" for gzip, ".zst" for zstd.
+func FromPath(path string) Algorithm {
+	switch {
+	case strings.HasSuffix(path, ".gz"), strings.HasSuffix(path, ".gzip"):
+		return Gzip
+	case strings.HasSuffix(path, ".zst"), strings.HasSuffix(path, ".zstd"):
+		return Zstd
+	default:
+		return None
+	}
+}
+
+// Extension returns the file extension of the algorithm, "" for None.
+func (a Algorithm) Extension() string {
+	switch a {
+	case Gzip:
+		return ".gz"
+	case Zstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// TrimExtension removes the extension of a compressed file name, so
+// "result.json.zst" reads as "result.json".
+func TrimExtension(name string) string {
+	if a := FromPath(name); a != None {
+		return name[:strings.LastIndexByte(name, '.')]
+	}
+	return name
+}
+
+// NewWriter returns a writer compressing to w with the algorithm. Close
+// flushes the compressed stream; it does not close w.
+func NewWriter(w io.Writer, a Algorithm) (io.WriteCloser, error) {
+	switch a {
+	case None:
+		return nopWriteCloser{w}, nil
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	default:
+		return nil, fmt.Errorf("unknown compression %q", a)
+	}
+}
+
+// Compress compresses data with the algorithm.
+func Compress(data []byte, a Algorithm) ([]byte, error) {
+	if a == None {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, a)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// NewReader returns a reader decompressing r if it starts with gzip or zstd
+// magic bytes, or reading it as is otherwise. Close releases the
+// decompressor; it does not close r.
+func NewReader(r io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+	head, err := buffered.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		return gzip.NewReader(buffered)
+	case bytes.HasPrefix(head, zstdMagic):
+		decoder, err := zstd.NewReader(buffered, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	default:
+		return io.NopCloser(buffered), nil
+	}
+}
+
+// Decompress returns data decompressed if it starts with gzip or zstd magic
+// bytes, or data itself otherwise.
+func Decompress(data []byte) ([]byte, error) {
+	if !IsCompressed(data) {
+		return data, nil
+	}
+	r, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+	defer func() { _ = r.Close() }()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+	return out, nil
+}
+
+// IsCompressed reports whether data starts with gzip or zstd magic bytes.
+func IsCompressed(data []byte) bool {
+	return bytes.HasPrefix(data, gzipMagic) || bytes.HasPrefix(data, zstdMagic)
+}
+
+// ReadFile reads a file, decompressing it if it is compressed.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: callers pass user-specified or configured result paths
+	if err != nil {
+		return nil, err
+	}
+	return Decompress(data)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,47 @@
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat([]byte(`{"control":"ssh","status":"pass"}`), 1000)
+	for _, algorithm := range []Algorithm{None, Gzip, Zstd} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			t.Parallel()
+			compressed, err := Compress(data, algorithm)
+			require.NoError(t, err)
+			assert.Equal(t, algorithm != None, IsCompressed(compressed))
+			if algorithm != None {
+				assert.Less(t, len(compressed), len(data)/10)
+			}
+
+			out, err := Decompress(compressed)
+			require.NoError(t, err)
+			assert.Equal(t, data, out)
+		})
+	}
+}
+
+func TestFromPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Zstd, FromPath("results.json.zst"))
+	assert.Equal(t, Gzip, FromPath("results.json.gz"))
+	assert.Equal(t, None, FromPath("results.json"))
+	assert.Equal(t, "results.json", TrimExtension("results.json.zst"))
+	assert.Equal(t, "results.json", TrimExtension("results.json"))
+	assert.Equal(t, ".zst", Zstd.Extension())
+
+	algorithm, err := Parse("none")
+	require.NoError(t, err)
+	assert.Equal(t, None, algorithm)
+	_, err = Parse("brotli")
+	assert.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/reglet-dev/reglet/internal/domain/entities"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -34,6 +35,60 @@ controls:
 	assert.Equal(t, "ctrl-001", profile.Controls.Items[0].ID)
 }
 
+func TestLoadProfileFromReader_Thresholds(t *testing.T) {
+	yaml := `
+profile:
+  name: Test Profile
+  version: 1.0.0
+controls:
+  items:
+    - id: ctrl-001
+      name: Test Control
+      observations:
+        - plugin: http
+          config:
+            url: http://example.com
+          thresholds:
+            latency_ms: { max: 500ms, unit: ms }
+            tls.days_left: { min: 30 }
+`
+	loader := NewProfileLoader()
+	profile, err := loader.LoadProfileFromReader(strings.NewReader(yaml))
+	require.NoError(t, err)
+
+	thresholds := profile.Controls.Items[0].ObservationDefinitions[0].Thresholds
+	assert.Equal(t, entities.Threshold{Max: "500ms", Unit: "ms"}, thresholds["latency_ms"])
+	assert.Equal(t, entities.Threshold{Min: "30"}, thresholds["tls.days_left"])
+}
+
+func TestLoadProfileFromReader_PluginAliases(t *testing.T) {
+	yaml := `
+profile:
+  name: Test Profile
+  version: 1.0.0
+plugins:
+  - reglet/http@1.0
+  - http2: reglet/http@2.0
+  - dns-legacy=dns@0.9
+controls:
+  items:
+    - id: ctrl-001
+      name: Test Control
+      observations:
+        - plugin: http2
+          config:
+            url: http://example.com
+`
+	loader := NewProfileLoader()
+	profile, err := loader.LoadProfileFromReader(strings.NewReader(yaml))
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.PluginDeclarations{"reglet/http@1.0", "http2=reglet/http@2.0", "dns-legacy=dns@0.9"}, profile.Plugins)
+
+	_, err = loader.LoadProfileFromReader(strings.NewReader("plugins:\n  - http2: [reglet/http]\n"))
+	assert.ErrorContains(t, err, `plugin alias "http2" must map to a plugin declaration`)
+}
+
 func TestLoadProfileFromReader_InvalidYAML(t *testing.T) {
 	yaml := `invalid yaml: [[[`
 
@@ -1,9 +1,8 @@
 package config
 
 import (
-	"runtime"
-
 	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/hostfacts"
 	"github.com/reglet-dev/reglet/internal/infrastructure/system"
 )
 
@@ -12,16 +11,23 @@ import (
 type RuntimeConfig struct {
 	// Security
 	SecurityLevel string
+	SymlinkPolicy string // how plugin paths may follow symlinks; empty = restrict to the granted tree
+	HardenPaths   bool   // deny plugin paths whose canonical form leaves their granted scope
+
+	// ProcessPlugins are the external process plugins trusted to run
+	ProcessPlugins []string
 
 	// Evidence
 	MaxEvidenceSizeBytes int
 
 	// WASM
 	WasmMemoryLimitMB int
+	ScratchLimitMB    int // -1 disables plugin scratch directories
 
 	// Concurrency
 	MaxConcurrentControls     int
 	MaxConcurrentObservations int
+	PluginWeights             map[string]int // shares of the control workers by plugin
 }
 
 // FromSystemConfig creates RuntimeConfig from system config.
@@ -29,7 +35,12 @@ func FromSystemConfig(sys *system.Config) *RuntimeConfig {
 	return &RuntimeConfig{
 		MaxEvidenceSizeBytes: sys.MaxEvidenceSizeBytes,
 		WasmMemoryLimitMB:    sys.WasmMemoryLimitMB,
+		ScratchLimitMB:       sys.ScratchLimitMB,
 		SecurityLevel:        string(sys.Security.GetSecurityLevel()),
+		SymlinkPolicy:        sys.Security.SymlinkPolicy,
+		HardenPaths:          sys.Security.HardenPaths,
+		PluginWeights:        sys.Scheduling.PluginWeights,
+		ProcessPlugins:       sys.ProcessPlugins,
 	}
 }
 
@@ -41,8 +52,11 @@ func (r *RuntimeConfig) ApplyDefaults() {
 	if r.WasmMemoryLimitMB == 0 {
 		r.WasmMemoryLimitMB = 512 // Default 512MB per instance
 	}
+	if r.ScratchLimitMB == 0 {
+		r.ScratchLimitMB = 256 // Default 256MB per observation
+	}
 	if r.MaxConcurrentControls == 0 {
-		r.MaxConcurrentControls = runtime.NumCPU()
+		r.MaxConcurrentControls = hostfacts.AvailableCPUs()
 	}
-	// MaxConcurrentObservations defaults to 0 (unlimited) if not set, which is fine.
+	// MaxConcurrentObservations stays 0, leaving the engine's CPU-based default.
 }
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/reglet-dev/reglet/internal/domain/services"
+)
+
+// severityRulesFile is the document of a severity rules file.
+type severityRulesFile struct {
+	Rules []services.SeverityRule `yaml:"rules"`
+}
+
+// LoadSeverityRules loads a severity rules file. Unknown fields are errors,
+// so a misspelled condition cannot silently widen a rule. Time functions in
+// rule conditions read clock (nil = system time).
+func LoadSeverityRules(path string, clock func() time.Time) (*services.SeverityRules, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is chosen by the user running reglet
+	if err != nil {
+		return nil, fmt.Errorf("failed to read severity rules: %w", err)
+	}
+
+	var file severityRulesFile
+	if err := yaml.UnmarshalWithOptions(data, &file, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("failed to decode severity rules %s: %w", path, err)
+	}
+	if clock == nil {
+		clock = time.Now
+	}
+	rules, err := services.NewSeverityRules(file.Rules, clock)
+	if err != nil {
+		return nil, fmt.Errorf("invalid severity rules %s: %w", path, err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSeverityRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "severity-rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+rules:
+  - name: tls-expiry
+    plugin: tcp
+    when: data.tls_cert_days_remaining < 7
+    severity: critical
+`), 0o600))
+
+	rules, err := LoadSeverityRules(path, nil)
+	require.NoError(t, err)
+
+	ctrl := execution.ControlResult{
+		Severity: "low",
+		ObservationResults: []execution.ObservationResult{{
+			Plugin:   "tcp",
+			Evidence: &execution.Evidence{Data: map[string]interface{}{"tls_cert_days_remaining": 2}},
+		}},
+	}
+	rules.Apply(&ctrl)
+	assert.Equal(t, "critical", ctrl.Severity)
+}
+
+func TestLoadSeverityRules_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadSeverityRules(filepath.Join(dir, "missing.yaml"), nil)
+	assert.ErrorContains(t, err, "failed to read severity rules")
+
+	typo := filepath.Join(dir, "typo.yaml")
+	require.NoError(t, os.WriteFile(typo, []byte("rules:\n  - name: x\n    severty: high\n"), 0o600))
+	_, err = LoadSeverityRules(typo, nil)
+	assert.ErrorContains(t, err, "failed to decode severity rules")
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("rules:\n  - name: x\n    severity: urgent\n"), 0o600))
+	_, err = LoadSeverityRules(invalid, nil)
+	assert.ErrorContains(t, err, `x: invalid severity "urgent"`)
+}
@@ -13,6 +13,9 @@ import (
 // Variable pattern: {{ .vars.key }}
 var varPattern = regexp.MustCompile(`\{\{\s*\.vars\.([a-zA-Z0-9_.]+)\s*\}\}`)
 
+// Target pattern: {{ .target }}
+var targetPattern = regexp.MustCompile(`\{\{\s*\.target\s*\}\}`)
+
 // Secret pattern: {{ secret "key" }}
 var secretPattern = regexp.MustCompile(`\{\{\s*secret\s+"([a-zA-Z0-9_.-]+)"\s*\}\}`)
 
@@ -40,6 +43,12 @@ func (s *VariableSubstitutor) Substitute(profile *entities.Profile) error {
 	// So we should remove the 'if len(profile.Vars) == 0' check if we want secrets to work without vars.
 	// But let's check if existing logic relied on it.
 
+	// Fan observations out to the selected environment's targets first, so
+	// vars and secrets are substituted in every copy
+	if err := expandTargets(profile); err != nil {
+		return err
+	}
+
 	// Substitute variables in each control
 	for i := range profile.Controls.Items {
 		ctrl := &profile.Controls.Items[i]
@@ -50,6 +59,10 @@ func (s *VariableSubstitutor) Substitute(profile *entities.Profile) error {
 		if err != nil {
 			return fmt.Errorf("control %s: %w", ctrl.ID, err)
 		}
+		ctrl.Rationale, err = s.substituteInString(ctrl.Rationale, profile.Vars)
+		if err != nil {
+			return fmt.Errorf("control %s: %w", ctrl.ID, err)
+		}
 
 		// Substitute in each observation config
 		for j := range ctrl.ObservationDefinitions {
@@ -58,12 +71,136 @@ func (s *VariableSubstitutor) Substitute(profile *entities.Profile) error {
 			if err := s.substituteInMap(obs.Config, profile.Vars); err != nil {
 				return fmt.Errorf("control %s, observation %d: %w", ctrl.ID, j, err)
 			}
+
+			if err := s.substituteInEnv(obs.Env, profile.Vars); err != nil {
+				return fmt.Errorf("control %s, observation %d: %w", ctrl.ID, j, err)
+			}
+		}
+	}
+
+	// Substitute in setup and teardown steps; setup reads secrets such as
+	// the credentials an auth token is obtained with
+	for _, steps := range [][]entities.LifecycleStep{profile.Setup, profile.Teardown} {
+		for i := range steps {
+			step := &steps[i]
+			if err := s.substituteInMap(step.Config, profile.Vars); err != nil {
+				return fmt.Errorf("step %s: %w", step.ID, err)
+			}
+			if err := s.substituteInEnv(step.Env, profile.Vars); err != nil {
+				return fmt.Errorf("step %s: %w", step.ID, err)
+			}
+		}
+	}
+
+	// Substitute in integration sections; secrets are allowed here since
+	// exporter settings never reach plugins
+	for name, section := range profile.Integrations {
+		if err := s.substituteInMap(section, profile.Vars); err != nil {
+			return fmt.Errorf("integration %s: %w", name, err)
 		}
 	}
 
 	return nil
 }
 
+// expandTargets replaces each observation referencing {{ .target }} in its
+// config or env with one copy per target of the selected environment.
+func expandTargets(profile *entities.Profile) error {
+	targets := profile.Targets()
+
+	for i := range profile.Controls.Items {
+		ctrl := &profile.Controls.Items[i]
+
+		var expanded []entities.ObservationDefinition
+		fannedOut := false
+		for j, obs := range ctrl.ObservationDefinitions {
+			if !referencesTarget(obs) {
+				expanded = append(expanded, obs)
+				continue
+			}
+			if len(targets) == 0 {
+				if profile.Environment == "" {
+					return fmt.Errorf("control %s, observation %d: uses {{ .target }} but no environment is selected (use --env)", ctrl.ID, j)
+				}
+				return fmt.Errorf("control %s, observation %d: uses {{ .target }} but environment %s has no targets", ctrl.ID, j, profile.Environment)
+			}
+
+			fannedOut = true
+			for _, target := range targets {
+				expanded = append(expanded, observationForTarget(obs, target))
+			}
+		}
+		if fannedOut {
+			ctrl.ObservationDefinitions = expanded
+		}
+	}
+	return nil
+}
+
+func referencesTarget(obs entities.ObservationDefinition) bool {
+	if _, found := withTarget(obs.Config, ""); found {
+		return true
+	}
+	for _, value := range obs.Env {
+		if targetPattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// observationForTarget returns a copy of obs with {{ .target }} replaced by
+// target. Config is copied deeply so copies never share nested values.
+func observationForTarget(obs entities.ObservationDefinition, target string) entities.ObservationDefinition {
+	if obs.Config != nil {
+		config, _ := withTarget(obs.Config, target)
+		obs.Config = config.(map[string]interface{})
+	}
+	if obs.Env != nil {
+		env := make(map[string]string, len(obs.Env))
+		for key, value := range obs.Env {
+			env[key] = targetPattern.ReplaceAllLiteralString(value, target)
+		}
+		obs.Env = env
+	}
+	return obs
+}
+
+// withTarget returns a copy of value with {{ .target }} replaced by target in
+// every string, and whether any string referenced it.
+func withTarget(value interface{}, target string) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		if !targetPattern.MatchString(v) {
+			return v, false
+		}
+		return targetPattern.ReplaceAllLiteralString(v, target), true
+
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		found := false
+		for key, elem := range v {
+			var f bool
+			out[key], f = withTarget(elem, target)
+			found = found || f
+		}
+		return out, found
+
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		found := false
+		for i, elem := range v {
+			var f bool
+			out[i], f = withTarget(elem, target)
+			found = found || f
+		}
+		return out, found
+
+	default:
+		return v, false
+	}
+}
+
 // substituteInString replaces patterns with values.
 func (s *VariableSubstitutor) substituteInString(str string, vars map[string]interface{}) (string, error) {
 	var lastErr error
@@ -122,6 +259,23 @@ func (s *VariableSubstitutor) substituteInString(str string, vars map[string]int
 	return result, nil
 }
 
+// substituteInEnv substitutes variables in observation env values.
+// Secrets are rejected: env is for non-secret settings and may be logged or
+// inherited by child processes.
+func (s *VariableSubstitutor) substituteInEnv(env map[string]string, vars map[string]interface{}) error {
+	for key, value := range env {
+		if secretPattern.MatchString(value) {
+			return fmt.Errorf("env %s: secrets cannot be passed through observation env", key)
+		}
+		substituted, err := s.substituteInString(value, vars)
+		if err != nil {
+			return fmt.Errorf("env %s: %w", key, err)
+		}
+		env[key] = substituted
+	}
+	return nil
+}
+
 // substituteInMap recursively substitutes variables in map values.
 // Modifies the map in place.
 func (s *VariableSubstitutor) substituteInMap(m map[string]interface{}, vars map[string]interface{}) error {
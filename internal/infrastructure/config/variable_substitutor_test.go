@@ -159,3 +159,193 @@ controls:
 	nested := profile.Controls.Items[0].ObservationDefinitions[0].Config["nested"].(map[string]interface{})
 	assert.Equal(t, "secure-password", nested["key"])
 }
+
+func TestSubstituteVariables_ObservationEnv(t *testing.T) {
+	yaml := `
+profile:
+  name: test-profile
+  version: 1.0.0
+
+vars:
+  sslmode: require
+
+controls:
+  items:
+    - id: test-control
+      name: Env Control
+      observations:
+        - plugin: command
+          config:
+            command: psql
+          env:
+            LANG: C.UTF-8
+            PGSSLMODE: "{{ .vars.sslmode }}"
+`
+
+	loader := NewProfileLoader()
+	profile, err := loader.LoadProfileFromReader(strings.NewReader(yaml))
+	require.NoError(t, err)
+
+	substitutor := NewVariableSubstitutor(nil)
+	require.NoError(t, substitutor.Substitute(profile))
+
+	assert.Equal(t, map[string]string{"LANG": "C.UTF-8", "PGSSLMODE": "require"},
+		profile.Controls.Items[0].ObservationDefinitions[0].Env)
+}
+
+func TestSubstituteVariables_ObservationEnvRejectsSecrets(t *testing.T) {
+	yaml := `
+profile:
+  name: test-profile
+  version: 1.0.0
+
+controls:
+  items:
+    - id: test-control
+      name: Env Control
+      observations:
+        - plugin: command
+          config:
+            command: psql
+          env:
+            PGPASSWORD: '{{ secret "db_pass" }}'
+`
+
+	loader := NewProfileLoader()
+	profile, err := loader.LoadProfileFromReader(strings.NewReader(yaml))
+	require.NoError(t, err)
+
+	substitutor := NewVariableSubstitutor(&MockSecretResolver{secrets: map[string]string{"db_pass": "x"}})
+	err = substitutor.Substitute(profile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "secrets cannot be passed through observation env")
+}
+
+func TestSubstituteVariables_Integrations(t *testing.T) {
+	yaml := `
+profile:
+  name: test-profile
+  version: 1.0.0
+
+vars:
+  jira_project: SEC
+
+integrations:
+  jira:
+    url: https://jira.example.com
+    project: "{{ .vars.jira_project }}"
+    token: '{{ secret "jira_token" }}'
+
+controls:
+  items:
+    - id: test-control
+      name: Test Control
+      observations:
+        - plugin: file
+          config:
+            path: /etc/hosts
+`
+
+	loader := NewProfileLoader()
+	profile, err := loader.LoadProfileFromReader(strings.NewReader(yaml))
+	require.NoError(t, err)
+
+	substitutor := NewVariableSubstitutor(&MockSecretResolver{secrets: map[string]string{"jira_token": "s3cret"}})
+	require.NoError(t, substitutor.Substitute(profile))
+
+	assert.Equal(t, "SEC", profile.Integrations["jira"]["project"])
+	assert.Equal(t, "s3cret", profile.Integrations["jira"]["token"])
+}
+
+const environmentsProfile = `
+profile:
+  name: test-profile
+  version: 1.0.0
+
+vars:
+  port: 443
+  min_days: 30
+
+environments:
+  staging:
+    vars:
+      min_days: 7
+    targets: [stg.example.com]
+  prod:
+    targets: [web-1.example.com, web-2.example.com]
+  empty: {}
+
+controls:
+  items:
+    - id: tls
+      name: TLS
+      description: "Certificates valid for {{ .vars.min_days }} days"
+      observations:
+        - plugin: tcp
+          config:
+            host: "{{ .target }}"
+            port: "{{ .vars.port }}"
+            tags: ["{{ .target }}:{{ .vars.port }}"]
+          env:
+            TARGET: "{{ .target }}"
+        - plugin: file
+          config:
+            path: /etc/hosts
+`
+
+func TestSubstituteVariables_EnvironmentTargets(t *testing.T) {
+	profile, err := NewProfileLoader().LoadProfileFromReader(strings.NewReader(environmentsProfile))
+	require.NoError(t, err)
+	require.NoError(t, profile.SelectEnvironment("prod"))
+
+	require.NoError(t, NewVariableSubstitutor(nil).Substitute(profile))
+
+	ctrl := profile.Controls.Items[0]
+	assert.Equal(t, "Certificates valid for 30 days", ctrl.Description)
+	require.Len(t, ctrl.ObservationDefinitions, 3, "one tcp observation per target, file untouched")
+	for i, host := range []string{"web-1.example.com", "web-2.example.com"} {
+		obs := ctrl.ObservationDefinitions[i]
+		assert.Equal(t, host, obs.Config["host"])
+		assert.Equal(t, "443", obs.Config["port"])
+		assert.Equal(t, []interface{}{host + ":443"}, obs.Config["tags"])
+		assert.Equal(t, host, obs.Env["TARGET"])
+	}
+	assert.Equal(t, "file", ctrl.ObservationDefinitions[2].Plugin)
+}
+
+func TestSubstituteVariables_EnvironmentVars(t *testing.T) {
+	profile, err := NewProfileLoader().LoadProfileFromReader(strings.NewReader(environmentsProfile))
+	require.NoError(t, err)
+	require.NoError(t, profile.SelectEnvironment("staging"))
+
+	require.NoError(t, NewVariableSubstitutor(nil).Substitute(profile))
+
+	ctrl := profile.Controls.Items[0]
+	assert.Equal(t, "Certificates valid for 7 days", ctrl.Description)
+	require.Len(t, ctrl.ObservationDefinitions, 2)
+	assert.Equal(t, "stg.example.com", ctrl.ObservationDefinitions[0].Config["host"])
+}
+
+func TestSubstituteVariables_TargetWithoutEnvironment(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		wantErr     string
+	}{
+		{"no environment", "", "no environment is selected (use --env)"},
+		{"environment without targets", "empty", "environment empty has no targets"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := NewProfileLoader().LoadProfileFromReader(strings.NewReader(environmentsProfile))
+			require.NoError(t, err)
+			require.NoError(t, profile.SelectEnvironment(tt.environment))
+
+			err = NewVariableSubstitutor(nil).Substitute(profile)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "control tls, observation 0: uses {{ .target }}")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
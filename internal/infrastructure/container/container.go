@@ -3,39 +3,83 @@ package container
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/reglet-dev/reglet/internal/application/dto"
 	"github.com/reglet-dev/reglet/internal/application/ports"
 	"github.com/reglet-dev/reglet/internal/application/services"
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
 	domainservices "github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/reglet-dev/reglet/internal/domain/values"
 	"github.com/reglet-dev/reglet/internal/infrastructure/adapters"
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	infracapabilities "github.com/reglet-dev/reglet/internal/infrastructure/capabilities"
+	"github.com/reglet-dev/reglet/internal/infrastructure/cluster"
+	"github.com/reglet-dev/reglet/internal/infrastructure/compression"
 	infraconfig "github.com/reglet-dev/reglet/internal/infrastructure/config"
+	"github.com/reglet-dev/reglet/internal/infrastructure/engine"
+	"github.com/reglet-dev/reglet/internal/infrastructure/exporter"
 	"github.com/reglet-dev/reglet/internal/infrastructure/filesystem"
+	"github.com/reglet-dev/reglet/internal/infrastructure/hostaccess"
+	"github.com/reglet-dev/reglet/internal/infrastructure/httpapi"
 	"github.com/reglet-dev/reglet/internal/infrastructure/output"
+	"github.com/reglet-dev/reglet/internal/infrastructure/persistence/jsonfile"
+	"github.com/reglet-dev/reglet/internal/infrastructure/persistence/memory"
+	"github.com/reglet-dev/reglet/internal/infrastructure/persistence/postgres"
 	"github.com/reglet-dev/reglet/internal/infrastructure/plugins"
+	"github.com/reglet-dev/reglet/internal/infrastructure/plugins/delta"
 	embeddedplugin "github.com/reglet-dev/reglet/internal/infrastructure/plugins/embedded"
+	pluginindex "github.com/reglet-dev/reglet/internal/infrastructure/plugins/index"
 	ociplugin "github.com/reglet-dev/reglet/internal/infrastructure/plugins/oci"
 	pluginrepo "github.com/reglet-dev/reglet/internal/infrastructure/plugins/repository"
 	signingplugin "github.com/reglet-dev/reglet/internal/infrastructure/plugins/signing"
+	"github.com/reglet-dev/reglet/internal/infrastructure/proftest"
+	"github.com/reglet-dev/reglet/internal/infrastructure/rundir"
 	"github.com/reglet-dev/reglet/internal/infrastructure/secrets"
 	"github.com/reglet-dev/reglet/internal/infrastructure/sensitivedata"
 	"github.com/reglet-dev/reglet/internal/infrastructure/system"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
 )
 
 // Container holds all application dependencies.
 type Container struct {
 	profileLoader       ports.ProfileLoader
+	secretResolver      ports.SecretResolver
 	profileValidator    ports.ProfileValidator
 	systemConfig        ports.SystemConfigProvider
 	pluginResolver      ports.PluginDirectoryResolver
 	engineFactory       ports.EngineFactory
+	fixtureEngines      ports.FixtureEngineFactory
+	evaluationEngines   ports.EvaluationEngineFactory
 	checkProfileUseCase *services.CheckProfileUseCase
 	pluginService       *services.PluginService
+	pluginRepository    ports.PluginRepository
+	pluginRegistry      ports.PluginRegistry
+	registryTransport   http.RoundTripper
+	integrityVerifier   ports.IntegrityVerifier
+	integrityService    *domainservices.IntegrityService
+	compilationCache    ports.CompilationCache
+	credentialStore     *secrets.CredentialStore
+	capOrchestrator     *services.CapabilityOrchestrator
+	capGatekeeper       *services.CapabilityGatekeeper
+	resultRepository    repositories.ExecutionResultRepository
 	systemCfg           *system.Config
 	logger              *slog.Logger
+	grantsPath          string
+	pluginDir           string // configured by the project, if any
+	securityLevel       string
 	trustPlugins        bool
 }
 
@@ -45,6 +89,13 @@ type Options struct {
 	SecurityLevel    string
 	SystemConfigPath string
 	TrustPlugins     bool
+	Offline          bool // Resolve plugins from the embedded set and the cache only
+	NonInteractive   bool // Grant saved capabilities only, never prompt
+
+	// Layers is the configuration merged from defaults, the user config, the
+	// project file, environment and flags; when set, SystemConfigPath is not
+	// read again
+	Layers *system.Layers
 }
 
 // New creates a new dependency injection container.
@@ -57,19 +108,45 @@ func New(opts Options) (*Container, error) {
 	sensitiveProvider := sensitivedata.NewProvider()
 
 	systemConfigAdapter := adapters.NewSystemConfigAdapter()
-	systemCfg, err := systemConfigAdapter.LoadConfig(context.Background(), opts.SystemConfigPath)
-	if err != nil {
-		opts.Logger.Debug("failed to load system config, using defaults", "error", err)
-		systemCfg = &system.Config{} // Use defaults
+	var (
+		systemCfg *system.Config
+		err       error
+	)
+	if opts.Layers != nil {
+		systemCfg = opts.Layers.Config()
+	} else {
+		systemCfg, err = systemConfigAdapter.LoadConfig(context.Background(), opts.SystemConfigPath)
+		if err != nil {
+			opts.Logger.Debug("failed to load system config, using defaults", "error", err)
+			systemCfg = &system.Config{} // Use defaults
+		}
 	}
 
 	// Create resolver with config from system config
 	secretResolver := secrets.NewResolver(&systemCfg.SensitiveData.Secrets, sensitiveProvider)
 
+	// Secrets not mapped in the config are looked up in the encrypted
+	// credential store
+	homeDir, _ := os.UserHomeDir()
+	credentialsPath := systemCfg.SensitiveData.Secrets.CredentialsFile
+	if credentialsPath == "" {
+		credentialsPath = filepath.Join(homeDir, ".reglet", "credentials.enc")
+	}
+	credentialStore := secrets.NewCredentialStore(credentialsPath, os.Getenv(secrets.CredentialsPassphraseEnv))
+	if systemCfg.SensitiveData.Secrets.InsecureKeyFile {
+		credentialStore.AllowKeyFile()
+	}
+	secretResolver.SetCredentials(credentialStore)
+
 	// Initialize adapters
 	profileLoader := adapters.NewProfileLoaderAdapter(secretResolver)
 	profileValidator := adapters.NewProfileValidatorAdapter()
 	pluginResolver := adapters.NewPluginDirectoryAdapter()
+	var projectPluginDir string
+	if opts.Layers != nil && opts.Layers.Project().PluginDir != "" {
+		projectPluginDir = opts.Layers.Project().PluginDir
+		pluginResolver.SetPluginDir(projectPluginDir)
+	}
 
 	// Initialize redactor with shared provider
 	redactor, err := sensitivedata.NewWithProvider(sensitivedata.Config{
@@ -88,6 +165,22 @@ func New(opts Options) (*Container, error) {
 
 	// Create engine factory
 	engineFactory := adapters.NewEngineFactoryAdapter(redactor, runtimeCfg)
+	if pii := systemCfg.Redaction.PII; pii.Enabled {
+		scanner, err := sensitivedata.NewPIIScanner(pii.Detectors, pii.Action)
+		if err != nil {
+			return nil, fmt.Errorf("redaction.pii: %w", err)
+		}
+		engineFactory.SetPIIScanner(scanner)
+	}
+
+	// Persist results when a storage backend is configured
+	resultRepo, err := newResultRepository(systemCfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if resultRepo != nil {
+		engineFactory.SetResultRepository(resultRepo)
+	}
 
 	// Determine security level (command-line flag takes precedence over config file)
 	securityLevel := opts.SecurityLevel
@@ -118,6 +211,9 @@ func New(opts Options) (*Container, error) {
 
 	// Create capability gatekeeper (application service)
 	capGatekeeper := services.NewCapabilityGatekeeper(configPath, securityLevel)
+	if opts.NonInteractive {
+		capGatekeeper.DisablePrompts()
+	}
 
 	// Create capability orchestrator with all dependencies injected
 	// This makes the full dependency graph visible at the composition root
@@ -127,23 +223,38 @@ func New(opts Options) (*Container, error) {
 		runtimeFactory,
 		opts.TrustPlugins,
 	)
+	capOrchestrator.SetProcessPluginLoader(adapters.NewProcessPluginLoaderAdapter(systemCfg.ProcessPlugins))
 
 	// --- Plugin Management Wiring ---
 
 	// 1. Auth Provider
-	authProvider := ociplugin.NewEnvAuthProvider()
+	authProvider := ociplugin.NewCredentialAuthProvider(credentialStore)
 
-	// 2. Registry Adapter
+	// 2. Registry Adapter, through the configured mirrors and proxy
 	registryAdapter := ociplugin.NewOCIRegistryAdapter(authProvider)
+	registryAdapter.SetMirrors(systemCfg.Registry.Mirrors)
+	transport, err := registryTransport(systemCfg.Registry, credentialStore)
+	if err != nil {
+		return nil, err
+	}
+	registryAdapter.SetTransport(transport)
 
 	// 3. Plugin Repository (Plugin Cache)
-	homeDir, _ := os.UserHomeDir() // Ignore error for now, worst case cache dir logic handles it or fails
 	cacheDir := filepath.Join(homeDir, ".reglet", "plugins")
 	pluginRepository, err := pluginrepo.NewFSPluginRepository(cacheDir)
 	if err != nil {
 		return nil, err
 	}
 
+	// Compiled plugin code is cached next to the plugins, so it is reused
+	// across processes; without it, plugins compile in every process.
+	var compilationCache ports.CompilationCache
+	if diskCache, err := wasm.UseDiskCompilationCache(filepath.Join(homeDir, ".reglet", "cache", "wasm")); err != nil {
+		opts.Logger.Debug("compilation cache unavailable, compiling plugins in memory", "error", err)
+	} else {
+		compilationCache = diskCache
+	}
+
 	// 4. Integrity Verifier
 	integrityVerifier := signingplugin.NewCosignVerifier(nil, nil)
 
@@ -155,18 +266,37 @@ func New(opts Options) (*Container, error) {
 	integrityService := domainservices.NewIntegrityService(false)
 
 	// 7. Resolvers (Chain of Responsibility)
-	// Resolution Order: Embedded -> Cache -> Registry
-	registryResolver := services.NewRegistryPluginResolver(
-		registryAdapter,
-		pluginRepository,
-		opts.Logger,
-	)
-
+	// Resolution Order: Embedded -> Vendored -> Cache -> Registry (offline:
+	// never pulled)
 	cachedResolver := services.NewCachedPluginResolver(pluginRepository)
-	cachedResolver.SetNext(registryResolver)
+	if opts.Offline {
+		cachedResolver.SetNext(services.NewOfflinePluginResolver())
+	} else {
+		registryResolver := services.NewRegistryPluginResolver(
+			registryAdapter,
+			pluginRepository,
+			opts.Logger,
+		)
+		// Updates patch the newest cached version when the registry
+		// publishes a patch from it
+		registryResolver.SetPatches(registryAdapter, delta.NewZstdPatcher())
+		cachedResolver.SetNext(registryResolver)
+	}
 
 	embeddedResolver := services.NewEmbeddedPluginResolver(embeddedSource)
 	embeddedResolver.SetNext(cachedResolver)
+	if opts.Layers != nil && opts.Layers.Project().Path != "" {
+		vendorDir := opts.Layers.Project().VendorDir()
+		if info, err := os.Stat(vendorDir); err == nil && info.IsDir() {
+			vendorRepository, err := pluginrepo.NewFSPluginRepository(vendorDir)
+			if err != nil {
+				return nil, err
+			}
+			vendoredResolver := services.NewVendoredPluginResolver(vendorRepository, pluginRepository, opts.Logger)
+			vendoredResolver.SetNext(cachedResolver)
+			embeddedResolver.SetNext(vendoredResolver)
+		}
+	}
 
 	// 8. Plugin Service (Application Service)
 	pluginService := services.NewPluginService(
@@ -206,18 +336,72 @@ func New(opts Options) (*Container, error) {
 
 	return &Container{
 		profileLoader:       profileLoader,
+		secretResolver:      secretResolver,
 		profileValidator:    profileValidator,
 		systemConfig:        systemConfigAdapter,
 		pluginResolver:      pluginResolver,
 		engineFactory:       engineFactory,
+		fixtureEngines:      engineFactory,
+		evaluationEngines:   engineFactory,
 		checkProfileUseCase: checkProfileUseCase,
 		pluginService:       pluginService,
+		pluginRepository:    pluginRepository,
+		pluginRegistry:      registryAdapter,
+		registryTransport:   transport,
+		integrityVerifier:   integrityVerifier,
+		integrityService:    integrityService,
+		compilationCache:    compilationCache,
+		credentialStore:     credentialStore,
+		capOrchestrator:     capOrchestrator,
+		capGatekeeper:       capGatekeeper,
+		resultRepository:    resultRepo,
 		trustPlugins:        opts.TrustPlugins,
 		systemCfg:           systemCfg,
 		logger:              opts.Logger,
+		grantsPath:          configPath,
+		pluginDir:           projectPluginDir,
+		securityLevel:       securityLevel,
 	}, nil
 }
 
+// newResultRepository creates the execution result repository of the
+// configured storage backend, or nil when results are not persisted.
+func newResultRepository(cfg system.StorageConfig) (repositories.ExecutionResultRepository, error) {
+	switch cfg.Backend {
+	case system.StorageBackendNone:
+		return nil, nil
+	case system.StorageBackendMemory:
+		return memory.NewExecutionResultRepository(), nil
+	case system.StorageBackendFile:
+		dir := cfg.File.Dir
+		if dir == "" {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("failed to locate results directory: %w", err)
+			}
+			dir = filepath.Join(homeDir, ".reglet", "results")
+		}
+		algorithm, err := compression.Parse(cfg.File.Compression)
+		if err != nil {
+			return nil, fmt.Errorf("storage.file.compression: %w", err)
+		}
+		repository := jsonfile.NewExecutionResultRepository(dir)
+		repository.SetCompression(algorithm)
+		return repository, nil
+	case system.StorageBackendPostgres:
+		return postgres.Open(context.Background(), postgres.Config{
+			DSN:             cfg.Postgres.DSN,
+			MaxConns:        cfg.Postgres.MaxConns,
+			MinConns:        cfg.Postgres.MinConns,
+			MaxConnLifetime: cfg.Postgres.MaxConnLifetime,
+			MaxConnIdleTime: cfg.Postgres.MaxConnIdleTime,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (expected %q, %q or %q)",
+			cfg.Backend, system.StorageBackendMemory, system.StorageBackendFile, system.StorageBackendPostgres)
+	}
+}
+
 // CheckProfileUseCase returns the check profile use case.
 func (c *Container) CheckProfileUseCase() *services.CheckProfileUseCase {
 	return c.checkProfileUseCase
@@ -228,6 +412,407 @@ func (c *Container) PluginService() *services.PluginService {
 	return c.pluginService
 }
 
+// PluginPruneService returns a service that removes cached plugin versions
+// no workspace profile or lockfile references, and ages out compiled code.
+func (c *Container) PluginPruneService() *services.PluginPruneService {
+	return services.NewPluginPruneService(c.pluginRepository, filesystem.NewWorkspacePluginScanner(), c.compilationCache)
+}
+
+// PluginDoctorService returns a service that checks the health of the
+// plugins in the plugin directory and the local cache.
+func (c *Container) PluginDoctorService() *services.PluginDoctorService {
+	return services.NewPluginDoctorService(c.pluginRepository, adapters.NewPluginInspectorAdapter(), c.integrityVerifier)
+}
+
+// PluginDir returns the directory plugins are loaded from.
+func (c *Container) PluginDir(ctx context.Context) (string, error) {
+	return c.pluginResolver.ResolvePluginDir(ctx)
+}
+
+// PluginInstallService returns a service that installs plugins from the
+// plugin index into the plugin directory. index overrides registry.index.
+func (c *Container) PluginInstallService(ctx context.Context, index string) *services.PluginInstallService {
+	if index == "" {
+		index = c.systemCfg.Registry.Index
+	}
+
+	// Plugins are installed where they are loaded from: the plugin directory
+	// of the project, else the one found, else a new one in the working
+	// directory
+	pluginDir := c.pluginDir
+	if pluginDir == "" {
+		var err error
+		if pluginDir, err = c.PluginDir(ctx); err != nil {
+			pluginDir = "plugins"
+		}
+	}
+
+	return services.NewPluginInstallService(
+		pluginindex.NewSource(index, c.registryTransport),
+		c.pluginRegistry,
+		pluginindex.NewDownloader(c.registryTransport),
+		pluginrepo.NewFSPluginInstallStore(pluginDir),
+		c.integrityVerifier,
+		c.integrityService,
+		c.logger,
+	)
+}
+
+// PluginVendorService returns a service that copies the cached plugins a
+// workspace uses into vendorDir.
+func (c *Container) PluginVendorService(vendorDir string) (*services.PluginVendorService, error) {
+	vendorRepository, err := pluginrepo.NewFSPluginRepository(vendorDir)
+	if err != nil {
+		return nil, err
+	}
+	return services.NewPluginVendorService(c.pluginRepository, vendorRepository, filesystem.NewWorkspacePluginScanner()), nil
+}
+
+// PreflightService returns a service that checks a profile's setup
+// prerequisites against the system resolver and environment, and the host
+// operations of its observations against the permissions of this process.
+func (c *Container) PreflightService() *services.PreflightService {
+	newLoader := func(resolver ports.SecretResolver) ports.ProfileLoader {
+		return adapters.NewProfileLoaderAdapter(resolver)
+	}
+	svc := services.NewPreflightService(newLoader, c.secretResolver, net.DefaultResolver, os.Getenv, c.logger)
+	svc.SetHostAccessProbe(hostaccess.NewProbe())
+	return svc
+}
+
+// ProfileTestService returns a service that runs profile unit tests against
+// fixture evidence instead of real plugins.
+func (c *Container) ProfileTestService() *services.ProfileTestService {
+	return services.NewProfileTestService(
+		proftest.NewSuiteLoader(),
+		c.profileLoader,
+		domainservices.NewProfileCompiler(),
+		c.fixtureEngines,
+		c.logger,
+	)
+}
+
+// EvidenceEvaluationService returns a service that evaluates the evidence of
+// runs stored by the configured storage backend against a profile.
+func (c *Container) EvidenceEvaluationService() (*services.EvidenceEvaluationService, error) {
+	if c.resultRepository == nil {
+		return nil, errors.New("no result storage configured: set storage.backend in the system config")
+	}
+	return services.NewEvidenceEvaluationService(
+		c.resultRepository,
+		c.profileLoader,
+		domainservices.NewProfileCompiler(),
+		c.evaluationEngines,
+		c.logger,
+	), nil
+}
+
+// EnvironmentComparisonService returns a service that compares saved runs
+// of a profile across environments.
+func (c *Container) EnvironmentComparisonService() *services.EnvironmentComparisonService {
+	return services.NewEnvironmentComparisonService(jsonfile.NewResultLoader(), c.logger)
+}
+
+// ResultAnonymizationService returns a service that anonymizes results for
+// sharing, recording pseudonyms in the file at mappingPath. An empty
+// mappingPath uses anonymization.mapping_file of the system config, or
+// ~/.reglet/anonymization-mapping.json.
+func (c *Container) ResultAnonymizationService(mappingPath string) (*services.ResultAnonymizationService, error) {
+	cfg := c.systemCfg.Anonymization
+	if mappingPath == "" {
+		mappingPath = cfg.MappingFile
+	}
+	if mappingPath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate pseudonym file: %w", err)
+		}
+		mappingPath = filepath.Join(homeDir, ".reglet", "anonymization-mapping.json")
+	}
+	store, err := sensitivedata.OpenPseudonymFile(mappingPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a configured salt, pseudonyms are keyed with the salt kept in
+	// the pseudonym file, so they stay stable across reports.
+	salt := cfg.Salt
+	if salt == "" {
+		salt = store.Salt
+	}
+	rules := sensitivedata.DefaultAnonymizationRules()
+	if len(cfg.Rules) > 0 {
+		rules = make([]sensitivedata.AnonymizationRule, 0, len(cfg.Rules))
+		for _, rule := range cfg.Rules {
+			rules = append(rules, sensitivedata.AnonymizationRule{
+				Match:   rule.Match,
+				Pattern: rule.Pattern,
+				Action:  rule.Action,
+				Name:    rule.Name,
+				Fields:  rule.Fields,
+			})
+		}
+	}
+	anonymizer, err := sensitivedata.NewAnonymizer(rules, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := services.NewResultAnonymizationService(anonymizer, store, jsonfile.NewResultLoader())
+	if c.resultRepository != nil {
+		svc.SetResultRepository(c.resultRepository)
+	}
+	return svc, nil
+}
+
+// ContinuousCheckService returns a service that re-runs a profile on an
+// interval and reports status transitions.
+func (c *Container) ContinuousCheckService() *services.ContinuousCheckService {
+	return services.NewContinuousCheckService(c.logger)
+}
+
+// WatchPlugins hot-reloads the local plugins of a long-running process: runs
+// pin the plugin files they load, and a file changed on disk is used by later
+// runs only once it matches a release installed with "reglet plugins
+// install", whose signature is verified again if it was on install or the
+// integrity policy requires it. The returned function stops watching.
+func (c *Container) WatchPlugins(ctx context.Context) (func(), error) {
+	runtime, err := wasm.NewRuntime(ctx, build.Get())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create plugin watcher runtime: %w", err)
+	}
+	watcher, err := engine.NewPluginWatcher(runtime, c.verifyInstalledPlugin)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+	c.checkProfileUseCase.SetPluginReader(watcher)
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = watcher.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+		_ = runtime.Close(context.Background())
+	}, nil
+}
+
+// verifyInstalledPlugin accepts a changed plugin file if it is an installed
+// release, verifying the signature of its OCI source when required.
+func (c *Container) verifyInstalledPlugin(ctx context.Context, path string, data []byte) error {
+	release, err := pluginrepo.InstalledRelease(path, data)
+	if err != nil {
+		return err
+	}
+	if !release.SignatureVerified && !c.integrityService.ShouldVerifySignature() {
+		return nil
+	}
+	if (entities.PluginRelease{Source: release.Source}).IsURL() {
+		return fmt.Errorf("signatures can only be verified for releases pulled from OCI registries, not %s", release.Source)
+	}
+	ref, err := values.ParsePluginReference(release.Source)
+	if err != nil {
+		return err
+	}
+	result, err := c.integrityVerifier.VerifySignature(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+	c.logger.InfoContext(ctx, "plugin signature verified", "plugin", ref.String(), "signer", result.Signer)
+	return nil
+}
+
+// ResultHistoryService returns a service that exports and imports results of
+// the configured storage backend.
+func (c *Container) ResultHistoryService() (*services.ResultHistoryService, error) {
+	if c.resultRepository == nil {
+		return nil, errors.New("no result storage configured: set storage.backend in the system config")
+	}
+	hostname, _ := os.Hostname() // provenance only; left empty when unknown
+	source := execution.ArchiveSource{Hostname: hostname, RegletVersion: build.Get().Version}
+	return services.NewResultHistoryService(c.resultRepository, source), nil
+}
+
+// APIServer returns the HTTP API over stored results, for "reglet serve".
+// Requests without a namespace header work in defaultNamespace. When
+// server.auth is configured, requests must carry a token of its issuer; ctx
+// bounds the fetching of the issuer's keys. The caller closes the server to
+// stop the runs it started.
+func (c *Container) APIServer(ctx context.Context, defaultNamespace string) (*httpapi.Server, error) {
+	history, err := c.ResultHistoryService()
+	if err != nil {
+		return nil, err
+	}
+	server := httpapi.NewServer(history, defaultNamespace, c.logger)
+	server.SetPluginManager(c.pluginService)
+	server.SetPluginInstaller(c.PluginInstallService(ctx, ""))
+	if cfg := c.systemCfg.Server; len(cfg.Profiles) > 0 {
+		// Runs use saved capability grants only; nobody answers prompts on
+		// the server's console.
+		c.capGatekeeper.DisablePrompts()
+		runs := services.NewRunService(cfg.Profiles, c.runServerProfile, dto.RunQueueOptions{
+			MaxConcurrent: cfg.Queue.MaxConcurrent,
+			MaxPerProfile: cfg.Queue.MaxPerProfile,
+		}, c.logger)
+		server.SetRunService(runs)
+		for _, schedule := range cfg.Schedules {
+			namespace := schedule.Namespace
+			if namespace == "" {
+				namespace = defaultNamespace
+			}
+			err := runs.Schedule(dto.RunSchedule{
+				Profile:     schedule.Profile,
+				Environment: schedule.Environment,
+				Namespace:   namespace,
+				Interval:    schedule.Interval,
+			})
+			if err != nil {
+				server.Close()
+				return nil, fmt.Errorf("invalid server.schedules entry: %w", err)
+			}
+		}
+	} else if len(cfg.Schedules) > 0 {
+		return nil, errors.New("server.schedules requires server.profiles")
+	}
+	server.SetCapabilityPolicy(c.securityLevel, infracapabilities.NewFileStore(c.grantsPath))
+
+	if auth := c.systemCfg.Server.Auth; auth.Issuer != "" {
+		authenticator, err := httpapi.NewOIDCAuthenticator(ctx, httpapi.AuthConfig{
+			Issuer:          auth.Issuer,
+			Audience:        auth.Audience,
+			JWKSURL:         auth.JWKSURL,
+			RolesClaim:      auth.RolesClaim,
+			NamespacesClaim: auth.NamespacesClaim,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid server.auth configuration: %w", err)
+		}
+		server.SetAuthenticator(authenticator)
+	}
+	return server, nil
+}
+
+// runProfile executes a check and returns its result.
+func (c *Container) runProfile(ctx context.Context, req dto.CheckProfileRequest) (*execution.ExecutionResult, error) {
+	response, err := c.checkProfileUseCase.Execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return response.ExecutionResult, nil
+}
+
+// runServerProfile executes a check of the server and, with server.work_dir
+// set, writes it to a run directory there. Server runs share the server's
+// log, so their logs/ stays empty.
+func (c *Container) runServerProfile(ctx context.Context, req dto.CheckProfileRequest) (*execution.ExecutionResult, error) {
+	workDir := c.systemCfg.Server.WorkDir
+	if workDir == "" {
+		return c.runProfile(ctx, req)
+	}
+
+	run, err := rundir.Create(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server.work_dir: %w", err)
+	}
+	defer run.Discard() // unless committed
+	req.Execution.Hooks = append(req.Execution.Hooks, run.Hooks())
+
+	result, err := c.runProfile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := run.Commit(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write run directory: %w", err)
+	}
+	c.logger.Info("run directory written", "path", dir)
+	return result, nil
+}
+
+// DistributedCheckUseCase returns a use case running profiles across the
+// agents listed under cluster.agents, and a function closing the connections
+// to them.
+func (c *Container) DistributedCheckUseCase() (*services.DistributedCheckUseCase, func(), error) {
+	cfg := c.systemCfg.Cluster
+	if len(cfg.Agents) == 0 {
+		return nil, nil, errors.New("no agents configured: list them under cluster.agents in the system config")
+	}
+	tlsConfig, err := clusterTLSFiles(cfg.TLS).ClientConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid cluster.tls configuration: %w", err)
+	}
+
+	agents := make([]dto.AgentNode, len(cfg.Agents))
+	for i, agent := range cfg.Agents {
+		if agent.Name == "" || agent.Address == "" {
+			return nil, nil, fmt.Errorf("cluster.agents entry %d needs a name and an address", i+1)
+		}
+		agents[i] = dto.AgentNode{Name: agent.Name, Address: agent.Address, Tags: agent.Tags}
+	}
+
+	client := cluster.NewClient(tlsConfig)
+	uc := services.NewDistributedCheckUseCase(
+		adapters.NewProfileLoaderAdapter(c.secretResolver),
+		client,
+		agents,
+		build.Get().Version,
+		c.logger,
+	)
+	if c.resultRepository != nil {
+		uc.SetResultRepository(c.resultRepository)
+	}
+	return uc, func() { _ = client.Close() }, nil
+}
+
+// AgentServer returns the agent side of distributed runs, for "reglet
+// agent", and the TLS configuration to serve it with (nil = plaintext).
+// facts are reported to coordinators besides the discovered ones. Agents run
+// with saved capability grants only; nobody answers prompts on their console.
+func (c *Container) AgentServer(name string, facts []string) (*cluster.AgentServer, *tls.Config, error) {
+	tlsConfig, err := clusterTLSFiles(c.systemCfg.Cluster.TLS).ServerConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid cluster.tls configuration: %w", err)
+	}
+	c.capGatekeeper.DisablePrompts()
+	agent := cluster.NewAgentServer(name, c.runPartial, c.logger)
+	agent.SetFacts(facts)
+	return agent, tlsConfig, nil
+}
+
+// runPartial runs the controls a coordinator placed on this agent.
+func (c *Container) runPartial(ctx context.Context, req dto.PartialCheckRequest) (*execution.ExecutionResult, error) {
+	dir, err := os.MkdirTemp("", "reglet-agent-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "profile.yaml")
+	if err := os.WriteFile(path, req.Profile, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write profile: %w", err)
+	}
+	return c.runProfile(ctx, dto.CheckProfileRequest{
+		ProfilePath: path,
+		Environment: req.Environment,
+		Metadata:    dto.RequestMetadata{RequestID: req.RequestID},
+		Filters:     dto.FilterOptions{IncludeControlIDs: req.ControlIDs},
+		Execution: dto.ExecutionOptions{
+			Parallel:                  req.Parallel,
+			MaxConcurrentControls:     req.MaxConcurrentControls,
+			MaxConcurrentObservations: req.MaxConcurrentObservations,
+			MaxEvidenceSizeBytes:      req.MaxEvidenceSizeBytes,
+		},
+	})
+}
+
+func clusterTLSFiles(cfg system.ClusterTLSConfig) cluster.TLSFiles {
+	return cluster.TLSFiles{CAFile: cfg.CA, CertFile: cfg.Cert, KeyFile: cfg.Key}
+}
+
 // ProfileLoader returns the profile loader port.
 func (c *Container) ProfileLoader() ports.ProfileLoader {
 	return c.profileLoader
@@ -248,6 +833,56 @@ func (c *Container) OutputFormatterFactory() ports.OutputFormatterFactory {
 	return output.NewFormatterFactory()
 }
 
+// ResultExportService returns a service that runs result exporters, resolved
+// from the compiled-in registry or as WASM exporter plugins. Capabilities
+// declared by exporter plugins go through the same grant flow as check plugins.
+func (c *Container) ResultExportService(ctx context.Context) (*services.ResultExportService, error) {
+	pluginDir, err := c.pluginResolver.ResolvePluginDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	grant := func(required map[string][]capabilities.Capability) (map[string][]capabilities.Capability, error) {
+		return c.capOrchestrator.GrantCapabilities(required, c.trustPlugins)
+	}
+	resolver := exporter.NewResolver(exporter.Default(), pluginDir, grant)
+
+	return services.NewResultExportService(resolver, c.logger), nil
+}
+
+// registryTransport builds the HTTP transport for plugin registries from the
+// registry settings, logging in to the proxy with the named credential.
+func registryTransport(cfg system.RegistryConfig, credentials *secrets.CredentialStore) (*http.Transport, error) {
+	var proxy *url.URL
+	if cfg.Proxy != "" {
+		var err error
+		proxy, err = url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("registry.proxy: %w", err)
+		}
+	}
+	if cfg.ProxyCredential != "" {
+		if proxy == nil {
+			return nil, errors.New("registry.proxy_credential is set but registry.proxy is not")
+		}
+		login, ok, err := credentials.Get(cfg.ProxyCredential)
+		if err != nil {
+			return nil, fmt.Errorf("registry.proxy_credential: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("registry.proxy_credential: no credential named %q (see reglet credentials set)", cfg.ProxyCredential)
+		}
+		username, password, _ := strings.Cut(login, ":")
+		proxy.User = url.UserPassword(username, password)
+	}
+	return ociplugin.NewTransport(cfg.CAFile, proxy)
+}
+
+// CredentialStore returns the encrypted store of named credentials.
+func (c *Container) CredentialStore() *secrets.CredentialStore {
+	return c.credentialStore
+}
+
 // Logger returns the configured logger.
 func (c *Container) Logger() *slog.Logger {
 	return c.logger
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_SetClock_FixesResultTimes(t *testing.T) {
+	t.Parallel()
+
+	fixed := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	eng, _ := newStreamTestEngine(false)
+	eng.SetClock(func() time.Time { return fixed })
+
+	result, err := eng.Execute(context.Background(), newStreamTestProfile())
+	require.NoError(t, err)
+
+	assert.Equal(t, fixed, result.StartTime)
+	assert.Equal(t, fixed, result.EndTime)
+	assert.Zero(t, result.Duration)
+}
+
+func TestObservationExecutor_ClockDrivesThresholds(t *testing.T) {
+	t.Parallel()
+
+	fixed := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	executor := NewExecutor(nil, WithPluginDir(t.TempDir()), WithClock(func() time.Time { return fixed }))
+
+	obs := entities.ObservationDefinition{
+		Plugin:     "tcp",
+		Expect:     []string{`daysUntil(data.tls_cert_not_after) < 30`},
+		Thresholds: map[string]entities.Threshold{"tls_cert_not_after": {Min: "14d"}},
+	}
+	result := &wasm.PluginObservationResult{Evidence: &execution.Evidence{
+		Status: true,
+		Data:   map[string]interface{}{"tls_cert_not_after": "2026-01-21T00:00:00Z"},
+	}}
+
+	status, expectations := executor.determineStatusWithExpect(context.Background(), result, obs)
+	assert.Equal(t, values.StatusPass, status, "%+v", expectations)
+
+	executor.SetClock(func() time.Time { return fixed.Add(10 * 24 * time.Hour) })
+	status, _ = executor.determineStatusWithExpect(context.Background(), result, obs)
+	assert.Equal(t, values.StatusFail, status, "10 days left is below the 14d minimum")
+}
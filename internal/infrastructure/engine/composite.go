@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
+)
+
+// maxDependencyDepth bounds how deeply composite plugins may nest, which also
+// stops dependency cycles.
+const maxDependencyDepth = 4
+
+type dependencyDepthKey struct{}
+
+// describePlugin returns the metadata of a loaded plugin, or nil for plugins
+// that do not report it. It records the plugin's build in the run's
+// provenance, and fails for plugins incompatible with this host.
+func (e *ObservationExecutor) describePlugin(ctx context.Context, name string, plugin pluginObserver) (*wasm.PluginInfo, error) {
+	d, ok := plugin.(pluginDescriber)
+	if !ok {
+		return nil, nil
+	}
+	info, err := d.Describe(ctx)
+	if err != nil {
+		return nil, nil //nolint:nilerr // metadata is optional; observe() reports broken plugins
+	}
+	if err := wasm.CheckCompatibility(info, e.hostVersion); err != nil {
+		return info, err
+	}
+	if provenance, ok := execution.ProvenanceFromContext(ctx); ok {
+		provenance.RecordPlugin(execution.BuildInfo{
+			Name:       name,
+			Version:    info.Version,
+			Commit:     info.Commit,
+			BuildTime:  info.BuildTime,
+			GoVersion:  info.GoVersion,
+			SDKVersion: info.SDKVersion,
+		})
+	}
+	return info, nil
+}
+
+// withDependencies prepares ctx to run a plugin. A composite plugin gets an
+// observer for the plugins it declares as dependencies, once they are all
+// available; other plugins get none, so a dependency cannot reach the
+// dependencies of the composite running it.
+func (e *ObservationExecutor) withDependencies(ctx context.Context, name string, info *wasm.PluginInfo) (context.Context, error) {
+	if info == nil || len(info.Dependencies) == 0 {
+		return hostfuncs.WithPluginObserver(ctx, nil), nil
+	}
+	for _, dependency := range info.Dependencies {
+		if _, err := e.loadObserver(ctx, dependency); err != nil {
+			return ctx, fmt.Errorf("plugin %s depends on plugin %s, which is not available: %w", name, dependency, err)
+		}
+	}
+	return hostfuncs.WithPluginObserver(ctx, e.dependencyObserver(name, info.Dependencies)), nil
+}
+
+// dependencyObserver returns the observer a composite plugin runs its
+// dependencies with. Each dependency runs in its own instance, with its own
+// capabilities.
+func (e *ObservationExecutor) dependencyObserver(composite string, dependencies []string) hostfuncs.PluginObserver {
+	return func(ctx context.Context, name string, config map[string]interface{}) (*execution.Evidence, error) {
+		if !slices.Contains(dependencies, name) {
+			return nil, fmt.Errorf("%w: plugin %s does not depend on plugin %s", hostfuncs.ErrUndeclaredDependency, composite, name)
+		}
+		depth, _ := ctx.Value(dependencyDepthKey{}).(int)
+		if depth >= maxDependencyDepth {
+			return nil, fmt.Errorf("plugin dependencies nest deeper than %d levels, check for a dependency cycle", maxDependencyDepth)
+		}
+		ctx = context.WithValue(ctx, dependencyDepthKey{}, depth+1)
+
+		plugin, err := e.loadObserver(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		info, err := e.describePlugin(ctx, name, plugin)
+		if err != nil {
+			return nil, err
+		}
+		if ctx, err = e.withDependencies(ctx, name, info); err != nil {
+			return nil, err
+		}
+
+		result, err := plugin.Observe(ctx, wasm.Config{Values: config})
+		switch {
+		case err != nil:
+			return nil, fmt.Errorf("plugin %s failed: %w", name, err)
+		case result.Error != nil:
+			return nil, fmt.Errorf("plugin %s failed: %w", name, result.Error)
+		case result.Evidence == nil:
+			return nil, fmt.Errorf("plugin %s returned no evidence", name)
+		}
+		return result.Evidence, nil
+	}
+}
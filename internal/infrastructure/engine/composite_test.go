@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/native"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObservationExecutor_Dependencies(t *testing.T) {
+	t.Parallel()
+
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", echoPlugin{}))
+	executor := NewExecutor(nil, WithNativePlugins(registry))
+	ctx := context.Background()
+
+	plain, err := executor.withDependencies(ctx, "echo", &wasm.PluginInfo{Name: "echo"})
+	require.NoError(t, err)
+	_, ok := hostfuncs.PluginObserverFromContext(plain)
+	assert.False(t, ok, "plugins without dependencies cannot observe other plugins")
+
+	_, err = executor.withDependencies(ctx, "webapp", &wasm.PluginInfo{Name: "webapp", Dependencies: []string{"echo", "tls"}})
+	assert.ErrorContains(t, err, "plugin webapp depends on plugin tls, which is not available")
+
+	composite, err := executor.withDependencies(ctx, "webapp", &wasm.PluginInfo{Name: "webapp", Dependencies: []string{"echo"}})
+	require.NoError(t, err)
+	observe, ok := hostfuncs.PluginObserverFromContext(composite)
+	require.True(t, ok)
+
+	evidence, err := observe(composite, "echo", map[string]interface{}{"port": 443})
+	require.NoError(t, err)
+	assert.True(t, evidence.Status)
+	assert.Equal(t, float64(443), evidence.Data["port"])
+
+	_, err = observe(composite, "dns", nil)
+	require.ErrorIs(t, err, hostfuncs.ErrUndeclaredDependency)
+	assert.ErrorContains(t, err, "plugin webapp does not depend on plugin dns")
+
+	deep := context.WithValue(composite, dependencyDepthKey{}, maxDependencyDepth)
+	_, err = observe(deep, "echo", nil)
+	assert.ErrorContains(t, err, "nest deeper than")
+}
@@ -2,9 +2,8 @@
 package engine
 
 import (
-	"runtime"
-
 	"github.com/expr-lang/expr/vm"
+	"github.com/reglet-dev/reglet/internal/infrastructure/hostfacts"
 )
 
 // Concurrency constants for parallel execution.
@@ -30,25 +29,33 @@ type ExecutionConfig struct {
 	ExcludeTags       []string
 	ExcludeControlIDs []string
 
+	// PluginWeights are the shares of the control workers each plugin gets
+	// while other plugins have controls waiting; unlisted plugins weigh 1
+	PluginWeights map[string]int
+
 	MaxConcurrentControls     int
 	MaxConcurrentObservations int
 	MaxEvidenceSizeBytes      int
 
 	Parallel            bool
 	IncludeDependencies bool
+	SkipMissingPlugins  bool // mark observations of plugins that are not installed not_run
+	NoObservationDedup  bool // run identical observations of different controls separately
 }
 
-// DefaultExecutionConfig returns sensible defaults for parallel execution.
+// DefaultExecutionConfig returns sensible defaults for parallel execution,
+// sized by the CPUs the process may use rather than those of the host, so a
+// container's CPU quota or a pinned CPU set is not oversubscribed.
 func DefaultExecutionConfig() ExecutionConfig {
-	numCPU := runtime.NumCPU()
+	numCPU := hostfacts.AvailableCPUs()
 
-	// Default to NumCPU for controls, but at least MinConcurrentControls
+	// Default to the CPUs for controls, but at least MinConcurrentControls
 	maxControls := numCPU
 	if maxControls < MinConcurrentControls {
 		maxControls = MinConcurrentControls
 	}
 
-	// Observations are within a control, so we use a smaller multiple of NumCPU
+	// Observations are within a control, so we use a smaller multiple of the CPUs
 	maxObs := numCPU / 2
 	if maxObs < MinConcurrentObservations {
 		maxObs = MinConcurrentObservations
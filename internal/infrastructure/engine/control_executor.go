@@ -16,6 +16,19 @@ import (
 // executeControl executes a single control and returns its result.
 // The index parameter tracks the control's original definition order for deterministic output.
 func (e *Engine) executeControl(ctx context.Context, ctrl entities.Control, index int, execResult *execution.ExecutionResult, requiredDeps map[string]bool) execution.ControlResult {
+	for _, hooks := range e.hooks {
+		hooks.OnControlStart(ctx, newControlResult(ctrl, index))
+	}
+	result := e.runControl(ctx, ctrl, index, execResult, requiredDeps)
+	for _, hooks := range e.hooks {
+		hooks.OnControlEnd(ctx, result)
+	}
+	return result
+}
+
+// runControl checks the skip conditions of a control, then runs its
+// observations, retrying transient errors.
+func (e *Engine) runControl(ctx context.Context, ctrl entities.Control, index int, execResult *execution.ExecutionResult, requiredDeps map[string]bool) execution.ControlResult {
 	startTime := time.Now()
 	result := newControlResult(ctrl, index)
 
@@ -24,6 +37,20 @@ func (e *Engine) executeControl(ctx context.Context, ctrl entities.Control, inde
 		return skipControl(result, skipReason, startTime)
 	}
 
+	// Check the when condition against the facts of this host
+	if ctrl.When != "" {
+		holds, err := services.EvaluateCondition(ctrl.When, e.hostFacts(ctx))
+		if err != nil {
+			result.Status = values.StatusError
+			result.Message = fmt.Sprintf("when condition failed: %v", err)
+			result.Duration = time.Since(startTime)
+			return result
+		}
+		if !holds {
+			return skipControl(result, fmt.Sprintf("Skipped: when condition %q is false on this host", ctrl.When), startTime)
+		}
+	}
+
 	maxAttempts := ctrl.Retries + 1
 	var lastErr error
 
@@ -89,8 +116,11 @@ func newControlResult(ctrl entities.Control, index int) execution.ControlResult
 		ID:                 ctrl.ID,
 		Name:               ctrl.Name,
 		Description:        ctrl.Description,
+		Rationale:          ctrl.Rationale,
+		References:         ctrl.References,
 		Severity:           ctrl.Severity,
 		Tags:               ctrl.Tags,
+		Labels:             ctrl.Labels,
 		ObservationResults: make([]execution.ObservationResult, 0, len(ctrl.ObservationDefinitions)),
 	}
 }
@@ -139,31 +169,52 @@ func skipControl(result execution.ControlResult, skipReason string, startTime ti
 // runObservations executes observations sequentially or in parallel.
 func (e *Engine) runObservations(ctx context.Context, ctrl entities.Control) []execution.ObservationResult {
 	if e.config.Parallel && len(ctrl.ObservationDefinitions) > 1 {
-		return e.executeObservationsParallel(ctx, ctrl.ObservationDefinitions)
+		return e.executeObservationsParallel(ctx, ctrl)
 	}
 
 	results := make([]execution.ObservationResult, 0, len(ctrl.ObservationDefinitions))
-	for _, obs := range ctrl.ObservationDefinitions {
-		obsResult := e.executor.Execute(ctx, obs)
+	for i, obs := range ctrl.ObservationDefinitions {
+		results = append(results, e.runObservation(ctx, ctrl, i, obs))
+	}
+	return results
+}
 
-		limit := e.config.MaxEvidenceSizeBytes
-		if limit == 0 {
-			limit = execution.DefaultMaxEvidenceSize
-		}
+// runObservation executes the observation at index i of a control, with
+// its references to setup evidence resolved, and truncates its evidence to
+// the configured limit.
+func (e *Engine) runObservation(ctx context.Context, ctrl entities.Control, i int, obs entities.ObservationDefinition) execution.ObservationResult {
+	ref := execution.ObservationRef{ControlID: ctrl.ID, Plugin: obs.Plugin, Index: i}
+	for _, hooks := range e.hooks {
+		hooks.OnObservationStart(ctx, ref)
+	}
 
-		if obsResult.Evidence != nil && obsResult.Evidence.Data != nil {
-			truncated, meta, err := e.truncator.Truncate(obsResult.Evidence.Data, limit)
-			if err != nil {
-				slog.ErrorContext(ctx, "failed to truncate evidence", "error", err, "plugin", obsResult.Plugin)
-			} else if meta != nil {
-				obsResult.Evidence.Data = truncated
-				obsResult.EvidenceMeta = meta
-			}
-		}
+	outputs, _ := setupOutputsFromContext(ctx)
+	obsResult := e.observeWithSetup(execution.WithObservationRef(ctx, ref), obs, outputs)
+	e.truncateEvidence(ctx, &obsResult)
 
-		results = append(results, obsResult)
+	for _, hooks := range e.hooks {
+		hooks.OnObservationEnd(ctx, ref, obsResult)
+	}
+	return obsResult
+}
+
+// truncateEvidence truncates the evidence of an observation to the
+// configured limit.
+func (e *Engine) truncateEvidence(ctx context.Context, obsResult *execution.ObservationResult) {
+	limit := e.config.MaxEvidenceSizeBytes
+	if limit == 0 {
+		limit = execution.DefaultMaxEvidenceSize
+	}
+
+	if obsResult.Evidence != nil && obsResult.Evidence.Data != nil {
+		truncated, meta, err := e.truncator.Truncate(obsResult.Evidence.Data, limit)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to truncate evidence", "error", err, "plugin", obsResult.Plugin)
+		} else if meta != nil {
+			obsResult.Evidence.Data = truncated
+			obsResult.EvidenceMeta = meta
+		}
 	}
-	return results
 }
 
 // finalizeResult aggregates observation statuses and generates the control message.
@@ -176,6 +227,9 @@ func finalizeResult(result execution.ControlResult, startTime time.Time) executi
 	aggregator := services.NewStatusAggregator()
 	result.Status = aggregator.AggregateControlStatus(statuses)
 	result.Message = generateControlMessage(result.Status, result.ObservationResults)
+	if result.Status == values.StatusSkipped {
+		result.SkipReason = result.Message // e.g. its plugins are not installed
+	}
 	result.Duration = time.Since(startTime)
 
 	return result
@@ -195,8 +249,9 @@ func (e *Engine) shouldRun(ctrl entities.Control) (bool, string) {
 }
 
 // executeObservationsParallel executes observations in parallel with concurrency limits.
-func (e *Engine) executeObservationsParallel(ctx context.Context, observations []entities.ObservationDefinition) []execution.ObservationResult {
+func (e *Engine) executeObservationsParallel(ctx context.Context, ctrl entities.Control) []execution.ObservationResult {
 	g, ctx := errgroup.WithContext(ctx)
+	observations := ctrl.ObservationDefinitions
 
 	if e.config.MaxConcurrentObservations > 0 {
 		g.SetLimit(e.config.MaxConcurrentObservations)
@@ -207,24 +262,7 @@ func (e *Engine) executeObservationsParallel(ctx context.Context, observations [
 	for i, obs := range observations {
 		i, obs := i, obs // capture for closure
 		g.Go(func() error {
-			obsResult := e.executor.Execute(ctx, obs)
-
-			limit := e.config.MaxEvidenceSizeBytes
-			if limit == 0 {
-				limit = execution.DefaultMaxEvidenceSize
-			}
-
-			if obsResult.Evidence != nil && obsResult.Evidence.Data != nil {
-				truncated, meta, err := e.truncator.Truncate(obsResult.Evidence.Data, limit)
-				if err != nil {
-					slog.ErrorContext(ctx, "failed to truncate evidence", "error", err, "plugin", obsResult.Plugin)
-				} else if meta != nil {
-					obsResult.Evidence.Data = truncated
-					obsResult.EvidenceMeta = meta
-				}
-			}
-
-			results[i] = obsResult
+			results[i] = e.runObservation(ctx, ctrl, i, obs)
 			return nil
 		})
 	}
@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+)
+
+// observationDedup shares the plugin results of identical observations
+// within a run: the first observation with a given plugin and configuration
+// runs the plugin, the others wait for and reuse its evidence. Each
+// observation still evaluates its own expectations against the evidence.
+type observationDedup struct {
+	calls map[string]*sharedObservation
+	mu    sync.Mutex
+}
+
+// sharedObservation is the plugin call of the first of identical observations.
+type sharedObservation struct {
+	done   chan struct{}
+	result *wasm.PluginObservationResult
+	err    error
+}
+
+func newObservationDedup() *observationDedup {
+	return &observationDedup{calls: make(map[string]*sharedObservation)}
+}
+
+type observationDedupKey struct{}
+
+// withObservationDedup attaches the observation dedup of a run to the context.
+func withObservationDedup(ctx context.Context, dedup *observationDedup) context.Context {
+	return context.WithValue(ctx, observationDedupKey{}, dedup)
+}
+
+// observationDedupFromContext returns the observation dedup attached to the context, if any.
+func observationDedupFromContext(ctx context.Context) (*observationDedup, bool) {
+	dedup, ok := ctx.Value(observationDedupKey{}).(*observationDedup)
+	return dedup, ok
+}
+
+// dedupKey identifies the plugin call of an observation: its plugin and its
+// configuration in canonical JSON, which sorts map keys and writes equal
+// numbers alike, with the settings that change what the plugin sees. It
+// reports false for configurations that do not encode to JSON.
+func dedupKey(obs entities.ObservationDefinition) (string, bool) {
+	key, err := json.Marshal(struct {
+		Plugin           string                 `json:"plugin"`
+		Config           map[string]interface{} `json:"config"`
+		Env              map[string]string      `json:"env"`
+		Privileged       bool                   `json:"privileged"`
+		FreshConnections bool                   `json:"fresh_connections"`
+	}{obs.Plugin, obs.Config, obs.Env, obs.Privileged, obs.FreshConnections})
+	if err != nil {
+		return "", false
+	}
+	return string(key), true
+}
+
+// observe returns the result of the plugin call identified by key, calling
+// observe for the first observation with the key and waiting for that call
+// otherwise. Calls ended by the cancellation of their own context are not
+// shared: the next observation with the key calls the plugin again. shared
+// reports whether the result came from the call of another observation.
+// Every observation gets its own copy of the evidence, which it redacts and
+// truncates in place.
+func (d *observationDedup) observe(ctx context.Context, key string, observe func() (*wasm.PluginObservationResult, error)) (result *wasm.PluginObservationResult, shared bool, err error) {
+	for {
+		d.mu.Lock()
+		call, ok := d.calls[key]
+		if !ok {
+			call = &sharedObservation{done: make(chan struct{})}
+			d.calls[key] = call
+			d.mu.Unlock()
+
+			call.result, call.err = observe()
+			if ctx.Err() != nil {
+				d.mu.Lock()
+				delete(d.calls, key)
+				d.mu.Unlock()
+			}
+			close(call.done)
+			return copyObservationResult(call.result), false, call.err
+		}
+		d.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, false, context.Cause(ctx)
+		}
+		d.mu.Lock()
+		retry := d.calls[key] != call
+		d.mu.Unlock()
+		if retry {
+			continue
+		}
+		return copyObservationResult(call.result), true, call.err
+	}
+}
+
+// copyObservationResult copies a plugin result down to its evidence data.
+func copyObservationResult(result *wasm.PluginObservationResult) *wasm.PluginObservationResult {
+	if result == nil {
+		return nil
+	}
+	copied := *result
+	if result.Evidence != nil {
+		evidence := *result.Evidence
+		if evidence.Data != nil {
+			evidence.Data, _ = copyEvidenceValue(evidence.Data).(map[string]interface{})
+		}
+		copied.Evidence = &evidence
+	}
+	return &copied
+}
+
+// copyEvidenceValue deep-copies the maps and slices of decoded evidence.
+func copyEvidenceValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = copyEvidenceValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyEvidenceValue(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
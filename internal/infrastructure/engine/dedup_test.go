@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/native"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPlugin is an echo plugin that counts its calls.
+type countingPlugin struct {
+	echoPlugin
+	calls *atomic.Int32
+}
+
+func (p countingPlugin) Observe(ctx context.Context, config []byte) ([]byte, error) {
+	p.calls.Add(1)
+	time.Sleep(20 * time.Millisecond)
+	return p.echoPlugin.Observe(ctx, config)
+}
+
+// overlappingProfile has two controls observing port 22 with different
+// expectations, and one observing port 443.
+func overlappingProfile() *entities.Profile {
+	return &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "overlap", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "ssh-open", Name: "SSH open", ObservationDefinitions: []entities.ObservationDefinition{{
+				Plugin: "echo",
+				Config: map[string]interface{}{"host": "bastion", "port": 22},
+				Expect: []string{"data.port == 22"},
+			}}},
+			{ID: "ssh-closed", Name: "SSH closed", ObservationDefinitions: []entities.ObservationDefinition{{
+				Plugin: "echo",
+				Config: map[string]interface{}{"port": 22.0, "host": "bastion"},
+				Expect: []string{"data.port != 22"},
+			}}},
+			{ID: "https", Name: "HTTPS", ObservationDefinitions: []entities.ObservationDefinition{{
+				Plugin: "echo",
+				Config: map[string]interface{}{"host": "bastion", "port": 443},
+				Expect: []string{"data.port == 443"},
+			}}},
+		}},
+	}
+}
+
+func TestNativeEngine_DedupObservations(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", countingPlugin{calls: &calls}))
+
+	eng := NewNativeEngine(build.Get(), registry, DefaultExecutionConfig(), nil, nil, &execution.GreedyTruncator{})
+	result, err := eng.Execute(context.Background(), overlappingProfile())
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), calls.Load(), "identical observations run once")
+	assert.Equal(t, values.StatusPass, result.GetControlResultByID("ssh-open").Status)
+	assert.Equal(t, values.StatusFail, result.GetControlResultByID("ssh-closed").Status, "each control evaluates its own expectations")
+	assert.Equal(t, values.StatusPass, result.GetControlResultByID("https").Status)
+
+	shared := 0
+	for _, id := range []string{"ssh-open", "ssh-closed"} {
+		obs := result.GetControlResultByID(id).ObservationResults[0]
+		require.NotNil(t, obs.Evidence)
+		assert.InDelta(t, 22, obs.Evidence.Data["port"], 0)
+		if obs.SharedEvidence {
+			shared++
+		}
+	}
+	assert.Equal(t, 1, shared)
+	assert.False(t, result.GetControlResultByID("https").ObservationResults[0].SharedEvidence)
+}
+
+func TestNativeEngine_NoObservationDedup(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", countingPlugin{calls: &calls}))
+
+	cfg := DefaultExecutionConfig()
+	cfg.NoObservationDedup = true
+	eng := NewNativeEngine(build.Get(), registry, cfg, nil, nil, &execution.GreedyTruncator{})
+	result, err := eng.Execute(context.Background(), overlappingProfile())
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(3), calls.Load())
+	for _, ctrl := range result.Controls {
+		assert.False(t, ctrl.ObservationResults[0].SharedEvidence, ctrl.ID)
+	}
+}
+
+func TestDedupKey(t *testing.T) {
+	t.Parallel()
+
+	key := func(obs entities.ObservationDefinition) string {
+		k, ok := dedupKey(obs)
+		require.True(t, ok)
+		return k
+	}
+	base := entities.ObservationDefinition{Plugin: "tcp", Config: map[string]interface{}{"host": "a", "port": 22}}
+
+	assert.Equal(t, key(base), key(entities.ObservationDefinition{
+		Plugin: "tcp",
+		Config: map[string]interface{}{"port": 22.0, "host": "a"},
+		Expect: []string{"data.connected"},
+	}), "expectations do not change the plugin call")
+	assert.NotEqual(t, key(base), key(entities.ObservationDefinition{Plugin: "dns", Config: base.Config}))
+	assert.NotEqual(t, key(base), key(entities.ObservationDefinition{Plugin: "tcp", Config: base.Config, Env: map[string]string{"PROXY": "x"}}))
+	assert.NotEqual(t, key(base), key(entities.ObservationDefinition{Plugin: "tcp", Config: base.Config, FreshConnections: true}))
+
+	_, ok := dedupKey(entities.ObservationDefinition{Plugin: "tcp", Config: map[string]interface{}{"f": func() {}}})
+	assert.False(t, ok, "configurations that do not encode are not deduplicated")
+}
+
+func TestObservationDedup_CanceledCallNotShared(t *testing.T) {
+	t.Parallel()
+
+	dedup := newObservationDedup()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, shared, err := dedup.observe(ctx, "k", func() (*wasm.PluginObservationResult, error) { return nil, context.Canceled })
+	assert.False(t, shared)
+	require.ErrorIs(t, err, context.Canceled)
+
+	result, shared, err := dedup.observe(context.Background(), "k", func() (*wasm.PluginObservationResult, error) {
+		return &wasm.PluginObservationResult{Evidence: &execution.Evidence{Status: true}}, nil
+	})
+	require.NoError(t, err)
+	assert.False(t, shared, "the canceled call is run again")
+	assert.True(t, result.Evidence.Status)
+
+	_, shared, err = dedup.observe(context.Background(), "k", func() (*wasm.PluginObservationResult, error) {
+		t.Fatal("completed calls are shared")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.True(t, shared)
+}
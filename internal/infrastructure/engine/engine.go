@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
 	"github.com/reglet-dev/reglet/internal/domain/entities"
@@ -13,8 +15,11 @@ import (
 	"github.com/reglet-dev/reglet/internal/domain/repositories"
 	"github.com/reglet-dev/reglet/internal/domain/services"
 	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/hostfacts"
+	"github.com/reglet-dev/reglet/internal/infrastructure/native"
 	"github.com/reglet-dev/reglet/internal/infrastructure/sensitivedata"
 	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
 )
 
 // ObservationExecutable defines the interface for executing observations.
@@ -28,8 +33,20 @@ type Engine struct {
 	executor   ObservationExecutable
 	truncator  execution.TruncationStrategy
 	runtime    *wasm.Runtime
+	stream     execution.ResultStream
+	hooks      []execution.Hooks
+	policy     execution.ResultPolicy
+	severity   *services.SeverityRules
+	clock      func() time.Time
+	facts      map[string]interface{}
+	streamErr  error
 	version    build.Info
 	config     ExecutionConfig
+	streamMu   sync.Mutex
+	factsOnce  sync.Once
+	collect    bool
+	retain     bool  // keep the evidence of streamed controls
+	maxEgress  int64 // bytes plugins may send per run; 0 = unlimited
 }
 
 // CapabilityCollector collects required capabilities from plugins.
@@ -97,14 +114,17 @@ func NewEngineWithCapabilities(
 	executor := NewExecutor(runtime,
 		WithPluginDir(pluginDir),
 		WithRedactor(redactor),
+		WithHostVersion(version.Version),
 	)
-
-	// Preload plugins for schema validation
-	for _, ctrl := range profile.GetAllControls() {
-		for _, obs := range ctrl.ObservationDefinitions {
-			if _, err := executor.LoadPlugin(ctx, obs.Plugin); err != nil {
-				return nil, fmt.Errorf("failed to preload plugin %s: %w", obs.Plugin, err)
-			}
+	executor.SetSkipMissingPlugins(cfg.SkipMissingPlugins)
+
+	// Preload plugins for schema validation. With SkipMissingPlugins, the
+	// observations of plugins that are not installed are marked not_run
+	// when they execute instead
+	for _, obs := range profile.AllObservations() {
+		_, err := executor.loadObserver(ctx, obs.Plugin)
+		if err != nil && !(cfg.SkipMissingPlugins && errors.Is(err, ErrPluginNotInstalled)) {
+			return nil, fmt.Errorf("failed to preload plugin %s: %w", obs.Plugin, err)
 		}
 	}
 
@@ -125,7 +145,7 @@ func NewEngineWithConfig(ctx context.Context, version build.Info, cfg ExecutionC
 		return nil, fmt.Errorf("failed to create WASM runtime: %w", err)
 	}
 
-	executor := NewExecutor(runtime) // Auto-detect plugin dir, no redactor
+	executor := NewExecutor(runtime, WithHostVersion(version.Version)) // Auto-detect plugin dir, no redactor
 
 	return &Engine{
 		runtime:   runtime,
@@ -136,6 +156,33 @@ func NewEngineWithConfig(ctx context.Context, version build.Info, cfg ExecutionC
 	}, nil
 }
 
+// NewNativeEngine creates an engine that runs plugins in-process from the given
+// registry instead of the WASM runtime. Capabilities are not enforced; this is a
+// development mode for debugging plugins before compiling them to WASM.
+func NewNativeEngine(
+	version build.Info,
+	registry *native.Registry,
+	cfg ExecutionConfig,
+	redactor *sensitivedata.Redactor,
+	repo repositories.ExecutionResultRepository,
+	truncator execution.TruncationStrategy,
+) *Engine {
+	executor := NewExecutor(nil,
+		WithNativePlugins(registry),
+		WithRedactor(redactor),
+		WithHostVersion(version.Version),
+	)
+	executor.SetSkipMissingPlugins(cfg.SkipMissingPlugins)
+
+	return &Engine{
+		executor:   executor,
+		config:     cfg,
+		repository: repo,
+		version:    version,
+		truncator:  truncator,
+	}
+}
+
 // checkContextCancellation checks if the context has been cancelled or timed out.
 // Returns an appropriate error if cancelled, nil if still active.
 func checkContextCancellation(ctx context.Context) error {
@@ -143,7 +190,7 @@ func checkContextCancellation(ctx context.Context) error {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return fmt.Errorf("execution timed out: %w", ctx.Err())
 		}
-		return ctx.Err()
+		return context.Cause(ctx) // e.g. ErrEgressBudgetExceeded when a host function aborted the run
 	}
 	return nil
 }
@@ -155,13 +202,75 @@ func (e *Engine) Execute(ctx context.Context, profile entities.ProfileReader) (*
 		return nil, err
 	}
 
+	start := time.Now()
 	metadata := profile.GetMetadata()
 	result := execution.NewExecutionResult(metadata.Name, metadata.Version)
 	result.RegletVersion = e.version.String()
+	result.Provenance = execution.NewProvenance(execution.BuildInfo{
+		Version:   e.version.Version,
+		Commit:    e.version.Commit,
+		BuildTime: e.version.BuildDate,
+		GoVersion: e.version.GoVersion,
+		Platform:  e.version.Platform,
+	})
+	ctx = execution.WithProvenance(ctx, result.Provenance)
+	result.Environment = profile.GetEnvironment()
+	ctx = execution.WithRunRef(ctx, execution.RunRef{
+		ExecutionID: result.ExecutionID.String(),
+		Profile:     metadata.Name,
+		Environment: result.Environment,
+	})
+	if e.collect {
+		result.Mode = execution.ModeCollect
+	}
+	if e.clock != nil {
+		result.StartTime = e.clock()
+	}
+
+	if e.maxEgress > 0 {
+		var abort context.CancelCauseFunc
+		ctx, abort = context.WithCancelCause(ctx)
+		defer abort(nil)
+		ctx = wasm.WithEgressBudget(ctx, hostfuncs.NewEgressBudget(e.maxEgress), abort)
+	}
+
+	if quotas := profile.GetQuotas(); len(quotas) > 0 {
+		ctx = execution.WithPluginQuotas(ctx, execution.NewPluginQuotas(quotas))
+	}
+
+	// Setup runs once before the controls, and teardown once after them or
+	// after setup aborted the run. Neither shares plugin calls: a teardown
+	// step must run even when it is identical to a setup step
+	lifecycleCtx := ctx
+	outputs, err := e.runSetup(ctx, profile.GetSetup(), result)
+	if err != nil {
+		e.runTeardown(lifecycleCtx, profile.GetTeardown(), outputs, result)
+		return nil, err
+	}
+	ctx = withSetupOutputs(ctx, outputs)
+	tornDown := false
+	teardown := func() {
+		if !tornDown {
+			tornDown = true
+			e.runTeardown(lifecycleCtx, profile.GetTeardown(), outputs, result)
+		}
+	}
+	defer teardown()
+
+	// Identical observations of different controls share one plugin call
+	if !e.config.NoObservationDedup {
+		ctx = withObservationDedup(ctx, newObservationDedup())
+	}
+
+	if e.stream != nil {
+		e.streamErr = nil
+		if err := e.stream.Begin(result); err != nil {
+			return nil, fmt.Errorf("failed to stream execution result: %w", err)
+		}
+	}
 
 	var requiredControls map[string]bool
 	if e.config.IncludeDependencies {
-		var err error
 		requiredControls, err = e.resolveDependencies(profile)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve dependencies: %w", err)
@@ -174,6 +283,9 @@ func (e *Engine) Execute(ctx context.Context, profile entities.ProfileReader) (*
 			if errors.Is(err, context.DeadlineExceeded) {
 				return nil, fmt.Errorf("execution timed out: %w", err)
 			}
+			if cause := context.Cause(ctx); cause != nil && cause != ctx.Err() { //nolint:errorlint // distinguishes a cause from plain cancellation
+				return nil, cause
+			}
 			return nil, err
 		}
 	} else {
@@ -184,7 +296,7 @@ func (e *Engine) Execute(ctx context.Context, profile entities.ProfileReader) (*
 			}
 
 			controlResult := e.executeControl(ctx, ctrl, i, result, requiredControls)
-			result.AddControlResult(controlResult)
+			e.recordControlResult(result, controlResult)
 		}
 
 		if err := checkContextCancellation(ctx); err != nil {
@@ -192,7 +304,26 @@ func (e *Engine) Execute(ctx context.Context, profile entities.ProfileReader) (*
 		}
 	}
 
-	result.Finalize()
+	if e.policy != nil {
+		if err := e.applyPolicy(ctx, result, len(allControls)); err != nil {
+			return nil, err
+		}
+	}
+
+	teardown()
+	e.finalize(result)
+
+	// Attached before the stream ends so streamed results carry it too
+	if recorder, ok := execution.PerfRecorderFromContext(ctx); ok {
+		recorder.RecordPhase(execution.PhaseExecution, time.Since(start))
+		result.Performance = recorder.Report()
+	}
+
+	if e.stream != nil {
+		if err := e.finishStream(result); err != nil {
+			return nil, fmt.Errorf("failed to stream execution result: %w", err)
+		}
+	}
 
 	if e.repository != nil {
 		if err := e.repository.Save(ctx, result); err != nil {
@@ -206,6 +337,277 @@ func (e *Engine) Execute(ctx context.Context, profile entities.ProfileReader) (*
 	return result, nil
 }
 
+// SetResultStream enables incremental output. Each control is written to the
+// stream as soon as it completes, and only a compacted copy (without evidence)
+// is retained in the returned ExecutionResult, unless the result is saved to a
+// repository, evaluated by a policy or retained with SetRetainEvidence.
+func (e *Engine) SetResultStream(stream execution.ResultStream) {
+	e.stream = stream
+}
+
+// SetRetainEvidence keeps the full evidence of streamed controls in the
+// returned ExecutionResult, for consumers of the finished result such as
+// exporters.
+func (e *Engine) SetRetainEvidence(retain bool) {
+	e.retain = retain
+}
+
+// AddHooks registers hooks called as controls and observations start and
+// end. Hooks run in the order they were added.
+func (e *Engine) AddHooks(hooks execution.Hooks) {
+	e.hooks = append(e.hooks, hooks)
+}
+
+// SetResultPolicy sets a policy evaluated over the result before it is
+// finalized. Its findings are recorded as additional failing controls.
+func (e *Engine) SetResultPolicy(policy execution.ResultPolicy) {
+	e.policy = policy
+}
+
+// SetSeverityRules sets rules that assign severities to controls as they
+// complete, before they are streamed or recorded.
+func (e *Engine) SetSeverityRules(rules *services.SeverityRules) {
+	e.severity = rules
+}
+
+// SetClock fixes the time the engine reports: result timestamps, the wall
+// time plugins see (including the time_now host function) and the reference
+// time of thresholds and time functions in expressions. Control and
+// observation durations are still measured. nil restores the system clock.
+func (e *Engine) SetClock(clock func() time.Time) {
+	e.clock = clock
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetClock(clock)
+	}
+}
+
+// SetCassette records the host calls and file reads of WASM plugins into
+// cassette, or replays them from it, depending on its mode. Native plugins
+// are not affected.
+func (e *Engine) SetCassette(cassette *hostfuncs.Cassette) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetCassette(cassette)
+	}
+}
+
+// SetDNSCache replaces the cache that DNS lookups of WASM plugins are
+// answered from. Each engine starts with its own TTL-respecting cache, so
+// names are resolved once per run; nil disables caching.
+func (e *Engine) SetDNSCache(cache hostfuncs.DNSCache) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetDNSCache(cache)
+	}
+}
+
+// SetHTTPPool replaces the pool that HTTP requests of WASM plugins reuse
+// connections from. Each engine starts with its own pool, so connections are
+// kept alive across the observations of a run; nil disables pooling.
+func (e *Engine) SetHTTPPool(pool *hostfuncs.HTTPPool) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetHTTPPool(pool)
+	}
+}
+
+// SetHTTPCache makes GET requests of WASM plugins answer from cache, for as
+// long as the responses' Cache-Control max-age allows, so controls checking
+// the same endpoint share one request. Engines start without one.
+func (e *Engine) SetHTTPCache(cache *hostfuncs.HTTPCache) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetHTTPCache(cache)
+	}
+}
+
+// SetCircuitBreaker replaces the breaker that fails network calls of WASM
+// plugins fast with a target_unreachable error once their destination has
+// timed out repeatedly. Each engine starts with its own breaker using the
+// default threshold and cooldown; nil disables it.
+func (e *Engine) SetCircuitBreaker(breaker *hostfuncs.CircuitBreaker) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetCircuitBreaker(breaker)
+	}
+}
+
+// SetDebugHTTP makes every WASM observation that fails or errors log a
+// sanitized summary of its HTTP exchanges: curl commands without header
+// values or query values, with status, timing and response header names.
+// Observations marked debug log them regardless.
+func (e *Engine) SetDebugHTTP(debug bool) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetDebugHTTP(debug)
+	}
+}
+
+// SetMaxEgress limits the request bytes WASM plugins may send through
+// network host functions in each run. The call that would pass the limit
+// fails and the run is aborted with hostfuncs.ErrEgressBudgetExceeded.
+// 0 removes the limit.
+func (e *Engine) SetMaxEgress(bytes int64) {
+	e.maxEgress = bytes
+}
+
+// SetScratchLimit sets the size limit in bytes of the scratch directory WASM
+// plugins get for each observation. 0 keeps the default; a negative limit
+// disables scratch directories.
+func (e *Engine) SetScratchLimit(limit int64) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetScratchLimit(limit)
+	}
+}
+
+// SetSymlinkPolicy sets how WASM plugins may follow symlinks in the host
+// paths they use, through their filesystem mounts and host file calls.
+func (e *Engine) SetSymlinkPolicy(policy capabilities.SymlinkPolicy) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetSymlinkPolicy(policy)
+	}
+}
+
+// SetPathHardening makes WASM plugins fail, with a warning, file access
+// through paths that are not canonical (".." traversal), or that resolve
+// outside their granted tree or across a mount point in it (bind mounts).
+func (e *Engine) SetPathHardening(enabled bool) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetPathHardening(enabled)
+	}
+}
+
+// SetProcessPlugins sets the external process plugins trusted to run. Other
+// process plugin executables are never started.
+func (e *Engine) SetProcessPlugins(names []string) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetProcessPlugins(names)
+	}
+}
+
+// SetPluginRegistry resolves the plugin aliases observations use to the
+// plugins they declare. Only native plugins need it: WASM plugins are
+// installed under their alias.
+func (e *Engine) SetPluginRegistry(registry *entities.PluginRegistry) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetPluginRegistry(registry)
+	}
+}
+
+// SetFaultInjector makes host calls of WASM plugins fail according to
+// injector, to test profiles under partial outages. Native plugins are not
+// affected.
+func (e *Engine) SetFaultInjector(injector *hostfuncs.FaultInjector) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetFaultInjector(injector)
+	}
+}
+
+// SetCollectOnly runs observations without evaluating their expectations:
+// results record the raw evidence, to be evaluated later.
+func (e *Engine) SetCollectOnly(collectOnly bool) {
+	e.collect = collectOnly
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetCollectOnly(collectOnly)
+	}
+}
+
+// SetFacts sets the host facts control when conditions are evaluated
+// against, instead of collecting them from this host on first use.
+func (e *Engine) SetFacts(facts map[string]interface{}) {
+	e.factsOnce.Do(func() {})
+	e.facts = facts
+}
+
+// hostFacts returns the host facts, collecting them on first use.
+func (e *Engine) hostFacts(ctx context.Context) map[string]interface{} {
+	e.factsOnce.Do(func() {
+		e.facts = hostfacts.Collect(ctx).Map()
+	})
+	return e.facts
+}
+
+// SetAllowSudo lets privileged observations read the files this process is
+// denied access to with "sudo -n reglet privileged-read". Without it,
+// privileged observations fail unless reglet runs as root. Native plugins
+// are not affected.
+func (e *Engine) SetAllowSudo(allow bool) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		var reader wasm.PrivilegedReader
+		if allow {
+			reader = wasm.SudoRead
+		}
+		executor.SetPrivilegedReader(reader)
+	}
+}
+
+// SetPIIScanner flags likely personal data in the evidence of every
+// observation, and redacts it if the scanner is configured to.
+func (e *Engine) SetPIIScanner(scanner *sensitivedata.PIIScanner) {
+	if executor, ok := e.executor.(*ObservationExecutor); ok {
+		executor.SetPIIScanner(scanner)
+	}
+}
+
+// finalize completes the result, ending it at the engine's clock time.
+func (e *Engine) finalize(result *execution.ExecutionResult) {
+	if e.clock == nil {
+		result.Finalize()
+		return
+	}
+	result.FinalizeAt(e.clock())
+}
+
+// applyPolicy evaluates the result policy and records its findings after the
+// profile's controls, starting at definition index firstIndex.
+func (e *Engine) applyPolicy(ctx context.Context, result *execution.ExecutionResult, firstIndex int) error {
+	// Give the policy sorted controls and a current summary to work with
+	e.finalize(result)
+
+	findings, err := e.policy.Evaluate(ctx, result)
+	if err != nil {
+		return fmt.Errorf("result policy evaluation failed: %w", err)
+	}
+
+	for i, finding := range findings {
+		e.recordControlResult(result, finding.ControlResult(firstIndex+i))
+	}
+	return nil
+}
+
+// recordControlResult adds a completed control to the execution result,
+// writing it to the result stream first when streaming is enabled.
+// Thread-safe for concurrent calls from worker pool goroutines.
+func (e *Engine) recordControlResult(result *execution.ExecutionResult, cr execution.ControlResult) {
+	if e.severity != nil {
+		e.severity.Apply(&cr)
+	}
+
+	if e.stream != nil {
+		if err := e.stream.WriteControl(cr); err != nil {
+			e.streamMu.Lock()
+			if e.streamErr == nil {
+				e.streamErr = err
+			}
+			e.streamMu.Unlock()
+		}
+	}
+
+	// Streaming bounds memory unless something still needs the evidence
+	// once the run is over
+	if e.stream == nil || e.retain || e.repository != nil || e.policy != nil {
+		result.AddControlResult(cr)
+		return
+	}
+	result.AddControlResult(cr.Compact())
+}
+
+// finishStream closes the result stream and reports the first write error, if any.
+func (e *Engine) finishStream(result *execution.ExecutionResult) error {
+	e.streamMu.Lock()
+	streamErr := e.streamErr
+	e.streamMu.Unlock()
+
+	if streamErr != nil {
+		return streamErr
+	}
+	return e.stream.End(result)
+}
+
 // resolveDependencies calculates the transitive closure of dependencies for matched controls.
 func (e *Engine) resolveDependencies(profile entities.ProfileReader) (map[string]bool, error) {
 	resolver := services.NewDependencyResolver()
@@ -233,11 +635,15 @@ func (e *Engine) resolveDependencies(profile entities.ProfileReader) (map[string
 }
 
 // Runtime returns the WASM runtime for accessing plugin schemas.
+// It is nil for engines running native plugins.
 func (e *Engine) Runtime() *wasm.Runtime {
 	return e.runtime
 }
 
 // Close closes the engine and releases resources.
 func (e *Engine) Close(ctx context.Context) error {
+	if e.runtime == nil {
+		return nil
+	}
 	return e.runtime.Close(ctx)
 }
@@ -129,6 +129,7 @@ func TestExecuteControl_SingleObservation(t *testing.T) {
 		Description: "A test control",
 		Severity:    "medium",
 		Tags:        []string{"test"},
+		Labels:      map[string]string{"cmdb_id": "CI0042"},
 		ObservationDefinitions: []entities.ObservationDefinition{
 			{
 				Plugin: "file",
@@ -150,6 +151,7 @@ func TestExecuteControl_SingleObservation(t *testing.T) {
 	assert.Equal(t, "A test control", result.Description)
 	assert.Equal(t, "medium", result.Severity)
 	assert.Equal(t, []string{"test"}, result.Tags)
+	assert.Equal(t, map[string]string{"cmdb_id": "CI0042"}, result.Labels)
 	assert.Len(t, result.ObservationResults, 1)
 	assert.Greater(t, result.Duration, time.Duration(0))
 	assert.NotEmpty(t, result.Message)
@@ -2,25 +2,109 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
 	"github.com/reglet-dev/reglet/internal/domain/entities"
 	"github.com/reglet-dev/reglet/internal/domain/execution"
 	"github.com/reglet-dev/reglet/internal/domain/services"
 	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/native"
+	"github.com/reglet-dev/reglet/internal/infrastructure/process"
 	"github.com/reglet-dev/reglet/internal/infrastructure/sensitivedata"
 	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
 )
 
+// ErrPluginNotInstalled is returned when an observation's plugin is neither
+// installed in the plugin directory nor registered as a native plugin.
+var ErrPluginNotInstalled = errors.New("plugin not installed")
+
+// CodePluginNotInstalled is the error code of observations not run because
+// their plugin is not installed.
+const CodePluginNotInstalled = "plugin_not_installed"
+
 // ObservationExecutor executes observations using WASM plugins.
 type ObservationExecutor struct {
 	runtime        *wasm.Runtime
 	redactor       *sensitivedata.Redactor
+	pii            *sensitivedata.PIIScanner
+	privileged     wasm.PrivilegedReader
 	pluginRegistry *entities.PluginRegistry
+	nativePlugins  *native.Registry
+	clock          func() time.Time
+	cassette       *hostfuncs.Cassette
+	faults         *hostfuncs.FaultInjector
+	described      *sync.Map // *wasm.PluginInfo of native and process plugins, by name
 	pluginDir      string
+	hostVersion    string
+	collectOnly    bool
+
+	scratchLimit int64 // bytes; 0 = default, negative = no scratch directory
+
+	symlinks    capabilities.SymlinkPolicy // how plugin paths may follow symlinks; empty = restrict to the granted tree
+	hardenPaths bool                       // deny plugin paths whose canonical form leaves their granted scope
+
+	processPlugins []string // external process plugins trusted to run
+
+	dnsCache  hostfuncs.DNSCache   // shared by the dns_lookup calls of the run; nil = no caching
+	httpPool  *hostfuncs.HTTPPool  // connections shared by the http_request calls of the run; nil = no pooling
+	httpCache *hostfuncs.HTTPCache // responses shared by the http_request calls of the run; nil = no caching
+
+	breaker *hostfuncs.CircuitBreaker // fails network calls fast to destinations that keep timing out; nil = none
+
+	debugHTTP bool // log the HTTP exchanges of every failed observation, not only of those marked debug
+
+	skipMissing bool // mark observations of plugins that are not installed not_run instead of errored
+}
+
+// pluginObserver runs observations for a loaded plugin.
+// *wasm.Plugin satisfies it, as does nativeObserver for native and external
+// process plugins.
+type pluginObserver interface {
+	Observe(ctx context.Context, cfg wasm.Config) (*wasm.PluginObservationResult, error)
+}
+
+// pluginDescriber is implemented by observers that report plugin metadata.
+type pluginDescriber interface {
+	Describe(ctx context.Context) (*wasm.PluginInfo, error)
+}
+
+// nativeObserver adapts an in-process or external process plugin to pluginObserver.
+type nativeObserver struct {
+	plugin    native.Plugin
+	described *sync.Map
+	name      string
+}
+
+func (o nativeObserver) Observe(ctx context.Context, cfg wasm.Config) (*wasm.PluginObservationResult, error) {
+	return native.Observe(ctx, o.name, o.plugin, cfg)
+}
+
+// Describe returns the plugin metadata, asking the plugin only once: each
+// describe call of a process plugin starts a process.
+func (o nativeObserver) Describe(ctx context.Context) (*wasm.PluginInfo, error) {
+	if info, ok := o.described.Load(o.name); ok {
+		return info.(*wasm.PluginInfo), nil
+	}
+	data, err := o.plugin.Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, err := wasm.ParsePluginInfo(data)
+	if err != nil {
+		return nil, err
+	}
+	o.described.Store(o.name, info)
+	return info, nil
 }
 
 // ExecutorOption configures an ObservationExecutor.
@@ -45,7 +129,11 @@ type ExecutorOption func(*ObservationExecutor)
 //	)
 func NewExecutor(runtime *wasm.Runtime, opts ...ExecutorOption) *ObservationExecutor {
 	e := &ObservationExecutor{
-		runtime: runtime,
+		runtime:   runtime,
+		described: &sync.Map{},
+		dnsCache:  hostfuncs.NewDNSCache(hostfuncs.DefaultDNSCacheMaxTTL),
+		httpPool:  hostfuncs.NewHTTPPool(),
+		breaker:   hostfuncs.NewCircuitBreaker(hostfuncs.DefaultCircuitThreshold, hostfuncs.DefaultCircuitCooldown),
 	}
 
 	// Apply options
@@ -68,6 +156,14 @@ func WithPluginDir(dir string) ExecutorOption {
 	}
 }
 
+// WithHostVersion sets the reglet version plugins are checked against for
+// compatibility. Without it, minimum host versions are not checked.
+func WithHostVersion(version string) ExecutorOption {
+	return func(e *ObservationExecutor) {
+		e.hostVersion = version
+	}
+}
+
 // WithRedactor enables secret redaction.
 func WithRedactor(redactor *sensitivedata.Redactor) ExecutorOption {
 	return func(e *ObservationExecutor) {
@@ -82,6 +178,37 @@ func WithPluginRegistry(registry *entities.PluginRegistry) ExecutorOption {
 	}
 }
 
+// WithNativePlugins runs plugins in-process from the given registry instead of
+// loading WASM modules. Intended for plugin development only: native plugins
+// are not sandboxed.
+func WithNativePlugins(registry *native.Registry) ExecutorOption {
+	return func(e *ObservationExecutor) {
+		e.nativePlugins = registry
+	}
+}
+
+// WithClock makes plugins and time-relative checks (thresholds, now() and
+// daysUntil() in expressions) read time from clock instead of the system clock.
+func WithClock(clock func() time.Time) ExecutorOption {
+	return func(e *ObservationExecutor) {
+		e.clock = clock
+	}
+}
+
+// WithCassette records plugin host calls into, or replays them from, cassette.
+func WithCassette(cassette *hostfuncs.Cassette) ExecutorOption {
+	return func(e *ObservationExecutor) {
+		e.cassette = cassette
+	}
+}
+
+// WithFaultInjector makes plugin host calls fail according to injector.
+func WithFaultInjector(injector *hostfuncs.FaultInjector) ExecutorOption {
+	return func(e *ObservationExecutor) {
+		e.faults = injector
+	}
+}
+
 // autoDetectPluginDir attempts to find the plugin directory.
 func autoDetectPluginDir() string {
 	// 1. Check Env Var (Best for production binaries)
@@ -93,6 +220,99 @@ func autoDetectPluginDir() string {
 	return filepath.Join(projectRoot, "plugins")
 }
 
+// SetClock sets the clock used by plugins and time-relative checks (nil = system time).
+func (e *ObservationExecutor) SetClock(clock func() time.Time) {
+	e.clock = clock
+}
+
+// SetCassette sets the cassette plugin host calls are recorded into or
+// replayed from (nil = live calls).
+func (e *ObservationExecutor) SetCassette(cassette *hostfuncs.Cassette) {
+	e.cassette = cassette
+}
+
+// SetScratchLimit sets the size limit of the scratch directory WASM plugins
+// get per observation (0 = default, negative = none).
+func (e *ObservationExecutor) SetScratchLimit(limit int64) {
+	e.scratchLimit = limit
+}
+
+// SetSymlinkPolicy sets how the file paths of WASM plugins may follow
+// symlinks (empty = restrict to the granted tree).
+func (e *ObservationExecutor) SetSymlinkPolicy(policy capabilities.SymlinkPolicy) {
+	e.symlinks = policy
+}
+
+// SetPathHardening makes WASM plugins fail file access through paths that
+// are not canonical, or that resolve outside their granted tree or across a
+// mount point in it.
+func (e *ObservationExecutor) SetPathHardening(enabled bool) {
+	e.hardenPaths = enabled
+}
+
+// SetProcessPlugins sets the external process plugins trusted to run.
+func (e *ObservationExecutor) SetProcessPlugins(names []string) {
+	e.processPlugins = names
+}
+
+// SetSkipMissingPlugins marks the observations of plugins that are not
+// installed not_run, with the reason, instead of errored.
+func (e *ObservationExecutor) SetSkipMissingPlugins(skip bool) {
+	e.skipMissing = skip
+}
+
+// SetDNSCache sets the cache plugin DNS lookups are answered from (nil = none).
+func (e *ObservationExecutor) SetDNSCache(cache hostfuncs.DNSCache) {
+	e.dnsCache = cache
+}
+
+// SetHTTPPool sets the pool plugin HTTP requests reuse connections from (nil = none).
+func (e *ObservationExecutor) SetHTTPPool(pool *hostfuncs.HTTPPool) {
+	e.httpPool = pool
+}
+
+// SetHTTPCache sets the cache plugin GET requests are answered from (nil = none).
+func (e *ObservationExecutor) SetHTTPCache(cache *hostfuncs.HTTPCache) {
+	e.httpCache = cache
+}
+
+// SetCircuitBreaker sets the breaker that fails plugin network calls fast to
+// destinations that keep timing out (nil = none).
+func (e *ObservationExecutor) SetCircuitBreaker(breaker *hostfuncs.CircuitBreaker) {
+	e.breaker = breaker
+}
+
+// SetDebugHTTP makes every failed or errored observation log its HTTP
+// exchanges, as observations marked debug always do.
+func (e *ObservationExecutor) SetDebugHTTP(debug bool) {
+	e.debugHTTP = debug
+}
+
+// SetFaultInjector sets the injector that makes plugin host calls fail (nil = none).
+func (e *ObservationExecutor) SetFaultInjector(injector *hostfuncs.FaultInjector) {
+	e.faults = injector
+}
+
+// SetCollectOnly makes observations report the evidence as collected
+// without evaluating expect expressions and thresholds. Plugin errors are
+// still reported as errors.
+func (e *ObservationExecutor) SetCollectOnly(collectOnly bool) {
+	e.collectOnly = collectOnly
+}
+
+// SetPIIScanner makes observations flag likely personal data in their
+// evidence, and redact it if the scanner is configured to (nil = no scan).
+func (e *ObservationExecutor) SetPIIScanner(scanner *sensitivedata.PIIScanner) {
+	e.pii = scanner
+}
+
+// SetPrivilegedReader lets privileged observations read files this process
+// is denied access to through reader (nil = privileged observations fail
+// unless reglet runs as root).
+func (e *ObservationExecutor) SetPrivilegedReader(reader wasm.PrivilegedReader) {
+	e.privileged = reader
+}
+
 // SetPluginRegistry sets the plugin registry for alias resolution.
 func (e *ObservationExecutor) SetPluginRegistry(registry *entities.PluginRegistry) {
 	e.pluginRegistry = registry
@@ -127,14 +347,87 @@ func findProjectRoot() string {
 func (e *ObservationExecutor) Execute(ctx context.Context, obs entities.ObservationDefinition) execution.ObservationResult {
 	startTime := time.Now()
 
+	// Expose the observation's env block to the plugin instance (capability-filtered)
+	ctx = wasm.WithObservationEnv(ctx, obs.Env)
+	ctx = wasm.WithClock(ctx, e.clock)
+	ctx = wasm.WithCassette(ctx, e.cassette)
+	ctx = wasm.WithFaultInjector(ctx, e.faults)
+	ctx = wasm.WithScratchLimit(ctx, e.scratchLimit)
+	ctx = wasm.WithSymlinkPolicy(ctx, e.symlinks)
+	if e.hardenPaths {
+		ctx = wasm.WithPathHardening(ctx)
+	}
+	ctx = wasm.WithDNSCache(ctx, e.dnsCache)
+	ctx = wasm.WithHTTPPool(ctx, e.httpPool)
+	ctx = wasm.WithHTTPCache(ctx, e.httpCache)
+	ctx = wasm.WithCircuitBreaker(ctx, e.breaker)
+	if obs.FreshConnections {
+		ctx = wasm.WithFreshConnections(ctx)
+	}
+
 	result := execution.ObservationResult{
 		Plugin:   obs.Plugin,
 		Config:   obs.Config,
 		Duration: 0,
 	}
 
+	if e.debugHTTP || obs.Debug {
+		debugLog := &hostfuncs.HTTPDebugLog{}
+		ctx = wasm.WithHTTPDebugLog(ctx, debugLog)
+		defer func() { logHTTPDebug(ctx, result, debugLog) }()
+	}
+
+	// Privileged observations escalate denied file reads, only when allowed
+	if obs.Privileged && os.Geteuid() != 0 {
+		if e.privileged == nil {
+			result.Status = values.StatusError
+			result.Error = &wasm.PluginError{
+				Code:    "privileges_required",
+				Message: "observation is privileged: run with --allow-sudo to read the files it needs through sudo, or run reglet as root",
+			}
+			return result
+		}
+		ctx = wasm.WithPrivilegedReader(ctx, e.privileged)
+	}
+
+	// Plugins that used up a quota are not run again; the others are
+	// stopped once they use one up
+	if quotas, ok := execution.PluginQuotasFromContext(ctx); ok {
+		remaining, err := quotas.Start(obs.Plugin)
+		if err != nil {
+			result.Status = values.StatusError
+			result.Error = &wasm.PluginError{
+				Code:    execution.CodeQuotaExceeded,
+				Message: err.Error(),
+			}
+			result.RawError = err
+			return result
+		}
+		defer func() { quotas.Finish(obs.Plugin, time.Since(startTime)) }()
+
+		var abort context.CancelCauseFunc
+		ctx, abort = context.WithCancelCause(ctx)
+		defer abort(nil)
+		ctx = wasm.WithQuotaAbort(ctx, abort)
+		if remaining > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeoutCause(ctx, remaining,
+				fmt.Errorf("%w: plugin %s used its runtime", execution.ErrQuotaExceeded, obs.Plugin))
+			defer cancel()
+		}
+	}
+
 	// Load the plugin
-	plugin, err := e.LoadPlugin(ctx, obs.Plugin)
+	plugin, err := e.loadObserver(ctx, obs.Plugin)
+	if err != nil && e.skipMissing && errors.Is(err, ErrPluginNotInstalled) {
+		result.Status = values.StatusNotRun
+		result.Error = &wasm.PluginError{
+			Code:    CodePluginNotInstalled,
+			Message: fmt.Sprintf("not run: plugin %s is not installed (--skip-missing-plugins)", obs.Plugin),
+		}
+		result.Duration = time.Since(startTime)
+		return result
+	}
 	if err != nil {
 		result.Status = values.StatusError
 		result.Error = &wasm.PluginError{
@@ -146,6 +439,44 @@ func (e *ObservationExecutor) Execute(ctx context.Context, obs entities.Observat
 		return result
 	}
 
+	// Record the plugin version so reloads show up as version transitions in
+	// results, and refuse plugins built for another host or SDK
+	info, err := e.describePlugin(ctx, obs.Plugin, plugin)
+	if info != nil {
+		result.PluginVersion = info.Version
+	}
+	if err != nil {
+		result.Status = values.StatusError
+		result.Error = &wasm.PluginError{
+			Code:    "plugin_incompatible",
+			Message: err.Error(),
+		}
+		result.RawError = err
+		result.Duration = time.Since(startTime)
+		return result
+	}
+
+	// Composite plugins run the plugins they depend on, which must be available
+	ctx, err = e.withDependencies(ctx, obs.Plugin, info)
+	if err != nil {
+		result.Status = values.StatusError
+		result.Error = &wasm.PluginError{
+			Code:    "plugin_dependency_missing",
+			Message: err.Error(),
+		}
+		result.RawError = err
+		result.Duration = time.Since(startTime)
+		return result
+	}
+
+	// Side-effecting plugins get a key to deduplicate their actions with
+	if info != nil && info.SideEffects {
+		run, _ := execution.RunRefFromContext(ctx)
+		ref, _ := execution.ObservationRefFromContext(ctx)
+		result.IdempotencyKey = execution.IdempotencyKey(run, ref, obs.Config)
+		ctx = execution.WithIdempotencyKey(ctx, result.IdempotencyKey)
+	}
+
 	// Convert observation config to WASM config
 	// Pass config values directly without type conversion to preserve types (int, bool, etc.)
 	wasmConfig := wasm.Config{
@@ -153,7 +484,28 @@ func (e *ObservationExecutor) Execute(ctx context.Context, obs entities.Observat
 	}
 
 	// Execute the observation
-	wasmResult, err := plugin.Observe(ctx, wasmConfig)
+	recorder, profiling := execution.PerfRecorderFromContext(ctx)
+	var ioTimer hostfuncs.IOTimer
+	if profiling {
+		ctx = wasm.WithIOTimer(ctx, &ioTimer)
+	}
+	observeStart := time.Now()
+	wasmResult, shared, err := e.observe(ctx, plugin, obs, info, wasmConfig)
+	result.SharedEvidence = shared
+	if profiling && !shared {
+		result.Timing = observationTiming(time.Since(observeStart), wasmResult, ioTimer.Elapsed())
+		recorder.RecordObservation(obs.Plugin, *result.Timing)
+	}
+	if cause := context.Cause(ctx); errors.Is(cause, execution.ErrQuotaExceeded) {
+		result.Status = values.StatusError
+		result.Error = &wasm.PluginError{
+			Code:    execution.CodeQuotaExceeded,
+			Message: cause.Error(),
+		}
+		result.RawError = cause
+		result.Duration = time.Since(startTime)
+		return result
+	}
 	if err != nil {
 		result.Status = values.StatusError
 		result.Error = &wasm.PluginError{
@@ -182,9 +534,20 @@ func (e *ObservationExecutor) Execute(ctx context.Context, obs entities.Observat
 		result.Evidence = wasmResult.Evidence // Set the full Evidence from wasmResult
 
 		// Determine status based on top-level Evidence.Status and expect expressions
-		status, expectations := e.determineStatusWithExpect(ctx, wasmResult, obs.Expect)
-		result.Status = status
-		result.Expectations = expectations
+		switch {
+		case !e.collectOnly:
+			result.Status, result.Expectations = e.determineStatusWithExpect(ctx, wasmResult, obs)
+		case wasmResult.Evidence.Error != nil:
+			result.Status = values.StatusError
+		default:
+			result.Status = values.StatusCollected
+		}
+
+		// Setup steps keep their evidence unredacted for the observations
+		// it is wired into
+		if capture, ok := evidenceCaptureFromContext(ctx); ok && wasmResult.Evidence.Data != nil {
+			capture.data, _ = copyEvidenceValue(wasmResult.Evidence.Data).(map[string]interface{})
+		}
 
 		// Redact sensitive data from evidence before returning/storing it
 		if e.redactor != nil && wasmResult.Evidence.Data != nil {
@@ -193,6 +556,11 @@ func (e *ObservationExecutor) Execute(ctx context.Context, obs entities.Observat
 				wasmResult.Evidence.Data = asMap
 			}
 		}
+		if e.pii != nil && wasmResult.Evidence.Data != nil {
+			if findings := e.pii.Scan(wasmResult.Evidence.Data); len(findings) > 0 {
+				result.PII = findings
+			}
+		}
 
 		// If the Evidence itself contains an error, propagate it to ObservationResult.Error
 		if wasmResult.Evidence.Error != nil {
@@ -213,6 +581,127 @@ func (e *ObservationExecutor) Execute(ctx context.Context, obs entities.Observat
 	return result
 }
 
+// observe calls the plugin with the observation's configuration. Within a
+// run deduplicating observations, identical observations of other controls
+// share one call, except those of side-effecting plugins, whose actions are
+// keyed per observation, and those logging their HTTP exchanges. shared
+// reports whether the result came from another observation's call.
+func (e *ObservationExecutor) observe(ctx context.Context, plugin pluginObserver, obs entities.ObservationDefinition, info *wasm.PluginInfo, config wasm.Config) (*wasm.PluginObservationResult, bool, error) {
+	dedup, ok := observationDedupFromContext(ctx)
+	if !ok || (info != nil && info.SideEffects) || e.debugHTTP || obs.Debug {
+		result, err := plugin.Observe(ctx, config)
+		return result, false, err
+	}
+	key, ok := dedupKey(obs)
+	if !ok {
+		result, err := plugin.Observe(ctx, config)
+		return result, false, err
+	}
+	result, shared, err := dedup.observe(ctx, key, func() (*wasm.PluginObservationResult, error) {
+		return plugin.Observe(ctx, config)
+	})
+	if shared {
+		slog.DebugContext(ctx, "reusing the evidence of an identical observation", "plugin", obs.Plugin)
+	}
+	return result, shared, err
+}
+
+// observationTiming splits the duration of a plugin call into instantiation,
+// host I/O and the plugin's own execution.
+func observationTiming(total time.Duration, result *wasm.PluginObservationResult, hostIO time.Duration) *execution.ObservationTiming {
+	timing := &execution.ObservationTiming{HostIO: hostIO}
+	if result != nil {
+		timing.Instantiation = result.Instantiation
+	}
+	timing.Execution = max(total-timing.Instantiation-timing.HostIO, 0)
+	return timing
+}
+
+// loadObserver resolves the plugin that will run an observation: from the
+// native registry in native plugin mode, otherwise its WASM module, falling
+// back to an external process plugin when no module is installed.
+func (e *ObservationExecutor) loadObserver(ctx context.Context, pluginName string) (pluginObserver, error) {
+	resolvedName := pluginName
+	if e.pluginRegistry != nil {
+		resolvedName = e.pluginRegistry.Resolve(pluginName).PluginName()
+	}
+
+	if e.nativePlugins != nil {
+		for _, name := range []string{pluginName, resolvedName} {
+			if p, ok := e.nativePlugins.Lookup(name); ok {
+				return nativeObserver{name: name, plugin: p, described: e.described}, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: no native plugin registered for %q (registered: %v)", ErrPluginNotInstalled, pluginName, e.nativePlugins.Names())
+	}
+
+	p, err := e.processPlugin(ctx, pluginName, resolvedName)
+	if err != nil {
+		return nil, err
+	}
+	if p != nil {
+		return nativeObserver{name: resolvedName, plugin: p, described: e.described}, nil
+	}
+	return e.LoadPlugin(ctx, pluginName)
+}
+
+// processPlugin returns the external process plugin for resolvedName, or nil
+// if the plugin is not installed as one. A WASM module takes precedence when
+// both exist. Granted capabilities are keyed by the name used in the profile.
+// Process plugins the user has not trusted are an error.
+func (e *ObservationExecutor) processPlugin(ctx context.Context, pluginName, resolvedName string) (*process.Plugin, error) {
+	if e.runtime != nil {
+		if _, ok := e.runtime.GetPlugin(pluginName); ok {
+			return nil, nil
+		}
+	}
+
+	validName, err := values.NewPluginName(resolvedName)
+	if err != nil {
+		return nil, nil
+	}
+	safeName := validName.String()
+	pluginDir := filepath.Join(e.pluginDir, safeName)
+
+	if _, err := os.Stat(filepath.Join(pluginDir, safeName+".wasm")); err == nil {
+		return nil, nil
+	}
+	execPath := filepath.Join(pluginDir, validName.ProcessExecutable())
+	info, err := os.Stat(execPath)
+	if err != nil || info.IsDir() || info.Mode().Perm()&0o111 == 0 {
+		return nil, nil
+	}
+	if err := process.CheckTrusted(safeName, e.processPlugins); err != nil {
+		return nil, err
+	}
+
+	var granted []capabilities.Capability
+	if e.runtime != nil {
+		granted = e.runtime.GrantedCapabilities(pluginName)
+	}
+	// Observation env is appended after the granted host variables so it
+	// overrides them for the same key. An env:observation:KEY grant passes
+	// only the profile's value, never the host's.
+	sandbox := process.NewSandboxProfile(granted, os.Environ())
+	obsEnv := wasm.ObservationEnvFromContext(ctx)
+	keys := make([]string, 0, len(obsEnv))
+	for key := range obsEnv {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch {
+		case capabilities.LoaderEnvironmentVariable(key):
+			slog.WarnContext(ctx, "observation env variable controls program loading, skipping", "plugin", pluginName, "key", key)
+		case !capabilities.ObservationEnvGranted(key, granted):
+			slog.WarnContext(ctx, "observation env variable not granted, skipping", "plugin", pluginName, "key", key)
+		default:
+			sandbox.Env = append(sandbox.Env, key+"="+obsEnv[key])
+		}
+	}
+	return process.NewPlugin(safeName, execPath, sandbox), nil
+}
+
 // LoadPlugin loads a plugin by name or alias.
 // If a plugin registry is set, aliases are resolved to their actual plugin names.
 // Phase 1b loads from file system. Phase 2 will use embedded plugins.
@@ -249,6 +738,9 @@ func (e *ObservationExecutor) LoadPlugin(ctx context.Context, pluginName string)
 	// Read the WASM file
 	//nolint:gosec // G304: pluginPath is constructed from validated pluginName (alphanumeric only)
 	wasmBytes, err := os.ReadFile(pluginPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: failed to read plugin %s: %w (expected at %s)", ErrPluginNotInstalled, resolvedName, err, pluginPath)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read plugin %s: %w (expected at %s)", resolvedName, err, pluginPath)
 	}
@@ -257,8 +749,13 @@ func (e *ObservationExecutor) LoadPlugin(ctx context.Context, pluginName string)
 	return e.runtime.LoadPlugin(ctx, pluginName, wasmBytes)
 }
 
-// determineStatusWithExpect determines the observation status by evaluating expect expressions.
-func (e *ObservationExecutor) determineStatusWithExpect(ctx context.Context, wasmResult *wasm.PluginObservationResult, expects []string) (values.Status, []execution.ExpectationResult) {
-	aggregator := services.NewStatusAggregator()
-	return aggregator.DetermineObservationStatus(ctx, wasmResult.Evidence, expects)
+// determineStatusWithExpect determines the observation status by evaluating
+// expect expressions and thresholds.
+func (e *ObservationExecutor) determineStatusWithExpect(ctx context.Context, wasmResult *wasm.PluginObservationResult, obs entities.ObservationDefinition) (values.Status, []execution.ExpectationResult) {
+	if e.clock == nil {
+		aggregator := services.NewStatusAggregator()
+		return aggregator.EvaluateObservation(ctx, wasmResult.Evidence, obs.Expect, obs.Thresholds, time.Now())
+	}
+	aggregator := services.NewStatusAggregatorWithClock(e.clock)
+	return aggregator.EvaluateObservation(ctx, wasmResult.Evidence, obs.Expect, obs.Thresholds, e.clock())
 }
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// fairQueue holds the controls that are ready to run, queued by plugin, and
+// hands them out so that plugins share the worker pool by weight: the next
+// control comes from the plugin holding the fewest workers for its weight,
+// ties going round-robin. A plugin with nothing else ready may use every
+// worker, so a slow plugin only holds back others while it is ahead of them.
+// It is owned by the coordinator goroutine and not safe for concurrent use.
+type fairQueue struct {
+	queues  map[string][]readyControl
+	running map[string]int
+	weights map[string]int
+	now     func() time.Time
+	order   []string // plugins in the order their first control became ready
+	next    int      // position in order the round-robin resumes from
+	size    int
+}
+
+// readyControl is a control waiting for a worker.
+type readyControl struct {
+	since time.Time
+	id    string
+}
+
+// newFairQueue creates a queue sharing workers by weights, keyed by plugin
+// name. Plugins without a weight, or with a weight below 1, weigh 1.
+func newFairQueue(weights map[string]int) *fairQueue {
+	return &fairQueue{
+		queues:  make(map[string][]readyControl),
+		running: make(map[string]int),
+		weights: weights,
+		now:     time.Now,
+	}
+}
+
+// schedulingPlugin returns the plugin a control is scheduled under: the
+// plugin of its first observation, or "" when it has none.
+func schedulingPlugin(ctrl entities.Control) string {
+	if len(ctrl.ObservationDefinitions) == 0 {
+		return ""
+	}
+	return ctrl.ObservationDefinitions[0].Plugin
+}
+
+// weight returns the share of workers plugin is entitled to.
+func (q *fairQueue) weight(plugin string) int {
+	if w := q.weights[plugin]; w > 0 {
+		return w
+	}
+	return 1
+}
+
+// push queues a ready control of plugin.
+func (q *fairQueue) push(plugin, id string) {
+	if _, seen := q.queues[plugin]; !seen {
+		q.order = append(q.order, plugin)
+	}
+	q.queues[plugin] = append(q.queues[plugin], readyControl{id: id, since: q.now()})
+	q.size++
+}
+
+// pop takes the next control to run and counts it as running until done is
+// called for its plugin. It returns how long the control waited in the
+// queue, and false when no control is ready.
+func (q *fairQueue) pop() (plugin, id string, wait time.Duration, ok bool) {
+	if q.size == 0 {
+		return "", "", 0, false
+	}
+
+	best := -1
+	for i := range q.order {
+		pos := (q.next + i) % len(q.order)
+		candidate := q.order[pos]
+		if len(q.queues[candidate]) == 0 {
+			continue
+		}
+		// running/weight < best's running/weight, without dividing
+		if best < 0 || q.running[candidate]*q.weight(q.order[best]) < q.running[q.order[best]]*q.weight(candidate) {
+			best = pos
+		}
+	}
+
+	plugin = q.order[best]
+	ctrl := q.queues[plugin][0]
+	q.queues[plugin] = q.queues[plugin][1:]
+	q.size--
+	q.running[plugin]++
+	q.next = (best + 1) % len(q.order)
+	return plugin, ctrl.id, q.now().Sub(ctrl.since), true
+}
+
+// done releases the worker held by a control of plugin.
+func (q *fairQueue) done(plugin string) {
+	if q.running[plugin] > 0 {
+		q.running[plugin]--
+	}
+}
@@ -0,0 +1,171 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/native"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// popAll pops n controls, returning their IDs.
+func popAll(t *testing.T, q *fairQueue, n int) []string {
+	t.Helper()
+	var ids []string
+	for i := 0; i < n; i++ {
+		_, id, _, ok := q.pop()
+		require.True(t, ok)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestFairQueue_RoundRobin(t *testing.T) {
+	q := newFairQueue(nil)
+	for _, id := range []string{"h1", "h2", "h3", "h4"} {
+		q.push("http", id)
+	}
+	q.push("file", "f1")
+	q.push("file", "f2")
+	q.push("dns", "d1")
+
+	assert.Equal(t, []string{"h1", "f1", "d1", "h2", "f2", "h3", "h4"}, popAll(t, q, 7))
+	_, _, _, ok := q.pop()
+	assert.False(t, ok)
+}
+
+func TestFairQueue_BusyPluginYields(t *testing.T) {
+	q := newFairQueue(nil)
+	q.push("http", "h1")
+	q.push("http", "h2")
+	assert.Equal(t, []string{"h1", "h2"}, popAll(t, q, 2), "a plugin alone may use every worker")
+
+	q.push("http", "h3")
+	q.push("file", "f1")
+	q.push("file", "f2")
+	assert.Equal(t, []string{"f1", "f2", "h3"}, popAll(t, q, 3),
+		"file runs until it holds as many workers as http")
+
+	q.done("http")
+	q.done("http")
+	q.push("http", "h4")
+	q.push("file", "f3")
+	assert.Equal(t, []string{"h4", "f3"}, popAll(t, q, 2))
+}
+
+func TestFairQueue_Weights(t *testing.T) {
+	q := newFairQueue(map[string]int{"file": 2})
+	for _, id := range []string{"h1", "h2", "h3"} {
+		q.push("http", id)
+	}
+	for _, id := range []string{"f1", "f2", "f3", "f4"} {
+		q.push("file", id)
+	}
+
+	assert.Equal(t, []string{"h1", "f1", "f2", "h2", "f3", "f4"}, popAll(t, q, 6))
+	assert.Equal(t, 2, q.weight("file"))
+	assert.Equal(t, 1, q.weight("http"))
+}
+
+func TestFairQueue_WaitTime(t *testing.T) {
+	q := newFairQueue(nil)
+	now := time.Unix(0, 0)
+	q.now = func() time.Time { return now }
+
+	q.push("http", "h1")
+	now = now.Add(3 * time.Second)
+	plugin, id, wait, ok := q.pop()
+	require.True(t, ok)
+	assert.Equal(t, "http", plugin)
+	assert.Equal(t, "h1", id)
+	assert.Equal(t, 3*time.Second, wait)
+}
+
+// blockingPlugin is a native plugin whose observations wait for release.
+type blockingPlugin struct{ release chan struct{} }
+
+func (blockingPlugin) Describe(_ context.Context) ([]byte, error) {
+	return []byte(`{"Name":"slow"}`), nil
+}
+func (blockingPlugin) Schema(_ context.Context) ([]byte, error) { return []byte(`{}`), nil }
+func (p blockingPlugin) Observe(ctx context.Context, _ []byte) ([]byte, error) {
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+	}
+	return []byte(`{"Status":true,"Data":{}}`), nil
+}
+
+// signalingPlugin is a native plugin that reports each observation on ran.
+type signalingPlugin struct{ ran chan struct{} }
+
+func (signalingPlugin) Describe(_ context.Context) ([]byte, error) {
+	return []byte(`{"Name":"fast"}`), nil
+}
+func (signalingPlugin) Schema(_ context.Context) ([]byte, error) { return []byte(`{}`), nil }
+func (p signalingPlugin) Observe(_ context.Context, _ []byte) ([]byte, error) {
+	p.ran <- struct{}{}
+	return []byte(`{"Status":true,"Data":{}}`), nil
+}
+
+func TestWorkerPool_SlowPluginDoesNotStarveOthers(t *testing.T) {
+	t.Parallel()
+
+	slow := blockingPlugin{release: make(chan struct{})}
+	fast := signalingPlugin{ran: make(chan struct{}, 2)}
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("slow", slow))
+	require.NoError(t, registry.Register("fast", fast))
+
+	// The slow controls come first and would take both workers in order
+	var controls []entities.Control
+	for _, id := range []string{"slow-1", "slow-2", "slow-3", "slow-4", "fast-1", "fast-2"} {
+		plugin := id[:4]
+		controls = append(controls, entities.Control{ID: id, Name: id, ObservationDefinitions: []entities.ObservationDefinition{{
+			Plugin: plugin,
+			Config: map[string]interface{}{"control": id}, // distinct, so every observation calls its plugin
+		}}})
+	}
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "fairness", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: controls},
+	}
+
+	cfg := DefaultExecutionConfig()
+	cfg.MaxConcurrentControls = 2
+	eng := NewNativeEngine(build.Get(), registry, cfg, nil, nil, &execution.GreedyTruncator{})
+
+	recorder := execution.NewPerfRecorder()
+	done := make(chan *execution.ExecutionResult, 1)
+	go func() {
+		result, err := eng.Execute(execution.WithPerfRecorder(context.Background(), recorder), profile)
+		assert.NoError(t, err)
+		done <- result
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-fast.ran:
+		case <-time.After(10 * time.Second):
+			close(slow.release)
+			t.Fatal("fast controls starved behind the slow plugin")
+		}
+	}
+	close(slow.release)
+	result := <-done
+
+	require.NotNil(t, result.Performance)
+	stats := result.Performance.Scheduling
+	require.Len(t, stats, 2)
+	assert.Equal(t, "fast", stats[0].Plugin)
+	assert.Equal(t, 2, stats[0].Controls)
+	assert.Equal(t, 1, stats[0].PeakWorkers)
+	assert.Equal(t, "slow", stats[1].Plugin)
+	assert.Equal(t, 4, stats[1].Controls)
+	assert.Equal(t, 2, stats[1].PeakWorkers, "slow uses both workers once fast is done")
+}
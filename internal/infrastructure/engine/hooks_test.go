@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/native"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHooks records the hook calls as "event id status" lines.
+type recordingHooks struct {
+	events []string
+	mu     sync.Mutex
+}
+
+func (h *recordingHooks) record(format string, args ...interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, fmt.Sprintf(format, args...))
+}
+
+func (h *recordingHooks) OnControlStart(_ context.Context, control execution.ControlResult) {
+	h.record("control-start %s", control.ID)
+}
+
+func (h *recordingHooks) OnControlEnd(_ context.Context, control execution.ControlResult) {
+	h.record("control-end %s %s", control.ID, control.Status)
+}
+
+func (h *recordingHooks) OnObservationStart(_ context.Context, ref execution.ObservationRef) {
+	h.record("observation-start %s/%d", ref.ControlID, ref.Index)
+}
+
+func (h *recordingHooks) OnObservationEnd(_ context.Context, ref execution.ObservationRef, result execution.ObservationResult) {
+	h.record("observation-end %s/%d %s", ref.ControlID, ref.Index, result.Status)
+}
+
+func hooksProfile() *entities.Profile {
+	return &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "hooks", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "ssh", Name: "SSH", ObservationDefinitions: []entities.ObservationDefinition{
+				{Plugin: "echo", Config: map[string]interface{}{"port": 22}, Expect: []string{"data.port == 22"}},
+				{Plugin: "echo", Config: map[string]interface{}{"port": 23}, Expect: []string{"data.port == 22"}},
+			}},
+			{ID: "dependent", Name: "Dependent", DependsOn: []string{"ssh"}, ObservationDefinitions: []entities.ObservationDefinition{
+				{Plugin: "echo"},
+			}},
+		}},
+	}
+}
+
+func TestEngine_Hooks(t *testing.T) {
+	t.Parallel()
+
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", echoPlugin{}))
+	cfg := DefaultExecutionConfig()
+	cfg.Parallel = false
+	eng := NewNativeEngine(build.Get(), registry, cfg, nil, nil, &execution.GreedyTruncator{})
+
+	hooks := &recordingHooks{}
+	eng.AddHooks(hooks)
+	eng.AddHooks(execution.NopHooks{})
+
+	_, err := eng.Execute(context.Background(), hooksProfile())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"control-start ssh",
+		"observation-start ssh/0",
+		"observation-end ssh/0 pass",
+		"observation-start ssh/1",
+		"observation-end ssh/1 fail",
+		"control-end ssh fail",
+		"control-start dependent",
+		"control-end dependent skipped",
+	}, hooks.events)
+}
+
+func TestEngine_HooksParallel(t *testing.T) {
+	t.Parallel()
+
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", echoPlugin{}))
+	cfg := DefaultExecutionConfig()
+	cfg.Parallel = true
+	eng := NewNativeEngine(build.Get(), registry, cfg, nil, nil, &execution.GreedyTruncator{})
+
+	hooks := &recordingHooks{}
+	eng.AddHooks(hooks)
+
+	_, err := eng.Execute(context.Background(), hooksProfile())
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"control-start ssh",
+		"observation-start ssh/0",
+		"observation-end ssh/0 pass",
+		"observation-start ssh/1",
+		"observation-end ssh/1 fail",
+		"control-end ssh fail",
+		"control-start dependent",
+		"control-end dependent skipped",
+	}, hooks.events)
+}
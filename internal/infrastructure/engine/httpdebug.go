@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
+)
+
+// logHTTPDebug logs the HTTP exchanges of an observation that failed or
+// errored, as curl commands with their outcome. Passing observations are not
+// logged.
+func logHTTPDebug(ctx context.Context, result execution.ObservationResult, log *hostfuncs.HTTPDebugLog) {
+	if result.Status != values.StatusFail && result.Status != values.StatusError {
+		return
+	}
+	exchanges, dropped := log.Exchanges()
+	ref, _ := execution.ObservationRefFromContext(ctx)
+	for i, exchange := range exchanges {
+		attrs := []any{
+			"control", ref.ControlID,
+			"observation", ref.Index,
+			"plugin", result.Plugin,
+			"exchange", i + 1,
+			"curl", exchange.Curl(),
+			"duration", exchange.Duration.Round(time.Millisecond),
+		}
+		if exchange.Error != "" {
+			attrs = append(attrs, "error", exchange.Error)
+		} else {
+			attrs = append(attrs, "status", exchange.Status, "response_headers", strings.Join(exchange.ResponseHeaders, ","))
+		}
+		if exchange.Cached {
+			attrs = append(attrs, "cached", true)
+		}
+		slog.InfoContext(ctx, "http exchange of "+string(result.Status)+" observation", attrs...)
+	}
+	if dropped > 0 {
+		slog.InfoContext(ctx, "more http exchanges not shown", "control", ref.ControlID, "observation", ref.Index, "count", dropped)
+	}
+}
@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// CodeSetupReference is the error code of observations whose reference to
+// setup evidence does not resolve.
+const CodeSetupReference = "setup_reference"
+
+// setupOutputs holds the unredacted evidence data of the setup steps of a
+// run, by step ID, for the observations that reference it.
+type setupOutputs map[string]map[string]interface{}
+
+type setupOutputsKey struct{}
+
+// withSetupOutputs attaches the setup evidence of a run to the context.
+func withSetupOutputs(ctx context.Context, outputs setupOutputs) context.Context {
+	return context.WithValue(ctx, setupOutputsKey{}, outputs)
+}
+
+// setupOutputsFromContext returns the setup evidence attached to the context, if any.
+func setupOutputsFromContext(ctx context.Context) (setupOutputs, bool) {
+	outputs, ok := ctx.Value(setupOutputsKey{}).(setupOutputs)
+	return outputs, ok
+}
+
+// evidenceCapture receives the evidence data of an observation before it
+// is redacted.
+type evidenceCapture struct {
+	data map[string]interface{}
+}
+
+type evidenceCaptureKey struct{}
+
+// withEvidenceCapture asks the executor to keep the unredacted evidence
+// data of the observation run with the context.
+func withEvidenceCapture(ctx context.Context, capture *evidenceCapture) context.Context {
+	return context.WithValue(ctx, evidenceCaptureKey{}, capture)
+}
+
+// evidenceCaptureFromContext returns the evidence capture attached to the context, if any.
+func evidenceCaptureFromContext(ctx context.Context) (*evidenceCapture, bool) {
+	capture, ok := ctx.Value(evidenceCaptureKey{}).(*evidenceCapture)
+	return capture, ok
+}
+
+// runSetup runs the setup steps in order and records their results. Each
+// step sees the evidence of the steps before it. The first step that does
+// not pass (or, collecting evidence, that errors) stops the setup: the run
+// is aborted with an error naming it.
+func (e *Engine) runSetup(ctx context.Context, steps []entities.LifecycleStep, result *execution.ExecutionResult) (setupOutputs, error) {
+	outputs := make(setupOutputs, len(steps))
+	for _, step := range steps {
+		if err := checkContextCancellation(ctx); err != nil {
+			return outputs, err
+		}
+
+		capture := &evidenceCapture{}
+		stepResult := e.runStep(withEvidenceCapture(ctx, capture), step, outputs)
+		result.Setup = append(result.Setup, stepResult)
+		if !stepSucceeded(stepResult.Status) {
+			return outputs, fmt.Errorf("setup step %s failed: %s", step.ID, stepFailure(stepResult))
+		}
+		outputs[step.ID] = capture.data
+	}
+	return outputs, nil
+}
+
+// runTeardown runs every teardown step, even after others failed, and
+// records their results. It runs to completion when the run was canceled:
+// teardown releases what setup acquired. Failures are logged, not returned,
+// as the controls already have their results.
+func (e *Engine) runTeardown(ctx context.Context, steps []entities.LifecycleStep, outputs setupOutputs, result *execution.ExecutionResult) {
+	ctx = context.WithoutCancel(ctx)
+	for _, step := range steps {
+		stepResult := e.runStep(ctx, step, outputs)
+		result.Teardown = append(result.Teardown, stepResult)
+		if !stepSucceeded(stepResult.Status) {
+			slog.WarnContext(ctx, "teardown step failed", "step", step.ID, "plugin", step.Plugin, "reason", stepFailure(stepResult))
+		}
+	}
+}
+
+// runStep runs the observation of a setup or teardown step with the setup
+// evidence wired into its config and env.
+func (e *Engine) runStep(ctx context.Context, step entities.LifecycleStep, outputs setupOutputs) execution.StepResult {
+	obsResult := e.observeWithSetup(ctx, step.ObservationDefinition, outputs)
+	e.truncateEvidence(ctx, &obsResult)
+	return execution.StepResult{ID: step.ID, ObservationResult: obsResult}
+}
+
+// observeWithSetup runs the observation with its references to setup
+// evidence resolved. The result keeps the configuration as written, so
+// tokens obtained by setup stay out of reports.
+func (e *Engine) observeWithSetup(ctx context.Context, obs entities.ObservationDefinition, outputs setupOutputs) execution.ObservationResult {
+	if len(obs.SetupReferences()) == 0 {
+		return e.executor.Execute(ctx, obs)
+	}
+
+	wired, err := outputs.wire(obs)
+	if err != nil {
+		return execution.ObservationResult{
+			Plugin: obs.Plugin,
+			Config: obs.Config,
+			Status: values.StatusError,
+			Error: &execution.PluginError{
+				Code:    CodeSetupReference,
+				Message: err.Error(),
+			},
+			RawError: err,
+		}
+	}
+	obsResult := e.executor.Execute(ctx, wired)
+	obsResult.Config = obs.Config
+	return obsResult
+}
+
+// stepSucceeded reports whether a step status lets the run go on.
+func stepSucceeded(status values.Status) bool {
+	return status == values.StatusPass || status == values.StatusCollected
+}
+
+// stepFailure describes why a step did not succeed.
+func stepFailure(step execution.StepResult) string {
+	if step.Error != nil {
+		return fmt.Sprintf("%s: %s", step.Error.Code, step.Error.Message)
+	}
+	for _, exp := range step.Expectations {
+		if !exp.Passed {
+			return "expectation failed: " + exp.Expression
+		}
+	}
+	return "status " + string(step.Status)
+}
+
+// wire returns a copy of obs with the references to setup evidence in its
+// config and env replaced by the values they point to.
+func (o setupOutputs) wire(obs entities.ObservationDefinition) (entities.ObservationDefinition, error) {
+	config, err := o.wireValue(obs.Config)
+	if err != nil {
+		return obs, err
+	}
+	obs.Config, _ = config.(map[string]interface{})
+
+	if obs.Env != nil {
+		env := make(map[string]string, len(obs.Env))
+		for key, value := range obs.Env {
+			wired, err := o.wireString(value)
+			if err != nil {
+				return obs, fmt.Errorf("env %s: %w", key, err)
+			}
+			env[key] = wired
+		}
+		obs.Env = env
+	}
+	return obs, nil
+}
+
+// wireValue resolves the references in every string of a config value,
+// copying maps and slices so the profile's config is left untouched.
+func (o setupOutputs) wireValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return o.wireString(v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			wired, err := o.wireValue(elem)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", key, err)
+			}
+			out[key] = wired
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			wired, err := o.wireValue(elem)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			out[i] = wired
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// wireString replaces the references to setup evidence in s.
+func (o setupOutputs) wireString(s string) (string, error) {
+	var lastErr error
+	wired := entities.SetupRefPattern.ReplaceAllStringFunc(s, func(match string) string {
+		submatches := entities.SetupRefPattern.FindStringSubmatch(match)
+		value, err := o.lookup(submatches[1], submatches[2])
+		if err != nil {
+			lastErr = err
+			return match
+		}
+		return value
+	})
+	if lastErr != nil {
+		return "", lastErr
+	}
+	return wired, nil
+}
+
+// lookup returns the field at path in the evidence of setup step id.
+func (o setupOutputs) lookup(id, path string) (string, error) {
+	data, ok := o[id]
+	if !ok {
+		return "", fmt.Errorf("setup step %s has no evidence", id)
+	}
+	current := interface{}(data)
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("setup.%s.%s: %s is not an object", id, path, strings.Join(parts[:i], "."))
+		}
+		if current, ok = m[part]; !ok {
+			return "", fmt.Errorf("setup.%s.%s: field not found in the evidence of setup step %s", id, path, id)
+		}
+	}
+	return fmt.Sprintf("%v", current), nil
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/native"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lifecycleProfile obtains a token in setup, checks it in a control and
+// releases it in teardown.
+func lifecycleProfile(setupExpect string) *entities.Profile {
+	return &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "lifecycle", Version: "1.0.0"},
+		Setup: []entities.LifecycleStep{{ID: "auth", ObservationDefinition: entities.ObservationDefinition{
+			Plugin: "echo",
+			Config: map[string]interface{}{"token": "s3cr3t", "session": map[string]interface{}{"port": 8443}},
+			Expect: []string{setupExpect},
+		}}},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "api", Name: "API", ObservationDefinitions: []entities.ObservationDefinition{{
+				Plugin: "echo",
+				Config: map[string]interface{}{
+					"header": "Bearer {{ .setup.auth.token }}",
+					"port":   "{{ .setup.auth.session.port }}",
+				},
+				Expect: []string{`data.header == "Bearer s3cr3t"`, `data.port == "8443"`},
+			}}},
+		}},
+		Teardown: []entities.LifecycleStep{{ID: "logout", ObservationDefinition: entities.ObservationDefinition{
+			Plugin: "echo",
+			Config: map[string]interface{}{"revoke": "{{ .setup.auth.token }}"},
+		}}},
+	}
+}
+
+func TestNativeEngine_SetupTeardown(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", countingPlugin{calls: &calls}))
+
+	eng := NewNativeEngine(build.Get(), registry, DefaultExecutionConfig(), nil, nil, &execution.GreedyTruncator{})
+	profile := lifecycleProfile("data.token != ''")
+	result, err := eng.Execute(context.Background(), profile)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(3), calls.Load(), "setup and teardown run once")
+	require.Len(t, result.Setup, 1)
+	assert.Equal(t, "auth", result.Setup[0].ID)
+	assert.Equal(t, values.StatusPass, result.Setup[0].Status)
+
+	api := result.GetControlResultByID("api")
+	assert.Equal(t, values.StatusPass, api.Status, "setup evidence is wired into the control")
+	assert.Equal(t, "Bearer {{ .setup.auth.token }}", api.ObservationResults[0].Config["header"], "results keep the config as written")
+	assert.Equal(t, "Bearer {{ .setup.auth.token }}", profile.Controls.Items[0].ObservationDefinitions[0].Config["header"], "the profile is not modified")
+
+	require.Len(t, result.Teardown, 1)
+	assert.Equal(t, "logout", result.Teardown[0].ID)
+	assert.Equal(t, "s3cr3t", result.Teardown[0].Evidence.Data["revoke"])
+}
+
+func TestNativeEngine_SetupFailureAborts(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", countingPlugin{calls: &calls}))
+
+	eng := NewNativeEngine(build.Get(), registry, DefaultExecutionConfig(), nil, nil, &execution.GreedyTruncator{})
+	profile := lifecycleProfile("data.token == ''")
+	profile.Teardown[0].Config = map[string]interface{}{"cleanup": true}
+
+	_, err := eng.Execute(context.Background(), profile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "setup step auth failed")
+	assert.Equal(t, int32(2), calls.Load(), "the controls do not run, teardown does")
+}
+
+func TestSetupOutputs_Wire(t *testing.T) {
+	t.Parallel()
+
+	outputs := setupOutputs{"auth": {"token": "abc", "nested": map[string]interface{}{"n": 3.0}}}
+	obs := entities.ObservationDefinition{
+		Plugin: "http",
+		Config: map[string]interface{}{
+			"headers": []interface{}{"X-Token: {{ .setup.auth.token }}"},
+			"count":   "{{.setup.auth.nested.n}}",
+		},
+		Env: map[string]string{"TOKEN": "{{ .setup.auth.token }}"},
+	}
+
+	wired, err := outputs.wire(obs)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"X-Token: abc"}, wired.Config["headers"])
+	assert.Equal(t, "3", wired.Config["count"])
+	assert.Equal(t, "abc", wired.Env["TOKEN"])
+	assert.Equal(t, "{{ .setup.auth.token }}", obs.Env["TOKEN"])
+
+	_, err = outputs.wire(entities.ObservationDefinition{Config: map[string]interface{}{"x": "{{ .setup.auth.missing }}"}})
+	require.ErrorContains(t, err, "field not found")
+	_, err = outputs.wire(entities.ObservationDefinition{Config: map[string]interface{}{"x": "{{ .setup.other.token }}"}})
+	require.ErrorContains(t, err, "setup step other has no evidence")
+}
@@ -46,8 +46,19 @@ func generateControlMessage(status values.Status, observations []execution.Obser
 		return fmt.Sprintf("%d checks encountered errors", errorCount)
 
 	case values.StatusSkipped:
+		for _, obs := range observations {
+			if obs.Status == values.StatusNotRun && obs.Error != nil {
+				return obs.Error.Message
+			}
+		}
 		return "Skipped due to failed dependency"
 
+	case values.StatusCollected:
+		if len(observations) == 1 {
+			return "Evidence collected"
+		}
+		return fmt.Sprintf("Evidence collected from %d checks", len(observations))
+
 	default:
 		return "Unknown status"
 	}
@@ -0,0 +1,308 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/native"
+	"github.com/reglet-dev/reglet/internal/infrastructure/sensitivedata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoPlugin is a native plugin that reports its config back as evidence.
+type echoPlugin struct{}
+
+func (echoPlugin) Describe(_ context.Context) ([]byte, error) { return []byte(`{"Name":"echo"}`), nil }
+func (echoPlugin) Schema(_ context.Context) ([]byte, error)   { return []byte(`{}`), nil }
+func (echoPlugin) Observe(_ context.Context, config []byte) ([]byte, error) {
+	return []byte(`{"Status":true,"Data":` + string(config) + `}`), nil
+}
+
+func TestNativeEngine_Execute(t *testing.T) {
+	t.Parallel()
+
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", echoPlugin{}))
+
+	eng := NewNativeEngine(build.Get(), registry, DefaultExecutionConfig(), nil, nil, &execution.GreedyTruncator{})
+	assert.Nil(t, eng.Runtime())
+
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "native", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "pass", Name: "Pass", ObservationDefinitions: []entities.ObservationDefinition{{
+				Plugin: "echo",
+				Config: map[string]interface{}{"port": 22},
+				Expect: []string{"data.port == 22"},
+			}}},
+			{ID: "missing", Name: "Missing", ObservationDefinitions: []entities.ObservationDefinition{{
+				Plugin: "not-registered",
+			}}},
+		}},
+	}
+
+	result, err := eng.Execute(context.Background(), profile)
+	require.NoError(t, err)
+	require.NoError(t, eng.Close(context.Background()))
+
+	require.Len(t, result.Controls, 2)
+	assert.Equal(t, values.StatusPass, result.GetControlResultByID("pass").Status)
+
+	missing := result.GetControlResultByID("missing")
+	assert.Equal(t, values.StatusError, missing.Status)
+	require.NotNil(t, missing.ObservationResults[0].Error)
+	assert.Contains(t, missing.ObservationResults[0].Error.Message, "no native plugin registered")
+}
+
+func TestNativeEngine_SkipMissingPlugins(t *testing.T) {
+	t.Parallel()
+
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", echoPlugin{}))
+
+	cfg := DefaultExecutionConfig()
+	cfg.SkipMissingPlugins = true
+	eng := NewNativeEngine(build.Get(), registry, cfg, nil, nil, &execution.GreedyTruncator{})
+
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "incomplete", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "mixed", Name: "Mixed", ObservationDefinitions: []entities.ObservationDefinition{
+				{Plugin: "echo", Config: map[string]interface{}{"port": 22}},
+				{Plugin: "ldap"},
+			}},
+			{ID: "missing", Name: "Missing", ObservationDefinitions: []entities.ObservationDefinition{{Plugin: "ldap"}}},
+		}},
+	}
+
+	result, err := eng.Execute(context.Background(), profile)
+	require.NoError(t, err)
+
+	mixed := result.GetControlResultByID("mixed")
+	assert.Equal(t, values.StatusPass, mixed.Status)
+	assert.Equal(t, values.StatusNotRun, mixed.ObservationResults[1].Status)
+
+	missing := result.GetControlResultByID("missing")
+	assert.Equal(t, values.StatusSkipped, missing.Status)
+	assert.Equal(t, "not run: plugin ldap is not installed (--skip-missing-plugins)", missing.SkipReason)
+	require.NotNil(t, missing.ObservationResults[0].Error)
+	assert.Equal(t, CodePluginNotInstalled, missing.ObservationResults[0].Error.Code)
+
+	assert.Equal(t, 2, result.Summary.NotRunObservations)
+	assert.Equal(t, 1, result.Summary.SkippedControls)
+	assert.Empty(t, result.ErrorGroups)
+}
+
+func TestNativeEngine_CollectOnly(t *testing.T) {
+	t.Parallel()
+
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", echoPlugin{}))
+
+	eng := NewNativeEngine(build.Get(), registry, DefaultExecutionConfig(), nil, nil, &execution.GreedyTruncator{})
+	eng.SetCollectOnly(true)
+
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "collect", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "failing", Name: "Failing", ObservationDefinitions: []entities.ObservationDefinition{{
+				Plugin: "echo",
+				Config: map[string]interface{}{"port": 23},
+				Expect: []string{"data.port == 22"},
+			}}},
+			{ID: "missing", Name: "Missing", ObservationDefinitions: []entities.ObservationDefinition{{
+				Plugin: "not-registered",
+			}}},
+		}},
+	}
+
+	result, err := eng.Execute(context.Background(), profile)
+	require.NoError(t, err)
+
+	assert.Equal(t, execution.ModeCollect, result.Mode)
+	failing := result.GetControlResultByID("failing")
+	assert.Equal(t, values.StatusCollected, failing.Status, "expectations are not evaluated")
+	assert.Empty(t, failing.ObservationResults[0].Expectations)
+	assert.InDelta(t, 23, failing.ObservationResults[0].Evidence.Data["port"], 0)
+	assert.Equal(t, values.StatusError, result.GetControlResultByID("missing").Status)
+	assert.Equal(t, 1, result.Summary.CollectedControls)
+	assert.Equal(t, 1, result.Summary.CollectedObservations)
+	assert.Equal(t, 1, result.Summary.ErrorControls)
+}
+
+func TestNativeEngine_PIIScan(t *testing.T) {
+	t.Parallel()
+
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", echoPlugin{}))
+	scanner, err := sensitivedata.NewPIIScanner(nil, sensitivedata.PIIRedact)
+	require.NoError(t, err)
+
+	eng := NewNativeEngine(build.Get(), registry, DefaultExecutionConfig(), nil, nil, &execution.GreedyTruncator{})
+	eng.SetPIIScanner(scanner)
+
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "pii", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{{
+			ID: "owner", Name: "Owner", ObservationDefinitions: []entities.ObservationDefinition{{
+				Plugin: "echo",
+				Config: map[string]interface{}{"owner": "jane@example.com", "port": 22},
+				Expect: []string{"data.owner == 'jane@example.com'"},
+			}},
+		}}},
+	}
+
+	result, err := eng.Execute(context.Background(), profile)
+	require.NoError(t, err)
+
+	obs := result.GetControlResultByID("owner").ObservationResults[0]
+	assert.Equal(t, values.StatusPass, obs.Status, "expectations see the evidence before redaction")
+	assert.Equal(t, "[REDACTED]", obs.Evidence.Data["owner"])
+	assert.Equal(t, []execution.PIIFinding{{Kind: "email", Path: "data.owner", Count: 1, Redacted: true}}, obs.PII)
+	assert.Equal(t, 1, result.Summary.PIIObservations)
+}
+
+func TestNativeEngine_PrivilegedRequiresAllowSudo(t *testing.T) {
+	t.Parallel()
+	if os.Geteuid() == 0 {
+		t.Skip("privileged observations run directly as root")
+	}
+
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", echoPlugin{}))
+
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "privileged", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{{
+			ID: "shadow", Name: "Shadow", ObservationDefinitions: []entities.ObservationDefinition{{
+				Plugin:     "echo",
+				Config:     map[string]interface{}{"path": "/etc/shadow"},
+				Privileged: true,
+			}},
+		}}},
+	}
+
+	eng := NewNativeEngine(build.Get(), registry, DefaultExecutionConfig(), nil, nil, &execution.GreedyTruncator{})
+	result, err := eng.Execute(context.Background(), profile)
+	require.NoError(t, err)
+	obs := result.GetControlResultByID("shadow").ObservationResults[0]
+	assert.Equal(t, values.StatusError, obs.Status)
+	require.NotNil(t, obs.Error)
+	assert.Equal(t, "privileges_required", obs.Error.Code)
+	assert.Contains(t, obs.Error.Message, "--allow-sudo")
+
+	eng = NewNativeEngine(build.Get(), registry, DefaultExecutionConfig(), nil, nil, &execution.GreedyTruncator{})
+	eng.SetAllowSudo(true)
+	result, err = eng.Execute(context.Background(), profile)
+	require.NoError(t, err)
+	assert.Equal(t, values.StatusPass, result.GetControlResultByID("shadow").Status)
+}
+
+func TestNativeEngine_QuotaExceeded(t *testing.T) {
+	t.Parallel()
+
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", echoPlugin{}))
+
+	observation := entities.ObservationDefinition{Plugin: "echo", Config: map[string]interface{}{"port": 22}}
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "quotas", Version: "1.0.0"},
+		Quotas:   map[string]entities.PluginQuota{"echo": {MaxInvocations: 2}},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "a", Name: "A", ObservationDefinitions: []entities.ObservationDefinition{observation, observation}},
+			{ID: "b", Name: "B", DependsOn: []string{"a"}, ObservationDefinitions: []entities.ObservationDefinition{observation}},
+		}},
+	}
+
+	eng := NewNativeEngine(build.Get(), registry, DefaultExecutionConfig(), nil, nil, &execution.GreedyTruncator{})
+	result, err := eng.Execute(context.Background(), profile)
+	require.NoError(t, err)
+
+	assert.Equal(t, values.StatusPass, result.GetControlResultByID("a").Status)
+	obs := result.GetControlResultByID("b").ObservationResults[0]
+	assert.Equal(t, values.StatusError, obs.Status)
+	require.NotNil(t, obs.Error)
+	assert.Equal(t, execution.CodeQuotaExceeded, obs.Error.Code)
+	assert.Contains(t, obs.Error.Message, "plugin echo ran its 2 invocations")
+
+	// Each run starts with fresh quotas
+	result, err = eng.Execute(context.Background(), profile)
+	require.NoError(t, err)
+	assert.Equal(t, values.StatusPass, result.GetControlResultByID("a").Status)
+}
+
+func TestNativeEngine_WhenCondition(t *testing.T) {
+	t.Parallel()
+
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", echoPlugin{}))
+
+	eng := NewNativeEngine(build.Get(), registry, DefaultExecutionConfig(), nil, nil, &execution.GreedyTruncator{})
+	eng.SetFacts(map[string]interface{}{"os": "darwin", "service_manager": "launchd"})
+
+	observe := []entities.ObservationDefinition{{Plugin: "echo", Config: map[string]interface{}{"port": 22}}}
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "platforms", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "macos", Name: "macOS", When: `facts.service_manager == "launchd"`, ObservationDefinitions: observe},
+			{ID: "windows", Name: "Windows", When: `facts.os == "windows"`, ObservationDefinitions: observe},
+			{ID: "broken", Name: "Broken", When: `facts.os > 3`, ObservationDefinitions: observe},
+		}},
+	}
+
+	result, err := eng.Execute(context.Background(), profile)
+	require.NoError(t, err)
+
+	assert.Equal(t, values.StatusPass, result.GetControlResultByID("macos").Status)
+	windows := result.GetControlResultByID("windows")
+	assert.Equal(t, values.StatusSkipped, windows.Status)
+	assert.Equal(t, `Skipped: when condition "facts.os == \"windows\"" is false on this host`, windows.SkipReason)
+	broken := result.GetControlResultByID("broken")
+	assert.Equal(t, values.StatusError, broken.Status)
+	assert.Contains(t, broken.Message, "when condition failed")
+}
+
+// ticketPlugin is a native plugin declaring side effects.
+type ticketPlugin struct{ echoPlugin }
+
+func (ticketPlugin) Describe(_ context.Context) ([]byte, error) {
+	return []byte(`{"name":"ticket","version":"1.0.0","side_effects":true}`), nil
+}
+
+func TestNativeEngine_IdempotencyKeys(t *testing.T) {
+	t.Parallel()
+
+	registry := native.NewRegistry()
+	require.NoError(t, registry.Register("echo", echoPlugin{}))
+	require.NoError(t, registry.Register("ticket", ticketPlugin{}))
+
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "remediate", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{{
+			ID: "ssh", Name: "SSH", ObservationDefinitions: []entities.ObservationDefinition{
+				{Plugin: "ticket", Config: map[string]interface{}{"summary": "root login enabled"}},
+				{Plugin: "echo", Config: map[string]interface{}{"port": 22}},
+			},
+		}}},
+	}
+
+	keys := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		eng := NewNativeEngine(build.Get(), registry, DefaultExecutionConfig(), nil, nil, &execution.GreedyTruncator{})
+		result, err := eng.Execute(context.Background(), profile)
+		require.NoError(t, err)
+
+		observations := result.GetControlResultByID("ssh").ObservationResults
+		assert.Empty(t, observations[1].IdempotencyKey, "plugins without side effects get no key")
+		keys = append(keys, observations[0].IdempotencyKey)
+	}
+
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1], "re-runs reuse the key")
+}
@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_Execute_AttachesPerformance(t *testing.T) {
+	t.Parallel()
+
+	eng, _ := newStreamTestEngine(false)
+
+	result, err := eng.Execute(context.Background(), newStreamTestProfile())
+	require.NoError(t, err)
+	assert.Nil(t, result.Performance, "no breakdown unless the run is profiled")
+
+	recorder := execution.NewPerfRecorder()
+	recorder.RecordPhase(execution.PhaseProfileLoad, time.Millisecond)
+	result, err = eng.Execute(execution.WithPerfRecorder(context.Background(), recorder), newStreamTestProfile())
+	require.NoError(t, err)
+
+	require.NotNil(t, result.Performance)
+	require.Len(t, result.Performance.Phases, 2)
+	assert.Equal(t, execution.PhaseProfileLoad, result.Performance.Phases[0].Phase)
+	assert.Equal(t, execution.PhaseExecution, result.Performance.Phases[1].Phase)
+	assert.Positive(t, result.Performance.Phases[1].Duration)
+}
+
+func TestObservationTiming(t *testing.T) {
+	t.Parallel()
+
+	result := &wasm.PluginObservationResult{Instantiation: 30 * time.Millisecond}
+	timing := observationTiming(100*time.Millisecond, result, 50*time.Millisecond)
+	assert.Equal(t, execution.ObservationTiming{
+		Instantiation: 30 * time.Millisecond,
+		Execution:     20 * time.Millisecond,
+		HostIO:        50 * time.Millisecond,
+	}, *timing)
+
+	// A failed call has no result; its time counts as execution
+	timing = observationTiming(10*time.Millisecond, nil, 0)
+	assert.Equal(t, 10*time.Millisecond, timing.Execution)
+}
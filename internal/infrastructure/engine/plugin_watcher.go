@@ -0,0 +1,210 @@
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+)
+
+// DefaultWatchInterval is how often PluginWatcher checks plugin files by default.
+const DefaultWatchInterval = 5 * time.Second
+
+// PluginVerifier checks a changed plugin file before it is used, e.g. its
+// signature or install digest. Returning an error keeps the current build.
+type PluginVerifier func(ctx context.Context, path string, data []byte) error
+
+// PluginReload describes a plugin file that was swapped for a new build.
+type PluginReload struct {
+	Path       string
+	OldVersion string // Empty for process plugins
+	NewVersion string
+	Digest     string
+}
+
+// pinnedPlugin is the build of a plugin file that runs use.
+type pinnedPlugin struct {
+	data     []byte
+	digest   string
+	rejected string // Digest of the last build that failed verification
+}
+
+// PluginWatcher hot-reloads plugins for long-running processes. The first
+// read of a plugin file pins its content; afterwards the file is polled and
+// a changed build is used only once the verifier accepts it and, for WASM
+// plugins, it compiles and describes itself. Until then runs keep getting the
+// pinned build, so a file swapped on disk never runs unverified. Runs started
+// after a reload use the new build and report its version.
+type PluginWatcher struct {
+	runtime  *wasm.Runtime
+	verify   PluginVerifier
+	interval time.Duration
+	pinned   map[string]*pinnedPlugin // by path
+	mu       sync.Mutex
+}
+
+// WatcherOption configures a PluginWatcher.
+type WatcherOption func(*PluginWatcher)
+
+// WithWatchInterval sets the polling interval.
+func WithWatchInterval(d time.Duration) WatcherOption {
+	return func(w *PluginWatcher) {
+		w.interval = d
+	}
+}
+
+// NewPluginWatcher creates a watcher that checks changed plugin files with
+// verify. runtime compiles the WASM builds and needs no capabilities: the
+// watcher only calls describe().
+func NewPluginWatcher(runtime *wasm.Runtime, verify PluginVerifier, opts ...WatcherOption) (*PluginWatcher, error) {
+	if verify == nil {
+		return nil, errors.New("plugin watcher needs a verifier")
+	}
+	w := &PluginWatcher{
+		runtime:  runtime,
+		verify:   verify,
+		interval: DefaultWatchInterval,
+		pinned:   make(map[string]*pinnedPlugin),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w, nil
+}
+
+// ReadPlugin implements ports.PluginFileReader. It returns the pinned build
+// of the plugin file at path, pinning the file as it is on the first read.
+func (w *PluginWatcher) ReadPlugin(ctx context.Context, path string) ([]byte, error) {
+	path = filepath.Clean(path)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if pinned, ok := w.pinned[path]; ok {
+		return pinned.data, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isWASMPlugin(path) {
+		if _, err := w.runtime.LoadPlugin(ctx, path, data); err != nil {
+			return nil, err
+		}
+	}
+	w.pinned[path] = &pinnedPlugin{data: data, digest: digestOf(data)}
+	return data, nil
+}
+
+// Run polls the pinned plugin files until ctx is canceled.
+func (w *PluginWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			w.Poll(ctx)
+		}
+	}
+}
+
+// Poll checks each pinned plugin file once and reloads those that changed.
+// A build that fails verification is logged once and not retried until the
+// file changes again.
+func (w *PluginWatcher) Poll(ctx context.Context) []PluginReload {
+	w.mu.Lock()
+	paths := make([]string, 0, len(w.pinned))
+	for path := range w.pinned {
+		paths = append(paths, path)
+	}
+	w.mu.Unlock()
+
+	var reloads []PluginReload
+	for _, path := range paths {
+		reload, err := w.check(ctx, path)
+		if err != nil {
+			slog.ErrorContext(ctx, "plugin reload failed, keeping current version", "path", path, "error", err)
+			continue
+		}
+		if reload != nil {
+			slog.InfoContext(ctx, "plugin reloaded",
+				"path", reload.Path, "from", reload.OldVersion, "to", reload.NewVersion, "digest", reload.Digest)
+			reloads = append(reloads, *reload)
+		}
+	}
+	return reloads
+}
+
+// check reloads a single plugin file if its digest changed.
+func (w *PluginWatcher) check(ctx context.Context, path string) (*PluginReload, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path was read by a run before
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // removed: runs keep the pinned build
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	digest := digestOf(data)
+
+	w.mu.Lock()
+	pinned := w.pinned[path]
+	unchanged := digest == pinned.digest || digest == pinned.rejected
+	if !unchanged {
+		pinned.rejected = digest // until verified, so a rejected build is not retried every poll
+	}
+	w.mu.Unlock()
+	if unchanged {
+		return nil, nil
+	}
+
+	if err := w.verify(ctx, path, data); err != nil {
+		return nil, fmt.Errorf("verification of %s failed: %w", digest, err)
+	}
+
+	reload := &PluginReload{Path: path, Digest: digest}
+	if isWASMPlugin(path) {
+		if current, ok := w.runtime.GetPlugin(path); ok {
+			if info, err := current.Describe(ctx); err == nil {
+				reload.OldVersion = info.Version
+			}
+		}
+		plugin, err := w.runtime.ReloadPlugin(ctx, path, data)
+		if err != nil {
+			return nil, err
+		}
+		info, err := plugin.Describe(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("reloaded plugin failed describe(): %w", err)
+		}
+		reload.NewVersion = info.Version
+	}
+
+	w.mu.Lock()
+	w.pinned[path] = &pinnedPlugin{data: data, digest: digest}
+	w.mu.Unlock()
+	return reload, nil
+}
+
+// isWASMPlugin reports whether path is a WASM plugin rather than the
+// executable of a process plugin.
+func isWASMPlugin(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".wasm")
+}
+
+// digestOf returns the sha256 digest of data.
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
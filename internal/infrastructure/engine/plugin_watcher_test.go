@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installFilePlugin copies the built file plugin into a fresh plugin directory.
+func installFilePlugin(t *testing.T) (pluginDir string, wasmBytes []byte) {
+	t.Helper()
+
+	wasmBytes, err := os.ReadFile(filepath.Join("..", "..", "..", "plugins", "file", "file.wasm"))
+	if err != nil {
+		t.Skip("file.wasm not built - run 'make -C plugins/file build' first")
+	}
+
+	pluginDir = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "file"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "file", "file.wasm"), wasmBytes, 0o600))
+	return pluginDir, wasmBytes
+}
+
+// withCustomSection returns a copy of a module with an extra custom section,
+// giving a different digest without changing behavior.
+func withCustomSection(wasmBytes []byte, name string) []byte {
+	payload := append([]byte{byte(len(name))}, name...)
+	payload = append(payload, "build"...)
+	section := append([]byte{0x00, byte(len(payload))}, payload...)
+	return append(append([]byte{}, wasmBytes...), section...)
+}
+
+func TestPluginWatcher_Poll(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("skipping plugin recompilation test in short mode")
+	}
+
+	ctx := context.Background()
+	pluginDir, wasmBytes := installFilePlugin(t)
+	pluginPath := filepath.Join(pluginDir, "file", "file.wasm")
+
+	rt, err := wasm.NewRuntime(ctx, build.Get())
+	require.NoError(t, err)
+	defer func() { _ = rt.Close(ctx) }()
+
+	var rejected bool
+	watcher, err := NewPluginWatcher(rt, func(_ context.Context, _ string, _ []byte) error {
+		if rejected {
+			return errors.New("signature mismatch")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	pinned, err := watcher.ReadPlugin(ctx, pluginPath)
+	require.NoError(t, err)
+	assert.Equal(t, wasmBytes, pinned)
+	assert.Empty(t, watcher.Poll(ctx), "unchanged files are not reloaded")
+
+	// Runs keep the pinned build until the new one is verified
+	v2 := withCustomSection(wasmBytes, "v2")
+	require.NoError(t, os.WriteFile(pluginPath, v2, 0o600))
+	pinned, err = watcher.ReadPlugin(ctx, pluginPath)
+	require.NoError(t, err)
+	assert.Equal(t, wasmBytes, pinned)
+
+	reloads := watcher.Poll(ctx)
+	require.Len(t, reloads, 1)
+	assert.Equal(t, pluginPath, reloads[0].Path)
+	assert.NotEmpty(t, reloads[0].NewVersion)
+	assert.Equal(t, reloads[0].OldVersion, reloads[0].NewVersion)
+	pinned, err = watcher.ReadPlugin(ctx, pluginPath)
+	require.NoError(t, err)
+	assert.Equal(t, v2, pinned)
+
+	// A build that fails verification keeps the current one
+	rejected = true
+	require.NoError(t, os.WriteFile(pluginPath, withCustomSection(wasmBytes, "v3"), 0o600))
+	assert.Empty(t, watcher.Poll(ctx))
+	pinned, err = watcher.ReadPlugin(ctx, pluginPath)
+	require.NoError(t, err)
+	assert.Equal(t, v2, pinned)
+}
+
+func TestPluginWatcher_ProcessPlugin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "reglet-plugin-net")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho v1\n"), 0o700))
+
+	var verified []string
+	watcher, err := NewPluginWatcher(nil, func(_ context.Context, path string, _ []byte) error {
+		verified = append(verified, path)
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = watcher.ReadPlugin(ctx, path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho v2\n"), 0o700))
+
+	reloads := watcher.Poll(ctx)
+	require.Len(t, reloads, 1)
+	assert.Empty(t, reloads[0].NewVersion, "process plugins are not described")
+	assert.Equal(t, []string{path}, verified)
+	data, err := watcher.ReadPlugin(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\necho v2\n", string(data))
+}
+
+func TestNewPluginWatcher_RequiresVerifier(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewPluginWatcher(nil, nil)
+	assert.Error(t, err)
+}
+
+func TestRuntime_ReloadPlugin_InvalidKeepsCurrent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	_, wasmBytes := installFilePlugin(t)
+
+	rt, err := wasm.NewRuntime(ctx, build.Get())
+	require.NoError(t, err)
+	defer func() { _ = rt.Close(ctx) }()
+
+	original, err := rt.LoadPlugin(ctx, "file", wasmBytes)
+	require.NoError(t, err)
+
+	_, err = rt.ReloadPlugin(ctx, "file", []byte("not wasm"))
+	require.Error(t, err)
+
+	current, ok := rt.GetPlugin("file")
+	require.True(t, ok)
+	assert.Same(t, original, current)
+}
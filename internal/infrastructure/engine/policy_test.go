@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// funcPolicy adapts a function to execution.ResultPolicy.
+type funcPolicy func(ctx context.Context, result *execution.ExecutionResult) ([]execution.PolicyFinding, error)
+
+func (f funcPolicy) Evaluate(ctx context.Context, result *execution.ExecutionResult) ([]execution.PolicyFinding, error) {
+	return f(ctx, result)
+}
+
+func TestEngine_Execute_ResultPolicy(t *testing.T) {
+	t.Parallel()
+
+	eng, _ := newStreamTestEngine(true)
+	var seen execution.ResultSummary
+	eng.SetResultPolicy(funcPolicy(func(_ context.Context, result *execution.ExecutionResult) ([]execution.PolicyFinding, error) {
+		seen = result.Summary
+		return []execution.PolicyFinding{{
+			ID:       "too-many-passes",
+			Message:  "3 controls passed",
+			Severity: "medium",
+			Controls: []string{"a", "b", "c"},
+		}}, nil
+	}))
+
+	result, err := eng.Execute(context.Background(), newStreamTestProfile())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, seen.PassedControls, "policy sees the summarized result")
+
+	require.Len(t, result.Controls, 4)
+	derived := result.Controls[3]
+	assert.Equal(t, "policy:too-many-passes", derived.ID)
+	assert.Equal(t, values.StatusFail, derived.Status)
+	assert.Equal(t, 3, derived.Index)
+	assert.Contains(t, derived.Tags, execution.PolicyTag)
+	assert.Equal(t, 1, result.Summary.FailedControls)
+	assert.Equal(t, 4, result.Summary.TotalControls)
+}
+
+func TestEngine_Execute_ResultPolicyStreamed(t *testing.T) {
+	t.Parallel()
+
+	eng, _ := newStreamTestEngine(false)
+	stream := &recordingStream{}
+	eng.SetResultStream(stream)
+	eng.SetResultPolicy(funcPolicy(func(context.Context, *execution.ExecutionResult) ([]execution.PolicyFinding, error) {
+		return []execution.PolicyFinding{{ID: "gate", Message: "blocked"}}, nil
+	}))
+
+	_, err := eng.Execute(context.Background(), newStreamTestProfile())
+	require.NoError(t, err)
+
+	require.Len(t, stream.controls, 4)
+	assert.Equal(t, "policy:gate", stream.controls[3].ID)
+	assert.True(t, stream.ended)
+}
+
+func TestEngine_Execute_ResultPolicyError(t *testing.T) {
+	t.Parallel()
+
+	eng, _ := newStreamTestEngine(false)
+	eng.SetResultPolicy(funcPolicy(func(context.Context, *execution.ExecutionResult) ([]execution.PolicyFinding, error) {
+		return nil, errors.New("bundle not found")
+	}))
+
+	_, err := eng.Execute(context.Background(), newStreamTestProfile())
+	assert.ErrorContains(t, err, "bundle not found")
+}
@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_ProcessPluginFallback(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS != "linux" {
+		t.Skip("process plugins only run on Linux")
+	}
+
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "echo"), 0o750))
+	script := "#!/bin/sh\necho '{\"Status\":true,\"Data\":{\"port\":22}}'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "echo", "reglet-plugin-echo"), []byte(script), 0o700))
+
+	ctx := context.Background()
+	// Network is granted so the test does not depend on user namespaces
+	rt, err := wasm.NewRuntimeWithCapabilities(ctx, build.Get(),
+		map[string][]capabilities.Capability{"echo": {{Kind: "network", Pattern: "*"}}}, nil, 0)
+	require.NoError(t, err)
+	defer func() { _ = rt.Close(ctx) }()
+
+	executor := NewExecutor(rt, WithPluginDir(pluginDir))
+
+	// Executables the user has not trusted are never started
+	result := executor.Execute(ctx, entities.ObservationDefinition{Plugin: "echo"})
+	assert.Equal(t, values.StatusError, result.Status)
+	require.NotNil(t, result.Error)
+	assert.Contains(t, result.Error.Message, "process_plugins")
+
+	executor.SetProcessPlugins([]string{"echo"})
+	result = executor.Execute(ctx, entities.ObservationDefinition{
+		Plugin: "echo",
+		Expect: []string{"data.port == 22"},
+	})
+	assert.Equal(t, values.StatusPass, result.Status)
+	assert.Empty(t, result.PluginVersion, "process plugins do not report a version per call")
+
+	result = executor.Execute(ctx, entities.ObservationDefinition{Plugin: "absent"})
+	assert.Equal(t, values.StatusError, result.Status)
+}
+
+func TestExecutor_ProcessPluginObservationEnv(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS != "linux" {
+		t.Skip("process plugins only run on Linux")
+	}
+
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "envecho"), 0o750))
+	script := "#!/bin/sh\nprintf '{\"Status\":true,\"Data\":{\"lang\":\"%s\",\"mode\":\"%s\"}}' \"$LANG\" \"$PGSSLMODE\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "envecho", "reglet-plugin-envecho"), []byte(script), 0o700))
+
+	ctx := context.Background()
+	rt, err := wasm.NewRuntimeWithCapabilities(ctx, build.Get(),
+		map[string][]capabilities.Capability{"envecho": {
+			{Kind: "network", Pattern: "*"},
+			capabilities.ObservationEnvCapability("LANG"),
+		}}, nil, 0)
+	require.NoError(t, err)
+	defer func() { _ = rt.Close(ctx) }()
+
+	executor := NewExecutor(rt, WithPluginDir(pluginDir))
+	executor.SetProcessPlugins([]string{"envecho"})
+
+	result := executor.Execute(ctx, entities.ObservationDefinition{
+		Plugin: "envecho",
+		Env:    map[string]string{"LANG": "C.UTF-8", "PGSSLMODE": "require"},
+		Expect: []string{`data.lang == "C.UTF-8"`, `data.mode == ""`},
+	})
+	assert.Equal(t, values.StatusPass, result.Status, "ungranted observation env must not reach the plugin")
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingStream captures streamed results for assertions.
+type recordingStream struct {
+	writeErr error
+	controls []execution.ControlResult
+	mu       sync.Mutex
+	begun    bool
+	ended    bool
+}
+
+func (s *recordingStream) Begin(_ *execution.ExecutionResult) error {
+	s.begun = true
+	return nil
+}
+
+func (s *recordingStream) WriteControl(control execution.ControlResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.controls = append(s.controls, control)
+	return s.writeErr
+}
+
+func (s *recordingStream) End(_ *execution.ExecutionResult) error {
+	s.ended = true
+	return nil
+}
+
+func newStreamTestProfile() *entities.Profile {
+	obs := []entities.ObservationDefinition{{Plugin: "test-plugin", Config: map[string]interface{}{"k": "v"}}}
+	return &entities.Profile{
+		Metadata: entities.ProfileMetadata{Name: "stream", Version: "1.0.0"},
+		Controls: entities.ControlsSection{Items: []entities.Control{
+			{ID: "a", Name: "A", ObservationDefinitions: obs},
+			{ID: "b", Name: "B", ObservationDefinitions: obs, DependsOn: []string{"a"}},
+			{ID: "c", Name: "C", ObservationDefinitions: obs},
+		}},
+	}
+}
+
+func newStreamTestEngine(parallel bool) (*Engine, *MockExecutor) {
+	mockExec := new(MockExecutor)
+	mockExec.On("Execute", mock.Anything, mock.Anything).Return(execution.ObservationResult{
+		Plugin: "test-plugin",
+		Config: map[string]interface{}{"k": "v"},
+		Status: values.StatusPass,
+		Evidence: &execution.Evidence{
+			Timestamp: time.Now(),
+			Status:    true,
+			Data:      map[string]interface{}{"payload": "large evidence"},
+		},
+	})
+
+	return &Engine{
+		executor:  mockExec,
+		truncator: &execution.GreedyTruncator{},
+		config: ExecutionConfig{
+			Parallel:              parallel,
+			MaxConcurrentControls: 2,
+		},
+	}, mockExec
+}
+
+func TestEngine_Execute_StreamsControls(t *testing.T) {
+	t.Parallel()
+
+	for _, parallel := range []bool{false, true} {
+		t.Run(map[bool]string{false: "sequential", true: "parallel"}[parallel], func(t *testing.T) {
+			t.Parallel()
+
+			eng, _ := newStreamTestEngine(parallel)
+			stream := &recordingStream{}
+			eng.SetResultStream(stream)
+
+			result, err := eng.Execute(context.Background(), newStreamTestProfile())
+			require.NoError(t, err)
+
+			assert.True(t, stream.begun)
+			assert.True(t, stream.ended)
+			require.Len(t, stream.controls, 3)
+			for _, ctrl := range stream.controls {
+				require.Len(t, ctrl.ObservationResults, 1)
+				assert.NotNil(t, ctrl.ObservationResults[0].Evidence, "streamed controls carry full evidence")
+			}
+
+			// Retained result is compacted but still summarized
+			require.Len(t, result.Controls, 3)
+			for _, ctrl := range result.Controls {
+				assert.Equal(t, values.StatusPass, ctrl.Status)
+				require.Len(t, ctrl.ObservationResults, 1)
+				assert.Nil(t, ctrl.ObservationResults[0].Evidence)
+				assert.Nil(t, ctrl.ObservationResults[0].Config)
+			}
+			assert.Equal(t, 3, result.Summary.PassedControls)
+			assert.Equal(t, 3, result.Summary.PassedObservations)
+		})
+	}
+}
+
+func TestEngine_Execute_StreamRetainsEvidence(t *testing.T) {
+	t.Parallel()
+
+	eng, _ := newStreamTestEngine(false)
+	eng.SetResultStream(&recordingStream{})
+	eng.SetRetainEvidence(true)
+
+	result, err := eng.Execute(context.Background(), newStreamTestProfile())
+	require.NoError(t, err)
+
+	require.Len(t, result.Controls, 3)
+	for _, ctrl := range result.Controls {
+		require.Len(t, ctrl.ObservationResults, 1)
+		assert.NotNil(t, ctrl.ObservationResults[0].Evidence, "exporters need the evidence of streamed controls")
+	}
+}
+
+func TestEngine_Execute_StreamWriteError(t *testing.T) {
+	t.Parallel()
+
+	eng, _ := newStreamTestEngine(false)
+	stream := &recordingStream{writeErr: errors.New("disk full")}
+	eng.SetResultStream(stream)
+
+	_, err := eng.Execute(context.Background(), newStreamTestProfile())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "disk full")
+	assert.False(t, stream.ended, "stream should not be closed after a write error")
+}
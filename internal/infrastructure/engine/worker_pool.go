@@ -3,18 +3,19 @@ package engine
 import (
 	"context"
 	"fmt"
-	"runtime"
 
 	"github.com/reglet-dev/reglet/internal/domain/entities"
 	"github.com/reglet-dev/reglet/internal/domain/execution"
 	"github.com/reglet-dev/reglet/internal/domain/services"
+	"github.com/reglet-dev/reglet/internal/infrastructure/hostfacts"
 	"golang.org/x/sync/errgroup"
 )
 
 // workerPoolState manages the state of dependency-aware parallel execution.
 // Instead of organizing controls into levels with barriers, this approach
 // maintains a dynamic ready queue and executes controls as soon as their
-// dependencies are satisfied.
+// dependencies are satisfied. Ready controls wait in a fairQueue so that a
+// plugin with many slow controls cannot take every worker from the others.
 type workerPoolState struct {
 	ctx              context.Context
 	inDegree         map[string]int
@@ -29,8 +30,10 @@ type workerPoolState struct {
 	cancel           context.CancelFunc
 	errGroup         *errgroup.Group
 	engine           *Engine
-	readyQueue       []string
+	ready            *fairQueue
 	totalControls    int
+	workers          int
+	inFlight         int
 }
 
 // initializeWorkerPoolState builds the dependency graph and prepares initial state.
@@ -74,21 +77,18 @@ func (e *Engine) initializeWorkerPoolState(
 	}
 
 	// Build initial ready queue (controls with no dependencies)
-	readyQueue := []string{}
+	ready := newFairQueue(e.config.PluginWeights)
 	for _, ctrl := range controls {
 		if inDegree[ctrl.ID] == 0 {
-			readyQueue = append(readyQueue, ctrl.ID)
+			ready.push(schedulingPlugin(ctrl), ctrl.ID)
 		}
 	}
 
 	// Create channels
-	// workChan is buffered to reduce blocking when coordinator sends work
+	// workChan holds a slot per worker, so the coordinator never blocks on it
 	// doneChan is buffered to prevent workers from blocking when signaling completion
-	maxConcurrent := e.config.MaxConcurrentControls
-	if maxConcurrent <= 0 {
-		maxConcurrent = 1 // Ensure at least 1 for buffer size
-	}
-	workChan := make(chan string, maxConcurrent)
+	workers := e.workerCount()
+	workChan := make(chan string, workers)
 	doneChan := make(chan string, len(controls))
 
 	// Create context and errgroup
@@ -100,9 +100,10 @@ func (e *Engine) initializeWorkerPoolState(
 		controlIndexByID: controlIndexByID,
 		reverseDeps:      reverseDeps,
 		inDegree:         inDegree,
-		readyQueue:       readyQueue,
+		ready:            ready,
 		completed:        make(map[string]bool),
 		totalControls:    len(controls),
+		workers:          workers,
 		workChan:         workChan,
 		doneChan:         doneChan,
 		ctx:              gCtx,
@@ -114,19 +115,34 @@ func (e *Engine) initializeWorkerPoolState(
 	}, nil
 }
 
+// workerCount returns the number of workers running controls.
+func (e *Engine) workerCount() int {
+	numWorkers := e.config.MaxConcurrentControls
+	if numWorkers <= 0 {
+		numWorkers = hostfacts.AvailableCPUs()
+		if numWorkers < MinConcurrentControls {
+			numWorkers = MinConcurrentControls
+		}
+	}
+	return numWorkers
+}
+
 // enqueueReadyControls sends ready controls from the queue to the work channel.
 // This is called by the coordinator after dependency updates.
-// It sends as many controls as the channel can accept without blocking.
+// It only sends controls to idle workers, so the fair queue picks each one
+// when a worker is free rather than ahead of time.
 func (state *workerPoolState) enqueueReadyControls() {
-	for len(state.readyQueue) > 0 {
-		select {
-		case state.workChan <- state.readyQueue[0]:
-			// Successfully sent to worker, remove from queue
-			state.readyQueue = state.readyQueue[1:]
-		default:
-			// Channel full (all workers busy), stop trying
+	recorder, recording := execution.PerfRecorderFromContext(state.ctx)
+	for state.inFlight < state.workers {
+		plugin, controlID, wait, ok := state.ready.pop()
+		if !ok {
 			return
 		}
+		if recording {
+			recorder.RecordScheduling(plugin, state.ready.weight(plugin), wait, state.ready.running[plugin])
+		}
+		state.inFlight++
+		state.workChan <- controlID
 	}
 }
 
@@ -135,18 +151,20 @@ func (state *workerPoolState) enqueueReadyControls() {
 // Any dependent that reaches in-degree 0 becomes ready to execute.
 func (state *workerPoolState) handleControlCompletion(controlID string) {
 	state.completed[controlID] = true
+	state.inFlight--
+	state.ready.done(schedulingPlugin(state.controlByID[controlID]))
 
 	for _, dependentID := range state.reverseDeps[controlID] {
 		state.inDegree[dependentID]--
 
 		if state.inDegree[dependentID] == 0 {
-			state.readyQueue = append(state.readyQueue, dependentID)
+			state.ready.push(schedulingPlugin(state.controlByID[dependentID]), dependentID)
 		}
 	}
 }
 
 // coordinateExecution is the central coordinator that manages control execution.
-// It runs in a single goroutine, owning all mutable state (inDegree, readyQueue).
+// It runs in a single goroutine, owning all mutable state (inDegree, ready).
 // This design eliminates the need for fine-grained locking.
 func (state *workerPoolState) coordinateExecution() error {
 	defer close(state.workChan)
@@ -190,7 +208,7 @@ func (state *workerPoolState) executeWorker() {
 			state.requiredDeps,
 		)
 
-		state.execResult.AddControlResult(controlResult)
+		state.engine.recordControlResult(state.execResult, controlResult)
 
 		select {
 		case state.doneChan <- controlID:
@@ -214,15 +232,7 @@ func (e *Engine) executeControlsWithWorkerPool(
 	}
 	defer state.cancel()
 
-	numWorkers := e.config.MaxConcurrentControls
-	if numWorkers <= 0 {
-		numWorkers = runtime.NumCPU()
-		if numWorkers < MinConcurrentControls {
-			numWorkers = MinConcurrentControls
-		}
-	}
-
-	for i := 0; i < numWorkers; i++ {
+	for i := 0; i < state.workers; i++ {
 		state.errGroup.Go(func() error {
 			state.executeWorker()
 			return nil
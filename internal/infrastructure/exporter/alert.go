@@ -0,0 +1,165 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// AlertConfig holds the settings shared by alerting integrations.
+type AlertConfig struct {
+	// Target identifies the checked system in dedup keys (default: hostname).
+	Target string `json:"target"`
+	// URL overrides the API endpoint, e.g. for an EU region.
+	URL string `json:"url"`
+	// Severities selects controls that alert (default: critical).
+	Severities []string `json:"severities"`
+	// Tags further restricts alerting to controls with any of these tags.
+	Tags []string `json:"tags"`
+}
+
+// alertLabelPrefix namespaces control labels in alert details so they cannot
+// clash with the built-in detail keys.
+const alertLabelPrefix = "label."
+
+// alert is a single control's incident, in a form both alerting APIs can map.
+type alert struct {
+	Details   map[string]interface{}
+	DedupKey  string
+	Summary   string
+	Severity  string
+	ControlID string
+	Source    string
+	Tags      []string
+}
+
+// applyDefaults fills in unset fields.
+func (c *AlertConfig) applyDefaults(defaultURL string) {
+	if c.URL == "" {
+		c.URL = defaultURL
+	}
+	c.URL = strings.TrimRight(c.URL, "/")
+	if len(c.Severities) == 0 {
+		c.Severities = []string{"critical"}
+	}
+	if c.Target == "" {
+		if hostname, err := os.Hostname(); err == nil {
+			c.Target = hostname
+		}
+	}
+}
+
+// selects reports whether a control is covered by the severity/tag selector.
+func (c *AlertConfig) selects(ctrl *execution.ControlResult) bool {
+	if !slices.ContainsFunc(c.Severities, func(s string) bool { return strings.EqualFold(s, ctrl.Severity) }) {
+		return false
+	}
+	if len(c.Tags) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(ctrl.Tags, func(tag string) bool { return slices.Contains(c.Tags, tag) })
+}
+
+// alerts splits the selected controls into incidents to trigger (failed or
+// flapping) and to resolve (passed). Errored and skipped controls leave
+// incidents untouched.
+func (c *AlertConfig) alerts(result *execution.ExecutionResult) (trigger, resolve []alert) {
+	for i := range result.Controls {
+		ctrl := &result.Controls[i]
+		if !c.selects(ctrl) {
+			continue
+		}
+
+		a := alert{
+			DedupKey:  fmt.Sprintf("reglet/%s/%s/%s", result.ProfileRef(), ctrl.ID, c.Target),
+			Summary:   fmt.Sprintf("[reglet] %s (%s) failed on %s", ctrl.Name, ctrl.ID, c.Target),
+			Severity:  strings.ToLower(ctrl.Severity),
+			ControlID: ctrl.ID,
+			Source:    c.Target,
+			Tags:      ctrl.Tags,
+			Details: map[string]interface{}{
+				"profile":      result.ProfileName,
+				"version":      result.ProfileVersion,
+				"control":      ctrl.ID,
+				"message":      ctrl.Message,
+				"execution_id": result.ExecutionID.String(),
+			},
+		}
+		if result.Environment != "" {
+			a.Details["environment"] = result.Environment
+		}
+		for key, value := range ctrl.Labels {
+			a.Details[alertLabelPrefix+key] = value
+		}
+
+		if ctrl.Transition != nil {
+			a.Details["transition"] = string(ctrl.Transition.Kind)
+		}
+
+		switch {
+		case ctrl.Transition != nil && ctrl.Transition.Kind == execution.TransitionFlapping:
+			// Keep one incident open while the status keeps changing
+			a.Summary = fmt.Sprintf("[reglet] %s (%s) is flapping on %s", ctrl.Name, ctrl.ID, c.Target)
+			trigger = append(trigger, a)
+		case ctrl.Status == values.StatusFail:
+			trigger = append(trigger, a)
+		case ctrl.Status == values.StatusPass:
+			resolve = append(resolve, a)
+		}
+	}
+	return trigger, resolve
+}
+
+// decodeConfig converts an integrations section into a typed config.
+func decodeConfig(name string, section map[string]interface{}, out interface{}) error {
+	if section == nil {
+		return fmt.Errorf("profile has no integrations.%s section", name)
+	}
+	data, err := json.Marshal(section)
+	if err != nil {
+		return fmt.Errorf("invalid integrations.%s section: %w", name, err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid integrations.%s section: %w", name, err)
+	}
+	return nil
+}
+
+// postJSON sends body to url and fails on a non-2xx response, unless the
+// status is listed in allow.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body interface{}, allow ...int) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if (resp.StatusCode < 200 || resp.StatusCode >= 300) && !slices.Contains(allow, resp.StatusCode) {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s returned %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordedRequest is a request captured by recordingServer.
+type recordedRequest struct {
+	Body map[string]interface{}
+	Auth string
+	Path string
+}
+
+// recordingServer captures JSON requests and answers with status.
+type recordingServer struct {
+	requests []recordedRequest
+	status   int
+	mu       sync.Mutex
+}
+
+func (s *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	s.requests = append(s.requests, recordedRequest{
+		Path: r.URL.RequestURI(),
+		Auth: r.Header.Get("Authorization"),
+		Body: body,
+	})
+	w.WriteHeader(s.status)
+}
+
+func alertTestResult() *execution.ExecutionResult {
+	result := execution.NewExecutionResult("baseline", "1.0.0")
+	result.Controls = []execution.ControlResult{
+		{ID: "ssh-root", Name: "SSH root login", Severity: "critical", Tags: []string{"ssh"}, Labels: map[string]string{"service": "bastion"}, Status: values.StatusFail},
+		{ID: "tls", Name: "TLS 1.2+", Severity: "critical", Tags: []string{"network"}, Status: values.StatusPass},
+		{ID: "ntp", Name: "NTP", Severity: "critical", Tags: []string{"time"}, Status: values.StatusFail},
+		{ID: "motd", Name: "MOTD", Severity: "low", Tags: []string{"ssh"}, Status: values.StatusFail},
+		{ID: "dns", Name: "DNS", Severity: "critical", Tags: []string{"network"}, Status: values.StatusError},
+	}
+	return result
+}
+
+func TestAlertConfig_Alerts(t *testing.T) {
+	t.Parallel()
+
+	cfg := AlertConfig{Target: "web-1", Tags: []string{"ssh", "network"}}
+	cfg.applyDefaults("https://example.com/")
+
+	trigger, resolve := cfg.alerts(alertTestResult())
+
+	require.Len(t, trigger, 1)
+	assert.Equal(t, "reglet/baseline/ssh-root/web-1", trigger[0].DedupKey)
+	assert.Equal(t, "bastion", trigger[0].Details["label.service"])
+	require.Len(t, resolve, 1)
+	assert.Equal(t, "reglet/baseline/tls/web-1", resolve[0].DedupKey)
+	assert.Equal(t, "https://example.com", cfg.URL)
+}
+
+func TestAlertConfig_AlertsScopedByEnvironment(t *testing.T) {
+	t.Parallel()
+
+	cfg := AlertConfig{Target: "web-1"}
+	cfg.applyDefaults("https://example.com")
+
+	result := alertTestResult()
+	result.Environment = "prod"
+	trigger, _ := cfg.alerts(result)
+
+	require.NotEmpty(t, trigger)
+	assert.Equal(t, "reglet/baseline@prod/ssh-root/web-1", trigger[0].DedupKey)
+	assert.Equal(t, "prod", trigger[0].Details["environment"])
+}
+
+func TestAlertConfig_AlertsFlapping(t *testing.T) {
+	t.Parallel()
+
+	cfg := AlertConfig{Target: "web-1"}
+	cfg.applyDefaults("https://example.com")
+
+	result := alertTestResult()
+	result.Controls[1].Transition = &execution.ControlTransition{Kind: execution.TransitionFlapping, From: values.StatusFail, Changes: 4}
+	trigger, resolve := cfg.alerts(result)
+
+	require.Len(t, resolve, 0, "a flapping control that currently passes stays open")
+	require.Len(t, trigger, 3)
+	assert.Equal(t, "reglet/baseline/tls/web-1", trigger[1].DedupKey)
+	assert.Equal(t, "[reglet] TLS 1.2+ (tls) is flapping on web-1", trigger[1].Summary)
+	assert.Equal(t, "flapping", trigger[1].Details["transition"])
+}
+
+func TestPagerDutyExporter_Export(t *testing.T) {
+	t.Parallel()
+
+	server := &recordingServer{status: http.StatusAccepted}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	e, err := NewPagerDutyExporter(ts.Client()).WithConfig(map[string]interface{}{
+		"routing_key": "rk",
+		"url":         ts.URL + "/v2/enqueue",
+		"target":      "web-1",
+	})
+	require.NoError(t, err)
+	require.NoError(t, e.Export(context.Background(), alertTestResult()))
+
+	require.Len(t, server.requests, 3)
+	byKey := make(map[string]map[string]interface{})
+	for _, req := range server.requests {
+		byKey[req.Body["dedup_key"].(string)] = req.Body
+	}
+
+	triggered := byKey["reglet/baseline/ssh-root/web-1"]
+	assert.Equal(t, "trigger", triggered["event_action"])
+	assert.Equal(t, "rk", triggered["routing_key"])
+	payload := triggered["payload"].(map[string]interface{})
+	assert.Equal(t, "critical", payload["severity"])
+	assert.Equal(t, "web-1", payload["source"])
+
+	assert.Equal(t, "trigger", byKey["reglet/baseline/ntp/web-1"]["event_action"])
+	assert.Equal(t, "resolve", byKey["reglet/baseline/tls/web-1"]["event_action"])
+}
+
+func TestOpsgenieExporter_Export(t *testing.T) {
+	t.Parallel()
+
+	server := &recordingServer{status: http.StatusAccepted}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	e, err := NewOpsgenieExporter(ts.Client()).WithConfig(map[string]interface{}{
+		"api_key": "key",
+		"url":     ts.URL,
+		"target":  "web-1",
+		"tags":    []interface{}{"ssh", "network"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, e.Export(context.Background(), alertTestResult()))
+
+	require.Len(t, server.requests, 2)
+	created := server.requests[0]
+	assert.Equal(t, "/v2/alerts", created.Path)
+	assert.Equal(t, "GenieKey key", created.Auth)
+	assert.Equal(t, "reglet/baseline/ssh-root/web-1", created.Body["alias"])
+	assert.Equal(t, "P1", created.Body["priority"])
+
+	assert.Equal(t, "/v2/alerts/reglet%2Fbaseline%2Ftls%2Fweb-1/close?identifierType=alias", server.requests[1].Path)
+}
+
+func TestOpsgenieExporter_CloseMissingAlert(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(&recordingServer{status: http.StatusNotFound})
+	defer ts.Close()
+
+	e, err := NewOpsgenieExporter(ts.Client()).WithConfig(map[string]interface{}{
+		"api_key":    "key",
+		"url":        ts.URL,
+		"severities": []interface{}{"critical"},
+	})
+	require.NoError(t, err)
+
+	result := execution.NewExecutionResult("baseline", "1.0.0")
+	result.Controls = []execution.ControlResult{{ID: "tls", Severity: "critical", Status: values.StatusPass}}
+	assert.NoError(t, e.Export(context.Background(), result))
+
+	result.Controls[0].Status = values.StatusFail
+	assert.ErrorContains(t, e.Export(context.Background(), result), "404")
+}
+
+func TestAlertExporters_RequireKey(t *testing.T) {
+	t.Setenv(PagerDutyRoutingKeyEnv, "")
+	t.Setenv(OpsgenieAPIKeyEnv, "")
+
+	_, err := NewPagerDutyExporter(nil).WithConfig(map[string]interface{}{})
+	assert.ErrorContains(t, err, "requires routing_key")
+
+	_, err = NewOpsgenieExporter(nil).WithConfig(map[string]interface{}{})
+	assert.ErrorContains(t, err, "requires api_key")
+
+	_, err = NewPagerDutyExporter(nil).WithConfig(nil)
+	assert.ErrorContains(t, err, "no integrations.pagerduty section")
+}
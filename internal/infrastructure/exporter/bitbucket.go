@@ -0,0 +1,233 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+const (
+	// BitbucketExporterName selects the Bitbucket Code Insights publisher (--publish bitbucket).
+	BitbucketExporterName = "bitbucket"
+
+	// maxBitbucketAnnotationsPerRequest is the Code Insights limit per bulk call.
+	maxBitbucketAnnotationsPerRequest = 100
+
+	// maxBitbucketAnnotations is the Code Insights limit per report.
+	maxBitbucketAnnotations = 1000
+
+	bitbucketAPIURL = "https://api.bitbucket.org"
+)
+
+func init() {
+	Register(NewBitbucketExporter(nil))
+}
+
+// BitbucketConfig is the optional integrations.bitbucket section of a profile.
+// Every field defaults to the variables Bitbucket Pipelines provides.
+type BitbucketConfig struct {
+	Token       string `json:"token"`        // default: $BITBUCKET_ACCESS_TOKEN
+	User        string `json:"user"`         // with app_password, for basic auth
+	AppPassword string `json:"app_password"` // default: $BITBUCKET_APP_PASSWORD
+	Repository  string `json:"repository"`   // workspace/repo, default: $BITBUCKET_WORKSPACE/$BITBUCKET_REPO_SLUG
+	Commit      string `json:"commit"`       // default: $BITBUCKET_COMMIT
+	APIURL      string `json:"api_url"`      // default: api.bitbucket.org
+	Workspace   string `json:"workspace"`    // repository checkout, default: $BITBUCKET_CLONE_DIR or cwd
+	ReportID    string `json:"report_id"`    // default: "reglet-<profile>"
+}
+
+// BitbucketExporter publishes the result as a Bitbucket Code Insights report
+// on the current commit, with an annotation per failing control.
+type BitbucketExporter struct {
+	client *http.Client
+	config *BitbucketConfig
+}
+
+// Compile-time interface check
+var _ ports.ConfigurableExporter = (*BitbucketExporter)(nil)
+
+// NewBitbucketExporter creates an unconfigured Bitbucket exporter. If client
+// is nil, a client with a default timeout is used.
+func NewBitbucketExporter(client *http.Client) *BitbucketExporter {
+	if client == nil {
+		client = &http.Client{Timeout: defaultRequestTimeout}
+	}
+	return &BitbucketExporter{client: client}
+}
+
+// Name returns the exporter name.
+func (e *BitbucketExporter) Name() string {
+	return BitbucketExporterName
+}
+
+// WithConfig returns a copy of the exporter using the integrations.bitbucket
+// section, falling back to the Bitbucket Pipelines environment.
+func (e *BitbucketExporter) WithConfig(config map[string]interface{}) (ports.ResultExporter, error) {
+	var cfg BitbucketConfig
+	if config != nil {
+		if err := decodeConfig(BitbucketExporterName, config, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	setDefault(&cfg.Token, os.Getenv("BITBUCKET_ACCESS_TOKEN"))
+	setDefault(&cfg.AppPassword, os.Getenv("BITBUCKET_APP_PASSWORD"))
+	if ws, slug := os.Getenv("BITBUCKET_WORKSPACE"), os.Getenv("BITBUCKET_REPO_SLUG"); ws != "" && slug != "" {
+		setDefault(&cfg.Repository, ws+"/"+slug)
+	}
+	setDefault(&cfg.Commit, os.Getenv("BITBUCKET_COMMIT"))
+	setDefault(&cfg.APIURL, bitbucketAPIURL)
+	setDefault(&cfg.Workspace, os.Getenv("BITBUCKET_CLONE_DIR"))
+	if cfg.Workspace == "" {
+		if cwd, err := os.Getwd(); err == nil {
+			cfg.Workspace = cwd
+		}
+	}
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/")
+
+	// Credentials are optional: inside Pipelines the reports API is reachable
+	// without them through the build's authenticating proxy.
+	if cfg.Repository == "" || cfg.Commit == "" {
+		return nil, fmt.Errorf("bitbucket exporter requires a repository and commit (set BITBUCKET_WORKSPACE, BITBUCKET_REPO_SLUG and BITBUCKET_COMMIT or integrations.bitbucket)")
+	}
+
+	return &BitbucketExporter{client: e.client, config: &cfg}, nil
+}
+
+// Export creates or replaces the commit's report and uploads its annotations.
+func (e *BitbucketExporter) Export(ctx context.Context, result *execution.ExecutionResult) error {
+	if e.config == nil {
+		return fmt.Errorf("bitbucket exporter is not configured")
+	}
+
+	reportID := e.config.ReportID
+	if reportID == "" {
+		reportID = "reglet-" + result.ProfileRef()
+	}
+	outcome := "PASSED"
+	if result.Summary.FailedControls > 0 || result.Summary.ErrorControls > 0 {
+		outcome = "FAILED"
+	}
+
+	report := map[string]interface{}{
+		"title":       "reglet: " + result.ProfileRef(),
+		"details":     fmt.Sprintf("%s %s", result.ProfileName, result.ProfileVersion),
+		"report_type": "SECURITY",
+		"reporter":    "reglet",
+		"result":      outcome,
+		"data": []map[string]interface{}{
+			{"title": "Passed", "type": "NUMBER", "value": result.Summary.PassedControls},
+			{"title": "Failed", "type": "NUMBER", "value": result.Summary.FailedControls},
+			{"title": "Errors", "type": "NUMBER", "value": result.Summary.ErrorControls},
+			{"title": "Skipped", "type": "NUMBER", "value": result.Summary.SkippedControls},
+		},
+	}
+
+	reportPath := fmt.Sprintf("/2.0/repositories/%s/commit/%s/reports/%s", e.config.Repository, e.config.Commit, reportID)
+	if err := e.do(ctx, http.MethodPut, reportPath, report); err != nil {
+		return err
+	}
+
+	annotations := e.annotations(result)
+	for start := 0; start < len(annotations); start += maxBitbucketAnnotationsPerRequest {
+		end := min(start+maxBitbucketAnnotationsPerRequest, len(annotations))
+		if err := e.do(ctx, http.MethodPost, reportPath+"/annotations", annotations[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bitbucketAnnotation is a Code Insights annotation.
+type bitbucketAnnotation struct {
+	ExternalID     string `json:"external_id"`
+	AnnotationType string `json:"annotation_type"`
+	Summary        string `json:"summary"`
+	Details        string `json:"details,omitempty"`
+	Severity       string `json:"severity"`
+	Result         string `json:"result"`
+	Path           string `json:"path,omitempty"`
+	Line           int    `json:"line,omitempty"`
+}
+
+// annotations returns one annotation per failing control. Controls whose
+// evidence references a file inside the workspace are placed on that file.
+func (e *BitbucketExporter) annotations(result *execution.ExecutionResult) []bitbucketAnnotation {
+	var annotations []bitbucketAnnotation
+	for i := range result.Controls {
+		ctrl := &result.Controls[i]
+		if !failing(ctrl) {
+			continue
+		}
+		if len(annotations) == maxBitbucketAnnotations {
+			break
+		}
+		annotation := bitbucketAnnotation{
+			ExternalID:     ctrl.ID,
+			AnnotationType: "VULNERABILITY",
+			Summary:        truncate(fmt.Sprintf("%s (%s)", ctrl.Name, ctrl.ID), 450),
+			Details:        truncate(controlMessage(ctrl), 2000),
+			Severity:       bitbucketSeverity(ctrl.Severity),
+			Result:         "FAILED",
+		}
+		if path, line, ok := repoLocation(ctrl, e.config.Workspace); ok {
+			annotation.Path = path
+			annotation.Line = line
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations
+}
+
+// do sends a JSON request to the Bitbucket REST API.
+func (e *BitbucketExporter) do(ctx context.Context, method, path string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bitbucket request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, e.config.APIURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create bitbucket request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	switch {
+	case e.config.Token != "":
+		req.Header.Set("Authorization", "Bearer "+e.config.Token)
+	case e.config.User != "" && e.config.AppPassword != "":
+		req.SetBasicAuth(e.config.User, e.config.AppPassword)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("bitbucket request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("bitbucket %s %s returned %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// bitbucketSeverity maps control severity onto Code Insights severities.
+func bitbucketSeverity(severity string) string {
+	switch severity {
+	case "critical":
+		return "CRITICAL"
+	case "high":
+		return "HIGH"
+	case "medium":
+		return "MEDIUM"
+	default:
+		return "LOW"
+	}
+}
@@ -0,0 +1,133 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bitbucketReportPath = "/2.0/repositories/acme/infra/commit/abc123/reports/reglet-baseline"
+
+// fakeBitbucket records Code Insights reports and annotation batches.
+type fakeBitbucket struct {
+	report      map[string]interface{}
+	annotations [][]bitbucketAnnotation
+	auth        string
+	mu          sync.Mutex
+}
+
+func (f *fakeBitbucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.auth = r.Header.Get("Authorization")
+	body, _ := io.ReadAll(r.Body)
+	switch {
+	case r.Method == http.MethodPut && r.URL.Path == bitbucketReportPath:
+		_ = json.Unmarshal(body, &f.report)
+	case r.Method == http.MethodPost && r.URL.Path == bitbucketReportPath+"/annotations":
+		var batch []bitbucketAnnotation
+		_ = json.Unmarshal(body, &batch)
+		f.annotations = append(f.annotations, batch)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestBitbucketExporter(t *testing.T, server *httptest.Server, extra map[string]interface{}) *BitbucketExporter {
+	t.Helper()
+
+	config := map[string]interface{}{
+		"repository": "acme/infra",
+		"commit":     "abc123",
+		"api_url":    server.URL,
+	}
+	for k, v := range extra {
+		config[k] = v
+	}
+	e, err := NewBitbucketExporter(server.Client()).WithConfig(config)
+	require.NoError(t, err)
+	return e.(*BitbucketExporter)
+}
+
+func TestBitbucketExporter_Export(t *testing.T) {
+	t.Parallel()
+
+	bb := &fakeBitbucket{}
+	server := httptest.NewServer(bb)
+	defer server.Close()
+
+	workspace := t.TempDir()
+	e := newTestBitbucketExporter(t, server, map[string]interface{}{
+		"workspace": workspace,
+		"token":     "bb-token",
+	})
+	require.NoError(t, e.Export(context.Background(), ciTestResult(workspace)))
+
+	assert.Equal(t, "Bearer bb-token", bb.auth)
+	require.NotNil(t, bb.report)
+	assert.Equal(t, "FAILED", bb.report["result"])
+	assert.Equal(t, "SECURITY", bb.report["report_type"])
+
+	require.Len(t, bb.annotations, 1)
+	batch := bb.annotations[0]
+	require.Len(t, batch, 2)
+	assert.Equal(t, "dockerfile-user", batch[0].ExternalID)
+	assert.Equal(t, "Dockerfile", batch[0].Path)
+	assert.Equal(t, 12, batch[0].Line)
+	assert.Equal(t, "HIGH", batch[0].Severity)
+	assert.Empty(t, batch[1].Path)
+	assert.Equal(t, "CRITICAL", batch[1].Severity)
+}
+
+func TestBitbucketExporter_BatchesAnnotations(t *testing.T) {
+	t.Parallel()
+
+	bb := &fakeBitbucket{}
+	server := httptest.NewServer(bb)
+	defer server.Close()
+
+	e := newTestBitbucketExporter(t, server, map[string]interface{}{
+		"user":         "bot",
+		"app_password": "secret",
+	})
+
+	result := execution.NewExecutionResult("baseline", "1.0.0")
+	for i := range 250 {
+		result.Controls = append(result.Controls, execution.ControlResult{
+			ID: fmt.Sprintf("ctrl-%d", i), Status: values.StatusFail,
+		})
+	}
+	require.NoError(t, e.Export(context.Background(), result))
+
+	assert.Contains(t, bb.auth, "Basic ")
+	require.Len(t, bb.annotations, 3)
+	assert.Len(t, bb.annotations[2], 50)
+}
+
+func TestBitbucketExporter_RequiresEnvironment(t *testing.T) {
+	t.Setenv("BITBUCKET_WORKSPACE", "")
+	t.Setenv("BITBUCKET_REPO_SLUG", "")
+	t.Setenv("BITBUCKET_COMMIT", "")
+
+	_, err := NewBitbucketExporter(nil).WithConfig(nil)
+	assert.ErrorContains(t, err, "requires a repository and commit")
+
+	// Credentials are optional inside Pipelines
+	t.Setenv("BITBUCKET_WORKSPACE", "acme")
+	t.Setenv("BITBUCKET_REPO_SLUG", "infra")
+	t.Setenv("BITBUCKET_COMMIT", "abc123")
+	e, err := NewBitbucketExporter(nil).WithConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "acme/infra", e.(*BitbucketExporter).config.Repository)
+}
@@ -0,0 +1,87 @@
+// Package exporter provides result exporters: sinks that receive the
+// finalized execution result after output formatting, such as ticketing
+// systems or in-house report formats.
+//
+// Exporters are either compiled into the binary and registered from an init
+// function (typically in a file behind a build tag), or shipped as WASM
+// exporter plugins installed in the plugin directory.
+package exporter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+)
+
+// defaultRequestTimeout bounds each HTTP call made by the built-in exporters.
+const defaultRequestTimeout = 30 * time.Second
+
+// Registry holds compiled-in exporters by name.
+type Registry struct {
+	exporters map[string]ports.ResultExporter
+	mu        sync.RWMutex
+}
+
+// NewRegistry creates an empty exporter registry.
+func NewRegistry() *Registry {
+	return &Registry{exporters: make(map[string]ports.ResultExporter)}
+}
+
+// Register adds an exporter under its name.
+func (r *Registry) Register(e ports.ResultExporter) error {
+	if e == nil {
+		return fmt.Errorf("exporter is nil")
+	}
+	name := e.Name()
+	if name == "" {
+		return fmt.Errorf("exporter name cannot be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.exporters[name]; exists {
+		return fmt.Errorf("exporter %q already registered", name)
+	}
+	r.exporters[name] = e
+	return nil
+}
+
+// Lookup returns the exporter registered under name.
+func (r *Registry) Lookup(name string) (ports.ResultExporter, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.exporters[name]
+	return e, ok
+}
+
+// Names returns the registered exporter names in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.exporters))
+	for name := range r.exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default returns the process-wide registry used by --export.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register adds an exporter to the default registry. It is meant to be
+// called from an init function and panics on misuse.
+func Register(e ports.ResultExporter) {
+	if err := defaultRegistry.Register(e); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,99 @@
+package exporter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExporter struct {
+	name string
+}
+
+func (e *fakeExporter) Name() string { return e.name }
+
+func (e *fakeExporter) Export(_ context.Context, _ *execution.ExecutionResult) error { return nil }
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	require.NoError(t, r.Register(&fakeExporter{name: "jira"}))
+	require.NoError(t, r.Register(&fakeExporter{name: "csv"}))
+
+	assert.Error(t, r.Register(&fakeExporter{name: "jira"}), "duplicate names must be rejected")
+	assert.Error(t, r.Register(&fakeExporter{}), "empty names must be rejected")
+	assert.Error(t, r.Register(nil))
+
+	e, ok := r.Lookup("jira")
+	require.True(t, ok)
+	assert.Equal(t, "jira", e.Name())
+
+	_, ok = r.Lookup("servicenow")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"csv", "jira"}, r.Names())
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(&fakeExporter{name: "csv"}))
+	resolver := NewResolver(registry, t.TempDir(), nil)
+	ctx := context.Background()
+	defer func() { _ = resolver.Close(ctx) }()
+
+	e, err := resolver.Resolve(ctx, "csv")
+	require.NoError(t, err)
+	assert.Equal(t, "csv", e.Name())
+
+	_, err = resolver.Resolve(ctx, "jira")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no jira.wasm plugin installed")
+	assert.Contains(t, err.Error(), "[csv]")
+
+	_, err = resolver.Resolve(ctx, "../etc")
+	assert.ErrorContains(t, err, "invalid exporter name")
+}
+
+func TestResolver_PluginExporter(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("skipping WASM exporter test in short mode")
+	}
+
+	wasmBytes, err := os.ReadFile(filepath.Join("..", "..", "..", "plugins", "file", "file.wasm"))
+	if err != nil {
+		t.Skip("file.wasm not built - run 'make -C plugins/file build' first")
+	}
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "file"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "file", "file.wasm"), wasmBytes, 0o600))
+
+	var requested map[string][]capabilities.Capability
+	grant := func(required map[string][]capabilities.Capability) (map[string][]capabilities.Capability, error) {
+		requested = required
+		return nil, nil
+	}
+	resolver := NewResolver(NewRegistry(), pluginDir, grant)
+	ctx := context.Background()
+	defer func() { _ = resolver.Close(ctx) }()
+
+	e, err := resolver.Resolve(ctx, "file")
+	require.NoError(t, err)
+	assert.Equal(t, "file", e.Name())
+	assert.NotEmpty(t, requested["file"], "declared capabilities should go through the grant function")
+
+	// The file plugin does not understand the result config, so the export
+	// fails with the plugin's own error rather than a transport error.
+	err = e.Export(ctx, execution.NewExecutionResult("test-profile", "1.0.0"))
+	assert.Error(t, err)
+}
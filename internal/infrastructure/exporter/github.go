@@ -0,0 +1,260 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+const (
+	// GitHubExporterName selects the GitHub check-run publisher (--export github).
+	GitHubExporterName = "github"
+
+	// maxAnnotationsPerRequest is the GitHub API limit on annotations per call.
+	maxAnnotationsPerRequest = 50
+
+	gitHubAPIURL = "https://api.github.com"
+)
+
+func init() {
+	Register(NewGitHubExporter(nil))
+}
+
+// GitHubConfig is the optional integrations.github section of a profile.
+// Every field defaults to the variables GitHub Actions provides.
+type GitHubConfig struct {
+	Token      string `json:"token"`      // default: $GITHUB_TOKEN
+	Repository string `json:"repository"` // owner/repo, default: $GITHUB_REPOSITORY
+	SHA        string `json:"sha"`        // default: $GITHUB_SHA
+	APIURL     string `json:"api_url"`    // default: $GITHUB_API_URL or api.github.com
+	Workspace  string `json:"workspace"`  // repository checkout, default: $GITHUB_WORKSPACE or cwd
+	Name       string `json:"name"`       // check run name, default: "reglet: <profile>"
+}
+
+// GitHubExporter publishes the result as a GitHub check run. Failing controls
+// whose evidence points at a file in the repository become line annotations;
+// all failing controls are listed in the summary.
+type GitHubExporter struct {
+	client *http.Client
+	config *GitHubConfig
+}
+
+// Compile-time interface check
+var _ ports.ConfigurableExporter = (*GitHubExporter)(nil)
+
+// NewGitHubExporter creates an unconfigured GitHub exporter. If client is nil,
+// a client with a default timeout is used.
+func NewGitHubExporter(client *http.Client) *GitHubExporter {
+	if client == nil {
+		client = &http.Client{Timeout: defaultRequestTimeout}
+	}
+	return &GitHubExporter{client: client}
+}
+
+// Name returns the exporter name.
+func (e *GitHubExporter) Name() string {
+	return GitHubExporterName
+}
+
+// WithConfig returns a copy of the exporter using the integrations.github
+// section, falling back to the GitHub Actions environment.
+func (e *GitHubExporter) WithConfig(config map[string]interface{}) (ports.ResultExporter, error) {
+	var cfg GitHubConfig
+	if config != nil {
+		if err := decodeConfig(GitHubExporterName, config, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	setDefault(&cfg.Token, os.Getenv("GITHUB_TOKEN"))
+	setDefault(&cfg.Repository, os.Getenv("GITHUB_REPOSITORY"))
+	setDefault(&cfg.SHA, os.Getenv("GITHUB_SHA"))
+	setDefault(&cfg.APIURL, os.Getenv("GITHUB_API_URL"))
+	setDefault(&cfg.APIURL, gitHubAPIURL)
+	setDefault(&cfg.Workspace, os.Getenv("GITHUB_WORKSPACE"))
+	if cfg.Workspace == "" {
+		if cwd, err := os.Getwd(); err == nil {
+			cfg.Workspace = cwd
+		}
+	}
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/")
+
+	if cfg.Token == "" || cfg.Repository == "" || cfg.SHA == "" {
+		return nil, fmt.Errorf("github exporter requires a token, repository and commit SHA (set GITHUB_TOKEN, GITHUB_REPOSITORY and GITHUB_SHA or integrations.github)")
+	}
+
+	return &GitHubExporter{client: e.client, config: &cfg}, nil
+}
+
+// Export creates a completed check run for the result.
+func (e *GitHubExporter) Export(ctx context.Context, result *execution.ExecutionResult) error {
+	if e.config == nil {
+		return fmt.Errorf("github exporter is not configured")
+	}
+
+	name := e.config.Name
+	if name == "" {
+		name = "reglet: " + result.ProfileRef()
+	}
+	conclusion := "success"
+	if result.Summary.FailedControls > 0 || result.Summary.ErrorControls > 0 {
+		conclusion = "failure"
+	}
+	title := fmt.Sprintf("%d passed, %d failed, %d errors",
+		result.Summary.PassedControls, result.Summary.FailedControls, result.Summary.ErrorControls)
+	summary := gitHubSummary(result)
+	annotations := e.annotations(result)
+
+	first := annotations
+	if len(first) > maxAnnotationsPerRequest {
+		first = first[:maxAnnotationsPerRequest]
+	}
+	body := map[string]interface{}{
+		"name":       name,
+		"head_sha":   e.config.SHA,
+		"status":     "completed",
+		"conclusion": conclusion,
+		"output": map[string]interface{}{
+			"title":       title,
+			"summary":     summary,
+			"annotations": first,
+		},
+	}
+
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	checkRunsPath := "/repos/" + e.config.Repository + "/check-runs"
+	if err := e.do(ctx, http.MethodPost, checkRunsPath, body, &created); err != nil {
+		return err
+	}
+
+	// Remaining annotations are appended by updating the check run in batches
+	for start := maxAnnotationsPerRequest; start < len(annotations); start += maxAnnotationsPerRequest {
+		end := min(start+maxAnnotationsPerRequest, len(annotations))
+		update := map[string]interface{}{
+			"output": map[string]interface{}{
+				"title":       title,
+				"summary":     summary,
+				"annotations": annotations[start:end],
+			},
+		}
+		if err := e.do(ctx, http.MethodPatch, fmt.Sprintf("%s/%d", checkRunsPath, created.ID), update, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gitHubAnnotation is a check run annotation.
+type gitHubAnnotation struct {
+	Path            string `json:"path"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title"`
+	Message         string `json:"message"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+}
+
+// annotations returns one annotation per failing control whose evidence
+// references a file inside the workspace.
+func (e *GitHubExporter) annotations(result *execution.ExecutionResult) []gitHubAnnotation {
+	var annotations []gitHubAnnotation
+	for i := range result.Controls {
+		ctrl := &result.Controls[i]
+		if !failing(ctrl) {
+			continue
+		}
+		path, line, ok := repoLocation(ctrl, e.config.Workspace)
+		if !ok {
+			continue
+		}
+		annotations = append(annotations, gitHubAnnotation{
+			Path:            path,
+			StartLine:       line,
+			EndLine:         line,
+			AnnotationLevel: gitHubAnnotationLevel(ctrl.Severity),
+			Title:           fmt.Sprintf("%s (%s)", ctrl.Name, ctrl.ID),
+			Message:         controlMessage(ctrl),
+		})
+	}
+	return annotations
+}
+
+// do sends a JSON request to the GitHub REST API and decodes the response into out.
+func (e *GitHubExporter) do(ctx context.Context, method, path string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal github request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, e.config.APIURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create github request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.config.Token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("github request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("github %s %s returned %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode github response: %w", err)
+	}
+	return nil
+}
+
+// gitHubSummary renders the check run summary in Markdown.
+func gitHubSummary(result *execution.ExecutionResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s** %s: %d controls, %d passed, %d failed, %d errors, %d skipped.\n",
+		result.ProfileRef(), result.ProfileVersion, result.Summary.TotalControls,
+		result.Summary.PassedControls, result.Summary.FailedControls,
+		result.Summary.ErrorControls, result.Summary.SkippedControls)
+
+	header := false
+	for i := range result.Controls {
+		ctrl := &result.Controls[i]
+		if !failing(ctrl) {
+			continue
+		}
+		if !header {
+			b.WriteString("\n| Control | Severity | Status | Message |\n|---|---|---|---|\n")
+			header = true
+		}
+		fmt.Fprintf(&b, "| %s (`%s`) | %s | %s | %s |\n", ctrl.Name, ctrl.ID, ctrl.Severity, ctrl.Status,
+			strings.NewReplacer("|", `\|`, "\n", " ").Replace(ctrl.Message))
+	}
+	return b.String()
+}
+
+// gitHubAnnotationLevel maps control severity onto annotation levels.
+func gitHubAnnotationLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "failure"
+	case "medium":
+		return "warning"
+	default:
+		return "notice"
+	}
+}
@@ -0,0 +1,157 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHub records check-run creations and updates.
+type fakeGitHub struct {
+	created map[string]interface{}
+	updates []map[string]interface{}
+	mu      sync.Mutex
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer gh-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/infra/check-runs":
+		f.created = body
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":42}`))
+	case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/infra/check-runs/42":
+		f.updates = append(f.updates, body)
+		_, _ = w.Write([]byte(`{"id":42}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestGitHubExporter_Export(t *testing.T) {
+	t.Parallel()
+
+	gh := &fakeGitHub{}
+	server := httptest.NewServer(gh)
+	defer server.Close()
+
+	workspace := t.TempDir()
+	e, err := NewGitHubExporter(server.Client()).WithConfig(map[string]interface{}{
+		"token":      "gh-token",
+		"repository": "acme/infra",
+		"sha":        "abc123",
+		"api_url":    server.URL,
+		"workspace":  workspace,
+	})
+	require.NoError(t, err)
+
+	result := execution.NewExecutionResult("baseline", "1.0.0")
+	result.Controls = []execution.ControlResult{
+		{
+			ID: "dockerfile-user", Name: "Non-root user", Severity: "high", Status: values.StatusFail,
+			Message: "USER not set",
+			ObservationResults: []execution.ObservationResult{{
+				Evidence: &execution.Evidence{Data: map[string]interface{}{
+					"path": filepath.Join(workspace, "Dockerfile"),
+					"line": float64(12),
+				}},
+			}},
+		},
+		{
+			ID: "hosts", Name: "Hosts file", Severity: "medium", Status: values.StatusFail,
+			ObservationResults: []execution.ObservationResult{{
+				Evidence: &execution.Evidence{Data: map[string]interface{}{"path": "/etc/hosts"}},
+			}},
+		},
+		{ID: "tls", Name: "TLS", Severity: "low", Status: values.StatusPass},
+	}
+	result.Finalize()
+
+	require.NoError(t, e.Export(context.Background(), result))
+
+	require.NotNil(t, gh.created)
+	assert.Equal(t, "reglet: baseline", gh.created["name"])
+	assert.Equal(t, "abc123", gh.created["head_sha"])
+	assert.Equal(t, "failure", gh.created["conclusion"])
+
+	output := gh.created["output"].(map[string]interface{})
+	assert.Contains(t, output["summary"], "| Hosts file (`hosts`) | medium | fail |")
+
+	// Only the file inside the workspace becomes an annotation
+	annotations := output["annotations"].([]interface{})
+	require.Len(t, annotations, 1)
+	annotation := annotations[0].(map[string]interface{})
+	assert.Equal(t, "Dockerfile", annotation["path"])
+	assert.Equal(t, float64(12), annotation["start_line"])
+	assert.Equal(t, "failure", annotation["annotation_level"])
+	assert.Equal(t, "USER not set", annotation["message"])
+	assert.Empty(t, gh.updates)
+}
+
+func TestGitHubExporter_BatchesAnnotations(t *testing.T) {
+	t.Parallel()
+
+	gh := &fakeGitHub{}
+	server := httptest.NewServer(gh)
+	defer server.Close()
+
+	e, err := NewGitHubExporter(server.Client()).WithConfig(map[string]interface{}{
+		"token":      "gh-token",
+		"repository": "acme/infra",
+		"sha":        "abc123",
+		"api_url":    server.URL,
+		"workspace":  "/repo",
+	})
+	require.NoError(t, err)
+
+	result := execution.NewExecutionResult("baseline", "1.0.0")
+	for i := range 120 {
+		result.Controls = append(result.Controls, execution.ControlResult{
+			ID: fmt.Sprintf("ctrl-%d", i), Status: values.StatusFail,
+			ObservationResults: []execution.ObservationResult{{
+				Evidence: &execution.Evidence{Data: map[string]interface{}{"path": fmt.Sprintf("manifests/%d.yaml", i)}},
+			}},
+		})
+	}
+
+	require.NoError(t, e.Export(context.Background(), result))
+
+	assert.Len(t, gh.created["output"].(map[string]interface{})["annotations"], 50)
+	require.Len(t, gh.updates, 2)
+	assert.Len(t, gh.updates[0]["output"].(map[string]interface{})["annotations"], 50)
+	assert.Len(t, gh.updates[1]["output"].(map[string]interface{})["annotations"], 20)
+}
+
+func TestGitHubExporter_RequiresEnvironment(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_SHA", "")
+
+	_, err := NewGitHubExporter(nil).WithConfig(nil)
+	assert.ErrorContains(t, err, "requires a token, repository and commit SHA")
+
+	t.Setenv("GITHUB_TOKEN", "gh-token")
+	t.Setenv("GITHUB_REPOSITORY", "acme/infra")
+	t.Setenv("GITHUB_SHA", "abc123")
+	_, err = NewGitHubExporter(nil).WithConfig(nil)
+	assert.NoError(t, err)
+}
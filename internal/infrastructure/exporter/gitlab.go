@@ -0,0 +1,170 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/output"
+)
+
+const (
+	// GitLabExporterName selects the GitLab pipeline report publisher (--publish gitlab).
+	GitLabExporterName = "gitlab"
+
+	defaultCodeQualityReport = "gl-code-quality-report.json"
+	defaultJUnitReport       = "reglet-junit.xml"
+)
+
+func init() {
+	Register(NewGitLabExporter())
+}
+
+// GitLabConfig is the optional integrations.gitlab section of a profile.
+type GitLabConfig struct {
+	Workspace   string `json:"workspace"`    // project checkout, default: $CI_PROJECT_DIR or cwd
+	CodeQuality string `json:"code_quality"` // default: gl-code-quality-report.json
+	JUnit       string `json:"junit"`        // default: reglet-junit.xml
+}
+
+// GitLabExporter writes the result as GitLab Code Quality and JUnit report
+// artifacts. GitLab picks them up through the job's artifacts:reports section
+// and shows them on merge requests and the pipeline's test tab.
+type GitLabExporter struct {
+	config *GitLabConfig
+}
+
+// Compile-time interface check
+var _ ports.ConfigurableExporter = (*GitLabExporter)(nil)
+
+// NewGitLabExporter creates an unconfigured GitLab exporter.
+func NewGitLabExporter() *GitLabExporter {
+	return &GitLabExporter{}
+}
+
+// Name returns the exporter name.
+func (e *GitLabExporter) Name() string {
+	return GitLabExporterName
+}
+
+// WithConfig returns a copy of the exporter using the integrations.gitlab
+// section, falling back to the GitLab CI environment.
+func (e *GitLabExporter) WithConfig(config map[string]interface{}) (ports.ResultExporter, error) {
+	var cfg GitLabConfig
+	if config != nil {
+		if err := decodeConfig(GitLabExporterName, config, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	setDefault(&cfg.Workspace, os.Getenv("CI_PROJECT_DIR"))
+	if cfg.Workspace == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("gitlab exporter: failed to determine workspace: %w", err)
+		}
+		cfg.Workspace = cwd
+	}
+	setDefault(&cfg.CodeQuality, defaultCodeQualityReport)
+	setDefault(&cfg.JUnit, defaultJUnitReport)
+
+	return &GitLabExporter{config: &cfg}, nil
+}
+
+// Export writes the Code Quality and JUnit reports.
+func (e *GitLabExporter) Export(_ context.Context, result *execution.ExecutionResult) error {
+	if e.config == nil {
+		return fmt.Errorf("gitlab exporter is not configured")
+	}
+
+	data, err := json.MarshalIndent(e.codeQuality(result), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal code quality report: %w", err)
+	}
+	if err := e.write(e.config.CodeQuality, data); err != nil {
+		return err
+	}
+
+	var junit bytes.Buffer
+	if err := output.NewJUnitFormatter(&junit).Format(result); err != nil {
+		return fmt.Errorf("failed to format junit report: %w", err)
+	}
+	return e.write(e.config.JUnit, junit.Bytes())
+}
+
+// codeQualityIssue is an entry of a GitLab Code Quality (Code Climate) report.
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+// codeQuality returns one issue per failing control. Controls without a file
+// in the project are attributed to the profile so they still show up.
+func (e *GitLabExporter) codeQuality(result *execution.ExecutionResult) []codeQualityIssue {
+	issues := []codeQualityIssue{}
+	for i := range result.Controls {
+		ctrl := &result.Controls[i]
+		if !failing(ctrl) {
+			continue
+		}
+		path, line, ok := repoLocation(ctrl, e.config.Workspace)
+		if !ok {
+			path, line = result.ProfileName, 1
+		}
+
+		fingerprint := sha256.Sum256([]byte(result.ProfileRef() + "/" + ctrl.ID))
+		issue := codeQualityIssue{
+			Description: fmt.Sprintf("%s: %s", ctrl.Name, controlMessage(ctrl)),
+			CheckName:   ctrl.ID,
+			Fingerprint: hex.EncodeToString(fingerprint[:]),
+			Severity:    codeQualitySeverity(ctrl.Severity),
+		}
+		issue.Location.Path = path
+		issue.Location.Lines.Begin = line
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// write stores a report relative to the workspace.
+func (e *GitLabExporter) write(name string, data []byte) error {
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(e.config.Workspace, path)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write gitlab report %s: %w", path, err)
+	}
+	return nil
+}
+
+// codeQualitySeverity maps control severity onto Code Quality severities.
+func codeQualitySeverity(severity string) string {
+	switch severity {
+	case "critical":
+		return "critical"
+	case "high":
+		return "major"
+	case "medium":
+		return "minor"
+	default:
+		return "info"
+	}
+}
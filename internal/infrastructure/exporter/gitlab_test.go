@@ -0,0 +1,91 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ciTestResult returns a result with a failing control on a repository file,
+// a failing control on a host file and a passing control.
+func ciTestResult(workspace string) *execution.ExecutionResult {
+	result := execution.NewExecutionResult("baseline", "1.0.0")
+	result.Controls = []execution.ControlResult{
+		{
+			ID: "dockerfile-user", Name: "Non-root user", Severity: "high", Status: values.StatusFail,
+			Message: "USER not set",
+			ObservationResults: []execution.ObservationResult{{
+				Evidence: &execution.Evidence{Data: map[string]interface{}{
+					"path": filepath.Join(workspace, "Dockerfile"),
+					"line": float64(12),
+				}},
+			}},
+		},
+		{
+			ID: "hosts", Name: "Hosts file", Severity: "critical", Status: values.StatusFail,
+			ObservationResults: []execution.ObservationResult{{
+				Evidence: &execution.Evidence{Data: map[string]interface{}{"path": "/etc/hosts"}},
+			}},
+		},
+		{ID: "tls", Name: "TLS", Severity: "low", Status: values.StatusPass},
+	}
+	result.Finalize()
+	return result
+}
+
+func TestGitLabExporter_Export(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	e, err := NewGitLabExporter().WithConfig(map[string]interface{}{"workspace": workspace})
+	require.NoError(t, err)
+	require.NoError(t, e.Export(context.Background(), ciTestResult(workspace)))
+
+	data, err := os.ReadFile(filepath.Join(workspace, "gl-code-quality-report.json"))
+	require.NoError(t, err)
+	var issues []codeQualityIssue
+	require.NoError(t, json.Unmarshal(data, &issues))
+
+	require.Len(t, issues, 2)
+	assert.Equal(t, "dockerfile-user", issues[0].CheckName)
+	assert.Equal(t, "Dockerfile", issues[0].Location.Path)
+	assert.Equal(t, 12, issues[0].Location.Lines.Begin)
+	assert.Equal(t, "major", issues[0].Severity)
+	assert.Equal(t, "Non-root user: USER not set", issues[0].Description)
+
+	// Host files are attributed to the profile
+	assert.Equal(t, "baseline", issues[1].Location.Path)
+	assert.Equal(t, "critical", issues[1].Severity)
+	assert.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+
+	junit, err := os.ReadFile(filepath.Join(workspace, "reglet-junit.xml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(junit), "<testsuites")
+}
+
+func TestGitLabExporter_EmptyReport(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	e, err := NewGitLabExporter().WithConfig(map[string]interface{}{
+		"workspace":    workspace,
+		"code_quality": "reports/quality.json",
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.Mkdir(filepath.Join(workspace, "reports"), 0o755))
+
+	result := execution.NewExecutionResult("baseline", "1.0.0")
+	require.NoError(t, e.Export(context.Background(), result))
+
+	// GitLab expects an array even when nothing failed
+	data, err := os.ReadFile(filepath.Join(workspace, "reports", "quality.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(data))
+}
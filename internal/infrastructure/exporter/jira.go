@@ -0,0 +1,297 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+const (
+	// JiraExporterName selects the Jira exporter (--export jira).
+	JiraExporterName = "jira"
+
+	// JiraTokenEnv is read when the integration section sets no token.
+	JiraTokenEnv = "JIRA_API_TOKEN"
+
+	// maxEvidenceSnippet caps the evidence JSON quoted per observation.
+	maxEvidenceSnippet = 2000
+)
+
+func init() {
+	Register(NewJiraExporter(nil))
+}
+
+// JiraConfig is the integrations.jira section of a profile.
+type JiraConfig struct {
+	// Priorities maps control severity to Jira priority name.
+	Priorities map[string]string `json:"priorities"`
+	// TagLabels renames control tags when they become labels; unmapped tags
+	// are used as-is.
+	TagLabels map[string]string `json:"tag_labels"`
+	URL       string            `json:"url"`
+	Project   string            `json:"project"`
+	IssueType string            `json:"issue_type"`
+	User      string            `json:"user"`
+	Token     string            `json:"token"`
+	// Severities selects which failing controls get an issue (default: critical).
+	Severities []string `json:"severities"`
+	// Labels are added to every issue.
+	Labels []string `json:"labels"`
+}
+
+// JiraExporter opens a Jira issue for each failing control of a selected
+// severity. Issues carry a label derived from the profile and control ID; if
+// an open issue with that label exists, a comment with the new evidence is
+// added instead of opening a duplicate.
+type JiraExporter struct {
+	client *http.Client
+	config *JiraConfig
+}
+
+// Compile-time interface check
+var _ ports.ConfigurableExporter = (*JiraExporter)(nil)
+
+// NewJiraExporter creates an unconfigured Jira exporter. If client is nil, a
+// client with a default timeout is used.
+func NewJiraExporter(client *http.Client) *JiraExporter {
+	if client == nil {
+		client = &http.Client{Timeout: defaultRequestTimeout}
+	}
+	return &JiraExporter{client: client}
+}
+
+// Name returns the exporter name.
+func (e *JiraExporter) Name() string {
+	return JiraExporterName
+}
+
+// WithConfig returns a copy of the exporter using the integrations.jira section.
+func (e *JiraExporter) WithConfig(config map[string]interface{}) (ports.ResultExporter, error) {
+	var cfg JiraConfig
+	if err := decodeConfig(JiraExporterName, config, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.URL == "" || cfg.Project == "" {
+		return nil, fmt.Errorf("integrations.%s requires url and project", JiraExporterName)
+	}
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+	if cfg.IssueType == "" {
+		cfg.IssueType = "Bug"
+	}
+	if len(cfg.Severities) == 0 {
+		cfg.Severities = []string{"critical"}
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv(JiraTokenEnv)
+	}
+
+	return &JiraExporter{client: e.client, config: &cfg}, nil
+}
+
+// Export opens or updates an issue for every matching failing control.
+func (e *JiraExporter) Export(ctx context.Context, result *execution.ExecutionResult) error {
+	if e.config == nil {
+		return fmt.Errorf("profile has no integrations.%s section", JiraExporterName)
+	}
+
+	var errs []error
+	for i := range result.Controls {
+		ctrl := &result.Controls[i]
+		if !e.selects(ctrl) {
+			continue
+		}
+		if err := e.exportControl(ctx, result, ctrl); err != nil {
+			errs = append(errs, fmt.Errorf("control %s: %w", ctrl.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// selects reports whether a control should be ticketed.
+func (e *JiraExporter) selects(ctrl *execution.ControlResult) bool {
+	if ctrl.Status != values.StatusFail {
+		return false
+	}
+	return slices.ContainsFunc(e.config.Severities, func(s string) bool {
+		return strings.EqualFold(s, ctrl.Severity)
+	})
+}
+
+// exportControl comments on the open issue for a control, or creates one.
+func (e *JiraExporter) exportControl(ctx context.Context, result *execution.ExecutionResult, ctrl *execution.ControlResult) error {
+	dedupLabel := jiraLabel("reglet-" + result.ProfileRef() + "-" + ctrl.ID)
+	description := jiraDescription(result, ctrl)
+
+	key, err := e.findOpenIssue(ctx, dedupLabel)
+	if err != nil {
+		return err
+	}
+	if key != "" {
+		return e.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/comment",
+			map[string]interface{}{"body": "Control still failing.\n\n" + description}, nil)
+	}
+
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": e.config.Project},
+		"issuetype":   map[string]string{"name": e.config.IssueType},
+		"summary":     fmt.Sprintf("[reglet] %s (%s) failed", ctrl.Name, ctrl.ID),
+		"description": description,
+		"labels":      e.labels(ctrl, dedupLabel),
+	}
+	if priority := e.config.Priorities[ctrl.Severity]; priority != "" {
+		fields["priority"] = map[string]string{"name": priority}
+	}
+	return e.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]interface{}{"fields": fields}, nil)
+}
+
+// findOpenIssue returns the key of an unresolved issue carrying label.
+func (e *JiraExporter) findOpenIssue(ctx context.Context, label string) (string, error) {
+	jql := fmt.Sprintf(`project = %q AND labels = %q AND statusCategory != Done`, e.config.Project, label)
+	query := url.Values{"jql": {jql}, "fields": {"key"}, "maxResults": {"1"}}
+
+	var resp struct {
+		Issues []struct {
+			Key string `json:"key"`
+		} `json:"issues"`
+	}
+	if err := e.do(ctx, http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Issues) == 0 {
+		return "", nil
+	}
+	return resp.Issues[0].Key, nil
+}
+
+// labels returns the configured labels, the control's tags and the dedup label.
+func (e *JiraExporter) labels(ctrl *execution.ControlResult, dedupLabel string) []string {
+	labels := make([]string, 0, len(e.config.Labels)+len(ctrl.Tags)+1)
+	add := func(label string) {
+		label = jiraLabel(label)
+		if label != "" && !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+
+	for _, label := range e.config.Labels {
+		add(label)
+	}
+	for _, tag := range ctrl.Tags {
+		if mapped, ok := e.config.TagLabels[tag]; ok {
+			add(mapped)
+			continue
+		}
+		add(tag)
+	}
+	add(dedupLabel)
+	return labels
+}
+
+// do sends a JSON request to the Jira REST API and decodes the response into out.
+func (e *JiraExporter) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal jira request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, e.config.URL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create jira request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case e.config.User != "":
+		req.SetBasicAuth(e.config.User, e.config.Token)
+	case e.config.Token != "":
+		req.Header.Set("Authorization", "Bearer "+e.config.Token)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("jira %s %s returned %s: %s", method, strings.SplitN(path, "?", 2)[0], resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode jira response: %w", err)
+	}
+	return nil
+}
+
+// jiraDescription renders a control failure in Jira wiki markup, quoting a
+// snippet of each failing observation's evidence.
+func jiraDescription(result *execution.ExecutionResult, ctrl *execution.ControlResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Control *%s* failed in profile %s %s.\n\n", ctrl.ID, result.ProfileRef(), result.ProfileVersion)
+	fmt.Fprintf(&b, "* Severity: %s\n", ctrl.Severity)
+	if labels := ctrl.LabelPairs(); len(labels) > 0 {
+		fmt.Fprintf(&b, "* Labels: %s\n", strings.Join(labels, ", "))
+	}
+	if ctrl.Message != "" {
+		fmt.Fprintf(&b, "* Message: %s\n", ctrl.Message)
+	}
+	fmt.Fprintf(&b, "* Execution: %s\n", result.ExecutionID)
+
+	for i := range ctrl.ObservationResults {
+		obs := &ctrl.ObservationResults[i]
+		if obs.Status == values.StatusPass {
+			continue
+		}
+		fmt.Fprintf(&b, "\nh3. Observation %d: %s (%s)\n", i+1, obs.Plugin, obs.Status)
+		for _, exp := range obs.Expectations {
+			if exp.Passed {
+				continue
+			}
+			fmt.Fprintf(&b, "* {{%s}}", exp.Expression)
+			if exp.Message != "" {
+				fmt.Fprintf(&b, ": %s", exp.Message)
+			}
+			b.WriteString("\n")
+		}
+		if obs.Error != nil {
+			fmt.Fprintf(&b, "* Error: %s\n", obs.Error.Message)
+		}
+		if obs.Evidence != nil && obs.Evidence.Data != nil {
+			if data, err := json.MarshalIndent(obs.Evidence.Data, "", "  "); err == nil {
+				snippet := string(data)
+				if len(snippet) > maxEvidenceSnippet {
+					snippet = snippet[:maxEvidenceSnippet] + "\n... (truncated)"
+				}
+				fmt.Fprintf(&b, "{code:json}\n%s\n{code}\n", snippet)
+			}
+		}
+	}
+	return b.String()
+}
+
+// jiraLabel turns s into a valid Jira label, which cannot contain spaces.
+func jiraLabel(s string) string {
+	return strings.Join(strings.Fields(s), "-")
+}
@@ -0,0 +1,162 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJira records created issues and comments. Issues whose dedup label is
+// in open are reported as unresolved by search.
+type fakeJira struct {
+	open     map[string]string // label -> issue key
+	created  []map[string]interface{}
+	comments map[string][]string
+	mu       sync.Mutex
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if user, pass, ok := r.BasicAuth(); !ok || user != "bot@example.com" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/search":
+		jql := r.URL.Query().Get("jql")
+		var issues []map[string]string
+		for label, key := range f.open {
+			if strings.Contains(jql, `labels = "`+label+`"`) {
+				issues = append(issues, map[string]string{"key": key})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"issues": issues})
+	case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+		var body map[string]map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.created = append(f.created, body["fields"])
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"key":"SEC-100"}`))
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/comment"):
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		key := strings.Split(r.URL.Path, "/")[5]
+		f.comments[key] = append(f.comments[key], body["body"])
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func jiraTestResult() *execution.ExecutionResult {
+	result := execution.NewExecutionResult("baseline", "1.0.0")
+	result.Controls = []execution.ControlResult{
+		{
+			ID: "ssh-root-login", Name: "SSH root login disabled", Severity: "critical",
+			Status: values.StatusFail, Tags: []string{"ssh", "cis benchmark"},
+			ObservationResults: []execution.ObservationResult{{
+				Plugin: "file",
+				Status: values.StatusFail,
+				Evidence: &execution.Evidence{
+					Status: true,
+					Data:   map[string]interface{}{"content": "PermitRootLogin yes"},
+				},
+				Expectations: []execution.ExpectationResult{
+					{Expression: `data.content contains "PermitRootLogin no"`, Message: "content does not match"},
+				},
+			}},
+		},
+		{ID: "tls-version", Name: "TLS 1.2+", Severity: "critical", Status: values.StatusFail},
+		{ID: "motd", Name: "MOTD present", Severity: "low", Status: values.StatusFail},
+		{ID: "firewall", Name: "Firewall on", Severity: "critical", Status: values.StatusPass},
+	}
+	return result
+}
+
+func TestJiraExporter_Export(t *testing.T) {
+	t.Parallel()
+
+	jira := &fakeJira{
+		open:     map[string]string{"reglet-baseline-tls-version": "SEC-7"},
+		comments: make(map[string][]string),
+	}
+	server := httptest.NewServer(jira)
+	defer server.Close()
+
+	e, err := NewJiraExporter(server.Client()).WithConfig(map[string]interface{}{
+		"url":        server.URL + "/",
+		"project":    "SEC",
+		"user":       "bot@example.com",
+		"token":      "secret",
+		"labels":     []interface{}{"compliance"},
+		"tag_labels": map[string]interface{}{"ssh": "openssh"},
+		"priorities": map[string]interface{}{"critical": "Highest"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, e.Export(context.Background(), jiraTestResult()))
+
+	// Only the critical failing control without an open issue gets a new one
+	require.Len(t, jira.created, 1)
+	issue := jira.created[0]
+	assert.Equal(t, "[reglet] SSH root login disabled (ssh-root-login) failed", issue["summary"])
+	assert.Equal(t, map[string]interface{}{"key": "SEC"}, issue["project"])
+	assert.Equal(t, map[string]interface{}{"name": "Bug"}, issue["issuetype"])
+	assert.Equal(t, map[string]interface{}{"name": "Highest"}, issue["priority"])
+	assert.Equal(t, []interface{}{"compliance", "openssh", "cis-benchmark", "reglet-baseline-ssh-root-login"}, issue["labels"])
+	assert.Contains(t, issue["description"], "PermitRootLogin yes")
+	assert.Contains(t, issue["description"], "content does not match")
+
+	// The control with an open issue is deduplicated into a comment
+	require.Len(t, jira.comments["SEC-7"], 1)
+	assert.Contains(t, jira.comments["SEC-7"][0], "Control still failing")
+}
+
+func TestJiraExporter_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewJiraExporter(nil).WithConfig(nil)
+	assert.ErrorContains(t, err, "no integrations.jira section")
+
+	_, err = NewJiraExporter(nil).WithConfig(map[string]interface{}{"url": "https://jira.example.com"})
+	assert.ErrorContains(t, err, "requires url and project")
+
+	err = NewJiraExporter(nil).Export(context.Background(), jiraTestResult())
+	assert.ErrorContains(t, err, "no integrations.jira section")
+
+	server := httptest.NewServer(&fakeJira{comments: make(map[string][]string)})
+	defer server.Close()
+
+	var e ports.ResultExporter
+	e, err = NewJiraExporter(server.Client()).WithConfig(map[string]interface{}{
+		"url":     server.URL,
+		"project": "SEC",
+		"user":    "bot@example.com",
+		"token":   "wrong",
+	})
+	require.NoError(t, err)
+	err = e.Export(context.Background(), jiraTestResult())
+	assert.ErrorContains(t, err, "401 Unauthorized")
+}
+
+func TestJiraExporter_Registered(t *testing.T) {
+	t.Parallel()
+
+	e, ok := Default().Lookup(JiraExporterName)
+	require.True(t, ok)
+	assert.IsType(t, &JiraExporter{}, e)
+}
@@ -0,0 +1,144 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+const (
+	// OpsgenieExporterName selects the Opsgenie exporter (--export opsgenie).
+	OpsgenieExporterName = "opsgenie"
+
+	// OpsgenieAPIKeyEnv is read when the integration section sets no API key.
+	OpsgenieAPIKeyEnv = "OPSGENIE_API_KEY"
+
+	opsgenieAPIURL = "https://api.opsgenie.com"
+)
+
+func init() {
+	Register(NewOpsgenieExporter(nil))
+}
+
+// OpsgenieConfig is the integrations.opsgenie section of a profile.
+type OpsgenieConfig struct {
+	APIKey string `json:"api_key"`
+	AlertConfig
+}
+
+// OpsgenieExporter creates an Opsgenie alert for each selected failing
+// control and closes it once the control passes. The dedup key is used as
+// the alert alias, so repeated failures update the same alert.
+type OpsgenieExporter struct {
+	client *http.Client
+	config *OpsgenieConfig
+}
+
+// Compile-time interface check
+var _ ports.ConfigurableExporter = (*OpsgenieExporter)(nil)
+
+// NewOpsgenieExporter creates an unconfigured Opsgenie exporter. If client is
+// nil, a client with a default timeout is used.
+func NewOpsgenieExporter(client *http.Client) *OpsgenieExporter {
+	if client == nil {
+		client = &http.Client{Timeout: defaultRequestTimeout}
+	}
+	return &OpsgenieExporter{client: client}
+}
+
+// Name returns the exporter name.
+func (e *OpsgenieExporter) Name() string {
+	return OpsgenieExporterName
+}
+
+// WithConfig returns a copy of the exporter using the integrations.opsgenie section.
+func (e *OpsgenieExporter) WithConfig(config map[string]interface{}) (ports.ResultExporter, error) {
+	var cfg OpsgenieConfig
+	if err := decodeConfig(OpsgenieExporterName, config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.APIKey == "" {
+		cfg.APIKey = os.Getenv(OpsgenieAPIKeyEnv)
+	}
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("integrations.%s requires api_key or $%s", OpsgenieExporterName, OpsgenieAPIKeyEnv)
+	}
+	cfg.applyDefaults(opsgenieAPIURL)
+
+	return &OpsgenieExporter{client: e.client, config: &cfg}, nil
+}
+
+// Export creates alerts for failing controls and closes those of passing ones.
+func (e *OpsgenieExporter) Export(ctx context.Context, result *execution.ExecutionResult) error {
+	if e.config == nil {
+		return fmt.Errorf("profile has no integrations.%s section", OpsgenieExporterName)
+	}
+
+	headers := map[string]string{"Authorization": "GenieKey " + e.config.APIKey}
+	trigger, resolve := e.config.alerts(result)
+
+	var errs []error
+	for _, a := range trigger {
+		body := map[string]interface{}{
+			"message":  truncate(a.Summary, 130),
+			"alias":    a.DedupKey,
+			"source":   a.Source,
+			"priority": opsgeniePriority(a.Severity),
+			"tags":     a.Tags,
+			"details":  stringDetails(a.Details),
+		}
+		if err := postJSON(ctx, e.client, e.config.URL+"/v2/alerts", headers, body); err != nil {
+			errs = append(errs, fmt.Errorf("control %s: %w", a.ControlID, err))
+		}
+	}
+	for _, a := range resolve {
+		closeURL := e.config.URL + "/v2/alerts/" + url.PathEscape(a.DedupKey) + "/close?identifierType=alias"
+		body := map[string]interface{}{"source": a.Source, "note": "Control passed"}
+		// Closing an alert that was never opened is not an error
+		if err := postJSON(ctx, e.client, closeURL, headers, body, http.StatusNotFound); err != nil {
+			errs = append(errs, fmt.Errorf("control %s: %w", a.ControlID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// opsgeniePriority maps a control severity onto Opsgenie priorities P1-P5.
+func opsgeniePriority(severity string) string {
+	switch severity {
+	case "critical":
+		return "P1"
+	case "high":
+		return "P2"
+	case "low":
+		return "P4"
+	case "info":
+		return "P5"
+	default:
+		return "P3"
+	}
+}
+
+// stringDetails converts alert details to the string map Opsgenie expects.
+func stringDetails(details map[string]interface{}) map[string]string {
+	out := make(map[string]string, len(details))
+	for k, v := range details {
+		if s := fmt.Sprint(v); s != "" {
+			out[k] = s
+		}
+	}
+	return out
+}
+
+// truncate shortens s to at most n bytes.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
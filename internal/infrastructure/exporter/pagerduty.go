@@ -0,0 +1,129 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+const (
+	// PagerDutyExporterName selects the PagerDuty exporter (--export pagerduty).
+	PagerDutyExporterName = "pagerduty"
+
+	// PagerDutyRoutingKeyEnv is read when the integration section sets no routing key.
+	PagerDutyRoutingKeyEnv = "PAGERDUTY_ROUTING_KEY"
+
+	pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+)
+
+func init() {
+	Register(NewPagerDutyExporter(nil))
+}
+
+// PagerDutyConfig is the integrations.pagerduty section of a profile.
+type PagerDutyConfig struct {
+	RoutingKey string `json:"routing_key"`
+	AlertConfig
+}
+
+// PagerDutyExporter triggers a PagerDuty incident through the Events API v2
+// for each selected failing control and resolves it once the control passes.
+type PagerDutyExporter struct {
+	client *http.Client
+	config *PagerDutyConfig
+}
+
+// Compile-time interface check
+var _ ports.ConfigurableExporter = (*PagerDutyExporter)(nil)
+
+// NewPagerDutyExporter creates an unconfigured PagerDuty exporter. If client
+// is nil, a client with a default timeout is used.
+func NewPagerDutyExporter(client *http.Client) *PagerDutyExporter {
+	if client == nil {
+		client = &http.Client{Timeout: defaultRequestTimeout}
+	}
+	return &PagerDutyExporter{client: client}
+}
+
+// Name returns the exporter name.
+func (e *PagerDutyExporter) Name() string {
+	return PagerDutyExporterName
+}
+
+// WithConfig returns a copy of the exporter using the integrations.pagerduty section.
+func (e *PagerDutyExporter) WithConfig(config map[string]interface{}) (ports.ResultExporter, error) {
+	var cfg PagerDutyConfig
+	if err := decodeConfig(PagerDutyExporterName, config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.RoutingKey == "" {
+		cfg.RoutingKey = os.Getenv(PagerDutyRoutingKeyEnv)
+	}
+	if cfg.RoutingKey == "" {
+		return nil, fmt.Errorf("integrations.%s requires routing_key or $%s", PagerDutyExporterName, PagerDutyRoutingKeyEnv)
+	}
+	cfg.applyDefaults(pagerDutyEventsURL)
+
+	return &PagerDutyExporter{client: e.client, config: &cfg}, nil
+}
+
+// Export triggers incidents for failing controls and resolves those of passing ones.
+func (e *PagerDutyExporter) Export(ctx context.Context, result *execution.ExecutionResult) error {
+	if e.config == nil {
+		return fmt.Errorf("profile has no integrations.%s section", PagerDutyExporterName)
+	}
+
+	trigger, resolve := e.config.alerts(result)
+
+	var errs []error
+	for _, a := range trigger {
+		event := map[string]interface{}{
+			"routing_key":  e.config.RoutingKey,
+			"event_action": "trigger",
+			"dedup_key":    a.DedupKey,
+			"payload": map[string]interface{}{
+				"summary":        a.Summary,
+				"source":         a.Source,
+				"severity":       pagerDutySeverity(a.Severity),
+				"component":      a.ControlID,
+				"class":          "compliance",
+				"custom_details": a.Details,
+			},
+		}
+		if err := postJSON(ctx, e.client, e.config.URL, nil, event); err != nil {
+			errs = append(errs, fmt.Errorf("control %s: %w", a.ControlID, err))
+		}
+	}
+	for _, a := range resolve {
+		event := map[string]interface{}{
+			"routing_key":  e.config.RoutingKey,
+			"event_action": "resolve",
+			"dedup_key":    a.DedupKey,
+		}
+		if err := postJSON(ctx, e.client, e.config.URL, nil, event); err != nil {
+			errs = append(errs, fmt.Errorf("control %s: %w", a.ControlID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pagerDutySeverity maps a control severity onto the four PagerDuty levels.
+func pagerDutySeverity(severity string) string {
+	switch severity {
+	case "critical":
+		return "critical"
+	case "high":
+		return "error"
+	case "medium":
+		return "warning"
+	case "low", "info":
+		return "info"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,83 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+)
+
+// ResultConfigKey is the config key under which a WASM exporter plugin
+// receives the execution result, in the same JSON shape as --format json.
+const ResultConfigKey = "result"
+
+// PluginExporter runs a WASM plugin as an exporter. Exporter plugins use the
+// regular plugin ABI: observe() is called once with the result in its config,
+// next to the settings from the profile's integrations section, and evidence
+// with a false status reports a failed export.
+type PluginExporter struct {
+	plugin *wasm.Plugin
+	config map[string]interface{}
+	name   string
+}
+
+// Compile-time interface check
+var _ ports.ConfigurableExporter = (*PluginExporter)(nil)
+
+// NewPluginExporter wraps a loaded WASM plugin as an exporter.
+func NewPluginExporter(name string, plugin *wasm.Plugin) *PluginExporter {
+	return &PluginExporter{name: name, plugin: plugin}
+}
+
+// Name returns the exporter name.
+func (e *PluginExporter) Name() string {
+	return e.name
+}
+
+// WithConfig returns a copy of the exporter that passes config to the plugin.
+func (e *PluginExporter) WithConfig(config map[string]interface{}) (ports.ResultExporter, error) {
+	if _, ok := config[ResultConfigKey]; ok {
+		return nil, fmt.Errorf("integrations.%s cannot set %q", e.name, ResultConfigKey)
+	}
+	return &PluginExporter{name: e.name, plugin: e.plugin, config: config}, nil
+}
+
+// Export passes the result to the plugin's observe function.
+func (e *PluginExporter) Export(ctx context.Context, result *execution.ExecutionResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	values := make(map[string]interface{}, len(e.config)+1)
+	for k, v := range e.config {
+		values[k] = v
+	}
+	values[ResultConfigKey] = payload
+
+	out, err := e.plugin.Observe(ctx, wasm.Config{Values: values})
+	if err != nil {
+		return err
+	}
+
+	if out.Error != nil {
+		return fmt.Errorf("%s: %s", out.Error.Code, out.Error.Message)
+	}
+	if out.Evidence == nil {
+		return fmt.Errorf("exporter plugin returned no evidence")
+	}
+	if out.Evidence.Error != nil {
+		return fmt.Errorf("%s: %s", out.Evidence.Error.Code, out.Evidence.Error.Message)
+	}
+	if !out.Evidence.Status {
+		return fmt.Errorf("exporter plugin reported failure")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package exporter
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// Helpers shared by the CI report publishers (GitHub, GitLab, Bitbucket).
+
+// failing reports whether a control should appear in a CI report.
+func failing(ctrl *execution.ControlResult) bool {
+	return ctrl.Status == values.StatusFail || ctrl.Status == values.StatusError
+}
+
+// repoLocation finds a file inside the repository checkout, and a line in it,
+// in a control's evidence. Relative paths are resolved against workspace.
+func repoLocation(ctrl *execution.ControlResult, workspace string) (string, int, bool) {
+	for _, obs := range ctrl.ObservationResults {
+		if obs.Evidence == nil || obs.Evidence.Data == nil {
+			continue
+		}
+		path, ok := obs.Evidence.Data["path"].(string)
+		if !ok || path == "" {
+			continue
+		}
+
+		abs := path
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(workspace, abs)
+		}
+		rel, err := filepath.Rel(workspace, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue // not a repository artifact
+		}
+
+		line := 1
+		for _, key := range []string{"line", "start_line", "lineNumber"} {
+			if v, ok := obs.Evidence.Data[key].(float64); ok && v > 0 {
+				line = int(v)
+				break
+			}
+			if v, ok := obs.Evidence.Data[key].(int); ok && v > 0 {
+				line = v
+				break
+			}
+		}
+		return filepath.ToSlash(rel), line, true
+	}
+	return "", 0, false
+}
+
+// controlMessage returns the control's message or a generic description.
+func controlMessage(ctrl *execution.ControlResult) string {
+	if ctrl.Message != "" {
+		return ctrl.Message
+	}
+	return "Control " + ctrl.ID + " " + string(ctrl.Status)
+}
+
+// setDefault assigns value to *field when the field is empty.
+func setDefault(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
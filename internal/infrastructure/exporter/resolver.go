@@ -0,0 +1,142 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+)
+
+// GrantFunc decides which of an exporter plugin's declared capabilities are
+// granted, typically by prompting or consulting the system config.
+type GrantFunc func(required map[string][]capabilities.Capability) (map[string][]capabilities.Capability, error)
+
+// Resolver finds exporters in a registry first and then as WASM exporter
+// plugins laid out as <pluginDir>/<name>/<name>.wasm.
+type Resolver struct {
+	registry  *Registry
+	grant     GrantFunc
+	pluginDir string
+	runtimes  []*wasm.Runtime
+	mu        sync.Mutex
+}
+
+// Compile-time interface check
+var _ ports.ResultExporterResolver = (*Resolver)(nil)
+
+// NewResolver creates a resolver. grant may be nil, in which case exporter
+// plugins run without any capabilities.
+func NewResolver(registry *Registry, pluginDir string, grant GrantFunc) *Resolver {
+	return &Resolver{
+		registry:  registry,
+		pluginDir: pluginDir,
+		grant:     grant,
+	}
+}
+
+// Resolve returns the exporter registered or installed under name.
+func (r *Resolver) Resolve(ctx context.Context, name string) (ports.ResultExporter, error) {
+	if e, ok := r.registry.Lookup(name); ok {
+		return e, nil
+	}
+	return r.loadPlugin(ctx, name)
+}
+
+// loadPlugin loads a WASM exporter plugin with the capabilities granted for it.
+func (r *Resolver) loadPlugin(ctx context.Context, name string) (ports.ResultExporter, error) {
+	validName, err := values.NewPluginName(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exporter name: %w", err)
+	}
+	safeName := validName.String()
+
+	// SECURITY: Use os.OpenRoot to prevent symlink-based path traversal.
+	rootDir, err := os.OpenRoot(r.pluginDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin directory %s: %w", r.pluginDir, err)
+	}
+	defer func() { _ = rootDir.Close() }()
+
+	wasmBytes, err := rootDir.ReadFile(filepath.Join(safeName, safeName+".wasm"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no exporter registered as %q and no %s.wasm plugin installed (registered: %v)",
+				safeName, safeName, r.registry.Names())
+		}
+		return nil, fmt.Errorf("failed to read exporter plugin %s: %w", safeName, err)
+	}
+
+	granted, err := r.grantCapabilities(ctx, safeName, wasmBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	runtime, err := wasm.NewRuntimeWithCapabilities(ctx, build.Get(), granted, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	plugin, err := runtime.LoadPlugin(ctx, safeName, wasmBytes)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to load exporter plugin %s: %w", safeName, err)
+	}
+
+	r.mu.Lock()
+	r.runtimes = append(r.runtimes, runtime)
+	r.mu.Unlock()
+
+	return NewPluginExporter(safeName, plugin), nil
+}
+
+// grantCapabilities describes the plugin in an unprivileged runtime and asks
+// the grant function for the capabilities it declares.
+func (r *Resolver) grantCapabilities(ctx context.Context, name string, wasmBytes []byte) (map[string][]capabilities.Capability, error) {
+	if r.grant == nil {
+		return nil, nil
+	}
+
+	runtime, err := wasm.NewRuntime(ctx, build.Get())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = runtime.Close(ctx) }()
+
+	plugin, err := runtime.LoadPlugin(ctx, name, wasmBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exporter plugin %s: %w", name, err)
+	}
+	info, err := plugin.Describe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe exporter plugin %s: %w", name, err)
+	}
+	if len(info.Capabilities) == 0 {
+		return nil, nil
+	}
+
+	granted, err := r.grant(map[string][]capabilities.Capability{name: info.Capabilities})
+	if err != nil {
+		return nil, fmt.Errorf("capabilities for exporter plugin %s not granted: %w", name, err)
+	}
+	return granted, nil
+}
+
+// Close releases the runtimes of loaded exporter plugins.
+func (r *Resolver) Close(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for _, runtime := range r.runtimes {
+		errs = append(errs, runtime.Close(ctx))
+	}
+	r.runtimes = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,104 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// WorkspacePluginScanner implements ports.PluginReferenceScanner by reading
+// the profiles and reglet.lock files of a directory tree.
+type WorkspacePluginScanner struct {
+	lockfiles *FileLockfileRepository
+}
+
+// NewWorkspacePluginScanner creates a new WorkspacePluginScanner.
+func NewWorkspacePluginScanner() *WorkspacePluginScanner {
+	return &WorkspacePluginScanner{lockfiles: NewFileLockfileRepository()}
+}
+
+// ScanPluginReferences returns the plugins declared by the YAML profiles and
+// pinned by the lockfiles under dir. Hidden directories are skipped, as are
+// YAML files that are not profiles. Lockfile pins reference their resolved
+// version.
+func (s *WorkspacePluginScanner) ScanPluginReferences(ctx context.Context, dir string) ([]*entities.PluginSpec, error) {
+	var specs []*entities.PluginSpec
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case d.Name() == "reglet.lock":
+			lock, err := s.lockfiles.Load(ctx, path)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			if lock != nil {
+				specs = append(specs, lockedPlugins(lock)...)
+			}
+		case strings.HasSuffix(d.Name(), ".yaml") || strings.HasSuffix(d.Name(), ".yml"):
+			specs = append(specs, declaredPlugins(path)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+// declaredPlugins returns the plugins a profile declares, or none if the file
+// is not a profile.
+func declaredPlugins(path string) []*entities.PluginSpec {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil
+	}
+	var doc struct {
+		Plugins entities.PluginDeclarations `yaml:"plugins"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	specs := make([]*entities.PluginSpec, 0, len(doc.Plugins))
+	for _, declaration := range doc.Plugins {
+		if spec, err := entities.ParsePluginDeclaration(declaration); err == nil {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// lockedPlugins returns the plugin versions a lockfile pins.
+func lockedPlugins(lock *entities.Lockfile) []*entities.PluginSpec {
+	specs := make([]*entities.PluginSpec, 0, len(lock.Plugins))
+	for name, pin := range lock.Plugins {
+		source := pin.Source
+		if source == "" {
+			source = name
+		}
+		spec, err := entities.ParsePluginDeclaration(source)
+		if err != nil {
+			continue
+		}
+		if pin.Resolved != "" {
+			spec.Version = pin.Resolved
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
@@ -0,0 +1,52 @@
+package filesystem_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/infrastructure/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspacePluginScanner_ScanPluginReferences(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	profile := `profile:
+  name: web
+plugins:
+  - file
+  - ghcr.io/reglet-dev/reglet-plugins/http:1.2.0
+controls:
+  items: []
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "web.yaml"), []byte(profile), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.yml"), []byte("replicas: 3\n"), 0o600))
+
+	hidden := filepath.Join(dir, ".git")
+	require.NoError(t, os.Mkdir(hidden, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(hidden, "old.yaml"), []byte("plugins:\n  - dns\n"), 0o600))
+
+	lock := entities.NewLockfile()
+	require.NoError(t, lock.AddPlugin("file", entities.PluginLock{Requested: "1.0", Resolved: "1.0.3", Digest: "sha256:abc"}))
+	require.NoError(t, filesystem.NewFileLockfileRepository().Save(ctx, lock, filepath.Join(dir, "reglet.lock")))
+
+	specs, err := filesystem.NewWorkspacePluginScanner().ScanPluginReferences(ctx, dir)
+	require.NoError(t, err)
+
+	got := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		got = append(got, spec.Source+"@"+spec.Version)
+	}
+	assert.ElementsMatch(t, []string{
+		"file@",
+		"file@1.0.3",
+		"ghcr.io/reglet-dev/reglet-plugins/http:1.2.0@1.2.0",
+	}, got)
+}
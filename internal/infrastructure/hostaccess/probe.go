@@ -0,0 +1,63 @@
+// Package hostaccess tries host operations with the permissions of the
+// reglet process, for preflight checks.
+package hostaccess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+)
+
+// Probe tries file reads, command lookups and TCP connections on the local
+// host.
+type Probe struct {
+	dialer net.Dialer
+}
+
+// Ensure interface compliance
+var _ ports.HostAccessProbe = (*Probe)(nil)
+
+// NewProbe creates a probe.
+func NewProbe() *Probe {
+	return &Probe{}
+}
+
+// OpenFile opens path for reading and closes it again. Directories are
+// opened for listing.
+func (p *Probe) OpenFile(path string) error {
+	//nolint:gosec // G304: probing the paths observations read is the point
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// LookupCommand finds an executable by path, or by name in PATH.
+func (p *Probe) LookupCommand(name string) error {
+	_, err := exec.LookPath(name)
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("%s not found in PATH: %w", name, fs.ErrNotExist)
+	}
+	return err
+}
+
+// Dial opens a TCP connection to address and closes it again. A refused
+// connection is not an error: the host answered.
+func (p *Probe) Dial(ctx context.Context, address string) error {
+	conn, err := p.dialer.DialContext(ctx, "tcp", address)
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
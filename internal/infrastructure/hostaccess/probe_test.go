@@ -0,0 +1,57 @@
+package hostaccess_test
+
+import (
+	"context"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/hostaccess"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe_OpenFile(t *testing.T) {
+	t.Parallel()
+
+	probe := hostaccess.NewProbe()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret.conf")
+	require.NoError(t, os.WriteFile(path, []byte("x"), 0o600))
+
+	require.NoError(t, probe.OpenFile(path))
+	require.NoError(t, probe.OpenFile(dir), "directories are opened for listing")
+	assert.ErrorIs(t, probe.OpenFile(filepath.Join(dir, "missing")), fs.ErrNotExist)
+
+	if os.Geteuid() == 0 {
+		return // root reads files regardless of their mode
+	}
+	require.NoError(t, os.Chmod(path, 0o000))
+	assert.ErrorIs(t, probe.OpenFile(path), fs.ErrPermission)
+}
+
+func TestProbe_LookupCommand(t *testing.T) {
+	t.Parallel()
+
+	probe := hostaccess.NewProbe()
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	require.NoError(t, probe.LookupCommand(exe))
+	assert.ErrorIs(t, probe.LookupCommand("reglet-no-such-command"), fs.ErrNotExist)
+}
+
+func TestProbe_Dial(t *testing.T) {
+	t.Parallel()
+
+	probe := hostaccess.NewProbe()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+
+	require.NoError(t, probe.Dial(context.Background(), addr))
+	require.NoError(t, listener.Close())
+	assert.NoError(t, probe.Dial(context.Background(), addr), "a refused connection means the host is reachable")
+}
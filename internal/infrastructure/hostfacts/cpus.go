@@ -0,0 +1,16 @@
+package hostfacts
+
+import "runtime"
+
+// AvailableCPUs returns how many CPUs reglet can keep busy: the CPUs it may
+// be scheduled on (its affinity mask, as set by taskset, cpusets or NUMA
+// pinning), capped by GOMAXPROCS and by the CPU quota of its cgroup rounded
+// up. Worker pools sized by it do not oversubscribe a container limited to
+// 2 CPUs on a 64-core host.
+func AvailableCPUs() int {
+	cpus := min(runtime.NumCPU(), runtime.GOMAXPROCS(0))
+	if quota, ok := cgroupCPUQuota(); ok && quota < cpus {
+		cpus = quota
+	}
+	return max(cpus, 1)
+}
@@ -0,0 +1,109 @@
+package hostfacts
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is where the cgroup filesystems are mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupCPUQuota returns the CPU quota of the process's cgroup, in CPUs
+// rounded up, and false when it has none.
+func cgroupCPUQuota() (int, bool) {
+	self, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return 0, false
+	}
+	return cpuQuota(cgroupRoot, string(self))
+}
+
+// cpuQuota returns the tightest CPU quota set on the cgroups listed in
+// selfCgroup (the contents of /proc/self/cgroup) or their ancestors, read
+// from the cgroup filesystems under root. Both cgroup v2 (cpu.max) and v1
+// (cpu.cfs_quota_us) are read. Without a cgroup namespace a container sees
+// the host's path of its cgroup, which does not exist in its own mount:
+// walking up to the mount root still finds the container's limit there.
+func cpuQuota(root, selfCgroup string) (int, bool) {
+	quota := 0
+	tighten := func(cpus int, ok bool) {
+		if ok && (quota == 0 || cpus < quota) {
+			quota = cpus
+		}
+	}
+
+	for _, line := range strings.Split(selfCgroup, "\n") {
+		// hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		switch {
+		case fields[0] == "0" && fields[1] == "":
+			for _, dir := range ancestors(fields[2]) {
+				tighten(quotaV2(filepath.Join(root, dir, "cpu.max")))
+			}
+		case slices.Contains(strings.Split(fields[1], ","), "cpu"):
+			for _, mount := range []string{"cpu", "cpu,cpuacct", "cpuacct,cpu"} {
+				for _, dir := range ancestors(fields[2]) {
+					tighten(quotaV1(filepath.Join(root, mount, dir)))
+				}
+			}
+		}
+	}
+	return quota, quota > 0
+}
+
+// ancestors returns a cgroup path and every directory above it.
+func ancestors(cgroup string) []string {
+	dirs := []string{path.Clean("/" + cgroup)}
+	for dirs[len(dirs)-1] != "/" {
+		dirs = append(dirs, path.Dir(dirs[len(dirs)-1]))
+	}
+	return dirs
+}
+
+// quotaV2 reads a cgroup v2 cpu.max file: "max 100000" when unlimited, or
+// "<quota> <period>" in microseconds.
+func quotaV2(file string) (int, bool) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, false
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0, false
+	}
+	return quotaCPUs(fields[0], fields[1])
+}
+
+// quotaV1 reads the cpu.cfs_quota_us (-1 when unlimited) and
+// cpu.cfs_period_us files of a cgroup v1 directory.
+func quotaV1(dir string) (int, bool) {
+	quota, err := os.ReadFile(filepath.Join(dir, "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, false
+	}
+	period, err := os.ReadFile(filepath.Join(dir, "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false
+	}
+	return quotaCPUs(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+// quotaCPUs converts a quota and period to CPUs, rounded up.
+func quotaCPUs(quota, period string) (int, bool) {
+	q, err := strconv.ParseInt(quota, 10, 64)
+	if err != nil || q <= 0 {
+		return 0, false
+	}
+	p, err := strconv.ParseInt(period, 10, 64)
+	if err != nil || p <= 0 {
+		return 0, false
+	}
+	return int((q + p - 1) / p), true
+}
@@ -0,0 +1,61 @@
+package hostfacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCgroupFile writes a file of a fake cgroup filesystem.
+func writeCgroupFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	file := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o750))
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+}
+
+func TestCPUQuota_V2(t *testing.T) {
+	root := t.TempDir()
+	writeCgroupFile(t, root, "cpu.max", "max 100000\n")
+	writeCgroupFile(t, root, "kubepods/pod1/cpu.max", "250000 100000\n")
+	writeCgroupFile(t, root, "kubepods/pod1/app/cpu.max", "max 100000\n")
+
+	quota, ok := cpuQuota(root, "0::/kubepods/pod1/app\n")
+	require.True(t, ok)
+	assert.Equal(t, 3, quota, "2.5 CPUs of the parent cgroup, rounded up")
+
+	_, ok = cpuQuota(root, "0::/\n")
+	assert.False(t, ok, "unlimited")
+}
+
+func TestCPUQuota_V2NoNamespace(t *testing.T) {
+	root := t.TempDir()
+	// The container sees its own cgroup at the mount root, not at the
+	// host path /proc/self/cgroup names
+	writeCgroupFile(t, root, "cpu.max", "200000 100000\n")
+
+	quota, ok := cpuQuota(root, "0::/system.slice/docker-abc.scope\n")
+	require.True(t, ok)
+	assert.Equal(t, 2, quota)
+}
+
+func TestCPUQuota_V1(t *testing.T) {
+	root := t.TempDir()
+	writeCgroupFile(t, root, "cpu,cpuacct/docker/abc/cpu.cfs_quota_us", "50000\n")
+	writeCgroupFile(t, root, "cpu,cpuacct/docker/abc/cpu.cfs_period_us", "100000\n")
+
+	quota, ok := cpuQuota(root, "12:memory:/docker/abc\n4:cpu,cpuacct:/docker/abc\n")
+	require.True(t, ok)
+	assert.Equal(t, 1, quota)
+
+	writeCgroupFile(t, root, "cpu,cpuacct/docker/abc/cpu.cfs_quota_us", "-1\n")
+	_, ok = cpuQuota(root, "4:cpu,cpuacct:/docker/abc\n")
+	assert.False(t, ok)
+}
+
+func TestAvailableCPUs(t *testing.T) {
+	assert.GreaterOrEqual(t, AvailableCPUs(), 1)
+}
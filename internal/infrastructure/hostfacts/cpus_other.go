@@ -0,0 +1,6 @@
+//go:build !linux
+
+package hostfacts
+
+// cgroupCPUQuota reports no quota: cgroups only exist on Linux.
+func cgroupCPUQuota() (int, bool) { return 0, false }
@@ -0,0 +1,64 @@
+// Package hostfacts collects facts about the host reglet runs on, which
+// control `when` conditions are evaluated against.
+package hostfacts
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Facts describes the host. Fields that cannot be determined are empty.
+type Facts struct {
+	OS              string // linux, darwin, windows (Go's GOOS)
+	Arch            string // amd64, arm64 (Go's GOARCH)
+	Family          string // unix or windows
+	Hostname        string
+	Platform        string // Linux distribution ID (ubuntu, rhel), macos, windows
+	PlatformVersion string // e.g. 22.04, 14.4.1, 10.0.22631
+	ServiceManager  string // systemd, openrc, launchd, scm
+}
+
+// Collect returns the facts of this host.
+func Collect(ctx context.Context) Facts {
+	f := Facts{OS: runtime.GOOS, Arch: runtime.GOARCH, Family: "unix", Platform: runtime.GOOS}
+	if runtime.GOOS == "windows" {
+		f.Family = "windows"
+	}
+	f.Hostname, _ = os.Hostname()
+	collectPlatform(ctx, &f)
+	return f
+}
+
+// Map returns the facts keyed as control conditions see them, e.g.
+// facts.platform_version.
+func (f Facts) Map() map[string]interface{} {
+	return map[string]interface{}{
+		"os":               f.OS,
+		"arch":             f.Arch,
+		"family":           f.Family,
+		"hostname":         f.Hostname,
+		"platform":         f.Platform,
+		"platform_version": f.PlatformVersion,
+		"service_manager":  f.ServiceManager,
+	}
+}
+
+// parseOSRelease reads the KEY=value pairs of an os-release file, unquoting
+// values.
+func parseOSRelease(r io.Reader) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(line, "#") {
+			continue
+		}
+		values[key] = strings.Trim(value, `"'`)
+	}
+	return values
+}
@@ -0,0 +1,22 @@
+package hostfacts
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// collectPlatform reads the macOS version with sw_vers. Services are managed
+// by launchd (launchctl).
+func collectPlatform(ctx context.Context, f *Facts) {
+	f.Platform = "macos"
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, "sw_vers", "-productVersion").Output(); err == nil {
+		f.PlatformVersion = strings.TrimSpace(string(out))
+	}
+	if _, err := exec.LookPath("launchctl"); err == nil {
+		f.ServiceManager = "launchd"
+	}
+}
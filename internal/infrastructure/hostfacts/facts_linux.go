@@ -0,0 +1,36 @@
+package hostfacts
+
+import (
+	"context"
+	"os"
+)
+
+// collectPlatform reads the distribution from os-release and detects the
+// init system.
+func collectPlatform(_ context.Context, f *Facts) {
+	for _, path := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		release := parseOSRelease(file)
+		_ = file.Close()
+		if release["ID"] != "" {
+			f.Platform = release["ID"]
+		}
+		f.PlatformVersion = release["VERSION_ID"]
+		break
+	}
+
+	switch {
+	case isDir("/run/systemd/system"):
+		f.ServiceManager = "systemd"
+	case isDir("/run/openrc"):
+		f.ServiceManager = "openrc"
+	}
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !windows
+
+package hostfacts
+
+import "context"
+
+// collectPlatform has nothing beyond the OS and architecture to add on other
+// systems.
+func collectPlatform(_ context.Context, _ *Facts) {}
@@ -0,0 +1,36 @@
+package hostfacts
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	facts := Collect(context.Background()).Map()
+	assert.Equal(t, runtime.GOOS, facts["os"])
+	assert.Equal(t, runtime.GOARCH, facts["arch"])
+	assert.Contains(t, []string{"unix", "windows"}, facts["family"])
+	assert.NotEmpty(t, facts["platform"])
+}
+
+func TestParseOSRelease(t *testing.T) {
+	t.Parallel()
+
+	release := parseOSRelease(strings.NewReader(`# Ubuntu
+NAME="Ubuntu"
+ID=ubuntu
+ID_LIKE=debian
+VERSION_ID="22.04"
+PRETTY_NAME='Ubuntu 22.04.4 LTS'
+`))
+	assert.Equal(t, "ubuntu", release["ID"])
+	assert.Equal(t, "22.04", release["VERSION_ID"])
+	assert.Equal(t, "Ubuntu 22.04.4 LTS", release["PRETTY_NAME"])
+	assert.NotContains(t, release, "# Ubuntu")
+}
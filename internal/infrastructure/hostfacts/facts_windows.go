@@ -0,0 +1,18 @@
+package hostfacts
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// collectPlatform reads the Windows version from the kernel, which unlike
+// GetVersionEx is not subject to compatibility shims. Services are managed
+// by the service control manager.
+func collectPlatform(_ context.Context, f *Facts) {
+	f.Platform = "windows"
+	v := windows.RtlGetVersion()
+	f.PlatformVersion = fmt.Sprintf("%d.%d.%d", v.MajorVersion, v.MinorVersion, v.BuildNumber)
+	f.ServiceManager = "scm"
+}
@@ -0,0 +1,245 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// PluginManager manages the plugin cache of the server.
+type PluginManager interface {
+	ListCachedPlugins(ctx context.Context) ([]*entities.Plugin, error)
+	PruneCache(ctx context.Context, keepVersions int) error
+}
+
+// PluginInstaller installs plugins from the plugin index into the plugin
+// directory the server's runs load them from.
+type PluginInstaller interface {
+	Install(ctx context.Context, declaration string, verifySignature bool) (dto.InstalledPlugin, error)
+	List(ctx context.Context) ([]dto.InstalledPlugin, error)
+	Remove(ctx context.Context, declaration string) ([]dto.InstalledPlugin, error)
+}
+
+// GrantStore loads and saves the capabilities granted on the server's host.
+type GrantStore interface {
+	Load() (capabilities.Grant, error)
+	Save(grants capabilities.Grant) error
+}
+
+// grantKinds are the capability kinds a grant can have.
+var grantKinds = map[string]bool{"fs": true, "network": true, "env": true, "exec": true}
+
+// pluginInfo describes a cached plugin.
+type pluginInfo struct {
+	Reference string `json:"reference"`
+	Registry  string `json:"registry,omitempty"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Digest    string `json:"digest"`
+}
+
+func (s *Server) listPlugins(w http.ResponseWriter, r *http.Request) {
+	plugins, err := s.plugins.ListCachedPlugins(r.Context())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	infos := make([]pluginInfo, 0, len(plugins))
+	for _, p := range plugins {
+		ref := p.Reference()
+		infos = append(infos, pluginInfo{
+			Reference: ref.String(),
+			Registry:  ref.Registry(),
+			Name:      ref.Name(),
+			Version:   ref.Version(),
+			Digest:    p.Digest().String(),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string][]pluginInfo{"plugins": infos})
+}
+
+// pruneRequest is the body of a plugin cache prune.
+type pruneRequest struct {
+	KeepVersions int `json:"keep_versions"`
+}
+
+func (s *Server) prunePlugins(w http.ResponseWriter, r *http.Request) {
+	var req pruneRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid prune request: %w", err))
+		return
+	}
+	if req.KeepVersions < 1 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid keep_versions %d (must be at least 1)", req.KeepVersions))
+		return
+	}
+	if err := s.plugins.PruneCache(r.Context(), req.KeepVersions); err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.logger.Info("pruned plugin cache",
+		"keep_versions", req.KeepVersions,
+		"subject", identityFromContext(r.Context()).Subject)
+	writeJSON(w, http.StatusOK, req)
+}
+
+// capabilityPolicy is the response of a capability policy read.
+type capabilityPolicy struct {
+	SecurityLevel string            `json:"security_level"`
+	Grants        []capabilityGrant `json:"grants"`
+}
+
+type capabilityGrant struct {
+	Kind    string `json:"kind"`
+	Pattern string `json:"pattern"`
+}
+
+func (s *Server) getCapabilityPolicy(w http.ResponseWriter, _ *http.Request) {
+	grants, err := s.grants.Load()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	policy := capabilityPolicy{SecurityLevel: s.securityLevel, Grants: make([]capabilityGrant, 0, len(grants))}
+	for _, grant := range grants {
+		policy.Grants = append(policy.Grants, capabilityGrant{Kind: grant.Kind, Pattern: grant.Pattern})
+	}
+	writeJSON(w, http.StatusOK, policy)
+}
+
+// grantsRequest is the body of a capability grant update.
+type grantsRequest struct {
+	Grants []capabilityGrant `json:"grants"`
+}
+
+// putCapabilityGrants replaces the capabilities granted on the host. Runs
+// started afterwards use the new grants; the security level is set in the
+// server's config and cannot be changed here.
+func (s *Server) putCapabilityGrants(w http.ResponseWriter, r *http.Request) {
+	var req grantsRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid grants request: %w", err))
+		return
+	}
+	grants := capabilities.NewGrant()
+	for _, grant := range req.Grants {
+		capability := capabilities.Capability{Kind: grant.Kind, Pattern: grant.Pattern}
+		switch {
+		case !grantKinds[grant.Kind]:
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid grant kind %q (expected fs, network, env or exec)", grant.Kind))
+			return
+		case grant.Pattern == "":
+			writeError(w, http.StatusBadRequest, fmt.Errorf("grant of kind %s has no pattern", grant.Kind))
+			return
+		case s.securityLevel == "strict" && capability.IsBroad():
+			writeError(w, http.StatusBadRequest, fmt.Errorf("security level strict denies the broad capability %s", capability))
+			return
+		}
+		grants.Add(capability)
+	}
+	if err := s.grants.Save(grants); err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.logger.Info("replaced capability grants",
+		"grants", len(grants),
+		"subject", identityFromContext(r.Context()).Subject)
+	s.getCapabilityPolicy(w, r)
+}
+
+// installedPlugin describes a plugin release installed from the plugin index.
+type installedPlugin struct {
+	InstalledAt       time.Time `json:"installed_at"`
+	Name              string    `json:"name"`
+	Version           string    `json:"version"`
+	Source            string    `json:"source"`
+	Digest            string    `json:"digest"`
+	SignatureVerified bool      `json:"signature_verified"`
+}
+
+func newInstalledPlugins(plugins []dto.InstalledPlugin) []installedPlugin {
+	infos := make([]installedPlugin, 0, len(plugins))
+	for _, p := range plugins {
+		infos = append(infos, installedPlugin{
+			InstalledAt:       p.InstalledAt,
+			Name:              p.Name,
+			Version:           p.Version,
+			Source:            p.Source,
+			Digest:            p.Digest,
+			SignatureVerified: p.SignatureVerified,
+		})
+	}
+	return infos
+}
+
+func (s *Server) listInstalledPlugins(w http.ResponseWriter, r *http.Request) {
+	plugins, err := s.installer.List(r.Context())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]installedPlugin{"plugins": newInstalledPlugins(plugins)})
+}
+
+// installRequest is the body of a plugin install or removal.
+type installRequest struct {
+	Plugin          string `json:"plugin"`
+	VerifySignature bool   `json:"verify_signature,omitempty"`
+}
+
+// decodeInstallRequest reads an install request, writing the error response
+// if it is invalid.
+func decodeInstallRequest(w http.ResponseWriter, r *http.Request) (installRequest, bool) {
+	var req installRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid plugin request: %w", err))
+		return req, false
+	}
+	if req.Plugin == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid plugin request: plugin is required"))
+		return req, false
+	}
+	return req, true
+}
+
+func (s *Server) installPlugin(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeInstallRequest(w, r)
+	if !ok {
+		return
+	}
+	plugin, err := s.installer.Install(r.Context(), req.Plugin, req.VerifySignature)
+	if err != nil {
+		// Unknown plugins, digest mismatches and unreachable registries are
+		// reported to the admin as they are.
+		s.logger.Warn("plugin install failed", "plugin", req.Plugin, "error", err)
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	s.logger.Info("installed plugin",
+		"plugin", plugin.Name, "version", plugin.Version,
+		"subject", identityFromContext(r.Context()).Subject)
+	writeJSON(w, http.StatusOK, newInstalledPlugins([]dto.InstalledPlugin{plugin})[0])
+}
+
+func (s *Server) removePlugin(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeInstallRequest(w, r)
+	if !ok {
+		return
+	}
+	removed, err := s.installer.Remove(r.Context(), req.Plugin)
+	if err != nil {
+		s.logger.Warn("plugin removal failed", "plugin", req.Plugin, "error", err)
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	s.logger.Info("removed plugin",
+		"plugin", req.Plugin, "releases", len(removed),
+		"subject", identityFromContext(r.Context()).Subject)
+	writeJSON(w, http.StatusOK, map[string][]installedPlugin{"removed": newInstalledPlugins(removed)})
+}
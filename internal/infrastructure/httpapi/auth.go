@@ -0,0 +1,198 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+// Role grants access to the API. Roles are ordered: each includes the
+// access of the roles below it.
+type Role int
+
+const (
+	// RoleNone grants no access.
+	RoleNone Role = iota
+	// RoleViewer reads results.
+	RoleViewer
+	// RoleOperator submits results and triggers runs.
+	RoleOperator
+	// RoleAdmin lists and prunes cached plugins and views the capability
+	// policy and grants.
+	RoleAdmin
+)
+
+// String returns the name of the role, as used in token claims.
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleOperator:
+		return "operator"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+// ParseRole returns the role with the given name.
+func ParseRole(name string) (Role, bool) {
+	for _, role := range []Role{RoleViewer, RoleOperator, RoleAdmin} {
+		if role.String() == name {
+			return role, true
+		}
+	}
+	return RoleNone, false
+}
+
+var (
+	// ErrUnauthenticated is returned for requests without a valid token.
+	ErrUnauthenticated = errors.New("unauthenticated")
+
+	// ErrForbidden is returned for requests whose identity lacks access.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	Subject string
+	// Namespaces the caller may work in; nil allows every namespace.
+	Namespaces []string
+	Role       Role
+}
+
+// CanAccess reports whether the identity may work in namespace.
+func (i *Identity) CanAccess(namespace string) bool {
+	return i.Namespaces == nil || slices.Contains(i.Namespaces, namespace)
+}
+
+// Authenticator resolves the bearer token of a request to an identity.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+}
+
+// AuthConfig configures an OIDCAuthenticator.
+type AuthConfig struct {
+	// Issuer is the OIDC issuer URL tokens must come from.
+	Issuer string
+	// Audience tokens must be issued for.
+	Audience string
+	// JWKSURL serves the signing keys; empty uses OIDC discovery.
+	JWKSURL string
+	// RolesClaim names the claim holding roles, with dots selecting nested
+	// claims (default: "roles").
+	RolesClaim string
+	// NamespacesClaim names the claim listing permitted namespaces. When
+	// empty, tokens are not restricted to namespaces.
+	NamespacesClaim string
+}
+
+// DefaultRolesClaim is the claim roles are read from by default.
+const DefaultRolesClaim = "roles"
+
+// Ensure interface compliance
+var _ Authenticator = (*OIDCAuthenticator)(nil)
+
+// OIDCAuthenticator authenticates JWTs issued by an OIDC provider. The token
+// signature, issuer, audience and expiry are verified; the role is the
+// highest reglet role named in the roles claim.
+type OIDCAuthenticator struct {
+	verifier        *oidc.IDTokenVerifier
+	rolesClaim      []string
+	namespacesClaim []string
+}
+
+// NewOIDCAuthenticator creates an authenticator for cfg. Without a JWKS URL
+// the issuer's discovery document is fetched, so the issuer must be
+// reachable. ctx bounds the lifetime of key fetches.
+func NewOIDCAuthenticator(ctx context.Context, cfg AuthConfig) (*OIDCAuthenticator, error) {
+	if cfg.Issuer == "" {
+		return nil, errors.New("token issuer is required")
+	}
+	if cfg.Audience == "" {
+		return nil, errors.New("token audience is required")
+	}
+
+	oidcCfg := &oidc.Config{ClientID: cfg.Audience}
+	if cfg.JWKSURL != "" {
+		keys := oidc.NewRemoteKeySet(ctx, cfg.JWKSURL)
+		return newOIDCAuthenticator(oidc.NewVerifier(cfg.Issuer, keys, oidcCfg), cfg), nil
+	}
+	provider, err := oidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover token issuer %s: %w", cfg.Issuer, err)
+	}
+	return newOIDCAuthenticator(provider.Verifier(oidcCfg), cfg), nil
+}
+
+func newOIDCAuthenticator(verifier *oidc.IDTokenVerifier, cfg AuthConfig) *OIDCAuthenticator {
+	rolesClaim := cfg.RolesClaim
+	if rolesClaim == "" {
+		rolesClaim = DefaultRolesClaim
+	}
+	a := &OIDCAuthenticator{
+		verifier:   verifier,
+		rolesClaim: strings.Split(rolesClaim, "."),
+	}
+	if cfg.NamespacesClaim != "" {
+		a.namespacesClaim = strings.Split(cfg.NamespacesClaim, ".")
+	}
+	return a
+}
+
+// Authenticate verifies token and returns the identity it carries.
+func (a *OIDCAuthenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	verified, err := a.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+	}
+	var claims map[string]any
+	if err := verified.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+	}
+
+	identity := &Identity{Subject: verified.Subject}
+	for _, name := range claimStrings(claims, a.rolesClaim) {
+		if role, ok := ParseRole(name); ok && role > identity.Role {
+			identity.Role = role
+		}
+	}
+	if a.namespacesClaim != nil {
+		// A token without the claim may use no namespace.
+		identity.Namespaces = append([]string{}, claimStrings(claims, a.namespacesClaim)...)
+	}
+	return identity, nil
+}
+
+// claimStrings returns the claim at path as a list of strings. A single
+// string counts as a list of one; other values are ignored.
+func claimStrings(claims map[string]any, path []string) []string {
+	var value any = claims
+	for _, key := range path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
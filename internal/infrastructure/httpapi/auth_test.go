@@ -0,0 +1,161 @@
+package httpapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "https://idp.example.com"
+
+// tokenIssuer signs test tokens with a key its authenticators trust.
+type tokenIssuer struct {
+	key *rsa.PrivateKey
+}
+
+func newTokenIssuer(t *testing.T) *tokenIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return &tokenIssuer{key: key}
+}
+
+func (i *tokenIssuer) authenticator(cfg AuthConfig) *OIDCAuthenticator {
+	keys := &oidc.StaticKeySet{PublicKeys: []crypto.PublicKey{i.key.Public()}}
+	return newOIDCAuthenticator(oidc.NewVerifier(testIssuer, keys, &oidc.Config{ClientID: "reglet"}), cfg)
+}
+
+// sign returns a token for subject "alice" with the given extra claims.
+func (i *tokenIssuer) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+
+	payload := map[string]any{
+		"iss": testIssuer,
+		"aud": "reglet",
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range claims {
+		payload[k] = v
+	}
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: i.key}, nil)
+	require.NoError(t, err)
+	signed, err := signer.Sign(data)
+	require.NoError(t, err)
+	token, err := signed.CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func TestParseRole(t *testing.T) {
+	t.Parallel()
+
+	for _, role := range []Role{RoleViewer, RoleOperator, RoleAdmin} {
+		parsed, ok := ParseRole(role.String())
+		assert.True(t, ok)
+		assert.Equal(t, role, parsed)
+	}
+	_, ok := ParseRole("none")
+	assert.False(t, ok)
+	assert.Less(t, RoleViewer, RoleOperator)
+	assert.Less(t, RoleOperator, RoleAdmin)
+}
+
+func TestOIDCAuthenticator_Roles(t *testing.T) {
+	t.Parallel()
+	issuer := newTokenIssuer(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		cfg    AuthConfig
+		claims map[string]any
+		want   Role
+	}{
+		{"highest role wins", AuthConfig{}, map[string]any{"roles": []string{"viewer", "admin", "operator"}}, RoleAdmin},
+		{"single string", AuthConfig{}, map[string]any{"roles": "operator"}, RoleOperator},
+		{"unknown roles ignored", AuthConfig{}, map[string]any{"roles": []string{"owner"}}, RoleNone},
+		{"no claim", AuthConfig{}, nil, RoleNone},
+		{
+			"nested claim",
+			AuthConfig{RolesClaim: "realm_access.roles"},
+			map[string]any{"realm_access": map[string]any{"roles": []string{"viewer"}}},
+			RoleViewer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			identity, err := issuer.authenticator(tt.cfg).Authenticate(ctx, issuer.sign(t, tt.claims))
+			require.NoError(t, err)
+			assert.Equal(t, "alice", identity.Subject)
+			assert.Equal(t, tt.want, identity.Role)
+		})
+	}
+}
+
+func TestOIDCAuthenticator_Namespaces(t *testing.T) {
+	t.Parallel()
+	issuer := newTokenIssuer(t)
+	ctx := context.Background()
+	token := issuer.sign(t, map[string]any{"namespaces": []string{"team-a"}})
+
+	unrestricted, err := issuer.authenticator(AuthConfig{}).Authenticate(ctx, token)
+	require.NoError(t, err)
+	assert.True(t, unrestricted.CanAccess("team-b"), "namespaces are not restricted without a namespaces claim")
+
+	restricted, err := issuer.authenticator(AuthConfig{NamespacesClaim: "namespaces"}).Authenticate(ctx, token)
+	require.NoError(t, err)
+	assert.True(t, restricted.CanAccess("team-a"))
+	assert.False(t, restricted.CanAccess("team-b"))
+
+	none, err := issuer.authenticator(AuthConfig{NamespacesClaim: "namespaces"}).Authenticate(ctx, issuer.sign(t, nil))
+	require.NoError(t, err)
+	assert.False(t, none.CanAccess("default"), "a token without the claim may use no namespace")
+}
+
+func TestOIDCAuthenticator_RejectsInvalidTokens(t *testing.T) {
+	t.Parallel()
+	issuer := newTokenIssuer(t)
+	authenticator := issuer.authenticator(AuthConfig{})
+	ctx := context.Background()
+
+	tokens := map[string]string{
+		"expired":        issuer.sign(t, map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}),
+		"wrong audience": issuer.sign(t, map[string]any{"aud": "other"}),
+		"wrong issuer":   issuer.sign(t, map[string]any{"iss": "https://evil.example.com"}),
+		"untrusted key":  newTokenIssuer(t).sign(t, nil),
+		"malformed":      "not-a-jwt",
+	}
+	for name, token := range tokens {
+		_, err := authenticator.Authenticate(ctx, token)
+		assert.ErrorIs(t, err, ErrUnauthenticated, name)
+	}
+}
+
+func TestNewOIDCAuthenticator_RequiresIssuerAndAudience(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewOIDCAuthenticator(context.Background(), AuthConfig{Audience: "reglet"})
+	assert.ErrorContains(t, err, "issuer is required")
+	_, err = NewOIDCAuthenticator(context.Background(), AuthConfig{Issuer: testIssuer})
+	assert.ErrorContains(t, err, "audience is required")
+
+	a, err := NewOIDCAuthenticator(context.Background(), AuthConfig{Issuer: testIssuer, Audience: "reglet", JWKSURL: testIssuer + "/keys"})
+	require.NoError(t, err, "with a JWKS URL nothing is fetched up front")
+	assert.Equal(t, []string{DefaultRolesClaim}, a.rolesClaim)
+}
@@ -0,0 +1,293 @@
+// Reglet dashboard: a read-mostly view over the reglet serve API. Everything
+// shown comes from /api/v1; values are inserted as text, never as HTML.
+"use strict";
+
+const roles = ["none", "viewer", "operator", "admin"];
+const trendDays = 30;
+const maxSummaries = 1000;
+
+const state = {
+  namespace: sessionStorage.getItem("reglet.namespace") || "",
+  token: sessionStorage.getItem("reglet.token") || "",
+  identity: null,
+  runsTimer: null,
+  following: false,
+  detail: null,
+};
+
+function $(id) {
+  return document.getElementById(id);
+}
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs || {})) {
+    if (key === "class") node.className = value;
+    else if (key === "onclick") node.addEventListener("click", value);
+    else node.setAttribute(key, value);
+  }
+  for (const child of children) {
+    node.append(child instanceof Node ? child : document.createTextNode(String(child ?? "")));
+  }
+  return node;
+}
+
+async function api(path, options = {}) {
+  const headers = { ...(options.headers || {}) };
+  if (state.token) headers["Authorization"] = "Bearer " + state.token;
+  if (state.namespace) headers["X-Reglet-Namespace"] = state.namespace;
+  const resp = await fetch(path, { ...options, headers });
+  const body = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    const err = new Error(body.error || resp.statusText);
+    err.status = resp.status;
+    throw err;
+  }
+  return body;
+}
+
+function showError(err) {
+  const box = $("error");
+  if (!err) {
+    box.hidden = true;
+    return;
+  }
+  box.textContent = err.status === 401 ? "Enter a valid token to continue." : err.message;
+  box.hidden = false;
+}
+
+function passRate(summary) {
+  const judged = summary.total_controls - summary.skipped_controls;
+  return judged > 0 ? summary.passed_controls / judged : 1;
+}
+
+function percent(rate) {
+  return Math.round(rate * 100) + "%";
+}
+
+function health(summary) {
+  if (summary.error_controls > 0) return "error";
+  if (summary.failed_controls > 0) return "fail";
+  return "pass";
+}
+
+function formatTime(value) {
+  return value ? new Date(value).toLocaleString() : "";
+}
+
+// loadSummaries pages through the result summaries of the trend window.
+async function loadSummaries() {
+  const since = new Date(Date.now() - trendDays * 24 * 3600 * 1000).toISOString();
+  const summaries = [];
+  let token = "";
+  do {
+    const params = new URLSearchParams({ view: "summary", since, limit: "200" });
+    if (token) params.set("page_token", token);
+    const page = await api("/api/v1/results?" + params);
+    summaries.push(...page.results);
+    token = page.next_page_token || "";
+  } while (token && summaries.length < maxSummaries);
+  return summaries;
+}
+
+// groupRuns groups summaries by profile and environment, oldest run first.
+function groupRuns(summaries) {
+  const groups = new Map();
+  for (const s of summaries) {
+    const key = s.profile_name + "\u0000" + (s.environment || "");
+    if (!groups.has(key)) groups.set(key, { profile: s.profile_name, environment: s.environment || "", runs: [] });
+    groups.get(key).runs.unshift(s);
+  }
+  return [...groups.values()].sort((a, b) =>
+    a.profile.localeCompare(b.profile) || a.environment.localeCompare(b.environment));
+}
+
+function sparkline(runs) {
+  const width = 160;
+  const height = 28;
+  const ns = "http://www.w3.org/2000/svg";
+  const svg = document.createElementNS(ns, "svg");
+  svg.setAttribute("width", width);
+  svg.setAttribute("height", height);
+  svg.setAttribute("class", "sparkline");
+  const step = runs.length > 1 ? width / (runs.length - 1) : 0;
+  const points = runs.map((run, i) => {
+    const x = runs.length > 1 ? i * step : width / 2;
+    const y = 2 + (1 - passRate(run.summary)) * (height - 4);
+    return x.toFixed(1) + "," + y.toFixed(1);
+  });
+  const line = document.createElementNS(ns, "polyline");
+  line.setAttribute("points", points.join(" "));
+  svg.append(line);
+  return svg;
+}
+
+function renderPosture(groups) {
+  const container = $("posture");
+  container.replaceChildren();
+  if (groups.length === 0) {
+    container.append(el("p", { class: "hint" }, "No results in this namespace yet."));
+    return;
+  }
+  for (const group of groups) {
+    const latest = group.runs[group.runs.length - 1];
+    const s = latest.summary;
+    container.append(el("button", { class: "card " + health(s), onclick: () => showDetail(latest.execution_id) },
+      el("strong", {}, group.profile),
+      el("span", { class: "env" }, group.environment || "no environment"),
+      el("span", { class: "rate" }, percent(passRate(s))),
+      el("span", {}, `${s.passed_controls} pass · ${s.failed_controls} fail · ${s.error_controls} error`),
+      el("span", { class: "hint" }, formatTime(latest.start_time))));
+  }
+}
+
+function renderTrends(groups) {
+  const body = $("trends");
+  body.replaceChildren();
+  for (const group of groups) {
+    const latest = group.runs[group.runs.length - 1];
+    body.append(el("tr", {},
+      el("td", {}, group.profile),
+      el("td", {}, group.environment),
+      el("td", {}, group.runs.length),
+      el("td", {}, sparkline(group.runs)),
+      el("td", {}, el("a", { href: "#", onclick: (e) => { e.preventDefault(); showDetail(latest.execution_id); } },
+        percent(passRate(latest.summary))))));
+  }
+}
+
+async function loadRuns() {
+  const page = await api("/api/v1/runs");
+  const body = $("runs");
+  body.replaceChildren();
+  let running = false;
+  for (const run of page.runs) {
+    running = running || run.state === "running" || run.state === "queued";
+    const label = run.state === "queued" && run.position ? `queued #${run.position}` : run.state;
+    const result = run.execution_id
+      ? el("a", { href: "#", onclick: (e) => { e.preventDefault(); showDetail(run.execution_id); } }, "view")
+      : el("span", { class: "hint" }, run.error || "");
+    body.append(el("tr", {},
+      el("td", {}, formatTime(run.created_at)),
+      el("td", {}, run.profile),
+      el("td", {}, run.environment || ""),
+      el("td", {}, run.requested_by || ""),
+      el("td", {}, el("span", { class: "badge " + run.state }, label)),
+      el("td", {}, result)));
+  }
+  clearTimeout(state.runsTimer);
+  if (running) {
+    // Follow runs in progress or queued; their results change the posture.
+    state.runsTimer = setTimeout(() => loadRuns().catch(showError), 3000);
+  } else if (state.following) {
+    await loadResults();
+  }
+  state.following = running;
+}
+
+async function setupRuns() {
+  let profiles;
+  try {
+    profiles = (await api("/api/v1/profiles")).profiles;
+  } catch (err) {
+    if (err.status === 404) return; // the server runs no profiles
+    throw err;
+  }
+  $("runs-section").hidden = false;
+  const canTrigger = roles.indexOf(state.identity.role) >= roles.indexOf("operator");
+  $("trigger").hidden = !canTrigger || profiles.length === 0;
+  $("trigger-profile").replaceChildren(...profiles.map((p) => el("option", { value: p }, p)));
+  await loadRuns();
+}
+
+async function triggerRun(event) {
+  event.preventDefault();
+  try {
+    await api("/api/v1/runs", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ profile: $("trigger-profile").value, environment: $("trigger-environment").value }),
+    });
+    showError(null);
+    await loadRuns();
+  } catch (err) {
+    showError(err);
+  }
+}
+
+async function showDetail(id) {
+  try {
+    state.detail = await api("/api/v1/results/" + encodeURIComponent(id));
+    renderDetail();
+    $("detail").hidden = false;
+    $("detail").scrollIntoView({ behavior: "smooth" });
+  } catch (err) {
+    showError(err);
+  }
+}
+
+function renderDetail() {
+  const result = state.detail;
+  const filter = $("detail-filter").value;
+  $("detail-title").textContent = result.profile_name + (result.environment ? " / " + result.environment : "");
+  $("detail-meta").textContent = `Run ${result.execution_id} · ${formatTime(result.start_time)} · ` +
+    `profile ${result.profile_version} · reglet ${result.reglet_version || "unknown"}`;
+
+  const body = $("controls");
+  body.replaceChildren();
+  for (const control of result.controls || []) {
+    if (filter && control.status !== filter) continue;
+    const observations = el("details", {}, el("summary", {}, control.message || control.skip_reason || ""));
+    for (const obs of control.observations || []) {
+      observations.append(el("div", { class: "observation" },
+        el("span", { class: "badge " + obs.status }, obs.status), " ", obs.plugin,
+        obs.error ? el("p", { class: "error" }, obs.error.Message || "") : "",
+        ...(obs.expectations || []).map((e) =>
+          el("p", { class: e.passed ? "hint" : "error" }, (e.passed ? "✓ " : "✗ ") + e.expression + (e.message ? ": " + e.message : ""))),
+        obs.evidence ? el("pre", {}, JSON.stringify(obs.evidence.Data ?? obs.evidence, null, 2)) : ""));
+    }
+    body.append(el("tr", {},
+      el("td", {}, el("span", { class: "badge " + control.status }, control.status)),
+      el("td", {}, el("strong", {}, control.id), el("br"), control.name),
+      el("td", {}, control.severity || ""),
+      el("td", {}, observations)));
+  }
+}
+
+async function loadResults() {
+  const groups = groupRuns(await loadSummaries());
+  renderPosture(groups);
+  renderTrends(groups);
+}
+
+async function load() {
+  showError(null);
+  $("detail").hidden = true;
+  $("runs-section").hidden = true;
+  try {
+    state.identity = await api("/api/v1/me");
+    $("identity").textContent = state.identity.authenticated
+      ? `${state.identity.subject} (${state.identity.role})`
+      : "authentication disabled";
+    await loadResults();
+    await setupRuns();
+  } catch (err) {
+    showError(err);
+  }
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+  $("namespace").value = state.namespace;
+  $("token").value = state.token;
+  $("settings").addEventListener("submit", (event) => {
+    event.preventDefault();
+    state.namespace = $("namespace").value.trim();
+    state.token = $("token").value.trim();
+    sessionStorage.setItem("reglet.namespace", state.namespace);
+    sessionStorage.setItem("reglet.token", state.token);
+    load();
+  });
+  $("trigger").addEventListener("submit", triggerRun);
+  $("detail-filter").addEventListener("change", renderDetail);
+  load();
+});
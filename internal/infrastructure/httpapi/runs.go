@@ -0,0 +1,123 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+)
+
+// runInfo describes a run triggered on the server.
+type runInfo struct {
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	ID          string     `json:"id"`
+	Profile     string     `json:"profile"`
+	Environment string     `json:"environment,omitempty"`
+	Namespace   string     `json:"namespace"`
+	RequestedBy string     `json:"requested_by,omitempty"`
+	State       string     `json:"state"`
+	ExecutionID string     `json:"execution_id,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Position    int        `json:"position,omitempty"`
+}
+
+func newRunInfo(run *dto.RunStatus) runInfo {
+	info := runInfo{
+		CreatedAt:   run.CreatedAt,
+		ID:          run.ID,
+		Profile:     run.Profile,
+		Environment: run.Environment,
+		Namespace:   run.Namespace,
+		RequestedBy: run.RequestedBy,
+		State:       run.State,
+		ExecutionID: run.ExecutionID,
+		Error:       run.Error,
+		Position:    run.Position,
+	}
+	if !run.StartedAt.IsZero() {
+		info.StartedAt = &run.StartedAt
+	}
+	if !run.FinishedAt.IsZero() {
+		info.FinishedAt = &run.FinishedAt
+	}
+	return info
+}
+
+// runRequest is the body of a run trigger.
+type runRequest struct {
+	Profile     string `json:"profile"`
+	Environment string `json:"environment"`
+}
+
+func (s *Server) listProfiles(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string][]string{"profiles": s.runs.Profiles()})
+}
+
+func (s *Server) triggerRun(w http.ResponseWriter, r *http.Request) {
+	var req runRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid run request: %w", err))
+		return
+	}
+	run, queued, err := s.runs.Trigger(r.Context(), dto.RunRequest{
+		Profile:     req.Profile,
+		Environment: req.Environment,
+		RequestedBy: identityFromContext(r.Context()).Subject,
+	})
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	if !queued {
+		// An identical request is already waiting; this one joined it.
+		writeJSON(w, http.StatusOK, newRunInfo(run))
+		return
+	}
+	writeJSON(w, http.StatusAccepted, newRunInfo(run))
+}
+
+// queueInfo is the response of a queue status read.
+type queueInfo struct {
+	Runs          []runInfo `json:"runs"`
+	Running       int       `json:"running"`
+	Queued        int       `json:"queued"`
+	MaxConcurrent int       `json:"max_concurrent"`
+	MaxPerProfile int       `json:"max_per_profile"`
+}
+
+func (s *Server) getQueue(w http.ResponseWriter, r *http.Request) {
+	queue := s.runs.Queue(r.Context())
+	info := queueInfo{
+		Runs:          make([]runInfo, 0, len(queue.Runs)),
+		Running:       queue.Running,
+		Queued:        queue.Queued,
+		MaxConcurrent: queue.MaxConcurrent,
+		MaxPerProfile: queue.MaxPerProfile,
+	}
+	for i := range queue.Runs {
+		info.Runs = append(info.Runs, newRunInfo(&queue.Runs[i]))
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (s *Server) listRuns(w http.ResponseWriter, r *http.Request) {
+	runs := s.runs.List(r.Context())
+	infos := make([]runInfo, 0, len(runs))
+	for i := range runs {
+		infos = append(infos, newRunInfo(&runs[i]))
+	}
+	writeJSON(w, http.StatusOK, map[string][]runInfo{"runs": infos})
+}
+
+func (s *Server) getRun(w http.ResponseWriter, r *http.Request) {
+	run, err := s.runs.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newRunInfo(run))
+}
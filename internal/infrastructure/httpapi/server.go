@@ -0,0 +1,411 @@
+// Package httpapi serves stored execution results over HTTP for
+// "reglet serve", so central controllers, dashboards and CI jobs can read
+// and submit results without database access.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/services"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// NamespaceHeader selects the namespace a request works in.
+const NamespaceHeader = "X-Reglet-Namespace"
+
+const (
+	// maxImportBytes bounds the size of an uploaded result archive.
+	maxImportBytes = 256 << 20
+	// maxPageSize bounds the limit of a result listing.
+	maxPageSize = 1000
+)
+
+// Server is the HTTP API over stored execution results, with a web
+// dashboard served from "/". Every request works in one namespace, taken
+// from the X-Reglet-Namespace header or the server's default, and the
+// repository enforces that it sees no other.
+//
+// With an authenticator, requests carry a bearer token and each endpoint
+// requires a role: viewers read results and runs, operators also submit
+// results and trigger runs, and admins also manage cached and installed
+// plugins and the capability grants. Without one, every request has full
+// access.
+type Server struct {
+	authenticator Authenticator
+	history       *services.ResultHistoryService
+	runs          *services.RunService
+	logger        *slog.Logger
+	plugins       PluginManager
+	installer     PluginInstaller
+	grants        GrantStore
+	namespace     string
+	securityLevel string
+}
+
+// NewServer creates an API server. Requests without a namespace header work
+// in defaultNamespace.
+func NewServer(history *services.ResultHistoryService, defaultNamespace string, logger *slog.Logger) *Server {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Server{
+		history:   history,
+		namespace: defaultNamespace,
+		logger:    logger,
+	}
+}
+
+// SetAuthenticator requires requests to authenticate with a bearer token.
+func (s *Server) SetAuthenticator(authenticator Authenticator) {
+	s.authenticator = authenticator
+}
+
+// SetRunService lets operators trigger runs of the server's profiles.
+func (s *Server) SetRunService(runs *services.RunService) {
+	s.runs = runs
+}
+
+// SetPluginManager serves plugin management to admins.
+func (s *Server) SetPluginManager(plugins PluginManager) {
+	s.plugins = plugins
+}
+
+// SetPluginInstaller lets admins install and remove plugins.
+func (s *Server) SetPluginInstaller(installer PluginInstaller) {
+	s.installer = installer
+}
+
+// SetCapabilityPolicy serves the capability policy, the security level and
+// the capabilities granted on this host, to admins, and lets them replace
+// the grants.
+func (s *Server) SetCapabilityPolicy(securityLevel string, grants GrantStore) {
+	s.securityLevel = securityLevel
+	s.grants = grants
+}
+
+// Close stops the runs the server started and waits for them to end.
+func (s *Server) Close() {
+	if s.runs != nil {
+		s.runs.Close()
+	}
+}
+
+// Handler returns the HTTP handler serving the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("GET /", dashboardHandler())
+	mux.Handle("GET /api/v1/me", s.authorized(RoleViewer, http.HandlerFunc(s.getIdentity)))
+	mux.Handle("GET /api/v1/results", s.authorized(RoleViewer, s.scoped(s.listResults)))
+	mux.Handle("GET /api/v1/results/{id}", s.authorized(RoleViewer, s.scoped(s.getResult)))
+	mux.Handle("POST /api/v1/results/import", mutating(s.authorized(RoleOperator, s.scoped(s.importResults))))
+	if s.runs != nil {
+		mux.Handle("GET /api/v1/profiles", s.authorized(RoleViewer, http.HandlerFunc(s.listProfiles)))
+		mux.Handle("GET /api/v1/runs", s.authorized(RoleViewer, s.scoped(s.listRuns)))
+		mux.Handle("GET /api/v1/runs/{id}", s.authorized(RoleViewer, s.scoped(s.getRun)))
+		mux.Handle("POST /api/v1/runs", mutating(s.authorized(RoleOperator, s.scoped(s.triggerRun))))
+		mux.Handle("GET /api/v1/queue", s.authorized(RoleViewer, s.scoped(s.getQueue)))
+	}
+	if s.plugins != nil {
+		mux.Handle("GET /api/v1/plugins", s.authorized(RoleAdmin, http.HandlerFunc(s.listPlugins)))
+		mux.Handle("POST /api/v1/plugins/prune", mutating(s.authorized(RoleAdmin, http.HandlerFunc(s.prunePlugins))))
+	}
+	if s.installer != nil {
+		mux.Handle("GET /api/v1/plugins/installed", s.authorized(RoleAdmin, http.HandlerFunc(s.listInstalledPlugins)))
+		mux.Handle("POST /api/v1/plugins/install", mutating(s.authorized(RoleAdmin, http.HandlerFunc(s.installPlugin))))
+		mux.Handle("POST /api/v1/plugins/remove", mutating(s.authorized(RoleAdmin, http.HandlerFunc(s.removePlugin))))
+	}
+	if s.grants != nil {
+		mux.Handle("GET /api/v1/capabilities", s.authorized(RoleAdmin, http.HandlerFunc(s.getCapabilityPolicy)))
+		mux.Handle("PUT /api/v1/capabilities/grants", mutating(s.authorized(RoleAdmin, http.HandlerFunc(s.putCapabilityGrants))))
+	}
+	return mux
+}
+
+type identityKey struct{}
+
+// identityFromContext returns the identity of an authorized request.
+func identityFromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityKey{}).(*Identity)
+	return identity
+}
+
+// mutating guards a state-changing endpoint against cross-site request
+// forgery. Its body must be JSON, which a page of another origin can only
+// send after a CORS preflight the server never answers, and browser requests
+// from another origin (by Origin or Sec-Fetch-Site) are refused.
+func mutating(handler http.Handler) http.Handler {
+	protection := http.NewCrossOriginProtection()
+	protection.SetDenyHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusForbidden, errors.New("cross-origin request refused"))
+	}))
+	return protection.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+			writeError(w, http.StatusUnsupportedMediaType, errors.New("Content-Type must be application/json"))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+}
+
+// anonymous is the identity of every request when authentication is off.
+var anonymous = &Identity{Subject: "anonymous", Role: RoleAdmin}
+
+// authorized runs handler for requests whose identity has at least role.
+func (s *Server) authorized(role Role, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity := anonymous
+		if s.authenticator != nil {
+			token, ok := bearerToken(r)
+			if !ok {
+				unauthenticated(w, errors.New("bearer token required"))
+				return
+			}
+			var err error
+			if identity, err = s.authenticator.Authenticate(r.Context(), token); err != nil {
+				s.logger.Debug("rejected api token", "error", err)
+				unauthenticated(w, errors.New("invalid bearer token"))
+				return
+			}
+		}
+		if identity.Role < role {
+			writeError(w, http.StatusForbidden, fmt.Errorf("%w: %s role required", ErrForbidden, role))
+			return
+		}
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityKey{}, identity)))
+	})
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+func unauthenticated(w http.ResponseWriter, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="reglet"`)
+	writeError(w, http.StatusUnauthorized, err)
+}
+
+// scoped runs handler in the namespace of the request, if the caller may
+// work in it.
+func (s *Server) scoped(handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace := r.Header.Get(NamespaceHeader)
+		if namespace == "" {
+			namespace = s.namespace
+		}
+		if err := repositories.ValidateNamespace(namespace); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if identity := identityFromContext(r.Context()); identity != nil && !identity.CanAccess(namespace) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("%w: no access to namespace %q", ErrForbidden, namespace))
+			return
+		}
+		handler(w, r.WithContext(repositories.WithNamespace(r.Context(), namespace)))
+	})
+}
+
+// identityInfo is the response of an identity read.
+type identityInfo struct {
+	Subject       string   `json:"subject"`
+	Role          string   `json:"role"`
+	Namespaces    []string `json:"namespaces,omitempty"`
+	Authenticated bool     `json:"authenticated"`
+}
+
+func (s *Server) getIdentity(w http.ResponseWriter, r *http.Request) {
+	identity := identityFromContext(r.Context())
+	writeJSON(w, http.StatusOK, identityInfo{
+		Subject:       identity.Subject,
+		Role:          identity.Role.String(),
+		Namespaces:    identity.Namespaces,
+		Authenticated: s.authenticator != nil,
+	})
+}
+
+// resultPage is the response of a result listing.
+type resultPage struct {
+	NextPageToken string                       `json:"next_page_token,omitempty"`
+	Results       []*execution.ExecutionResult `json:"results"`
+}
+
+// summaryPage is the response of a result listing with view=summary.
+type summaryPage struct {
+	NextPageToken string          `json:"next_page_token,omitempty"`
+	Results       []resultSummary `json:"results"`
+}
+
+// resultSummary is a result without its controls, for overviews.
+type resultSummary struct {
+	StartTime      time.Time               `json:"start_time"`
+	EndTime        time.Time               `json:"end_time"`
+	ExecutionID    string                  `json:"execution_id"`
+	ProfileName    string                  `json:"profile_name"`
+	ProfileVersion string                  `json:"profile_version"`
+	Environment    string                  `json:"environment,omitempty"`
+	Summary        execution.ResultSummary `json:"summary"`
+}
+
+func (s *Server) listResults(w http.ResponseWriter, r *http.Request) {
+	query, err := parseResultQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	view := r.URL.Query().Get("view")
+	if view != "" && view != "full" && view != "summary" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid view %q (must be full or summary)", view))
+		return
+	}
+	page, err := s.history.List(r.Context(), query)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	if view == "summary" {
+		summaries := make([]resultSummary, 0, len(page.Results))
+		for _, result := range page.Results {
+			summaries = append(summaries, resultSummary{
+				StartTime:      result.StartTime,
+				EndTime:        result.EndTime,
+				ExecutionID:    result.GetID().String(),
+				ProfileName:    result.ProfileName,
+				ProfileVersion: result.ProfileVersion,
+				Environment:    result.Environment,
+				Summary:        result.Summary,
+			})
+		}
+		writeJSON(w, http.StatusOK, summaryPage{Results: summaries, NextPageToken: page.NextPageToken})
+		return
+	}
+	results := page.Results
+	if results == nil {
+		results = []*execution.ExecutionResult{}
+	}
+	writeJSON(w, http.StatusOK, resultPage{Results: results, NextPageToken: page.NextPageToken})
+}
+
+func (s *Server) getResult(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := values.ParseExecutionID(id); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	result, err := s.history.Find(r.Context(), id)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// importReport is the response of an import.
+type importReport struct {
+	Imported []string `json:"imported"`
+	Skipped  []string `json:"skipped"`
+}
+
+func (s *Server) importResults(w http.ResponseWriter, r *http.Request) {
+	var archive execution.ResultArchive
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBytes)).Decode(&archive); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid result archive: %w", err))
+		return
+	}
+	report, err := s.history.Import(r.Context(), &archive)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.logger.Info("imported results",
+		"namespace", repositories.NamespaceFromContext(r.Context()),
+		"subject", identityFromContext(r.Context()).Subject,
+		"imported", len(report.Imported),
+		"skipped", len(report.Skipped),
+		"source", report.Source.Hostname)
+	writeJSON(w, http.StatusOK, importReport{
+		Imported: nonNil(report.Imported),
+		Skipped:  nonNil(report.Skipped),
+	})
+}
+
+// parseResultQuery reads a result query from URL parameters: profile,
+// environment, since and until (RFC 3339), limit and page_token.
+func parseResultQuery(r *http.Request) (repositories.ResultQuery, error) {
+	params := r.URL.Query()
+	query := repositories.ResultQuery{
+		ProfileName: params.Get("profile"),
+		Environment: params.Get("environment"),
+		PageToken:   params.Get("page_token"),
+	}
+	for name, target := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		if v := params.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return query, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			*target = t
+		}
+	}
+	if v := params.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return query, fmt.Errorf("invalid limit %q (must be 1-%d)", v, maxPageSize)
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+// fail writes the response for an error of the application layer.
+func (s *Server) fail(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrResultNotFound), errors.Is(err, services.ErrRunNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, repositories.ErrVersionConflict):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, repositories.ErrInvalidPageToken), errors.Is(err, execution.ErrInvalidArchive),
+		errors.Is(err, services.ErrUnknownProfile):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, execution.ErrArchiveTampered):
+		writeError(w, http.StatusUnprocessableEntity, err)
+	default:
+		s.logger.Error("api request failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errors.New("internal error"))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body) // the client is gone if this fails
+}
+
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
@@ -0,0 +1,495 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/services"
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/persistence/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *memory.ExecutionResultRepository) {
+	t.Helper()
+
+	repo := memory.NewExecutionResultRepository()
+	history := services.NewResultHistoryService(repo, execution.ArchiveSource{Hostname: "central"})
+	server := httptest.NewServer(NewServer(history, repositories.DefaultNamespace, nil).Handler())
+	t.Cleanup(server.Close)
+	return server, repo
+}
+
+func get(t *testing.T, url, namespace string, body any) int {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if namespace != "" {
+		req.Header.Set(NamespaceHeader, namespace)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if body != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(body))
+	}
+	return resp.StatusCode
+}
+
+func TestServer_NamespaceIsolation(t *testing.T) {
+	t.Parallel()
+	server, repo := newTestServer(t)
+
+	result := execution.NewExecutionResult("web", "1.0.0")
+	require.NoError(t, repo.Save(repositories.WithNamespace(context.Background(), "team-a"), result))
+	url := server.URL + "/api/v1/results/" + result.GetID().String()
+
+	var found execution.ExecutionResult
+	assert.Equal(t, http.StatusOK, get(t, url, "team-a", &found))
+	assert.Equal(t, result.ExecutionID, found.ExecutionID)
+
+	assert.Equal(t, http.StatusNotFound, get(t, url, "team-b", nil))
+	assert.Equal(t, http.StatusNotFound, get(t, url, "", nil), "no header means the default namespace")
+	assert.Equal(t, http.StatusBadRequest, get(t, url, "../team-a", nil))
+
+	var page resultPage
+	assert.Equal(t, http.StatusOK, get(t, server.URL+"/api/v1/results?profile=web", "team-b", &page))
+	assert.Empty(t, page.Results)
+	assert.Equal(t, http.StatusOK, get(t, server.URL+"/api/v1/results?profile=web", "team-a", &page))
+	assert.Len(t, page.Results, 1)
+}
+
+func TestServer_ListPagination(t *testing.T) {
+	t.Parallel()
+	server, repo := newTestServer(t)
+
+	for range 3 {
+		require.NoError(t, repo.Save(context.Background(), execution.NewExecutionResult("web", "1.0.0")))
+	}
+
+	var first, second resultPage
+	require.Equal(t, http.StatusOK, get(t, server.URL+"/api/v1/results?limit=2", "", &first))
+	assert.Len(t, first.Results, 2)
+	require.NotEmpty(t, first.NextPageToken)
+	require.Equal(t, http.StatusOK, get(t, server.URL+"/api/v1/results?limit=2&page_token="+first.NextPageToken, "", &second))
+	assert.Len(t, second.Results, 1)
+	assert.Empty(t, second.NextPageToken)
+
+	assert.Equal(t, http.StatusBadRequest, get(t, server.URL+"/api/v1/results?limit=0", "", nil))
+	assert.Equal(t, http.StatusBadRequest, get(t, server.URL+"/api/v1/results?since=yesterday", "", nil))
+	assert.Equal(t, http.StatusBadRequest, get(t, server.URL+"/api/v1/results?page_token=bogus", "", nil))
+	assert.Equal(t, http.StatusBadRequest, get(t, server.URL+"/api/v1/results/latest", "", nil))
+}
+
+func TestServer_Import(t *testing.T) {
+	t.Parallel()
+	server, repo := newTestServer(t)
+
+	archive := execution.NewResultArchive(execution.ArchiveSource{Hostname: "airgap-01"}, time.Now())
+	result := execution.NewExecutionResult("web", "1.0.0")
+	require.NoError(t, archive.Add(result))
+	body, err := json.Marshal(archive)
+	require.NoError(t, err)
+
+	post := func(namespace string, body []byte) (int, importReport) {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/api/v1/results/import", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(NamespaceHeader, namespace)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var report importReport
+		_ = json.NewDecoder(resp.Body).Decode(&report)
+		return resp.StatusCode, report
+	}
+
+	status, report := post("team-a", body)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{result.GetID().String()}, report.Imported)
+
+	_, err = repo.FindByID(repositories.WithNamespace(context.Background(), "team-a"), result.GetID().UUID())
+	require.NoError(t, err, "imported into the namespace of the request")
+	_, err = repo.FindByID(context.Background(), result.GetID().UUID())
+	require.ErrorIs(t, err, repositories.ErrResultNotFound)
+
+	status, report = post("team-a", body)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{result.GetID().String()}, report.Skipped)
+
+	tampered := bytes.Replace(body, []byte(`"profile_name":"web"`), []byte(`"profile_name":"api"`), 1)
+	require.NotEqual(t, body, tampered)
+	status, _ = post("team-b", tampered)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+	status, _ = post("team-b", []byte(`{"format":"other"}`))
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestServer_RefusesCrossSiteWrites(t *testing.T) {
+	t.Parallel()
+	server, _ := newTestServer(t)
+
+	post := func(contentType string, headers map[string]string) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/api/v1/results/import", bytes.NewReader([]byte(`{}`)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, post("text/plain", nil), "a form post needs no preflight")
+	assert.Equal(t, http.StatusUnsupportedMediaType, post("application/x-www-form-urlencoded", nil))
+	assert.Equal(t, http.StatusForbidden, post("application/json", map[string]string{"Origin": "https://evil.example"}))
+	assert.Equal(t, http.StatusForbidden, post("application/json", map[string]string{"Sec-Fetch-Site": "cross-site"}))
+	assert.Equal(t, http.StatusBadRequest, post("application/json", map[string]string{"Origin": server.URL}), "same-origin requests reach the handler")
+	assert.Equal(t, http.StatusBadRequest, post("application/json; charset=utf-8", nil))
+}
+
+type fakePlugins struct {
+	pruned int
+}
+
+func (f *fakePlugins) ListCachedPlugins(context.Context) ([]*entities.Plugin, error) {
+	ref, err := values.ParsePluginReference("ghcr.io/reglet-dev/plugins/file:1.2.0")
+	if err != nil {
+		return nil, err
+	}
+	return []*entities.Plugin{entities.NewPlugin(ref, values.Digest{}, values.PluginMetadata{})}, nil
+}
+
+func (f *fakePlugins) PruneCache(_ context.Context, keepVersions int) error {
+	f.pruned = keepVersions
+	return nil
+}
+
+type fakeGrants capabilities.Grant
+
+func (f *fakeGrants) Load() (capabilities.Grant, error) {
+	return capabilities.Grant(*f), nil
+}
+
+func (f *fakeGrants) Save(grants capabilities.Grant) error {
+	*f = fakeGrants(grants)
+	return nil
+}
+
+type fakeInstaller struct {
+	installed []dto.InstalledPlugin
+}
+
+func (f *fakeInstaller) Install(_ context.Context, declaration string, verifySignature bool) (dto.InstalledPlugin, error) {
+	if declaration != "reglet/file@1.2" {
+		return dto.InstalledPlugin{}, fmt.Errorf("plugin %s is not in the index", declaration)
+	}
+	plugin := dto.InstalledPlugin{Name: "reglet/file", Version: "1.2.0", SignatureVerified: verifySignature}
+	f.installed = append(f.installed, plugin)
+	return plugin, nil
+}
+
+func (f *fakeInstaller) List(context.Context) ([]dto.InstalledPlugin, error) {
+	return f.installed, nil
+}
+
+func (f *fakeInstaller) Remove(_ context.Context, declaration string) ([]dto.InstalledPlugin, error) {
+	removed := f.installed
+	f.installed = nil
+	return removed, nil
+}
+
+func TestServer_RoleBasedAccess(t *testing.T) {
+	t.Parallel()
+	issuer := newTokenIssuer(t)
+
+	repo := memory.NewExecutionResultRepository()
+	plugins := &fakePlugins{}
+	api := NewServer(services.NewResultHistoryService(repo, execution.ArchiveSource{}), repositories.DefaultNamespace, nil)
+	api.SetAuthenticator(issuer.authenticator(AuthConfig{NamespacesClaim: "namespaces"}))
+	api.SetPluginManager(plugins)
+	api.SetPluginInstaller(&fakeInstaller{})
+	api.SetCapabilityPolicy("strict", &fakeGrants{{Kind: "fs", Pattern: "read:/etc/**"}})
+	server := httptest.NewServer(api.Handler())
+	t.Cleanup(server.Close)
+
+	archive, err := json.Marshal(execution.NewResultArchive(execution.ArchiveSource{}, time.Now()))
+	require.NoError(t, err)
+
+	do := func(method, path, token, namespace string, body []byte) int {
+		req, err := http.NewRequest(method, server.URL+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if namespace != "" {
+			req.Header.Set(NamespaceHeader, namespace)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	token := func(role string) string {
+		return issuer.sign(t, map[string]any{"roles": role, "namespaces": []string{"default", "team-a"}})
+	}
+	viewer, operator, admin := token("viewer"), token("operator"), token("admin")
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/healthz", "", "", nil), "liveness needs no token")
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/api/v1/results", "", "", nil))
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/api/v1/results", "bogus", "", nil))
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet, "/api/v1/results", token("owner"), "", nil))
+
+	// Viewers read results of their namespaces only.
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/results", viewer, "", nil))
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/results", viewer, "team-a", nil))
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet, "/api/v1/results", admin, "team-b", nil))
+
+	// Operators also submit results.
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "/api/v1/results/import", viewer, "", archive))
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/api/v1/results/import", operator, "", archive))
+
+	// Admins also manage plugins and read the capability policy.
+	for _, path := range []string{"/api/v1/plugins", "/api/v1/capabilities"} {
+		assert.Equal(t, http.StatusForbidden, do(http.MethodGet, path, operator, "", nil), path)
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, path, admin, "", nil), path)
+	}
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "/api/v1/plugins/prune", operator, "", []byte(`{"keep_versions":2}`)))
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/api/v1/plugins/prune", admin, "", []byte(`{"keep_versions":0}`)))
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/api/v1/plugins/prune", admin, "", []byte(`{"keep_versions":2}`)))
+	assert.Equal(t, 2, plugins.pruned)
+
+	// Installing plugins and granting capabilities is for admins only.
+	install := []byte(`{"plugin":"reglet/file@1.2"}`)
+	grants := []byte(`{"grants":[{"kind":"fs","pattern":"read:/var/log/**"}]}`)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "/api/v1/plugins/install", operator, "", install))
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPut, "/api/v1/capabilities/grants", operator, "", grants))
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/api/v1/plugins/install", admin, "", install))
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "/api/v1/capabilities/grants", admin, "", grants))
+}
+
+func TestServer_AdminEndpoints(t *testing.T) {
+	t.Parallel()
+
+	api := NewServer(services.NewResultHistoryService(memory.NewExecutionResultRepository(), execution.ArchiveSource{}), repositories.DefaultNamespace, nil)
+	api.SetPluginManager(&fakePlugins{})
+	installer := &fakeInstaller{}
+	api.SetPluginInstaller(installer)
+	api.SetCapabilityPolicy("strict", &fakeGrants{{Kind: "fs", Pattern: "read:/etc/**"}})
+	server := httptest.NewServer(api.Handler())
+	t.Cleanup(server.Close)
+
+	var plugins map[string][]pluginInfo
+	require.Equal(t, http.StatusOK, get(t, server.URL+"/api/v1/plugins", "", &plugins))
+	require.Len(t, plugins["plugins"], 1)
+	assert.Equal(t, "file", plugins["plugins"][0].Name)
+	assert.Equal(t, "1.2.0", plugins["plugins"][0].Version)
+
+	var policy capabilityPolicy
+	require.Equal(t, http.StatusOK, get(t, server.URL+"/api/v1/capabilities", "", &policy))
+	assert.Equal(t, capabilityPolicy{
+		SecurityLevel: "strict",
+		Grants:        []capabilityGrant{{Kind: "fs", Pattern: "read:/etc/**"}},
+	}, policy)
+
+	send := func(method, path, body string, out any) int {
+		req, err := http.NewRequest(method, server.URL+path, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+		return resp.StatusCode
+	}
+
+	// Grants are replaced as a whole; broad grants are refused at the
+	// strict security level.
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPut, "/api/v1/capabilities/grants", `{"grants":[{"kind":"shell","pattern":"x"}]}`, nil))
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPut, "/api/v1/capabilities/grants", `{"grants":[{"kind":"fs","pattern":""}]}`, nil))
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPut, "/api/v1/capabilities/grants", `{"grants":[{"kind":"fs","pattern":"write:/etc/**"}]}`, nil))
+	require.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/capabilities/grants", `{"grants":[{"kind":"env","pattern":"HOME"}]}`, &policy))
+	assert.Equal(t, []capabilityGrant{{Kind: "env", Pattern: "HOME"}}, policy.Grants)
+
+	// Plugins are installed from the index and removed again.
+	var installed installedPlugin
+	assert.Equal(t, http.StatusUnprocessableEntity, send(http.MethodPost, "/api/v1/plugins/install", `{"plugin":"reglet/nope@1"}`, nil))
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/plugins/install", `{}`, nil))
+	require.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/plugins/install", `{"plugin":"reglet/file@1.2","verify_signature":true}`, &installed))
+	assert.Equal(t, "1.2.0", installed.Version)
+	assert.True(t, installed.SignatureVerified)
+
+	var list map[string][]installedPlugin
+	require.Equal(t, http.StatusOK, get(t, server.URL+"/api/v1/plugins/installed", "", &list))
+	assert.Len(t, list["plugins"], 1)
+	require.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/plugins/remove", `{"plugin":"reglet/file"}`, &list))
+	assert.Len(t, list["removed"], 1)
+	assert.Empty(t, installer.installed)
+}
+
+func TestServer_Dashboard(t *testing.T) {
+	t.Parallel()
+	server, _ := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+	assert.Contains(t, resp.Header.Get("Content-Security-Policy"), "default-src 'self'")
+
+	for _, asset := range []string{"/app.js", "/style.css"} {
+		assert.Equal(t, http.StatusOK, get(t, server.URL+asset, "", nil), asset)
+	}
+	assert.Equal(t, http.StatusNotFound, get(t, server.URL+"/api/v1/runs", "", nil), "runs are off without profiles")
+}
+
+func TestServer_SummaryView(t *testing.T) {
+	t.Parallel()
+	server, repo := newTestServer(t)
+
+	result := execution.NewExecutionResult("web", "1.0.0")
+	result.Environment = "prod"
+	result.Controls = []execution.ControlResult{{ID: "c1", Status: values.StatusPass}}
+	result.Finalize()
+	require.NoError(t, repo.Save(context.Background(), result))
+
+	var page summaryPage
+	require.Equal(t, http.StatusOK, get(t, server.URL+"/api/v1/results?view=summary", "", &page))
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, result.GetID().String(), page.Results[0].ExecutionID)
+	assert.Equal(t, "prod", page.Results[0].Environment)
+	assert.Equal(t, 1, page.Results[0].Summary.PassedControls)
+
+	assert.Equal(t, http.StatusBadRequest, get(t, server.URL+"/api/v1/results?view=compact", "", nil))
+
+	var identity identityInfo
+	require.Equal(t, http.StatusOK, get(t, server.URL+"/api/v1/me", "", &identity))
+	assert.Equal(t, identityInfo{Subject: "anonymous", Role: "admin"}, identity)
+}
+
+func TestServer_Runs(t *testing.T) {
+	t.Parallel()
+	issuer := newTokenIssuer(t)
+
+	repo := memory.NewExecutionResultRepository()
+	runs := services.NewRunService(map[string]string{"web": "web.yaml"}, func(ctx context.Context, _ dto.CheckProfileRequest) (*execution.ExecutionResult, error) {
+		result := execution.NewExecutionResult("web", "1.0.0")
+		return result, repo.Save(ctx, result)
+	}, dto.RunQueueOptions{}, nil)
+	api := NewServer(services.NewResultHistoryService(repo, execution.ArchiveSource{}), repositories.DefaultNamespace, nil)
+	api.SetAuthenticator(issuer.authenticator(AuthConfig{}))
+	api.SetRunService(runs)
+	server := httptest.NewServer(api.Handler())
+	t.Cleanup(server.Close)
+	t.Cleanup(api.Close)
+
+	do := func(method, path, role string, body string, out any) int {
+		req, err := http.NewRequest(method, server.URL+path, bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+issuer.sign(t, map[string]any{"roles": role}))
+		req.Header.Set(NamespaceHeader, "team-a")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+		return resp.StatusCode
+	}
+
+	var profiles map[string][]string
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/profiles", "viewer", "", &profiles))
+	assert.Equal(t, []string{"web"}, profiles["profiles"])
+
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "/api/v1/runs", "viewer", `{"profile":"web"}`, nil))
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/api/v1/runs", "operator", `{"profile":"db"}`, nil))
+
+	var started runInfo
+	require.Equal(t, http.StatusAccepted, do(http.MethodPost, "/api/v1/runs", "operator", `{"profile":"web"}`, &started))
+	assert.Equal(t, "alice", started.RequestedBy)
+	assert.Equal(t, "team-a", started.Namespace)
+
+	var run runInfo
+	require.Eventually(t, func() bool {
+		require.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/runs/"+started.ID, "viewer", "", &run))
+		return run.State == dto.RunStateCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NotNil(t, run.FinishedAt)
+
+	var found execution.ExecutionResult
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/results/"+run.ExecutionID, "viewer", "", &found),
+		"the result is stored in the namespace the run was requested in")
+
+	var listed map[string][]runInfo
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/runs", "viewer", "", &listed))
+	assert.Len(t, listed["runs"], 1)
+
+	var queue queueInfo
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/queue", "viewer", "", &queue))
+	assert.Equal(t, queueInfo{Runs: []runInfo{}, MaxConcurrent: 4, MaxPerProfile: 1}, queue)
+}
+
+func TestServer_RunQueue(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	runs := services.NewRunService(map[string]string{"web": "web.yaml"}, func(ctx context.Context, _ dto.CheckProfileRequest) (*execution.ExecutionResult, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return execution.NewExecutionResult("web", "1.0.0"), nil
+	}, dto.RunQueueOptions{}, nil)
+	api := NewServer(services.NewResultHistoryService(memory.NewExecutionResultRepository(), execution.ArchiveSource{}), repositories.DefaultNamespace, nil)
+	api.SetRunService(runs)
+	server := httptest.NewServer(api.Handler())
+	t.Cleanup(server.Close)
+	t.Cleanup(api.Close)
+	t.Cleanup(func() { close(release) })
+
+	trigger := func(out *runInfo) int {
+		resp, err := http.Post(server.URL+"/api/v1/runs", "application/json", bytes.NewReader([]byte(`{"profile":"web"}`)))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		return resp.StatusCode
+	}
+
+	var running, queued, joined runInfo
+	require.Equal(t, http.StatusAccepted, trigger(&running))
+	assert.Equal(t, dto.RunStateRunning, running.State)
+	assert.NotNil(t, running.StartedAt)
+	require.Equal(t, http.StatusAccepted, trigger(&queued))
+	assert.Equal(t, dto.RunStateQueued, queued.State)
+	assert.Equal(t, 1, queued.Position)
+	require.Equal(t, http.StatusOK, trigger(&joined), "an identical queued request is joined")
+	assert.Equal(t, queued.ID, joined.ID)
+
+	var queue queueInfo
+	require.Equal(t, http.StatusOK, get(t, server.URL+"/api/v1/queue", "", &queue))
+	assert.Equal(t, 1, queue.Running)
+	assert.Equal(t, 1, queue.Queued)
+	require.Len(t, queue.Runs, 2)
+	assert.Equal(t, []string{running.ID, queued.ID}, []string{queue.Runs[0].ID, queue.Runs[1].ID})
+}
@@ -0,0 +1,134 @@
+// Package i18n translates the user-facing strings of reports. Each language
+// has a message catalog in catalogs/<lang>.json mapping message keys to
+// fmt format strings; messages missing from a catalog fall back to English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is used when no supported language is selected.
+const DefaultLanguage = "en"
+
+//go:embed catalogs/*.json
+var catalogFS embed.FS
+
+var (
+	loadOnce sync.Once
+	catalogs map[string]map[string]string
+)
+
+// load parses the embedded catalogs once.
+func load() map[string]map[string]string {
+	loadOnce.Do(func() {
+		catalogs = make(map[string]map[string]string)
+		entries, err := catalogFS.ReadDir("catalogs")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: failed to read catalogs: %v", err))
+		}
+		for _, entry := range entries {
+			data, err := catalogFS.ReadFile(path.Join("catalogs", entry.Name()))
+			if err != nil {
+				panic(fmt.Sprintf("i18n: failed to read catalog %s: %v", entry.Name(), err))
+			}
+			var messages map[string]string
+			if err := json.Unmarshal(data, &messages); err != nil {
+				panic(fmt.Sprintf("i18n: invalid catalog %s: %v", entry.Name(), err))
+			}
+			catalogs[strings.TrimSuffix(entry.Name(), ".json")] = messages
+		}
+	})
+	return catalogs
+}
+
+// Languages returns the codes of the languages with a catalog, sorted.
+func Languages() []string {
+	langs := make([]string, 0, len(load()))
+	for lang := range load() {
+		langs = append(langs, lang)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// base returns the lowercased language of a language tag or POSIX locale.
+func base(tag string) string {
+	lang := strings.ToLower(tag)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// Supported reports whether the language of a tag has a catalog.
+func Supported(tag string) bool {
+	_, ok := load()[base(tag)]
+	return ok
+}
+
+// Resolve maps a language tag or POSIX locale ("de", "de-AT",
+// "fr_FR.UTF-8") to a supported language, DefaultLanguage when there is none.
+func Resolve(tag string) string {
+	if Supported(tag) {
+		return base(tag)
+	}
+	return DefaultLanguage
+}
+
+// FromEnv returns the language of the POSIX locale variables, in their order
+// of precedence: LC_ALL, LC_MESSAGES, LANG.
+func FromEnv(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := getenv(name); value != "" {
+			return Resolve(value)
+		}
+	}
+	return DefaultLanguage
+}
+
+// Catalog translates messages into one language.
+type Catalog struct {
+	messages map[string]string
+	lang     string
+}
+
+// For returns the catalog of a language tag, resolved with Resolve.
+func For(tag string) *Catalog {
+	lang := Resolve(tag)
+	return &Catalog{lang: lang, messages: load()[lang]}
+}
+
+// Lang returns the language code of the catalog.
+func (c *Catalog) Lang() string {
+	return c.lang
+}
+
+// T returns the message of key formatted with args. Keys missing from the
+// catalog use the English message, or else the key itself.
+func (c *Catalog) T(key string, args ...any) string {
+	msg, ok := c.messages[key]
+	if !ok {
+		if msg, ok = load()[DefaultLanguage][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Has reports whether key has a message, in this language or in English.
+func (c *Catalog) Has(key string) bool {
+	if _, ok := c.messages[key]; ok {
+		return true
+	}
+	_, ok := load()[DefaultLanguage][key]
+	return ok
+}
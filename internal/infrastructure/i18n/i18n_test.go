@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguages(t *testing.T) {
+	assert.Equal(t, []string{"de", "en", "fr"}, Languages())
+}
+
+func TestResolve(t *testing.T) {
+	assert.Equal(t, "de", Resolve("de"))
+	assert.Equal(t, "de", Resolve("de-AT"))
+	assert.Equal(t, "fr", Resolve("fr_FR.UTF-8"))
+	assert.Equal(t, "en", Resolve("C.UTF-8"))
+	assert.Equal(t, "en", Resolve("ja_JP"))
+	assert.Equal(t, "en", Resolve(""))
+
+	assert.True(t, Supported("fr-CA"))
+	assert.False(t, Supported("ja"))
+}
+
+func TestFromEnv(t *testing.T) {
+	env := map[string]string{"LANG": "fr_FR.UTF-8"}
+	getenv := func(name string) string { return env[name] }
+	assert.Equal(t, "fr", FromEnv(getenv))
+
+	env["LC_ALL"] = "de_DE.UTF-8"
+	assert.Equal(t, "de", FromEnv(getenv))
+
+	assert.Equal(t, "en", FromEnv(func(string) string { return "" }))
+}
+
+func TestCatalog_T(t *testing.T) {
+	de := For("de")
+	assert.Equal(t, "de", de.Lang())
+	assert.Equal(t, "Zusammenfassung", de.T("summary"))
+	assert.Equal(t, "3 gesamt", de.T("n_total", 3))
+	assert.Equal(t, "no.such.key", de.T("no.such.key"))
+	assert.False(t, de.Has("no.such.key"))
+
+	de.messages = map[string]string{}
+	assert.Equal(t, "Summary", de.T("summary"), "falls back to English")
+}
+
+// TestCatalogsComplete keeps every catalog in step with the English one:
+// the same keys, with the same format verbs.
+func TestCatalogsComplete(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+	en := load()[DefaultLanguage]
+	for _, lang := range Languages() {
+		messages := load()[lang]
+		assert.Len(t, messages, len(en), "catalog %s", lang)
+		for key, msg := range en {
+			translated, ok := messages[key]
+			if assert.True(t, ok, "catalog %s lacks %q", lang, key) {
+				assert.Equal(t, verbs.FindAllString(msg, -1), verbs.FindAllString(translated, -1), "catalog %s, %q", lang, key)
+			}
+		}
+	}
+}
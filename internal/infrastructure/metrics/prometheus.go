@@ -0,0 +1,150 @@
+// Package metrics exposes the results of runs as Prometheus metrics: the
+// status of every control, observation durations, and engine statistics
+// such as plugin compile time and WASM instantiations.
+package metrics
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// controlStatuses are the states of the reglet_control_status state set.
+var controlStatuses = []values.Status{
+	values.StatusPass, values.StatusFail, values.StatusError,
+	values.StatusSkipped, values.StatusNotRun, values.StatusCollected,
+}
+
+// observationBuckets are the bounds of the observation duration histogram,
+// in seconds, from local file checks to slow network calls.
+var observationBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Exporter keeps the metrics of the latest run of each profile for scraping.
+// It is safe for concurrent use.
+type Exporter struct {
+	registry *prometheus.Registry
+
+	controlPassed *prometheus.GaugeVec
+	controlStatus *prometheus.GaugeVec
+
+	observationDuration *prometheus.HistogramVec
+	instantiations      *prometheus.CounterVec
+
+	runs         *prometheus.CounterVec
+	runDuration  *prometheus.GaugeVec
+	runTimestamp *prometheus.GaugeVec
+	pluginLoad   *prometheus.GaugeVec
+}
+
+// NewExporter creates an exporter without any runs recorded.
+func NewExporter() *Exporter {
+	controlLabels := []string{"profile", "control", "severity", "tags"}
+	e := &Exporter{
+		registry: prometheus.NewRegistry(),
+		controlPassed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "reglet_control_passed",
+			Help: "Whether the control passed in the latest run (1) or not (0). tags lists the control's tags, comma-separated.",
+		}, controlLabels),
+		controlStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "reglet_control_status",
+			Help: "Status of the control in the latest run: 1 for its status, 0 for the others.",
+		}, append(slices.Clone(controlLabels), "status")),
+		observationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "reglet_observation_duration_seconds",
+			Help:    "Duration of observations, by plugin and observation status.",
+			Buckets: observationBuckets,
+		}, []string{"profile", "plugin", "status"}),
+		instantiations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "reglet_wasm_instantiations_total",
+			Help: "WASM plugin instances created to run observations.",
+		}, []string{"profile", "plugin"}),
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "reglet_runs_total",
+			Help: "Runs of the profile.",
+		}, []string{"profile"}),
+		runDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "reglet_run_duration_seconds",
+			Help: "Duration of the latest run of the profile.",
+		}, []string{"profile"}),
+		runTimestamp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "reglet_last_run_timestamp_seconds",
+			Help: "Start time of the latest run of the profile, in seconds since the epoch.",
+		}, []string{"profile"}),
+		pluginLoad: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "reglet_plugin_load_seconds",
+			Help: "Time the latest run of the profile spent compiling WASM plugins, summed over plugins.",
+		}, []string{"profile"}),
+	}
+	e.registry.MustRegister(
+		e.controlPassed, e.controlStatus,
+		e.observationDuration, e.instantiations,
+		e.runs, e.runDuration, e.runTimestamp, e.pluginLoad,
+	)
+	return e
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (e *Exporter) Handler() http.Handler {
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{})
+}
+
+// Record replaces the control metrics of the result's profile with those of
+// the result and adds its observations to the histograms and counters.
+// WASM instantiations and plugin load time are only known for runs with a
+// performance breakdown.
+func (e *Exporter) Record(result *execution.ExecutionResult) {
+	profile := prometheus.Labels{"profile": result.ProfileName}
+	e.controlPassed.DeletePartialMatch(profile)
+	e.controlStatus.DeletePartialMatch(profile)
+
+	for i := range result.Controls {
+		ctrl := &result.Controls[i]
+		labels := []string{result.ProfileName, ctrl.ID, ctrl.Severity, joinTags(ctrl.Tags)}
+
+		passed := 0.0
+		if ctrl.Status == values.StatusPass {
+			passed = 1
+		}
+		e.controlPassed.WithLabelValues(labels...).Set(passed)
+		for _, status := range controlStatuses {
+			current := 0.0
+			if ctrl.Status == status {
+				current = 1
+			}
+			e.controlStatus.WithLabelValues(append(labels, string(status))...).Set(current)
+		}
+
+		for _, obs := range ctrl.ObservationResults {
+			e.observationDuration.WithLabelValues(result.ProfileName, obs.Plugin, string(obs.Status)).Observe(obs.Duration.Seconds())
+			if obs.Timing != nil && obs.Timing.Instantiation > 0 {
+				e.instantiations.WithLabelValues(result.ProfileName, obs.Plugin).Inc()
+			}
+		}
+	}
+
+	e.runs.With(profile).Inc()
+	e.runDuration.With(profile).Set(result.Duration.Seconds())
+	e.runTimestamp.With(profile).Set(float64(result.StartTime.UnixNano()) / 1e9)
+	if result.Performance != nil {
+		load := 0.0
+		for _, phase := range result.Performance.Phases {
+			if phase.Phase == execution.PhasePluginCompile {
+				load = phase.Duration.Seconds()
+			}
+		}
+		e.pluginLoad.With(profile).Set(load)
+	}
+}
+
+// joinTags returns the tags sorted and comma-separated, so a control's
+// series keeps its labels whatever the order of its tags.
+func joinTags(tags []string) string {
+	sorted := slices.Clone(tags)
+	slices.Sort(sorted)
+	return strings.Join(sorted, ",")
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrape returns the metrics served by the exporter.
+func scrape(t *testing.T, e *Exporter) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	e.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestExporter_Record(t *testing.T) {
+	t.Parallel()
+
+	result := &execution.ExecutionResult{
+		ProfileName: "web",
+		StartTime:   time.Unix(1700000000, 0),
+		Duration:    2 * time.Second,
+		Controls: []execution.ControlResult{
+			{
+				ID: "tls", Severity: "high", Tags: []string{"web", "crypto"}, Status: values.StatusPass,
+				ObservationResults: []execution.ObservationResult{{
+					Plugin: "http", Status: values.StatusPass, Duration: 30 * time.Millisecond,
+					Timing: &execution.ObservationTiming{Instantiation: time.Millisecond},
+				}},
+			},
+			{
+				ID: "ssh", Severity: "critical", Status: values.StatusFail,
+				ObservationResults: []execution.ObservationResult{{Plugin: "tcp", Status: values.StatusFail, Duration: 2 * time.Second}},
+			},
+		},
+		Performance: &execution.PerformanceReport{Phases: []execution.PhaseTiming{
+			{Phase: execution.PhasePluginCompile, Duration: 1500 * time.Millisecond},
+		}},
+	}
+
+	e := NewExporter()
+	e.Record(result)
+	body := scrape(t, e)
+
+	assert.Contains(t, body, `reglet_control_passed{control="tls",profile="web",severity="high",tags="crypto,web"} 1`)
+	assert.Contains(t, body, `reglet_control_passed{control="ssh",profile="web",severity="critical",tags=""} 0`)
+	assert.Contains(t, body, `reglet_control_status{control="ssh",profile="web",severity="critical",status="fail",tags=""} 1`)
+	assert.Contains(t, body, `reglet_control_status{control="ssh",profile="web",severity="critical",status="pass",tags=""} 0`)
+	assert.Contains(t, body, `reglet_observation_duration_seconds_bucket{plugin="http",profile="web",status="pass",le="0.05"} 1`)
+	assert.Contains(t, body, `reglet_observation_duration_seconds_bucket{plugin="tcp",profile="web",status="fail",le="1"} 0`)
+	assert.Contains(t, body, `reglet_wasm_instantiations_total{plugin="http",profile="web"} 1`)
+	assert.NotContains(t, body, `reglet_wasm_instantiations_total{plugin="tcp"`)
+	assert.Contains(t, body, `reglet_runs_total{profile="web"} 1`)
+	assert.Contains(t, body, `reglet_run_duration_seconds{profile="web"} 2`)
+	assert.Contains(t, body, `reglet_last_run_timestamp_seconds{profile="web"} 1.7e+09`)
+	assert.Contains(t, body, `reglet_plugin_load_seconds{profile="web"} 1.5`)
+
+	// The next run replaces the controls of the profile
+	result.Controls = result.Controls[1:]
+	result.Controls[0].Status = values.StatusPass
+	e.Record(result)
+	body = scrape(t, e)
+
+	assert.NotContains(t, body, `control="tls"`)
+	assert.Contains(t, body, `reglet_control_passed{control="ssh",profile="web",severity="critical",tags=""} 1`)
+	assert.Contains(t, body, `reglet_runs_total{profile="web"} 2`)
+}
@@ -0,0 +1,122 @@
+// Package native runs plugins in-process instead of inside the WASM sandbox.
+// It exists for plugin development: a plugin linked into a Reglet build can be
+// stepped through with a debugger and iterated on without compiling to wasip1.
+//
+// Native plugins are NOT sandboxed. Capabilities are not enforced, so this mode
+// must never be used to run untrusted plugins.
+package native
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+)
+
+// Plugin is an in-process plugin. Its methods mirror the describe, schema and
+// observe WASM exports and exchange the same JSON documents, so the SDK's
+// NativePlugin adapter satisfies it without the host importing the SDK.
+type Plugin interface {
+	Describe(ctx context.Context) ([]byte, error)
+	Schema(ctx context.Context) ([]byte, error)
+	Observe(ctx context.Context, config []byte) ([]byte, error)
+}
+
+// Registry holds native plugins by name.
+type Registry struct {
+	plugins map[string]Plugin
+	mu      sync.RWMutex
+}
+
+// NewRegistry creates an empty native plugin registry.
+func NewRegistry() *Registry {
+	return &Registry{plugins: make(map[string]Plugin)}
+}
+
+// Register adds a plugin under the given name.
+func (r *Registry) Register(name string, p Plugin) error {
+	if name == "" {
+		return fmt.Errorf("native plugin name cannot be empty")
+	}
+	if p == nil {
+		return fmt.Errorf("native plugin %q is nil", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.plugins[name]; exists {
+		return fmt.Errorf("native plugin %q already registered", name)
+	}
+	r.plugins[name] = p
+	return nil
+}
+
+// Lookup returns the plugin registered under name.
+func (r *Registry) Lookup(name string) (Plugin, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.plugins[name]
+	return p, ok
+}
+
+// Names returns the registered plugin names in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.plugins))
+	for name := range r.plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default returns the process-wide registry used by --plugin-mode native.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register adds a plugin to the default registry. It is meant to be called
+// from an init function in a development build and panics on misuse.
+func Register(name string, p Plugin) {
+	if err := defaultRegistry.Register(name, p); err != nil {
+		panic(err)
+	}
+}
+
+// Observe runs a native plugin with the given config and parses its evidence.
+// A panic in the plugin is recovered and reported like a WASM trap.
+func Observe(ctx context.Context, name string, p Plugin, cfg wasm.Config) (result *wasm.PluginObservationResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = fmt.Errorf("native plugin %s panicked: %v\n%s", name, r, debug.Stack())
+		}
+	}()
+
+	configData, err := json.Marshal(cfg.Values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	resultData, err := p.Observe(ctx, configData)
+	if err != nil {
+		return nil, fmt.Errorf("native plugin %s observe failed: %w", name, err)
+	}
+
+	var evidence execution.Evidence
+	if err := json.Unmarshal(resultData, &evidence); err != nil {
+		return nil, fmt.Errorf("failed to parse native plugin %s result: %w", name, err)
+	}
+
+	return &wasm.PluginObservationResult{Evidence: &evidence}, nil
+}
@@ -0,0 +1,98 @@
+package native
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlugin returns canned observe output, mirroring the SDK's NativePlugin.
+type fakePlugin struct {
+	err    error
+	output string
+	panic  bool
+}
+
+func (p *fakePlugin) Describe(_ context.Context) ([]byte, error) {
+	return []byte(`{"Name":"fake"}`), nil
+}
+
+func (p *fakePlugin) Schema(_ context.Context) ([]byte, error) {
+	return []byte(`{}`), nil
+}
+
+func (p *fakePlugin) Observe(_ context.Context, config []byte) ([]byte, error) {
+	if p.panic {
+		panic("plugin bug")
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.output != "" {
+		return []byte(p.output), nil
+	}
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{"Status": true, "Data": cfg})
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	require.NoError(t, r.Register("b", &fakePlugin{}))
+	require.NoError(t, r.Register("a", &fakePlugin{}))
+
+	assert.Error(t, r.Register("a", &fakePlugin{}), "duplicate names are rejected")
+	assert.Error(t, r.Register("", &fakePlugin{}))
+	assert.Error(t, r.Register("c", nil))
+
+	_, ok := r.Lookup("a")
+	assert.True(t, ok)
+	_, ok = r.Lookup("missing")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"a", "b"}, r.Names())
+}
+
+func TestObserve(t *testing.T) {
+	t.Parallel()
+
+	cfg := wasm.Config{Values: map[string]interface{}{"path": "/etc/hosts"}}
+
+	t.Run("evidence", func(t *testing.T) {
+		t.Parallel()
+		result, err := Observe(context.Background(), "fake", &fakePlugin{}, cfg)
+		require.NoError(t, err)
+		require.NotNil(t, result.Evidence)
+		assert.True(t, result.Evidence.Status)
+		assert.Equal(t, "/etc/hosts", result.Evidence.Data["path"])
+	})
+
+	t.Run("observe error", func(t *testing.T) {
+		t.Parallel()
+		_, err := Observe(context.Background(), "fake", &fakePlugin{err: errors.New("boom")}, cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		t.Parallel()
+		_, err := Observe(context.Background(), "fake", &fakePlugin{output: "not json"}, cfg)
+		require.Error(t, err)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+		result, err := Observe(context.Background(), "fake", &fakePlugin{panic: true}, cfg)
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "plugin bug")
+	})
+}
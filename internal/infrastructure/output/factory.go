@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
 )
 
 // FormatterFactory implements ports.OutputFormatterFactory.
@@ -23,15 +24,34 @@ func (f *FormatterFactory) Create(
 ) (ports.OutputFormatter, error) {
 	switch format {
 	case "table":
-		return NewTableFormatter(writer), nil
+		formatter := NewTableFormatter(writer)
+		formatter.Verbosity = options.Verbosity
+		formatter.Width = options.Width
+		formatter.EnableColor = options.Color
+		formatter.Language = options.Language
+		return formatter, nil
 	case "json":
 		return NewJSONFormatter(writer, options.Indent), nil
+	case "jsonl":
+		return NewJSONLFormatter(writer), nil
 	case "yaml":
 		return NewYAMLFormatter(writer), nil
 	case "junit":
 		return NewJUnitFormatter(writer), nil
 	case "sarif":
 		return NewSARIFFormatter(writer, options.ProfilePath), nil
+	case "html":
+		formatter := NewHTMLFormatter(writer)
+		formatter.Language = options.Language
+		return formatter, nil
+	case "html-accessible":
+		formatter := NewAccessibleHTMLFormatter(writer)
+		formatter.Language = options.Language
+		return formatter, nil
+	case "text":
+		formatter := NewTextFormatter(writer)
+		formatter.Language = options.Language
+		return formatter, nil
 	default:
 		return nil, fmt.Errorf(
 			"unknown format: %s (supported: %v)",
@@ -40,7 +60,32 @@ func (f *FormatterFactory) Create(
 	}
 }
 
+// CreateStream returns a streaming writer for the given format name.
+// Only formats that can be emitted incrementally support streaming.
+func (f *FormatterFactory) CreateStream(
+	format string,
+	writer io.Writer,
+	_ ports.FormatterOptions,
+) (execution.ResultStream, error) {
+	switch format {
+	case "json":
+		return NewJSONStreamWriter(writer), nil
+	case "jsonl":
+		return NewJSONLFormatter(writer), nil
+	default:
+		return nil, fmt.Errorf(
+			"format %s does not support streaming (supported: %v)",
+			format, f.StreamingFormats(),
+		)
+	}
+}
+
 // SupportedFormats returns list of available format names.
 func (f *FormatterFactory) SupportedFormats() []string {
-	return []string{"table", "json", "yaml", "junit", "sarif"}
+	return []string{"table", "json", "jsonl", "yaml", "junit", "sarif", "html", "html-accessible", "text"}
+}
+
+// StreamingFormats returns the format names that support streaming output.
+func (f *FormatterFactory) StreamingFormats() []string {
+	return []string{"json", "jsonl"}
 }
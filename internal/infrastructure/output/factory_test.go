@@ -32,6 +32,11 @@ func TestFormatterFactory_Create(t *testing.T) {
 			options:  ports.FormatterOptions{Indent: true},
 			wantType: &JSONFormatter{},
 		},
+		{
+			name:     "jsonl format",
+			format:   "jsonl",
+			wantType: &JSONLFormatter{},
+		},
 		{
 			name:     "yaml format",
 			format:   "yaml",
@@ -48,6 +53,21 @@ func TestFormatterFactory_Create(t *testing.T) {
 			options:  ports.FormatterOptions{ProfilePath: "test.yaml"},
 			wantType: &SARIFFormatter{},
 		},
+		{
+			name:     "html format",
+			format:   "html",
+			wantType: &HTMLFormatter{},
+		},
+		{
+			name:     "html-accessible format",
+			format:   "html-accessible",
+			wantType: &HTMLFormatter{},
+		},
+		{
+			name:     "text format",
+			format:   "text",
+			wantType: &TextFormatter{},
+		},
 		{
 			name:        "unknown format",
 			format:      "invalid",
@@ -79,8 +99,12 @@ func TestFormatterFactory_SupportedFormats(t *testing.T) {
 
 	assert.Contains(t, formats, "table")
 	assert.Contains(t, formats, "json")
+	assert.Contains(t, formats, "jsonl")
 	assert.Contains(t, formats, "yaml")
 	assert.Contains(t, formats, "junit")
 	assert.Contains(t, formats, "sarif")
-	assert.Len(t, formats, 5)
+	assert.Contains(t, formats, "html")
+	assert.Contains(t, formats, "html-accessible")
+	assert.Contains(t, formats, "text")
+	assert.Len(t, formats, 9)
 }
@@ -0,0 +1,99 @@
+package output
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/i18n"
+)
+
+// Ensure interface compliance
+var _ ports.OutputFormatter = (*HTMLFormatter)(nil)
+
+//go:embed templates/report.html.tmpl
+var htmlReportTemplate string
+
+//go:embed templates/report-accessible.html.tmpl
+var accessibleHTMLReportTemplate string
+
+// The report templates are parsed with English messages; Format clones them
+// with the messages of the report language.
+var (
+	htmlReport           = parseHTMLReport(htmlReportTemplate)
+	accessibleHTMLReport = parseHTMLReport(accessibleHTMLReportTemplate)
+)
+
+// parseHTMLReport parses a report template with the report functions.
+func parseHTMLReport(text string) *template.Template {
+	return template.Must(template.New("report").Funcs(template.FuncMap{
+		"duration": func(d time.Duration) string { return d.Round(time.Millisecond).String() },
+		"time":     func(t time.Time) string { return t.Format(time.RFC3339) },
+		"inc":      func(i int) int { return i + 1 },
+		"symbol": func(s values.Status) string {
+			symbol, _ := (&TableFormatter{}).getStatusInfo(s)
+			return symbol
+		},
+	}).Funcs(messageFuncs(i18n.For(i18n.DefaultLanguage))).Parse(text))
+}
+
+// messageFuncs returns the template functions translating report strings.
+func messageFuncs(msg *i18n.Catalog) template.FuncMap {
+	return template.FuncMap{
+		"lang": msg.Lang,
+		"t":    msg.T,
+		"status": func(s values.Status) string {
+			if key := "status." + string(s); msg.Has(key) {
+				return msg.T(key)
+			}
+			return strings.ToUpper(string(s))
+		},
+		"severity": func(severity string) string {
+			if key := "severity." + strings.ToLower(severity); msg.Has(key) {
+				return msg.T(key)
+			}
+			return severity
+		},
+	}
+}
+
+// HTMLFormatter formats execution results as a self-contained HTML report.
+// Errors shared by several observations are listed once per root cause, with
+// the affected observations in an expandable list.
+type HTMLFormatter struct {
+	writer   io.Writer
+	report   *template.Template
+	Language string // Language of the report strings (default: English)
+}
+
+// NewHTMLFormatter creates a new HTML formatter.
+func NewHTMLFormatter(w io.Writer) *HTMLFormatter {
+	return &HTMLFormatter{writer: w, report: htmlReport}
+}
+
+// NewAccessibleHTMLFormatter creates an HTML formatter whose report follows
+// the WCAG basics: landmarks and headings to navigate by, a skip link,
+// table headers with scopes, visible keyboard focus, and statuses spelled
+// out rather than told by color alone.
+func NewAccessibleHTMLFormatter(w io.Writer) *HTMLFormatter {
+	return &HTMLFormatter{writer: w, report: accessibleHTMLReport}
+}
+
+// Format writes the execution result as an HTML document.
+func (f *HTMLFormatter) Format(result *execution.ExecutionResult) error {
+	report, err := f.report.Clone()
+	if err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	report.Funcs(messageFuncs(i18n.For(f.Language)))
+	if err := report.Execute(f.writer, result); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return nil
+}
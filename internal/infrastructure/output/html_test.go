@@ -0,0 +1,124 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createOutageResult returns a result where several controls failed with the
+// same DNS error.
+func createOutageResult(n int) *execution.ExecutionResult {
+	result := execution.NewExecutionResult("outage-profile", "1.0.0")
+	for i := 0; i < n; i++ {
+		result.AddControlResult(execution.ControlResult{
+			ID:     fmt.Sprintf("svc-%d", i),
+			Index:  i,
+			Status: values.StatusError,
+			ObservationResults: []execution.ObservationResult{{
+				Plugin: "http",
+				Status: values.StatusError,
+				Error: &execution.PluginError{
+					Code:    "network",
+					Message: fmt.Sprintf("lookup svc-%d.example.com on 127.0.0.53:53: no such host", i),
+				},
+			}},
+		})
+	}
+	result.Finalize()
+	return result
+}
+
+func TestHTMLFormatter_Format(t *testing.T) {
+	result := createTestResult()
+	var buf bytes.Buffer
+
+	require.NoError(t, NewHTMLFormatter(&buf).Format(result))
+
+	output := buf.String()
+	assert.True(t, strings.HasPrefix(output, "<!DOCTYPE html>"))
+	assert.Contains(t, output, "test-profile")
+	assert.Contains(t, output, `<tr id="control-ctrl-1">`)
+	assert.Contains(t, output, "<code>cmdb_id=CI0042</code>")
+	assert.Contains(t, output, "PASS")
+	assert.Contains(t, output, "FAIL")
+	assert.Contains(t, output, "[plugin_load_error] unknown plugin: nonexistent")
+	assert.NotContains(t, output, "Grouped Errors", "a single error is not grouped")
+}
+
+func TestHTMLFormatter_GroupedErrors(t *testing.T) {
+	result := createOutageResult(42)
+	var buf bytes.Buffer
+
+	require.NoError(t, NewHTMLFormatter(&buf).Format(result))
+
+	output := buf.String()
+	assert.Contains(t, output, "Grouped Errors")
+	assert.Equal(t, 1, strings.Count(output, `<details class="error-group"`))
+	assert.Contains(t, output, "42 observations failed:")
+	assert.Contains(t, output, "lookup &lt;host&gt; on &lt;addr&gt;: no such host")
+	assert.Contains(t, output, `<a href="#control-svc-41">svc-41</a> (http, observation 1)`)
+}
+
+func TestHTMLFormatter_Language(t *testing.T) {
+	var buf bytes.Buffer
+	formatter := NewHTMLFormatter(&buf)
+	formatter.Language = "fr"
+	require.NoError(t, formatter.Format(createTestResult()))
+
+	output := buf.String()
+	assert.Contains(t, output, `<html lang="fr">`)
+	assert.Contains(t, output, "<h2>Résumé</h2>")
+	assert.Contains(t, output, "RÉUSSI")
+	assert.NotContains(t, output, "<h2>Summary</h2>")
+
+	buf.Reset()
+	require.NoError(t, NewHTMLFormatter(&buf).Format(createTestResult()))
+	assert.Contains(t, buf.String(), `<html lang="en">`, "English by default")
+}
+
+func TestHTMLFormatter_Accessible(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewAccessibleHTMLFormatter(&buf).Format(createOutageResult(3)))
+
+	output := buf.String()
+	assert.Contains(t, output, `<a class="skip-link" href="#controls">Skip to controls</a>`)
+	assert.Contains(t, output, `<nav role="navigation" aria-label="Report sections">`)
+	assert.Contains(t, output, `<main role="main">`)
+	assert.Contains(t, output, `<th scope="col">Total</th>`)
+	assert.Contains(t, output, `<a href="#grouped-errors">Grouped Errors</a>`)
+	assert.Contains(t, output, `<article id="control-svc-0" aria-labelledby="control-svc-0-heading">`)
+	assert.Contains(t, output, `<span aria-hidden="true">⚠</span> ERROR</span> svc-0</h3>`, "status in words, symbol hidden")
+	assert.Contains(t, output, ":focus-visible")
+
+	buf.Reset()
+	formatter := NewAccessibleHTMLFormatter(&buf)
+	formatter.Language = "de"
+	require.NoError(t, formatter.Format(createTestResult()))
+	assert.Contains(t, buf.String(), `<html lang="de">`)
+	assert.Contains(t, buf.String(), "Zu den Kontrollen springen")
+}
+
+func TestHTMLFormatter_EscapesContent(t *testing.T) {
+	result := execution.NewExecutionResult("<script>alert(1)</script>", "1.0.0")
+	result.AddControlResult(execution.ControlResult{
+		ID:      "ctrl-1",
+		Status:  values.StatusFail,
+		Message: `<img src=x onerror="alert(1)">`,
+	})
+	result.Finalize()
+
+	var buf bytes.Buffer
+	require.NoError(t, NewHTMLFormatter(&buf).Format(result))
+
+	output := buf.String()
+	assert.NotContains(t, output, "<script>alert(1)</script>")
+	assert.NotContains(t, output, "<img src=x")
+	assert.Contains(t, output, "&lt;script&gt;")
+}
@@ -0,0 +1,141 @@
+package output
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata/")
+
+// createGoldenResult builds a fully deterministic execution result covering
+// every field of the serialized schema.
+func createGoldenResult() *execution.ExecutionResult {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	raw := "raw plugin output"
+
+	result := execution.NewExecutionResultWithID(
+		values.MustParseExecutionID("0b7e6c1a-4f2d-4e8b-9a3c-5d6e7f809a1b"),
+		"golden-profile", "1.2.3",
+	)
+	result.StartTime = start
+	result.RegletVersion = "0.0.0-test"
+
+	result.AddControlResult(execution.ControlResult{
+		ID:          "ctrl-pass",
+		Name:        "Passing control",
+		Description: "Checks a file exists",
+		Severity:    "high",
+		Tags:        []string{"security"},
+		Status:      values.StatusPass,
+		Message:     "All 1 checks passed",
+		Index:       0,
+		Duration:    150 * time.Millisecond,
+		ObservationResults: []execution.ObservationResult{
+			{
+				Plugin: "file",
+				Config: map[string]interface{}{"path": "/etc/hosts"},
+				Status: values.StatusPass,
+				Evidence: &execution.Evidence{
+					Timestamp: start,
+					Status:    true,
+					Data:      map[string]interface{}{"exists": true, "size": 42},
+					Raw:       &raw,
+				},
+				EvidenceMeta: &execution.EvidenceMeta{
+					Truncated:    true,
+					OriginalSize: 2048,
+					TruncatedAt:  1024,
+					Reason:       "evidence exceeded 1024 bytes limit (greedy strategy)",
+				},
+				Expectations: []execution.ExpectationResult{
+					{Expression: "data.exists == true", Passed: true},
+				},
+				Duration: 100 * time.Millisecond,
+			},
+		},
+	})
+
+	result.AddControlResult(execution.ControlResult{
+		ID:       "ctrl-error",
+		Name:     "Erroring control",
+		Status:   values.StatusError,
+		Message:  "1 check errored",
+		Index:    1,
+		Duration: 10 * time.Millisecond,
+		ObservationResults: []execution.ObservationResult{
+			{
+				Plugin: "http",
+				Config: map[string]interface{}{"url": "https://example.com"},
+				Status: values.StatusError,
+				Error: &execution.PluginError{
+					Code:    "plugin_execution_error",
+					Message: "connection refused",
+				},
+				Duration: 5 * time.Millisecond,
+			},
+		},
+	})
+
+	result.AddControlResult(execution.ControlResult{
+		ID:                 "ctrl-skip",
+		Name:               "Skipped control",
+		Status:             values.StatusSkipped,
+		SkipReason:         "Skipped: dependency 'ctrl-error' has status 'error'",
+		Message:            "Skipped: dependency 'ctrl-error' has status 'error'",
+		Index:              2,
+		ObservationResults: []execution.ObservationResult{},
+	})
+
+	result.Finalize()
+	result.EndTime = start.Add(time.Second)
+	result.Duration = time.Second
+
+	return result
+}
+
+// TestJSONFormatter_Golden pins the serialized result schema.
+// Run with -update to regenerate after an intentional, versioned schema change.
+func TestJSONFormatter_Golden(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, NewJSONFormatter(&buf, true).Format(createGoldenResult()))
+
+	goldenPath := filepath.Join("testdata", "result.golden.json")
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(goldenPath), 0o750))
+		require.NoError(t, os.WriteFile(goldenPath, buf.Bytes(), 0o600))
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	require.NoError(t, err, "golden file missing; run go test -run TestJSONFormatter_Golden -update")
+	assert.JSONEq(t, string(want), buf.String(),
+		"serialized result schema changed; bump execution.CurrentSchemaVersion if intentional")
+}
+
+// TestJSONFormatter_GoldenDecodes verifies the golden document can be read back
+// through the compatibility reader.
+func TestJSONFormatter_GoldenDecodes(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "result.golden.json"))
+	require.NoError(t, err)
+
+	decoded, err := execution.DecodeExecutionResult(data)
+	require.NoError(t, err)
+
+	want := createGoldenResult()
+	assert.Equal(t, execution.CurrentSchemaVersion, decoded.SchemaVersion)
+	assert.Equal(t, want.ExecutionID, decoded.ExecutionID)
+	assert.Equal(t, want.Summary, decoded.Summary)
+	assert.Len(t, decoded.Controls, len(want.Controls))
+}
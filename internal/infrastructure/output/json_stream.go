@@ -0,0 +1,151 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// Ensure interface compliance
+var _ execution.ResultStream = (*JSONStreamWriter)(nil)
+
+// streamHeader holds the run-level fields known before the first control
+// runs, including the results of the profile's setup steps.
+type streamHeader struct {
+	StartTime      time.Time              `json:"start_time"`
+	RegletVersion  string                 `json:"reglet_version,omitempty"`
+	ProfileName    string                 `json:"profile_name"`
+	ProfileVersion string                 `json:"profile_version"`
+	Environment    string                 `json:"environment,omitempty"`
+	Mode           string                 `json:"mode,omitempty"`
+	EvaluatedRun   string                 `json:"evaluated_run,omitempty"`
+	Setup          []execution.StepResult `json:"setup,omitempty"`
+	SchemaVersion  int                    `json:"schema_version"`
+	ExecutionID    values.ExecutionID     `json:"execution_id"`
+}
+
+// streamTrailer holds the run-level fields only known after finalization,
+// including the results of the profile's teardown steps.
+type streamTrailer struct {
+	EndTime     time.Time                    `json:"end_time"`
+	Performance *execution.PerformanceReport `json:"performance,omitempty"`
+	Provenance  *execution.Provenance        `json:"provenance,omitempty"`
+	ErrorGroups []execution.ErrorGroup       `json:"error_groups,omitempty"`
+	Teardown    []execution.StepResult       `json:"teardown,omitempty"`
+	Nodes       []execution.NodeRun          `json:"nodes,omitempty"`
+	Summary     execution.ResultSummary      `json:"summary"`
+	Version     int                          `json:"version"`
+	Duration    time.Duration                `json:"duration_ms"`
+}
+
+func newStreamHeader(result *execution.ExecutionResult) streamHeader {
+	return streamHeader{
+		SchemaVersion:  result.SchemaVersion,
+		ExecutionID:    result.ExecutionID,
+		ProfileName:    result.ProfileName,
+		ProfileVersion: result.ProfileVersion,
+		Environment:    result.Environment,
+		Mode:           result.Mode,
+		EvaluatedRun:   result.EvaluatedRun,
+		Setup:          result.Setup,
+		RegletVersion:  result.RegletVersion,
+		StartTime:      result.StartTime,
+	}
+}
+
+func newStreamTrailer(result *execution.ExecutionResult) streamTrailer {
+	return streamTrailer{
+		EndTime:     result.EndTime,
+		Duration:    result.Duration,
+		Version:     result.Version,
+		Summary:     result.Summary,
+		Performance: result.Performance,
+		Provenance:  result.Provenance,
+		ErrorGroups: result.ErrorGroups,
+		Teardown:    result.Teardown,
+		Nodes:       result.Nodes,
+	}
+}
+
+// JSONStreamWriter writes an execution result as a single JSON document,
+// emitting each control as soon as it completes. The document has the same
+// schema as JSONFormatter output, but controls appear in completion order.
+type JSONStreamWriter struct {
+	writer   io.Writer
+	controls int
+	mu       sync.Mutex
+}
+
+// NewJSONStreamWriter creates a streaming JSON writer.
+func NewJSONStreamWriter(w io.Writer) *JSONStreamWriter {
+	return &JSONStreamWriter{writer: w}
+}
+
+// Begin opens the document and writes the run metadata.
+func (s *JSONStreamWriter) Begin(result *execution.ExecutionResult) error {
+	header, err := json.Marshal(newStreamHeader(result))
+	if err != nil {
+		return fmt.Errorf("failed to marshal result header: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Re-open the header object to append the controls array
+	if _, err := s.writer.Write(header[:len(header)-1]); err != nil {
+		return err
+	}
+	_, err = io.WriteString(s.writer, `,"controls":[`)
+	return err
+}
+
+// WriteControl appends a completed control to the controls array.
+func (s *JSONStreamWriter) WriteControl(control execution.ControlResult) error {
+	data, err := json.Marshal(control)
+	if err != nil {
+		return fmt.Errorf("failed to marshal control %s: %w", control.ID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	separator := "\n"
+	if s.controls > 0 {
+		separator = ",\n"
+	}
+	if _, err := io.WriteString(s.writer, separator); err != nil {
+		return err
+	}
+	if _, err := s.writer.Write(data); err != nil {
+		return err
+	}
+
+	s.controls++
+	return nil
+}
+
+// End closes the controls array and writes the summary.
+func (s *JSONStreamWriter) End(result *execution.ExecutionResult) error {
+	trailer, err := json.Marshal(newStreamTrailer(result))
+	if err != nil {
+		return fmt.Errorf("failed to marshal result summary: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := io.WriteString(s.writer, "\n],"); err != nil {
+		return err
+	}
+	// Drop the trailer's opening brace; it continues the already-open object
+	if _, err := s.writer.Write(trailer[1:]); err != nil {
+		return err
+	}
+	_, err = io.WriteString(s.writer, "\n")
+	return err
+}
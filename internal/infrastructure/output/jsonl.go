@@ -0,0 +1,105 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+// Ensure interface compliance
+var _ execution.ResultStream = (*JSONLFormatter)(nil)
+
+// JSONL record types. Every line carries a "type" field identifying its shape.
+const (
+	JSONLRecordStart   = "execution_start"
+	JSONLRecordControl = "control"
+	JSONLRecordEnd     = "execution_end"
+)
+
+// jsonlStartRecord is the first line of a JSONL result.
+type jsonlStartRecord struct {
+	Type string `json:"type"`
+	streamHeader
+}
+
+// jsonlControlRecord is written once per control.
+type jsonlControlRecord struct {
+	Control execution.ControlResult `json:"control"`
+	Type    string                  `json:"type"`
+}
+
+// jsonlEndRecord is the last line of a JSONL result.
+type jsonlEndRecord struct {
+	Type string `json:"type"`
+	streamTrailer
+}
+
+// JSONLFormatter formats execution results as newline-delimited JSON:
+// an execution_start record, one control record per control, and an
+// execution_end record with the summary. It can be used as a batch formatter
+// or as a ResultStream that writes each control as it completes.
+type JSONLFormatter struct {
+	writer io.Writer
+	mu     sync.Mutex
+}
+
+// NewJSONLFormatter creates a new JSONL formatter.
+func NewJSONLFormatter(w io.Writer) *JSONLFormatter {
+	return &JSONLFormatter{writer: w}
+}
+
+// Format writes a complete execution result as JSONL.
+func (f *JSONLFormatter) Format(result *execution.ExecutionResult) error {
+	if err := f.Begin(result); err != nil {
+		return err
+	}
+	for _, ctrl := range result.Controls {
+		if err := f.WriteControl(ctrl); err != nil {
+			return err
+		}
+	}
+	return f.End(result)
+}
+
+// Begin writes the execution_start record.
+func (f *JSONLFormatter) Begin(result *execution.ExecutionResult) error {
+	return f.writeLine(jsonlStartRecord{
+		Type:         JSONLRecordStart,
+		streamHeader: newStreamHeader(result),
+	})
+}
+
+// WriteControl writes a control record.
+func (f *JSONLFormatter) WriteControl(control execution.ControlResult) error {
+	return f.writeLine(jsonlControlRecord{
+		Type:    JSONLRecordControl,
+		Control: control,
+	})
+}
+
+// End writes the execution_end record.
+func (f *JSONLFormatter) End(result *execution.ExecutionResult) error {
+	return f.writeLine(jsonlEndRecord{
+		Type:          JSONLRecordEnd,
+		streamTrailer: newStreamTrailer(result),
+	})
+}
+
+// writeLine marshals a record and writes it followed by a newline.
+func (f *JSONLFormatter) writeLine(record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSONL record: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
@@ -137,6 +137,15 @@ func (f *JUnitFormatter) Format(result *execution.ExecutionResult) error {
 
 func formatObservations(ctrl execution.ControlResult) string {
 	var out string
+	if ctrl.Rationale != "" {
+		out += fmt.Sprintf("Rationale: %s\n", ctrl.Rationale)
+	}
+	for _, ref := range ctrl.References {
+		out += fmt.Sprintf("Reference: %s\n", ref)
+	}
+	if out != "" {
+		out += "\n"
+	}
 	for _, obs := range ctrl.ObservationResults {
 		if obs.Status != values.StatusPass {
 			out += fmt.Sprintf("Observation (%s): %s\n", obs.Plugin, obs.Status)
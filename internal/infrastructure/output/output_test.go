@@ -6,8 +6,10 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/goccy/go-yaml"
+	"github.com/reglet-dev/reglet/internal/application/ports"
 	"github.com/reglet-dev/reglet/internal/domain/execution"
 	"github.com/reglet-dev/reglet/internal/domain/values"
 	"github.com/reglet-dev/reglet/internal/infrastructure/wasm"
@@ -26,6 +28,7 @@ func createTestResult() *execution.ExecutionResult {
 		Description: "A test control that passes",
 		Severity:    "high",
 		Tags:        []string{"security", "test"},
+		Labels:      map[string]string{"service": "web", "cmdb_id": "CI0042"},
 		Status:      values.StatusPass,
 		Message:     "All 2 checks passed",
 		Duration:    100 * time.Millisecond,
@@ -140,9 +143,10 @@ func TestTableFormatter_Format(t *testing.T) {
 
 	output := buf.String()
 	assert.Contains(t, output, "Profile: test-profile (v1.0.0)")
-	assert.Contains(t, output, "ctrl-1: Test Control 1")
-	assert.Contains(t, output, "ctrl-2: Test Control 2")
-	assert.Contains(t, output, "ctrl-3: Test Control 3")
+	assert.Contains(t, output, "✓ ctrl-1  HIGH      Test Control 1\n")
+	assert.Contains(t, output, "Labels: cmdb_id=CI0042, service=web")
+	assert.Contains(t, output, "✗ ctrl-2  MEDIUM    Test Control 2\n")
+	assert.Contains(t, output, "⚠ ctrl-3  CRITICAL  Test Control 3\n")
 	assert.Contains(t, output, "Summary:")
 	assert.Contains(t, output, "Controls:     3 total")
 	assert.Contains(t, output, "Passed:   1")
@@ -167,6 +171,32 @@ func TestTableFormatter_EmptyResult(t *testing.T) {
 	assert.Contains(t, output, "No controls executed.")
 }
 
+func TestTableFormatter_GroupedErrors(t *testing.T) {
+	result := createOutageResult(42)
+	var buf bytes.Buffer
+	formatter := NewTableFormatter(&buf)
+	formatter.EnableColor = false
+
+	require.NoError(t, formatter.Format(result))
+
+	output := buf.String()
+	assert.Contains(t, output, "Grouped Errors:")
+	assert.Contains(t, output, "42 observations failed: lookup <host> on <addr>: no such host")
+	assert.Contains(t, output, "- svc-0 (http, observation 1)")
+	assert.Contains(t, output, "- svc-9 (http, observation 1)")
+	assert.NotContains(t, output, "- svc-10 (http")
+	assert.Contains(t, output, "... and 32 more")
+}
+
+func TestTableFormatter_NoGroupedErrors(t *testing.T) {
+	var buf bytes.Buffer
+	formatter := NewTableFormatter(&buf)
+	formatter.EnableColor = false
+
+	require.NoError(t, formatter.Format(createTestResult()))
+	assert.NotContains(t, buf.String(), "Grouped Errors:")
+}
+
 func TestJSONFormatter_Format_Indented(t *testing.T) {
 	t.Parallel()
 	result := createTestResult()
@@ -298,6 +328,137 @@ func TestAllFormatters_WithSameData(t *testing.T) {
 	}
 }
 
+// TestAllFormatters_RationaleAndReferences checks that every report format
+// explains why a failed control's requirement exists.
+func TestAllFormatters_RationaleAndReferences(t *testing.T) {
+	t.Parallel()
+
+	const (
+		rationale = "Password logins let attackers brute-force accounts"
+		reference = "https://www.cisecurity.org/benchmark/ubuntu_linux"
+	)
+	result := createTestResult()
+	result.Controls[1].Rationale = rationale
+	result.Controls[1].References = []string{reference, "https://wiki.example.com/ssh?a=1&b=2"}
+
+	factory := NewFormatterFactory()
+	for _, format := range factory.SupportedFormats() {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			formatter, err := factory.Create(format, &buf, ports.FormatterOptions{})
+			require.NoError(t, err)
+			require.NoError(t, formatter.Format(result))
+
+			assert.Contains(t, buf.String(), rationale)
+			assert.Contains(t, buf.String(), reference)
+		})
+	}
+}
+
+func TestTableFormatter_Verbosity(t *testing.T) {
+	t.Parallel()
+
+	format := func(verbosity int) string {
+		result := createTestResult()
+		result.Controls[1].ObservationResults[0].Evidence.Data["content"] = strings.Repeat("x", 200) + strings.Repeat("\nline", 8)
+
+		var buf bytes.Buffer
+		formatter := NewTableFormatter(&buf)
+		formatter.EnableColor = false
+		formatter.Verbosity = verbosity
+		require.NoError(t, formatter.Format(result))
+		return buf.String()
+	}
+
+	quiet := format(ports.VerbosityQuiet)
+	assert.Equal(t, "⚠ ERROR test-profile: 3 controls, 1 passed, 1 failed, 1 errors, 0 skipped (0s)\n", quiet)
+
+	normal := format(ports.VerbosityNormal)
+	assert.Contains(t, normal, "ctrl-1  HIGH      Test Control 1")
+	assert.Contains(t, normal, "1. ✗ Plugin: file (fail)")
+	assert.Contains(t, normal, "Error: [plugin_load_error]")
+	assert.NotContains(t, normal, "1. ✓ Plugin: file (pass)", "passing observations are only listed with -v")
+	assert.NotContains(t, normal, "Evidence:")
+
+	detail := format(ports.VerbosityDetail)
+	assert.Contains(t, detail, "1. ✓ Plugin: file (pass)")
+	assert.Contains(t, detail, "       Duration: 50ms")
+	assert.NotContains(t, detail, "Evidence:")
+
+	evidence := format(ports.VerbosityEvidence)
+	assert.Contains(t, evidence, "Evidence:")
+	assert.Contains(t, evidence, "- path: /etc/missing")
+	assert.Contains(t, evidence, "… 4 more lines")
+	assert.Contains(t, evidence, strings.Repeat("x", 119)+"…")
+	assert.NotContains(t, evidence, strings.Repeat("x", 120))
+}
+
+func TestTableFormatter_Width(t *testing.T) {
+	t.Parallel()
+
+	result := createTestResult()
+	result.Controls[1].Name = strings.Repeat("long name ", 10)
+	result.Controls[1].Message = strings.Repeat("m", 100)
+
+	var buf bytes.Buffer
+	formatter := NewTableFormatter(&buf)
+	formatter.EnableColor = false
+	formatter.Width = 60
+	require.NoError(t, formatter.Format(result))
+
+	output := buf.String()
+	assert.Contains(t, output, strings.Repeat("─", 60)+"\n")
+	assert.NotContains(t, output, strings.Repeat("─", 61))
+	for _, line := range strings.Split(output, "\n") {
+		assert.LessOrEqual(t, utf8.RuneCountInString(line), 60, line)
+	}
+	assert.Contains(t, output, "✗ ctrl-2  MEDIUM    long name long name long name long name…\n")
+	assert.Contains(t, output, "  Message: "+strings.Repeat("m", 48)+"…\n")
+	assert.NotContains(t, output, "\033[", "no color when disabled")
+
+	buf.Reset()
+	formatter.EnableColor = true
+	require.NoError(t, formatter.Format(result))
+	assert.Contains(t, buf.String(), colorBold+colorPurple+"CRITICAL"+colorReset, "severity colored")
+}
+
+func TestTableFormatter_SeverityRule(t *testing.T) {
+	t.Parallel()
+
+	result := createTestResult()
+	result.Controls[1].Severity = "critical"
+	result.Controls[1].DeclaredSeverity = "medium"
+	result.Controls[1].SeverityRule = "tls-expiry"
+
+	var buf bytes.Buffer
+	formatter := NewTableFormatter(&buf)
+	formatter.EnableColor = false
+	require.NoError(t, formatter.Format(result))
+
+	assert.Contains(t, buf.String(), "✗ ctrl-2  CRITICAL  Test Control 2\n")
+	assert.Contains(t, buf.String(), "  Severity rule: tls-expiry (declared medium)\n")
+}
+
+func TestTableFormatter_Language(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	formatter := NewTableFormatter(&buf)
+	formatter.EnableColor = false
+	formatter.Language = "de"
+	require.NoError(t, formatter.Format(createTestResult()))
+
+	output := buf.String()
+	assert.Contains(t, output, "Zusammenfassung:")
+	assert.Contains(t, output, "Kontrollen:    3 gesamt\n")
+	assert.Contains(t, output, "  ✗ Fehlgeschlagen:  1\n")
+	assert.Contains(t, output, "  Status: BESTANDEN\n")
+	assert.Contains(t, output, "✗ ctrl-2  MITTEL    ")
+	assert.NotContains(t, output, "Summary:")
+}
+
 func TestTableFormatter_StatusSymbols(t *testing.T) {
 	t.Parallel()
 	var buf bytes.Buffer
@@ -370,3 +531,42 @@ func TestYAMLFormatter_PreservesTypes(t *testing.T) {
 	assert.Equal(t, values.StatusFail, decoded.Controls[1].Status)
 	assert.Equal(t, values.StatusError, decoded.Controls[2].Status)
 }
+
+func TestTableFormatter_Performance(t *testing.T) {
+	result := createTestResult()
+	result.Controls[0].ObservationResults[0].Timing = &execution.ObservationTiming{
+		Instantiation: 12 * time.Millisecond,
+		Execution:     3 * time.Millisecond,
+	}
+	result.Performance = &execution.PerformanceReport{
+		Phases: []execution.PhaseTiming{{Phase: execution.PhaseProfileLoad, Duration: 2 * time.Millisecond}},
+		Plugins: []execution.PluginTiming{{
+			Plugin:            "file",
+			Observations:      3,
+			ObservationTiming: execution.ObservationTiming{Instantiation: 40 * time.Millisecond, HostIO: time.Millisecond},
+		}},
+		DNS:  &execution.DNSStats{Lookups: 12, CacheHits: 9},
+		HTTP: &execution.HTTPStats{Connections: 5, ReusedConnections: 4, CachedResponses: 7},
+		Traffic: &execution.TrafficReport{
+			Plugins:      []execution.TrafficStats{{Name: "http", Requests: 5, BytesSent: 900, BytesReceived: 3 << 20}},
+			Destinations: []execution.TrafficStats{{Name: "example.com:443", Requests: 5, BytesSent: 900, BytesReceived: 3 << 20}},
+		},
+	}
+
+	var buf bytes.Buffer
+	formatter := NewTableFormatter(&buf)
+	formatter.EnableColor = false
+	formatter.Verbosity = ports.VerbosityDetail
+	require.NoError(t, formatter.Format(result))
+
+	output := buf.String()
+	assert.Contains(t, output, "Timing: instantiation 12ms, execution 3ms, host I/O 0s")
+	assert.Contains(t, output, "Performance:")
+	assert.Regexp(t, `profile_load\s+2ms`, output)
+	assert.Regexp(t, `file\s+3\s+40ms\s+0s\s+1ms`, output)
+	assert.Contains(t, output, "DNS lookups: 12 (9 from cache)")
+	assert.Contains(t, output, "HTTP connections: 5 (4 reused)")
+	assert.Contains(t, output, "HTTP responses from cache: 7")
+	assert.Regexp(t, `http\s+5\s+900B\s+3\.0MiB`, output)
+	assert.Regexp(t, `example\.com:443\s+5\s+900B\s+3\.0MiB`, output)
+}
@@ -6,16 +6,20 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
 	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
 	"github.com/reglet-dev/reglet/internal/domain/execution"
 	"github.com/reglet-dev/reglet/internal/domain/values"
 )
 
 type sarifMapper struct {
-	result      *execution.ExecutionResult
-	artifacts   map[string]*sarif.Artifact
-	profilePath string
-	cwd         string
+	result       *execution.ExecutionResult
+	artifacts    map[string]*sarif.Artifact
+	controlLines map[string]int // line of each control's id in the profile file
+	profilePath  string
+	cwd          string
 }
 
 func newSARIFMapper(result *execution.ExecutionResult, profilePath string) *sarifMapper {
@@ -58,26 +62,51 @@ func (m *sarifMapper) addRules(run *sarif.Run) {
 			Text: &desc,
 		})
 
+		// Help: why the requirement exists, linking to its documentation
+		if help := ruleHelp(ctrl); help != "" {
+			rule.WithHelp(&sarif.MultiformatMessageString{Text: &help})
+		}
+		if len(ctrl.References) > 0 {
+			rule.WithHelpURI(ctrl.References[0])
+		}
+
 		// Default configuration (severity → level)
 		level := m.mapSeverityToLevel(ctrl.Severity)
 		rule.WithDefaultConfiguration(&sarif.ReportingConfiguration{
 			Level: level,
 		})
 
-		// Properties (tags, severity)
+		// Properties (tags, labels, severity)
 		props := sarif.NewPropertyBag()
 		if len(ctrl.Tags) > 0 {
 			props.WithTags(ctrl.Tags)
 		}
+		if len(ctrl.Labels) > 0 {
+			props.Add("labels", ctrl.Labels)
+		}
 		if ctrl.Severity != "" {
 			props.Add("severity", ctrl.Severity)
 		}
+		if len(ctrl.References) > 0 {
+			props.Add("references", ctrl.References)
+		}
 		rule.WithProperties(props)
 
 		run.Tool.Driver.AddRule(rule)
 	}
 }
 
+// ruleHelp returns the help text of a control's rule: its rationale followed
+// by its references, one per line.
+func ruleHelp(ctrl execution.ControlResult) string {
+	lines := make([]string, 0, 1+len(ctrl.References))
+	if ctrl.Rationale != "" {
+		lines = append(lines, ctrl.Rationale)
+	}
+	lines = append(lines, ctrl.References...)
+	return strings.Join(lines, "\n")
+}
+
 // addResults converts control results to SARIF results.
 func (m *sarifMapper) addResults(run *sarif.Run) {
 	for _, ctrl := range m.result.Controls {
@@ -101,8 +130,14 @@ func (m *sarifMapper) mapControlResult(ctrl execution.ControlResult) *sarif.Resu
 	}
 	result.Message = sarif.NewTextMessage(msg)
 
-	// Extract location from evidence
-	if loc := m.extractLocation(ctrl); loc != nil {
+	// Extract location from evidence, else point at the control in the
+	// profile: code scanning drops results without a location
+	loc := m.extractLocation(ctrl)
+	if loc == nil {
+		loc = m.profileLocation(ctrl)
+	}
+	if loc != nil {
+		loc.AddLogicalLocation(m.controlLogicalLocation(ctrl))
 		result.Locations = []*sarif.Location{loc}
 	}
 
@@ -114,6 +149,9 @@ func (m *sarifMapper) mapControlResult(ctrl execution.ControlResult) *sarif.Resu
 	if len(ctrl.Tags) > 0 {
 		props.WithTags(ctrl.Tags)
 	}
+	if len(ctrl.Labels) > 0 {
+		props.Add("labels", ctrl.Labels)
+	}
 	if ctrl.Severity != "" {
 		props.Add("severity", ctrl.Severity)
 	}
@@ -142,7 +180,7 @@ func (m *sarifMapper) mapStatusToLevel(status values.Status, severity string) st
 		}
 	case values.StatusError:
 		return "error"
-	case values.StatusSkipped:
+	case values.StatusSkipped, values.StatusCollected:
 		return "none"
 	default:
 		return "warning"
@@ -158,6 +196,8 @@ func (m *sarifMapper) mapStatusToKind(status values.Status) string {
 		return "fail"
 	case values.StatusSkipped:
 		return "notApplicable"
+	case values.StatusCollected:
+		return "informational"
 	default:
 		return "fail"
 	}
@@ -235,6 +275,75 @@ func (m *sarifMapper) createLocation(path string, data map[string]interface{}) *
 	return sarif.NewLocation().WithPhysicalLocation(pLoc)
 }
 
+// profileLocation returns the location of a control's definition in the
+// profile file, or nil when the profile is not a readable file.
+func (m *sarifMapper) profileLocation(ctrl execution.ControlResult) *sarif.Location {
+	if m.profilePath == "" {
+		return nil
+	}
+	if info, err := os.Stat(m.profilePath); err != nil || info.IsDir() {
+		return nil
+	}
+	if m.controlLines == nil {
+		m.controlLines = profileControlLines(m.profilePath)
+	}
+
+	m.registerArtifact(m.profilePath, nil)
+	pLoc := sarif.NewPhysicalLocation().
+		WithArtifactLocation(sarif.NewArtifactLocation().WithURI(m.normalizeURI(m.profilePath)))
+	// Controls from included profiles have no line in this file
+	if line := m.controlLines[ctrl.ID]; line > 0 {
+		pLoc.WithRegion(sarif.NewRegion().WithStartLine(line))
+	}
+	return sarif.NewLocation().WithPhysicalLocation(pLoc)
+}
+
+// controlLogicalLocation names the control a result is about.
+func (m *sarifMapper) controlLogicalLocation(ctrl execution.ControlResult) *sarif.LogicalLocation {
+	return sarif.NewLogicalLocation().
+		WithName(ctrl.ID).
+		WithFullyQualifiedName(m.result.ProfileName + "/" + ctrl.ID).
+		WithKind("object")
+}
+
+// profileControlLines returns the line of the id of each control under
+// controls.items of a profile file. It is empty when the file does not parse.
+func profileControlLines(path string) map[string]int {
+	lines := make(map[string]int)
+	file, err := parser.ParseFile(path, 0)
+	if err != nil {
+		return lines
+	}
+	itemsPath, err := yaml.PathString("$.controls.items")
+	if err != nil {
+		return lines
+	}
+	node, err := itemsPath.FilterFile(file)
+	if err != nil {
+		return lines
+	}
+	items, ok := node.(*ast.SequenceNode)
+	if !ok {
+		return lines
+	}
+
+	for _, item := range items.Values {
+		var fields []*ast.MappingValueNode
+		switch n := item.(type) {
+		case *ast.MappingNode:
+			fields = n.Values
+		case *ast.MappingValueNode:
+			fields = []*ast.MappingValueNode{n}
+		}
+		for _, field := range fields {
+			if field.Key.GetToken().Value == "id" {
+				lines[field.Value.GetToken().Value] = field.Key.GetToken().Position.Line
+			}
+		}
+	}
+	return lines
+}
+
 // normalizeURI converts a file path to a SARIF-compliant URI.
 func (m *sarifMapper) normalizeURI(path string) string {
 	abs, err := filepath.Abs(path)
@@ -270,7 +379,6 @@ func (m *sarifMapper) getInt(data map[string]interface{}, keys ...string) int {
 	return 0
 }
 
-// registerArtifact adds a file to the artifacts map (deduplicated).
 // registerArtifact adds a file to the artifacts map (deduplicated).
 func (m *sarifMapper) registerArtifact(path string, data map[string]interface{}) {
 	uri := m.normalizeURI(path)
@@ -355,6 +463,9 @@ func (m *sarifMapper) addInvocation(run *sarif.Run) {
 	props := sarif.NewPropertyBag()
 	props.Add("profileName", m.result.ProfileName)
 	props.Add("profileVersion", m.result.ProfileVersion)
+	if m.result.Environment != "" {
+		props.Add("environment", m.result.Environment)
+	}
 	props.Add("executionId", m.result.ExecutionID)
 	invocation.WithProperties(props)
 
@@ -379,6 +490,8 @@ func (m *sarifMapper) generateDefaultMessage(ctrl execution.ControlResult) strin
 		return fmt.Sprintf("Control %s encountered an error", ctrl.ID)
 	case values.StatusSkipped:
 		return fmt.Sprintf("Control %s was skipped", ctrl.ID)
+	case values.StatusCollected:
+		return fmt.Sprintf("Evidence collected for control %s", ctrl.ID)
 	default:
 		return fmt.Sprintf("Control %s completed with status %s", ctrl.ID, ctrl.Status)
 	}
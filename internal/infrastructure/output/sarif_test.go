@@ -3,6 +3,7 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -222,6 +223,49 @@ func TestSARIFMapper_ExtractLocation_ShellCommand_Invalid(t *testing.T) {
 	assert.Empty(t, res.Locations, "Should not extract location for inline command")
 }
 
+func TestSARIFMapper_ProfileLocation(t *testing.T) {
+	t.Parallel()
+
+	profilePath := filepath.Join(t.TempDir(), "web.yaml")
+	profile := `profile:
+  name: web
+controls:
+  items:
+    - id: tls
+      name: TLS
+      observations:
+        - plugin: http
+    - name: Headers
+      id: "headers"
+`
+	require.NoError(t, os.WriteFile(profilePath, []byte(profile), 0o600))
+
+	result := execution.NewExecutionResult("web", "1.0.0")
+	result.AddControlResult(execution.ControlResult{ID: "tls", Status: values.StatusFail})
+	result.AddControlResult(execution.ControlResult{ID: "headers", Status: values.StatusFail})
+	result.AddControlResult(execution.ControlResult{ID: "included", Status: values.StatusFail})
+	result.Finalize()
+
+	var buf bytes.Buffer
+	require.NoError(t, NewSARIFFormatter(&buf, profilePath).Format(result))
+	report, err := sarif.FromBytes(buf.Bytes())
+	require.NoError(t, err)
+
+	lines := map[string]int{"tls": 5, "headers": 10, "included": 0}
+	for _, res := range report.Runs[0].Results {
+		require.Len(t, res.Locations, 1, "results without evidence paths point at the profile")
+		loc := res.Locations[0]
+		assert.Equal(t, "file://"+filepath.ToSlash(profilePath), *loc.PhysicalLocation.ArtifactLocation.URI)
+		if line := lines[*res.RuleID]; line > 0 {
+			assert.Equal(t, line, *loc.PhysicalLocation.Region.StartLine, *res.RuleID)
+		} else {
+			assert.Nil(t, loc.PhysicalLocation.Region, "controls of included profiles have no line")
+		}
+		require.Len(t, loc.LogicalLocations, 1)
+		assert.Equal(t, "web/"+*res.RuleID, *loc.LogicalLocations[0].FullyQualifiedName)
+	}
+}
+
 func TestSARIFMapper_ArtifactRegistration_Deduplication(t *testing.T) {
 	t.Parallel()
 	result := execution.NewExecutionResult("test", "1.0.0")
@@ -319,6 +363,7 @@ func TestSARIFMapper_PropertiesPreservation(t *testing.T) {
 		Status:     values.StatusPass,
 		Severity:   "critical",
 		Tags:       []string{"tag1", "tag2"},
+		Labels:     map[string]string{"service": "billing"},
 		SkipReason: "not skipped",
 	}
 	result.AddControlResult(ctrl)
@@ -330,6 +375,8 @@ func TestSARIFMapper_PropertiesPreservation(t *testing.T) {
 	props := res.Properties.Properties
 	assert.Equal(t, "critical", props["severity"])
 	assert.Equal(t, "not skipped", props["skipReason"])
+	assert.Equal(t, map[string]interface{}{"service": "billing"}, props["labels"])
+	assert.Equal(t, map[string]interface{}{"service": "billing"}, report.Runs[0].Tool.Driver.Rules[0].Properties.Properties["labels"])
 
 	// Tags are in res.Properties.Tags (slice)
 	assert.Contains(t, res.Properties.Tags, "tag1")
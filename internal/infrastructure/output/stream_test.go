@@ -0,0 +1,156 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamResult drives a ResultStream the same way the engine does.
+func streamResult(t *testing.T, stream execution.ResultStream, result *execution.ExecutionResult) {
+	t.Helper()
+	require.NoError(t, stream.Begin(result))
+	for _, ctrl := range result.Controls {
+		require.NoError(t, stream.WriteControl(ctrl))
+	}
+	require.NoError(t, stream.End(result))
+}
+
+func TestJSONStreamWriter_MatchesBatchSchema(t *testing.T) {
+	t.Parallel()
+
+	result := createGoldenResult()
+
+	var streamed bytes.Buffer
+	streamResult(t, NewJSONStreamWriter(&streamed), result)
+
+	var batch bytes.Buffer
+	require.NoError(t, NewJSONFormatter(&batch, false).Format(result))
+
+	assert.JSONEq(t, batch.String(), streamed.String())
+
+	decoded, err := execution.DecodeExecutionResult(streamed.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, result.Summary, decoded.Summary)
+}
+
+func TestJSONStreamWriter_NoControls(t *testing.T) {
+	t.Parallel()
+
+	result := execution.NewExecutionResult("empty", "1.0.0")
+	result.Finalize()
+
+	var buf bytes.Buffer
+	streamResult(t, NewJSONStreamWriter(&buf), result)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, []interface{}{}, doc["controls"])
+}
+
+func TestJSONLFormatter_Records(t *testing.T) {
+	t.Parallel()
+
+	result := createGoldenResult()
+
+	var buf bytes.Buffer
+	require.NoError(t, NewJSONLFormatter(&buf).Format(result))
+
+	var types []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		types = append(types, record["type"].(string))
+
+		switch record["type"] {
+		case JSONLRecordStart:
+			assert.EqualValues(t, execution.CurrentSchemaVersion, record["schema_version"])
+			assert.Equal(t, result.ExecutionID.String(), record["execution_id"])
+		case JSONLRecordControl:
+			assert.Contains(t, record, "control")
+		case JSONLRecordEnd:
+			assert.Contains(t, record, "summary")
+		}
+	}
+	require.NoError(t, scanner.Err())
+
+	assert.Equal(t, []string{
+		JSONLRecordStart,
+		JSONLRecordControl, JSONLRecordControl, JSONLRecordControl,
+		JSONLRecordEnd,
+	}, types)
+}
+
+func TestFormatterFactory_CreateStream(t *testing.T) {
+	t.Parallel()
+
+	factory := NewFormatterFactory()
+	buf := &bytes.Buffer{}
+
+	stream, err := factory.CreateStream("json", buf, ports.FormatterOptions{})
+	require.NoError(t, err)
+	assert.IsType(t, &JSONStreamWriter{}, stream)
+
+	stream, err = factory.CreateStream("jsonl", buf, ports.FormatterOptions{})
+	require.NoError(t, err)
+	assert.IsType(t, &JSONLFormatter{}, stream)
+
+	_, err = factory.CreateStream("table", buf, ports.FormatterOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support streaming")
+}
+
+func TestJSONStreamWriter_Performance(t *testing.T) {
+	t.Parallel()
+
+	result := createGoldenResult()
+	result.Performance = &execution.PerformanceReport{
+		Phases:  []execution.PhaseTiming{{Phase: execution.PhaseExecution, Duration: 5}},
+		Plugins: []execution.PluginTiming{},
+	}
+
+	var streamed bytes.Buffer
+	streamResult(t, NewJSONStreamWriter(&streamed), result)
+
+	var batch bytes.Buffer
+	require.NoError(t, NewJSONFormatter(&batch, false).Format(result))
+
+	assert.JSONEq(t, batch.String(), streamed.String())
+	assert.Contains(t, streamed.String(), `"performance":{"phases":[{"phase":"execution","duration_ns":5}]`)
+}
+
+func TestStreams_RunLevelFields(t *testing.T) {
+	t.Parallel()
+
+	result := createGoldenResult()
+	result.Mode = execution.ModeEvaluate
+	result.EvaluatedRun = "exec-collected"
+	result.Setup = []execution.StepResult{{ID: "seed", ObservationResult: execution.ObservationResult{Plugin: "command", Status: "pass"}}}
+	result.Teardown = []execution.StepResult{{ID: "cleanup", ObservationResult: execution.ObservationResult{Plugin: "command", Status: "pass"}}}
+	result.Nodes = []execution.NodeRun{{Node: "web-1", Controls: []string{"ctrl-1"}}}
+
+	var streamed bytes.Buffer
+	streamResult(t, NewJSONStreamWriter(&streamed), result)
+	var batch bytes.Buffer
+	require.NoError(t, NewJSONFormatter(&batch, false).Format(result))
+	assert.JSONEq(t, batch.String(), streamed.String())
+
+	var jsonl bytes.Buffer
+	require.NoError(t, NewJSONLFormatter(&jsonl).Format(result))
+	lines := bytes.Split(bytes.TrimSpace(jsonl.Bytes()), []byte("\n"))
+	var start, end map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &start))
+	require.NoError(t, json.Unmarshal(lines[len(lines)-1], &end))
+	assert.Contains(t, start, "setup")
+	assert.Equal(t, execution.ModeEvaluate, start["mode"])
+	assert.Equal(t, "exec-collected", start["evaluated_run"])
+	assert.Contains(t, end, "teardown")
+	assert.Contains(t, end, "nodes")
+}
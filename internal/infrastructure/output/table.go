@@ -5,10 +5,14 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"text/tabwriter"
 	"time"
+	"unicode/utf8"
 
+	"github.com/reglet-dev/reglet/internal/application/ports"
 	"github.com/reglet-dev/reglet/internal/domain/execution"
 	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/i18n"
 )
 
 const (
@@ -19,13 +23,34 @@ const (
 	colorBlue   = "\033[34m"
 	colorGray   = "\033[90m"
 	colorCyan   = "\033[36m"
+	colorPurple = "\033[35m"
 	colorBold   = "\033[1m"
 )
 
+// Evidence snippets shown at ports.VerbosityEvidence are clipped to keep the
+// table readable; the full evidence is in the json and yaml formats.
+const (
+	maxSnippetLines = 5
+	maxSnippetWidth = 120
+)
+
+// ruleWidth is the width of the section rules, unless the terminal is narrower.
+const ruleWidth = 80
+
+// minFitWidth is the least room fit leaves for text, however deep the indent.
+const minFitWidth = 20
+
 // TableFormatter formats execution results as a human-readable table.
 type TableFormatter struct {
 	writer      io.Writer
+	Verbosity   int    // One of the ports.Verbosity tiers
+	Width       int    // Terminal width lines are truncated to; 0 = no limit
+	Language    string // Language of the report strings (default: English)
 	EnableColor bool
+
+	msg           *i18n.Catalog
+	idWidth       int // Widest control ID, for column alignment
+	severityWidth int // Widest control severity, 0 when no control has one
 }
 
 // NewTableFormatter creates a new table formatter.
@@ -44,36 +69,130 @@ func (f *TableFormatter) colorize(text, code string) string {
 	return code + text + colorReset
 }
 
+// rule returns a section separator line.
+func (f *TableFormatter) rule() string {
+	width := ruleWidth
+	if f.Width > 0 && f.Width < width {
+		width = f.Width
+	}
+	return f.colorize(strings.Repeat("─", width), colorGray)
+}
+
+// fit truncates text to the room left on a line after indent columns,
+// marking the cut with "…". Text is never truncated without a Width.
+func (f *TableFormatter) fit(text string, indent int) string {
+	if f.Width <= 0 {
+		return text
+	}
+	room := max(f.Width-indent, minFitWidth)
+	if utf8.RuneCountInString(text) <= room {
+		return text
+	}
+	return string([]rune(text)[:room-1]) + "…"
+}
+
+// field writes "label: value" indented by indent columns, fitting the value
+// to the room left after the label.
+//
+//nolint:errcheck // Best-effort terminal output
+func (f *TableFormatter) field(indent int, label, color, value string) {
+	styled := label
+	if color != "" {
+		styled = f.colorize(label, color)
+	}
+	fmt.Fprintf(f.writer, "%s%s: %s\n", strings.Repeat(" ", indent), styled, f.fit(value, indent+utf8.RuneCountInString(label)+2))
+}
+
+// statusLabel returns the translated name of a status, in capitals.
+func (f *TableFormatter) statusLabel(status values.Status) string {
+	if key := "status." + string(status); f.msg.Has(key) {
+		return f.msg.T(key)
+	}
+	return strings.ToUpper(string(status))
+}
+
+// severityLabel returns the translated name of a severity, in capitals.
+func (f *TableFormatter) severityLabel(severity string) string {
+	if key := "severity." + strings.ToLower(severity); f.msg.Has(key) {
+		return f.msg.T(key)
+	}
+	return strings.ToUpper(severity)
+}
+
+// severityRuleText names the severity rule that set the severity of ctrl,
+// with the severity the profile declared.
+func (f *TableFormatter) severityRuleText(ctrl *execution.ControlResult) string {
+	if ctrl.DeclaredSeverity == "" {
+		return ctrl.SeverityRule
+	}
+	return fmt.Sprintf("%s (%s)", ctrl.SeverityRule, f.msg.T("declared_severity", strings.ToLower(f.severityLabel(ctrl.DeclaredSeverity))))
+}
+
+// pad left-aligns text in a column of width characters.
+func pad(text string, width int) string {
+	return text + strings.Repeat(" ", max(width-utf8.RuneCountInString(text), 0))
+}
+
 // Format writes the execution result as a table.
 //
 //nolint:errcheck // Table formatting errors are non-critical (best-effort terminal output)
 func (f *TableFormatter) Format(result *execution.ExecutionResult) error {
+	f.msg = i18n.For(f.Language)
+	if f.Verbosity <= ports.VerbosityQuiet {
+		f.formatSummaryLine(result)
+		return nil
+	}
+
 	// Print header
-	fmt.Fprintln(f.writer, f.colorize(strings.Repeat("─", 80), colorGray))
-	fmt.Fprintf(f.writer, "Profile: %s (v%s)\n", f.colorize(result.ProfileName, colorBold), result.ProfileVersion)
-	fmt.Fprintf(f.writer, "Executed: %s\n", result.StartTime.Format(time.RFC3339))
-	fmt.Fprintf(f.writer, "Duration: %s\n", result.Duration.Round(time.Millisecond))
+	fmt.Fprintln(f.writer, f.rule())
+	fmt.Fprintf(f.writer, "%s: %s (v%s)\n", f.msg.T("profile"), f.colorize(result.ProfileName, colorBold), result.ProfileVersion)
+	if result.Environment != "" {
+		fmt.Fprintf(f.writer, "%s: %s\n", f.msg.T("environment"), f.colorize(result.Environment, colorBold))
+	}
+	switch result.Mode {
+	case execution.ModeCollect:
+		fmt.Fprintln(f.writer, f.msg.T("mode.collect"))
+	case execution.ModeEvaluate:
+		fmt.Fprintln(f.writer, f.msg.T("mode.evaluate", result.EvaluatedRun))
+	}
+	fmt.Fprintf(f.writer, "%s: %s\n", f.msg.T("executed"), result.StartTime.Format(time.RFC3339))
+	fmt.Fprintf(f.writer, "%s: %s\n", f.msg.T("duration"), result.Duration.Round(time.Millisecond))
 	fmt.Fprintln(f.writer)
 
 	// Print controls table
 	if len(result.Controls) == 0 {
-		fmt.Fprintln(f.writer, "No controls executed.")
+		fmt.Fprintln(f.writer, f.msg.T("no_controls"))
 		return nil
 	}
 
-	fmt.Fprintln(f.writer, f.colorize("Controls:", colorBold))
-	fmt.Fprintln(f.writer, f.colorize(strings.Repeat("─", 80), colorGray))
+	fmt.Fprintln(f.writer, f.colorize(f.msg.T("controls")+":", colorBold))
+	fmt.Fprintln(f.writer, f.rule())
 
+	f.idWidth, f.severityWidth = 0, 0
+	for _, ctrl := range result.Controls {
+		f.idWidth = max(f.idWidth, utf8.RuneCountInString(ctrl.ID))
+		if ctrl.Severity != "" {
+			f.severityWidth = max(f.severityWidth, utf8.RuneCountInString(f.severityLabel(ctrl.Severity)))
+		}
+	}
 	for _, ctrl := range result.Controls {
 		f.formatControl(ctrl)
 	}
 
-	fmt.Fprintln(f.writer, f.colorize(strings.Repeat("─", 80), colorGray))
+	fmt.Fprintln(f.writer, f.rule())
 	fmt.Fprintln(f.writer)
 
+	if len(result.ErrorGroups) > 0 {
+		f.formatErrorGroups(result.ErrorGroups)
+	}
+
 	// Print summary
 	f.formatSummary(result.Summary)
 
+	if result.Performance != nil {
+		f.formatPerformance(result.Performance)
+	}
+
 	return nil
 }
 
@@ -84,46 +203,78 @@ func (f *TableFormatter) formatControl(ctrl execution.ControlResult) {
 	// Status symbol and color
 	statusSymbol, statusColor := f.getStatusInfo(ctrl.Status)
 	coloredSymbol := f.colorize(statusSymbol, statusColor)
-	coloredID := f.colorize(ctrl.ID, statusColor)
 
-	// Control header
-	fmt.Fprintf(f.writer, "%s %s: %s\n", coloredSymbol, coloredID, ctrl.Name)
+	// Control header: ID and severity columns aligned across controls
+	header := f.colorize(pad(ctrl.ID, f.idWidth), statusColor) + "  "
+	indent := 2 + f.idWidth + 2
+	if f.severityWidth > 0 {
+		severity := ""
+		if ctrl.Severity != "" {
+			severity = f.severityLabel(ctrl.Severity)
+		}
+		header += f.colorize(pad(severity, f.severityWidth), severityColor(ctrl.Severity)) + "  "
+		indent += f.severityWidth + 2
+	}
+	fmt.Fprintf(f.writer, "%s %s%s\n", coloredSymbol, header, f.fit(ctrl.Name, indent))
 
 	// Description
 	if ctrl.Description != "" {
-		fmt.Fprintf(f.writer, "  Description: %s\n", ctrl.Description)
+		f.field(2, f.msg.T("description"), "", ctrl.Description)
 	}
 
-	// Severity
-	if ctrl.Severity != "" {
-		fmt.Fprintf(f.writer, "  Severity: %s\n", ctrl.Severity)
+	// Severity assigned by a severity rule rather than the profile
+	if ctrl.SeverityRule != "" {
+		f.field(2, f.msg.T("severity_rule"), "", f.severityRuleText(&ctrl))
+	}
+
+	// Rationale and references explain why the requirement exists. URLs
+	// are never truncated: a cut link is useless.
+	if ctrl.Rationale != "" {
+		f.field(2, f.msg.T("rationale"), "", ctrl.Rationale)
+	}
+	if len(ctrl.References) > 0 {
+		fmt.Fprintf(f.writer, "  %s:\n", f.msg.T("references"))
+		for _, ref := range ctrl.References {
+			fmt.Fprintf(f.writer, "    - %s\n", ref)
+		}
 	}
 
 	// Tags
 	if len(ctrl.Tags) > 0 {
-		fmt.Fprintf(f.writer, "  Tags: %s\n", strings.Join(ctrl.Tags, ", "))
+		f.field(2, f.msg.T("tags"), "", strings.Join(ctrl.Tags, ", "))
+	}
+
+	// Labels
+	if len(ctrl.Labels) > 0 {
+		f.field(2, f.msg.T("labels"), "", strings.Join(ctrl.LabelPairs(), ", "))
 	}
 
 	// Status and message
-	statusText := f.colorize(strings.ToUpper(string(ctrl.Status)), statusColor)
-	fmt.Fprintf(f.writer, "  Status: %s\n", statusText)
+	statusText := f.colorize(f.statusLabel(ctrl.Status), statusColor)
+	fmt.Fprintf(f.writer, "  %s: %s\n", f.msg.T("status"), statusText)
 	if ctrl.Message != "" {
-		fmt.Fprintf(f.writer, "  Message: %s\n", ctrl.Message)
+		f.field(2, f.msg.T("message"), "", ctrl.Message)
 	}
 
 	// Explicit skip reason if different from message or for clarity
 	if ctrl.SkipReason != "" && ctrl.SkipReason != ctrl.Message {
-		fmt.Fprintf(f.writer, "  Skip Reason: %s\n", ctrl.SkipReason)
+		f.field(2, f.msg.T("skip_reason"), "", ctrl.SkipReason)
 	}
 
 	// Duration
-	fmt.Fprintf(f.writer, "  Duration: %s\n", ctrl.Duration.Round(time.Millisecond))
+	fmt.Fprintf(f.writer, "  %s: %s\n", f.msg.T("duration"), ctrl.Duration.Round(time.Millisecond))
 
-	// Observations
-	if len(ctrl.ObservationResults) > 0 {
-		fmt.Fprintln(f.writer, "  Observations:")
-		for i, obs := range ctrl.ObservationResults {
-			f.formatObservation(obs, i+1)
+	// Observations: by default only those that did not pass
+	var listed []int
+	for i, obs := range ctrl.ObservationResults {
+		if f.Verbosity >= ports.VerbosityDetail || (obs.Status != values.StatusPass && obs.Status != values.StatusCollected) {
+			listed = append(listed, i)
+		}
+	}
+	if len(listed) > 0 {
+		fmt.Fprintf(f.writer, "  %s:\n", f.msg.T("observations"))
+		for _, i := range listed {
+			f.formatObservation(ctrl.ObservationResults[i], i+1)
 		}
 	}
 
@@ -138,13 +289,23 @@ func (f *TableFormatter) formatObservation(obs execution.ObservationResult, inde
 	coloredSymbol := f.colorize(statusSymbol, statusColor)
 	pluginName := f.colorize(obs.Plugin, colorCyan)
 
-	fmt.Fprintf(f.writer, "    %d. %s Plugin: %s (%s)\n", index, coloredSymbol, pluginName, obs.Status)
+	fmt.Fprintf(f.writer, "    %d. %s %s: %s (%s)\n", index, coloredSymbol, f.msg.T("plugin"), pluginName, strings.ToLower(f.statusLabel(obs.Status)))
 
 	f.formatObsError(obs)
 	f.formatFailedExpectations(obs)
-	f.formatEvidence(obs)
+	if f.Verbosity < ports.VerbosityDetail {
+		return
+	}
+	if f.Verbosity >= ports.VerbosityEvidence {
+		f.formatEvidence(obs)
+	}
+	f.formatPII(obs)
 
-	fmt.Fprintf(f.writer, "       Duration: %s\n", obs.Duration.Round(time.Millisecond))
+	fmt.Fprintf(f.writer, "       %s: %s\n", f.msg.T("duration"), obs.Duration.Round(time.Millisecond))
+	if obs.Timing != nil {
+		fmt.Fprintf(f.writer, "       %s\n", f.msg.T("timing",
+			roundTiming(obs.Timing.Instantiation), roundTiming(obs.Timing.Execution), roundTiming(obs.Timing.HostIO)))
+	}
 }
 
 // formatObsError formats the error section of an observation.
@@ -155,7 +316,11 @@ func (f *TableFormatter) formatObsError(obs execution.ObservationResult) {
 		return
 	}
 	errMsg := fmt.Sprintf("[%s] %s", obs.Error.Code, obs.Error.Message)
-	fmt.Fprintf(f.writer, "       %s: %s\n", f.colorize("Error", colorRed), errMsg)
+	if obs.Status == values.StatusNotRun {
+		f.field(7, f.msg.T("reason"), colorGray, errMsg)
+		return
+	}
+	f.field(7, f.msg.T("error"), colorRed, errMsg)
 }
 
 // formatFailedExpectations formats the failed expectations section.
@@ -177,11 +342,11 @@ func (f *TableFormatter) formatFailedExpectations(obs execution.ObservationResul
 		return
 	}
 
-	fmt.Fprintf(f.writer, "       %s:\n", f.colorize("Failed Expectations", colorRed))
+	fmt.Fprintf(f.writer, "       %s:\n", f.colorize(f.msg.T("failed_expectations"), colorRed))
 	for _, exp := range failedExpectations {
-		fmt.Fprintf(f.writer, "         - %s\n", exp.Expression)
+		fmt.Fprintf(f.writer, "         - %s\n", f.fit(exp.Expression, 11))
 		if exp.Message != "" {
-			fmt.Fprintf(f.writer, "           %s\n", f.colorize(exp.Message, colorYellow))
+			fmt.Fprintf(f.writer, "           %s\n", f.colorize(f.fit(exp.Message, 11), colorYellow))
 		}
 	}
 }
@@ -199,12 +364,29 @@ func (f *TableFormatter) formatEvidence(obs execution.ObservationResult) {
 		return
 	}
 
-	fmt.Fprintf(f.writer, "       Evidence:\n")
+	fmt.Fprintf(f.writer, "       %s:\n", f.msg.T("evidence"))
 	for _, key := range keys {
 		f.formatEvidenceValue(key, obs.Evidence.Data[key])
 	}
 }
 
+// formatPII lists the likely personal data found in the evidence.
+//
+//nolint:errcheck // Best-effort terminal output
+func (f *TableFormatter) formatPII(obs execution.ObservationResult) {
+	if len(obs.PII) == 0 {
+		return
+	}
+	fmt.Fprintf(f.writer, "       %s:\n", f.colorize(f.msg.T("personal_data"), colorYellow))
+	for _, finding := range obs.PII {
+		note := ""
+		if finding.Redacted {
+			note = " (" + f.msg.T("redacted") + ")"
+		}
+		fmt.Fprintf(f.writer, "         - %s: %d %s%s\n", finding.Path, finding.Count, finding.Kind, note)
+	}
+}
+
 // collectEvidenceKeys collects valid keys for evidence display.
 func (f *TableFormatter) collectEvidenceKeys(data map[string]interface{}) []string {
 	var keys []string
@@ -230,16 +412,35 @@ func (f *TableFormatter) collectEvidenceKeys(data map[string]interface{}) []stri
 //
 //nolint:errcheck // Best-effort terminal output
 func (f *TableFormatter) formatEvidenceValue(key string, value interface{}) {
-	valStr := f.formatValue(value)
+	valStr := snippet(f.formatValue(value))
 
 	if strings.Contains(valStr, "\n") {
 		fmt.Fprintf(f.writer, "         - %s:\n", f.colorize(key, colorBlue))
 		for _, line := range strings.Split(valStr, "\n") {
-			fmt.Fprintf(f.writer, "           %s\n", line)
+			fmt.Fprintf(f.writer, "           %s\n", f.fit(line, 11))
 		}
 	} else {
-		fmt.Fprintf(f.writer, "         - %s: %s\n", f.colorize(key, colorBlue), valStr)
+		fmt.Fprintf(f.writer, "         - %s: %s\n", f.colorize(key, colorBlue), f.fit(valStr, 13+utf8.RuneCountInString(key)))
+	}
+}
+
+// snippet clips an evidence value to maxSnippetLines lines of at most
+// maxSnippetWidth characters.
+func snippet(value string) string {
+	lines := strings.Split(value, "\n")
+	clipped := lines
+	if len(clipped) > maxSnippetLines {
+		clipped = clipped[:maxSnippetLines]
+	}
+	for i, line := range clipped {
+		if utf8.RuneCountInString(line) > maxSnippetWidth {
+			clipped[i] = string([]rune(line)[:maxSnippetWidth-1]) + "…"
+		}
+	}
+	if more := len(lines) - len(clipped); more > 0 {
+		clipped = append(clipped, fmt.Sprintf("… %d more lines", more))
 	}
+	return strings.Join(clipped, "\n")
 }
 
 // formatValue formats a value for display
@@ -282,28 +483,192 @@ func (f *TableFormatter) formatValue(value interface{}) string {
 	}
 }
 
+// maxListedObservations caps the observations listed under an error group.
+const maxListedObservations = 10
+
+// formatErrorGroups formats errors shared by several observations, once per
+// root cause.
+//
+//nolint:errcheck // Best-effort terminal output
+func (f *TableFormatter) formatErrorGroups(groups []execution.ErrorGroup) {
+	fmt.Fprintln(f.writer, f.colorize(f.msg.T("grouped_errors")+":", colorBold))
+	fmt.Fprintln(f.writer, f.rule())
+
+	for _, group := range groups {
+		prefix := f.msg.T("observations_failed", len(group.Observations)) + " "
+		fmt.Fprintf(f.writer, "%s %s%s\n",
+			f.colorize("⚠", colorYellow), prefix, f.fit(group.Message, 2+len(prefix)))
+		if group.Code != "" {
+			fmt.Fprintf(f.writer, "  %s: %s\n", f.msg.T("code"), group.Code)
+		}
+		f.field(2, f.msg.T("example"), "", group.Example)
+
+		listed := group.Observations
+		if len(listed) > maxListedObservations {
+			listed = listed[:maxListedObservations]
+		}
+		for _, ref := range listed {
+			fmt.Fprintf(f.writer, "    - %s (%s, %s)\n", ref.ControlID, f.colorize(ref.Plugin, colorCyan), f.msg.T("observation_ref", ref.Index+1))
+		}
+		if more := len(group.Observations) - len(listed); more > 0 {
+			fmt.Fprintf(f.writer, "    %s\n", f.colorize(f.msg.T("and_more", more), colorGray))
+		}
+		fmt.Fprintln(f.writer)
+	}
+
+	fmt.Fprintln(f.writer, f.rule())
+	fmt.Fprintln(f.writer)
+}
+
+// formatSummaryLine writes the outcome of the run as a single line.
+//
+//nolint:errcheck // Best-effort terminal output
+func (f *TableFormatter) formatSummaryLine(result *execution.ExecutionResult) {
+	summary := result.Summary
+	status := values.StatusPass
+	switch {
+	case summary.ErrorControls > 0:
+		status = values.StatusError
+	case summary.FailedControls > 0:
+		status = values.StatusFail
+	}
+	symbol, color := f.getStatusInfo(status)
+
+	counts := f.msg.T("summary_line", summary.TotalControls,
+		summary.PassedControls, summary.FailedControls, summary.ErrorControls, summary.SkippedControls)
+	if summary.CollectedControls > 0 {
+		counts += f.msg.T("summary_line.collected", summary.CollectedControls)
+	}
+	fmt.Fprintf(f.writer, "%s %s: %s (%s)\n",
+		f.colorize(symbol+" "+f.statusLabel(status), color), result.ProfileName,
+		counts, result.Duration.Round(time.Millisecond))
+}
+
 // formatSummary formats the summary statistics.
 //
 //nolint:errcheck // Best-effort terminal output
 func (f *TableFormatter) formatSummary(summary execution.ResultSummary) {
-	fmt.Fprintln(f.writer, f.colorize("Summary:", colorBold))
-	fmt.Fprintln(f.writer, f.colorize(strings.Repeat("─", 80), colorGray))
+	fmt.Fprintln(f.writer, f.colorize(f.msg.T("summary")+":", colorBold))
+	fmt.Fprintln(f.writer, f.rule())
+
+	// Align the counts after the widest heading and status label
+	controls, observations := f.msg.T("controls")+":", f.msg.T("observations")+":"
+	headingWidth := max(utf8.RuneCountInString(controls), utf8.RuneCountInString(observations)) + 1
+	passed, failed, errors, skipped := f.msg.T("passed")+":", f.msg.T("failed")+":", f.msg.T("errors")+":", f.msg.T("skipped")+":"
+	labelWidth := 0
+	for _, label := range []string{passed, failed, errors, skipped} {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(label)+2)
+	}
 
 	// Controls summary
-	fmt.Fprintf(f.writer, "Controls:     %d total\n", summary.TotalControls)
-	fmt.Fprintf(f.writer, "  %s Passed:   %d\n", f.colorize("✓", colorGreen), summary.PassedControls)
-	fmt.Fprintf(f.writer, "  %s Failed:   %d\n", f.colorize("✗", colorRed), summary.FailedControls)
-	fmt.Fprintf(f.writer, "  %s Errors:   %d\n", f.colorize("⚠", colorYellow), summary.ErrorControls)
-	fmt.Fprintf(f.writer, "  %s Skipped:  %d\n", f.colorize("⊘", colorGray), summary.SkippedControls)
+	fmt.Fprintf(f.writer, "%s%s\n", pad(controls, headingWidth), f.msg.T("n_total", summary.TotalControls))
+	fmt.Fprintf(f.writer, "  %s %s%d\n", f.colorize("✓", colorGreen), pad(passed, labelWidth), summary.PassedControls)
+	fmt.Fprintf(f.writer, "  %s %s%d\n", f.colorize("✗", colorRed), pad(failed, labelWidth), summary.FailedControls)
+	fmt.Fprintf(f.writer, "  %s %s%d\n", f.colorize("⚠", colorYellow), pad(errors, labelWidth), summary.ErrorControls)
+	fmt.Fprintf(f.writer, "  %s %s%d\n", f.colorize("⊘", colorGray), pad(skipped, labelWidth), summary.SkippedControls)
+	if summary.CollectedControls > 0 {
+		fmt.Fprintf(f.writer, "  %s %s: %d\n", f.colorize("●", colorBlue), f.msg.T("collected"), summary.CollectedControls)
+	}
 	fmt.Fprintln(f.writer)
 
 	// Observations summary
-	fmt.Fprintf(f.writer, "Observations: %d total\n", summary.TotalObservations)
-	fmt.Fprintf(f.writer, "  %s Passed:   %d\n", f.colorize("✓", colorGreen), summary.PassedObservations)
-	fmt.Fprintf(f.writer, "  %s Failed:   %d\n", f.colorize("✗", colorRed), summary.FailedObservations)
-	fmt.Fprintf(f.writer, "  %s Errors:   %d\n", f.colorize("⚠", colorYellow), summary.ErrorObservations)
+	fmt.Fprintf(f.writer, "%s%s\n", pad(observations, headingWidth), f.msg.T("n_total", summary.TotalObservations))
+	fmt.Fprintf(f.writer, "  %s %s%d\n", f.colorize("✓", colorGreen), pad(passed, labelWidth), summary.PassedObservations)
+	fmt.Fprintf(f.writer, "  %s %s%d\n", f.colorize("✗", colorRed), pad(failed, labelWidth), summary.FailedObservations)
+	fmt.Fprintf(f.writer, "  %s %s%d\n", f.colorize("⚠", colorYellow), pad(errors, labelWidth), summary.ErrorObservations)
+	if summary.CollectedObservations > 0 {
+		fmt.Fprintf(f.writer, "  %s %s: %d\n", f.colorize("●", colorBlue), f.msg.T("collected"), summary.CollectedObservations)
+	}
+	if summary.PIIObservations > 0 {
+		fmt.Fprintf(f.writer, "  %s %s: %d\n", f.colorize("⚠", colorYellow), f.msg.T("with_personal_data"), summary.PIIObservations)
+	}
 
-	fmt.Fprintln(f.writer, f.colorize(strings.Repeat("─", 80), colorGray))
+	fmt.Fprintln(f.writer, f.rule())
+}
+
+// formatPerformance formats the per-phase and per-plugin timing breakdown.
+//
+//nolint:errcheck // Best-effort terminal output
+func (f *TableFormatter) formatPerformance(perf *execution.PerformanceReport) {
+	fmt.Fprintln(f.writer)
+	fmt.Fprintln(f.writer, f.colorize("Performance:", colorBold))
+	fmt.Fprintln(f.writer, f.rule())
+
+	tw := tabwriter.NewWriter(f.writer, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "PHASE\tDURATION")
+	for _, phase := range perf.Phases {
+		fmt.Fprintf(tw, "%s\t%s\n", phase.Phase, roundTiming(phase.Duration))
+	}
+	tw.Flush()
+
+	if len(perf.Plugins) > 0 {
+		fmt.Fprintln(f.writer)
+		fmt.Fprintln(tw, "PLUGIN\tOBSERVATIONS\tINSTANTIATION\tEXECUTION\tHOST I/O")
+		for _, plugin := range perf.Plugins {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", plugin.Plugin, plugin.Observations,
+				roundTiming(plugin.Instantiation), roundTiming(plugin.Execution), roundTiming(plugin.HostIO))
+		}
+		tw.Flush()
+	}
+
+	if perf.Traffic != nil {
+		fmt.Fprintln(f.writer)
+		fmt.Fprintln(tw, "PLUGIN\tREQUESTS\tSENT\tRECEIVED")
+		for _, stats := range perf.Traffic.Plugins {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", stats.Name, stats.Requests, formatBytes(stats.BytesSent), formatBytes(stats.BytesReceived))
+		}
+		tw.Flush()
+		fmt.Fprintln(f.writer)
+		fmt.Fprintln(tw, "DESTINATION\tREQUESTS\tSENT\tRECEIVED")
+		for _, stats := range perf.Traffic.Destinations {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", stats.Name, stats.Requests, formatBytes(stats.BytesSent), formatBytes(stats.BytesReceived))
+		}
+		tw.Flush()
+	}
+
+	if len(perf.Scheduling) > 0 {
+		fmt.Fprintln(f.writer)
+		fmt.Fprintln(tw, "SCHEDULED\tWEIGHT\tCONTROLS\tAVG WAIT\tMAX WAIT\tPEAK WORKERS")
+		for _, stats := range perf.Scheduling {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%d\n", stats.Plugin, stats.Weight, stats.Controls,
+				roundTiming(stats.TotalWait/time.Duration(stats.Controls)), roundTiming(stats.MaxWait), stats.PeakWorkers)
+		}
+		tw.Flush()
+	}
+
+	if perf.DNS != nil || perf.HTTP != nil {
+		fmt.Fprintln(f.writer)
+	}
+	if perf.DNS != nil {
+		fmt.Fprintf(f.writer, "DNS lookups: %d (%d from cache)\n", perf.DNS.Lookups, perf.DNS.CacheHits)
+	}
+	if perf.HTTP != nil {
+		fmt.Fprintf(f.writer, "HTTP connections: %d (%d reused)\n", perf.HTTP.Connections, perf.HTTP.ReusedConnections)
+		if perf.HTTP.CachedResponses > 0 {
+			fmt.Fprintf(f.writer, "HTTP responses from cache: %d\n", perf.HTTP.CachedResponses)
+		}
+	}
+
+	fmt.Fprintln(f.writer, f.rule())
+}
+
+// formatBytes renders a byte count in binary units.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// roundTiming rounds a phase or plugin timing for display.
+func roundTiming(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
 }
 
 // formatErrorDetail formats a structured error with type, code, message, and wrapped errors.
@@ -335,6 +700,22 @@ func (f *TableFormatter) formatErrorDetail(errMap map[string]interface{}, indent
 	return result
 }
 
+// severityColor returns the color of a control severity.
+func severityColor(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return colorBold + colorPurple
+	case "high":
+		return colorRed
+	case "medium":
+		return colorYellow
+	case "low":
+		return colorBlue
+	default:
+		return colorGray
+	}
+}
+
 // getStatusInfo returns a symbol and color for the given status.
 func (f *TableFormatter) getStatusInfo(status values.Status) (string, string) {
 	switch status {
@@ -344,8 +725,10 @@ func (f *TableFormatter) getStatusInfo(status values.Status) (string, string) {
 		return "✗", colorRed
 	case values.StatusError:
 		return "⚠", colorYellow
-	case values.StatusSkipped:
+	case values.StatusSkipped, values.StatusNotRun:
 		return "⊘", colorGray
+	case values.StatusCollected:
+		return "●", colorBlue
 	default:
 		return "?", colorReset
 	}
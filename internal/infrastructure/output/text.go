@@ -0,0 +1,171 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/i18n"
+)
+
+// Ensure interface compliance
+var _ ports.OutputFormatter = (*TextFormatter)(nil)
+
+// TextFormatter formats execution results as plain text for screen readers:
+// one statement per line, in words, without symbols, colors, rules or
+// columns padded with spaces. Every control and observation is listed, with
+// its position, so a listener knows where they are.
+type TextFormatter struct {
+	writer   io.Writer
+	Language string // Language of the report strings (default: English)
+
+	msg *i18n.Catalog
+	b   strings.Builder
+}
+
+// NewTextFormatter creates a new plain text formatter.
+func NewTextFormatter(w io.Writer) *TextFormatter {
+	return &TextFormatter{writer: w}
+}
+
+// Format writes the execution result as plain text.
+func (f *TextFormatter) Format(result *execution.ExecutionResult) error {
+	f.msg = i18n.For(f.Language)
+	f.b.Reset()
+
+	f.line(f.msg.T("text.title", result.ProfileName, result.ProfileVersion))
+	if result.Environment != "" {
+		f.field("environment", result.Environment)
+	}
+	switch result.Mode {
+	case execution.ModeCollect:
+		f.line(f.msg.T("mode.collect"))
+	case execution.ModeEvaluate:
+		f.line(f.msg.T("mode.evaluate", result.EvaluatedRun))
+	}
+	f.line(f.msg.T("executed_in", result.StartTime.Format(time.RFC3339), result.Duration.Round(time.Millisecond)))
+	f.line("")
+
+	summary := result.Summary
+	f.line(f.msg.T("summary") + ": " + f.msg.T("summary_line", summary.TotalControls,
+		summary.PassedControls, summary.FailedControls, summary.ErrorControls, summary.SkippedControls))
+	f.line(f.msg.T("text.observations_line", summary.TotalObservations,
+		summary.PassedObservations, summary.FailedObservations, summary.ErrorObservations))
+	f.line("")
+
+	f.formatErrorGroups(result.ErrorGroups)
+
+	if len(result.Controls) == 0 {
+		f.line(f.msg.T("no_controls"))
+	}
+	for i := range result.Controls {
+		f.formatControl(i+1, len(result.Controls), &result.Controls[i])
+	}
+	f.line(f.msg.T("text.end"))
+
+	if _, err := io.WriteString(f.writer, f.b.String()); err != nil {
+		return fmt.Errorf("failed to write text report: %w", err)
+	}
+	return nil
+}
+
+// line appends a line of text.
+func (f *TextFormatter) line(text string) {
+	f.b.WriteString(text)
+	f.b.WriteByte('\n')
+}
+
+// field appends a "label: value" line, the label a message key.
+func (f *TextFormatter) field(key, value string) {
+	f.line(f.msg.T(key) + ": " + value)
+}
+
+// status returns the translated name of a status in lowercase words, which
+// screen readers pronounce instead of spelling out.
+func (f *TextFormatter) status(status values.Status) string {
+	label := strings.ToUpper(string(status))
+	if key := "status." + string(status); f.msg.Has(key) {
+		label = f.msg.T(key)
+	}
+	return strings.ToLower(strings.ReplaceAll(label, "_", " "))
+}
+
+// formatErrorGroups appends the errors shared by several observations.
+func (f *TextFormatter) formatErrorGroups(groups []execution.ErrorGroup) {
+	if len(groups) == 0 {
+		return
+	}
+	f.line(f.msg.T("grouped_errors"))
+	for _, group := range groups {
+		f.line(f.msg.T("observations_failed", len(group.Observations)) + " " + group.Message)
+		f.field("example", group.Example)
+		for _, ref := range group.Observations {
+			f.line(fmt.Sprintf("%s, %s, %s", ref.ControlID, ref.Plugin, f.msg.T("observation_ref", ref.Index+1)))
+		}
+	}
+	f.line("")
+}
+
+// formatControl appends control number n of total.
+func (f *TextFormatter) formatControl(n, total int, ctrl *execution.ControlResult) {
+	title := ctrl.ID
+	if ctrl.Name != "" {
+		title += ", " + ctrl.Name
+	}
+	f.line(f.msg.T("text.control", n, total, title))
+	f.field("status", f.status(ctrl.Status))
+	if ctrl.Severity != "" {
+		severity := strings.ToLower(ctrl.Severity)
+		if key := "severity." + severity; f.msg.Has(key) {
+			severity = strings.ToLower(f.msg.T(key))
+		}
+		f.field("severity", severity)
+	}
+	if ctrl.SeverityRule != "" {
+		f.field("severity_rule", ctrl.SeverityRule)
+	}
+	if ctrl.Description != "" {
+		f.field("description", ctrl.Description)
+	}
+	if ctrl.Rationale != "" {
+		f.field("rationale", ctrl.Rationale)
+	}
+	if len(ctrl.References) > 0 {
+		f.field("references", strings.Join(ctrl.References, ", "))
+	}
+	if ctrl.Message != "" {
+		f.field("message", ctrl.Message)
+	}
+	if ctrl.SkipReason != "" && ctrl.SkipReason != ctrl.Message {
+		f.field("skip_reason", ctrl.SkipReason)
+	}
+	if len(ctrl.Labels) > 0 {
+		f.field("labels", strings.Join(ctrl.LabelPairs(), ", "))
+	}
+
+	for i, obs := range ctrl.ObservationResults {
+		f.line(f.msg.T("text.observation", i+1, len(ctrl.ObservationResults), obs.Plugin, f.status(obs.Status)))
+		if obs.Error != nil {
+			key := "error"
+			if obs.Status == values.StatusNotRun {
+				key = "reason"
+			}
+			f.field(key, fmt.Sprintf("%s, %s", obs.Error.Code, obs.Error.Message))
+		}
+		for _, exp := range obs.Expectations {
+			if exp.Passed {
+				continue
+			}
+			failed := exp.Expression
+			if exp.Message != "" {
+				failed += ", " + exp.Message
+			}
+			f.field("failed_expectation", failed)
+		}
+	}
+	f.line("")
+}
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextFormatter_Format(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, NewTextFormatter(&buf).Format(createTestResult()))
+
+	output := buf.String()
+	assert.True(t, strings.HasPrefix(output, "Reglet report for profile test-profile, version 1.0.0\n"))
+	assert.Contains(t, output, "Summary: 3 controls, 1 passed, 1 failed, 1 errors, 0 skipped\n")
+	assert.Contains(t, output, "Control 1 of 3: ctrl-1, Test Control 1\nStatus: pass\nSeverity: high\n")
+	assert.Contains(t, output, "Labels: cmdb_id=CI0042, service=web\n")
+	assert.Contains(t, output, "Observation 1 of 1: plugin nonexistent, error\nError: plugin_load_error, unknown plugin: nonexistent\n")
+	assert.True(t, strings.HasSuffix(output, "End of report.\n"))
+
+	for _, symbol := range []string{"✓", "✗", "⚠", "─", "\033["} {
+		assert.NotContains(t, output, symbol)
+	}
+	assert.NotContains(t, output, "  ", "no padding for alignment")
+}
+
+func TestTextFormatter_GroupedErrors(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, NewTextFormatter(&buf).Format(createOutageResult(2)))
+
+	output := buf.String()
+	assert.Contains(t, output, "Grouped Errors\n2 observations failed: lookup <host> on <addr>: no such host\n")
+	assert.Contains(t, output, "svc-1, http, observation 1\n")
+}
+
+func TestTextFormatter_Language(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	formatter := NewTextFormatter(&buf)
+	formatter.Language = "fr"
+	require.NoError(t, formatter.Format(createTestResult()))
+
+	output := buf.String()
+	assert.Contains(t, output, "Contrôle 2 sur 3 : ctrl-2, Test Control 2\n")
+	assert.Contains(t, output, "Fin du rapport.\n")
+}
@@ -0,0 +1,401 @@
+package jsonfile
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/reglet-dev/reglet/internal/infrastructure/compression"
+)
+
+// Ensure interface compliance
+var _ repositories.ExecutionResultRepository = (*ExecutionResultRepository)(nil)
+
+// ExecutionResultRepository stores execution results as JSON files, one per
+// execution, named after the execution ID, in a subdirectory per namespace.
+// It needs no server, which makes it the local store of single machines,
+// including air-gapped ones whose results are later moved with
+// "reglet history export".
+//
+// Results are optionally compressed with gzip or zstd (<id>.json.gz,
+// <id>.json.zst); results stored with any compression are read alike.
+//
+// Files are replaced atomically. Locking is per process: writers in separate
+// processes sharing a directory may overwrite each other's concurrent saves.
+type ExecutionResultRepository struct {
+	dir         string
+	compression compression.Algorithm
+	mu          sync.Mutex
+}
+
+// header is the part of a stored result that queries filter on.
+type header struct {
+	StartTime   time.Time `json:"start_time"`
+	ProfileName string    `json:"profile_name"`
+	Environment string    `json:"environment"`
+	path        string
+	Version     int       `json:"version"`
+	ExecutionID uuid.UUID `json:"execution_id"`
+}
+
+// NewExecutionResultRepository creates a repository storing results in dir.
+// The directory is created on first save.
+func NewExecutionResultRepository(dir string) *ExecutionResultRepository {
+	return &ExecutionResultRepository{dir: dir}
+}
+
+// SetCompression sets the compression of the results saved from now on.
+// Results already stored keep theirs until saved again.
+func (r *ExecutionResultRepository) SetCompression(algorithm compression.Algorithm) {
+	r.compression = algorithm
+}
+
+// Save persists an execution result.
+func (r *ExecutionResultRepository) Save(ctx context.Context, result *execution.ExecutionResult) error {
+	return r.SaveBatch(ctx, []*execution.ExecutionResult{result})
+}
+
+// SaveBatch persists several results. Every result is checked before any is
+// written, so a conflict saves nothing.
+func (r *ExecutionResultRepository) SaveBatch(ctx context.Context, results []*execution.ExecutionResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	dir, err := r.namespaceDir(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	writes := make([][]byte, len(results))
+	bumps := make([]bool, len(results))
+	seen := make(map[uuid.UUID]bool, len(results))
+	for i, result := range results {
+		id := result.GetID().UUID()
+		if seen[id] {
+			return fmt.Errorf("%w: %s saved twice in one batch", repositories.ErrVersionConflict, id)
+		}
+		seen[id] = true
+
+		data, err := snapshot(result, result.GetVersion())
+		if err != nil {
+			return err
+		}
+		stored, err := compression.ReadFile(findResult(dir, id))
+		if errors.Is(err, os.ErrNotExist) {
+			writes[i] = data
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read stored execution result %s: %w", id, err)
+		}
+
+		var current header
+		if err := json.Unmarshal(stored, &current); err != nil {
+			return fmt.Errorf("failed to read stored execution result %s: %w", id, err)
+		}
+		if result.GetVersion() != current.Version {
+			return fmt.Errorf("%w: %s is at version %d, saved at %d", repositories.ErrVersionConflict, id, current.Version, result.GetVersion())
+		}
+		if bytes.Equal(data, stored) {
+			continue // retried write
+		}
+		if writes[i], err = snapshot(result, result.GetVersion()+1); err != nil {
+			return err
+		}
+		bumps[i] = true
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create results directory: %w", err)
+	}
+	for i, data := range writes {
+		if data == nil {
+			continue
+		}
+		id := results[i].GetID().UUID()
+		compressed, err := compression.Compress(data, r.compression)
+		if err != nil {
+			return fmt.Errorf("failed to compress execution result %s: %w", id, err)
+		}
+		path := r.resultPath(dir, id)
+		if err := writeAtomic(path, compressed); err != nil {
+			return err
+		}
+		// Drop the copy stored with a previous compression setting
+		for _, other := range resultPaths(dir, id) {
+			if other != path {
+				_ = os.Remove(other)
+			}
+		}
+		if bumps[i] {
+			results[i].IncrementVersion()
+		}
+	}
+	return nil
+}
+
+// FindByID retrieves an execution result by its unique ID.
+func (r *ExecutionResultRepository) FindByID(ctx context.Context, id uuid.UUID) (*execution.ExecutionResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	dir, err := r.namespaceDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.load(findResult(dir, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", repositories.ErrResultNotFound, id)
+	}
+	return result, err
+}
+
+// FindByProfile retrieves recent execution results for a specific profile.
+func (r *ExecutionResultRepository) FindByProfile(ctx context.Context, profileName string, limit int) ([]*execution.ExecutionResult, error) {
+	matches, err := r.match(ctx, repositories.ResultQuery{ProfileName: profileName})
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return r.loadAll(ctx, matches)
+}
+
+// FindBetween retrieves execution results for a profile within a time range.
+func (r *ExecutionResultRepository) FindBetween(ctx context.Context, profileName string, start, end time.Time) ([]*execution.ExecutionResult, error) {
+	matches, err := r.match(ctx, repositories.ResultQuery{ProfileName: profileName, Since: start, Until: end})
+	if err != nil {
+		return nil, err
+	}
+	return r.loadAll(ctx, matches)
+}
+
+// List returns one page of the results matching query, newest first. Page
+// tokens mark a position in that order rather than an offset, so results
+// saved between pages neither repeat nor shift later pages.
+func (r *ExecutionResultRepository) List(ctx context.Context, query repositories.ResultQuery) (*repositories.ResultPage, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = repositories.DefaultPageSize
+	}
+
+	matches, err := r.match(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if query.PageToken != "" {
+		after, err := decodePageToken(query.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		i := sort.Search(len(matches), func(i int) bool { return newerFirst(after, matches[i]) })
+		matches = matches[i:]
+	}
+
+	page := &repositories.ResultPage{}
+	if len(matches) > limit {
+		matches = matches[:limit]
+		page.NextPageToken = encodePageToken(matches[limit-1])
+	}
+	if page.Results, err = r.loadAll(ctx, matches); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// match reads the headers of the results stored in the context's namespace
+// and returns those matching query, newest first.
+func (r *ExecutionResultRepository) match(ctx context.Context, query repositories.ResultQuery) ([]*header, error) {
+	dir, err := r.namespaceDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results directory: %w", err)
+	}
+
+	var matches []*header
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(compression.TrimExtension(entry.Name()), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := compression.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stored execution result: %w", err)
+		}
+		h := &header{path: path}
+		if err := json.Unmarshal(data, h); err != nil {
+			return nil, fmt.Errorf("failed to read stored execution result %s: %w", path, err)
+		}
+
+		if query.ProfileName != "" && h.ProfileName != query.ProfileName {
+			continue
+		}
+		if query.Environment != "" && h.Environment != query.Environment {
+			continue
+		}
+		if !query.Since.IsZero() && h.StartTime.Before(query.Since) {
+			continue
+		}
+		if !query.Until.IsZero() && h.StartTime.After(query.Until) {
+			continue
+		}
+		matches = append(matches, h)
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return newerFirst(matches[i], matches[j]) })
+	return matches, nil
+}
+
+// namespaceDir returns the directory of the context's namespace. The name is
+// validated, as it becomes part of the path.
+func (r *ExecutionResultRepository) namespaceDir(ctx context.Context) (string, error) {
+	namespace := repositories.NamespaceFromContext(ctx)
+	if err := repositories.ValidateNamespace(namespace); err != nil {
+		return "", err
+	}
+	return filepath.Join(r.dir, namespace), nil
+}
+
+// resultPath returns the path a result is saved to.
+func (r *ExecutionResultRepository) resultPath(dir string, id uuid.UUID) string {
+	return filepath.Join(dir, id.String()+".json"+r.compression.Extension())
+}
+
+// resultPaths returns the paths a result may be stored at, one per
+// compression.
+func resultPaths(dir string, id uuid.UUID) []string {
+	base := filepath.Join(dir, id.String()+".json")
+	return []string{base, base + compression.Gzip.Extension(), base + compression.Zstd.Extension()}
+}
+
+// findResult returns the path a result is stored at, or the uncompressed
+// path when it is not stored.
+func findResult(dir string, id uuid.UUID) string {
+	paths := resultPaths(dir, id)
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return paths[0]
+}
+
+func (r *ExecutionResultRepository) load(path string) (*execution.ExecutionResult, error) {
+	data, err := compression.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	result, err := execution.DecodeExecutionResult(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored execution result %s: %w", path, err)
+	}
+	return result, nil
+}
+
+func (r *ExecutionResultRepository) loadAll(ctx context.Context, headers []*header) ([]*execution.ExecutionResult, error) {
+	results := make([]*execution.ExecutionResult, 0, len(headers))
+	for _, h := range headers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := r.load(h.path)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// newerFirst orders results by start time descending, then by ID descending
+// so results starting at the same time have a stable order across pages.
+func newerFirst(a, b *header) bool {
+	if !a.StartTime.Equal(b.StartTime) {
+		return a.StartTime.After(b.StartTime)
+	}
+	return strings.Compare(a.ExecutionID.String(), b.ExecutionID.String()) > 0
+}
+
+// snapshot serializes result as stored at version.
+func snapshot(result *execution.ExecutionResult, version int) ([]byte, error) {
+	// The outer Version shadows the result's own, so the caller's result is
+	// left unchanged until the whole batch is accepted.
+	data, err := json.Marshal(struct {
+		*execution.ExecutionResult
+		Version int `json:"version"`
+	}{result, version})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize execution result %s: %w", result.GetID(), err)
+	}
+	return data, nil
+}
+
+// writeAtomic replaces path with data, so readers never see a partial file.
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".result-*")
+	if err != nil {
+		return fmt.Errorf("failed to write execution result: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write execution result: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write execution result: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write execution result: %w", err)
+	}
+	return nil
+}
+
+// encodePageToken marks the position of the last result of a page.
+func encodePageToken(last *header) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(last.StartTime.UnixNano(), 10) + "/" + last.ExecutionID.String()))
+}
+
+func decodePageToken(token string) (*header, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	nanos, id, ok := strings.Cut(string(raw), "/")
+	if !ok {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	return &header{StartTime: time.Unix(0, n), ExecutionID: parsed}, nil
+}
@@ -0,0 +1,191 @@
+package jsonfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/compression"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionResultRepository_SaveAndFind(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "results")
+	repo := NewExecutionResultRepository(dir)
+	ctx := context.Background()
+
+	_, err := repo.FindByID(ctx, values.NewExecutionID().UUID())
+	require.ErrorIs(t, err, repositories.ErrResultNotFound, "before the directory exists")
+
+	result := execution.NewExecutionResult("web", "1.0")
+	result.Environment = "prod"
+	result.Controls = []execution.ControlResult{{ID: "c", Status: values.StatusPass}}
+	result.Finalize()
+	require.NoError(t, repo.Save(ctx, result))
+
+	found, err := repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+	assert.Equal(t, "prod", found.Environment)
+	require.Len(t, found.Controls, 1)
+
+	// Stored files are ordinary results, usable by compare
+	files, err := NewResultLoader().DiscoverResults([]string{dir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "default", result.GetID().String()+".json")}, files)
+
+	entries, err := os.ReadDir(filepath.Join(dir, "default"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
+
+func TestExecutionResultRepository_OptimisticLocking(t *testing.T) {
+	t.Parallel()
+
+	repo := NewExecutionResultRepository(t.TempDir())
+	ctx := context.Background()
+
+	result := execution.NewExecutionResult("web", "1.0")
+	require.NoError(t, repo.Save(ctx, result))
+	require.NoError(t, repo.Save(ctx, result), "retried write is a no-op")
+	assert.Equal(t, 1, result.GetVersion())
+
+	stale, err := repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+
+	result.Environment = "prod"
+	require.NoError(t, repo.Save(ctx, result))
+	assert.Equal(t, 2, result.GetVersion())
+
+	stale.Environment = "staging"
+	fresh := execution.NewExecutionResult("web", "1.0")
+	err = repo.SaveBatch(ctx, []*execution.ExecutionResult{fresh, stale})
+	require.ErrorIs(t, err, repositories.ErrVersionConflict)
+	assert.Equal(t, 1, stale.GetVersion())
+
+	_, err = repo.FindByID(ctx, fresh.GetID().UUID())
+	assert.ErrorIs(t, err, repositories.ErrResultNotFound, "no result of a failed batch is saved")
+
+	stored, err := repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+	assert.Equal(t, "prod", stored.Environment)
+	assert.Equal(t, 2, stored.GetVersion())
+}
+
+func TestExecutionResultRepository_List(t *testing.T) {
+	t.Parallel()
+
+	repo := NewExecutionResultRepository(t.TempDir())
+	ctx := context.Background()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var prod []*execution.ExecutionResult
+	for i := 0; i < 5; i++ {
+		r := execution.NewExecutionResult("web", "1.0")
+		r.Environment = "prod"
+		r.StartTime = start.Add(time.Duration(i) * time.Hour)
+		prod = append(prod, r)
+	}
+	staging := execution.NewExecutionResult("web", "1.0")
+	staging.Environment = "staging"
+	require.NoError(t, repo.SaveBatch(ctx, append(prod, staging, execution.NewExecutionResult("api", "1.0"))))
+
+	var listed []string
+	query := repositories.ResultQuery{ProfileName: "web", Environment: "prod", Limit: 2}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		page, err := repo.List(ctx, query)
+		require.NoError(t, err)
+		for _, r := range page.Results {
+			listed = append(listed, r.GetID().String())
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		query.PageToken = page.NextPageToken
+	}
+
+	require.Len(t, listed, 5)
+	assert.Equal(t, prod[4].GetID().String(), listed[0], "newest first")
+	assert.Equal(t, prod[0].GetID().String(), listed[4])
+
+	between, err := repo.FindBetween(ctx, "web", start.Add(time.Hour), start.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, between, 2)
+
+	recent, err := repo.FindByProfile(ctx, "web", 1)
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+
+	_, err = repo.List(ctx, repositories.ResultQuery{PageToken: "not-a-token"})
+	assert.Error(t, err)
+}
+
+func TestExecutionResultRepository_NamespaceIsolation(t *testing.T) {
+	t.Parallel()
+
+	repo := NewExecutionResultRepository(t.TempDir())
+	teamA := repositories.WithNamespace(context.Background(), "team-a")
+	teamB := repositories.WithNamespace(context.Background(), "team-b")
+
+	result := execution.NewExecutionResult("web", "1.0")
+	require.NoError(t, repo.Save(teamA, result))
+
+	_, err := repo.FindByID(teamB, result.GetID().UUID())
+	require.ErrorIs(t, err, repositories.ErrResultNotFound)
+	page, err := repo.List(teamB, repositories.ResultQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Results)
+
+	// Namespace names become directories, so they are validated
+	escape := repositories.WithNamespace(context.Background(), "../team-a")
+	_, err = repo.FindByID(escape, result.GetID().UUID())
+	assert.ErrorIs(t, err, repositories.ErrInvalidNamespace)
+}
+
+func TestExecutionResultRepository_Compression(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	repo := NewExecutionResultRepository(dir)
+	repo.SetCompression(compression.Zstd)
+	ctx := context.Background()
+
+	result := execution.NewExecutionResult("web", "1.0")
+	result.Environment = "prod"
+	require.NoError(t, repo.Save(ctx, result))
+	require.NoError(t, repo.Save(ctx, result), "retried write is a no-op")
+	assert.Equal(t, 1, result.GetVersion())
+
+	path := filepath.Join(dir, "default", result.GetID().String()+".json.zst")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, compression.IsCompressed(data))
+
+	page, err := repo.List(ctx, repositories.ResultQuery{Environment: "prod"})
+	require.NoError(t, err)
+	require.Len(t, page.Results, 1)
+	files, err := NewResultLoader().DiscoverResults([]string{dir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{path}, files, "compressed results are usable by compare")
+
+	// Results stored with another compression are read and replaced
+	repo.SetCompression(compression.Gzip)
+	stored, err := repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+	stored.Environment = "staging"
+	require.NoError(t, repo.Save(ctx, stored))
+	assert.Equal(t, 2, stored.GetVersion())
+
+	entries, err := os.ReadDir(filepath.Join(dir, "default"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, result.GetID().String()+".json.gz", entries[0].Name())
+}
@@ -0,0 +1,104 @@
+// Package jsonfile reads execution results saved with
+// "reglet check --format json", and stores results as JSON files.
+package jsonfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/compression"
+	"github.com/reglet-dev/reglet/internal/infrastructure/rundir"
+)
+
+// ResultLoader reads execution results from the filesystem.
+type ResultLoader struct{}
+
+// Ensure interface compliance
+var _ ports.ExecutionResultLoader = (*ResultLoader)(nil)
+
+// NewResultLoader creates a new result loader.
+func NewResultLoader() *ResultLoader {
+	return &ResultLoader{}
+}
+
+// DiscoverResults expands paths into result files. Files are used as given;
+// directories are searched recursively for *.json files, plain or compressed
+// as *.json.gz or *.json.zst, that hold an execution result, skipping other
+// JSON such as cassettes or bench reports, and run directories of a work
+// directory still being written.
+func (l *ResultLoader) DiscoverResults(paths []string) ([]string, error) {
+	var results []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			results = append(results, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && rundir.IsStaging(d.Name()) {
+				return filepath.SkipDir // run directory still being written
+			}
+			if !d.IsDir() && strings.HasSuffix(compression.TrimExtension(d.Name()), ".json") && isResultFile(p) {
+				results = append(results, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s for results: %w", path, err)
+		}
+	}
+	sort.Strings(results)
+	return results, nil
+}
+
+// LoadResult reads a result file written by any supported schema version,
+// gzip- or zstd-compressed or not, or the result of a run directory written
+// with --work-dir. The result of a run directory is checked against its
+// manifest first.
+func (l *ResultLoader) LoadResult(path string) (*execution.ExecutionResult, error) {
+	path = rundir.ResultPath(path)
+	if dir := filepath.Dir(path); filepath.Base(path) == rundir.ResultsFile && rundir.IsRunDir(dir) {
+		if err := rundir.Verify(dir, rundir.ResultsFile); err != nil {
+			return nil, err
+		}
+	}
+	data, err := compression.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result: %w", err)
+	}
+	result, err := execution.DecodeExecutionResult(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return result, nil
+}
+
+// isResultFile reports whether the JSON file at path is an execution result.
+func isResultFile(path string) bool {
+	data, err := compression.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var header struct {
+		ExecutionID *json.RawMessage `json:"execution_id"`
+		ProfileName *string          `json:"profile_name"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return false
+	}
+	return header.ExecutionID != nil && header.ProfileName != nil
+}
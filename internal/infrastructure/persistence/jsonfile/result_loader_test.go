@@ -0,0 +1,81 @@
+package jsonfile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/rundir"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultLoader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	result := execution.NewExecutionResult("web", "1.0.0")
+	result.Environment = "prod"
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "prod"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "prod", "run.json"), data, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cassette.json"), []byte(`{"interactions": []}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), data, 0o600))
+
+	loader := NewResultLoader()
+	files, err := loader.DiscoverResults([]string{dir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "prod", "run.json")}, files)
+
+	loaded, err := loader.LoadResult(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, "web", loaded.ProfileName)
+	assert.Equal(t, "prod", loaded.Environment)
+	assert.Equal(t, result.ExecutionID, loaded.ExecutionID)
+
+	_, err = loader.LoadResult(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestResultLoader_RunDirectories(t *testing.T) {
+	t.Parallel()
+
+	workDir := t.TempDir()
+	result := execution.NewExecutionResult("web", "1.0.0")
+	run, err := rundir.Create(workDir)
+	require.NoError(t, err)
+	runDir, err := run.Commit(result)
+	require.NoError(t, err)
+
+	// A run directory still being written is skipped
+	pending, err := rundir.Create(workDir)
+	require.NoError(t, err)
+	defer pending.Discard()
+	staging, err := filepath.Glob(filepath.Join(workDir, ".run-*"))
+	require.NoError(t, err)
+	require.Len(t, staging, 1)
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(staging[0], rundir.ResultsFile), data, 0o600))
+
+	loader := NewResultLoader()
+	files, err := loader.DiscoverResults([]string{workDir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(runDir, rundir.ResultsFile)}, files)
+
+	loaded, err := loader.LoadResult(runDir)
+	require.NoError(t, err)
+	assert.Equal(t, result.ExecutionID, loaded.ExecutionID)
+
+	// A result changed after the run no longer matches the manifest
+	result.Environment = "prod"
+	data, err = json.Marshal(result)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, rundir.ResultsFile), data, 0o600))
+	_, err = loader.LoadResult(files[0])
+	assert.ErrorIs(t, err, rundir.ErrManifestMismatch)
+}
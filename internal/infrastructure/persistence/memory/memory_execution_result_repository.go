@@ -2,9 +2,14 @@
 package memory
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,86 +23,276 @@ var _ repositories.ExecutionResultRepository = (*ExecutionResultRepository)(nil)
 
 // ExecutionResultRepository is an in-memory implementation of ExecutionResultRepository.
 // Useful for testing and ephemeral storage.
+//
+// Results are stored serialized, as a database would store them: callers get
+// independent copies and may keep modifying what they saved.
 type ExecutionResultRepository struct {
-	results map[uuid.UUID]*execution.ExecutionResult
+	results map[resultKey]*storedResult
 	mu      sync.RWMutex
 }
 
+// resultKey identifies a result within its namespace.
+type resultKey struct {
+	namespace string
+	id        uuid.UUID
+}
+
+// storedResult is a serialized result with the fields queries filter on.
+type storedResult struct {
+	startTime   time.Time
+	namespace   string
+	profileName string
+	environment string
+	data        []byte
+	version     int
+	id          uuid.UUID
+}
+
 // NewExecutionResultRepository creates a new in-memory repository.
 func NewExecutionResultRepository() *ExecutionResultRepository {
 	return &ExecutionResultRepository{
-		results: make(map[uuid.UUID]*execution.ExecutionResult),
+		results: make(map[resultKey]*storedResult),
 	}
 }
 
 // Save persists an execution result.
-func (r *ExecutionResultRepository) Save(_ context.Context, result *execution.ExecutionResult) error {
+func (r *ExecutionResultRepository) Save(ctx context.Context, result *execution.ExecutionResult) error {
+	return r.SaveBatch(ctx, []*execution.ExecutionResult{result})
+}
+
+// SaveBatch persists several results atomically.
+func (r *ExecutionResultRepository) SaveBatch(ctx context.Context, results []*execution.ExecutionResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	namespace := repositories.NamespaceFromContext(ctx)
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Store the result
-	// In a real DB, we'd serialize here. In memory, we store the pointer.
-	// Callers should not modify the result after saving if they want consistency.
-	r.results[result.GetID().UUID()] = result
+	// Check every result before writing any, so a conflict leaves the store
+	// and the callers' versions untouched.
+	writes := make([]*storedResult, len(results))
+	bumps := make([]bool, len(results))
+	seen := make(map[uuid.UUID]bool, len(results))
+	for i, result := range results {
+		id := result.GetID().UUID()
+		if seen[id] {
+			return fmt.Errorf("%w: %s saved twice in one batch", repositories.ErrVersionConflict, id)
+		}
+		seen[id] = true
+
+		stored, exists := r.results[resultKey{namespace, id}]
+		if !exists {
+			record, err := newStoredResult(namespace, result, result.GetVersion())
+			if err != nil {
+				return err
+			}
+			writes[i] = record
+			continue
+		}
+		if result.GetVersion() != stored.version {
+			return fmt.Errorf("%w: %s is at version %d, saved at %d", repositories.ErrVersionConflict, id, stored.version, result.GetVersion())
+		}
+
+		unchanged, err := newStoredResult(namespace, result, result.GetVersion())
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(unchanged.data, stored.data) {
+			continue // retried write
+		}
+		record, err := newStoredResult(namespace, result, result.GetVersion()+1)
+		if err != nil {
+			return err
+		}
+		writes[i] = record
+		bumps[i] = true
+	}
+
+	for i, record := range writes {
+		if record == nil {
+			continue
+		}
+		r.results[resultKey{record.namespace, record.id}] = record
+		if bumps[i] {
+			results[i].IncrementVersion()
+		}
+	}
 	return nil
 }
 
 // FindByID retrieves an execution result by its unique ID.
-func (r *ExecutionResultRepository) FindByID(_ context.Context, id uuid.UUID) (*execution.ExecutionResult, error) {
+func (r *ExecutionResultRepository) FindByID(ctx context.Context, id uuid.UUID) (*execution.ExecutionResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
-	defer r.mu.RUnlock()
+	stored, ok := r.results[resultKey{repositories.NamespaceFromContext(ctx), id}]
+	r.mu.RUnlock()
 
-	result, ok := r.results[id]
 	if !ok {
-		return nil, fmt.Errorf("execution result not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", repositories.ErrResultNotFound, id)
 	}
-	return result, nil
+	return stored.decode()
 }
 
 // FindByProfile retrieves recent execution results for a specific profile.
-func (r *ExecutionResultRepository) FindByProfile(_ context.Context, profileName string, limit int) ([]*execution.ExecutionResult, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+func (r *ExecutionResultRepository) FindByProfile(ctx context.Context, profileName string, limit int) ([]*execution.ExecutionResult, error) {
+	matches := r.match(ctx, repositories.ResultQuery{ProfileName: profileName})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return decodeAll(ctx, matches)
+}
 
-	var matches []*execution.ExecutionResult
-	for _, res := range r.results {
-		if res.ProfileName == profileName {
-			matches = append(matches, res)
-		}
+// FindBetween retrieves execution results for a profile within a time range.
+func (r *ExecutionResultRepository) FindBetween(ctx context.Context, profileName string, start, end time.Time) ([]*execution.ExecutionResult, error) {
+	return decodeAll(ctx, r.match(ctx, repositories.ResultQuery{ProfileName: profileName, Since: start, Until: end}))
+}
+
+// List returns one page of the results matching query, newest first. Page
+// tokens mark a position in that order rather than an offset, so results
+// saved between pages neither repeat nor shift later pages.
+func (r *ExecutionResultRepository) List(ctx context.Context, query repositories.ResultQuery) (*repositories.ResultPage, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = repositories.DefaultPageSize
 	}
 
-	// Sort by start time descending (newest first)
-	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].StartTime.After(matches[j].StartTime)
-	})
+	matches := r.match(ctx, query)
+	if query.PageToken != "" {
+		after, err := decodePageToken(query.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		i := sort.Search(len(matches), func(i int) bool { return newerFirst(after, matches[i]) })
+		matches = matches[i:]
+	}
 
-	if limit > 0 && len(matches) > limit {
+	page := &repositories.ResultPage{}
+	if len(matches) > limit {
 		matches = matches[:limit]
+		page.NextPageToken = encodePageToken(matches[limit-1])
 	}
 
-	return matches, nil
+	results, err := decodeAll(ctx, matches)
+	if err != nil {
+		return nil, err
+	}
+	page.Results = results
+	return page, nil
 }
 
-// FindBetween retrieves execution results for a profile within a time range.
-func (r *ExecutionResultRepository) FindBetween(_ context.Context, profileName string, start, end time.Time) ([]*execution.ExecutionResult, error) {
+// match returns the stored results of the context's namespace matching
+// query, newest first.
+func (r *ExecutionResultRepository) match(ctx context.Context, query repositories.ResultQuery) []*storedResult {
+	namespace := repositories.NamespaceFromContext(ctx)
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	var matches []*execution.ExecutionResult
-	for _, res := range r.results {
-		if res.ProfileName == profileName {
-			// Check if start time is within range [start, end]
-			if (res.StartTime.Equal(start) || res.StartTime.After(start)) &&
-				(res.StartTime.Equal(end) || res.StartTime.Before(end)) {
-				matches = append(matches, res)
-			}
+	var matches []*storedResult
+	for _, stored := range r.results {
+		if stored.namespace != namespace {
+			continue
+		}
+		if query.ProfileName != "" && stored.profileName != query.ProfileName {
+			continue
 		}
+		if query.Environment != "" && stored.environment != query.Environment {
+			continue
+		}
+		if !query.Since.IsZero() && stored.startTime.Before(query.Since) {
+			continue
+		}
+		if !query.Until.IsZero() && stored.startTime.After(query.Until) {
+			continue
+		}
+		matches = append(matches, stored)
 	}
 
-	// Sort by start time descending
-	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].StartTime.After(matches[j].StartTime)
-	})
+	sort.Slice(matches, func(i, j int) bool { return newerFirst(matches[i], matches[j]) })
+	return matches
+}
 
-	return matches, nil
+// newerFirst orders results by start time descending, then by ID descending so results
+// starting at the same time have a stable order across pages.
+func newerFirst(a, b *storedResult) bool {
+	if !a.startTime.Equal(b.startTime) {
+		return a.startTime.After(b.startTime)
+	}
+	return strings.Compare(a.id.String(), b.id.String()) > 0
+}
+
+func newStoredResult(namespace string, result *execution.ExecutionResult, version int) (*storedResult, error) {
+	// The outer Version shadows the result's own, so the caller's result is
+	// left unchanged until the whole batch is accepted.
+	snapshot := struct {
+		*execution.ExecutionResult
+		Version int `json:"version"`
+	}{result, version}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize execution result %s: %w", result.GetID(), err)
+	}
+	return &storedResult{
+		namespace:   namespace,
+		id:          result.GetID().UUID(),
+		version:     version,
+		startTime:   result.StartTime,
+		profileName: result.ProfileName,
+		environment: result.Environment,
+		data:        data,
+	}, nil
+}
+
+func (s *storedResult) decode() (*execution.ExecutionResult, error) {
+	result, err := execution.DecodeExecutionResult(s.data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored execution result %s: %w", s.id, err)
+	}
+	return result, nil
+}
+
+func decodeAll(ctx context.Context, stored []*storedResult) ([]*execution.ExecutionResult, error) {
+	results := make([]*execution.ExecutionResult, 0, len(stored))
+	for _, s := range stored {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := s.decode()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// encodePageToken marks the position of the last result of a page.
+func encodePageToken(last *storedResult) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(last.startTime.UnixNano(), 10) + "/" + last.id.String()))
+}
+
+func decodePageToken(token string) (*storedResult, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	nanos, id, ok := strings.Cut(string(raw), "/")
+	if !ok {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	return &storedResult{startTime: time.Unix(0, n), id: parsed}, nil
 }
@@ -2,10 +2,13 @@ package memory
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
 	"github.com/reglet-dev/reglet/internal/domain/values"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -94,3 +97,197 @@ func TestMemoryExecutionResultRepository_FindBetween(t *testing.T) {
 	require.Len(t, results, 1)
 	assert.Equal(t, r2.GetID(), results[0].GetID())
 }
+
+func TestMemoryExecutionResultRepository_OptimisticLocking(t *testing.T) {
+	repo := NewExecutionResultRepository()
+	ctx := context.Background()
+
+	result := execution.NewExecutionResult("profile-a", "1.0")
+	require.NoError(t, repo.Save(ctx, result))
+	assert.Equal(t, 1, result.GetVersion())
+
+	// Retried write of the same content is a no-op
+	require.NoError(t, repo.Save(ctx, result))
+	assert.Equal(t, 1, result.GetVersion())
+
+	// Two writers read version 1; the first update wins
+	first, err := repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+	second, err := repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+
+	first.Environment = "prod"
+	require.NoError(t, repo.Save(ctx, first))
+	assert.Equal(t, 2, first.GetVersion())
+
+	second.Environment = "staging"
+	err = repo.Save(ctx, second)
+	require.ErrorIs(t, err, repositories.ErrVersionConflict)
+	assert.Equal(t, 1, second.GetVersion(), "a rejected save leaves the version alone")
+
+	stored, err := repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+	assert.Equal(t, "prod", stored.Environment)
+	assert.Equal(t, 2, stored.GetVersion())
+
+	// Stored results are copies
+	first.Environment = "changed"
+	stored, err = repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+	assert.Equal(t, "prod", stored.Environment)
+
+	_, err = repo.FindByID(ctx, values.NewExecutionID().UUID())
+	assert.ErrorIs(t, err, repositories.ErrResultNotFound)
+}
+
+func TestMemoryExecutionResultRepository_SaveBatchIsAtomic(t *testing.T) {
+	repo := NewExecutionResultRepository()
+	ctx := context.Background()
+
+	existing := execution.NewExecutionResult("profile-a", "1.0")
+	require.NoError(t, repo.Save(ctx, existing))
+	stale, err := repo.FindByID(ctx, existing.GetID().UUID())
+	require.NoError(t, err)
+	existing.Environment = "prod"
+	require.NoError(t, repo.Save(ctx, existing))
+
+	fresh := execution.NewExecutionResult("profile-a", "1.0")
+	stale.Environment = "staging"
+	err = repo.SaveBatch(ctx, []*execution.ExecutionResult{fresh, stale})
+	require.ErrorIs(t, err, repositories.ErrVersionConflict)
+
+	_, err = repo.FindByID(ctx, fresh.GetID().UUID())
+	assert.ErrorIs(t, err, repositories.ErrResultNotFound, "no result of a failed batch is saved")
+
+	err = repo.SaveBatch(ctx, []*execution.ExecutionResult{fresh, fresh})
+	assert.ErrorIs(t, err, repositories.ErrVersionConflict)
+
+	require.NoError(t, repo.SaveBatch(ctx, []*execution.ExecutionResult{fresh, existing}))
+	_, err = repo.FindByID(ctx, fresh.GetID().UUID())
+	assert.NoError(t, err)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, repo.Save(cancelled, execution.NewExecutionResult("profile-a", "1.0")), context.Canceled)
+}
+
+func TestMemoryExecutionResultRepository_List(t *testing.T) {
+	repo := NewExecutionResultRepository()
+	ctx := context.Background()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var prod []*execution.ExecutionResult
+	for i := 0; i < 5; i++ {
+		r := execution.NewExecutionResult("profile-a", "1.0")
+		r.Environment = "prod"
+		r.StartTime = start.Add(time.Duration(i) * time.Hour)
+		prod = append(prod, r)
+	}
+	same := execution.NewExecutionResult("profile-a", "1.0")
+	same.Environment = "prod"
+	same.StartTime = prod[2].StartTime // ties are ordered by ID
+	prod = append(prod, same)
+	staging := execution.NewExecutionResult("profile-a", "1.0")
+	staging.Environment = "staging"
+	require.NoError(t, repo.SaveBatch(ctx, append(prod, staging, execution.NewExecutionResult("profile-b", "1.0"))))
+
+	var listed []string
+	query := repositories.ResultQuery{ProfileName: "profile-a", Environment: "prod", Limit: 4}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		page, err := repo.List(ctx, query)
+		require.NoError(t, err)
+		for _, r := range page.Results {
+			listed = append(listed, r.GetID().String())
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		query.PageToken = page.NextPageToken
+
+		// A result saved between pages does not shift the next page
+		newer := execution.NewExecutionResult("profile-a", "1.0")
+		newer.Environment = "prod"
+		newer.StartTime = start.Add(24 * time.Hour)
+		require.NoError(t, repo.Save(ctx, newer))
+	}
+
+	require.Len(t, listed, 6)
+	assert.Equal(t, prod[4].GetID().String(), listed[0], "newest first")
+	assert.Equal(t, prod[0].GetID().String(), listed[5])
+	assert.ElementsMatch(t, []string{prod[2].GetID().String(), same.GetID().String()}, listed[2:4])
+
+	page, err := repo.List(ctx, repositories.ResultQuery{Since: start.Add(time.Hour), Until: start.Add(3 * time.Hour)})
+	require.NoError(t, err)
+	assert.Len(t, page.Results, 4)
+	assert.Empty(t, page.NextPageToken)
+
+	_, err = repo.List(ctx, repositories.ResultQuery{PageToken: "not-a-token"})
+	assert.Error(t, err)
+}
+
+func TestMemoryExecutionResultRepository_ConcurrentUpdates(t *testing.T) {
+	repo := NewExecutionResultRepository()
+	ctx := context.Background()
+
+	result := execution.NewExecutionResult("profile-a", "1.0")
+	require.NoError(t, repo.Save(ctx, result))
+
+	// Each writer retries on conflict, so every update lands exactly once
+	const writers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for {
+				current, err := repo.FindByID(ctx, result.GetID().UUID())
+				if !assert.NoError(t, err) {
+					return
+				}
+				current.Controls = append(current.Controls, execution.ControlResult{ID: fmt.Sprintf("c%d", i)})
+				err = repo.Save(ctx, current)
+				if err == nil {
+					return
+				}
+				if !assert.ErrorIs(t, err, repositories.ErrVersionConflict) {
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stored, err := repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+	assert.Len(t, stored.Controls, writers)
+	assert.Equal(t, 1+writers, stored.GetVersion())
+}
+
+func TestMemoryExecutionResultRepository_NamespaceIsolation(t *testing.T) {
+	repo := NewExecutionResultRepository()
+	teamA := repositories.WithNamespace(context.Background(), "team-a")
+	teamB := repositories.WithNamespace(context.Background(), "team-b")
+
+	result := execution.NewExecutionResult("profile-a", "1.0")
+	require.NoError(t, repo.Save(teamA, result))
+
+	_, err := repo.FindByID(teamB, result.GetID().UUID())
+	require.ErrorIs(t, err, repositories.ErrResultNotFound)
+	_, err = repo.FindByID(context.Background(), result.GetID().UUID())
+	require.ErrorIs(t, err, repositories.ErrResultNotFound, "the default namespace is a namespace too")
+
+	found, err := repo.FindByProfile(teamB, "profile-a", 0)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	// The same ID saved in another namespace is a separate result
+	copied, err := repo.FindByID(teamA, result.GetID().UUID())
+	require.NoError(t, err)
+	copied.Environment = "copy"
+	require.NoError(t, repo.Save(teamB, copied))
+
+	original, err := repo.FindByID(teamA, result.GetID().UUID())
+	require.NoError(t, err)
+	assert.Empty(t, original.Environment)
+}
@@ -0,0 +1,410 @@
+// Package postgres provides PostgreSQL implementations of domain repositories,
+// for controllers and servers that keep results durably and share them
+// between users.
+package postgres
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+)
+
+// Ensure interface compliance
+var _ repositories.ExecutionResultRepository = (*ExecutionResultRepository)(nil)
+
+// Config configures the connection pool.
+type Config struct {
+	// DSN is a libpq connection string or URL. Settings it leaves out fall
+	// back to the PG* environment variables.
+	DSN string
+	// MaxConnLifetime and MaxConnIdleTime recycle connections (0 = pgx default).
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+	// MaxConns and MinConns size the pool (0 = pgx default).
+	MaxConns int32
+	MinConns int32
+}
+
+// ExecutionResultRepository stores execution results in PostgreSQL.
+//
+// Each result is stored whole as JSONB next to the columns queries filter on.
+// The schema is migrated on first use, so pointing reglet at an empty
+// database is enough to start storing results.
+type ExecutionResultRepository struct {
+	pool     *pgxpool.Pool
+	mu       sync.Mutex // guards migrated
+	migrated bool
+}
+
+// Open creates a repository over a new connection pool. Connections are made
+// on first use, so Open succeeds while the database is unreachable.
+func Open(ctx context.Context, cfg Config) (*ExecutionResultRepository, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid postgres connection settings: %w", err)
+	}
+	if cfg.MaxConns > 0 {
+		poolCfg.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		poolCfg.MinConns = cfg.MinConns
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres connection pool: %w", err)
+	}
+	return &ExecutionResultRepository{pool: pool}, nil
+}
+
+// Close closes all connections of the pool.
+func (r *ExecutionResultRepository) Close() {
+	r.pool.Close()
+}
+
+// migrate applies pending migrations once per repository. A failure is
+// retried on the next call, so a database that comes up late is picked up.
+func (r *ExecutionResultRepository) migrate(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.migrated {
+		return nil
+	}
+	if err := Migrate(ctx, r.pool); err != nil {
+		return err
+	}
+	r.migrated = true
+	return nil
+}
+
+// Save persists an execution result.
+func (r *ExecutionResultRepository) Save(ctx context.Context, result *execution.ExecutionResult) error {
+	return r.SaveBatch(ctx, []*execution.ExecutionResult{result})
+}
+
+// SaveBatch persists several results atomically in one transaction.
+func (r *ExecutionResultRepository) SaveBatch(ctx context.Context, results []*execution.ExecutionResult) error {
+	if err := r.migrate(ctx); err != nil {
+		return err
+	}
+
+	namespace := repositories.NamespaceFromContext(ctx)
+
+	// Callers' versions are bumped only once the transaction has committed.
+	bumps := make([]bool, len(results))
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		seen := make(map[uuid.UUID]bool, len(results))
+		for i, result := range results {
+			id := result.GetID().UUID()
+			if seen[id] {
+				return fmt.Errorf("%w: %s saved twice in one batch", repositories.ErrVersionConflict, id)
+			}
+			seen[id] = true
+
+			bumped, err := saveResult(ctx, tx, namespace, result)
+			if err != nil {
+				return err
+			}
+			bumps[i] = bumped
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, bumped := range bumps {
+		if bumped {
+			results[i].IncrementVersion()
+		}
+	}
+	return nil
+}
+
+// saveResult writes one result to namespace within tx and reports whether its
+// version was incremented.
+func saveResult(ctx context.Context, tx pgx.Tx, namespace string, result *execution.ExecutionResult) (bool, error) {
+	id := result.GetID().UUID()
+	data, err := snapshot(result, result.GetVersion())
+	if err != nil {
+		return false, err
+	}
+
+	// Lock the row so concurrent writers of the same result serialize here.
+	var stored int
+	var unchanged bool
+	err = tx.QueryRow(ctx,
+		"SELECT version, result = $3::jsonb FROM execution_results WHERE namespace = $1 AND execution_id = $2 FOR UPDATE",
+		namespace, id, string(data),
+	).Scan(&stored, &unchanged)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		tag, err := tx.Exec(ctx,
+			`INSERT INTO execution_results (namespace, execution_id, profile_name, environment, start_time, version, result)
+			VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb)
+			ON CONFLICT (namespace, execution_id) DO NOTHING`,
+			namespace, id, result.ProfileName, result.Environment, result.StartTime, result.GetVersion(), string(data),
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to insert execution result %s: %w", id, err)
+		}
+		if tag.RowsAffected() == 0 {
+			return false, fmt.Errorf("%w: %s was inserted concurrently", repositories.ErrVersionConflict, id)
+		}
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to read execution result %s: %w", id, err)
+	}
+
+	if result.GetVersion() != stored {
+		return false, fmt.Errorf("%w: %s is at version %d, saved at %d", repositories.ErrVersionConflict, id, stored, result.GetVersion())
+	}
+	if unchanged {
+		return false, nil // retried write
+	}
+
+	data, err = snapshot(result, stored+1)
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE execution_results
+		SET profile_name = $3, environment = $4, start_time = $5, version = $6, result = $7::jsonb, updated_at = now()
+		WHERE namespace = $1 AND execution_id = $2`,
+		namespace, id, result.ProfileName, result.Environment, result.StartTime, stored+1, string(data),
+	); err != nil {
+		return false, fmt.Errorf("failed to update execution result %s: %w", id, err)
+	}
+	return true, nil
+}
+
+// FindByID retrieves an execution result by its unique ID.
+func (r *ExecutionResultRepository) FindByID(ctx context.Context, id uuid.UUID) (*execution.ExecutionResult, error) {
+	if err := r.migrate(ctx); err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err := r.pool.QueryRow(ctx,
+		"SELECT result FROM execution_results WHERE namespace = $1 AND execution_id = $2",
+		repositories.NamespaceFromContext(ctx), id,
+	).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", repositories.ErrResultNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read execution result %s: %w", id, err)
+	}
+	return decode(id, data)
+}
+
+// FindByProfile retrieves recent execution results for a specific profile.
+func (r *ExecutionResultRepository) FindByProfile(ctx context.Context, profileName string, limit int) ([]*execution.ExecutionResult, error) {
+	rows, err := r.query(ctx, repositories.ResultQuery{ProfileName: profileName}, nil, max(limit, 0))
+	if err != nil {
+		return nil, err
+	}
+	return resultsOf(rows), nil
+}
+
+// FindBetween retrieves execution results for a profile within a time range.
+func (r *ExecutionResultRepository) FindBetween(ctx context.Context, profileName string, start, end time.Time) ([]*execution.ExecutionResult, error) {
+	rows, err := r.query(ctx, repositories.ResultQuery{ProfileName: profileName, Since: start, Until: end}, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return resultsOf(rows), nil
+}
+
+// List returns one page of the results matching query, newest first. Page
+// tokens mark a position in that order rather than an offset, so results
+// saved between pages neither repeat nor shift later pages.
+func (r *ExecutionResultRepository) List(ctx context.Context, query repositories.ResultQuery) (*repositories.ResultPage, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = repositories.DefaultPageSize
+	}
+
+	var after *position
+	if query.PageToken != "" {
+		var err error
+		if after, err = decodePageToken(query.PageToken); err != nil {
+			return nil, err
+		}
+	}
+
+	// One extra row tells whether there is a next page.
+	rows, err := r.query(ctx, query, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &repositories.ResultPage{}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.NextPageToken = encodePageToken(rows[limit-1].position)
+	}
+	page.Results = resultsOf(rows)
+	return page, nil
+}
+
+// position is a place in newest-first order.
+type position struct {
+	startTime time.Time
+	id        uuid.UUID
+}
+
+// row is a decoded result with its position.
+type row struct {
+	result *execution.ExecutionResult
+	position
+}
+
+func (r *ExecutionResultRepository) query(ctx context.Context, query repositories.ResultQuery, after *position, limit int) ([]row, error) {
+	if err := r.migrate(ctx); err != nil {
+		return nil, err
+	}
+
+	sql, args := buildListQuery(repositories.NamespaceFromContext(ctx), query, after, limit)
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution results: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []row
+	for rows.Next() {
+		var data []byte
+		var pos position
+		if err := rows.Scan(&data, &pos.startTime, &pos.id); err != nil {
+			return nil, fmt.Errorf("failed to read execution result: %w", err)
+		}
+		result, err := decode(pos.id, data)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, row{result: result, position: pos})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query execution results: %w", err)
+	}
+	return matches, nil
+}
+
+// buildListQuery returns the SQL and arguments selecting the results of
+// namespace matching query after the given position, newest first. A limit of
+// 0 selects all.
+func buildListQuery(namespace string, query repositories.ResultQuery, after *position, limit int) (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, values ...any) {
+		for _, v := range values {
+			args = append(args, v)
+			condition = strings.Replace(condition, "?", "$"+strconv.Itoa(len(args)), 1)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	add("namespace = ?", namespace)
+	if query.ProfileName != "" {
+		add("profile_name = ?", query.ProfileName)
+	}
+	if query.Environment != "" {
+		add("environment = ?", query.Environment)
+	}
+	if !query.Since.IsZero() {
+		add("start_time >= ?", query.Since)
+	}
+	if !query.Until.IsZero() {
+		add("start_time <= ?", query.Until)
+	}
+	if after != nil {
+		add("(start_time, execution_id) < (?::timestamptz, ?::uuid)", after.startTime, after.id)
+	}
+
+	var sql strings.Builder
+	sql.WriteString("SELECT result, start_time, execution_id FROM execution_results WHERE ")
+	sql.WriteString(strings.Join(conditions, " AND "))
+	sql.WriteString(" ORDER BY start_time DESC, execution_id DESC")
+	if limit > 0 {
+		args = append(args, limit)
+		sql.WriteString(" LIMIT $" + strconv.Itoa(len(args)))
+	}
+	return sql.String(), args
+}
+
+// snapshot serializes result as stored at version.
+func snapshot(result *execution.ExecutionResult, version int) ([]byte, error) {
+	// The outer Version shadows the result's own, so the caller's result is
+	// left unchanged until the transaction commits.
+	data, err := json.Marshal(struct {
+		*execution.ExecutionResult
+		Version int `json:"version"`
+	}{result, version})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize execution result %s: %w", result.GetID(), err)
+	}
+	return data, nil
+}
+
+func decode(id uuid.UUID, data []byte) (*execution.ExecutionResult, error) {
+	result, err := execution.DecodeExecutionResult(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored execution result %s: %w", id, err)
+	}
+	return result, nil
+}
+
+func resultsOf(rows []row) []*execution.ExecutionResult {
+	results := make([]*execution.ExecutionResult, len(rows))
+	for i, r := range rows {
+		results[i] = r.result
+	}
+	return results
+}
+
+// encodePageToken marks the position of the last result of a page. Stored
+// start times have microsecond precision, as PostgreSQL keeps them.
+func encodePageToken(last position) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(last.startTime.UnixMicro(), 10) + "/" + last.id.String()))
+}
+
+func decodePageToken(token string) (*position, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	micros, id, ok := strings.Cut(string(raw), "/")
+	if !ok {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	n, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil, repositories.ErrInvalidPageToken
+	}
+	return &position{startTime: time.UnixMicro(n), id: parsed}, nil
+}
@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/repositories"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildListQuery(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	after := &position{startTime: since.Add(time.Hour), id: uuid.New()}
+
+	sql, args := buildListQuery("team-a", repositories.ResultQuery{ProfileName: "web", Environment: "prod", Since: since}, after, 11)
+
+	assert.Equal(t, "SELECT result, start_time, execution_id FROM execution_results"+
+		" WHERE namespace = $1 AND profile_name = $2 AND environment = $3 AND start_time >= $4"+
+		" AND (start_time, execution_id) < ($5::timestamptz, $6::uuid)"+
+		" ORDER BY start_time DESC, execution_id DESC LIMIT $7", sql)
+	assert.Equal(t, []any{"team-a", "web", "prod", since, after.startTime, after.id, 11}, args)
+}
+
+func TestBuildListQuery_NamespaceOnly(t *testing.T) {
+	t.Parallel()
+
+	sql, args := buildListQuery(repositories.DefaultNamespace, repositories.ResultQuery{}, nil, 0)
+
+	assert.Equal(t, "SELECT result, start_time, execution_id FROM execution_results"+
+		" WHERE namespace = $1 ORDER BY start_time DESC, execution_id DESC", sql)
+	assert.Equal(t, []any{"default"}, args)
+}
+
+func TestPageToken_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	pos := position{startTime: time.Date(2026, 3, 4, 5, 6, 7, 123456000, time.UTC), id: uuid.New()}
+
+	decoded, err := decodePageToken(encodePageToken(pos))
+	require.NoError(t, err)
+	assert.True(t, pos.startTime.Equal(decoded.startTime))
+	assert.Equal(t, pos.id, decoded.id)
+
+	for _, token := range []string{"%%%", "bm8tc2xhc2g", "eC95"} {
+		_, err := decodePageToken(token)
+		assert.ErrorIs(t, err, repositories.ErrInvalidPageToken, token)
+	}
+}
+
+func TestReadMigrations(t *testing.T) {
+	t.Parallel()
+
+	migrations, err := readMigrations(fstest.MapFS{
+		"m/0002_second.sql": {Data: []byte("SELECT 2")},
+		"m/0001_first.sql":  {Data: []byte("SELECT 1")},
+		"m/README.md":       {Data: []byte("ignored")},
+	}, "m")
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, migration{version: 1, name: "0001_first.sql", sql: "SELECT 1"}, migrations[0])
+	assert.Equal(t, 2, migrations[1].version)
+
+	_, err = readMigrations(fstest.MapFS{
+		"m/0001_a.sql": {Data: []byte("SELECT 1")},
+		"m/1_b.sql":    {Data: []byte("SELECT 1")},
+	}, "m")
+	assert.ErrorContains(t, err, "share version 1")
+
+	_, err = readMigrations(fstest.MapFS{"m/first.sql": {}}, "m")
+	assert.ErrorContains(t, err, "positive version number")
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	t.Parallel()
+
+	migrations, err := loadMigrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, 1, migrations[0].version)
+	assert.Equal(t, len(migrations), migrations[len(migrations)-1].version, "versions have no gaps")
+}
+
+func TestOpen_InvalidDSN(t *testing.T) {
+	t.Parallel()
+
+	_, err := Open(context.Background(), Config{DSN: "postgres://%zz"})
+	assert.ErrorContains(t, err, "invalid postgres connection settings")
+}
+
+// openTestRepository connects to the database in REGLET_TEST_POSTGRES_DSN,
+// skipping the test when it is not set. Tables are dropped so every test
+// starts from migrations.
+func openTestRepository(t *testing.T) *ExecutionResultRepository {
+	t.Helper()
+
+	dsn := os.Getenv("REGLET_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("REGLET_TEST_POSTGRES_DSN not set")
+	}
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, "DROP TABLE IF EXISTS execution_results, reglet_schema_migrations")
+	require.NoError(t, err)
+	pool.Close()
+
+	repo, err := Open(ctx, Config{DSN: dsn, MaxConns: 4})
+	require.NoError(t, err)
+	t.Cleanup(repo.Close)
+	return repo
+}
+
+// These tests share one database, so they do not run in parallel.
+
+func TestExecutionResultRepository_Postgres_SaveAndFind(t *testing.T) {
+	repo := openTestRepository(t)
+	ctx := context.Background()
+
+	result := execution.NewExecutionResult("web", "1.0.0")
+	result.Environment = "prod"
+	result.Controls = []execution.ControlResult{{ID: "c1", Status: values.StatusPass}}
+	result.Finalize()
+	require.NoError(t, repo.Save(ctx, result))
+	require.NoError(t, repo.Save(ctx, result), "saving an unchanged result is a no-op")
+	assert.Equal(t, 1, result.GetVersion())
+
+	found, err := repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+	assert.Equal(t, "prod", found.Environment)
+	require.Len(t, found.Controls, 1)
+
+	_, err = repo.FindByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, repositories.ErrResultNotFound)
+
+	// Migrations are applied once; a second repository finds nothing to do.
+	require.NoError(t, Migrate(ctx, repo.pool))
+}
+
+func TestExecutionResultRepository_Postgres_OptimisticLocking(t *testing.T) {
+	repo := openTestRepository(t)
+	ctx := context.Background()
+
+	result := execution.NewExecutionResult("web", "1.0.0")
+	require.NoError(t, repo.Save(ctx, result))
+
+	stale, err := repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+
+	result.Environment = "qa"
+	require.NoError(t, repo.Save(ctx, result))
+	assert.Equal(t, 2, result.GetVersion())
+
+	stale.Environment = "prod"
+	assert.ErrorIs(t, repo.Save(ctx, stale), repositories.ErrVersionConflict)
+
+	// A conflicting batch saves nothing.
+	fresh := execution.NewExecutionResult("web", "1.0.0")
+	err = repo.SaveBatch(ctx, []*execution.ExecutionResult{fresh, stale})
+	require.ErrorIs(t, err, repositories.ErrVersionConflict)
+	_, err = repo.FindByID(ctx, fresh.GetID().UUID())
+	assert.ErrorIs(t, err, repositories.ErrResultNotFound)
+
+	stored, err := repo.FindByID(ctx, result.GetID().UUID())
+	require.NoError(t, err)
+	assert.Equal(t, "qa", stored.Environment)
+	assert.Equal(t, 2, stored.GetVersion())
+}
+
+func TestExecutionResultRepository_Postgres_List(t *testing.T) {
+	repo := openTestRepository(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var batch []*execution.ExecutionResult
+	for i := range 5 {
+		result := execution.NewExecutionResult("web", "1.0.0")
+		result.Environment = "prod"
+		result.StartTime = base.Add(time.Duration(i) * time.Hour)
+		batch = append(batch, result)
+	}
+	other := execution.NewExecutionResult("web", "1.0.0")
+	other.Environment = "qa"
+	other.StartTime = base
+	batch = append(batch, other)
+	require.NoError(t, repo.SaveBatch(ctx, batch))
+
+	var listed []string
+	query := repositories.ResultQuery{ProfileName: "web", Environment: "prod", Limit: 2}
+	for {
+		page, err := repo.List(ctx, query)
+		require.NoError(t, err)
+		for _, r := range page.Results {
+			listed = append(listed, r.GetID().String())
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		query.PageToken = page.NextPageToken
+	}
+
+	require.Len(t, listed, 5)
+	assert.Equal(t, batch[4].GetID().String(), listed[0], "newest first")
+	assert.Equal(t, batch[0].GetID().String(), listed[4])
+
+	between, err := repo.FindBetween(ctx, "web", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, between, 3)
+
+	recent, err := repo.FindByProfile(ctx, "web", 1)
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+	assert.Equal(t, batch[4].GetID(), recent[0].GetID())
+}
+
+func TestExecutionResultRepository_Postgres_NamespaceIsolation(t *testing.T) {
+	repo := openTestRepository(t)
+	teamA := repositories.WithNamespace(context.Background(), "team-a")
+	teamB := repositories.WithNamespace(context.Background(), "team-b")
+
+	result := execution.NewExecutionResult("web", "1.0.0")
+	require.NoError(t, repo.Save(teamA, result))
+
+	_, err := repo.FindByID(teamB, result.GetID().UUID())
+	require.ErrorIs(t, err, repositories.ErrResultNotFound)
+	page, err := repo.List(teamB, repositories.ResultQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Results)
+
+	// The same ID saved in another namespace is a separate result.
+	copied, err := repo.FindByID(teamA, result.GetID().UUID())
+	require.NoError(t, err)
+	copied.Environment = "team-b-copy"
+	require.NoError(t, repo.Save(teamB, copied))
+
+	original, err := repo.FindByID(teamA, result.GetID().UUID())
+	require.NoError(t, err)
+	assert.Empty(t, original.Environment)
+}
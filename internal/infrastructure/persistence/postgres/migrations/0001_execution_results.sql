@@ -0,0 +1,19 @@
+-- Execution results, stored whole as JSON with the fields queries filter and
+-- order on pulled out into columns.
+CREATE TABLE execution_results (
+    execution_id UUID PRIMARY KEY,
+    profile_name TEXT NOT NULL,
+    environment  TEXT NOT NULL DEFAULT '',
+    start_time   TIMESTAMPTZ NOT NULL,
+    version      INTEGER NOT NULL,
+    result       JSONB NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Listing is newest first, with the ID breaking ties for keyset pagination.
+CREATE INDEX execution_results_profile_idx
+    ON execution_results (profile_name, environment, start_time DESC, execution_id DESC);
+
+CREATE INDEX execution_results_start_time_idx
+    ON execution_results (start_time DESC, execution_id DESC);
@@ -0,0 +1,14 @@
+-- Results belong to a namespace (team or tenant). Execution IDs are unique per
+-- namespace only, so one namespace cannot learn which IDs another holds.
+ALTER TABLE execution_results ADD COLUMN namespace TEXT NOT NULL DEFAULT 'default';
+
+ALTER TABLE execution_results DROP CONSTRAINT execution_results_pkey;
+ALTER TABLE execution_results ADD PRIMARY KEY (namespace, execution_id);
+
+DROP INDEX execution_results_profile_idx;
+CREATE INDEX execution_results_profile_idx
+    ON execution_results (namespace, profile_name, environment, start_time DESC, execution_id DESC);
+
+DROP INDEX execution_results_start_time_idx;
+CREATE INDEX execution_results_start_time_idx
+    ON execution_results (namespace, start_time DESC, execution_id DESC);
@@ -0,0 +1,266 @@
+// Package pluginbuild compiles plugins reproducibly for WASM and for native
+// targets (external process plugins), embedding their version and commit
+// into describe() and recording SHA-256 checksums of the artifacts.
+package pluginbuild
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// ChecksumFile is written to the output directory, listing the artifacts of
+// a build in sha256sum format.
+const ChecksumFile = "SHA256SUMS"
+
+// sdkPackage is the package holding the build metadata variables.
+const sdkPackage = "github.com/reglet-dev/reglet/sdk"
+
+// WASM is the target of WASM plugins. Other targets are GOOS/GOARCH pairs.
+const WASM = "wasm"
+
+// Targets are the supported build targets.
+var Targets = []string{
+	WASM,
+	"linux/amd64", "linux/arm64",
+	"darwin/amd64", "darwin/arm64",
+	"windows/amd64", "windows/arm64",
+}
+
+// Options configure a build.
+type Options struct {
+	OutDir    string   // Artifacts are written to OutDir/<plugin>/
+	Version   string   // Reported by describe() instead of the declared version, when set
+	Commit    string   // Reported by describe()
+	BuildTime string   // Reported by describe(); use the source date to keep builds reproducible
+	GoVersion string   // Pins the Go toolchain (GOTOOLCHAIN=go<version>), when set
+	Targets   []string // Default: wasm
+}
+
+// Artifact is a built plugin file.
+type Artifact struct {
+	Plugin string
+	Target string
+	Path   string // Relative to the output directory
+	SHA256 string
+}
+
+// Discover expands plugin directory arguments. A path ending in "/..." stands
+// for every directory below it holding a main.go, like Go package patterns.
+func Discover(patterns []string) ([]string, error) {
+	var dirs []string
+	for _, pattern := range patterns {
+		root, recursive := strings.CutSuffix(filepath.ToSlash(pattern), "/...")
+		root = filepath.FromSlash(root)
+		if !recursive {
+			if _, err := os.Stat(filepath.Join(root, "main.go")); err != nil {
+				return nil, fmt.Errorf("%s is not a plugin directory: no main.go", pattern)
+			}
+			dirs = append(dirs, filepath.Clean(root))
+			continue
+		}
+
+		found := false
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "main.go")); err == nil {
+				dirs = append(dirs, filepath.Clean(path))
+				found = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", pattern, err)
+		}
+		if !found {
+			return nil, fmt.Errorf("no plugins found in %s", pattern)
+		}
+	}
+	return dirs, nil
+}
+
+// Build compiles the plugin in dir for each target. The plugin is named after
+// its directory. Builds are reproducible: the same sources, options and Go
+// version produce the same bytes.
+func Build(ctx context.Context, dir string, opts Options) ([]Artifact, error) {
+	name, err := values.NewPluginName(filepath.Base(dir))
+	if err != nil {
+		return nil, fmt.Errorf("plugin directory %s: %w", dir, err)
+	}
+	targets := opts.Targets
+	if len(targets) == 0 {
+		targets = []string{WASM}
+	}
+
+	artifacts := make([]Artifact, 0, len(targets))
+	for _, target := range targets {
+		if !slices.Contains(Targets, target) {
+			return nil, fmt.Errorf("unsupported target %q (expected %s)", target, strings.Join(Targets, ", "))
+		}
+		rel := artifactPath(name.String(), target)
+		out := filepath.Join(opts.OutDir, rel)
+		if err := os.MkdirAll(filepath.Dir(out), 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+		if err := compile(ctx, dir, out, target, opts); err != nil {
+			return nil, fmt.Errorf("failed to build %s for %s: %w", name, target, err)
+		}
+		sum, err := fileSHA256(out)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, Artifact{Plugin: name.String(), Target: target, Path: filepath.ToSlash(rel), SHA256: sum})
+	}
+	return artifacts, nil
+}
+
+// artifactPath returns where a target's artifact goes in the output directory:
+// <plugin>/<plugin>.wasm, or <plugin>/<os>-<arch>/ with the process plugin
+// executable.
+func artifactPath(plugin, target string) string {
+	if target == WASM {
+		return filepath.Join(plugin, plugin+".wasm")
+	}
+	goos, goarch, _ := strings.Cut(target, "/")
+	executable := values.ProcessPluginPrefix + plugin
+	if goos == "windows" {
+		executable += ".exe"
+	}
+	return filepath.Join(plugin, goos+"-"+goarch, executable)
+}
+
+// compile runs go build for one target.
+func compile(ctx context.Context, dir, out, target string, opts Options) error {
+	absOut, err := filepath.Abs(out)
+	if err != nil {
+		return err
+	}
+
+	ldflags := "-s -w -buildid=" +
+		" -X " + sdkPackage + ".buildVersion=" + opts.Version +
+		" -X " + sdkPackage + ".buildCommit=" + opts.Commit +
+		" -X " + sdkPackage + ".buildTime=" + opts.BuildTime
+	args := []string{"build", "-trimpath", "-buildvcs=false", "-ldflags", ldflags, "-o", absOut}
+
+	env := append(os.Environ(), "CGO_ENABLED=0")
+	if target == WASM {
+		args = append(args, "-buildmode=c-shared")
+		env = append(env, "GOOS=wasip1", "GOARCH=wasm")
+	} else {
+		goos, goarch, _ := strings.Cut(target, "/")
+		env = append(env, "GOOS="+goos, "GOARCH="+goarch)
+	}
+	if opts.GoVersion != "" {
+		env = append(env, "GOTOOLCHAIN=go"+strings.TrimPrefix(opts.GoVersion, "go"))
+	}
+	args = append(args, ".")
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(output.String())
+		if target != WASM && strings.Contains(msg, "function main is undeclared") {
+			msg += "\n(the plugin's main package only builds for wasip1; native targets need an entry point for " + target + ")"
+		}
+		return fmt.Errorf("%w\n%s", err, msg)
+	}
+	return nil
+}
+
+// GoVersion returns the version of the Go toolchain builds of dir use, which
+// is pinned by goVersion when set.
+func GoVersion(ctx context.Context, dir, goVersion string) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", "env", "GOVERSION")
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	if goVersion != "" {
+		cmd.Env = append(cmd.Env, "GOTOOLCHAIN=go"+strings.TrimPrefix(goVersion, "go"))
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine the Go version: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// WriteChecksums writes the checksums of artifacts to ChecksumFile in outDir,
+// sorted by path.
+func WriteChecksums(outDir string, artifacts []Artifact) error {
+	sorted := slices.Clone(artifacts)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	var b strings.Builder
+	for _, a := range sorted {
+		fmt.Fprintf(&b, "%s  %s\n", a.SHA256, a.Path)
+	}
+	//nolint:gosec // G306: checksums are published alongside the artifacts
+	if err := os.WriteFile(filepath.Join(outDir, ChecksumFile), []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write checksums: %w", err)
+	}
+	return nil
+}
+
+func fileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to read artifact: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// GitCommit returns the abbreviated commit checked out in dir, or "" when dir
+// is not in a git repository.
+func GitCommit(ctx context.Context, dir string) string {
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// SourceDate returns the time builds of dir record: SOURCE_DATE_EPOCH when
+// set, otherwise the time of the commit checked out in dir, in RFC 3339. It
+// returns "" when neither is available, since the current time would make
+// builds unreproducible.
+func SourceDate(ctx context.Context, dir string) string {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		out, err := exec.CommandContext(ctx, "git", "-C", dir, "log", "-1", "--format=%ct").Output()
+		if err != nil {
+			return ""
+		}
+		epoch = strings.TrimSpace(string(out))
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return ""
+	}
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,95 @@
+package pluginbuild
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePlugin creates a minimal plugin module named name below root.
+func writePlugin(t *testing.T, root, name string) string {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/"+name+"\n\ngo 1.25\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o600))
+	return dir
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writePlugin(t, root, "file")
+	writePlugin(t, root, "http")
+	writePlugin(t, filepath.Join(root, "http"), "testdata")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "docs"), 0o750))
+
+	dirs, err := Discover([]string{root + "/..."})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "file"), filepath.Join(root, "http")}, dirs)
+
+	dirs, err = Discover([]string{filepath.Join(root, "file")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "file")}, dirs)
+
+	_, err = Discover([]string{filepath.Join(root, "docs")})
+	assert.ErrorContains(t, err, "no main.go")
+	_, err = Discover([]string{filepath.Join(root, "docs") + "/..."})
+	assert.ErrorContains(t, err, "no plugins found")
+}
+
+func TestArtifactPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, filepath.Join("file", "file.wasm"), artifactPath("file", WASM))
+	assert.Equal(t, filepath.Join("file", "linux-arm64", "reglet-plugin-file"), artifactPath("file", "linux/arm64"))
+	assert.Equal(t, filepath.Join("file", "windows-amd64", "reglet-plugin-file.exe"), artifactPath("file", "windows/amd64"))
+}
+
+func TestBuild_Reproducible(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("compiles plugins")
+	}
+
+	dir := writePlugin(t, t.TempDir(), "demo")
+	opts := Options{Version: "1.2.3", Commit: "abc1234", Targets: []string{WASM, "linux/arm64"}}
+
+	var builds [][]Artifact
+	for range 2 {
+		opts.OutDir = t.TempDir()
+		artifacts, err := Build(context.Background(), dir, opts)
+		require.NoError(t, err)
+		require.NoError(t, WriteChecksums(opts.OutDir, artifacts))
+		builds = append(builds, artifacts)
+
+		sums, err := os.ReadFile(filepath.Join(opts.OutDir, ChecksumFile))
+		require.NoError(t, err)
+		assert.Contains(t, string(sums), "  demo/demo.wasm\n")
+		assert.Contains(t, string(sums), "  demo/linux-arm64/reglet-plugin-demo\n")
+	}
+
+	require.Len(t, builds[0], 2)
+	assert.Equal(t, builds[0], builds[1], "identical inputs build identical artifacts")
+}
+
+func TestBuild_UnsupportedTarget(t *testing.T) {
+	t.Parallel()
+
+	dir := writePlugin(t, t.TempDir(), "demo")
+	_, err := Build(context.Background(), dir, Options{OutDir: t.TempDir(), Targets: []string{"plan9/386"}})
+	assert.ErrorContains(t, err, `unsupported target "plan9/386"`)
+}
+
+func TestSourceDate(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1767225600")
+	assert.Equal(t, "2026-01-01T00:00:00Z", SourceDate(context.Background(), t.TempDir()))
+
+	t.Setenv("SOURCE_DATE_EPOCH", "not-a-number")
+	assert.Empty(t, SourceDate(context.Background(), t.TempDir()))
+}
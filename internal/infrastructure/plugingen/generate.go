@@ -0,0 +1,260 @@
+package plugingen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// schemaDraft is the JSON Schema dialect of generated schemas, matching the
+// host's schema compiler.
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// GoSource returns the generated Go file: the config struct, a parse function
+// that applies defaults and validates, and the embedded JSON Schema.
+func (s *Spec) GoSource() ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by reglet plugin gen from %s. DO NOT EDIT.\n\n", SpecFile)
+	fmt.Fprintf(&b, "package %s\n\n", s.Package)
+	b.WriteString("import (\n\t_ \"embed\"\n")
+	if s.hasDefaults() {
+		b.WriteString("\t\"maps\"\n")
+	}
+	b.WriteString("\n\tregletsdk \"github.com/reglet-dev/reglet/sdk\"\n)\n\n")
+
+	fmt.Fprintf(&b, "// %s is the configuration of the %s plugin.\n", s.Struct, s.Plugin)
+	fmt.Fprintf(&b, "type %s struct {\n", s.Struct)
+	writeFields(&b, s.Fields)
+	b.WriteString("}\n\n")
+
+	for _, f := range s.Fields {
+		if f.Type != "object" {
+			continue
+		}
+		fmt.Fprintf(&b, "// %s is the %s configuration of the %s plugin.\n", f.structName, f.Name, s.Plugin)
+		fmt.Fprintf(&b, "type %s struct {\n", f.structName)
+		writeFields(&b, f.Fields)
+		b.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(&b, "// configSchema is the JSON Schema of %s, generated alongside it.\n", s.Struct)
+	fmt.Fprintf(&b, "//\n//go:embed %s\nvar configSchema []byte\n\n", SchemaFile)
+
+	parse := "parse" + s.Struct
+	if s.hasDefaults() {
+		fmt.Fprintf(&b, "// %s applies defaults to config, then decodes and validates it.\n", parse)
+	} else {
+		fmt.Fprintf(&b, "// %s decodes and validates config.\n", parse)
+	}
+	fmt.Fprintf(&b, "func %s(config regletsdk.Config) (%s, error) {\n", parse, s.Struct)
+	if s.hasDefaults() {
+		// Copy so defaults never leak into the caller's map
+		b.WriteString("\tconfig = maps.Clone(config)\n\tif config == nil {\n\t\tconfig = regletsdk.Config{}\n\t}\n")
+	}
+	for _, f := range s.Fields {
+		if f.Default == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "\tif _, ok := config[%q]; !ok {\n\t\tconfig[%q] = %s\n\t}\n", f.Name, f.Name, goLiteral(f.Default))
+	}
+	fmt.Fprintf(&b, "\tvar cfg %s\n", s.Struct)
+	b.WriteString("\tif err := regletsdk.ValidateConfig(config, &cfg); err != nil {\n")
+	b.WriteString("\t\treturn cfg, &regletsdk.ConfigError{Err: err}\n\t}\n")
+	b.WriteString("\treturn cfg, nil\n}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// writeFields writes the struct fields declaring fields.
+func writeFields(b *bytes.Buffer, fields []Field) {
+	for _, f := range fields {
+		fmt.Fprintf(b, "\t%s %s `%s`\n", goName(f.Name), f.goType(), f.structTag())
+	}
+}
+
+func (s *Spec) hasDefaults() bool {
+	for _, f := range s.Fields {
+		if f.Default != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// structTag returns the field's json, validate, default and description tags.
+func (f *Field) structTag() string {
+	tags := []string{fmt.Sprintf("json:%q", f.jsonName())}
+	if rules := f.validateRules(); rules != "" {
+		tags = append(tags, fmt.Sprintf("validate:%q", rules))
+	}
+	if f.Default != nil {
+		tags = append(tags, fmt.Sprintf("default:%q", fmt.Sprint(f.Default)))
+	}
+	if f.Description != "" {
+		tags = append(tags, fmt.Sprintf("description:%q", f.Description))
+	}
+	return strings.Join(tags, " ")
+}
+
+func (f *Field) jsonName() string {
+	if f.Required {
+		return f.Name
+	}
+	return f.Name + ",omitempty"
+}
+
+// validateRules returns the go-playground/validator rules for the field.
+func (f *Field) validateRules() string {
+	var rules []string
+	if len(f.Enum) > 0 {
+		rules = append(rules, "oneof="+strings.Join(f.Enum, " "))
+	}
+	if rule, ok := formatRules[f.Format]; ok {
+		rules = append(rules, rule)
+	}
+	if f.Minimum != nil {
+		rules = append(rules, "gte="+formatNumber(*f.Minimum))
+	}
+	if f.Maximum != nil {
+		rules = append(rules, "lte="+formatNumber(*f.Maximum))
+	}
+
+	switch {
+	case f.Required:
+		rules = append([]string{"required"}, rules...)
+	case len(rules) > 0:
+		// An unset optional field is valid whatever its other rules say
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	return strings.Join(rules, ",")
+}
+
+// JSONSchema returns the generated JSON Schema, indented and newline-terminated.
+func (s *Spec) JSONSchema() ([]byte, error) {
+	properties, required := objectProperties(s.Fields)
+
+	schema := struct {
+		Schema               string            `json:"$schema"`
+		Title                string            `json:"title"`
+		Type                 string            `json:"type"`
+		Properties           orderedProperties `json:"properties"`
+		Required             []string          `json:"required"`
+		AdditionalProperties bool              `json:"additionalProperties"`
+	}{
+		Schema:     schemaDraft,
+		Title:      s.Plugin + " plugin configuration",
+		Type:       "object",
+		Properties: properties,
+		Required:   required,
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// objectProperties returns the schema properties of fields and the names of
+// the required ones.
+func objectProperties(fields []Field) (orderedProperties, []string) {
+	properties := make(orderedProperties, 0, len(fields))
+	required := []string{}
+	for _, f := range fields {
+		properties = append(properties, property{name: f.Name, schema: f.schema()})
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+	return properties, required
+}
+
+// propertySchema is the JSON Schema of one field.
+type propertySchema struct {
+	Default              any               `json:"default,omitempty"`
+	Items                *propertySchema   `json:"items,omitempty"`
+	Minimum              *float64          `json:"minimum,omitempty"`
+	Maximum              *float64          `json:"maximum,omitempty"`
+	AdditionalProperties *bool             `json:"additionalProperties,omitempty"`
+	Type                 string            `json:"type"`
+	Format               string            `json:"format,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	Enum                 []any             `json:"enum,omitempty"`
+	Properties           orderedProperties `json:"properties,omitempty"`
+	Required             []string          `json:"required,omitempty"`
+}
+
+func (f *Field) schema() propertySchema {
+	schema := propertySchema{
+		Type:        f.Type,
+		Format:      f.Format,
+		Description: f.Description,
+		Default:     f.Default,
+		Minimum:     f.Minimum,
+		Maximum:     f.Maximum,
+	}
+	switch f.Type {
+	case "array":
+		schema.Items = &propertySchema{Type: f.Items}
+	case "object":
+		closed := false
+		schema.Properties, schema.Required = objectProperties(f.Fields)
+		schema.AdditionalProperties = &closed
+	}
+	for _, v := range f.Enum {
+		if f.Type == "integer" {
+			n, _ := strconv.Atoi(v)
+			schema.Enum = append(schema.Enum, n)
+			continue
+		}
+		schema.Enum = append(schema.Enum, v)
+	}
+	return schema
+}
+
+type property struct {
+	name   string
+	schema propertySchema
+}
+
+// orderedProperties marshals as a JSON object keeping spec field order.
+type orderedProperties []property
+
+func (p orderedProperties) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, prop := range p {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(prop.name)
+		value, err := json.Marshal(prop.schema)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// goLiteral formats a YAML scalar as a Go literal.
+func goLiteral(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,198 @@
+package plugingen
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+plugin: http
+fields:
+  - name: url
+    type: string
+    format: url
+    required: true
+    description: URL to request
+  - name: method
+    type: string
+    enum: [GET, HEAD, POST]
+    default: GET
+  - name: timeout_ms
+    type: integer
+    minimum: 1
+    maximum: 60000
+    default: 5000
+  - name: headers
+    type: array
+    items: string
+`
+
+func TestParseSpec_Defaults(t *testing.T) {
+	t.Parallel()
+
+	spec, err := ParseSpec([]byte(testSpec))
+	require.NoError(t, err)
+	assert.Equal(t, "HTTPConfig", spec.Struct)
+	assert.Equal(t, "main", spec.Package)
+	require.Len(t, spec.Fields, 4)
+}
+
+func TestParseSpec_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name, spec, wantErr string
+	}{
+		{"no plugin", "fields: [{name: a, type: string}]", "plugin is required"},
+		{"no fields", "plugin: x", "at least one field"},
+		{"unknown key", "plugin: x\nfoo: 1\nfields: [{name: a, type: string}]", "failed to parse spec"},
+		{"bad name", "plugin: x\nfields: [{name: BadName, type: string}]", "snake_case"},
+		{"duplicate", "plugin: x\nfields: [{name: a, type: string}, {name: a, type: string}]", "declared twice"},
+		{"bad type", "plugin: x\nfields: [{name: a, type: map}]", "unsupported type"},
+		{"empty object", "plugin: x\nfields: [{name: a, type: object}]", "at least one field"},
+		{"nested object", "plugin: x\nfields: [{name: a, type: object, fields: [{name: b, type: object}]}]", "cannot be nested"},
+		{"object field default", "plugin: x\nfields: [{name: a, type: object, fields: [{name: b, type: string, default: c}]}]", "cannot have defaults"},
+		{"fields on string", "plugin: x\nfields: [{name: a, type: string, fields: [{name: b, type: string}]}]", "only valid for objects"},
+		{"bad items", "plugin: x\nfields: [{name: a, type: array, items: array}]", "array items"},
+		{"format on int", "plugin: x\nfields: [{name: a, type: integer, format: url}]", "format is only valid"},
+		{"enum on bool", "plugin: x\nfields: [{name: a, type: boolean, enum: [x]}]", "enum is only valid"},
+		{"non-integer enum", "plugin: x\nfields: [{name: a, type: integer, enum: [x]}]", "not an integer"},
+		{"min on string", "plugin: x\nfields: [{name: a, type: string, minimum: 1}]", "minimum and maximum"},
+		{"required default", "plugin: x\nfields: [{name: a, type: string, required: true, default: b}]", "cannot have a default"},
+		{"mistyped default", "plugin: x\nfields: [{name: a, type: boolean, default: yes please}]", "is not a boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseSpec([]byte(tt.spec))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSpec_GoSource(t *testing.T) {
+	t.Parallel()
+
+	spec, err := ParseSpec([]byte(testSpec))
+	require.NoError(t, err)
+	src, err := spec.GoSource()
+	require.NoError(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, "// Code generated by reglet plugin gen from config.yaml. DO NOT EDIT.")
+	assert.Contains(t, code, "type HTTPConfig struct {")
+	assert.Contains(t, code, "URL       string   `json:\"url\" validate:\"required,url\" description:\"URL to request\"`")
+	assert.Contains(t, code, "Method    string   `json:\"method,omitempty\" validate:\"omitempty,oneof=GET HEAD POST\" default:\"GET\"`")
+	assert.Contains(t, code, "TimeoutMs int      `json:\"timeout_ms,omitempty\" validate:\"omitempty,gte=1,lte=60000\" default:\"5000\"`")
+	assert.Contains(t, code, "Headers   []string `json:\"headers,omitempty\"`")
+	assert.Contains(t, code, "//go:embed schema.json")
+	assert.Contains(t, code, "config = maps.Clone(config)")
+	assert.Contains(t, code, "config[\"method\"] = \"GET\"")
+	assert.Contains(t, code, "config[\"timeout_ms\"] = 5000")
+}
+
+func TestSpec_JSONSchema(t *testing.T) {
+	t.Parallel()
+
+	spec, err := ParseSpec([]byte(testSpec))
+	require.NoError(t, err)
+	data, err := spec.JSONSchema()
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+	assert.Equal(t, schemaDraft, schema["$schema"])
+	assert.Equal(t, []any{"url"}, schema["required"])
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	props := schema["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "format": "url", "description": "URL to request"}, props["url"])
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"GET", "HEAD", "POST"}, "default": "GET"}, props["method"])
+	assert.Equal(t, map[string]any{"type": "integer", "minimum": 1.0, "maximum": 60000.0, "default": 5000.0}, props["timeout_ms"])
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, props["headers"])
+
+	// Properties keep spec order
+	assert.Less(t, bytes.Index(data, []byte(`"url"`)), bytes.Index(data, []byte(`"method"`)))
+	assert.Less(t, bytes.Index(data, []byte(`"method"`)), bytes.Index(data, []byte(`"timeout_ms"`)))
+}
+
+func TestSpec_Object(t *testing.T) {
+	t.Parallel()
+
+	spec, err := ParseSpec([]byte(`
+plugin: file
+fields:
+  - name: read_range
+    type: object
+    description: Bytes to read
+    fields:
+      - name: offset
+        type: integer
+        minimum: 0
+      - name: length
+        type: integer
+        required: true
+`))
+	require.NoError(t, err)
+
+	src, err := spec.GoSource()
+	require.NoError(t, err)
+	code := string(src)
+	assert.Contains(t, code, "ReadRange *FileReadRange `json:\"read_range,omitempty\" description:\"Bytes to read\"`")
+	assert.Contains(t, code, "// FileReadRange is the read_range configuration of the file plugin.\ntype FileReadRange struct {")
+	assert.Contains(t, code, "Length int `json:\"length\" validate:\"required\"`")
+
+	data, err := spec.JSONSchema()
+	require.NoError(t, err)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+	assert.Equal(t, map[string]any{
+		"type":        "object",
+		"description": "Bytes to read",
+		"properties": map[string]any{
+			"offset": map[string]any{"type": "integer", "minimum": 0.0},
+			"length": map[string]any{"type": "integer"},
+		},
+		"required":             []any{"length"},
+		"additionalProperties": false,
+	}, schema["properties"].(map[string]any)["read_range"])
+}
+
+func TestRenderWriteStale(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, SpecFile), []byte(testSpec), 0o600))
+
+	outputs, err := Render(dir)
+	require.NoError(t, err)
+	assert.Len(t, Stale(outputs), 2, "nothing generated yet")
+
+	require.NoError(t, Write(outputs))
+	assert.Empty(t, Stale(outputs))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, SchemaFile), []byte("{}"), 0o600))
+	assert.Equal(t, []string{filepath.Join(dir, SchemaFile)}, Stale(outputs))
+}
+
+func TestBuiltinPluginsAreCurrent(t *testing.T) {
+	t.Parallel()
+
+	specs, err := filepath.Glob(filepath.Join("..", "..", "..", "plugins", "*", SpecFile))
+	require.NoError(t, err)
+	require.NotEmpty(t, specs)
+
+	for _, spec := range specs {
+		outputs, err := Render(filepath.Dir(spec))
+		require.NoError(t, err)
+		assert.Empty(t, Stale(outputs), "run 'reglet plugin gen %s'", filepath.Dir(spec))
+	}
+}
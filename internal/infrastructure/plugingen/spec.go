@@ -0,0 +1,251 @@
+// Package plugingen generates a plugin's Go config struct and its JSON Schema
+// from one spec file, so the schema a plugin reports and the struct it
+// unmarshals configuration into cannot drift apart.
+package plugingen
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/goccy/go-yaml"
+)
+
+// SpecFile is the spec's file name in a plugin directory.
+const SpecFile = "config.yaml"
+
+// Generated file names, written next to the spec.
+const (
+	GoFile     = "config_gen.go"
+	SchemaFile = "schema.json"
+)
+
+// Spec describes a plugin's configuration.
+type Spec struct {
+	Plugin  string  `yaml:"plugin"`
+	Struct  string  `yaml:"struct"`  // Go struct name; defaults to <Plugin>Config
+	Package string  `yaml:"package"` // Go package; defaults to main
+	Fields  []Field `yaml:"fields"`
+}
+
+// Field is one configuration key.
+type Field struct {
+	Default     any      `yaml:"default"`
+	Minimum     *float64 `yaml:"minimum"`
+	Maximum     *float64 `yaml:"maximum"`
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"`  // string, integer, number, boolean, array or object
+	Items       string   `yaml:"items"` // element type of an array
+	Format      string   `yaml:"format"`
+	Description string   `yaml:"description"`
+	Enum        []string `yaml:"enum"`
+	Fields      []Field  `yaml:"fields"` // keys of an object
+	Required    bool     `yaml:"required"`
+
+	structName string // Go type of an object, set by ParseSpec
+}
+
+// goTypes maps spec types to Go types.
+var goTypes = map[string]string{
+	"string":  "string",
+	"integer": "int",
+	"number":  "float64",
+	"boolean": "bool",
+}
+
+// formatRules maps string formats to validator tags.
+var formatRules = map[string]string{
+	"email":    "email",
+	"hostname": "hostname",
+	"ip":       "ip",
+	"uri":      "uri",
+	"url":      "url",
+}
+
+var fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// LoadSpec reads and validates a spec file.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: user-specified spec path is intentional
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	return ParseSpec(data)
+}
+
+// ParseSpec parses and validates a spec, filling in defaults.
+func ParseSpec(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := yaml.UnmarshalWithOptions(data, &spec, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	if spec.Struct == "" {
+		spec.Struct = goName(spec.Plugin) + "Config"
+	}
+	if spec.Package == "" {
+		spec.Package = "main"
+	}
+	for i := range spec.Fields {
+		if spec.Fields[i].Type == "object" {
+			spec.Fields[i].structName = goName(spec.Plugin) + goName(spec.Fields[i].Name)
+		}
+	}
+	return &spec, nil
+}
+
+func (s *Spec) validate() error {
+	if s.Plugin == "" {
+		return fmt.Errorf("spec: plugin is required")
+	}
+	if len(s.Fields) == 0 {
+		return fmt.Errorf("spec: at least one field is required")
+	}
+
+	if err := validateFields(s.Fields, true); err != nil {
+		return fmt.Errorf("spec: %w", err)
+	}
+	return nil
+}
+
+// validateFields validates the fields of the spec (top level) or of an object.
+func validateFields(fields []Field, topLevel bool) error {
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if !fieldNamePattern.MatchString(f.Name) {
+			return fmt.Errorf("field %q: name must be snake_case", f.Name)
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("field %q is declared twice", f.Name)
+		}
+		seen[f.Name] = true
+
+		if err := f.validate(topLevel); err != nil {
+			return fmt.Errorf("field %q: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func (f *Field) validate(topLevel bool) error {
+	if f.Type != "object" && len(f.Fields) > 0 {
+		return fmt.Errorf("fields is only valid for objects")
+	}
+	switch f.Type {
+	case "object":
+		// Defaults are applied to the top-level config map only
+		if !topLevel {
+			return fmt.Errorf("objects cannot be nested")
+		}
+		if len(f.Fields) == 0 {
+			return fmt.Errorf("an object needs at least one field")
+		}
+		if f.Default != nil {
+			return fmt.Errorf("an object cannot have a default")
+		}
+		if err := validateFields(f.Fields, false); err != nil {
+			return err
+		}
+	case "array":
+		if _, ok := goTypes[f.Items]; !ok {
+			return fmt.Errorf("array items must be one of string, integer, number or boolean, got %q", f.Items)
+		}
+	case "":
+		return fmt.Errorf("type is required")
+	default:
+		if _, ok := goTypes[f.Type]; !ok {
+			return fmt.Errorf("unsupported type %q", f.Type)
+		}
+		if f.Default != nil && !topLevel {
+			return fmt.Errorf("fields of an object cannot have defaults")
+		}
+		if f.Items != "" {
+			return fmt.Errorf("items is only valid for arrays")
+		}
+	}
+
+	if f.Format != "" {
+		if f.Type != "string" {
+			return fmt.Errorf("format is only valid for strings")
+		}
+		if _, ok := formatRules[f.Format]; !ok {
+			return fmt.Errorf("unsupported format %q", f.Format)
+		}
+	}
+	if len(f.Enum) > 0 && f.Type != "string" && f.Type != "integer" {
+		return fmt.Errorf("enum is only valid for strings and integers")
+	}
+	if (f.Minimum != nil || f.Maximum != nil) && f.Type != "integer" && f.Type != "number" {
+		return fmt.Errorf("minimum and maximum are only valid for numbers")
+	}
+	for _, v := range f.Enum {
+		if strings.ContainsAny(v, " '") {
+			return fmt.Errorf("enum value %q must not contain spaces or quotes", v)
+		}
+		if _, err := strconv.Atoi(v); f.Type == "integer" && err != nil {
+			return fmt.Errorf("enum value %q is not an integer", v)
+		}
+	}
+	if f.Default != nil {
+		if f.Required {
+			return fmt.Errorf("a required field cannot have a default")
+		}
+		if !f.defaultMatchesType() {
+			return fmt.Errorf("default %v is not a %s", f.Default, f.Type)
+		}
+	}
+	return nil
+}
+
+// defaultMatchesType reports whether the default is a scalar of the field's
+// type. Array defaults are not supported.
+func (f *Field) defaultMatchesType() bool {
+	switch f.Default.(type) {
+	case string:
+		return f.Type == "string"
+	case bool:
+		return f.Type == "boolean"
+	case int, int64, uint64:
+		return f.Type == "integer" || f.Type == "number"
+	case float64:
+		return f.Type == "number"
+	}
+	return false
+}
+
+// goType returns the field's Go type. Objects are pointers, nil when unset.
+func (f *Field) goType() string {
+	switch f.Type {
+	case "array":
+		return "[]" + goTypes[f.Items]
+	case "object":
+		return "*" + f.structName
+	}
+	return goTypes[f.Type]
+}
+
+// goName converts a snake_case or kebab-case name to an exported Go name.
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' }) {
+		if upper := strings.ToUpper(part); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// initialisms are spelled in upper case in Go names.
+var initialisms = map[string]bool{
+	"DNS": true, "HTTP": true, "ID": true, "IP": true, "SMTP": true,
+	"TCP": true, "TLS": true, "TTL": true, "URL": true,
+}
@@ -0,0 +1,60 @@
+package plugingen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Output is a generated file and its contents.
+type Output struct {
+	Path    string
+	Content []byte
+}
+
+// Render loads the spec in dir and returns the files generated from it.
+func Render(dir string) ([]Output, error) {
+	spec, err := LoadSpec(filepath.Join(dir, SpecFile))
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := spec.GoSource()
+	if err != nil {
+		return nil, err
+	}
+	schema, err := spec.JSONSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	return []Output{
+		{Path: filepath.Join(dir, GoFile), Content: src},
+		{Path: filepath.Join(dir, SchemaFile), Content: schema},
+	}, nil
+}
+
+// Write writes the generated files to disk.
+func Write(outputs []Output) error {
+	for _, out := range outputs {
+		//nolint:gosec // G306: Generated plugin sources are committed and need normal permissions
+		if err := os.WriteFile(out.Path, out.Content, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", out.Path, err)
+		}
+	}
+	return nil
+}
+
+// Stale returns the paths of generated files that are missing or differ from
+// what the spec produces.
+func Stale(outputs []Output) []string {
+	var stale []string
+	for _, out := range outputs {
+		current, err := os.ReadFile(out.Path)
+		if err != nil || !bytes.Equal(current, out.Content) {
+			stale = append(stale, out.Path)
+		}
+	}
+	return stale
+}
@@ -0,0 +1,39 @@
+package delta
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateApply(t *testing.T) {
+	base := make([]byte, 4<<20)
+	_, err := rand.Read(base)
+	require.NoError(t, err)
+
+	target := append([]byte(nil), base[:1<<20]...)
+	target = append(target, []byte("a new function")...)
+	target = append(target, base[2<<20:]...)
+
+	patch, err := Create(base, target)
+	require.NoError(t, err)
+	assert.Less(t, len(patch), 64<<10, "patch holds only the changes")
+
+	patched, err := Apply(base, patch)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(target, patched))
+}
+
+func TestApply_WrongBase(t *testing.T) {
+	base := bytes.Repeat([]byte("plugin v1 "), 1000)
+	target := append(bytes.Repeat([]byte("plugin v1 "), 900), []byte("plugin v2")...)
+
+	patch, err := Create(base, target)
+	require.NoError(t, err)
+
+	_, err = Apply(bytes.Repeat([]byte("other "), 1000), patch)
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+// Package index reads plugin indexes, the manifests of plugin registries,
+// and downloads the plugin binaries they list by HTTPS URL.
+package index
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+)
+
+// maxDownloadSize bounds the size of a downloaded index or plugin binary.
+const maxDownloadSize = 256 << 20
+
+// Source implements ports.PluginIndexSource on an HTTPS URL or a local
+// file. An index is YAML or JSON.
+type Source struct {
+	client   *http.Client
+	location string
+}
+
+// NewSource creates an index source reading location through transport.
+func NewSource(location string, transport http.RoundTripper) *Source {
+	return &Source{location: location, client: &http.Client{Transport: transport}}
+}
+
+// FetchIndex reads and validates the index.
+func (s *Source) FetchIndex(ctx context.Context) (*entities.PluginIndex, error) {
+	if s.location == "" {
+		return nil, errors.New("no plugin index configured: set registry.index or pass --index")
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if strings.Contains(s.location, "://") {
+		data, err = get(ctx, s.client, s.location)
+	} else {
+		data, err = os.ReadFile(s.location)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin index %s: %w", s.location, err)
+	}
+
+	var index entities.PluginIndex
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin index %s: %w", s.location, err)
+	}
+	if err := index.Validate(); err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+// Downloader implements ports.PluginDownloader over HTTPS.
+type Downloader struct {
+	client *http.Client
+}
+
+// NewDownloader creates a downloader sending requests through transport.
+func NewDownloader(transport http.RoundTripper) *Downloader {
+	return &Downloader{client: &http.Client{Transport: transport}}
+}
+
+// Download fetches the content of an HTTPS URL.
+func (d *Downloader) Download(ctx context.Context, url string) ([]byte, error) {
+	return get(ctx, d.client, url)
+}
+
+// get fetches the content of an HTTPS URL.
+func get(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("refusing to download %s: only https:// URLs are supported", url)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDownloadSize {
+		return nil, fmt.Errorf("GET %s: response exceeds %d bytes", url, maxDownloadSize)
+	}
+	return data, nil
+}
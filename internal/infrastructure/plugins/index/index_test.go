@@ -0,0 +1,67 @@
+package index
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIndex = `plugins:
+  reglet/http:
+    description: HTTP requests
+    releases:
+      1.2.0:
+        source: ghcr.io/reglet-dev/plugins/http:1.2.0
+        digest: sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824
+      2.0.0:
+        source: https://plugins.example.com/http-2.0.0.wasm
+        digest: sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824
+`
+
+func TestSource_FetchIndex(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			_, _ = w.Write([]byte(testIndex))
+		case "/http.wasm":
+			_, _ = w.Write([]byte("wasm"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	transport := server.Client().Transport
+
+	index, err := NewSource(server.URL+"/index.yaml", transport).FetchIndex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reglet/http"}, index.Names())
+	assert.Equal(t, "HTTP requests", index.Plugins["reglet/http"].Description)
+	assert.True(t, index.Plugins["reglet/http"].Releases["2.0.0"].IsURL())
+
+	path := filepath.Join(t.TempDir(), "index.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testIndex), 0o600))
+	index, err = NewSource(path, transport).FetchIndex(ctx)
+	require.NoError(t, err)
+	assert.Len(t, index.Plugins["reglet/http"].Releases, 2)
+
+	_, err = NewSource("", transport).FetchIndex(ctx)
+	assert.ErrorContains(t, err, "no plugin index configured")
+	_, err = NewSource("http://plugins.example.com/index.yaml", transport).FetchIndex(ctx)
+	assert.ErrorContains(t, err, "only https:// URLs")
+
+	downloader := NewDownloader(transport)
+	data, err := downloader.Download(ctx, server.URL+"/http.wasm")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("wasm"), data)
+	_, err = downloader.Download(ctx, server.URL+"/missing.wasm")
+	assert.ErrorContains(t, err, "404")
+}
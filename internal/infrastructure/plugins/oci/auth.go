@@ -3,7 +3,9 @@ package oci
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 )
 
 // EnvAuthProvider retrieves credentials from environment variables.
@@ -20,3 +22,46 @@ func (p *EnvAuthProvider) GetCredentials(ctx context.Context, registry string) (
 	password = os.Getenv("REGISTRY_PASSWORD")
 	return username, password, nil
 }
+
+// CredentialSource looks up named credentials, e.g. in the encrypted
+// credential store.
+type CredentialSource interface {
+	Get(name string) (string, bool, error)
+}
+
+// RegistryCredentialName returns the name under which the login of a
+// registry is stored: "registry/<host>", holding "username:password".
+func RegistryCredentialName(registry string) string {
+	return "registry/" + registry
+}
+
+// CredentialAuthProvider retrieves registry logins from a credential
+// source, falling back to environment variables for registries it has no
+// login for.
+type CredentialAuthProvider struct {
+	source   CredentialSource
+	fallback *EnvAuthProvider
+}
+
+// NewCredentialAuthProvider creates an auth provider reading logins from
+// source.
+func NewCredentialAuthProvider(source CredentialSource) *CredentialAuthProvider {
+	return &CredentialAuthProvider{source: source, fallback: NewEnvAuthProvider()}
+}
+
+// GetCredentials returns username and password for a registry.
+func (p *CredentialAuthProvider) GetCredentials(ctx context.Context, registry string) (username, password string, err error) {
+	name := RegistryCredentialName(registry)
+	login, ok, err := p.source.Get(name)
+	if err != nil {
+		return "", "", err
+	}
+	if !ok {
+		return p.fallback.GetCredentials(ctx, registry)
+	}
+	username, password, found := strings.Cut(login, ":")
+	if !found {
+		return "", "", fmt.Errorf("credential %q must be username:password", name)
+	}
+	return username, password, nil
+}
@@ -4,12 +4,18 @@ package oci
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/errdef"
 	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras-go/v2/registry/remote/auth"
 
@@ -19,9 +25,23 @@ import (
 	"github.com/reglet-dev/reglet/internal/domain/values"
 )
 
+// Media types and annotations of reglet plugin artifacts.
+const (
+	// MediaTypeWASM is the layer holding a plugin binary
+	MediaTypeWASM = "application/vnd.reglet.plugin.wasm.v1"
+	// MediaTypePatch is the layer holding a zstd patch between plugin
+	// binaries (see package delta)
+	MediaTypePatch = "application/vnd.reglet.plugin.wasm.patch.v1+zstd"
+	// AnnotationPatchTarget is the digest of the binary a patch produces; it
+	// must match the WASM layer of the version the patch updates to
+	AnnotationPatchTarget = "dev.reglet.patch.target"
+)
+
 // OCIRegistryAdapter implements ports.PluginRegistry using oras-go.
 type OCIRegistryAdapter struct {
-	auth ports.AuthProvider
+	auth      ports.AuthProvider
+	transport http.RoundTripper
+	mirrors   map[string]string
 }
 
 // NewOCIRegistryAdapter creates an OCI registry adapter.
@@ -31,100 +51,214 @@ func NewOCIRegistryAdapter(auth ports.AuthProvider) *OCIRegistryAdapter {
 	}
 }
 
-// Pull downloads a plugin from OCI registry.
-func (a *OCIRegistryAdapter) Pull(ctx context.Context, ref values.PluginReference) (*dto.PluginArtifactDTO, error) {
-	// Create repository client
-	repo, err := remote.NewRepository(ref.Registry() + "/" + ref.String())
+// SetMirrors sets the mirror rewrite rules: plugin repositories starting
+// with a key are pulled from its value instead.
+func (a *OCIRegistryAdapter) SetMirrors(mirrors map[string]string) {
+	a.mirrors = mirrors
+}
+
+// SetTransport sets the HTTP transport registries are reached through, e.g.
+// one with a proxy or extra CAs (see NewTransport).
+func (a *OCIRegistryAdapter) SetTransport(transport http.RoundTripper) {
+	a.transport = transport
+}
+
+// repository returns the client of the repository holding ref, after mirror
+// rewrites, logged in with the credentials of the registry serving it.
+func (a *OCIRegistryAdapter) repository(ctx context.Context, ref values.PluginReference) (*remote.Repository, error) {
+	name := rewriteRepository(strings.TrimSuffix(ref.String(), ":"+ref.Version()), a.mirrors)
+	repo, err := remote.NewRepository(name)
 	if err != nil {
 		return nil, fmt.Errorf("create repository: %w", err)
 	}
 
-	// Set credentials
-	username, password, err := a.auth.GetCredentials(ctx, ref.Registry())
+	client := &auth.Client{Client: &http.Client{Transport: a.transport}}
+	if a.transport == nil {
+		client.Client = http.DefaultClient
+	}
+	username, password, err := a.auth.GetCredentials(ctx, repo.Reference.Registry)
 	if err == nil && username != "" {
-		repo.Client = &auth.Client{
-			Credential: func(ctx context.Context, registry string) (auth.Credential, error) {
-				return auth.Credential{
-					Username: username,
-					Password: password,
-				}, nil
-			},
-		}
+		client.Credential = auth.StaticCredential(repo.Reference.Registry, auth.Credential{
+			Username: username,
+			Password: password,
+		})
+	}
+	repo.Client = client
+	return repo, nil
+}
+
+// Pull downloads a plugin from OCI registry.
+func (a *OCIRegistryAdapter) Pull(ctx context.Context, ref values.PluginReference) (*dto.PluginArtifactDTO, error) {
+	repo, err := a.repository(ctx, ref)
+	if err != nil {
+		return nil, err
 	}
 
 	// Pull manifest and layers
-	memoryStore := memory.New()
-	manifestDesc, err := oras.Copy(ctx, repo, ref.Version(), memoryStore, ref.Version(), oras.CopyOptions{})
+	store, manifest, err := a.fetchManifest(ctx, repo, ref.Version())
 	if err != nil {
 		return nil, fmt.Errorf("pull artifact: %w", err)
 	}
 
-	// Parse manifest
-	manifestRC, err := memoryStore.Fetch(ctx, manifestDesc)
+	// Extract metadata from config layer
+	metadata, err := a.fetchMetadata(ctx, store, manifest)
 	if err != nil {
-		return nil, fmt.Errorf("fetch manifest: %w", err)
+		return nil, err
 	}
-	defer func() {
-		_ = manifestRC.Close()
-	}()
 
-	manifestBytes, err := io.ReadAll(manifestRC)
+	// Find and fetch WASM binary
+	wasmDesc, err := a.findLayer(manifest, MediaTypeWASM)
 	if err != nil {
-		return nil, fmt.Errorf("read manifest: %w", err)
+		return nil, err
+	}
+	wasmBytes, err := fetchBlob(ctx, store, wasmDesc)
+	if err != nil {
+		return nil, fmt.Errorf("fetch wasm: %w", err)
 	}
 
-	manifest, err := a.parseManifest(manifestBytes)
+	// Create domain entities
+	digest, _ := values.ParseDigest(string(wasmDesc.Digest))
+	plugin := entities.NewPlugin(ref, digest, metadata)
+
+	// Create DTO with I/O
+	artifact := dto.NewPluginArtifactDTO(plugin, io.NopCloser(bytes.NewReader(wasmBytes)))
+
+	return artifact, nil
+}
+
+// PullPatch downloads the patch from the plugin binary with digest base to
+// ref, published under PatchTag. It returns nil if there is none.
+//
+// The returned plugin, and so the digest the patched binary is verified
+// against, comes from ref's own manifest: whoever can push a patch tag must
+// not also choose the digest that vouches for it.
+func (a *OCIRegistryAdapter) PullPatch(ctx context.Context, ref values.PluginReference, base values.Digest) (*dto.PluginPatchDTO, error) {
+	repo, err := a.repository(ctx, ref)
 	if err != nil {
 		return nil, err
 	}
 
-	// Extract metadata from config layer
-	configRC, err := memoryStore.Fetch(ctx, manifest.Config)
-	if err != nil {
-		return nil, fmt.Errorf("fetch config: %w", err)
+	tag := PatchTag(ref.Version(), base)
+	if _, err := repo.Resolve(ctx, tag); errors.Is(err, errdef.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("resolve patch: %w", err)
 	}
-	defer func() {
-		_ = configRC.Close()
-	}()
 
-	configBytes, err := io.ReadAll(configRC)
+	target, metadata, err := a.describeArtifact(ctx, repo, ref.Version())
 	if err != nil {
-		return nil, fmt.Errorf("read config: %w", err)
+		return nil, fmt.Errorf("resolve patch target: %w", err)
 	}
 
-	metadata, err := a.parseMetadata(configBytes)
+	store, manifest, err := a.fetchManifest(ctx, repo, tag)
+	if err != nil {
+		return nil, fmt.Errorf("pull patch: %w", err)
+	}
+	patchDesc, err := a.findLayer(manifest, MediaTypePatch)
 	if err != nil {
 		return nil, err
 	}
+	if announced := patchDesc.Annotations[AnnotationPatchTarget]; announced != target.String() {
+		return nil, fmt.Errorf("patch %s produces %s, but %s is %s", tag, announced, ref.String(), target)
+	}
+	patch, err := fetchBlob(ctx, store, patchDesc)
+	if err != nil {
+		return nil, fmt.Errorf("fetch patch: %w", err)
+	}
+
+	return &dto.PluginPatchDTO{Plugin: entities.NewPlugin(ref, target, metadata), Patch: patch}, nil
+}
+
+// describeArtifact returns the digest of the plugin binary tagged tag and
+// its metadata, without downloading the binary.
+func (a *OCIRegistryAdapter) describeArtifact(ctx context.Context, repo *remote.Repository, tag string) (values.Digest, values.PluginMetadata, error) {
+	manifestDesc, rc, err := repo.FetchReference(ctx, tag)
+	if err != nil {
+		return values.Digest{}, values.PluginMetadata{}, err
+	}
+	manifestBytes, err := content.ReadAll(rc, manifestDesc)
+	_ = rc.Close()
+	if err != nil {
+		return values.Digest{}, values.PluginMetadata{}, fmt.Errorf("fetch manifest: %w", err)
+	}
+	manifest, err := a.parseManifest(manifestBytes)
+	if err != nil {
+		return values.Digest{}, values.PluginMetadata{}, err
+	}
 
-	// Find WASM layer
-	wasmDesc, err := a.findWASMLayer(manifest)
+	rc, err = repo.Fetch(ctx, manifest.Config)
 	if err != nil {
-		return nil, err
+		return values.Digest{}, values.PluginMetadata{}, fmt.Errorf("fetch config: %w", err)
+	}
+	configBytes, err := content.ReadAll(rc, manifest.Config)
+	_ = rc.Close()
+	if err != nil {
+		return values.Digest{}, values.PluginMetadata{}, fmt.Errorf("fetch config: %w", err)
+	}
+	metadata, err := a.parseMetadata(configBytes)
+	if err != nil {
+		return values.Digest{}, values.PluginMetadata{}, err
 	}
 
-	// Fetch WASM binary
-	wasmRC, err := memoryStore.Fetch(ctx, wasmDesc)
+	wasmDesc, err := a.findLayer(manifest, MediaTypeWASM)
 	if err != nil {
-		return nil, fmt.Errorf("fetch wasm: %w", err)
+		return values.Digest{}, values.PluginMetadata{}, err
 	}
-	defer func() {
-		_ = wasmRC.Close()
-	}()
+	digest, err := values.ParseDigest(string(wasmDesc.Digest))
+	if err != nil {
+		return values.Digest{}, values.PluginMetadata{}, fmt.Errorf("invalid wasm layer digest: %w", err)
+	}
+	return digest, metadata, nil
+}
+
+// PatchTag returns the tag the patch from the plugin binary with digest
+// base to version is published under.
+func PatchTag(version string, base values.Digest) string {
+	hash := base.Value()
+	if len(hash) > 16 {
+		hash = hash[:16]
+	}
+	return strings.ReplaceAll(version, "+", "_") + ".patch-" + hash
+}
 
-	wasmBytes, err := io.ReadAll(wasmRC)
+// fetchManifest copies the artifact tagged tag into memory and returns its
+// manifest.
+func (a *OCIRegistryAdapter) fetchManifest(ctx context.Context, repo *remote.Repository, tag string) (*memory.Store, *ocispec.Manifest, error) {
+	store := memory.New()
+	manifestDesc, err := oras.Copy(ctx, repo, tag, store, tag, oras.CopyOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("read wasm: %w", err)
+		return nil, nil, err
 	}
 
-	// Create domain entities
-	digest, _ := values.ParseDigest(string(wasmDesc.Digest))
-	plugin := entities.NewPlugin(ref, digest, metadata)
+	manifestBytes, err := fetchBlob(ctx, store, manifestDesc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch manifest: %w", err)
+	}
+	manifest, err := a.parseManifest(manifestBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return store, manifest, nil
+}
 
-	// Create DTO with I/O
-	artifact := dto.NewPluginArtifactDTO(plugin, io.NopCloser(bytes.NewReader(wasmBytes)))
+// fetchMetadata reads the plugin metadata from the config of a manifest.
+func (a *OCIRegistryAdapter) fetchMetadata(ctx context.Context, store *memory.Store, manifest *ocispec.Manifest) (values.PluginMetadata, error) {
+	configBytes, err := fetchBlob(ctx, store, manifest.Config)
+	if err != nil {
+		return values.PluginMetadata{}, fmt.Errorf("fetch config: %w", err)
+	}
+	return a.parseMetadata(configBytes)
+}
 
-	return artifact, nil
+func fetchBlob(ctx context.Context, store *memory.Store, desc ocispec.Descriptor) ([]byte, error) {
+	rc, err := store.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+	return io.ReadAll(rc)
 }
 
 // Push uploads a plugin to OCI registry.
@@ -142,20 +276,31 @@ func (a *OCIRegistryAdapter) Resolve(ctx context.Context, ref values.PluginRefer
 
 // Helper methods
 func (a *OCIRegistryAdapter) parseManifest(data []byte) (*ocispec.Manifest, error) {
-	// Parse OCI manifest JSON
-	return nil, nil
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	return &manifest, nil
 }
 
 func (a *OCIRegistryAdapter) parseMetadata(data []byte) (values.PluginMetadata, error) {
-	// Parse plugin metadata JSON
-	return values.PluginMetadata{}, nil
+	var meta struct {
+		Name         string   `json:"name"`
+		Version      string   `json:"version"`
+		Description  string   `json:"description"`
+		Capabilities []string `json:"capabilities"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return values.PluginMetadata{}, fmt.Errorf("parse plugin metadata: %w", err)
+	}
+	return values.NewPluginMetadata(meta.Name, meta.Version, meta.Description, meta.Capabilities), nil
 }
 
-func (a *OCIRegistryAdapter) findWASMLayer(manifest *ocispec.Manifest) (ocispec.Descriptor, error) {
+func (a *OCIRegistryAdapter) findLayer(manifest *ocispec.Manifest, mediaType string) (ocispec.Descriptor, error) {
 	for _, layer := range manifest.Layers {
-		if layer.MediaType == "application/vnd.reglet.plugin.wasm.v1" {
+		if layer.MediaType == mediaType {
 			return layer, nil
 		}
 	}
-	return ocispec.Descriptor{}, fmt.Errorf("no WASM layer found")
+	return ocispec.Descriptor{}, fmt.Errorf("no %s layer found", mediaType)
 }
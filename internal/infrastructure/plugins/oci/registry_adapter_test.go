@@ -0,0 +1,106 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// fakeRegistry serves manifests and blobs of a single repository.
+type fakeRegistry struct {
+	manifests map[string][]byte // by tag and digest
+	blobs     map[string][]byte // by digest
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var data []byte
+	var ok bool
+	if ref, found := strings.CutPrefix(req.URL.Path, "/v2/acme/plugins/file/manifests/"); found {
+		data, ok = r.manifests[ref]
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+	} else if ref, found := strings.CutPrefix(req.URL.Path, "/v2/acme/plugins/file/blobs/"); found {
+		data, ok = r.blobs[ref]
+	}
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	w.Header().Set("Docker-Content-Digest", content.NewDescriptorFromBytes("", data).Digest.String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if req.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
+
+// push stores an artifact with the given layer under tag.
+func (r *fakeRegistry) push(t *testing.T, tag string, layer ocispec.Descriptor, layerData []byte) {
+	t.Helper()
+	config := []byte(`{"name":"file","version":"1.1.0"}`)
+	configDesc := content.NewDescriptorFromBytes("application/json", config)
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    configDesc,
+		Layers:    []ocispec.Descriptor{layer},
+	})
+	require.NoError(t, err)
+	r.blobs[configDesc.Digest.String()] = config
+	r.blobs[layer.Digest.String()] = layerData
+	r.manifests[tag] = manifest
+	r.manifests[content.NewDescriptorFromBytes("", manifest).Digest.String()] = manifest
+}
+
+func TestOCIRegistryAdapter_PullPatch(t *testing.T) {
+	t.Parallel()
+
+	wasm := []byte("wasm file 1.1.0")
+	wasmDesc := content.NewDescriptorFromBytes(MediaTypeWASM, wasm)
+	base, err := values.ParseDigest(content.NewDescriptorFromBytes("", []byte("wasm file 1.0.0")).Digest.String())
+	require.NoError(t, err)
+
+	pullPatch := func(t *testing.T, target string) (values.Digest, error) {
+		t.Helper()
+		registry := &fakeRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+		registry.push(t, "1.1.0", wasmDesc, wasm)
+		patch := []byte("patch")
+		patchDesc := content.NewDescriptorFromBytes(MediaTypePatch, patch)
+		patchDesc.Annotations = map[string]string{AnnotationPatchTarget: target}
+		registry.push(t, PatchTag("1.1.0", base), patchDesc, patch)
+
+		server := httptest.NewTLSServer(registry)
+		t.Cleanup(server.Close)
+		adapter := NewOCIRegistryAdapter(NewEnvAuthProvider())
+		adapter.SetTransport(server.Client().Transport)
+
+		ref := values.NewPluginReference(strings.TrimPrefix(server.URL, "https://"), "acme", "plugins", "file", "1.1.0")
+		result, err := adapter.PullPatch(context.Background(), ref, base)
+		if err != nil {
+			return values.Digest{}, err
+		}
+		require.NotNil(t, result)
+		assert.Equal(t, "patch", string(result.Patch))
+		return result.Plugin.Digest(), nil
+	}
+
+	got, err := pullPatch(t, wasmDesc.Digest.String())
+	require.NoError(t, err)
+	assert.Equal(t, wasmDesc.Digest.String(), got.String(), "the patched binary is verified against the version's manifest")
+
+	// A patch announcing a different binary is refused, so its author cannot
+	// vouch for its own output
+	_, err = pullPatch(t, content.NewDescriptorFromBytes("", []byte("evil")).Digest.String())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "produces")
+}
@@ -0,0 +1,61 @@
+package oci
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// NewTransport returns the HTTP transport registries are reached through.
+// caFile adds CAs to the system pool; a nil proxy uses the proxy
+// environment variables. A proxy URL carrying a login authenticates to the
+// proxy with it.
+func NewTransport(caFile string, proxy *url.URL) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(filepath.Clean(caFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read registry CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("registry CA file %s holds no PEM certificates", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return transport, nil
+}
+
+// rewriteRepository returns the repository a plugin is pulled from: name with
+// the longest mirror prefix it starts with replaced. Prefixes match whole
+// path segments.
+func rewriteRepository(name string, mirrors map[string]string) string {
+	prefixes := make([]string, 0, len(mirrors))
+	for prefix := range mirrors {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	for _, prefix := range prefixes {
+		trimmed := strings.TrimSuffix(prefix, "/")
+		if name == trimmed || strings.HasPrefix(name, trimmed+"/") {
+			return strings.TrimSuffix(mirrors[prefix], "/") + name[len(trimmed):]
+		}
+	}
+	return name
+}
@@ -122,12 +122,6 @@ func (r *FSPluginRepository) List(ctx context.Context) ([]*entities.Plugin, erro
 	return plugins, err
 }
 
-// Prune removes old versions.
-func (r *FSPluginRepository) Prune(ctx context.Context, keepVersions int) error {
-	// Group by plugin name, sort by version, delete old ones
-	return nil
-}
-
 // Delete removes a plugin.
 func (r *FSPluginRepository) Delete(ctx context.Context, ref values.PluginReference) error {
 	path := r.pluginPath(ref)
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+)
+
+// installRecordFile records the origin of an installed release next to its
+// binary.
+const installRecordFile = "install.json"
+
+// FSPluginInstallStore implements ports.PluginInstallStore on a plugin
+// directory. A release of plugin ns/name is installed as
+// <root>/ns/name/<version>/name.wasm, where declarations pinning a version
+// find it; the highest installed release is also copied to
+// <root>/ns/name/name.wasm for declarations without one.
+type FSPluginInstallStore struct {
+	root string
+}
+
+// NewFSPluginInstallStore creates an install store on the plugin directory
+// root.
+func NewFSPluginInstallStore(root string) *FSPluginInstallStore {
+	return &FSPluginInstallStore{root: root}
+}
+
+// installRecord is the content of install.json.
+type installRecord struct {
+	InstalledAt time.Time `json:"installed_at"`
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`
+	Source      string    `json:"source"`
+	Digest      string    `json:"digest"`
+
+	SignatureVerified bool `json:"signature_verified,omitempty"`
+}
+
+// Install writes the binary of a release and makes the highest installed
+// release of the plugin its default. It returns the path of the binary.
+func (s *FSPluginInstallStore) Install(_ context.Context, plugin dto.InstalledPlugin, wasm []byte) (string, error) {
+	pluginDir, short, err := s.pluginDir(plugin.Name)
+	if err != nil {
+		return "", err
+	}
+	releaseDir := filepath.Join(pluginDir, plugin.Version)
+	if err := os.MkdirAll(releaseDir, 0o750); err != nil {
+		return "", fmt.Errorf("create plugin directory: %w", err)
+	}
+
+	path := filepath.Join(releaseDir, short+".wasm")
+	if err := writeFileAtomic(path, wasm); err != nil {
+		return "", err
+	}
+	record, err := json.MarshalIndent(installRecord{
+		InstalledAt: plugin.InstalledAt,
+		Name:        plugin.Name,
+		Version:     plugin.Version,
+		Source:      plugin.Source,
+		Digest:      plugin.Digest,
+
+		SignatureVerified: plugin.SignatureVerified,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(filepath.Join(releaseDir, installRecordFile), append(record, '\n')); err != nil {
+		return "", err
+	}
+
+	if err := s.refreshDefault(pluginDir, short); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// List returns the installed releases, sorted by name and version.
+func (s *FSPluginInstallStore) List(_ context.Context) ([]dto.InstalledPlugin, error) {
+	records, err := filepath.Glob(filepath.Join(s.root, "*", "*", "*", installRecordFile))
+	if err != nil {
+		return nil, err
+	}
+	plugins := make([]dto.InstalledPlugin, 0, len(records))
+	for _, path := range records {
+		plugin, err := readInstallRecord(path)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, plugin)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		if plugins[i].Name != plugins[j].Name {
+			return plugins[i].Name < plugins[j].Name
+		}
+		return lessVersion(plugins[i].Version, plugins[j].Version)
+	})
+	return plugins, nil
+}
+
+// Remove removes a release of a plugin, or all its releases when version is
+// empty, and makes the highest remaining release its default. It returns the
+// releases removed.
+func (s *FSPluginInstallStore) Remove(ctx context.Context, name, version string) ([]dto.InstalledPlugin, error) {
+	pluginDir, short, err := s.pluginDir(name)
+	if err != nil {
+		return nil, err
+	}
+	installed, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []dto.InstalledPlugin
+	for _, plugin := range installed {
+		if plugin.Name != name || (version != "" && plugin.Version != version) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(pluginDir, plugin.Version)); err != nil {
+			return removed, fmt.Errorf("remove %s %s: %w", name, plugin.Version, err)
+		}
+		removed = append(removed, plugin)
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	if err := s.refreshDefault(pluginDir, short); err != nil {
+		return removed, err
+	}
+	// Leave no empty plugin or namespace directories behind
+	_ = os.Remove(pluginDir)
+	_ = os.Remove(filepath.Dir(pluginDir))
+	return removed, nil
+}
+
+// pluginDir returns the directory of plugin ns/name and its short name.
+func (s *FSPluginInstallStore) pluginDir(name string) (string, string, error) {
+	namespace, short, ok := strings.Cut(name, "/")
+	if !ok || namespace == "" || short == "" || strings.ContainsAny(short, `/\`) || namespace == ".." || short == ".." {
+		return "", "", fmt.Errorf("invalid plugin name %q", name)
+	}
+	return filepath.Join(s.root, namespace, short), short, nil
+}
+
+// refreshDefault copies the highest installed release of a plugin to its
+// default binary, or removes the default when no release is left.
+func (s *FSPluginInstallStore) refreshDefault(pluginDir, short string) error {
+	defaultPath := filepath.Join(pluginDir, short+".wasm")
+
+	entries, err := os.ReadDir(pluginDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	var highest string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(pluginDir, entry.Name(), installRecordFile)); err != nil {
+			continue
+		}
+		if highest == "" || lessVersion(highest, entry.Name()) {
+			highest = entry.Name()
+		}
+	}
+
+	if highest == "" {
+		if err := os.Remove(defaultPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove default plugin: %w", err)
+		}
+		return nil
+	}
+	wasm, err := os.ReadFile(filepath.Clean(filepath.Join(pluginDir, highest, short+".wasm")))
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(defaultPath, wasm)
+}
+
+// readInstallRecord reads the install.json of a release.
+func readInstallRecord(path string) (dto.InstalledPlugin, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return dto.InstalledPlugin{}, err
+	}
+	var record installRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return dto.InstalledPlugin{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	dir := filepath.Dir(path)
+	return dto.InstalledPlugin{
+		InstalledAt: record.InstalledAt,
+		Name:        record.Name,
+		Version:     record.Version,
+		Source:      record.Source,
+		Digest:      record.Digest,
+		Path:        filepath.Join(dir, filepath.Base(filepath.Dir(dir))+".wasm"),
+
+		SignatureVerified: record.SignatureVerified,
+	}, nil
+}
+
+// InstalledRelease returns the installed release whose binary is data, for
+// path being the binary of a release or the default binary of its plugin.
+// Files that were not installed by Install, or that changed since, have none.
+func InstalledRelease(path string, data []byte) (dto.InstalledPlugin, error) {
+	dir := filepath.Dir(path)
+	records, err := filepath.Glob(filepath.Join(dir, "*", installRecordFile))
+	if err != nil {
+		return dto.InstalledPlugin{}, err
+	}
+	records = append(records, filepath.Join(dir, installRecordFile))
+
+	for _, recordPath := range records {
+		plugin, err := readInstallRecord(recordPath)
+		if err != nil {
+			continue // not an install, or unreadable
+		}
+		if filepath.Base(plugin.Path) != filepath.Base(path) {
+			continue
+		}
+		digest, err := values.ParseDigest(plugin.Digest)
+		if err == nil && digest.Verify(data) == nil {
+			return plugin, nil
+		}
+	}
+	return dto.InstalledPlugin{}, fmt.Errorf("%s is not a release installed with \"reglet plugins install\"", path)
+}
+
+// lessVersion orders semantic versions, falling back to string order.
+func lessVersion(a, b string) bool {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return va.LessThan(vb)
+}
+
+// writeFileAtomic writes data to path through a temporary file, so readers
+// never load a partial binary.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
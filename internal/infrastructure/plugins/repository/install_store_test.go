@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSPluginInstallStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	root := t.TempDir()
+	store := NewFSPluginInstallStore(root)
+	defaultPath := filepath.Join(root, "reglet", "http", "http.wasm")
+
+	install := func(version string) string {
+		path, err := store.Install(ctx, dto.InstalledPlugin{
+			InstalledAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+			Name:        "reglet/http",
+			Version:     version,
+			Source:      "https://plugins.example.com/http-" + version + ".wasm",
+			Digest:      "sha256:" + version,
+		}, []byte("http "+version))
+		require.NoError(t, err)
+		return path
+	}
+	content := func(path string) string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	path := install("1.10.0")
+	assert.Equal(t, filepath.Join(root, "reglet", "http", "1.10.0", "http.wasm"), path)
+	install("1.9.0")
+	assert.Equal(t, "http 1.10.0", content(defaultPath), "the highest release is the default")
+
+	plugins, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, plugins, 2)
+	assert.Equal(t, "1.9.0", plugins[0].Version, "releases sort by version")
+	assert.Equal(t, "1.10.0", plugins[1].Version)
+	assert.Equal(t, path, plugins[1].Path)
+	assert.Equal(t, "https://plugins.example.com/http-1.10.0.wasm", plugins[1].Source)
+	assert.Equal(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), plugins[1].InstalledAt)
+
+	removed, err := store.Remove(ctx, "reglet/http", "1.10.0")
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "http 1.9.0", content(defaultPath), "the highest remaining release becomes the default")
+
+	removed, err = store.Remove(ctx, "reglet/http", "2.0.0")
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+
+	removed, err = store.Remove(ctx, "reglet/http", "")
+	require.NoError(t, err)
+	assert.Len(t, removed, 1)
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "no empty directories are left behind")
+
+	_, err = store.Install(ctx, dto.InstalledPlugin{Name: "http", Version: "1.0.0"}, nil)
+	assert.Error(t, err)
+}
+
+func TestInstalledRelease(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	binary := []byte("http 1.2.0")
+	digest, err := values.ComputeDigestSHA256(bytes.NewReader(binary))
+	require.NoError(t, err)
+	releasePath, err := NewFSPluginInstallStore(root).Install(context.Background(), dto.InstalledPlugin{
+		Name:              "reglet/http",
+		Version:           "1.2.0",
+		Source:            "ghcr.io/reglet-dev/plugins/http:1.2.0",
+		Digest:            digest.String(),
+		SignatureVerified: true,
+	}, binary)
+	require.NoError(t, err)
+
+	// Both the release binary and the default copy are found
+	for _, path := range []string{releasePath, filepath.Join(root, "reglet", "http", "http.wasm")} {
+		release, err := InstalledRelease(path, binary)
+		require.NoError(t, err, path)
+		assert.Equal(t, "1.2.0", release.Version)
+		assert.True(t, release.SignatureVerified)
+	}
+
+	_, err = InstalledRelease(releasePath, []byte("swapped"))
+	assert.Error(t, err, "a changed binary is not the installed release")
+	_, err = InstalledRelease(filepath.Join(root, "reglet", "dns", "dns.wasm"), binary)
+	assert.Error(t, err)
+}
@@ -0,0 +1,178 @@
+// Package policy evaluates organization policies over execution results.
+//
+// Rego bundles are evaluated with the opa CLI, which keeps the policy engine
+// (and its version) under the organization's control rather than linked into
+// the reglet binary.
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+const (
+	// DefaultQuery is the rule evaluated when no query is configured. Bundles
+	// define it as a set of findings in package reglet.
+	DefaultQuery = "data.reglet.findings"
+
+	// DefaultTimeout bounds a single policy evaluation when the context has no deadline.
+	DefaultTimeout = 60 * time.Second
+)
+
+// RegoPolicy evaluates a Rego policy bundle over the execution result with
+// `opa eval`. The result is passed as input, exactly as rendered by
+// --format json.
+type RegoPolicy struct {
+	bundle  string
+	opaPath string
+	query   string
+	timeout time.Duration
+}
+
+// Compile-time interface check
+var _ execution.ResultPolicy = (*RegoPolicy)(nil)
+
+// Option configures a RegoPolicy.
+type Option func(*RegoPolicy)
+
+// WithOPAPath sets the opa executable (default: opa from PATH).
+func WithOPAPath(path string) Option {
+	return func(p *RegoPolicy) {
+		p.opaPath = path
+	}
+}
+
+// WithQuery sets the Rego query producing findings (default DefaultQuery).
+func WithQuery(query string) Option {
+	return func(p *RegoPolicy) {
+		p.query = query
+	}
+}
+
+// WithTimeout sets the evaluation timeout used when the context has no deadline.
+func WithTimeout(d time.Duration) Option {
+	return func(p *RegoPolicy) {
+		p.timeout = d
+	}
+}
+
+// NewRegoPolicy creates a policy for the bundle at path (a directory or a
+// bundle .tar.gz).
+func NewRegoPolicy(bundle string, opts ...Option) *RegoPolicy {
+	p := &RegoPolicy{
+		bundle:  bundle,
+		opaPath: "opa",
+		query:   DefaultQuery,
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Evaluate runs the query and converts its value into findings. An undefined
+// query yields no findings.
+func (p *RegoPolicy) Evaluate(ctx context.Context, result *execution.ExecutionResult) ([]execution.PolicyFinding, error) {
+	input, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal policy input: %w", err)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, p.opaPath, "eval",
+		"--format", "json",
+		"--stdin-input",
+		"--bundle", p.bundle,
+		p.query)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("opa executable not found (install Open Policy Agent or set its path): %w", err)
+		}
+		return nil, fmt.Errorf("opa eval %s failed: %w: %s", p.bundle, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return parseEvalOutput(stdout.Bytes())
+}
+
+// evalOutput is the subset of `opa eval --format json` output used here.
+type evalOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+// parseEvalOutput extracts findings from the query value. The value must be a
+// set (array) whose members are finding objects or plain message strings.
+func parseEvalOutput(data []byte) ([]execution.PolicyFinding, error) {
+	var out evalOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse opa output: %w", err)
+	}
+	if len(out.Result) == 0 || len(out.Result[0].Expressions) == 0 {
+		return nil, nil // undefined: no findings
+	}
+
+	var members []json.RawMessage
+	if err := json.Unmarshal(out.Result[0].Expressions[0].Value, &members); err != nil {
+		return nil, fmt.Errorf("policy query must produce a set of findings: %w", err)
+	}
+
+	findings := make([]execution.PolicyFinding, 0, len(members))
+	for i, member := range members {
+		finding, err := parseFinding(member)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy finding %d: %w", i, err)
+		}
+		if finding.ID == "" {
+			finding.ID = fmt.Sprintf("finding-%d", i+1)
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// parseFinding accepts a finding object or a message string. Objects may use
+// "msg" for the message, following the deny-rule convention.
+func parseFinding(member json.RawMessage) (execution.PolicyFinding, error) {
+	var message string
+	if err := json.Unmarshal(member, &message); err == nil {
+		return execution.PolicyFinding{Message: message}, nil
+	}
+
+	var raw struct {
+		execution.PolicyFinding
+		Msg string `json:"msg"`
+	}
+	if err := json.Unmarshal(member, &raw); err != nil {
+		return execution.PolicyFinding{}, err
+	}
+	finding := raw.PolicyFinding
+	if finding.Message == "" {
+		finding.Message = raw.Msg
+	}
+	if finding.Message == "" {
+		return execution.PolicyFinding{}, fmt.Errorf("finding has no message")
+	}
+	return finding, nil
+}
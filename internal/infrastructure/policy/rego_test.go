@@ -0,0 +1,103 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEvalOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		output  string
+		want    []execution.PolicyFinding
+		wantErr string
+	}{
+		{
+			name:   "undefined",
+			output: `{}`,
+			want:   nil,
+		},
+		{
+			name:   "empty set",
+			output: `{"result":[{"expressions":[{"value":[],"text":"data.reglet.findings"}]}]}`,
+			want:   []execution.PolicyFinding{},
+		},
+		{
+			name: "objects and strings",
+			output: `{"result":[{"expressions":[{"value":[
+				{"id":"prod-medium","msg":"4 medium findings in prod","severity":"high","controls":["a","b"]},
+				"no owner tag"
+			]}]}]}`,
+			want: []execution.PolicyFinding{
+				{ID: "prod-medium", Message: "4 medium findings in prod", Severity: "high", Controls: []string{"a", "b"}},
+				{ID: "finding-2", Message: "no owner tag"},
+			},
+		},
+		{
+			name:    "not a set",
+			output:  `{"result":[{"expressions":[{"value":true}]}]}`,
+			wantErr: "must produce a set",
+		},
+		{
+			name:    "missing message",
+			output:  `{"result":[{"expressions":[{"value":[{"id":"x"}]}]}]}`,
+			wantErr: "has no message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseEvalOutput([]byte(tt.output))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegoPolicy_Evaluate(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake opa is a shell script")
+	}
+
+	// The fake opa checks its input and echoes its arguments as a finding
+	dir := t.TempDir()
+	opa := filepath.Join(dir, "opa")
+	script := `#!/bin/sh
+input=$(cat)
+case "$input" in *'"profile_name":"baseline"'*) ;; *) echo "bad input" >&2; exit 1;; esac
+printf '{"result":[{"expressions":[{"value":[{"id":"gate","message":"%s"}]}]}]}' "$*"
+`
+	require.NoError(t, os.WriteFile(opa, []byte(script), 0o755))
+
+	p := NewRegoPolicy("/policies", WithOPAPath(opa), WithQuery("data.acme.deny"))
+	findings, err := p.Evaluate(context.Background(), execution.NewExecutionResult("baseline", "1.0.0"))
+	require.NoError(t, err)
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, "gate", findings[0].ID)
+	assert.Equal(t, "eval --format json --stdin-input --bundle /policies data.acme.deny", findings[0].Message)
+}
+
+func TestRegoPolicy_MissingOPA(t *testing.T) {
+	t.Parallel()
+
+	p := NewRegoPolicy("/policies", WithOPAPath(filepath.Join(t.TempDir(), "missing-opa")))
+	_, err := p.Evaluate(context.Background(), execution.NewExecutionResult("baseline", "1.0.0"))
+	assert.ErrorContains(t, err, "opa eval /policies failed")
+}
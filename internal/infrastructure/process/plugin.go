@@ -0,0 +1,208 @@
+// Package process runs external process plugins: standalone executables that
+// speak the wireformat stdio protocol instead of being compiled to WASM. It is a
+// secondary transport for plugins that cannot target wasip1 (for example ones
+// that need CGO). Granted capabilities are mapped onto an OS-level SandboxProfile.
+package process
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/wireformat"
+)
+
+const (
+	// DefaultTimeout bounds a single plugin call when the context has no deadline.
+	DefaultTimeout = 60 * time.Second
+
+	// MaxOutputSize caps how much a plugin may write to stdout per call.
+	MaxOutputSize = 16 * 1024 * 1024 // 16MB
+)
+
+// ErrNotTrusted is returned for process plugins the user has not trusted;
+// their executables are never started.
+var ErrNotTrusted = errors.New("process plugin is not trusted")
+
+// CheckTrusted returns ErrNotTrusted unless name is one of the trusted
+// process plugins (the process_plugins user config).
+func CheckTrusted(name string, trusted []string) error {
+	if slices.Contains(trusted, name) {
+		return nil
+	}
+	return fmt.Errorf("%w: add %s to process_plugins in the user config to run %s",
+		ErrNotTrusted, name, values.ProcessPluginExecutable(name))
+}
+
+// Plugin is an external process plugin. Its methods match native.Plugin, so
+// results are parsed exactly like in-process and WASM plugins.
+type Plugin struct {
+	stderr  io.Writer
+	name    string
+	path    string
+	sandbox SandboxProfile
+	timeout time.Duration
+}
+
+// Option configures a Plugin.
+type Option func(*Plugin)
+
+// WithStderr sets where the plugin's stderr is written (default os.Stderr).
+func WithStderr(w io.Writer) Option {
+	return func(p *Plugin) {
+		p.stderr = w
+	}
+}
+
+// WithTimeout sets the per-call timeout used when the context has no deadline.
+func WithTimeout(d time.Duration) Option {
+	return func(p *Plugin) {
+		p.timeout = d
+	}
+}
+
+// NewPlugin creates an external process plugin for the executable at path.
+func NewPlugin(name, path string, sandbox SandboxProfile, opts ...Option) *Plugin {
+	p := &Plugin{
+		name:    name,
+		path:    path,
+		sandbox: sandbox,
+		stderr:  os.Stderr,
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Name returns the plugin name.
+func (p *Plugin) Name() string {
+	return p.name
+}
+
+// Describe returns the plugin metadata JSON.
+func (p *Plugin) Describe(ctx context.Context) ([]byte, error) {
+	return p.call(ctx, wireformat.ProcessMethodDescribe, nil)
+}
+
+// Schema returns the plugin's config JSON schema.
+func (p *Plugin) Schema(ctx context.Context) ([]byte, error) {
+	return p.call(ctx, wireformat.ProcessMethodSchema, nil)
+}
+
+// Observe runs the plugin with the given JSON config and returns evidence JSON.
+func (p *Plugin) Observe(ctx context.Context, config []byte) ([]byte, error) {
+	return p.call(ctx, wireformat.ProcessMethodObserve, config)
+}
+
+// call starts the plugin process for a single request and returns its stdout.
+func (p *Plugin) call(ctx context.Context, method string, config []byte) ([]byte, error) {
+	if _, ok := ctx.Deadline(); !ok && p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	request := wireformat.ProcessRequest{
+		ProtocolVersion: wireformat.ProcessProtocolVersion,
+		Method:          method,
+		Config:          config,
+		Context:         contextToWire(ctx),
+	}
+	if method == wireformat.ProcessMethodObserve {
+		request.Execution = executionToWire(ctx)
+	}
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+
+	stdout := &limitedBuffer{limit: MaxOutputSize}
+
+	//nolint:gosec // G204: path is a plugin executable resolved from a validated plugin name
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.Stdin = bytes.NewReader(requestData)
+	cmd.Stdout = stdout
+	cmd.Stderr = p.stderr
+	if err := p.sandbox.run(cmd); err != nil {
+		if errors.Is(err, ErrSandboxUnavailable) {
+			return nil, fmt.Errorf("process plugin %s: %w", p.name, err)
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("process plugin %s %s: %w", p.name, method, ctx.Err())
+		}
+		if stdout.exceeded {
+			return nil, fmt.Errorf("process plugin %s %s: output exceeds %d bytes", p.name, method, MaxOutputSize)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("process plugin %s %s exited with code %d", p.name, method, exitErr.ExitCode())
+		}
+		return nil, fmt.Errorf("failed to run process plugin %s: %w", p.name, err)
+	}
+
+	output := bytes.TrimSpace(stdout.buf.Bytes())
+	if len(output) == 0 {
+		return nil, fmt.Errorf("process plugin %s %s returned no output", p.name, method)
+	}
+	if !json.Valid(output) {
+		return nil, fmt.Errorf("process plugin %s %s returned invalid JSON", p.name, method)
+	}
+	return output, nil
+}
+
+// contextToWire converts a context's deadline into the wire format.
+func contextToWire(ctx context.Context) wireformat.ContextWireFormat {
+	wire := wireformat.ContextWireFormat{Canceled: ctx.Err() != nil}
+	if deadline, ok := ctx.Deadline(); ok {
+		wire.Deadline = &deadline
+		wire.TimeoutMs = time.Until(deadline).Milliseconds()
+	}
+	return wire
+}
+
+// executionToWire returns the run and observation ctx carries, or nil if it
+// carries neither.
+func executionToWire(ctx context.Context) *wireformat.ExecutionContextWire {
+	run, hasRun := execution.RunRefFromContext(ctx)
+	ref, hasRef := execution.ObservationRefFromContext(ctx)
+	if !hasRun && !hasRef {
+		return nil
+	}
+	key, _ := execution.IdempotencyKeyFromContext(ctx)
+	return &wireformat.ExecutionContextWire{
+		RunID:            run.ExecutionID,
+		Profile:          run.Profile,
+		Environment:      run.Environment,
+		ControlID:        ref.ControlID,
+		Plugin:           ref.Plugin,
+		ObservationIndex: ref.Index,
+		IdempotencyKey:   key,
+	}
+}
+
+// limitedBuffer collects output up to limit bytes and fails writes beyond it,
+// which terminates a plugin that floods stdout.
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(data []byte) (int, error) {
+	if b.buf.Len()+len(data) > b.limit {
+		b.exceeded = true
+		return 0, fmt.Errorf("output limit of %d bytes exceeded", b.limit)
+	}
+	return b.buf.Write(data)
+}
@@ -0,0 +1,171 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/wireformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeScript writes an executable shell script plugin and returns its path.
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	if err := sandboxSupported(); err != nil {
+		t.Skip(err)
+	}
+	path := filepath.Join(t.TempDir(), "reglet-plugin-test")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o700))
+	return path
+}
+
+func TestPlugin_Call(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string
+		want    string
+	}{
+		{
+			name:   "echoes request",
+			script: `cat`,
+		},
+		{
+			name:   "fixed evidence",
+			script: `echo '{"Status":true}'`,
+			want:   `{"Status":true}`,
+		},
+		{
+			name:    "nonzero exit",
+			script:  `echo boom >&2; exit 3`,
+			wantErr: "exited with code 3",
+		},
+		{
+			name:    "no output",
+			script:  `exit 0`,
+			wantErr: "returned no output",
+		},
+		{
+			name:    "invalid json",
+			script:  `echo not-json`,
+			wantErr: "returned invalid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sandbox := SandboxProfile{Network: true, Exec: []string{"cat"}}
+			p := NewPlugin("test", writeScript(t, tt.script), sandbox, WithStderr(&bytes.Buffer{}))
+			out, err := p.Observe(context.Background(), []byte(`{"path":"/etc/hosts"}`))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.want != "" {
+				assert.JSONEq(t, tt.want, string(out))
+				return
+			}
+
+			var req wireformat.ProcessRequest
+			require.NoError(t, json.Unmarshal(out, &req))
+			assert.Equal(t, wireformat.ProcessMethodObserve, req.Method)
+			assert.Equal(t, wireformat.ProcessProtocolVersion, req.ProtocolVersion)
+			assert.JSONEq(t, `{"path":"/etc/hosts"}`, string(req.Config))
+			assert.NotNil(t, req.Context.Deadline, "default timeout is propagated")
+		})
+	}
+}
+
+func TestPlugin_ObserveExecution(t *testing.T) {
+	t.Parallel()
+
+	sandbox := SandboxProfile{Network: true, Exec: []string{"cat"}}
+	p := NewPlugin("test", writeScript(t, `cat`), sandbox, WithStderr(&bytes.Buffer{}))
+
+	out, err := p.Observe(context.Background(), []byte(`{}`))
+	require.NoError(t, err)
+	var req wireformat.ProcessRequest
+	require.NoError(t, json.Unmarshal(out, &req))
+	assert.Nil(t, req.Execution, "nothing to send outside a run")
+
+	ctx := execution.WithRunRef(context.Background(), execution.RunRef{ExecutionID: "run-1", Profile: "baseline"})
+	ctx = execution.WithObservationRef(ctx, execution.ObservationRef{ControlID: "ssh", Plugin: "test", Index: 1})
+	out, err = p.Observe(ctx, []byte(`{}`))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(out, &req))
+	assert.Equal(t, &wireformat.ExecutionContextWire{
+		RunID:            "run-1",
+		Profile:          "baseline",
+		ControlID:        "ssh",
+		Plugin:           "test",
+		ObservationIndex: 1,
+	}, req.Execution)
+}
+
+func TestPlugin_Timeout(t *testing.T) {
+	t.Parallel()
+
+	p := NewPlugin("slow", writeScript(t, `exec sleep 10`), SandboxProfile{Network: true, Exec: []string{"sleep"}},
+		WithTimeout(100*time.Millisecond))
+
+	start := time.Now()
+	_, err := p.Describe(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestPlugin_EnvironmentFiltered(t *testing.T) {
+	t.Parallel()
+
+	sandbox := NewSandboxProfile(
+		[]capabilities.Capability{{Kind: "env", Pattern: "REGLET_OK_*"}, {Kind: "network", Pattern: "*"}},
+		[]string{"REGLET_OK_A=1", "SECRET_TOKEN=x"},
+	)
+	p := NewPlugin("env", writeScript(t, `printf '{"a":"%s","secret":"%s"}' "$REGLET_OK_A" "$SECRET_TOKEN"`), sandbox)
+
+	out, err := p.Schema(context.Background())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":"1","secret":""}`, string(out))
+}
+
+func TestNewSandboxProfile(t *testing.T) {
+	t.Parallel()
+
+	profile := NewSandboxProfile([]capabilities.Capability{
+		{Kind: "fs", Pattern: "read:/etc/**"},
+		{Kind: "fs", Pattern: "write:/tmp/out"},
+		{Kind: "exec", Pattern: "/usr/bin/systemctl"},
+		{Kind: "env", Pattern: "HOME"},
+	}, []string{"HOME=/root", "PATH=/usr/bin", "malformed"})
+
+	assert.Equal(t, []string{"HOME=/root"}, profile.Env)
+	assert.Equal(t, []string{"/etc/**"}, profile.ReadPaths)
+	assert.Equal(t, []string{"/tmp/out"}, profile.WritePaths)
+	assert.Equal(t, []string{"/usr/bin/systemctl"}, profile.Exec)
+	assert.False(t, profile.Network)
+}
+
+func TestCheckTrusted(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, CheckTrusted("ldap", []string{"ldap"}))
+
+	err := CheckTrusted("evil", []string{"ldap"})
+	require.ErrorIs(t, err, ErrNotTrusted)
+	assert.Contains(t, err.Error(), "process_plugins")
+}
@@ -0,0 +1,80 @@
+package process
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+)
+
+// ErrSandboxUnavailable is returned when the OS cannot enforce the sandbox of
+// a process plugin, so the plugin is not started.
+var ErrSandboxUnavailable = errors.New("process plugin sandbox is not available")
+
+// SandboxProfile is the OS-level sandbox for an external process plugin,
+// derived from its granted capabilities.
+//
+// Enforcement:
+//   - env: only granted variables are passed; the rest of the host environment is dropped.
+//   - network: without a network grant the plugin runs in an empty network
+//     namespace (no interfaces besides loopback).
+//   - fs, exec: Landlock limits the plugin and everything it starts to the
+//     granted paths and executables, plus read access to the system
+//     libraries it needs to load.
+//
+// The sandbox needs Linux with Landlock; elsewhere process plugins do not run.
+type SandboxProfile struct {
+	Env        []string // KEY=VALUE pairs passed to the process
+	ReadPaths  []string
+	WritePaths []string
+	Exec       []string
+	Network    bool
+}
+
+// NewSandboxProfile maps granted capabilities onto a sandbox profile.
+// hostEnv is the environment snapshot to filter (typically os.Environ()).
+func NewSandboxProfile(granted []capabilities.Capability, hostEnv []string) SandboxProfile {
+	var profile SandboxProfile
+	var envPatterns []string
+
+	for _, c := range granted {
+		switch c.Kind {
+		case "env":
+			envPatterns = append(envPatterns, c.Pattern)
+		case "network":
+			profile.Network = true
+		case "exec":
+			profile.Exec = append(profile.Exec, c.Pattern)
+		case "fs":
+			switch {
+			case strings.HasPrefix(c.Pattern, "read:"):
+				profile.ReadPaths = append(profile.ReadPaths, strings.TrimPrefix(c.Pattern, "read:"))
+			case strings.HasPrefix(c.Pattern, "write:"):
+				profile.WritePaths = append(profile.WritePaths, strings.TrimPrefix(c.Pattern, "write:"))
+			}
+		}
+	}
+
+	for _, envVar := range hostEnv {
+		key, _, ok := strings.Cut(envVar, "=")
+		if !ok {
+			continue
+		}
+		for _, pattern := range envPatterns {
+			if capabilities.MatchEnvironmentPattern(key, pattern) {
+				profile.Env = append(profile.Env, envVar)
+				break
+			}
+		}
+	}
+
+	return profile
+}
+
+// run runs cmd inside the sandbox and waits for it to exit.
+func (s SandboxProfile) run(cmd *exec.Cmd) error {
+	// A non-nil empty slice gives the child an empty environment rather than ours
+	cmd.Env = append([]string{}, s.Env...)
+	return runSandboxed(cmd, s)
+}
@@ -0,0 +1,345 @@
+//go:build linux
+
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"debug/elf"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Landlock access rights, grouped by what a grant allows.
+const (
+	accessExecute = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_READ_FILE
+	accessRead    = unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR
+	accessWrite   = accessRead | unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR | unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR | unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK | unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM | unix.LANDLOCK_ACCESS_FS_REFER |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	accessCreate = unix.LANDLOCK_ACCESS_FS_MAKE_REG | unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE
+
+	// accessFile are the rights that apply to files; a rule on a file may
+	// hold no others
+	accessFile = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE |
+		unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+)
+
+// systemReadPaths are readable by every process plugin, so dynamically
+// linked executables can load their libraries.
+var systemReadPaths = []string{
+	"/lib", "/lib32", "/lib64", "/usr/lib", "/usr/lib32", "/usr/lib64", "/usr/local/lib",
+	"/etc/ld.so.cache", "/dev/urandom", "/dev/random",
+}
+
+// landlockRule grants access to a path and everything beneath it.
+type landlockRule struct {
+	Path   string `json:"path"`
+	Access uint64 `json:"access"`
+}
+
+// The sandbox is entered by re-executing this binary as sandboxArg0, with the
+// Landlock rules in sandboxEnv. It applies the rules and then executes the
+// plugin, so Landlock is enforced from the plugin's first instruction while
+// the parent stays unrestricted (it still has to set up the namespaces).
+const (
+	sandboxArg0 = "reglet-process-sandbox"
+	sandboxEnv  = "_REGLET_PROCESS_SANDBOX"
+)
+
+func init() {
+	spec, ok := os.LookupEnv(sandboxEnv)
+	if !ok || len(os.Args) != 2 || os.Args[0] != sandboxArg0 {
+		return
+	}
+	err := execSandboxed(spec, os.Args[1])
+	_, _ = fmt.Fprintf(os.Stderr, "reglet: process plugin sandbox: %v\n", err)
+	os.Exit(125)
+}
+
+// runSandboxed runs cmd in a Landlock domain limited to the profile's grants,
+// and without a network grant in an empty network namespace.
+func runSandboxed(cmd *exec.Cmd, s SandboxProfile) error {
+	if err := sandboxSupported(); err != nil {
+		return err
+	}
+
+	attr := &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+	if !s.Network {
+		attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}}
+	}
+	cmd.SysProcAttr = attr
+
+	spec, err := json.Marshal(s.landlockRules(cmd.Path))
+	if err != nil {
+		return fmt.Errorf("failed to encode sandbox rules: %w", err)
+	}
+	cmd.Env = append(cmd.Env, sandboxEnv+"="+string(spec))
+	cmd.Args = []string{sandboxArg0, cmd.Path}
+	cmd.Path = "/proc/self/exe"
+	return cmd.Run()
+}
+
+// sandboxSupported returns ErrSandboxUnavailable if the kernel has no
+// Landlock.
+func sandboxSupported() error {
+	if _, err := landlockABI(); err != nil {
+		return fmt.Errorf("%w: Landlock is not enabled in this kernel: %w", ErrSandboxUnavailable, err)
+	}
+	return nil
+}
+
+// execSandboxed restricts this process to the rules in spec and replaces it
+// with the plugin at path. It only returns on failure.
+func execSandboxed(spec, path string) error {
+	var rules []landlockRule
+	if err := json.Unmarshal([]byte(spec), &rules); err != nil {
+		return fmt.Errorf("invalid sandbox rules: %w", err)
+	}
+	abi, err := landlockABI()
+	if err != nil {
+		return fmt.Errorf("Landlock is not enabled in this kernel: %w", err)
+	}
+	ruleset, err := newLandlockRuleset(abi, rules)
+	if err != nil {
+		return err
+	}
+
+	// Landlock restricts the calling thread, which execve then makes the
+	// whole process
+	runtime.LockOSThread()
+	if err := restrictThread(ruleset); err != nil {
+		return err
+	}
+	env := make([]string, 0, len(os.Environ()))
+	for _, pair := range os.Environ() {
+		if !strings.HasPrefix(pair, sandboxEnv+"=") {
+			env = append(env, pair)
+		}
+	}
+	return syscall.Exec(path, []string{path}, env) //nolint:gosec // G204: path is the plugin executable the parent resolved
+}
+
+// landlockRules returns the paths the plugin at executable may access.
+func (s SandboxProfile) landlockRules(executable string) []landlockRule {
+	rules := []landlockRule{{Path: "/dev/null", Access: unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_WRITE_FILE}}
+	for _, path := range systemReadPaths {
+		rules = append(rules, landlockRule{Path: path, Access: accessRead})
+	}
+	rules = append(rules, executableRules(executable)...)
+
+	for _, pattern := range s.ReadPaths {
+		rules = append(rules, landlockRule{Path: grantRoot(pattern), Access: accessRead})
+	}
+	for _, pattern := range s.WritePaths {
+		path := grantRoot(pattern)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			// Files that do not exist yet are created in their directory,
+			// without access to what else is in it
+			rules = append(rules, landlockRule{Path: filepath.Dir(path), Access: accessCreate})
+			continue
+		}
+		rules = append(rules, landlockRule{Path: path, Access: accessWrite})
+	}
+	for _, pattern := range s.Exec {
+		switch {
+		case pattern == "*" || pattern == "**":
+			// Any program may run, but scripts still need their file granted
+			rules = append(rules, landlockRule{Path: "/", Access: unix.LANDLOCK_ACCESS_FS_EXECUTE})
+		case strings.ContainsAny(pattern, "*?["):
+			if !strings.Contains(pattern, "/") {
+				slog.Debug("process plugin exec grant is not a path, skipping", "pattern", pattern)
+				continue
+			}
+			rules = append(rules, landlockRule{Path: grantRoot(pattern), Access: unix.LANDLOCK_ACCESS_FS_EXECUTE})
+		default:
+			path, err := exec.LookPath(pattern)
+			if err != nil {
+				slog.Debug("process plugin exec grant not found", "pattern", pattern, "error", err)
+				continue
+			}
+			rules = append(rules, executableRules(path)...)
+		}
+	}
+	return rules
+}
+
+// executableRules allow running the executable at path, along with the
+// script interpreter or dynamic loader it is started with.
+func executableRules(path string) []landlockRule {
+	rules := []landlockRule{{Path: path, Access: accessExecute}}
+	for depth := 0; depth < 4; depth++ {
+		interpreter := interpreterOf(path)
+		if interpreter == "" {
+			break
+		}
+		rules = append(rules, landlockRule{Path: interpreter, Access: accessExecute})
+		path = interpreter
+	}
+	return rules
+}
+
+// interpreterOf returns the "#!" interpreter of a script, or the dynamic
+// loader of an ELF executable; "" if it has neither.
+func interpreterOf(path string) string {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	line, _ := bufio.NewReader(f).ReadString('\n')
+	if rest, ok := strings.CutPrefix(line, "#!"); ok {
+		if fields := strings.Fields(rest); len(fields) > 0 {
+			return fields[0]
+		}
+		return ""
+	}
+
+	file, err := elf.NewFile(f)
+	if err != nil {
+		return ""
+	}
+	for _, prog := range file.Progs {
+		if prog.Type != elf.PT_INTERP {
+			continue
+		}
+		data := make([]byte, prog.Filesz)
+		if _, err := prog.ReadAt(data, 0); err != nil {
+			return ""
+		}
+		return string(bytes.TrimRight(data, "\x00"))
+	}
+	return ""
+}
+
+// grantRoot returns the path a grant pattern covers: the pattern itself, or
+// for globs the directory before the first wildcard. Landlock rules cover a
+// whole directory tree, so "/var/log/*.log" allows all of /var/log.
+func grantRoot(pattern string) string {
+	if i := strings.IndexAny(pattern, "*?["); i >= 0 {
+		pattern = filepath.Dir(pattern[:i] + "x")
+	}
+	path, err := filepath.Abs(pattern)
+	if err != nil {
+		return filepath.Clean(pattern)
+	}
+	return path
+}
+
+// landlockABI returns the Landlock ABI version of the kernel.
+func landlockABI() (int, error) {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(abi), nil
+}
+
+// handledAccess returns the filesystem rights the kernel's Landlock ABI
+// knows; all of them are denied unless a rule grants them.
+func handledAccess(abi int) uint64 {
+	access := uint64(unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR | unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR | unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG | unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO | unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM)
+	if abi >= 2 {
+		access |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		access |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	if abi >= 5 {
+		access |= unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	}
+	return access
+}
+
+// newLandlockRuleset creates a ruleset granting rules and returns its file
+// descriptor. Paths that do not exist are skipped.
+func newLandlockRuleset(abi int, rules []landlockRule) (int, error) {
+	handled := handledAccess(abi)
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	if abi >= 6 {
+		// The plugin may not signal processes outside its domain
+		attr.Scoped = unix.LANDLOCK_SCOPE_SIGNAL
+	}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return -1, fmt.Errorf("failed to create Landlock ruleset: %w", errno)
+	}
+	ruleset := int(fd)
+
+	for _, rule := range rules {
+		if err := addLandlockRule(ruleset, rule, handled); err != nil {
+			_ = unix.Close(ruleset)
+			return -1, err
+		}
+	}
+	return ruleset, nil
+}
+
+// addLandlockRule adds rule to the ruleset, limited to the rights its file
+// type can hold.
+func addLandlockRule(ruleset int, rule landlockRule, handled uint64) error {
+	fd, err := unix.Open(rule.Path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		slog.Debug("process plugin sandbox path unavailable, skipping", "path", rule.Path, "error", err)
+		return nil
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	access := rule.Access & handled
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return fmt.Errorf("failed to stat %s: %w", rule.Path, err)
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= accessFile
+	}
+	if access == 0 {
+		return nil
+	}
+
+	attr := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)} //nolint:gosec // G115: file descriptors fit in int32
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset),
+		unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&attr)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to add Landlock rule for %s: %w", rule.Path, errno)
+	}
+	return nil
+}
+
+// restrictThread enforces the ruleset on the calling thread and the
+// processes it starts.
+func restrictThread(ruleset int) error {
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, uintptr(ruleset), 0, 0); errno != 0 {
+		return fmt.Errorf("failed to enforce Landlock ruleset: %w", errno)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+//go:build linux
+
+package process
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandbox_NetworkIsolation(t *testing.T) {
+	t.Parallel()
+
+	if err := exec.Command("unshare", "-Urn", "true").Run(); err != nil {
+		t.Skip("unprivileged user namespaces are not available")
+	}
+
+	// Without a network grant only the loopback interface is visible
+	script := `n=$(tail -n +3 /proc/self/net/dev | wc -l); printf '{"interfaces":%s}' "$n"`
+	sandbox := SandboxProfile{ReadPaths: []string{"/proc/**"}, Exec: []string{"tail", "wc"}}
+	p := NewPlugin("net", writeScript(t, script), sandbox, WithStderr(&bytes.Buffer{}))
+
+	out, err := p.Observe(context.Background(), []byte(`{}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"interfaces":1}`, string(out))
+}
+
+func TestSandbox_Filesystem(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	granted := filepath.Join(dir, "granted")
+	require.NoError(t, os.Mkdir(granted, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(granted, "motd"), []byte("hello\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret"), []byte("token\n"), 0o600))
+	out := filepath.Join(dir, "out")
+
+	// Reads, writes and commands outside the grants fail
+	script := `
+check() { if eval "$1" >/dev/null 2>&1; then printf true; else printf false; fi; }
+printf '{"granted":%s,"secret":%s,"write":%s,"exec":%s}' \
+  "$(check 'read -r l < ` + granted + `/motd')" \
+  "$(check 'read -r l < ` + dir + `/secret')" \
+  "$(check 'echo x > ` + out + `')" \
+  "$(check 'cat ` + granted + `/motd')"`
+	sandbox := SandboxProfile{Network: true, ReadPaths: []string{granted + "/**"}}
+	p := NewPlugin("fs", writeScript(t, script), sandbox, WithStderr(&bytes.Buffer{}))
+
+	result, err := p.Observe(context.Background(), []byte(`{}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"granted":true,"secret":false,"write":false,"exec":false}`, string(result))
+	assert.NoFileExists(t, out)
+
+	// Granted writes and commands work
+	sandbox.WritePaths = []string{out}
+	sandbox.Exec = []string{"cat"}
+	p = NewPlugin("fs", writeScript(t, script), sandbox, WithStderr(&bytes.Buffer{}))
+
+	result, err = p.Observe(context.Background(), []byte(`{}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"granted":true,"secret":false,"write":true,"exec":true}`, string(result))
+	assert.FileExists(t, out)
+}
+
+func TestGrantRoot(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/etc/hosts", grantRoot("/etc/hosts"))
+	assert.Equal(t, "/etc", grantRoot("/etc/**"))
+	assert.Equal(t, "/var/log", grantRoot("/var/log/*.log"))
+	assert.Equal(t, "/", grantRoot("/**"))
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// runSandboxed refuses to run the plugin: outside Linux there is no way to
+// enforce its filesystem, exec and network grants.
+func runSandboxed(_ *exec.Cmd, _ SandboxProfile) error {
+	return sandboxSupported()
+}
+
+// sandboxSupported returns ErrSandboxUnavailable.
+func sandboxSupported() error {
+	return fmt.Errorf("%w on %s: process plugins need Linux with Landlock", ErrSandboxUnavailable, runtime.GOOS)
+}
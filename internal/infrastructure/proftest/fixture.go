@@ -0,0 +1,152 @@
+package proftest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/native"
+)
+
+// FixturePlugin is a native plugin that answers observations from fixtures.
+// One instance is registered under every plugin name a profile uses; it tells
+// them apart by the observation the engine attaches to the context.
+type FixturePlugin struct {
+	fixtures []fixture
+}
+
+// Ensure interface compliance
+var _ native.Plugin = (*FixturePlugin)(nil)
+
+// fixture is a fixture with config and data normalized to their JSON form,
+// so numbers compare and evaluate as they would coming from a real plugin.
+type fixture struct {
+	config   map[string]interface{}
+	evidence []byte
+	spec     dto.ProfileTestFixture
+}
+
+// NewFixturePlugin prepares fixtures for matching.
+func NewFixturePlugin(fixtures []dto.ProfileTestFixture) (*FixturePlugin, error) {
+	p := &FixturePlugin{fixtures: make([]fixture, 0, len(fixtures))}
+	for i, spec := range fixtures {
+		f, err := newFixture(spec)
+		if err != nil {
+			return nil, fmt.Errorf("fixture %d: %w", i, err)
+		}
+		p.fixtures = append(p.fixtures, f)
+	}
+	return p, nil
+}
+
+// NewFixtureRegistry returns a native registry serving fixtures under each of
+// the given plugin names.
+func NewFixtureRegistry(fixtures []dto.ProfileTestFixture, plugins []string) (*native.Registry, error) {
+	p, err := NewFixturePlugin(fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := native.NewRegistry()
+	for _, name := range plugins {
+		if _, exists := registry.Lookup(name); exists {
+			continue
+		}
+		if err := registry.Register(name, p); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Describe reports the plugin as a fixture stand-in.
+func (p *FixturePlugin) Describe(_ context.Context) ([]byte, error) {
+	return json.Marshal(map[string]string{"name": "fixture", "version": "0.0.0"})
+}
+
+// Schema accepts any config.
+func (p *FixturePlugin) Schema(_ context.Context) ([]byte, error) {
+	return []byte(`{}`), nil
+}
+
+// Observe returns the evidence of the first fixture matching the observation.
+func (p *FixturePlugin) Observe(ctx context.Context, config []byte) ([]byte, error) {
+	ref, ok := execution.ObservationRefFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("fixture plugin called outside of a control")
+	}
+
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	for _, f := range p.fixtures {
+		if f.matches(ref, cfg) {
+			return f.evidence, nil
+		}
+	}
+	return nil, fmt.Errorf("no fixture matches observation %d of control %s (plugin %s)", ref.Index, ref.ControlID, ref.Plugin)
+}
+
+func newFixture(spec dto.ProfileTestFixture) (fixture, error) {
+	f := fixture{spec: spec}
+
+	if spec.Config != nil {
+		if err := roundTrip(spec.Config, &f.config); err != nil {
+			return fixture{}, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+
+	evidence := execution.Evidence{Status: spec.Error == nil}
+	if spec.Status != nil {
+		evidence.Status = *spec.Status
+	}
+	if spec.Error != nil {
+		evidence.Error = &execution.PluginError{Code: spec.Error.Code, Message: spec.Error.Message}
+	}
+	if spec.Data != nil {
+		if err := roundTrip(spec.Data, &evidence.Data); err != nil {
+			return fixture{}, fmt.Errorf("invalid data: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(evidence)
+	if err != nil {
+		return fixture{}, fmt.Errorf("failed to encode evidence: %w", err)
+	}
+	f.evidence = data
+	return f, nil
+}
+
+// matches reports whether every selector of the fixture holds for the observation.
+func (f fixture) matches(ref execution.ObservationRef, cfg map[string]interface{}) bool {
+	if f.spec.Control != "" && f.spec.Control != ref.ControlID {
+		return false
+	}
+	if f.spec.Observation != nil && *f.spec.Observation != ref.Index {
+		return false
+	}
+	if f.spec.Plugin != "" && f.spec.Plugin != ref.Plugin {
+		return false
+	}
+	for key, want := range f.config {
+		got, ok := cfg[key]
+		if !ok || !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// roundTrip converts YAML-decoded values to what JSON decoding produces.
+func roundTrip(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,107 @@
+package proftest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func observe(t *testing.T, p *FixturePlugin, ref execution.ObservationRef, config string) (execution.Evidence, error) {
+	t.Helper()
+	data, err := p.Observe(execution.WithObservationRef(context.Background(), ref), []byte(config))
+	if err != nil {
+		return execution.Evidence{}, err
+	}
+	var evidence execution.Evidence
+	require.NoError(t, json.Unmarshal(data, &evidence))
+	return evidence, nil
+}
+
+func TestFixturePlugin_Observe(t *testing.T) {
+	t.Parallel()
+
+	second := 1
+	unhealthy := false
+	p, err := NewFixturePlugin([]dto.ProfileTestFixture{
+		{Control: "ssh-readable", Error: &dto.ProfileTestError{Code: "permission_denied", Message: "permission denied"}},
+		{Control: "multi", Observation: &second, Data: map[string]interface{}{"which": "second"}},
+		{Plugin: "http", Status: &unhealthy, Data: map[string]interface{}{"status_code": 503}},
+		{Config: map[string]interface{}{"path": "/etc/ssh/sshd_config", "port": 22}, Data: map[string]interface{}{"size": 0}},
+		{Data: map[string]interface{}{"which": "fallback"}},
+	})
+	require.NoError(t, err)
+
+	t.Run("control selector reports error", func(t *testing.T) {
+		t.Parallel()
+		ev, err := observe(t, p, execution.ObservationRef{ControlID: "ssh-readable", Plugin: "file"}, `{}`)
+		require.NoError(t, err)
+		assert.False(t, ev.Status)
+		require.NotNil(t, ev.Error)
+		assert.Equal(t, "permission_denied", ev.Error.Code)
+	})
+
+	t.Run("observation selector", func(t *testing.T) {
+		t.Parallel()
+		ev, err := observe(t, p, execution.ObservationRef{ControlID: "multi", Plugin: "file", Index: 1}, `{}`)
+		require.NoError(t, err)
+		assert.Equal(t, "second", ev.Data["which"])
+
+		ev, err = observe(t, p, execution.ObservationRef{ControlID: "multi", Plugin: "file", Index: 0}, `{}`)
+		require.NoError(t, err)
+		assert.Equal(t, "fallback", ev.Data["which"])
+	})
+
+	t.Run("plugin selector with status override", func(t *testing.T) {
+		t.Parallel()
+		ev, err := observe(t, p, execution.ObservationRef{ControlID: "web", Plugin: "http"}, `{}`)
+		require.NoError(t, err)
+		assert.False(t, ev.Status)
+		assert.Nil(t, ev.Error)
+		assert.Equal(t, float64(503), ev.Data["status_code"])
+	})
+
+	t.Run("config subset selector", func(t *testing.T) {
+		t.Parallel()
+		ev, err := observe(t, p, execution.ObservationRef{ControlID: "ssh", Plugin: "file"}, `{"path": "/etc/ssh/sshd_config", "port": 22, "mode": "stat"}`)
+		require.NoError(t, err)
+		assert.True(t, ev.Status)
+		assert.Equal(t, float64(0), ev.Data["size"])
+
+		ev, err = observe(t, p, execution.ObservationRef{ControlID: "ssh", Plugin: "file"}, `{"path": "/etc/ssh/sshd_config", "port": 2222}`)
+		require.NoError(t, err)
+		assert.Equal(t, "fallback", ev.Data["which"])
+	})
+}
+
+func TestFixturePlugin_NoMatch(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewFixturePlugin([]dto.ProfileTestFixture{{Control: "other"}})
+	require.NoError(t, err)
+
+	_, err = observe(t, p, execution.ObservationRef{ControlID: "ssh", Plugin: "file", Index: 2}, `{}`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no fixture matches observation 2 of control ssh (plugin file)")
+
+	_, err = p.Observe(context.Background(), []byte(`{}`))
+	assert.Error(t, err)
+}
+
+func TestNewFixtureRegistry(t *testing.T) {
+	t.Parallel()
+
+	registry, err := NewFixtureRegistry(nil, []string{"file", "http", "file"})
+	require.NoError(t, err)
+
+	for _, name := range []string{"file", "http"} {
+		_, ok := registry.Lookup(name)
+		assert.True(t, ok, name)
+	}
+	_, ok := registry.Lookup("dns")
+	assert.False(t, ok)
+}
@@ -0,0 +1,144 @@
+// Package proftest loads profile unit test suites (*_test.yaml) and answers
+// their observations from fixture evidence, so profiles can be tested without
+// touching real infrastructure.
+package proftest
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/application/ports"
+)
+
+// suiteSuffixes are the file name endings of test suites.
+var suiteSuffixes = []string{"_test.yaml", "_test.yml"}
+
+// SuiteLoader reads test suites from the filesystem.
+type SuiteLoader struct{}
+
+// Ensure interface compliance
+var _ ports.ProfileTestSuiteLoader = (*SuiteLoader)(nil)
+
+// NewSuiteLoader creates a new suite loader.
+func NewSuiteLoader() *SuiteLoader {
+	return &SuiteLoader{}
+}
+
+// DiscoverSuites expands paths into suite files. Files are used as given;
+// directories are searched recursively for *_test.yaml files.
+func (l *SuiteLoader) DiscoverSuites(paths []string) ([]string, error) {
+	var suites []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			suites = append(suites, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && isSuiteFile(d.Name()) {
+				suites = append(suites, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s for test suites: %w", path, err)
+		}
+	}
+	sort.Strings(suites)
+	return suites, nil
+}
+
+// LoadSuite reads and validates a suite file.
+func (l *SuiteLoader) LoadSuite(path string) (*dto.ProfileTestSuite, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: user-specified suite path is intentional
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test suite: %w", err)
+	}
+	suite, err := ParseSuite(data)
+	if err != nil {
+		return nil, err
+	}
+	suite.Path = path
+	return suite, nil
+}
+
+// ParseSuite parses and validates a suite.
+func ParseSuite(data []byte) (*dto.ProfileTestSuite, error) {
+	var suite dto.ProfileTestSuite
+	if err := yaml.UnmarshalWithOptions(data, &suite, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("failed to parse test suite: %w", err)
+	}
+	if err := validateSuite(&suite); err != nil {
+		return nil, err
+	}
+	return &suite, nil
+}
+
+func isSuiteFile(name string) bool {
+	for _, suffix := range suiteSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateSuite(suite *dto.ProfileTestSuite) error {
+	if suite.Profile == "" {
+		return fmt.Errorf("test suite: profile is required")
+	}
+	if len(suite.Tests) == 0 {
+		return fmt.Errorf("test suite: at least one test is required")
+	}
+
+	names := make(map[string]bool, len(suite.Tests))
+	for i, tc := range suite.Tests {
+		if tc.Name == "" {
+			return fmt.Errorf("test %d: name is required", i)
+		}
+		if names[tc.Name] {
+			return fmt.Errorf("test %q is declared twice", tc.Name)
+		}
+		names[tc.Name] = true
+
+		for j, fixture := range tc.Fixtures {
+			if fixture.Observation != nil && *fixture.Observation < 0 {
+				return fmt.Errorf("test %q: fixture %d: observation must not be negative", tc.Name, j)
+			}
+			if fixture.Error != nil && fixture.Error.Message == "" {
+				return fmt.Errorf("test %q: fixture %d: error message is required", tc.Name, j)
+			}
+		}
+
+		if len(tc.Expect) == 0 {
+			return fmt.Errorf("test %q: at least one expectation is required", tc.Name)
+		}
+		for j, exp := range tc.Expect {
+			if exp.Control == "" {
+				return fmt.Errorf("test %q: expectation %d: control is required", tc.Name, j)
+			}
+			if exp.Status != "" {
+				if err := exp.Status.Validate(); err != nil {
+					return fmt.Errorf("test %q: expectation %d: %w", tc.Name, j, err)
+				}
+			}
+			if exp.Status == "" && exp.Message == "" && exp.MessageContains == "" {
+				return fmt.Errorf("test %q: expectation %d: set status, message or message_contains", tc.Name, j)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package proftest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSuite(t *testing.T) {
+	t.Parallel()
+
+	suite, err := ParseSuite([]byte(`
+profile: ssh.yaml
+tests:
+  - name: empty config fails
+    fixtures:
+      - plugin: file
+        observation: 0
+        config:
+          path: /etc/ssh/sshd_config
+        data:
+          size: 0
+      - control: ssh-readable
+        error:
+          code: permission_denied
+          message: permission denied
+    expect:
+      - control: ssh-not-empty
+        status: fail
+        message_contains: failed
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "ssh.yaml", suite.Profile)
+	require.Len(t, suite.Tests, 1)
+	tc := suite.Tests[0]
+	require.Len(t, tc.Fixtures, 2)
+	assert.Equal(t, "file", tc.Fixtures[0].Plugin)
+	require.NotNil(t, tc.Fixtures[0].Observation)
+	assert.Equal(t, 0, *tc.Fixtures[0].Observation)
+	assert.Equal(t, "permission_denied", tc.Fixtures[1].Error.Code)
+	assert.Equal(t, values.StatusFail, tc.Expect[0].Status)
+	assert.Equal(t, "failed", tc.Expect[0].MessageContains)
+}
+
+func TestParseSuite_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"no profile", "tests: [{name: a, expect: [{control: c, status: pass}]}]", "profile is required"},
+		{"no tests", "profile: p.yaml", "at least one test"},
+		{"unnamed test", "profile: p.yaml\ntests: [{expect: [{control: c, status: pass}]}]", "name is required"},
+		{"duplicate test", "profile: p.yaml\ntests: [{name: a, expect: [{control: c, status: pass}]}, {name: a, expect: [{control: c, status: pass}]}]", "declared twice"},
+		{"negative observation", "profile: p.yaml\ntests: [{name: a, fixtures: [{observation: -1}], expect: [{control: c, status: pass}]}]", "must not be negative"},
+		{"error without message", "profile: p.yaml\ntests: [{name: a, fixtures: [{error: {code: x}}], expect: [{control: c, status: pass}]}]", "error message is required"},
+		{"no expectations", "profile: p.yaml\ntests: [{name: a}]", "at least one expectation"},
+		{"expectation without control", "profile: p.yaml\ntests: [{name: a, expect: [{status: pass}]}]", "control is required"},
+		{"invalid status", "profile: p.yaml\ntests: [{name: a, expect: [{control: c, status: passed}]}]", "expectation 0"},
+		{"empty expectation", "profile: p.yaml\ntests: [{name: a, expect: [{control: c}]}]", "set status, message or message_contains"},
+		{"unknown field", "profile: p.yaml\nprofiles: []", "failed to parse test suite"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseSuite([]byte(tt.yaml))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSuiteLoader_DiscoverSuites(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"b_test.yaml", "profile.yaml", "nested/a_test.yml", "nested/notes.txt"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+	explicit := filepath.Join(dir, "profile.yaml")
+
+	suites, err := NewSuiteLoader().DiscoverSuites([]string{dir, explicit})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "b_test.yaml"),
+		filepath.Join(dir, "nested", "a_test.yml"),
+		explicit,
+	}, suites)
+
+	_, err = NewSuiteLoader().DiscoverSuites([]string{filepath.Join(dir, "missing")})
+	assert.Error(t, err)
+}
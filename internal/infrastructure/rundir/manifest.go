@@ -0,0 +1,154 @@
+package rundir
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	// ManifestFormat identifies the manifest of a run directory.
+	ManifestFormat = "reglet-run-manifest"
+	// ManifestVersion is the manifest layout version this build writes.
+	ManifestVersion = 1
+)
+
+// ErrManifestMismatch is returned when a file of a run directory does not
+// match its manifest.
+var ErrManifestMismatch = errors.New("run directory does not match its manifest")
+
+// Manifest lists the files of a run directory with their sizes and SHA-256
+// digests, for consumers of the run to check them.
+type Manifest struct {
+	Format        string          `json:"format"`
+	ExecutionID   string          `json:"execution_id"`
+	Files         []ManifestEntry `json:"files"` // by path
+	FormatVersion int             `json:"format_version"`
+}
+
+// ManifestEntry is a file of a run directory.
+type ManifestEntry struct {
+	Path   string `json:"path"`   // relative to the run directory, slash-separated
+	Digest string `json:"digest"` // "sha256:<hex>"
+	Size   int64  `json:"size"`
+}
+
+// ReadManifest reads the manifest of a run directory.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile)) //nolint:gosec // G304: user-specified run directory is intentional
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.Format != ManifestFormat {
+		return nil, fmt.Errorf("invalid manifest: format %q", manifest.Format)
+	}
+	if manifest.FormatVersion > ManifestVersion {
+		return nil, fmt.Errorf("manifest version %d is newer than supported (%d): upgrade reglet", manifest.FormatVersion, ManifestVersion)
+	}
+	return &manifest, nil
+}
+
+// Verify checks files of a run directory against its manifest: the given
+// paths, or every file it lists when none are given. A listed file that was
+// changed or removed, or a given path the manifest does not list, fails with
+// ErrManifestMismatch.
+func Verify(dir string, paths ...string) error {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	entries := manifest.Files
+	if len(paths) > 0 {
+		byPath := make(map[string]ManifestEntry, len(manifest.Files))
+		for _, entry := range manifest.Files {
+			byPath[entry.Path] = entry
+		}
+		entries = make([]ManifestEntry, 0, len(paths))
+		for _, path := range paths {
+			entry, ok := byPath[path]
+			if !ok {
+				return fmt.Errorf("%w: %s is not listed", ErrManifestMismatch, path)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, entry := range entries {
+		if !filepath.IsLocal(filepath.FromSlash(entry.Path)) {
+			return fmt.Errorf("%w: %s is outside the run directory", ErrManifestMismatch, entry.Path)
+		}
+		actual, err := describe(dir, entry.Path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("%w: %s is missing", ErrManifestMismatch, entry.Path)
+			}
+			return err
+		}
+		if actual.Size != entry.Size || actual.Digest != entry.Digest {
+			return fmt.Errorf("%w: %s was modified", ErrManifestMismatch, entry.Path)
+		}
+	}
+	return nil
+}
+
+// buildManifest lists every file in dir.
+func buildManifest(dir, executionID string) (*Manifest, error) {
+	manifest := &Manifest{
+		Format:        ManifestFormat,
+		FormatVersion: ManifestVersion,
+		ExecutionID:   executionID,
+		Files:         []ManifestEntry{},
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == ManifestFile {
+			return nil
+		}
+		entry, err := describe(dir, rel)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	sort.Slice(manifest.Files, func(i, j int) bool { return manifest.Files[i].Path < manifest.Files[j].Path })
+	return manifest, nil
+}
+
+// describe returns the size and digest of a file of a run directory.
+func describe(dir, path string) (ManifestEntry, error) {
+	file, err := os.Open(filepath.Join(dir, filepath.FromSlash(path))) //nolint:gosec // G304: path is inside the run directory
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return ManifestEntry{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return ManifestEntry{Path: path, Digest: "sha256:" + hex.EncodeToString(hash.Sum(nil)), Size: size}, nil
+}
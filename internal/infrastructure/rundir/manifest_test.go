@@ -0,0 +1,94 @@
+package rundir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitRun writes a run directory with a report artifact.
+func commitRun(t *testing.T) string {
+	t.Helper()
+	run, err := Create(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(run.ArtifactPath("report.txt"), []byte("PASS ssh\n"), 0o600))
+	dir, err := run.Commit(execution.NewExecutionResult("web", "1.0.0"))
+	require.NoError(t, err)
+	return dir
+}
+
+func TestManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := commitRun(t)
+	manifest, err := ReadManifest(dir)
+	require.NoError(t, err)
+
+	paths := make([]string, len(manifest.Files))
+	for i, entry := range manifest.Files {
+		paths[i] = entry.Path
+	}
+	assert.Equal(t, []string{"artifacts/report.txt", EventsFile, "logs/reglet.log", ResultsFile}, paths)
+	report := manifest.Files[0]
+	assert.Equal(t, int64(9), report.Size)
+	assert.Equal(t, "sha256:58b3ecaaa2dd8e09f2aaa38155d57c2207466c520b8940ab825820988b43876a", report.Digest)
+
+	require.NoError(t, Verify(dir))
+	require.NoError(t, Verify(dir, ResultsFile))
+}
+
+func TestVerify_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, dir string)
+		paths  []string
+		want   string
+	}{
+		{
+			name: "modified",
+			tamper: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, ArtifactsDir, "report.txt"), []byte("PASS all\n"), 0o600))
+			},
+			want: "artifacts/report.txt was modified",
+		},
+		{
+			name: "missing",
+			tamper: func(t *testing.T, dir string) {
+				require.NoError(t, os.Remove(filepath.Join(dir, EventsFile)))
+			},
+			want: "events.jsonl is missing",
+		},
+		{
+			name:   "not listed",
+			tamper: func(*testing.T, string) {},
+			paths:  []string{"artifacts/extra.txt"},
+			want:   "artifacts/extra.txt is not listed",
+		},
+		{
+			name: "outside",
+			tamper: func(t *testing.T, dir string) {
+				manifest := `{"format": "reglet-run-manifest", "format_version": 1, "files": [{"path": "../secret", "size": 0, "digest": ""}]}`
+				require.NoError(t, os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0o600))
+			},
+			want: "../secret is outside the run directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := commitRun(t)
+			tt.tamper(t, dir)
+
+			err := Verify(dir, tt.paths...)
+			require.ErrorIs(t, err, ErrManifestMismatch)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,296 @@
+// Package rundir lays out the files of a run in one directory of a work
+// directory: the result, the events of the run as it progressed, its log,
+// the provenance of reglet and its plugins, and artifacts such as rendered
+// reports. A run directory is written under a hidden staging name and
+// renamed into place once complete, so readers never see a partial run, and
+// its manifest lets them check that its files are the ones the run wrote.
+package rundir
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+// Layout of a run directory.
+const (
+	ResultsFile    = "results.json"    // the execution result, as --format json writes it
+	EventsFile     = "events.jsonl"    // control and observation events, one JSON object per line
+	ProvenanceFile = "provenance.json" // builds of reglet and the plugins used
+	ManifestFile   = "manifest.json"   // size and digest of every other file
+	ArtifactsDir   = "artifacts"       // files produced for the run, e.g. rendered reports
+	LogsDir        = "logs"            // logs of the run
+	LogFile        = "reglet.log"      // log of the run in logs/, JSON lines
+)
+
+// stagingPrefix starts the names of run directories still being written.
+const stagingPrefix = ".run-"
+
+// Run is a run directory being written.
+type Run struct {
+	workDir string
+	staging string
+
+	events   *os.File
+	eventsMu sync.Mutex
+	log      *os.File
+	logMu    sync.Mutex
+}
+
+// Create starts a run directory in workDir, creating workDir if needed.
+func Create(workDir string) (*Run, error) {
+	if err := os.MkdirAll(workDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	staging, err := os.MkdirTemp(workDir, stagingPrefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create run directory: %w", err)
+	}
+	r := &Run{workDir: workDir, staging: staging}
+
+	for _, dir := range []string{ArtifactsDir, LogsDir} {
+		if err := os.Mkdir(filepath.Join(staging, dir), 0o750); err != nil {
+			r.Discard()
+			return nil, fmt.Errorf("failed to create run directory: %w", err)
+		}
+	}
+	r.events, err = os.OpenFile(filepath.Join(staging, EventsFile), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err == nil {
+		r.log, err = os.OpenFile(filepath.Join(staging, LogsDir, LogFile), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	}
+	if err != nil {
+		r.Discard()
+		return nil, fmt.Errorf("failed to create run directory: %w", err)
+	}
+	return r, nil
+}
+
+// ArtifactPath returns the path of an artifact of the run.
+func (r *Run) ArtifactPath(name string) string {
+	return filepath.Join(r.staging, ArtifactsDir, filepath.Base(name))
+}
+
+// Logger returns a logger writing both to base and to the log of the run.
+// The log of the run records every level, whatever base filters out.
+func (r *Run) Logger(base *slog.Logger) *slog.Logger {
+	file := slog.NewJSONHandler(lockedWriter{r}, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return slog.New(teeHandler{base.Handler(), file})
+}
+
+// Hooks returns hooks recording the events of the run in events.jsonl.
+func (r *Run) Hooks() execution.Hooks {
+	return eventHooks{run: r}
+}
+
+// Commit writes the result, its provenance and the manifest of the run
+// directory, and moves the run directory into place as <start
+// time>-<execution ID>. It returns the path of the run directory.
+func (r *Run) Commit(result *execution.ExecutionResult) (string, error) {
+	if err := writeJSON(filepath.Join(r.staging, ResultsFile), result); err != nil {
+		r.Discard()
+		return "", err
+	}
+	if result.Provenance != nil {
+		if err := writeJSON(filepath.Join(r.staging, ProvenanceFile), result.Provenance); err != nil {
+			r.Discard()
+			return "", err
+		}
+	}
+	if err := r.close(); err != nil {
+		r.Discard()
+		return "", fmt.Errorf("failed to write run directory: %w", err)
+	}
+	manifest, err := buildManifest(r.staging, result.ExecutionID.String())
+	if err == nil {
+		err = writeJSON(filepath.Join(r.staging, ManifestFile), manifest)
+	}
+	if err != nil {
+		r.Discard()
+		return "", err
+	}
+
+	dir := filepath.Join(r.workDir, Name(result))
+	if err := os.Rename(r.staging, dir); err != nil {
+		r.Discard()
+		return "", fmt.Errorf("failed to commit run directory: %w", err)
+	}
+	return dir, nil
+}
+
+// Discard removes the run directory of a run that did not complete.
+func (r *Run) Discard() {
+	_ = r.close()
+	_ = os.RemoveAll(r.staging)
+}
+
+// close closes the event and log files.
+func (r *Run) close() error {
+	var errs []error
+	r.eventsMu.Lock()
+	if r.events != nil {
+		errs = append(errs, r.events.Close())
+		r.events = nil
+	}
+	r.eventsMu.Unlock()
+	r.logMu.Lock()
+	if r.log != nil {
+		errs = append(errs, r.log.Close())
+		r.log = nil
+	}
+	r.logMu.Unlock()
+	return errors.Join(errs...)
+}
+
+// Name returns the name of the run directory of a result: its start time
+// in UTC, so run directories sort by time, and its execution ID.
+func Name(result *execution.ExecutionResult) string {
+	return result.StartTime.UTC().Format("20060102T150405Z") + "-" + result.ExecutionID.String()
+}
+
+// IsRunDir reports whether path is a complete run directory.
+func IsRunDir(path string) bool {
+	for _, name := range []string{ResultsFile, ManifestFile} {
+		info, err := os.Stat(filepath.Join(path, name))
+		if err != nil || !info.Mode().IsRegular() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsStaging reports whether a directory name is that of a run directory
+// still being written, which readers skip.
+func IsStaging(name string) bool {
+	return strings.HasPrefix(name, stagingPrefix)
+}
+
+// ResultPath returns the result file of a run directory, or path itself
+// when it is not a run directory.
+func ResultPath(path string) string {
+	if IsRunDir(path) {
+		return filepath.Join(path, ResultsFile)
+	}
+	return path
+}
+
+// writeJSON writes value as indented JSON to path.
+func writeJSON(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// event is a line of events.jsonl.
+type event struct {
+	Time        time.Time `json:"time"`
+	Event       string    `json:"event"`
+	Control     string    `json:"control"`
+	Plugin      string    `json:"plugin,omitempty"`
+	Observation *int      `json:"observation,omitempty"` // index within the control
+	Status      string    `json:"status,omitempty"`
+	DurationMs  *int64    `json:"duration_ms,omitempty"`
+}
+
+// Event names of events.jsonl.
+const (
+	EventControlStart     = "control_start"
+	EventControlEnd       = "control_end"
+	EventObservationStart = "observation_start"
+	EventObservationEnd   = "observation_end"
+)
+
+// eventHooks appends the events of a run to events.jsonl.
+type eventHooks struct {
+	run *Run
+}
+
+func (h eventHooks) OnControlStart(_ context.Context, control execution.ControlResult) {
+	h.write(event{Event: EventControlStart, Control: control.ID})
+}
+
+func (h eventHooks) OnControlEnd(_ context.Context, control execution.ControlResult) {
+	ms := control.Duration.Milliseconds()
+	h.write(event{Event: EventControlEnd, Control: control.ID, Status: string(control.Status), DurationMs: &ms})
+}
+
+func (h eventHooks) OnObservationStart(_ context.Context, ref execution.ObservationRef) {
+	index := ref.Index
+	h.write(event{Event: EventObservationStart, Control: ref.ControlID, Plugin: ref.Plugin, Observation: &index})
+}
+
+func (h eventHooks) OnObservationEnd(_ context.Context, ref execution.ObservationRef, result execution.ObservationResult) {
+	index := ref.Index
+	ms := result.Duration.Milliseconds()
+	h.write(event{Event: EventObservationEnd, Control: ref.ControlID, Plugin: ref.Plugin, Observation: &index, Status: string(result.Status), DurationMs: &ms})
+}
+
+// write appends an event. Events of a closed run are dropped.
+func (h eventHooks) write(e event) {
+	e.Time = time.Now().UTC()
+	line, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	h.run.eventsMu.Lock()
+	defer h.run.eventsMu.Unlock()
+	if h.run.events != nil {
+		_, _ = h.run.events.Write(append(line, '\n'))
+	}
+}
+
+// lockedWriter appends to the log of a run. Records logged after the run
+// was committed or discarded are dropped.
+type lockedWriter struct {
+	run *Run
+}
+
+func (w lockedWriter) Write(p []byte) (int, error) {
+	w.run.logMu.Lock()
+	defer w.run.logMu.Unlock()
+	if w.run.log == nil {
+		return len(p), nil
+	}
+	return w.run.log.Write(p)
+}
+
+// teeHandler sends records to two handlers.
+type teeHandler struct {
+	primary, secondary slog.Handler
+}
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return t.primary.Enabled(ctx, level) || t.secondary.Enabled(ctx, level)
+}
+
+func (t teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	if t.primary.Enabled(ctx, record.Level) {
+		errs = append(errs, t.primary.Handle(ctx, record.Clone()))
+	}
+	if t.secondary.Enabled(ctx, record.Level) {
+		errs = append(errs, t.secondary.Handle(ctx, record))
+	}
+	return errors.Join(errs...)
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return teeHandler{t.primary.WithAttrs(attrs), t.secondary.WithAttrs(attrs)}
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	return teeHandler{t.primary.WithGroup(name), t.secondary.WithGroup(name)}
+}
@@ -0,0 +1,114 @@
+package rundir
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// entries returns the names in dir.
+func entries(t *testing.T, dir string) []string {
+	t.Helper()
+	list, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := make([]string, len(list))
+	for i, entry := range list {
+		names[i] = entry.Name()
+	}
+	return names
+}
+
+func TestRun_Commit(t *testing.T) {
+	t.Parallel()
+
+	workDir := filepath.Join(t.TempDir(), "runs")
+	run, err := Create(workDir)
+	require.NoError(t, err)
+
+	// The run is staged under a hidden name until committed
+	names := entries(t, workDir)
+	require.Len(t, names, 1)
+	assert.True(t, IsStaging(names[0]))
+	assert.False(t, IsRunDir(filepath.Join(workDir, names[0])))
+
+	hooks := run.Hooks()
+	ctx := context.Background()
+	ref := execution.ObservationRef{ControlID: "ssh", Plugin: "file", Index: 0}
+	hooks.OnControlStart(ctx, execution.ControlResult{ID: "ssh"})
+	hooks.OnObservationStart(ctx, ref)
+	hooks.OnObservationEnd(ctx, ref, execution.ObservationResult{Status: values.StatusPass, Duration: 3 * time.Millisecond})
+	hooks.OnControlEnd(ctx, execution.ControlResult{ID: "ssh", Status: values.StatusPass})
+
+	logger := run.Logger(slog.New(slog.NewTextHandler(&strings.Builder{}, &slog.HandlerOptions{Level: slog.LevelError})))
+	logger.Debug("resolving plugin", "plugin", "file")
+
+	require.NoError(t, os.WriteFile(run.ArtifactPath("report.txt"), []byte("PASS ssh\n"), 0o600))
+
+	result := execution.NewExecutionResult("web", "1.0.0")
+	result.StartTime = time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	result.Provenance = &execution.Provenance{Reglet: execution.BuildInfo{Version: "1.2.0"}}
+	dir, err := run.Commit(result)
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(workDir, "20260301T123000Z-"+result.ExecutionID.String()), dir)
+	assert.Equal(t, []string{filepath.Base(dir)}, entries(t, workDir), "the staging directory is renamed")
+	assert.True(t, IsRunDir(dir))
+	assert.Equal(t, filepath.Join(dir, ResultsFile), ResultPath(dir))
+	assert.ElementsMatch(t, []string{ResultsFile, EventsFile, ProvenanceFile, ManifestFile, ArtifactsDir, LogsDir}, entries(t, dir))
+	assert.Equal(t, []string{"report.txt"}, entries(t, filepath.Join(dir, ArtifactsDir)))
+
+	data, err := os.ReadFile(filepath.Join(dir, ResultsFile))
+	require.NoError(t, err)
+	var stored execution.ExecutionResult
+	require.NoError(t, json.Unmarshal(data, &stored))
+	assert.Equal(t, result.ExecutionID, stored.ExecutionID)
+
+	file, err := os.Open(filepath.Join(dir, EventsFile))
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	var events []event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, e)
+	}
+	require.Len(t, events, 4)
+	assert.Equal(t, EventControlStart, events[0].Event)
+	assert.Equal(t, EventObservationEnd, events[2].Event)
+	assert.Equal(t, "file", events[2].Plugin)
+	assert.Equal(t, "pass", events[2].Status)
+	assert.Equal(t, int64(3), *events[2].DurationMs)
+
+	log, err := os.ReadFile(filepath.Join(dir, LogsDir, LogFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(log), "resolving plugin", "the run log records debug messages")
+
+	// Events after the commit are dropped
+	hooks.OnControlStart(ctx, execution.ControlResult{ID: "late"})
+	run.Discard()
+	assert.True(t, IsRunDir(dir), "discarding a committed run keeps it")
+}
+
+func TestRun_Discard(t *testing.T) {
+	t.Parallel()
+
+	workDir := t.TempDir()
+	run, err := Create(workDir)
+	require.NoError(t, err)
+	run.Discard()
+
+	assert.Empty(t, entries(t, workDir))
+	assert.Equal(t, filepath.Join(workDir, "result.json"), ResultPath(filepath.Join(workDir, "result.json")))
+}
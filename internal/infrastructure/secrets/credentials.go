@@ -0,0 +1,278 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// CredentialsPassphraseEnv names the environment variable holding the
+// passphrase the credential store key is derived from.
+const CredentialsPassphraseEnv = "REGLET_CREDENTIALS_PASSPHRASE"
+
+const (
+	credentialsVersion = 1
+	kdfPassphrase      = "pbkdf2-sha256"
+	kdfKeyFile         = "keyfile"
+	pbkdf2Iterations   = 600_000
+	keySize            = 32
+)
+
+// credentialsFile is the on-disk form of the credential store. The names
+// and values of the credentials are only stored encrypted.
+type credentialsFile struct {
+	KDF        string `json:"kdf"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+	Version    int    `json:"version"`
+}
+
+// CredentialStore keeps named credentials (integration tokens, registry
+// logins) in an AES-256-GCM encrypted file, so they need not appear in
+// config files or shell history. The key is derived from a passphrase.
+//
+// A store may instead be written with a random key kept in path + ".key",
+// if the user opts in with AllowKeyFile. That key sits next to the store
+// with the same permissions, so it keeps credentials out of config files
+// but protects nothing against anyone who can read the store.
+type CredentialStore struct {
+	path         string
+	passphrase   string
+	mu           sync.Mutex
+	allowKeyFile bool
+}
+
+// NewCredentialStore opens the credential store at path, encrypted with a
+// key derived from passphrase.
+func NewCredentialStore(path, passphrase string) *CredentialStore {
+	return &CredentialStore{path: path, passphrase: passphrase}
+}
+
+// AllowKeyFile lets the store be written without a passphrase, with its key
+// in a file next to it. Stores written this way are not protected at rest.
+func (s *CredentialStore) AllowKeyFile() {
+	s.allowKeyFile = true
+}
+
+// Path returns the path of the store file.
+func (s *CredentialStore) Path() string {
+	return s.path
+}
+
+// Get returns the credential name, or false if it is not stored.
+func (s *CredentialStore) Get(name string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	credentials, err := s.load()
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := credentials[name]
+	return value, ok, nil
+}
+
+// Set stores the credential name, replacing any previous value.
+func (s *CredentialStore) Set(name, value string) error {
+	if name == "" {
+		return errors.New("credential name is empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	credentials, err := s.load()
+	if err != nil {
+		return err
+	}
+	credentials[name] = value
+	return s.save(credentials)
+}
+
+// Delete removes the credential name. It returns false if it was not stored.
+func (s *CredentialStore) Delete(name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	credentials, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := credentials[name]; !ok {
+		return false, nil
+	}
+	delete(credentials, name)
+	return true, s.save(credentials)
+}
+
+// Names returns the names of the stored credentials, sorted.
+func (s *CredentialStore) Names() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	credentials, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(credentials))
+	for name := range credentials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// load decrypts the store; a missing store is empty.
+func (s *CredentialStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(filepath.Clean(s.path))
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]string), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credential store: %w", err)
+	}
+
+	var file credentialsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("credential store %s is corrupt: %w", s.path, err)
+	}
+	if file.Version != credentialsVersion {
+		return nil, fmt.Errorf("credential store %s has unsupported version %d", s.path, file.Version)
+	}
+
+	key, err := s.key(file.KDF, file.Salt, false)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credential store %s: wrong passphrase or key", s.path)
+	}
+
+	credentials := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &credentials); err != nil {
+		return nil, fmt.Errorf("credential store %s is corrupt: %w", s.path, err)
+	}
+	return credentials, nil
+}
+
+// save encrypts the credentials with a fresh nonce (and salt) and replaces
+// the store file.
+func (s *CredentialStore) save(credentials map[string]string) error {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+
+	file := credentialsFile{Version: credentialsVersion, KDF: kdfKeyFile}
+	switch {
+	case s.passphrase != "":
+		file.KDF = kdfPassphrase
+		file.Salt = make([]byte, 16)
+		if _, err := rand.Read(file.Salt); err != nil {
+			return err
+		}
+	case !s.allowKeyFile:
+		return fmt.Errorf("set %s to encrypt the credential store %s", CredentialsPassphraseEnv, s.path)
+	}
+	key, err := s.key(file.KDF, file.Salt, true)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Ciphertext = gcm.Seal(nil, file.Nonce, plaintext, nil)
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create credential store directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write credential store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write credential store: %w", err)
+	}
+	if file.KDF == kdfPassphrase {
+		// A store moved to a passphrase no longer needs its old key file
+		if err := os.Remove(s.path + ".key"); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove credential key file: %w", err)
+		}
+	}
+	return nil
+}
+
+// key returns the encryption key of the store. A key file is created when
+// create is set and it does not exist yet.
+func (s *CredentialStore) key(kdf string, salt []byte, create bool) ([]byte, error) {
+	switch kdf {
+	case kdfPassphrase:
+		if s.passphrase == "" {
+			return nil, fmt.Errorf("credential store %s is protected by a passphrase: set %s", s.path, CredentialsPassphraseEnv)
+		}
+		return pbkdf2.Key(sha256.New, s.passphrase, salt, pbkdf2Iterations, keySize)
+	case kdfKeyFile:
+		return s.keyFile(create)
+	default:
+		return nil, fmt.Errorf("credential store %s uses unknown key derivation %q", s.path, kdf)
+	}
+}
+
+func (s *CredentialStore) keyFile(create bool) ([]byte, error) {
+	path := s.path + ".key"
+	key, err := os.ReadFile(filepath.Clean(path))
+	if err == nil {
+		if len(key) != keySize {
+			return nil, fmt.Errorf("credential key file %s is corrupt", path)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) || !create {
+		return nil, fmt.Errorf("failed to read credential key file: %w", err)
+	}
+
+	key = make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create credential store directory: %w", err)
+	}
+	if err := os.WriteFile(path, key, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write credential key file: %w", err)
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,137 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/sensitivedata"
+	"github.com/reglet-dev/reglet/internal/infrastructure/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialStore_KeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	store := NewCredentialStore(path, "")
+	store.AllowKeyFile()
+
+	names, err := store.Names()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	require.NoError(t, store.Set("slack_token", "xoxb-secret"))
+	require.NoError(t, store.Set("jira_token", "jira-secret"))
+
+	// Values are only stored encrypted
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "xoxb-secret")
+	assert.NotContains(t, string(data), "slack_token")
+
+	info, err := os.Stat(path + ".key")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// A second store on the same file reads the credentials back
+	reopened := NewCredentialStore(path, "")
+	reopened.AllowKeyFile()
+	value, ok, err := reopened.Get("slack_token")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "xoxb-secret", value)
+
+	names, err = reopened.Names()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jira_token", "slack_token"}, names)
+
+	removed, err := reopened.Delete("jira_token")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = reopened.Delete("jira_token")
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	_, ok, err = store.Get("jira_token")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestCredentialStore_Passphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	require.NoError(t, NewCredentialStore(path, "correct horse").Set("token", "secret"))
+
+	assert.NoFileExists(t, path+".key")
+
+	value, ok, err := NewCredentialStore(path, "correct horse").Get("token")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "secret", value)
+
+	_, _, err = NewCredentialStore(path, "wrong").Get("token")
+	assert.ErrorContains(t, err, "wrong passphrase")
+
+	_, _, err = NewCredentialStore(path, "").Get("token")
+	assert.ErrorContains(t, err, CredentialsPassphraseEnv)
+}
+
+func TestCredentialStore_RequiresPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	err := NewCredentialStore(path, "").Set("token", "secret")
+	assert.ErrorContains(t, err, CredentialsPassphraseEnv)
+	assert.NoFileExists(t, path)
+	assert.NoFileExists(t, path+".key")
+}
+
+func TestCredentialStore_MovesKeyFileToPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	store := NewCredentialStore(path, "")
+	store.AllowKeyFile()
+	require.NoError(t, store.Set("token", "secret"))
+	require.FileExists(t, path+".key")
+
+	// Writing with a passphrase re-encrypts the store and drops the key file
+	require.NoError(t, NewCredentialStore(path, "correct horse").Set("other", "value"))
+	assert.NoFileExists(t, path+".key")
+
+	value, ok, err := NewCredentialStore(path, "correct horse").Get("token")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "secret", value)
+}
+
+func TestCredentialStore_MissingKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	store := NewCredentialStore(path, "")
+	store.AllowKeyFile()
+	require.NoError(t, store.Set("token", "secret"))
+	require.NoError(t, os.Remove(path+".key"))
+
+	_, _, err := NewCredentialStore(path, "").Get("token")
+	assert.ErrorContains(t, err, "key file")
+}
+
+func TestResolver_CredentialStore(t *testing.T) {
+	store := NewCredentialStore(filepath.Join(t.TempDir(), "credentials.enc"), "correct horse")
+	require.NoError(t, store.Set("slack_token", "stored-value"))
+	require.NoError(t, store.Set("shadowed", "stored-value"))
+
+	provider := sensitivedata.NewProvider()
+	resolver := NewResolver(&system.SecretsConfig{
+		Local: map[string]string{"shadowed": "local-value"},
+	}, provider)
+	resolver.SetCredentials(store)
+
+	value, err := resolver.Resolve("slack_token")
+	require.NoError(t, err)
+	assert.Equal(t, "stored-value", value)
+	assert.Contains(t, provider.AllValues(), "stored-value")
+
+	// Mapped sources take precedence over the store
+	value, err = resolver.Resolve("shadowed")
+	require.NoError(t, err)
+	assert.Equal(t, "local-value", value)
+
+	_, err = resolver.Resolve("unknown")
+	assert.ErrorContains(t, err, "credential store")
+}
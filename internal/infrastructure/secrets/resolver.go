@@ -17,10 +17,11 @@ import (
 // Resolver implements ports.SecretResolver.
 // It resolves secrets from configured sources and automatically tracks them for redaction.
 type Resolver struct {
-	config   *system.SecretsConfig
-	provider ports.SensitiveValueProvider // For auto-tracking
-	cache    map[string]string
-	mu       sync.RWMutex
+	config      *system.SecretsConfig
+	provider    ports.SensitiveValueProvider // For auto-tracking
+	credentials *CredentialStore
+	cache       map[string]string
+	mu          sync.RWMutex
 }
 
 // NewResolver creates a new secret resolver.
@@ -35,8 +36,16 @@ func NewResolver(
 	}
 }
 
+// SetCredentials adds the encrypted credential store as the last source of
+// secrets.
+func (r *Resolver) SetCredentials(store *CredentialStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.credentials = store
+}
+
 // Resolve returns the secret value by name.
-// It checks sources in order: Local -> Env -> Files.
+// It checks sources in order: Local -> Env -> Files -> Credential store.
 // The resolved value is automatically tracked for redaction.
 func (r *Resolver) Resolve(name string) (string, error) {
 	r.mu.RLock()
@@ -108,5 +117,16 @@ func (r *Resolver) resolveFromSources(name string) (string, error) {
 		return strings.TrimSpace(string(data)), nil
 	}
 
-	return "", fmt.Errorf("secret %q not found in local, env, or files", name)
+	// 4. Check the encrypted credential store
+	if r.credentials != nil {
+		value, ok, err := r.credentials.Get(name)
+		if err != nil {
+			return "", fmt.Errorf("secret %q: %w", name, err)
+		}
+		if ok {
+			return value, nil
+		}
+	}
+
+	return "", fmt.Errorf("secret %q not found in local, env, files, or credential store", name)
 }
@@ -0,0 +1,265 @@
+package sensitivedata
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+// Anonymization actions.
+const (
+	// AnonymizeHash replaces values with a keyed-hash pseudonym, the same for
+	// equal values, so anonymized reports can still be correlated.
+	AnonymizeHash = "hash"
+	// AnonymizeMask replaces values with a fixed marker.
+	AnonymizeMask = "mask"
+)
+
+// maskMarker replaces masked values.
+const maskMarker = "[MASKED]"
+
+// AnonymizationRule selects values to anonymize: substrings found by the
+// built-in matcher Match or by Pattern, or whole values of Fields.
+type AnonymizationRule struct {
+	Match   string
+	Pattern string
+	Action  string
+	Name    string
+	Fields  []string
+}
+
+// DefaultAnonymizationRules hash IP addresses, host names and emails.
+func DefaultAnonymizationRules() []AnonymizationRule {
+	return []AnonymizationRule{{Match: "email"}, {Match: "ip"}, {Match: "hostname"}}
+}
+
+// matchers are the built-in matchers. A candidate is only replaced when
+// valid is nil or accepts it.
+var matchers = map[string]struct {
+	pattern *regexp.Regexp
+	valid   func(string) bool
+}{
+	"email": {pattern: regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`)},
+	"ip": {
+		pattern: regexp.MustCompile(`(?i)\b\d{1,3}(?:\.\d{1,3}){3}\b|[0-9a-f]*:[0-9a-f:]*:[0-9a-f.]*`),
+		valid:   func(s string) bool { return net.ParseIP(s) != nil },
+	},
+	// Host names must end in a common top-level domain, so dotted names
+	// like data.exists or README.md in messages are left alone.
+	"hostname": {pattern: regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+` +
+		`(?:com|net|org|edu|gov|mil|int|io|dev|app|cloud|local|localdomain|internal|intranet|corp|lan|home|arpa|` +
+		`uk|de|fr|nl|eu|us|ca|au|jp|ch|se|no|fi|dk|be|at|it|es|pl|cz|ie|nz|in|br)\b`)},
+}
+
+// anonymizationRule is a compiled AnonymizationRule.
+type anonymizationRule struct {
+	pattern *regexp.Regexp
+	valid   func(string) bool
+	action  string
+	name    string
+	fields  []string
+}
+
+// Anonymizer replaces identifying values in results with pseudonyms or
+// masks. It is safe for concurrent use.
+type Anonymizer struct {
+	salt  []byte
+	rules []anonymizationRule
+}
+
+// Ensure interface compliance
+var _ ports.ResultAnonymizer = (*Anonymizer)(nil)
+
+// NewAnonymizer compiles rules. salt keys the pseudonym hashes.
+func NewAnonymizer(rules []AnonymizationRule, salt string) (*Anonymizer, error) {
+	a := &Anonymizer{salt: []byte(salt), rules: make([]anonymizationRule, 0, len(rules))}
+	for i, rule := range rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("anonymization rule %d: %w", i+1, err)
+		}
+		if compiled.action == AnonymizeHash && salt == "" {
+			return nil, fmt.Errorf("anonymization rule %d: hashing requires a salt", i+1)
+		}
+		a.rules = append(a.rules, compiled)
+	}
+	return a, nil
+}
+
+func compileRule(rule AnonymizationRule) (anonymizationRule, error) {
+	compiled := anonymizationRule{action: rule.Action, name: rule.Name, fields: rule.Fields}
+	if compiled.action == "" {
+		compiled.action = AnonymizeHash
+	}
+	if compiled.action != AnonymizeHash && compiled.action != AnonymizeMask {
+		return compiled, fmt.Errorf("invalid action %q (expected %q or %q)", rule.Action, AnonymizeHash, AnonymizeMask)
+	}
+
+	switch {
+	case rule.Match != "" && rule.Pattern != "":
+		return compiled, fmt.Errorf("match and pattern are mutually exclusive")
+	case rule.Match != "":
+		matcher, ok := matchers[rule.Match]
+		if !ok {
+			return compiled, fmt.Errorf("unknown matcher %q (expected email, hostname or ip)", rule.Match)
+		}
+		compiled.pattern = matcher.pattern
+		compiled.valid = matcher.valid
+		if compiled.name == "" {
+			compiled.name = rule.Match
+		}
+	case rule.Pattern != "":
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return compiled, fmt.Errorf("invalid pattern: %w", err)
+		}
+		compiled.pattern = re
+	case len(rule.Fields) == 0:
+		return compiled, fmt.Errorf("one of match, pattern or fields is required")
+	}
+	if compiled.name == "" {
+		compiled.name = "anon"
+	}
+	return compiled, nil
+}
+
+// Anonymize returns a copy of result with identifying values replaced in
+// messages, errors, expectations, node addresses, and observation configs
+// and evidence, and the original value of each pseudonym it used. Rules
+// restricted to fields only apply to config and evidence values.
+func (a *Anonymizer) Anonymize(result *execution.ExecutionResult) (*execution.ExecutionResult, map[string]string, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to copy result: %w", err)
+	}
+	out, err := execution.DecodeExecutionResult(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to copy result: %w", err)
+	}
+
+	pseudonyms := make(map[string]string)
+	text := func(s string) string { return a.scrub(s, "", pseudonyms) }
+
+	for i := range out.Controls {
+		ctrl := &out.Controls[i]
+		ctrl.Message = text(ctrl.Message)
+		ctrl.SkipReason = text(ctrl.SkipReason)
+		ctrl.Node = text(ctrl.Node)
+		for j := range ctrl.ObservationResults {
+			obs := &ctrl.ObservationResults[j]
+			a.walk(obs.Config, "config", pseudonyms)
+			if obs.Error != nil {
+				obs.Error.Message = text(obs.Error.Message)
+			}
+			for k := range obs.Expectations {
+				obs.Expectations[k].Expression = text(obs.Expectations[k].Expression)
+				obs.Expectations[k].Message = text(obs.Expectations[k].Message)
+			}
+			if obs.Evidence == nil {
+				continue
+			}
+			a.walk(obs.Evidence.Data, "data", pseudonyms)
+			if obs.Evidence.Raw != nil {
+				raw := text(*obs.Evidence.Raw)
+				obs.Evidence.Raw = &raw
+			}
+			if obs.Evidence.Error != nil {
+				obs.Evidence.Error.Message = text(obs.Evidence.Error.Message)
+			}
+		}
+	}
+	for i := range out.ErrorGroups {
+		out.ErrorGroups[i].Message = text(out.ErrorGroups[i].Message)
+		out.ErrorGroups[i].Example = text(out.ErrorGroups[i].Example)
+	}
+	for i := range out.Nodes {
+		out.Nodes[i].Node = text(out.Nodes[i].Node)
+		out.Nodes[i].Address = text(out.Nodes[i].Address)
+		out.Nodes[i].Error = text(out.Nodes[i].Error)
+	}
+	return out, pseudonyms, nil
+}
+
+// walk anonymizes the string values of a config or evidence map in place.
+// path is the dotted key path of data; list items share the path of their
+// list.
+func (a *Anonymizer) walk(data map[string]interface{}, path string, pseudonyms map[string]string) {
+	for key, value := range data {
+		data[key] = a.walkValue(value, path+"."+key, pseudonyms)
+	}
+}
+
+func (a *Anonymizer) walkValue(value interface{}, path string, pseudonyms map[string]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return a.scrub(v, path, pseudonyms)
+	case map[string]interface{}:
+		a.walk(v, path, pseudonyms)
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = a.walkValue(item, path, pseudonyms)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// scrub applies the rules to s, found at path ("" outside config and
+// evidence).
+func (a *Anonymizer) scrub(s, path string, pseudonyms map[string]string) string {
+	if s == "" {
+		return s
+	}
+	for _, rule := range a.rules {
+		if len(rule.fields) > 0 && !matchesField(path, rule.fields) {
+			continue
+		}
+		if rule.pattern == nil {
+			s = a.replace(rule, s, pseudonyms)
+			continue
+		}
+		s = rule.pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if rule.valid != nil && !rule.valid(match) {
+				return match
+			}
+			return a.replace(rule, match, pseudonyms)
+		})
+	}
+	return s
+}
+
+// replace returns the mask or pseudonym of value.
+func (a *Anonymizer) replace(rule anonymizationRule, value string, pseudonyms map[string]string) string {
+	if rule.action == AnonymizeMask {
+		return maskMarker
+	}
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(value))
+	pseudonym := rule.name + "-" + hex.EncodeToString(mac.Sum(nil))[:12]
+	pseudonyms[pseudonym] = value
+	return pseudonym
+}
+
+// matchesField reports whether path ends with one of fields, on key
+// boundaries: "owner" matches "data.owner" and "data.files.owner".
+func matchesField(path string, fields []string) bool {
+	if path == "" {
+		return false
+	}
+	for _, field := range fields {
+		if path == field || strings.HasSuffix(path, "."+field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package sensitivedata_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/domain/values"
+	"github.com/reglet-dev/reglet/internal/infrastructure/sensitivedata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func anonymizerResult() *execution.ExecutionResult {
+	result := execution.NewExecutionResult("web", "1.0.0")
+	result.AddControlResult(execution.ControlResult{
+		ID:      "tls",
+		Status:  values.StatusFail,
+		Message: "web01.corp.example.com (10.0.0.12) failed: see README.md",
+		ObservationResults: []execution.ObservationResult{{
+			Plugin: "http",
+			Config: map[string]interface{}{"url": "https://web01.corp.example.com/health"},
+			Status: values.StatusFail,
+			Evidence: &execution.Evidence{Status: true, Data: map[string]interface{}{
+				"owner":     "jdoe",
+				"addresses": []interface{}{"10.0.0.12", "::1"},
+				"contact":   "ops@example.com",
+			}},
+			Expectations: []execution.ExpectationResult{{Expression: "data.exists", Passed: false}},
+		}},
+	})
+	result.Finalize()
+	return result
+}
+
+func TestAnonymizer_Defaults(t *testing.T) {
+	t.Parallel()
+
+	anonymizer, err := sensitivedata.NewAnonymizer(sensitivedata.DefaultAnonymizationRules(), "salt")
+	require.NoError(t, err)
+	source := anonymizerResult()
+
+	result, pseudonyms, err := anonymizer.Anonymize(source)
+	require.NoError(t, err)
+
+	ctrl := result.Controls[0]
+	obs := ctrl.ObservationResults[0]
+	host := strings.Fields(ctrl.Message)[0]
+	assert.True(t, strings.HasPrefix(host, "hostname-"), ctrl.Message)
+	assert.Equal(t, "web01.corp.example.com", pseudonyms[host])
+	assert.Contains(t, ctrl.Message, "see README.md")
+	assert.Equal(t, "https://"+host+"/health", obs.Config["url"], "equal values get equal pseudonyms")
+
+	addresses := obs.Evidence.Data["addresses"].([]interface{})
+	assert.Equal(t, "10.0.0.12", pseudonyms[addresses[0].(string)])
+	assert.Equal(t, "::1", pseudonyms[addresses[1].(string)])
+	assert.True(t, strings.HasPrefix(obs.Evidence.Data["contact"].(string), "email-"))
+	assert.Equal(t, "jdoe", obs.Evidence.Data["owner"])
+	assert.Equal(t, "data.exists", obs.Expectations[0].Expression)
+
+	assert.Contains(t, source.Controls[0].Message, "web01.corp.example.com", "source is not modified")
+	assert.Equal(t, "jdoe", source.Controls[0].ObservationResults[0].Evidence.Data["owner"])
+}
+
+func TestAnonymizer_Rules(t *testing.T) {
+	t.Parallel()
+
+	anonymizer, err := sensitivedata.NewAnonymizer([]sensitivedata.AnonymizationRule{
+		{Fields: []string{"owner"}, Name: "user"},
+		{Match: "ip", Action: sensitivedata.AnonymizeMask},
+	}, "salt")
+	require.NoError(t, err)
+
+	result, pseudonyms, err := anonymizer.Anonymize(anonymizerResult())
+	require.NoError(t, err)
+
+	ctrl := result.Controls[0]
+	data := ctrl.ObservationResults[0].Evidence.Data
+	assert.Equal(t, "jdoe", pseudonyms[data["owner"].(string)])
+	assert.True(t, strings.HasPrefix(data["owner"].(string), "user-"))
+	assert.Equal(t, []interface{}{"[MASKED]", "[MASKED]"}, data["addresses"])
+	assert.Equal(t, "web01.corp.example.com ([MASKED]) failed: see README.md", ctrl.Message)
+	assert.Len(t, pseudonyms, 1, "masked values have no pseudonym")
+
+	_, err = sensitivedata.NewAnonymizer([]sensitivedata.AnonymizationRule{{Match: "ip"}}, "")
+	assert.ErrorContains(t, err, "hashing requires a salt")
+	_, err = sensitivedata.NewAnonymizer([]sensitivedata.AnonymizationRule{{Match: "phone"}}, "salt")
+	assert.ErrorContains(t, err, `unknown matcher "phone"`)
+	_, err = sensitivedata.NewAnonymizer([]sensitivedata.AnonymizationRule{{Action: "drop", Pattern: "x"}}, "salt")
+	assert.ErrorContains(t, err, `invalid action "drop"`)
+}
+
+func TestPseudonymFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "mapping", "pseudonyms.json")
+	file, err := sensitivedata.OpenPseudonymFile(path)
+	require.NoError(t, err)
+	assert.Len(t, file.Salt, 64)
+	require.NoError(t, file.Add(map[string]string{"ip-1": "10.0.0.1"}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	reopened, err := sensitivedata.OpenPseudonymFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, file.Salt, reopened.Salt, "the salt is kept so pseudonyms stay stable")
+	require.NoError(t, reopened.Add(map[string]string{"ip-2": "10.0.0.2"}))
+	assert.Equal(t, map[string]string{"ip-1": "10.0.0.1", "ip-2": "10.0.0.2"}, reopened.Pseudonyms)
+}
@@ -0,0 +1,155 @@
+package sensitivedata
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+// PII scan actions.
+const (
+	// PIIReport records findings and keeps the evidence as collected.
+	PIIReport = "report"
+	// PIIRedact records findings and replaces the values in the evidence.
+	PIIRedact = "redact"
+)
+
+// piiDetectors are the built-in PII detectors. A candidate only counts when
+// valid is nil or accepts it.
+var piiDetectors = map[string][]struct {
+	pattern *regexp.Regexp
+	valid   func(string) bool
+}{
+	"email": {{pattern: regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`)}},
+	"phone": {
+		// International format, and the North American "(555) 123-4567"
+		{pattern: regexp.MustCompile(`\+\d{1,3}(?:[ .-]?\(?\d{1,4}\)?){2,5}\b`), valid: phoneDigits},
+		{pattern: regexp.MustCompile(`\(\d{3}\) ?\d{3}-\d{4}\b`)},
+	},
+	"national_id": {
+		// US social security number, UK national insurance number
+		{pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), valid: validSSN},
+		{pattern: regexp.MustCompile(`\b[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z] ?\d{2} ?\d{2} ?\d{2} ?[A-D]\b`)},
+	},
+}
+
+// DefaultPIIDetectors returns the names of all built-in PII detectors.
+func DefaultPIIDetectors() []string {
+	return []string{"email", "phone", "national_id"}
+}
+
+// PIIScanner flags likely personal data in evidence, and redacts it when
+// configured to. It is safe for concurrent use.
+type PIIScanner struct {
+	detectors []string
+	action    string
+}
+
+// NewPIIScanner creates a scanner running detectors (all built-in ones when
+// empty) with action PIIReport or PIIRedact (default PIIReport).
+func NewPIIScanner(detectors []string, action string) (*PIIScanner, error) {
+	if len(detectors) == 0 {
+		detectors = DefaultPIIDetectors()
+	}
+	for _, name := range detectors {
+		if _, ok := piiDetectors[name]; !ok {
+			return nil, fmt.Errorf("unknown PII detector %q (expected %s)", name, strings.Join(DefaultPIIDetectors(), ", "))
+		}
+	}
+	if action == "" {
+		action = PIIReport
+	}
+	if action != PIIReport && action != PIIRedact {
+		return nil, fmt.Errorf("invalid PII action %q (expected %q or %q)", action, PIIReport, PIIRedact)
+	}
+	return &PIIScanner{detectors: detectors, action: action}, nil
+}
+
+// Scan returns the likely personal data in the string values of evidence
+// data, sorted by path and kind. With PIIRedact the values are replaced in
+// place.
+func (s *PIIScanner) Scan(data map[string]interface{}) []execution.PIIFinding {
+	counts := make(map[[2]string]int)
+	s.walk(data, "data", counts)
+
+	findings := make([]execution.PIIFinding, 0, len(counts))
+	for key, count := range counts {
+		findings = append(findings, execution.PIIFinding{
+			Kind:     key[1],
+			Path:     key[0],
+			Count:    count,
+			Redacted: s.action == PIIRedact,
+		})
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		return findings[i].Kind < findings[j].Kind
+	})
+	return findings
+}
+
+// walk scans the values of data in place. List items share the path of
+// their list.
+func (s *PIIScanner) walk(data map[string]interface{}, path string, counts map[[2]string]int) {
+	for key, value := range data {
+		data[key] = s.walkValue(value, path+"."+key, counts)
+	}
+}
+
+func (s *PIIScanner) walkValue(value interface{}, path string, counts map[[2]string]int) interface{} {
+	switch v := value.(type) {
+	case string:
+		return s.scanString(v, path, counts)
+	case map[string]interface{}:
+		s.walk(v, path, counts)
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = s.walkValue(item, path, counts)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func (s *PIIScanner) scanString(value, path string, counts map[[2]string]int) string {
+	for _, name := range s.detectors {
+		for _, detector := range piiDetectors[name] {
+			value = detector.pattern.ReplaceAllStringFunc(value, func(match string) string {
+				if detector.valid != nil && !detector.valid(match) {
+					return match
+				}
+				counts[[2]string{path, name}]++
+				if s.action == PIIRedact {
+					return "[REDACTED]"
+				}
+				return match
+			})
+		}
+	}
+	return value
+}
+
+// phoneDigits accepts candidates with a plausible number of digits for an
+// international phone number (E.164 allows up to 15).
+func phoneDigits(candidate string) bool {
+	digits := 0
+	for _, r := range candidate {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 8 && digits <= 15
+}
+
+// validSSN rejects numbers never issued as social security numbers.
+func validSSN(candidate string) bool {
+	area, group, serial := candidate[0:3], candidate[4:6], candidate[7:11]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
@@ -0,0 +1,60 @@
+package sensitivedata_test
+
+import (
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/reglet-dev/reglet/internal/infrastructure/sensitivedata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func piiEvidence() map[string]interface{} {
+	return map[string]interface{}{
+		"users": []interface{}{
+			map[string]interface{}{"email": "jane@example.com", "phone": "+44 20 7946 0958"},
+			map[string]interface{}{"email": "joe@example.org", "phone": "(555) 123-4567"},
+		},
+		"notes":   "SSN 123-45-6789, NINO AB 12 34 56 C",
+		"version": "1.2.3-4",
+		"serial":  "000-12-3456",
+		"build":   "+1.2",
+	}
+}
+
+func TestPIIScanner_Report(t *testing.T) {
+	t.Parallel()
+
+	scanner, err := sensitivedata.NewPIIScanner(nil, "")
+	require.NoError(t, err)
+	data := piiEvidence()
+
+	findings := scanner.Scan(data)
+
+	assert.Equal(t, []execution.PIIFinding{
+		{Kind: "national_id", Path: "data.notes", Count: 2},
+		{Kind: "email", Path: "data.users.email", Count: 2},
+		{Kind: "phone", Path: "data.users.phone", Count: 2},
+	}, findings)
+	assert.Equal(t, piiEvidence(), data, "report leaves the evidence as collected")
+}
+
+func TestPIIScanner_Redact(t *testing.T) {
+	t.Parallel()
+
+	scanner, err := sensitivedata.NewPIIScanner([]string{"email"}, sensitivedata.PIIRedact)
+	require.NoError(t, err)
+	data := piiEvidence()
+
+	findings := scanner.Scan(data)
+
+	assert.Equal(t, []execution.PIIFinding{{Kind: "email", Path: "data.users.email", Count: 2, Redacted: true}}, findings)
+	user := data["users"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "[REDACTED]", user["email"])
+	assert.Equal(t, "+44 20 7946 0958", user["phone"], "only the selected detectors run")
+
+	_, err = sensitivedata.NewPIIScanner([]string{"passport"}, "")
+	assert.ErrorContains(t, err, `unknown PII detector "passport"`)
+	_, err = sensitivedata.NewPIIScanner(nil, "drop")
+	assert.ErrorContains(t, err, `invalid PII action "drop"`)
+}
@@ -0,0 +1,80 @@
+package sensitivedata
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/reglet-dev/reglet/internal/application/ports"
+)
+
+// PseudonymFile is a local JSON file mapping pseudonyms to the values they
+// replace, for tracing anonymized reports back internally. It also keeps the
+// salt pseudonyms are derived with when none is configured, so a value gets
+// the same pseudonym in every report. The file is as sensitive as the
+// original results.
+type PseudonymFile struct {
+	Pseudonyms map[string]string `json:"pseudonyms"`
+	Salt       string            `json:"salt"`
+	path       string
+}
+
+// Ensure interface compliance
+var _ ports.PseudonymStore = (*PseudonymFile)(nil)
+
+// OpenPseudonymFile reads the pseudonym file at path. A missing file starts
+// empty, with a new random salt; it is created by the first Add.
+func OpenPseudonymFile(path string) (*PseudonymFile, error) {
+	f := &PseudonymFile{path: path, Pseudonyms: make(map[string]string)}
+
+	//nolint:gosec // G304: user-configured mapping file path is intentional
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read pseudonym file: %w", err)
+	default:
+		if err := json.Unmarshal(data, f); err != nil {
+			return nil, fmt.Errorf("failed to parse pseudonym file %s: %w", path, err)
+		}
+		if f.Pseudonyms == nil {
+			f.Pseudonyms = make(map[string]string)
+		}
+	}
+
+	if f.Salt == "" {
+		salt := make([]byte, 32)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		f.Salt = hex.EncodeToString(salt)
+	}
+	return f, nil
+}
+
+// Add records pseudonyms and writes the file, readable only by its owner.
+func (f *PseudonymFile) Add(pseudonyms map[string]string) error {
+	for pseudonym, value := range pseudonyms {
+		f.Pseudonyms[pseudonym] = value
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create pseudonym file directory: %w", err)
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write pseudonym file: %w", err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return fmt.Errorf("failed to write pseudonym file: %w", err)
+	}
+	return nil
+}
@@ -5,7 +5,9 @@ package system
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"time"
 
 	"github.com/goccy/go-yaml"
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
@@ -16,10 +18,23 @@ import (
 type Config struct {
 	SensitiveData        SensitiveDataConfig `yaml:"sensitive_data"`
 	Redaction            RedactionConfig     `yaml:"redaction"`
+	Anonymization        AnonymizationConfig `yaml:"anonymization"`
 	Security             SecurityConfig      `yaml:"security"`
+	Storage              StorageConfig       `yaml:"storage"`
+	Server               ServerConfig        `yaml:"server"`
+	Cluster              ClusterConfig       `yaml:"cluster"`
+	Registry             RegistryConfig      `yaml:"registry"`
+	Scheduling           SchedulingConfig    `yaml:"scheduling"`
+	Telemetry            TelemetryConfig     `yaml:"telemetry"`
 	Capabilities         []CapabilityConfig  `yaml:"capabilities"`
 	WasmMemoryLimitMB    int                 `yaml:"wasm_memory_limit_mb"`
+	ScratchLimitMB       int                 `yaml:"scratch_limit_mb"`
 	MaxEvidenceSizeBytes int                 `yaml:"max_evidence_size_bytes"`
+
+	// ProcessPlugins name the external process plugins trusted to run as
+	// native executables; other reglet-plugin-<name> executables are never
+	// started
+	ProcessPlugins []string `yaml:"process_plugins"`
 }
 
 // CapabilityConfig represents a capability grant in the system configuration.
@@ -28,6 +43,48 @@ type CapabilityConfig struct {
 	Pattern string `yaml:"pattern"`
 }
 
+// SchedulingConfig configures how parallel runs share their control workers
+// between plugins.
+type SchedulingConfig struct {
+	// PluginWeights give plugins a larger share of the workers while other
+	// plugins have controls waiting (default: 1 each), e.g. file: 2
+	PluginWeights map[string]int `yaml:"plugin_weights"`
+}
+
+// TelemetryConfig configures anonymous usage metrics. Nothing is collected
+// or sent unless they are enabled.
+type TelemetryConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Endpoint receives the usage reports (default: the reglet project's
+	// telemetry endpoint)
+	Endpoint string `yaml:"endpoint"`
+}
+
+// RegistryConfig configures how plugins are downloaded from OCI registries,
+// e.g. through a corporate artifact mirror.
+type RegistryConfig struct {
+	// Mirrors rewrite plugin repositories: a reference starting with a key
+	// (a registry host, or host and path) is pulled from the value instead,
+	// e.g. ghcr.io/reglet-dev: artifacts.corp.example/reglet
+	Mirrors map[string]string `yaml:"mirrors"`
+
+	// CAFile is a PEM bundle of extra CAs trusted for registry TLS
+	CAFile string `yaml:"ca_file"`
+
+	// Proxy is the URL of the HTTP(S) proxy registries are reached through
+	// (default: HTTPS_PROXY / HTTP_PROXY / NO_PROXY)
+	Proxy string `yaml:"proxy"`
+
+	// ProxyCredential names the credential store entry holding the proxy
+	// login as username:password
+	ProxyCredential string `yaml:"proxy_credential"`
+
+	// Index is the HTTPS URL or path of the plugin index reglet plugin
+	// install resolves plugins from
+	Index string `yaml:"index"`
+}
+
 // SensitiveDataConfig configures secret resolution and protection.
 // This structure is forward-compatible with future phases (OIDC, Cloud).
 type SensitiveDataConfig struct {
@@ -44,6 +101,15 @@ type SecretsConfig struct {
 
 	// Files defines file path mappings (secret_name -> file_path)
 	Files map[string]string `yaml:"files"`
+
+	// CredentialsFile is the encrypted credential store managed with
+	// "reglet credentials" (default: ~/.reglet/credentials.enc)
+	CredentialsFile string `yaml:"credentials_file"`
+
+	// InsecureKeyFile writes the credential store without
+	// REGLET_CREDENTIALS_PASSPHRASE, with its key in a file next to it. That
+	// protects nothing against anyone who can read the store.
+	InsecureKeyFile bool `yaml:"insecure_key_file"`
 }
 
 // RedactionConfig configures how sensitive data is sanitized.
@@ -51,6 +117,18 @@ type RedactionConfig struct {
 	HashMode HashModeConfig `yaml:"hash_mode"`
 	Patterns []string       `yaml:"patterns"`
 	Paths    []string       `yaml:"paths"`
+	PII      PIIConfig      `yaml:"pii"`
+}
+
+// PIIConfig configures the scan of evidence for likely personal data
+// (emails, phone numbers, national ID numbers).
+type PIIConfig struct {
+	// Action is "report" to record findings, or "redact" to also replace the
+	// values in the evidence (default: report)
+	Action string `yaml:"action"`
+	// Detectors to run: email, phone, national_id (default: all)
+	Detectors []string `yaml:"detectors"`
+	Enabled   bool     `yaml:"enabled"`
 }
 
 // HashModeConfig controls hash-based redaction.
@@ -59,6 +137,34 @@ type HashModeConfig struct {
 	Enabled bool   `yaml:"enabled"`
 }
 
+// AnonymizationConfig configures "reglet anonymize", which replaces
+// identifying values in results before they are shared externally.
+type AnonymizationConfig struct {
+	// Salt keys pseudonym hashes (default: a random salt kept in the mapping file)
+	Salt string `yaml:"salt"`
+	// MappingFile keeps the original value of each pseudonym
+	// (default: ~/.reglet/anonymization-mapping.json)
+	MappingFile string `yaml:"mapping_file"`
+	// Rules apply in order (default: hash IP addresses, host names and emails)
+	Rules []AnonymizationRuleConfig `yaml:"rules"`
+}
+
+// AnonymizationRuleConfig selects values to anonymize: substrings found by
+// a built-in matcher or a pattern, or whole values of fields.
+type AnonymizationRuleConfig struct {
+	// Match is a built-in matcher: "ip", "hostname" or "email"
+	Match string `yaml:"match"`
+	// Pattern is a regular expression, instead of Match
+	Pattern string `yaml:"pattern"`
+	// Fields limit the rule to these config and evidence keys ("owner",
+	// "data.tls.subject"); without Match or Pattern their whole values are replaced
+	Fields []string `yaml:"fields"`
+	// Action is "hash" (default) for a pseudonym or "mask"
+	Action string `yaml:"action"`
+	// Name prefixes pseudonyms (default: the matcher name, or "anon")
+	Name string `yaml:"name"`
+}
+
 // SecurityConfig configures capability security policies.
 type SecurityConfig struct {
 	// Level defines the security policy: "strict", "standard", or "permissive"
@@ -70,6 +176,128 @@ type SecurityConfig struct {
 	// CustomBroadPatterns allows users to define additional patterns considered "broad"
 	// Format: "kind:pattern" (e.g., "fs:write:/tmp/**")
 	CustomBroadPatterns []string `yaml:"custom_broad_patterns"`
+
+	// SymlinkPolicy controls symlinks in the paths plugins use:
+	// "restrict-to-granted-tree" (default), "follow" or "no-follow"
+	SymlinkPolicy string `yaml:"symlink_policy"`
+
+	// HardenPaths logs and denies plugin file access through paths that are
+	// not canonical, or that resolve outside their granted tree or across a
+	// mount point in it
+	HardenPaths bool `yaml:"harden_paths"`
+}
+
+// Storage backends for execution results.
+const (
+	// StorageBackendNone keeps no results beyond the run (default).
+	StorageBackendNone = ""
+	// StorageBackendMemory keeps results in process memory.
+	StorageBackendMemory = "memory"
+	// StorageBackendFile stores results as JSON files in a local directory.
+	StorageBackendFile = "file"
+	// StorageBackendPostgres stores results durably in PostgreSQL.
+	StorageBackendPostgres = "postgres"
+)
+
+// StorageConfig configures where execution results are persisted.
+type StorageConfig struct {
+	// Backend is "" (no persistence), "memory", "file" or "postgres"
+	Backend string `yaml:"backend"`
+	// Namespace scopes stored results to a team or tenant (default: "default")
+	Namespace string            `yaml:"namespace"`
+	File      FileStorageConfig `yaml:"file"`
+	Postgres  PostgresConfig    `yaml:"postgres"`
+}
+
+// FileStorageConfig configures the file result store.
+type FileStorageConfig struct {
+	// Dir holds one JSON file per result (default: ~/.reglet/results)
+	Dir string `yaml:"dir"`
+	// Compression of saved results: "none" (default), "gzip" or "zstd"
+	Compression string `yaml:"compression"`
+}
+
+// PostgresConfig configures the PostgreSQL result store. Connection settings
+// left out of the DSN fall back to the standard PG* environment variables
+// (PGHOST, PGPASSWORD, ...), which keeps passwords out of the config file.
+type PostgresConfig struct {
+	DSN             string        `yaml:"dsn"`
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time"`
+	MaxConns        int32         `yaml:"max_conns"`
+	MinConns        int32         `yaml:"min_conns"`
+}
+
+// ServerConfig configures "reglet serve".
+type ServerConfig struct {
+	// Profiles operators may run from the API or dashboard (name -> path)
+	Profiles map[string]string `yaml:"profiles"`
+	// Schedules run profiles on an interval
+	Schedules []RunScheduleConfig `yaml:"schedules"`
+	Auth      ServerAuthConfig    `yaml:"auth"`
+	Queue     RunQueueConfig      `yaml:"queue"`
+	// WorkDir receives a run directory per run (see check --work-dir)
+	WorkDir string `yaml:"work_dir"`
+}
+
+// RunQueueConfig limits how many server runs execute at once; further runs
+// wait in the queue.
+type RunQueueConfig struct {
+	// MaxConcurrent limits runs across all profiles (default: 4)
+	MaxConcurrent int `yaml:"max_concurrent"`
+	// MaxPerProfile limits runs of one profile (default: 1)
+	MaxPerProfile int `yaml:"max_per_profile"`
+}
+
+// RunScheduleConfig runs a server profile on an interval.
+type RunScheduleConfig struct {
+	// Profile names an entry of server.profiles
+	Profile     string `yaml:"profile"`
+	Environment string `yaml:"environment"`
+	// Namespace stores the results (default: the server's namespace)
+	Namespace string        `yaml:"namespace"`
+	Interval  time.Duration `yaml:"interval"`
+}
+
+// ServerAuthConfig configures bearer token authentication of the API. Tokens
+// are JWTs from an OIDC provider; authentication is off when Issuer is empty.
+type ServerAuthConfig struct {
+	// Issuer is the OIDC issuer URL tokens must come from
+	Issuer string `yaml:"issuer"`
+	// Audience tokens must be issued for (required with Issuer)
+	Audience string `yaml:"audience"`
+	// JWKSURL serves the issuer's signing keys (default: OIDC discovery)
+	JWKSURL string `yaml:"jwks_url"`
+	// RolesClaim names the claim holding reglet roles; dots select nested
+	// claims, e.g. "realm_access.roles" (default: "roles")
+	RolesClaim string `yaml:"roles_claim"`
+	// NamespacesClaim names the claim listing the namespaces a token may
+	// use (default: any namespace)
+	NamespacesClaim string `yaml:"namespaces_claim"`
+}
+
+// ClusterConfig configures distributed runs: the agents "reglet check
+// --distributed" places controls on, and the TLS files of coordinators and
+// agents ("reglet agent").
+type ClusterConfig struct {
+	Agents []AgentConfig    `yaml:"agents"`
+	TLS    ClusterTLSConfig `yaml:"tls"`
+}
+
+// AgentConfig registers an agent with the coordinator.
+type AgentConfig struct {
+	Name    string `yaml:"name"`
+	Address string `yaml:"address"` // host:port of "reglet agent"
+	// Tags attract controls with the same tags
+	Tags []string `yaml:"tags"`
+}
+
+// ClusterTLSConfig names the PEM files securing agent connections.
+type ClusterTLSConfig struct {
+	// CA verifies agents, and the coordinators agents accept
+	CA   string `yaml:"ca"`
+	Cert string `yaml:"cert"`
+	Key  string `yaml:"key"`
 }
 
 // SecurityLevel represents the security enforcement level.
@@ -101,6 +329,59 @@ func (c *SecurityConfig) GetSecurityLevel() SecurityLevel {
 	}
 }
 
+// Validate checks the keys that take one of a fixed set of values.
+func (c *Config) Validate() error {
+	switch SecurityLevel(c.Security.Level) {
+	case "", SecurityLevelStrict, SecurityLevelStandard, SecurityLevelPermissive:
+	default:
+		return fmt.Errorf("security.level must be %s, %s or %s, got %q",
+			SecurityLevelStrict, SecurityLevelStandard, SecurityLevelPermissive, c.Security.Level)
+	}
+	if _, err := capabilities.ParseSymlinkPolicy(c.Security.SymlinkPolicy); err != nil {
+		return fmt.Errorf("security.%w", err)
+	}
+	switch c.Storage.Backend {
+	case StorageBackendNone, StorageBackendMemory, StorageBackendFile, StorageBackendPostgres:
+	default:
+		return fmt.Errorf("storage.backend must be %q, %q or %q, got %q",
+			StorageBackendMemory, StorageBackendFile, StorageBackendPostgres, c.Storage.Backend)
+	}
+	switch c.Storage.File.Compression {
+	case "", "none", "gzip", "zstd":
+	default:
+		return fmt.Errorf("storage.file.compression must be none, gzip or zstd, got %q", c.Storage.File.Compression)
+	}
+	if c.WasmMemoryLimitMB < -1 {
+		return fmt.Errorf("wasm_memory_limit_mb must be >= -1, got %d", c.WasmMemoryLimitMB)
+	}
+	if c.ScratchLimitMB < -1 {
+		return fmt.Errorf("scratch_limit_mb must be >= -1, got %d", c.ScratchLimitMB)
+	}
+	if c.Registry.Proxy != "" {
+		proxy, err := url.Parse(c.Registry.Proxy)
+		if err != nil || proxy.Host == "" {
+			return fmt.Errorf("registry.proxy must be a URL such as http://proxy:3128, got %q", c.Registry.Proxy)
+		}
+	}
+	for plugin, weight := range c.Scheduling.PluginWeights {
+		if weight < 1 {
+			return fmt.Errorf("scheduling.plugin_weights.%s must be >= 1, got %d", plugin, weight)
+		}
+	}
+	if c.Telemetry.Endpoint != "" {
+		endpoint, err := url.Parse(c.Telemetry.Endpoint)
+		if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+			return fmt.Errorf("telemetry.endpoint must be an http(s) URL, got %q", c.Telemetry.Endpoint)
+		}
+	}
+	for prefix, mirror := range c.Registry.Mirrors {
+		if prefix == "" || mirror == "" {
+			return fmt.Errorf("registry.mirrors: %q -> %q must name a registry on both sides", prefix, mirror)
+		}
+	}
+	return nil
+}
+
 // ConfigLoader loads system configuration from disk.
 type ConfigLoader struct{}
 
@@ -133,6 +414,7 @@ func DefaultConfig() *Config {
 		},
 		Capabilities:         []CapabilityConfig{},
 		WasmMemoryLimitMB:    0, // 0 means use runtime default
+		ScratchLimitMB:       0, // 0 means use runtime default
 		MaxEvidenceSizeBytes: 0, // 0 means no limit
 	}
 }
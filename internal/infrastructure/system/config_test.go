@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -154,6 +155,7 @@ security:
   custom_broad_patterns:
     - "fs:write:/tmp/**"
     - "network:outbound:*"
+  symlink_policy: no-follow
 `
 	err := os.WriteFile(configPath, []byte(yaml), 0644)
 	require.NoError(t, err)
@@ -167,4 +169,143 @@ security:
 	assert.Len(t, cfg.Security.CustomBroadPatterns, 2)
 	assert.Contains(t, cfg.Security.CustomBroadPatterns, "fs:write:/tmp/**")
 	assert.Contains(t, cfg.Security.CustomBroadPatterns, "network:outbound:*")
+	assert.Equal(t, "no-follow", cfg.Security.SymlinkPolicy)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Security.SymlinkPolicy = "sometimes"
+	assert.ErrorContains(t, cfg.Validate(), "security.symlink_policy must be")
+}
+
+func TestConfigLoader_Load_WithStorageConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	yaml := `
+storage:
+  backend: postgres
+  namespace: team-a
+  postgres:
+    dsn: postgres://reglet@localhost/reglet
+    max_conns: 8
+    max_conn_idle_time: 30m
+`
+	err := os.WriteFile(configPath, []byte(yaml), 0644)
+	require.NoError(t, err)
+
+	cfg, err := NewConfigLoader().Load(configPath)
+
+	require.NoError(t, err)
+	assert.Equal(t, StorageBackendPostgres, cfg.Storage.Backend)
+	assert.Equal(t, "team-a", cfg.Storage.Namespace)
+	assert.Equal(t, "postgres://reglet@localhost/reglet", cfg.Storage.Postgres.DSN)
+	assert.Equal(t, int32(8), cfg.Storage.Postgres.MaxConns)
+	assert.Equal(t, 30*time.Minute, cfg.Storage.Postgres.MaxConnIdleTime)
+	assert.Zero(t, cfg.Storage.Postgres.MaxConnLifetime)
+}
+
+func TestConfigLoader_Load_WithServerConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	yaml := `
+server:
+  profiles:
+    web: /etc/reglet/web.yaml
+  queue:
+    max_concurrent: 8
+  schedules:
+    - profile: web
+      environment: prod
+      interval: 1h
+  auth:
+    issuer: https://idp.example.com/realms/ops
+    audience: reglet
+    roles_claim: realm_access.roles
+    namespaces_claim: reglet_namespaces
+`
+	err := os.WriteFile(configPath, []byte(yaml), 0644)
+	require.NoError(t, err)
+
+	cfg, err := NewConfigLoader().Load(configPath)
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com/realms/ops", cfg.Server.Auth.Issuer)
+	assert.Equal(t, "reglet", cfg.Server.Auth.Audience)
+	assert.Equal(t, "realm_access.roles", cfg.Server.Auth.RolesClaim)
+	assert.Equal(t, "reglet_namespaces", cfg.Server.Auth.NamespacesClaim)
+	assert.Empty(t, cfg.Server.Auth.JWKSURL)
+	assert.Equal(t, map[string]string{"web": "/etc/reglet/web.yaml"}, cfg.Server.Profiles)
+	assert.Equal(t, RunQueueConfig{MaxConcurrent: 8}, cfg.Server.Queue)
+	assert.Equal(t, []RunScheduleConfig{{Profile: "web", Environment: "prod", Interval: time.Hour}}, cfg.Server.Schedules)
+}
+
+func TestConfigLoader_Load_WithClusterConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	yaml := `
+cluster:
+  tls:
+    ca: /etc/reglet/ca.pem
+    cert: /etc/reglet/node.pem
+    key: /etc/reglet/node-key.pem
+  agents:
+    - name: web-1
+      address: web-1.internal:9443
+      tags: [web, linux]
+`
+	err := os.WriteFile(configPath, []byte(yaml), 0644)
+	require.NoError(t, err)
+
+	cfg, err := NewConfigLoader().Load(configPath)
+
+	require.NoError(t, err)
+	assert.Equal(t, []AgentConfig{{Name: "web-1", Address: "web-1.internal:9443", Tags: []string{"web", "linux"}}}, cfg.Cluster.Agents)
+	assert.Equal(t, ClusterTLSConfig{CA: "/etc/reglet/ca.pem", Cert: "/etc/reglet/node.pem", Key: "/etc/reglet/node-key.pem"}, cfg.Cluster.TLS)
+}
+
+func TestConfigLoader_Load_WithSchedulingConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	yaml := `
+scheduling:
+  plugin_weights:
+    file: 2
+    http: 1
+`
+	err := os.WriteFile(configPath, []byte(yaml), 0644)
+	require.NoError(t, err)
+
+	cfg, err := NewConfigLoader().Load(configPath)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"file": 2, "http": 1}, cfg.Scheduling.PluginWeights)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Scheduling.PluginWeights["http"] = 0
+	assert.ErrorContains(t, cfg.Validate(), "scheduling.plugin_weights.http must be >= 1")
+}
+
+func TestConfigLoader_Load_WithTelemetryConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	yaml := `
+telemetry:
+  enabled: true
+  endpoint: https://telemetry.example.com/usage
+`
+	err := os.WriteFile(configPath, []byte(yaml), 0644)
+	require.NoError(t, err)
+
+	cfg, err := NewConfigLoader().Load(configPath)
+
+	require.NoError(t, err)
+	assert.Equal(t, TelemetryConfig{Enabled: true, Endpoint: "https://telemetry.example.com/usage"}, cfg.Telemetry)
+	require.NoError(t, cfg.Validate())
+	assert.False(t, DefaultConfig().Telemetry.Enabled, "telemetry is opt-in")
+
+	cfg.Telemetry.Endpoint = "telemetry.example.com"
+	assert.ErrorContains(t, cfg.Validate(), "telemetry.endpoint must be an http(s) URL")
 }
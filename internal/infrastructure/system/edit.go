@@ -0,0 +1,146 @@
+package system
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// SetConfigValue sets key to value in the configuration file at path (the
+// user config or a project file), creating the file if needed. Comments and
+// the other keys are kept as they are. The file is only written if the
+// result is a valid configuration.
+func SetConfigValue(path, key string, value interface{}) error {
+	if !IsConfigKey(key) {
+		return fmt.Errorf("unknown configuration key %q", key)
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	updated, err := setYAMLValue(data, strings.Split(key, "."), value)
+	if err != nil {
+		return fmt.Errorf("failed to set %s in %s: %w", key, path, err)
+	}
+	if err := validateConfigDocument(updated); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, updated, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ConfigFileValue returns the value key has in the configuration file at
+// path, or false if the file does not set it.
+func ConfigFileValue(path, key string) (interface{}, bool, error) {
+	values, err := readLayerFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	known := make(map[string]bool)
+	for _, k := range ConfigKeys() {
+		known[k] = true
+	}
+	value, ok := flatten("", values, known)[key]
+	return value, ok, nil
+}
+
+// IsConfigKey reports whether key is a key of the system config or project
+// file.
+func IsConfigKey(key string) bool {
+	for _, known := range ConfigKeys() {
+		if known == key {
+			return true
+		}
+	}
+	return false
+}
+
+// setYAMLValue sets the value at path in a YAML document, editing its syntax
+// tree so comments survive. Missing parent mappings are created.
+func setYAMLValue(data []byte, path []string, value interface{}) ([]byte, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return yaml.Marshal(nestedValue(path, value))
+	}
+
+	file, err := parser.ParseBytes(data, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace the value if the key exists, else merge the missing part of
+	// the path into its deepest existing parent
+	for depth := len(path); depth >= 0; depth-- {
+		yamlPath, err := yaml.PathString(pathString(path[:depth]))
+		if err != nil {
+			return nil, err
+		}
+		if depth > 0 {
+			if _, err := yamlPath.FilterFile(file); err != nil {
+				continue
+			}
+		}
+
+		if depth == len(path) {
+			rendered, err := yaml.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			err = yamlPath.ReplaceWithReader(file, bytes.NewReader(rendered))
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			rendered, err := yaml.Marshal(nestedValue(path[depth:], value))
+			if err != nil {
+				return nil, err
+			}
+			if err := yamlPath.MergeFromReader(file, bytes.NewReader(rendered)); err != nil {
+				return nil, fmt.Errorf("%s is not a mapping", pathString(path[:depth]))
+			}
+		}
+		return []byte(file.String()), nil
+	}
+	return nil, errors.New("document root is not a mapping")
+}
+
+func pathString(path []string) string {
+	if len(path) == 0 {
+		return "$"
+	}
+	return "$." + strings.Join(path, ".")
+}
+
+func nestedValue(path []string, value interface{}) map[string]interface{} {
+	nested := make(map[string]interface{})
+	setNested(nested, path, value)
+	return nested
+}
+
+// validateConfigDocument checks that a configuration document decodes into
+// the configuration types and holds valid values.
+func validateConfigDocument(data []byte) error {
+	var project Project
+	if err := yaml.Unmarshal(data, &project); err != nil {
+		return err
+	}
+	return project.Validate()
+}
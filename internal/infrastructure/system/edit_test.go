@@ -0,0 +1,69 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetConfigValue(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`# reglet settings
+security:
+  # capability policy
+  level: standard
+`), 0o600))
+
+	require.NoError(t, SetConfigValue(path, "security.level", "strict"))
+	require.NoError(t, SetConfigValue(path, "storage.postgres.max_conns", 8))
+	require.NoError(t, SetConfigValue(path, "redaction.patterns", []interface{}{"token=\\w+"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# reglet settings")
+	assert.Contains(t, string(data), "# capability policy")
+
+	cfg, err := NewConfigLoader().Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "strict", cfg.Security.Level)
+	assert.Equal(t, int32(8), cfg.Storage.Postgres.MaxConns)
+	assert.Equal(t, []string{"token=\\w+"}, cfg.Redaction.Patterns)
+
+	value, ok, err := ConfigFileValue(path, "redaction.patterns")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"token=\\w+"}, value)
+}
+
+func TestSetConfigValue_NewFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".reglet", "config.yaml")
+	require.NoError(t, SetConfigValue(path, "storage.backend", "file"))
+
+	cfg, err := NewConfigLoader().Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "file", cfg.Storage.Backend)
+}
+
+func TestSetConfigValue_Invalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	original := []byte("security:\n  level: standard\n")
+	require.NoError(t, os.WriteFile(path, original, 0o600))
+
+	assert.ErrorContains(t, SetConfigValue(path, "security.levle", "strict"), "unknown configuration key")
+	assert.ErrorContains(t, SetConfigValue(path, "security.level", "paranoid"), "security.level must be")
+	assert.Error(t, SetConfigValue(path, "wasm_memory_limit_mb", "lots"))
+	assert.ErrorContains(t, SetConfigValue(path, "registry.proxy", "proxy.corp"), "registry.proxy must be a URL")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, original, data, "invalid values are not written")
+}
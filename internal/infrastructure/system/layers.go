@@ -0,0 +1,362 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Configuration layers, lowest precedence first.
+const (
+	LayerDefault = "default"
+	LayerUser    = "user"
+	LayerProject = "project"
+	LayerEnv     = "env"
+	LayerFlag    = "flag"
+)
+
+// EnvPrefix prefixes the environment variables that set configuration keys:
+// REGLET_SECURITY_LEVEL sets security.level.
+const EnvPrefix = "REGLET_"
+
+// sensitiveKeys hold secrets, or connection strings that may embed them.
+var sensitiveKeys = map[string]bool{
+	"sensitive_data.secrets.local": true,
+	"redaction.hash_mode.salt":     true,
+	"anonymization.salt":           true,
+	"storage.postgres.dsn":         true,
+	"registry.proxy":               true,
+}
+
+// projectKeyPrefixes prefix the keys a project file may set. A project file
+// comes with the repository being checked, so it cannot grant capabilities,
+// relax the security policy, choose the plugins that run, redirect
+// registries, exporters or telemetry, or name secrets: those belong to the
+// user. security.level may only be raised.
+var projectKeyPrefixes = []string{
+	"profiles",
+	"output.",
+	"storage.backend",
+	"storage.namespace",
+	"storage.file.",
+	"security.level",
+	"security.custom_broad_patterns",
+	"redaction.patterns",
+	"redaction.paths",
+	"scheduling.",
+	"wasm_memory_limit_mb",
+	"scratch_limit_mb",
+	"max_evidence_size_bytes",
+}
+
+// securityRanks order the security levels from least to most strict.
+var securityRanks = map[SecurityLevel]int{
+	SecurityLevelPermissive: 0,
+	SecurityLevelStandard:   1,
+	SecurityLevelStrict:     2,
+}
+
+// projectPathKeys are paths relative to the project file when set there.
+var projectPathKeys = []string{"output.file", "storage.file.dir"}
+
+// Setting is an effective configuration value and the layer it came from.
+type Setting struct {
+	Value interface{}
+	Key   string
+	Layer string
+	// Source names the file, environment variable or flag within the layer
+	Source string
+}
+
+// Origin describes where the value came from, e.g.
+// "env (REGLET_SECURITY_LEVEL)".
+func (s Setting) Origin() string {
+	if s.Source == "" {
+		return s.Layer
+	}
+	return fmt.Sprintf("%s (%s)", s.Layer, s.Source)
+}
+
+// Sensitive reports whether the value may hold a secret.
+func (s Setting) Sensitive() bool {
+	return sensitiveKeys[s.Key]
+}
+
+// FlagSetting is a configuration key set by a command-line flag.
+type FlagSetting struct {
+	Value interface{}
+	Name  string // flag name, without dashes
+}
+
+// LayerSources locate the configuration layers. Each layer overrides the
+// keys it sets in the layers before it: built-in defaults, the user config,
+// the project file, environment variables and flags. Lists and maps are
+// replaced, not merged.
+type LayerSources struct {
+	// Flags are keyed by configuration key
+	Flags map[string]FlagSetting
+	// UserConfigPath is the user config (~/.reglet/config.yaml or --config);
+	// a missing file sets nothing
+	UserConfigPath string
+	// ProjectPath is the workspace's reglet.yaml, "" outside a workspace
+	ProjectPath string
+	// Env holds KEY=value pairs, as returned by os.Environ
+	Env []string
+}
+
+// Layers is the configuration merged from all layers.
+type Layers struct {
+	project  *Project
+	settings map[string]Setting
+}
+
+// LoadLayers reads and merges the configuration layers.
+func LoadLayers(src LayerSources) (*Layers, error) {
+	known := make(map[string]bool)
+	for _, key := range ConfigKeys() {
+		known[key] = true
+	}
+	settings := make(map[string]Setting)
+	apply := func(layer, source string, values map[string]interface{}) {
+		for key, value := range values {
+			settings[key] = Setting{Key: key, Value: value, Layer: layer, Source: source}
+		}
+	}
+
+	defaults, err := toMap(&Project{Config: *DefaultConfig()})
+	if err != nil {
+		return nil, err
+	}
+	apply(LayerDefault, "", flatten("", defaults, known))
+
+	if src.UserConfigPath != "" {
+		values, err := readLayerFile(src.UserConfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read system config: %w", err)
+		}
+		apply(LayerUser, src.UserConfigPath, flatten("", values, known))
+	}
+
+	if src.ProjectPath != "" {
+		if _, err := LoadProject(src.ProjectPath); err != nil {
+			return nil, err
+		}
+		values, err := readLayerFile(src.ProjectPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read project file: %w", err)
+		}
+		projectValues := flatten("", values, known)
+		for key, value := range projectValues {
+			if UserOnlyKey(key) {
+				slog.Warn("ignoring user-only setting in the project file", "key", key, "file", src.ProjectPath)
+				delete(projectValues, key)
+			} else if key == "security.level" && securityRank(value) < securityRank(settings[key].Value) {
+				slog.Warn("ignoring project security level below the user's", "level", value, "file", src.ProjectPath)
+				delete(projectValues, key)
+			}
+		}
+		apply(LayerProject, src.ProjectPath, projectValues)
+		for _, key := range projectPathKeys {
+			setting := settings[key]
+			if path, ok := setting.Value.(string); ok && setting.Layer == LayerProject && path != "" && !filepath.IsAbs(path) {
+				setting.Value = filepath.Join(filepath.Dir(src.ProjectPath), path)
+				settings[key] = setting
+			}
+		}
+	}
+
+	env := make(map[string]string, len(src.Env))
+	for _, pair := range src.Env {
+		if name, value, ok := strings.Cut(pair, "="); ok {
+			env[name] = value
+		}
+	}
+	for _, key := range ConfigKeys() {
+		name := EnvVar(key)
+		if value, ok := env[name]; ok {
+			settings[key] = Setting{Key: key, Value: ParseValue(value), Layer: LayerEnv, Source: name}
+		}
+	}
+
+	for key, flag := range src.Flags {
+		settings[key] = Setting{Key: key, Value: flag.Value, Layer: LayerFlag, Source: "--" + flag.Name}
+	}
+
+	nested := make(map[string]interface{})
+	for key, setting := range settings {
+		setNested(nested, strings.Split(key, "."), setting.Value)
+	}
+	data, err := yaml.Marshal(nested)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge configuration: %w", err)
+	}
+	var project Project
+	if err := yaml.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	project.Path = src.ProjectPath
+
+	return &Layers{project: &project, settings: settings}, nil
+}
+
+// Config returns the effective system configuration.
+func (l *Layers) Config() *Config {
+	return &l.project.Config
+}
+
+// Project returns the effective project settings. Outside a workspace its
+// Path is empty, and relative paths are relative to the working directory.
+func (l *Layers) Project() *Project {
+	return l.project
+}
+
+// Settings returns the effective settings, sorted by key.
+func (l *Layers) Settings() []Setting {
+	settings := make([]Setting, 0, len(l.settings))
+	for _, setting := range l.settings {
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+// Lookup returns the effective setting of key.
+func (l *Layers) Lookup(key string) (Setting, bool) {
+	setting, ok := l.settings[key]
+	return setting, ok
+}
+
+// UserOnlyKey reports whether key can only be set by the user config,
+// environment variables and flags, not by a project file.
+func UserOnlyKey(key string) bool {
+	for _, prefix := range projectKeyPrefixes {
+		if key == prefix || strings.HasSuffix(prefix, ".") && strings.HasPrefix(key, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// securityRank returns the strictness of a security.level value; unknown
+// levels rank as the default.
+func securityRank(level interface{}) int {
+	s, _ := level.(string)
+	return securityRanks[(&SecurityConfig{Level: s}).GetSecurityLevel()]
+}
+
+// EnvVar returns the environment variable that sets key.
+func EnvVar(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// ConfigKeys returns the keys of the system config and project file, in
+// dotted form ("storage.postgres.dsn"). Lists and maps are single keys.
+func ConfigKeys() []string {
+	var keys []string
+	collectKeys(reflect.TypeOf(Project{}), "", &keys)
+	sort.Strings(keys)
+	return keys
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func collectKeys(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if opts == "inline" {
+			collectKeys(field.Type, prefix, keys)
+			continue
+		}
+		key := prefix + name
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != durationType {
+			collectKeys(ft, key+".", keys)
+			continue
+		}
+		*keys = append(*keys, key)
+	}
+}
+
+// readLayerFile reads a YAML configuration file into a map; a missing file
+// is empty.
+func readLayerFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// flatten maps the leaves of values to their dotted keys. Known keys are
+// leaves even when their values are maps.
+func flatten(prefix string, values map[string]interface{}, known map[string]bool) map[string]interface{} {
+	leaves := make(map[string]interface{})
+	for name, value := range values {
+		key := prefix + name
+		if nested, ok := value.(map[string]interface{}); ok && !known[key] {
+			for k, v := range flatten(key+".", nested, known) {
+				leaves[k] = v
+			}
+			continue
+		}
+		leaves[key] = value
+	}
+	return leaves
+}
+
+func setNested(values map[string]interface{}, path []string, value interface{}) {
+	for _, name := range path[:len(path)-1] {
+		next, ok := values[name].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			values[name] = next
+		}
+		values = next
+	}
+	values[path[len(path)-1]] = value
+}
+
+// ParseValue reads a value given as text, e.g. in an environment variable,
+// as a YAML value, so numbers, booleans and flow lists ("[a, b]") keep their
+// types.
+func ParseValue(s string) interface{} {
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(s), &value); err != nil || value == nil {
+		return s
+	}
+	return value
+}
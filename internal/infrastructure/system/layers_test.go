@@ -0,0 +1,182 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLayers_Precedence(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	userPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(userPath, []byte(`security:
+  level: permissive
+storage:
+  backend: file
+  namespace: user-ns
+wasm_memory_limit_mb: 128
+`), 0o600))
+	projectPath := filepath.Join(dir, ProjectFileName)
+	require.NoError(t, os.WriteFile(projectPath, []byte(`security:
+  level: strict
+storage:
+  file:
+    dir: results
+  namespace: project-ns
+`), 0o600))
+
+	layers, err := LoadLayers(LayerSources{
+		UserConfigPath: userPath,
+		ProjectPath:    projectPath,
+		Env: []string{
+			"REGLET_STORAGE_NAMESPACE=env-ns",
+			"REGLET_WASM_MEMORY_LIMIT_MB=512",
+			"REGLET_UNRELATED=1",
+		},
+		Flags: map[string]FlagSetting{
+			"storage.namespace": {Name: "namespace", Value: "flag-ns"},
+		},
+	})
+	require.NoError(t, err)
+
+	cfg := layers.Config()
+	assert.Equal(t, "strict", cfg.Security.Level)
+	assert.Equal(t, "file", cfg.Storage.Backend)
+	assert.Equal(t, filepath.Join(dir, "results"), cfg.Storage.File.Dir)
+	assert.Equal(t, "flag-ns", cfg.Storage.Namespace)
+	assert.Equal(t, 512, cfg.WasmMemoryLimitMB)
+	assert.Equal(t, projectPath, layers.Project().Path)
+
+	origins := make(map[string]string)
+	for _, setting := range layers.Settings() {
+		origins[setting.Key] = setting.Origin()
+	}
+	assert.Equal(t, "default", origins["redaction.hash_mode.enabled"])
+	assert.Equal(t, "user ("+userPath+")", origins["storage.backend"])
+	assert.Equal(t, "project ("+projectPath+")", origins["security.level"])
+	assert.Equal(t, "env (REGLET_WASM_MEMORY_LIMIT_MB)", origins["wasm_memory_limit_mb"])
+	assert.Equal(t, "flag (--namespace)", origins["storage.namespace"])
+}
+
+func TestLoadLayers_TelemetryIsUserOnly(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	userPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(userPath, []byte("telemetry:\n  enabled: false\n"), 0o600))
+	projectPath := filepath.Join(dir, ProjectFileName)
+	require.NoError(t, os.WriteFile(projectPath, []byte(`telemetry:
+  enabled: true
+  endpoint: https://collector.example.com/usage
+`), 0o600))
+
+	layers, err := LoadLayers(LayerSources{UserConfigPath: userPath, ProjectPath: projectPath})
+	require.NoError(t, err)
+	assert.False(t, layers.Config().Telemetry.Enabled, "a project cannot opt the user in")
+	assert.Empty(t, layers.Config().Telemetry.Endpoint)
+	setting, _ := layers.Lookup("telemetry.enabled")
+	assert.Equal(t, LayerUser, setting.Layer)
+}
+
+func TestLoadLayers_ProjectCannotWeakenSecurity(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	userPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(userPath, []byte("security:\n  level: strict\n"), 0o600))
+	projectPath := filepath.Join(dir, ProjectFileName)
+	require.NoError(t, os.WriteFile(projectPath, []byte(`security:
+  level: permissive
+  symlink_policy: follow
+plugin_dir: tools/plugins
+process_plugins: [ldap]
+capabilities:
+  - kind: exec
+    pattern: "**"
+registry:
+  mirrors:
+    ghcr.io: attacker.example/plugins
+sensitive_data:
+  secrets:
+    env:
+      token: JIRA_API_TOKEN
+storage:
+  namespace: project-ns
+  postgres:
+    dsn: postgres://attacker.example/reglet
+notifications:
+  export: [jira]
+  integrations:
+    jira:
+      url: https://attacker.example
+`), 0o600))
+
+	layers, err := LoadLayers(LayerSources{UserConfigPath: userPath, ProjectPath: projectPath})
+	require.NoError(t, err)
+
+	cfg := layers.Config()
+	assert.Equal(t, "strict", cfg.Security.Level, "a project cannot lower the security level")
+	assert.Empty(t, cfg.Security.SymlinkPolicy)
+	assert.Empty(t, cfg.Capabilities)
+	assert.Empty(t, layers.Project().PluginDir, "a project cannot choose the plugins that run")
+	assert.Empty(t, cfg.ProcessPlugins)
+	assert.Empty(t, cfg.Registry.Mirrors)
+	assert.Empty(t, cfg.SensitiveData.Secrets.Env)
+	assert.Empty(t, cfg.Storage.Postgres.DSN)
+	assert.Empty(t, layers.Project().Notifications.Export, "a project cannot redirect exporters")
+	assert.Empty(t, layers.Project().Notifications.Integrations)
+	assert.Equal(t, "project-ns", cfg.Storage.Namespace)
+
+	setting, _ := layers.Lookup("security.level")
+	assert.Equal(t, LayerUser, setting.Layer)
+}
+
+func TestUserOnlyKey(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{"output.format", "storage.file.dir", "security.level", "scheduling.plugin_weights"} {
+		assert.False(t, UserOnlyKey(key), key)
+	}
+	for _, key := range []string{"telemetry.enabled", "capabilities", "security.symlink_policy", "registry.ca_file",
+		"sensitive_data.secrets.local", "notifications.export", "storage.postgres.dsn", "plugin_dir", "process_plugins"} {
+		assert.True(t, UserOnlyKey(key), key)
+	}
+}
+
+func TestLoadLayers_Defaults(t *testing.T) {
+	t.Parallel()
+
+	layers, err := LoadLayers(LayerSources{UserConfigPath: filepath.Join(t.TempDir(), "missing.yaml")})
+	require.NoError(t, err)
+
+	assert.Equal(t, string(SecurityLevelStandard), layers.Config().Security.Level)
+	assert.Empty(t, layers.Project().Path)
+	assert.Len(t, layers.Settings(), len(ConfigKeys()))
+
+	setting, ok := layers.Lookup("storage.postgres.dsn")
+	require.True(t, ok)
+	assert.Equal(t, LayerDefault, setting.Layer)
+	assert.True(t, setting.Sensitive())
+}
+
+func TestLoadLayers_InvalidProject(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ProjectFileName)
+	require.NoError(t, os.WriteFile(path, []byte("secuirty:\n  level: strict\n"), 0o600))
+
+	_, err := LoadLayers(LayerSources{ProjectPath: path})
+	assert.ErrorContains(t, err, "secuirty")
+}
+
+func TestEnvVar(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "REGLET_STORAGE_POSTGRES_DSN", EnvVar("storage.postgres.dsn"))
+	assert.Equal(t, "REGLET_PLUGIN_DIR", EnvVar("plugin_dir"))
+}
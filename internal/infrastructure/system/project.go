@@ -0,0 +1,133 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+)
+
+// ProjectFileName is the name of the project file marking a workspace.
+const ProjectFileName = "reglet.yaml"
+
+// VendorPluginDir is the directory, relative to the workspace root, of the
+// registry plugins vendored into a repository.
+const VendorPluginDir = "vendor/plugins"
+
+// Project represents a workspace's project file (reglet.yaml). It holds the
+// defaults a team would otherwise pass as flags on every invocation, and may
+// set the keys of the system config that cannot weaken the user's security,
+// e.g. storage.file to point the project at its own result repository (see
+// UserOnlyKey). Relative paths are relative to the directory of the project
+// file.
+type Project struct {
+	Config `yaml:",inline"`
+
+	// Notifications are the exporters results are sent to after a run
+	Notifications NotificationsConfig `yaml:"notifications"`
+
+	// Output sets the defaults of --format and --output
+	Output ProjectOutputConfig `yaml:"output"`
+
+	// Path is the path of the project file, set when it is loaded
+	Path string `yaml:"-"`
+
+	// PluginDir is the directory of local plugins (default: ./plugins). It is
+	// user-only: a project file cannot swap in the plugins that run
+	PluginDir string `yaml:"plugin_dir"`
+
+	// Profiles are the profiles "reglet check" runs without arguments; globs
+	// are expanded
+	Profiles []string `yaml:"profiles"`
+}
+
+// ProjectOutputConfig sets the output defaults of a project.
+type ProjectOutputConfig struct {
+	Format string `yaml:"format"`
+	File   string `yaml:"file"`
+}
+
+// NotificationsConfig selects the exporters of a project and their settings.
+type NotificationsConfig struct {
+	// Integrations are exporter settings, under those of the profile
+	Integrations map[string]map[string]interface{} `yaml:"integrations"`
+
+	// Export names the exporters run after every check
+	Export []string `yaml:"export"`
+}
+
+// FindProject looks for a project file in dir and its parents, returning the
+// nearest one, or nil if there is none.
+func FindProject(dir string) (*Project, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return LoadProject(path)
+		} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read project file: %w", err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProject loads the project file at path.
+func LoadProject(path string) (*Project, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project file: %w", err)
+	}
+
+	var project Project
+	if err := yaml.UnmarshalWithOptions(data, &project, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("failed to parse project file %s: %w", path, err)
+	}
+	project.Path = path
+	return &project, nil
+}
+
+// Dir returns the root directory of the workspace.
+func (p *Project) Dir() string {
+	return filepath.Dir(p.Path)
+}
+
+// VendorDir returns the directory of the workspace's vendored plugins.
+func (p *Project) VendorDir() string {
+	return filepath.Join(p.Dir(), filepath.FromSlash(VendorPluginDir))
+}
+
+// Resolve returns path relative to the workspace root, or path itself when
+// it is absolute or empty.
+func (p *Project) Resolve(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(p.Dir(), path)
+}
+
+// ProfilePaths returns the paths of the project's profiles, with globs
+// expanded in order.
+func (p *Project) ProfilePaths() ([]string, error) {
+	var paths []string
+	for _, pattern := range p.Profiles {
+		matches, err := filepath.Glob(p.Resolve(pattern))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid profile pattern %q: %w", p.Path, pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%s: no profile matches %q", p.Path, pattern)
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
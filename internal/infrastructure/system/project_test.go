@@ -0,0 +1,84 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindProject(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	nested := filepath.Join(root, "services", "api")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "profiles"), 0o750))
+	require.NoError(t, os.MkdirAll(nested, 0o750))
+	for _, name := range []string{"web.yaml", "db.yaml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, "profiles", name), []byte("profile: {}\n"), 0o600))
+	}
+
+	content := `profiles:
+  - profiles/*.yaml
+plugin_dir: ./plugins
+output:
+  format: json
+  file: out/results.json
+storage:
+  backend: postgres
+  postgres:
+    dsn: postgres://reglet@db/reglet
+notifications:
+  export: [slack]
+  integrations:
+    slack:
+      channel: "#compliance"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(root, ProjectFileName), []byte(content), 0o600))
+
+	project, err := FindProject(nested)
+	require.NoError(t, err)
+	require.NotNil(t, project)
+
+	assert.Equal(t, filepath.Join(root, ProjectFileName), project.Path)
+	assert.Equal(t, root, project.Dir())
+	assert.Equal(t, filepath.Join(root, "plugins"), project.Resolve(project.PluginDir))
+	assert.Equal(t, "json", project.Output.Format)
+	assert.Equal(t, "postgres://reglet@db/reglet", project.Storage.Postgres.DSN)
+	assert.Equal(t, []string{"slack"}, project.Notifications.Export)
+	assert.Equal(t, "#compliance", project.Notifications.Integrations["slack"]["channel"])
+
+	profiles, err := project.ProfilePaths()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "profiles", "db.yaml"),
+		filepath.Join(root, "profiles", "web.yaml"),
+	}, profiles)
+}
+
+func TestFindProject_None(t *testing.T) {
+	t.Parallel()
+
+	project, err := FindProject(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, project)
+}
+
+func TestLoadProject_Errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ProjectFileName)
+
+	require.NoError(t, os.WriteFile(path, []byte("profile_paths: [a.yaml]\n"), 0o600))
+	_, err := LoadProject(path)
+	assert.Error(t, err, "unknown keys are rejected")
+
+	require.NoError(t, os.WriteFile(path, []byte("profiles: [missing/*.yaml]\n"), 0o600))
+	project, err := LoadProject(path)
+	require.NoError(t, err)
+	_, err = project.ProfilePaths()
+	assert.ErrorContains(t, err, "no profile matches")
+}
@@ -0,0 +1,238 @@
+// Package telemetry collects anonymous usage metrics for users who opt in:
+// counts of the commands run, the kinds of plugins used and how long runs
+// take, aggregated locally and sent at most once a day. Reports carry no
+// identifiers, host names, profiles, paths or results; docs/telemetry.md
+// documents the payload.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// DefaultEndpoint receives usage reports unless telemetry.endpoint is set.
+const DefaultEndpoint = "https://telemetry.reglet.dev/v1/usage"
+
+// ReportInterval is how long usage is aggregated before it is sent.
+const ReportInterval = 24 * time.Hour
+
+// SchemaVersion is the version of the Report format.
+const SchemaVersion = 1
+
+// DoNotTrackEnv disables telemetry when set to a true value, whatever the
+// configuration says (https://consoledonottrack.com).
+const DoNotTrackEnv = "DO_NOT_TRACK"
+
+// Report is the payload sent to the telemetry endpoint: counts aggregated
+// since the day in Since.
+type Report struct {
+	SchemaVersion int            `json:"schema_version"`
+	RegletVersion string         `json:"reglet_version"`
+	OS            string         `json:"os"`
+	Arch          string         `json:"arch"`
+	Since         string         `json:"since"`                   // YYYY-MM-DD, UTC
+	Commands      map[string]int `json:"commands,omitempty"`      // runs per command, e.g. "plugins list"
+	PluginKinds   map[string]int `json:"plugin_kinds,omitempty"`  // runs using each built-in plugin, or "custom"
+	RunDurations  map[string]int `json:"run_durations,omitempty"` // runs per duration bucket
+}
+
+// pending is the usage file: the report being aggregated and when it started.
+type pending struct {
+	Started time.Time `json:"started"`
+	Report  Report    `json:"report"`
+}
+
+// Enabled reports whether telemetry is on: enabled in the configuration and
+// not refused through DO_NOT_TRACK.
+func Enabled(configured bool, getenv func(string) string) bool {
+	switch getenv(DoNotTrackEnv) {
+	case "", "0", "false":
+		return configured
+	default:
+		return false
+	}
+}
+
+// Collector aggregates usage in a local file and sends it to an endpoint
+// when it is due. Recording is best effort: concurrent processes may lose
+// each other's counts.
+type Collector struct {
+	client   *http.Client
+	now      func() time.Time
+	path     string
+	endpoint string
+	version  string
+}
+
+// NewCollector creates a collector aggregating in the file at path and
+// reporting to endpoint ("" = DefaultEndpoint) as reglet version.
+func NewCollector(path, endpoint, version string) *Collector {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &Collector{
+		client:   &http.Client{Timeout: 5 * time.Second},
+		now:      time.Now,
+		path:     path,
+		endpoint: endpoint,
+		version:  version,
+	}
+}
+
+// Endpoint returns the URL reports are sent to.
+func (c *Collector) Endpoint() string {
+	return c.endpoint
+}
+
+// RecordCommand counts a run of command, given as its path below reglet
+// (e.g. "plugins list").
+func (c *Collector) RecordCommand(command string) error {
+	return c.update(func(r *Report) {
+		r.Commands = increment(r.Commands, command)
+	})
+}
+
+// RecordRun counts a profile run that took duration and used plugins of
+// pluginKinds, each counted once.
+func (c *Collector) RecordRun(duration time.Duration, pluginKinds []string) error {
+	return c.update(func(r *Report) {
+		r.RunDurations = increment(r.RunDurations, DurationBucket(duration))
+		seen := make(map[string]bool, len(pluginKinds))
+		for _, kind := range pluginKinds {
+			if !seen[kind] {
+				seen[kind] = true
+				r.PluginKinds = increment(r.PluginKinds, kind)
+			}
+		}
+	})
+}
+
+// Pending returns the usage aggregated since the last report, nil when
+// nothing was recorded.
+func (c *Collector) Pending() (*Report, error) {
+	state, err := c.load()
+	if err != nil || state == nil {
+		return nil, err
+	}
+	return &state.Report, nil
+}
+
+// Flush sends the aggregated usage once ReportInterval has passed since it
+// started, and starts over when the endpoint accepted it. It reports
+// whether a report was sent.
+func (c *Collector) Flush(ctx context.Context) (bool, error) {
+	state, err := c.load()
+	if err != nil || state == nil || c.now().Sub(state.Started) < ReportInterval {
+		return false, err
+	}
+
+	body, err := json.Marshal(state.Report)
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to send usage report: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("failed to send usage report: %s", resp.Status)
+	}
+	return true, c.Discard()
+}
+
+// Discard removes the aggregated usage without sending it.
+func (c *Collector) Discard() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove usage file: %w", err)
+	}
+	return nil
+}
+
+// update applies fn to the aggregated usage and writes it back, starting a
+// new report when there is none.
+func (c *Collector) update(fn func(*Report)) error {
+	state, err := c.load()
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		now := c.now().UTC()
+		state = &pending{Started: now, Report: Report{
+			SchemaVersion: SchemaVersion,
+			OS:            runtime.GOOS,
+			Arch:          runtime.GOARCH,
+			Since:         now.Format(time.DateOnly),
+		}}
+	}
+	state.Report.RegletVersion = c.version
+	fn(&state.Report)
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create usage file directory: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write usage file: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write usage file: %w", err)
+	}
+	return nil
+}
+
+// load reads the usage file, nil when there is none. A corrupt file is
+// dropped rather than failing the command that records usage.
+func (c *Collector) load() (*pending, error) {
+	data, err := os.ReadFile(c.path) //nolint:gosec // G304: path is fixed by the CLI, under ~/.reglet
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read usage file: %w", err)
+	}
+	var state pending
+	if err := json.Unmarshal(data, &state); err != nil || state.Started.IsZero() {
+		return nil, nil //nolint:nilerr // start over from a corrupt file
+	}
+	return &state, nil
+}
+
+// DurationBucket returns the coarse bucket a run duration is reported in.
+func DurationBucket(d time.Duration) string {
+	switch {
+	case d < 10*time.Second:
+		return "<10s"
+	case d < time.Minute:
+		return "10s-1m"
+	case d < 10*time.Minute:
+		return "1m-10m"
+	default:
+		return ">=10m"
+	}
+}
+
+func increment(counts map[string]int, key string) map[string]int {
+	if counts == nil {
+		counts = make(map[string]int)
+	}
+	counts[key]++
+	return counts
+}
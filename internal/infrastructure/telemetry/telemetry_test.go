@@ -0,0 +1,117 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnabled(t *testing.T) {
+	env := func(value string) func(string) string {
+		return func(string) string { return value }
+	}
+	assert.False(t, Enabled(false, env("")))
+	assert.True(t, Enabled(true, env("")))
+	assert.True(t, Enabled(true, env("0")))
+	assert.False(t, Enabled(true, env("1")))
+	assert.False(t, Enabled(true, env("true")))
+}
+
+func TestCollector_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.json")
+	c := NewCollector(path, "", "1.2.0")
+	c.now = func() time.Time { return time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC) }
+
+	pending, err := c.Pending()
+	require.NoError(t, err)
+	assert.Nil(t, pending, "nothing recorded")
+
+	require.NoError(t, c.RecordCommand("check"))
+	require.NoError(t, c.RecordCommand("check"))
+	require.NoError(t, c.RecordCommand("plugins list"))
+	require.NoError(t, c.RecordRun(3*time.Second, []string{"file", "file", "custom"}))
+	require.NoError(t, c.RecordRun(2*time.Minute, []string{"http"}))
+
+	pending, err = c.Pending()
+	require.NoError(t, err)
+	assert.Equal(t, &Report{
+		SchemaVersion: SchemaVersion,
+		RegletVersion: "1.2.0",
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		Since:         "2026-10-17",
+		Commands:      map[string]int{"check": 2, "plugins list": 1},
+		PluginKinds:   map[string]int{"file": 1, "custom": 1, "http": 1},
+		RunDurations:  map[string]int{"<10s": 1, "1m-10m": 1},
+	}, pending)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestCollector_Flush(t *testing.T) {
+	var received []Report
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report Report
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&report))
+		received = append(received, report)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	c := NewCollector(filepath.Join(t.TempDir(), "telemetry.json"), server.URL, "1.2.0")
+	c.now = func() time.Time { return now }
+	require.NoError(t, c.RecordCommand("check"))
+
+	sent, err := c.Flush(context.Background())
+	require.NoError(t, err)
+	assert.False(t, sent, "not due yet")
+
+	now = now.Add(ReportInterval)
+	status = http.StatusServiceUnavailable
+	_, err = c.Flush(context.Background())
+	assert.ErrorContains(t, err, "503")
+	pending, _ := c.Pending()
+	assert.NotNil(t, pending, "kept when the endpoint refuses it")
+
+	status = http.StatusAccepted
+	sent, err = c.Flush(context.Background())
+	require.NoError(t, err)
+	assert.True(t, sent)
+	require.Len(t, received, 2)
+	assert.Equal(t, map[string]int{"check": 1}, received[1].Commands)
+
+	pending, err = c.Pending()
+	require.NoError(t, err)
+	assert.Nil(t, pending, "starts over once sent")
+}
+
+func TestCollector_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+
+	c := NewCollector(path, "", "dev")
+	require.NoError(t, c.RecordCommand("check"))
+	pending, err := c.Pending()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"check": 1}, pending.Commands)
+}
+
+func TestDurationBucket(t *testing.T) {
+	assert.Equal(t, "<10s", DurationBucket(0))
+	assert.Equal(t, "10s-1m", DurationBucket(10*time.Second))
+	assert.Equal(t, "1m-10m", DurationBucket(time.Minute))
+	assert.Equal(t, ">=10m", DurationBucket(time.Hour))
+}
@@ -7,10 +7,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
 	"github.com/reglet-dev/reglet/internal/domain/entities"
 	"github.com/reglet-dev/reglet/internal/domain/values"
 	"github.com/santhosh-tekuri/jsonschema/v5"
@@ -19,6 +22,21 @@ import (
 // Control ID must be alphanumeric with dashes and underscores
 var controlIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
 
+// envKeyPattern matches portable environment variable names.
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// labelKeyPattern matches label names that every exporter can carry as-is,
+// including Prometheus.
+var labelKeyPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// stepIDPattern matches setup and teardown step IDs, which references to
+// setup evidence separate from the field path with a dot.
+var stepIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// environmentNamePattern matches environment names usable with --env and in
+// exporter keys.
+var environmentNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
 // PluginSchemaProvider is an interface for loading plugins and retrieving their schemas.
 // This allows validation code to be decoupled from the WASM runtime implementation.
 type PluginSchemaProvider interface {
@@ -114,6 +132,13 @@ func (v *ProfileValidator) Validate(profile *entities.Profile) error {
 		errors = append(errors, err.Error())
 	}
 
+	// Validate environments
+	errors = append(errors, validateEnvironments(profile.Environments)...)
+
+	// Validate setup and teardown steps
+	errors = append(errors, validateLifecycleSteps("setup", profile.Setup)...)
+	errors = append(errors, validateLifecycleSteps("teardown", profile.Teardown)...)
+
 	if len(errors) > 0 {
 		return fmt.Errorf("profile validation failed:\n  - %s", strings.Join(errors, "\n  - "))
 	}
@@ -143,6 +168,17 @@ func (v *ProfileValidator) ValidateWithSchemas(ctx context.Context, profile *ent
 		}
 	}
 
+	for _, phase := range []struct {
+		name  string
+		steps []entities.LifecycleStep
+	}{{"setup", profile.Setup}, {"teardown", profile.Teardown}} {
+		for _, step := range phase.steps {
+			if err := validateObservationSchemaCompiled(ctx, step.ObservationDefinition, compiler); err != nil {
+				errors = append(errors, fmt.Sprintf("%s step %s (%s): %s", phase.name, step.ID, step.Plugin, err.Error()))
+			}
+		}
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("schema validation failed:\n  - %s", strings.Join(errors, "\n  - "))
 	}
@@ -150,6 +186,56 @@ func (v *ProfileValidator) ValidateWithSchemas(ctx context.Context, profile *ent
 	return nil
 }
 
+// validateEnvironments validates environment names and target lists.
+func validateEnvironments(environments map[string]entities.Environment) []string {
+	names := make([]string, 0, len(environments))
+	for name := range environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errors []string
+	for _, name := range names {
+		if !environmentNamePattern.MatchString(name) {
+			errors = append(errors, fmt.Sprintf("environment %q: name must match %s", name, environmentNamePattern))
+		}
+		seen := make(map[string]bool)
+		for i, target := range environments[name].Targets {
+			if strings.TrimSpace(target) == "" {
+				errors = append(errors, fmt.Sprintf("environment %q: target %d is empty", name, i))
+				continue
+			}
+			if seen[target] {
+				errors = append(errors, fmt.Sprintf("environment %q: target %q is listed twice", name, target))
+			}
+			seen[target] = true
+		}
+	}
+	return errors
+}
+
+// validateLifecycleSteps validates the setup or teardown steps of a profile.
+func validateLifecycleSteps(phase string, steps []entities.LifecycleStep) []string {
+	var errors []string
+	seen := make(map[string]bool)
+	for i, step := range steps {
+		switch {
+		case step.ID == "":
+			errors = append(errors, fmt.Sprintf("%s step %d: step ID is required", phase, i))
+		case !stepIDPattern.MatchString(step.ID):
+			errors = append(errors, fmt.Sprintf("%s step %d: step ID %q is invalid (must match %s)", phase, i, step.ID, stepIDPattern))
+		case seen[step.ID]:
+			errors = append(errors, fmt.Sprintf("duplicate %s step ID: %s", phase, step.ID))
+		}
+		seen[step.ID] = true
+
+		if err := validateObservation(step.ObservationDefinition); err != nil {
+			errors = append(errors, fmt.Sprintf("%s step %d: %s", phase, i, err.Error()))
+		}
+	}
+	return errors
+}
+
 // validateMetadata validates profile metadata fields.
 func validateMetadata(meta entities.ProfileMetadata) error {
 	var errors []string
@@ -220,6 +306,25 @@ func validateControl(ctrl entities.Control) error {
 		errors = append(errors, "control name is required")
 	}
 
+	// Label keys must be portable across exporters
+	keys := make([]string, 0, len(ctrl.Labels))
+	for key := range ctrl.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !labelKeyPattern.MatchString(key) || strings.HasPrefix(key, "__") {
+			errors = append(errors, fmt.Sprintf("label %q is invalid (must match %s and not start with __)", key, labelKeyPattern))
+		}
+	}
+
+	// References must be links a reader can follow
+	for _, ref := range ctrl.References {
+		if err := validateReference(ref); err != nil {
+			errors = append(errors, fmt.Sprintf("reference %q is invalid: %s", ref, err))
+		}
+	}
+
 	// At least one observation is required
 	if len(ctrl.ObservationDefinitions) == 0 {
 		errors = append(errors, "at least one observation is required")
@@ -239,6 +344,22 @@ func validateControl(ctrl entities.Control) error {
 	return nil
 }
 
+// validateReference checks that a control reference is an absolute http or
+// https URL.
+func validateReference(ref string) error {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return errors.Unwrap(err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("must be an http or https URL")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("has no host")
+	}
+	return nil
+}
+
 // ValidatePluginName validates that a plugin name is safe to use in filesystem paths.
 // This prevents path traversal attacks when loading plugins.
 // EXPORTED for use by other packages that need to validate plugin names.
@@ -270,6 +391,30 @@ func validateObservation(obs entities.ObservationDefinition) error {
 		errors = append(errors, "config is required")
 	}
 
+	envKeys := make([]string, 0, len(obs.Env))
+	for key := range obs.Env {
+		envKeys = append(envKeys, key)
+	}
+	sort.Strings(envKeys)
+	for _, key := range envKeys {
+		if !envKeyPattern.MatchString(key) {
+			errors = append(errors, fmt.Sprintf("env key %q is not a valid environment variable name", key))
+		} else if capabilities.LoaderEnvironmentVariable(key) {
+			errors = append(errors, fmt.Sprintf("env key %q controls program loading and cannot be set by a profile", key))
+		}
+	}
+
+	fields := make([]string, 0, len(obs.Thresholds))
+	for field := range obs.Thresholds {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if _, err := obs.Thresholds[field].Parse(); err != nil {
+			errors = append(errors, fmt.Sprintf("threshold %q: %v", field, err))
+		}
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("%s", strings.Join(errors, "; "))
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/reglet-dev/reglet/internal/domain/entities"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidate_Valid(t *testing.T) {
@@ -156,6 +157,85 @@ func TestValidate_InvalidControlID(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid")
 }
 
+func TestValidate_ControlLabels(t *testing.T) {
+	tests := []struct {
+		labels  map[string]string
+		name    string
+		wantErr string
+	}{
+		{name: "valid", labels: map[string]string{"cmdb_id": "CI0042", "service": "billing", "CostCenter": ""}},
+		{name: "dash", labels: map[string]string{"cost-center": "cc-1"}, wantErr: `label "cost-center" is invalid`},
+		{name: "leading digit", labels: map[string]string{"1team": "x"}, wantErr: `label "1team" is invalid`},
+		{name: "reserved", labels: map[string]string{"__name__": "x"}, wantErr: `label "__name__" is invalid`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &entities.Profile{
+				Metadata: entities.ProfileMetadata{Name: "test-profile", Version: "1.0.0"},
+				Controls: entities.ControlsSection{
+					Items: []entities.Control{{
+						ID:     "test-control",
+						Name:   "Test Control",
+						Labels: tt.labels,
+						ObservationDefinitions: []entities.ObservationDefinition{
+							{Plugin: "file", Config: map[string]interface{}{"path": "/etc/test"}},
+						},
+					}},
+				},
+			}
+
+			err := NewProfileValidator().Validate(profile)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidate_ControlReferences(t *testing.T) {
+	tests := []struct {
+		name       string
+		references []string
+		wantErr    string
+	}{
+		{name: "valid", references: []string{"https://www.cisecurity.org/benchmark/ubuntu_linux", "http://wiki.internal/runbooks/ssh"}},
+		{name: "relative", references: []string{"docs/ssh.md"}, wantErr: `reference "docs/ssh.md" is invalid: must be an http or https URL`},
+		{name: "scheme", references: []string{"ftp://example.com/std.pdf"}, wantErr: "must be an http or https URL"},
+		{name: "no host", references: []string{"https:///path"}, wantErr: "has no host"},
+		{name: "malformed", references: []string{"https://exa mple.com"}, wantErr: `reference "https://exa mple.com" is invalid`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &entities.Profile{
+				Metadata: entities.ProfileMetadata{Name: "test-profile", Version: "1.0.0"},
+				Controls: entities.ControlsSection{
+					Items: []entities.Control{{
+						ID:         "test-control",
+						Name:       "Test Control",
+						References: tt.references,
+						ObservationDefinitions: []entities.ObservationDefinition{
+							{Plugin: "file", Config: map[string]interface{}{"path": "/etc/test"}},
+						},
+					}},
+				},
+			}
+
+			err := NewProfileValidator().Validate(profile)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
 func TestValidate_DuplicateControlIDs(t *testing.T) {
 	profile := &entities.Profile{
 		Metadata: entities.ProfileMetadata{
@@ -276,3 +356,146 @@ func TestValidate_ManualStruct(t *testing.T) {
 	validator := NewProfileValidator()
 	assert.NoError(t, validator.Validate(profile))
 }
+
+func TestValidate_InvalidObservationEnvKey(t *testing.T) {
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{
+			Name:    "test-profile",
+			Version: "1.0.0",
+		},
+		Controls: entities.ControlsSection{
+			Items: []entities.Control{
+				{
+					ID:   "test-control",
+					Name: "Test Control",
+					ObservationDefinitions: []entities.ObservationDefinition{
+						{
+							Plugin: "command",
+							Config: map[string]interface{}{"command": "locale"},
+							Env: map[string]string{
+								"LANG":       "C",
+								"BAD-KEY":    "x",
+								"AWS_*":      "y",
+								"LD_PRELOAD": "/tmp/x.so",
+								"PATH":       "/tmp",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	validator := NewProfileValidator()
+	err := validator.Validate(profile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `env key "AWS_*" is not a valid environment variable name`)
+	assert.Contains(t, err.Error(), `env key "BAD-KEY" is not a valid environment variable name`)
+	assert.Contains(t, err.Error(), `env key "LD_PRELOAD" controls program loading`)
+	assert.Contains(t, err.Error(), `env key "PATH" controls program loading`)
+	assert.NotContains(t, err.Error(), `"LANG"`)
+}
+
+func TestValidate_InvalidObservationThresholds(t *testing.T) {
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{
+			Name:    "test-profile",
+			Version: "1.0.0",
+		},
+		Controls: entities.ControlsSection{
+			Items: []entities.Control{
+				{
+					ID:   "test-control",
+					Name: "Test Control",
+					ObservationDefinitions: []entities.ObservationDefinition{
+						{
+							Plugin: "http",
+							Config: map[string]interface{}{"url": "https://example.com"},
+							Thresholds: map[string]entities.Threshold{
+								"latency_ms": {Max: "500ms", Unit: "ms"},
+								"disk":       {Min: "10%", Max: "5GB"},
+								"cert":       {Within: "80%"},
+								"empty":      {},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	validator := NewProfileValidator()
+	err := validator.Validate(profile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `threshold "disk": max is a size but other bounds are percent`)
+	assert.Contains(t, err.Error(), `threshold "cert": within must be a duration`)
+	assert.Contains(t, err.Error(), `threshold "empty": threshold needs min, max or within`)
+	assert.NotContains(t, err.Error(), `"latency_ms"`)
+}
+
+func TestValidate_Environments(t *testing.T) {
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{
+			Name:    "test-profile",
+			Version: "1.0.0",
+		},
+		Environments: map[string]entities.Environment{
+			"prod":     {Targets: []string{"web-1", "web-2"}},
+			"bad name": {},
+			"staging":  {Targets: []string{"stg-1", " ", "stg-1"}},
+		},
+		Controls: entities.ControlsSection{
+			Items: []entities.Control{
+				{
+					ID:   "test-control",
+					Name: "Test Control",
+					ObservationDefinitions: []entities.ObservationDefinition{
+						{Plugin: "file", Config: map[string]interface{}{"path": "/etc/test"}},
+					},
+				},
+			},
+		},
+	}
+
+	err := NewProfileValidator().Validate(profile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `environment "bad name": name must match`)
+	assert.Contains(t, err.Error(), `environment "staging": target 1 is empty`)
+	assert.Contains(t, err.Error(), `environment "staging": target "stg-1" is listed twice`)
+	assert.NotContains(t, err.Error(), `environment "prod"`)
+}
+
+func TestValidate_LifecycleSteps(t *testing.T) {
+	profile := &entities.Profile{
+		Metadata: entities.ProfileMetadata{
+			Name:    "test-profile",
+			Version: "1.0.0",
+		},
+		Setup: []entities.LifecycleStep{
+			{ID: "auth", ObservationDefinition: entities.ObservationDefinition{Plugin: "http", Config: map[string]interface{}{}}},
+			{ID: "auth.v2", ObservationDefinition: entities.ObservationDefinition{Plugin: "http", Config: map[string]interface{}{}}},
+			{ID: "auth", ObservationDefinition: entities.ObservationDefinition{Plugin: "http", Config: map[string]interface{}{}}},
+		},
+		Teardown: []entities.LifecycleStep{
+			{ObservationDefinition: entities.ObservationDefinition{Plugin: "command"}},
+		},
+		Controls: entities.ControlsSection{
+			Items: []entities.Control{
+				{
+					ID:   "test-control",
+					Name: "Test Control",
+					ObservationDefinitions: []entities.ObservationDefinition{
+						{Plugin: "file", Config: map[string]interface{}{"path": "/etc/test"}},
+					},
+				},
+			},
+		},
+	}
+
+	err := NewProfileValidator().Validate(profile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `setup step 1: step ID "auth.v2" is invalid`)
+	assert.Contains(t, err.Error(), "duplicate setup step ID: auth")
+	assert.Contains(t, err.Error(), "teardown step 0: step ID is required")
+	assert.Contains(t, err.Error(), "teardown step 0: config is required")
+}
@@ -0,0 +1,57 @@
+package wasm
+
+import (
+	"bytes"
+	"errors"
+)
+
+// BinaryKind identifies the flavor of a WebAssembly binary.
+type BinaryKind int
+
+const (
+	// BinaryKindUnknown is not a WebAssembly binary.
+	BinaryKindUnknown BinaryKind = iota
+	// BinaryKindCoreModule is a core WebAssembly module (the Go SDK's wasip1 output).
+	BinaryKindCoreModule
+	// BinaryKindComponent is a component-model binary (WASI preview 2 / WIT),
+	// as produced by componentize-py or jco.
+	BinaryKindComponent
+)
+
+// String returns a human-readable name for the binary kind.
+func (k BinaryKind) String() string {
+	switch k {
+	case BinaryKindCoreModule:
+		return "core module"
+	case BinaryKindComponent:
+		return "component"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrComponentNotSupported is returned when loading a component-model plugin.
+// wazero only executes core modules, so components cannot run until the
+// runtime gains component-model support.
+var ErrComponentNotSupported = errors.New("WebAssembly components are not supported yet; build the plugin as a core wasip1 module")
+
+var wasmMagic = []byte{0x00, 'a', 's', 'm'}
+
+// DetectBinaryKind inspects the preamble of a WebAssembly binary. Core modules
+// and components share the magic number and differ in the version and layer
+// fields that follow it.
+func DetectBinaryKind(data []byte) BinaryKind {
+	if len(data) < 8 || !bytes.Equal(data[:4], wasmMagic) {
+		return BinaryKindUnknown
+	}
+
+	// Bytes 4-5 are the version, bytes 6-7 the layer (0 = core, 1 = component)
+	switch {
+	case data[6] == 0x00 && data[7] == 0x00 && data[4] == 0x01 && data[5] == 0x00:
+		return BinaryKindCoreModule
+	case data[6] == 0x01 && data[7] == 0x00:
+		return BinaryKindComponent
+	default:
+		return BinaryKindUnknown
+	}
+}
@@ -0,0 +1,49 @@
+package wasm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectBinaryKind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data []byte
+		want BinaryKind
+	}{
+		{"core module", []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}, BinaryKindCoreModule},
+		{"component", []byte{0x00, 'a', 's', 'm', 0x0d, 0x00, 0x01, 0x00}, BinaryKindComponent},
+		{"unknown layer", []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x02, 0x00}, BinaryKindUnknown},
+		{"bad magic", []byte("not wasm"), BinaryKindUnknown},
+		{"truncated", []byte{0x00, 'a', 's', 'm'}, BinaryKindUnknown},
+		{"empty", nil, BinaryKindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, DetectBinaryKind(tt.data))
+		})
+	}
+}
+
+func TestLoadPlugin_Component(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	runtime, err := NewRuntime(ctx, build.Get())
+	require.NoError(t, err)
+	defer runtime.Close(ctx)
+
+	component := []byte{0x00, 'a', 's', 'm', 0x0d, 0x00, 0x01, 0x00}
+	plugin, err := runtime.LoadPlugin(ctx, "py-plugin", component)
+
+	assert.ErrorIs(t, err, ErrComponentNotSupported)
+	assert.Nil(t, plugin)
+}
@@ -0,0 +1,66 @@
+package wasm
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+)
+
+// DiskCompilationCache keeps compiled plugin code in a directory, so plugins
+// are not recompiled in every process. It implements ports.CompilationCache.
+type DiskCompilationCache struct {
+	dir string
+}
+
+// UseDiskCompilationCache makes runtimes created from now on compile plugins
+// through a cache in dir (e.g., ~/.reglet/cache/wasm). Call it before the
+// first runtime is created.
+func UseDiskCompilationCache(dir string) (*DiskCompilationCache, error) {
+	cache, err := wazero.NewCompilationCacheWithDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open compilation cache %s: %w", dir, err)
+	}
+	globalCache = cache
+	return &DiskCompilationCache{dir: dir}, nil
+}
+
+// Prune removes the cache entries written before cutoff and returns their
+// paths relative to the cache directory. With dryRun, it only returns them.
+func (c *DiskCompilationCache) Prune(ctx context.Context, cutoff time.Time, dryRun bool) ([]string, error) {
+	var pruned []string
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == c.dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		if !dryRun {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+		rel, _ := filepath.Rel(c.dir, path)
+		pruned = append(pruned, rel)
+		return nil
+	})
+	return pruned, err
+}
@@ -0,0 +1,50 @@
+package wasm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskCompilationCache_Prune(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	entries := filepath.Join(dir, "wazero-v1.11.0-amd64-linux")
+	require.NoError(t, os.Mkdir(entries, 0o750))
+
+	now := time.Now()
+	old := filepath.Join(entries, "old")
+	fresh := filepath.Join(entries, "fresh")
+	require.NoError(t, os.WriteFile(old, []byte("compiled"), 0o600))
+	require.NoError(t, os.WriteFile(fresh, []byte("compiled"), 0o600))
+	require.NoError(t, os.Chtimes(old, now.Add(-48*time.Hour), now.Add(-48*time.Hour)))
+
+	cache := &DiskCompilationCache{dir: dir}
+	ctx := context.Background()
+
+	pruned, err := cache.Prune(ctx, now.Add(-24*time.Hour), true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("wazero-v1.11.0-amd64-linux", "old")}, pruned)
+	assert.FileExists(t, old)
+
+	pruned, err = cache.Prune(ctx, now.Add(-24*time.Hour), false)
+	require.NoError(t, err)
+	assert.Len(t, pruned, 1)
+	assert.NoFileExists(t, old)
+	assert.FileExists(t, fresh)
+}
+
+func TestDiskCompilationCache_PruneMissingDir(t *testing.T) {
+	t.Parallel()
+
+	cache := &DiskCompilationCache{dir: filepath.Join(t.TempDir(), "missing")}
+	pruned, err := cache.Prune(context.Background(), time.Now(), false)
+	require.NoError(t, err)
+	assert.Empty(t, pruned)
+}
@@ -0,0 +1,58 @@
+package wasm
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilePlugin_RecordAndReplay(t *testing.T) {
+	t.Parallel()
+	wasmBytes := getWasmBytes(t, "file")
+
+	dir, err := filepath.Abs(t.TempDir())
+	require.NoError(t, err)
+	path := filepath.Join(dir, "motd")
+	require.NoError(t, os.WriteFile(path, []byte("recorded"), 0o600))
+
+	caps := map[string][]capabilities.Capability{
+		"file": {{Kind: "fs", Pattern: "read:" + dir + "/**"}},
+	}
+	ctx := context.Background()
+	runtime, err := NewRuntimeWithCapabilities(ctx, build.Get(), caps, nil, 0)
+	require.NoError(t, err)
+	defer runtime.Close(ctx)
+
+	plugin, err := runtime.LoadPlugin(ctx, "file", wasmBytes)
+	require.NoError(t, err)
+	config := Config{Values: map[string]interface{}{"path": path, "read_content": true}}
+
+	observe := func(cassette *hostfuncs.Cassette) string {
+		result, err := plugin.Observe(WithCassette(ctx, cassette), config)
+		require.NoError(t, err)
+		require.NotNil(t, result.Evidence)
+		require.Nil(t, result.Evidence.Error, "%+v", result.Evidence.Error)
+		content, err := base64.StdEncoding.DecodeString(result.Evidence.Data["content_b64"].(string))
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	recorder := hostfuncs.NewRecordingCassette()
+	assert.Equal(t, "recorded", observe(recorder))
+
+	cassettePath := filepath.Join(t.TempDir(), "file.cassette.json")
+	require.NoError(t, recorder.Save(cassettePath))
+	require.NoError(t, os.WriteFile(path, []byte("changed"), 0o600))
+
+	player, err := hostfuncs.LoadCassette(cassettePath)
+	require.NoError(t, err)
+	assert.Equal(t, "recorded", observe(player))
+}
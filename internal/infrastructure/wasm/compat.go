@@ -0,0 +1,49 @@
+package wasm
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Plugin SDK versions this host runs plugins of: from MinSDKVersion up to,
+// but excluding, the next major version after MaxSDKMajor.
+const (
+	MinSDKVersion = "0.1.0-alpha"
+	MaxSDKMajor   = 0
+)
+
+// CheckCompatibility verifies that a plugin can run on this host: it must be
+// built with a supported SDK version, and this host must be at least the
+// plugin's minimum host version. Plugins that do not report an SDK version
+// (not built with the Go SDK) and development builds of the host ("dev") skip
+// the respective check.
+func CheckCompatibility(info *PluginInfo, hostVersion string) error {
+	if info.SDKVersion != "" {
+		sdk, err := semver.NewVersion(info.SDKVersion)
+		if err != nil {
+			return fmt.Errorf("plugin %s reports invalid SDK version %q", info.Name, info.SDKVersion)
+		}
+		if sdk.LessThan(semver.MustParse(MinSDKVersion)) || sdk.Major() > MaxSDKMajor {
+			return fmt.Errorf("plugin %s is built with SDK %s, which this reglet does not support (supported: %s to %d.x); rebuild the plugin with a supported SDK",
+				info.Name, info.SDKVersion, MinSDKVersion, MaxSDKMajor)
+		}
+	}
+
+	if info.MinHostVersion == "" {
+		return nil
+	}
+	host, err := semver.NewVersion(hostVersion)
+	if err != nil {
+		// Development builds run every plugin
+		return nil
+	}
+	minimum, err := semver.NewVersion(info.MinHostVersion)
+	if err != nil {
+		return fmt.Errorf("plugin %s reports invalid minimum host version %q", info.Name, info.MinHostVersion)
+	}
+	if host.LessThan(minimum) {
+		return fmt.Errorf("plugin %s requires reglet %s or newer (this is %s)", info.Name, info.MinHostVersion, hostVersion)
+	}
+	return nil
+}
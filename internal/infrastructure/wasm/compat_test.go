@@ -0,0 +1,55 @@
+package wasm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		info    PluginInfo
+		host    string
+		wantErr string
+	}{
+		{name: "no build metadata", info: PluginInfo{Name: "file"}, host: "0.3.0"},
+		{name: "compatible", info: PluginInfo{Name: "file", SDKVersion: "0.1.0-alpha", MinHostVersion: "0.2.0"}, host: "0.3.0"},
+		{name: "v-prefixed host", info: PluginInfo{Name: "file", SDKVersion: "0.2.1", MinHostVersion: "0.2.0"}, host: "v0.2.0"},
+		{name: "development host", info: PluginInfo{Name: "file", MinHostVersion: "9.0.0"}, host: "dev"},
+		{name: "host too old", info: PluginInfo{Name: "file", MinHostVersion: "0.4.0"}, host: "0.3.9", wantErr: "requires reglet 0.4.0 or newer"},
+		{name: "SDK too new", info: PluginInfo{Name: "file", SDKVersion: "1.0.0"}, host: "0.3.0", wantErr: "built with SDK 1.0.0"},
+		{name: "SDK too old", info: PluginInfo{Name: "file", SDKVersion: "0.0.9"}, host: "0.3.0", wantErr: "built with SDK 0.0.9"},
+		{name: "invalid SDK version", info: PluginInfo{Name: "file", SDKVersion: "latest"}, host: "0.3.0", wantErr: `invalid SDK version "latest"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := CheckCompatibility(&tt.info, tt.host)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestParsePluginInfo_BuildMetadata(t *testing.T) {
+	t.Parallel()
+
+	info, err := ParsePluginInfo([]byte(`{"name":"file","version":"1.2.0","sdk_version":"0.1.0-alpha",` +
+		`"min_host_version":"0.2.0","commit":"4f2a9c1","build_time":"2026-03-01T12:00:00Z","go_version":"go1.25.5"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "0.1.0-alpha", info.SDKVersion)
+	assert.Equal(t, "0.2.0", info.MinHostVersion)
+	assert.Equal(t, "4f2a9c1", info.Commit)
+	assert.Equal(t, "2026-03-01T12:00:00Z", info.BuildTime)
+	assert.Equal(t, "go1.25.5", info.GoVersion)
+}
@@ -0,0 +1,348 @@
+package hostfuncs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/tetratelabs/wazero/api"
+)
+
+const (
+	// defaultArchiveEntries is how many members archive_list returns when the
+	// request sets no limit, and maxArchiveEntries the most it returns at all.
+	defaultArchiveEntries = 1000
+	maxArchiveEntries     = 100000
+
+	// defaultArchiveReadBytes is how much of a member archive_read_entry
+	// returns when the request sets no limit, and maxArchiveReadBytes the
+	// most it returns at all, since the data is copied into guest memory.
+	defaultArchiveReadBytes = 1 << 20
+	maxArchiveReadBytes     = 16 << 20
+)
+
+// Archive formats.
+const (
+	archiveTar    = "tar"
+	archiveTarGz  = "tar.gz"
+	archiveTarZst = "tar.zst"
+	archiveZip    = "zip"
+)
+
+// errStopWalk ends an archive walk early without an error.
+var errStopWalk = errors.New("stop walk")
+
+// ArchiveList lists the members of a tar or zip archive on the host.
+// It receives a packed uint64 (ptr+len) pointing to a JSON-encoded ArchiveListRequestWire.
+// It returns a packed uint64 (ptr+len) pointing to a JSON-encoded ArchiveListResponseWire.
+func ArchiveList(ctx context.Context, mod api.Module, stack []uint64, checker *CapabilityChecker) {
+	var request ArchiveListRequestWire
+	if detail := readGuestJSON(ctx, mod, stack[0], "archive list", &request); detail != nil {
+		stack[0] = hostWriteResponse(ctx, mod, ArchiveListResponseWire{Error: detail})
+		return
+	}
+
+	listCtx, cancel := createContextFromWire(ctx, request.Context)
+	defer cancel()
+
+	if detail := checkFileRead(ctx, checker, getPluginName(ctx, mod), request.Path); detail != nil {
+		stack[0] = hostWriteResponse(ctx, mod, ArchiveListResponseWire{Error: detail})
+		return
+	}
+
+	stack[0] = hostWriteResponse(ctx, mod, listArchive(listCtx, request.Path, request.Format, request.MaxEntries))
+}
+
+// ArchiveReadEntry reads one member of a tar or zip archive on the host.
+// It receives a packed uint64 (ptr+len) pointing to a JSON-encoded ArchiveReadRequestWire.
+// It returns a packed uint64 (ptr+len) pointing to a JSON-encoded ArchiveReadResponseWire.
+func ArchiveReadEntry(ctx context.Context, mod api.Module, stack []uint64, checker *CapabilityChecker) {
+	var request ArchiveReadRequestWire
+	if detail := readGuestJSON(ctx, mod, stack[0], "archive read", &request); detail != nil {
+		stack[0] = hostWriteResponse(ctx, mod, ArchiveReadResponseWire{Error: detail})
+		return
+	}
+
+	readCtx, cancel := createContextFromWire(ctx, request.Context)
+	defer cancel()
+
+	if detail := checkFileRead(ctx, checker, getPluginName(ctx, mod), request.Path); detail != nil {
+		stack[0] = hostWriteResponse(ctx, mod, ArchiveReadResponseWire{Error: detail})
+		return
+	}
+
+	stack[0] = hostWriteResponse(ctx, mod, readArchiveEntry(readCtx, request.Path, request.Format, request.Entry, request.MaxBytes))
+}
+
+// readGuestJSON reads and unmarshals a request from guest memory. what
+// names the request in error messages.
+func readGuestJSON(ctx context.Context, mod api.Module, packed uint64, what string, v any) *ErrorDetail {
+	ptr, length := unpackPtrLen(packed)
+	requestBytes, ok := mod.Memory().Read(ptr, length)
+	if !ok {
+		errMsg := fmt.Sprintf("hostfuncs: failed to read %s request from Guest memory", what)
+		slog.ErrorContext(ctx, errMsg)
+		return &ErrorDetail{Message: errMsg, Type: "internal"}
+	}
+	if err := json.Unmarshal(requestBytes, v); err != nil {
+		errMsg := fmt.Sprintf("hostfuncs: failed to unmarshal %s request: %v", what, err)
+		slog.ErrorContext(ctx, errMsg)
+		return &ErrorDetail{Message: errMsg, Type: "internal"}
+	}
+	return nil
+}
+
+// listArchive returns up to maxEntries members of the archive at path.
+func listArchive(ctx context.Context, archivePath, format string, maxEntries int) ArchiveListResponseWire {
+	if maxEntries <= 0 {
+		maxEntries = defaultArchiveEntries
+	}
+	maxEntries = min(maxEntries, maxArchiveEntries)
+
+	response := ArchiveListResponseWire{Entries: []ArchiveEntryWire{}}
+	format, err := walkArchive(ctx, archivePath, format, func(entry ArchiveEntryWire, _ func() (io.Reader, error)) error {
+		if len(response.Entries) == maxEntries {
+			response.Truncated = true
+			return errStopWalk
+		}
+		response.Entries = append(response.Entries, entry)
+		return nil
+	})
+	if err != nil {
+		return ArchiveListResponseWire{Error: archiveErrorDetail(err)}
+	}
+	response.Format = format
+	return response
+}
+
+// readArchiveEntry returns up to maxBytes of the member named entry.
+func readArchiveEntry(ctx context.Context, archivePath, format, entry string, maxBytes int64) ArchiveReadResponseWire {
+	if maxBytes <= 0 {
+		maxBytes = defaultArchiveReadBytes
+	}
+	maxBytes = min(maxBytes, maxArchiveReadBytes)
+
+	want := cleanEntryName(entry)
+	var response ArchiveReadResponseWire
+	found := false
+	_, err := walkArchive(ctx, archivePath, format, func(member ArchiveEntryWire, open func() (io.Reader, error)) error {
+		if cleanEntryName(member.Name) != want {
+			return nil
+		}
+		found = true
+		response.Entry = member
+		if member.Type != "file" {
+			return fmt.Errorf("archive entry %s is a %s, not a file", member.Name, member.Type)
+		}
+		r, err := open()
+		if err != nil {
+			return err
+		}
+		var data bytes.Buffer
+		if _, err := io.Copy(&data, io.LimitReader(r, maxBytes+1)); err != nil {
+			return err
+		}
+		if int64(data.Len()) > maxBytes {
+			data.Truncate(int(maxBytes))
+			response.Truncated = true
+		}
+		response.Data = data.Bytes()
+		return errStopWalk
+	})
+	if err != nil {
+		return ArchiveReadResponseWire{Error: archiveErrorDetail(err)}
+	}
+	if !found {
+		return ArchiveReadResponseWire{Error: &ErrorDetail{
+			Message:    fmt.Sprintf("archive %s has no entry %s", archivePath, entry),
+			Type:       "validation",
+			IsNotFound: true,
+		}}
+	}
+	return response
+}
+
+// cleanEntryName normalizes a member name, so "./etc/hosts" and "etc/hosts" match.
+func cleanEntryName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// archiveErrorDetail converts an archive walk error to an ErrorDetail.
+func archiveErrorDetail(err error) *ErrorDetail {
+	detail := toErrorDetail(err)
+	if errors.Is(err, context.DeadlineExceeded) {
+		detail.Type = "timeout"
+		detail.IsTimeout = true
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		detail.IsNotFound = true
+	}
+	return detail
+}
+
+// walkArchive calls fn for each member of the archive at path in order,
+// until fn returns an error. open returns the member's contents; it is only
+// valid during the call. It returns the archive format, detected from the
+// file's magic bytes unless given.
+func walkArchive(ctx context.Context, archivePath, format string, fn func(entry ArchiveEntryWire, open func() (io.Reader, error)) error) (string, error) {
+	f, err := os.Open(archivePath) //nolint:gosec // G304: path is checked against the plugin's fs capabilities
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	if format == "" {
+		if format, err = detectArchiveFormat(f); err != nil {
+			return "", err
+		}
+	}
+
+	r := &contextReader{ctx: ctx, r: f}
+	switch format {
+	case archiveZip:
+		info, err := f.Stat()
+		if err != nil {
+			return format, err
+		}
+		err = walkZip(ctx, f, info.Size(), fn)
+		return format, ignoreStop(err)
+	case archiveTar:
+		return format, ignoreStop(walkTar(r, fn))
+	case archiveTarGz:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return format, err
+		}
+		defer func() { _ = gz.Close() }()
+		return format, ignoreStop(walkTar(gz, fn))
+	case archiveTarZst:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return format, err
+		}
+		defer zr.Close()
+		return format, ignoreStop(walkTar(zr, fn))
+	default:
+		return format, fmt.Errorf("unsupported archive format %q (want tar, tar.gz, tar.zst or zip)", format)
+	}
+}
+
+// ignoreStop drops errStopWalk.
+func ignoreStop(err error) error {
+	if errors.Is(err, errStopWalk) {
+		return nil
+	}
+	return err
+}
+
+// detectArchiveFormat reads the magic bytes of f and seeks back to its start.
+func detectArchiveFormat(f *os.File) (string, error) {
+	magic := make([]byte, 4)
+	n, err := io.ReadFull(f, magic)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	magic = magic[:n]
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK\x03\x04")), bytes.HasPrefix(magic, []byte("PK\x05\x06")):
+		return archiveZip, nil
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return archiveTarGz, nil
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return archiveTarZst, nil
+	default:
+		return archiveTar, nil
+	}
+}
+
+func walkTar(r io.Reader, fn func(ArchiveEntryWire, func() (io.Reader, error)) error) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		entry := ArchiveEntryWire{
+			Name:    header.Name,
+			Type:    "other",
+			Size:    header.Size,
+			Mode:    uint32(header.Mode & 0o7777), //nolint:gosec // G115: masked to permission bits
+			ModTime: header.ModTime.UTC(),
+		}
+		switch header.Typeflag {
+		case tar.TypeReg:
+			entry.Type = "file"
+		case tar.TypeDir:
+			entry.Type = "dir"
+		case tar.TypeSymlink, tar.TypeLink:
+			entry.Type = "symlink"
+			entry.LinkTarget = header.Linkname
+		}
+		if err := fn(entry, func() (io.Reader, error) { return tr, nil }); err != nil {
+			return err
+		}
+	}
+}
+
+func walkZip(ctx context.Context, f *os.File, size int64, fn func(ArchiveEntryWire, func() (io.Reader, error)) error) error {
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		return err
+	}
+	for _, file := range zr.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		mode := file.Mode()
+		entry := ArchiveEntryWire{
+			Name:    file.Name,
+			Type:    "other",
+			Size:    int64(file.UncompressedSize64), //nolint:gosec // G115: archive sizes fit in int64
+			Mode:    uint32(mode.Perm()),
+			ModTime: file.Modified.UTC(),
+		}
+		switch {
+		case mode.IsRegular():
+			entry.Type = "file"
+		case mode.IsDir():
+			entry.Type = "dir"
+		case mode&fs.ModeSymlink != 0:
+			entry.Type = "symlink"
+		}
+
+		var rc io.ReadCloser
+		open := func() (io.Reader, error) {
+			var openErr error
+			if rc, openErr = file.Open(); openErr != nil {
+				return nil, openErr
+			}
+			return &contextReader{ctx: ctx, r: rc}, nil
+		}
+		err := fn(entry, open)
+		if rc != nil {
+			_ = rc.Close()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
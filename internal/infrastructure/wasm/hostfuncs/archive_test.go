@@ -0,0 +1,129 @@
+package hostfuncs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var archiveModTime = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// writeTar writes a tar archive of a directory and a file, gzipped when gz is set.
+func writeTar(t *testing.T, path string, gz bool) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, f.Close()) }()
+
+	var w io.Writer = f
+	if gz {
+		zw := gzip.NewWriter(f)
+		defer func() { require.NoError(t, zw.Close()) }()
+		w = zw
+	}
+	tw := tar.NewWriter(w)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "./etc/", Typeflag: tar.TypeDir, Mode: 0o755, ModTime: archiveModTime}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "./etc/hosts", Typeflag: tar.TypeReg, Mode: 0o644, Size: 9, ModTime: archiveModTime}))
+	_, err = tw.Write([]byte("127.0.0.1"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+}
+
+func writeZip(t *testing.T, path string) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, f.Close()) }()
+
+	zw := zip.NewWriter(f)
+	_, err = zw.CreateHeader(&zip.FileHeader{Name: "etc/", Modified: archiveModTime})
+	require.NoError(t, err)
+	header := &zip.FileHeader{Name: "etc/hosts", Method: zip.Deflate, Modified: archiveModTime}
+	header.SetMode(0o644)
+	w, err := zw.CreateHeader(header)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("127.0.0.1"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+}
+
+func TestListArchive(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	archives := map[string]string{
+		archiveTar:   filepath.Join(dir, "backup.tar"),
+		archiveTarGz: filepath.Join(dir, "backup.tgz"),
+		archiveZip:   filepath.Join(dir, "backup.zip"),
+	}
+	writeTar(t, archives[archiveTar], false)
+	writeTar(t, archives[archiveTarGz], true)
+	writeZip(t, archives[archiveZip])
+
+	for format, path := range archives {
+		res := listArchive(context.Background(), path, "", 0)
+		require.Nil(t, res.Error, format)
+		assert.Equal(t, format, res.Format)
+		assert.False(t, res.Truncated)
+		require.Len(t, res.Entries, 2, format)
+		assert.Equal(t, "dir", res.Entries[0].Type, format)
+		hosts := res.Entries[1]
+		assert.Equal(t, "etc/hosts", cleanEntryName(hosts.Name), format)
+		assert.Equal(t, "file", hosts.Type, format)
+		assert.Equal(t, int64(9), hosts.Size, format)
+		assert.Equal(t, uint32(0o644), hosts.Mode, format)
+		assert.True(t, archiveModTime.Equal(hosts.ModTime), format)
+	}
+
+	res := listArchive(context.Background(), archives[archiveTar], "", 1)
+	require.Nil(t, res.Error)
+	assert.Len(t, res.Entries, 1)
+	assert.True(t, res.Truncated)
+
+	res = listArchive(context.Background(), archives[archiveTar], "rar", 0)
+	require.NotNil(t, res.Error)
+	assert.Contains(t, res.Error.Message, "unsupported archive format")
+
+	res = listArchive(context.Background(), filepath.Join(dir, "missing.tar"), "", 0)
+	require.NotNil(t, res.Error)
+	assert.True(t, res.Error.IsNotFound)
+}
+
+func TestReadArchiveEntry(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tarPath := filepath.Join(dir, "backup.tar.gz")
+	zipPath := filepath.Join(dir, "backup.zip")
+	writeTar(t, tarPath, true)
+	writeZip(t, zipPath)
+
+	for _, path := range []string{tarPath, zipPath} {
+		res := readArchiveEntry(context.Background(), path, "", "etc/hosts", 0)
+		require.Nil(t, res.Error, path)
+		assert.Equal(t, []byte("127.0.0.1"), res.Data)
+		assert.False(t, res.Truncated)
+
+		res = readArchiveEntry(context.Background(), path, "", "/etc/hosts", 3)
+		require.Nil(t, res.Error, path)
+		assert.Equal(t, []byte("127"), res.Data)
+		assert.True(t, res.Truncated)
+
+		res = readArchiveEntry(context.Background(), path, "", "etc/passwd", 0)
+		require.NotNil(t, res.Error, path)
+		assert.True(t, res.Error.IsNotFound)
+
+		res = readArchiveEntry(context.Background(), path, "", "etc", 0)
+		require.NotNil(t, res.Error, path)
+		assert.Contains(t, res.Error.Message, "not a file")
+	}
+}
@@ -0,0 +1,440 @@
+package hostfuncs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing/fstest"
+	"time"
+
+	"github.com/reglet-dev/reglet/wireformat"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// CassetteVersion is the cassette file format version.
+const CassetteVersion = 1
+
+// CassetteMode selects whether a cassette captures or serves plugin interactions.
+type CassetteMode int
+
+const (
+	// CassetteRecord runs host functions normally and captures every call.
+	CassetteRecord CassetteMode = iota + 1
+	// CassetteReplay serves recorded responses without touching the network,
+	// running commands or reading the host filesystem.
+	CassetteReplay
+)
+
+// Interaction is a single recorded host function call.
+type Interaction struct {
+	Plugin   string          `json:"plugin"`
+	Function string          `json:"function"`
+	Request  json.RawMessage `json:"request"` // JSON request without its context block
+	Response json.RawMessage `json:"response"`
+	// RequestPayloads and ResponsePayloads hold raw frames (e.g. binary HTTP bodies)
+	RequestPayloads  []CassettePayload `json:"request_payloads,omitempty"`
+	ResponsePayloads []CassettePayload `json:"response_payloads,omitempty"`
+}
+
+// CassettePayload is a raw payload frame of a recorded call.
+type CassettePayload struct {
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// CassetteFile is a file a plugin read through a read-only filesystem mount.
+type CassetteFile struct {
+	ModTime time.Time   `json:"mod_time"`
+	Data    []byte      `json:"data"`
+	Mode    fs.FileMode `json:"mode"`
+}
+
+// cassetteDocument is the on-disk cassette format.
+type cassetteDocument struct {
+	RecordedAt   time.Time               `json:"recorded_at"`
+	Files        map[string]CassetteFile `json:"files,omitempty"`
+	Interactions []Interaction           `json:"interactions"`
+	Version      int                     `json:"version"`
+}
+
+// requestRedactor removes secrets from recorded requests.
+type requestRedactor interface {
+	Redact(data interface{}) interface{}
+}
+
+// Cassette captures plugin<->host interactions (DNS, HTTP, TCP, SMTP and
+// exec calls, and files read through read-only mounts) and serves them back,
+// so a profile can be re-executed offline with the same plugin inputs.
+//
+// Calls are matched by plugin, function and request (ignoring the context
+// block). Identical calls are replayed in recorded order; once exhausted, the
+// last recording is repeated.
+type Cassette struct {
+	redactor     requestRedactor
+	files        map[string]CassetteFile
+	cursor       map[string]int
+	recordedAt   time.Time
+	interactions []Interaction
+	mode         CassetteMode
+	mu           sync.Mutex
+}
+
+// CassetteOption configures a Cassette.
+type CassetteOption func(*Cassette)
+
+// WithCassetteRedactor redacts secrets from requests before they are stored
+// or matched, so recorded cassettes do not contain credentials.
+func WithCassetteRedactor(redactor requestRedactor) CassetteOption {
+	return func(c *Cassette) {
+		c.redactor = redactor
+	}
+}
+
+// NewRecordingCassette creates an empty cassette in record mode.
+func NewRecordingCassette(opts ...CassetteOption) *Cassette {
+	c := &Cassette{
+		mode:       CassetteRecord,
+		recordedAt: time.Now().UTC(),
+		files:      make(map[string]CassetteFile),
+		cursor:     make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// LoadCassette reads a recorded cassette for replay.
+func LoadCassette(path string, opts ...CassetteOption) (*Cassette, error) {
+	//nolint:gosec // G304: cassette path is provided by the user
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var doc cassetteDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if doc.Version != CassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d in %s (expected %d)", doc.Version, path, CassetteVersion)
+	}
+
+	// Saved cassettes are indented; requests are matched and responses served compact
+	for i := range doc.Interactions {
+		in := &doc.Interactions[i]
+		for _, raw := range []*json.RawMessage{&in.Request, &in.Response} {
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, *raw); err != nil {
+				return nil, fmt.Errorf("invalid interaction %d in cassette %s: %w", i, path, err)
+			}
+			*raw = compact.Bytes()
+		}
+	}
+
+	c := &Cassette{
+		mode:         CassetteReplay,
+		recordedAt:   doc.RecordedAt,
+		interactions: doc.Interactions,
+		files:        doc.Files,
+		cursor:       make(map[string]int),
+	}
+	if c.files == nil {
+		c.files = make(map[string]CassetteFile)
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Mode returns whether the cassette records or replays.
+func (c *Cassette) Mode() CassetteMode {
+	return c.mode
+}
+
+// RecordedAt returns when the cassette was recorded. Replays can pin their
+// clock to it so time-relative checks see the recorded time.
+func (c *Cassette) RecordedAt() time.Time {
+	return c.recordedAt
+}
+
+// Interactions returns a copy of the recorded calls.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// Save writes the cassette as JSON.
+func (c *Cassette) Save(path string) error {
+	c.mu.Lock()
+	doc := cassetteDocument{
+		Version:      CassetteVersion,
+		RecordedAt:   c.recordedAt,
+		Interactions: c.interactions,
+		Files:        c.files,
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// record appends a completed call.
+func (c *Cassette) record(interaction Interaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, interaction)
+}
+
+// next returns the recorded response for a call.
+func (c *Cassette) next(plugin, function string, request json.RawMessage, payloads []CassettePayload) (Interaction, bool) {
+	key := plugin + "\x00" + function + "\x00" + string(request)
+	for _, p := range payloads {
+		key += "\x00" + p.ContentType + "\x00" + string(p.Data)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var matches []int
+	for i, in := range c.interactions {
+		if in.Plugin == plugin && in.Function == function &&
+			bytes.Equal(in.Request, request) && payloadsEqual(in.RequestPayloads, payloads) {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		return Interaction{}, false
+	}
+
+	n := c.cursor[key]
+	c.cursor[key] = n + 1
+	return c.interactions[matches[min(n, len(matches)-1)]], true
+}
+
+// normalizeRequest drops the context block, which varies between runs, and
+// redacts secrets. The result is compact JSON with sorted keys.
+func (c *Cassette) normalizeRequest(request []byte) (json.RawMessage, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(request, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "context")
+
+	var normalized interface{} = fields
+	if c.redactor != nil {
+		normalized = c.redactor.Redact(normalized)
+	}
+	return json.Marshal(normalized)
+}
+
+func payloadsEqual(a, b []CassettePayload) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ContentType != b[i].ContentType || !bytes.Equal(a[i].Data, b[i].Data) {
+			return false
+		}
+	}
+	return true
+}
+
+func toCassettePayloads(frames []wireformat.Frame) []CassettePayload {
+	if len(frames) == 0 {
+		return nil
+	}
+	payloads := make([]CassettePayload, len(frames))
+	for i, f := range frames {
+		payloads[i] = CassettePayload{ContentType: f.ContentType, Data: bytes.Clone(f.Data)}
+	}
+	return payloads
+}
+
+var cassetteKey = &contextKey{name: "cassette"}
+
+// WithCassette attaches a cassette to the context. Host function calls and
+// plugin instances created with the context record into or replay from it.
+func WithCassette(ctx context.Context, cassette *Cassette) context.Context {
+	if cassette == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, cassetteKey, cassette)
+}
+
+// CassetteFromContext returns the cassette attached to the context, if any.
+func CassetteFromContext(ctx context.Context) (*Cassette, bool) {
+	cassette, ok := ctx.Value(cassetteKey).(*Cassette)
+	return cassette, ok
+}
+
+// recordable wraps a request/response host function so calls are recorded
+// into, or replayed from, the cassette in ctx. Without a cassette the
+// function runs unchanged.
+func recordable(function string, fn api.GoModuleFunc) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		cassette, ok := CassetteFromContext(ctx)
+		if !ok {
+			fn(ctx, mod, stack)
+			return
+		}
+
+		// Copy the request out of guest memory before the call can reuse it
+		header, frames, err := hostReadMessage(mod, stack[0])
+		if err != nil {
+			fn(ctx, mod, stack) // Reports the malformed request itself
+			return
+		}
+		request, err := cassette.normalizeRequest(header)
+		if err != nil {
+			fn(ctx, mod, stack)
+			return
+		}
+		payloads := toCassettePayloads(frames)
+		plugin := getPluginName(ctx, mod)
+
+		if cassette.mode == CassetteReplay {
+			interaction, ok := cassette.next(plugin, function, request, payloads)
+			if !ok {
+				stack[0] = hostWriteResponse(ctx, mod, ErrorResponseWire{Error: &ErrorDetail{
+					Message: fmt.Sprintf("replay: no recorded %s call from plugin %s for request %s", function, plugin, request),
+					Type:    "internal",
+				}})
+				return
+			}
+			stack[0] = writeRecordedResponse(ctx, mod, interaction)
+			return
+		}
+
+		fn(ctx, mod, stack)
+
+		ptr, length := unpackPtrLen(stack[0])
+		data, ok := mod.Memory().Read(ptr, length)
+		if !ok || stack[0] == 0 {
+			return
+		}
+		interaction := Interaction{
+			Plugin:          plugin,
+			Function:        function,
+			Request:         request,
+			RequestPayloads: payloads,
+		}
+		if wireformat.IsFramed(data) {
+			responseFrames, err := wireformat.DecodeFrames(data)
+			if err != nil || len(responseFrames) == 0 {
+				return
+			}
+			interaction.Response = bytes.Clone(responseFrames[0].Data)
+			interaction.ResponsePayloads = toCassettePayloads(responseFrames[1:])
+		} else {
+			interaction.Response = bytes.Clone(data)
+		}
+		cassette.record(interaction)
+	}
+}
+
+// writeRecordedResponse writes a recorded response to guest memory.
+func writeRecordedResponse(ctx context.Context, mod api.Module, interaction Interaction) uint64 {
+	if len(interaction.ResponsePayloads) == 0 {
+		return hostWriteBytes(ctx, mod, interaction.Response)
+	}
+
+	frames := []wireformat.Frame{{ContentType: wireformat.ContentTypeJSON, Data: interaction.Response}}
+	for _, p := range interaction.ResponsePayloads {
+		frames = append(frames, wireformat.Frame{ContentType: p.ContentType, Data: p.Data})
+	}
+	data, err := wireformat.EncodeFrames(frames...)
+	if err != nil {
+		return hostWriteResponse(ctx, mod, ErrorResponseWire{Error: &ErrorDetail{Message: err.Error(), Type: "internal"}})
+	}
+	return hostWriteBytes(ctx, mod, data)
+}
+
+// FS returns the filesystem to mount read-only at hostRoot. When recording,
+// files are read from host, the host directory as the plugin may access it,
+// and captured as they are opened; when replaying, only captured files exist.
+func (c *Cassette) FS(hostRoot string, host fs.FS) fs.FS {
+	if c.mode == CassetteReplay {
+		return c.replayFS(hostRoot)
+	}
+	return &recordingFS{cassette: c, root: hostRoot, fsys: host}
+}
+
+// replayFS builds an in-memory filesystem from the files captured under hostRoot.
+func (c *Cassette) replayFS(hostRoot string) fs.FS {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	mapFS := fstest.MapFS{}
+	for hostPath, file := range c.files {
+		rel, err := filepath.Rel(hostRoot, hostPath)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		mapFS[filepath.ToSlash(rel)] = &fstest.MapFile{Data: file.Data, Mode: file.Mode, ModTime: file.ModTime}
+	}
+	return mapFS
+}
+
+// recordingFS captures regular files as plugins open them.
+type recordingFS struct {
+	cassette *Cassette
+	fsys     fs.FS
+	root     string
+}
+
+// Open opens a file, capturing the contents of regular files. Directories
+// are recorded as existing but their listings are not captured.
+func (r *recordingFS) Open(name string) (fs.File, error) {
+	f, err := r.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	hostPath := filepath.Join(r.root, filepath.FromSlash(name))
+	if info.IsDir() {
+		r.cassette.mu.Lock()
+		if name != "." {
+			r.cassette.files[hostPath] = CassetteFile{Mode: info.Mode(), ModTime: info.ModTime()}
+		}
+		r.cassette.mu.Unlock()
+		return f, nil
+	}
+
+	data, err := io.ReadAll(f)
+	_ = f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	r.cassette.mu.Lock()
+	r.cassette.files[hostPath] = CassetteFile{Data: data, Mode: info.Mode(), ModTime: info.ModTime()}
+	r.cassette.mu.Unlock()
+
+	return fstest.MapFS{path.Base(name): &fstest.MapFile{
+		Data:    data,
+		Mode:    info.Mode(),
+		ModTime: info.ModTime(),
+	}}.Open(path.Base(name))
+}
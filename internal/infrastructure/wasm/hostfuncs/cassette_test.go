@@ -0,0 +1,216 @@
+package hostfuncs
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/wireformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// fakeMemory is a growable guest memory with a bump allocator.
+type fakeMemory struct {
+	api.Memory
+	data []byte
+}
+
+func (m *fakeMemory) Read(offset, count uint32) ([]byte, bool) {
+	if int(offset)+int(count) > len(m.data) {
+		return nil, false
+	}
+	return m.data[offset : offset+count], true
+}
+
+func (m *fakeMemory) Write(offset uint32, v []byte) bool {
+	if int(offset)+len(v) > len(m.data) {
+		return false
+	}
+	copy(m.data[offset:], v)
+	return true
+}
+
+// allocate reserves n bytes and returns their offset.
+func (m *fakeMemory) allocate(n int) uint32 {
+	ptr := uint32(len(m.data)) //nolint:gosec // G115: test memory is small
+	m.data = append(m.data, make([]byte, n)...)
+	return ptr
+}
+
+type fakeAllocate struct {
+	api.Function
+	mem *fakeMemory
+}
+
+func (f fakeAllocate) Call(_ context.Context, params ...uint64) ([]uint64, error) {
+	return []uint64{uint64(f.mem.allocate(int(params[0])))}, nil //nolint:gosec // G115: test sizes are small
+}
+
+// fakeModule provides the guest memory and allocate export host functions use.
+type fakeModule struct {
+	api.Module
+	mem *fakeMemory
+}
+
+func newFakeModule() *fakeModule {
+	return &fakeModule{mem: &fakeMemory{data: make([]byte, 8)}}
+}
+
+func (m *fakeModule) Memory() api.Memory { return m.mem }
+
+func (m *fakeModule) Name() string { return "guest" }
+
+func (m *fakeModule) ExportedFunction(name string) api.Function {
+	if name == "allocate" {
+		return fakeAllocate{mem: m.mem}
+	}
+	return nil
+}
+
+// call writes request into guest memory, invokes fn and returns the response.
+func (m *fakeModule) call(t *testing.T, ctx context.Context, fn api.GoModuleFunc, request []byte) []byte {
+	t.Helper()
+	ptr := m.mem.allocate(len(request))
+	m.mem.Write(ptr, request)
+	stack := []uint64{packPtrLen(ptr, uint32(len(request)))} //nolint:gosec // G115: test sizes are small
+	fn(ctx, m, stack)
+	response, ok := m.mem.Read(unpackPtrLen(stack[0]))
+	require.True(t, ok)
+	return append([]byte(nil), response...)
+}
+
+func dnsRequest(t *testing.T, hostname string, timeoutMs int64) []byte {
+	t.Helper()
+	data, err := json.Marshal(DNSRequestWire{
+		Context:  ContextWireFormat{TimeoutMs: timeoutMs, RequestID: "req-" + hostname},
+		Hostname: hostname,
+		Type:     "A",
+	})
+	require.NoError(t, err)
+	return data
+}
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	lookup := recordable("dns_lookup", func(ctx context.Context, mod api.Module, stack []uint64) {
+		calls++
+		stack[0] = hostWriteResponse(ctx, mod, DNSResponseWire{Records: []string{"192.0.2.1"}})
+	})
+
+	recorder := NewRecordingCassette()
+	ctx := WithPluginName(WithCassette(context.Background(), recorder), "dns")
+	recorded := newFakeModule().call(t, ctx, lookup, dnsRequest(t, "example.com", 5000))
+	require.Equal(t, 1, calls)
+
+	interactions := recorder.Interactions()
+	require.Len(t, interactions, 1)
+	assert.Equal(t, "dns", interactions[0].Plugin)
+	assert.Equal(t, "dns_lookup", interactions[0].Function)
+	assert.NotContains(t, string(interactions[0].Request), "context", "context block varies between runs")
+
+	path := filepath.Join(t.TempDir(), "run.cassette.json")
+	require.NoError(t, recorder.Save(path))
+
+	player, err := LoadCassette(path)
+	require.NoError(t, err)
+	assert.Equal(t, CassetteReplay, player.Mode())
+	assert.Equal(t, recorder.RecordedAt(), player.RecordedAt())
+
+	ctx = WithPluginName(WithCassette(context.Background(), player), "dns")
+	replayed := newFakeModule().call(t, ctx, lookup, dnsRequest(t, "example.com", 100))
+	assert.Equal(t, recorded, replayed)
+	assert.Equal(t, 1, calls, "replay does not run the host function")
+
+	missing := newFakeModule().call(t, ctx, lookup, dnsRequest(t, "example.org", 100))
+	var resp DNSResponseWire
+	require.NoError(t, json.Unmarshal(missing, &resp))
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, "replay: no recorded dns_lookup call")
+}
+
+func TestCassette_ReplaysIdenticalCallsInOrder(t *testing.T) {
+	t.Parallel()
+
+	n := 0
+	exec := recordable("exec_command", func(ctx context.Context, mod api.Module, stack []uint64) {
+		n++
+		stack[0] = hostWriteResponse(ctx, mod, ExecResponseWire{Stdout: string(rune('0' + n))})
+	})
+	request := []byte(`{"command":"date"}`)
+
+	recorder := NewRecordingCassette()
+	ctx := WithCassette(context.Background(), recorder)
+	newFakeModule().call(t, ctx, exec, request)
+	newFakeModule().call(t, ctx, exec, request)
+
+	player := &Cassette{mode: CassetteReplay, interactions: recorder.Interactions(), cursor: map[string]int{}}
+	ctx = WithCassette(context.Background(), player)
+
+	var stdout []string
+	for range 3 {
+		var resp ExecResponseWire
+		require.NoError(t, json.Unmarshal(newFakeModule().call(t, ctx, exec, request), &resp))
+		stdout = append(stdout, resp.Stdout)
+	}
+	assert.Equal(t, []string{"1", "2", "2"}, stdout, "the last recording repeats once exhausted")
+}
+
+func TestCassette_FramedResponse(t *testing.T) {
+	t.Parallel()
+
+	body := []byte{0x00, 0xff, 0x10}
+	httpRequest := recordable("http_request", func(ctx context.Context, mod api.Module, stack []uint64) {
+		stack[0] = hostWriteFramed(ctx, mod, HTTPResponseWire{StatusCode: 200},
+			wireformat.Frame{ContentType: wireformat.ContentTypeOctetStream, Data: body})
+	})
+	request := []byte(`{"method":"GET","url":"https://example.com/blob","binary_body":true}`)
+
+	recorder := NewRecordingCassette()
+	recorded := newFakeModule().call(t, WithCassette(context.Background(), recorder), httpRequest, request)
+
+	interactions := recorder.Interactions()
+	require.Len(t, interactions, 1)
+	require.Len(t, interactions[0].ResponsePayloads, 1)
+	assert.Equal(t, body, interactions[0].ResponsePayloads[0].Data)
+
+	player := &Cassette{mode: CassetteReplay, interactions: interactions, cursor: map[string]int{}}
+	replayed := newFakeModule().call(t, WithCassette(context.Background(), player), httpRequest, request)
+	assert.Equal(t, recorded, replayed)
+}
+
+func TestCassette_FS(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "ssh"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "ssh", "sshd_config"), []byte("PermitRootLogin no\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "unread"), []byte("x"), 0o600))
+
+	recorder := NewRecordingCassette()
+	data, err := fs.ReadFile(recorder.FS(root, os.DirFS(root)), "ssh/sshd_config")
+	require.NoError(t, err)
+	assert.Equal(t, "PermitRootLogin no\n", string(data))
+
+	path := filepath.Join(t.TempDir(), "fs.cassette.json")
+	require.NoError(t, recorder.Save(path))
+	player, err := LoadCassette(path)
+	require.NoError(t, err)
+
+	// The host file changes after recording; replay serves the recorded copy
+	require.NoError(t, os.WriteFile(filepath.Join(root, "ssh", "sshd_config"), []byte("PermitRootLogin yes\n"), 0o600))
+
+	replayFS := player.FS(root, os.DirFS(root))
+	data, err = fs.ReadFile(replayFS, "ssh/sshd_config")
+	require.NoError(t, err)
+	assert.Equal(t, "PermitRootLogin no\n", string(data))
+
+	_, err = fs.Stat(replayFS, "unread")
+	assert.ErrorIs(t, err, fs.ErrNotExist, "files not read while recording do not exist on replay")
+}
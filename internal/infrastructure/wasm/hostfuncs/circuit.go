@@ -0,0 +1,222 @@
+package hostfuncs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet/wireformat"
+	"github.com/tetratelabs/wazero/api"
+)
+
+const (
+	// DefaultCircuitThreshold is how many consecutive timeouts open the
+	// circuit of a destination.
+	DefaultCircuitThreshold = 3
+
+	// DefaultCircuitCooldown is how long an open circuit fails calls fast
+	// before letting one through to probe the destination again.
+	DefaultCircuitCooldown = 30 * time.Second
+)
+
+// CodeTargetUnreachable is the error code of calls failed fast by an open circuit.
+const CodeTargetUnreachable = "target_unreachable"
+
+// CircuitBreaker stops network host calls to destinations that keep timing
+// out, so the remaining observations against them fail fast instead of each
+// waiting out its timeout. Destinations are host:port pairs, shared by all
+// plugins and host functions. It is safe for concurrent use.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is the state of one destination.
+type circuit struct {
+	timeouts  int       // consecutive timeouts
+	openUntil time.Time // calls fail fast until then
+}
+
+// NewCircuitBreaker creates a breaker that opens a destination's circuit
+// after threshold consecutive timeouts, for cooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		circuits:  make(map[string]*circuit),
+	}
+}
+
+// allow reports whether a call to destination may go ahead. Once the
+// cooldown of an open circuit ends, calls go ahead until one more times out.
+func (b *CircuitBreaker) allow(destination string) (timeouts int, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, found := b.circuits[destination]
+	if !found || !b.now().Before(c.openUntil) {
+		return 0, true
+	}
+	return c.timeouts, false
+}
+
+// record updates the circuit of destination with the outcome of a call:
+// successes close it, timeouts count towards opening it, and other errors
+// leave it as is. It reports whether the call opened the circuit.
+func (b *CircuitBreaker) record(destination string, outcome callOutcome) (opened bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch outcome {
+	case callSucceeded:
+		delete(b.circuits, destination)
+		return false
+	case callFailed:
+		return false
+	}
+	c, found := b.circuits[destination]
+	if !found {
+		c = &circuit{}
+		b.circuits[destination] = c
+	}
+	c.timeouts++
+	if c.timeouts < b.threshold {
+		return false
+	}
+	c.openUntil = b.now().Add(b.cooldown)
+	return true
+}
+
+// unreachable returns the error of a call failed fast. The message only
+// varies in the destination, so error groups fingerprint these calls alike.
+func (b *CircuitBreaker) unreachable(destination string, timeouts int) *ErrorDetail {
+	return &ErrorDetail{
+		Message: fmt.Sprintf("%s is unreachable: circuit open after %d consecutive timeouts", destination, timeouts),
+		Type:    "network",
+		Code:    CodeTargetUnreachable,
+	}
+}
+
+var circuitBreakerKey = &contextKey{name: "circuit_breaker"}
+
+// WithCircuitBreaker attaches a circuit breaker to the context. Network host
+// calls made with the context fail fast to destinations it has opened.
+func WithCircuitBreaker(ctx context.Context, breaker *CircuitBreaker) context.Context {
+	if breaker == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, circuitBreakerKey, breaker)
+}
+
+// CircuitBreakerFromContext returns the circuit breaker attached to the context, if any.
+func CircuitBreakerFromContext(ctx context.Context) (*CircuitBreaker, bool) {
+	breaker, ok := ctx.Value(circuitBreakerKey).(*CircuitBreaker)
+	return breaker, ok
+}
+
+// breakable wraps a request/response host function so calls to destinations
+// the circuit breaker in ctx has opened fail fast. Calls without a network
+// destination, and all calls without a breaker, run unchanged.
+func breakable(fn api.GoModuleFunc) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		breaker, ok := CircuitBreakerFromContext(ctx)
+		if !ok {
+			fn(ctx, mod, stack)
+			return
+		}
+		header, _, err := hostReadMessage(mod, stack[0])
+		if err != nil {
+			fn(ctx, mod, stack) // Reports the malformed request itself
+			return
+		}
+		destination := callDestination(header)
+		if destination == "" {
+			fn(ctx, mod, stack)
+			return
+		}
+
+		if timeouts, ok := breaker.allow(destination); !ok {
+			stack[0] = hostWriteResponse(ctx, mod, ErrorResponseWire{Error: breaker.unreachable(destination, timeouts)})
+			return
+		}
+
+		fn(ctx, mod, stack)
+
+		if breaker.record(destination, responseOutcome(mod, stack[0])) {
+			slog.WarnContext(ctx, "destination keeps timing out, failing further calls fast",
+				"destination", destination, "plugin", getPluginName(ctx, mod), "cooldown", breaker.cooldown)
+		}
+	}
+}
+
+// callDestination returns the host:port a host function request goes to, or
+// "" for requests without one. DNS lookups count against their nameserver.
+func callDestination(header []byte) string {
+	var request struct {
+		URL        string `json:"url"`
+		Host       string `json:"host"`
+		Port       string `json:"port"`
+		Nameserver string `json:"nameserver"`
+	}
+	if err := json.Unmarshal(header, &request); err != nil {
+		return ""
+	}
+	switch {
+	case request.URL != "":
+		u, err := url.Parse(request.URL)
+		if err != nil || u.Hostname() == "" {
+			return ""
+		}
+		return strings.ToLower(net.JoinHostPort(u.Hostname(), getPort(u)))
+	case request.Host != "":
+		return strings.ToLower(net.JoinHostPort(request.Host, request.Port))
+	default:
+		return strings.ToLower(request.Nameserver)
+	}
+}
+
+// callOutcome classifies host function responses for the circuit breaker.
+type callOutcome int
+
+const (
+	callSucceeded callOutcome = iota
+	callTimedOut
+	callFailed
+)
+
+// responseOutcome classifies the response a host function wrote to guest
+// memory. Framed responses only carry successes.
+func responseOutcome(mod api.Module, packed uint64) callOutcome {
+	ptr, length := unpackPtrLen(packed)
+	data, ok := mod.Memory().Read(ptr, length)
+	if !ok || packed == 0 {
+		return callFailed
+	}
+	if wireformat.IsFramed(data) {
+		return callSucceeded
+	}
+	var response struct {
+		Error *ErrorDetail `json:"error"`
+	}
+	switch err := json.Unmarshal(data, &response); {
+	case err != nil:
+		return callFailed
+	case response.Error == nil:
+		return callSucceeded
+	case response.Error.IsTimeout:
+		return callTimedOut
+	default:
+		return callFailed
+	}
+}
@@ -0,0 +1,100 @@
+package hostfuncs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero/api"
+)
+
+func tcpRequest(t *testing.T, host, port string) []byte {
+	t.Helper()
+	data, err := json.Marshal(TCPRequestWire{Host: host, Port: port})
+	require.NoError(t, err)
+	return data
+}
+
+func TestBreakable_FailsFastAfterTimeouts(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	calls := 0
+	timeout := true
+	connect := breakable(func(ctx context.Context, mod api.Module, stack []uint64) {
+		calls++
+		var detail *ErrorDetail
+		if timeout {
+			detail = &ErrorDetail{Message: "i/o timeout", Type: "timeout", IsTimeout: true}
+		}
+		stack[0] = hostWriteResponse(ctx, mod, TCPResponseWire{Error: detail})
+	})
+
+	ctx := WithCircuitBreaker(context.Background(), breaker)
+	mod := newFakeModule()
+	call := func(host string) *ErrorDetail {
+		var resp TCPResponseWire
+		require.NoError(t, json.Unmarshal(mod.call(t, ctx, connect, tcpRequest(t, host, "443")), &resp))
+		return resp.Error
+	}
+
+	call("db.example.com")
+	call("db.example.com")
+	require.Equal(t, 2, calls)
+
+	detail := call("DB.example.com")
+	require.NotNil(t, detail)
+	assert.Equal(t, 2, calls, "open circuit fails without calling the function")
+	assert.Equal(t, CodeTargetUnreachable, detail.Code)
+	assert.Contains(t, detail.Message, "db.example.com:443")
+
+	call("other.example.com")
+	assert.Equal(t, 3, calls, "other destinations are not affected")
+
+	// After the cooldown a probe goes through; its success closes the circuit
+	now = now.Add(time.Minute)
+	timeout = false
+	assert.Nil(t, call("db.example.com"))
+	assert.Nil(t, call("db.example.com"))
+	assert.Equal(t, 5, calls)
+}
+
+func TestCircuitBreaker_OtherErrorsKeepCount(t *testing.T) {
+	t.Parallel()
+
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.record("db:5432", callTimedOut)
+	breaker.record("db:5432", callFailed)
+	_, ok := breaker.allow("db:5432")
+	assert.True(t, ok)
+
+	assert.True(t, breaker.record("db:5432", callTimedOut))
+	timeouts, ok := breaker.allow("db:5432")
+	assert.False(t, ok)
+	assert.Equal(t, 2, timeouts)
+}
+
+func TestCallDestination(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		request string
+		want    string
+	}{
+		{`{"method":"GET","url":"https://Example.com/health"}`, "example.com:443"},
+		{`{"method":"GET","url":"http://example.com:8080/"}`, "example.com:8080"},
+		{`{"host":"db.internal","port":"5432"}`, "db.internal:5432"},
+		{`{"hostname":"example.com","type":"A","nameserver":"10.0.0.53:53"}`, "10.0.0.53:53"},
+		{`{"hostname":"example.com","type":"A"}`, ""},
+		{`{"command":"ls"}`, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, callDestination([]byte(tt.request)), tt.request)
+	}
+}
@@ -0,0 +1,37 @@
+package hostfuncs
+
+import (
+	"context"
+	"time"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+var clockKey = &contextKey{name: "clock"}
+
+// WithClock attaches a clock to the context. Plugin instances created with
+// the context read wall time from it, through WASI and the time_now host
+// function, instead of the system clock.
+func WithClock(ctx context.Context, clock func() time.Time) context.Context {
+	if clock == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, clockKey, clock)
+}
+
+// ClockFromContext returns the clock attached to the context, if any.
+func ClockFromContext(ctx context.Context) (func() time.Time, bool) {
+	clock, ok := ctx.Value(clockKey).(func() time.Time)
+	return clock, ok
+}
+
+// TimeNow implements the `time_now` host function.
+// It takes no parameters and returns the current time as Unix nanoseconds,
+// from the clock in ctx when one is set.
+func TimeNow(ctx context.Context, _ api.Module, stack []uint64) {
+	now := time.Now()
+	if clock, ok := ClockFromContext(ctx); ok {
+		now = clock()
+	}
+	stack[0] = uint64(now.UnixNano()) //nolint:gosec // G115: reinterpreted as i64 by the guest
+}
@@ -0,0 +1,31 @@
+package hostfuncs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeNow_FixedClock(t *testing.T) {
+	t.Parallel()
+
+	fixed := time.Date(2026, 1, 1, 12, 0, 0, 42, time.UTC)
+	ctx := WithClock(context.Background(), func() time.Time { return fixed })
+
+	stack := make([]uint64, 1)
+	TimeNow(ctx, nil, stack)
+
+	assert.Equal(t, fixed.UnixNano(), int64(stack[0])) //nolint:gosec // G115: test round-trip
+}
+
+func TestTimeNow_SystemClock(t *testing.T) {
+	t.Parallel()
+
+	stack := make([]uint64, 1)
+	TimeNow(WithClock(context.Background(), nil), nil, stack)
+
+	got := time.Unix(0, int64(stack[0])) //nolint:gosec // G115: test round-trip
+	assert.WithinDuration(t, time.Now(), got, time.Minute)
+}
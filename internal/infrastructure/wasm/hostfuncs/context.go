@@ -0,0 +1,36 @@
+package hostfuncs
+
+import (
+	"context"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// ExecutionContext returns the run, control and observation ctx belongs
+// to. Fields the context does not carry, e.g. outside a profile run, are
+// empty.
+func ExecutionContext(ctx context.Context) ExecutionContextWire {
+	var wire ExecutionContextWire
+	if run, ok := execution.RunRefFromContext(ctx); ok {
+		wire.RunID = run.ExecutionID
+		wire.Profile = run.Profile
+		wire.Environment = run.Environment
+	}
+	if ref, ok := execution.ObservationRefFromContext(ctx); ok {
+		wire.ControlID = ref.ControlID
+		wire.Plugin = ref.Plugin
+		wire.ObservationIndex = ref.Index
+	}
+	if key, ok := execution.IdempotencyKeyFromContext(ctx); ok {
+		wire.IdempotencyKey = key
+	}
+	return wire
+}
+
+// ContextGet implements the `context_get` host function.
+// It takes no parameters and returns a packed uint64 (ptr+len) pointing to a
+// JSON-encoded ExecutionContextWire.
+func ContextGet(ctx context.Context, mod api.Module, stack []uint64) {
+	stack[0] = hostWriteResponse(ctx, mod, ExecutionContext(ctx))
+}
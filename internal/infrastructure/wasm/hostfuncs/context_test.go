@@ -0,0 +1,40 @@
+package hostfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutionContext(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ExecutionContextWire{}, ExecutionContext(context.Background()))
+
+	ctx := execution.WithRunRef(context.Background(), execution.RunRef{ExecutionID: "run-1", Profile: "baseline", Environment: "prod"})
+	ctx = execution.WithObservationRef(ctx, execution.ObservationRef{ControlID: "ssh", Plugin: "file", Index: 2})
+	assert.Equal(t, ExecutionContextWire{
+		RunID:            "run-1",
+		Profile:          "baseline",
+		Environment:      "prod",
+		ControlID:        "ssh",
+		Plugin:           "file",
+		ObservationIndex: 2,
+	}, ExecutionContext(ctx))
+}
+
+func TestCorrelationAttrs(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, correlationAttrs(context.Background()))
+
+	ctx := execution.WithRunRef(context.Background(), execution.RunRef{ExecutionID: "run-1"})
+	ctx = execution.WithObservationRef(ctx, execution.ObservationRef{ControlID: "ssh", Index: 1})
+	attrs := correlationAttrs(ctx)
+	assert.Len(t, attrs, 3)
+	assert.Equal(t, "run-1", attrs[0].Value.String())
+	assert.Equal(t, "ssh", attrs[1].Value.String())
+	assert.Equal(t, int64(1), attrs[2].Value.Int64())
+}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/reglet-dev/reglet/internal/domain/execution"
 	"github.com/tetratelabs/wazero/api"
 )
 
@@ -75,8 +76,8 @@ func DNSLookup(ctx context.Context, mod api.Module, stack []uint64, checker *Cap
 		return
 	}
 
-	// 3. Perform DNS lookup
-	dnsResult, err := performDNSLookup(lookupCtx, request.Hostname, request.Type, request.Nameserver)
+	// 3. Perform DNS lookup, answering from the run's cache when there is one
+	dnsResult, err := cachedDNSLookup(lookupCtx, request)
 	if err != nil {
 		errMsg := fmt.Sprintf("DNS lookup failed: %v", err)
 		slog.ErrorContext(ctx, errMsg, "hostname", request.Hostname, "record_type", request.Type)
@@ -93,23 +94,64 @@ func DNSLookup(ctx context.Context, mod api.Module, stack []uint64, checker *Cap
 	})
 }
 
+// cachedDNSLookup performs the lookup through the DNS cache in ctx, if any,
+// and counts it in the run's performance report.
+func cachedDNSLookup(ctx context.Context, request DNSRequestWire) (*DNSLookupResult, error) {
+	cache, ok := DNSCacheFromContext(ctx)
+	if !ok {
+		result, err := performDNSLookup(ctx, request.Hostname, request.Type, request.Nameserver, nil)
+		if err == nil {
+			recordDNSLookup(ctx, false)
+		}
+		return result, err
+	}
+
+	key := DNSCacheKey{Hostname: request.Hostname, Type: request.Type, Nameserver: request.Nameserver}
+	result, hit, err := cache.Lookup(ctx, key, func(ctx context.Context) (*DNSLookupResult, time.Duration, error) {
+		ttl := &dnsTTL{}
+		result, err := performDNSLookup(ctx, request.Hostname, request.Type, request.Nameserver, ttl)
+		return result, ttl.get(), err
+	})
+	if err == nil {
+		recordDNSLookup(ctx, hit)
+	}
+	return result, err
+}
+
+// recordDNSLookup counts a lookup in the performance recorder in ctx, if any.
+func recordDNSLookup(ctx context.Context, cached bool) {
+	if recorder, ok := execution.PerfRecorderFromContext(ctx); ok {
+		recorder.RecordDNSLookup(cached)
+	}
+}
+
 // performDNSLookup executes the actual DNS lookup based on record type.
-func performDNSLookup(ctx context.Context, hostname string, recordType string, nameserver string) (*DNSLookupResult, error) {
-	resolver := createResolver(nameserver)
+// When ttl is set, the answer TTLs of the DNS responses are recorded into it.
+func performDNSLookup(ctx context.Context, hostname string, recordType string, nameserver string, ttl *dnsTTL) (*DNSLookupResult, error) {
+	resolver := createResolver(nameserver, ttl)
 	return lookupByType(ctx, resolver, hostname, recordType)
 }
 
 // createResolver creates a DNS resolver, optionally using a custom nameserver.
-func createResolver(nameserver string) *net.Resolver {
-	if nameserver == "" {
+// Recording TTLs needs the pure Go resolver, which reads the DNS responses
+// through the connections it dials.
+func createResolver(nameserver string, ttl *dnsTTL) *net.Resolver {
+	if nameserver == "" && ttl == nil {
 		return net.DefaultResolver
 	}
 
 	return &net.Resolver{
 		PreferGo: true,
-		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			if nameserver != "" {
+				network, address = "udp", nameserver
+			}
 			d := net.Dialer{Timeout: 5 * time.Second}
-			return d.DialContext(ctx, "udp", nameserver)
+			conn, err := d.DialContext(ctx, network, address)
+			if err != nil || ttl == nil {
+				return conn, err
+			}
+			return ttl.wrap(conn), nil
 		},
 	}
 }
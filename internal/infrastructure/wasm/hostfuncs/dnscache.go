@@ -0,0 +1,207 @@
+package hostfuncs
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultDNSCacheMaxTTL caps how long a DNS answer is reused within a run.
+	DefaultDNSCacheMaxTTL = 5 * time.Minute
+
+	// defaultDNSTTL is how long answers without a known TTL, such as names
+	// from /etc/hosts, are cached.
+	defaultDNSTTL = 30 * time.Second
+)
+
+// DNSCacheKey identifies a DNS lookup: the same name, record type and
+// nameserver give the same answer.
+type DNSCacheKey struct {
+	Hostname   string
+	Type       string
+	Nameserver string
+}
+
+// DNSCache shares DNS answers between the dns_lookup calls of a run.
+// Implementations must be safe for concurrent use.
+type DNSCache interface {
+	// Lookup returns the cached answer for key, or calls resolve and caches
+	// its answer for the TTL it returns. hit reports a cached answer.
+	Lookup(ctx context.Context, key DNSCacheKey, resolve DNSResolveFunc) (result *DNSLookupResult, hit bool, err error)
+}
+
+// DNSResolveFunc resolves a lookup that is not cached and returns its answer
+// with the time it may be reused for.
+type DNSResolveFunc func(ctx context.Context) (*DNSLookupResult, time.Duration, error)
+
+// TTLDNSCache is the default DNSCache. It keeps answers for the smallest TTL
+// of their records, at most maxTTL, and does not cache failures. Concurrent
+// lookups of the same key wait for a single query.
+type TTLDNSCache struct {
+	maxTTL time.Duration
+	now    func() time.Time
+
+	mu       sync.Mutex
+	entries  map[DNSCacheKey]dnsCacheEntry
+	inflight singleflight.Group
+}
+
+type dnsCacheEntry struct {
+	result  *DNSLookupResult
+	expires time.Time
+}
+
+// NewDNSCache creates an empty cache keeping answers for at most maxTTL.
+func NewDNSCache(maxTTL time.Duration) *TTLDNSCache {
+	return &TTLDNSCache{
+		maxTTL:  maxTTL,
+		now:     time.Now,
+		entries: make(map[DNSCacheKey]dnsCacheEntry),
+	}
+}
+
+// Lookup implements DNSCache.
+func (c *TTLDNSCache) Lookup(ctx context.Context, key DNSCacheKey, resolve DNSResolveFunc) (*DNSLookupResult, bool, error) {
+	key.Hostname = strings.ToLower(strings.TrimSuffix(key.Hostname, "."))
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		return entry.result, true, nil
+	}
+
+	resolved := false
+	v, err, _ := c.inflight.Do(key.Type+"\x00"+key.Nameserver+"\x00"+key.Hostname, func() (any, error) {
+		resolved = true
+		result, ttl, err := resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ttl = min(ttl, c.maxTTL); ttl > 0 {
+			c.mu.Lock()
+			c.entries[key] = dnsCacheEntry{result: result, expires: c.now().Add(ttl)}
+			c.mu.Unlock()
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return v.(*DNSLookupResult), !resolved, nil //nolint:forcetypeassert // only *DNSLookupResult is stored
+}
+
+var dnsCacheKey = &contextKey{name: "dns_cache"}
+
+// WithDNSCache attaches a DNS cache to the context. dns_lookup calls made
+// with the context answer from it.
+func WithDNSCache(ctx context.Context, cache DNSCache) context.Context {
+	if cache == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, dnsCacheKey, cache)
+}
+
+// DNSCacheFromContext returns the DNS cache attached to the context, if any.
+func DNSCacheFromContext(ctx context.Context) (DNSCache, bool) {
+	cache, ok := ctx.Value(dnsCacheKey).(DNSCache)
+	return cache, ok
+}
+
+// dnsTTL records the smallest answer TTL of the DNS responses of a lookup.
+// It is safe for concurrent use: lookups of A and AAAA records query in
+// parallel.
+type dnsTTL struct {
+	mu   sync.Mutex
+	min  uint32
+	seen bool
+}
+
+// get returns the smallest TTL seen, or defaultDNSTTL when no response
+// carried one.
+func (t *dnsTTL) get() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.seen {
+		return defaultDNSTTL
+	}
+	return time.Duration(t.min) * time.Second
+}
+
+// observe records the answer TTLs of a DNS response message.
+func (t *dnsTTL) observe(msg []byte) {
+	var p dnsmessage.Parser
+	if _, err := p.Start(msg); err != nil {
+		return
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return
+	}
+	for {
+		header, err := p.AnswerHeader()
+		if err != nil {
+			return
+		}
+		t.mu.Lock()
+		if !t.seen || header.TTL < t.min {
+			t.min, t.seen = header.TTL, true
+		}
+		t.mu.Unlock()
+		if err := p.SkipAnswer(); err != nil {
+			return
+		}
+	}
+}
+
+// wrap returns conn with the responses read through it observed. UDP
+// connections stay packet connections, which the Go resolver relies on to
+// tell datagram from stream framing.
+func (t *dnsTTL) wrap(conn net.Conn) net.Conn {
+	if udp, ok := conn.(*net.UDPConn); ok {
+		return &ttlPacketConn{UDPConn: udp, ttl: t}
+	}
+	return &ttlStreamConn{Conn: conn, ttl: t}
+}
+
+// ttlPacketConn observes each datagram read, a whole DNS message.
+type ttlPacketConn struct {
+	*net.UDPConn
+	ttl *dnsTTL
+}
+
+func (c *ttlPacketConn) Read(p []byte) (int, error) {
+	n, err := c.UDPConn.Read(p)
+	if n > 0 {
+		c.ttl.observe(p[:n])
+	}
+	return n, err
+}
+
+// ttlStreamConn reassembles the length-prefixed DNS messages of a TCP
+// connection and observes each.
+type ttlStreamConn struct {
+	net.Conn
+	ttl *dnsTTL
+	buf []byte
+}
+
+func (c *ttlStreamConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.buf = append(c.buf, p[:n]...)
+	for len(c.buf) >= 2 {
+		size := int(binary.BigEndian.Uint16(c.buf))
+		if len(c.buf) < 2+size {
+			break
+		}
+		c.ttl.observe(c.buf[2 : 2+size])
+		c.buf = c.buf[2+size:]
+	}
+	return n, err
+}
@@ -0,0 +1,205 @@
+package hostfuncs
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestTTLDNSCache(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewDNSCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	var queries int
+	resolve := func(ttl time.Duration) DNSResolveFunc {
+		return func(context.Context) (*DNSLookupResult, time.Duration, error) {
+			queries++
+			return &DNSLookupResult{Records: []string{"192.0.2.1"}}, ttl, nil
+		}
+	}
+	key := DNSCacheKey{Hostname: "Example.com.", Type: "A"}
+
+	result, hit, err := cache.Lookup(context.Background(), key, resolve(30*time.Second))
+	require.NoError(t, err)
+	assert.False(t, hit)
+	assert.Equal(t, []string{"192.0.2.1"}, result.Records)
+
+	// Names are case-insensitive and the trailing dot is optional
+	_, hit, err = cache.Lookup(context.Background(), DNSCacheKey{Hostname: "example.com", Type: "A"}, resolve(30*time.Second))
+	require.NoError(t, err)
+	assert.True(t, hit)
+	assert.Equal(t, 1, queries)
+
+	// Other record types and nameservers are separate entries
+	_, hit, _ = cache.Lookup(context.Background(), DNSCacheKey{Hostname: "example.com", Type: "AAAA"}, resolve(30*time.Second))
+	assert.False(t, hit)
+	_, hit, _ = cache.Lookup(context.Background(), DNSCacheKey{Hostname: "example.com", Type: "A", Nameserver: "192.0.2.53:53"}, resolve(30*time.Second))
+	assert.False(t, hit)
+	assert.Equal(t, 3, queries)
+
+	// Answers expire with their TTL
+	now = now.Add(31 * time.Second)
+	_, hit, _ = cache.Lookup(context.Background(), key, resolve(time.Hour))
+	assert.False(t, hit)
+	assert.Equal(t, 4, queries)
+
+	// ... which is capped at the cache's maximum
+	now = now.Add(61 * time.Second)
+	_, hit, _ = cache.Lookup(context.Background(), key, resolve(0))
+	assert.False(t, hit)
+
+	// A zero TTL is not cached
+	_, hit, _ = cache.Lookup(context.Background(), key, resolve(0))
+	assert.False(t, hit)
+	assert.Equal(t, 6, queries)
+}
+
+func TestTTLDNSCache_DoesNotCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	cache := NewDNSCache(time.Minute)
+	key := DNSCacheKey{Hostname: "missing.example", Type: "A"}
+	_, _, err := cache.Lookup(context.Background(), key, func(context.Context) (*DNSLookupResult, time.Duration, error) {
+		return nil, 0, errors.New("no such host")
+	})
+	require.Error(t, err)
+
+	_, hit, err := cache.Lookup(context.Background(), key, func(context.Context) (*DNSLookupResult, time.Duration, error) {
+		return &DNSLookupResult{}, time.Minute, nil
+	})
+	require.NoError(t, err)
+	assert.False(t, hit)
+}
+
+func TestTTLDNSCache_CoalescesConcurrentLookups(t *testing.T) {
+	t.Parallel()
+
+	cache := NewDNSCache(time.Minute)
+	release := make(chan struct{})
+	var queries atomic.Int32
+	resolve := func(context.Context) (*DNSLookupResult, time.Duration, error) {
+		queries.Add(1)
+		<-release
+		return &DNSLookupResult{Records: []string{"192.0.2.1"}}, time.Minute, nil
+	}
+
+	var wg sync.WaitGroup
+	var hits atomic.Int32
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, hit, err := cache.Lookup(context.Background(), DNSCacheKey{Hostname: "example.com", Type: "A"}, resolve)
+			assert.NoError(t, err)
+			if hit {
+				hits.Add(1)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), queries.Load())
+	assert.Equal(t, int32(4), hits.Load())
+}
+
+// dnsResponse builds a DNS response with A answers of the given TTLs.
+func dnsResponse(t *testing.T, ttls ...uint32) []byte {
+	t.Helper()
+	name := dnsmessage.MustNewName("example.com.")
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{Response: true})
+	require.NoError(t, builder.StartQuestions())
+	require.NoError(t, builder.Question(dnsmessage.Question{Name: name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}))
+	require.NoError(t, builder.StartAnswers())
+	for _, ttl := range ttls {
+		header := dnsmessage.ResourceHeader{Name: name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: ttl}
+		require.NoError(t, builder.AResource(header, dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}}))
+	}
+	msg, err := builder.Finish()
+	require.NoError(t, err)
+	return msg
+}
+
+func TestDNSTTL(t *testing.T) {
+	t.Parallel()
+
+	ttl := &dnsTTL{}
+	assert.Equal(t, defaultDNSTTL, ttl.get(), "no response seen")
+
+	ttl.observe(dnsResponse(t))
+	assert.Equal(t, defaultDNSTTL, ttl.get(), "no answers")
+	ttl.observe([]byte("garbage"))
+
+	ttl.observe(dnsResponse(t, 300, 60))
+	ttl.observe(dnsResponse(t, 120))
+	assert.Equal(t, time.Minute, ttl.get())
+}
+
+func TestDNSTTL_StreamConn(t *testing.T) {
+	t.Parallel()
+
+	server, client := net.Pipe()
+	t.Cleanup(func() { _ = server.Close() })
+	ttl := &dnsTTL{}
+	conn := ttl.wrap(client)
+	_, isPacket := conn.(net.PacketConn)
+	assert.False(t, isPacket)
+
+	msg := dnsResponse(t, 42)
+	framed := binary.BigEndian.AppendUint16(nil, uint16(len(msg))) //nolint:gosec // G115: test message is small
+	framed = append(framed, msg...)
+	go func() {
+		// Split the message across writes, as TCP may
+		_, _ = server.Write(framed[:5])
+		_, _ = server.Write(framed[5:])
+	}()
+
+	buf := make([]byte, len(framed))
+	read := 0
+	for read < len(framed) {
+		n, err := conn.Read(buf[read:])
+		require.NoError(t, err)
+		read += n
+	}
+	assert.Equal(t, 42*time.Second, ttl.get())
+}
+
+func TestDNSTTL_PacketConn(t *testing.T) {
+	t.Parallel()
+
+	udp, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = udp.Close() })
+	conn, err := net.DialUDP("udp", nil, udp.LocalAddr().(*net.UDPAddr))
+	require.NoError(t, err)
+
+	ttl := &dnsTTL{}
+	wrapped := ttl.wrap(conn)
+	t.Cleanup(func() { _ = wrapped.Close() })
+	_, isPacket := wrapped.(net.PacketConn)
+	require.True(t, isPacket, "the Go resolver needs a PacketConn to use datagram framing")
+
+	_, err = wrapped.Write([]byte("query"))
+	require.NoError(t, err)
+	_, from, err := udp.ReadFrom(make([]byte, 512))
+	require.NoError(t, err)
+	_, err = udp.WriteTo(dnsResponse(t, 7), from)
+	require.NoError(t, err)
+
+	_, err = wrapped.Read(make([]byte, 512))
+	require.NoError(t, err)
+	assert.Equal(t, 7*time.Second, ttl.get())
+}
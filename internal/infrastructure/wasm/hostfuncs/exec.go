@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/reglet-dev/reglet/wireformat"
 	"github.com/tetratelabs/wazero/api"
 )
 
@@ -25,6 +26,13 @@ func ExecCommand(ctx context.Context, mod api.Module, stack []uint64, checker *C
 		return
 	}
 
+	if err := wireformat.ValidateEncoding(request.Encoding); err != nil {
+		stack[0] = hostWriteResponse(ctx, mod, ExecResponseWire{
+			Error: &ErrorDetail{Message: err.Error(), Type: "validation"},
+		})
+		return
+	}
+
 	// Create context
 	execCtx, cancel := createContextFromWire(ctx, request.Context)
 	defer cancel()
@@ -169,7 +177,7 @@ func executeCommand(ctx, execCtx context.Context, request *ExecRequestWire) Exec
 	err := cmd.Run()
 	duration := time.Since(start)
 
-	response := buildExecResponse(execCtx, err, stdout, stderr, duration)
+	response := buildExecResponse(execCtx, err, stdout, stderr, duration, request.Encoding)
 
 	if stdout.Truncated || stderr.Truncated {
 		slog.WarnContext(ctx, "command output truncated",
@@ -188,14 +196,15 @@ func executeCommand(ctx, execCtx context.Context, request *ExecRequestWire) Exec
 	return response
 }
 
-// buildExecResponse constructs the response from command execution results.
-func buildExecResponse(execCtx context.Context, err error, stdout, stderr *BoundedBuffer, duration time.Duration) ExecResponseWire {
+// buildExecResponse constructs the response from command execution results,
+// decoding the output in the requested encoding.
+func buildExecResponse(execCtx context.Context, err error, stdout, stderr *BoundedBuffer, duration time.Duration, encoding string) ExecResponseWire {
 	response := ExecResponseWire{
-		Stdout:     stdout.String(),
-		Stderr:     stderr.String(),
 		ExitCode:   0,
 		DurationMs: duration.Milliseconds(),
 	}
+	response.Stdout, response.StdoutCharset = wireformat.DecodeText(stdout.Bytes(), encoding)
+	response.Stderr, response.StderrCharset = wireformat.DecodeText(stderr.Bytes(), encoding)
 
 	if err == nil {
 		return response
@@ -254,16 +263,22 @@ func (b *BoundedBuffer) Write(p []byte) (n int, err error) {
 	return b.buffer.Write(p)
 }
 
+// Bytes returns the buffer contents.
+func (b *BoundedBuffer) Bytes() []byte {
+	return b.buffer.Bytes()
+}
+
 // String returns the buffer contents as a string.
 func (b *BoundedBuffer) String() string {
 	return b.buffer.String()
 }
 
 // isShellExecution detects if a command is a shell invocation.
-// Common shells: sh, bash, dash, zsh, ksh, csh, tcsh, fish
+// Common shells: sh, bash, dash, zsh, ksh, csh, tcsh, fish, and cmd,
+// powershell and pwsh on Windows
 func isShellExecution(command string) bool {
 	base := getBasename(command)
-	shells := []string{"sh", "bash", "dash", "zsh", "ksh", "csh", "tcsh", "fish"}
+	shells := []string{"sh", "bash", "dash", "zsh", "ksh", "csh", "tcsh", "fish", "cmd", "powershell", "pwsh"}
 	for _, shell := range shells {
 		if base == shell {
 			return true
@@ -272,10 +287,21 @@ func isShellExecution(command string) bool {
 	return false
 }
 
-// getBasename extracts the binary name from a path.
+// windowsExecutableExts are the executable extensions dropped from Windows
+// command names, so C:\Windows\System32\cmd.exe is recognized as "cmd".
+var windowsExecutableExts = []string{".exe", ".com", ".bat", ".cmd"}
+
+// getBasename extracts the binary name from a Unix or Windows path. Windows
+// executable names are lowercased and lose their extension.
 func getBasename(command string) string {
-	if idx := strings.LastIndex(command, "/"); idx >= 0 {
-		return command[idx+1:]
+	if idx := strings.LastIndexAny(command, `/\`); idx >= 0 {
+		command = command[idx+1:]
+	}
+	lower := strings.ToLower(command)
+	for _, ext := range windowsExecutableExts {
+		if strings.HasSuffix(lower, ext) {
+			return strings.TrimSuffix(lower, ext)
+		}
 	}
 	return command
 }
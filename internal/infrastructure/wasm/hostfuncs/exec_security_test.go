@@ -18,6 +18,8 @@ func TestGetBasename(t *testing.T) {
 		{"relative path", "./scripts/python", "python"},
 		{"versioned", "/usr/bin/python3.11", "python3.11"},
 		{"nested path", "/usr/local/bin/custom/ruby", "ruby"},
+		{"windows path", `C:\Python312\python.exe`, "python"},
+		{"windows batch file", `C:\Tools\Deploy.CMD`, "deploy"},
 	}
 
 	for _, tt := range tests {
@@ -1,9 +1,12 @@
 package hostfuncs
 
 import (
+	"context"
+	"os/exec"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_isShellExecution(t *testing.T) {
@@ -27,6 +30,11 @@ func Test_isShellExecution(t *testing.T) {
 		{"bash bare", "bash", true},
 		{"zsh bare", "zsh", true},
 
+		// Windows shells
+		{"cmd with path", `C:\Windows\System32\cmd.exe`, true},
+		{"powershell with path", `C:\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`, true},
+		{"pwsh bare", "pwsh", true},
+
 		// Non-shell commands
 		{"systemctl", "/usr/bin/systemctl", false},
 		{"echo", "/bin/echo", false},
@@ -102,6 +110,42 @@ func TestEnvironmentVariableIsolation(t *testing.T) {
 	}
 }
 
+func TestExecuteCommand_Encoding(t *testing.T) {
+	printf, err := exec.LookPath("printf")
+	if err != nil {
+		t.Skip("printf not available")
+	}
+
+	tests := []struct {
+		encoding    string
+		wantStdout  string
+		wantCharset string
+	}{
+		{"", "caf\uFFFD", "utf-8"},
+		{"utf-8", "caf\uFFFD", "utf-8"},
+		{"latin-1", "café", "latin-1"},
+		{"auto", "café", "latin-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			ctx := context.Background()
+			response := executeCommand(ctx, ctx, &ExecRequestWire{
+				Command:  printf,
+				Args:     []string{`caf\351`}, // é in Latin-1
+				Encoding: tt.encoding,
+			})
+			require.Nil(t, response.Error)
+			assert.Equal(t, tt.wantStdout, response.Stdout)
+			assert.Equal(t, tt.wantCharset, response.StdoutCharset)
+		})
+	}
+
+	ctx := context.Background()
+	response := executeCommand(ctx, ctx, &ExecRequestWire{Command: printf, Args: []string{"café"}, Encoding: "auto"})
+	assert.Equal(t, "café", response.Stdout, "valid UTF-8 is kept")
+	assert.Equal(t, "utf-8", response.StdoutCharset)
+}
+
 // Note: Full integration tests for ExecCommand would require:
 // - Creating a WASM module with exec capabilities
 // - Setting up CapabilityChecker with test grants
@@ -0,0 +1,173 @@
+package hostfuncs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Fault types accepted by --inject-fault.
+const (
+	FaultTimeout  = "timeout"   // the call times out
+	FaultRefused  = "refused"   // the connection is refused
+	FaultNotFound = "not_found" // the DNS name does not resolve
+	FaultError    = "error"     // the host reports an internal error
+)
+
+var faultTypes = []string{FaultTimeout, FaultRefused, FaultNotFound, FaultError}
+
+// Fault makes matching host function calls fail at a given rate.
+type Fault struct {
+	Plugin   string        // plugin name ("" or "*" = all plugins)
+	Function string        // host function, e.g. http_request ("" = all)
+	Type     string        // one of the Fault* types
+	Rate     float64       // probability in [0, 1]
+	Delay    time.Duration // wait before failing, e.g. to exercise timeouts
+}
+
+// ParseFault parses a fault spec such as "plugin=http,rate=0.3,type=timeout".
+// Keys are plugin, function, type (default error), rate (default 1) and delay.
+func ParseFault(spec string) (Fault, error) {
+	fault := Fault{Type: FaultError, Rate: 1}
+	for _, part := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Fault{}, fmt.Errorf("invalid fault %q: expected key=value, got %q", spec, part)
+		}
+		switch key {
+		case "plugin":
+			fault.Plugin = value
+		case "function":
+			fault.Function = value
+		case "type":
+			if !slices.Contains(faultTypes, value) {
+				return Fault{}, fmt.Errorf("invalid fault %q: type must be one of %s", spec, strings.Join(faultTypes, ", "))
+			}
+			fault.Type = value
+		case "rate":
+			rate, err := strconv.ParseFloat(value, 64)
+			if err != nil || rate < 0 || rate > 1 {
+				return Fault{}, fmt.Errorf("invalid fault %q: rate must be between 0 and 1", spec)
+			}
+			fault.Rate = rate
+		case "delay":
+			delay, err := time.ParseDuration(value)
+			if err != nil || delay < 0 {
+				return Fault{}, fmt.Errorf("invalid fault %q: delay must be a duration such as 2s", spec)
+			}
+			fault.Delay = delay
+		default:
+			return Fault{}, fmt.Errorf("invalid fault %q: unknown key %q", spec, key)
+		}
+	}
+	return fault, nil
+}
+
+// matches reports whether the fault applies to a call.
+func (f Fault) matches(plugin, function string) bool {
+	return (f.Plugin == "" || f.Plugin == "*" || f.Plugin == plugin) &&
+		(f.Function == "" || f.Function == function)
+}
+
+// errorDetail returns the error a faulted call reports to the plugin.
+func (f Fault) errorDetail(function string) *ErrorDetail {
+	message := fmt.Sprintf("injected fault: %s in %s", f.Type, function)
+	switch f.Type {
+	case FaultTimeout:
+		return &ErrorDetail{Message: message, Type: "timeout", Code: "ETIMEDOUT", IsTimeout: true}
+	case FaultRefused:
+		return &ErrorDetail{Message: message, Type: "network", Code: "ECONNREFUSED"}
+	case FaultNotFound:
+		return &ErrorDetail{Message: message, Type: "network", Code: "NXDOMAIN", IsNotFound: true}
+	default:
+		return &ErrorDetail{Message: message, Type: "internal"}
+	}
+}
+
+// FaultInjector makes host function calls fail according to a set of faults,
+// to test how profiles behave under partial outages. The first matching
+// fault decides each call.
+type FaultInjector struct {
+	rng    *rand.Rand
+	faults []Fault
+	mu     sync.Mutex
+}
+
+// NewFaultInjector creates an injector. A seed makes the sequence of
+// failures reproducible; 0 picks a random seed.
+func NewFaultInjector(faults []Fault, seed uint64) *FaultInjector {
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return &FaultInjector{
+		faults: faults,
+		rng:    rand.New(rand.NewPCG(seed, seed)), //nolint:gosec // G404: fault sampling is not security sensitive
+	}
+}
+
+// fault returns the fault to inject into a call, if any.
+func (i *FaultInjector) fault(plugin, function string) (Fault, bool) {
+	for _, f := range i.faults {
+		if !f.matches(plugin, function) {
+			continue
+		}
+		i.mu.Lock()
+		hit := i.rng.Float64() < f.Rate
+		i.mu.Unlock()
+		return f, hit
+	}
+	return Fault{}, false
+}
+
+var faultInjectorKey = &contextKey{name: "fault_injector"}
+
+// WithFaultInjector attaches a fault injector to the context. Host function
+// calls made with the context may fail according to its faults.
+func WithFaultInjector(ctx context.Context, injector *FaultInjector) context.Context {
+	if injector == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, faultInjectorKey, injector)
+}
+
+// FaultInjectorFromContext returns the fault injector attached to the context, if any.
+func FaultInjectorFromContext(ctx context.Context) (*FaultInjector, bool) {
+	injector, ok := ctx.Value(faultInjectorKey).(*FaultInjector)
+	return injector, ok
+}
+
+// faultable wraps a request/response host function so calls can fail with an
+// injected fault instead of running. Without an injector in ctx the function
+// runs unchanged.
+func faultable(function string, fn api.GoModuleFunc) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		injector, ok := FaultInjectorFromContext(ctx)
+		if !ok {
+			fn(ctx, mod, stack)
+			return
+		}
+		plugin := getPluginName(ctx, mod)
+		fault, hit := injector.fault(plugin, function)
+		if !hit {
+			fn(ctx, mod, stack)
+			return
+		}
+
+		slog.WarnContext(ctx, "injecting fault", "plugin", plugin, "function", function, "type", fault.Type)
+		if fault.Delay > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(fault.Delay):
+			}
+		}
+		stack[0] = hostWriteResponse(ctx, mod, ErrorResponseWire{Error: fault.errorDetail(function)})
+	}
+}
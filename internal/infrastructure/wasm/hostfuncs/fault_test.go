@@ -0,0 +1,94 @@
+package hostfuncs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero/api"
+)
+
+func TestParseFault(t *testing.T) {
+	t.Parallel()
+
+	fault, err := ParseFault("plugin=http,rate=0.3,type=timeout")
+	require.NoError(t, err)
+	assert.Equal(t, Fault{Plugin: "http", Rate: 0.3, Type: FaultTimeout}, fault)
+
+	fault, err = ParseFault("function=dns_lookup, delay=2s")
+	require.NoError(t, err)
+	assert.Equal(t, Fault{Function: "dns_lookup", Rate: 1, Type: FaultError, Delay: 2 * time.Second}, fault)
+
+	for _, spec := range []string{
+		"plugin",
+		"rate=1.5",
+		"rate=abc",
+		"type=explode",
+		"delay=-1s",
+		"color=red",
+	} {
+		_, err := ParseFault(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestFaultInjector_Matching(t *testing.T) {
+	t.Parallel()
+
+	injector := NewFaultInjector([]Fault{
+		{Plugin: "http", Rate: 0, Type: FaultTimeout},
+		{Function: "dns_lookup", Rate: 1, Type: FaultNotFound},
+	}, 1)
+
+	_, hit := injector.fault("http", "dns_lookup")
+	assert.False(t, hit, "the first matching fault decides")
+
+	fault, hit := injector.fault("dns", "dns_lookup")
+	assert.True(t, hit)
+	assert.Equal(t, FaultNotFound, fault.Type)
+
+	_, hit = injector.fault("tcp", "tcp_connect")
+	assert.False(t, hit)
+}
+
+func TestFaultInjector_SeedIsReproducible(t *testing.T) {
+	t.Parallel()
+
+	sample := func() []bool {
+		injector := NewFaultInjector([]Fault{{Rate: 0.5, Type: FaultError}}, 42)
+		hits := make([]bool, 50)
+		for i := range hits {
+			_, hits[i] = injector.fault("http", "http_request")
+		}
+		return hits
+	}
+
+	first := sample()
+	assert.Equal(t, first, sample())
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+}
+
+func TestFaultable_WritesInjectedError(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	lookup := faultable("dns_lookup", func(context.Context, api.Module, []uint64) {
+		called = true
+	})
+
+	injector := NewFaultInjector([]Fault{{Plugin: "dns", Rate: 1, Type: FaultTimeout}}, 1)
+	ctx := WithPluginName(WithFaultInjector(context.Background(), injector), "dns")
+	response := newFakeModule().call(t, ctx, lookup, dnsRequest(t, "example.com", 100))
+
+	assert.False(t, called)
+	var resp DNSResponseWire
+	require.NoError(t, json.Unmarshal(response, &resp))
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, "timeout", resp.Error.Type)
+	assert.True(t, resp.Error.IsTimeout)
+	assert.Contains(t, resp.Error.Message, "injected fault")
+}
@@ -0,0 +1,73 @@
+package hostfuncs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/reglet-dev/reglet/wireformat"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// maxFileReadBytes is the most file_read_range returns per call, since the
+// data is copied into guest memory.
+const maxFileReadBytes = 16 << 20
+
+// FileReadRange reads part of a host file on behalf of the plugin, so
+// sampling a large file never loads the rest of it into guest memory.
+// It receives a packed uint64 (ptr+len) pointing to a JSON-encoded FileReadRangeRequestWire.
+// It returns a packed uint64 (ptr+len) pointing to a framed FileReadRangeResponseWire
+// followed by the data, or to a plain JSON FileReadRangeResponseWire on error.
+func FileReadRange(ctx context.Context, mod api.Module, stack []uint64, checker *CapabilityChecker) {
+	var request FileReadRangeRequestWire
+	if detail := readGuestJSON(ctx, mod, stack[0], "file read", &request); detail != nil {
+		stack[0] = hostWriteResponse(ctx, mod, FileReadRangeResponseWire{Error: detail})
+		return
+	}
+
+	readCtx, cancel := createContextFromWire(ctx, request.Context)
+	defer cancel()
+
+	if detail := checkFileRead(ctx, checker, getPluginName(ctx, mod), request.Path); detail != nil {
+		stack[0] = hostWriteResponse(ctx, mod, FileReadRangeResponseWire{Error: detail})
+		return
+	}
+
+	response, data := readFileRange(readCtx, request.Path, request.Offset, request.Length)
+	if response.Error != nil {
+		stack[0] = hostWriteResponse(ctx, mod, response)
+		return
+	}
+	stack[0] = hostWriteFramed(ctx, mod, response, wireformat.Frame{ContentType: wireformat.ContentTypeOctetStream, Data: data})
+}
+
+// readFileRange reads length bytes at offset of the regular file at path.
+func readFileRange(ctx context.Context, path string, offset, length int64) (FileReadRangeResponseWire, []byte) {
+	if offset < 0 || length <= 0 || length > maxFileReadBytes {
+		return FileReadRangeResponseWire{Error: &ErrorDetail{
+			Message: fmt.Sprintf("invalid range: offset must be at least 0 and length between 1 and %d, got offset %d and length %d", maxFileReadBytes, offset, length),
+			Type:    "validation",
+		}}, nil
+	}
+
+	f, err := os.Open(path) //nolint:gosec // G304: path is checked against the plugin's fs capabilities
+	if err != nil {
+		return FileReadRangeResponseWire{Error: toErrorDetail(err)}, nil
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return FileReadRangeResponseWire{Error: toErrorDetail(err)}, nil
+	}
+	if !info.Mode().IsRegular() {
+		return FileReadRangeResponseWire{Error: &ErrorDetail{Message: fmt.Sprintf("%s is not a regular file", path), Type: "validation"}}, nil
+	}
+
+	data, err := io.ReadAll(&contextReader{ctx: ctx, r: io.NewSectionReader(f, offset, length)})
+	if err != nil {
+		return FileReadRangeResponseWire{Error: toErrorDetail(err)}, nil
+	}
+	return FileReadRangeResponseWire{Length: int64(len(data)), Size: info.Size()}, data
+}
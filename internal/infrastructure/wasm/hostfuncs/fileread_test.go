@@ -0,0 +1,88 @@
+package hostfuncs
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/wireformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero/api"
+)
+
+func TestReadFileRange(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "data")
+	require.NoError(t, os.WriteFile(path, []byte("hello world"), 0o600))
+
+	res, data := readFileRange(context.Background(), path, 6, 3)
+	assert.Equal(t, FileReadRangeResponseWire{Length: 3, Size: 11}, res)
+	assert.Equal(t, "wor", string(data))
+
+	res, data = readFileRange(context.Background(), path, 6, 100)
+	assert.Equal(t, FileReadRangeResponseWire{Length: 5, Size: 11}, res, "stops at the end of the file")
+	assert.Equal(t, "world", string(data))
+
+	res, _ = readFileRange(context.Background(), path, 20, 5)
+	assert.Equal(t, FileReadRangeResponseWire{Size: 11}, res)
+}
+
+func TestReadFileRange_Errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0o600))
+
+	for _, r := range [][2]int64{{-1, 5}, {0, 0}, {0, maxFileReadBytes + 1}} {
+		res, _ := readFileRange(context.Background(), path, r[0], r[1])
+		require.NotNil(t, res.Error, r)
+		assert.Equal(t, "validation", res.Error.Type)
+	}
+
+	res, _ := readFileRange(context.Background(), dir, 0, 5)
+	require.NotNil(t, res.Error)
+	assert.Contains(t, res.Error.Message, "not a regular file")
+
+	res, _ = readFileRange(context.Background(), filepath.Join(dir, "missing"), 0, 5)
+	assert.NotNil(t, res.Error)
+}
+
+func TestFileReadRange_FramedResponse(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data")
+	require.NoError(t, os.WriteFile(path, []byte("hello world"), 0o600))
+
+	checker := NewCapabilityChecker(map[string][]capabilities.Capability{
+		"file": {{Kind: "fs", Pattern: "read:" + dir + "/**"}},
+	})
+	read := func(ctx context.Context, mod api.Module, stack []uint64) {
+		FileReadRange(ctx, mod, stack, checker)
+	}
+	ctx := WithPluginName(context.Background(), "file")
+	mod := newFakeModule()
+
+	request, err := json.Marshal(FileReadRangeRequestWire{Path: path, Offset: 0, Length: 5})
+	require.NoError(t, err)
+	frames, err := wireformat.DecodeFrames(mod.call(t, ctx, read, request))
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+	var res FileReadRangeResponseWire
+	require.NoError(t, json.Unmarshal(frames[0].Data, &res))
+	assert.Equal(t, FileReadRangeResponseWire{Length: 5, Size: 11}, res)
+	assert.Equal(t, "hello", string(frames[1].Data))
+
+	request, err = json.Marshal(FileReadRangeRequestWire{Path: "/etc/passwd", Length: 5})
+	require.NoError(t, err)
+	res = FileReadRangeResponseWire{}
+	require.NoError(t, json.Unmarshal(mod.call(t, ctx, read, request), &res))
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "capability", res.Error.Type)
+}
@@ -0,0 +1,129 @@
+package hostfuncs
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/tetratelabs/wazero/api"
+	"github.com/zeebo/blake3"
+)
+
+// hashAlgorithms are the digests hash_compute supports, by wire name.
+var hashAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+	"blake3": func() hash.Hash { return blake3.New() },
+}
+
+// HashCompute hashes a host file on behalf of the plugin, so large files
+// never pass through guest memory.
+// It receives a packed uint64 (ptr+len) pointing to a JSON-encoded HashRequestWire.
+// It returns a packed uint64 (ptr+len) pointing to a JSON-encoded HashResponseWire.
+func HashCompute(ctx context.Context, mod api.Module, stack []uint64, checker *CapabilityChecker) {
+	ptr, length := unpackPtrLen(stack[0])
+	requestBytes, ok := mod.Memory().Read(ptr, length)
+	if !ok {
+		errMsg := "hostfuncs: failed to read hash request from Guest memory"
+		slog.ErrorContext(ctx, errMsg)
+		stack[0] = hostWriteResponse(ctx, mod, HashResponseWire{
+			Error: &ErrorDetail{Message: errMsg, Type: "internal"},
+		})
+		return
+	}
+
+	var request HashRequestWire
+	if err := json.Unmarshal(requestBytes, &request); err != nil {
+		errMsg := fmt.Sprintf("hostfuncs: failed to unmarshal hash request: %v", err)
+		slog.ErrorContext(ctx, errMsg)
+		stack[0] = hostWriteResponse(ctx, mod, HashResponseWire{
+			Error: &ErrorDetail{Message: errMsg, Type: "internal"},
+		})
+		return
+	}
+
+	hashCtx, cancel := createContextFromWire(ctx, request.Context)
+	defer cancel()
+
+	if detail := checkFileRead(ctx, checker, getPluginName(ctx, mod), request.Path); detail != nil {
+		stack[0] = hostWriteResponse(ctx, mod, HashResponseWire{Error: detail})
+		return
+	}
+
+	stack[0] = hostWriteResponse(ctx, mod, hashFile(hashCtx, request.Path, request.Algorithm))
+}
+
+// checkFileRead verifies the plugin may read the host file at path, which
+// must be absolute. It returns the error to send back when it may not.
+func checkFileRead(ctx context.Context, checker *CapabilityChecker, pluginName, path string) *ErrorDetail {
+	if !filepath.IsAbs(path) {
+		return &ErrorDetail{Message: fmt.Sprintf("path must be absolute, got %q", path), Type: "validation"}
+	}
+	if err := checker.CheckFile(ctx, pluginName, "read", path); err != nil {
+		errMsg := fmt.Sprintf("permission denied: %v", err)
+		slog.WarnContext(ctx, errMsg, "path", path)
+		return &ErrorDetail{Message: errMsg, Type: "capability"}
+	}
+	return nil
+}
+
+// hashFile streams the regular file at path through the algorithm's digest.
+func hashFile(ctx context.Context, path, algorithm string) HashResponseWire {
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	newHash, ok := hashAlgorithms[algorithm]
+	if !ok {
+		return HashResponseWire{Error: &ErrorDetail{
+			Message: fmt.Sprintf("unsupported hash algorithm %q (want sha256, sha512 or blake3)", algorithm),
+			Type:    "validation",
+		}}
+	}
+
+	f, err := os.Open(path) //nolint:gosec // G304: path is checked against the plugin's fs capabilities
+	if err != nil {
+		return HashResponseWire{Error: toErrorDetail(err)}
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return HashResponseWire{Error: toErrorDetail(err)}
+	}
+	if !info.Mode().IsRegular() {
+		return HashResponseWire{Error: &ErrorDetail{Message: fmt.Sprintf("%s is not a regular file", path), Type: "validation"}}
+	}
+
+	h := newHash()
+	size, err := io.Copy(h, &contextReader{ctx: ctx, r: f})
+	if err != nil {
+		return HashResponseWire{Error: toErrorDetail(err)}
+	}
+	return HashResponseWire{
+		Algorithm: algorithm,
+		Digest:    hex.EncodeToString(h.Sum(nil)),
+		Size:      size,
+	}
+}
+
+// contextReader stops reading once ctx is done, so hashing a large file
+// honors the call's deadline.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,55 @@
+package hostfuncs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "data")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0o600))
+
+	tests := []struct {
+		algorithm string
+		want      HashResponseWire
+	}{
+		{"", HashResponseWire{Algorithm: "sha256", Digest: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", Size: 5}},
+		{"sha512", HashResponseWire{Algorithm: "sha512", Digest: "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043", Size: 5}},
+		{"blake3", HashResponseWire{Algorithm: "blake3", Digest: "ea8f163db38682925e4491c5e58d4bb3506ef8c14eb78a86e908c5624a67200f", Size: 5}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, hashFile(context.Background(), path, tt.algorithm), tt.algorithm)
+	}
+}
+
+func TestHashFile_Errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0o600))
+
+	res := hashFile(context.Background(), path, "md5")
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "validation", res.Error.Type)
+
+	res = hashFile(context.Background(), dir, "")
+	require.NotNil(t, res.Error)
+	assert.Contains(t, res.Error.Message, "not a regular file")
+
+	res = hashFile(context.Background(), filepath.Join(dir, "missing"), "")
+	assert.NotNil(t, res.Error)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res = hashFile(ctx, path, "")
+	require.NotNil(t, res.Error)
+	assert.Empty(t, res.Digest)
+}
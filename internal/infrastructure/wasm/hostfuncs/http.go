@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/wireformat"
 	"github.com/tetratelabs/wazero/api"
 )
 
@@ -38,8 +39,23 @@ func (t *dnsPinningTransport) RoundTrip(req *http.Request) (*http.Response, erro
 	}
 
 	port := getPort(req.URL)
-	pinnedTransport := t.createPinnedTransport(validatedIP, port, hostname, req.URL.Scheme)
+	newTransport := func() *http.Transport {
+		return t.createPinnedTransport(validatedIP, port, hostname, req.URL.Scheme)
+	}
+
+	// Pooled transports are keyed by the validated IP, so a reused connection
+	// always goes to an address that passed the checks above.
+	if pool, ok := HTTPPoolFromContext(t.ctx); ok {
+		dest := httpDestination{scheme: req.URL.Scheme, ip: validatedIP, port: port, serverName: hostname}
+		if transport, ok := pool.transport(dest, newTransport); ok {
+			return transport.RoundTrip(req)
+		}
+	}
 
+	// Unpooled transports are dropped after the request, so their connections
+	// must not outlive it.
+	pinnedTransport := newTransport()
+	pinnedTransport.DisableKeepAlives = true
 	return pinnedTransport.RoundTrip(req)
 }
 
@@ -75,7 +91,7 @@ func (t *dnsPinningTransport) createPinnedTransport(validatedIP, port, hostname,
 
 // HTTPRequest performs an HTTP request on behalf of the plugin.
 func HTTPRequest(ctx context.Context, mod api.Module, stack []uint64, checker *CapabilityChecker, version build.Info) {
-	request, err := readHTTPRequest(ctx, mod, stack[0])
+	request, rawBody, err := readHTTPRequest(ctx, mod, stack[0])
 	if err != nil {
 		stack[0] = hostWriteResponse(ctx, mod, HTTPResponseWire{Error: err})
 		return
@@ -91,35 +107,51 @@ func HTTPRequest(ctx context.Context, mod api.Module, stack []uint64, checker *C
 		return
 	}
 
-	req, err := buildHTTPRequest(ctx, httpCtx, request, version)
+	req, err := buildHTTPRequest(ctx, httpCtx, request, rawBody, version)
 	if err != nil {
 		stack[0] = hostWriteResponse(ctx, mod, HTTPResponseWire{Error: err})
 		return
 	}
 
-	response := executeHTTPRequest(ctx, req, pluginName, checker, request.URL)
+	response, body := cachedHTTPRequest(ctx, req, pluginName, checker, request, rawBody)
+	if request.BinaryBody && response.Error == nil {
+		contentType := http.Header(response.Headers).Get("Content-Type")
+		if contentType == "" {
+			contentType = wireformat.ContentTypeOctetStream
+		}
+		stack[0] = hostWriteFramed(ctx, mod, response, wireformat.Frame{ContentType: contentType, Data: body})
+		return
+	}
+	if len(body) > 0 {
+		response.Body = base64.StdEncoding.EncodeToString(body)
+	}
 	stack[0] = hostWriteResponse(ctx, mod, response)
 }
 
 // readHTTPRequest reads and unmarshals the HTTP request from guest memory.
-func readHTTPRequest(ctx context.Context, mod api.Module, requestPacked uint64) (*HTTPRequestWire, *ErrorDetail) {
-	ptr, length := unpackPtrLen(requestPacked)
-
-	requestBytes, ok := mod.Memory().Read(ptr, length)
-	if !ok {
-		errMsg := "hostfuncs: failed to read HTTP request from Guest memory"
+// Framed requests carry the request body as a raw payload frame, which is returned separately.
+func readHTTPRequest(ctx context.Context, mod api.Module, requestPacked uint64) (*HTTPRequestWire, []byte, *ErrorDetail) {
+	requestBytes, payloads, err := hostReadMessage(mod, requestPacked)
+	if err != nil {
+		errMsg := fmt.Sprintf("hostfuncs: failed to read HTTP request from Guest memory: %v", err)
 		slog.ErrorContext(ctx, errMsg)
-		return nil, &ErrorDetail{Message: errMsg, Type: "internal"}
+		return nil, nil, &ErrorDetail{Message: errMsg, Type: "internal"}
 	}
 
 	var request HTTPRequestWire
 	if err := json.Unmarshal(requestBytes, &request); err != nil {
 		errMsg := fmt.Sprintf("hostfuncs: failed to unmarshal HTTP request: %v", err)
 		slog.ErrorContext(ctx, errMsg)
-		return nil, &ErrorDetail{Message: errMsg, Type: "internal"}
+		return nil, nil, &ErrorDetail{Message: errMsg, Type: "internal"}
+	}
+
+	var rawBody []byte
+	if len(payloads) > 0 {
+		// Copy out of guest memory; it may move when the response is allocated
+		rawBody = bytes.Clone(payloads[0].Data)
 	}
 
-	return &request, nil
+	return &request, rawBody, nil
 }
 
 // checkHTTPCapability validates URL and checks network capability.
@@ -149,9 +181,11 @@ func checkHTTPCapability(ctx context.Context, checker *CapabilityChecker, plugin
 }
 
 // buildHTTPRequest creates the native http.Request from wire format.
-func buildHTTPRequest(ctx context.Context, httpCtx context.Context, request *HTTPRequestWire, version build.Info) (*http.Request, *ErrorDetail) {
+func buildHTTPRequest(ctx context.Context, httpCtx context.Context, request *HTTPRequestWire, rawBody []byte, version build.Info) (*http.Request, *ErrorDetail) {
 	var reqBody io.Reader
-	if request.Body != "" {
+	if rawBody != nil {
+		reqBody = bytes.NewReader(rawBody)
+	} else if request.Body != "" {
 		decodedBody, err := base64.StdEncoding.DecodeString(request.Body)
 		if err != nil {
 			errMsg := fmt.Sprintf("failed to decode request body: %v", err)
@@ -180,8 +214,33 @@ func buildHTTPRequest(ctx context.Context, httpCtx context.Context, request *HTT
 	return req, nil
 }
 
+// cachedHTTPRequest performs the HTTP request, answering it from the HTTP
+// cache attached to ctx when it is cacheable, and adds the exchange to the
+// debug log attached to ctx.
+func cachedHTTPRequest(ctx context.Context, req *http.Request, pluginName string, checker *CapabilityChecker, request *HTTPRequestWire, rawBody []byte) (HTTPResponseWire, []byte) {
+	start := time.Now()
+	maxBodySize := effectiveMaxBodySize(request.MaxBodySize)
+	fetch := func() (HTTPResponseWire, []byte) {
+		return executeHTTPRequest(ctx, req, pluginName, checker, request.URL, maxBodySize)
+	}
+
+	var response HTTPResponseWire
+	var body []byte
+	hit := false
+	if cache, ok := HTTPCacheFromContext(ctx); ok && cacheableRequest(request, rawBody) {
+		response, body, hit = cache.do(httpCacheRequestKey(pluginName, request, maxBodySize), fetch)
+	} else {
+		response, body = fetch()
+	}
+	if hit {
+		recordHTTPCacheHit(ctx)
+	}
+	recordHTTPExchange(ctx, request, response, time.Since(start), hit)
+	return response, body
+}
+
 // executeHTTPRequest performs the HTTP request and returns the response.
-func executeHTTPRequest(ctx context.Context, req *http.Request, pluginName string, checker *CapabilityChecker, requestURL string) HTTPResponseWire {
+func executeHTTPRequest(ctx context.Context, req *http.Request, pluginName string, checker *CapabilityChecker, requestURL string, maxBodySize int64) (HTTPResponseWire, []byte) {
 	baseTransport := &http.Transport{
 		ForceAttemptHTTP2:     true,
 		MaxIdleConns:          10,
@@ -205,44 +264,50 @@ func executeHTTPRequest(ctx context.Context, req *http.Request, pluginName strin
 		},
 	}
 
-	resp, err := client.Do(req)
+	resp, err := client.Do(req.WithContext(withConnectionStats(req.Context())))
 	if err != nil {
 		errMsg := fmt.Sprintf("HTTP request failed: %v", err)
 		slog.ErrorContext(ctx, errMsg, "url", requestURL, "method", req.Method)
-		return HTTPResponseWire{Error: toErrorDetail(err)}
+		return HTTPResponseWire{Error: toErrorDetail(err)}, nil
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	return readHTTPResponse(ctx, resp, requestURL)
+	return readHTTPResponse(ctx, resp, requestURL, maxBodySize)
 }
 
-// readHTTPResponse reads and encodes the HTTP response.
-func readHTTPResponse(ctx context.Context, resp *http.Response, requestURL string) HTTPResponseWire {
-	const maxBodySize = 10 * 1024 * 1024 // 10MB limit
+// maxHTTPBodySize is the largest response body the host will return to a guest.
+const maxHTTPBodySize = 10 * 1024 * 1024 // 10MB limit
 
+// effectiveMaxBodySize negotiates the response body limit for a request.
+// Guests may ask for a smaller limit; anything else falls back to the host limit.
+func effectiveMaxBodySize(requested int64) int64 {
+	if requested <= 0 || requested > maxHTTPBodySize {
+		return maxHTTPBodySize
+	}
+	return requested
+}
+
+// readHTTPResponse reads the HTTP response, returning at most maxBodySize bytes of raw body.
+// The body is returned separately so callers can choose base64 or a framed payload.
+func readHTTPResponse(ctx context.Context, resp *http.Response, requestURL string, maxBodySize int64) (HTTPResponseWire, []byte) {
 	limitedReader := io.LimitReader(resp.Body, maxBodySize+1)
 	respBodyBytes, err := io.ReadAll(limitedReader)
 	if err != nil {
 		errMsg := fmt.Sprintf("failed to read response body: %v", err)
 		slog.ErrorContext(ctx, errMsg, "url", requestURL)
-		return HTTPResponseWire{Error: toErrorDetail(err)}
+		return HTTPResponseWire{Error: toErrorDetail(err)}, nil
 	}
 
 	bodyTruncated := false
-	if len(respBodyBytes) > maxBodySize {
+	if int64(len(respBodyBytes)) > maxBodySize {
 		respBodyBytes = respBodyBytes[:maxBodySize]
 		bodyTruncated = true
 		slog.WarnContext(ctx, "HTTP response body truncated",
 			"url", requestURL,
-			"max_size_mb", maxBodySize/(1024*1024),
+			"max_size_bytes", maxBodySize,
 			"truncated", true)
 	}
 
-	var encodedRespBody string
-	if len(respBodyBytes) > 0 {
-		encodedRespBody = base64.StdEncoding.EncodeToString(respBodyBytes)
-	}
-
 	responseHeaders := make(map[string][]string)
 	for key, values := range resp.Header {
 		responseHeaders[key] = values
@@ -251,7 +316,6 @@ func readHTTPResponse(ctx context.Context, resp *http.Response, requestURL strin
 	return HTTPResponseWire{
 		StatusCode:    resp.StatusCode,
 		Headers:       responseHeaders,
-		Body:          encodedRespBody,
 		BodyTruncated: bodyTruncated,
-	}
+	}, respBodyBytes
 }
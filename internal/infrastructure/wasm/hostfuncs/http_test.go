@@ -2,9 +2,15 @@ package hostfuncs
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"io"
+	"net/http"
 	"testing"
 
+	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/wireformat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -63,3 +69,91 @@ func Test_HTTP_Body_Truncation_ExactlyAtLimit(t *testing.T) {
 	assert.False(t, bodyTruncated, "Expected NO truncation for body exactly at limit")
 	assert.Equal(t, int(maxBodySize), len(readerBytes), "Should have read exactly maxBodySize bytes")
 }
+
+func newTestHTTPResponse(body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/octet-stream"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+func Test_readHTTPResponse_BinaryBodyRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// Larger than 64KB and full of NUL bytes; must survive framing intact
+	body := make([]byte, 200*1024)
+	for i := range body {
+		body[i] = byte(i % 7)
+	}
+
+	wire, raw := readHTTPResponse(context.Background(), newTestHTTPResponse(body), "https://example.com", maxHTTPBodySize)
+	require.Nil(t, wire.Error)
+	assert.False(t, wire.BodyTruncated)
+	assert.Empty(t, wire.Body, "body is returned raw, not base64 encoded")
+
+	header, err := json.Marshal(wire)
+	require.NoError(t, err)
+	framed, err := wireformat.EncodeFrames(
+		wireformat.Frame{ContentType: wireformat.ContentTypeJSON, Data: header},
+		wireformat.Frame{ContentType: wireformat.ContentTypeOctetStream, Data: raw},
+	)
+	require.NoError(t, err)
+	assert.Less(t, len(framed), len(body)+len(header)+64, "framing must not inflate the payload")
+
+	frames, err := wireformat.DecodeFrames(framed)
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+	assert.Equal(t, body, frames[1].Data)
+}
+
+func Test_readHTTPResponse_NegotiatedLimit(t *testing.T) {
+	t.Parallel()
+
+	body := bytes.Repeat([]byte("C"), 1024)
+
+	wire, raw := readHTTPResponse(context.Background(), newTestHTTPResponse(body), "https://example.com", effectiveMaxBodySize(512))
+	require.Nil(t, wire.Error)
+	assert.True(t, wire.BodyTruncated)
+	assert.Len(t, raw, 512)
+}
+
+func Test_effectiveMaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		requested int64
+		want      int64
+	}{
+		{"default", 0, maxHTTPBodySize},
+		{"negative", -1, maxHTTPBodySize},
+		{"smaller", 4096, 4096},
+		{"clamped", maxHTTPBodySize * 2, maxHTTPBodySize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, effectiveMaxBodySize(tt.requested))
+		})
+	}
+}
+
+func Test_buildHTTPRequest_RawBody(t *testing.T) {
+	t.Parallel()
+
+	raw := []byte{0x00, 0xff, 0x00, 0x10}
+	request := &HTTPRequestWire{
+		Method: http.MethodPost,
+		URL:    "https://example.com",
+		Body:   base64.StdEncoding.EncodeToString([]byte("ignored")),
+	}
+
+	req, errDetail := buildHTTPRequest(context.Background(), context.Background(), request, raw, build.Get())
+	require.Nil(t, errDetail)
+
+	got, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, raw, got, "raw payload frame takes precedence over base64 body")
+}
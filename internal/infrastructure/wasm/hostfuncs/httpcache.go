@@ -0,0 +1,190 @@
+package hostfuncs
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"golang.org/x/sync/singleflight"
+)
+
+// maxHTTPCacheBytes bounds the response bodies an HTTPCache holds. Responses
+// beyond it are returned but not stored.
+const maxHTTPCacheBytes = 64 << 20
+
+// HTTPCache shares the responses of GET requests between the http_request
+// calls of a run, for as long as their Cache-Control max-age allows.
+// Requests are keyed by plugin, URL, headers and body limit, so a plugin only
+// gets responses to requests it could have made itself. Concurrent identical
+// requests wait for a single fetch. It is safe for concurrent use.
+type HTTPCache struct {
+	now func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]httpCacheEntry
+	size     int
+	inflight singleflight.Group
+}
+
+type httpCacheEntry struct {
+	response HTTPResponseWire
+	body     []byte
+	expires  time.Time
+}
+
+// httpFetch is a response with its raw body.
+type httpFetch struct {
+	response HTTPResponseWire
+	body     []byte
+}
+
+// NewHTTPCache creates an empty cache.
+func NewHTTPCache() *HTTPCache {
+	return &HTTPCache{now: time.Now, entries: make(map[string]httpCacheEntry)}
+}
+
+// do returns the cached response for key, or calls fetch and caches what it
+// returns for the response's max-age. hit reports a response not fetched for
+// this call.
+func (c *HTTPCache) do(key string, fetch func() (HTTPResponseWire, []byte)) (response HTTPResponseWire, body []byte, hit bool) {
+	now := c.now()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !now.Before(entry.expires) {
+		delete(c.entries, key)
+		c.size -= len(entry.body)
+		ok = false
+	}
+	c.mu.Unlock()
+	if ok {
+		return entry.response, entry.body, true
+	}
+
+	fetched := false
+	v, _, _ := c.inflight.Do(key, func() (any, error) {
+		fetched = true
+		response, body := fetch()
+		if ttl := responseFreshness(response); ttl > 0 && !response.BodyTruncated {
+			c.mu.Lock()
+			if c.size+len(body) <= maxHTTPCacheBytes {
+				c.entries[key] = httpCacheEntry{response: response, body: body, expires: c.now().Add(ttl)}
+				c.size += len(body)
+			}
+			c.mu.Unlock()
+		}
+		return httpFetch{response: response, body: body}, nil
+	})
+	result := v.(httpFetch) //nolint:forcetypeassert // only httpFetch is returned
+	return result.response, result.body, !fetched
+}
+
+// responseFreshness returns how long a response may be reused: its
+// Cache-Control max-age less its Age. Responses without a max-age, or marked
+// no-store or no-cache, are not reused.
+func responseFreshness(response HTTPResponseWire) time.Duration {
+	if response.Error != nil {
+		return 0
+	}
+	headers := http.Header(response.Headers)
+	maxAge := -1
+	for _, directive := range cacheControlDirectives(headers) {
+		name, value, _ := strings.Cut(directive, "=")
+		switch name {
+		case "no-store", "no-cache":
+			return 0
+		case "max-age":
+			seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil {
+				return 0
+			}
+			maxAge = seconds
+		}
+	}
+	if maxAge <= 0 {
+		return 0
+	}
+	if age, err := strconv.Atoi(headers.Get("Age")); err == nil && age > 0 {
+		maxAge -= age
+	}
+	return time.Duration(max(maxAge, 0)) * time.Second
+}
+
+// cacheControlDirectives returns the lowercased Cache-Control directives of headers.
+func cacheControlDirectives(headers http.Header) []string {
+	var directives []string
+	for _, value := range headers.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			if directive = strings.ToLower(strings.TrimSpace(directive)); directive != "" {
+				directives = append(directives, directive)
+			}
+		}
+	}
+	return directives
+}
+
+// httpCacheRequestKey identifies a request to the cache.
+func httpCacheRequestKey(pluginName string, request *HTTPRequestWire, maxBodySize int64) string {
+	var key strings.Builder
+	key.WriteString(pluginName + "\n" + request.URL + "\n" + strconv.FormatInt(maxBodySize, 10))
+	if request.BinaryBody {
+		key.WriteString(" binary")
+	}
+	names := make([]string, 0, len(request.Headers))
+	for name := range request.Headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		key.WriteString("\n" + http.CanonicalHeaderKey(name) + ": " + strings.Join(request.Headers[name], ", "))
+	}
+	return key.String()
+}
+
+// cacheableRequest reports whether a request may be answered from the cache:
+// a GET without a body whose headers do not ask for a fresh response.
+func cacheableRequest(request *HTTPRequestWire, rawBody []byte) bool {
+	if !strings.EqualFold(request.Method, http.MethodGet) || request.Body != "" || len(rawBody) > 0 {
+		return false
+	}
+	for _, directive := range cacheControlDirectives(http.Header(request.Headers)) {
+		if directive == "no-cache" || directive == "no-store" || directive == "max-age=0" {
+			return false
+		}
+	}
+	return true
+}
+
+var httpCacheKey = &contextKey{name: "http_cache"}
+
+// WithHTTPCache attaches a response cache to the context. GET requests made
+// with the context are answered from it.
+func WithHTTPCache(ctx context.Context, cache *HTTPCache) context.Context {
+	if cache == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, httpCacheKey, cache)
+}
+
+// HTTPCacheFromContext returns the response cache attached to the context,
+// unless fresh connections are requested: observations measuring handshakes
+// must reach the server.
+func HTTPCacheFromContext(ctx context.Context) (*HTTPCache, bool) {
+	if fresh, _ := ctx.Value(freshConnectionsKey).(bool); fresh {
+		return nil, false
+	}
+	cache, ok := ctx.Value(httpCacheKey).(*HTTPCache)
+	return cache, ok
+}
+
+// recordHTTPCacheHit counts a response served from the cache in the
+// performance recorder in ctx, if any.
+func recordHTTPCacheHit(ctx context.Context) {
+	if recorder, ok := execution.PerfRecorderFromContext(ctx); ok {
+		recorder.RecordHTTPCacheHit()
+	}
+}
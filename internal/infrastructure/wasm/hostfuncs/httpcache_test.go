@@ -0,0 +1,106 @@
+package hostfuncs
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPCache_MaxAge(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewHTTPCache()
+	cache.now = func() time.Time { return now }
+
+	fetches := 0
+	fetch := func() (HTTPResponseWire, []byte) {
+		fetches++
+		return HTTPResponseWire{
+			StatusCode: http.StatusOK,
+			Headers:    map[string][]string{"Cache-Control": {"public, max-age=60"}, "Age": {"10"}},
+		}, []byte("ok")
+	}
+
+	_, body, hit := cache.do("key", fetch)
+	assert.False(t, hit)
+	assert.Equal(t, []byte("ok"), body)
+
+	now = now.Add(49 * time.Second)
+	_, body, hit = cache.do("key", fetch)
+	assert.True(t, hit)
+	assert.Equal(t, []byte("ok"), body)
+	assert.Equal(t, 1, fetches)
+
+	now = now.Add(time.Second)
+	_, _, hit = cache.do("key", fetch)
+	assert.False(t, hit, "max-age less Age has passed")
+	assert.Equal(t, 2, fetches)
+}
+
+func TestHTTPCache_NotStored(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response HTTPResponseWire
+	}{
+		{"no max-age", HTTPResponseWire{StatusCode: http.StatusOK}},
+		{"no-store", HTTPResponseWire{Headers: map[string][]string{"Cache-Control": {"max-age=60, no-store"}}}},
+		{"no-cache", HTTPResponseWire{Headers: map[string][]string{"Cache-Control": {"No-Cache", "max-age=60"}}}},
+		{"truncated", HTTPResponseWire{Headers: map[string][]string{"Cache-Control": {"max-age=60"}}, BodyTruncated: true}},
+		{"error", HTTPResponseWire{Headers: map[string][]string{"Cache-Control": {"max-age=60"}}, Error: &ErrorDetail{Message: "refused"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := NewHTTPCache()
+			fetch := func() (HTTPResponseWire, []byte) { return tt.response, nil }
+			cache.do("key", fetch)
+			_, _, hit := cache.do("key", fetch)
+			assert.False(t, hit)
+		})
+	}
+}
+
+func TestHTTPCache_RequestKey(t *testing.T) {
+	t.Parallel()
+
+	request := &HTTPRequestWire{
+		Method:  http.MethodGet,
+		URL:     "https://example.com/",
+		Headers: map[string][]string{"accept": {"text/html"}, "X-Trace": {"1"}},
+	}
+	key := httpCacheRequestKey("http", request, maxHTTPBodySize)
+
+	assert.Equal(t, key, httpCacheRequestKey("http", &HTTPRequestWire{
+		Method:  http.MethodGet,
+		URL:     "https://example.com/",
+		Headers: map[string][]string{"X-Trace": {"1"}, "accept": {"text/html"}},
+	}, maxHTTPBodySize), "header order does not matter")
+	assert.NotEqual(t, key, httpCacheRequestKey("other", request, maxHTTPBodySize), "plugins do not share responses")
+	assert.NotEqual(t, key, httpCacheRequestKey("http", request, 1024), "body limits do not share responses")
+	assert.NotEqual(t, key, httpCacheRequestKey("http", &HTTPRequestWire{
+		Method:  http.MethodGet,
+		URL:     "https://example.com/",
+		Headers: map[string][]string{"Accept": {"application/json"}, "X-Trace": {"1"}},
+	}, maxHTTPBodySize))
+}
+
+func TestCacheableRequest(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, cacheableRequest(&HTTPRequestWire{Method: "GET"}, nil))
+	assert.True(t, cacheableRequest(&HTTPRequestWire{Method: "get"}, nil))
+	assert.False(t, cacheableRequest(&HTTPRequestWire{Method: "HEAD"}, nil))
+	assert.False(t, cacheableRequest(&HTTPRequestWire{Method: "POST"}, nil))
+	assert.False(t, cacheableRequest(&HTTPRequestWire{Method: "GET", Body: "e30="}, nil))
+	assert.False(t, cacheableRequest(&HTTPRequestWire{Method: "GET"}, []byte("{}")))
+	assert.False(t, cacheableRequest(&HTTPRequestWire{
+		Method:  "GET",
+		Headers: map[string][]string{"Cache-Control": {"no-cache"}},
+	}, nil))
+}
@@ -0,0 +1,139 @@
+package hostfuncs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxHTTPExchanges bounds the exchanges an HTTPDebugLog keeps per observation.
+const maxHTTPExchanges = 20
+
+// HTTPExchange summarizes one http_request call for troubleshooting. It holds
+// no header values, bodies, credentials or query values, so it can be logged.
+type HTTPExchange struct {
+	Method          string
+	URL             string   // with user info removed and query values redacted
+	RequestHeaders  []string // names only
+	Status          int      // 0 when the request failed
+	ResponseHeaders []string // names only
+	Duration        time.Duration
+	Cached          bool   // answered by the HTTP cache
+	Error           string // message of a failed request
+}
+
+// Curl returns a curl command reproducing the request, with header values
+// left for the reader to fill in.
+func (e HTTPExchange) Curl() string {
+	var cmd strings.Builder
+	cmd.WriteString("curl -X " + e.Method)
+	for _, name := range e.RequestHeaders {
+		cmd.WriteString(" -H '" + name + ": <redacted>'")
+	}
+	cmd.WriteString(" '" + e.URL + "'")
+	return cmd.String()
+}
+
+// sanitizeURL removes user info from rawURL and replaces its query values.
+func sanitizeURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid url>"
+	}
+	u.User = nil
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query[key] = []string{"redacted"}
+		}
+		u.RawQuery = query.Encode()
+	}
+	u.Fragment = ""
+	return u.String()
+}
+
+// headerNames returns the sorted canonical names of headers.
+func headerNames(headers map[string][]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, http.CanonicalHeaderKey(name))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// HTTPDebugLog collects the HTTP exchanges of an observation, so they can be
+// reported if it fails. It is safe for concurrent use.
+type HTTPDebugLog struct {
+	mu        sync.Mutex
+	exchanges []HTTPExchange
+	dropped   int
+}
+
+// Exchanges returns the exchanges collected so far, in call order, and how
+// many more were dropped over the limit.
+func (l *HTTPDebugLog) Exchanges() ([]HTTPExchange, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.exchanges), l.dropped
+}
+
+func (l *HTTPDebugLog) add(exchange HTTPExchange) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.exchanges) == maxHTTPExchanges {
+		l.dropped++
+		return
+	}
+	l.exchanges = append(l.exchanges, exchange)
+}
+
+var httpDebugLogKey = &contextKey{name: "http_debug_log"}
+
+// WithHTTPDebugLog attaches a debug log to the context. http_request calls
+// made with the context add a summary of their exchange to it.
+func WithHTTPDebugLog(ctx context.Context, log *HTTPDebugLog) context.Context {
+	if log == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, httpDebugLogKey, log)
+}
+
+// HTTPDebugLogFromContext returns the debug log attached to the context, if any.
+func HTTPDebugLogFromContext(ctx context.Context) (*HTTPDebugLog, bool) {
+	log, ok := ctx.Value(httpDebugLogKey).(*HTTPDebugLog)
+	return log, ok
+}
+
+// recordHTTPExchange adds the summary of a request and its response to the
+// debug log in ctx, if any.
+func recordHTTPExchange(ctx context.Context, request *HTTPRequestWire, response HTTPResponseWire, duration time.Duration, cached bool) {
+	log, ok := HTTPDebugLogFromContext(ctx)
+	if !ok {
+		return
+	}
+	exchange := HTTPExchange{
+		Method:         strings.ToUpper(request.Method),
+		URL:            sanitizeURL(request.URL),
+		RequestHeaders: headerNames(request.Headers),
+		Duration:       duration,
+		Cached:         cached,
+	}
+	if exchange.Method == "" {
+		exchange.Method = http.MethodGet
+	}
+	if response.Error != nil {
+		// Client errors quote the request URL
+		message := strings.ReplaceAll(response.Error.Message, request.URL, exchange.URL)
+		exchange.Error = fmt.Sprintf("%s: %s", response.Error.Type, message)
+	} else {
+		exchange.Status = response.StatusCode
+		exchange.ResponseHeaders = headerNames(response.Headers)
+	}
+	log.add(exchange)
+}
@@ -0,0 +1,119 @@
+package hostfuncs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+const (
+	// maxPooledDestinations bounds the transports an HTTPPool keeps. Requests
+	// to further destinations use a transport of their own.
+	maxPooledDestinations = 256
+
+	// Per destination, at most maxIdleConnsPerDestination connections stay
+	// open between requests, for up to pooledIdleConnTimeout.
+	maxIdleConnsPerDestination = 4
+	pooledIdleConnTimeout      = 30 * time.Second
+)
+
+// httpDestination identifies the connections a pooled transport holds: the
+// validated IP a hostname was pinned to, and the TLS server name.
+type httpDestination struct {
+	scheme     string
+	ip         string
+	port       string
+	serverName string
+}
+
+// HTTPPool keeps an HTTP transport per destination, so http_request calls
+// to the same host reuse its connections across observations instead of
+// repeating TCP and TLS handshakes. It is safe for concurrent use.
+type HTTPPool struct {
+	mu         sync.Mutex
+	transports map[httpDestination]*http.Transport
+}
+
+// NewHTTPPool creates an empty pool.
+func NewHTTPPool() *HTTPPool {
+	return &HTTPPool{transports: make(map[httpDestination]*http.Transport)}
+}
+
+// transport returns the pooled transport for dest, creating it with
+// newTransport. It returns false when dest is new and the pool is full.
+func (p *HTTPPool) transport(dest httpDestination, newTransport func() *http.Transport) (*http.Transport, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if transport, ok := p.transports[dest]; ok {
+		return transport, true
+	}
+	if len(p.transports) >= maxPooledDestinations {
+		return nil, false
+	}
+	transport := newTransport()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerDestination
+	transport.IdleConnTimeout = pooledIdleConnTimeout
+	p.transports[dest] = transport
+	return transport, true
+}
+
+// CloseIdleConnections closes the idle connections of all pooled transports.
+func (p *HTTPPool) CloseIdleConnections() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, transport := range p.transports {
+		transport.CloseIdleConnections()
+	}
+}
+
+var (
+	httpPoolKey         = &contextKey{name: "http_pool"}
+	freshConnectionsKey = &contextKey{name: "fresh_connections"}
+)
+
+// WithHTTPPool attaches a connection pool to the context. http_request calls
+// made with the context reuse its connections.
+func WithHTTPPool(ctx context.Context, pool *HTTPPool) context.Context {
+	if pool == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, httpPoolKey, pool)
+}
+
+// WithFreshConnections makes http_request calls made with the context open
+// new connections and close them after the request, even when a pool is
+// attached, and skip the response cache, e.g. so latency checks include the
+// full handshake.
+func WithFreshConnections(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshConnectionsKey, true)
+}
+
+// HTTPPoolFromContext returns the connection pool attached to the context,
+// unless fresh connections are requested.
+func HTTPPoolFromContext(ctx context.Context) (*HTTPPool, bool) {
+	if fresh, _ := ctx.Value(freshConnectionsKey).(bool); fresh {
+		return nil, false
+	}
+	pool, ok := ctx.Value(httpPoolKey).(*HTTPPool)
+	return pool, ok
+}
+
+// withConnectionStats counts the connections requests made with the
+// returned context get, and whether they were reused, in the performance
+// recorder in ctx, if any.
+func withConnectionStats(ctx context.Context) context.Context {
+	recorder, ok := execution.PerfRecorderFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			recorder.RecordHTTPConnection(info.Reused)
+		},
+	})
+}
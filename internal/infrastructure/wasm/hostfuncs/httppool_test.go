@@ -0,0 +1,82 @@
+package hostfuncs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getN makes n GET requests to url through executeHTTPRequest.
+func getN(t *testing.T, ctx context.Context, url string, n int) {
+	t.Helper()
+
+	checker := NewCapabilityChecker(map[string][]capabilities.Capability{
+		"test-plugin": {{Kind: "network", Pattern: "outbound:private"}},
+	})
+	for range n {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		require.NoError(t, err)
+		response, _ := executeHTTPRequest(ctx, req, "test-plugin", checker, url, maxHTTPBodySize)
+		require.Nil(t, response.Error)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+	}
+}
+
+func TestHTTPPool_ReusesConnections(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	pool := NewHTTPPool()
+	defer pool.CloseIdleConnections()
+	recorder := execution.NewPerfRecorder()
+	ctx := execution.WithPerfRecorder(WithHTTPPool(context.Background(), pool), recorder)
+
+	getN(t, ctx, server.URL, 3)
+	assert.Equal(t, &execution.HTTPStats{Connections: 3, ReusedConnections: 2}, recorder.Report().HTTP)
+	assert.Len(t, pool.transports, 1)
+}
+
+func TestHTTPPool_FreshConnections(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	pool := NewHTTPPool()
+	recorder := execution.NewPerfRecorder()
+	ctx := WithFreshConnections(WithHTTPPool(context.Background(), pool))
+	ctx = execution.WithPerfRecorder(ctx, recorder)
+
+	getN(t, ctx, server.URL, 3)
+	assert.Equal(t, &execution.HTTPStats{Connections: 3}, recorder.Report().HTTP)
+	assert.Empty(t, pool.transports)
+}
+
+func TestHTTPPool_Full(t *testing.T) {
+	t.Parallel()
+
+	pool := NewHTTPPool()
+	newTransport := func() *http.Transport { return &http.Transport{} }
+	for i := range maxPooledDestinations {
+		_, ok := pool.transport(httpDestination{scheme: "http", ip: "192.0.2.1", port: fmt.Sprint(i)}, newTransport)
+		require.True(t, ok)
+	}
+
+	_, ok := pool.transport(httpDestination{scheme: "http", ip: "192.0.2.2", port: "80"}, newTransport)
+	assert.False(t, ok, "new destinations are not pooled once the pool is full")
+	_, ok = pool.transport(httpDestination{scheme: "http", ip: "192.0.2.1", port: "0"}, newTransport)
+	assert.True(t, ok, "known destinations still are")
+}
@@ -0,0 +1,51 @@
+package hostfuncs
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// IOTimer accumulates the time request/response host functions spend on
+// I/O for a plugin call. It is safe for concurrent use.
+type IOTimer struct {
+	nanos atomic.Int64
+}
+
+// Elapsed returns the accumulated host I/O time.
+func (t *IOTimer) Elapsed() time.Duration {
+	return time.Duration(t.nanos.Load())
+}
+
+var ioTimerKey = &contextKey{name: "io_timer"}
+
+// WithIOTimer attaches a timer to the context. Host function calls made with
+// the context add their duration to it.
+func WithIOTimer(ctx context.Context, timer *IOTimer) context.Context {
+	if timer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, ioTimerKey, timer)
+}
+
+// IOTimerFromContext returns the timer attached to the context, if any.
+func IOTimerFromContext(ctx context.Context) (*IOTimer, bool) {
+	timer, ok := ctx.Value(ioTimerKey).(*IOTimer)
+	return timer, ok
+}
+
+// timed wraps a host function so its duration is added to the timer in ctx.
+func timed(fn api.GoModuleFunc) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		timer, ok := IOTimerFromContext(ctx)
+		if !ok {
+			fn(ctx, mod, stack)
+			return
+		}
+		start := time.Now()
+		fn(ctx, mod, stack)
+		timer.nanos.Add(int64(time.Since(start)))
+	}
+}
@@ -0,0 +1,51 @@
+package hostfuncs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tetratelabs/wazero/api"
+)
+
+func TestTimed(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	slow := timed(func(context.Context, api.Module, []uint64) {
+		calls++
+		time.Sleep(5 * time.Millisecond)
+	})
+
+	// Without a timer the function just runs
+	slow(context.Background(), nil, nil)
+	assert.Equal(t, 1, calls)
+
+	timer := &IOTimer{}
+	ctx := WithIOTimer(context.Background(), timer)
+	slow(ctx, nil, nil)
+	slow(ctx, nil, nil)
+	assert.Equal(t, 3, calls)
+	assert.GreaterOrEqual(t, timer.Elapsed(), 10*time.Millisecond)
+}
+
+func TestIntercepted_ReplayIsNotTimed(t *testing.T) {
+	t.Parallel()
+
+	lookup := intercepted("dns_lookup", func(ctx context.Context, mod api.Module, stack []uint64) {
+		stack[0] = hostWriteResponse(ctx, mod, DNSResponseWire{Records: []string{"192.0.2.1"}})
+	})
+
+	recorder := NewRecordingCassette()
+	recordTimer := &IOTimer{}
+	ctx := WithIOTimer(WithCassette(context.Background(), recorder), recordTimer)
+	newFakeModule().call(t, ctx, lookup, dnsRequest(t, "example.com", 5000))
+	assert.Positive(t, recordTimer.Elapsed(), "recorded calls reach the host function")
+
+	player := &Cassette{mode: CassetteReplay, interactions: recorder.Interactions(), cursor: map[string]int{}}
+	replayTimer := &IOTimer{}
+	ctx = WithIOTimer(WithCassette(context.Background(), player), replayTimer)
+	newFakeModule().call(t, ctx, lookup, dnsRequest(t, "example.com", 5000))
+	assert.Zero(t, replayTimer.Elapsed(), "replayed calls do no I/O")
+}
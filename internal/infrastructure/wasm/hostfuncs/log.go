@@ -42,7 +42,7 @@ func LogMessage(ctx context.Context, mod api.Module, stack []uint64) {
 
 	logCtx := buildLogContext(ctx, logMsg)
 	level := parseLogLevel(logMsg.Level)
-	attrs := convertLogAttrs(logMsg.Attrs)
+	attrs := append(convertLogAttrs(logMsg.Attrs), correlationAttrs(ctx)...)
 
 	slog.LogAttrs(logCtx, level, logMsg.Message, attrs...)
 }
@@ -84,6 +84,20 @@ func parseLogLevel(levelStr string) slog.Level {
 	return level
 }
 
+// correlationAttrs tie a plugin log message to the run and control it was
+// logged for.
+func correlationAttrs(ctx context.Context) []slog.Attr {
+	exec := ExecutionContext(ctx)
+	var attrs []slog.Attr
+	if exec.RunID != "" {
+		attrs = append(attrs, slog.String("run_id", exec.RunID))
+	}
+	if exec.ControlID != "" {
+		attrs = append(attrs, slog.String("control_id", exec.ControlID), slog.Int("observation", exec.ObservationIndex))
+	}
+	return attrs
+}
+
 // convertLogAttrs converts wire attributes to slog.Attr slice.
 func convertLogAttrs(wireAttrs []LogAttrWire) []slog.Attr {
 	attrs := make([]slog.Attr, 0, len(wireAttrs))
@@ -0,0 +1,101 @@
+package hostfuncs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// PluginObserver runs an observation of a dependency of a composite plugin.
+type PluginObserver func(ctx context.Context, plugin string, config map[string]interface{}) (*execution.Evidence, error)
+
+// ErrUndeclaredDependency is returned by observers for plugins the composite
+// plugin does not declare as a dependency.
+var ErrUndeclaredDependency = errors.New("plugin is not a declared dependency")
+
+var pluginObserverKey = &contextKey{name: "plugin_observer"}
+
+// WithPluginObserver attaches the observer the observe_plugin host function
+// runs dependencies with. A nil observer detaches the one of an enclosing
+// composite plugin, so dependencies cannot reach its dependencies.
+func WithPluginObserver(ctx context.Context, observer PluginObserver) context.Context {
+	return context.WithValue(ctx, pluginObserverKey, observer)
+}
+
+// PluginObserverFromContext returns the observer attached to the context, if any.
+func PluginObserverFromContext(ctx context.Context) (PluginObserver, bool) {
+	observer, ok := ctx.Value(pluginObserverKey).(PluginObserver)
+	return observer, ok && observer != nil
+}
+
+// ObservePlugin implements the `observe_plugin` host function, through which
+// composite plugins run observations of the plugins they depend on.
+// It receives a packed uint64 (ptr+len) pointing to a JSON-encoded PluginObserveRequestWire.
+// It returns a packed uint64 (ptr+len) pointing to a JSON-encoded PluginObserveResponseWire.
+func ObservePlugin(ctx context.Context, mod api.Module, stack []uint64) {
+	ptr, length := unpackPtrLen(stack[0])
+	requestBytes, ok := mod.Memory().Read(ptr, length)
+	if !ok {
+		errMsg := "hostfuncs: failed to read observe request from Guest memory"
+		slog.ErrorContext(ctx, errMsg)
+		stack[0] = hostWriteResponse(ctx, mod, PluginObserveResponseWire{
+			Error: &ErrorDetail{Message: errMsg, Type: "internal"},
+		})
+		return
+	}
+
+	var request PluginObserveRequestWire
+	if err := json.Unmarshal(requestBytes, &request); err != nil {
+		errMsg := fmt.Sprintf("hostfuncs: failed to unmarshal observe request: %v", err)
+		slog.ErrorContext(ctx, errMsg)
+		stack[0] = hostWriteResponse(ctx, mod, PluginObserveResponseWire{
+			Error: &ErrorDetail{Message: errMsg, Type: "internal"},
+		})
+		return
+	}
+
+	pluginName := mod.Name()
+	if name, ok := PluginNameFromContext(ctx); ok {
+		pluginName = name
+	}
+
+	observer, ok := PluginObserverFromContext(ctx)
+	if !ok {
+		errMsg := fmt.Sprintf("plugin %s cannot observe plugin %s: it declares no dependencies", pluginName, request.Plugin)
+		slog.WarnContext(ctx, errMsg)
+		stack[0] = hostWriteResponse(ctx, mod, PluginObserveResponseWire{
+			Error: &ErrorDetail{Message: errMsg, Type: "capability"},
+		})
+		return
+	}
+
+	observeCtx, cancel := createContextFromWire(ctx, request.Context)
+	defer cancel()
+
+	evidence, err := observer(observeCtx, request.Plugin, request.Config)
+	if err != nil {
+		errType := "internal"
+		if errors.Is(err, ErrUndeclaredDependency) {
+			errType = "capability"
+		}
+		slog.WarnContext(ctx, "plugin observation failed", "plugin", pluginName, "dependency", request.Plugin, "error", err)
+		stack[0] = hostWriteResponse(ctx, mod, PluginObserveResponseWire{
+			Error: &ErrorDetail{Message: err.Error(), Type: errType},
+		})
+		return
+	}
+
+	data, err := json.Marshal(evidence)
+	if err != nil {
+		stack[0] = hostWriteResponse(ctx, mod, PluginObserveResponseWire{
+			Error: &ErrorDetail{Message: fmt.Sprintf("failed to marshal evidence: %v", err), Type: "internal"},
+		})
+		return
+	}
+	stack[0] = hostWriteResponse(ctx, mod, PluginObserveResponseWire{Evidence: data})
+}
@@ -0,0 +1,148 @@
+package hostfuncs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+)
+
+// mountInfoPath lists the mount points of the process on Linux. Elsewhere
+// it does not exist and hardening cannot detect mount points.
+const mountInfoPath = "/proc/self/mountinfo"
+
+var pathHardeningKey = &contextKey{name: "path_hardening"}
+
+// WithPathHardening enables path hardening for host file operations made
+// with the context: see AuditPath.
+func WithPathHardening(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pathHardeningKey, true)
+}
+
+// PathHardeningFromContext reports whether path hardening is enabled for the context.
+func PathHardeningFromContext(ctx context.Context) bool {
+	hardened, _ := ctx.Value(pathHardeningKey).(bool)
+	return hardened
+}
+
+// AuditPath checks an absolute path a plugin uses against the tree at root
+// it was granted, for path hardening. The path must already be canonical as
+// written (no ".", ".." or repeated separators), what the kernel resolves it
+// to must stay in the tree, and it must not cross a mount point below root,
+// such as a bind mount exposing another part of the host.
+func AuditPath(root, path string) error {
+	mounts, err := mountPoints()
+	if err != nil {
+		mounts = nil // no mount table on this platform
+	}
+	return auditPath(root, path, mounts)
+}
+
+// auditPath is AuditPath with the mount points of the host.
+func auditPath(root, path string, mounts []string) error {
+	if strings.ContainsRune(path, 0) {
+		return fmt.Errorf("path %q contains a NUL byte", path)
+	}
+	if !filepath.IsAbs(path) || path != filepath.Clean(path) {
+		return fmt.Errorf("path %q is not canonical", path)
+	}
+
+	root = capabilities.Canonicalize(root)
+	canonical := capabilities.Canonicalize(path)
+	if !capabilities.SymlinkFollow.Permits(root, canonical) {
+		return fmt.Errorf("path %s resolves to %s, outside the granted tree %s", path, canonical, root)
+	}
+
+	for _, mount := range mounts {
+		if mount != root && capabilities.SymlinkFollow.Permits(root, mount) && capabilities.SymlinkFollow.Permits(mount, canonical) {
+			return fmt.Errorf("path %s crosses mount point %s inside the granted tree %s", path, mount, root)
+		}
+	}
+	return nil
+}
+
+// auditGrantedPath applies AuditPath to a file operation the plugin's grants
+// allow: the path must pass it for one of the grants that match it as
+// written, and what it resolves to must still match that grant.
+func (c *CapabilityChecker) auditGrantedPath(pluginGrants []capabilities.Capability, operation, path string) error {
+	lexical := capabilities.NewPolicyWithSymlinks(capabilities.SymlinkFollow)
+	requested := capabilities.Capability{Kind: "fs", Pattern: operation + ":" + path}
+	canonical := capabilities.Capability{Kind: "fs", Pattern: operation + ":" + capabilities.Canonicalize(path)}
+
+	var firstErr error
+	for _, grant := range pluginGrants {
+		scope := []capabilities.Capability{grant}
+		if !lexical.IsGranted(requested, scope, c.cwd) {
+			continue
+		}
+		_, pattern, _ := strings.Cut(grant.Pattern, ":")
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(c.cwd, pattern)
+		}
+		err := AuditPath(capabilities.PatternRoot(pattern), path)
+		if err == nil && !lexical.IsGranted(canonical, scope, c.cwd) {
+			err = fmt.Errorf("path %s resolves to %s, which %s does not grant", path, capabilities.Canonicalize(path), grant.Pattern)
+		}
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		return fmt.Errorf("path %s is not granted as written", path)
+	}
+	return firstErr
+}
+
+// mountPoints returns the mount points of the process.
+func mountPoints() ([]string, error) {
+	f, err := os.Open(mountInfoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return parseMountInfo(f)
+}
+
+// parseMountInfo returns the mount points listed in a mountinfo file, the
+// fifth field of each line, with octal escapes such as \040 decoded.
+func parseMountInfo(r io.Reader) ([]string, error) {
+	var mounts []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+		mounts = append(mounts, unescapeMountPath(fields[4]))
+	}
+	return mounts, scanner.Err()
+}
+
+// unescapeMountPath decodes the \NNN octal escapes the kernel writes for
+// spaces, tabs, newlines and backslashes in mount paths.
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, `\`) {
+		return path
+	}
+	var out strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+4 <= len(path) {
+			if b, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				out.WriteByte(byte(b))
+				i += 3
+				continue
+			}
+		}
+		out.WriteByte(path[i])
+	}
+	return out.String()
+}
@@ -0,0 +1,117 @@
+package hostfuncs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bypassTree creates base/granted, holding a file, a subdirectory, a link to
+// a file outside, a link to a deep directory outside, and a link from outside
+// into the tree.
+func bypassTree(t *testing.T) (base, granted string) {
+	t.Helper()
+	base = t.TempDir()
+	granted = filepath.Join(base, "granted")
+	require.NoError(t, os.MkdirAll(filepath.Join(granted, "sub"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "outside", "deep"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(granted, "file"), []byte("in"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "outside", "secret"), []byte("out"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(base, "outside", "secret"), filepath.Join(granted, "escape")))
+	require.NoError(t, os.Symlink(filepath.Join(base, "outside", "deep"), filepath.Join(granted, "deep")))
+	require.NoError(t, os.Symlink(granted, filepath.Join(base, "into")))
+	return base, granted
+}
+
+func TestAuditPath_BypassVectors(t *testing.T) {
+	t.Parallel()
+
+	base, granted := bypassTree(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		mounts []string
+		denied string // part of the error, or "" when allowed
+	}{
+		{name: "canonical file", path: granted + "/file"},
+		{name: "canonical directory", path: granted + "/sub"},
+		{name: "dot-dot traversal", path: granted + "/../outside/secret", denied: "not canonical"},
+		{name: "dot-dot staying inside", path: granted + "/sub/../file", denied: "not canonical"},
+		{name: "dot component", path: granted + "/./file", denied: "not canonical"},
+		{name: "repeated separator", path: granted + "//file", denied: "not canonical"},
+		{name: "trailing separator", path: granted + "/sub/", denied: "not canonical"},
+		{name: "relative path", path: "granted/file", denied: "not canonical"},
+		{name: "NUL byte", path: granted + "/file\x00.txt", denied: "NUL byte"},
+		{name: "symlink escape", path: granted + "/escape", denied: "outside the granted tree"},
+		{name: "symlink directory escape", path: granted + "/deep/new", denied: "outside the granted tree"},
+		{name: "bind mount inside the tree", path: granted + "/sub", mounts: []string{"/", granted + "/sub"}, denied: "crosses mount point"},
+		{name: "mount above the tree", path: granted + "/file", mounts: []string{"/", base}},
+		{name: "tree is a mount point", path: granted + "/file", mounts: []string{"/", granted}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := auditPath(granted, tt.path, tt.mounts)
+			if tt.denied == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.denied)
+		})
+	}
+}
+
+func TestCheckFile_PathHardening(t *testing.T) {
+	t.Parallel()
+
+	base, granted := bypassTree(t)
+	checker := NewCapabilityChecker(map[string][]capabilities.Capability{
+		"file": {
+			{Kind: "fs", Pattern: "read:" + granted + "/**"},
+			{Kind: "fs", Pattern: "read:" + base + "/outside/deep/**"},
+		},
+	})
+	hardened := WithPathHardening(context.Background())
+	follow := WithSymlinkPolicy(hardened, capabilities.SymlinkFollow)
+
+	require.NoError(t, checker.CheckFile(hardened, "file", "read", granted+"/file"))
+
+	// Following links is allowed by the policy, but not by hardening
+	require.NoError(t, checker.CheckFile(WithSymlinkPolicy(context.Background(), capabilities.SymlinkFollow), "file", "read", granted+"/escape"))
+	err := checker.CheckFile(follow, "file", "read", granted+"/escape")
+	assert.ErrorContains(t, err, "path hardening")
+
+	// The link leads into another granted tree, but not the one it was reached through
+	err = checker.CheckFile(hardened, "file", "read", granted+"/deep/x")
+	assert.ErrorContains(t, err, "outside the granted tree")
+
+	// A link from outside that leads into the tree is fine for the restrict
+	// policy, but hardening wants the path granted as written
+	require.NoError(t, checker.CheckFile(context.Background(), "file", "read", base+"/into/file"))
+	assert.ErrorContains(t, checker.CheckFile(hardened, "file", "read", base+"/into/file"), "not granted as written")
+
+	assert.NoError(t, checker.CheckFile(context.Background(), "file", "read", granted+"/sub/../file"), "only hardening wants canonical paths")
+	assert.ErrorContains(t, checker.CheckFile(hardened, "file", "read", granted+"/sub/../file"), "not canonical")
+}
+
+func TestParseMountInfo(t *testing.T) {
+	t.Parallel()
+
+	mountinfo := `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+35 22 0:31 / /proc rw,nosuid shared:13 - proc proc rw
+41 22 8:1 /srv/data /mnt/with\040space rw,relatime shared:1 - ext4 /dev/sda1 rw
+`
+	mounts, err := parseMountInfo(strings.NewReader(mountinfo))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/", "/proc", "/mnt/with space"}, mounts)
+	assert.Equal(t, `a\b`, unescapeMountPath(`a\134b`))
+	assert.Equal(t, `a\9`, unescapeMountPath(`a\9`))
+}
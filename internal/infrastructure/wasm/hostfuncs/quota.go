@@ -0,0 +1,50 @@
+package hostfuncs
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/tetratelabs/wazero/api"
+)
+
+var quotaAbortKey = &contextKey{name: "quota_abort"}
+
+// WithQuotaAbort attaches the function cancelling the current observation to
+// the context. A host call refused by the plugin quotas in the context
+// cancels the observation with the refusal, which wraps
+// execution.ErrQuotaExceeded.
+func WithQuotaAbort(ctx context.Context, abort context.CancelCauseFunc) context.Context {
+	if abort == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, quotaAbortKey, abort)
+}
+
+// rationed wraps a request/response host function so calls count against
+// the host call quota of the plugin in the plugin quotas of ctx. The call
+// that would exceed it fails and cancels the observation, so the plugin does
+// not keep retrying refused calls.
+func rationed(fn api.GoModuleFunc) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		quotas, ok := execution.PluginQuotasFromContext(ctx)
+		if !ok {
+			fn(ctx, mod, stack)
+			return
+		}
+		plugin := getPluginName(ctx, mod)
+		if err := quotas.HostCall(plugin); err != nil {
+			slog.WarnContext(ctx, "stopping observation", "error", err, "plugin", plugin)
+			if abort, ok := ctx.Value(quotaAbortKey).(context.CancelCauseFunc); ok {
+				abort(err)
+			}
+			stack[0] = hostWriteResponse(ctx, mod, ErrorResponseWire{Error: &ErrorDetail{
+				Message: err.Error(),
+				Type:    "capability",
+				Code:    execution.CodeQuotaExceeded,
+			}})
+			return
+		}
+		fn(ctx, mod, stack)
+	}
+}
@@ -0,0 +1,45 @@
+package hostfuncs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/entities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero/api"
+)
+
+func TestRationed_HostCallQuotaStopsObservation(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	connect := rationed(func(ctx context.Context, mod api.Module, stack []uint64) {
+		calls++
+		stack[0] = hostWriteResponse(ctx, mod, TCPResponseWire{Connected: true})
+	})
+
+	quotas := execution.NewPluginQuotas(map[string]entities.PluginQuota{"tcp": {MaxHostCalls: 2}})
+	ctx, abort := context.WithCancelCause(context.Background())
+	defer abort(nil)
+	ctx = WithQuotaAbort(WithPluginName(execution.WithPluginQuotas(ctx, quotas), "tcp"), abort)
+
+	request := tcpRequest(t, "db.example.com", "5432")
+	mod := newFakeModule()
+	mod.call(t, ctx, connect, request)
+	mod.call(t, ctx, connect, request)
+	require.NoError(t, ctx.Err())
+
+	var resp TCPResponseWire
+	require.NoError(t, json.Unmarshal(mod.call(t, ctx, connect, request), &resp))
+	assert.Equal(t, 2, calls, "the call over the quota is not made")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, execution.CodeQuotaExceeded, resp.Error.Code)
+	assert.ErrorIs(t, context.Cause(ctx), execution.ErrQuotaExceeded)
+
+	other := WithPluginName(execution.WithPluginQuotas(context.Background(), quotas), "other")
+	mod.call(t, other, connect, request)
+	assert.Equal(t, 3, calls, "plugins without a quota are not limited")
+}
@@ -9,6 +9,16 @@ import (
 	"github.com/tetratelabs/wazero/api"
 )
 
+// intercepted wraps a request/response host function with a host call
+// quota, a circuit breaker, fault injection, cassette recording, traffic
+// metering and I/O timing. The quota applies first, so every call counts;
+// the breaker next, so injected and replayed timeouts open circuits too;
+// faults apply before recording, so they also hit replays; only calls that
+// reach the real function are metered and timed.
+func intercepted(function string, fn api.GoModuleFunc) api.GoModuleFunc {
+	return rationed(breakable(faultable(function, recordable(function, metered(timed(fn))))))
+}
+
 // RegisterHostFunctions registers all host functions with the wazero runtime
 func RegisterHostFunctions(ctx context.Context, runtime wazero.Runtime, version build.Info, caps map[string][]capabilities.Capability) error {
 	checker := NewCapabilityChecker(caps)
@@ -16,11 +26,15 @@ func RegisterHostFunctions(ctx context.Context, runtime wazero.Runtime, version
 	// Create host module "reglet_host"
 	builder := runtime.NewHostModuleBuilder("reglet_host")
 
+	// Request/response functions are wrapped with intercepted so the call
+	// context can ration them, fail them fast, inject faults into them,
+	// record or replay them, meter them and time them
+
 	// Register DNS lookup function
 	// Parameters: requestPacked (i64) - packed ptr+len of DNSRequestWire JSON
 	// Returns: responsePacked (i64) - packed ptr+len of DNSResponseWire JSON
 	builder.NewFunctionBuilder().
-		WithGoModuleFunction(api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+		WithGoModuleFunction(intercepted("dns_lookup", func(ctx context.Context, mod api.Module, stack []uint64) {
 			DNSLookup(ctx, mod, stack, checker)
 		}), []api.ValueType{api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).
 		Export("dns_lookup")
@@ -29,7 +43,7 @@ func RegisterHostFunctions(ctx context.Context, runtime wazero.Runtime, version
 	// Parameters: http_requestPacked (i64) - packed ptr+len of HTTPRequestWire JSON
 	// Returns: http_responsePacked (i64) - packed ptr+len of HTTPResponseWire JSON
 	builder.NewFunctionBuilder().
-		WithGoModuleFunction(api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+		WithGoModuleFunction(intercepted("http_request", func(ctx context.Context, mod api.Module, stack []uint64) {
 			HTTPRequest(ctx, mod, stack, checker, version) // Now passes version
 		}), []api.ValueType{api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).
 		Export("http_request")
@@ -38,7 +52,7 @@ func RegisterHostFunctions(ctx context.Context, runtime wazero.Runtime, version
 	// Parameters: tcp_requestPacked (i64) - packed ptr+len of TCPRequestWire JSON
 	// Returns: tcp_responsePacked (i64) - packed ptr+len of TCPResponseWire JSON
 	builder.NewFunctionBuilder().
-		WithGoModuleFunction(api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+		WithGoModuleFunction(intercepted("tcp_connect", func(ctx context.Context, mod api.Module, stack []uint64) {
 			TCPConnect(ctx, mod, stack, checker)
 		}), []api.ValueType{api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).
 		Export("tcp_connect")
@@ -47,7 +61,7 @@ func RegisterHostFunctions(ctx context.Context, runtime wazero.Runtime, version
 	// Parameters: smtp_requestPacked (i64) - packed ptr+len of SMTPRequestWire JSON
 	// Returns: smtp_responsePacked (i64) - packed ptr+len of SMTPResponseWire JSON
 	builder.NewFunctionBuilder().
-		WithGoModuleFunction(api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+		WithGoModuleFunction(intercepted("smtp_connect", func(ctx context.Context, mod api.Module, stack []uint64) {
 			SMTPConnect(ctx, mod, stack, checker)
 		}), []api.ValueType{api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).
 		Export("smtp_connect")
@@ -56,11 +70,69 @@ func RegisterHostFunctions(ctx context.Context, runtime wazero.Runtime, version
 	// Parameters: exec_requestPacked (i64) - packed ptr+len of ExecRequestWire JSON
 	// Returns: exec_responsePacked (i64) - packed ptr+len of ExecResponseWire JSON
 	builder.NewFunctionBuilder().
-		WithGoModuleFunction(api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+		WithGoModuleFunction(intercepted("exec_command", func(ctx context.Context, mod api.Module, stack []uint64) {
 			ExecCommand(ctx, mod, stack, checker)
 		}), []api.ValueType{api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).
 		Export("exec_command")
 
+	// Register file hash function
+	// Parameters: hash_requestPacked (i64) - packed ptr+len of HashRequestWire JSON
+	// Returns: hash_responsePacked (i64) - packed ptr+len of HashResponseWire JSON
+	builder.NewFunctionBuilder().
+		WithGoModuleFunction(intercepted("hash_compute", func(ctx context.Context, mod api.Module, stack []uint64) {
+			HashCompute(ctx, mod, stack, checker)
+		}), []api.ValueType{api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).
+		Export("hash_compute")
+
+	// Register file range read function
+	// Parameters: requestPacked (i64) - packed ptr+len of FileReadRangeRequestWire JSON
+	// Returns: responsePacked (i64) - packed ptr+len of a framed FileReadRangeResponseWire
+	builder.NewFunctionBuilder().
+		WithGoModuleFunction(intercepted("file_read_range", func(ctx context.Context, mod api.Module, stack []uint64) {
+			FileReadRange(ctx, mod, stack, checker)
+		}), []api.ValueType{api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).
+		Export("file_read_range")
+
+	// Register archive functions
+	// Parameters: requestPacked (i64) - packed ptr+len of ArchiveListRequestWire / ArchiveReadRequestWire JSON
+	// Returns: responsePacked (i64) - packed ptr+len of ArchiveListResponseWire / ArchiveReadResponseWire JSON
+	builder.NewFunctionBuilder().
+		WithGoModuleFunction(intercepted("archive_list", func(ctx context.Context, mod api.Module, stack []uint64) {
+			ArchiveList(ctx, mod, stack, checker)
+		}), []api.ValueType{api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).
+		Export("archive_list")
+	builder.NewFunctionBuilder().
+		WithGoModuleFunction(intercepted("archive_read_entry", func(ctx context.Context, mod api.Module, stack []uint64) {
+			ArchiveReadEntry(ctx, mod, stack, checker)
+		}), []api.ValueType{api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).
+		Export("archive_read_entry")
+
+	// Register plugin observation function for composite plugins. Not
+	// intercepted: the dependency's own host calls are
+	// Parameters: requestPacked (i64) - packed ptr+len of PluginObserveRequestWire JSON
+	// Returns: responsePacked (i64) - packed ptr+len of PluginObserveResponseWire JSON
+	builder.NewFunctionBuilder().
+		WithGoModuleFunction(api.GoModuleFunc(ObservePlugin), []api.ValueType{api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).
+		Export("observe_plugin")
+
+	// Register clock function
+	// Returns: unix_nanos (i64) - current time, fixed when the engine has a clock set
+	builder.NewFunctionBuilder().
+		WithGoModuleFunction(api.GoModuleFunc(TimeNow), []api.ValueType{}, []api.ValueType{api.ValueTypeI64}).
+		Export("time_now")
+
+	// Register execution context function
+	// Returns: contextPacked (i64) - packed ptr+len of ExecutionContextWire JSON
+	builder.NewFunctionBuilder().
+		WithGoModuleFunction(api.GoModuleFunc(ContextGet), []api.ValueType{}, []api.ValueType{api.ValueTypeI64}).
+		Export("context_get")
+
+	// Register scratch directory function
+	// Returns: scratchPacked (i64) - packed ptr+len of ScratchDirWire JSON
+	builder.NewFunctionBuilder().
+		WithGoModuleFunction(api.GoModuleFunc(ScratchDir), []api.ValueType{}, []api.ValueType{api.ValueTypeI64}).
+		Export("scratch_dir")
+
 	// Register logging function
 	builder.NewFunctionBuilder().
 		WithGoModuleFunction(api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
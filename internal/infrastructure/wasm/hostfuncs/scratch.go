@@ -0,0 +1,315 @@
+package hostfuncs
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/tetratelabs/wazero/api"
+	experimentalsys "github.com/tetratelabs/wazero/experimental/sys"
+	"github.com/tetratelabs/wazero/experimental/sysfs"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// ScratchGuestPath is where a plugin instance sees its scratch directory.
+const ScratchGuestPath = "/scratch"
+
+// DefaultScratchLimit is the size limit of a scratch directory when none is
+// configured.
+const DefaultScratchLimit int64 = 256 << 20
+
+// Scratch is a size-limited temporary directory private to one plugin
+// observation. The host directory is created on first use and removed by
+// Close. It is safe for concurrent use.
+type Scratch struct {
+	limit int64
+
+	mu     sync.Mutex
+	dir    string
+	root   experimentalsys.FS
+	used   int64
+	closed bool
+}
+
+// NewScratch creates a scratch directory holding at most limit bytes.
+func NewScratch(limit int64) *Scratch {
+	return &Scratch{limit: limit}
+}
+
+// Limit returns the number of bytes the scratch directory may hold.
+func (s *Scratch) Limit() int64 {
+	return s.limit
+}
+
+// Used returns the number of bytes currently stored in the scratch directory.
+func (s *Scratch) Used() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.used
+}
+
+// FS returns the filesystem to mount at ScratchGuestPath. Writes that would
+// exceed the limit fail with EIO, as WASI preview 1 has no ENOSPC. Links are
+// refused, so the guest cannot reach host files through the directory.
+func (s *Scratch) FS() experimentalsys.FS {
+	return &scratchFS{scratch: s}
+}
+
+// Close removes the scratch directory and everything in it.
+func (s *Scratch) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(s.dir)
+}
+
+// fs returns the host directory, creating it on first use.
+func (s *Scratch) fs() (experimentalsys.FS, experimentalsys.Errno) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, experimentalsys.EBADF
+	}
+	if s.root == nil {
+		dir, err := os.MkdirTemp("", "reglet-scratch-*")
+		if err != nil {
+			return nil, experimentalsys.UnwrapOSError(err)
+		}
+		s.dir = dir
+		s.root = sysfs.DirFS(dir)
+	}
+	return s.root, 0
+}
+
+// reserve accounts for n more bytes, or releases -n bytes when n is negative.
+func (s *Scratch) reserve(n int64) experimentalsys.Errno {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n > 0 && s.used+n > s.limit {
+		return experimentalsys.EIO
+	}
+	s.used = max(s.used+n, 0)
+	return 0
+}
+
+// regularSize returns the size of the regular file at path, or 0.
+func regularSize(root experimentalsys.FS, path string) int64 {
+	st, errno := root.Lstat(path)
+	if errno != 0 || !st.Mode.IsRegular() {
+		return 0
+	}
+	return st.Size
+}
+
+// scratchFS is the guest view of a Scratch.
+type scratchFS struct {
+	experimentalsys.UnimplementedFS
+	scratch *Scratch
+}
+
+func (f *scratchFS) OpenFile(path string, flag experimentalsys.Oflag, perm fs.FileMode) (experimentalsys.File, experimentalsys.Errno) {
+	root, errno := f.scratch.fs()
+	if errno != 0 {
+		return nil, errno
+	}
+	var truncated int64
+	if flag&experimentalsys.O_TRUNC != 0 {
+		truncated = regularSize(root, path)
+	}
+	file, errno := root.OpenFile(path, flag, perm)
+	if errno != 0 {
+		return nil, errno
+	}
+	f.scratch.reserve(-truncated)
+	return &scratchFile{File: file, scratch: f.scratch}, 0
+}
+
+func (f *scratchFS) Lstat(path string) (sys.Stat_t, experimentalsys.Errno) {
+	root, errno := f.scratch.fs()
+	if errno != 0 {
+		return sys.Stat_t{}, errno
+	}
+	return root.Lstat(path)
+}
+
+func (f *scratchFS) Stat(path string) (sys.Stat_t, experimentalsys.Errno) {
+	root, errno := f.scratch.fs()
+	if errno != 0 {
+		return sys.Stat_t{}, errno
+	}
+	return root.Stat(path)
+}
+
+func (f *scratchFS) Readlink(path string) (string, experimentalsys.Errno) {
+	root, errno := f.scratch.fs()
+	if errno != 0 {
+		return "", errno
+	}
+	return root.Readlink(path)
+}
+
+func (f *scratchFS) Mkdir(path string, perm fs.FileMode) experimentalsys.Errno {
+	root, errno := f.scratch.fs()
+	if errno != 0 {
+		return errno
+	}
+	return root.Mkdir(path, perm)
+}
+
+func (f *scratchFS) Chmod(path string, perm fs.FileMode) experimentalsys.Errno {
+	root, errno := f.scratch.fs()
+	if errno != 0 {
+		return errno
+	}
+	return root.Chmod(path, perm)
+}
+
+func (f *scratchFS) Rename(from, to string) experimentalsys.Errno {
+	root, errno := f.scratch.fs()
+	if errno != 0 {
+		return errno
+	}
+	replaced := regularSize(root, to)
+	if errno := root.Rename(from, to); errno != 0 {
+		return errno
+	}
+	f.scratch.reserve(-replaced)
+	return 0
+}
+
+func (f *scratchFS) Rmdir(path string) experimentalsys.Errno {
+	root, errno := f.scratch.fs()
+	if errno != 0 {
+		return errno
+	}
+	return root.Rmdir(path)
+}
+
+func (f *scratchFS) Unlink(path string) experimentalsys.Errno {
+	root, errno := f.scratch.fs()
+	if errno != 0 {
+		return errno
+	}
+	removed := regularSize(root, path)
+	if errno := root.Unlink(path); errno != 0 {
+		return errno
+	}
+	f.scratch.reserve(-removed)
+	return 0
+}
+
+// Link is refused: a hard link would be counted once but could be removed twice.
+func (f *scratchFS) Link(_, _ string) experimentalsys.Errno {
+	return experimentalsys.EPERM
+}
+
+// Symlink is refused: the host follows links, so one could point outside the directory.
+func (f *scratchFS) Symlink(_, _ string) experimentalsys.Errno {
+	return experimentalsys.EPERM
+}
+
+func (f *scratchFS) Utimens(path string, atim, mtim int64) experimentalsys.Errno {
+	root, errno := f.scratch.fs()
+	if errno != 0 {
+		return errno
+	}
+	return root.Utimens(path, atim, mtim)
+}
+
+// scratchFile accounts for the bytes writes add to a scratch file.
+type scratchFile struct {
+	experimentalsys.File
+	scratch *Scratch
+}
+
+func (f *scratchFile) Write(buf []byte) (int, experimentalsys.Errno) {
+	off := int64(-1)
+	if !f.IsAppend() {
+		var errno experimentalsys.Errno
+		if off, errno = f.File.Seek(0, io.SeekCurrent); errno != 0 {
+			return 0, errno
+		}
+	}
+	if errno := f.grow(off, int64(len(buf))); errno != 0 {
+		return 0, errno
+	}
+	return f.File.Write(buf)
+}
+
+func (f *scratchFile) Pwrite(buf []byte, off int64) (int, experimentalsys.Errno) {
+	if errno := f.grow(off, int64(len(buf))); errno != 0 {
+		return 0, errno
+	}
+	return f.File.Pwrite(buf, off)
+}
+
+func (f *scratchFile) Truncate(size int64) experimentalsys.Errno {
+	st, errno := f.File.Stat()
+	if errno != 0 {
+		return errno
+	}
+	delta := size - st.Size
+	if errno := f.scratch.reserve(delta); errno != 0 {
+		return errno
+	}
+	if errno := f.File.Truncate(size); errno != 0 {
+		f.scratch.reserve(-delta)
+		return errno
+	}
+	return 0
+}
+
+// grow reserves the bytes a write of n bytes at off (-1 = end of file) adds.
+func (f *scratchFile) grow(off, n int64) experimentalsys.Errno {
+	st, errno := f.File.Stat()
+	if errno != 0 {
+		return errno
+	}
+	if off < 0 {
+		off = st.Size
+	}
+	if end := off + n; end > st.Size {
+		return f.scratch.reserve(end - st.Size)
+	}
+	return 0
+}
+
+var scratchKey = &contextKey{name: "scratch"}
+
+// WithScratch attaches a scratch directory to the context. Plugin instances
+// created with the context mount it at ScratchGuestPath.
+func WithScratch(ctx context.Context, scratch *Scratch) context.Context {
+	if scratch == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, scratchKey, scratch)
+}
+
+// ScratchFromContext returns the scratch directory attached to the context, if any.
+func ScratchFromContext(ctx context.Context) (*Scratch, bool) {
+	scratch, ok := ctx.Value(scratchKey).(*Scratch)
+	return scratch, ok
+}
+
+// ScratchDir implements the `scratch_dir` host function.
+// It takes no parameters and returns a packed uint64 (ptr+len) pointing to a
+// JSON-encoded ScratchDirWire. Outside an observation, or when scratch
+// directories are disabled, the response carries an error.
+func ScratchDir(ctx context.Context, mod api.Module, stack []uint64) {
+	scratch, ok := ScratchFromContext(ctx)
+	if !ok {
+		stack[0] = hostWriteResponse(ctx, mod, ScratchDirWire{
+			Error: &ErrorDetail{Message: "no scratch directory is available to this call", Type: "capability"},
+		})
+		return
+	}
+	stack[0] = hostWriteResponse(ctx, mod, ScratchDirWire{
+		Path:       ScratchGuestPath,
+		LimitBytes: scratch.Limit(),
+	})
+}
@@ -0,0 +1,95 @@
+package hostfuncs
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	experimentalsys "github.com/tetratelabs/wazero/experimental/sys"
+)
+
+func TestScratch_Limit(t *testing.T) {
+	t.Parallel()
+
+	scratch := NewScratch(10)
+	t.Cleanup(func() { _ = scratch.Close() })
+	scratchFS := scratch.FS()
+
+	f, errno := scratchFS.OpenFile("a", experimentalsys.O_CREAT|experimentalsys.O_RDWR, 0o600)
+	require.Zero(t, errno)
+	n, errno := f.Write([]byte("12345678"))
+	require.Zero(t, errno)
+	assert.Equal(t, 8, n)
+	assert.Equal(t, int64(8), scratch.Used())
+
+	// Overwriting existing bytes does not grow the file
+	_, errno = f.Pwrite([]byte("ab"), 0)
+	require.Zero(t, errno)
+	assert.Equal(t, int64(8), scratch.Used())
+
+	_, errno = f.Write([]byte("123"))
+	assert.Equal(t, experimentalsys.EIO, errno)
+	assert.Equal(t, experimentalsys.EIO, f.Truncate(11))
+	require.Zero(t, f.Close())
+
+	// Removing a file frees its bytes
+	require.Zero(t, scratchFS.Unlink("a"))
+	assert.Equal(t, int64(0), scratch.Used())
+
+	f, errno = scratchFS.OpenFile("b", experimentalsys.O_CREAT|experimentalsys.O_WRONLY|experimentalsys.O_APPEND, 0o600)
+	require.Zero(t, errno)
+	_, errno = f.Write([]byte("0123456789"))
+	require.Zero(t, errno)
+	require.Zero(t, f.Close())
+
+	// Truncating on open frees the old contents
+	f, errno = scratchFS.OpenFile("b", experimentalsys.O_WRONLY|experimentalsys.O_TRUNC, 0o600)
+	require.Zero(t, errno)
+	assert.Equal(t, int64(0), scratch.Used())
+	require.Zero(t, f.Close())
+}
+
+func TestScratch_RefusesLinks(t *testing.T) {
+	t.Parallel()
+
+	scratch := NewScratch(DefaultScratchLimit)
+	t.Cleanup(func() { _ = scratch.Close() })
+
+	assert.Equal(t, experimentalsys.EPERM, scratch.FS().Symlink("/etc/passwd", "passwd"))
+	assert.Equal(t, experimentalsys.EPERM, scratch.FS().Link("a", "b"))
+}
+
+func TestScratch_Close(t *testing.T) {
+	t.Parallel()
+
+	// Nothing is created until the plugin uses the directory
+	unused := NewScratch(DefaultScratchLimit)
+	require.NoError(t, unused.Close())
+	assert.Empty(t, unused.dir)
+
+	scratch := NewScratch(DefaultScratchLimit)
+	require.Zero(t, scratch.FS().Mkdir("downloads", 0o700))
+	dir := scratch.dir
+	require.DirExists(t, dir)
+
+	require.NoError(t, scratch.Close())
+	_, err := os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	_, errno := scratch.FS().OpenFile("a", experimentalsys.O_CREAT|experimentalsys.O_RDWR, 0o600)
+	assert.Equal(t, experimentalsys.EBADF, errno)
+}
+
+func TestScratchFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := ScratchFromContext(context.Background())
+	assert.False(t, ok)
+
+	scratch := NewScratch(1024)
+	got, ok := ScratchFromContext(WithScratch(context.Background(), scratch))
+	require.True(t, ok)
+	assert.Same(t, scratch, got)
+}
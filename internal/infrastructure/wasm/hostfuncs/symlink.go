@@ -0,0 +1,52 @@
+package hostfuncs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+)
+
+var symlinkPolicyKey = &contextKey{name: "symlink_policy"}
+
+// WithSymlinkPolicy attaches a symlink policy to the context. Host file
+// operations made with the context check paths under it instead of the
+// default, capabilities.SymlinkRestrict.
+func WithSymlinkPolicy(ctx context.Context, policy capabilities.SymlinkPolicy) context.Context {
+	if policy == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, symlinkPolicyKey, policy)
+}
+
+// SymlinkPolicyFromContext returns the symlink policy attached to the
+// context, or capabilities.SymlinkRestrict.
+func SymlinkPolicyFromContext(ctx context.Context) capabilities.SymlinkPolicy {
+	if policy, ok := ctx.Value(symlinkPolicyKey).(capabilities.SymlinkPolicy); ok {
+		return policy
+	}
+	return capabilities.SymlinkRestrict
+}
+
+// CheckFile verifies if a plugin may perform operation ("read" or "write")
+// on the host file at path, handling symlinks in it as the symlink policy of
+// ctx says. With path hardening enabled in ctx, the path must also pass
+// AuditPath.
+func (c *CapabilityChecker) CheckFile(ctx context.Context, pluginName, operation, path string) error {
+	pattern := operation + ":" + path
+	pluginGrants, ok := c.grantedCapabilities[pluginName]
+	if !ok {
+		return fmt.Errorf("no capabilities granted to plugin %s", pluginName)
+	}
+
+	policy := capabilities.NewPolicyWithSymlinks(SymlinkPolicyFromContext(ctx))
+	if !policy.IsGranted(capabilities.Capability{Kind: "fs", Pattern: pattern}, pluginGrants, c.cwd) {
+		return fmt.Errorf("capability denied: fs:%s", pattern)
+	}
+	if PathHardeningFromContext(ctx) {
+		if err := c.auditGrantedPath(pluginGrants, operation, path); err != nil {
+			return fmt.Errorf("capability denied by path hardening: %w", err)
+		}
+	}
+	return nil
+}
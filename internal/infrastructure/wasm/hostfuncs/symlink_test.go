@@ -0,0 +1,44 @@
+package hostfuncs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymlinkPolicyFromContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assert.Equal(t, capabilities.SymlinkRestrict, SymlinkPolicyFromContext(ctx))
+	assert.Equal(t, capabilities.SymlinkRestrict, SymlinkPolicyFromContext(WithSymlinkPolicy(ctx, "")))
+	assert.Equal(t, capabilities.SymlinkNoFollow, SymlinkPolicyFromContext(WithSymlinkPolicy(ctx, capabilities.SymlinkNoFollow)))
+}
+
+func TestCheckFileRead_Symlinks(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	granted := filepath.Join(base, "granted")
+	require.NoError(t, os.Mkdir(granted, 0o750))
+	secret := filepath.Join(base, "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("out"), 0o600))
+	link := filepath.Join(granted, "link")
+	require.NoError(t, os.Symlink(secret, link))
+
+	checker := NewCapabilityChecker(map[string][]capabilities.Capability{
+		"file": {{Kind: "fs", Pattern: "read:" + granted + "/**"}},
+	})
+	ctx := context.Background()
+
+	detail := checkFileRead(ctx, checker, "file", link)
+	require.NotNil(t, detail, "restricted to the granted tree by default")
+	assert.Equal(t, "capability", detail.Type)
+	assert.NotNil(t, checkFileRead(WithSymlinkPolicy(ctx, capabilities.SymlinkNoFollow), checker, "file", link))
+	assert.Nil(t, checkFileRead(WithSymlinkPolicy(ctx, capabilities.SymlinkFollow), checker, "file", link))
+}
@@ -0,0 +1,127 @@
+package hostfuncs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// CodeEgressBudgetExceeded is the error code of calls refused because they
+// would exceed the run's egress budget.
+const CodeEgressBudgetExceeded = "egress_budget_exceeded"
+
+// ErrEgressBudgetExceeded is the cause a run is aborted with when plugins
+// would send more than its egress budget allows.
+var ErrEgressBudgetExceeded = errors.New("egress budget exceeded")
+
+// EgressBudget limits the request bytes plugins send through network host
+// functions during a run. Once a call would exceed it, the call fails and the
+// run is aborted. It is safe for concurrent use.
+type EgressBudget struct {
+	limit int64
+
+	mu   sync.Mutex
+	sent int64
+}
+
+// NewEgressBudget creates a budget of limit bytes.
+func NewEgressBudget(limit int64) *EgressBudget {
+	return &EgressBudget{limit: limit}
+}
+
+// Sent returns the bytes spent so far.
+func (b *EgressBudget) Sent() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sent
+}
+
+// spend accounts for n more bytes, or fails without accounting for them when
+// they do not fit.
+func (b *EgressBudget) spend(n int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.sent+n > b.limit {
+		return fmt.Errorf("%w: sending %d more bytes after %d would pass the limit of %d bytes", ErrEgressBudgetExceeded, n, b.sent, b.limit)
+	}
+	b.sent += n
+	return nil
+}
+
+// egressBinding is an egress budget with the function aborting its run.
+type egressBinding struct {
+	budget *EgressBudget
+	abort  context.CancelCauseFunc
+}
+
+var egressBudgetKey = &contextKey{name: "egress_budget"}
+
+// WithEgressBudget attaches an egress budget to the context. Network host
+// calls made with the context spend it; the call that would exceed it fails
+// and cancels the run through abort with ErrEgressBudgetExceeded.
+func WithEgressBudget(ctx context.Context, budget *EgressBudget, abort context.CancelCauseFunc) context.Context {
+	if budget == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, egressBudgetKey, egressBinding{budget: budget, abort: abort})
+}
+
+// EgressBudgetFromContext returns the egress budget attached to the context, if any.
+func EgressBudgetFromContext(ctx context.Context) (*EgressBudget, bool) {
+	binding, ok := ctx.Value(egressBudgetKey).(egressBinding)
+	return binding.budget, ok
+}
+
+// metered wraps a request/response host function so calls with a network
+// destination are counted, per plugin and destination, in the performance
+// recorder in ctx and spend the egress budget in ctx. Sizes are those of the
+// request and response messages exchanged with the plugin, not of the
+// protocol traffic they cause.
+func metered(fn api.GoModuleFunc) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		recorder, recording := execution.PerfRecorderFromContext(ctx)
+		binding, budgeted := ctx.Value(egressBudgetKey).(egressBinding)
+		if !recording && !budgeted {
+			fn(ctx, mod, stack)
+			return
+		}
+		header, _, err := hostReadMessage(mod, stack[0])
+		if err != nil {
+			fn(ctx, mod, stack) // Reports the malformed request itself
+			return
+		}
+		destination := callDestination(header)
+		if destination == "" {
+			fn(ctx, mod, stack)
+			return
+		}
+
+		_, sent := unpackPtrLen(stack[0])
+		if budgeted {
+			if err := binding.budget.spend(int64(sent)); err != nil {
+				slog.ErrorContext(ctx, "aborting run", "error", err, "plugin", getPluginName(ctx, mod), "destination", destination)
+				if binding.abort != nil {
+					binding.abort(err)
+				}
+				stack[0] = hostWriteResponse(ctx, mod, ErrorResponseWire{Error: &ErrorDetail{
+					Message: err.Error(),
+					Type:    "capability",
+					Code:    CodeEgressBudgetExceeded,
+				}})
+				return
+			}
+		}
+
+		fn(ctx, mod, stack)
+
+		if recording {
+			_, received := unpackPtrLen(stack[0])
+			recorder.RecordTraffic(getPluginName(ctx, mod), destination, int64(sent), int64(received))
+		}
+	}
+}
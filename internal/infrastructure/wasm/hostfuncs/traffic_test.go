@@ -0,0 +1,75 @@
+package hostfuncs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero/api"
+)
+
+func TestMetered_RecordsTraffic(t *testing.T) {
+	t.Parallel()
+
+	connect := metered(func(ctx context.Context, mod api.Module, stack []uint64) {
+		stack[0] = hostWriteResponse(ctx, mod, TCPResponseWire{Connected: true})
+	})
+	recorder := execution.NewPerfRecorder()
+	ctx := WithPluginName(execution.WithPerfRecorder(context.Background(), recorder), "tcp")
+
+	request := tcpRequest(t, "db.example.com", "5432")
+	response := newFakeModule().call(t, ctx, connect, request)
+
+	traffic := recorder.Report().Traffic
+	require.NotNil(t, traffic)
+	want := execution.TrafficStats{Requests: 1, BytesSent: int64(len(request)), BytesReceived: int64(len(response))}
+	want.Name = "tcp"
+	assert.Equal(t, []execution.TrafficStats{want}, traffic.Plugins)
+	want.Name = "db.example.com:5432"
+	assert.Equal(t, []execution.TrafficStats{want}, traffic.Destinations)
+}
+
+func TestMetered_EgressBudgetAbortsRun(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	connect := metered(func(ctx context.Context, mod api.Module, stack []uint64) {
+		calls++
+		stack[0] = hostWriteResponse(ctx, mod, TCPResponseWire{Connected: true})
+	})
+
+	request := tcpRequest(t, "db.example.com", "5432")
+	budget := NewEgressBudget(int64(len(request)) * 2)
+	ctx, abort := context.WithCancelCause(context.Background())
+	defer abort(nil)
+	ctx = WithEgressBudget(ctx, budget, abort)
+
+	mod := newFakeModule()
+	mod.call(t, ctx, connect, request)
+	mod.call(t, ctx, connect, request)
+	require.NoError(t, ctx.Err())
+
+	var resp TCPResponseWire
+	require.NoError(t, json.Unmarshal(mod.call(t, ctx, connect, request), &resp))
+	assert.Equal(t, 2, calls, "the call over budget is not made")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, CodeEgressBudgetExceeded, resp.Error.Code)
+	assert.ErrorIs(t, context.Cause(ctx), ErrEgressBudgetExceeded)
+	assert.Equal(t, int64(len(request))*2, budget.Sent())
+}
+
+func TestMetered_IgnoresCallsWithoutDestination(t *testing.T) {
+	t.Parallel()
+
+	exec := metered(func(ctx context.Context, mod api.Module, stack []uint64) {
+		stack[0] = hostWriteResponse(ctx, mod, ExecResponseWire{})
+	})
+	recorder := execution.NewPerfRecorder()
+	ctx := execution.WithPerfRecorder(context.Background(), recorder)
+	newFakeModule().call(t, ctx, exec, []byte(`{"command":"ls"}`))
+
+	assert.Nil(t, recorder.Report().Traffic)
+}
@@ -36,6 +36,34 @@ type (
 	ExecRequestWire = wireformat.ExecRequestWire
 	// ExecResponseWire is a re-export of wireformat.ExecResponseWire
 	ExecResponseWire = wireformat.ExecResponseWire
+	// HashRequestWire is a re-export of wireformat.HashRequestWire
+	HashRequestWire = wireformat.HashRequestWire
+	// HashResponseWire is a re-export of wireformat.HashResponseWire
+	HashResponseWire = wireformat.HashResponseWire
+	// FileReadRangeRequestWire is a re-export of wireformat.FileReadRangeRequestWire
+	FileReadRangeRequestWire = wireformat.FileReadRangeRequestWire
+	// FileReadRangeResponseWire is a re-export of wireformat.FileReadRangeResponseWire
+	FileReadRangeResponseWire = wireformat.FileReadRangeResponseWire
+	// ArchiveListRequestWire is a re-export of wireformat.ArchiveListRequestWire
+	ArchiveListRequestWire = wireformat.ArchiveListRequestWire
+	// ArchiveListResponseWire is a re-export of wireformat.ArchiveListResponseWire
+	ArchiveListResponseWire = wireformat.ArchiveListResponseWire
+	// ArchiveEntryWire is a re-export of wireformat.ArchiveEntryWire
+	ArchiveEntryWire = wireformat.ArchiveEntryWire
+	// ArchiveReadRequestWire is a re-export of wireformat.ArchiveReadRequestWire
+	ArchiveReadRequestWire = wireformat.ArchiveReadRequestWire
+	// ArchiveReadResponseWire is a re-export of wireformat.ArchiveReadResponseWire
+	ArchiveReadResponseWire = wireformat.ArchiveReadResponseWire
+	// PluginObserveRequestWire is a re-export of wireformat.PluginObserveRequestWire
+	PluginObserveRequestWire = wireformat.PluginObserveRequestWire
+	// PluginObserveResponseWire is a re-export of wireformat.PluginObserveResponseWire
+	PluginObserveResponseWire = wireformat.PluginObserveResponseWire
+	// ExecutionContextWire is a re-export of wireformat.ExecutionContextWire
+	ExecutionContextWire = wireformat.ExecutionContextWire
+	// ScratchDirWire is a re-export of wireformat.ScratchDirWire
+	ScratchDirWire = wireformat.ScratchDirWire
+	// ErrorResponseWire is a re-export of wireformat.ErrorResponseWire
+	ErrorResponseWire = wireformat.ErrorResponseWire
 	// ErrorDetail is a re-export of wireformat.ErrorDetail
 	ErrorDetail = wireformat.ErrorDetail
 	// MXRecordWire is a re-export of wireformat.MXRecordWire
@@ -90,6 +118,12 @@ func toErrorDetail(err error) *ErrorDetail {
 		// Consider other net.DNSError flags if relevant
 	}
 
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		detail.Type = "timeout"
+		detail.IsTimeout = true
+	}
+
 	// TODO: Expand this to unwrap and categorize errors more granularly for other types of errors
 	return detail
 }
@@ -101,22 +135,71 @@ func hostWriteResponse(ctx context.Context, mod api.Module, response interface{}
 		// Fallback to write a generic error if marshaling fails
 		errMsg := fmt.Sprintf("hostfuncs: failed to marshal response: %v", err)
 		slog.ErrorContext(ctx, errMsg)
-		errResponse := DNSResponseWire{ // Using DNS response as a generic error container for now
+		errResponse := ErrorResponseWire{
 			Error: &ErrorDetail{Message: errMsg, Type: "internal"},
 		}
 		data, _ = json.Marshal(errResponse) // Attempt to marshal fallback
 	}
 
+	return hostWriteBytes(ctx, mod, data)
+}
+
+// hostWriteFramed writes a JSON response followed by raw payload frames to WASM
+// memory and returns packed ptr+len. Payloads are passed as-is, without base64.
+func hostWriteFramed(ctx context.Context, mod api.Module, response interface{}, payloads ...wireformat.Frame) uint64 {
+	header, err := json.Marshal(response)
+	if err != nil {
+		return hostWriteResponse(ctx, mod, response) // Reuses the marshal error fallback
+	}
+
+	frames := append([]wireformat.Frame{{ContentType: wireformat.ContentTypeJSON, Data: header}}, payloads...)
+	data, err := wireformat.EncodeFrames(frames...)
+	if err != nil {
+		slog.ErrorContext(ctx, "hostfuncs: failed to encode framed response", "error", err)
+		return hostWriteResponse(ctx, mod, ErrorResponseWire{Error: &ErrorDetail{Message: err.Error(), Type: "internal"}})
+	}
+
+	return hostWriteBytes(ctx, mod, data)
+}
+
+// hostReadMessage reads a guest message from WASM memory. Framed messages are
+// split into the JSON header and any payload frames; plain JSON has no payloads.
+func hostReadMessage(mod api.Module, packed uint64) ([]byte, []wireformat.Frame, error) {
+	ptr, length := unpackPtrLen(packed)
+
+	data, ok := mod.Memory().Read(ptr, length)
+	if !ok {
+		return nil, nil, fmt.Errorf("hostfuncs: failed to read %d bytes at %d from Guest memory", length, ptr)
+	}
+	if !wireformat.IsFramed(data) {
+		return data, nil, nil
+	}
+
+	frames, err := wireformat.DecodeFrames(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(frames) == 0 || frames[0].ContentType != wireformat.ContentTypeJSON {
+		return nil, nil, fmt.Errorf("%w: first frame must be %s", wireformat.ErrMalformedFrame, wireformat.ContentTypeJSON)
+	}
+	return frames[0].Data, frames[1:], nil
+}
+
+// hostWriteBytes copies data into guest memory and returns packed ptr+len.
+func hostWriteBytes(ctx context.Context, mod api.Module, data []byte) uint64 {
 	// Allocate memory in Guest and copy data
 	results, err := mod.ExportedFunction("allocate").Call(ctx, uint64(len(data)))
-	if err != nil { // Check for error from Guest's allocate function
-		slog.ErrorContext(ctx, "hostfuncs: critical - failed to call guest allocate function", "error", err)
-		return 0 // Return 0, Host will likely panic or handle this
+	if err != nil || len(results) == 0 { // Check for error from Guest's allocate function
+		slog.ErrorContext(ctx, "hostfuncs: critical - failed to call guest allocate function", "error", err, "size", len(data))
+		return 0 // Guests treat a zero packed value as "no response"
 	}
 	ptr := uint32(results[0]) //nolint:gosec // G115: WASM32 pointers are always 32-bit
 
 	// Copy data to Guest memory
-	mod.Memory().Write(ptr, data)
+	if !mod.Memory().Write(ptr, data) {
+		slog.ErrorContext(ctx, "hostfuncs: failed to write response to guest memory", "ptr", ptr, "size", len(data))
+		return 0
+	}
 
 	// Return packed ptr+len
 	return packPtrLen(ptr, uint32(len(data))) //nolint:gosec // G115: WASM memory allocations are bounded to 4GB
@@ -1,7 +1,10 @@
 package hostfuncs
 
 import (
+	"bytes"
 	"testing"
+
+	"github.com/reglet-dev/reglet/wireformat"
 )
 
 // FuzzPackedPtrLen fuzzes pointer packing for overflow
@@ -34,3 +37,38 @@ func FuzzPackedPtrLen(f *testing.F) {
 		}
 	})
 }
+
+// FuzzDecodeFrames fuzzes framed buffer parsing with guest-controlled input
+func FuzzDecodeFrames(f *testing.F) {
+	valid, _ := wireformat.EncodeFrames(
+		wireformat.Frame{ContentType: wireformat.ContentTypeJSON, Data: []byte(`{"url":"https://example.com"}`)},
+		wireformat.Frame{ContentType: wireformat.ContentTypeOctetStream, Data: []byte{0, 1, 2, 0}},
+	)
+	f.Add(valid)
+	f.Add([]byte("RGLF"))
+	f.Add([]byte("RGLF\xff\xff"))
+	f.Add([]byte("RGLF\x00\x00\xff\xff\xff\xff"))
+	f.Add([]byte(`{"not":"framed"}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("PANIC on %q: %v", data, r)
+			}
+		}()
+
+		frames, err := wireformat.DecodeFrames(data)
+		if err != nil {
+			return
+		}
+
+		// Anything that decodes must re-encode to the same bytes
+		encoded, err := wireformat.EncodeFrames(frames...)
+		if err != nil {
+			t.Fatalf("re-encode failed: %v", err)
+		}
+		if !bytes.Equal(encoded, data) {
+			t.Errorf("round trip mismatch: input %q output %q", data, encoded)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package hostfuncs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/reglet-dev/reglet/wireformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero/api"
+)
+
+func TestHostWriteFramed_EncodingError(t *testing.T) {
+	t.Parallel()
+
+	httpRequest := func(ctx context.Context, mod api.Module, stack []uint64) {
+		stack[0] = hostWriteFramed(ctx, mod, HTTPResponseWire{StatusCode: 200},
+			wireformat.Frame{ContentType: strings.Repeat("x", math.MaxUint16+1), Data: []byte("body")})
+	}
+	response := newFakeModule().call(t, context.Background(), httpRequest, []byte(`{}`))
+
+	// The error envelope decodes as the caller's response, with no fields of
+	// another function's response
+	var decoded HTTPResponseWire
+	decoder := json.NewDecoder(bytes.NewReader(response))
+	decoder.DisallowUnknownFields()
+	require.NoError(t, decoder.Decode(&decoded))
+	require.NotNil(t, decoded.Error)
+	assert.Equal(t, "internal", decoded.Error.Type)
+	assert.Contains(t, decoded.Error.Message, "content type too long")
+	assert.Zero(t, decoded.StatusCode)
+}
@@ -4,18 +4,25 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
 	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
+	"github.com/reglet-dev/reglet/wireformat"
 	"github.com/tetratelabs/wazero"
 	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/experimental/sysfs"
+	"github.com/tetratelabs/wazero/sys"
 )
 
 // Plugin manages the lifecycle and execution of a compiled WASM module.
@@ -161,25 +168,64 @@ func (p *Plugin) extractFilesystemMounts() []fsMount {
 // createModuleConfig builds the wazero module configuration with necessary host functions.
 // It enables filesystem access, time, random, and logging.
 // stdout/stderr are automatically redacted to prevent secret leakage to logs.
-func (p *Plugin) createModuleConfig(_ context.Context) wazero.ModuleConfig {
+func (p *Plugin) createModuleConfig(ctx context.Context) wazero.ModuleConfig {
 	// Build filesystem mounts from capabilities
 	mounts := p.extractFilesystemMounts()
 	fsConfig := wazero.NewFSConfig()
 
+	cassette, recording := hostfuncs.CassetteFromContext(ctx)
+	privileged, escalate := privilegedReaderFromContext(ctx)
+	symlinks := hostfuncs.SymlinkPolicyFromContext(ctx)
+	harden := hostfuncs.PathHardeningFromContext(ctx)
 	for _, mount := range mounts {
-		if mount.readOnly {
+		switch {
+		case mount.readOnly && recording:
+			// Files are captured into, or served from, the cassette; recording
+			// reads the host under the symlink policy and hardening
+			host := newPolicyFS(p.name, mount.hostPath, symlinks, harden)
+			fsConfig = fsConfig.WithFSMount(cassette.FS(mount.hostPath, host), mount.guestPath)
+			slog.Debug("mounting recorded filesystem",
+				"plugin", p.name,
+				"path", mount.hostPath)
+		case mount.readOnly && escalate:
+			// Files this process may not read are read through sudo, once the
+			// symlink policy and hardening permit them
+			host := newPolicyFS(p.name, mount.hostPath, symlinks, harden)
+			fsConfig = fsConfig.WithFSMount(newPrivilegedFS(ctx, p.name, mount.hostPath, host, privileged), mount.guestPath)
+			slog.Debug("mounting privileged read-only filesystem",
+				"plugin", p.name,
+				"path", mount.hostPath)
+		case symlinks == capabilities.SymlinkFollow && !harden && mount.readOnly:
 			fsConfig = fsConfig.WithReadOnlyDirMount(mount.hostPath, mount.guestPath)
 			slog.Debug("mounting read-only filesystem",
 				"plugin", p.name,
 				"path", mount.hostPath)
-		} else {
+		case symlinks == capabilities.SymlinkFollow && !harden:
 			fsConfig = fsConfig.WithDirMount(mount.hostPath, mount.guestPath)
 			slog.Debug("mounting read-write filesystem",
 				"plugin", p.name,
 				"path", mount.hostPath)
+		case mount.readOnly:
+			// Links are only followed as the symlink policy and hardening allow
+			fsConfig = fsConfig.(sysfs.FSConfig).WithSysFSMount(&sysfs.ReadFS{FS: newSymlinkFS(p.name, mount.hostPath, symlinks, harden)}, mount.guestPath)
+			slog.Debug("mounting read-only filesystem",
+				"plugin", p.name,
+				"path", mount.hostPath,
+				"symlinks", symlinks)
+		default:
+			fsConfig = fsConfig.(sysfs.FSConfig).WithSysFSMount(newSymlinkFS(p.name, mount.hostPath, symlinks, harden), mount.guestPath)
+			slog.Debug("mounting read-write filesystem",
+				"plugin", p.name,
+				"path", mount.hostPath,
+				"symlinks", symlinks)
 		}
 	}
 
+	// The observation's scratch directory, if any, is always writable
+	if scratch, ok := hostfuncs.ScratchFromContext(ctx); ok {
+		fsConfig = fsConfig.(sysfs.FSConfig).WithSysFSMount(scratch.FS(), hostfuncs.ScratchGuestPath)
+	}
+
 	// Log when plugin has no filesystem access
 	if len(mounts) == 0 {
 		slog.Debug("plugin has no filesystem access",
@@ -196,16 +242,25 @@ func (p *Plugin) createModuleConfig(_ context.Context) wazero.ModuleConfig {
 		WithStderr(p.stderr).
 		WithStdout(p.stdout)
 
-	// Inject environment variables based on granted capabilities
-	if len(p.capabilities) > 0 {
-		config = p.injectEnvironmentVariables(config)
+	// A clock set by the engine replaces the system wall time (monotonic time is kept)
+	if clock, ok := hostfuncs.ClockFromContext(ctx); ok {
+		config = config.WithWalltime(func() (int64, int32) {
+			now := clock()
+			return now.Unix(), int32(now.Nanosecond()) //nolint:gosec // G115: nanoseconds are below 1e9
+		}, sys.ClockResolution(time.Microsecond))
 	}
 
+	// Inject environment variables based on granted capabilities
+	config = p.injectEnvironmentVariables(ctx, config)
+
 	return config
 }
 
-// injectEnvironmentVariables filters host environment variables based on granted capabilities
-func (p *Plugin) injectEnvironmentVariables(config wazero.ModuleConfig) wazero.ModuleConfig {
+// injectEnvironmentVariables filters host environment variables based on granted
+// capabilities, then applies the observation's env block from ctx. Its keys
+// need an env:observation:KEY grant, which matches no host variable, or a
+// grant of the host's variable.
+func (p *Plugin) injectEnvironmentVariables(ctx context.Context, config wazero.ModuleConfig) wazero.ModuleConfig {
 	// Get all granted env capabilities for this plugin
 	envCapabilities := []capabilities.Capability{}
 	for _, cap := range p.capabilities {
@@ -214,48 +269,177 @@ func (p *Plugin) injectEnvironmentVariables(config wazero.ModuleConfig) wazero.M
 		}
 	}
 
-	if len(envCapabilities) == 0 {
-		return config // No env capabilities granted
+	allowed := func(key string) (capabilities.Capability, bool) {
+		for _, cap := range envCapabilities {
+			if capabilities.MatchEnvironmentPattern(key, cap.Pattern) {
+				return cap, true
+			}
+		}
+		return capabilities.Capability{}, false
 	}
 
 	// Use frozen environment snapshot from runtime initialization
 	// This prevents runtime environment changes from leaking to plugins
-	hostEnv := p.frozenEnv
-
-	// Filter environment variables that match granted patterns
-	allowedEnv := []string{}
-	for _, envVar := range hostEnv {
-		// Parse "KEY=VALUE"
-		parts := strings.SplitN(envVar, "=", 2)
-		if len(parts) != 2 {
+	for _, envVar := range p.frozenEnv {
+		key, value, ok := strings.Cut(envVar, "=")
+		if !ok {
 			continue
 		}
-		key := parts[0]
-
-		// Check if this key is allowed by any granted capability
-		for _, cap := range envCapabilities {
-			if capabilities.MatchEnvironmentPattern(key, cap.Pattern) {
-				allowedEnv = append(allowedEnv, envVar)
-				slog.Debug("injecting environment variable",
-					"plugin", p.name,
-					"key", key,
-					"capability", cap.String())
-				break
-			}
+		if cap, ok := allowed(key); ok {
+			config = config.WithEnv(key, value)
+			slog.Debug("injecting environment variable",
+				"plugin", p.name,
+				"key", key,
+				"capability", cap.String())
 		}
 	}
 
-	// Inject allowed variables
-	for _, envVar := range allowedEnv {
-		parts := strings.SplitN(envVar, "=", 2)
-		if len(parts) == 2 {
-			config = config.WithEnv(parts[0], parts[1])
+	// Observation env overrides host values for the same key
+	obsEnv := ObservationEnvFromContext(ctx)
+	keys := make([]string, 0, len(obsEnv))
+	for key := range obsEnv {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !capabilities.ObservationEnvGranted(key, envCapabilities) {
+			slog.WarnContext(ctx, "observation env variable not granted, skipping",
+				"plugin", p.name,
+				"key", key)
+			continue
 		}
+		config = config.WithEnv(key, obsEnv[key])
 	}
 
 	return config
 }
 
+type observationEnvKey struct{}
+
+// WithObservationEnv attaches an observation's env block to ctx. Plugin
+// instances created with the context receive the variables their env
+// capabilities allow.
+func WithObservationEnv(ctx context.Context, env map[string]string) context.Context {
+	if len(env) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, observationEnvKey{}, env)
+}
+
+// ObservationEnvFromContext returns the observation env attached to ctx, if any.
+func ObservationEnvFromContext(ctx context.Context) map[string]string {
+	env, _ := ctx.Value(observationEnvKey{}).(map[string]string)
+	return env
+}
+
+// WithClock attaches a clock to ctx. Plugin instances created with the
+// context read it as their wall time and from the time_now host function.
+func WithClock(ctx context.Context, clock func() time.Time) context.Context {
+	return hostfuncs.WithClock(ctx, clock)
+}
+
+// WithCassette attaches a cassette to ctx. Host function calls and read-only
+// filesystem mounts of plugin instances created with the context are
+// recorded into, or replayed from, it.
+func WithCassette(ctx context.Context, cassette *hostfuncs.Cassette) context.Context {
+	return hostfuncs.WithCassette(ctx, cassette)
+}
+
+// WithFaultInjector attaches a fault injector to ctx. Host function calls of
+// plugin instances created with the context may fail with injected faults.
+func WithFaultInjector(ctx context.Context, injector *hostfuncs.FaultInjector) context.Context {
+	return hostfuncs.WithFaultInjector(ctx, injector)
+}
+
+// WithDNSCache attaches a DNS cache to ctx. DNS lookups of plugin instances
+// created with the context are answered from it.
+func WithDNSCache(ctx context.Context, cache hostfuncs.DNSCache) context.Context {
+	return hostfuncs.WithDNSCache(ctx, cache)
+}
+
+// WithHTTPPool attaches a connection pool to ctx. HTTP requests of plugin
+// instances created with the context reuse its connections.
+func WithHTTPPool(ctx context.Context, pool *hostfuncs.HTTPPool) context.Context {
+	return hostfuncs.WithHTTPPool(ctx, pool)
+}
+
+// WithFreshConnections makes HTTP requests of plugin instances created with
+// ctx bypass the connection pool and response cache, and open a new
+// connection each.
+func WithFreshConnections(ctx context.Context) context.Context {
+	return hostfuncs.WithFreshConnections(ctx)
+}
+
+// WithHTTPCache attaches a response cache to ctx. GET requests of plugin
+// instances created with the context are answered from it while fresh.
+func WithHTTPCache(ctx context.Context, cache *hostfuncs.HTTPCache) context.Context {
+	return hostfuncs.WithHTTPCache(ctx, cache)
+}
+
+// WithCircuitBreaker attaches a circuit breaker to ctx. Network calls of
+// plugin instances created with the context fail fast to destinations that
+// keep timing out.
+func WithCircuitBreaker(ctx context.Context, breaker *hostfuncs.CircuitBreaker) context.Context {
+	return hostfuncs.WithCircuitBreaker(ctx, breaker)
+}
+
+// WithEgressBudget attaches an egress budget to ctx. Network calls of plugin
+// instances created with the context spend it; once one would exceed it,
+// abort is called with hostfuncs.ErrEgressBudgetExceeded.
+func WithEgressBudget(ctx context.Context, budget *hostfuncs.EgressBudget, abort context.CancelCauseFunc) context.Context {
+	return hostfuncs.WithEgressBudget(ctx, budget, abort)
+}
+
+// WithSymlinkPolicy attaches a symlink policy to ctx. Plugin instances
+// created with the context follow links in their filesystem mounts and host
+// file calls only as it allows (default: restrict to the granted tree).
+func WithSymlinkPolicy(ctx context.Context, policy capabilities.SymlinkPolicy) context.Context {
+	return hostfuncs.WithSymlinkPolicy(ctx, policy)
+}
+
+// WithPathHardening makes plugin instances created with ctx deny file
+// access through paths that are not canonical, or that resolve outside their
+// granted tree or across a mount point in it.
+func WithPathHardening(ctx context.Context) context.Context {
+	return hostfuncs.WithPathHardening(ctx)
+}
+
+// WithQuotaAbort attaches the function cancelling the current observation to
+// ctx. A host call of a plugin instance created with the context that would
+// exceed the plugin's host call quota cancels the observation through it.
+func WithQuotaAbort(ctx context.Context, abort context.CancelCauseFunc) context.Context {
+	return hostfuncs.WithQuotaAbort(ctx, abort)
+}
+
+// WithHTTPDebugLog attaches a debug log to ctx. HTTP requests of plugin
+// instances created with the context add a sanitized summary to it.
+func WithHTTPDebugLog(ctx context.Context, log *hostfuncs.HTTPDebugLog) context.Context {
+	return hostfuncs.WithHTTPDebugLog(ctx, log)
+}
+
+type scratchLimitKey struct{}
+
+// WithScratchLimit sets the size limit of the scratch directory each
+// observation run with ctx gets. A negative limit disables scratch
+// directories; without one, hostfuncs.DefaultScratchLimit applies.
+func WithScratchLimit(ctx context.Context, limit int64) context.Context {
+	return context.WithValue(ctx, scratchLimitKey{}, limit)
+}
+
+// scratchLimitFromContext returns the scratch size limit for ctx.
+func scratchLimitFromContext(ctx context.Context) int64 {
+	if limit, ok := ctx.Value(scratchLimitKey{}).(int64); ok && limit != 0 {
+		return limit
+	}
+	return hostfuncs.DefaultScratchLimit
+}
+
+// WithIOTimer attaches a timer to ctx. Network and exec host function calls
+// of plugin instances created with the context add their duration to it.
+func WithIOTimer(ctx context.Context, timer *hostfuncs.IOTimer) context.Context {
+	return hostfuncs.WithIOTimer(ctx, timer)
+}
+
 // createInstance instantiates the WASM module with a fresh memory environment.
 // It ensures thread safety by providing isolated memory for each execution.
 func (p *Plugin) createInstance(ctx context.Context) (api.Module, error) {
@@ -283,6 +467,16 @@ func (p *Plugin) createInstance(ctx context.Context) (api.Module, error) {
 	return instance, nil
 }
 
+// Instantiate creates a fresh instance and closes it again. Every observation
+// pays this cost before the plugin runs; it exists to measure it.
+func (p *Plugin) Instantiate(ctx context.Context) error {
+	instance, err := p.createInstance(hostfuncs.WithPluginName(ctx, p.name))
+	if err != nil {
+		return err
+	}
+	return instance.Close(ctx)
+}
+
 // Describe executes the plugin's 'describe' function to retrieve metadata.
 func (p *Plugin) Describe(ctx context.Context) (*PluginInfo, error) {
 	// Wrap context with plugin name for host functions
@@ -403,16 +597,79 @@ func (p *Plugin) Schema(ctx context.Context) (*ConfigSchema, error) {
 	return schema, nil
 }
 
+// ErrNoSelfTest is returned by SelfTest for plugins without a self-test.
+var ErrNoSelfTest = errors.New("plugin has no self-test")
+
+// SelfTest executes the plugin's optional 'self_test' function, which checks
+// the plugin itself without touching the host and returns evidence like
+// observe(). It runs without capabilities.
+func (p *Plugin) SelfTest(ctx context.Context) (*Evidence, error) {
+	ctx = hostfuncs.WithPluginName(ctx, p.name)
+
+	instance, err := p.createInstance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = instance.Close(ctx)
+	}()
+
+	selfTestFn := instance.ExportedFunction("self_test")
+	if selfTestFn == nil {
+		return nil, ErrNoSelfTest
+	}
+
+	results, err := selfTestFn.Call(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call self_test(): %w", err)
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("self_test() returned no results")
+	}
+
+	packed := results[0]
+	ptr := uint32(packed >> 32)         //nolint:gosec // G115: WASM32 pointers are always 32-bit
+	size := uint32(packed & 0xFFFFFFFF) //nolint:gosec // G115: WASM32 lengths are always 32-bit
+
+	if ptr == 0 || size == 0 {
+		return nil, fmt.Errorf("self_test() returned null pointer or zero length")
+	}
+
+	var evidence Evidence
+	if err := p.readJSON(ctx, instance, ptr, size, &evidence); err != nil {
+		return nil, fmt.Errorf("failed to read self_test() result: %w", err)
+	}
+	// The Go SDK exports self_test() for every plugin
+	if evidence.Error != nil && evidence.Error.Code == wireformat.CodeNoSelfTest {
+		return nil, ErrNoSelfTest
+	}
+	return &evidence, nil
+}
+
 // Observe executes the main validation logic of the plugin.
 func (p *Plugin) Observe(ctx context.Context, cfg Config) (*PluginObservationResult, error) {
 	// Wrap context with plugin name so host functions can access it
 	ctx = hostfuncs.WithPluginName(ctx, p.name)
 
+	// Give the observation a private scratch directory, removed when it ends
+	if limit := scratchLimitFromContext(ctx); limit > 0 {
+		scratch := hostfuncs.NewScratch(limit)
+		defer func() {
+			if err := scratch.Close(); err != nil {
+				slog.WarnContext(ctx, "failed to remove scratch directory", "plugin", p.name, "error", err)
+			}
+		}()
+		ctx = hostfuncs.WithScratch(ctx, scratch)
+	}
+
 	// Create FRESH instance for this call - ensures thread safety
+	start := time.Now()
 	instance, err := p.createInstance(ctx)
 	if err != nil {
 		return nil, err
 	}
+	instantiation := time.Since(start)
 	// CRITICAL: Always close instance when done
 	defer func() {
 		_ = instance.Close(ctx) // Best-effort cleanup
@@ -424,16 +681,19 @@ func (p *Plugin) Observe(ctx context.Context, cfg Config) (*PluginObservationRes
 		return nil, fmt.Errorf("plugin %s does not export observe() function", p.name)
 	}
 
-	// Marshal config to JSON
-	configData, err := json.Marshal(cfg.Values)
+	// Marshal config to JSON into a pooled buffer and copy it to WASM memory
+	var configPtr, configLen uint32
+	var writeErr error
+	err = execution.EncodeJSON(cfg.Values, func(configData []byte) error {
+		configLen = uint32(len(configData)) //nolint:gosec // G115: WASM32 lengths are always 32-bit
+		configPtr, writeErr = p.writeToMemory(ctx, instance, configData)
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal config: %w", err)
 	}
-
-	// Write config to WASM memory
-	configPtr, err := p.writeToMemory(ctx, instance, configData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to write config to WASM memory: %w", err)
+	if writeErr != nil {
+		return nil, fmt.Errorf("failed to write config to WASM memory: %w", writeErr)
 	}
 
 	// CRITICAL: Ensure config memory is always deallocated, even on error
@@ -446,12 +706,12 @@ func (p *Plugin) Observe(ctx context.Context, cfg Config) (*PluginObservationRes
 		deallocateFn := instance.ExportedFunction("deallocate")
 		if deallocateFn != nil {
 			//nolint:errcheck,gosec // G104: Deallocation is best-effort cleanup
-			deallocateFn.Call(ctx, uint64(configPtr), uint64(len(configData)))
+			deallocateFn.Call(ctx, uint64(configPtr), uint64(configLen))
 		}
 	}()
 
 	// Call observe(configPtr, configLen)
-	results, err := observeFn.Call(ctx, uint64(configPtr), uint64(len(configData)))
+	results, err := observeFn.Call(ctx, uint64(configPtr), uint64(configLen))
 	if err != nil {
 		return nil, fmt.Errorf("failed to call observe(): %w", err)
 	}
@@ -469,16 +729,10 @@ func (p *Plugin) Observe(ctx context.Context, cfg Config) (*PluginObservationRes
 		return nil, fmt.Errorf("observe() returned null pointer or zero length")
 	}
 
-	// Read EXACT size
-	resultData, err := p.readString(ctx, instance, resultPtr, resultSize)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read observe() result: %w", err)
-	}
-
-	// Parse JSON result directly into internal/wasm/types.Evidence
+	// Parse JSON result directly from WASM memory into internal/wasm/types.Evidence
 	var hostEvidence Evidence
-	if err := json.Unmarshal(resultData, &hostEvidence); err != nil {
-		return nil, fmt.Errorf("failed to parse observe() result into internal/wasm/types.Evidence: %w", err)
+	if err := p.readJSON(ctx, instance, resultPtr, resultSize, &hostEvidence); err != nil {
+		return nil, fmt.Errorf("failed to read observe() result into internal/wasm/types.Evidence: %w", err)
 	}
 
 	// Construct and return PluginObservationResult
@@ -486,8 +740,9 @@ func (p *Plugin) Observe(ctx context.Context, cfg Config) (*PluginObservationRes
 	// PluginObservationResult.Error represents WASM execution errors (panics, plugin failures)
 	// Don't propagate Evidence.Error to PluginObservationResult.Error - they serve different purposes
 	return &PluginObservationResult{
-			Evidence: &hostEvidence,
-			Error:    nil, // Plugin executed successfully, errors are in Evidence
+			Evidence:      &hostEvidence,
+			Error:         nil, // Plugin executed successfully, errors are in Evidence
+			Instantiation: instantiation,
 		},
 		nil
 }
@@ -502,6 +757,27 @@ func (p *Plugin) Close() error {
 
 // readString safely reads a byte slice from WASM memory and deallocates it.
 func (p *Plugin) readString(ctx context.Context, instance api.Module, ptr uint32, size uint32) ([]byte, error) {
+	var result []byte
+	err := p.viewMemory(ctx, instance, ptr, size, func(data []byte) error {
+		// Copy to our own buffer
+		result = make([]byte, size)
+		copy(result, data)
+		return nil
+	})
+	return result, err
+}
+
+// readJSON decodes a JSON value straight out of WASM memory and deallocates
+// it, skipping the intermediate copy readString makes.
+func (p *Plugin) readJSON(ctx context.Context, instance api.Module, ptr uint32, size uint32, v any) error {
+	return p.viewMemory(ctx, instance, ptr, size, func(data []byte) error {
+		return json.Unmarshal(data, v)
+	})
+}
+
+// viewMemory passes fn a view of WASM memory, valid only until fn returns,
+// and deallocates the block afterwards.
+func (p *Plugin) viewMemory(ctx context.Context, instance api.Module, ptr uint32, size uint32, fn func(data []byte) error) error {
 	// CRITICAL: Ensure memory is always deallocated, even on error
 	defer func() {
 		// Prevent cleanup panic from clobbering an existing panic
@@ -519,14 +795,9 @@ func (p *Plugin) readString(ctx context.Context, instance api.Module, ptr uint32
 	// Read EXACT size (no more guessing!)
 	data, ok := instance.Memory().Read(ptr, size)
 	if !ok {
-		return nil, fmt.Errorf("failed to read memory at offset %d", ptr)
+		return fmt.Errorf("failed to read memory at offset %d", ptr)
 	}
-
-	// Copy to our own buffer
-	result := make([]byte, size)
-	copy(result, data)
-
-	return result, nil
+	return fn(data)
 }
 
 // writeToMemory allocates WASM memory and copies data into it.
@@ -568,6 +839,12 @@ func (p *Plugin) writeToMemory(ctx context.Context, instance api.Module, data []
 	return ptr, nil
 }
 
+// ParsePluginInfo decodes plugin metadata JSON as returned by describe().
+// External process plugins return the same document.
+func ParsePluginInfo(data []byte) (*PluginInfo, error) {
+	return parsePluginInfo(data)
+}
+
 // parsePluginInfo decodes the JSON metadata returned by the plugin.
 func parsePluginInfo(data []byte) (*PluginInfo, error) {
 	var raw map[string]interface{}
@@ -590,6 +867,31 @@ func parsePluginInfo(data []byte) (*PluginInfo, error) {
 		info.Description = description
 	}
 
+	// Build metadata reported by plugins built with the Go SDK
+	for key, field := range map[string]*string{
+		"sdk_version":      &info.SDKVersion,
+		"min_host_version": &info.MinHostVersion,
+		"commit":           &info.Commit,
+		"build_time":       &info.BuildTime,
+		"go_version":       &info.GoVersion,
+	} {
+		if value, ok := raw[key].(string); ok {
+			*field = value
+		}
+	}
+
+	if dependencies, ok := raw["dependencies"].([]interface{}); ok {
+		for _, dependency := range dependencies {
+			if name, ok := dependency.(string); ok {
+				info.Dependencies = append(info.Dependencies, name)
+			}
+		}
+	}
+
+	if sideEffects, ok := raw["side_effects"].(bool); ok {
+		info.SideEffects = sideEffects
+	}
+
 	// Parse capabilities array
 	if caps, ok := raw["capabilities"].([]interface{}); ok {
 		for _, capRaw := range caps {
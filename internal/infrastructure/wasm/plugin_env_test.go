@@ -1,6 +1,7 @@
 package wasm
 
 import (
+	"context"
 	"testing"
 
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
@@ -71,3 +72,12 @@ func TestMatchEnvPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestObservationEnvContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, ObservationEnvFromContext(ctx))
+	assert.Equal(t, ctx, WithObservationEnv(ctx, nil), "empty env should not wrap the context")
+
+	env := map[string]string{"LANG": "C"}
+	assert.Equal(t, env, ObservationEnvFromContext(WithObservationEnv(ctx, env)))
+}
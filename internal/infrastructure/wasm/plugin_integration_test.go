@@ -11,6 +11,7 @@ import (
 
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
 	"github.com/reglet-dev/reglet/internal/infrastructure/build"
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -85,6 +86,22 @@ func TestLoadFilePlugin(t *testing.T) {
 	assert.Equal(t, "file", plugin.Name())
 }
 
+// TestFilePlugin_SelfTest_NotExported tests that plugins without a
+// self_test() export report ErrNoSelfTest
+func TestFilePlugin_SelfTest_NotExported(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	runtime, err := NewRuntime(ctx, build.Get())
+	require.NoError(t, err)
+	defer runtime.Close(ctx)
+
+	plugin, err := runtime.LoadPlugin(ctx, "file", getWasmBytes(t, "file"))
+	require.NoError(t, err)
+
+	_, err = plugin.SelfTest(ctx)
+	assert.ErrorIs(t, err, ErrNoSelfTest)
+}
+
 // TestFilePlugin_Describe tests calling the describe function
 // Uses Go 1.24+ //go:wasmexport for function exports
 func TestFilePlugin_Describe(t *testing.T) {
@@ -235,6 +252,46 @@ func TestFilePlugin_Observe_FileExists(t *testing.T) {
 	require.True(t, ok, "gid should be present")
 }
 
+// TestFilePlugin_Observe_TypedEvidence checks the file plugin's evidence
+// against the shared typed struct: decoding and re-encoding must be lossless,
+// so the schema covers every field the plugin reports.
+func TestFilePlugin_Observe_TypedEvidence(t *testing.T) {
+	t.Parallel()
+	wasmBytes := getWasmBytes(t, "file")
+
+	path := filepath.Join(t.TempDir(), "typed.txt")
+	require.NoError(t, os.WriteFile(path, []byte("typed"), 0o600))
+
+	caps := map[string][]capabilities.Capability{
+		"file": {{Kind: "fs", Pattern: "read:/**"}},
+	}
+	ctx := context.Background()
+	runtime, err := NewRuntimeWithCapabilities(ctx, build.Get(), caps, nil, 0)
+	require.NoError(t, err)
+	defer runtime.Close(ctx)
+
+	plugin, err := runtime.LoadPlugin(ctx, "file", wasmBytes)
+	require.NoError(t, err)
+
+	result, err := plugin.Observe(ctx, Config{Values: map[string]interface{}{"path": path, "hash": true}})
+	require.NoError(t, err)
+	require.NotNil(t, result.Evidence)
+
+	file, err := evidence.Decode[evidence.File](result.Evidence.Data)
+	require.NoError(t, err)
+	assert.Equal(t, path, file.Path)
+	assert.True(t, file.Exists)
+	require.NotNil(t, file.Size)
+	assert.Equal(t, int64(5), *file.Size)
+	require.NotNil(t, file.SHA256)
+
+	original, err := json.Marshal(result.Evidence.Data)
+	require.NoError(t, err)
+	roundTrip, err := json.Marshal(file)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(original), string(roundTrip))
+}
+
 // TestFilePlugin_Observe_Symlink tests checking a symlink
 func TestFilePlugin_Observe_Symlink(t *testing.T) {
 	t.Parallel()
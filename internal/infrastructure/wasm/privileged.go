@@ -0,0 +1,180 @@
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// maxPrivilegedReadBytes bounds the size of a file read through sudo.
+	maxPrivilegedReadBytes = 32 << 20
+
+	// privilegedReadTimeout bounds a sudo read, which must never prompt.
+	privilegedReadTimeout = 30 * time.Second
+)
+
+// PrivilegedReadCommand is the hidden reglet command SudoRead runs as root.
+// It reads one file with ReadNoFollow and writes it to stdout.
+const PrivilegedReadCommand = "privileged-read"
+
+// PrivilegedReader reads a file the reglet process is not permitted to read.
+// path is canonical: it holds no symlinks, and a reader must not follow any.
+type PrivilegedReader func(ctx context.Context, path string) ([]byte, error)
+
+// SudoRead reads path with "sudo -n reglet privileged-read <path>", which
+// refuses symlinks in every component of the path and anything but a regular
+// file. It never prompts for a password, so a sudoers rule can grant exactly
+// the files privileged observations need:
+//
+//	reglet ALL=(root) NOPASSWD: /usr/local/bin/reglet privileged-read /etc/shadow
+func SudoRead(ctx context.Context, path string) ([]byte, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("sudo read of %s failed: %w", path, err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, privilegedReadTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	//nolint:gosec // G204: the path is absolute and passed as a single argument
+	cmd := exec.CommandContext(ctx, "sudo", "-n", exe, PrivilegedReadCommand, path)
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("sudo read of %s failed: %w", path, err)
+	}
+
+	data, readErr := io.ReadAll(io.LimitReader(stdout, maxPrivilegedReadBytes+1))
+	if len(data) > maxPrivilegedReadBytes {
+		cancel()
+		_ = cmd.Wait()
+		return nil, fmt.Errorf("%s is larger than %d bytes", path, maxPrivilegedReadBytes)
+	}
+	if err := cmd.Wait(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("sudo read of %s failed: %s: %w", path, msg, fs.ErrPermission)
+	}
+	if readErr != nil {
+		return nil, fmt.Errorf("sudo read of %s failed: %w", path, readErr)
+	}
+	return data, nil
+}
+
+type privilegedReaderKey struct{}
+
+// WithPrivilegedReader attaches reader to ctx. Plugin instances created with
+// the context read regular files of their read-only mounts through it when
+// the reglet process is denied access to them.
+func WithPrivilegedReader(ctx context.Context, reader PrivilegedReader) context.Context {
+	if reader == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, privilegedReaderKey{}, reader)
+}
+
+// privilegedReaderFromContext returns the privileged reader attached to ctx, if any.
+func privilegedReaderFromContext(ctx context.Context) (PrivilegedReader, bool) {
+	reader, ok := ctx.Value(privilegedReaderKey{}).(PrivilegedReader)
+	return reader, ok
+}
+
+// privilegedFS serves a host directory, reading the regular files the reglet
+// process may not open through a privileged reader. Metadata always comes
+// from the unprivileged process, and nothing but denied reads is escalated.
+// Paths the symlink policy or hardening reject are never escalated.
+type privilegedFS struct {
+	ctx    context.Context
+	fsys   fs.FS
+	check  func(name string) error
+	read   PrivilegedReader
+	plugin string
+	root   string
+}
+
+// newPrivilegedFS serves the host directory root of a plugin under the
+// checks of host, its policy-checked view.
+func newPrivilegedFS(ctx context.Context, plugin, root string, host *policyFS, read PrivilegedReader) *privilegedFS {
+	return &privilegedFS{ctx: ctx, fsys: host.fsys, check: host.check, read: read, plugin: plugin, root: root}
+}
+
+// Open opens name, falling back to the privileged reader when a regular
+// file the symlink policy permits cannot be opened for lack of permission.
+// The reader gets the canonical path of the file inside the mount. Failed
+// privileged reads are logged and return the original permission error.
+func (p *privilegedFS) Open(name string) (fs.File, error) {
+	if p.check != nil {
+		if err := p.check(name); err != nil {
+			return nil, err
+		}
+	}
+	f, err := p.fsys.Open(name)
+	if err == nil || !errors.Is(err, fs.ErrPermission) {
+		return f, err
+	}
+
+	hostPath, ok := p.canonical(name)
+	if !ok {
+		return nil, err
+	}
+	info, statErr := os.Lstat(hostPath)
+	if statErr != nil || !info.Mode().IsRegular() {
+		return nil, err
+	}
+
+	data, readErr := p.read(p.ctx, hostPath)
+	if readErr != nil {
+		// The plugin sees the permission error it would have without escalation
+		slog.WarnContext(p.ctx, "privileged read failed", "plugin", p.plugin, "path", hostPath, "error", readErr)
+		return nil, err
+	}
+	slog.InfoContext(p.ctx, "privileged read", "plugin", p.plugin, "path", hostPath, "bytes", len(data))
+	return &privilegedFile{Reader: bytes.NewReader(data), info: info}, nil
+}
+
+// canonical returns the host path of name with every symlink resolved, if it
+// is still inside the mount and passes the checks. The privileged reader
+// refuses symlinks, so a link swapped in after this makes the read fail
+// rather than escape the mount.
+func (p *privilegedFS) canonical(name string) (string, bool) {
+	root, err := filepath.EvalSymlinks(p.root)
+	if err != nil {
+		return "", false
+	}
+	path, err := filepath.EvalSymlinks(filepath.Join(p.root, filepath.FromSlash(name)))
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	if p.check != nil && p.check(filepath.ToSlash(rel)) != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// privilegedFile is a file read through the privileged reader.
+type privilegedFile struct {
+	*bytes.Reader
+	info fs.FileInfo
+}
+
+func (f *privilegedFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *privilegedFile) Close() error               { return nil }
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package wasm
+
+import (
+	"errors"
+	"io"
+)
+
+// ReadNoFollow is only supported on Unix, where privileged reads go through
+// sudo.
+func ReadNoFollow(string, io.Writer) error {
+	return errors.New("privileged reads are only supported on Unix")
+}
@@ -0,0 +1,127 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deniedFS denies opening one file of a MapFS, as the host would for a file
+// the reglet process has no read permission on.
+type deniedFS struct {
+	fstest.MapFS
+	denied string
+}
+
+func (d deniedFS) Open(name string) (fs.File, error) {
+	if name == d.denied {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	return d.MapFS.Open(name)
+}
+
+func TestPrivilegedFS_Open(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "shadow"), []byte("on disk"), 0o600))
+
+	var reads []string
+	reader := func(_ context.Context, path string) ([]byte, error) {
+		reads = append(reads, path)
+		return []byte("root:*:19000:0:99999:7:::\n"), nil
+	}
+	pfs := &privilegedFS{
+		ctx: context.Background(),
+		fsys: deniedFS{MapFS: fstest.MapFS{
+			"passwd": {Data: []byte("root:x:0:0::/root:/bin/sh\n")},
+		}, denied: "shadow"},
+		read:   reader,
+		plugin: "file",
+		root:   root,
+	}
+
+	f, err := pfs.Open("passwd")
+	require.NoError(t, err)
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "root:x:0:0::/root:/bin/sh\n", string(data))
+	assert.Empty(t, reads, "readable files are not escalated")
+
+	f, err = pfs.Open("shadow")
+	require.NoError(t, err)
+	data, err = io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "root:*:19000:0:99999:7:::\n", string(data))
+	info, err := f.Stat()
+	require.NoError(t, err)
+	assert.Equal(t, "shadow", info.Name())
+	require.NoError(t, f.Close())
+	assert.Equal(t, []string{filepath.Join(root, "shadow")}, reads)
+
+	_, err = pfs.Open("missing")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	pfs.read = func(context.Context, string) ([]byte, error) {
+		return nil, errors.New("sudo: a password is required")
+	}
+	_, err = pfs.Open("shadow")
+	assert.ErrorIs(t, err, fs.ErrPermission, "failed escalations keep the original error")
+}
+
+func TestPrivilegedFS_OpenRefusesLinksOutOfTheMount(t *testing.T) {
+	t.Parallel()
+
+	outside := filepath.Join(t.TempDir(), "shadow")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0o600))
+	root := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "shadow")))
+
+	var reads []string
+	pfs := &privilegedFS{
+		ctx:  context.Background(),
+		fsys: deniedFS{MapFS: fstest.MapFS{}, denied: "shadow"},
+		read: func(_ context.Context, path string) ([]byte, error) {
+			reads = append(reads, path)
+			return nil, nil
+		},
+		plugin: "file",
+		root:   root,
+	}
+
+	_, err := pfs.Open("shadow")
+	assert.ErrorIs(t, err, fs.ErrPermission)
+	assert.Empty(t, reads, "a link out of the mount is never read as root")
+}
+
+func TestPrivilegedFS_SymlinkPolicy(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	root := filepath.Join(base, "granted")
+	require.NoError(t, os.Mkdir(root, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "shadow"), []byte("out"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(base, "shadow"), filepath.Join(root, "escape")))
+
+	var reads []string
+	reader := func(_ context.Context, path string) ([]byte, error) {
+		reads = append(reads, path)
+		return []byte("escalated"), nil
+	}
+	host := newPolicyFS("file", root, capabilities.SymlinkRestrict, false)
+	pfs := newPrivilegedFS(context.Background(), "file", root, host, reader)
+	pfs.fsys = deniedFS{MapFS: fstest.MapFS{}, denied: "escape"}
+
+	_, err := pfs.Open("escape")
+	assert.ErrorIs(t, err, fs.ErrPermission)
+	assert.Empty(t, reads, "links the policy rejects are not escalated")
+}
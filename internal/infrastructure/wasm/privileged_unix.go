@@ -0,0 +1,60 @@
+//go:build unix
+
+package wasm
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// ReadNoFollow writes the regular file at path to w. It opens every
+// component of the path with O_NOFOLLOW, relative to the directory opened
+// before it, so a symlink swapped into the path after it was checked makes
+// the read fail instead of redirecting it. path must be absolute and clean,
+// as returned by filepath.EvalSymlinks. It is what the privileged-read
+// helper runs as root.
+func ReadNoFollow(path string, w io.Writer) error {
+	if !filepath.IsAbs(path) || filepath.Clean(path) != path || path == "/" {
+		return &fs.PathError{Op: "open", Path: path, Err: fs.ErrInvalid}
+	}
+
+	fd, err := unix.Open("/", unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return &fs.PathError{Op: "open", Path: "/", Err: err}
+	}
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, part := range parts {
+		// Non-blocking, so a FIFO at the end of the path cannot stall the read
+		flags := unix.O_RDONLY | unix.O_NOFOLLOW | unix.O_CLOEXEC | unix.O_NONBLOCK
+		if i < len(parts)-1 {
+			flags |= unix.O_DIRECTORY
+		}
+		next, err := unix.Openat(fd, part, flags, 0)
+		_ = unix.Close(fd)
+		if err != nil {
+			return &fs.PathError{Op: "open", Path: path, Err: err}
+		}
+		fd = next
+	}
+
+	f := os.NewFile(uintptr(fd), path)
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+	if info.Size() > maxPrivilegedReadBytes {
+		return fmt.Errorf("%s is larger than %d bytes", path, maxPrivilegedReadBytes)
+	}
+	_, err = io.Copy(w, io.LimitReader(f, maxPrivilegedReadBytes+1))
+	return err
+}
@@ -0,0 +1,37 @@
+//go:build unix
+
+package wasm
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadNoFollow(t *testing.T) {
+	t.Parallel()
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "etc"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "etc", "shadow"), []byte("root:*:19000::::::\n"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "etc", "shadow"), filepath.Join(dir, "link")))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "etc"), filepath.Join(dir, "linkdir")))
+	require.NoError(t, syscall.Mkfifo(filepath.Join(dir, "fifo"), 0o600))
+
+	var buf bytes.Buffer
+	require.NoError(t, ReadNoFollow(filepath.Join(dir, "etc", "shadow"), &buf))
+	assert.Equal(t, "root:*:19000::::::\n", buf.String())
+
+	assert.Error(t, ReadNoFollow(filepath.Join(dir, "link"), &buf), "a symlink as the file")
+	assert.Error(t, ReadNoFollow(filepath.Join(dir, "linkdir", "shadow"), &buf), "a symlink as a directory")
+	assert.ErrorContains(t, ReadNoFollow(filepath.Join(dir, "etc"), &buf), "not a regular file")
+	assert.ErrorContains(t, ReadNoFollow(filepath.Join(dir, "fifo"), &buf), "not a regular file")
+	assert.Error(t, ReadNoFollow("etc/shadow", &buf), "a relative path")
+	assert.Error(t, ReadNoFollow(dir+"/etc/../etc/shadow", &buf), "an unclean path")
+}
@@ -6,9 +6,12 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/domain/execution"
 	"github.com/reglet-dev/reglet/internal/infrastructure/build"
 	"github.com/reglet-dev/reglet/internal/infrastructure/sensitivedata"
 	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
@@ -128,11 +131,57 @@ func (r *Runtime) LoadPlugin(ctx context.Context, name string, wasmBytes []byte)
 		return p, nil
 	}
 
+	plugin, err := r.newPlugin(ctx, name, wasmBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the plugin
+	r.plugins[name] = plugin
+
+	return plugin, nil
+}
+
+// ReloadPlugin recompiles a plugin from new WASM bytes and atomically swaps it
+// into the cache, for long-running processes that pick up plugin updates
+// without restarting. Observations already running finish on the old module;
+// later calls get the new one. On error the previously loaded plugin is kept.
+func (r *Runtime) ReloadPlugin(ctx context.Context, name string, wasmBytes []byte) (*Plugin, error) {
+	plugin, err := r.newPlugin(ctx, name, wasmBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	previous := r.plugins[name]
+	r.plugins[name] = plugin
+	r.mu.Unlock()
+
+	// Safe with calls in flight: instances keep running after their compiled module is closed
+	if previous != nil {
+		if err := previous.module.Close(ctx); err != nil {
+			slog.WarnContext(ctx, "failed to close replaced plugin module", "plugin", name, "error", err)
+		}
+	}
+
+	return plugin, nil
+}
+
+// newPlugin compiles wasmBytes into an uncached Plugin.
+func (r *Runtime) newPlugin(ctx context.Context, name string, wasmBytes []byte) (*Plugin, error) {
+	if DetectBinaryKind(wasmBytes) == BinaryKindComponent {
+		return nil, fmt.Errorf("failed to load plugin %s: %w", name, ErrComponentNotSupported)
+	}
+
 	// Compile the WASM module
+	start := time.Now()
 	compiledModule, err := r.runtime.CompileModule(ctx, wasmBytes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile plugin %s: %w", name, err)
 	}
+	if recorder, ok := execution.PerfRecorderFromContext(ctx); ok {
+		recorder.RecordPhase(execution.PhasePluginCompile, time.Since(start))
+	}
 
 	// Create output writers with optional redaction
 	var stdout, stderr io.Writer = os.Stderr, os.Stderr
@@ -153,12 +202,14 @@ func (r *Runtime) LoadPlugin(ctx context.Context, name string, wasmBytes []byte)
 		frozenEnv:    r.frozenEnv,                 // Pass frozen environment snapshot (prevents runtime env leakage)
 	}
 
-	// Cache the plugin
-	r.plugins[name] = plugin
-
 	return plugin, nil
 }
 
+// GrantedCapabilities returns the capabilities granted to the named plugin.
+func (r *Runtime) GrantedCapabilities(name string) []capabilities.Capability {
+	return r.grantedCapabilities[name]
+}
+
 // GetPlugin retrieves a loaded plugin by name.
 func (r *Runtime) GetPlugin(name string) (*Plugin, bool) {
 	r.mu.RLock()
@@ -167,6 +218,19 @@ func (r *Runtime) GetPlugin(name string) (*Plugin, bool) {
 	return p, ok
 }
 
+// PluginNames returns the names of all loaded plugins in sorted order.
+func (r *Runtime) PluginNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.plugins))
+	for name := range r.plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // GetPluginSchema implements config.PluginSchemaProvider.
 // It loads the plugin (if not already loaded) and retrieves its JSON Schema.
 func (r *Runtime) GetPluginSchema(ctx context.Context, pluginName string) ([]byte, error) {
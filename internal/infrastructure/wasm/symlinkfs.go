@@ -0,0 +1,219 @@
+package wasm
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
+	experimentalsys "github.com/tetratelabs/wazero/experimental/sys"
+	"github.com/tetratelabs/wazero/experimental/sysfs"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// symlinkFS is a host directory mount that applies a symlink policy to every
+// path the guest uses, so links inside a granted directory cannot lead the
+// plugin out of it. Operations that act on a link itself rather than on its
+// target (lstat, readlink, unlink, rename) only check the link's directory.
+// With hardening, paths must also pass hostfuncs.AuditPath.
+//
+// A path is checked before it is opened, so under any policy but follow the
+// guest may not create, move or hard-link symlinks: otherwise another
+// instance of the plugin could swap a link into a path between its check and
+// its use.
+type symlinkFS struct {
+	experimentalsys.FS
+	plugin string
+	root   string
+	policy capabilities.SymlinkPolicy
+	harden bool
+}
+
+// newSymlinkFS mounts the host directory root of a plugin under policy.
+func newSymlinkFS(plugin, root string, policy capabilities.SymlinkPolicy, harden bool) experimentalsys.FS {
+	return &symlinkFS{FS: sysfs.DirFS(root), plugin: plugin, root: filepath.Clean(root), policy: policy, harden: harden}
+}
+
+// check returns EACCES unless the policy permits the guest path, relative
+// to the mount root.
+func (f *symlinkFS) check(path string) experimentalsys.Errno {
+	hostPath := f.root
+	if path != "." {
+		hostPath = strings.TrimSuffix(f.root, string(filepath.Separator)) + string(filepath.Separator) + path
+	}
+	if !f.policy.Permits(f.root, hostPath) {
+		return experimentalsys.EACCES
+	}
+	if f.harden {
+		if err := hostfuncs.AuditPath(f.root, hostPath); err != nil {
+			slog.Warn("path hardening denied file access", "plugin", f.plugin, "path", hostPath, "reason", err)
+			return experimentalsys.EACCES
+		}
+	}
+	return 0
+}
+
+// checkDir checks the directory holding the guest path.
+func (f *symlinkFS) checkDir(path string) experimentalsys.Errno {
+	return f.check(filepath.Dir(path))
+}
+
+// checkNotLink returns EACCES if the guest path is a symlink and the policy
+// is not follow, so links cannot be moved into paths that were checked.
+func (f *symlinkFS) checkNotLink(path string) experimentalsys.Errno {
+	if f.policy == capabilities.SymlinkFollow {
+		return 0
+	}
+	st, errno := f.FS.Lstat(path)
+	if errno != 0 {
+		return errno
+	}
+	if st.Mode&fs.ModeSymlink != 0 {
+		return experimentalsys.EACCES
+	}
+	return 0
+}
+
+func (f *symlinkFS) OpenFile(path string, flag experimentalsys.Oflag, perm fs.FileMode) (experimentalsys.File, experimentalsys.Errno) {
+	check := f.check
+	if flag&experimentalsys.O_NOFOLLOW != 0 {
+		check = f.checkDir
+	}
+	if errno := check(path); errno != 0 {
+		return nil, errno
+	}
+	return f.FS.OpenFile(path, flag, perm)
+}
+
+func (f *symlinkFS) Lstat(path string) (sys.Stat_t, experimentalsys.Errno) {
+	if errno := f.checkDir(path); errno != 0 {
+		return sys.Stat_t{}, errno
+	}
+	return f.FS.Lstat(path)
+}
+
+func (f *symlinkFS) Stat(path string) (sys.Stat_t, experimentalsys.Errno) {
+	if errno := f.check(path); errno != 0 {
+		return sys.Stat_t{}, errno
+	}
+	return f.FS.Stat(path)
+}
+
+func (f *symlinkFS) Mkdir(path string, perm fs.FileMode) experimentalsys.Errno {
+	if errno := f.check(path); errno != 0 {
+		return errno
+	}
+	return f.FS.Mkdir(path, perm)
+}
+
+func (f *symlinkFS) Chmod(path string, perm fs.FileMode) experimentalsys.Errno {
+	if errno := f.check(path); errno != 0 {
+		return errno
+	}
+	return f.FS.Chmod(path, perm)
+}
+
+func (f *symlinkFS) Rename(from, to string) experimentalsys.Errno {
+	if errno := f.checkDir(from); errno != 0 {
+		return errno
+	}
+	if errno := f.checkNotLink(from); errno != 0 {
+		return errno
+	}
+	if errno := f.checkDir(to); errno != 0 {
+		return errno
+	}
+	return f.FS.Rename(from, to)
+}
+
+func (f *symlinkFS) Rmdir(path string) experimentalsys.Errno {
+	if errno := f.checkDir(path); errno != 0 {
+		return errno
+	}
+	return f.FS.Rmdir(path)
+}
+
+func (f *symlinkFS) Unlink(path string) experimentalsys.Errno {
+	if errno := f.checkDir(path); errno != 0 {
+		return errno
+	}
+	return f.FS.Unlink(path)
+}
+
+func (f *symlinkFS) Link(oldPath, newPath string) experimentalsys.Errno {
+	if errno := f.check(oldPath); errno != 0 {
+		return errno
+	}
+	if errno := f.checkNotLink(oldPath); errno != 0 {
+		return errno
+	}
+	if errno := f.checkDir(newPath); errno != 0 {
+		return errno
+	}
+	return f.FS.Link(oldPath, newPath)
+}
+
+// Symlink is only allowed under the follow policy, which checks paths as
+// written; there, following the link is checked when it is used.
+func (f *symlinkFS) Symlink(oldPath, linkName string) experimentalsys.Errno {
+	if f.policy != capabilities.SymlinkFollow {
+		slog.Warn("symlink policy denied creating a symlink", "plugin", f.plugin, "path", linkName, "policy", f.policy)
+		return experimentalsys.EACCES
+	}
+	if errno := f.checkDir(linkName); errno != 0 {
+		return errno
+	}
+	return f.FS.Symlink(oldPath, linkName)
+}
+
+func (f *symlinkFS) Readlink(path string) (string, experimentalsys.Errno) {
+	if errno := f.checkDir(path); errno != 0 {
+		return "", errno
+	}
+	return f.FS.Readlink(path)
+}
+
+func (f *symlinkFS) Utimens(path string, atim, mtim int64) experimentalsys.Errno {
+	if errno := f.check(path); errno != 0 {
+		return errno
+	}
+	return f.FS.Utimens(path, atim, mtim)
+}
+
+// policyFS is a read-only fs.FS view of a host directory that applies the
+// same symlink policy and hardening as symlinkFS, for the mounts served
+// through fs.FS wrappers (cassette recording, privileged reads).
+type policyFS struct {
+	checker *symlinkFS
+	fsys    fs.FS
+}
+
+// newPolicyFS returns the fs.FS view of the host directory root of a plugin
+// under policy.
+func newPolicyFS(plugin, root string, policy capabilities.SymlinkPolicy, harden bool) *policyFS {
+	return &policyFS{
+		checker: &symlinkFS{plugin: plugin, root: filepath.Clean(root), policy: policy, harden: harden},
+		fsys:    os.DirFS(root),
+	}
+}
+
+// check returns a permission error unless the policy permits the path.
+func (f *policyFS) check(name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if errno := f.checker.check(name); errno != 0 {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	return nil
+}
+
+func (f *policyFS) Open(name string) (fs.File, error) {
+	if err := f.check(name); err != nil {
+		return nil, err
+	}
+	return f.fsys.Open(name)
+}
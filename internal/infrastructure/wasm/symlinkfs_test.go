@@ -0,0 +1,122 @@
+package wasm
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/domain/capabilities"
+	"github.com/reglet-dev/reglet/internal/infrastructure/wasm/hostfuncs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	experimentalsys "github.com/tetratelabs/wazero/experimental/sys"
+)
+
+func TestSymlinkFS(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	root := filepath.Join(base, "granted")
+	require.NoError(t, os.Mkdir(root, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file"), []byte("in"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "secret"), []byte("out"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(base, "secret"), filepath.Join(root, "escape")))
+	require.NoError(t, os.Symlink("file", filepath.Join(root, "alias")))
+
+	open := func(fsys experimentalsys.FS, path string) experimentalsys.Errno {
+		f, errno := fsys.OpenFile(path, experimentalsys.O_RDONLY, 0)
+		if errno == 0 {
+			_ = f.Close()
+		}
+		return errno
+	}
+
+	restrict := newSymlinkFS("file", root, capabilities.SymlinkRestrict, false)
+	assert.Zero(t, open(restrict, "file"))
+	assert.Zero(t, open(restrict, "alias"))
+	assert.Equal(t, experimentalsys.EACCES, open(restrict, "escape"))
+	_, errno := restrict.Stat("escape")
+	assert.Equal(t, experimentalsys.EACCES, errno)
+	_, errno = restrict.Lstat("escape")
+	assert.Zero(t, errno, "the link itself may be inspected")
+	target, errno := restrict.Readlink("escape")
+	assert.Zero(t, errno)
+	assert.Equal(t, filepath.Join(base, "secret"), target)
+
+	noFollow := newSymlinkFS("file", root, capabilities.SymlinkNoFollow, false)
+	assert.Zero(t, open(noFollow, "file"))
+	assert.Equal(t, experimentalsys.EACCES, open(noFollow, "alias"))
+
+	follow := newSymlinkFS("file", root, capabilities.SymlinkFollow, false)
+	assert.Zero(t, open(follow, "escape"))
+}
+
+func TestSymlinkFS_NoLinkSwaps(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file"), []byte("in"), 0o600))
+	require.NoError(t, os.Symlink("file", filepath.Join(root, "alias")))
+
+	for _, policy := range []capabilities.SymlinkPolicy{capabilities.SymlinkRestrict, capabilities.SymlinkNoFollow} {
+		fsys := newSymlinkFS("file", root, policy, false)
+		assert.Equal(t, experimentalsys.EACCES, fsys.Symlink("/etc/shadow", "file2"), policy)
+		assert.Equal(t, experimentalsys.EACCES, fsys.Rename("alias", "file"), policy)
+		assert.Equal(t, experimentalsys.EACCES, fsys.Link("alias", "alias2"), policy)
+	}
+	_, err := os.Lstat(filepath.Join(root, "file2"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	restrict := newSymlinkFS("file", root, capabilities.SymlinkRestrict, false)
+	assert.Zero(t, restrict.Rename("file", "moved"), "regular files may still move")
+
+	follow := newSymlinkFS("file", root, capabilities.SymlinkFollow, false)
+	assert.Zero(t, follow.Symlink("moved", "link"))
+	assert.Zero(t, follow.Rename("link", "link2"))
+}
+
+func TestSymlinkFS_Hardened(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "sub"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file"), []byte("in"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Dir(root), filepath.Join(root, "up")))
+
+	hardened := newSymlinkFS("file", root, capabilities.SymlinkFollow, true)
+	_, errno := hardened.Stat("file")
+	assert.Zero(t, errno)
+	_, errno = hardened.Stat(".")
+	assert.Zero(t, errno)
+	_, errno = hardened.Stat("sub/../file")
+	assert.Equal(t, experimentalsys.EACCES, errno, "not canonical")
+	_, errno = hardened.Stat("up")
+	assert.Equal(t, experimentalsys.EACCES, errno, "follow is overridden")
+
+	_, errno = newSymlinkFS("file", root, capabilities.SymlinkFollow, false).Stat("up")
+	assert.Zero(t, errno)
+}
+
+func TestPolicyFS_Recording(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	root := filepath.Join(base, "granted")
+	require.NoError(t, os.Mkdir(root, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file"), []byte("in"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "secret"), []byte("out"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(base, "secret"), filepath.Join(root, "escape")))
+
+	recorder := hostfuncs.NewRecordingCassette()
+	recording := recorder.FS(root, newPolicyFS("file", root, capabilities.SymlinkRestrict, false))
+
+	data, err := fs.ReadFile(recording, "file")
+	require.NoError(t, err)
+	assert.Equal(t, "in", string(data))
+	_, err = fs.ReadFile(recording, "escape")
+	assert.ErrorIs(t, err, fs.ErrPermission, "recording applies the symlink policy")
+
+	_, err = fs.ReadFile(recorder.FS(root, newPolicyFS("file", root, capabilities.SymlinkFollow, true)), "escape")
+	assert.ErrorIs(t, err, fs.ErrPermission, "recording applies hardening")
+}
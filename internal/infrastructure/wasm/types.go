@@ -3,6 +3,8 @@
 package wasm
 
 import (
+	"time"
+
 	"github.com/reglet-dev/reglet/internal/domain/capabilities"
 	"github.com/reglet-dev/reglet/internal/domain/execution"
 )
@@ -10,10 +12,17 @@ import (
 // PluginInfo contains metadata about a plugin
 // Maps to the WIT plugin-info record
 type PluginInfo struct {
-	Name         string
-	Version      string
-	Description  string
-	Capabilities []capabilities.Capability
+	Name           string
+	Version        string
+	Description    string
+	SDKVersion     string // Empty for plugins not built with the Go SDK
+	MinHostVersion string
+	Commit         string
+	BuildTime      string
+	GoVersion      string
+	Dependencies   []string // Plugins a composite plugin observes
+	SideEffects    bool     // Plugin changes external state; observations get idempotency keys
+	Capabilities   []capabilities.Capability
 }
 
 // Config represents plugin configuration
@@ -51,4 +60,8 @@ type FieldDef struct {
 type PluginObservationResult struct {
 	Evidence *execution.Evidence
 	Error    *execution.PluginError
+
+	// Instantiation is the time spent creating the plugin instance, zero for
+	// plugins that do not run in a fresh instance
+	Instantiation time.Duration
 }
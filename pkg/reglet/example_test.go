@@ -0,0 +1,65 @@
+package reglet_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/reglet-dev/reglet/pkg/reglet"
+)
+
+func Example() {
+	engine, err := reglet.New(reglet.WithSecurityLevel("standard"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result, err := engine.Check(context.Background(), "profiles/ssh.yaml",
+		reglet.WithSeverities("high", "critical"),
+		reglet.WithParallel(4),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, control := range result.Controls {
+		fmt.Printf("%s: %s\n", control.ID, control.Status)
+	}
+	if result.Failed() {
+		log.Fatalf("%d controls failed", result.Summary.FailedControls)
+	}
+}
+
+func ExampleEngine_LoadProfile() {
+	engine, err := reglet.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	profile, err := engine.LoadProfile(context.Background(), "profiles/ssh.yaml", "production")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, control := range profile.Controls {
+		fmt.Println(control.ID, control.Severity)
+	}
+}
+
+// progress prints each control as it completes.
+type progress struct {
+	reglet.NopHooks
+}
+
+func (progress) OnControlEnd(_ context.Context, result reglet.ControlResult) {
+	fmt.Printf("%s: %s (%s)\n", result.ID, result.Status, result.Duration)
+}
+
+func ExampleWithHooks() {
+	engine, err := reglet.New(reglet.WithHooks(progress{}))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := engine.Check(context.Background(), "profiles/ssh.yaml"); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,11 @@
+package reglet
+
+import "github.com/reglet-dev/reglet/internal/application/dto"
+
+// WithNativePlugins runs the plugins registered with the native package
+// in-process, so tests need no WASM builds.
+func WithNativePlugins() CheckOption {
+	return func(r *dto.CheckProfileRequest) {
+		r.Execution.PluginMode = dto.PluginModeNative
+	}
+}
@@ -0,0 +1,73 @@
+package reglet
+
+import (
+	"context"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+// Hooks observe a check while it runs, e.g. to report progress. They are
+// called synchronously from the goroutine running the control or
+// observation: they should return quickly and, with WithParallel, be safe
+// for concurrent calls.
+type Hooks interface {
+	// OnControlStart is called before a control runs, skipped ones included.
+	OnControlStart(ctx context.Context, control Control)
+	// OnControlEnd is called with the result of a control.
+	OnControlEnd(ctx context.Context, result ControlResult)
+	// OnObservationStart is called before an observation runs. Controls
+	// retried after transient errors run their observations again.
+	OnObservationStart(ctx context.Context, observation Observation)
+	// OnObservationEnd is called with the result of an observation.
+	OnObservationEnd(ctx context.Context, observation Observation, result ObservationResult)
+}
+
+// Observation identifies an observation of a control.
+type Observation struct {
+	ControlID string `json:"control_id"`
+	Plugin    string `json:"plugin"`
+	Index     int    `json:"index"` // position within the control's observations
+}
+
+// NopHooks implements Hooks doing nothing. Embed it to implement only some
+// of the hooks.
+type NopHooks struct{}
+
+// OnControlStart implements Hooks.
+func (NopHooks) OnControlStart(context.Context, Control) {}
+
+// OnControlEnd implements Hooks.
+func (NopHooks) OnControlEnd(context.Context, ControlResult) {}
+
+// OnObservationStart implements Hooks.
+func (NopHooks) OnObservationStart(context.Context, Observation) {}
+
+// OnObservationEnd implements Hooks.
+func (NopHooks) OnObservationEnd(context.Context, Observation, ObservationResult) {}
+
+// engineHooks calls Hooks with the public types.
+type engineHooks struct {
+	hooks Hooks
+}
+
+func (h engineHooks) OnControlStart(ctx context.Context, control execution.ControlResult) {
+	h.hooks.OnControlStart(ctx, Control{
+		ID:          control.ID,
+		Name:        control.Name,
+		Description: control.Description,
+		Severity:    control.Severity,
+		Tags:        control.Tags,
+	})
+}
+
+func (h engineHooks) OnControlEnd(ctx context.Context, control execution.ControlResult) {
+	h.hooks.OnControlEnd(ctx, newControlResult(control))
+}
+
+func (h engineHooks) OnObservationStart(ctx context.Context, ref execution.ObservationRef) {
+	h.hooks.OnObservationStart(ctx, Observation(ref))
+}
+
+func (h engineHooks) OnObservationEnd(ctx context.Context, ref execution.ObservationRef, result execution.ObservationResult) {
+	h.hooks.OnObservationEnd(ctx, Observation(ref), newObservationResult(result))
+}
@@ -0,0 +1,147 @@
+package reglet
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/application/dto"
+)
+
+type options struct {
+	logger           *slog.Logger
+	hooks            []Hooks
+	securityLevel    string
+	systemConfigPath string
+	pluginDir        string
+	trustPlugins     bool
+	offline          bool
+}
+
+// Option configures an Engine.
+type Option func(*options)
+
+// WithLogger sets the logger of the engine. By default, it logs nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithHooks calls hooks as the controls and observations of every check
+// start and end. It may be given several times; hooks run in that order.
+func WithHooks(hooks Hooks) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hooks)
+	}
+}
+
+// WithSecurityLevel sets the security level plugins run under: "strict",
+// "standard" or "permissive" (default: that of the system config).
+func WithSecurityLevel(level string) Option {
+	return func(o *options) {
+		o.securityLevel = level
+	}
+}
+
+// WithSystemConfig reads the system config, with its capability grants,
+// secrets and storage settings, from path (default: ~/.reglet/config.yaml).
+func WithSystemConfig(path string) Option {
+	return func(o *options) {
+		o.systemConfigPath = path
+	}
+}
+
+// WithPluginDir sets the directory of local plugins.
+func WithPluginDir(dir string) Option {
+	return func(o *options) {
+		o.pluginDir = dir
+	}
+}
+
+// WithTrustPlugins grants plugins every capability they request. Without
+// it, plugins only get the capabilities granted in the system config.
+func WithTrustPlugins() Option {
+	return func(o *options) {
+		o.trustPlugins = true
+	}
+}
+
+// WithOffline resolves plugins from the embedded set and the local cache
+// only, and withholds network capabilities from them.
+func WithOffline() Option {
+	return func(o *options) {
+		o.offline = true
+	}
+}
+
+// CheckOption configures a single check.
+type CheckOption func(*dto.CheckProfileRequest)
+
+// WithEnvironment selects one of the profile's environments.
+func WithEnvironment(name string) CheckOption {
+	return func(r *dto.CheckProfileRequest) {
+		r.Environment = name
+	}
+}
+
+// WithTags runs only the controls with one of tags.
+func WithTags(tags ...string) CheckOption {
+	return func(r *dto.CheckProfileRequest) {
+		r.Filters.IncludeTags = append(r.Filters.IncludeTags, tags...)
+	}
+}
+
+// WithoutTags skips the controls with one of tags.
+func WithoutTags(tags ...string) CheckOption {
+	return func(r *dto.CheckProfileRequest) {
+		r.Filters.ExcludeTags = append(r.Filters.ExcludeTags, tags...)
+	}
+}
+
+// WithSeverities runs only the controls of one of severities.
+func WithSeverities(severities ...string) CheckOption {
+	return func(r *dto.CheckProfileRequest) {
+		r.Filters.IncludeSeverities = append(r.Filters.IncludeSeverities, severities...)
+	}
+}
+
+// WithControls runs only the controls with one of ids, and the controls
+// they depend on.
+func WithControls(ids ...string) CheckOption {
+	return func(r *dto.CheckProfileRequest) {
+		r.Filters.IncludeControlIDs = append(r.Filters.IncludeControlIDs, ids...)
+		r.Filters.IncludeDependencies = true
+	}
+}
+
+// WithFilter runs only the controls matching an expression over their
+// fields, e.g. `severity in ["high", "critical"] && "prod" in tags`.
+func WithFilter(expression string) CheckOption {
+	return func(r *dto.CheckProfileRequest) {
+		r.Filters.FilterExpression = expression
+	}
+}
+
+// WithParallel runs up to maxControls controls at once (0 = no limit).
+func WithParallel(maxControls int) CheckOption {
+	return func(r *dto.CheckProfileRequest) {
+		r.Execution.Parallel = true
+		r.Execution.MaxConcurrentControls = maxControls
+	}
+}
+
+// WithClock replaces the system clock for result timestamps and
+// time-relative checks, for reproducible results.
+func WithClock(clock func() time.Time) CheckOption {
+	return func(r *dto.CheckProfileRequest) {
+		r.Execution.Clock = clock
+	}
+}
+
+// WithCollectOnly stores the evidence of observations without evaluating
+// their expectations; controls end up collected or errored.
+func WithCollectOnly() CheckOption {
+	return func(r *dto.CheckProfileRequest) {
+		r.Execution.CollectOnly = true
+	}
+}
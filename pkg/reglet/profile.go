@@ -0,0 +1,42 @@
+package reglet
+
+import "github.com/reglet-dev/reglet/internal/domain/entities"
+
+// Profile describes a loaded profile.
+type Profile struct {
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`
+	Description string    `json:"description,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	Controls    []Control `json:"controls"`
+}
+
+// Control describes a control of a profile.
+type Control struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Severity    string   `json:"severity,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	DependsOn   []string `json:"depends_on,omitempty"`
+}
+
+func newProfile(p *entities.Profile) *Profile {
+	profile := &Profile{
+		Name:        p.Metadata.Name,
+		Version:     p.Metadata.Version,
+		Description: p.Metadata.Description,
+		Environment: p.GetEnvironment(),
+	}
+	for _, ctrl := range p.GetAllControls() {
+		profile.Controls = append(profile.Controls, Control{
+			ID:          ctrl.ID,
+			Name:        ctrl.Name,
+			Description: ctrl.Description,
+			Severity:    ctrl.Severity,
+			Tags:        ctrl.Tags,
+			DependsOn:   ctrl.DependsOn,
+		})
+	}
+	return profile
+}
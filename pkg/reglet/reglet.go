@@ -0,0 +1,107 @@
+// Package reglet embeds the reglet compliance engine in Go programs.
+//
+// An Engine loads profiles and runs their controls with the same plugins,
+// capability grants and configuration as the reglet CLI, so services can run
+// checks in-process instead of shelling out:
+//
+//	engine, err := reglet.New(reglet.WithSecurityLevel("standard"))
+//	if err != nil {
+//		return err
+//	}
+//	result, err := engine.Check(ctx, "profiles/ssh.yaml", reglet.WithTags("critical"))
+//	if err != nil {
+//		return err
+//	}
+//	if result.Failed() {
+//		...
+//	}
+//
+// Embedded engines never prompt for capabilities: plugins get the grants
+// saved in the system config, or every capability they request with
+// WithTrustPlugins.
+//
+// The types of this package are its stable surface; they are converted from
+// the engine's internal types, which may change between releases.
+package reglet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/reglet-dev/reglet/internal/application/dto"
+	"github.com/reglet-dev/reglet/internal/infrastructure/container"
+)
+
+// Engine runs reglet profiles. It is safe for concurrent use.
+type Engine struct {
+	container *container.Container
+	options   options
+}
+
+// New creates an engine configured by opts.
+func New(opts ...Option) (*Engine, error) {
+	o := options{logger: slog.New(slog.DiscardHandler)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c, err := container.New(container.Options{
+		Logger:           o.logger,
+		SecurityLevel:    o.securityLevel,
+		SystemConfigPath: o.systemConfigPath,
+		TrustPlugins:     o.trustPlugins,
+		Offline:          o.offline,
+		NonInteractive:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize reglet: %w", err)
+	}
+	return &Engine{container: c, options: o}, nil
+}
+
+// LoadProfile loads and validates the profile at path, with its parents
+// merged and the variables of environment ("" = none) applied.
+func (e *Engine) LoadProfile(_ context.Context, path, environment string) (*Profile, error) {
+	profile, err := e.container.ProfileLoader().LoadProfile(path, environment)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.container.ProfileValidator().Validate(profile); err != nil {
+		return nil, err
+	}
+	return newProfile(profile), nil
+}
+
+// Check runs the controls of the profile at path and returns their results.
+// A check whose controls fail is not an error: see Result.Failed.
+func (e *Engine) Check(ctx context.Context, path string, opts ...CheckOption) (*Result, error) {
+	if path == "" {
+		return nil, errors.New("profile path is required")
+	}
+
+	request := dto.CheckProfileRequest{
+		ProfilePath: path,
+		Execution:   dto.ExecutionOptions{Offline: e.options.offline},
+		Options: dto.CheckOptions{
+			PluginDir:        e.options.pluginDir,
+			SystemConfigPath: e.options.systemConfigPath,
+			TrustPlugins:     e.options.trustPlugins,
+		},
+		Metadata: dto.RequestMetadata{RequestID: uuid.NewString()},
+	}
+	for _, hooks := range e.options.hooks {
+		request.Execution.Hooks = append(request.Execution.Hooks, engineHooks{hooks: hooks})
+	}
+	for _, opt := range opts {
+		opt(&request)
+	}
+
+	response, err := e.container.CheckProfileUseCase().Execute(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return newResult(response.ExecutionResult), nil
+}
@@ -0,0 +1,178 @@
+package reglet_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/reglet-dev/reglet/internal/infrastructure/native"
+	"github.com/reglet-dev/reglet/pkg/reglet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoPlugin is a native plugin that reports its config back as evidence.
+type echoPlugin struct{}
+
+func (echoPlugin) Describe(_ context.Context) ([]byte, error) { return []byte(`{"Name":"echo"}`), nil }
+func (echoPlugin) Schema(_ context.Context) ([]byte, error)   { return []byte(`{}`), nil }
+func (echoPlugin) Observe(_ context.Context, config []byte) ([]byte, error) {
+	return []byte(`{"Status":true,"Data":` + string(config) + `}`), nil
+}
+
+func init() {
+	native.Register("echo", echoPlugin{})
+}
+
+const echoProfile = `
+profile:
+  name: embedded
+  version: 1.0.0
+plugins:
+  - echo
+controls:
+  items:
+    - id: tls
+      name: TLS 1.3
+      severity: high
+      tags: [network]
+      observations:
+        - plugin: echo
+          config:
+            tls_version: TLS 1.3
+          expect:
+            - data.tls_version == "TLS 1.3"
+    - id: legacy
+      name: No legacy TLS
+      severity: low
+      observations:
+        - plugin: echo
+          config:
+            tls_version: TLS 1.0
+          expect:
+            - data.tls_version != "TLS 1.0"
+`
+
+func writeProfile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "profile.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(echoProfile), 0o600))
+	return path
+}
+
+func newEngine(t *testing.T) *reglet.Engine {
+	t.Helper()
+	engine, err := reglet.New(
+		reglet.WithSystemConfig(filepath.Join(t.TempDir(), "config.yaml")),
+	)
+	require.NoError(t, err)
+	return engine
+}
+
+func TestEngine_LoadProfile(t *testing.T) {
+	profile, err := newEngine(t).LoadProfile(context.Background(), writeProfile(t), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "embedded", profile.Name)
+	assert.Equal(t, "1.0.0", profile.Version)
+	require.Len(t, profile.Controls, 2)
+	assert.Equal(t, "tls", profile.Controls[0].ID)
+	assert.Equal(t, "high", profile.Controls[0].Severity)
+	assert.Equal(t, []string{"network"}, profile.Controls[0].Tags)
+}
+
+func TestEngine_Check(t *testing.T) {
+	result, err := newEngine(t).Check(context.Background(), writeProfile(t), reglet.WithNativePlugins())
+	require.NoError(t, err)
+
+	assert.Equal(t, "embedded", result.ProfileName)
+	assert.NotEmpty(t, result.ExecutionID)
+	assert.True(t, result.Failed())
+	assert.Equal(t, 2, result.Summary.TotalControls)
+	assert.Equal(t, 1, result.Summary.PassedControls)
+	assert.Equal(t, 1, result.Summary.FailedControls)
+
+	tls := result.Control("tls")
+	require.NotNil(t, tls)
+	assert.Equal(t, reglet.StatusPass, tls.Status)
+	require.Len(t, tls.Observations, 1)
+	assert.Equal(t, "echo", tls.Observations[0].Plugin)
+	assert.Equal(t, "TLS 1.3", tls.Observations[0].Evidence["tls_version"])
+	require.Len(t, tls.Observations[0].Expectations, 1)
+	assert.True(t, tls.Observations[0].Expectations[0].Passed)
+
+	assert.Equal(t, reglet.StatusFail, result.Control("legacy").Status)
+	assert.Nil(t, result.Control("missing"))
+}
+
+func TestEngine_CheckOptions(t *testing.T) {
+	engine := newEngine(t)
+	path := writeProfile(t)
+
+	t.Run("tags", func(t *testing.T) {
+		result, err := engine.Check(context.Background(), path, reglet.WithNativePlugins(), reglet.WithTags("network"))
+		require.NoError(t, err)
+		assert.False(t, result.Failed())
+		assert.Equal(t, reglet.StatusPass, result.Control("tls").Status)
+		assert.Equal(t, reglet.StatusSkipped, result.Control("legacy").Status)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		result, err := engine.Check(context.Background(), path, reglet.WithNativePlugins(), reglet.WithFilter(`severity == "low"`))
+		require.NoError(t, err)
+		assert.Equal(t, reglet.StatusSkipped, result.Control("tls").Status)
+		assert.Equal(t, reglet.StatusFail, result.Control("legacy").Status)
+	})
+
+	t.Run("collect only", func(t *testing.T) {
+		result, err := engine.Check(context.Background(), path, reglet.WithNativePlugins(), reglet.WithCollectOnly())
+		require.NoError(t, err)
+		assert.False(t, result.Failed())
+		assert.Equal(t, 2, result.Summary.CollectedControls)
+	})
+}
+
+func TestEngine_CheckMissingProfile(t *testing.T) {
+	_, err := newEngine(t).Check(context.Background(), filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+// progressHooks counts the controls and observations that ended.
+type progressHooks struct {
+	reglet.NopHooks
+	controls     []string
+	observations []reglet.Observation
+	mu           sync.Mutex
+}
+
+func (h *progressHooks) OnControlEnd(_ context.Context, result reglet.ControlResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.controls = append(h.controls, result.ID+" "+string(result.Status))
+}
+
+func (h *progressHooks) OnObservationEnd(_ context.Context, observation reglet.Observation, _ reglet.ObservationResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.observations = append(h.observations, observation)
+}
+
+func TestEngine_Hooks(t *testing.T) {
+	hooks := &progressHooks{}
+	engine, err := reglet.New(
+		reglet.WithSystemConfig(filepath.Join(t.TempDir(), "config.yaml")),
+		reglet.WithHooks(hooks),
+	)
+	require.NoError(t, err)
+
+	_, err = engine.Check(context.Background(), writeProfile(t), reglet.WithNativePlugins())
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"tls pass", "legacy fail"}, hooks.controls)
+	assert.ElementsMatch(t, []reglet.Observation{
+		{ControlID: "tls", Plugin: "echo"},
+		{ControlID: "legacy", Plugin: "echo"},
+	}, hooks.observations)
+}
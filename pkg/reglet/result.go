@@ -0,0 +1,175 @@
+package reglet
+
+import (
+	"time"
+
+	"github.com/reglet-dev/reglet/internal/domain/execution"
+)
+
+// Status is the outcome of a control or observation.
+type Status string
+
+// Statuses of controls and observations.
+const (
+	StatusPass    Status = "pass"
+	StatusFail    Status = "fail"
+	StatusError   Status = "error"
+	StatusSkipped Status = "skipped"
+	// StatusCollected marks evidence stored without evaluation (WithCollectOnly)
+	StatusCollected Status = "collected"
+)
+
+// Result is the outcome of a check.
+type Result struct {
+	StartTime      time.Time       `json:"start_time"`
+	EndTime        time.Time       `json:"end_time"`
+	ExecutionID    string          `json:"execution_id"`
+	ProfileName    string          `json:"profile_name"`
+	ProfileVersion string          `json:"profile_version"`
+	Environment    string          `json:"environment,omitempty"`
+	Controls       []ControlResult `json:"controls"`
+	Summary        Summary         `json:"summary"`
+	Duration       time.Duration   `json:"duration"`
+}
+
+// Failed reports whether a control failed or could not be evaluated.
+func (r *Result) Failed() bool {
+	return r.Summary.FailedControls > 0 || r.Summary.ErrorControls > 0
+}
+
+// Control returns the result of the control id, or nil if it did not run.
+func (r *Result) Control(id string) *ControlResult {
+	for i := range r.Controls {
+		if r.Controls[i].ID == id {
+			return &r.Controls[i]
+		}
+	}
+	return nil
+}
+
+// ControlResult is the outcome of a control.
+type ControlResult struct {
+	Labels       map[string]string   `json:"labels,omitempty"`
+	ID           string              `json:"id"`
+	Name         string              `json:"name"`
+	Description  string              `json:"description,omitempty"`
+	Severity     string              `json:"severity,omitempty"`
+	Status       Status              `json:"status"`
+	Message      string              `json:"message,omitempty"`
+	SkipReason   string              `json:"skip_reason,omitempty"`
+	Tags         []string            `json:"tags,omitempty"`
+	Observations []ObservationResult `json:"observations"`
+	Duration     time.Duration       `json:"duration"`
+}
+
+// ObservationResult is the outcome of an observation of a control.
+type ObservationResult struct {
+	Evidence      map[string]interface{} `json:"evidence,omitempty"`
+	Plugin        string                 `json:"plugin"`
+	PluginVersion string                 `json:"plugin_version,omitempty"`
+	Status        Status                 `json:"status"`
+	// Error is the plugin error or the reason the observation failed to run
+	Error        string              `json:"error,omitempty"`
+	Expectations []ExpectationResult `json:"expectations,omitempty"`
+	Duration     time.Duration       `json:"duration"`
+
+	// IdempotencyKey was passed to a plugin declaring side effects
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// ExpectationResult is the outcome of an expectation of an observation.
+type ExpectationResult struct {
+	Expression string `json:"expression"`
+	Message    string `json:"message,omitempty"`
+	Passed     bool   `json:"passed"`
+}
+
+// Summary counts the controls and observations of a check by status.
+type Summary struct {
+	TotalControls      int `json:"total_controls"`
+	PassedControls     int `json:"passed_controls"`
+	FailedControls     int `json:"failed_controls"`
+	ErrorControls      int `json:"error_controls"`
+	SkippedControls    int `json:"skipped_controls"`
+	CollectedControls  int `json:"collected_controls"`
+	TotalObservations  int `json:"total_observations"`
+	PassedObservations int `json:"passed_observations"`
+	FailedObservations int `json:"failed_observations"`
+	ErrorObservations  int `json:"error_observations"`
+}
+
+func newResult(r *execution.ExecutionResult) *Result {
+	result := &Result{
+		StartTime:      r.StartTime,
+		EndTime:        r.EndTime,
+		ExecutionID:    r.ExecutionID.String(),
+		ProfileName:    r.ProfileName,
+		ProfileVersion: r.ProfileVersion,
+		Environment:    r.Environment,
+		Duration:       r.Duration,
+		Summary: Summary{
+			TotalControls:      r.Summary.TotalControls,
+			PassedControls:     r.Summary.PassedControls,
+			FailedControls:     r.Summary.FailedControls,
+			ErrorControls:      r.Summary.ErrorControls,
+			SkippedControls:    r.Summary.SkippedControls,
+			CollectedControls:  r.Summary.CollectedControls,
+			TotalObservations:  r.Summary.TotalObservations,
+			PassedObservations: r.Summary.PassedObservations,
+			FailedObservations: r.Summary.FailedObservations,
+			ErrorObservations:  r.Summary.ErrorObservations,
+		},
+	}
+	for _, ctrl := range r.Controls {
+		result.Controls = append(result.Controls, newControlResult(ctrl))
+	}
+	return result
+}
+
+func newControlResult(ctrl execution.ControlResult) ControlResult {
+	control := ControlResult{
+		Labels:      ctrl.Labels,
+		ID:          ctrl.ID,
+		Name:        ctrl.Name,
+		Description: ctrl.Description,
+		Severity:    ctrl.Severity,
+		Status:      Status(ctrl.Status),
+		Message:     ctrl.Message,
+		SkipReason:  ctrl.SkipReason,
+		Tags:        ctrl.Tags,
+		Duration:    ctrl.Duration,
+	}
+	for _, obs := range ctrl.ObservationResults {
+		control.Observations = append(control.Observations, newObservationResult(obs))
+	}
+	return control
+}
+
+func newObservationResult(obs execution.ObservationResult) ObservationResult {
+	observation := ObservationResult{
+		Plugin:         obs.Plugin,
+		PluginVersion:  obs.PluginVersion,
+		Status:         Status(obs.Status),
+		Duration:       obs.Duration,
+		IdempotencyKey: obs.IdempotencyKey,
+	}
+	if obs.Evidence != nil {
+		observation.Evidence = obs.Evidence.Data
+	}
+	switch {
+	case obs.Error != nil:
+		observation.Error = obs.Error.Message
+	case obs.Evidence != nil && obs.Evidence.Error != nil:
+		observation.Error = obs.Evidence.Error.Message
+	case obs.RawError != nil:
+		observation.Error = obs.RawError.Error()
+	}
+	for _, exp := range obs.Expectations {
+		observation.Expectations = append(observation.Expectations, ExpectationResult{
+			Expression: exp.Expression,
+			Message:    exp.Message,
+			Passed:     exp.Passed,
+		})
+	}
+	return observation
+}
@@ -8,8 +8,9 @@ replace (
 )
 
 require (
-	github.com/stretchr/testify v1.8.4
 	github.com/reglet-dev/reglet/sdk v0.0.0-00010101000000-000000000000
+	github.com/reglet-dev/reglet/wireformat v0.0.0
+	github.com/stretchr/testify v1.8.4
 )
 
 require (
@@ -24,7 +25,6 @@ require (
 	github.com/leodido/go-urn v1.4.0 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/reglet-dev/reglet/wireformat v0.0.0 // indirect
 	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
 	golang.org/x/crypto v0.45.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
@@ -3,11 +3,13 @@ package main
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
 	regletsdk "github.com/reglet-dev/reglet/sdk"
 	"github.com/reglet-dev/reglet/sdk/exec"
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 )
 
 // commandPlugin implements the sdk.Plugin interface.
@@ -30,12 +32,14 @@ func (p *commandPlugin) Describe(ctx context.Context) (regletsdk.Metadata, error
 
 // CommandConfig represents the configuration for the command plugin.
 type CommandConfig struct {
-	Run     string   `json:"run,omitempty" description:"Command string to execute via shell"`
-	Command string   `json:"command,omitempty" description:"Executable path"`
-	Args    []string `json:"args,omitempty" description:"Arguments"`
-	Dir     string   `json:"dir,omitempty" description:"Working directory"`
-	Env     []string `json:"env,omitempty" description:"Environment variables"`
-	Timeout int      `json:"timeout,omitempty" default:"30" description:"Execution timeout in seconds"`
+	Run      string   `json:"run,omitempty" description:"Command string to execute via shell"`
+	Shell    string   `json:"shell,omitempty" description:"Shell for run: a POSIX shell (default /bin/sh), cmd.exe, powershell.exe or pwsh"`
+	Command  string   `json:"command,omitempty" description:"Executable path"`
+	Args     []string `json:"args,omitempty" description:"Arguments"`
+	Dir      string   `json:"dir,omitempty" description:"Working directory"`
+	Env      []string `json:"env,omitempty" description:"Environment variables"`
+	Timeout  int      `json:"timeout,omitempty" default:"30" description:"Execution timeout in seconds"`
+	Encoding string   `json:"encoding,omitempty" validate:"omitempty,oneof=auto utf-8 latin-1" description:"Encoding of the command output: auto (UTF-8, else Latin-1), utf-8 (default) or latin-1"`
 }
 
 // Schema returns the JSON schema for the plugin's configuration.
@@ -78,14 +82,13 @@ func (p *commandPlugin) Check(ctx context.Context, config regletsdk.Config) (reg
 	// "run" mode: execute via shell
 	if cfg.Run != "" {
 		// ⚠️  SECURITY WARNING: Shell execution can be dangerous!
-		// - Requires explicit "exec:/bin/sh" capability (user must grant shell access)
+		// - Requires explicit "exec:<shell>" capability (user must grant shell access)
 		// - Vulnerable to command injection if Run contains untrusted input
 		// - For untrusted input, use "command" mode with explicit args instead
 		//
 		// Safe:   run: "systemctl is-active sshd"
 		// Unsafe: run: "echo " + userInput  (if userInput can contain shell metacharacters)
-		cmd = "/bin/sh"
-		args = []string{"-c", cfg.Run}
+		cmd, args = shellCommand(cfg.Shell, cfg.Run)
 		execMode = "shell"
 	} else {
 		// "command" mode: direct execution (safer - no shell interpretation)
@@ -95,11 +98,12 @@ func (p *commandPlugin) Check(ctx context.Context, config regletsdk.Config) (reg
 	}
 
 	resp, err := exec.Run(ctx, exec.CommandRequest{
-		Command: cmd,
-		Args:    args,
-		Dir:     cfg.Dir,
-		Env:     cfg.Env,
-		Timeout: cfg.Timeout,
+		Command:  cmd,
+		Args:     args,
+		Dir:      cfg.Dir,
+		Env:      cfg.Env,
+		Timeout:  cfg.Timeout,
+		Encoding: cfg.Encoding,
 	})
 	if err != nil {
 		return regletsdk.Failure("exec", fmt.Sprintf("execution failed: %v", err)), nil
@@ -112,38 +116,62 @@ func (p *commandPlugin) Check(ctx context.Context, config regletsdk.Config) (reg
 	// Determine status based on exit code
 	statusPass := resp.ExitCode == 0
 
-	result := map[string]interface{}{
+	result := evidence.Command{
 		// Output streams
-		"stdout":     stdoutTrimmed,
-		"stderr":     stderrTrimmed,
-		"stdout_raw": resp.Stdout, // Keep raw for regex matching if needed
-		"stderr_raw": resp.Stderr,
+		Stdout:    stdoutTrimmed,
+		Stderr:    stderrTrimmed,
+		StdoutRaw: resp.Stdout, // Keep raw for regex matching if needed
+		StderrRaw: resp.Stderr,
 
 		// Execution results
-		"exit_code":   resp.ExitCode,
-		"duration_ms": resp.DurationMs,
-		"is_timeout":  resp.IsTimeout,
+		ExitCode:   int64(resp.ExitCode),
+		DurationMs: resp.DurationMs,
+		IsTimeout:  resp.IsTimeout,
 
 		// Command metadata (for debugging and auditing)
-		"exec_mode":      execMode, // "shell" or "direct"
-		"command":        cmd,      // Actual command executed
-		"args":           args,     // Actual arguments used
-		"working_dir":    cfg.Dir,
-		"timeout_config": cfg.Timeout,
+		ExecMode:      execMode, // "shell" or "direct"
+		Command:       cmd,      // Actual command executed
+		Args:          args,     // Actual arguments used
+		WorkingDir:    cfg.Dir,
+		TimeoutConfig: int64(cfg.Timeout),
+	}
+
+	// Hosts predating output encodings report no charsets
+	if resp.StdoutCharset != "" {
+		result.StdoutCharset = evidence.Ptr(resp.StdoutCharset)
+		result.StderrCharset = evidence.Ptr(resp.StderrCharset)
 	}
 
 	// Add original command for clarity
 	if execMode == "shell" {
-		result["shell_command"] = cfg.Run
+		result.ShellCommand = evidence.Ptr(cfg.Run)
 	} else {
-		result["command_path"] = cfg.Command
-		result["command_args"] = cfg.Args
+		result.CommandPath = evidence.Ptr(cfg.Command)
+		result.CommandArgs = cfg.Args
 	}
 
 	// Return Evidence with Status based on exit code
 	return regletsdk.Evidence{
 		Status:    statusPass,
-		Data:      result,
+		Typed:     result,
 		Timestamp: time.Now(),
 	}, nil
 }
+
+// shellCommand returns the command and arguments running script with shell
+// (default /bin/sh). cmd.exe and PowerShell take the script differently from
+// POSIX shells.
+func shellCommand(shell, script string) (string, []string) {
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	name := strings.ToLower(path.Base(strings.ReplaceAll(shell, `\`, "/")))
+	switch strings.TrimSuffix(name, ".exe") {
+	case "cmd":
+		return shell, []string{"/C", script}
+	case "powershell", "pwsh":
+		return shell, []string{"-NoProfile", "-NonInteractive", "-Command", script}
+	default:
+		return shell, []string{"-c", script}
+	}
+}
@@ -4,8 +4,10 @@ package main
 
 import (
 	"context"
+	osexec "os/exec"
 	"testing"
 
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -97,6 +99,14 @@ func TestCommandConfig_Validation(t *testing.T) {
 			wantError: true,
 			errMsg:    "cannot specify both",
 		},
+		{
+			name: "unsupported encoding",
+			config: map[string]interface{}{
+				"run":      "echo hello",
+				"encoding": "ebcdic",
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -157,6 +167,47 @@ func TestCommandConfig_DirectMode(t *testing.T) {
 	_ = evidence
 }
 
+func TestCommandPlugin_Encoding(t *testing.T) {
+	printf, err := osexec.LookPath("printf")
+	if err != nil {
+		t.Skip("printf not available")
+	}
+
+	plugin := &commandPlugin{}
+	result, err := plugin.Check(context.Background(), map[string]interface{}{
+		"command":  printf,
+		"args":     []string{`caf\351`}, // é in Latin-1
+		"encoding": "auto",
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Error)
+
+	cmd, ok := result.Typed.(evidence.Command)
+	require.True(t, ok)
+	assert.Equal(t, "café", cmd.Stdout)
+	assert.Equal(t, evidence.Ptr("latin-1"), cmd.StdoutCharset)
+}
+
+func TestShellCommand(t *testing.T) {
+	tests := []struct {
+		shell    string
+		wantCmd  string
+		wantArgs []string
+	}{
+		{"", "/bin/sh", []string{"-c", "uname -a"}},
+		{"/bin/bash", "/bin/bash", []string{"-c", "uname -a"}},
+		{`C:\Windows\System32\cmd.exe`, `C:\Windows\System32\cmd.exe`, []string{"/C", "uname -a"}},
+		{"powershell.exe", "powershell.exe", []string{"-NoProfile", "-NonInteractive", "-Command", "uname -a"}},
+		{"/usr/local/bin/pwsh", "/usr/local/bin/pwsh", []string{"-NoProfile", "-NonInteractive", "-Command", "uname -a"}},
+	}
+
+	for _, tt := range tests {
+		cmd, args := shellCommand(tt.shell, "uname -a")
+		assert.Equal(t, tt.wantCmd, cmd, tt.shell)
+		assert.Equal(t, tt.wantArgs, args, tt.shell)
+	}
+}
+
 // Note: Full execution tests require WASM runtime
 // These tests focus on configuration validation and structure
 // Integration tests in the main test suite will cover actual execution
@@ -10,6 +10,7 @@ import (
 	regletsdk "github.com/reglet-dev/reglet/sdk"
 	regletnet "github.com/reglet-dev/reglet/sdk/net"
 	"github.com/reglet-dev/reglet/wireformat"
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 )
 
 // dnsPlugin implements the sdk.Plugin interface.
@@ -65,13 +66,13 @@ func (p *dnsPlugin) Check(ctx context.Context, config regletsdk.Config) (reglets
 	queryTime := time.Since(start).Milliseconds()
 
 	// Prepare data for evidence.
-	data := map[string]interface{}{
-		"hostname":      cfg.Hostname,
-		"record_type":   cfg.RecordType,
-		"query_time_ms": queryTime,
+	data := evidence.DNS{
+		Hostname:    cfg.Hostname,
+		RecordType:  cfg.RecordType,
+		QueryTimeMs: queryTime,
 	}
 
-	var evidence regletsdk.Evidence
+	var result regletsdk.Evidence
 	var finalErrorDetail *wireformat.ErrorDetail
 
 	if sdkErr != nil {
@@ -79,12 +80,12 @@ func (p *dnsPlugin) Check(ctx context.Context, config regletsdk.Config) (reglets
 		// sdkErr is *wireformat.ErrorDetail (due to SDK's LookupRaw function mapping it).
 		if errors.As(sdkErr, &finalErrorDetail) {
 			if finalErrorDetail.Type == "config" {
-				evidence = regletsdk.Evidence{
+				result = regletsdk.Evidence{
 					Status: false,
 					Error:  regletsdk.ToErrorDetail(&regletsdk.ConfigError{Err: finalErrorDetail}),
 				}
 			} else {
-				evidence = regletsdk.Evidence{
+				result = regletsdk.Evidence{
 					Status: false,
 					Error: regletsdk.ToErrorDetail(&regletsdk.NetworkError{
 						Operation: "dns_lookup",
@@ -99,18 +100,18 @@ func (p *dnsPlugin) Check(ctx context.Context, config regletsdk.Config) (reglets
 				Message: sdkErr.Error(),
 				Type:    "internal",
 			}
-			evidence = regletsdk.Failure("dns_sdk_error", finalErrorDetail.Message)
+			result = regletsdk.Failure("dns_sdk_error", finalErrorDetail.Message)
 		}
 	} else if dnsResponseWire.Error != nil {
 		// Host returned a structured error in the wire response (e.g. DNS NXDOMAIN, timeout)
 		finalErrorDetail = dnsResponseWire.Error
 		if finalErrorDetail.Type == "config" {
-			evidence = regletsdk.Evidence{
+			result = regletsdk.Evidence{
 				Status: false,
 				Error:  regletsdk.ToErrorDetail(&regletsdk.ConfigError{Err: finalErrorDetail}),
 			}
 		} else {
-			evidence = regletsdk.Evidence{
+			result = regletsdk.Evidence{
 				Status: false,
 				Error: regletsdk.ToErrorDetail(&regletsdk.NetworkError{
 					Operation: "dns_lookup",
@@ -123,31 +124,28 @@ func (p *dnsPlugin) Check(ctx context.Context, config regletsdk.Config) (reglets
 		// Success path: host returned no error, populate records
 		recordCount := 0
 		if dnsResponseWire.Records != nil {
-			data["records"] = dnsResponseWire.Records
+			data.Records = dnsResponseWire.Records
 			recordCount = len(dnsResponseWire.Records)
 		}
 		if dnsResponseWire.MXRecords != nil {
-			var mxRecords []map[string]interface{}
+			mxRecords := make([]evidence.MXRecord, 0, len(dnsResponseWire.MXRecords))
 			for _, mx := range dnsResponseWire.MXRecords {
-				mxRecords = append(mxRecords, map[string]interface{}{"host": mx.Host, "pref": mx.Pref})
+				mxRecords = append(mxRecords, evidence.MXRecord{Host: mx.Host, Pref: int64(mx.Pref)})
 			}
-			data["mx_records"] = mxRecords
+			data.MXRecords = mxRecords
 			recordCount = len(mxRecords)
 		}
-		data["record_count"] = recordCount
-		evidence = regletsdk.Success(data) // Final success
+		data.RecordCount = evidence.Ptr(int64(recordCount))
+		result = regletsdk.TypedSuccess(data) // Final success
 	}
 
-	// Always populate error flags and message into Evidence.Data for consistent OPA policy access.
+	// Always populate error flags and message into the evidence data for consistent OPA policy access.
 	if finalErrorDetail != nil {
-		data["error_message"] = finalErrorDetail.Message
-		data["is_timeout"] = finalErrorDetail.IsTimeout
-		data["is_not_found"] = finalErrorDetail.IsNotFound
-	} else {
-		data["is_timeout"] = false
-		data["is_not_found"] = false
+		data.ErrorMessage = evidence.Ptr(finalErrorDetail.Message)
+		data.IsTimeout = finalErrorDetail.IsTimeout
+		data.IsNotFound = finalErrorDetail.IsNotFound
 	}
-	evidence.Data = data
+	result.Typed = data
 
-	return evidence, nil
+	return result, nil
 }
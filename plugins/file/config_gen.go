@@ -0,0 +1,39 @@
+// Code generated by reglet plugin gen from config.yaml. DO NOT EDIT.
+
+package main
+
+import (
+	_ "embed"
+
+	regletsdk "github.com/reglet-dev/reglet/sdk"
+)
+
+// FileConfig is the configuration of the file plugin.
+type FileConfig struct {
+	Path        string         `json:"path" validate:"required" description:"Path to file to check"`
+	ReadContent bool           `json:"read_content,omitempty" description:"Read and return file content"`
+	Hash        bool           `json:"hash,omitempty" description:"Calculate SHA256 hash of file"`
+	HashOnly    bool           `json:"hash_only,omitempty" description:"Calculate SHA256 hash of file on the host, without reading the file into plugin memory"`
+	ReadRange   *FileReadRange `json:"read_range,omitempty" description:"Read and return only this byte range of file content"`
+	Encoding    string         `json:"encoding,omitempty" validate:"omitempty,oneof=auto utf-8 latin-1" description:"Return content read with read_content or read_range as text in this encoding instead of base64: auto (UTF-8, else Latin-1), utf-8 or latin-1"`
+}
+
+// FileReadRange is the read_range configuration of the file plugin.
+type FileReadRange struct {
+	Offset int `json:"offset,omitempty" validate:"omitempty,gte=0" description:"First byte to read"`
+	Length int `json:"length" validate:"required,gte=1,lte=16777216" description:"Bytes to read, at most 16MB"`
+}
+
+// configSchema is the JSON Schema of FileConfig, generated alongside it.
+//
+//go:embed schema.json
+var configSchema []byte
+
+// parseFileConfig decodes and validates config.
+func parseFileConfig(config regletsdk.Config) (FileConfig, error) {
+	var cfg FileConfig
+	if err := regletsdk.ValidateConfig(config, &cfg); err != nil {
+		return cfg, &regletsdk.ConfigError{Err: err}
+	}
+	return cfg, nil
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	regletsdk "github.com/reglet-dev/reglet/sdk"
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 )
 
 func TestFilePlugin_Check_Exists(t *testing.T) {
@@ -32,14 +33,15 @@ func TestFilePlugin_Check_Exists(t *testing.T) {
 	}
 
 	// Verify metadata
-	if exists, ok := evidence.Data["exists"].(bool); !ok || !exists {
-		t.Errorf("Expected exists=true, got %v", evidence.Data["exists"])
+	data := fileEvidence(t, evidence)
+	if !data.Exists {
+		t.Errorf("Expected exists=true, got false")
 	}
-	if isDir, ok := evidence.Data["is_dir"].(bool); !ok || isDir {
-		t.Errorf("Expected is_dir=false, got %v", evidence.Data["is_dir"])
+	if data.IsDir == nil || *data.IsDir {
+		t.Errorf("Expected is_dir=false, got %v", data.IsDir)
 	}
-	if size, ok := evidence.Data["size"].(int64); !ok || size != 7 {
-		t.Errorf("Expected size=7, got %v", evidence.Data["size"])
+	if data.Size == nil || *data.Size != 7 {
+		t.Errorf("Expected size=7, got %v", data.Size)
 	}
 }
 
@@ -67,15 +69,60 @@ func TestFilePlugin_Check_Content(t *testing.T) {
 		t.Errorf("Expected status true, got false")
 	}
 
-	b64, ok := evidence.Data["content_b64"].(string)
-	if !ok {
-		t.Errorf("Expected content_b64 string")
+	b64 := fileEvidence(t, evidence).ContentB64
+	if b64 == nil {
+		t.Fatalf("Expected content_b64 string")
 	}
-	if b64 == "" {
+	if *b64 == "" {
 		t.Errorf("Expected non-empty content")
 	}
 }
 
+func TestFilePlugin_Check_ContentEncoding(t *testing.T) {
+	tmpDir := t.TempDir()
+	latin1File := filepath.Join(tmpDir, "latin1")
+	if err := os.WriteFile(latin1File, []byte("caf\xe9"), 0o644); err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	utf8File := filepath.Join(tmpDir, "utf8")
+	if err := os.WriteFile(utf8File, []byte("café"), 0o644); err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+
+	tests := []struct {
+		path        string
+		encoding    string
+		wantContent string
+		wantCharset string
+	}{
+		{latin1File, "auto", "café", "latin-1"},
+		{latin1File, "latin-1", "café", "latin-1"},
+		{latin1File, "utf-8", "caf\uFFFD", "utf-8"},
+		{utf8File, "auto", "café", "utf-8"},
+	}
+	for _, tt := range tests {
+		evidence, err := (&filePlugin{}).Check(context.Background(), regletsdk.Config{
+			"path":         tt.path,
+			"read_content": true,
+			"encoding":     tt.encoding,
+		})
+		if err != nil {
+			t.Fatalf("Check returned error: %v", err)
+		}
+
+		data := fileEvidence(t, evidence)
+		if data.Content == nil || *data.Content != tt.wantContent {
+			t.Errorf("%s as %s: expected content %q, got %v", filepath.Base(tt.path), tt.encoding, tt.wantContent, data.Content)
+		}
+		if data.Charset == nil || *data.Charset != tt.wantCharset {
+			t.Errorf("%s as %s: expected charset %q, got %v", filepath.Base(tt.path), tt.encoding, tt.wantCharset, data.Charset)
+		}
+		if data.ContentB64 != nil {
+			t.Errorf("Expected no content_b64 with an encoding")
+		}
+	}
+}
+
 func TestFilePlugin_Check_Hash(t *testing.T) {
 	// Create temp file
 	tmpDir := t.TempDir()
@@ -104,12 +151,82 @@ func TestFilePlugin_Check_Hash(t *testing.T) {
 	// 54a6483b8aca55c9df2a35baf71d9965ddfd623468d81d51229bd5eb7d1e1c1b
 	expectedHash := "54a6483b8aca55c9df2a35baf71d9965ddfd623468d81d51229bd5eb7d1e1c1b"
 
-	hash, ok := evidence.Data["sha256"].(string)
-	if !ok {
-		t.Errorf("Expected sha256 string")
+	hash := fileEvidence(t, evidence).SHA256
+	if hash == nil {
+		t.Fatalf("Expected sha256 string")
 	}
-	if hash != expectedHash {
-		t.Errorf("Expected hash %s, got %s", expectedHash, hash)
+	if *hash != expectedHash {
+		t.Errorf("Expected hash %s, got %s", expectedHash, *hash)
+	}
+}
+
+func TestFilePlugin_Check_HashOnly(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "testfile")
+	if err := os.WriteFile(tmpFile, []byte("test hash"), 0o644); err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+
+	evidence, err := (&filePlugin{}).Check(context.Background(), regletsdk.Config{
+		"path":      tmpFile,
+		"hash_only": true,
+	})
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+
+	data := fileEvidence(t, evidence)
+	expectedHash := "54a6483b8aca55c9df2a35baf71d9965ddfd623468d81d51229bd5eb7d1e1c1b"
+	if data.SHA256 == nil || *data.SHA256 != expectedHash {
+		t.Errorf("Expected hash %s, got %v", expectedHash, data.SHA256)
+	}
+	if data.ContentB64 != nil {
+		t.Errorf("Expected no content with hash_only")
+	}
+}
+
+func TestFilePlugin_Check_ReadRange(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "testfile")
+	if err := os.WriteFile(tmpFile, []byte("hello world"), 0o644); err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+
+	evidence, err := (&filePlugin{}).Check(context.Background(), regletsdk.Config{
+		"path":       tmpFile,
+		"read_range": map[string]interface{}{"offset": 6, "length": 100},
+		"encoding":   "utf-8",
+	})
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+
+	data := fileEvidence(t, evidence)
+	if data.Content == nil || *data.Content != "world" {
+		t.Errorf("Expected content %q, got %v", "world", data.Content)
+	}
+	if data.RangeOffset == nil || *data.RangeOffset != 6 {
+		t.Errorf("Expected range_offset 6, got %v", data.RangeOffset)
+	}
+	if data.RangeLength == nil || *data.RangeLength != 5 {
+		t.Errorf("Expected range_length 5 at the end of the file, got %v", data.RangeLength)
+	}
+}
+
+func TestFilePlugin_Check_ConflictingReads(t *testing.T) {
+	configs := []regletsdk.Config{
+		{"path": "/etc/hosts", "read_content": true, "read_range": map[string]interface{}{"length": 10}},
+		{"path": "/etc/hosts", "hash_only": true, "read_content": true},
+		{"path": "/etc/hosts", "hash_only": true, "read_range": map[string]interface{}{"length": 10}},
+		{"path": "/etc/hosts", "read_range": map[string]interface{}{"offset": 10}},
+		{"path": "/etc/hosts", "read_range": map[string]interface{}{"length": 32 << 20}},
+	}
+	for _, config := range configs {
+		evidence, err := (&filePlugin{}).Check(context.Background(), config)
+		if err != nil {
+			t.Fatalf("Check returned unexpected error: %v", err)
+		}
+		if evidence.Error == nil || evidence.Error.Type != "config" {
+			t.Errorf("%v: expected config error, got %v", config, evidence.Error)
+		}
 	}
 }
 
@@ -132,8 +249,8 @@ func TestFilePlugin_Check_NonExistent(t *testing.T) {
 		t.Errorf("Expected status true for non-existent file check, got false. Error: %v", evidence.Error)
 	}
 
-	if exists, ok := evidence.Data["exists"].(bool); !ok || exists {
-		t.Errorf("Expected exists=false, got %v", evidence.Data["exists"])
+	if fileEvidence(t, evidence).Exists {
+		t.Errorf("Expected exists=false, got true")
 	}
 }
 
@@ -153,3 +270,13 @@ func TestFilePlugin_Check_MissingPath(t *testing.T) {
 		t.Errorf("Expected config error, got %v", evidence.Error)
 	}
 }
+
+// fileEvidence returns the typed evidence data the plugin reported.
+func fileEvidence(t *testing.T, ev regletsdk.Evidence) evidence.File {
+	t.Helper()
+	data, ok := ev.Typed.(evidence.File)
+	if !ok {
+		t.Fatalf("Expected evidence.File data, got %T", ev.Typed)
+	}
+	return data
+}
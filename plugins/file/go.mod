@@ -7,7 +7,10 @@ replace (
 	github.com/reglet-dev/reglet/wireformat => ../../wireformat
 )
 
-require github.com/reglet-dev/reglet/sdk v0.0.0-00010101000000-000000000000
+require (
+	github.com/reglet-dev/reglet/sdk v0.0.0-00010101000000-000000000000
+	github.com/reglet-dev/reglet/wireformat v0.0.0
+)
 
 require (
 	github.com/bahlo/generic-list-go v0.2.0 // indirect
@@ -17,10 +20,11 @@ require (
 	github.com/go-playground/universal-translator v0.18.1 // indirect
 	github.com/go-playground/validator/v10 v10.28.0 // indirect
 	github.com/invopop/jsonschema v0.13.0 // indirect
+	github.com/klauspost/cpuid/v2 v2.3.0 // indirect
 	github.com/leodido/go-urn v1.4.0 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
-	github.com/reglet-dev/reglet/wireformat v0.0.0 // indirect
 	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
+	github.com/zeebo/blake3 v0.2.4 // indirect
 	golang.org/x/crypto v0.45.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
 	golang.org/x/text v0.31.0 // indirect
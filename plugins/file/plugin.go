@@ -12,6 +12,10 @@ import (
 	"time"
 
 	regletsdk "github.com/reglet-dev/reglet/sdk"
+	"github.com/reglet-dev/reglet/sdk/checksum"
+	"github.com/reglet-dev/reglet/sdk/filerange"
+	"github.com/reglet-dev/reglet/wireformat"
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 )
 
 // filePlugin implements the sdk.Plugin interface for file system operations.
@@ -32,47 +36,51 @@ func (p *filePlugin) Describe(ctx context.Context) (regletsdk.Metadata, error) {
 	}, nil
 }
 
-type FileConfig struct {
-	Path        string `json:"path" validate:"required" description:"Path to file to check"`
-	ReadContent bool   `json:"read_content,omitempty" description:"Read and return file content"`
-	Hash        bool   `json:"hash,omitempty" description:"Calculate SHA256 hash of file"`
-}
-
-// Schema generates the JSON schema for the plugin's configuration.
+// Schema returns the JSON schema generated from config.yaml.
 func (p *filePlugin) Schema(ctx context.Context) ([]byte, error) {
-	return regletsdk.GenerateSchema(FileConfig{})
+	return configSchema, nil
 }
 
 // Check executes file system validation based on the provided configuration.
 func (p *filePlugin) Check(ctx context.Context, config regletsdk.Config) (regletsdk.Evidence, error) {
-	var cfg FileConfig
-	if err := regletsdk.ValidateConfig(config, &cfg); err != nil {
+	cfg, err := parseFileConfig(config)
+	if err == nil {
+		err = validateReads(cfg)
+	}
+	if err != nil {
 		return regletsdk.Evidence{
 			Status: false,
-			Error:  regletsdk.ToErrorDetail(&regletsdk.ConfigError{Err: err}),
+			Error:  regletsdk.ToErrorDetail(err),
 		}, nil
 	}
 
-	return checkFile(cfg)
+	return checkFile(ctx, cfg)
 }
 
-// checkFile performs the actual file check logic.
-func checkFile(cfg FileConfig) (regletsdk.Evidence, error) {
-	result := map[string]interface{}{
-		"path": cfg.Path,
+// validateReads rejects combinations of read options that contradict each other.
+func validateReads(cfg FileConfig) error {
+	switch {
+	case cfg.ReadContent && cfg.ReadRange != nil:
+		return &regletsdk.ConfigError{Err: fmt.Errorf("read_range reads part of the content; do not combine it with read_content")}
+	case cfg.HashOnly && (cfg.ReadContent || cfg.ReadRange != nil):
+		return &regletsdk.ConfigError{Err: fmt.Errorf("hash_only does not read content; do not combine it with read_content or read_range")}
 	}
+	return nil
+}
+
+// checkFile performs the actual file check logic.
+func checkFile(ctx context.Context, cfg FileConfig) (regletsdk.Evidence, error) {
+	result := &evidence.File{Path: cfg.Path}
 
 	// 1. Open file and get metadata
 	f, info, err := openAndStat(cfg.Path)
 	if err != nil {
-		return handleOpenError(err, cfg.Path, result)
+		return handleOpenError(err, result)
 	}
 	if f != nil {
 		defer f.Close()
-		result["readable"] = true
-	} else {
-		result["readable"] = false
 	}
+	result.Readable = f != nil
 
 	// 2. Populate metadata
 	populateMetadata(result, info)
@@ -82,19 +90,30 @@ func checkFile(cfg FileConfig) (regletsdk.Evidence, error) {
 
 	// 4. Read content if requested
 	if cfg.ReadContent && !info.IsDir() {
-		if err := readContent(f, result); err != nil {
-			return err.(regletsdk.Evidence), nil
+		if failure, ok := readContent(f, cfg.Encoding, result); !ok {
+			return failure, nil
+		}
+	}
+	if cfg.ReadRange != nil && !info.IsDir() {
+		if failure, ok := readRange(ctx, cfg.Path, *cfg.ReadRange, cfg.Encoding, result); !ok {
+			return failure, nil
 		}
 	}
 
 	// 5. Calculate hash if requested
-	if cfg.Hash && !info.IsDir() {
-		if err := calculateHash(f, result); err != nil {
-			return err.(regletsdk.Evidence), nil
+	switch {
+	case info.IsDir():
+	case cfg.HashOnly:
+		if failure, ok := hostHash(ctx, cfg.Path, result); !ok {
+			return failure, nil
+		}
+	case cfg.Hash:
+		if failure, ok := calculateHash(f, result); !ok {
+			return failure, nil
 		}
 	}
 
-	return regletsdk.Success(result), nil
+	return regletsdk.TypedSuccess(*result), nil
 }
 
 // openAndStat attempts to open the file and get its metadata.
@@ -125,84 +144,126 @@ func openAndStat(path string) (*os.File, os.FileInfo, error) {
 }
 
 // handleOpenError handles errors from openAndStat.
-func handleOpenError(err error, path string, result map[string]interface{}) (regletsdk.Evidence, error) {
+func handleOpenError(err error, result *evidence.File) (regletsdk.Evidence, error) {
 	if os.IsNotExist(err) {
-		result["exists"] = false
-		result["readable"] = false
-		return regletsdk.Success(result), nil
+		result.Exists = false
+		result.Readable = false
+		return regletsdk.TypedSuccess(*result), nil
 	}
 	return regletsdk.Failure("fs", err.Error()), nil
 }
 
 // populateMetadata fills in file metadata fields.
-func populateMetadata(result map[string]interface{}, info os.FileInfo) {
-	result["exists"] = true
-	result["is_dir"] = info.IsDir()
-	result["size"] = info.Size()
-	result["mode"] = fmt.Sprintf("%04o", info.Mode().Perm())
-	result["permissions"] = info.Mode().String()
-	result["mod_time"] = info.ModTime().Format(time.RFC3339)
+func populateMetadata(result *evidence.File, info os.FileInfo) {
+	result.Exists = true
+	result.IsDir = evidence.Ptr(info.IsDir())
+	result.Size = evidence.Ptr(info.Size())
+	result.Mode = evidence.Ptr(fmt.Sprintf("%04o", info.Mode().Perm()))
+	result.Permissions = evidence.Ptr(info.Mode().String())
+	result.ModTime = evidence.Ptr(info.ModTime().Format(time.RFC3339))
 
 	// Attempt to get ownership (Unix-specific)
 	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
-		result["uid"] = stat.Uid
-		result["gid"] = stat.Gid
+		result.UID = evidence.Ptr(int64(stat.Uid))
+		result.GID = evidence.Ptr(int64(stat.Gid))
 	}
 }
 
 // checkSymlink checks if the path is a symlink and populates result.
-func checkSymlink(result map[string]interface{}, path string) {
+func checkSymlink(result *evidence.File, path string) {
 	linfo, err := os.Lstat(path)
 	if err != nil {
-		result["is_symlink"] = false
+		result.IsSymlink = evidence.Ptr(false)
 		return
 	}
 
-	if linfo.Mode()&os.ModeSymlink != 0 {
-		result["is_symlink"] = true
+	isSymlink := linfo.Mode()&os.ModeSymlink != 0
+	result.IsSymlink = evidence.Ptr(isSymlink)
+	if isSymlink {
 		if target, err := os.Readlink(path); err == nil {
-			result["symlink_target"] = target
+			result.SymlinkTarget = evidence.Ptr(target)
 		}
-	} else {
-		result["is_symlink"] = false
 	}
 }
 
-// readContent reads file content into result. Returns Evidence on error.
-func readContent(f *os.File, result map[string]interface{}) interface{} {
+// readContent reads file content into result. Returns failed Evidence and
+// false on error.
+func readContent(f *os.File, encoding string, result *evidence.File) (regletsdk.Evidence, bool) {
 	if f == nil {
-		return regletsdk.Failure("fs", "read failed: file not readable")
+		return regletsdk.Failure("fs", "read failed: file not readable"), false
 	}
 
 	if _, err := f.Seek(0, 0); err != nil {
-		return regletsdk.Failure("fs", fmt.Sprintf("seek failed: %v", err))
+		return regletsdk.Failure("fs", fmt.Sprintf("seek failed: %v", err)), false
 	}
 
 	content, err := io.ReadAll(f)
 	if err != nil {
-		return regletsdk.Failure("fs", fmt.Sprintf("read failed: %v", err))
+		return regletsdk.Failure("fs", fmt.Sprintf("read failed: %v", err)), false
 	}
 
-	result["content_b64"] = base64.StdEncoding.EncodeToString(content)
-	result["encoding"] = "base64"
-	return nil
+	setContent(result, content, encoding)
+	return regletsdk.Evidence{}, true
 }
 
-// calculateHash calculates SHA256 hash of file content. Returns Evidence on error.
-func calculateHash(f *os.File, result map[string]interface{}) interface{} {
+// readRange reads the configured byte range of the file into result through
+// the host, so the rest of a large file never reaches plugin memory. Returns
+// failed Evidence and false on error.
+func readRange(ctx context.Context, path string, rng FileReadRange, encoding string, result *evidence.File) (regletsdk.Evidence, bool) {
+	res, err := filerange.Read(ctx, filerange.Request{Path: path, Offset: int64(rng.Offset), Length: int64(rng.Length)})
+	if err != nil {
+		return regletsdk.Failure("fs", fmt.Sprintf("range read failed: %v", err)), false
+	}
+
+	result.RangeOffset = evidence.Ptr(int64(rng.Offset))
+	result.RangeLength = evidence.Ptr(int64(len(res.Data)))
+	setContent(result, res.Data, encoding)
+	return regletsdk.Evidence{}, true
+}
+
+// setContent stores content in result, as text when an encoding is given and
+// base64 otherwise.
+func setContent(result *evidence.File, content []byte, encoding string) {
+	if encoding != "" {
+		text, charset := wireformat.DecodeText(content, encoding)
+		result.Content = evidence.Ptr(text)
+		result.Charset = evidence.Ptr(charset)
+		return
+	}
+
+	result.ContentB64 = evidence.Ptr(base64.StdEncoding.EncodeToString(content))
+	result.Encoding = evidence.Ptr("base64")
+}
+
+// calculateHash calculates SHA256 hash of file content. Returns failed
+// Evidence and false on error.
+func calculateHash(f *os.File, result *evidence.File) (regletsdk.Evidence, bool) {
 	if f == nil {
-		return regletsdk.Failure("fs", "hash calculation failed: file not readable")
+		return regletsdk.Failure("fs", "hash calculation failed: file not readable"), false
 	}
 
 	if _, err := f.Seek(0, 0); err != nil {
-		return regletsdk.Failure("fs", fmt.Sprintf("seek for hash failed: %v", err))
+		return regletsdk.Failure("fs", fmt.Sprintf("seek for hash failed: %v", err)), false
 	}
 
 	hasher := sha256.New()
 	if _, err := io.Copy(hasher, f); err != nil {
-		return regletsdk.Failure("fs", fmt.Sprintf("hash calculation failed: %v", err))
+		return regletsdk.Failure("fs", fmt.Sprintf("hash calculation failed: %v", err)), false
 	}
 
-	result["sha256"] = hex.EncodeToString(hasher.Sum(nil))
-	return nil
+	result.SHA256 = evidence.Ptr(hex.EncodeToString(hasher.Sum(nil)))
+	return regletsdk.Evidence{}, true
+}
+
+// hostHash has the host stream the file through SHA256, so hashing a
+// multi-gigabyte file never reads it into plugin memory. Returns failed
+// Evidence and false on error.
+func hostHash(ctx context.Context, path string, result *evidence.File) (regletsdk.Evidence, bool) {
+	res, err := checksum.File(ctx, path, checksum.SHA256)
+	if err != nil {
+		return regletsdk.Failure("fs", fmt.Sprintf("hash calculation failed: %v", err)), false
+	}
+
+	result.SHA256 = evidence.Ptr(res.Digest)
+	return regletsdk.Evidence{}, true
 }
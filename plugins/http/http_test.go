@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	regletsdk "github.com/reglet-dev/reglet/sdk"
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 )
 
 func TestHTTPPlugin_Check_Success(t *testing.T) {
@@ -33,7 +34,7 @@ func TestHTTPPlugin_Check_Success(t *testing.T) {
 		t.Errorf("Expected status true, got false. Error: %v", evidence.Error)
 	}
 
-	if statusCode, ok := evidence.Data["status_code"].(int); !ok || statusCode != 200 {
+	if statusCode := httpEvidence(t, evidence).StatusCode; statusCode != 200 {
 		t.Errorf("Expected status code 200, got %v", statusCode)
 	}
 }
@@ -55,7 +56,7 @@ func TestHTTPPlugin_Check_ExpectedStatus_Pass(t *testing.T) {
 		t.Fatalf("Check returned error: %v", err)
 	}
 
-	if evidence.Data["expectation_failed"] == true {
+	if failed := httpEvidence(t, evidence).ExpectationFailed; failed != nil && *failed {
 		t.Errorf("Expected expectation to pass")
 	}
 }
@@ -77,7 +78,7 @@ func TestHTTPPlugin_Check_ExpectedStatus_Fail(t *testing.T) {
 		t.Fatalf("Check returned error: %v", err)
 	}
 
-	if evidence.Data["expectation_failed"] != true {
+	if failed := httpEvidence(t, evidence).ExpectationFailed; failed == nil || !*failed {
 		t.Errorf("Expected expectation_failed to be true")
 	}
 }
@@ -99,7 +100,7 @@ func TestHTTPPlugin_Check_ExpectedBody_Pass(t *testing.T) {
 		t.Fatalf("Check returned error: %v", err)
 	}
 
-	if evidence.Data["expectation_failed"] == true {
+	if failed := httpEvidence(t, evidence).ExpectationFailed; failed != nil && *failed {
 		t.Errorf("Expected expectation to pass")
 	}
 }
@@ -121,7 +122,7 @@ func TestHTTPPlugin_Check_ExpectedBody_Fail(t *testing.T) {
 		t.Fatalf("Check returned error: %v", err)
 	}
 
-	if evidence.Data["expectation_failed"] != true {
+	if failed := httpEvidence(t, evidence).ExpectationFailed; failed == nil || !*failed {
 		t.Errorf("Expected expectation_failed to be true")
 	}
 }
@@ -147,7 +148,17 @@ func TestHTTPPlugin_Check_Method(t *testing.T) {
 		t.Fatalf("Check returned error: %v", err)
 	}
 
-	if statusCode, ok := evidence.Data["status_code"].(int); !ok || statusCode != 200 {
+	if statusCode := httpEvidence(t, evidence).StatusCode; statusCode != 200 {
 		t.Errorf("Expected status code 200, got %v", statusCode)
 	}
 }
+
+// httpEvidence returns the typed evidence data the plugin reported.
+func httpEvidence(t *testing.T, ev regletsdk.Evidence) evidence.HTTP {
+	t.Helper()
+	data, ok := ev.Typed.(evidence.HTTP)
+	if !ok {
+		t.Fatalf("Expected evidence.HTTP data, got %T", ev.Typed)
+	}
+	return data
+}
@@ -14,6 +14,7 @@ import (
 
 	regletsdk "github.com/reglet-dev/reglet/sdk"
 	regletnet "github.com/reglet-dev/reglet/sdk/net"
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 )
 
 // httpPlugin implements the sdk.Plugin interface.
@@ -68,10 +69,10 @@ func (p *httpPlugin) Check(ctx context.Context, config regletsdk.Config) (reglet
 	result := buildHTTPResult(resp, respBody, duration, cfg)
 
 	if err := validateExpectations(cfg, resp, respBody, result); err != nil {
-		return regletsdk.Success(result), nil
+		return regletsdk.TypedSuccess(*result), nil
 	}
 
-	return regletsdk.Success(result), nil
+	return regletsdk.TypedSuccess(*result), nil
 }
 
 // parseHTTPConfig validates and parses the config with defaults.
@@ -124,18 +125,18 @@ func (p *httpPlugin) executeRequest(ctx context.Context, cfg *HTTPConfig) (*http
 	return resp, respBodyBytes, duration, nil
 }
 
-// buildHTTPResult constructs the result map from the response.
-func buildHTTPResult(resp *http.Response, respBody []byte, duration int64, cfg *HTTPConfig) map[string]interface{} {
+// buildHTTPResult constructs the evidence data from the response.
+func buildHTTPResult(resp *http.Response, respBody []byte, duration int64, cfg *HTTPConfig) *evidence.HTTP {
 	hash := sha256.Sum256(respBody)
 	bodyHash := hex.EncodeToString(hash[:])
 
-	result := map[string]interface{}{
-		"status_code":      resp.StatusCode,
-		"response_time_ms": duration,
-		"protocol":         resp.Proto,
-		"headers":          resp.Header,
-		"body_size":        len(respBody),
-		"body_sha256":      bodyHash,
+	result := &evidence.HTTP{
+		StatusCode:     int64(resp.StatusCode),
+		ResponseTimeMs: duration,
+		Protocol:       resp.Proto,
+		Headers:        resp.Header,
+		BodySize:       int64(len(respBody)),
+		BodySHA256:     bodyHash,
 	}
 
 	addBodyContent(result, respBody, cfg.BodyPreviewLength)
@@ -143,33 +144,33 @@ func buildHTTPResult(resp *http.Response, respBody []byte, duration int64, cfg *
 }
 
 // addBodyContent adds body content to result based on configuration.
-func addBodyContent(result map[string]interface{}, respBody []byte, previewLength int) {
+func addBodyContent(result *evidence.HTTP, respBody []byte, previewLength int) {
 	switch {
 	case previewLength == -1:
-		result["body"] = string(respBody)
+		result.Body = evidence.Ptr(string(respBody))
 	case previewLength > 0:
 		respBodyStr := string(respBody)
 		if len(respBodyStr) > previewLength {
-			result["body_preview"] = respBodyStr[:previewLength] + "..."
-			result["body_truncated"] = true
+			result.BodyPreview = evidence.Ptr(respBodyStr[:previewLength] + "...")
+			result.BodyTruncated = evidence.Ptr(true)
 		} else {
-			result["body"] = respBodyStr
+			result.Body = evidence.Ptr(respBodyStr)
 		}
 	}
 	// If previewLength == 0, only include hash and size (no body content)
 }
 
 // validateExpectations checks if response matches expected values.
-func validateExpectations(cfg *HTTPConfig, resp *http.Response, respBody []byte, result map[string]interface{}) error {
+func validateExpectations(cfg *HTTPConfig, resp *http.Response, respBody []byte, result *evidence.HTTP) error {
 	if cfg.ExpectedStatus != 0 && resp.StatusCode != cfg.ExpectedStatus {
-		result["expectation_failed"] = true
-		result["expectation_error"] = fmt.Sprintf("expected status %d, got %d", cfg.ExpectedStatus, resp.StatusCode)
+		result.ExpectationFailed = evidence.Ptr(true)
+		result.ExpectationError = evidence.Ptr(fmt.Sprintf("expected status %d, got %d", cfg.ExpectedStatus, resp.StatusCode))
 		return fmt.Errorf("status mismatch")
 	}
 
 	if cfg.ExpectedBodyContains != "" && !strings.Contains(string(respBody), cfg.ExpectedBodyContains) {
-		result["expectation_failed"] = true
-		result["expectation_error"] = fmt.Sprintf("expected body to contain '%s'", cfg.ExpectedBodyContains)
+		result.ExpectationFailed = evidence.Ptr(true)
+		result.ExpectationError = evidence.Ptr(fmt.Sprintf("expected body to contain '%s'", cfg.ExpectedBodyContains))
 		return fmt.Errorf("body mismatch")
 	}
 
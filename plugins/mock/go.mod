@@ -0,0 +1,28 @@
+module github.com/reglet-dev/reglet/plugins/mock
+
+go 1.25.4
+
+replace (
+	github.com/reglet-dev/reglet/sdk => ../../sdk/go
+	github.com/reglet-dev/reglet/wireformat => ../../wireformat
+)
+
+require github.com/reglet-dev/reglet/sdk v0.0.0-00010101000000-000000000000
+
+require (
+	github.com/bahlo/generic-list-go v0.2.0 // indirect
+	github.com/buger/jsonparser v1.1.1 // indirect
+	github.com/gabriel-vasile/mimetype v1.4.10 // indirect
+	github.com/go-playground/locales v0.14.1 // indirect
+	github.com/go-playground/universal-translator v0.18.1 // indirect
+	github.com/go-playground/validator/v10 v10.28.0 // indirect
+	github.com/invopop/jsonschema v0.13.0 // indirect
+	github.com/leodido/go-urn v1.4.0 // indirect
+	github.com/mailru/easyjson v0.7.7 // indirect
+	github.com/reglet-dev/reglet/wireformat v0.0.0 // indirect
+	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
+	golang.org/x/crypto v0.45.0 // indirect
+	golang.org/x/sys v0.38.0 // indirect
+	golang.org/x/text v0.31.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,16 @@
+// Package main provides a mock plugin for Reglet.
+// This is compiled to WASM and loaded by the Reglet runtime.
+//go:build wasip1
+
+package main
+
+import (
+	regletsdk "github.com/reglet-dev/reglet/sdk"
+)
+
+func init() {
+	regletsdk.Register(&mockPlugin{})
+}
+
+// main is the entry point for the WASM module.
+func main() {}
@@ -0,0 +1,101 @@
+//go:build wasip1
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	regletsdk "github.com/reglet-dev/reglet/sdk"
+)
+
+func TestMockPlugin_Check_ReturnsData(t *testing.T) {
+	plugin := &mockPlugin{}
+	config := regletsdk.Config{
+		"data": map[string]interface{}{"exists": true, "size": 42},
+		"raw":  "-rw-r--r-- 1 root root 42 passwd",
+	}
+
+	evidence, err := plugin.Check(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+
+	if !evidence.Status {
+		t.Errorf("Expected status true, got false")
+	}
+	if evidence.Error != nil {
+		t.Errorf("Expected no error, got %v", evidence.Error)
+	}
+	if evidence.Data["exists"] != true {
+		t.Errorf("Expected data.exists true, got %v", evidence.Data["exists"])
+	}
+	if evidence.Raw == nil || *evidence.Raw != "-rw-r--r-- 1 root root 42 passwd" {
+		t.Errorf("Expected raw output to be returned, got %v", evidence.Raw)
+	}
+}
+
+func TestMockPlugin_Check_ReportsError(t *testing.T) {
+	plugin := &mockPlugin{}
+	config := regletsdk.Config{
+		"error":         "dial tcp 10.0.0.1:443: i/o timeout",
+		"error_type":    "network",
+		"error_code":    "ETIMEDOUT",
+		"error_timeout": true,
+	}
+
+	evidence, err := plugin.Check(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+
+	if evidence.Status {
+		t.Errorf("Expected status false, got true")
+	}
+	if evidence.Error == nil {
+		t.Fatalf("Expected error detail")
+	}
+	if evidence.Error.Type != "network" || evidence.Error.Code != "ETIMEDOUT" || !evidence.Error.IsTimeout {
+		t.Errorf("Unexpected error detail: %+v", evidence.Error)
+	}
+}
+
+func TestMockPlugin_Check_StatusOverride(t *testing.T) {
+	plugin := &mockPlugin{}
+	config := regletsdk.Config{
+		"status": false,
+		"data":   map[string]interface{}{"connected": false},
+	}
+
+	evidence, err := plugin.Check(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+
+	if evidence.Status {
+		t.Errorf("Expected status false, got true")
+	}
+	if evidence.Error != nil {
+		t.Errorf("Expected no error, got %v", evidence.Error)
+	}
+}
+
+func TestMockPlugin_Check_InvalidConfig(t *testing.T) {
+	plugin := &mockPlugin{}
+	config := regletsdk.Config{
+		"error":      "boom",
+		"error_type": "kaboom",
+	}
+
+	evidence, err := plugin.Check(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+
+	if evidence.Status {
+		t.Errorf("Expected status false, got true")
+	}
+	if evidence.Error == nil || evidence.Error.Type != "config" {
+		t.Errorf("Expected config error, got %v", evidence.Error)
+	}
+}
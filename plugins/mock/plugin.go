@@ -0,0 +1,80 @@
+//go:build wasip1
+
+package main
+
+import (
+	"context"
+
+	regletsdk "github.com/reglet-dev/reglet/sdk"
+)
+
+// mockPlugin implements the sdk.Plugin interface. It touches no system: the
+// evidence it returns is read from its own configuration, for examples,
+// profile tests and CI pipelines that must not reach real infrastructure.
+type mockPlugin struct{}
+
+// Describe returns plugin metadata. The plugin requires no capabilities.
+func (p *mockPlugin) Describe(ctx context.Context) (regletsdk.Metadata, error) {
+	return regletsdk.Metadata{
+		Name:         "mock",
+		Version:      "1.0.0",
+		Description:  "Returns the evidence given in its config without touching any system",
+		Capabilities: []regletsdk.Capability{},
+	}, nil
+}
+
+// MockConfig is the configuration of the mock plugin.
+type MockConfig struct {
+	Data         map[string]interface{} `json:"data,omitempty" description:"Evidence data to return"`
+	Status       *bool                  `json:"status,omitempty" description:"Reported status (default: true, or false when error is set)"`
+	Raw          string                 `json:"raw,omitempty" description:"Raw evidence output to return"`
+	Error        string                 `json:"error,omitempty" description:"Report an error with this message instead of succeeding"`
+	ErrorType    string                 `json:"error_type,omitempty" validate:"omitempty,oneof=network timeout config capability validation internal" description:"Type of the reported error (default: internal)"`
+	ErrorCode    string                 `json:"error_code,omitempty" description:"Code of the reported error"`
+	ErrorTimeout bool                   `json:"error_timeout,omitempty" description:"Mark the reported error as a timeout"`
+}
+
+// Schema returns the JSON schema for the plugin's configuration.
+func (p *mockPlugin) Schema(ctx context.Context) ([]byte, error) {
+	return regletsdk.GenerateSchema(MockConfig{})
+}
+
+// Check returns the evidence described by the configuration.
+func (p *mockPlugin) Check(ctx context.Context, config regletsdk.Config) (regletsdk.Evidence, error) {
+	var cfg MockConfig
+	if err := regletsdk.ValidateConfig(config, &cfg); err != nil {
+		return regletsdk.Evidence{
+			Status: false,
+			Error:  regletsdk.ToErrorDetail(&regletsdk.ConfigError{Err: err}),
+		}, nil
+	}
+
+	return mockEvidence(cfg), nil
+}
+
+// mockEvidence builds the evidence described by cfg.
+func mockEvidence(cfg MockConfig) regletsdk.Evidence {
+	ev := regletsdk.Success(cfg.Data)
+	if cfg.Raw != "" {
+		ev.Raw = &cfg.Raw
+	}
+
+	if cfg.Error != "" {
+		errType := cfg.ErrorType
+		if errType == "" {
+			errType = "internal"
+		}
+		ev.Status = false
+		ev.Error = &regletsdk.ErrorDetail{
+			Message:   cfg.Error,
+			Type:      errType,
+			Code:      cfg.ErrorCode,
+			IsTimeout: cfg.ErrorTimeout,
+		}
+	}
+
+	if cfg.Status != nil {
+		ev.Status = *cfg.Status
+	}
+	return ev
+}
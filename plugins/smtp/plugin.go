@@ -8,6 +8,7 @@ import (
 
 	regletsdk "github.com/reglet-dev/reglet/sdk"
 	regletnet "github.com/reglet-dev/reglet/sdk/net"
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 )
 
 // smtpPlugin implements the sdk.Plugin interface.
@@ -84,21 +85,21 @@ func (p *smtpPlugin) Check(ctx context.Context, config regletsdk.Config) (reglet
 	}
 
 	// Prepare evidence data from result
-	data := map[string]interface{}{
-		"connected":        result.Connected,
-		"address":          result.Address,
-		"response_time_ms": result.ResponseTimeMs,
-		"banner":           result.Banner,
+	data := evidence.SMTP{
+		Connected:      result.Connected,
+		Address:        result.Address,
+		ResponseTimeMs: result.ResponseTimeMs,
+		Banner:         result.Banner,
 	}
 
 	if result.TLS {
-		data["tls"] = true
-		data["tls_version"] = result.TLSVersion
-		data["tls_cipher_suite"] = result.TLSCipherSuite
-		data["tls_server_name"] = result.TLSServerName
+		data.TLS = evidence.Ptr(true)
+		data.TLSVersion = evidence.Ptr(result.TLSVersion)
+		data.TLSCipherSuite = evidence.Ptr(result.TLSCipherSuite)
+		data.TLSServerName = evidence.Ptr(result.TLSServerName)
 	}
 
-	return regletsdk.Success(data), nil
+	return regletsdk.TypedSuccess(data), nil
 }
 
 func main() {
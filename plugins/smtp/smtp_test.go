@@ -9,6 +9,7 @@ import (
 
 	regletsdk "github.com/reglet-dev/reglet/sdk"
 	regletnet "github.com/reglet-dev/reglet/sdk/net"
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 )
 
 func TestSMTPPlugin_Check_Success(t *testing.T) {
@@ -36,8 +37,8 @@ func TestSMTPPlugin_Check_Success(t *testing.T) {
 		t.Errorf("Expected status true, got false")
 	}
 
-	if evidence.Data["banner"] != "220 smtp.example.com ESMTP" {
-		t.Errorf("Expected banner to be set, got %v", evidence.Data["banner"])
+	if banner := smtpEvidence(t, evidence).Banner; banner != "220 smtp.example.com ESMTP" {
+		t.Errorf("Expected banner to be set, got %v", banner)
 	}
 }
 
@@ -95,12 +96,12 @@ func TestSMTPPlugin_Check_WithTLS(t *testing.T) {
 		t.Errorf("Expected status true, got false")
 	}
 
-	if evidence.Data["tls"] != true {
+	if tls := smtpEvidence(t, evidence).TLS; tls == nil || !*tls {
 		t.Errorf("Expected TLS to be true")
 	}
 
-	if evidence.Data["tls_version"] != "TLS 1.3" {
-		t.Errorf("Expected TLS version 1.3, got %v", evidence.Data["tls_version"])
+	if version := smtpEvidence(t, evidence).TLSVersion; version == nil || *version != "TLS 1.3" {
+		t.Errorf("Expected TLS version 1.3, got %v", version)
 	}
 }
 
@@ -136,7 +137,7 @@ func TestSMTPPlugin_Check_WithStartTLS(t *testing.T) {
 		t.Errorf("Expected status true, got false")
 	}
 
-	if evidence.Data["tls"] != true {
+	if tls := smtpEvidence(t, evidence).TLS; tls == nil || !*tls {
 		t.Errorf("Expected TLS to be true after STARTTLS")
 	}
 }
@@ -164,3 +165,13 @@ func TestSMTPPlugin_Check_InvalidConfig(t *testing.T) {
 		t.Errorf("Expected config error, got %v", evidence.Error)
 	}
 }
+
+// smtpEvidence returns the typed evidence data the plugin reported.
+func smtpEvidence(t *testing.T, ev regletsdk.Evidence) evidence.SMTP {
+	t.Helper()
+	data, ok := ev.Typed.(evidence.SMTP)
+	if !ok {
+		t.Fatalf("Expected evidence.SMTP data, got %T", ev.Typed)
+	}
+	return data
+}
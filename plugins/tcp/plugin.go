@@ -9,6 +9,7 @@ import (
 
 	regletsdk "github.com/reglet-dev/reglet/sdk"
 	regletnet "github.com/reglet-dev/reglet/sdk/net"
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 )
 
 // tcpPlugin implements the sdk.Plugin interface.
@@ -85,41 +86,41 @@ func (p *tcpPlugin) Check(ctx context.Context, config regletsdk.Config) (reglets
 	}
 
 	// Prepare evidence data from result
-	data := map[string]interface{}{
-		"connected":        result.Connected,
-		"address":          result.Address,
-		"response_time_ms": result.ResponseTimeMs,
-		"remote_addr":      result.RemoteAddr,
-		"local_addr":       result.LocalAddr,
+	data := evidence.TCP{
+		Connected:      result.Connected,
+		Address:        result.Address,
+		ResponseTimeMs: result.ResponseTimeMs,
+		RemoteAddr:     result.RemoteAddr,
+		LocalAddr:      result.LocalAddr,
 	}
 
 	if result.TLS {
-		data["tls"] = true
-		data["tls_version"] = result.TLSVersion
-		data["tls_cipher_suite"] = result.TLSCipherSuite
-		data["tls_server_name"] = result.TLSServerName
+		data.TLS = evidence.Ptr(true)
+		data.TLSVersion = evidence.Ptr(result.TLSVersion)
+		data.TLSCipherSuite = evidence.Ptr(result.TLSCipherSuite)
+		data.TLSServerName = evidence.Ptr(result.TLSServerName)
 		if result.TLSCertSubject != "" {
-			data["tls_cert_subject"] = result.TLSCertSubject
-			data["tls_cert_issuer"] = result.TLSCertIssuer
+			data.TLSCertSubject = evidence.Ptr(result.TLSCertSubject)
+			data.TLSCertIssuer = evidence.Ptr(result.TLSCertIssuer)
 		}
 		if result.TLSCertNotAfter != nil {
-			data["tls_cert_not_after"] = result.TLSCertNotAfter.Format(time.RFC3339)
+			data.TLSCertNotAfter = evidence.Ptr(result.TLSCertNotAfter.Format(time.RFC3339))
 			// Calculate days remaining
-			days := int(time.Until(*result.TLSCertNotAfter).Hours() / 24)
-			data["tls_cert_days_remaining"] = days
+			days := int64(time.Until(*result.TLSCertNotAfter).Hours() / 24)
+			data.TLSCertDaysRemaining = evidence.Ptr(days)
 		}
 	}
 
 	// Check TLS version expectation
 	if cfg.ExpectedTLSVersion != "" {
 		if !isTLSVersionAtLeast(result.TLSVersion, cfg.ExpectedTLSVersion) {
-			data["expectation_failed"] = true
-			data["expectation_error"] = fmt.Sprintf("expected TLS version >= %s, got %s", cfg.ExpectedTLSVersion, result.TLSVersion)
-			return regletsdk.Success(data), nil
+			data.ExpectationFailed = evidence.Ptr(true)
+			data.ExpectationError = evidence.Ptr(fmt.Sprintf("expected TLS version >= %s, got %s", cfg.ExpectedTLSVersion, result.TLSVersion))
+			return regletsdk.TypedSuccess(data), nil
 		}
 	}
 
-	return regletsdk.Success(data), nil
+	return regletsdk.TypedSuccess(data), nil
 }
 
 // isTLSVersionAtLeast checks if actual TLS version meets the minimum requirement
@@ -9,6 +9,7 @@ import (
 
 	regletsdk "github.com/reglet-dev/reglet/sdk"
 	regletnet "github.com/reglet-dev/reglet/sdk/net"
+	"github.com/reglet-dev/reglet/wireformat/evidence"
 )
 
 func TestTCPPlugin_Check_Success(t *testing.T) {
@@ -83,7 +84,7 @@ func TestTCPPlugin_Check_TLS_Version_Pass(t *testing.T) {
 		t.Fatalf("Check returned error: %v", err)
 	}
 
-	if evidence.Data["expectation_failed"] == true {
+	if failed := tcpEvidence(t, evidence).ExpectationFailed; failed != nil && *failed {
 		t.Errorf("Expected expectation to pass")
 	}
 }
@@ -110,7 +111,17 @@ func TestTCPPlugin_Check_TLS_Version_Fail(t *testing.T) {
 		t.Fatalf("Check returned error: %v", err)
 	}
 
-	if evidence.Data["expectation_failed"] != true {
+	if failed := tcpEvidence(t, evidence).ExpectationFailed; failed == nil || !*failed {
 		t.Errorf("Expected expectation_failed to be true")
 	}
 }
+
+// tcpEvidence returns the typed evidence data the plugin reported.
+func tcpEvidence(t *testing.T, ev regletsdk.Evidence) evidence.TCP {
+	t.Helper()
+	data, ok := ev.Typed.(evidence.TCP)
+	if !ok {
+		t.Fatalf("Expected evidence.TCP data, got %T", ev.Typed)
+	}
+	return data
+}
@@ -0,0 +1,102 @@
+//go:build wasip1
+
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/reglet-dev/reglet/sdk/internal/abi"
+	sdkcontext "github.com/reglet-dev/reglet/sdk/internal/context"
+	"github.com/reglet-dev/reglet/wireformat"
+)
+
+//go:wasmimport reglet_host archive_list
+func host_archive_list(reqPacked uint64) uint64
+
+//go:wasmimport reglet_host archive_read_entry
+func host_archive_read_entry(reqPacked uint64) uint64
+
+// List returns the members of a tar or zip archive on the host.
+// Requires "fs:read:<path>" capability.
+func List(ctx context.Context, req ListRequest) (*Listing, error) {
+	var wireRes wireformat.ArchiveListResponseWire
+	err := call(host_archive_list, wireformat.ArchiveListRequestWire{
+		Context:    sdkcontext.ContextToWire(ctx),
+		Path:       req.Path,
+		Format:     req.Format,
+		MaxEntries: req.MaxEntries,
+	}, &wireRes)
+	if err != nil {
+		return nil, err
+	}
+	if wireRes.Error != nil {
+		return nil, wireRes.Error
+	}
+
+	listing := &Listing{Format: wireRes.Format, Truncated: wireRes.Truncated}
+	for _, entry := range wireRes.Entries {
+		listing.Entries = append(listing.Entries, fromWire(entry))
+	}
+	return listing, nil
+}
+
+// ReadEntry returns the contents of one regular file in a tar or zip archive
+// on the host. Requires "fs:read:<path>" capability.
+func ReadEntry(ctx context.Context, req ReadRequest) (*Content, error) {
+	var wireRes wireformat.ArchiveReadResponseWire
+	err := call(host_archive_read_entry, wireformat.ArchiveReadRequestWire{
+		Context:  sdkcontext.ContextToWire(ctx),
+		Path:     req.Path,
+		Format:   req.Format,
+		Entry:    req.Entry,
+		MaxBytes: req.MaxBytes,
+	}, &wireRes)
+	if err != nil {
+		return nil, err
+	}
+	if wireRes.Error != nil {
+		return nil, wireRes.Error
+	}
+
+	return &Content{
+		Entry:     fromWire(wireRes.Entry),
+		Data:      wireRes.Data,
+		Truncated: wireRes.Truncated,
+	}, nil
+}
+
+// call sends a JSON request to a host function and decodes its response.
+func call(fn func(uint64) uint64, request, response any) error {
+	reqData, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	reqPacked := abi.PtrFromBytes(reqData)
+	defer abi.DeallocatePacked(reqPacked)
+
+	resPacked := fn(reqPacked)
+	resBytes := abi.BytesFromPtr(resPacked)
+	if resBytes == nil {
+		return fmt.Errorf("host returned null response")
+	}
+	defer abi.DeallocatePacked(resPacked)
+
+	if err := json.Unmarshal(resBytes, response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+func fromWire(entry wireformat.ArchiveEntryWire) Entry {
+	return Entry{
+		Name:       entry.Name,
+		Type:       entry.Type,
+		Size:       entry.Size,
+		Mode:       entry.Mode,
+		ModTime:    entry.ModTime,
+		LinkTarget: entry.LinkTarget,
+	}
+}
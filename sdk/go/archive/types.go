@@ -0,0 +1,53 @@
+// Package archive inspects tar and zip archives on the host for plugins,
+// e.g. to check a backup contains the expected files. The host reads the
+// archive; only listings and the requested member cross into guest memory.
+package archive
+
+import "time"
+
+// Formats. Format is detected from the file when a request leaves it empty.
+const (
+	Tar    = "tar"
+	TarGz  = "tar.gz"
+	TarZst = "tar.zst"
+	Zip    = "zip"
+)
+
+// Entry describes one archive member.
+type Entry struct {
+	Name       string
+	Type       string // "file", "dir", "symlink" or "other"
+	Size       int64
+	Mode       uint32 // Permission bits
+	ModTime    time.Time
+	LinkTarget string
+}
+
+// Listing is the result of List.
+type Listing struct {
+	Format    string
+	Entries   []Entry
+	Truncated bool // The archive has more than MaxEntries members
+}
+
+// ListRequest selects an archive to list.
+type ListRequest struct {
+	Path       string // Absolute host path
+	Format     string
+	MaxEntries int // Host default (1000) when 0
+}
+
+// ReadRequest selects an archive member to read.
+type ReadRequest struct {
+	Path     string
+	Format   string
+	Entry    string // Member name, as listed
+	MaxBytes int64  // Host default (1MB, at most 16MB) when 0
+}
+
+// Content is the result of ReadEntry.
+type Content struct {
+	Entry     Entry
+	Data      []byte
+	Truncated bool // Data holds only the first MaxBytes
+}
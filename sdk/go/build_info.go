@@ -0,0 +1,21 @@
+package sdk
+
+import "runtime"
+
+// Build metadata, set by "reglet plugins build" with -ldflags -X.
+var (
+	buildVersion string
+	buildCommit  string
+	buildTime    string
+)
+
+// applyBuildInfo records the build metadata in plugin metadata. A version
+// given at build time replaces the one the plugin declares.
+func applyBuildInfo(m *Metadata) {
+	if buildVersion != "" {
+		m.Version = buildVersion
+	}
+	m.Commit = buildCommit
+	m.BuildTime = buildTime
+	m.GoVersion = runtime.Version()
+}
@@ -0,0 +1,26 @@
+package sdk
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyBuildInfo(t *testing.T) {
+	defer func(version, commit, at string) {
+		buildVersion, buildCommit, buildTime = version, commit, at
+	}(buildVersion, buildCommit, buildTime)
+
+	m := Metadata{Name: "file", Version: "1.0.0"}
+	applyBuildInfo(&m)
+	assert.Equal(t, "1.0.0", m.Version, "the declared version is kept without a build version")
+	assert.Empty(t, m.Commit)
+	assert.Equal(t, runtime.Version(), m.GoVersion)
+
+	buildVersion, buildCommit, buildTime = "1.2.0", "4f2a9c1", "2026-03-01T12:00:00Z"
+	applyBuildInfo(&m)
+	assert.Equal(t, "1.2.0", m.Version)
+	assert.Equal(t, "4f2a9c1", m.Commit)
+	assert.Equal(t, "2026-03-01T12:00:00Z", m.BuildTime)
+}
@@ -0,0 +1,53 @@
+//go:build wasip1
+
+package checksum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/reglet-dev/reglet/sdk/internal/abi"
+	sdkcontext "github.com/reglet-dev/reglet/sdk/internal/context"
+	"github.com/reglet-dev/reglet/wireformat"
+)
+
+//go:wasmimport reglet_host hash_compute
+func host_hash_compute(reqPacked uint64) uint64
+
+// File hashes the regular file at the absolute host path with algorithm
+// (SHA256 when empty). Requires "fs:read:<path>" capability.
+func File(ctx context.Context, path, algorithm string) (*Result, error) {
+	reqData, err := json.Marshal(wireformat.HashRequestWire{
+		Context:   sdkcontext.ContextToWire(ctx),
+		Path:      path,
+		Algorithm: algorithm,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	reqPacked := abi.PtrFromBytes(reqData)
+	defer abi.DeallocatePacked(reqPacked)
+
+	resPacked := host_hash_compute(reqPacked)
+	resBytes := abi.BytesFromPtr(resPacked)
+	if resBytes == nil {
+		return nil, fmt.Errorf("host returned null response")
+	}
+	defer abi.DeallocatePacked(resPacked)
+
+	var wireRes wireformat.HashResponseWire
+	if err := json.Unmarshal(resBytes, &wireRes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if wireRes.Error != nil {
+		return nil, wireRes.Error
+	}
+
+	return &Result{
+		Algorithm: wireRes.Algorithm,
+		Digest:    wireRes.Digest,
+		Size:      wireRes.Size,
+	}, nil
+}
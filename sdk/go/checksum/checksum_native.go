@@ -0,0 +1,65 @@
+//go:build !wasip1
+
+package checksum
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/zeebo/blake3"
+)
+
+// File hashes the regular file at path with algorithm (SHA256 when empty).
+// Outside WASM the file is read directly, without a capability check.
+func File(ctx context.Context, path, algorithm string) (*Result, error) {
+	var h hash.Hash
+	switch algorithm {
+	case "", SHA256:
+		algorithm, h = SHA256, sha256.New()
+	case SHA512:
+		h = sha512.New()
+	case BLAKE3:
+		h = blake3.New()
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm %q (want sha256, sha512 or blake3)", algorithm)
+	}
+
+	f, err := os.Open(path) //nolint:gosec // G304: path is chosen by the plugin, as with the host function
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", path)
+	}
+
+	size, err := io.Copy(h, &contextReader{ctx: ctx, r: f})
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Algorithm: algorithm, Digest: hex.EncodeToString(h.Sum(nil)), Size: size}, nil
+}
+
+// contextReader stops reading once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,38 @@
+//go:build !wasip1
+
+package checksum
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0o600))
+
+	tests := []struct {
+		algorithm string
+		want      string
+	}{
+		{"", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{SHA512, "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043"},
+		{BLAKE3, "ea8f163db38682925e4491c5e58d4bb3506ef8c14eb78a86e908c5624a67200f"},
+	}
+	for _, tt := range tests {
+		res, err := File(context.Background(), path, tt.algorithm)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, res.Digest)
+		assert.Equal(t, int64(5), res.Size)
+	}
+
+	_, err := File(context.Background(), path, "md5")
+	assert.Error(t, err)
+	_, err = File(context.Background(), filepath.Dir(path), SHA256)
+	assert.Error(t, err)
+}
@@ -0,0 +1,17 @@
+// Package checksum hashes host files for plugins. In WASM the host reads
+// and hashes the file, so large files never pass through guest memory.
+package checksum
+
+// Supported algorithms.
+const (
+	SHA256 = "sha256"
+	SHA512 = "sha512"
+	BLAKE3 = "blake3"
+)
+
+// Result is the digest of a file.
+type Result struct {
+	Algorithm string
+	Digest    string // Lowercase hex
+	Size      int64  // Bytes hashed
+}
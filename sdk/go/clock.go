@@ -0,0 +1,15 @@
+//go:build wasip1
+
+package sdk
+
+import "time"
+
+//go:wasmimport reglet_host time_now
+func host_time_now() uint64
+
+// Now returns the host's current time. It matches time.Now except that the
+// monotonic reading is dropped, and follows a fixed clock when the host runs
+// with one (reglet check --clock), so time-relative checks are reproducible.
+func Now() time.Time {
+	return time.Unix(0, int64(host_time_now())) //nolint:gosec // G115: host sends i64 nanoseconds
+}
@@ -0,0 +1,11 @@
+//go:build !wasip1
+
+package sdk
+
+import "time"
+
+// Now returns the current time. Native plugins run in the host process and
+// read the system clock.
+func Now() time.Time {
+	return time.Now()
+}
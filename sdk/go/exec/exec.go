@@ -22,6 +22,9 @@ type CommandRequest struct {
 	Dir     string
 	Env     []string
 	Timeout int // seconds
+	// Encoding of the command's output: "auto", "utf-8" (default) or
+	// "latin-1". See the wireformat Encoding constants.
+	Encoding string
 }
 
 // CommandResponse contains the result of the command execution.
@@ -31,6 +34,9 @@ type CommandResponse struct {
 	ExitCode   int
 	DurationMs int64 // Execution duration in milliseconds
 	IsTimeout  bool  // True if command timed out
+	// Charsets Stdout and Stderr were read as: "utf-8" or "latin-1"
+	StdoutCharset string
+	StderrCharset string
 }
 
 // Run executes a command on the host system.
@@ -38,11 +44,12 @@ type CommandResponse struct {
 func Run(ctx context.Context, req CommandRequest) (*CommandResponse, error) {
 	// 1. Prepare wire request with context
 	wireReq := wireformat.ExecRequestWire{
-		Context: sdkcontext.ContextToWire(ctx),
-		Command: req.Command,
-		Args:    req.Args,
-		Dir:     req.Dir,
-		Env:     req.Env,
+		Context:  sdkcontext.ContextToWire(ctx),
+		Command:  req.Command,
+		Args:     req.Args,
+		Dir:      req.Dir,
+		Env:      req.Env,
+		Encoding: req.Encoding,
 	}
 
 	reqData, err := json.Marshal(wireReq)
@@ -76,10 +83,12 @@ func Run(ctx context.Context, req CommandRequest) (*CommandResponse, error) {
 	}
 
 	return &CommandResponse{
-		Stdout:     wireRes.Stdout,
-		Stderr:     wireRes.Stderr,
-		ExitCode:   wireRes.ExitCode,
-		DurationMs: wireRes.DurationMs,
-		IsTimeout:  wireRes.IsTimeout,
+		Stdout:        wireRes.Stdout,
+		Stderr:        wireRes.Stderr,
+		ExitCode:      wireRes.ExitCode,
+		DurationMs:    wireRes.DurationMs,
+		IsTimeout:     wireRes.IsTimeout,
+		StdoutCharset: wireRes.StdoutCharset,
+		StderrCharset: wireRes.StderrCharset,
 	}, nil
 }
@@ -0,0 +1,86 @@
+//go:build !wasip1
+
+// Package exec provides command execution capabilities for plugins.
+// Outside WASM (native and process plugins) commands run directly on the
+// host with os/exec, on any operating system.
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"time"
+
+	"github.com/reglet-dev/reglet/wireformat"
+)
+
+// ErrNotWASM was returned by Run outside the WASM environment.
+//
+// Deprecated: Run now executes commands natively outside WASM.
+var ErrNotWASM = errors.New("exec: not available outside WASM environment")
+
+// CommandRequest defines the parameters for executing a command.
+type CommandRequest struct {
+	Command string
+	Args    []string
+	Dir     string
+	Env     []string
+	Timeout int // seconds
+	// Encoding of the command's output: "auto", "utf-8" (default) or
+	// "latin-1". See the wireformat Encoding constants.
+	Encoding string
+}
+
+// CommandResponse contains the result of the command execution.
+type CommandResponse struct {
+	Stdout     string
+	Stderr     string
+	ExitCode   int
+	DurationMs int64 // Execution duration in milliseconds
+	IsTimeout  bool  // True if command timed out
+	// Charsets Stdout and Stderr were read as: "utf-8" or "latin-1"
+	StdoutCharset string
+	StderrCharset string
+}
+
+// Run executes a command on the host. Like the WASM host function, the
+// command gets only the environment in req.Env, and a non-zero exit status
+// is reported in ExitCode rather than as an error.
+func Run(ctx context.Context, req CommandRequest) (*CommandResponse, error) {
+	if err := wireformat.ValidateEncoding(req.Encoding); err != nil {
+		return nil, err
+	}
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(req.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	//nolint:gosec // G204: commands are chosen by the plugin, as with the host function
+	cmd := exec.CommandContext(ctx, req.Command, req.Args...)
+	cmd.Dir = req.Dir
+	cmd.Env = append([]string{}, req.Env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	response := &CommandResponse{DurationMs: time.Since(start).Milliseconds()}
+	response.Stdout, response.StdoutCharset = wireformat.DecodeText(stdout.Bytes(), req.Encoding)
+	response.Stderr, response.StderrCharset = wireformat.DecodeText(stderr.Bytes(), req.Encoding)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		response.IsTimeout = true
+		response.ExitCode = -1
+	case errors.As(err, &exitErr):
+		response.ExitCode = exitErr.ExitCode()
+	default:
+		return nil, err
+	}
+	return response, nil
+}
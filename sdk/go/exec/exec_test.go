@@ -3,7 +3,10 @@
 package exec
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"os"
 	"testing"
 
 	"github.com/reglet-dev/reglet/wireformat"
@@ -11,8 +14,76 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// Note: Actual command execution requires WASM runtime with host functions.
-// These tests focus on wire format structures and data serialization.
+// TestHelperProcess is the command run by TestRun: it prints its arguments
+// and the value of HELPER_GREETING, then exits with status HELPER_EXIT.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("HELPER_EXIT") == "" {
+		return
+	}
+	fmt.Println(os.Args[len(os.Args)-1], os.Getenv("HELPER_GREETING"))
+	fmt.Fprintln(os.Stderr, "to stderr")
+	if os.Getenv("HELPER_EXIT") != "0" {
+		os.Exit(3)
+	}
+	os.Exit(0)
+}
+
+func TestRun(t *testing.T) {
+	self, err := os.Executable()
+	require.NoError(t, err)
+	args := []string{"-test.run=^TestHelperProcess$", "--", "hello"}
+
+	resp, err := Run(context.Background(), CommandRequest{
+		Command: self,
+		Args:    args,
+		Env:     []string{"HELPER_EXIT=0", "HELPER_GREETING=world"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.ExitCode)
+	assert.Equal(t, "hello world\n", resp.Stdout)
+	assert.Equal(t, "to stderr\n", resp.Stderr)
+
+	resp, err = Run(context.Background(), CommandRequest{Command: self, Args: args, Env: []string{"HELPER_EXIT=1"}})
+	require.NoError(t, err, "a non-zero exit status is not an error")
+	assert.Equal(t, 3, resp.ExitCode)
+	assert.Equal(t, "hello \n", resp.Stdout, "only req.Env reaches the command")
+
+	_, err = Run(context.Background(), CommandRequest{Command: "reglet-no-such-command"})
+	assert.Error(t, err)
+}
+
+func TestRun_Encoding(t *testing.T) {
+	self, err := os.Executable()
+	require.NoError(t, err)
+	run := func(arg, encoding string) *CommandResponse {
+		resp, err := Run(context.Background(), CommandRequest{
+			Command:  self,
+			Args:     []string{"-test.run=^TestHelperProcess$", "--", arg},
+			Env:      []string{"HELPER_EXIT=0"},
+			Encoding: encoding,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := run("caf\xe9", "auto") // é in Latin-1
+	assert.Equal(t, "café \n", resp.Stdout)
+	assert.Equal(t, wireformat.EncodingLatin1, resp.StdoutCharset)
+	assert.Equal(t, wireformat.EncodingUTF8, resp.StderrCharset)
+
+	resp = run("caf\xe9", "")
+	assert.Equal(t, "caf\uFFFD \n", resp.Stdout)
+
+	resp = run("café", "auto")
+	assert.Equal(t, "café \n", resp.Stdout)
+	assert.Equal(t, wireformat.EncodingUTF8, resp.StdoutCharset)
+
+	_, err = Run(context.Background(), CommandRequest{Command: self, Encoding: "ebcdic"})
+	assert.ErrorContains(t, err, "unsupported encoding")
+}
+
+// Note: The tests below cover wire format structures and data serialization
+// used by the WASM implementation.
 
 func TestCommandRequest_Serialization(t *testing.T) {
 	tests := []struct {
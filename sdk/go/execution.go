@@ -0,0 +1,32 @@
+//go:build wasip1
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/reglet-dev/reglet/sdk/internal/abi"
+)
+
+//go:wasmimport reglet_host context_get
+func host_context_get() uint64
+
+// Execution returns the run, control and observation the current call
+// belongs to. Include its IDs in logs and in the external side effects of
+// the plugin (request headers, ticket fields) to correlate them with results.
+// Fields the host does not know, e.g. outside a profile run, are empty.
+func Execution(_ context.Context) ExecutionContext {
+	responsePacked := host_context_get()
+	responseBytes := abi.BytesFromPtr(responsePacked)
+	if responseBytes == nil {
+		return ExecutionContext{}
+	}
+	defer abi.DeallocatePacked(responsePacked)
+
+	var exec ExecutionContext
+	if err := json.Unmarshal(responseBytes, &exec); err != nil {
+		return ExecutionContext{}
+	}
+	return exec
+}
@@ -0,0 +1,22 @@
+//go:build !wasip1
+
+package sdk
+
+import "context"
+
+type executionKey struct{}
+
+// withExecution attaches the execution context a process plugin received
+// with its request.
+func withExecution(ctx context.Context, exec ExecutionContext) context.Context {
+	return context.WithValue(ctx, executionKey{}, exec)
+}
+
+// Execution returns the run, control and observation the current call
+// belongs to. External process plugins receive it with each observe
+// request; plugins running in-process with --plugin-mode native do not, so
+// for them it is empty.
+func Execution(ctx context.Context) ExecutionContext {
+	exec, _ := ctx.Value(executionKey{}).(ExecutionContext)
+	return exec
+}
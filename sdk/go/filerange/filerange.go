@@ -0,0 +1,67 @@
+//go:build wasip1
+
+package filerange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/reglet-dev/reglet/sdk/internal/abi"
+	sdkcontext "github.com/reglet-dev/reglet/sdk/internal/context"
+	"github.com/reglet-dev/reglet/wireformat"
+)
+
+//go:wasmimport reglet_host file_read_range
+func host_file_read_range(reqPacked uint64) uint64
+
+// Read returns up to req.Length bytes at req.Offset of a regular file on the
+// host. Requires "fs:read:<path>" capability.
+func Read(ctx context.Context, req Request) (*Range, error) {
+	reqData, err := json.Marshal(wireformat.FileReadRangeRequestWire{
+		Context: sdkcontext.ContextToWire(ctx),
+		Path:    req.Path,
+		Offset:  req.Offset,
+		Length:  req.Length,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	reqPacked := abi.PtrFromBytes(reqData)
+	defer abi.DeallocatePacked(reqPacked)
+
+	resPacked := host_file_read_range(reqPacked)
+	resBytes := abi.BytesFromPtr(resPacked)
+	if resBytes == nil {
+		return nil, fmt.Errorf("host returned null response")
+	}
+	defer abi.DeallocatePacked(resPacked)
+
+	// Successful responses carry the data as a raw frame after the header
+	header := resBytes
+	var data []byte
+	if wireformat.IsFramed(resBytes) {
+		frames, err := wireformat.DecodeFrames(resBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response frames: %w", err)
+		}
+		if len(frames) == 0 || frames[0].ContentType != wireformat.ContentTypeJSON {
+			return nil, fmt.Errorf("framed response has no %s header", wireformat.ContentTypeJSON)
+		}
+		header = frames[0].Data
+		if len(frames) > 1 {
+			data = frames[1].Data
+		}
+	}
+
+	var wireRes wireformat.FileReadRangeResponseWire
+	if err := json.Unmarshal(header, &wireRes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if wireRes.Error != nil {
+		return nil, wireRes.Error
+	}
+
+	return &Range{Data: data, Size: wireRes.Size}, nil
+}
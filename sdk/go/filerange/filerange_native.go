@@ -0,0 +1,51 @@
+//go:build !wasip1
+
+package filerange
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Read returns up to req.Length bytes at req.Offset of the regular file at
+// req.Path. Outside WASM the file is read directly, without a capability check.
+func Read(ctx context.Context, req Request) (*Range, error) {
+	if req.Offset < 0 || req.Length <= 0 || req.Length > MaxLength {
+		return nil, fmt.Errorf("invalid range: offset must be at least 0 and length between 1 and %d, got offset %d and length %d", MaxLength, req.Offset, req.Length)
+	}
+
+	f, err := os.Open(req.Path) //nolint:gosec // G304: path is chosen by the plugin, as with the host function
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", req.Path)
+	}
+
+	data, err := io.ReadAll(&contextReader{ctx: ctx, r: io.NewSectionReader(f, req.Offset, req.Length)})
+	if err != nil {
+		return nil, err
+	}
+	return &Range{Data: data, Size: info.Size()}, nil
+}
+
+// contextReader stops reading once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
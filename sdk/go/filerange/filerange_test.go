@@ -0,0 +1,32 @@
+//go:build !wasip1
+
+package filerange
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	require.NoError(t, os.WriteFile(path, []byte("hello world"), 0o600))
+
+	res, err := Read(context.Background(), Request{Path: path, Offset: 6, Length: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "wor", string(res.Data))
+	assert.Equal(t, int64(11), res.Size)
+
+	res, err = Read(context.Background(), Request{Path: path, Offset: 6, Length: 100})
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(res.Data), "stops at the end of the file")
+
+	_, err = Read(context.Background(), Request{Path: path, Length: MaxLength + 1})
+	assert.Error(t, err)
+	_, err = Read(context.Background(), Request{Path: filepath.Dir(path), Length: 1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,20 @@
+// Package filerange reads part of a host file for plugins, e.g. the header
+// of a multi-gigabyte log or image. In WASM the host reads the range; only
+// the requested bytes cross into guest memory.
+package filerange
+
+// MaxLength is the most bytes one Read returns.
+const MaxLength = 16 << 20
+
+// Request selects the bytes to read.
+type Request struct {
+	Path   string // Absolute host path of a regular file
+	Offset int64  // First byte to read
+	Length int64  // Bytes to read, between 1 and MaxLength
+}
+
+// Range is the result of Read.
+type Range struct {
+	Data []byte // Shorter than Length at the end of the file
+	Size int64  // Size of the whole file
+}
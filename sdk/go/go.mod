@@ -5,8 +5,9 @@ go 1.25.4
 require (
 	github.com/go-playground/validator/v10 v10.28.0
 	github.com/invopop/jsonschema v0.13.0
-	github.com/stretchr/testify v1.8.4
 	github.com/reglet-dev/reglet/wireformat v0.0.0
+	github.com/stretchr/testify v1.8.4
+	github.com/zeebo/blake3 v0.2.4
 )
 
 replace github.com/reglet-dev/reglet/wireformat => ../../wireformat
@@ -18,6 +19,7 @@ require (
 	github.com/gabriel-vasile/mimetype v1.4.10 // indirect
 	github.com/go-playground/locales v0.14.1 // indirect
 	github.com/go-playground/universal-translator v0.18.1 // indirect
+	github.com/klauspost/cpuid/v2 v2.3.0 // indirect
 	github.com/leodido/go-urn v1.4.0 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
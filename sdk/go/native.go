@@ -0,0 +1,72 @@
+//go:build !wasip1
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// NativePlugin runs a Plugin in-process, outside WASM, for development and
+// debugging. Its methods return the same JSON documents as the describe,
+// schema and observe WASM exports, so a host running in native plugin mode
+// can treat it exactly like a compiled plugin.
+//
+// Only plugins that avoid sdk/net compile natively; it requires
+// GOOS=wasip1. sdk/exec runs commands directly on the host outside WASM.
+type NativePlugin struct {
+	plugin Plugin
+}
+
+// NewNativePlugin wraps a plugin for in-process execution.
+func NewNativePlugin(p Plugin) *NativePlugin {
+	return &NativePlugin{plugin: p}
+}
+
+// Describe returns the plugin metadata as JSON, including SDK version fields.
+func (n *NativePlugin) Describe(ctx context.Context) ([]byte, error) {
+	metadata, err := n.plugin.Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata.SDKVersion = Version
+	metadata.MinHostVersion = MinHostVersion
+	applyBuildInfo(&metadata)
+	return json.Marshal(metadata)
+}
+
+// Schema returns the plugin's raw JSON schema.
+func (n *NativePlugin) Schema(ctx context.Context) ([]byte, error) {
+	return n.plugin.Schema(ctx)
+}
+
+// Observe parses the JSON config, runs Check and returns the evidence as JSON.
+// Errors and panics become failed evidence, matching the WASM observe export.
+func (n *NativePlugin) Observe(ctx context.Context, configJSON []byte) (result []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = json.Marshal(Evidence{
+				Status:    false,
+				Error:     &ErrorDetail{Message: fmt.Sprintf("plugin panic: %v", r), Type: "panic", Stack: debug.Stack()},
+				Timestamp: time.Now(),
+			})
+		}
+	}()
+
+	var config Config
+	if err := json.Unmarshal(configJSON, &config); err != nil {
+		return json.Marshal(Evidence{Status: false, Error: ToErrorDetail(fmt.Errorf("failed to parse config: %w", err)), Timestamp: time.Now()})
+	}
+
+	evidence, err := n.plugin.Check(ctx, config)
+	if err != nil {
+		evidence = Failure("plugin_error", err.Error())
+	}
+	if evidence.Timestamp.IsZero() {
+		evidence.Timestamp = time.Now()
+	}
+	return json.Marshal(evidence)
+}
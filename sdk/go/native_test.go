@@ -0,0 +1,87 @@
+//go:build !wasip1
+
+package sdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/reglet-dev/reglet/sdk"
+)
+
+type nativeTestPlugin struct {
+	checkErr error
+	panicMsg string
+}
+
+func (p *nativeTestPlugin) Describe(_ context.Context) (sdk.Metadata, error) {
+	return sdk.Metadata{Name: "native-test", Version: "1.0.0"}, nil
+}
+
+func (p *nativeTestPlugin) Schema(_ context.Context) ([]byte, error) {
+	return []byte(`{"type":"object"}`), nil
+}
+
+func (p *nativeTestPlugin) Check(_ context.Context, config sdk.Config) (sdk.Evidence, error) {
+	if p.panicMsg != "" {
+		panic(p.panicMsg)
+	}
+	if p.checkErr != nil {
+		return sdk.Evidence{}, p.checkErr
+	}
+	return sdk.Success(map[string]interface{}{"echo": config["value"]}), nil
+}
+
+func TestNativePlugin_Describe(t *testing.T) {
+	t.Parallel()
+
+	data, err := sdk.NewNativePlugin(&nativeTestPlugin{}).Describe(context.Background())
+	require.NoError(t, err)
+
+	var metadata sdk.Metadata
+	require.NoError(t, json.Unmarshal(data, &metadata))
+	assert.Equal(t, "native-test", metadata.Name)
+	assert.Equal(t, sdk.Version, metadata.SDKVersion)
+}
+
+func TestNativePlugin_Observe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		plugin     *nativeTestPlugin
+		config     string
+		wantStatus bool
+		wantType   string
+	}{
+		{name: "success", plugin: &nativeTestPlugin{}, config: `{"value":"hi"}`, wantStatus: true},
+		{name: "check error", plugin: &nativeTestPlugin{checkErr: errors.New("boom")}, config: `{}`, wantType: "plugin_error"},
+		{name: "panic", plugin: &nativeTestPlugin{panicMsg: "bad"}, config: `{}`, wantType: "panic"},
+		{name: "invalid config", plugin: &nativeTestPlugin{}, config: `not json`, wantType: "internal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := sdk.NewNativePlugin(tt.plugin).Observe(context.Background(), []byte(tt.config))
+			require.NoError(t, err)
+
+			var evidence sdk.Evidence
+			require.NoError(t, json.Unmarshal(data, &evidence))
+			assert.Equal(t, tt.wantStatus, evidence.Status)
+			assert.False(t, evidence.Timestamp.IsZero())
+			if tt.wantStatus {
+				assert.Equal(t, "hi", evidence.Data["echo"])
+			} else {
+				require.NotNil(t, evidence.Error)
+				assert.Equal(t, tt.wantType, evidence.Error.Type)
+			}
+		})
+	}
+}
@@ -5,8 +5,6 @@ package net
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,8 +14,8 @@ import (
 	"github.com/reglet-dev/reglet/wireformat"
 )
 
-// MaxHTTPBodySize is the maximum size of HTTP response body that can be returned.
-// Response bodies exceeding this limit will result in an error.
+// MaxHTTPBodySize is the maximum size of HTTP response body the host will return.
+// Response bodies exceeding the negotiated limit will result in an error.
 const MaxHTTPBodySize = 10 * 1024 * 1024 // 10 MB
 
 // Define the host function signature for HTTP requests.
@@ -28,7 +26,19 @@ func host_http_request(requestPacked uint64) uint64
 
 // WasmTransport implements http.RoundTripper for the WASM environment.
 // It intercepts standard library HTTP calls and routes them through the host function.
-type WasmTransport struct{}
+type WasmTransport struct {
+	// MaxBodySize caps the response body the host reads for this transport.
+	// Zero uses MaxHTTPBodySize; larger values are clamped by the host.
+	MaxBodySize int64
+}
+
+// maxBodySize returns the response body limit requested from the host.
+func (t *WasmTransport) maxBodySize() int64 {
+	if t.MaxBodySize <= 0 || t.MaxBodySize > MaxHTTPBodySize {
+		return MaxHTTPBodySize
+	}
+	return t.MaxBodySize
+}
 
 // RoundTrip implements the http.RoundTripper interface.
 func (t *WasmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -37,36 +47,42 @@ func (t *WasmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	// Prepare HTTPRequestWire
 	request := HTTPRequestWire{
-		Context: wireCtx,
-		Method:  req.Method,
-		URL:     req.URL.String(),
-		Headers: req.Header,
+		Context:     wireCtx,
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		Headers:     req.Header,
+		MaxBodySize: t.maxBodySize(),
+		BinaryBody:  true,
 	}
 
-	// Read request body, encode if present
+	// Read request body; it is sent as a raw payload frame, not base64
+	var bodyBytes []byte
 	if req.Body != nil && req.Body != http.NoBody {
-		bodyBytes, err := io.ReadAll(req.Body)
+		var err error
+		bodyBytes, err = io.ReadAll(req.Body)
 		if err != nil {
 			return nil, fmt.Errorf("sdk: failed to read request body: %w", err)
 		}
-		request.Body = base64.StdEncoding.EncodeToString(bodyBytes)
 	}
 
-	requestBytes, err := json.Marshal(request)
+	requestBytes, err := encodeHTTPRequest(request, bodyBytes, req.Header.Get("Content-Type"))
 	if err != nil {
-		return nil, fmt.Errorf("sdk: failed to marshal HTTP request: %w", err)
+		return nil, err
 	}
 
 	// Call the host function
 	responsePacked := host_http_request(abi.PtrFromBytes(requestBytes))
+	if responsePacked == 0 {
+		return nil, fmt.Errorf("sdk: host returned an empty HTTP response")
+	}
 
 	// Read and unmarshal the response
 	responseBytes := abi.BytesFromPtr(responsePacked)
 	abi.DeallocatePacked(responsePacked) // Free memory on Guest side
 
-	var response HTTPResponseWire
-	if err := json.Unmarshal(responseBytes, &response); err != nil {
-		return nil, fmt.Errorf("sdk: failed to unmarshal HTTP response: %w", err)
+	response, body, err := decodeHTTPResponse(responseBytes)
+	if err != nil {
+		return nil, err
 	}
 
 	if response.Error != nil {
@@ -76,7 +92,7 @@ func (t *WasmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	// Check if response body was truncated due to size limit
 	// Return explicit error instead of silently truncating
 	if response.BodyTruncated {
-		return nil, fmt.Errorf("sdk: HTTP response body exceeds maximum size (%d bytes). URL: %s", MaxHTTPBodySize, req.URL.String())
+		return nil, fmt.Errorf("sdk: HTTP response body exceeds maximum size (%d bytes). URL: %s", t.maxBodySize(), req.URL.String())
 	}
 
 	resp := &http.Response{
@@ -89,17 +105,8 @@ func (t *WasmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		Status:     http.StatusText(response.StatusCode),
 	}
 
-	// Decode response body if present
-	if response.Body != "" {
-		decodedBody, err := base64.StdEncoding.DecodeString(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("sdk: failed to decode response body: %w", err)
-		}
-		resp.Body = io.NopCloser(bytes.NewReader(decodedBody))
-		resp.ContentLength = int64(len(decodedBody))
-	} else {
-		resp.Body = io.NopCloser(bytes.NewReader(nil))
-	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
 
 	return resp, nil
 }
@@ -0,0 +1,68 @@
+package net
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/reglet-dev/reglet/wireformat"
+)
+
+// encodeHTTPRequest serializes a request for the host. Requests with a body are
+// framed so the body travels as raw bytes; requests without one stay plain JSON.
+func encodeHTTPRequest(request wireformat.HTTPRequestWire, body []byte, contentType string) ([]byte, error) {
+	header, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("sdk: failed to marshal HTTP request: %w", err)
+	}
+	if len(body) == 0 {
+		return header, nil
+	}
+
+	if contentType == "" {
+		contentType = wireformat.ContentTypeOctetStream
+	}
+	framed, err := wireformat.EncodeFrames(
+		wireformat.Frame{ContentType: wireformat.ContentTypeJSON, Data: header},
+		wireformat.Frame{ContentType: contentType, Data: body},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sdk: failed to frame HTTP request: %w", err)
+	}
+	return framed, nil
+}
+
+// decodeHTTPResponse parses a host response and returns the raw body. It accepts
+// both framed responses and plain JSON with a base64 body from older hosts.
+func decodeHTTPResponse(data []byte) (wireformat.HTTPResponseWire, []byte, error) {
+	var response wireformat.HTTPResponseWire
+
+	header := data
+	var body []byte
+	if wireformat.IsFramed(data) {
+		frames, err := wireformat.DecodeFrames(data)
+		if err != nil {
+			return response, nil, fmt.Errorf("sdk: failed to decode HTTP response frames: %w", err)
+		}
+		if len(frames) == 0 || frames[0].ContentType != wireformat.ContentTypeJSON {
+			return response, nil, fmt.Errorf("sdk: framed HTTP response has no %s header", wireformat.ContentTypeJSON)
+		}
+		header = frames[0].Data
+		if len(frames) > 1 {
+			body = frames[1].Data
+		}
+	}
+
+	if err := json.Unmarshal(header, &response); err != nil {
+		return response, nil, fmt.Errorf("sdk: failed to unmarshal HTTP response: %w", err)
+	}
+
+	if body == nil && response.Body != "" {
+		decoded, err := base64.StdEncoding.DecodeString(response.Body)
+		if err != nil {
+			return response, nil, fmt.Errorf("sdk: failed to decode response body: %w", err)
+		}
+		body = decoded
+	}
+	return response, body, nil
+}
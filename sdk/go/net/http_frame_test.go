@@ -0,0 +1,72 @@
+package net
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/reglet-dev/reglet/wireformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeHTTPRequest_FramesBody(t *testing.T) {
+	body := []byte{0x30, 0x82, 0x00, 0x00, 0xff} // DER-ish bytes with NULs
+	data, err := encodeHTTPRequest(wireformat.HTTPRequestWire{Method: "POST", URL: "https://example.com"}, body, "application/pkix-cert")
+	require.NoError(t, err)
+	require.True(t, wireformat.IsFramed(data))
+
+	frames, err := wireformat.DecodeFrames(data)
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+	assert.Equal(t, wireformat.ContentTypeJSON, frames[0].ContentType)
+	assert.Equal(t, "application/pkix-cert", frames[1].ContentType)
+	assert.Equal(t, body, frames[1].Data)
+}
+
+func TestEncodeHTTPRequest_NoBodyIsPlainJSON(t *testing.T) {
+	data, err := encodeHTTPRequest(wireformat.HTTPRequestWire{Method: "GET", URL: "https://example.com"}, nil, "")
+	require.NoError(t, err)
+	assert.False(t, wireformat.IsFramed(data))
+	assert.True(t, json.Valid(data))
+}
+
+func TestDecodeHTTPResponse_Framed(t *testing.T) {
+	header, err := json.Marshal(wireformat.HTTPResponseWire{StatusCode: 200})
+	require.NoError(t, err)
+	body := []byte{0x00, 0x01, 0x00, 0x02}
+
+	data, err := wireformat.EncodeFrames(
+		wireformat.Frame{ContentType: wireformat.ContentTypeJSON, Data: header},
+		wireformat.Frame{ContentType: wireformat.ContentTypeOctetStream, Data: body},
+	)
+	require.NoError(t, err)
+
+	resp, got, err := decodeHTTPResponse(data)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, body, got)
+}
+
+func TestDecodeHTTPResponse_LegacyBase64(t *testing.T) {
+	data, err := json.Marshal(wireformat.HTTPResponseWire{
+		StatusCode: 200,
+		Body:       base64.StdEncoding.EncodeToString([]byte("hello")),
+	})
+	require.NoError(t, err)
+
+	_, got, err := decodeHTTPResponse(data)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), got)
+}
+
+func TestDecodeHTTPResponse_Malformed(t *testing.T) {
+	_, _, err := decodeHTTPResponse([]byte("RGLF\x05\x00ab"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, wireformat.ErrMalformedFrame)
+
+	data, err := wireformat.EncodeFrames(wireformat.Frame{ContentType: wireformat.ContentTypeOctetStream, Data: []byte("x")})
+	require.NoError(t, err)
+	_, _, err = decodeHTTPResponse(data)
+	require.Error(t, err)
+}
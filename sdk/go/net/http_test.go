@@ -181,3 +181,16 @@ func TestHTTPBodySizeLimits(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, decoded.BodyTruncated)
 }
+
+func TestHTTPRequestWire_MaxBodySize(t *testing.T) {
+	req := wireformat.HTTPRequestWire{Method: "GET", URL: "https://example.com", MaxBodySize: 4096}
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"max_body_size":4096`)
+
+	// Zero is omitted so older hosts see an unchanged request
+	data, err = json.Marshal(wireformat.HTTPRequestWire{Method: "GET", URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "max_body_size")
+}
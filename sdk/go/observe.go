@@ -0,0 +1,58 @@
+//go:build wasip1
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/reglet-dev/reglet/sdk/internal/abi"
+	sdkcontext "github.com/reglet-dev/reglet/sdk/internal/context"
+	"github.com/reglet-dev/reglet/wireformat"
+)
+
+//go:wasmimport reglet_host observe_plugin
+func host_observe_plugin(requestPacked uint64) uint64
+
+// ObservePlugin runs an observation of another plugin and returns its
+// evidence, so composite plugins can build on existing ones. The plugin must
+// be listed in Metadata.Dependencies; the host checks it is available before
+// running the composite, and the observation runs with the dependency's own
+// capabilities. The evidence reports failures of the dependency, such as an
+// unreachable host, in its Error; the error return is for observations the
+// host could not run.
+func ObservePlugin(ctx context.Context, plugin string, config Config) (Evidence, error) {
+	request, err := json.Marshal(wireformat.PluginObserveRequestWire{
+		Context: sdkcontext.ContextToWire(ctx),
+		Plugin:  plugin,
+		Config:  config,
+	})
+	if err != nil {
+		return Evidence{}, fmt.Errorf("sdk: failed to marshal observe request: %w", err)
+	}
+
+	requestPacked := abi.PtrFromBytes(request)
+	defer abi.DeallocatePacked(requestPacked)
+
+	responsePacked := host_observe_plugin(requestPacked)
+	responseBytes := abi.BytesFromPtr(responsePacked)
+	if responseBytes == nil {
+		return Evidence{}, fmt.Errorf("sdk: host returned null response")
+	}
+	defer abi.DeallocatePacked(responsePacked)
+
+	var response wireformat.PluginObserveResponseWire
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return Evidence{}, fmt.Errorf("sdk: failed to unmarshal observe response: %w", err)
+	}
+	if response.Error != nil {
+		return Evidence{}, response.Error
+	}
+
+	var evidence Evidence
+	if err := json.Unmarshal(response.Evidence, &evidence); err != nil {
+		return Evidence{}, fmt.Errorf("sdk: failed to unmarshal evidence of %s: %w", plugin, err)
+	}
+	return evidence, nil
+}
@@ -0,0 +1,14 @@
+//go:build !wasip1
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// ObservePlugin runs an observation of another plugin. Only the WASM host
+// runs dependencies of composite plugins, so natively it always fails.
+func ObservePlugin(_ context.Context, plugin string, _ Config) (Evidence, error) {
+	return Evidence{}, fmt.Errorf("sdk: cannot observe plugin %s: composite plugins only run as WASM plugins", plugin)
+}
@@ -13,18 +13,9 @@ import (
 	"github.com/reglet-dev/reglet/sdk/internal/abi"
 	sdkcontext "github.com/reglet-dev/reglet/sdk/internal/context"
 	_ "github.com/reglet-dev/reglet/sdk/log" // Initialize WASM logging handler
+	"github.com/reglet-dev/reglet/wireformat"
 )
 
-// Plugin is the interface every Reglet plugin must implement.
-type Plugin interface {
-	// Describe returns metadata about the plugin.
-	Describe(ctx context.Context) (Metadata, error)
-	// Schema returns the JSON schema for the plugin's configuration.
-	Schema(ctx context.Context) ([]byte, error)
-	// Check executes the plugin's main logic with the given configuration.
-	Check(ctx context.Context, config Config) (Evidence, error)
-}
-
 // Internal variable to hold the user's plugin implementation.
 var userPlugin Plugin
 
@@ -64,6 +55,7 @@ func _describe() uint64 {
 		// Auto-populate SDK version for metadata
 		metadata.SDKVersion = Version
 		metadata.MinHostVersion = MinHostVersion
+		applyBuildInfo(&metadata)
 		return metadata, nil
 	})
 }
@@ -123,6 +115,32 @@ func _observe(configPtr uint32, configLen uint32) uint64 {
 	})
 }
 
+//go:wasmexport self_test
+func _selfTest() uint64 {
+	return handleExportedCall(func() (interface{}, error) {
+		if userPlugin == nil {
+			return nil, fmt.Errorf("plugin not registered")
+		}
+		tester, ok := userPlugin.(SelfTester)
+		if !ok {
+			return Evidence{
+				Status:    false,
+				Error:     &ErrorDetail{Message: "plugin has no self-test", Type: "internal", Code: wireformat.CodeNoSelfTest},
+				Timestamp: time.Now(),
+			}, nil
+		}
+
+		evidence, err := tester.SelfTest(sdkcontext.GetCurrentContext())
+		if err != nil {
+			evidence = Failure("self_test", err.Error())
+		}
+		if evidence.Timestamp.IsZero() {
+			evidence.Timestamp = time.Now()
+		}
+		return evidence, nil
+	})
+}
+
 // handleExportedCall is a generic wrapper for WASM exported functions.
 // It provides panic recovery, error handling, and JSON serialization.
 // It ensures that on any error or panic, a structured Evidence with ErrorDetail is returned.
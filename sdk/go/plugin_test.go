@@ -487,3 +487,37 @@ func TestToErrorDetail_TimeoutPropagation(t *testing.T) {
 		})
 	}
 }
+
+func TestEvidence_MarshalJSON_Typed(t *testing.T) {
+	type fileEvidence struct {
+		Size *int64 `json:"size,omitempty"`
+		Path string `json:"path"`
+	}
+	size := int64(0)
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	typed, err := json.Marshal(Evidence{
+		Status:    true,
+		Timestamp: ts,
+		Data:      map[string]interface{}{"ignored": true},
+		Typed:     fileEvidence{Path: "/etc/hosts", Size: &size},
+	})
+	require.NoError(t, err)
+
+	generic, err := json.Marshal(Evidence{
+		Status:    true,
+		Timestamp: ts,
+		Data:      map[string]interface{}{"path": "/etc/hosts", "size": 0},
+	})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, string(generic), string(typed), "typed evidence must share the generic wire format")
+	assert.NotContains(t, string(typed), "Typed")
+}
+
+func TestTypedSuccess(t *testing.T) {
+	evidence := TypedSuccess(struct{ OK bool }{OK: true})
+	assert.True(t, evidence.Status)
+	assert.NotNil(t, evidence.Typed)
+	assert.False(t, evidence.Timestamp.IsZero())
+}
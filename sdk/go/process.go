@@ -0,0 +1,77 @@
+//go:build !wasip1
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/reglet-dev/reglet/wireformat"
+)
+
+// ServeProcess runs p as an external process plugin: a standalone executable
+// named reglet-plugin-<name> that the host starts once per call, writing a
+// wireformat.ProcessRequest to stdin and reading the JSON result from stdout.
+//
+// Use this transport only for plugins that cannot target wasip1 (for example
+// ones that need CGO). Call it from main:
+//
+//	func main() {
+//	    sdk.ServeProcess(&MyPlugin{})
+//	}
+//
+// ServeProcess exits the process with status 1 if the request cannot be served.
+func ServeProcess(p Plugin) {
+	if err := serveProcess(context.Background(), NewNativePlugin(p), os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "reglet plugin: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// serveProcess handles a single stdio request.
+func serveProcess(ctx context.Context, n *NativePlugin, r io.Reader, w io.Writer) error {
+	var request wireformat.ProcessRequest
+	if err := json.NewDecoder(r).Decode(&request); err != nil {
+		return fmt.Errorf("failed to decode request: %w", err)
+	}
+	if request.ProtocolVersion != wireformat.ProcessProtocolVersion {
+		return fmt.Errorf("unsupported protocol version %d (want %d)", request.ProtocolVersion, wireformat.ProcessProtocolVersion)
+	}
+
+	if request.Execution != nil {
+		ctx = withExecution(ctx, *request.Execution)
+	}
+	if request.Context.Deadline != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, *request.Context.Deadline)
+		defer cancel()
+	}
+
+	var (
+		output []byte
+		err    error
+	)
+	switch request.Method {
+	case wireformat.ProcessMethodDescribe:
+		output, err = n.Describe(ctx)
+	case wireformat.ProcessMethodSchema:
+		output, err = n.Schema(ctx)
+	case wireformat.ProcessMethodObserve:
+		config := request.Config
+		if len(config) == 0 {
+			config = []byte("{}")
+		}
+		output, err = n.Observe(ctx, config)
+	default:
+		return fmt.Errorf("unknown method %q", request.Method)
+	}
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", request.Method, err)
+	}
+
+	_, err = w.Write(output)
+	return err
+}
@@ -0,0 +1,107 @@
+//go:build !wasip1
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type processTestPlugin struct{}
+
+func (p *processTestPlugin) Describe(_ context.Context) (Metadata, error) {
+	return Metadata{Name: "process-test", Version: "1.0.0"}, nil
+}
+
+func (p *processTestPlugin) Schema(_ context.Context) ([]byte, error) {
+	return []byte(`{"type":"object"}`), nil
+}
+
+func (p *processTestPlugin) Check(ctx context.Context, config Config) (Evidence, error) {
+	return Success(map[string]interface{}{"echo": config["value"], "control": Execution(ctx).ControlID}), nil
+}
+
+func TestServeProcess(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		request string
+		wantErr string
+		check   func(t *testing.T, out []byte)
+	}{
+		{
+			name:    "describe",
+			request: `{"method":"describe","protocol_version":1}`,
+			check: func(t *testing.T, out []byte) {
+				var metadata Metadata
+				require.NoError(t, json.Unmarshal(out, &metadata))
+				assert.Equal(t, "process-test", metadata.Name)
+				assert.Equal(t, Version, metadata.SDKVersion)
+			},
+		},
+		{
+			name:    "schema",
+			request: `{"method":"schema","protocol_version":1}`,
+			check: func(t *testing.T, out []byte) {
+				assert.JSONEq(t, `{"type":"object"}`, string(out))
+			},
+		},
+		{
+			name:    "observe",
+			request: `{"method":"observe","config":{"value":"hi"},"protocol_version":1,"context":{"deadline":"2999-01-01T00:00:00Z"}}`,
+			check: func(t *testing.T, out []byte) {
+				var evidence Evidence
+				require.NoError(t, json.Unmarshal(out, &evidence))
+				assert.True(t, evidence.Status)
+				assert.Equal(t, "hi", evidence.Data["echo"])
+			},
+		},
+		{
+			name:    "observe with execution",
+			request: `{"method":"observe","protocol_version":1,"execution":{"run_id":"run-1","control_id":"ssh","observation_index":0}}`,
+			check: func(t *testing.T, out []byte) {
+				var evidence Evidence
+				require.NoError(t, json.Unmarshal(out, &evidence))
+				assert.Equal(t, "ssh", evidence.Data["control"])
+			},
+		},
+		{
+			name:    "unknown method",
+			request: `{"method":"run","protocol_version":1}`,
+			wantErr: "unknown method",
+		},
+		{
+			name:    "protocol mismatch",
+			request: `{"method":"describe","protocol_version":99}`,
+			wantErr: "unsupported protocol version",
+		},
+		{
+			name:    "malformed request",
+			request: `{`,
+			wantErr: "failed to decode request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			err := serveProcess(context.Background(), NewNativePlugin(&processTestPlugin{}), strings.NewReader(tt.request), &out)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, out.Bytes())
+		})
+	}
+}
@@ -0,0 +1,36 @@
+//go:build wasip1
+
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/reglet-dev/reglet/sdk/internal/abi"
+	"github.com/reglet-dev/reglet/wireformat"
+)
+
+//go:wasmimport reglet_host scratch_dir
+func host_scratch_dir() uint64
+
+// ScratchDir returns the path of a temporary directory private to the current
+// observation, for staging files such as downloaded artifacts. The host
+// removes it when the observation ends. Writes that would take it over its
+// size limit fail.
+func ScratchDir() (string, error) {
+	responsePacked := host_scratch_dir()
+	responseBytes := abi.BytesFromPtr(responsePacked)
+	if responseBytes == nil {
+		return "", fmt.Errorf("host returned null response")
+	}
+	defer abi.DeallocatePacked(responsePacked)
+
+	var response wireformat.ScratchDirWire
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if response.Error != nil {
+		return "", response.Error
+	}
+	return response.Path, nil
+}
@@ -0,0 +1,12 @@
+//go:build !wasip1
+
+package sdk
+
+import "errors"
+
+// ScratchDir returns the path of a temporary directory private to the current
+// observation. Only WASM plugins get one: native and external process plugins
+// manage their own temporary files.
+func ScratchDir() (string, error) {
+	return "", errors.New("scratch directories are only available to WASM plugins")
+}
@@ -2,6 +2,8 @@
 package sdk
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time" // Added for Timestamp
@@ -9,31 +11,74 @@ import (
 	"github.com/reglet-dev/reglet/wireformat"
 )
 
+// Plugin is the interface every Reglet plugin must implement.
+type Plugin interface {
+	// Describe returns metadata about the plugin.
+	Describe(ctx context.Context) (Metadata, error)
+	// Schema returns the JSON schema for the plugin's configuration.
+	Schema(ctx context.Context) ([]byte, error)
+	// Check executes the plugin's main logic with the given configuration.
+	Check(ctx context.Context, config Config) (Evidence, error)
+}
+
+// SelfTester is implemented by plugins with a self-test, which `reglet
+// plugins doctor` runs. It runs without capabilities, so it should check the
+// plugin itself (embedded data, parsers), not the host.
+type SelfTester interface {
+	SelfTest(ctx context.Context) (Evidence, error)
+}
+
 // Config represents the configuration passed to a plugin observation.
 type Config map[string]interface{}
 
 // Evidence represents the structured data returned by a plugin observation.
 // This struct directly mirrors the WIT 'evidence' record for direct mapping
 // across the WebAssembly boundary.
+//
+// Data is the generic form of the evidence data. Plugins with a typed evidence
+// struct, such as the built-in ones in wireformat/evidence, set Typed instead;
+// it is sent in place of Data, so the host sees the same wire format.
 type Evidence struct {
 	Status    bool                   // Corresponds to WIT 'status'
 	Error     *ErrorDetail           // Corresponds to WIT 'error'
 	Timestamp time.Time              // Corresponds to WIT 'timestamp'
 	Data      map[string]interface{} // Corresponds to WIT 'data'
 	Raw       *string                // Corresponds to WIT 'raw'
+	Typed     any                    `json:"-"` // Typed form of 'data'; takes precedence over Data
+}
+
+// MarshalJSON encodes the evidence with Typed, when set, as its data.
+func (e Evidence) MarshalJSON() ([]byte, error) {
+	type plain Evidence // drops this method to avoid recursion
+	if e.Typed == nil {
+		return json.Marshal(plain(e))
+	}
+	return json.Marshal(struct {
+		Data any `json:"Data"`
+		plain
+	}{Data: e.Typed, plain: plain(e)})
 }
 
 // ErrorDetail is re-exported from wireformat for backward compatibility.
 // Error Types: "network", "timeout", "config", "panic", "capability", "validation", "internal"
 type ErrorDetail = wireformat.ErrorDetail
 
+// ExecutionContext is the run, control and observation a plugin call
+// belongs to; see Execution.
+type ExecutionContext = wireformat.ExecutionContextWire
+
 // Metadata contains information about the plugin.
 type Metadata struct {
 	Name           string       `json:"name"`
 	Version        string       `json:"version"`
 	Description    string       `json:"description"`
-	SDKVersion     string       `json:"sdk_version"`      // Auto-populated
-	MinHostVersion string       `json:"min_host_version"` // Minimum compatible host
+	SDKVersion     string       `json:"sdk_version"`            // Auto-populated
+	MinHostVersion string       `json:"min_host_version"`       // Minimum compatible host
+	Commit         string       `json:"commit,omitempty"`       // Source commit, set by reglet plugins build
+	BuildTime      string       `json:"build_time,omitempty"`   // Source date (RFC 3339), set by reglet plugins build
+	GoVersion      string       `json:"go_version,omitempty"`   // Auto-populated: Go toolchain the plugin was built with
+	Dependencies   []string     `json:"dependencies,omitempty"` // Plugins this plugin observes through ObservePlugin
+	SideEffects    bool         `json:"side_effects,omitempty"` // Plugin changes external state; see ExecutionContext.IdempotencyKey
 	Capabilities   []Capability `json:"capabilities"`
 }
 
@@ -204,6 +249,12 @@ func Success(data map[string]interface{}) Evidence {
 	return Evidence{Status: true, Data: data, Timestamp: time.Now()}
 }
 
+// TypedSuccess creates a successful Evidence with typed data, such as one of
+// the structs in wireformat/evidence.
+func TypedSuccess(data any) Evidence {
+	return Evidence{Status: true, Typed: data, Timestamp: time.Now()}
+}
+
 // Failure creates a failed Evidence with an error.
 func Failure(errType, message string) Evidence {
 	return Evidence{
@@ -0,0 +1,41 @@
+// Code generated by evidencegen from schemas/command.json. DO NOT EDIT.
+
+package evidence
+
+// Command is the evidence reported by the command plugin.
+type Command struct {
+	// Standard output with surrounding whitespace trimmed.
+	Stdout string `json:"stdout"`
+	// Standard error with surrounding whitespace trimmed.
+	Stderr string `json:"stderr"`
+	// Standard output as written.
+	StdoutRaw string `json:"stdout_raw"`
+	// Standard error as written.
+	StderrRaw string `json:"stderr_raw"`
+	// Process exit code.
+	ExitCode int64 `json:"exit_code"`
+	// Run time in milliseconds.
+	DurationMs int64 `json:"duration_ms"`
+	// Whether the command was killed at its timeout.
+	IsTimeout bool `json:"is_timeout"`
+	// How the command ran: shell for run, direct for command.
+	ExecMode string `json:"exec_mode"`
+	// Executable that ran; /bin/sh in shell mode.
+	Command string `json:"command"`
+	// Arguments passed to the executable.
+	Args []string `json:"args"`
+	// Configured working directory.
+	WorkingDir string `json:"working_dir"`
+	// Configured timeout in seconds.
+	TimeoutConfig int64 `json:"timeout_config"`
+	// Shell command line. Set in shell mode.
+	ShellCommand *string `json:"shell_command,omitempty"`
+	// Configured command. Set in direct mode.
+	CommandPath *string `json:"command_path,omitempty"`
+	// Configured arguments. Set in direct mode.
+	CommandArgs []string `json:"command_args,omitempty"`
+	// Charset stdout was read as: utf-8 or latin-1.
+	StdoutCharset *string `json:"stdout_charset,omitempty"`
+	// Charset stderr was read as: utf-8 or latin-1.
+	StderrCharset *string `json:"stderr_charset,omitempty"`
+}
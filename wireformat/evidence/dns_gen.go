@@ -0,0 +1,33 @@
+// Code generated by evidencegen from schemas/dns.json. DO NOT EDIT.
+
+package evidence
+
+// DNS is the evidence reported by the dns plugin.
+type DNS struct {
+	// Hostname that was resolved.
+	Hostname string `json:"hostname"`
+	// Record type that was queried, such as A or MX.
+	RecordType string `json:"record_type"`
+	// Query time in milliseconds.
+	QueryTimeMs int64 `json:"query_time_ms"`
+	// Resolved records for all types except MX.
+	Records []string `json:"records,omitempty"`
+	// Resolved mail exchangers for MX queries.
+	MXRecords []MXRecord `json:"mx_records,omitempty"`
+	// Number of resolved records. Set when the lookup succeeds.
+	RecordCount *int64 `json:"record_count,omitempty"`
+	// Lookup error. Set when the lookup fails.
+	ErrorMessage *string `json:"error_message,omitempty"`
+	// Whether the lookup timed out.
+	IsTimeout bool `json:"is_timeout"`
+	// Whether the hostname does not exist.
+	IsNotFound bool `json:"is_not_found"`
+}
+
+// MXRecord is one resolved mail exchanger.
+type MXRecord struct {
+	// Mail server hostname.
+	Host string `json:"host"`
+	// Preference; lower values are preferred.
+	Pref int64 `json:"pref"`
+}
@@ -0,0 +1,14 @@
+// Package evidence defines typed evidence for Reglet's built-in plugins.
+//
+// Each plugin's evidence is described once, as a JSON Schema in schemas/.
+// The Go structs in the *_gen.go files are generated from those schemas and
+// shared by plugins, which build evidence with compile-time checked fields,
+// and by the host, which can decode evidence data into the same types.
+// Evidence from third-party plugins stays a generic map.
+//
+// After editing a schema, regenerate the structs with:
+//
+//	go generate ./evidence
+package evidence
+
+//go:generate go run ./internal/cmd/evidencegen
@@ -0,0 +1,49 @@
+package evidence
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+//go:embed schemas/*.json
+var schemas embed.FS
+
+// Schema returns the JSON Schema of a built-in plugin's evidence.
+func Schema(plugin string) ([]byte, bool) {
+	data, err := schemas.ReadFile(path.Join("schemas", plugin+".json"))
+	return data, err == nil
+}
+
+// Plugins returns the built-in plugins that have typed evidence, sorted.
+func Plugins() []string {
+	entries, _ := schemas.ReadDir("schemas")
+	plugins := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		plugins = append(plugins, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	slices.Sort(plugins)
+	return plugins
+}
+
+// Decode converts generic evidence data, as the host receives it, into a
+// typed evidence struct.
+func Decode[T any](data map[string]interface{}) (T, error) {
+	var typed T
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return typed, fmt.Errorf("encode evidence data: %w", err)
+	}
+	if err := json.Unmarshal(raw, &typed); err != nil {
+		return typed, fmt.Errorf("decode evidence data into %T: %w", typed, err)
+	}
+	return typed, nil
+}
+
+// Ptr returns a pointer to v, for setting optional evidence fields.
+func Ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,75 @@
+package evidence
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/reglet-dev/reglet/wireformat/evidence/internal/gen"
+)
+
+// TestGeneratedCodeIsCurrent fails when a schema changed without rerunning
+// go generate, which would let the structs drift from the schemas.
+func TestGeneratedCodeIsCurrent(t *testing.T) {
+	t.Parallel()
+
+	for _, plugin := range Plugins() {
+		schema, ok := Schema(plugin)
+		if !ok {
+			t.Fatalf("schema for %s not embedded", plugin)
+		}
+		want, err := gen.Generate("evidence", "schemas/"+plugin+".json", schema)
+		if err != nil {
+			t.Fatalf("generate %s: %v", plugin, err)
+		}
+		got, err := os.ReadFile(plugin + "_gen.go")
+		if err != nil {
+			t.Fatalf("read generated code: %v", err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s_gen.go is stale; run go generate ./evidence", plugin)
+		}
+	}
+}
+
+func TestPlugins(t *testing.T) {
+	t.Parallel()
+
+	got := strings.Join(Plugins(), ",")
+	if got != "command,dns,file,http,smtp,tcp" {
+		t.Errorf("Plugins() = %s", got)
+	}
+	if _, ok := Schema("unknown"); ok {
+		t.Error("Schema(unknown) reported a schema")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	// Host-side evidence data is decoded JSON, so numbers are float64
+	data := map[string]interface{}{
+		"path":     "/etc/passwd",
+		"exists":   true,
+		"readable": true,
+		"size":     float64(1024),
+		"uid":      float64(0),
+	}
+	file, err := Decode[File](data)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if file.Path != "/etc/passwd" || !file.Exists || *file.Size != 1024 {
+		t.Errorf("unexpected file evidence: %+v", file)
+	}
+	if file.UID == nil || *file.UID != 0 {
+		t.Error("uid 0 must survive decoding")
+	}
+	if file.IsDir != nil {
+		t.Error("absent optional fields must stay nil")
+	}
+
+	if _, err := Decode[File](map[string]interface{}{"size": "large"}); err == nil {
+		t.Error("expected an error for a mistyped field")
+	}
+}
@@ -0,0 +1,45 @@
+// Code generated by evidencegen from schemas/file.json. DO NOT EDIT.
+
+package evidence
+
+// File is the evidence reported by the file plugin.
+type File struct {
+	// Path that was checked.
+	Path string `json:"path"`
+	// Whether the path exists.
+	Exists bool `json:"exists"`
+	// Whether the plugin could open the path.
+	Readable bool `json:"readable"`
+	// Whether the path is a directory. Set when the path exists.
+	IsDir *bool `json:"is_dir,omitempty"`
+	// Size in bytes. Set when the path exists.
+	Size *int64 `json:"size,omitempty"`
+	// Permission bits in octal, such as 0644.
+	Mode *string `json:"mode,omitempty"`
+	// Mode in ls notation, such as -rw-r--r--.
+	Permissions *string `json:"permissions,omitempty"`
+	// Modification time in RFC 3339 format.
+	ModTime *string `json:"mod_time,omitempty"`
+	// Owner user ID, on platforms that report it.
+	UID *int64 `json:"uid,omitempty"`
+	// Owner group ID, on platforms that report it.
+	GID *int64 `json:"gid,omitempty"`
+	// Whether the path is a symbolic link.
+	IsSymlink *bool `json:"is_symlink,omitempty"`
+	// Target of the symbolic link.
+	SymlinkTarget *string `json:"symlink_target,omitempty"`
+	// File content, base64 encoded. Set when read_content or read_range is enabled.
+	ContentB64 *string `json:"content_b64,omitempty"`
+	// Encoding of content_b64, always base64.
+	Encoding *string `json:"encoding,omitempty"`
+	// File content as text. Set instead of content_b64 when read_content or read_range is enabled with an encoding.
+	Content *string `json:"content,omitempty"`
+	// Charset content was read as: utf-8 or latin-1.
+	Charset *string `json:"charset,omitempty"`
+	// Offset of the content in the file. Set when read_range is enabled.
+	RangeOffset *int64 `json:"range_offset,omitempty"`
+	// Bytes of content read, fewer than requested at the end of the file. Set when read_range is enabled.
+	RangeLength *int64 `json:"range_length,omitempty"`
+	// Hex SHA-256 digest of the content. Set when hash or hash_only is enabled.
+	SHA256 *string `json:"sha256,omitempty"`
+}
@@ -0,0 +1,29 @@
+// Code generated by evidencegen from schemas/http.json. DO NOT EDIT.
+
+package evidence
+
+// HTTP is the evidence reported by the http plugin.
+type HTTP struct {
+	// Response status code.
+	StatusCode int64 `json:"status_code"`
+	// Time to the complete response in milliseconds.
+	ResponseTimeMs int64 `json:"response_time_ms"`
+	// Response protocol, such as HTTP/1.1.
+	Protocol string `json:"protocol"`
+	// Response headers.
+	Headers map[string][]string `json:"headers"`
+	// Response body size in bytes.
+	BodySize int64 `json:"body_size"`
+	// Hex SHA-256 digest of the response body.
+	BodySHA256 string `json:"body_sha256"`
+	// Response body, when it fits in body_preview_length or that is -1.
+	Body *string `json:"body,omitempty"`
+	// First body_preview_length characters of a longer body.
+	BodyPreview *string `json:"body_preview,omitempty"`
+	// Whether the body was cut to body_preview.
+	BodyTruncated *bool `json:"body_truncated,omitempty"`
+	// Whether expected_status or expected_body_contains did not match.
+	ExpectationFailed *bool `json:"expectation_failed,omitempty"`
+	// Why the expectation failed.
+	ExpectationError *string `json:"expectation_error,omitempty"`
+}
@@ -0,0 +1,43 @@
+// Command evidencegen regenerates the evidence structs from the JSON Schemas
+// in wireformat/evidence/schemas. Run it through go generate in that package.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/reglet-dev/reglet/wireformat/evidence/internal/gen"
+)
+
+func main() {
+	if err := run("."); err != nil {
+		fmt.Fprintln(os.Stderr, "evidencegen:", err)
+		os.Exit(1)
+	}
+}
+
+// run writes <name>_gen.go in dir for every schemas/<name>.json.
+func run(dir string) error {
+	schemas, err := filepath.Glob(filepath.Join(dir, "schemas", "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range schemas {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: paths come from the package's own schemas directory
+		if err != nil {
+			return err
+		}
+		source := filepath.ToSlash(filepath.Join("schemas", filepath.Base(path)))
+		code, err := gen.Generate("evidence", source, data)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".json") + "_gen.go"
+		if err := os.WriteFile(filepath.Join(dir, name), code, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Package gen generates Go evidence structs from the JSON Schemas in
+// wireformat/evidence/schemas. It understands the subset of JSON Schema the
+// evidence schemas use: objects with properties and required lists, arrays,
+// string-keyed maps via additionalProperties, and scalar types.
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Schema is the subset of a JSON Schema the generator reads.
+type Schema struct {
+	Items                *Schema    `json:"items"`
+	AdditionalProperties *Schema    `json:"additionalProperties"`
+	Title                string     `json:"title"`
+	Description          string     `json:"description"`
+	Type                 string     `json:"type"`
+	Format               string     `json:"format"`
+	Properties           Properties `json:"properties"`
+	Required             []string   `json:"required"`
+}
+
+// Property is a named property of an object schema.
+type Property struct {
+	Schema *Schema
+	Name   string
+}
+
+// Properties keeps object properties in the order the schema declares them,
+// so generated fields follow the schema.
+type Properties []Property
+
+// UnmarshalJSON decodes a properties object preserving key order.
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("properties must be an object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := tok.(string)
+		var schema Schema
+		if err := dec.Decode(&schema); err != nil {
+			return fmt.Errorf("property %q: %w", name, err)
+		}
+		*p = append(*p, Property{Name: name, Schema: &schema})
+	}
+	return nil
+}
+
+// Generate returns the gofmt-ed Go source for the schema. source names the
+// schema file in the generated header.
+func Generate(pkg, source string, schemaJSON []byte) ([]byte, error) {
+	var schema Schema
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", source, err)
+	}
+	if schema.Type != "object" || schema.Title == "" {
+		return nil, fmt.Errorf("%s: top-level schema must be an object with a title", source)
+	}
+
+	g := &generator{}
+	if err := g.object(&schema); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by evidencegen from %s. DO NOT EDIT.\n\npackage %s\n", source, pkg)
+	for _, decl := range g.decls {
+		out.WriteString("\n")
+		out.WriteString(decl)
+	}
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s: format generated code: %w", source, err)
+	}
+	return formatted, nil
+}
+
+type generator struct {
+	decls []string
+}
+
+// object emits a struct for schema followed by any nested object types.
+func (g *generator) object(schema *Schema) error {
+	slot := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var b strings.Builder
+	writeComment(&b, "", schema.Description, schema.Title+" is a generated evidence type.")
+	fmt.Fprintf(&b, "type %s struct {\n", schema.Title)
+
+	for _, prop := range schema.Properties {
+		required := slices.Contains(schema.Required, prop.Name)
+		goType, err := g.goType(prop.Schema)
+		if err != nil {
+			return fmt.Errorf("property %q: %w", prop.Name, err)
+		}
+
+		tag := prop.Name
+		if !required {
+			// Optional scalars are pointers so a zero value such as uid 0
+			// or is_dir false is still reported when it was set
+			if isScalar(prop.Schema) {
+				goType = "*" + goType
+			}
+			tag += ",omitempty"
+		}
+
+		writeComment(&b, "\t", prop.Schema.Description, "")
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", fieldName(prop.Name), goType, tag)
+	}
+	b.WriteString("}\n")
+
+	g.decls[slot] = b.String()
+	return nil
+}
+
+// goType maps a property schema to a Go type, emitting nested structs.
+func (g *generator) goType(schema *Schema) (string, error) {
+	switch schema.Type {
+	case "string":
+		return "string", nil
+	case "boolean":
+		return "bool", nil
+	case "integer":
+		return "int64", nil
+	case "number":
+		return "float64", nil
+	case "array":
+		if schema.Items == nil {
+			return "", fmt.Errorf("array without items")
+		}
+		elem, err := g.goType(schema.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case "object":
+		if len(schema.Properties) > 0 {
+			if schema.Title == "" {
+				return "", fmt.Errorf("nested object needs a title to name its type")
+			}
+			if err := g.object(schema); err != nil {
+				return "", err
+			}
+			return schema.Title, nil
+		}
+		if schema.AdditionalProperties != nil {
+			elem, err := g.goType(schema.AdditionalProperties)
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + elem, nil
+		}
+		return "map[string]interface{}", nil
+	default:
+		return "", fmt.Errorf("unsupported type %q", schema.Type)
+	}
+}
+
+func isScalar(schema *Schema) bool {
+	switch schema.Type {
+	case "string", "boolean", "integer", "number":
+		return true
+	}
+	return false
+}
+
+// writeComment writes text as a Go comment, falling back to fallback.
+func writeComment(b *strings.Builder, indent, text, fallback string) {
+	if text == "" {
+		text = fallback
+	}
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(b, "%s// %s\n", indent, text)
+}
+
+// initialisms are spelled in upper case in field names, following Go style.
+var initialisms = map[string]bool{
+	"dns": true, "gid": true, "http": true, "id": true, "ip": true,
+	"mx": true, "sha256": true, "tls": true, "ttl": true, "uid": true, "url": true,
+}
+
+// fieldName converts a snake_case property name to an exported Go name.
+func fieldName(name string) string {
+	var b strings.Builder
+	for part := range strings.SplitSeq(name, "_") {
+		if part == "" {
+			continue
+		}
+		if initialisms[part] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
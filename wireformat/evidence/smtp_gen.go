@@ -0,0 +1,23 @@
+// Code generated by evidencegen from schemas/smtp.json. DO NOT EDIT.
+
+package evidence
+
+// SMTP is the evidence reported by the smtp plugin.
+type SMTP struct {
+	// Whether the connection was established.
+	Connected bool `json:"connected"`
+	// Address that was dialed, as host:port.
+	Address string `json:"address"`
+	// Connection time in milliseconds.
+	ResponseTimeMs int64 `json:"response_time_ms"`
+	// Greeting banner sent by the server.
+	Banner string `json:"banner"`
+	// Whether TLS was negotiated.
+	TLS *bool `json:"tls,omitempty"`
+	// Negotiated TLS version, such as TLS 1.3.
+	TLSVersion *string `json:"tls_version,omitempty"`
+	// Negotiated cipher suite.
+	TLSCipherSuite *string `json:"tls_cipher_suite,omitempty"`
+	// Server name sent in the TLS handshake.
+	TLSServerName *string `json:"tls_server_name,omitempty"`
+}
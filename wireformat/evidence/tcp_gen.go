@@ -0,0 +1,37 @@
+// Code generated by evidencegen from schemas/tcp.json. DO NOT EDIT.
+
+package evidence
+
+// TCP is the evidence reported by the tcp plugin.
+type TCP struct {
+	// Whether the connection was established.
+	Connected bool `json:"connected"`
+	// Address that was dialed, as host:port.
+	Address string `json:"address"`
+	// Connection time in milliseconds.
+	ResponseTimeMs int64 `json:"response_time_ms"`
+	// Remote address of the connection.
+	RemoteAddr string `json:"remote_addr"`
+	// Local address of the connection.
+	LocalAddr string `json:"local_addr"`
+	// Whether TLS was negotiated.
+	TLS *bool `json:"tls,omitempty"`
+	// Negotiated TLS version, such as TLS 1.3.
+	TLSVersion *string `json:"tls_version,omitempty"`
+	// Negotiated cipher suite.
+	TLSCipherSuite *string `json:"tls_cipher_suite,omitempty"`
+	// Server name sent in the TLS handshake.
+	TLSServerName *string `json:"tls_server_name,omitempty"`
+	// Subject of the server certificate.
+	TLSCertSubject *string `json:"tls_cert_subject,omitempty"`
+	// Issuer of the server certificate.
+	TLSCertIssuer *string `json:"tls_cert_issuer,omitempty"`
+	// Certificate expiry in RFC 3339 format.
+	TLSCertNotAfter *string `json:"tls_cert_not_after,omitempty"`
+	// Whole days until the certificate expires.
+	TLSCertDaysRemaining *int64 `json:"tls_cert_days_remaining,omitempty"`
+	// Whether the negotiated TLS version is below expected_tls_version.
+	ExpectationFailed *bool `json:"expectation_failed,omitempty"`
+	// Why the TLS version expectation failed.
+	ExpectationError *string `json:"expectation_error,omitempty"`
+}
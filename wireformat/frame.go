@@ -0,0 +1,101 @@
+package wireformat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Framed buffers carry binary payloads between host and guest without base64.
+//
+// Layout (all integers little-endian, matching WASM linear memory):
+//
+//	magic "RGLF" (4 bytes)
+//	repeated until end of buffer:
+//	  content type length (uint16) | content type (UTF-8)
+//	  payload length (uint32)      | payload
+//
+// The first frame of a message is conventionally the JSON wire struct
+// (ContentTypeJSON); later frames hold raw payloads it refers to. Buffers that
+// do not start with the magic are plain JSON messages.
+
+// Common frame content types.
+const (
+	ContentTypeJSON        = "application/json"
+	ContentTypeOctetStream = "application/octet-stream"
+)
+
+// frameMagic prefixes every framed buffer. It can never start a JSON document.
+var frameMagic = []byte("RGLF")
+
+// ErrMalformedFrame is returned when a framed buffer cannot be decoded.
+var ErrMalformedFrame = errors.New("wireformat: malformed frame")
+
+// Frame is a single content-type-tagged binary payload.
+type Frame struct {
+	ContentType string
+	Data        []byte
+}
+
+// IsFramed reports whether data is a framed buffer rather than plain JSON.
+func IsFramed(data []byte) bool {
+	return bytes.HasPrefix(data, frameMagic)
+}
+
+// EncodeFrames serializes frames into a single framed buffer.
+func EncodeFrames(frames ...Frame) ([]byte, error) {
+	size := len(frameMagic)
+	for _, f := range frames {
+		if len(f.ContentType) > math.MaxUint16 {
+			return nil, fmt.Errorf("%w: content type too long (%d bytes)", ErrMalformedFrame, len(f.ContentType))
+		}
+		if uint64(len(f.Data)) > math.MaxUint32 {
+			return nil, fmt.Errorf("%w: payload too large (%d bytes)", ErrMalformedFrame, len(f.Data))
+		}
+		size += 2 + len(f.ContentType) + 4 + len(f.Data)
+	}
+
+	buf := make([]byte, 0, size)
+	buf = append(buf, frameMagic...)
+	for _, f := range frames {
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(len(f.ContentType))) //nolint:gosec // G115: checked above
+		buf = append(buf, f.ContentType...)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(f.Data))) //nolint:gosec // G115: checked above
+		buf = append(buf, f.Data...)
+	}
+	return buf, nil
+}
+
+// DecodeFrames parses a framed buffer. Frame payloads alias data; callers that
+// release the underlying memory must copy them first.
+func DecodeFrames(data []byte) ([]Frame, error) {
+	if !IsFramed(data) {
+		return nil, fmt.Errorf("%w: missing magic", ErrMalformedFrame)
+	}
+
+	var frames []Frame
+	rest := data[len(frameMagic):]
+	for len(rest) > 0 {
+		if len(rest) < 2 {
+			return nil, fmt.Errorf("%w: truncated content type length", ErrMalformedFrame)
+		}
+		ctLen := int(binary.LittleEndian.Uint16(rest))
+		rest = rest[2:]
+		if len(rest) < ctLen+4 {
+			return nil, fmt.Errorf("%w: truncated content type", ErrMalformedFrame)
+		}
+		contentType := string(rest[:ctLen])
+		rest = rest[ctLen:]
+
+		dataLen := uint64(binary.LittleEndian.Uint32(rest))
+		rest = rest[4:]
+		if uint64(len(rest)) < dataLen {
+			return nil, fmt.Errorf("%w: truncated payload for %q", ErrMalformedFrame, contentType)
+		}
+		frames = append(frames, Frame{ContentType: contentType, Data: rest[:dataLen:dataLen]})
+		rest = rest[dataLen:]
+	}
+	return frames, nil
+}
@@ -0,0 +1,29 @@
+package wireformat
+
+import "encoding/json"
+
+// ProcessProtocolVersion is the stdio protocol spoken by external process plugins.
+//
+// An external process plugin is a standalone executable. For every call the host
+// starts it, writes one ProcessRequest as JSON to stdin and closes stdin. The
+// plugin writes exactly one JSON document to stdout and exits 0: plugin metadata
+// for describe, the raw JSON schema for schema, and evidence for observe (the
+// same documents the WASM exports return). Stderr is treated as log output.
+const ProcessProtocolVersion = 1
+
+// Process plugin methods.
+const (
+	ProcessMethodDescribe = "describe"
+	ProcessMethodSchema   = "schema"
+	ProcessMethodObserve  = "observe"
+)
+
+// ProcessRequest is the JSON request written to an external process plugin's stdin.
+type ProcessRequest struct {
+	Method          string            `json:"method"`
+	Config          json.RawMessage   `json:"config,omitempty"` // Observe only
+	Context         ContextWireFormat `json:"context"`
+	ProtocolVersion int               `json:"protocol_version"`
+	// Execution is the run, control and observation of an observe call
+	Execution *ExecutionContextWire `json:"execution,omitempty"`
+}
@@ -0,0 +1,43 @@
+package wireformat
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Text encodings plugins can ask for when turning file content or command
+// output into evidence strings.
+const (
+	// EncodingAuto keeps valid UTF-8 and reads anything else as Latin-1.
+	EncodingAuto = "auto"
+	// EncodingUTF8 reads UTF-8, replacing invalid bytes with U+FFFD.
+	EncodingUTF8 = "utf-8"
+	// EncodingLatin1 reads ISO 8859-1, one character per byte.
+	EncodingLatin1 = "latin-1"
+)
+
+// ValidateEncoding reports whether encoding is empty (UTF-8) or one of the
+// Encoding constants.
+func ValidateEncoding(encoding string) error {
+	switch encoding {
+	case "", EncodingAuto, EncodingUTF8, EncodingLatin1:
+		return nil
+	default:
+		return fmt.Errorf("unsupported encoding %q: want %s, %s or %s", encoding, EncodingAuto, EncodingUTF8, EncodingLatin1)
+	}
+}
+
+// DecodeText converts data in the given encoding to a UTF-8 string, safe to
+// embed in JSON evidence, and returns the charset it was read as: utf-8 or
+// latin-1. An empty or unknown encoding is read as UTF-8.
+func DecodeText(data []byte, encoding string) (text, charset string) {
+	if encoding == EncodingLatin1 || (encoding == EncodingAuto && !utf8.Valid(data)) {
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes), EncodingLatin1
+	}
+	return strings.ToValidUTF8(string(data), "�"), EncodingUTF8
+}
@@ -4,6 +4,7 @@
 package wireformat
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -43,7 +44,14 @@ type HTTPRequestWire struct {
 	Method  string              `json:"method"`
 	URL     string              `json:"url"`
 	Headers map[string][]string `json:"headers,omitempty"`
-	Body    string              `json:"body,omitempty"` // Base64 encoded for binary, or plain string
+	// Body is base64 encoded. Framed requests carry the body as a raw payload frame instead.
+	Body string `json:"body,omitempty"`
+	// MaxBodySize is the largest response body (in bytes) the guest will accept.
+	// Zero means the host default; values above the host limit are clamped.
+	MaxBodySize int64 `json:"max_body_size,omitempty"`
+	// BinaryBody asks the host to return a framed response (see EncodeFrames)
+	// with the body as a raw payload frame instead of base64 in Body.
+	BinaryBody bool `json:"binary_body,omitempty"`
 	// TimeoutMs is implied by Context.TimeoutMs
 }
 
@@ -112,6 +120,8 @@ type ExecRequestWire struct {
 	Args    []string          `json:"args"`
 	Dir     string            `json:"dir,omitempty"`
 	Env     []string          `json:"env,omitempty"`
+	// Encoding of the command's output: "auto", "utf-8" (default) or "latin-1"
+	Encoding string `json:"encoding,omitempty"`
 }
 
 // ExecResponseWire is the JSON wire format for an exec response from Host to Guest.
@@ -122,6 +132,133 @@ type ExecResponseWire struct {
 	DurationMs int64        `json:"duration_ms,omitempty"` // Execution duration in milliseconds
 	IsTimeout  bool         `json:"is_timeout,omitempty"`  // True if command timed out
 	Error      *ErrorDetail `json:"error,omitempty"`
+	// Charsets stdout and stderr were read as: "utf-8" or "latin-1"
+	StdoutCharset string `json:"stdout_charset,omitempty"`
+	StderrCharset string `json:"stderr_charset,omitempty"`
+}
+
+// HashRequestWire is the JSON wire format for a file hash request from Guest to Host.
+type HashRequestWire struct {
+	Context   ContextWireFormat `json:"context"`
+	Path      string            `json:"path"`                // Absolute host path of a regular file
+	Algorithm string            `json:"algorithm,omitempty"` // "sha256" (default), "sha512" or "blake3"
+}
+
+// HashResponseWire is the JSON wire format for a file hash response from Host to Guest.
+type HashResponseWire struct {
+	Algorithm string       `json:"algorithm,omitempty"`
+	Digest    string       `json:"digest,omitempty"` // Lowercase hex
+	Size      int64        `json:"size"`             // Bytes hashed
+	Error     *ErrorDetail `json:"error,omitempty"`
+}
+
+// FileReadRangeRequestWire is the JSON wire format for reading part of a file from Guest to Host.
+type FileReadRangeRequestWire struct {
+	Context ContextWireFormat `json:"context"`
+	Path    string            `json:"path"`   // Absolute host path of a regular file
+	Offset  int64             `json:"offset"` // First byte to read
+	Length  int64             `json:"length"` // Bytes to read, at most 16MB
+}
+
+// FileReadRangeResponseWire is the JSON wire format for part of a file from
+// Host to Guest. Successful responses are framed (see EncodeFrames), with the
+// bytes read as a raw payload frame after this header.
+type FileReadRangeResponseWire struct {
+	Length int64        `json:"length"` // Bytes read; less than requested at the end of the file
+	Size   int64        `json:"size"`   // Size of the whole file
+	Error  *ErrorDetail `json:"error,omitempty"`
+}
+
+// ArchiveListRequestWire is the JSON wire format for listing an archive from Guest to Host.
+type ArchiveListRequestWire struct {
+	Context    ContextWireFormat `json:"context"`
+	Path       string            `json:"path"`                  // Absolute host path of the archive
+	Format     string            `json:"format,omitempty"`      // "tar", "tar.gz", "tar.zst" or "zip"; detected when empty
+	MaxEntries int               `json:"max_entries,omitempty"` // Host default when 0
+}
+
+// ArchiveEntryWire describes one archive member.
+type ArchiveEntryWire struct {
+	Name       string    `json:"name"`
+	Type       string    `json:"type"` // "file", "dir", "symlink" or "other"
+	Size       int64     `json:"size"`
+	Mode       uint32    `json:"mode"` // Permission bits
+	ModTime    time.Time `json:"mod_time"`
+	LinkTarget string    `json:"link_target,omitempty"`
+}
+
+// ArchiveListResponseWire is the JSON wire format for an archive listing from Host to Guest.
+type ArchiveListResponseWire struct {
+	Format    string             `json:"format,omitempty"`
+	Entries   []ArchiveEntryWire `json:"entries,omitempty"`
+	Truncated bool               `json:"truncated,omitempty"` // More entries than MaxEntries
+	Error     *ErrorDetail       `json:"error,omitempty"`
+}
+
+// ArchiveReadRequestWire is the JSON wire format for reading an archive member from Guest to Host.
+type ArchiveReadRequestWire struct {
+	Context  ContextWireFormat `json:"context"`
+	Path     string            `json:"path"`
+	Format   string            `json:"format,omitempty"`
+	Entry    string            `json:"entry"`               // Member name, as listed
+	MaxBytes int64             `json:"max_bytes,omitempty"` // Host default when 0
+}
+
+// ArchiveReadResponseWire is the JSON wire format for an archive member's contents from Host to Guest.
+type ArchiveReadResponseWire struct {
+	Entry     ArchiveEntryWire `json:"entry"`
+	Data      []byte           `json:"data,omitempty"`
+	Truncated bool             `json:"truncated,omitempty"` // Data holds only the first MaxBytes
+	Error     *ErrorDetail     `json:"error,omitempty"`
+}
+
+// PluginObserveRequestWire is the JSON wire format for a composite plugin
+// running an observation of a plugin it depends on, from Guest to Host.
+type PluginObserveRequestWire struct {
+	Context ContextWireFormat      `json:"context"`
+	Plugin  string                 `json:"plugin"`
+	Config  map[string]interface{} `json:"config"`
+}
+
+// PluginObserveResponseWire is the JSON wire format for the result of a
+// dependency's observation, from Host to Guest. Evidence is the evidence the
+// dependency returned, in the plugin evidence format.
+type PluginObserveResponseWire struct {
+	Evidence json.RawMessage `json:"evidence,omitempty"`
+	Error    *ErrorDetail    `json:"error,omitempty"`
+}
+
+// ExecutionContextWire is the JSON wire format of the run, control and
+// observation a plugin call belongs to, from Host to Guest. Plugins use it to
+// correlate their logs and external side effects with results.
+type ExecutionContextWire struct {
+	RunID            string `json:"run_id,omitempty"`
+	Profile          string `json:"profile,omitempty"`
+	Environment      string `json:"environment,omitempty"`
+	ControlID        string `json:"control_id,omitempty"`
+	Plugin           string `json:"plugin,omitempty"`
+	ObservationIndex int    `json:"observation_index"`
+	// IdempotencyKey is set for plugins declaring side effects. It is the
+	// same for retries and re-runs of an unchanged observation, so systems
+	// the plugin changes can drop duplicate actions.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// ScratchDirWire is the JSON wire format of a plugin's scratch directory,
+// from Host to Guest. The directory is private to the observation and
+// removed when it ends; writes beyond LimitBytes fail with EIO.
+type ScratchDirWire struct {
+	Path       string       `json:"path,omitempty"`
+	LimitBytes int64        `json:"limit_bytes,omitempty"`
+	Error      *ErrorDetail `json:"error,omitempty"`
+}
+
+// ErrorResponseWire is the JSON wire format of a host function call that
+// failed before the function could build its own response, e.g. because it
+// was refused or its response could not be encoded. Every response wire
+// format carries its error under "error", so it decodes as any of them.
+type ErrorResponseWire struct {
+	Error *ErrorDetail `json:"error"`
 }
 
 // ErrorDetail provides structured error information, consistent across host and SDK.
@@ -136,6 +273,10 @@ type ErrorDetail struct {
 	Stack      []byte       `json:"stack,omitempty"` // Stack trace for panic errors (SDK only)
 }
 
+// CodeNoSelfTest is the error code of the self_test() evidence of plugins
+// that do not implement a self-test.
+const CodeNoSelfTest = "no_self_test"
+
 // Error implements the error interface for ErrorDetail.
 func (e *ErrorDetail) Error() string {
 	if e == nil {